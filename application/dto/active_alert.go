@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// ActiveAlertOutput is an alert with a live Mattermost post as returned by
+// the alerts admin API.
+type ActiveAlertOutput struct {
+	Fingerprint string    `json:"fingerprint"`
+	AlertName   string    `json:"alert_name"`
+	Severity    string    `json:"severity"`
+	ChannelID   string    `json:"channel_id"`
+	PostID      string    `json:"post_id"`
+	Assignee    string    `json:"assignee,omitempty"`
+	FiringSince time.Time `json:"firing_since"`
+	AgeSeconds  int64     `json:"age_seconds"`
+}
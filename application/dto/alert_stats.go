@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// AlertStatsOutput summarizes the tracked alerts and the recent webhook
+// throughput as returned by the stats API. Counts are lists of rows, so
+// dashboards can chart them without reshaping.
+type AlertStatsOutput struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Active      ActiveStats     `json:"active"`
+	Throughput  ThroughputStats `json:"throughput"`
+}
+
+// ActiveStats counts the alerts with an active post. Severities are listed
+// most severe first, channels busiest first and age buckets youngest first;
+// Heatmap has a row for every severity and age bucket.
+type ActiveStats struct {
+	Total      int                `json:"total"`
+	BySeverity []SeverityCount    `json:"by_severity"`
+	ByChannel  []ChannelCount     `json:"by_channel"`
+	ByAge      []AgeCount         `json:"by_age"`
+	Heatmap    []SeverityAgeCount `json:"heatmap"`
+}
+
+type SeverityCount struct {
+	Severity string `json:"severity"`
+	Count    int    `json:"count"`
+}
+
+type ChannelCount struct {
+	ChannelID string `json:"channel_id"`
+	Count     int    `json:"count"`
+}
+
+type AgeCount struct {
+	Age   string `json:"age"`
+	Count int    `json:"count"`
+}
+
+type SeverityAgeCount struct {
+	Severity string `json:"severity"`
+	Age      string `json:"age"`
+	Count    int    `json:"count"`
+}
+
+// ThroughputStats counts the alert webhooks this replica handled over the
+// last WindowSeconds, in total and per minute, oldest minute first.
+type ThroughputStats struct {
+	WindowSeconds int64              `json:"window_seconds"`
+	WebhookCounts                    // over the whole window
+	PerMinute     []MinuteThroughput `json:"per_minute"`
+}
+
+// WebhookCounts counts alert webhooks by status and those that failed.
+type WebhookCounts struct {
+	Received int `json:"received"`
+	Firing   int `json:"firing"`
+	Resolved int `json:"resolved"`
+	Failed   int `json:"failed"`
+}
+
+type MinuteThroughput struct {
+	Minute time.Time `json:"minute"`
+	WebhookCounts
+}
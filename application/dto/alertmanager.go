@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// AlertmanagerSource is the source reported for alerts received from
+// Alertmanager directly.
+const AlertmanagerSource = "alertmanager"
+
+// AlertmanagerWebhookInput is the Alertmanager webhook payload, version 4.
+// Only the fields the bridge uses are decoded.
+type AlertmanagerWebhookInput struct {
+	Version      string              `json:"version"      binding:"max=16"`
+	GroupKey     string              `json:"groupKey"`
+	Status       string              `json:"status"`
+	Receiver     string              `json:"receiver"`
+	CommonLabels map[string]string   `json:"commonLabels"`
+	ExternalURL  string              `json:"externalURL"`
+	Alerts       []AlertmanagerAlert `json:"alerts"       binding:"required,min=1,max=1000,dive"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"       binding:"required,oneof=firing resolved"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     string            `json:"startsAt"     binding:"max=64"`
+	EndsAt       string            `json:"endsAt"       binding:"max=64"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"  binding:"max=512"`
+}
+
+// KeepAlertInputs translates every alert of the payload into the input the
+// Keep webhook would have produced for it.
+func (in AlertmanagerWebhookInput) KeepAlertInputs() []KeepAlertInput {
+	inputs := make([]KeepAlertInput, 0, len(in.Alerts))
+	for _, a := range in.Alerts {
+		inputs = append(inputs, a.KeepAlertInput())
+	}
+	return inputs
+}
+
+// KeepAlertInput maps the alert the way Keep's Alertmanager provider does:
+// the alertname label becomes the name, the severity label the severity and
+// the description annotation, or else the summary, the description.
+func (a AlertmanagerAlert) KeepAlertInput() KeepAlertInput {
+	fingerprint := a.Fingerprint
+	if fingerprint == "" {
+		fingerprint = labelsFingerprint(a.Labels)
+	}
+
+	name := a.Labels["alertname"]
+	if name == "" {
+		name = fingerprint
+	}
+
+	description := a.Annotations["description"]
+	if description == "" {
+		description = a.Annotations["summary"]
+	}
+
+	return KeepAlertInput{
+		Name:            name,
+		Status:          strings.ToLower(a.Status),
+		Severity:        alertmanagerSeverity(a.Labels["severity"]),
+		Source:          FlexStrings{AlertmanagerSource},
+		Fingerprint:     fingerprint,
+		Description:     description,
+		Labels:          FlexLabels(a.Labels),
+		Annotations:     FlexLabels(a.Annotations),
+		GeneratorURL:    a.GeneratorURL,
+		FiringStartTime: a.StartsAt,
+	}
+}
+
+// alertmanagerSeverity maps the severity label onto the bridge's severities.
+// Alerts without a recognizable severity, and debug ones, which the bridge
+// does not accept by default, are treated as info.
+func alertmanagerSeverity(label string) string {
+	if severity, err := alert.NewSeverity(label); err == nil && severity.String() != alert.SeverityDebug {
+		return severity.String()
+	}
+	switch strings.ToLower(label) {
+	case "page", "error", "major":
+		return alert.SeverityHigh
+	case "minor":
+		return alert.SeverityWarning
+	default:
+		return alert.SeverityInfo
+	}
+}
+
+// labelsFingerprint derives a stable fingerprint from the label set for
+// senders that omit one, as older Alertmanager versions do.
+func labelsFingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[name]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerWebhookInput_KeepAlertInputs(t *testing.T) {
+	payload := `{
+		"version": "4",
+		"status": "firing",
+		"receiver": "bridge",
+		"alerts": [
+			{
+				"status": "firing",
+				"labels": {"alertname": "HighCPU", "severity": "critical", "instance": "server1"},
+				"annotations": {"summary": "CPU high", "description": "CPU above 90% for 5m"},
+				"startsAt": "2024-01-01T00:00:00.5Z",
+				"endsAt": "0001-01-01T00:00:00Z",
+				"generatorURL": "http://prometheus/graph",
+				"fingerprint": "c0ffee"
+			},
+			{
+				"status": "resolved",
+				"labels": {"alertname": "DiskFull", "severity": "page"},
+				"annotations": {"summary": "Disk almost full"},
+				"fingerprint": "beef"
+			}
+		]
+	}`
+
+	var input AlertmanagerWebhookInput
+	require.NoError(t, json.Unmarshal([]byte(payload), &input))
+
+	assert.Equal(t, []KeepAlertInput{
+		{
+			Name:            "HighCPU",
+			Status:          "firing",
+			Severity:        "critical",
+			Source:          FlexStrings{"alertmanager"},
+			Fingerprint:     "c0ffee",
+			Description:     "CPU above 90% for 5m",
+			Labels:          FlexLabels{"alertname": "HighCPU", "severity": "critical", "instance": "server1"},
+			Annotations:     FlexLabels{"summary": "CPU high", "description": "CPU above 90% for 5m"},
+			GeneratorURL:    "http://prometheus/graph",
+			FiringStartTime: "2024-01-01T00:00:00.5Z",
+		},
+		{
+			Name:        "DiskFull",
+			Status:      "resolved",
+			Severity:    "high",
+			Source:      FlexStrings{"alertmanager"},
+			Fingerprint: "beef",
+			Description: "Disk almost full",
+			Labels:      FlexLabels{"alertname": "DiskFull", "severity": "page"},
+			Annotations: FlexLabels{"summary": "Disk almost full"},
+		},
+	}, input.KeepAlertInputs())
+}
+
+func TestAlertmanagerAlert_Defaults(t *testing.T) {
+	a := AlertmanagerAlert{Status: "firing", Labels: map[string]string{"job": "node", "instance": "server1"}}
+	input := a.KeepAlertInput()
+
+	assert.Len(t, input.Fingerprint, 16, "derived from labels when missing")
+	assert.Equal(t, input.Fingerprint, input.Name, "no alertname label")
+	assert.Equal(t, "info", input.Severity, "no severity label")
+
+	same := AlertmanagerAlert{Status: "resolved", Labels: map[string]string{"instance": "server1", "job": "node"}}
+	assert.Equal(t, input.Fingerprint, same.KeepAlertInput().Fingerprint)
+	other := AlertmanagerAlert{Status: "firing", Labels: map[string]string{"job": "node", "instance": "server2"}}
+	assert.NotEqual(t, input.Fingerprint, other.KeepAlertInput().Fingerprint)
+}
+
+func TestAlertmanagerSeverity(t *testing.T) {
+	tests := map[string]string{
+		"critical": "critical",
+		"Warning":  "warning",
+		"low":      "low",
+		"error":    "high",
+		"page":     "high",
+		"minor":    "warning",
+		"none":     "info",
+		"debug":    "info",
+		"":         "info",
+	}
+	for label, want := range tests {
+		assert.Equal(t, want, alertmanagerSeverity(label), label)
+	}
+}
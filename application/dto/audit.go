@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// AuditEventOutput is one step in the history of an alert as returned by the
+// audit API.
+type AuditEventOutput struct {
+	Kind   string    `json:"kind"`
+	Actor  string    `json:"actor,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+	At     time.Time `json:"at"`
+}
@@ -4,5 +4,6 @@ type MattermostCallbackInput struct {
 	UserID    string            `json:"user_id"`
 	PostID    string            `json:"post_id"`
 	ChannelID string            `json:"channel_id"`
+	TriggerID string            `json:"trigger_id"`
 	Context   map[string]string `json:"context"`
 }
@@ -2,9 +2,16 @@ package dto
 
 import "github.com/alexmorbo/keep-mattermost-bridge/domain/post"
 
+// CallbackOutput is the immediate reply to a button click. When Ephemeral is
+// set it is shown only to the clicking user, the post is left unchanged and
+// there is no asynchronous phase unless RunAsync is set too. DialogOpened
+// likewise leaves the post unchanged: the action continues when the dialog
+// is submitted.
 type CallbackOutput struct {
-	Attachment AttachmentDTO
-	Ephemeral  string
+	Attachment   AttachmentDTO
+	Ephemeral    string
+	RunAsync     bool
+	DialogOpened bool
 }
 
 type AttachmentDTO struct {
@@ -28,9 +35,17 @@ type ButtonDTO struct {
 	ID          string
 	Name        string
 	Style       string
+	Type        string
+	DataSource  string
+	Options     []SelectOptionDTO
 	Integration ButtonIntegrationDTO
 }
 
+type SelectOptionDTO struct {
+	Text  string
+	Value string
+}
+
 type ButtonIntegrationDTO struct {
 	URL     string
 	Context map[string]string
@@ -44,10 +59,17 @@ func NewAttachmentDTO(a post.Attachment) AttachmentDTO {
 
 	buttons := make([]ButtonDTO, len(a.Actions))
 	for i, b := range a.Actions {
+		var options []SelectOptionDTO
+		for _, o := range b.Options {
+			options = append(options, SelectOptionDTO{Text: o.Text, Value: o.Value})
+		}
 		buttons[i] = ButtonDTO{
-			ID:    b.ID,
-			Name:  b.Name,
-			Style: b.Style,
+			ID:         b.ID,
+			Name:       b.Name,
+			Style:      b.Style,
+			Type:       b.Type,
+			DataSource: b.DataSource,
+			Options:    options,
 			Integration: ButtonIntegrationDTO{
 				URL:     b.Integration.URL,
 				Context: b.Integration.Context,
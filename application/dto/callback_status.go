@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// CallbackStatusOutput is the outcome of a button callback's asynchronous
+// phase as returned by the callbacks API. FinishedAt is nil while the phase
+// runs.
+type CallbackStatusOutput struct {
+	ID          string     `json:"id"`
+	Action      string     `json:"action"`
+	Fingerprint string     `json:"fingerprint"`
+	PostID      string     `json:"post_id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// CorrelationOutput is the correlation record of an alert as returned by the
+// correlation API.
+type CorrelationOutput struct {
+	Fingerprint string    `json:"fingerprint"`
+	PostIDs     []string  `json:"post_ids"`
+	IncidentID  string    `json:"incident_id,omitempty"`
+	TicketKey   string    `json:"ticket_key,omitempty"`
+	TicketURL   string    `json:"ticket_url,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetterOutput is a failed Mattermost delivery as returned by the
+// dead-letter API. Alert entries carry the webhook payload; update entries
+// carry the post and the title it should show.
+type DeadLetterOutput struct {
+	ID            string          `json:"id"`
+	Kind          string          `json:"kind"`
+	Fingerprint   string          `json:"fingerprint,omitempty"`
+	PostID        string          `json:"post_id,omitempty"`
+	Title         string          `json:"title,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	LastError     string          `json:"last_error"`
+	Attempts      int             `json:"attempts"`
+	Exhausted     bool            `json:"exhausted"`
+	FailedAt      time.Time       `json:"failed_at"`
+	LastAttemptAt time.Time       `json:"last_attempt_at"`
+}
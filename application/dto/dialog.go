@@ -0,0 +1,20 @@
+package dto
+
+// MattermostDialogSubmission is what Mattermost posts when a user submits an
+// interactive dialog. Submission maps element names to the entered values.
+type MattermostDialogSubmission struct {
+	CallbackID string         `json:"callback_id"`
+	State      string         `json:"state"`
+	UserID     string         `json:"user_id"`
+	ChannelID  string         `json:"channel_id"`
+	Submission map[string]any `json:"submission"`
+	Cancelled  bool           `json:"cancelled"`
+}
+
+// DialogSubmissionOutput rejects a submission and keeps the dialog open:
+// Error is shown above the form and Errors next to the named elements. An
+// empty output accepts the submission and closes the dialog.
+type DialogSubmissionOutput struct {
+	Error  string
+	Errors map[string]string
+}
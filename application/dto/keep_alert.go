@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"strings"
 )
@@ -14,7 +16,32 @@ type KeepAlertInput struct {
 	Fingerprint     string      `json:"fingerprint"     binding:"required,max=512"`
 	Description     string      `json:"description"     binding:"max=4096"`
 	Labels          FlexLabels  `json:"labels"`
+	Annotations     FlexLabels  `json:"annotations,omitempty"`
+	GeneratorURL    string      `json:"generatorURL,omitempty" binding:"max=2048"`
+	URL             string      `json:"url,omitempty"          binding:"max=2048"` // Keep's name for it on some providers
 	FiringStartTime string      `json:"firingStartTime" binding:"max=64"`
+	LastReceived    string      `json:"lastReceived"    binding:"max=64"`
+	IncidentID      string      `json:"incident_id"     binding:"max=256"`  // enrichment set by incident workflows
+	TicketID        string      `json:"ticket_id"       binding:"max=256"`  // enrichment set by ticketing providers
+	TicketURL       string      `json:"ticket_url"      binding:"max=2048"` // enrichment set by ticketing providers
+}
+
+// IdempotencyKey identifies this delivery of the alert. Keep stamps every
+// event with lastReceived, so a webhook delivered twice yields the same key
+// while a new event for the same fingerprint yields a new one.
+func (in KeepAlertInput) IdempotencyKey() string {
+	payload, _ := json.Marshal(in)
+	sum := sha256.Sum256(payload)
+	return in.Fingerprint + ":" + hex.EncodeToString(sum[:16])
+}
+
+// SourceURL returns the URL of the alert in the system that generated it:
+// generatorURL when the payload has one, Keep's url otherwise.
+func (in KeepAlertInput) SourceURL() string {
+	if in.GeneratorURL != "" {
+		return in.GeneratorURL
+	}
+	return in.URL
 }
 
 // FlexStrings handles both []string and Python list repr string like "['a', 'b']"
@@ -0,0 +1,228 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Keep webhook payload schemas told apart by DecodeKeepAlert.
+const (
+	// KeepSchemaV1 is the payload of Keep's webhook provider: source is a
+	// list and labels an object.
+	KeepSchemaV1 = "v1"
+	// KeepSchemaRepr is the payload rendered by Keep workflow templates,
+	// where source and labels arrive as Python repr strings such as
+	// "['a', 'b']" and "{'k': 'v'}".
+	KeepSchemaRepr = "repr"
+)
+
+// keepSeverityLevels maps the numeric severities some Keep versions send to
+// their names.
+var keepSeverityLevels = map[int]string{
+	5: alert.SeverityCritical,
+	4: alert.SeverityHigh,
+	3: alert.SeverityWarning,
+	2: alert.SeverityInfo,
+	1: alert.SeverityLow,
+}
+
+// keepStringFields are the string fields of KeepAlertInput, by JSON name.
+var keepStringFields = []string{
+	"id", "name", "status", "fingerprint", "description",
+	"firingStartTime", "lastReceived", "incident_id", "ticket_id", "ticket_url",
+}
+
+// KeepAlertDecoding describes how DecodeKeepAlert read a payload.
+type KeepAlertDecoding struct {
+	// Schema is KeepSchemaV1 or KeepSchemaRepr.
+	Schema string
+	// Coerced lists, sorted, the fields whose JSON type had to be converted.
+	Coerced []string
+}
+
+// DecodeKeepAlert decodes a Keep webhook payload, accepting the variations
+// Keep's payload has gone through instead of failing on them: numbers and
+// booleans where strings are expected, numeric severities, a single source
+// string, labels with non-string values, and source and labels as Python
+// repr strings. Validation of the decoded input is left to the caller.
+func DecodeKeepAlert(data []byte) (KeepAlertInput, KeepAlertDecoding, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return KeepAlertInput{}, KeepAlertDecoding{}, err
+	}
+	if fields == nil {
+		return KeepAlertInput{}, KeepAlertDecoding{}, errors.New("payload is not a JSON object")
+	}
+
+	decoding := KeepAlertDecoding{Schema: KeepSchemaV1}
+	if jsonKind(fields["source"]) == '"' || jsonKind(fields["labels"]) == '"' {
+		decoding.Schema = KeepSchemaRepr
+	}
+
+	coerce := func(name string, raw json.RawMessage, ok bool) {
+		if ok {
+			fields[name] = raw
+			decoding.Coerced = append(decoding.Coerced, name)
+		}
+	}
+	for _, name := range keepStringFields {
+		raw, ok := coerceString(fields[name])
+		coerce(name, raw, ok)
+	}
+	raw, ok := coerceSeverity(fields["severity"])
+	coerce("severity", raw, ok)
+	raw, ok = coerceSource(fields["source"])
+	coerce("source", raw, ok)
+	raw, ok = coerceLabels(fields["labels"])
+	coerce("labels", raw, ok)
+	raw, ok = coerceLabels(fields["annotations"])
+	coerce("annotations", raw, ok)
+	slices.Sort(decoding.Coerced)
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return KeepAlertInput{}, KeepAlertDecoding{}, err
+	}
+	var input KeepAlertInput
+	if err := json.Unmarshal(normalized, &input); err != nil {
+		return KeepAlertInput{}, KeepAlertDecoding{}, err
+	}
+	return input, decoding, nil
+}
+
+// jsonKind returns the first byte of raw: '"', '[', '{', 'n', 't', 'f', a
+// digit or '-', or 0 when raw is empty.
+func jsonKind(raw json.RawMessage) byte {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0
+	}
+	return raw[0]
+}
+
+// scalarString returns the text of a JSON number or boolean.
+func scalarString(raw json.RawMessage) (string, bool) {
+	switch jsonKind(raw) {
+	case 't', 'f':
+		var b bool
+		if json.Unmarshal(raw, &b) != nil {
+			return "", false
+		}
+		return strconv.FormatBool(b), true
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		var n json.Number
+		if json.Unmarshal(raw, &n) != nil {
+			return "", false
+		}
+		return n.String(), true
+	}
+	return "", false
+}
+
+func coerceString(raw json.RawMessage) (json.RawMessage, bool) {
+	s, ok := scalarString(raw)
+	if !ok {
+		return nil, false
+	}
+	return mustMarshal(s), true
+}
+
+func coerceSeverity(raw json.RawMessage) (json.RawMessage, bool) {
+	s, ok := scalarString(raw)
+	if !ok {
+		return nil, false
+	}
+	if level, err := strconv.Atoi(s); err == nil {
+		if name, known := keepSeverityLevels[level]; known {
+			s = name
+		}
+	}
+	return mustMarshal(s), true
+}
+
+// coerceSource turns a lone source into a list and the items of a list into
+// strings. Repr strings are parsed by FlexStrings but still reported.
+func coerceSource(raw json.RawMessage) (json.RawMessage, bool) {
+	switch jsonKind(raw) {
+	case '"':
+		return raw, true
+	case '[':
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return nil, false
+		}
+		changed := false
+		sources := make([]string, 0, len(items))
+		for _, item := range items {
+			var s string
+			if json.Unmarshal(item, &s) == nil {
+				sources = append(sources, s)
+				continue
+			}
+			changed = true
+			if s, ok := scalarString(item); ok {
+				sources = append(sources, s)
+			}
+		}
+		if !changed {
+			return nil, false
+		}
+		return mustMarshal(sources), true
+	}
+	if s, ok := scalarString(raw); ok {
+		return mustMarshal([]string{s}), true
+	}
+	return nil, false
+}
+
+// coerceLabels turns label values that are not strings into strings: numbers
+// and booleans as written, null as empty, objects and lists as compact JSON.
+// Repr strings are parsed by FlexLabels but still reported.
+func coerceLabels(raw json.RawMessage) (json.RawMessage, bool) {
+	switch jsonKind(raw) {
+	case '"':
+		return raw, true
+	case '{':
+	default:
+		return nil, false
+	}
+	var values map[string]json.RawMessage
+	if json.Unmarshal(raw, &values) != nil {
+		return nil, false
+	}
+	changed := false
+	labels := make(map[string]string, len(values))
+	for k, v := range values {
+		var s string
+		if json.Unmarshal(v, &s) == nil && jsonKind(v) == '"' {
+			labels[k] = s
+			continue
+		}
+		changed = true
+		switch kind := jsonKind(v); {
+		case kind == 'n':
+			labels[k] = ""
+		case kind == '{' || kind == '[':
+			var compact bytes.Buffer
+			if json.Compact(&compact, v) == nil {
+				labels[k] = compact.String()
+			}
+		default:
+			labels[k], _ = scalarString(v)
+		}
+	}
+	if !changed {
+		return nil, false
+	}
+	return mustMarshal(labels), true
+}
+
+func mustMarshal(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeKeepAlert(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected KeepAlertInput
+		schema   string
+		coerced  []string
+	}{
+		{
+			name:     "native payload needs no coercion",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": "critical", "fingerprint": "fp-1", "source": ["prometheus"], "labels": {"env": "prod"}}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "critical", Fingerprint: "fp-1", Source: FlexStrings{"prometheus"}, Labels: FlexLabels{"env": "prod"}},
+			schema:   KeepSchemaV1,
+		},
+		{
+			name:     "repr source and labels",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": "high", "fingerprint": "fp-1", "source": "['prometheus', 'grafana']", "labels": "{'env': 'prod'}"}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "high", Fingerprint: "fp-1", Source: FlexStrings{"prometheus", "grafana"}, Labels: FlexLabels{"env": "prod"}},
+			schema:   KeepSchemaRepr,
+			coerced:  []string{"labels", "source"},
+		},
+		{
+			name:     "numeric severity and fingerprint",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": 4, "fingerprint": 12345}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "high", Fingerprint: "12345"},
+			schema:   KeepSchemaV1,
+			coerced:  []string{"fingerprint", "severity"},
+		},
+		{
+			name:     "unknown numeric severity is kept as text",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": 9, "fingerprint": "fp-1"}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "9", Fingerprint: "fp-1"},
+			schema:   KeepSchemaV1,
+			coerced:  []string{"severity"},
+		},
+		{
+			name:     "label values of other types",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": "info", "fingerprint": "fp-1", "labels": {"port": 8080, "canary": true, "owner": null, "tags": ["a", "b"]}}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "info", Fingerprint: "fp-1", Labels: FlexLabels{"port": "8080", "canary": "true", "owner": "", "tags": `["a","b"]`}},
+			schema:   KeepSchemaV1,
+			coerced:  []string{"labels"},
+		},
+		{
+			name:     "annotations are decoded like labels",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": "info", "fingerprint": "fp-1", "annotations": {"summary": "CPU high", "runbook_rev": 3}}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "info", Fingerprint: "fp-1", Annotations: FlexLabels{"summary": "CPU high", "runbook_rev": "3"}},
+			schema:   KeepSchemaV1,
+			coerced:  []string{"annotations"},
+		},
+		{
+			name:     "non-string sources",
+			input:    `{"name": "HighCPU", "status": "firing", "severity": "info", "fingerprint": "fp-1", "source": ["prometheus", 2]}`,
+			expected: KeepAlertInput{Name: "HighCPU", Status: "firing", Severity: "info", Fingerprint: "fp-1", Source: FlexStrings{"prometheus", "2"}},
+			schema:   KeepSchemaV1,
+			coerced:  []string{"source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, decoding, err := DecodeKeepAlert([]byte(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, input)
+			assert.Equal(t, tt.schema, decoding.Schema)
+			assert.Equal(t, tt.coerced, decoding.Coerced)
+		})
+	}
+}
+
+func TestDecodeKeepAlertInvalid(t *testing.T) {
+	for _, payload := range []string{`not json`, `null`, `["a"]`, `{"labels": {"a": }`} {
+		_, _, err := DecodeKeepAlert([]byte(payload))
+		assert.Error(t, err, payload)
+	}
+}
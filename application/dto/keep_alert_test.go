@@ -326,3 +326,22 @@ func TestKeepAlertInput_BindingValidation(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestKeepAlertInput_IdempotencyKey(t *testing.T) {
+	first := KeepAlertInput{
+		Fingerprint:  "fp-1",
+		Name:         "DiskFull",
+		Status:       "firing",
+		Severity:     "critical",
+		Labels:       FlexLabels{"host": "db-1", "team": "sre"},
+		LastReceived: "2024-01-15T10:35:00Z",
+	}
+	again := first
+	again.Labels = FlexLabels{"team": "sre", "host": "db-1"}
+	later := first
+	later.LastReceived = "2024-01-15T10:40:00Z"
+
+	assert.Equal(t, first.IdempotencyKey(), again.IdempotencyKey())
+	assert.NotEqual(t, first.IdempotencyKey(), later.IdempotencyKey())
+	assert.True(t, strings.HasPrefix(first.IdempotencyKey(), "fp-1:"))
+}
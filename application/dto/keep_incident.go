@@ -0,0 +1,26 @@
+package dto
+
+import "strings"
+
+// KeepIncidentInput is the incident payload Keep sends to the incident
+// webhook, a subset of Keep's IncidentDto.
+type KeepIncidentInput struct {
+	ID                string `json:"id"                  binding:"required,max=256"`
+	UserGeneratedName string `json:"user_generated_name" binding:"max=512"`
+	AIGeneratedName   string `json:"ai_generated_name"   binding:"max=512"`
+	Severity          string `json:"severity"            binding:"required,max=64"`
+	Status            string `json:"status"              binding:"required,max=64"`
+	AlertsCount       int    `json:"alerts_count"        binding:"min=0"`
+	StartTime         string `json:"start_time"          binding:"max=64"`
+}
+
+// Name returns the name a user gave the incident, else the one Keep
+// generated, else the incident ID.
+func (in KeepIncidentInput) Name() string {
+	for _, name := range []string{in.UserGeneratedName, in.AIGeneratedName} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return in.ID
+}
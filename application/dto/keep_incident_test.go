@@ -0,0 +1,13 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepIncidentInput_Name(t *testing.T) {
+	assert.Equal(t, "mine", KeepIncidentInput{ID: "inc-1", UserGeneratedName: "mine", AIGeneratedName: "ai"}.Name())
+	assert.Equal(t, "ai", KeepIncidentInput{ID: "inc-1", UserGeneratedName: " ", AIGeneratedName: "ai"}.Name())
+	assert.Equal(t, "inc-1", KeepIncidentInput{ID: "inc-1"}.Name())
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+
+// Replay actions: what handling an alert does to its post.
+const (
+	ReplayCreate  = "create"
+	ReplayUpdate  = "update"
+	ReplayResolve = "resolve"
+	ReplayClose   = "close"
+	ReplayHold    = "hold"
+	ReplayIgnore  = "ignore"
+	ReplayDrop    = "drop"
+)
+
+// ReplayOutput is what the replay admin API reports for an alert: the
+// decisions handling it takes and the attachment it renders. Executed tells
+// whether the alert was also handled for real.
+type ReplayOutput struct {
+	Fingerprint       string           `json:"fingerprint"`
+	Status            string           `json:"status"`
+	Severity          string           `json:"severity"`
+	Action            string           `json:"action"`
+	Reason            string           `json:"reason,omitempty"`
+	DropRule          string           `json:"drop_rule,omitempty"`
+	ChannelID         string           `json:"channel_id,omitempty"`
+	CopyChannelIDs    []string         `json:"copy_channel_ids,omitempty"`
+	PostID            string           `json:"post_id,omitempty"`
+	MaintenanceWindow string           `json:"maintenance_window,omitempty"`
+	QuietHours        string           `json:"quiet_hours,omitempty"`
+	Attachment        *post.Attachment `json:"attachment,omitempty"`
+	Executed          bool             `json:"executed"`
+}
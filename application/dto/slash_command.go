@@ -0,0 +1,22 @@
+package dto
+
+// Mattermost slash command response types.
+const (
+	SlashResponseEphemeral = "ephemeral"
+	SlashResponseInChannel = "in_channel"
+)
+
+// SlashCommandInput carries the fields of a Mattermost slash command request
+// that the bridge acts on.
+type SlashCommandInput struct {
+	UserID    string
+	UserName  string
+	ChannelID string
+	Command   string
+	Text      string
+}
+
+type SlashCommandOutput struct {
+	ResponseType string
+	Text         string
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+// AlertUseCase applies a Keep alert webhook payload to the alert's posts.
+type AlertUseCase interface {
+	Execute(ctx context.Context, input dto.KeepAlertInput) error
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+// StreamedAlert is an alert webhook read from an AlertStream.
+type StreamedAlert struct {
+	ID    string
+	Input dto.KeepAlertInput
+	// Deliveries counts how often the alert has been handed to a consumer,
+	// including this time.
+	Deliveries int64
+	// ReceivedAt is when the alert was appended, or zero when unknown.
+	ReceivedAt time.Time
+}
+
+// AlertStream is a durable log of alert webhooks read by a consumer group:
+// every alert is handed to one consumer and stays pending until it is
+// acknowledged, so alerts survive a crash of the consumer handling them.
+type AlertStream interface {
+	Append(ctx context.Context, input dto.KeepAlertInput) error
+	// Read returns up to count new alerts for consumer, waiting up to block
+	// for the first one. It returns no alerts and no error on timeout.
+	Read(ctx context.Context, consumer string, count int, block time.Duration) ([]StreamedAlert, error)
+	// Claim takes over up to count alerts that another consumer, or a
+	// previous run of this one, left pending for at least minIdle.
+	Claim(ctx context.Context, consumer string, minIdle time.Duration, count int) ([]StreamedAlert, error)
+	Ack(ctx context.Context, id string) error
+}
@@ -1,8 +1,24 @@
 package port
 
-import "github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+import (
+	"context"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
 
 type CallbackUseCase interface {
 	ExecuteImmediate(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error)
 	ExecuteAsync(input dto.MattermostCallbackInput)
 }
+
+// AlertActionUseCase applies alert actions outside of interactive buttons,
+// e.g. from slash commands.
+type AlertActionUseCase interface {
+	ExecuteAction(ctx context.Context, action, fingerprint, userID string) error
+}
+
+// DialogUseCase handles interactive dialog submissions, e.g. the resolve
+// dialog opened by a Resolve button.
+type DialogUseCase interface {
+	ExecuteDialogSubmission(input dto.MattermostDialogSubmission) (*dto.DialogSubmissionOutput, error)
+}
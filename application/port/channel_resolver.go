@@ -1,5 +1,27 @@
 package port
 
+import (
+	"context"
+	"time"
+)
+
 type ChannelResolver interface {
-	ChannelIDForSeverity(severity string) string
+	// ChannelIDsForAlert returns the channels an alert is routed to. The
+	// first one holds the tracked post; any others receive a copy. Alerts
+	// that routing drops get none.
+	ChannelIDsForAlert(severity string, labels map[string]string) []string
+}
+
+// OnCallResolver selects the users who get a direct message about a new
+// firing alert.
+type OnCallResolver interface {
+	// UsersForAlert returns Mattermost usernames without the leading @.
+	UsersForAlert(severity string, labels map[string]string) []string
+}
+
+// ChannelIDCache remembers the IDs of channels the config names, so every
+// replica and restart reuses the lookups.
+type ChannelIDCache interface {
+	GetChannelID(ctx context.Context, teamID, name string) (channelID string, ok bool, err error)
+	SetChannelID(ctx context.Context, teamID, name, channelID string, ttl time.Duration) error
 }
@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
+)
+
+// CustomAction is a configured button shown on firing alert posts.
+type CustomAction = attachment.CustomAction
+
+// Workflow is a Keep workflow offered in the Run workflow menu of firing
+// alert posts.
+type Workflow = attachment.Workflow
+
+// CustomActionRequest is what a custom action does when clicked: run the Keep
+// workflow WorkflowID, or POST to URL, with Body as JSON payload.
+type CustomActionRequest struct {
+	WorkflowID string
+	URL        string
+	Body       []byte
+}
+
+// CustomActions renders the requests of configured custom actions.
+type CustomActions interface {
+	// Request renders the request of the action named name for a, clicked
+	// by username. It fails when no such action applies to a.
+	Request(name string, a *alert.Alert, username string) (CustomActionRequest, error)
+}
+
+// WebhookSender POSTs JSON payloads to URLs outside Keep and Mattermost.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, body []byte) error
+}
@@ -0,0 +1,7 @@
+package port
+
+// DropRules tell which alerts the bridge ignores.
+type DropRules interface {
+	// RuleForAlert returns the name of the first rule that drops the alert.
+	RuleForAlert(name, severity string, labels map[string]string) (string, bool)
+}
@@ -0,0 +1,15 @@
+package port
+
+import "time"
+
+// EscalationRule escalates a firing alert of Severity that nobody has
+// acknowledged within After. Rules for the same severity are applied one at a
+// time in order of After, so a severity can escalate in several steps.
+type EscalationRule struct {
+	Severity string
+	After    time.Duration
+	// Mention is posted in the alert thread, e.g. "@sre-oncall".
+	Mention string
+	// ChannelID, when set, receives a copy of the alert.
+	ChannelID string
+}
@@ -0,0 +1,51 @@
+package port
+
+// Mocks for the application ports live in the portmock package and are
+// regenerated with `make generate` whenever a port interface changes.
+
+//go:generate moq -rm -out portmock/keep_client.go -pkg portmock . KeepClient
+//go:generate moq -rm -out portmock/keep_alert_querier.go -pkg portmock . KeepAlertQuerier
+//go:generate moq -rm -out portmock/keep_incident_client.go -pkg portmock . KeepIncidentClient
+//go:generate moq -rm -out portmock/keep_workflow_updater.go -pkg portmock . KeepWorkflowUpdater
+//go:generate moq -rm -out portmock/keep_workflow_runner.go -pkg portmock . KeepWorkflowRunner
+//go:generate moq -rm -out portmock/keep_user_client.go -pkg portmock . KeepUserClient
+//go:generate moq -rm -out portmock/mattermost_client.go -pkg portmock . MattermostClient
+//go:generate moq -rm -out portmock/mattermost_status_client.go -pkg portmock . MattermostStatusClient
+//go:generate moq -rm -out portmock/mattermost_thread_client.go -pkg portmock . MattermostThreadClient
+//go:generate moq -rm -out portmock/mattermost_post_reader.go -pkg portmock . MattermostPostReader
+//go:generate moq -rm -out portmock/mattermost_pin_client.go -pkg portmock . MattermostPinClient
+//go:generate moq -rm -out portmock/mattermost_reaction_client.go -pkg portmock . MattermostReactionClient
+//go:generate moq -rm -out portmock/mattermost_reaction_events.go -pkg portmock . MattermostReactionEvents
+//go:generate moq -rm -out portmock/mattermost_reply_events.go -pkg portmock . MattermostReplyEvents
+//go:generate moq -rm -out portmock/mattermost_direct_client.go -pkg portmock . MattermostDirectClient
+//go:generate moq -rm -out portmock/mattermost_membership_client.go -pkg portmock . MattermostMembershipClient
+//go:generate moq -rm -out portmock/mattermost_dialog_client.go -pkg portmock . MattermostDialogClient
+//go:generate moq -rm -out portmock/mattermost_user_directory.go -pkg portmock . MattermostUserDirectory
+//go:generate moq -rm -out portmock/mattermost_channel_directory.go -pkg portmock . MattermostChannelDirectory
+//go:generate moq -rm -out portmock/message_builder.go -pkg portmock . MessageBuilder
+//go:generate moq -rm -out portmock/incident_message_builder.go -pkg portmock . IncidentMessageBuilder
+//go:generate moq -rm -out portmock/message_config.go -pkg portmock . MessageConfig
+//go:generate moq -rm -out portmock/channel_resolver.go -pkg portmock . ChannelResolver
+//go:generate moq -rm -out portmock/channel_id_cache.go -pkg portmock . ChannelIDCache
+//go:generate moq -rm -out portmock/status_board_store.go -pkg portmock . StatusBoardStore
+//go:generate moq -rm -out portmock/on_call_resolver.go -pkg portmock . OnCallResolver
+//go:generate moq -rm -out portmock/quiet_hours.go -pkg portmock . QuietHours
+//go:generate moq -rm -out portmock/custom_actions.go -pkg portmock . CustomActions
+//go:generate moq -rm -out portmock/webhook_sender.go -pkg portmock . WebhookSender
+//go:generate moq -rm -out portmock/user_mapper.go -pkg portmock . UserMapper
+//go:generate moq -rm -out portmock/user_email_cache.go -pkg portmock . UserEmailCache
+//go:generate moq -rm -out portmock/callback_use_case.go -pkg portmock . CallbackUseCase
+//go:generate moq -rm -out portmock/dialog_use_case.go -pkg portmock . DialogUseCase
+//go:generate moq -rm -out portmock/alert_use_case.go -pkg portmock . AlertUseCase
+//go:generate moq -rm -out portmock/alert_stream.go -pkg portmock . AlertStream
+//go:generate moq -rm -out portmock/locker.go -pkg portmock . Locker
+//go:generate moq -rm -out portmock/idempotency_store.go -pkg portmock . IdempotencyStore
+//go:generate moq -rm -out portmock/alert_action_use_case.go -pkg portmock . AlertActionUseCase
+//go:generate moq -rm -out portmock/post_repository.go -pkg portmock ../../domain/post Repository
+//go:generate moq -rm -out portmock/post_expiry_store.go -pkg portmock ../../domain/post ExpiryStore:PostExpiryStoreMock
+//go:generate moq -rm -out portmock/group_repository.go -pkg portmock ../../domain/group Repository:GroupRepositoryMock
+//go:generate moq -rm -out portmock/correlation_repository.go -pkg portmock ../../domain/correlation Repository:CorrelationRepositoryMock
+//go:generate moq -rm -out portmock/retention_repository.go -pkg portmock ../../domain/retention Repository:RetentionRepositoryMock
+//go:generate moq -rm -out portmock/dead_letter_repository.go -pkg portmock ../../domain/deadletter Repository:DeadLetterRepositoryMock
+//go:generate moq -rm -out portmock/audit_repository.go -pkg portmock ../../domain/audit Repository:AuditRepositoryMock
+//go:generate moq -rm -out portmock/incident_repository.go -pkg portmock ../../domain/incident Repository:IncidentRepositoryMock
@@ -2,9 +2,19 @@ package port
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrKeepUnavailable is wrapped by the errors of EnrichAlert, UnenrichAlert
+// and GetAlert when Keep could not be reached or answered with a 5xx, so the
+// same request may succeed later.
+var ErrKeepUnavailable = errors.New("keep unavailable")
+
+// ErrKeepAlertNotFound is wrapped by the error of GetAlert when Keep does not
+// know the fingerprint.
+var ErrKeepAlertNotFound = errors.New("keep alert not found")
+
 type KeepAlert struct {
 	Fingerprint     string
 	Name            string
@@ -13,6 +23,8 @@ type KeepAlert struct {
 	Description     string
 	Source          []string
 	Labels          map[string]string
+	Annotations     map[string]string
+	GeneratorURL    string
 	FiringStartTime time.Time
 	Enrichments     map[string]string
 }
@@ -29,6 +41,7 @@ type KeepWorkflow struct {
 	Name          string
 	WorkflowRawID string
 	Disabled      bool
+	Raw           string // YAML definition as stored by Keep
 }
 
 type WebhookProviderConfig struct {
@@ -53,9 +66,82 @@ type KeepClient interface {
 	EnrichAlert(ctx context.Context, fingerprint string, enrichments map[string]string, opts EnrichOptions) error
 	UnenrichAlert(ctx context.Context, fingerprint string, enrichments []string) error
 	GetAlert(ctx context.Context, fingerprint string) (*KeepAlert, error)
-	GetAlerts(ctx context.Context, limit int) ([]KeepAlert, error)
+	// GetAlerts returns up to limit alerts, restricted to the given
+	// fingerprints when the slice is non-empty.
+	GetAlerts(ctx context.Context, limit int, fingerprints []string) ([]KeepAlert, error)
 	GetProviders(ctx context.Context) ([]KeepProvider, error)
 	CreateWebhookProvider(ctx context.Context, config WebhookProviderConfig) error
 	GetWorkflows(ctx context.Context) ([]KeepWorkflow, error)
 	CreateWorkflow(ctx context.Context, config WorkflowConfig) error
 }
+
+// KeepAvailability tells whether Keep requests are being sent, e.g. false
+// while the Keep client's circuit breaker is open.
+type KeepAvailability interface {
+	Available() bool
+}
+
+// AlertQuery selects Keep alerts on the Keep side. Empty filters match every
+// alert.
+type AlertQuery struct {
+	Fingerprints []string
+	Statuses     []string
+	Severities   []string
+	PageSize     int // alerts per request; the client's default when zero
+}
+
+// KeepAlertQuerier pages through the Keep alerts matching a query, so large
+// alert lists are neither fetched in one response nor held in memory at once.
+type KeepAlertQuerier interface {
+	// QueryAlerts calls handle with each page of matching alerts until the
+	// last page, stopping at the first error handle returns.
+	QueryAlerts(ctx context.Context, query AlertQuery, handle func(page []KeepAlert) error) error
+}
+
+// KeepWorkflowUpdater replaces the definition of an existing Keep workflow.
+type KeepWorkflowUpdater interface {
+	UpdateWorkflow(ctx context.Context, workflowID string, config WorkflowConfig) error
+}
+
+// Statuses of a Keep workflow execution.
+const (
+	WorkflowExecutionInProgress = "in_progress"
+	WorkflowExecutionSuccess    = "success"
+)
+
+// KeepWorkflowExecution is the outcome of a workflow run so far. Status is
+// WorkflowExecutionInProgress until the run ends; Error says why it failed.
+type KeepWorkflowExecution struct {
+	Status string
+	Error  string
+}
+
+// KeepWorkflowRunner starts Keep workflows manually.
+type KeepWorkflowRunner interface {
+	// RunWorkflow starts workflowID with body as its JSON event and returns
+	// the ID of the execution.
+	RunWorkflow(ctx context.Context, workflowID string, body []byte) (string, error)
+	// RunAlertWorkflow starts workflowID for alert, as if the alert had
+	// triggered it, and returns the ID of the execution.
+	RunAlertWorkflow(ctx context.Context, workflowID string, alert KeepAlert) (string, error)
+	GetWorkflowExecution(ctx context.Context, workflowID, executionID string) (KeepWorkflowExecution, error)
+}
+
+// KeepUser is a user of the Keep UI. Keep identifies users by their email,
+// which is what it records as the assignee of an alert.
+type KeepUser struct {
+	Email string
+	Name  string
+}
+
+// KeepUserClient lists the users of the Keep UI.
+type KeepUserClient interface {
+	GetUsers(ctx context.Context) ([]KeepUser, error)
+}
+
+// KeepIncidentClient changes the status of Keep incidents.
+type KeepIncidentClient interface {
+	// ChangeIncidentStatus sets the incident status, e.g. "acknowledged" or
+	// "resolved".
+	ChangeIncidentStatus(ctx context.Context, incidentID, status string) error
+}
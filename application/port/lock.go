@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// Locker serializes work on a key across bridge replicas.
+type Locker interface {
+	// Lock waits until key is locked or ctx ends. The lock expires after ttl
+	// even when release is never called, so a crashed replica cannot hold it
+	// forever.
+	Lock(ctx context.Context, key string, ttl time.Duration) (release func(), err error)
+}
+
+// IdempotencyStore remembers which deliveries were already handled.
+type IdempotencyStore interface {
+	Seen(ctx context.Context, key string) (bool, error)
+	// Mark records key as handled for ttl.
+	Mark(ctx context.Context, key string, ttl time.Duration) error
+}
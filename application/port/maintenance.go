@@ -0,0 +1,29 @@
+package port
+
+import "time"
+
+// Maintenance window actions.
+const (
+	// MaintenanceSuppress drops matching alerts without posting them.
+	MaintenanceSuppress = "suppress"
+	// MaintenanceAnnotate posts matching alerts as maintenance cards without
+	// buttons, copies or direct messages.
+	MaintenanceAnnotate = "annotate"
+)
+
+// MaintenanceWindow is a window that is open at a given time.
+type MaintenanceWindow struct {
+	Name   string
+	Action string // MaintenanceSuppress or MaintenanceAnnotate
+	// Until is when the window closes.
+	Until time.Time
+}
+
+// MaintenanceSchedule tells which maintenance windows are open.
+type MaintenanceSchedule interface {
+	// WindowForAlert returns the first open window whose matchers hold for
+	// the alert.
+	WindowForAlert(severity string, labels map[string]string, now time.Time) (MaintenanceWindow, bool)
+	// OpenWindows returns the names of the windows open at now.
+	OpenWindows(now time.Time) []string
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"errors"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
 )
@@ -9,6 +10,196 @@ import (
 type MattermostClient interface {
 	CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error)
 	UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error
+	DeletePost(ctx context.Context, postID string) error
 	ReplyToThread(ctx context.Context, channelID, rootID, message string) error
 	GetUser(ctx context.Context, userID string) (string, error)
 }
+
+const (
+	BadgeTargetStatus         = "status"
+	BadgeTargetChannelPurpose = "channel_purpose"
+)
+
+// MattermostStatusClient updates glanceable indicators outside of alert posts.
+type MattermostStatusClient interface {
+	// SetCustomStatus sets the bot user's custom status. An empty text clears it.
+	SetCustomStatus(ctx context.Context, emoji, text string) error
+	SetChannelPurpose(ctx context.Context, channelID, purpose string) error
+}
+
+// MattermostThreadClient posts full attachments into existing threads.
+type MattermostThreadClient interface {
+	CreateThreadPost(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error)
+}
+
+// MattermostPinClient pins posts to their channel.
+type MattermostPinClient interface {
+	PinPost(ctx context.Context, postID string) error
+}
+
+// ErrMattermostPostNotFound is returned when a post does not exist or was
+// deleted.
+var ErrMattermostPostNotFound = errors.New("mattermost post not found")
+
+// ErrMattermostPostUnreadable is returned by chat platforms that cannot read
+// posts back, so whether a post still exists cannot be checked.
+var ErrMattermostPostUnreadable = errors.New("mattermost post cannot be read")
+
+// MattermostPost is a post as Mattermost stores it.
+type MattermostPost struct {
+	ID        string
+	ChannelID string
+}
+
+// MattermostPostReader reads posts back from Mattermost, e.g. to notice
+// alert posts that were deleted.
+type MattermostPostReader interface {
+	// GetPost returns ErrMattermostPostNotFound when postID was deleted.
+	GetPost(ctx context.Context, postID string) (MattermostPost, error)
+}
+
+// What the poller does about an alert whose post someone deleted.
+const (
+	// DeletedPostRecreate posts the alert again in the same channel.
+	DeletedPostRecreate = "recreate"
+	// DeletedPostUntrack stops tracking the alert; it is posted again when
+	// it fires anew.
+	DeletedPostUntrack = "untrack"
+	// DeletedPostResolve resolves the alert in Keep and stops tracking it.
+	DeletedPostResolve = "resolve"
+)
+
+// Reaction is a single emoji reaction left by a user on a post.
+type Reaction struct {
+	UserID    string
+	EmojiName string
+}
+
+// MattermostReactionClient reads emoji reactions on alert posts.
+type MattermostReactionClient interface {
+	GetReactions(ctx context.Context, postID string) ([]Reaction, error)
+}
+
+// ReactionEvent is an emoji reaction a user just added to a post.
+type ReactionEvent struct {
+	PostID    string
+	UserID    string
+	EmojiName string
+}
+
+// MattermostReactionEvents follows the reactions users add to posts as they
+// happen.
+type MattermostReactionEvents interface {
+	// Listen calls handle for every reaction added until ctx ends or the
+	// connection to Mattermost fails.
+	Listen(ctx context.Context, handle func(ctx context.Context, event ReactionEvent)) error
+}
+
+// ReplyEvent is a reply a user just posted in a thread.
+type ReplyEvent struct {
+	PostID  string
+	RootID  string // the post that started the thread
+	UserID  string
+	Message string
+	// FromBot is set for replies by the bridge's own user, other bots and
+	// incoming webhooks.
+	FromBot bool
+}
+
+// MattermostReplyEvents follows the replies users post in threads as they
+// happen.
+type MattermostReplyEvents interface {
+	// ListenReplies calls handle for every thread reply posted until ctx
+	// ends or the connection to Mattermost fails.
+	ListenReplies(ctx context.Context, handle func(ctx context.Context, event ReplyEvent)) error
+}
+
+// MattermostDirectClient opens direct message channels between the bot and
+// other users.
+type MattermostDirectClient interface {
+	GetUserIDByUsername(ctx context.Context, username string) (string, error)
+	CreateDirectChannel(ctx context.Context, userID string) (string, error)
+}
+
+// ErrMattermostUserNotFound is returned when no Mattermost user matches a
+// lookup.
+var ErrMattermostUserNotFound = errors.New("mattermost user not found")
+
+// MattermostUserDirectory finds Mattermost users by email. Mattermost only
+// reveals emails to tokens allowed to see them.
+type MattermostUserDirectory interface {
+	// GetUsernameByEmail returns ErrMattermostUserNotFound when no user has
+	// email.
+	GetUsernameByEmail(ctx context.Context, email string) (string, error)
+}
+
+// ErrMattermostChannelNotFound is returned when a team has no channel with a
+// name.
+var ErrMattermostChannelNotFound = errors.New("mattermost channel not found")
+
+// MattermostChannelDirectory finds the channels of a team by name and creates
+// missing ones.
+type MattermostChannelDirectory interface {
+	// GetChannelIDByName returns ErrMattermostChannelNotFound when the team
+	// has no channel name.
+	GetChannelIDByName(ctx context.Context, teamID, name string) (string, error)
+	CreateChannel(ctx context.Context, teamID, name string, private bool) (string, error)
+}
+
+// MattermostMembershipClient looks up the teams, channels and user groups a
+// user belongs to.
+type MattermostMembershipClient interface {
+	IsTeamMember(ctx context.Context, teamID, userID string) (bool, error)
+	IsChannelMember(ctx context.Context, channelID, userID string) (bool, error)
+	// GetUserGroups returns group names as used in @group mentions.
+	GetUserGroups(ctx context.Context, userID string) ([]string, error)
+}
+
+// Dialog element types.
+const (
+	DialogElementText     = "text"
+	DialogElementTextarea = "textarea"
+	DialogElementSelect   = "select"
+)
+
+// Dialog is a Mattermost interactive dialog. Submitting it makes Mattermost
+// POST the entered values together with CallbackID and State to the URL the
+// dialog was opened with.
+type Dialog struct {
+	CallbackID       string
+	Title            string
+	IntroductionText string
+	SubmitLabel      string
+	State            string
+	Elements         []DialogElement
+}
+
+// DialogElement is an input of a Dialog. Options apply to select elements.
+type DialogElement struct {
+	Name        string
+	DisplayName string
+	Type        string
+	Placeholder string
+	Optional    bool
+	MaxLength   int
+	Options     []DialogOption
+}
+
+type DialogOption struct {
+	Text  string
+	Value string
+}
+
+// MattermostDialogClient opens interactive dialogs in answer to a button
+// click. The trigger ID comes with the click and expires after a few seconds.
+type MattermostDialogClient interface {
+	OpenDialog(ctx context.Context, triggerID, submitURL string, dialog Dialog) error
+}
+
+// MattermostServerIDs tells apart the IDs of several Mattermost servers. IDs
+// sent with a button click or dialog submission (post, user, trigger) are
+// qualified with the server of the channel they came from, as the client
+// returns IDs of that server.
+type MattermostServerIDs interface {
+	QualifyID(channelID, id string) string
+}
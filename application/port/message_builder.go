@@ -1,17 +1,70 @@
 package port
 
 import (
+	"time"
+
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
 )
 
 type MessageBuilder interface {
+	// ForChannel returns the builder of posts in channelID, which may look
+	// different from those in other channels.
+	ForChannel(channelID string) MessageBuilder
 	BuildFiringAttachment(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment
+	// BuildMentionMessage returns the post text of a new firing alert in
+	// channelID, mentioning whom the mention rules pick, or "".
+	BuildMentionMessage(a *alert.Alert, channelID string) string
+	BuildAssignedAttachment(a *alert.Alert, callbackURL, keepUIURL, assignee string) post.Attachment
+	BuildFlappingAttachment(a *alert.Alert, callbackURL, keepUIURL string, changes int, window time.Duration) post.Attachment
 	BuildAcknowledgedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string) post.Attachment
+	BuildSnoozedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string, until time.Time) post.Attachment
 	BuildResolvedAttachment(a *alert.Alert, keepUIURL, acknowledgedBy string) post.Attachment
 	BuildSuppressedAttachment(a *alert.Alert, keepUIURL string) post.Attachment
 	BuildPendingAttachment(a *alert.Alert, keepUIURL string) post.Attachment
 	BuildMaintenanceAttachment(a *alert.Alert, keepUIURL string) post.Attachment
+	BuildDismissedAttachment(a *alert.Alert, keepUIURL string) post.Attachment
+	BuildMergedAttachment(a *alert.Alert, keepUIURL string) post.Attachment
+	BuildExpiredAttachment(a *alert.Alert, keepUIURL string) post.Attachment
 	BuildProcessingAttachment(attachmentJSON, action string) (post.Attachment, error)
 	BuildErrorAttachment(alertName, fingerprint, keepUIURL, errorMsg string) post.Attachment
+	BuildCollapsedAttachment(alertName, fingerprint, keepUIURL string, resolvedAt time.Time) post.Attachment
+	BuildGroupRootAttachment(g *group.Group, keepUIURL string) post.Attachment
+	BuildOverflowAttachment(held []HeldAlert, keepUIURL string) post.Attachment
+	BuildQuietHoursDigestAttachment(held []DigestAlert, keepUIURL string) post.Attachment
+	BuildAlertDigestAttachment(d AlertDigest, keepUIURL string) post.Attachment
+	BuildSLOReportAttachment(results []SLOResult, from, to time.Time) post.Attachment
+	BuildStatusBoardAttachment(alerts []BoardAlert, keepUIURL string) post.Attachment
+}
+
+// IncidentMessageBuilder renders Keep incidents. Processing attachments are
+// built like those of alerts.
+type IncidentMessageBuilder interface {
+	BuildIncidentAttachment(inc *incident.Incident, callbackURL, keepUIURL string) post.Attachment
+	BuildProcessingAttachment(attachmentJSON, action string) (post.Attachment, error)
 }
+
+// HeldAlert is an alert the notification budget has not posted yet.
+type HeldAlert = attachment.HeldAlert
+
+// DigestAlert is an alert held during quiet hours, as listed in the digest.
+type DigestAlert = attachment.DigestAlert
+
+// BoardAlert is an active alert as listed on a channel's status board.
+type BoardAlert = attachment.BoardAlert
+
+// AlertDigest is the alert activity of one digest period.
+type AlertDigest = attachment.AlertDigest
+
+// NoisyAlert is an alert that fired often within a digest period.
+type NoisyAlert = attachment.NoisyAlert
+
+// ResponseTimes is how fast alerts of one severity were acknowledged and
+// resolved within a digest period.
+type ResponseTimes = attachment.ResponseTimes
+
+// SLOResult is how one response time objective fared over a report period.
+type SLOResult = attachment.SLOResult
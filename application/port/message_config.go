@@ -1,23 +1,21 @@
 package port
 
-type LabelGroupConfig struct {
-	Prefixes  []string
-	GroupName string
-	Priority  int
-}
+import "github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
 
-type MessageConfig interface {
-	ColorForSeverity(severity string) string
-	EmojiForSeverity(severity string) string
-	IsLabelExcluded(label string) bool
-	IsLabelDisplayed(label string) bool
-	RenameLabel(label string) string
-	FooterText() string
-	FooterIconURL() string
-	IsLabelGroupingEnabled() bool
-	GetLabelGroupingThreshold() int
-	GetLabelGroups() []LabelGroupConfig
-	ShowSeverityField() bool
-	ShowDescriptionField() bool
-	SeverityFieldPosition() string
-}
+type LabelGroupConfig = attachment.LabelGroup
+
+// CopyCommand is a named text/template rendered from alert data, offered by
+// the Commands button as a ready-to-copy command line.
+type CopyCommand = attachment.CopyCommand
+
+// Link is a titled URL shown with an alert, e.g. its runbook.
+type Link = attachment.Link
+
+// EnrichmentField is a Keep enrichment shown as a field of alert posts.
+type EnrichmentField = attachment.EnrichmentField
+
+// SourceLinks configures the Source field of alert posts.
+type SourceLinks = attachment.SourceLinks
+
+// MessageConfig styles the attachments the MessageBuilder renders.
+type MessageConfig = attachment.Style
@@ -0,0 +1,17 @@
+package port
+
+// PermissionRule limits who may apply alert actions. A rule applies to an
+// action on an alert when both Actions and Severities are empty or contain
+// it; the user then has to be listed in Users, or belong to one of Teams,
+// Channels or Groups. When several rules apply, passing any one is enough.
+type PermissionRule struct {
+	Actions    []string
+	Severities []string
+	// Users are Mattermost usernames without the leading @.
+	Users []string
+	// Teams and Channels are Mattermost IDs.
+	Teams    []string
+	Channels []string
+	// Groups are user group names as used in @group mentions.
+	Groups []string
+}
@@ -0,0 +1,444 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package portmock
+
+import (
+	"context"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"sync"
+)
+
+// Ensure, that KeepClientMock does implement port.KeepClient.
+// If this is not the case, regenerate this file with moq.
+var _ port.KeepClient = &KeepClientMock{}
+
+// KeepClientMock is a mock implementation of port.KeepClient.
+//
+//	func TestSomethingThatUsesKeepClient(t *testing.T) {
+//
+//		// make and configure a mocked port.KeepClient
+//		mockedKeepClient := &KeepClientMock{
+//			CreateWebhookProviderFunc: func(ctx context.Context, config port.WebhookProviderConfig) error {
+//				panic("mock out the CreateWebhookProvider method")
+//			},
+//			CreateWorkflowFunc: func(ctx context.Context, config port.WorkflowConfig) error {
+//				panic("mock out the CreateWorkflow method")
+//			},
+//			EnrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+//				panic("mock out the EnrichAlert method")
+//			},
+//			GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+//				panic("mock out the GetAlert method")
+//			},
+//			GetAlertsFunc: func(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error) {
+//				panic("mock out the GetAlerts method")
+//			},
+//			GetProvidersFunc: func(ctx context.Context) ([]port.KeepProvider, error) {
+//				panic("mock out the GetProviders method")
+//			},
+//			GetWorkflowsFunc: func(ctx context.Context) ([]port.KeepWorkflow, error) {
+//				panic("mock out the GetWorkflows method")
+//			},
+//			UnenrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments []string) error {
+//				panic("mock out the UnenrichAlert method")
+//			},
+//		}
+//
+//		// use mockedKeepClient in code that requires port.KeepClient
+//		// and then make assertions.
+//
+//	}
+type KeepClientMock struct {
+	// CreateWebhookProviderFunc mocks the CreateWebhookProvider method.
+	CreateWebhookProviderFunc func(ctx context.Context, config port.WebhookProviderConfig) error
+
+	// CreateWorkflowFunc mocks the CreateWorkflow method.
+	CreateWorkflowFunc func(ctx context.Context, config port.WorkflowConfig) error
+
+	// EnrichAlertFunc mocks the EnrichAlert method.
+	EnrichAlertFunc func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error
+
+	// GetAlertFunc mocks the GetAlert method.
+	GetAlertFunc func(ctx context.Context, fingerprint string) (*port.KeepAlert, error)
+
+	// GetAlertsFunc mocks the GetAlerts method.
+	GetAlertsFunc func(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error)
+
+	// GetProvidersFunc mocks the GetProviders method.
+	GetProvidersFunc func(ctx context.Context) ([]port.KeepProvider, error)
+
+	// GetWorkflowsFunc mocks the GetWorkflows method.
+	GetWorkflowsFunc func(ctx context.Context) ([]port.KeepWorkflow, error)
+
+	// UnenrichAlertFunc mocks the UnenrichAlert method.
+	UnenrichAlertFunc func(ctx context.Context, fingerprint string, enrichments []string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateWebhookProvider holds details about calls to the CreateWebhookProvider method.
+		CreateWebhookProvider []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Config is the config argument value.
+			Config port.WebhookProviderConfig
+		}
+		// CreateWorkflow holds details about calls to the CreateWorkflow method.
+		CreateWorkflow []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Config is the config argument value.
+			Config port.WorkflowConfig
+		}
+		// EnrichAlert holds details about calls to the EnrichAlert method.
+		EnrichAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fingerprint is the fingerprint argument value.
+			Fingerprint string
+			// Enrichments is the enrichments argument value.
+			Enrichments map[string]string
+			// Opts is the opts argument value.
+			Opts port.EnrichOptions
+		}
+		// GetAlert holds details about calls to the GetAlert method.
+		GetAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fingerprint is the fingerprint argument value.
+			Fingerprint string
+		}
+		// GetAlerts holds details about calls to the GetAlerts method.
+		GetAlerts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+			// Fingerprints is the fingerprints argument value.
+			Fingerprints []string
+		}
+		// GetProviders holds details about calls to the GetProviders method.
+		GetProviders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetWorkflows holds details about calls to the GetWorkflows method.
+		GetWorkflows []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// UnenrichAlert holds details about calls to the UnenrichAlert method.
+		UnenrichAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fingerprint is the fingerprint argument value.
+			Fingerprint string
+			// Enrichments is the enrichments argument value.
+			Enrichments []string
+		}
+	}
+	lockCreateWebhookProvider sync.RWMutex
+	lockCreateWorkflow        sync.RWMutex
+	lockEnrichAlert           sync.RWMutex
+	lockGetAlert              sync.RWMutex
+	lockGetAlerts             sync.RWMutex
+	lockGetProviders          sync.RWMutex
+	lockGetWorkflows          sync.RWMutex
+	lockUnenrichAlert         sync.RWMutex
+}
+
+// CreateWebhookProvider calls CreateWebhookProviderFunc.
+func (mock *KeepClientMock) CreateWebhookProvider(ctx context.Context, config port.WebhookProviderConfig) error {
+	if mock.CreateWebhookProviderFunc == nil {
+		panic("KeepClientMock.CreateWebhookProviderFunc: method is nil but KeepClient.CreateWebhookProvider was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Config port.WebhookProviderConfig
+	}{
+		Ctx:    ctx,
+		Config: config,
+	}
+	mock.lockCreateWebhookProvider.Lock()
+	mock.calls.CreateWebhookProvider = append(mock.calls.CreateWebhookProvider, callInfo)
+	mock.lockCreateWebhookProvider.Unlock()
+	return mock.CreateWebhookProviderFunc(ctx, config)
+}
+
+// CreateWebhookProviderCalls gets all the calls that were made to CreateWebhookProvider.
+// Check the length with:
+//
+//	len(mockedKeepClient.CreateWebhookProviderCalls())
+func (mock *KeepClientMock) CreateWebhookProviderCalls() []struct {
+	Ctx    context.Context
+	Config port.WebhookProviderConfig
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Config port.WebhookProviderConfig
+	}
+	mock.lockCreateWebhookProvider.RLock()
+	calls = mock.calls.CreateWebhookProvider
+	mock.lockCreateWebhookProvider.RUnlock()
+	return calls
+}
+
+// CreateWorkflow calls CreateWorkflowFunc.
+func (mock *KeepClientMock) CreateWorkflow(ctx context.Context, config port.WorkflowConfig) error {
+	if mock.CreateWorkflowFunc == nil {
+		panic("KeepClientMock.CreateWorkflowFunc: method is nil but KeepClient.CreateWorkflow was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Config port.WorkflowConfig
+	}{
+		Ctx:    ctx,
+		Config: config,
+	}
+	mock.lockCreateWorkflow.Lock()
+	mock.calls.CreateWorkflow = append(mock.calls.CreateWorkflow, callInfo)
+	mock.lockCreateWorkflow.Unlock()
+	return mock.CreateWorkflowFunc(ctx, config)
+}
+
+// CreateWorkflowCalls gets all the calls that were made to CreateWorkflow.
+// Check the length with:
+//
+//	len(mockedKeepClient.CreateWorkflowCalls())
+func (mock *KeepClientMock) CreateWorkflowCalls() []struct {
+	Ctx    context.Context
+	Config port.WorkflowConfig
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Config port.WorkflowConfig
+	}
+	mock.lockCreateWorkflow.RLock()
+	calls = mock.calls.CreateWorkflow
+	mock.lockCreateWorkflow.RUnlock()
+	return calls
+}
+
+// EnrichAlert calls EnrichAlertFunc.
+func (mock *KeepClientMock) EnrichAlert(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+	if mock.EnrichAlertFunc == nil {
+		panic("KeepClientMock.EnrichAlertFunc: method is nil but KeepClient.EnrichAlert was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Fingerprint string
+		Enrichments map[string]string
+		Opts        port.EnrichOptions
+	}{
+		Ctx:         ctx,
+		Fingerprint: fingerprint,
+		Enrichments: enrichments,
+		Opts:        opts,
+	}
+	mock.lockEnrichAlert.Lock()
+	mock.calls.EnrichAlert = append(mock.calls.EnrichAlert, callInfo)
+	mock.lockEnrichAlert.Unlock()
+	return mock.EnrichAlertFunc(ctx, fingerprint, enrichments, opts)
+}
+
+// EnrichAlertCalls gets all the calls that were made to EnrichAlert.
+// Check the length with:
+//
+//	len(mockedKeepClient.EnrichAlertCalls())
+func (mock *KeepClientMock) EnrichAlertCalls() []struct {
+	Ctx         context.Context
+	Fingerprint string
+	Enrichments map[string]string
+	Opts        port.EnrichOptions
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Fingerprint string
+		Enrichments map[string]string
+		Opts        port.EnrichOptions
+	}
+	mock.lockEnrichAlert.RLock()
+	calls = mock.calls.EnrichAlert
+	mock.lockEnrichAlert.RUnlock()
+	return calls
+}
+
+// GetAlert calls GetAlertFunc.
+func (mock *KeepClientMock) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+	if mock.GetAlertFunc == nil {
+		panic("KeepClientMock.GetAlertFunc: method is nil but KeepClient.GetAlert was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Fingerprint string
+	}{
+		Ctx:         ctx,
+		Fingerprint: fingerprint,
+	}
+	mock.lockGetAlert.Lock()
+	mock.calls.GetAlert = append(mock.calls.GetAlert, callInfo)
+	mock.lockGetAlert.Unlock()
+	return mock.GetAlertFunc(ctx, fingerprint)
+}
+
+// GetAlertCalls gets all the calls that were made to GetAlert.
+// Check the length with:
+//
+//	len(mockedKeepClient.GetAlertCalls())
+func (mock *KeepClientMock) GetAlertCalls() []struct {
+	Ctx         context.Context
+	Fingerprint string
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Fingerprint string
+	}
+	mock.lockGetAlert.RLock()
+	calls = mock.calls.GetAlert
+	mock.lockGetAlert.RUnlock()
+	return calls
+}
+
+// GetAlerts calls GetAlertsFunc.
+func (mock *KeepClientMock) GetAlerts(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error) {
+	if mock.GetAlertsFunc == nil {
+		panic("KeepClientMock.GetAlertsFunc: method is nil but KeepClient.GetAlerts was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Limit        int
+		Fingerprints []string
+	}{
+		Ctx:          ctx,
+		Limit:        limit,
+		Fingerprints: fingerprints,
+	}
+	mock.lockGetAlerts.Lock()
+	mock.calls.GetAlerts = append(mock.calls.GetAlerts, callInfo)
+	mock.lockGetAlerts.Unlock()
+	return mock.GetAlertsFunc(ctx, limit, fingerprints)
+}
+
+// GetAlertsCalls gets all the calls that were made to GetAlerts.
+// Check the length with:
+//
+//	len(mockedKeepClient.GetAlertsCalls())
+func (mock *KeepClientMock) GetAlertsCalls() []struct {
+	Ctx          context.Context
+	Limit        int
+	Fingerprints []string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Limit        int
+		Fingerprints []string
+	}
+	mock.lockGetAlerts.RLock()
+	calls = mock.calls.GetAlerts
+	mock.lockGetAlerts.RUnlock()
+	return calls
+}
+
+// GetProviders calls GetProvidersFunc.
+func (mock *KeepClientMock) GetProviders(ctx context.Context) ([]port.KeepProvider, error) {
+	if mock.GetProvidersFunc == nil {
+		panic("KeepClientMock.GetProvidersFunc: method is nil but KeepClient.GetProviders was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetProviders.Lock()
+	mock.calls.GetProviders = append(mock.calls.GetProviders, callInfo)
+	mock.lockGetProviders.Unlock()
+	return mock.GetProvidersFunc(ctx)
+}
+
+// GetProvidersCalls gets all the calls that were made to GetProviders.
+// Check the length with:
+//
+//	len(mockedKeepClient.GetProvidersCalls())
+func (mock *KeepClientMock) GetProvidersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetProviders.RLock()
+	calls = mock.calls.GetProviders
+	mock.lockGetProviders.RUnlock()
+	return calls
+}
+
+// GetWorkflows calls GetWorkflowsFunc.
+func (mock *KeepClientMock) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error) {
+	if mock.GetWorkflowsFunc == nil {
+		panic("KeepClientMock.GetWorkflowsFunc: method is nil but KeepClient.GetWorkflows was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetWorkflows.Lock()
+	mock.calls.GetWorkflows = append(mock.calls.GetWorkflows, callInfo)
+	mock.lockGetWorkflows.Unlock()
+	return mock.GetWorkflowsFunc(ctx)
+}
+
+// GetWorkflowsCalls gets all the calls that were made to GetWorkflows.
+// Check the length with:
+//
+//	len(mockedKeepClient.GetWorkflowsCalls())
+func (mock *KeepClientMock) GetWorkflowsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetWorkflows.RLock()
+	calls = mock.calls.GetWorkflows
+	mock.lockGetWorkflows.RUnlock()
+	return calls
+}
+
+// UnenrichAlert calls UnenrichAlertFunc.
+func (mock *KeepClientMock) UnenrichAlert(ctx context.Context, fingerprint string, enrichments []string) error {
+	if mock.UnenrichAlertFunc == nil {
+		panic("KeepClientMock.UnenrichAlertFunc: method is nil but KeepClient.UnenrichAlert was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Fingerprint string
+		Enrichments []string
+	}{
+		Ctx:         ctx,
+		Fingerprint: fingerprint,
+		Enrichments: enrichments,
+	}
+	mock.lockUnenrichAlert.Lock()
+	mock.calls.UnenrichAlert = append(mock.calls.UnenrichAlert, callInfo)
+	mock.lockUnenrichAlert.Unlock()
+	return mock.UnenrichAlertFunc(ctx, fingerprint, enrichments)
+}
+
+// UnenrichAlertCalls gets all the calls that were made to UnenrichAlert.
+// Check the length with:
+//
+//	len(mockedKeepClient.UnenrichAlertCalls())
+func (mock *KeepClientMock) UnenrichAlertCalls() []struct {
+	Ctx         context.Context
+	Fingerprint string
+	Enrichments []string
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Fingerprint string
+		Enrichments []string
+	}
+	mock.lockUnenrichAlert.RLock()
+	calls = mock.calls.UnenrichAlert
+	mock.lockUnenrichAlert.RUnlock()
+	return calls
+}
@@ -0,0 +1,307 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package portmock
+
+import (
+	"context"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"sync"
+)
+
+// Ensure, that MattermostClientMock does implement port.MattermostClient.
+// If this is not the case, regenerate this file with moq.
+var _ port.MattermostClient = &MattermostClientMock{}
+
+// MattermostClientMock is a mock implementation of port.MattermostClient.
+//
+//	func TestSomethingThatUsesMattermostClient(t *testing.T) {
+//
+//		// make and configure a mocked port.MattermostClient
+//		mockedMattermostClient := &MattermostClientMock{
+//			CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+//				panic("mock out the CreatePost method")
+//			},
+//			DeletePostFunc: func(ctx context.Context, postID string) error {
+//				panic("mock out the DeletePost method")
+//			},
+//			GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+//				panic("mock out the GetUser method")
+//			},
+//			ReplyToThreadFunc: func(ctx context.Context, channelID string, rootID string, message string) error {
+//				panic("mock out the ReplyToThread method")
+//			},
+//			UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+//				panic("mock out the UpdatePost method")
+//			},
+//		}
+//
+//		// use mockedMattermostClient in code that requires port.MattermostClient
+//		// and then make assertions.
+//
+//	}
+type MattermostClientMock struct {
+	// CreatePostFunc mocks the CreatePost method.
+	CreatePostFunc func(ctx context.Context, channelID string, attachment post.Attachment) (string, error)
+
+	// DeletePostFunc mocks the DeletePost method.
+	DeletePostFunc func(ctx context.Context, postID string) error
+
+	// GetUserFunc mocks the GetUser method.
+	GetUserFunc func(ctx context.Context, userID string) (string, error)
+
+	// ReplyToThreadFunc mocks the ReplyToThread method.
+	ReplyToThreadFunc func(ctx context.Context, channelID string, rootID string, message string) error
+
+	// UpdatePostFunc mocks the UpdatePost method.
+	UpdatePostFunc func(ctx context.Context, postID string, attachment post.Attachment) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreatePost holds details about calls to the CreatePost method.
+		CreatePost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Attachment is the attachment argument value.
+			Attachment post.Attachment
+		}
+		// DeletePost holds details about calls to the DeletePost method.
+		DeletePost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PostID is the postID argument value.
+			PostID string
+		}
+		// GetUser holds details about calls to the GetUser method.
+		GetUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID string
+		}
+		// ReplyToThread holds details about calls to the ReplyToThread method.
+		ReplyToThread []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// RootID is the rootID argument value.
+			RootID string
+			// Message is the message argument value.
+			Message string
+		}
+		// UpdatePost holds details about calls to the UpdatePost method.
+		UpdatePost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PostID is the postID argument value.
+			PostID string
+			// Attachment is the attachment argument value.
+			Attachment post.Attachment
+		}
+	}
+	lockCreatePost    sync.RWMutex
+	lockDeletePost    sync.RWMutex
+	lockGetUser       sync.RWMutex
+	lockReplyToThread sync.RWMutex
+	lockUpdatePost    sync.RWMutex
+}
+
+// CreatePost calls CreatePostFunc.
+func (mock *MattermostClientMock) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+	if mock.CreatePostFunc == nil {
+		panic("MattermostClientMock.CreatePostFunc: method is nil but MattermostClient.CreatePost was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ChannelID  string
+		Attachment post.Attachment
+	}{
+		Ctx:        ctx,
+		ChannelID:  channelID,
+		Attachment: attachment,
+	}
+	mock.lockCreatePost.Lock()
+	mock.calls.CreatePost = append(mock.calls.CreatePost, callInfo)
+	mock.lockCreatePost.Unlock()
+	return mock.CreatePostFunc(ctx, channelID, attachment)
+}
+
+// CreatePostCalls gets all the calls that were made to CreatePost.
+// Check the length with:
+//
+//	len(mockedMattermostClient.CreatePostCalls())
+func (mock *MattermostClientMock) CreatePostCalls() []struct {
+	Ctx        context.Context
+	ChannelID  string
+	Attachment post.Attachment
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ChannelID  string
+		Attachment post.Attachment
+	}
+	mock.lockCreatePost.RLock()
+	calls = mock.calls.CreatePost
+	mock.lockCreatePost.RUnlock()
+	return calls
+}
+
+// DeletePost calls DeletePostFunc.
+func (mock *MattermostClientMock) DeletePost(ctx context.Context, postID string) error {
+	if mock.DeletePostFunc == nil {
+		panic("MattermostClientMock.DeletePostFunc: method is nil but MattermostClient.DeletePost was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		PostID string
+	}{
+		Ctx:    ctx,
+		PostID: postID,
+	}
+	mock.lockDeletePost.Lock()
+	mock.calls.DeletePost = append(mock.calls.DeletePost, callInfo)
+	mock.lockDeletePost.Unlock()
+	return mock.DeletePostFunc(ctx, postID)
+}
+
+// DeletePostCalls gets all the calls that were made to DeletePost.
+// Check the length with:
+//
+//	len(mockedMattermostClient.DeletePostCalls())
+func (mock *MattermostClientMock) DeletePostCalls() []struct {
+	Ctx    context.Context
+	PostID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		PostID string
+	}
+	mock.lockDeletePost.RLock()
+	calls = mock.calls.DeletePost
+	mock.lockDeletePost.RUnlock()
+	return calls
+}
+
+// GetUser calls GetUserFunc.
+func (mock *MattermostClientMock) GetUser(ctx context.Context, userID string) (string, error) {
+	if mock.GetUserFunc == nil {
+		panic("MattermostClientMock.GetUserFunc: method is nil but MattermostClient.GetUser was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID string
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetUser.Lock()
+	mock.calls.GetUser = append(mock.calls.GetUser, callInfo)
+	mock.lockGetUser.Unlock()
+	return mock.GetUserFunc(ctx, userID)
+}
+
+// GetUserCalls gets all the calls that were made to GetUser.
+// Check the length with:
+//
+//	len(mockedMattermostClient.GetUserCalls())
+func (mock *MattermostClientMock) GetUserCalls() []struct {
+	Ctx    context.Context
+	UserID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID string
+	}
+	mock.lockGetUser.RLock()
+	calls = mock.calls.GetUser
+	mock.lockGetUser.RUnlock()
+	return calls
+}
+
+// ReplyToThread calls ReplyToThreadFunc.
+func (mock *MattermostClientMock) ReplyToThread(ctx context.Context, channelID string, rootID string, message string) error {
+	if mock.ReplyToThreadFunc == nil {
+		panic("MattermostClientMock.ReplyToThreadFunc: method is nil but MattermostClient.ReplyToThread was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		RootID    string
+		Message   string
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		RootID:    rootID,
+		Message:   message,
+	}
+	mock.lockReplyToThread.Lock()
+	mock.calls.ReplyToThread = append(mock.calls.ReplyToThread, callInfo)
+	mock.lockReplyToThread.Unlock()
+	return mock.ReplyToThreadFunc(ctx, channelID, rootID, message)
+}
+
+// ReplyToThreadCalls gets all the calls that were made to ReplyToThread.
+// Check the length with:
+//
+//	len(mockedMattermostClient.ReplyToThreadCalls())
+func (mock *MattermostClientMock) ReplyToThreadCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	RootID    string
+	Message   string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		RootID    string
+		Message   string
+	}
+	mock.lockReplyToThread.RLock()
+	calls = mock.calls.ReplyToThread
+	mock.lockReplyToThread.RUnlock()
+	return calls
+}
+
+// UpdatePost calls UpdatePostFunc.
+func (mock *MattermostClientMock) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	if mock.UpdatePostFunc == nil {
+		panic("MattermostClientMock.UpdatePostFunc: method is nil but MattermostClient.UpdatePost was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		PostID     string
+		Attachment post.Attachment
+	}{
+		Ctx:        ctx,
+		PostID:     postID,
+		Attachment: attachment,
+	}
+	mock.lockUpdatePost.Lock()
+	mock.calls.UpdatePost = append(mock.calls.UpdatePost, callInfo)
+	mock.lockUpdatePost.Unlock()
+	return mock.UpdatePostFunc(ctx, postID, attachment)
+}
+
+// UpdatePostCalls gets all the calls that were made to UpdatePost.
+// Check the length with:
+//
+//	len(mockedMattermostClient.UpdatePostCalls())
+func (mock *MattermostClientMock) UpdatePostCalls() []struct {
+	Ctx        context.Context
+	PostID     string
+	Attachment post.Attachment
+} {
+	var calls []struct {
+		Ctx        context.Context
+		PostID     string
+		Attachment post.Attachment
+	}
+	mock.lockUpdatePost.RLock()
+	calls = mock.calls.UpdatePost
+	mock.lockUpdatePost.RUnlock()
+	return calls
+}
@@ -0,0 +1,36 @@
+package port
+
+import "time"
+
+// Quiet hours actions for new firing alerts.
+const (
+	// QuietNotify posts matching alerts as usual.
+	QuietNotify = "notify"
+	// QuietMute posts matching alerts without mentions.
+	QuietMute = "mute"
+	// QuietRoute posts matching alerts to the decision's channel instead of
+	// their routed channels.
+	QuietRoute = "route"
+	// QuietHold holds matching alerts back until quiet hours end, when they
+	// are listed in a digest and posted if still firing.
+	QuietHold = "hold"
+)
+
+// QuietDecision is what quiet hours do with a new firing alert.
+type QuietDecision struct {
+	Action    string // QuietNotify, QuietMute, QuietRoute or QuietHold
+	ChannelID string // for QuietRoute
+}
+
+// QuietHours tells whether quiet hours are on and how they treat alerts.
+type QuietHours interface {
+	// Decide returns the decision of the first rule matching an alert of
+	// severity routed to channelID. It reports false outside quiet hours or
+	// when no rule matches.
+	Decide(severity, channelID string, now time.Time) (QuietDecision, bool)
+	// Active reports whether quiet hours are on at now.
+	Active(now time.Time) bool
+	// DigestChannelID returns the channel digests are posted to, or "" to
+	// post each digest in the channel its alerts were routed to.
+	DigestChannelID() string
+}
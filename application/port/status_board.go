@@ -0,0 +1,11 @@
+package port
+
+import "context"
+
+// StatusBoardStore remembers the status board post of each channel, so every
+// replica and restart keeps updating the same post.
+type StatusBoardStore interface {
+	// GetStatusBoards returns the board post IDs by channel ID.
+	GetStatusBoards(ctx context.Context) (map[string]string, error)
+	SetStatusBoard(ctx context.Context, channelID, postID string) error
+}
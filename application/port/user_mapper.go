@@ -1,5 +1,10 @@
 package port
 
+import (
+	"context"
+	"time"
+)
+
 // UserMapper translates between Mattermost and Keep usernames.
 // Used to assign alerts to the corresponding Keep user when
 // a Mattermost user acknowledges or resolves an alert.
@@ -13,3 +18,13 @@ type UserMapper interface {
 	// Returns the Mattermost username and true if mapping exists, or empty string and false if not found.
 	GetMattermostUsername(keepUsername string) (string, bool)
 }
+
+// UserEmailCache remembers which Mattermost user an email belongs to, so
+// user auto-mapping does not look every Keep user up on each refresh.
+type UserEmailCache interface {
+	// GetUsername returns the cached username of email and whether it was
+	// cached. A cached empty username records that no Mattermost user has
+	// the email.
+	GetUsername(ctx context.Context, email string) (username string, ok bool, err error)
+	SetUsername(ctx context.Context, email, username string, ttl time.Duration) error
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// ActiveAlerts lets operators inspect the alerts the bridge tracks and drop
+// stale ones, e.g. alerts resolved in Keep while the bridge was down.
+type ActiveAlerts struct {
+	postRepo post.Repository
+	clock    clock.Clock
+	logger   *slog.Logger
+}
+
+func NewActiveAlerts(postRepo post.Repository, logger *slog.Logger) *ActiveAlerts {
+	return &ActiveAlerts{
+		postRepo: postRepo,
+		clock:    clock.Real(),
+		logger:   logger,
+	}
+}
+
+// SetClock replaces the clock used to compute alert ages.
+func (a *ActiveAlerts) SetClock(c clock.Clock) {
+	a.clock = c
+}
+
+// List returns every tracked alert, oldest first.
+func (a *ActiveAlerts) List(ctx context.Context) ([]dto.ActiveAlertOutput, error) {
+	posts, err := a.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find active posts: %w", err)
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].FiringStartTime().Before(posts[j].FiringStartTime()) })
+
+	now := a.clock.Now()
+	output := make([]dto.ActiveAlertOutput, 0, len(posts))
+	for _, p := range posts {
+		output = append(output, dto.ActiveAlertOutput{
+			Fingerprint: p.Fingerprint().Value(),
+			AlertName:   p.AlertName(),
+			Severity:    p.Severity().Value(),
+			ChannelID:   p.ChannelID(),
+			PostID:      p.PostID(),
+			Assignee:    p.LastKnownAssignee(),
+			FiringSince: p.FiringStartTime(),
+			AgeSeconds:  int64(now.Sub(p.FiringStartTime()).Seconds()),
+		})
+	}
+	return output, nil
+}
+
+// Delete forgets the alert with fingerprint. Its Mattermost post is left as
+// it is; a later webhook for the alert creates a new post. It returns
+// post.ErrNotFound for alerts the bridge does not track.
+func (a *ActiveAlerts) Delete(ctx context.Context, fingerprint string) error {
+	fp, err := alert.NewFingerprint(fingerprint)
+	if err != nil {
+		return fmt.Errorf("parse fingerprint: %w", err)
+	}
+	existing, err := a.postRepo.FindByFingerprint(ctx, fp)
+	if err != nil {
+		return err
+	}
+	if err := a.postRepo.Delete(ctx, fp); err != nil {
+		return fmt.Errorf("delete post: %w", err)
+	}
+	a.logger.Info("Active alert deleted by admin",
+		logger.ApplicationFields("active_alert_deleted",
+			slog.String("fingerprint", fingerprint),
+			slog.String("post_id", existing.PostID()),
+		),
+	)
+	return nil
+}
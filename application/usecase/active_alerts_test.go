@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func TestActiveAlertsList(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := newPostStore()
+	older := post.NewPost("post-1", "ch-1", alert.RestoreFingerprint("fp-1"), "DB down", alert.RestoreSeverity("critical"), now.Add(-time.Hour), time.Now())
+	older.SetLastKnownAssignee("john")
+	repo.posts["fp-1"] = older
+	repo.posts["fp-2"] = post.NewPost("post-2", "ch-2", alert.RestoreFingerprint("fp-2"), "Disk full", alert.RestoreSeverity("warning"), now.Add(-time.Minute), time.Now())
+
+	alerts := NewActiveAlerts(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	alerts.SetClock(clock.NewFake(now))
+
+	out, err := alerts.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []dto.ActiveAlertOutput{
+		{Fingerprint: "fp-1", AlertName: "DB down", Severity: "critical", ChannelID: "ch-1", PostID: "post-1", Assignee: "john", FiringSince: now.Add(-time.Hour), AgeSeconds: 3600},
+		{Fingerprint: "fp-2", AlertName: "Disk full", Severity: "warning", ChannelID: "ch-2", PostID: "post-2", FiringSince: now.Add(-time.Minute), AgeSeconds: 60},
+	}, out, "oldest first")
+}
+
+func TestActiveAlertsDelete(t *testing.T) {
+	repo := newPostStore()
+	repo.posts["fp-1"] = post.NewPost("post-1", "ch-1", alert.RestoreFingerprint("fp-1"), "DB down", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	alerts := NewActiveAlerts(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	require.NoError(t, alerts.Delete(ctx, "fp-1"))
+	assert.Empty(t, repo.posts)
+
+	assert.ErrorIs(t, alerts.Delete(ctx, "fp-1"), post.ErrNotFound)
+
+	repo.posts["fp-2"] = post.NewPost("post-2", "ch-1", alert.RestoreFingerprint("fp-2"), "DB down", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	repo.DeleteFunc = func(context.Context, alert.Fingerprint) error { return errors.New("redis down") }
+	assert.ErrorContains(t, alerts.Delete(ctx, "fp-2"), "redis down")
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/cron"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// AlertDigestPolicy says where and when the alert digest is posted.
+type AlertDigestPolicy struct {
+	ChannelID string
+	Schedule  *cron.Schedule
+	Period    time.Duration // covered by each digest, ending at its scheduled time
+	Top       int           // noisiest alerts listed
+}
+
+// AlertDigestUseCase posts a summary of recent alert activity, built from the
+// audit trail, each time its schedule comes due.
+type AlertDigestUseCase struct {
+	auditRepo  audit.Repository
+	keepClient port.KeepClient
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	policy     AlertDigestPolicy
+	keepUIURL  string
+	clock      clock.Clock
+	logger     *slog.Logger
+
+	mu   sync.Mutex
+	next time.Time // next scheduled digest; zero until the first run
+}
+
+func NewAlertDigestUseCase(
+	auditRepo audit.Repository,
+	keepClient port.KeepClient,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	policy AlertDigestPolicy,
+	keepUIURL string,
+	logger *slog.Logger,
+) *AlertDigestUseCase {
+	return &AlertDigestUseCase{
+		auditRepo:  auditRepo,
+		keepClient: keepClient,
+		mmClient:   mmClient,
+		msgBuilder: msgBuilder,
+		policy:     policy,
+		keepUIURL:  keepUIURL,
+		clock:      clock.Real(),
+		logger:     logger,
+	}
+}
+
+// SetClock replaces the clock that decides when digests are due.
+func (uc *AlertDigestUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Execute posts the digest once its scheduled time has passed. The first run
+// only schedules the next digest, so a restart does not post one. A digest
+// that fails to post is retried on the next run for the same period.
+func (uc *AlertDigestUseCase) Execute(ctx context.Context) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.clock.Now()
+	if uc.next.IsZero() {
+		uc.next = uc.policy.Schedule.Next(now)
+		return nil
+	}
+	if now.Before(uc.next) {
+		return nil
+	}
+
+	if err := uc.post(ctx, uc.next.Add(-uc.policy.Period), uc.next); err != nil {
+		alertDigestErrorsCounter.Inc()
+		return err
+	}
+	uc.next = uc.policy.Schedule.Next(now)
+	return nil
+}
+
+func (uc *AlertDigestUseCase) post(ctx context.Context, from, to time.Time) error {
+	events, err := uc.auditRepo.FindBetween(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("find audit events: %w", err)
+	}
+
+	d := summarizeAlerts(events, uc.policy.Top)
+	d.From, d.To = from, to
+	uc.nameNoisyAlerts(ctx, d.Noisy)
+
+	attachment := uc.msgBuilder.BuildAlertDigestAttachment(d, uc.keepUIURL)
+	if _, err := uc.mmClient.CreatePost(ctx, uc.policy.ChannelID, attachment); err != nil {
+		return fmt.Errorf("post alert digest: %w", err)
+	}
+
+	uc.logger.Info("Alert digest posted",
+		logger.ApplicationFields("alert_digest",
+			slog.Time("from", from),
+			slog.Time("to", to),
+			slog.Int("fired", d.Fired),
+			slog.Int("acknowledged", d.Acknowledged),
+			slog.Int("resolved", d.Resolved),
+		),
+	)
+	alertDigestsCounter.Inc()
+	return nil
+}
+
+// nameNoisyAlerts looks up the names of noisy alerts in Keep. Alerts Keep
+// does not return keep their fingerprint as name.
+func (uc *AlertDigestUseCase) nameNoisyAlerts(ctx context.Context, noisy []port.NoisyAlert) {
+	if len(noisy) == 0 {
+		return
+	}
+	fingerprints := make([]string, len(noisy))
+	for i, n := range noisy {
+		fingerprints[i] = n.Fingerprint
+	}
+	alerts, err := uc.keepClient.GetAlerts(ctx, len(fingerprints), fingerprints)
+	if err != nil {
+		uc.logger.Warn("Failed to get digest alerts from Keep, listing fingerprints",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	names := make(map[string]string, len(alerts))
+	for _, a := range alerts {
+		names[a.Fingerprint] = a.Name
+	}
+	for i := range noisy {
+		if name := names[noisy[i].Fingerprint]; name != "" {
+			noisy[i].Name = name
+		}
+	}
+}
+
+// alertActivity follows one alert through a digest period.
+type alertActivity struct {
+	fingerprint  string
+	fires        int
+	severity     string    // of the first firing
+	firedAt      time.Time // first firing in the period
+	acknowledged time.Time // first acknowledge after firedAt
+	resolved     time.Time // first resolve after firedAt
+}
+
+// summarizeAlerts counts the alerts that started firing among events, oldest
+// first, and the mean times until they were acknowledged and resolved, in
+// total and per severity. Alerts that fired before the period only count
+// towards their fires.
+func summarizeAlerts(events []*audit.Event, top int) port.AlertDigest {
+	d := port.AlertDigest{BySeverity: make(map[string]int), ResponseBySeverity: make(map[string]port.ResponseTimes)}
+	activity := make(map[string]*alertActivity)
+	var mtta, mttr time.Duration
+	// Sums of the response times per severity, divided into means below.
+	sums := make(map[string]*port.ResponseTimes)
+	sum := func(severity string) *port.ResponseTimes {
+		if sums[severity] == nil {
+			sums[severity] = &port.ResponseTimes{}
+		}
+		return sums[severity]
+	}
+	for _, e := range events {
+		fp := e.Fingerprint().Value()
+		a, ok := activity[fp]
+		if !ok {
+			a = &alertActivity{fingerprint: fp}
+			activity[fp] = a
+		}
+		switch e.Kind() {
+		case audit.KindReceived:
+			status, severity, ok := audit.ParseReceivedDetail(e.Detail())
+			if !ok || !status.IsFiring() {
+				continue
+			}
+			a.fires++
+			if a.firedAt.IsZero() {
+				a.firedAt = e.At()
+				a.severity = severity.String()
+				d.Fired++
+				d.BySeverity[severity.String()]++
+			}
+		case audit.KindAcknowledged:
+			if !a.firedAt.IsZero() && a.acknowledged.IsZero() {
+				a.acknowledged = e.At()
+				d.Acknowledged++
+				mtta += a.acknowledged.Sub(a.firedAt)
+				s := sum(a.severity)
+				s.Acknowledged++
+				s.MTTA += a.acknowledged.Sub(a.firedAt)
+			}
+		case audit.KindResolved:
+			if !a.firedAt.IsZero() && a.resolved.IsZero() {
+				a.resolved = e.At()
+				d.Resolved++
+				mttr += a.resolved.Sub(a.firedAt)
+				s := sum(a.severity)
+				s.Resolved++
+				s.MTTR += a.resolved.Sub(a.firedAt)
+			}
+		}
+	}
+	if d.Acknowledged > 0 {
+		d.MTTA = mtta / time.Duration(d.Acknowledged)
+	}
+	if d.Resolved > 0 {
+		d.MTTR = mttr / time.Duration(d.Resolved)
+	}
+	for severity, s := range sums {
+		if s.Acknowledged > 0 {
+			s.MTTA /= time.Duration(s.Acknowledged)
+		}
+		if s.Resolved > 0 {
+			s.MTTR /= time.Duration(s.Resolved)
+		}
+		d.ResponseBySeverity[severity] = *s
+	}
+
+	noisy := make([]*alertActivity, 0, len(activity))
+	for _, a := range activity {
+		if a.fires > 0 {
+			noisy = append(noisy, a)
+		}
+	}
+	slices.SortFunc(noisy, func(a, b *alertActivity) int {
+		return cmp.Or(cmp.Compare(b.fires, a.fires), cmp.Compare(a.fingerprint, b.fingerprint))
+	})
+	for _, a := range noisy[:min(top, len(noisy))] {
+		d.Noisy = append(d.Noisy, port.NoisyAlert{Fingerprint: a.fingerprint, Name: a.fingerprint, Fires: a.fires})
+	}
+	return d
+}
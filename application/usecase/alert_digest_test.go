@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/cron"
+)
+
+func digestEvent(fp, kind, detail string, at time.Time) *audit.Event {
+	return audit.NewEvent(alert.RestoreFingerprint(fp), kind, "", detail, at)
+}
+
+func firingDetail(severity string) string {
+	return audit.ReceivedDetail(alert.RestoreStatus(alert.StatusFiring), alert.RestoreSeverity(severity))
+}
+
+func TestSummarizeAlerts(t *testing.T) {
+	at := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	events := []*audit.Event{
+		digestEvent("fp-before", audit.KindAcknowledged, "in Mattermost", at),
+		digestEvent("fp-1", audit.KindReceived, firingDetail("critical"), at.Add(time.Minute)),
+		digestEvent("fp-1", audit.KindPosted, "post p1 in channel c1", at.Add(time.Minute)),
+		digestEvent("fp-2", audit.KindReceived, firingDetail("warning"), at.Add(2*time.Minute)),
+		digestEvent("fp-1", audit.KindAcknowledged, "in Mattermost", at.Add(11*time.Minute)),
+		digestEvent("fp-2", audit.KindReceived, firingDetail("warning"), at.Add(3*time.Minute)),
+		digestEvent("fp-2", audit.KindReceived, firingDetail("warning"), at.Add(4*time.Minute)),
+		digestEvent("fp-1", audit.KindResolved, "in Keep", at.Add(61*time.Minute)),
+		digestEvent("fp-2", audit.KindResolved, "in Keep", at.Add(32*time.Minute)),
+		digestEvent("fp-3", audit.KindReceived, firingDetail("critical"), at.Add(5*time.Minute)),
+		digestEvent("fp-4", audit.KindReceived, audit.ReceivedDetail(alert.RestoreStatus(alert.StatusResolved), alert.RestoreSeverity("info")), at.Add(5*time.Minute)),
+	}
+
+	d := summarizeAlerts(events, 2)
+
+	assert.Equal(t, 3, d.Fired)
+	assert.Equal(t, map[string]int{"critical": 2, "warning": 1}, d.BySeverity)
+	assert.Equal(t, 1, d.Acknowledged, "acknowledges of alerts fired before the period are ignored")
+	assert.Equal(t, 10*time.Minute, d.MTTA)
+	assert.Equal(t, 2, d.Resolved)
+	assert.Equal(t, 45*time.Minute, d.MTTR)
+	assert.Equal(t, map[string]port.ResponseTimes{
+		"critical": {Acknowledged: 1, MTTA: 10 * time.Minute, Resolved: 1, MTTR: time.Hour},
+		"warning":  {Resolved: 1, MTTR: 30 * time.Minute},
+	}, d.ResponseBySeverity)
+	assert.Equal(t, []port.NoisyAlert{
+		{Fingerprint: "fp-2", Name: "fp-2", Fires: 3},
+		{Fingerprint: "fp-1", Name: "fp-1", Fires: 1},
+	}, d.Noisy)
+}
+
+func TestAlertDigestUseCase(t *testing.T) {
+	start := time.Date(2026, 1, 5, 8, 30, 0, 0, time.UTC)
+	fakeClock := clock.NewFake(start)
+	schedule, err := cron.Parse("0 9 * * *", time.UTC)
+	require.NoError(t, err)
+
+	var from, to time.Time
+	auditRepo := &portmock.AuditRepositoryMock{
+		FindBetweenFunc: func(ctx context.Context, f, t time.Time) ([]*audit.Event, error) {
+			from, to = f, t
+			return []*audit.Event{
+				digestEvent("fp-1", audit.KindReceived, firingDetail("high"), f.Add(time.Hour)),
+				digestEvent("fp-2", audit.KindReceived, firingDetail("high"), f.Add(time.Hour)),
+			}, nil
+		},
+	}
+	keepClient := &portmock.KeepClientMock{
+		GetAlertsFunc: func(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error) {
+			return []port.KeepAlert{{Fingerprint: "fp-1", Name: "Disk full"}}, nil
+		},
+	}
+	postErr := errors.New("mattermost down")
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-1", postErr
+		},
+	}
+	var digest port.AlertDigest
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildAlertDigestAttachmentFunc: func(d port.AlertDigest, keepUIURL string) post.Attachment {
+			digest = d
+			return post.Attachment{Title: "digest"}
+		},
+	}
+	uc := NewAlertDigestUseCase(auditRepo, keepClient, mmClient, msgBuilder, AlertDigestPolicy{
+		ChannelID: "digest-channel",
+		Schedule:  schedule,
+		Period:    24 * time.Hour,
+		Top:       5,
+	}, "https://keep.example.com", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	uc.SetClock(fakeClock)
+	ctx := context.Background()
+
+	require.NoError(t, uc.Execute(ctx))
+	fakeClock.Advance(29 * time.Minute)
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, mmClient.CreatePostCalls(), "nothing is posted before the schedule comes due")
+
+	fakeClock.Advance(time.Minute)
+	require.Error(t, uc.Execute(ctx))
+	fakeClock.Advance(time.Minute)
+	postErr = nil
+	require.NoError(t, uc.Execute(ctx))
+
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 2, "a failed digest is retried")
+	assert.Equal(t, "digest-channel", calls[1].ChannelID)
+	assert.Equal(t, time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), to, "the retry covers the scheduled period")
+	assert.Equal(t, from, digest.From)
+	assert.Equal(t, 2, digest.Fired)
+	require.Len(t, digest.Noisy, 2)
+	assert.Equal(t, "Disk full", digest.Noisy[0].Name)
+	assert.Equal(t, "fp-2", digest.Noisy[1].Name, "alerts Keep does not return are listed by fingerprint")
+
+	fakeClock.Advance(time.Hour)
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, mmClient.CreatePostCalls(), 2, "the next digest is due tomorrow")
+}
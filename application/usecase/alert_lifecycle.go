@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// observeAcknowledged records that the alert of p was acknowledged at the
+// given time and, for its first acknowledgement, observes the time since the
+// post was created. The caller saves p.
+func observeAcknowledged(p *post.Post, at time.Time) {
+	if p.Acknowledge(at) {
+		alertTimeToAcknowledgeSeconds(p.Severity().String(), p.ChannelID()).Update(at.Sub(p.CreatedAt()).Seconds())
+	}
+}
+
+// observeResolved records that the alert of p was resolved at the given time
+// and observes the time since the post was created.
+func observeResolved(p *post.Post, at time.Time) {
+	if p.Resolve(at) {
+		alertTimeToResolveSeconds(p.Severity().String(), p.ChannelID()).Update(at.Sub(p.CreatedAt()).Seconds())
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// AlertOrder applies the webhooks of each alert one at a time, in the order
+// they arrived, so a resolve handled concurrently with the firing that
+// preceded it cannot overtake it. Webhooks for different alerts still run in
+// parallel. The order holds within one bridge; across replicas it takes
+// FingerprintLocks as well.
+type AlertOrder struct {
+	mu sync.Mutex
+	// turns holds, per fingerprint, the webhooks in arrival order. The first
+	// one is being handled; its channel is closed once it got its turn.
+	turns map[string][]chan struct{}
+}
+
+func NewAlertOrder() *AlertOrder {
+	return &AlertOrder{turns: make(map[string][]chan struct{})}
+}
+
+// Do runs fn once every call for fingerprint that came before it returned,
+// or fails when ctx ends first.
+func (o *AlertOrder) Do(ctx context.Context, fingerprint string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	turn := make(chan struct{})
+	o.mu.Lock()
+	queue := o.turns[fingerprint]
+	if len(queue) == 0 {
+		close(turn)
+	}
+	o.turns[fingerprint] = append(queue, turn)
+	o.mu.Unlock()
+	defer o.done(fingerprint, turn)
+
+	if len(queue) > 0 {
+		select {
+		case <-turn:
+			alertOrderWaitSeconds.UpdateDuration(start)
+		case <-ctx.Done():
+			alertOrderTimeoutsCounter.Inc()
+			return ctx.Err()
+		}
+	}
+	return fn(ctx)
+}
+
+// done removes turn from the queue of fingerprint and hands the turn to the
+// next webhook when turn held it.
+func (o *AlertOrder) done(fingerprint string, turn chan struct{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	queue := o.turns[fingerprint]
+	for i, t := range queue {
+		if t != turn {
+			continue
+		}
+		queue = append(queue[:i:i], queue[i+1:]...)
+		if len(queue) == 0 {
+			delete(o.turns, fingerprint)
+			return
+		}
+		o.turns[fingerprint] = queue
+		if i == 0 {
+			close(queue[0])
+		}
+		return
+	}
+}
+
+// WrapAlerts returns an alert use case that hands alerts to alerts in the
+// order they arrived, one at a time per fingerprint.
+func (o *AlertOrder) WrapAlerts(alerts port.AlertUseCase) port.AlertUseCase {
+	return &orderedAlerts{alerts: alerts, order: o}
+}
+
+type orderedAlerts struct {
+	alerts port.AlertUseCase
+	order  *AlertOrder
+}
+
+func (a *orderedAlerts) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	return a.order.Do(ctx, input.Fingerprint, func(ctx context.Context) error {
+		return a.alerts.Execute(ctx, input)
+	})
+}
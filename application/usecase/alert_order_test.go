@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+)
+
+// waitQueued waits until n webhooks for fingerprint are queued in o.
+func waitQueued(t *testing.T, o *AlertOrder, fingerprint string, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return len(o.turns[fingerprint]) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestAlertOrder_AppliesAlertsInArrivalOrder(t *testing.T) {
+	o := NewAlertOrder()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	alerts := o.WrapAlerts(&portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Status == "firing" {
+				<-release
+			}
+			mu.Lock()
+			handled = append(handled, input.Fingerprint+":"+input.Status)
+			mu.Unlock()
+			return nil
+		},
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	run := func(fingerprint, status string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: fingerprint, Status: status}))
+		}()
+	}
+	run("fp-1", "firing")
+	waitQueued(t, o, "fp-1", 1)
+	run("fp-1", "acknowledged")
+	waitQueued(t, o, "fp-1", 2)
+	run("fp-1", "resolved")
+	waitQueued(t, o, "fp-1", 3)
+
+	require.NoError(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-2", Status: "resolved"}), "other alerts do not wait")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"fp-2:resolved", "fp-1:firing", "fp-1:acknowledged", "fp-1:resolved"}, handled)
+	assert.Empty(t, o.turns, "finished alerts are forgotten")
+}
+
+func TestAlertOrder_SkipsCanceledAlerts(t *testing.T) {
+	o := NewAlertOrder()
+	release := make(chan struct{})
+	var handled []string
+	alerts := o.WrapAlerts(&portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Status == "firing" {
+				<-release
+			}
+			handled = append(handled, input.Status)
+			return nil
+		},
+	})
+
+	done := make(chan error, 2)
+	go func() {
+		done <- alerts.Execute(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1", Status: "firing"})
+	}()
+	waitQueued(t, o, "fp-1", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Status: "acknowledged"})
+	}()
+	waitQueued(t, o, "fp-1", 2)
+	cancel()
+	waitQueued(t, o, "fp-1", 1)
+
+	go func() {
+		done <- alerts.Execute(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1", Status: "resolved"})
+	}()
+	waitQueued(t, o, "fp-1", 2)
+
+	require.ErrorIs(t, <-done, context.Canceled)
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"firing", "resolved"}, handled)
+}
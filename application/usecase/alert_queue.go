@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when the queue has no room left.
+	ErrQueueFull = errors.New("alert queue full")
+	// ErrQueueClosed is returned by Enqueue once Drain has been called.
+	ErrQueueClosed = errors.New("alert queue closed")
+)
+
+type queuedAlert struct {
+	input      dto.KeepAlertInput
+	enqueuedAt time.Time
+}
+
+// AlertQueue takes alert webhooks off the HTTP request path. Enqueue returns
+// as soon as the alert is queued; a pool of workers hands queued alerts to
+// the wrapped use case and retries failures with backoff. Alerts with the
+// same fingerprint always go to the same worker, so they are handled in the
+// order they arrived.
+type AlertQueue struct {
+	alerts port.AlertUseCase
+	shards []chan queuedAlert
+	policy retry.Policy
+	clock  clock.Clock
+	logger *slog.Logger
+
+	mu      sync.RWMutex
+	closed  bool
+	started bool
+	stop    context.CancelFunc
+	stopCtx context.Context
+	wg      sync.WaitGroup
+}
+
+// NewAlertQueue returns a queue holding up to size alerts, split evenly
+// between workers. policy says how often a failed alert is retried.
+func NewAlertQueue(alerts port.AlertUseCase, size, workers int, policy retry.Policy, logger *slog.Logger) *AlertQueue {
+	workers = max(workers, 1)
+	perWorker := max(size/workers, 1)
+	shards := make([]chan queuedAlert, workers)
+	for i := range shards {
+		shards[i] = make(chan queuedAlert, perWorker)
+	}
+	stopCtx, stop := context.WithCancel(context.Background())
+	return &AlertQueue{
+		alerts:  alerts,
+		shards:  shards,
+		policy:  policy,
+		clock:   clock.Real(),
+		logger:  logger,
+		stop:    stop,
+		stopCtx: stopCtx,
+	}
+}
+
+// SetClock replaces the clock used for queue latency and retry delays.
+func (q *AlertQueue) SetClock(c clock.Clock) {
+	q.clock = c
+}
+
+// Start launches the workers. Alerts enqueued before Start wait in the queue.
+func (q *AlertQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		return
+	}
+	q.started = true
+	for _, shard := range q.shards {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for item := range shard {
+				q.process(item)
+			}
+		}()
+	}
+}
+
+// Enqueue queues an alert without waiting. It fails with ErrQueueFull when
+// the alert's worker has no room left and with ErrQueueClosed after Drain.
+func (q *AlertQueue) Enqueue(ctx context.Context, input dto.KeepAlertInput) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.shardFor(input.Fingerprint) <- queuedAlert{input: input, enqueuedAt: q.clock.Now()}:
+		alertQueueDepthGauge.Set(float64(q.Depth()))
+		return nil
+	default:
+		alertQueueRejectedCounter.Inc()
+		q.logger.Warn("Alert queue full, rejecting alert",
+			logger.ApplicationFields("alert_queue_full",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.Int("depth", q.Depth()),
+			),
+		)
+		return ErrQueueFull
+	}
+}
+
+// Depth returns the number of alerts waiting for a worker.
+func (q *AlertQueue) Depth() int {
+	var depth int
+	for _, shard := range q.shards {
+		depth += len(shard)
+	}
+	return depth
+}
+
+// Drain stops accepting alerts and waits until the workers have handled
+// every queued alert. When ctx ends first, pending retries are abandoned,
+// the remaining alerts are dropped and ctx's error is returned.
+func (q *AlertQueue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		for _, shard := range q.shards {
+			close(shard)
+		}
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *AlertQueue) shardFor(fingerprint string) chan queuedAlert {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fingerprint))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+func (q *AlertQueue) process(item queuedAlert) {
+	alertQueueDepthGauge.Set(float64(q.Depth()))
+	alertQueueWaitSeconds.Update(q.clock.Now().Sub(item.enqueuedAt).Seconds())
+
+	if q.stopCtx.Err() != nil {
+		alertQueueDroppedCounter.Inc()
+		q.logger.Warn("Alert dropped, queue drain timed out",
+			slog.String("fingerprint", item.input.Fingerprint),
+		)
+		return
+	}
+
+	attempts := max(q.policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(withReceivedAt(q.stopCtx, item.enqueuedAt), 30*time.Second)
+		err := q.alerts.Execute(ctx, item.input)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt >= attempts {
+			alertQueueFailedCounter.Inc()
+			q.logger.Error("Queued alert failed, giving up",
+				logger.ApplicationFields("alert_queue_failed",
+					slog.String("fingerprint", item.input.Fingerprint),
+					slog.Int("attempts", attempt),
+					slog.String("error", err.Error()),
+				),
+			)
+			return
+		}
+
+		delay := q.policy.Delay(attempt-1, rand.Float64())
+		q.logger.Warn("Queued alert failed, retrying",
+			slog.String("fingerprint", item.input.Fingerprint),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+		alertQueueRetriesCounter.Inc()
+		select {
+		case <-q.clock.After(delay):
+		case <-q.stopCtx.Done():
+			alertQueueDroppedCounter.Inc()
+			return
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+func newTestAlertQueue(alerts *portmock.AlertUseCaseMock, size, workers, attempts int) (*AlertQueue, *clock.Fake) {
+	policy := retry.Policy{MaxAttempts: attempts, InitialDelay: time.Second, Multiplier: 2, MaxDelay: 30 * time.Second}
+	q := NewAlertQueue(alerts, size, workers, policy, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	q.SetClock(fake)
+	return q, fake
+}
+
+func TestAlertQueueProcessesAlertsInOrderPerFingerprint(t *testing.T) {
+	var mu sync.Mutex
+	var statuses []string
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Fingerprint == "fp-1" {
+				mu.Lock()
+				statuses = append(statuses, input.Status)
+				mu.Unlock()
+			}
+			return nil
+		},
+	}
+	q, _ := newTestAlertQueue(alerts, 100, 4, 1)
+
+	for _, status := range []string{"firing", "acknowledged", "resolved"} {
+		require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1", Status: status}))
+		require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-2", Status: status}))
+	}
+	assert.Equal(t, 6, q.Depth())
+
+	q.Start()
+	require.NoError(t, q.Drain(context.Background()))
+
+	assert.Len(t, alerts.ExecuteCalls(), 6)
+	assert.Equal(t, []string{"firing", "acknowledged", "resolved"}, statuses)
+	assert.Equal(t, 0, q.Depth())
+}
+
+func TestAlertQueuePassesEnqueueTime(t *testing.T) {
+	var got time.Time
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			got, _ = receivedAt(ctx)
+			return nil
+		},
+	}
+	q, fake := newTestAlertQueue(alerts, 10, 1, 1)
+	enqueuedAt := fake.Now()
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+	fake.Advance(5 * time.Second)
+
+	q.Start()
+	require.NoError(t, q.Drain(context.Background()))
+
+	assert.Equal(t, enqueuedAt, got, "delivery latency covers the time spent queued")
+}
+
+func TestAlertQueueRejectsWhenFull(t *testing.T) {
+	q, _ := newTestAlertQueue(&portmock.AlertUseCaseMock{}, 1, 1, 1)
+
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+	assert.ErrorIs(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-2"}), ErrQueueFull)
+}
+
+func TestAlertQueueRetriesFailedAlert(t *testing.T) {
+	alerts := &portmock.AlertUseCaseMock{}
+	alerts.ExecuteFunc = func(ctx context.Context, input dto.KeepAlertInput) error {
+		if len(alerts.ExecuteCalls()) < 3 {
+			return errors.New("mattermost create post: status 502")
+		}
+		return nil
+	}
+	q, fake := newTestAlertQueue(alerts, 10, 1, 3)
+	q.Start()
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	fake.BlockUntil(1)
+	fake.Advance(2 * time.Second)
+
+	require.NoError(t, q.Drain(context.Background()))
+	assert.Len(t, alerts.ExecuteCalls(), 3)
+}
+
+func TestAlertQueueGivesUpAfterAttempts(t *testing.T) {
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			return errors.New("mattermost create post: status 502")
+		},
+	}
+	q, fake := newTestAlertQueue(alerts, 10, 1, 2)
+	q.Start()
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+
+	require.NoError(t, q.Drain(context.Background()))
+	assert.Len(t, alerts.ExecuteCalls(), 2)
+}
+
+func TestAlertQueueDrainRejectsNewAlerts(t *testing.T) {
+	q, _ := newTestAlertQueue(&portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error { return nil },
+	}, 10, 2, 1)
+	q.Start()
+
+	require.NoError(t, q.Drain(context.Background()))
+	assert.ErrorIs(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}), ErrQueueClosed)
+	require.NoError(t, q.Drain(context.Background()))
+}
+
+func TestAlertQueueDrainTimeoutAbandonsRetries(t *testing.T) {
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			return errors.New("mattermost create post: status 502")
+		},
+	}
+	q, fake := newTestAlertQueue(alerts, 10, 1, 5)
+	q.Start()
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+	require.NoError(t, q.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-2"}))
+	fake.BlockUntil(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, q.Drain(ctx), context.Canceled)
+	assert.Len(t, alerts.ExecuteCalls(), 1)
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// statsWindowMinutes is how many minutes of webhook throughput are kept.
+const statsWindowMinutes = 60
+
+// statsAgeBuckets group active alerts by how long they have been firing. An
+// alert falls in the first bucket it is younger than; the last one takes
+// the rest.
+var statsAgeBuckets = []struct {
+	label string
+	below time.Duration
+}{
+	{"0-15m", 15 * time.Minute},
+	{"15m-1h", time.Hour},
+	{"1h-4h", 4 * time.Hour},
+	{"4h-24h", 24 * time.Hour},
+	{"24h+", 0},
+}
+
+// AlertStats summarizes the tracked alerts and the webhooks handled lately
+// for external dashboards, complementing the Prometheus metrics with counts
+// that need the tracked posts, such as alert ages. Throughput is counted by
+// the alert use case WrapAlerts returns, per replica and in memory.
+type AlertStats struct {
+	postRepo post.Repository
+	clock    clock.Clock
+
+	mu      sync.Mutex
+	minutes [statsWindowMinutes]statsMinute // a ring indexed by minute
+}
+
+type statsMinute struct {
+	minute time.Time
+	counts dto.WebhookCounts
+}
+
+func NewAlertStats(postRepo post.Repository) *AlertStats {
+	return &AlertStats{
+		postRepo: postRepo,
+		clock:    clock.Real(),
+	}
+}
+
+// SetClock replaces the clock that ages alerts and times webhooks.
+func (s *AlertStats) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// WrapAlerts returns alerts with every webhook it handles counted.
+func (s *AlertStats) WrapAlerts(alerts port.AlertUseCase) port.AlertUseCase {
+	return &countedAlerts{alerts: alerts, stats: s}
+}
+
+type countedAlerts struct {
+	alerts port.AlertUseCase
+	stats  *AlertStats
+}
+
+func (a *countedAlerts) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	err := a.alerts.Execute(ctx, input)
+	a.stats.record(input.Status, err != nil)
+	return err
+}
+
+func (s *AlertStats) record(status string, failed bool) {
+	minute := s.clock.Now().Truncate(time.Minute)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot := &s.minutes[minute.Unix()/60%statsWindowMinutes]
+	if !slot.minute.Equal(minute) {
+		*slot = statsMinute{minute: minute}
+	}
+	slot.counts.Received++
+	switch status {
+	case alert.StatusFiring:
+		slot.counts.Firing++
+	case alert.StatusResolved:
+		slot.counts.Resolved++
+	}
+	if failed {
+		slot.counts.Failed++
+	}
+}
+
+// Stats returns the counts of the active alerts and the webhook throughput
+// of the last hour.
+func (s *AlertStats) Stats(ctx context.Context) (dto.AlertStatsOutput, error) {
+	posts, err := s.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return dto.AlertStatsOutput{}, fmt.Errorf("find active posts: %w", err)
+	}
+	now := s.clock.Now()
+	return dto.AlertStatsOutput{
+		GeneratedAt: now,
+		Active:      activeStats(posts, now),
+		Throughput:  s.throughput(now),
+	}, nil
+}
+
+func activeStats(posts []*post.Post, now time.Time) dto.ActiveStats {
+	bySeverity := make(map[string]int)
+	byChannel := make(map[string]int)
+	byAge := make([]int, len(statsAgeBuckets))
+	heat := make(map[string][]int)
+	for _, p := range posts {
+		severity := p.Severity().Value()
+		bySeverity[severity]++
+		byChannel[p.ChannelID()]++
+		bucket := ageBucket(now.Sub(p.FiringStartTime()))
+		byAge[bucket]++
+		if heat[severity] == nil {
+			heat[severity] = make([]int, len(statsAgeBuckets))
+		}
+		heat[severity][bucket]++
+	}
+
+	stats := dto.ActiveStats{
+		Total:      len(posts),
+		BySeverity: make([]dto.SeverityCount, 0, len(bySeverity)),
+		ByChannel:  make([]dto.ChannelCount, 0, len(byChannel)),
+		ByAge:      make([]dto.AgeCount, len(statsAgeBuckets)),
+		Heatmap:    make([]dto.SeverityAgeCount, 0, len(bySeverity)*len(statsAgeBuckets)),
+	}
+	for severity, n := range bySeverity {
+		stats.BySeverity = append(stats.BySeverity, dto.SeverityCount{Severity: severity, Count: n})
+	}
+	slices.SortFunc(stats.BySeverity, func(x, y dto.SeverityCount) int {
+		if c := cmp.Compare(alert.RestoreSeverity(y.Severity).Rank(), alert.RestoreSeverity(x.Severity).Rank()); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Severity, y.Severity)
+	})
+	for channelID, n := range byChannel {
+		stats.ByChannel = append(stats.ByChannel, dto.ChannelCount{ChannelID: channelID, Count: n})
+	}
+	slices.SortFunc(stats.ByChannel, func(x, y dto.ChannelCount) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.ChannelID, y.ChannelID)
+	})
+	for i, b := range statsAgeBuckets {
+		stats.ByAge[i] = dto.AgeCount{Age: b.label, Count: byAge[i]}
+	}
+	for _, sc := range stats.BySeverity {
+		for i, b := range statsAgeBuckets {
+			stats.Heatmap = append(stats.Heatmap, dto.SeverityAgeCount{Severity: sc.Severity, Age: b.label, Count: heat[sc.Severity][i]})
+		}
+	}
+	return stats
+}
+
+func ageBucket(age time.Duration) int {
+	for i, b := range statsAgeBuckets {
+		if age < b.below {
+			return i
+		}
+	}
+	return len(statsAgeBuckets) - 1
+}
+
+func (s *AlertStats) throughput(now time.Time) dto.ThroughputStats {
+	current := now.Truncate(time.Minute)
+	t := dto.ThroughputStats{
+		WindowSeconds: statsWindowMinutes * 60,
+		PerMinute:     make([]dto.MinuteThroughput, statsWindowMinutes),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range statsWindowMinutes {
+		minute := current.Add(-time.Duration(statsWindowMinutes-1-i) * time.Minute)
+		t.PerMinute[i].Minute = minute
+		if slot := s.minutes[minute.Unix()/60%statsWindowMinutes]; slot.minute.Equal(minute) {
+			t.PerMinute[i].WebhookCounts = slot.counts
+			t.Received += slot.counts.Received
+			t.Firing += slot.counts.Firing
+			t.Resolved += slot.counts.Resolved
+			t.Failed += slot.counts.Failed
+		}
+	}
+	return t
+}
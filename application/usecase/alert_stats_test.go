@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func TestAlertStatsActive(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	repo := newPostStore()
+	add := func(fingerprint, channelID, severity string, age time.Duration) {
+		repo.posts[fingerprint] = post.NewPost("post-"+fingerprint, channelID, alert.RestoreFingerprint(fingerprint), "Alert", alert.RestoreSeverity(severity), now.Add(-age), time.Now())
+	}
+	add("fp-1", "ch-1", "warning", time.Minute)
+	add("fp-2", "ch-1", "critical", 2*time.Hour)
+	add("fp-3", "ch-2", "critical", 3*24*time.Hour)
+
+	stats := NewAlertStats(repo)
+	stats.SetClock(clock.NewFake(now))
+
+	out, err := stats.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, out.GeneratedAt)
+	assert.Equal(t, 3, out.Active.Total)
+	assert.Equal(t, []dto.SeverityCount{{Severity: "critical", Count: 2}, {Severity: "warning", Count: 1}}, out.Active.BySeverity)
+	assert.Equal(t, []dto.ChannelCount{{ChannelID: "ch-1", Count: 2}, {ChannelID: "ch-2", Count: 1}}, out.Active.ByChannel)
+	assert.Equal(t, []dto.AgeCount{
+		{Age: "0-15m", Count: 1},
+		{Age: "15m-1h", Count: 0},
+		{Age: "1h-4h", Count: 1},
+		{Age: "4h-24h", Count: 0},
+		{Age: "24h+", Count: 1},
+	}, out.Active.ByAge)
+	require.Len(t, out.Active.Heatmap, 10, "a row for every severity and age bucket")
+	assert.Equal(t, dto.SeverityAgeCount{Severity: "critical", Age: "1h-4h", Count: 1}, out.Active.Heatmap[2])
+	assert.Equal(t, dto.SeverityAgeCount{Severity: "warning", Age: "0-15m", Count: 1}, out.Active.Heatmap[5])
+}
+
+func TestAlertStatsThroughput(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC))
+	stats := NewAlertStats(newPostStore())
+	stats.SetClock(fakeClock)
+	inner := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Fingerprint == "broken" {
+				return errors.New("mattermost down")
+			}
+			return nil
+		},
+	}
+	alerts := stats.WrapAlerts(inner)
+	ctx := context.Background()
+
+	require.NoError(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Status: "firing"}))
+	require.Error(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "broken", Status: "firing"}))
+	fakeClock.Advance(time.Minute)
+	require.NoError(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Status: "resolved"}))
+	require.NoError(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-2", Status: "acknowledged"}))
+
+	out, err := stats.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), out.Throughput.WindowSeconds)
+	assert.Equal(t, dto.WebhookCounts{Received: 4, Firing: 2, Resolved: 1, Failed: 1}, out.Throughput.WebhookCounts)
+	require.Len(t, out.Throughput.PerMinute, statsWindowMinutes)
+	last := out.Throughput.PerMinute[statsWindowMinutes-1]
+	assert.Equal(t, time.Date(2026, 1, 1, 12, 1, 0, 0, time.UTC), last.Minute)
+	assert.Equal(t, dto.WebhookCounts{Received: 2, Resolved: 1}, last.WebhookCounts)
+	assert.Equal(t, dto.WebhookCounts{Received: 2, Firing: 2, Failed: 1}, out.Throughput.PerMinute[statsWindowMinutes-2].WebhookCounts)
+
+	fakeClock.Advance(time.Hour)
+	out, err = stats.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, dto.WebhookCounts{}, out.Throughput.WebhookCounts, "webhooks older than the window are dropped")
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// streamReadBlock bounds how long one read waits for new alerts, so the
+// consumer notices shutdown and pending alerts to claim in time.
+const streamReadBlock = 2 * time.Second
+
+// AlertStreamConsumer appends alert webhooks to a durable stream and, in
+// Run, hands the alerts in the stream to the wrapped use case. An alert is
+// acknowledged only once it was handled, so alerts in flight when a bridge
+// replica crashes stay pending and are claimed by a replica after claimIdle.
+// Failed alerts are left pending the same way and dropped after
+// maxDeliveries.
+type AlertStreamConsumer struct {
+	stream        port.AlertStream
+	alerts        port.AlertUseCase
+	consumer      string
+	batch         int
+	claimIdle     time.Duration
+	maxDeliveries int64
+	clock         clock.Clock
+	logger        *slog.Logger
+}
+
+func NewAlertStreamConsumer(
+	stream port.AlertStream,
+	alerts port.AlertUseCase,
+	consumer string,
+	batch int,
+	claimIdle time.Duration,
+	maxDeliveries int,
+	logger *slog.Logger,
+) *AlertStreamConsumer {
+	return &AlertStreamConsumer{
+		stream:        stream,
+		alerts:        alerts,
+		consumer:      consumer,
+		batch:         max(batch, 1),
+		claimIdle:     claimIdle,
+		maxDeliveries: int64(max(maxDeliveries, 1)),
+		clock:         clock.Real(),
+		logger:        logger,
+	}
+}
+
+// SetClock replaces the clock used to wait after stream errors.
+func (c *AlertStreamConsumer) SetClock(cl clock.Clock) {
+	c.clock = cl
+}
+
+// Enqueue appends the alert to the stream.
+func (c *AlertStreamConsumer) Enqueue(ctx context.Context, input dto.KeepAlertInput) error {
+	if err := c.stream.Append(ctx, input); err != nil {
+		alertStreamAppendErrorsCounter.Inc()
+		return err
+	}
+	alertStreamAppendedCounter.Inc()
+	return nil
+}
+
+// Run consumes the stream until ctx is cancelled. The alert being handled
+// when ctx ends is finished first.
+func (c *AlertStreamConsumer) Run(ctx context.Context) {
+	c.logger.Info("Alert stream consumer started", slog.String("consumer", c.consumer))
+	for ctx.Err() == nil {
+		if err := c.poll(ctx); err != nil {
+			c.logger.Error("Alert stream read failed",
+				logger.ApplicationFields("alert_stream_read_failed",
+					slog.String("consumer", c.consumer),
+					slog.String("error", err.Error()),
+				),
+			)
+			select {
+			case <-c.clock.After(time.Second):
+			case <-ctx.Done():
+			}
+		}
+	}
+	c.logger.Info("Alert stream consumer stopped", slog.String("consumer", c.consumer))
+}
+
+// poll handles alerts left pending by other consumers first, then waits for
+// new ones.
+func (c *AlertStreamConsumer) poll(ctx context.Context) error {
+	claimed, err := c.stream.Claim(ctx, c.consumer, c.claimIdle, c.batch)
+	if err != nil {
+		return err
+	}
+	if len(claimed) > 0 {
+		alertStreamClaimedCounter.Add(len(claimed))
+		c.handle(ctx, claimed)
+		return nil
+	}
+
+	alerts, err := c.stream.Read(ctx, c.consumer, c.batch, streamReadBlock)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	c.handle(ctx, alerts)
+	return nil
+}
+
+func (c *AlertStreamConsumer) handle(ctx context.Context, alerts []port.StreamedAlert) {
+	// Alerts already taken from the stream are finished even when shutdown
+	// starts; the use case gets its own deadline instead.
+	ctx = context.WithoutCancel(ctx)
+
+	for _, a := range alerts {
+		execCtx := ctx
+		if !a.ReceivedAt.IsZero() {
+			execCtx = withReceivedAt(ctx, a.ReceivedAt)
+		}
+		execCtx, cancel := context.WithTimeout(execCtx, 30*time.Second)
+		execErr := c.alerts.Execute(execCtx, a.Input)
+		cancel()
+
+		if execErr != nil && a.Deliveries < c.maxDeliveries {
+			c.logger.Warn("Streamed alert failed, leaving it for redelivery",
+				slog.String("id", a.ID),
+				slog.String("fingerprint", a.Input.Fingerprint),
+				slog.Int64("deliveries", a.Deliveries),
+				slog.String("error", execErr.Error()),
+			)
+			continue
+		}
+		if execErr != nil {
+			alertStreamFailedCounter.Inc()
+			c.logger.Error("Streamed alert failed, giving up",
+				logger.ApplicationFields("alert_stream_failed",
+					slog.String("id", a.ID),
+					slog.String("fingerprint", a.Input.Fingerprint),
+					slog.Int64("deliveries", a.Deliveries),
+					slog.String("error", execErr.Error()),
+				),
+			)
+		} else {
+			alertStreamProcessedCounter.Inc()
+		}
+
+		if err := c.stream.Ack(ctx, a.ID); err != nil {
+			c.logger.Error("Failed to acknowledge streamed alert",
+				slog.String("id", a.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+)
+
+func newTestAlertStreamConsumer(stream *portmock.AlertStreamMock, alerts *portmock.AlertUseCaseMock) *AlertStreamConsumer {
+	return NewAlertStreamConsumer(stream, alerts, "bridge-1", 10, time.Minute, 3, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestAlertStreamConsumerEnqueueAppends(t *testing.T) {
+	stream := &portmock.AlertStreamMock{
+		AppendFunc: func(ctx context.Context, input dto.KeepAlertInput) error { return nil },
+	}
+	c := newTestAlertStreamConsumer(stream, &portmock.AlertUseCaseMock{})
+
+	require.NoError(t, c.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+	require.Len(t, stream.AppendCalls(), 1)
+	assert.Equal(t, "fp-1", stream.AppendCalls()[0].Input.Fingerprint)
+
+	stream.AppendFunc = func(ctx context.Context, input dto.KeepAlertInput) error { return errors.New("valkey down") }
+	assert.Error(t, c.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+}
+
+func TestAlertStreamConsumerHandlesNewAlerts(t *testing.T) {
+	stream := &portmock.AlertStreamMock{
+		ClaimFunc: func(ctx context.Context, consumer string, minIdle time.Duration, count int) ([]port.StreamedAlert, error) {
+			return nil, nil
+		},
+		ReadFunc: func(ctx context.Context, consumer string, count int, block time.Duration) ([]port.StreamedAlert, error) {
+			return []port.StreamedAlert{
+				{ID: "1-0", Input: dto.KeepAlertInput{Fingerprint: "fp-1"}, Deliveries: 1},
+				{ID: "2-0", Input: dto.KeepAlertInput{Fingerprint: "fp-2"}, Deliveries: 1},
+			}, nil
+		},
+		AckFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Fingerprint == "fp-2" {
+				return errors.New("mattermost create post: status 502")
+			}
+			return nil
+		},
+	}
+	c := newTestAlertStreamConsumer(stream, alerts)
+
+	require.NoError(t, c.poll(context.Background()))
+
+	assert.Len(t, alerts.ExecuteCalls(), 2)
+	assert.Equal(t, "bridge-1", stream.ReadCalls()[0].Consumer)
+	require.Len(t, stream.AckCalls(), 1, "the failed alert stays pending")
+	assert.Equal(t, "1-0", stream.AckCalls()[0].ID)
+}
+
+func TestAlertStreamConsumerClaimsPendingAlertsFirst(t *testing.T) {
+	stream := &portmock.AlertStreamMock{
+		ClaimFunc: func(ctx context.Context, consumer string, minIdle time.Duration, count int) ([]port.StreamedAlert, error) {
+			assert.Equal(t, time.Minute, minIdle)
+			return []port.StreamedAlert{
+				{ID: "1-0", Input: dto.KeepAlertInput{Fingerprint: "fp-1"}, Deliveries: 3},
+			}, nil
+		},
+		AckFunc: func(ctx context.Context, id string) error { return nil },
+	}
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			return errors.New("mattermost create post: status 502")
+		},
+	}
+	c := newTestAlertStreamConsumer(stream, alerts)
+
+	require.NoError(t, c.poll(context.Background()))
+
+	assert.Empty(t, stream.ReadCalls(), "new alerts wait until pending ones are handled")
+	require.Len(t, stream.AckCalls(), 1, "the alert is dropped after max deliveries")
+	assert.Equal(t, "1-0", stream.AckCalls()[0].ID)
+}
+
+func TestAlertStreamConsumerFinishesAlertsOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &portmock.AlertStreamMock{
+		ClaimFunc: func(ctx context.Context, consumer string, minIdle time.Duration, count int) ([]port.StreamedAlert, error) {
+			return nil, nil
+		},
+		ReadFunc: func(ctx context.Context, consumer string, count int, block time.Duration) ([]port.StreamedAlert, error) {
+			cancel()
+			return []port.StreamedAlert{{ID: "1-0", Input: dto.KeepAlertInput{Fingerprint: "fp-1"}, Deliveries: 1}}, nil
+		},
+		AckFunc: func(ctx context.Context, id string) error {
+			assert.NoError(t, ctx.Err())
+			return nil
+		},
+	}
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			assert.NoError(t, ctx.Err(), "shutdown does not cancel an alert in flight")
+			return nil
+		},
+	}
+	c := newTestAlertStreamConsumer(stream, alerts)
+
+	c.Run(ctx)
+
+	assert.Len(t, alerts.ExecuteCalls(), 1)
+	assert.Len(t, stream.ReadCalls(), 1)
+	assert.Len(t, stream.AckCalls(), 1)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"math"
+	"time"
+)
+
+// AssigneeRetryPolicy controls how long an acknowledge webhook waits for Keep
+// to expose the assignee enrichment. Keep applies enrichments asynchronously,
+// so the webhook can arrive before GetAlert returns the assignee.
+type AssigneeRetryPolicy struct {
+	// Attempts is the total number of GetAlert calls, including the first one.
+	Attempts int
+	// InitialDelay is the wait before the second attempt.
+	InitialDelay time.Duration
+	// Multiplier grows each following delay. Values below 1 are treated as 1.
+	Multiplier float64
+	// MaxDelay caps a single delay. Zero means no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each delay by up to this fraction in either direction,
+	// e.g. 0.2 turns 100ms into 80-120ms. Zero disables jitter.
+	Jitter float64
+	// Deadline bounds the total time spent waiting. A retry whose delay would
+	// cross it is skipped. Zero means no deadline.
+	Deadline time.Duration
+}
+
+// DefaultAssigneeRetryPolicy returns 4 attempts with 100ms, 200ms and 400ms
+// between them.
+func DefaultAssigneeRetryPolicy() AssigneeRetryPolicy {
+	return AssigneeRetryPolicy{
+		Attempts:     4,
+		InitialDelay: 100 * time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     2 * time.Second,
+		Deadline:     2 * time.Second,
+	}
+}
+
+// delay returns the wait after the given zero-based retry. r is a uniform
+// random number in [0, 1) used for jitter.
+func (p AssigneeRetryPolicy) delay(retry int, r float64) time.Duration {
+	mult := math.Max(p.Multiplier, 1)
+	d := float64(p.InitialDelay) * math.Pow(mult, float64(retry))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*r - 1)
+	}
+	return time.Duration(math.Max(d, 0))
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// AuditTrail records the lifecycle of every alert for postmortems: when it
+// was received, posted, re-fired, escalated, and who acknowledged, snoozed or
+// resolved it. Recording failures are logged and never fail the caller.
+type AuditTrail struct {
+	repo   audit.Repository
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+func NewAuditTrail(repo audit.Repository, logger *slog.Logger) *AuditTrail {
+	return &AuditTrail{
+		repo:   repo,
+		clock:  clock.Real(),
+		logger: logger,
+	}
+}
+
+// SetClock replaces the clock that timestamps events.
+func (t *AuditTrail) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// Record appends an event to the alert's history. A nil trail records
+// nothing, so use cases can call it whether or not auditing is enabled.
+func (t *AuditTrail) Record(ctx context.Context, fingerprint alert.Fingerprint, kind, actor, detail string) {
+	if t == nil {
+		return
+	}
+	if err := t.repo.Append(ctx, audit.NewEvent(fingerprint, kind, actor, detail, t.clock.Now())); err != nil {
+		t.logger.Warn("Failed to record audit event",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("kind", kind),
+			slog.String("error", err.Error()),
+		)
+		auditErrorsCounter.Inc()
+		return
+	}
+	auditEventsCounter(kind).Inc()
+}
+
+// History returns the recorded events of an alert, oldest first.
+func (t *AuditTrail) History(ctx context.Context, fingerprint string) ([]dto.AuditEventOutput, error) {
+	fp, err := alert.NewFingerprint(fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("parse fingerprint: %w", err)
+	}
+	events, err := t.repo.FindByFingerprint(ctx, fp)
+	if err != nil {
+		return nil, fmt.Errorf("find audit events: %w", err)
+	}
+
+	out := make([]dto.AuditEventOutput, len(events))
+	for i, e := range events {
+		out[i] = dto.AuditEventOutput{
+			Kind:   e.Kind(),
+			Actor:  e.Actor(),
+			Detail: e.Detail(),
+			At:     e.At(),
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func TestAuditTrail(t *testing.T) {
+	var events []*audit.Event
+	repo := &portmock.AuditRepositoryMock{
+		AppendFunc: func(ctx context.Context, e *audit.Event) error {
+			events = append(events, e)
+			return nil
+		},
+		FindByFingerprintFunc: func(ctx context.Context, fp alert.Fingerprint) ([]*audit.Event, error) {
+			return events, nil
+		},
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	trail := NewAuditTrail(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	trail.SetClock(clock.NewFake(now))
+
+	fp, err := alert.NewFingerprint("fp-1")
+	require.NoError(t, err)
+	ctx := context.Background()
+	trail.Record(ctx, fp, audit.KindReceived, "", "status firing, severity critical")
+	trail.Record(ctx, fp, audit.KindAcknowledged, "john", "in Mattermost")
+
+	history, err := trail.History(ctx, "fp-1")
+	require.NoError(t, err)
+	assert.Equal(t, []dto.AuditEventOutput{
+		{Kind: audit.KindReceived, Detail: "status firing, severity critical", At: now},
+		{Kind: audit.KindAcknowledged, Actor: "john", Detail: "in Mattermost", At: now},
+	}, history)
+
+	_, err = trail.History(ctx, "")
+	assert.Error(t, err)
+}
+
+func TestAuditTrailRecordFailureIsLogged(t *testing.T) {
+	repo := &portmock.AuditRepositoryMock{
+		AppendFunc: func(ctx context.Context, e *audit.Event) error {
+			return errors.New("redis down")
+		},
+	}
+	trail := NewAuditTrail(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	fp, err := alert.NewFingerprint("fp-1")
+	require.NoError(t, err)
+
+	before := auditErrorsCounter.Get()
+	trail.Record(context.Background(), fp, audit.KindResolved, "", "in Keep")
+	assert.Equal(t, before+1, auditErrorsCounter.Get())
+
+	var disabled *AuditTrail
+	assert.NotPanics(t, func() { disabled.Record(context.Background(), fp, audit.KindResolved, "", "") })
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	// bulkProgressEvery is how many alerts a bulk action handles between
+	// progress replies in its thread.
+	bulkProgressEvery = 10
+	// bulkFailuresListed bounds the failed fingerprints listed in the
+	// summary of a bulk action.
+	bulkFailuresListed = 10
+)
+
+// Results of the alerts of a bulk action.
+const (
+	bulkApplied = "applied"
+	bulkDenied  = "denied"
+	bulkGone    = "gone"
+	bulkFailed  = "failed"
+)
+
+// bulkFilter selects the alerts of a bulk action: those of a severity, when
+// set, carrying every label.
+type bulkFilter struct {
+	severity string
+	labels   map[string]string
+}
+
+// parseBulkFilter reads the arguments of ack-all and resolve-all: a severity
+// and label=value selectors, in any order.
+func parseBulkFilter(args []string) (bulkFilter, error) {
+	f := bulkFilter{labels: make(map[string]string)}
+	for _, arg := range args {
+		if key, value, ok := strings.Cut(arg, "="); ok {
+			if key == "" {
+				return bulkFilter{}, fmt.Errorf("invalid label selector `%s`", arg)
+			}
+			f.labels[key] = value
+			continue
+		}
+		if f.severity != "" {
+			return bulkFilter{}, fmt.Errorf("more than one severity given")
+		}
+		severity, err := alert.NewSeverity(arg)
+		if err != nil {
+			return bulkFilter{}, fmt.Errorf("invalid severity `%s`", arg)
+		}
+		f.severity = severity.Value()
+	}
+	return f, nil
+}
+
+func (f bulkFilter) matches(p *post.Post) bool {
+	if f.severity != "" && p.Severity().Value() != f.severity {
+		return false
+	}
+	labels := p.Labels()
+	for key, value := range f.labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// describe phrases the filter for n alerts in the messages of a bulk action,
+// e.g. "critical alerts with env=prod".
+func (f bulkFilter) describe(n int) string {
+	text := "alerts"
+	if n == 1 {
+		text = "alert"
+	}
+	if f.severity != "" {
+		text = f.severity + " " + text
+	}
+	if len(f.labels) == 0 {
+		return text
+	}
+	selectors := make([]string, 0, len(f.labels))
+	for key, value := range f.labels {
+		selectors = append(selectors, key+"="+value)
+	}
+	sort.Strings(selectors)
+	return text + " with " + strings.Join(selectors, ", ")
+}
+
+// runBulkAction acknowledges or resolves every active alert of the channel
+// the command was run in that matches the arguments. The alerts are handled
+// in the background, one at a time through the same path as /keep ack and
+// /keep resolve; a post in the channel announces the run and its thread
+// reports the progress and the outcome.
+func (uc *HandleSlashCommandUseCase) runBulkAction(ctx context.Context, action, verb string, args []string, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error) {
+	filter, err := parseBulkFilter(args)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("%s.\n%s", capitalize(err.Error()), slashCommandUsage)), nil
+	}
+
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find all active posts: %w", err)
+	}
+	var targets []*post.Post
+	for _, p := range posts {
+		if p.ChannelID() != input.ChannelID || !filter.matches(p) {
+			continue
+		}
+		if action == post.ActionAcknowledge && p.ShownStatus() == alert.StatusAcknowledged {
+			continue
+		}
+		targets = append(targets, p)
+	}
+	if len(targets) == 0 {
+		return ephemeral(fmt.Sprintf("No active %s to %s in this channel.", filter.describe(0), action)), nil
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].FiringStartTime().Before(targets[j].FiringStartTime()) })
+
+	username, err := uc.mmClient.GetUser(ctx, input.UserID)
+	if err != nil {
+		username = input.UserID
+	}
+	announcement := post.Attachment{
+		Text: fmt.Sprintf("@%s started to %s %d %s in this channel.", username, action, len(targets), filter.describe(len(targets))),
+	}
+	rootID, err := uc.mmClient.CreatePost(ctx, input.ChannelID, announcement)
+	if err != nil {
+		return nil, fmt.Errorf("post bulk %s: %w", action, err)
+	}
+
+	uc.logger.Info("Bulk action started",
+		logger.ApplicationFields("bulk_action_started",
+			slog.String("action", action),
+			slog.String("channel_id", input.ChannelID),
+			slog.String("filter", filter.describe(0)),
+			slog.Int("alerts", len(targets)),
+			slog.String("username", username),
+		),
+	)
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		// The command was answered once the run started.
+		uc.bulkAction(context.WithoutCancel(ctx), action, verb, targets, input.UserID, input.ChannelID, rootID)
+	}()
+
+	return ephemeral(fmt.Sprintf("Started to %s %d %s, see the thread for progress.", action, len(targets), filter.describe(len(targets)))), nil
+}
+
+func (uc *HandleSlashCommandUseCase) bulkAction(ctx context.Context, action, verb string, targets []*post.Post, userID, channelID, rootID string) {
+	counts := make(map[string]int)
+	var failed []string
+	for i, p := range targets {
+		fingerprint := p.Fingerprint().Value()
+		actionCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := uc.actions.ExecuteAction(actionCtx, action, fingerprint, userID)
+		cancel()
+
+		result := bulkApplied
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrNotPermitted):
+			result = bulkDenied
+		case errors.Is(err, post.ErrNotFound):
+			// Resolved since the run started.
+			result = bulkGone
+		default:
+			result = bulkFailed
+			failed = append(failed, fingerprint)
+			uc.logger.Error("Bulk action failed for alert",
+				slog.String("action", action),
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+		}
+		counts[result]++
+		bulkActionAlertsCounter(action, result).Inc()
+
+		if done := i + 1; done%bulkProgressEvery == 0 && done < len(targets) {
+			uc.replyBulk(ctx, channelID, rootID, fmt.Sprintf("%d of %d alerts handled…", done, len(targets)))
+		}
+	}
+
+	summary := fmt.Sprintf("Done: %d %s", counts[bulkApplied], verb)
+	if n := counts[bulkGone]; n > 0 {
+		summary += fmt.Sprintf(", %d already gone", n)
+	}
+	if n := counts[bulkDenied]; n > 0 {
+		summary += fmt.Sprintf(", %d not permitted", n)
+	}
+	if n := counts[bulkFailed]; n > 0 {
+		listed := failed
+		if len(listed) > bulkFailuresListed {
+			listed = listed[:bulkFailuresListed]
+		}
+		summary += fmt.Sprintf(", %d failed: `%s`", n, strings.Join(listed, "`, `"))
+		if len(failed) > len(listed) {
+			summary += fmt.Sprintf(" and %d more", len(failed)-len(listed))
+		}
+	}
+	uc.replyBulk(ctx, channelID, rootID, summary+".")
+
+	uc.logger.Info("Bulk action finished",
+		logger.ApplicationFields("bulk_action_finished",
+			slog.String("action", action),
+			slog.String("channel_id", channelID),
+			slog.Int(bulkApplied, counts[bulkApplied]),
+			slog.Int(bulkGone, counts[bulkGone]),
+			slog.Int(bulkDenied, counts[bulkDenied]),
+			slog.Int(bulkFailed, counts[bulkFailed]),
+		),
+	)
+}
+
+func (uc *HandleSlashCommandUseCase) replyBulk(ctx context.Context, channelID, rootID, message string) {
+	if err := uc.mmClient.ReplyToThread(ctx, channelID, rootID, message); err != nil {
+		uc.logger.Error("Failed to reply to thread",
+			slog.String("post_id", rootID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// Wait blocks until the bulk actions running in the background finished.
+func (uc *HandleSlashCommandUseCase) Wait() {
+	uc.wg.Wait()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// CallbackPermissions decides who may apply an alert action. Membership is
+// looked up in Mattermost on every check, so changes to teams, channels and
+// groups apply at once. Lookup failures deny the action.
+type CallbackPermissions struct {
+	rules      []port.PermissionRule
+	mmClient   port.MattermostClient
+	membership port.MattermostMembershipClient
+	logger     *slog.Logger
+}
+
+func NewCallbackPermissions(
+	rules []port.PermissionRule,
+	mmClient port.MattermostClient,
+	membership port.MattermostMembershipClient,
+	logger *slog.Logger,
+) *CallbackPermissions {
+	return &CallbackPermissions{
+		rules:      rules,
+		mmClient:   mmClient,
+		membership: membership,
+		logger:     logger,
+	}
+}
+
+// Allowed reports whether userID may apply action to an alert of severity.
+func (p *CallbackPermissions) Allowed(ctx context.Context, userID, action, severity string) bool {
+	severity = strings.ToLower(severity)
+	applied := false
+	user := &permissionSubject{userID: userID}
+	for _, rule := range p.rules {
+		if !appliesTo(rule.Actions, action) || !appliesTo(rule.Severities, severity) {
+			continue
+		}
+		applied = true
+		ok, err := p.admits(ctx, rule, user)
+		if err != nil {
+			p.logger.Warn("Permission lookup failed, denying action",
+				logger.ApplicationFields("permission_lookup_failed",
+					slog.String("user_id", userID),
+					slog.String("action", action),
+					slog.String("error", err.Error()),
+				),
+			)
+			return false
+		}
+		if ok {
+			return true
+		}
+	}
+	return !applied
+}
+
+func appliesTo(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+// permissionSubject caches what has been looked up about the user during
+// one check.
+type permissionSubject struct {
+	userID   string
+	username *string
+	groups   []string
+	grouped  bool
+}
+
+func (p *CallbackPermissions) admits(ctx context.Context, rule port.PermissionRule, user *permissionSubject) (bool, error) {
+	if len(rule.Users) > 0 {
+		if user.username == nil {
+			name, err := p.mmClient.GetUser(ctx, user.userID)
+			if err != nil {
+				return false, err
+			}
+			user.username = &name
+		}
+		if slices.Contains(rule.Users, *user.username) {
+			return true, nil
+		}
+	}
+	for _, teamID := range rule.Teams {
+		ok, err := p.membership.IsTeamMember(ctx, teamID, user.userID)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	for _, channelID := range rule.Channels {
+		ok, err := p.membership.IsChannelMember(ctx, channelID, user.userID)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	if len(rule.Groups) > 0 {
+		if !user.grouped {
+			groups, err := p.membership.GetUserGroups(ctx, user.userID)
+			if err != nil {
+				return false, err
+			}
+			user.groups, user.grouped = groups, true
+		}
+		for _, group := range user.groups {
+			if slices.Contains(rule.Groups, group) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func newTestCallbackPermissions(rules []port.PermissionRule, lookupErr error) *CallbackPermissions {
+	users := map[string]string{"u-alice": "alice", "u-bob": "bob", "u-carol": "carol"}
+	mmClient := &portmock.MattermostClientMock{
+		GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+			return users[userID], nil
+		},
+	}
+	membership := &portmock.MattermostMembershipClientMock{
+		IsTeamMemberFunc: func(ctx context.Context, teamID, userID string) (bool, error) {
+			return teamID == "team-ops" && userID == "u-bob", lookupErr
+		},
+		IsChannelMemberFunc: func(ctx context.Context, channelID, userID string) (bool, error) {
+			return channelID == "chan-oncall" && userID == "u-carol", lookupErr
+		},
+		GetUserGroupsFunc: func(ctx context.Context, userID string) ([]string, error) {
+			if userID == "u-alice" {
+				return []string{"sre"}, lookupErr
+			}
+			return nil, lookupErr
+		},
+	}
+	return NewCallbackPermissions(rules, mmClient, membership, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestCallbackPermissionsAllowed(t *testing.T) {
+	rules := []port.PermissionRule{
+		{Teams: []string{"team-ops"}, Channels: []string{"chan-oncall"}, Groups: []string{"sre"}},
+		{Actions: []string{post.ActionResolve}, Severities: []string{"critical"}, Groups: []string{"sre"}},
+		{Actions: []string{post.ActionSnooze}, Users: []string{"carol"}},
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		action   string
+		severity string
+		want     bool
+	}{
+		{name: "team member acknowledges", userID: "u-bob", action: post.ActionAcknowledge, severity: "high", want: true},
+		{name: "channel member resolves", userID: "u-carol", action: post.ActionResolve, severity: "warning", want: true},
+		{name: "outsider acknowledges", userID: "u-dave", action: post.ActionAcknowledge, severity: "high", want: false},
+		{name: "only sre resolves critical", userID: "u-bob", action: post.ActionResolve, severity: "CRITICAL", want: true},
+		{name: "sre resolves critical", userID: "u-alice", action: post.ActionResolve, severity: "critical", want: true},
+		{name: "allowlisted user snoozes", userID: "u-carol", action: post.ActionSnooze, severity: "high", want: true},
+		{name: "outsider snoozes", userID: "u-dave", action: post.ActionSnooze, severity: "high", want: false},
+	}
+
+	p := newTestCallbackPermissions(rules, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Allowed(context.Background(), tt.userID, tt.action, tt.severity))
+		})
+	}
+}
+
+func TestCallbackPermissionsUnrestrictedAction(t *testing.T) {
+	rules := []port.PermissionRule{{Actions: []string{post.ActionResolve}, Severities: []string{"critical"}, Groups: []string{"sre"}}}
+	p := newTestCallbackPermissions(rules, nil)
+
+	assert.True(t, p.Allowed(context.Background(), "u-dave", post.ActionAcknowledge, "critical"), "no rule covers acknowledge")
+	assert.True(t, p.Allowed(context.Background(), "u-dave", post.ActionResolve, "high"), "no rule covers high alerts")
+	assert.False(t, p.Allowed(context.Background(), "u-dave", post.ActionResolve, "critical"))
+}
+
+func TestCallbackPermissionsLookupFailureDenies(t *testing.T) {
+	rules := []port.PermissionRule{{Teams: []string{"team-ops"}}}
+	p := newTestCallbackPermissions(rules, errors.New("mattermost GetTeamMembersByIDs: status 502"))
+
+	assert.False(t, p.Allowed(context.Background(), "u-bob", post.ActionAcknowledge, "high"))
+}
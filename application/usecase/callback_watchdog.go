@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// Callback statuses.
+const (
+	CallbackProcessing = "processing"
+	CallbackSucceeded  = "succeeded"
+	CallbackFailed     = "failed"
+	CallbackTimedOut   = "timed_out"
+)
+
+// CallbackWatchdog tracks the asynchronous phase of button callbacks. A click
+// answers with a "Processing..." post and leaves the real update to that
+// phase, so a phase that hangs, or fails without updating the post, would
+// leave it processing forever. The watchdog rewrites such posts with the
+// error and a Retry button that sends the click again, and keeps the outcome
+// of each callback for inspection.
+type CallbackWatchdog struct {
+	mmClient    port.MattermostClient
+	msgBuilder  port.MessageBuilder
+	keepUIURL   string
+	callbackURL string
+	timeout     time.Duration
+	retention   time.Duration
+	clock       clock.Clock
+	logger      *slog.Logger
+
+	mu  sync.Mutex
+	ops map[string]*callbackOp
+}
+
+type callbackOp struct {
+	status     dto.CallbackStatusOutput
+	alertName  string
+	context    map[string]string // the clicked button's context, sent again by Retry
+	processing bool              // the post shows "Processing..."
+	settled    bool              // the post was updated since
+	timedOut   bool
+	failure    string // the error the phase showed on the post
+	done       chan struct{}
+}
+
+type callbackOpKey struct{}
+
+// NewCallbackWatchdog returns a watchdog that rewrites posts still processing
+// timeout after the click and keeps outcomes for retention.
+func NewCallbackWatchdog(mmClient port.MattermostClient, msgBuilder port.MessageBuilder, keepUIURL, callbackURL string, timeout, retention time.Duration, logger *slog.Logger) *CallbackWatchdog {
+	return &CallbackWatchdog{
+		mmClient:    mmClient,
+		msgBuilder:  msgBuilder,
+		keepUIURL:   keepUIURL,
+		callbackURL: callbackURL,
+		timeout:     timeout,
+		retention:   retention,
+		clock:       clock.Real(),
+		logger:      logger,
+		ops:         make(map[string]*callbackOp),
+	}
+}
+
+// SetClock replaces the clock that times callbacks out.
+func (w *CallbackWatchdog) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// WrapClient returns client with post updates made by a tracked callback
+// recorded, so the watchdog knows the callback's post left "Processing...".
+func (w *CallbackWatchdog) WrapClient(client port.MattermostClient) port.MattermostClient {
+	return &watchedClient{MattermostClient: client, watchdog: w}
+}
+
+type watchedClient struct {
+	port.MattermostClient
+	watchdog *CallbackWatchdog
+}
+
+func (c *watchedClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	if err := c.MattermostClient.UpdatePost(ctx, postID, attachment); err != nil {
+		return err
+	}
+	if op, ok := ctx.Value(callbackOpKey{}).(*callbackOp); ok && op.status.PostID == postID {
+		c.watchdog.mu.Lock()
+		op.settled = true
+		c.watchdog.mu.Unlock()
+	}
+	return nil
+}
+
+// start tracks a callback whose asynchronous phase is about to run and
+// returns ctx carrying it. The watchdog fires timeout later unless finish
+// was called.
+func (w *CallbackWatchdog) start(ctx context.Context, input dto.MattermostCallbackInput, buttonContext map[string]string) (context.Context, *callbackOp) {
+	action := input.Context[post.ContextKeyAction]
+	op := &callbackOp{
+		status: dto.CallbackStatusOutput{
+			ID:          rand.Text(),
+			Action:      callbackMetricAction(action),
+			Fingerprint: input.Context[post.ContextKeyFingerprint],
+			PostID:      input.PostID,
+			UserID:      input.UserID,
+			Status:      CallbackProcessing,
+			StartedAt:   w.clock.Now(),
+		},
+		alertName: input.Context[post.ContextKeyAlertName],
+		context:   maps.Clone(buttonContext),
+		// Custom actions, workflow runs and resolve dialogs leave the post
+		// as it was.
+		processing: action != post.ActionCustom && action != post.ActionRunWorkflow &&
+			input.Context[post.ContextKeyAttachmentJSON] != "",
+		done: make(chan struct{}),
+	}
+
+	w.mu.Lock()
+	w.prune()
+	w.ops[op.status.ID] = op
+	w.mu.Unlock()
+
+	go func() {
+		select {
+		case <-op.done:
+		case <-w.clock.After(w.timeout):
+			w.expire(op)
+		}
+	}()
+	return context.WithValue(ctx, callbackOpKey{}, op), op
+}
+
+// fail records that the callback in ctx failed with errorMsg.
+func (w *CallbackWatchdog) fail(ctx context.Context, errorMsg string) {
+	op, ok := ctx.Value(callbackOpKey{}).(*callbackOp)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	op.failure = errorMsg
+}
+
+// finish records the outcome of op once its asynchronous phase returned, and
+// rewrites its post when the phase left it processing.
+func (w *CallbackWatchdog) finish(op *callbackOp) {
+	w.mu.Lock()
+	now := w.clock.Now()
+	op.status.FinishedAt = &now
+	stuck := op.processing && !op.settled
+	rewrite := false
+	switch {
+	case stuck && op.timedOut:
+		// The post was rewritten when the callback timed out.
+	case stuck:
+		op.status.Status, op.status.Error = CallbackFailed, op.failure
+		if op.failure == "" {
+			op.status.Error = "The post could not be updated"
+		}
+		rewrite = true
+	case op.failure != "":
+		op.status.Status, op.status.Error = CallbackFailed, op.failure
+	default:
+		// A callback that timed out but updated its post in the end
+		// succeeded late.
+		op.status.Status, op.status.Error = CallbackSucceeded, ""
+	}
+	status := op.status
+	w.mu.Unlock()
+	close(op.done)
+
+	callbacksAsyncCounter(status.Status).Inc()
+	w.logger.Info("Callback finished",
+		slog.String("callback_id", status.ID),
+		slog.String("action", status.Action),
+		slog.String("fingerprint", status.Fingerprint),
+		slog.String("post_id", status.PostID),
+		slog.String("status", status.Status),
+	)
+	if rewrite {
+		w.rewrite(op, status)
+	}
+}
+
+// expire rewrites the post of op when its asynchronous phase still runs.
+func (w *CallbackWatchdog) expire(op *callbackOp) {
+	w.mu.Lock()
+	if op.status.FinishedAt != nil {
+		w.mu.Unlock()
+		return
+	}
+	op.timedOut = true
+	op.status.Status = CallbackTimedOut
+	op.status.Error = "Timed out after " + w.timeout.String()
+	status := op.status
+	rewrite := op.processing && !op.settled
+	w.mu.Unlock()
+
+	callbackWatchdogTimeoutsCounter.Inc()
+	w.logger.Warn("Callback timed out",
+		slog.String("callback_id", status.ID),
+		slog.String("action", status.Action),
+		slog.String("fingerprint", status.Fingerprint),
+		slog.String("post_id", status.PostID),
+	)
+	if rewrite {
+		w.rewrite(op, status)
+	}
+}
+
+// rewrite replaces the "Processing..." post of op with the error and a Retry
+// button that sends the clicked button's context again.
+func (w *CallbackWatchdog) rewrite(op *callbackOp, status dto.CallbackStatusOutput) {
+	attachment := w.msgBuilder.BuildErrorAttachment(op.alertName, status.Fingerprint, w.keepUIURL, status.Error)
+	attachment.Actions = append(attachment.Actions, post.Button{
+		ID:    "retry",
+		Name:  "Retry",
+		Style: post.ButtonStyleDefault,
+		Integration: post.ButtonIntegration{
+			URL:     w.callbackURL,
+			Context: op.context,
+		},
+	})
+	attachment.Footer = "Callback " + status.ID
+
+	// The callback's own context may have ended.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := w.mmClient.UpdatePost(ctx, status.PostID, attachment); err != nil {
+		callbackWatchdogErrorsCounter.Inc()
+		w.logger.Error("Failed to rewrite stuck callback post",
+			slog.String("callback_id", status.ID),
+			slog.String("post_id", status.PostID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	w.logger.Info("Stuck callback post rewritten",
+		logger.ApplicationFields("callback_post_rewritten",
+			slog.String("callback_id", status.ID),
+			slog.String("action", status.Action),
+			slog.String("fingerprint", status.Fingerprint),
+			slog.String("post_id", status.PostID),
+			slog.String("status", status.Status),
+		),
+	)
+}
+
+// Status returns the outcome of the callback with id.
+func (w *CallbackWatchdog) Status(id string) (dto.CallbackStatusOutput, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	op, ok := w.ops[id]
+	if !ok {
+		return dto.CallbackStatusOutput{}, false
+	}
+	return op.status, true
+}
+
+// prune forgets callbacks that finished more than the retention ago. The
+// caller holds w.mu.
+func (w *CallbackWatchdog) prune() {
+	cutoff := w.clock.Now().Add(-w.retention)
+	for id, op := range w.ops {
+		if op.status.FinishedAt != nil && op.status.FinishedAt.Before(cutoff) {
+			delete(w.ops, id)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// setupCallbackWatchdog returns the callback use case with a watchdog that
+// rewrites posts through its own client.
+func setupCallbackWatchdog() (*HandleCallbackUseCase, *portmock.MattermostClientMock, *CallbackWatchdog, *portmock.MattermostClientMock, *clock.Fake) {
+	uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+	rewrites := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+	}
+	fakeClock := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	w := NewCallbackWatchdog(rewrites, newMessageBuilderMock(), "https://keep.example.com", "https://callback.example.com", 45*time.Second, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	w.SetClock(fakeClock)
+	uc.SetWatchdog(w)
+	return uc, mmClient, w, rewrites, fakeClock
+}
+
+func watchdogInput() dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			post.ContextKeyAction:         post.ActionAcknowledge,
+			post.ContextKeyFingerprint:    "fp-12345",
+			post.ContextKeyAlertName:      "Test Alert",
+			post.ContextKeyAttachmentJSON: `{"Title":"Test Alert"}`,
+		},
+	}
+}
+
+// onlyCallback returns the status of the one callback w tracked.
+func onlyCallback(t *testing.T, w *CallbackWatchdog) dto.CallbackStatusOutput {
+	t.Helper()
+	w.mu.Lock()
+	require.Len(t, w.ops, 1)
+	var id string
+	for id = range w.ops {
+	}
+	w.mu.Unlock()
+	status, ok := w.Status(id)
+	require.True(t, ok)
+	return status
+}
+
+func TestCallbackWatchdog_RecordsSucceededCallbacks(t *testing.T) {
+	uc, _, w, rewrites, _ := setupCallbackWatchdog()
+
+	uc.ExecuteAsync(watchdogInput())
+	uc.Wait()
+
+	status := onlyCallback(t, w)
+	assert.Equal(t, CallbackSucceeded, status.Status)
+	assert.Equal(t, post.ActionAcknowledge, status.Action)
+	assert.Equal(t, "fp-12345", status.Fingerprint)
+	assert.Equal(t, "post-456", status.PostID)
+	assert.Empty(t, status.Error)
+	assert.NotNil(t, status.FinishedAt)
+	assert.Empty(t, rewrites.UpdatePostCalls())
+
+	_, ok := w.Status("unknown")
+	assert.False(t, ok)
+}
+
+func TestCallbackWatchdog_RewritesPostsLeftProcessing(t *testing.T) {
+	uc, mmClient, w, rewrites, _ := setupCallbackWatchdog()
+	mmClient.UpdatePostFunc = func(context.Context, string, post.Attachment) error { return errors.New("mattermost down") }
+	input := watchdogInput()
+
+	uc.ExecuteAsync(input)
+	uc.Wait()
+
+	status := onlyCallback(t, w)
+	assert.Equal(t, CallbackFailed, status.Status)
+	assert.Equal(t, "The post could not be updated", status.Error)
+
+	calls := rewrites.UpdatePostCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "post-456", calls[0].PostID)
+	assert.Equal(t, "Error: The post could not be updated", calls[0].Attachment.Text)
+	assert.Equal(t, "Callback "+status.ID, calls[0].Attachment.Footer)
+	require.Len(t, calls[0].Attachment.Actions, 1)
+	retry := calls[0].Attachment.Actions[0]
+	assert.Equal(t, "Retry", retry.Name)
+	assert.Equal(t, "https://callback.example.com", retry.Integration.URL)
+	assert.Equal(t, input.Context, retry.Integration.Context, "retry sends the click again")
+}
+
+func TestCallbackWatchdog_RewritesPostsOfHangingCallbacks(t *testing.T) {
+	uc, mmClient, w, rewrites, fakeClock := setupCallbackWatchdog()
+	release := make(chan struct{})
+	mmClient.GetUserFunc = func(ctx context.Context, userID string) (string, error) {
+		<-release
+		return "testuser", nil
+	}
+
+	uc.ExecuteAsync(watchdogInput())
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(45 * time.Second)
+
+	require.Eventually(t, func() bool { return len(rewrites.UpdatePostCalls()) == 1 }, time.Second, time.Millisecond)
+	status := onlyCallback(t, w)
+	assert.Equal(t, CallbackTimedOut, status.Status)
+	assert.Equal(t, "Timed out after 45s", status.Error)
+	assert.Nil(t, status.FinishedAt)
+
+	close(release)
+	uc.Wait()
+	status = onlyCallback(t, w)
+	assert.Equal(t, CallbackSucceeded, status.Status, "the post was updated in the end")
+	assert.Len(t, rewrites.UpdatePostCalls(), 1)
+}
+
+func TestCallbackWatchdog_ForgetsOldCallbacks(t *testing.T) {
+	uc, _, w, _, fakeClock := setupCallbackWatchdog()
+
+	uc.ExecuteAsync(watchdogInput())
+	uc.Wait()
+	first := onlyCallback(t, w)
+
+	fakeClock.Advance(2 * time.Hour)
+	uc.ExecuteAsync(watchdogInput())
+	uc.Wait()
+
+	_, ok := w.Status(first.ID)
+	assert.False(t, ok)
+	assert.NotEqual(t, first.ID, onlyCallback(t, w).ID)
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// ChannelNames finds the IDs of the channels the config gives by name in a
+// team, creating the missing ones when allowed, so routing rules can name
+// channels instead of pasting their IDs.
+type ChannelNames struct {
+	directory port.MattermostChannelDirectory
+	teamID    string
+	create    bool
+	private   bool
+	cache     port.ChannelIDCache
+	cacheTTL  time.Duration
+	logger    *slog.Logger
+}
+
+func NewChannelNames(directory port.MattermostChannelDirectory, teamID string, logger *slog.Logger) *ChannelNames {
+	return &ChannelNames{directory: directory, teamID: teamID, logger: logger}
+}
+
+// SetCreate creates channels that do not exist yet, private ones when
+// private is set. Without it a missing channel fails Resolve.
+func (n *ChannelNames) SetCreate(private bool) {
+	n.create = true
+	n.private = private
+}
+
+// SetCache keeps found IDs for ttl, so restarts and other replicas skip the
+// lookups.
+func (n *ChannelNames) SetCache(cache port.ChannelIDCache, ttl time.Duration) {
+	n.cache = cache
+	n.cacheTTL = ttl
+}
+
+// Resolve returns the ID of each of names. It fails on the first channel it
+// cannot find or create, as the alerts routed there would have nowhere to go.
+func (n *ChannelNames) Resolve(ctx context.Context, names []string) (map[string]string, error) {
+	ids := make(map[string]string, len(names))
+	for _, name := range names {
+		id, err := n.resolve(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("channel ~%s: %w", name, err)
+		}
+		ids[name] = id
+	}
+	return ids, nil
+}
+
+func (n *ChannelNames) resolve(ctx context.Context, name string) (string, error) {
+	if n.cache != nil {
+		id, ok, err := n.cache.GetChannelID(ctx, n.teamID, name)
+		if err != nil {
+			n.logger.Warn("Failed to read channel ID cache",
+				slog.String("channel", name),
+				slog.String("error", err.Error()),
+			)
+		} else if ok {
+			channelNamesResolvedCounter("cache").Inc()
+			return id, nil
+		}
+	}
+
+	source := "lookup"
+	id, err := n.directory.GetChannelIDByName(ctx, n.teamID, name)
+	if errors.Is(err, port.ErrMattermostChannelNotFound) && n.create {
+		source = "created"
+		id, err = n.directory.CreateChannel(ctx, n.teamID, name, n.private)
+		if err != nil {
+			// Another replica may have created it meanwhile.
+			if existing, lookupErr := n.directory.GetChannelIDByName(ctx, n.teamID, name); lookupErr == nil {
+				source, id, err = "lookup", existing, nil
+			}
+		}
+		if err == nil && source == "created" {
+			n.logger.Info("Channel created",
+				logger.ApplicationFields("channel_created",
+					slog.String("channel", name),
+					slog.String("channel_id", id),
+					slog.String("team_id", n.teamID),
+					slog.Bool("private", n.private),
+				),
+			)
+		}
+	}
+	if errors.Is(err, port.ErrMattermostChannelNotFound) {
+		return "", fmt.Errorf("no such channel in team %s; set channel_names.create to create it", n.teamID)
+	}
+	if err != nil {
+		return "", err
+	}
+	channelNamesResolvedCounter(source).Inc()
+
+	if n.cache != nil {
+		if err := n.cache.SetChannelID(ctx, n.teamID, name, id, n.cacheTTL); err != nil {
+			n.logger.Warn("Failed to write channel ID cache",
+				slog.String("channel", name),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+)
+
+func newTestChannelNames(existing map[string]string) (*ChannelNames, *portmock.MattermostChannelDirectoryMock) {
+	directory := &portmock.MattermostChannelDirectoryMock{
+		GetChannelIDByNameFunc: func(ctx context.Context, teamID, name string) (string, error) {
+			if id, ok := existing[name]; ok {
+				return id, nil
+			}
+			return "", port.ErrMattermostChannelNotFound
+		},
+		CreateChannelFunc: func(ctx context.Context, teamID, name string, private bool) (string, error) {
+			return "created-" + name, nil
+		},
+	}
+	return NewChannelNames(directory, "team-1", slog.New(slog.NewTextHandler(io.Discard, nil))), directory
+}
+
+func TestChannelNames_Resolve(t *testing.T) {
+	n, directory := newTestChannelNames(map[string]string{"alerts": "channel-alerts"})
+
+	ids, err := n.Resolve(context.Background(), []string{"alerts"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"alerts": "channel-alerts"}, ids)
+	require.Len(t, directory.GetChannelIDByNameCalls(), 1)
+	assert.Equal(t, "team-1", directory.GetChannelIDByNameCalls()[0].TeamID)
+
+	_, err = n.Resolve(context.Background(), []string{"alerts", "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel ~missing: no such channel in team team-1")
+	assert.Empty(t, directory.CreateChannelCalls(), "channels are only created when allowed")
+}
+
+func TestChannelNames_CreatesMissingChannels(t *testing.T) {
+	n, directory := newTestChannelNames(map[string]string{"alerts": "channel-alerts"})
+	n.SetCreate(true)
+
+	ids, err := n.Resolve(context.Background(), []string{"alerts", "payments"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"alerts": "channel-alerts", "payments": "created-payments"}, ids)
+	calls := directory.CreateChannelCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "payments", calls[0].Name)
+	assert.True(t, calls[0].Private)
+}
+
+func TestChannelNames_CreateRace(t *testing.T) {
+	created := false
+	directory := &portmock.MattermostChannelDirectoryMock{
+		GetChannelIDByNameFunc: func(ctx context.Context, teamID, name string) (string, error) {
+			if created {
+				return "channel-payments", nil
+			}
+			return "", port.ErrMattermostChannelNotFound
+		},
+		CreateChannelFunc: func(ctx context.Context, teamID, name string, private bool) (string, error) {
+			// Another replica won.
+			created = true
+			return "", errors.New("status 400: a channel with that name already exists")
+		},
+	}
+	n := NewChannelNames(directory, "team-1", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	n.SetCreate(false)
+
+	ids, err := n.Resolve(context.Background(), []string{"payments"})
+	require.NoError(t, err)
+	assert.Equal(t, "channel-payments", ids["payments"])
+}
+
+func TestChannelNames_Cache(t *testing.T) {
+	n, directory := newTestChannelNames(map[string]string{"alerts": "channel-alerts"})
+	cached := map[string]string{"payments": "channel-payments"}
+	cache := &portmock.ChannelIDCacheMock{
+		GetChannelIDFunc: func(ctx context.Context, teamID, name string) (string, bool, error) {
+			if name == "broken" {
+				return "", false, errors.New("valkey down")
+			}
+			id, ok := cached[name]
+			return id, ok, nil
+		},
+		SetChannelIDFunc: func(ctx context.Context, teamID, name, channelID string, ttl time.Duration) error {
+			cached[name] = channelID
+			return nil
+		},
+	}
+	n.SetCache(cache, time.Hour)
+
+	ids, err := n.Resolve(context.Background(), []string{"alerts", "payments"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"alerts": "channel-alerts", "payments": "channel-payments"}, ids)
+	require.Len(t, directory.GetChannelIDByNameCalls(), 1, "cached channels are not looked up")
+	assert.Equal(t, "channel-alerts", cached["alerts"])
+	assert.Equal(t, time.Hour, cache.SetChannelIDCalls()[0].TTL)
+
+	_, err = n.Resolve(context.Background(), []string{"broken"})
+	require.Error(t, err, "a cache failure falls back to the lookup")
+	assert.Len(t, directory.GetChannelIDByNameCalls(), 2)
+}
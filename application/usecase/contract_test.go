@@ -0,0 +1,12 @@
+package usecase
+
+import "github.com/alexmorbo/keep-mattermost-bridge/application/port"
+
+// Compile-time contracts: HandleCallbackUseCase and HandleIncidentUseCase are
+// wired into the HTTP layer as port.CallbackUseCase, and HandleCallbackUseCase
+// also serves the resolve dialog as port.DialogUseCase.
+var (
+	_ port.CallbackUseCase = (*HandleCallbackUseCase)(nil)
+	_ port.CallbackUseCase = (*HandleIncidentUseCase)(nil)
+	_ port.DialogUseCase   = (*HandleCallbackUseCase)(nil)
+)
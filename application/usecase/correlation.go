@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+const (
+	correlationFooterPrefix    = "🔗 "
+	correlationFooterSeparator = " | "
+)
+
+// CorrelationTracker maintains the correlation record of each alert, linking
+// its fingerprint to the Mattermost posts showing it, its Keep incident and
+// its ticket, and renders the incident and ticket as a footer line on the
+// alert post.
+type CorrelationTracker struct {
+	repo   correlation.Repository
+	logger *slog.Logger
+	clock  clock.Clock
+}
+
+func NewCorrelationTracker(repo correlation.Repository, logger *slog.Logger) *CorrelationTracker {
+	return &CorrelationTracker{
+		repo:   repo,
+		logger: logger,
+		clock:  clock.Real(),
+	}
+}
+
+// SetClock replaces the clock that timestamps record changes.
+func (t *CorrelationTracker) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// Link records the incident and ticket of an alert. Empty values keep what
+// was recorded before, so payloads without enrichments do not unlink.
+func (t *CorrelationTracker) Link(ctx context.Context, fingerprint alert.Fingerprint, incidentID, ticketKey, ticketURL string) error {
+	if incidentID == "" && ticketKey == "" && ticketURL == "" {
+		return nil
+	}
+	return t.update(ctx, fingerprint, func(r *correlation.Record, now time.Time) bool {
+		incidentChanged := r.SetIncident(incidentID, now)
+		return r.SetTicket(ticketKey, ticketURL, now) || incidentChanged
+	})
+}
+
+// AddPosts records Mattermost posts showing an alert.
+func (t *CorrelationTracker) AddPosts(ctx context.Context, fingerprint alert.Fingerprint, postIDs ...string) error {
+	return t.update(ctx, fingerprint, func(r *correlation.Record, now time.Time) bool {
+		changed := false
+		for _, postID := range postIDs {
+			changed = r.AddPostID(postID, now) || changed
+		}
+		return changed
+	})
+}
+
+func (t *CorrelationTracker) update(ctx context.Context, fingerprint alert.Fingerprint, apply func(r *correlation.Record, now time.Time) bool) error {
+	now := t.clock.Now()
+	r, err := t.repo.FindByFingerprint(ctx, fingerprint)
+	if errors.Is(err, correlation.ErrNotFound) {
+		r, err = correlation.NewRecord(fingerprint, now), nil
+	}
+	if err != nil {
+		return fmt.Errorf("find correlation: %w", err)
+	}
+	if !apply(r, now) {
+		return nil
+	}
+	if err := t.repo.Save(ctx, r); err != nil {
+		return fmt.Errorf("save correlation: %w", err)
+	}
+	return nil
+}
+
+// Resolve returns the correlation record holding id as fingerprint, post ID,
+// incident ID or ticket key. It returns correlation.ErrNotFound for unknown
+// identifiers.
+func (t *CorrelationTracker) Resolve(ctx context.Context, id string) (*dto.CorrelationOutput, error) {
+	r, err := t.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	postIDs := r.PostIDs()
+	if postIDs == nil {
+		postIDs = []string{}
+	}
+	return &dto.CorrelationOutput{
+		Fingerprint: r.Fingerprint().Value(),
+		PostIDs:     postIDs,
+		IncidentID:  r.IncidentID(),
+		TicketKey:   r.TicketKey(),
+		TicketURL:   r.TicketURL(),
+		UpdatedAt:   r.UpdatedAt(),
+	}, nil
+}
+
+// Decorate adds the correlation footer line of an alert to a new post.
+func (t *CorrelationTracker) Decorate(ctx context.Context, fingerprint alert.Fingerprint, attachment *post.Attachment) {
+	r, err := t.repo.FindByFingerprint(ctx, fingerprint)
+	t.decorate(r, err, attachment)
+}
+
+func (t *CorrelationTracker) decorate(r *correlation.Record, err error, attachment *post.Attachment) {
+	if err != nil {
+		if !errors.Is(err, correlation.ErrNotFound) {
+			t.logger.Warn("Failed to load correlation",
+				slog.String("error", err.Error()),
+			)
+			correlationErrorsCounter.Inc()
+		}
+		return
+	}
+	attachment.Footer = withCorrelationFooter(attachment.Footer, r)
+}
+
+// WrapClient returns a client that adds the correlation footer line to every
+// updated post, whichever use case renders it.
+func (t *CorrelationTracker) WrapClient(client port.MattermostClient) port.MattermostClient {
+	return &correlatedClient{MattermostClient: client, tracker: t}
+}
+
+type correlatedClient struct {
+	port.MattermostClient
+	tracker *CorrelationTracker
+}
+
+func (c *correlatedClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	r, err := c.tracker.repo.FindByID(ctx, postID)
+	c.tracker.decorate(r, err, &attachment)
+	return c.MattermostClient.UpdatePost(ctx, postID, attachment)
+}
+
+// withCorrelationFooter replaces any correlation line in footer, such as one
+// carried over from a stored attachment, with the current one for r.
+func withCorrelationFooter(footer string, r *correlation.Record) string {
+	if i := strings.Index(footer, correlationFooterSeparator+correlationFooterPrefix); i >= 0 {
+		footer = footer[:i]
+	} else if strings.HasPrefix(footer, correlationFooterPrefix) {
+		footer = ""
+	}
+
+	var parts []string
+	if r.IncidentID() != "" {
+		parts = append(parts, "Incident "+r.IncidentID())
+	}
+	switch {
+	case r.TicketKey() != "":
+		parts = append(parts, "Ticket "+r.TicketKey())
+	case r.TicketURL() != "":
+		parts = append(parts, "Ticket "+r.TicketURL())
+	}
+	if len(parts) == 0 {
+		return footer
+	}
+
+	line := correlationFooterPrefix + strings.Join(parts, " · ")
+	if footer == "" {
+		return line
+	}
+	return footer + correlationFooterSeparator + line
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// memoryCorrelationRepository stores records by fingerprint and finds them
+// by any identifier.
+type memoryCorrelationRepository struct {
+	records map[string]*correlation.Record
+	saves   int
+}
+
+func newMemoryCorrelationRepository() *memoryCorrelationRepository {
+	return &memoryCorrelationRepository{records: make(map[string]*correlation.Record)}
+}
+
+func (m *memoryCorrelationRepository) Save(ctx context.Context, r *correlation.Record) error {
+	m.saves++
+	m.records[r.Fingerprint().Value()] = r
+	return nil
+}
+
+func (m *memoryCorrelationRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*correlation.Record, error) {
+	if r, ok := m.records[fingerprint.Value()]; ok {
+		return r, nil
+	}
+	return nil, correlation.ErrNotFound
+}
+
+func (m *memoryCorrelationRepository) FindByID(ctx context.Context, id string) (*correlation.Record, error) {
+	for _, r := range m.records {
+		if r.Has(id) {
+			return r, nil
+		}
+	}
+	return nil, correlation.ErrNotFound
+}
+
+func newTestCorrelationTracker(repo correlation.Repository) *CorrelationTracker {
+	return NewCorrelationTracker(repo, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestCorrelationTracker_LinkAndResolve(t *testing.T) {
+	repo := newMemoryCorrelationRepository()
+	tracker := newTestCorrelationTracker(repo)
+	ctx := context.Background()
+	fp := alert.RestoreFingerprint("fp-1")
+
+	require.NoError(t, tracker.Link(ctx, fp, "", "", ""))
+	assert.Zero(t, repo.saves, "nothing to link")
+
+	require.NoError(t, tracker.AddPosts(ctx, fp, "post-1", "post-2"))
+	require.NoError(t, tracker.Link(ctx, fp, "inc-1", "OPS-42", "https://jira.example.com/browse/OPS-42"))
+	require.NoError(t, tracker.Link(ctx, fp, "", "OPS-42", ""))
+	assert.Equal(t, 2, repo.saves, "unchanged links are not saved")
+
+	for _, id := range []string{"fp-1", "post-2", "inc-1", "OPS-42"} {
+		out, err := tracker.Resolve(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "fp-1", out.Fingerprint)
+		assert.Equal(t, []string{"post-1", "post-2"}, out.PostIDs)
+		assert.Equal(t, "inc-1", out.IncidentID)
+		assert.Equal(t, "OPS-42", out.TicketKey)
+		assert.Equal(t, "https://jira.example.com/browse/OPS-42", out.TicketURL)
+	}
+
+	_, err := tracker.Resolve(ctx, "OPS-43")
+	assert.ErrorIs(t, err, correlation.ErrNotFound)
+}
+
+func TestCorrelationTracker_UpdateErrors(t *testing.T) {
+	repo := &portmock.CorrelationRepositoryMock{
+		FindByFingerprintFunc: func(ctx context.Context, fingerprint alert.Fingerprint) (*correlation.Record, error) {
+			return nil, errors.New("redis down")
+		},
+	}
+	tracker := newTestCorrelationTracker(repo)
+
+	err := tracker.AddPosts(context.Background(), alert.RestoreFingerprint("fp-1"), "post-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "find correlation: redis down")
+	assert.Empty(t, repo.SaveCalls())
+}
+
+func TestWithCorrelationFooter(t *testing.T) {
+	fp := alert.RestoreFingerprint("fp-1")
+	tests := []struct {
+		name   string
+		footer string
+		record *correlation.Record
+		want   string
+	}{
+		{
+			name:   "no links",
+			footer: "Keep AIOps",
+			record: correlation.RestoreRecord(fp, []string{"post-1"}, "", "", "", time.Time{}),
+			want:   "Keep AIOps",
+		},
+		{
+			name:   "incident and ticket",
+			footer: "Keep AIOps",
+			record: correlation.RestoreRecord(fp, nil, "inc-1", "OPS-42", "https://jira.example.com/browse/OPS-42", time.Time{}),
+			want:   "Keep AIOps | 🔗 Incident inc-1 · Ticket OPS-42",
+		},
+		{
+			name:   "ticket URL only",
+			footer: "",
+			record: correlation.RestoreRecord(fp, nil, "", "", "https://jira.example.com/browse/OPS-42", time.Time{}),
+			want:   "🔗 Ticket https://jira.example.com/browse/OPS-42",
+		},
+		{
+			name:   "replaces previous line",
+			footer: "Acknowledged by @alice | 🔗 Incident inc-1",
+			record: correlation.RestoreRecord(fp, nil, "inc-2", "", "", time.Time{}),
+			want:   "Acknowledged by @alice | 🔗 Incident inc-2",
+		},
+		{
+			name:   "replaces line without footer",
+			footer: "🔗 Incident inc-1",
+			record: correlation.RestoreRecord(fp, nil, "", "OPS-42", "", time.Time{}),
+			want:   "🔗 Ticket OPS-42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, withCorrelationFooter(tt.footer, tt.record))
+		})
+	}
+}
+
+func TestCorrelationTracker_WrapClient(t *testing.T) {
+	repo := newMemoryCorrelationRepository()
+	tracker := newTestCorrelationTracker(repo)
+	ctx := context.Background()
+	require.NoError(t, tracker.AddPosts(ctx, alert.RestoreFingerprint("fp-1"), "post-1"))
+	require.NoError(t, tracker.Link(ctx, alert.RestoreFingerprint("fp-1"), "inc-1", "", ""))
+
+	inner := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+	}
+	client := tracker.WrapClient(inner)
+
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Footer: "Keep AIOps"}))
+	require.NoError(t, client.UpdatePost(ctx, "group-root", post.Attachment{Footer: "Keep AIOps"}))
+
+	calls := inner.UpdatePostCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "Keep AIOps | 🔗 Incident inc-1", calls[0].Attachment.Footer)
+	assert.Equal(t, "Keep AIOps", calls[1].Attachment.Footer, "posts without a record are left alone")
+}
+
+func TestHandleAlertUseCase_Correlation(t *testing.T) {
+	uc, _, _, _, _, _ := setupHandleAlertUseCase()
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-" + channelID, nil
+		},
+	}
+	uc.mmClient = mmClient
+	repo := newMemoryCorrelationRepository()
+	uc.SetCorrelationTracker(newTestCorrelationTracker(repo))
+	ctx := context.Background()
+
+	require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{
+		Fingerprint: "fp-1",
+		Name:        "Disk full",
+		Severity:    "critical",
+		Status:      "firing",
+		IncidentID:  "inc-1",
+		TicketID:    "OPS-42",
+	}))
+
+	require.Len(t, mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "🔗 Incident inc-1 · Ticket OPS-42", mmClient.CreatePostCalls()[0].Attachment.Footer)
+
+	rec, err := repo.FindByID(ctx, "OPS-42")
+	require.NoError(t, err)
+	assert.Equal(t, "fp-1", rec.Fingerprint().Value())
+	assert.Equal(t, []string{"post-channel-456"}, rec.PostIDs())
+	assert.Equal(t, "inc-1", rec.IncidentID())
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// CustomActionRunner carries out the custom action buttons of alert posts:
+// it renders the action's request and runs the Keep workflow or calls the URL
+// it names.
+type CustomActionRunner struct {
+	actions   port.CustomActions
+	workflows port.KeepWorkflowRunner
+	webhooks  port.WebhookSender
+	logger    *slog.Logger
+}
+
+func NewCustomActionRunner(
+	actions port.CustomActions,
+	workflows port.KeepWorkflowRunner,
+	webhooks port.WebhookSender,
+	logger *slog.Logger,
+) *CustomActionRunner {
+	return &CustomActionRunner{
+		actions:   actions,
+		workflows: workflows,
+		webhooks:  webhooks,
+		logger:    logger,
+	}
+}
+
+// Run runs the action named name for a on behalf of username. It returns
+// what was done, e.g. "Keep workflow restart-pod, execution 42", for the
+// thread reply and the audit trail.
+func (r *CustomActionRunner) Run(ctx context.Context, name string, a *alert.Alert, username string) (string, error) {
+	req, err := r.actions.Request(name, a, username)
+	if err != nil {
+		return "", err
+	}
+
+	target, detail := "url", ""
+	if req.WorkflowID != "" {
+		target = "workflow"
+		executionID, err := r.workflows.RunWorkflow(ctx, req.WorkflowID, req.Body)
+		if err != nil {
+			customActionsCounter(target, "error").Inc()
+			return "", fmt.Errorf("run keep workflow %s: %w", req.WorkflowID, err)
+		}
+		detail = fmt.Sprintf("Keep workflow %s, execution %s", req.WorkflowID, executionID)
+	} else if err := r.webhooks.Send(ctx, req.URL, req.Body); err != nil {
+		customActionsCounter(target, "error").Inc()
+		return "", fmt.Errorf("call %s: %w", req.URL, err)
+	}
+
+	r.logger.Info("Custom action run",
+		logger.ApplicationFields("custom_action_run",
+			slog.String("action", name),
+			slog.String("fingerprint", a.Fingerprint().Value()),
+			slog.String("username", username),
+			slog.String("target", target),
+		),
+	)
+	customActionsCounter(target, "ok").Inc()
+	return detail, nil
+}
@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// DeadLetterQueue keeps Mattermost deliveries that failed so a Mattermost
+// outage does not drop alerts. It sits in front of the alert use case and the
+// Mattermost client:
+//   - a webhook whose post could not be created or updated is kept whole and
+//     re-delivered by processing the alert again
+//   - any other failed post update, e.g. after a button press, is kept as
+//     the rendered attachment and re-delivered by updating the post again
+//
+// Redeliver retries every entry until it succeeds or has failed maxAttempts
+// times; the rest stay in the queue for Replay or Delete through the admin
+// API.
+type DeadLetterQueue struct {
+	repo        deadletter.Repository
+	maxAttempts int
+	alerts      port.AlertUseCase
+	mmClient    port.MattermostClient
+	errorPosts  *ErrorPosts
+	clock       clock.Clock
+	logger      *slog.Logger
+}
+
+// ErrDeadLettered wraps the error of a webhook whose post failed and that
+// was kept for re-delivery.
+var ErrDeadLettered = errors.New("kept for re-delivery")
+
+func NewDeadLetterQueue(repo deadletter.Repository, maxAttempts int, logger *slog.Logger) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		repo:        repo,
+		maxAttempts: maxAttempts,
+		clock:       clock.Real(),
+		logger:      logger,
+	}
+}
+
+// SetClock replaces the clock that timestamps entries.
+func (q *DeadLetterQueue) SetClock(c clock.Clock) {
+	q.clock = c
+}
+
+// SetErrorPosts reports alert entries that used up their attempts to the
+// error post channel.
+func (q *DeadLetterQueue) SetErrorPosts(p *ErrorPosts) {
+	q.errorPosts = p
+}
+
+// deliveryScope marks a context as processing one alert webhook, so post
+// failures inside it are kept with the webhook instead of on their own.
+type deliveryScope struct {
+	failed atomic.Bool
+}
+
+type deliveryScopeKey struct{}
+
+func withDeliveryScope(ctx context.Context) (context.Context, *deliveryScope) {
+	scope := &deliveryScope{}
+	return context.WithValue(ctx, deliveryScopeKey{}, scope), scope
+}
+
+func deliveryScopeFrom(ctx context.Context) *deliveryScope {
+	scope, _ := ctx.Value(deliveryScopeKey{}).(*deliveryScope)
+	return scope
+}
+
+// WrapClient returns a client that reports failed post creates and updates
+// to the queue. Redeliver and Replay update posts through client itself.
+func (q *DeadLetterQueue) WrapClient(client port.MattermostClient) port.MattermostClient {
+	q.mmClient = client
+	return &deadLetterClient{MattermostClient: client, queue: q}
+}
+
+type deadLetterClient struct {
+	port.MattermostClient
+	queue *DeadLetterQueue
+}
+
+func (c *deadLetterClient) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+	postID, err := c.MattermostClient.CreatePost(ctx, channelID, attachment)
+	if err != nil {
+		// A post that was never created can only be re-delivered with the
+		// alert that asked for it.
+		if scope := deliveryScopeFrom(ctx); scope != nil {
+			scope.failed.Store(true)
+		}
+	}
+	return postID, err
+}
+
+func (c *deadLetterClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	err := c.MattermostClient.UpdatePost(ctx, postID, attachment)
+	if scope := deliveryScopeFrom(ctx); scope != nil {
+		if err != nil {
+			scope.failed.Store(true)
+		}
+		return err
+	}
+
+	id := deadletter.EntryID(deadletter.KindUpdate, postID)
+	if err == nil {
+		// The post now shows a newer state than any update still queued.
+		c.queue.drop(ctx, id)
+		return nil
+	}
+	c.queue.enqueue(ctx, deadletter.NewUpdateEntry(postID, attachment, err.Error(), c.queue.clock.Now()))
+	return err
+}
+
+// WrapAlerts returns an alert use case that keeps webhooks whose posts could
+// not be delivered. Redeliver and Replay process them with alerts itself.
+func (q *DeadLetterQueue) WrapAlerts(alerts port.AlertUseCase) port.AlertUseCase {
+	q.alerts = alerts
+	return &deadLetterAlerts{alerts: alerts, queue: q}
+}
+
+type deadLetterAlerts struct {
+	alerts port.AlertUseCase
+	queue  *DeadLetterQueue
+}
+
+func (a *deadLetterAlerts) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	scoped, scope := withDeliveryScope(ctx)
+	err := a.alerts.Execute(scoped, input)
+
+	id := deadletter.EntryID(deadletter.KindAlert, input.Fingerprint)
+	if err == nil {
+		// A newer webhook for the alert made it through.
+		a.queue.drop(ctx, id)
+		return nil
+	}
+	if !scope.failed.Load() {
+		// Invalid payloads and Keep errors would fail again.
+		return err
+	}
+
+	payload, marshalErr := json.Marshal(input)
+	if marshalErr != nil {
+		a.queue.logger.Error("Failed to marshal alert for dead-letter queue",
+			slog.String("fingerprint", input.Fingerprint),
+			slog.String("error", marshalErr.Error()),
+		)
+		return err
+	}
+	if !a.queue.enqueue(ctx, deadletter.NewAlertEntry(input.Fingerprint, payload, err.Error(), a.queue.clock.Now())) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrDeadLettered, err)
+}
+
+// enqueue saves e and reports whether it was kept.
+func (q *DeadLetterQueue) enqueue(ctx context.Context, e *deadletter.Entry) bool {
+	// Keep the entry even if the request that failed was canceled.
+	ctx = context.WithoutCancel(ctx)
+	if err := q.repo.Save(ctx, e); err != nil {
+		deadLetterErrorsCounter.Inc()
+		q.logger.Error("Failed to save dead-letter entry, delivery is lost",
+			slog.String("id", e.ID()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	deadLetterEnqueuedCounter(e.Kind()).Inc()
+	q.logger.Warn("Mattermost delivery failed, kept in dead-letter queue",
+		logger.ApplicationFields("dead_letter_enqueued",
+			slog.String("id", e.ID()),
+			slog.String("error", e.LastError()),
+		),
+	)
+	return true
+}
+
+func (q *DeadLetterQueue) drop(ctx context.Context, id string) {
+	if err := q.repo.Delete(context.WithoutCancel(ctx), id); err != nil {
+		q.logger.Warn("Failed to drop superseded dead-letter entry",
+			slog.String("id", id),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// Redeliver retries every entry that has not used up its attempts.
+func (q *DeadLetterQueue) Redeliver(ctx context.Context) error {
+	entries, err := q.repo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("find dead-letter entries: %w", err)
+	}
+
+	var errs []error
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if e.Attempts() >= q.maxAttempts {
+			continue
+		}
+		if err := q.redeliver(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// List returns every entry in the queue, oldest failure first.
+func (q *DeadLetterQueue) List(ctx context.Context) ([]dto.DeadLetterOutput, error) {
+	entries, err := q.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find dead-letter entries: %w", err)
+	}
+	output := make([]dto.DeadLetterOutput, 0, len(entries))
+	for _, e := range entries {
+		output = append(output, toDeadLetterOutput(e, q.maxAttempts))
+	}
+	return output, nil
+}
+
+// Replay re-delivers entry id now, even when it has used up its attempts.
+// It returns deadletter.ErrNotFound for unknown IDs.
+func (q *DeadLetterQueue) Replay(ctx context.Context, id string) error {
+	e, err := q.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return q.redeliver(ctx, e)
+}
+
+// ErrNotAlertEntry is returned by AlertPayload for entries that do not hold
+// a webhook.
+var ErrNotAlertEntry = errors.New("not an alert entry")
+
+// AlertPayload returns the webhook kept by alert entry id, for replaying it
+// through the replay API. It returns deadletter.ErrNotFound for unknown IDs.
+func (q *DeadLetterQueue) AlertPayload(ctx context.Context, id string) (dto.KeepAlertInput, error) {
+	e, err := q.repo.FindByID(ctx, id)
+	if err != nil {
+		return dto.KeepAlertInput{}, err
+	}
+	if e.Kind() != deadletter.KindAlert {
+		return dto.KeepAlertInput{}, fmt.Errorf("%w: %s", ErrNotAlertEntry, id)
+	}
+	var input dto.KeepAlertInput
+	if err := json.Unmarshal(e.Payload(), &input); err != nil {
+		return dto.KeepAlertInput{}, fmt.Errorf("unmarshal alert payload: %w", err)
+	}
+	return input, nil
+}
+
+// Delete discards entry id without delivering it.
+func (q *DeadLetterQueue) Delete(ctx context.Context, id string) error {
+	if _, err := q.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	if err := q.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete dead-letter entry: %w", err)
+	}
+	q.logger.Info("Dead-letter entry discarded", slog.String("id", id))
+	return nil
+}
+
+func (q *DeadLetterQueue) redeliver(ctx context.Context, e *deadletter.Entry) error {
+	if err := q.deliver(ctx, e); err != nil {
+		deadLetterErrorsCounter.Inc()
+		e.RecordFailure(err.Error(), q.clock.Now())
+		if saveErr := q.repo.Save(ctx, e); saveErr != nil {
+			return fmt.Errorf("redeliver %s: %w", e.ID(), errors.Join(err, saveErr))
+		}
+		if e.Attempts() >= q.maxAttempts {
+			q.logger.Error("Dead-letter entry failed too many times, waiting for manual replay",
+				logger.ApplicationFields("dead_letter_exhausted",
+					slog.String("id", e.ID()),
+					slog.Int("attempts", e.Attempts()),
+					slog.String("error", err.Error()),
+				),
+			)
+		}
+		if e.Attempts() == q.maxAttempts {
+			q.reportExhausted(ctx, e, err)
+		}
+		return fmt.Errorf("redeliver %s: %w", e.ID(), err)
+	}
+
+	if err := q.repo.Delete(ctx, e.ID()); err != nil {
+		return fmt.Errorf("delete redelivered entry %s: %w", e.ID(), err)
+	}
+	deadLetterRedeliveredCounter(e.Kind()).Inc()
+	q.logger.Info("Dead-letter entry redelivered",
+		logger.ApplicationFields("dead_letter_redelivered",
+			slog.String("id", e.ID()),
+			slog.Int("attempts", e.Attempts()+1),
+		),
+	)
+	return nil
+}
+
+func (q *DeadLetterQueue) deliver(ctx context.Context, e *deadletter.Entry) error {
+	switch e.Kind() {
+	case deadletter.KindAlert:
+		if q.alerts == nil {
+			return errors.New("no alert use case to redeliver with")
+		}
+		var input dto.KeepAlertInput
+		if err := json.Unmarshal(e.Payload(), &input); err != nil {
+			return fmt.Errorf("unmarshal alert payload: %w", err)
+		}
+		// Failures inside a replayed alert belong to this entry.
+		scoped, _ := withDeliveryScope(ctx)
+		return q.alerts.Execute(scoped, input)
+	case deadletter.KindUpdate:
+		if q.mmClient == nil {
+			return errors.New("no Mattermost client to redeliver with")
+		}
+		return q.mmClient.UpdatePost(ctx, e.Key(), e.Attachment())
+	default:
+		return fmt.Errorf("unknown dead-letter kind %q", e.Kind())
+	}
+}
+
+// reportExhausted posts an alert entry that just used up its attempts to the
+// error post channel.
+func (q *DeadLetterQueue) reportExhausted(ctx context.Context, e *deadletter.Entry, err error) {
+	if q.errorPosts == nil || e.Kind() != deadletter.KindAlert {
+		return
+	}
+	var input dto.KeepAlertInput
+	if jsonErr := json.Unmarshal(e.Payload(), &input); jsonErr != nil {
+		return
+	}
+	q.errorPosts.Report(ctx, input, err)
+}
+
+func toDeadLetterOutput(e *deadletter.Entry, maxAttempts int) dto.DeadLetterOutput {
+	output := dto.DeadLetterOutput{
+		ID:            e.ID(),
+		Kind:          e.Kind(),
+		LastError:     e.LastError(),
+		Attempts:      e.Attempts(),
+		Exhausted:     e.Attempts() >= maxAttempts,
+		FailedAt:      e.FailedAt(),
+		LastAttemptAt: e.LastAttemptAt(),
+	}
+	switch e.Kind() {
+	case deadletter.KindAlert:
+		output.Fingerprint = e.Key()
+		output.Payload = json.RawMessage(e.Payload())
+	case deadletter.KindUpdate:
+		output.PostID = e.Key()
+		attachment := e.Attachment()
+		output.Title = attachment.Title
+	}
+	return output
+}
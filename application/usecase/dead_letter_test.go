@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type memoryDeadLetterRepository struct {
+	entries map[string]*deadletter.Entry
+}
+
+func newMemoryDeadLetterRepository() *memoryDeadLetterRepository {
+	return &memoryDeadLetterRepository{entries: make(map[string]*deadletter.Entry)}
+}
+
+func (m *memoryDeadLetterRepository) Save(ctx context.Context, e *deadletter.Entry) error {
+	m.entries[e.ID()] = e
+	return nil
+}
+
+func (m *memoryDeadLetterRepository) FindByID(ctx context.Context, id string) (*deadletter.Entry, error) {
+	e, ok := m.entries[id]
+	if !ok {
+		return nil, deadletter.ErrNotFound
+	}
+	return e, nil
+}
+
+func (m *memoryDeadLetterRepository) FindAll(ctx context.Context) ([]*deadletter.Entry, error) {
+	entries := make([]*deadletter.Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FailedAt().Before(entries[j].FailedAt()) })
+	return entries, nil
+}
+
+func (m *memoryDeadLetterRepository) Delete(ctx context.Context, id string) error {
+	delete(m.entries, id)
+	return nil
+}
+
+// setupDeadLetterQueue wires the queue around a Mattermost client that fails
+// while *down is true and an alert use case that posts through it.
+func setupDeadLetterQueue(maxAttempts int) (*DeadLetterQueue, *memoryDeadLetterRepository, port.MattermostClient, port.AlertUseCase, *portmock.MattermostClientMock, *bool) {
+	repo := newMemoryDeadLetterRepository()
+	down := new(bool)
+	inner := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			if *down {
+				return "", errors.New("mattermost create post: status 502")
+			}
+			return "post-1", nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			if *down {
+				return errors.New("mattermost update post: status 502")
+			}
+			return nil
+		},
+	}
+	q := NewDeadLetterQueue(repo, maxAttempts, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	q.SetClock(clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	client := q.WrapClient(inner)
+
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if input.Severity == "bogus" {
+				return errors.New("parse severity: invalid severity")
+			}
+			if _, err := client.CreatePost(ctx, "channel-1", post.Attachment{Title: input.Name}); err != nil {
+				return err
+			}
+			return client.UpdatePost(ctx, "post-1", post.Attachment{Title: input.Name})
+		},
+	}
+	return q, repo, client, q.WrapAlerts(alerts), inner, down
+}
+
+func TestDeadLetterQueue_KeepsFailedUpdate(t *testing.T) {
+	q, repo, client, _, inner, down := setupDeadLetterQueue(3)
+	ctx := context.Background()
+
+	*down = true
+	require.Error(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "ACK"}))
+	entry, err := repo.FindByID(ctx, "update:post-1")
+	require.NoError(t, err)
+	assert.Equal(t, "ACK", entry.Attachment().Title)
+
+	require.Error(t, q.Redeliver(ctx))
+	assert.Equal(t, 1, repo.entries["update:post-1"].Attempts())
+
+	*down = false
+	require.NoError(t, q.Redeliver(ctx))
+	assert.Empty(t, repo.entries)
+	calls := inner.UpdatePostCalls()
+	assert.Equal(t, "ACK", calls[len(calls)-1].Attachment.Title)
+}
+
+func TestDeadLetterQueue_SuccessfulUpdateDropsStaleEntry(t *testing.T) {
+	_, repo, client, _, _, down := setupDeadLetterQueue(3)
+	ctx := context.Background()
+
+	*down = true
+	require.Error(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "ACK"}))
+	*down = false
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "RESOLVED"}))
+
+	assert.Empty(t, repo.entries, "a queued update must not overwrite a newer one")
+}
+
+func TestDeadLetterQueue_KeepsFailedAlert(t *testing.T) {
+	q, repo, _, alerts, inner, down := setupDeadLetterQueue(3)
+	ctx := context.Background()
+
+	*down = true
+	require.Error(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Disk full", Severity: "high"}))
+	require.Error(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-2", Name: "Invalid", Severity: "bogus"}))
+
+	require.Len(t, repo.entries, 1, "failures inside a webhook are kept with the webhook only")
+	entry, err := repo.FindByID(ctx, "alert:fp-1")
+	require.NoError(t, err)
+	assert.Contains(t, string(entry.Payload()), `"name":"Disk full"`)
+
+	*down = false
+	require.NoError(t, q.Redeliver(ctx))
+	assert.Empty(t, repo.entries)
+	calls := inner.CreatePostCalls()
+	assert.Equal(t, "Disk full", calls[len(calls)-1].Attachment.Title)
+}
+
+func TestDeadLetterQueue_ExhaustedEntriesWaitForReplay(t *testing.T) {
+	q, repo, client, _, _, down := setupDeadLetterQueue(2)
+	ctx := context.Background()
+
+	*down = true
+	require.Error(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "ACK"}))
+	require.Error(t, q.Redeliver(ctx))
+	require.Error(t, q.Redeliver(ctx))
+	require.NoError(t, q.Redeliver(ctx), "exhausted entries are skipped")
+	assert.Equal(t, 2, repo.entries["update:post-1"].Attempts())
+
+	listed, err := q.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "update:post-1", listed[0].ID)
+	assert.Equal(t, "post-1", listed[0].PostID)
+	assert.True(t, listed[0].Exhausted)
+	assert.Equal(t, "mattermost update post: status 502", listed[0].LastError)
+
+	*down = false
+	require.NoError(t, q.Replay(ctx, "update:post-1"))
+	assert.Empty(t, repo.entries)
+
+	assert.ErrorIs(t, q.Replay(ctx, "update:post-1"), deadletter.ErrNotFound)
+	assert.ErrorIs(t, q.Delete(ctx, "update:post-1"), deadletter.ErrNotFound)
+}
+
+func TestDeadLetterQueue_AlertPayload(t *testing.T) {
+	q, _, client, alerts, _, down := setupDeadLetterQueue(3)
+	ctx := context.Background()
+
+	*down = true
+	require.Error(t, alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Disk full", Severity: "high"}))
+	require.Error(t, client.UpdatePost(ctx, "post-2", post.Attachment{Title: "ACK"}))
+
+	input, err := q.AlertPayload(ctx, "alert:fp-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Disk full", input.Name)
+
+	_, err = q.AlertPayload(ctx, "update:post-2")
+	assert.ErrorIs(t, err, ErrNotAlertEntry)
+	_, err = q.AlertPayload(ctx, "alert:missing")
+	assert.ErrorIs(t, err, deadletter.ErrNotFound)
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/textdiff"
 )
 
 const (
@@ -14,8 +18,73 @@ const (
 	workflowRawID = "kmbridge-webhook"
 )
 
+// keepWorkflowYAML forwards every alert event to the bridge through the
+// kmbridge webhook provider.
+const keepWorkflowYAML = `id: kmbridge-webhook
+description: Route alerts to Mattermost channels via kmbridge
+disabled: false
+triggers:
+- type: alert
+name: Mattermost updates via kmbridge
+inputs: []
+consts: {}
+owners: []
+services: []
+steps: []
+actions:
+- name: webhook-action
+  provider:
+    type: webhook
+    config: "{{ providers.kmbridge }}"
+    with:
+      body:
+        id: "{{ alert.id }}"
+        name: "{{ alert.name }}"
+        status: "{{ alert.status }}"
+        severity: "{{ alert.severity }}"
+        source: "{{ alert.source }}"
+        fingerprint: "{{ alert.fingerprint }}"
+        description: "{{ alert.description }}"
+        labels: "{{ alert.labels }}"
+        firingStartTime: "{{ alert.firingStartTime }}"
+        lastReceived: "{{ alert.lastReceived }}"
+  vars: {}`
+
+// Outcomes of Keep setup for the provider and the workflow.
+const (
+	KeepSetupUnchanged = "unchanged"
+	KeepSetupCreated   = "created"
+	KeepSetupUpdated   = "updated"
+	// KeepSetupMissing means the object does not exist and a dry run did not
+	// create it.
+	KeepSetupMissing = "missing"
+	// KeepSetupOutdated means the object differs from what the bridge
+	// expects and was left as it is.
+	KeepSetupOutdated = "outdated"
+)
+
+// KeepSetupResult says what setup found and did in Keep.
+type KeepSetupResult struct {
+	Provider string
+	// ProviderURL is where the existing provider sends alerts when that is
+	// not the bridge's webhook URL.
+	ProviderURL string
+	Workflow    string
+	// WorkflowDiff is a unified diff from the workflow in Keep to the one the
+	// bridge expects, set when they differ.
+	WorkflowDiff string
+}
+
+// Changed reports whether Keep differs, or differed before Run, from what
+// the bridge expects.
+func (r KeepSetupResult) Changed() bool {
+	return r.Provider != KeepSetupUnchanged || r.Workflow != KeepSetupUnchanged
+}
+
 type EnsureKeepSetupUseCase struct {
 	keepClient port.KeepClient
+	updater    port.KeepWorkflowUpdater
+	dryRun     bool
 	webhookURL string
 	logger     *slog.Logger
 }
@@ -32,36 +101,77 @@ func NewEnsureKeepSetupUseCase(
 	}
 }
 
+// SetWorkflowUpdater lets Run replace a workflow that differs from the one
+// the bridge expects. A nil updater, the default, leaves such a workflow as
+// it is and logs a warning.
+func (uc *EnsureKeepSetupUseCase) SetWorkflowUpdater(updater port.KeepWorkflowUpdater) {
+	uc.updater = updater
+}
+
+// SetDryRun makes Run report what it would change without changing Keep.
+func (uc *EnsureKeepSetupUseCase) SetDryRun(dryRun bool) {
+	uc.dryRun = dryRun
+}
+
 func (uc *EnsureKeepSetupUseCase) Execute(ctx context.Context) error {
-	if err := uc.ensureProvider(ctx); err != nil {
-		return fmt.Errorf("ensure provider: %w", err)
+	_, err := uc.Run(ctx)
+	return err
+}
+
+// Run makes sure Keep has the bridge's webhook provider and workflow, and
+// reports what it found and did.
+func (uc *EnsureKeepSetupUseCase) Run(ctx context.Context) (KeepSetupResult, error) {
+	var result KeepSetupResult
+	var err error
+
+	result.Provider, result.ProviderURL, err = uc.ensureProvider(ctx)
+	if err != nil {
+		return result, fmt.Errorf("ensure provider: %w", err)
 	}
 
-	if err := uc.ensureWorkflow(ctx); err != nil {
-		return fmt.Errorf("ensure workflow: %w", err)
+	result.Workflow, result.WorkflowDiff, err = uc.ensureWorkflow(ctx)
+	if err != nil {
+		return result, fmt.Errorf("ensure workflow: %w", err)
 	}
 
-	return nil
+	return result, nil
 }
 
-func (uc *EnsureKeepSetupUseCase) ensureProvider(ctx context.Context) error {
+func (uc *EnsureKeepSetupUseCase) ensureProvider(ctx context.Context) (string, string, error) {
 	providers, err := uc.keepClient.GetProviders(ctx)
 	if err != nil {
-		return fmt.Errorf("get providers: %w", err)
+		return "", "", fmt.Errorf("get providers: %w", err)
 	}
 
 	for _, p := range providers {
 		if p.Type == "webhook" && p.Name == providerName {
+			if url := providerURL(p); url != "" && url != uc.webhookURL {
+				// Keep offers no way to change an installed provider's
+				// settings through the API the bridge uses.
+				uc.logger.Warn("Keep webhook provider sends alerts elsewhere; update or delete it in Keep",
+					logger.ApplicationFields("provider_outdated",
+						slog.String("provider_name", providerName),
+						slog.String("provider_id", p.ID),
+						slog.String("provider_url", url),
+						slog.String("webhook_url", uc.webhookURL),
+					),
+				)
+				return KeepSetupOutdated, url, nil
+			}
 			uc.logger.Info("Keep webhook provider already exists",
 				logger.ApplicationFields("provider_exists",
 					slog.String("provider_name", providerName),
 					slog.String("provider_id", p.ID),
 				),
 			)
-			return nil
+			return KeepSetupUnchanged, "", nil
 		}
 	}
 
+	if uc.dryRun {
+		return KeepSetupMissing, "", nil
+	}
+
 	uc.logger.Info("Creating Keep webhook provider",
 		logger.ApplicationFields("provider_create",
 			slog.String("provider_name", providerName),
@@ -77,13 +187,13 @@ func (uc *EnsureKeepSetupUseCase) ensureProvider(ctx context.Context) error {
 	}
 
 	if err := uc.keepClient.CreateWebhookProvider(ctx, config); err != nil {
-		return fmt.Errorf("create webhook provider: %w", err)
+		return "", "", fmt.Errorf("create webhook provider: %w", err)
 	}
 
 	// Verify provider was created by checking it appears in the list
 	providers, err = uc.keepClient.GetProviders(ctx)
 	if err != nil {
-		return fmt.Errorf("verify provider creation: %w", err)
+		return "", "", fmt.Errorf("verify provider creation: %w", err)
 	}
 
 	for _, p := range providers {
@@ -94,29 +204,80 @@ func (uc *EnsureKeepSetupUseCase) ensureProvider(ctx context.Context) error {
 					slog.String("provider_id", p.ID),
 				),
 			)
-			return nil
+			return KeepSetupCreated, "", nil
 		}
 	}
 
-	return fmt.Errorf("provider created but not found in providers list")
+	return "", "", fmt.Errorf("provider created but not found in providers list")
+}
+
+// providerURL returns the URL a webhook provider posts to, or an empty
+// string when Keep did not report it.
+func providerURL(p port.KeepProvider) string {
+	auth, _ := p.Details["authentication"].(map[string]any)
+	url, _ := auth["url"].(string)
+	return url
 }
 
-func (uc *EnsureKeepSetupUseCase) ensureWorkflow(ctx context.Context) error {
+func (uc *EnsureKeepSetupUseCase) ensureWorkflow(ctx context.Context) (string, string, error) {
 	workflows, err := uc.keepClient.GetWorkflows(ctx)
 	if err != nil {
-		return fmt.Errorf("get workflows: %w", err)
+		return "", "", fmt.Errorf("get workflows: %w", err)
+	}
+
+	config := port.WorkflowConfig{
+		ID:          workflowRawID,
+		Name:        "Mattermost updates via kmbridge",
+		Description: "Route alerts to Mattermost channels via kmbridge",
+		Workflow:    keepWorkflowYAML,
 	}
 
 	for _, w := range workflows {
-		if w.WorkflowRawID == workflowRawID {
+		if w.WorkflowRawID != workflowRawID {
+			continue
+		}
+		// Older Keep versions do not return the definition, so there is
+		// nothing to compare.
+		diff := ""
+		if w.Raw != "" {
+			diff = textdiff.Unified("keep", "kmbridge", normalizeWorkflow(w.Raw), normalizeWorkflow(keepWorkflowYAML))
+		}
+		if diff == "" {
 			uc.logger.Info("Keep workflow already exists",
 				logger.ApplicationFields("workflow_exists",
 					slog.String("workflow_raw_id", workflowRawID),
 					slog.String("workflow_id", w.ID),
 				),
 			)
-			return nil
+			return KeepSetupUnchanged, "", nil
 		}
+
+		if uc.updater == nil || uc.dryRun {
+			if !uc.dryRun {
+				uc.logger.Warn("Keep workflow differs from the one this bridge expects; run kmbridge bootstrap to update it",
+					logger.ApplicationFields("workflow_outdated",
+						slog.String("workflow_raw_id", workflowRawID),
+						slog.String("workflow_id", w.ID),
+					),
+				)
+			}
+			return KeepSetupOutdated, diff, nil
+		}
+
+		if err := uc.updater.UpdateWorkflow(ctx, w.ID, config); err != nil {
+			return "", diff, fmt.Errorf("update workflow: %w", err)
+		}
+		uc.logger.Info("Keep workflow updated",
+			logger.ApplicationFields("workflow_updated",
+				slog.String("workflow_raw_id", workflowRawID),
+				slog.String("workflow_id", w.ID),
+			),
+		)
+		return KeepSetupUpdated, diff, nil
+	}
+
+	if uc.dryRun {
+		return KeepSetupMissing, "", nil
 	}
 
 	uc.logger.Info("Creating Keep workflow",
@@ -125,44 +286,8 @@ func (uc *EnsureKeepSetupUseCase) ensureWorkflow(ctx context.Context) error {
 		),
 	)
 
-	workflowYAML := `id: kmbridge-webhook
-description: Route alerts to Mattermost channels via kmbridge
-disabled: false
-triggers:
-- type: alert
-name: Mattermost updates via kmbridge
-inputs: []
-consts: {}
-owners: []
-services: []
-steps: []
-actions:
-- name: webhook-action
-  provider:
-    type: webhook
-    config: "{{ providers.kmbridge }}"
-    with:
-      body:
-        id: "{{ alert.id }}"
-        name: "{{ alert.name }}"
-        status: "{{ alert.status }}"
-        severity: "{{ alert.severity }}"
-        source: "{{ alert.source }}"
-        fingerprint: "{{ alert.fingerprint }}"
-        description: "{{ alert.description }}"
-        labels: "{{ alert.labels }}"
-        firingStartTime: "{{ alert.firingStartTime }}"
-  vars: {}`
-
-	config := port.WorkflowConfig{
-		ID:          workflowRawID,
-		Name:        "Mattermost updates via kmbridge",
-		Description: "Route alerts to Mattermost channels via kmbridge",
-		Workflow:    workflowYAML,
-	}
-
 	if err := uc.keepClient.CreateWorkflow(ctx, config); err != nil {
-		return fmt.Errorf("create workflow: %w", err)
+		return "", "", fmt.Errorf("create workflow: %w", err)
 	}
 
 	uc.logger.Info("Keep workflow created successfully",
@@ -171,5 +296,26 @@ actions:
 		),
 	)
 
-	return nil
+	return KeepSetupCreated, "", nil
+}
+
+// normalizeWorkflow re-renders a workflow definition with sorted keys and
+// uniform quoting, so definitions Keep re-serialized compare equal to the
+// original. Keep may return the definition wrapped in a "workflow" key.
+// Text that is not YAML is returned as it is.
+func normalizeWorkflow(raw string) string {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return raw
+	}
+	if inner, ok := doc["workflow"].(map[string]any); ok && len(doc) == 1 {
+		doc = inner
+	}
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return raw
+	}
+	return sb.String()
 }
@@ -5,16 +5,42 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
 )
 
-func setupEnsureKeepSetupUseCase() (*EnsureKeepSetupUseCase, *mockKeepClient) {
-	keepClient := newMockKeepClient()
+// keepSetup backs the generated Keep client mock with the providers and
+// workflows Keep already has. Created webhook providers join the list.
+type keepSetup struct {
+	*portmock.KeepClientMock
+	providers []port.KeepProvider
+	workflows []port.KeepWorkflow
+}
+
+func newKeepSetup() *keepSetup {
+	k := &keepSetup{KeepClientMock: newKeepClientMock()}
+	k.GetProvidersFunc = func(context.Context) ([]port.KeepProvider, error) {
+		return k.providers, nil
+	}
+	k.CreateWebhookProviderFunc = func(_ context.Context, config port.WebhookProviderConfig) error {
+		k.providers = append(k.providers, port.KeepProvider{ID: "created-provider-id", Type: "webhook", Name: config.Name})
+		return nil
+	}
+	k.GetWorkflowsFunc = func(context.Context) ([]port.KeepWorkflow, error) {
+		return k.workflows, nil
+	}
+	return k
+}
+
+func setupEnsureKeepSetupUseCase() (*EnsureKeepSetupUseCase, *keepSetup) {
+	keepClient := newKeepSetup()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	uc := NewEnsureKeepSetupUseCase(
@@ -33,17 +59,17 @@ func TestEnsureKeepSetupUseCase_CreatesProviderAndWorkflow(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, keepClient.createWebhookCalled)
-	assert.True(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
+	assert.NotEmpty(t, keepClient.CreateWorkflowCalls())
 
-	assert.Equal(t, "kmbridge", keepClient.createdWebhookConfig.Name)
-	assert.Equal(t, "https://kmbridge.example.com/webhook", keepClient.createdWebhookConfig.URL)
-	assert.Equal(t, "POST", keepClient.createdWebhookConfig.Method)
-	assert.False(t, keepClient.createdWebhookConfig.Verify)
+	assert.Equal(t, "kmbridge", lastCall(keepClient.CreateWebhookProviderCalls()).Config.Name)
+	assert.Equal(t, "https://kmbridge.example.com/webhook", lastCall(keepClient.CreateWebhookProviderCalls()).Config.URL)
+	assert.Equal(t, "POST", lastCall(keepClient.CreateWebhookProviderCalls()).Config.Method)
+	assert.False(t, lastCall(keepClient.CreateWebhookProviderCalls()).Config.Verify)
 
-	assert.Equal(t, "kmbridge-webhook", keepClient.createdWorkflowConfig.ID)
-	assert.Equal(t, "Mattermost updates via kmbridge", keepClient.createdWorkflowConfig.Name)
-	assert.Contains(t, keepClient.createdWorkflowConfig.Workflow, "providers.kmbridge")
+	assert.Equal(t, "kmbridge-webhook", lastCall(keepClient.CreateWorkflowCalls()).Config.ID)
+	assert.Equal(t, "Mattermost updates via kmbridge", lastCall(keepClient.CreateWorkflowCalls()).Config.Name)
+	assert.Contains(t, lastCall(keepClient.CreateWorkflowCalls()).Config.Workflow, "providers.kmbridge")
 }
 
 func TestEnsureKeepSetupUseCase_ProviderAlreadyExists(t *testing.T) {
@@ -57,8 +83,8 @@ func TestEnsureKeepSetupUseCase_ProviderAlreadyExists(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, keepClient.createWebhookCalled)
-	assert.True(t, keepClient.createWorkflowCalled)
+	assert.Empty(t, keepClient.CreateWebhookProviderCalls())
+	assert.NotEmpty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_WorkflowAlreadyExists(t *testing.T) {
@@ -72,8 +98,8 @@ func TestEnsureKeepSetupUseCase_WorkflowAlreadyExists(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, keepClient.createWebhookCalled)
-	assert.False(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_BothAlreadyExist(t *testing.T) {
@@ -90,68 +116,68 @@ func TestEnsureKeepSetupUseCase_BothAlreadyExist(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, keepClient.createWebhookCalled)
-	assert.False(t, keepClient.createWorkflowCalled)
+	assert.Empty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_GetProvidersError(t *testing.T) {
 	uc, keepClient := setupEnsureKeepSetupUseCase()
 	ctx := context.Background()
 
-	keepClient.getProvidersErr = errors.New("api error")
+	keepClient.GetProvidersFunc = func(context.Context) ([]port.KeepProvider, error) { return nil, errors.New("api error") }
 
 	err := uc.Execute(ctx)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "ensure provider")
 	assert.Contains(t, err.Error(), "get providers")
-	assert.False(t, keepClient.createWebhookCalled)
-	assert.False(t, keepClient.createWorkflowCalled)
+	assert.Empty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_CreateProviderError(t *testing.T) {
 	uc, keepClient := setupEnsureKeepSetupUseCase()
 	ctx := context.Background()
 
-	keepClient.createWebhookErr = errors.New("create error")
+	keepClient.CreateWebhookProviderFunc = func(context.Context, port.WebhookProviderConfig) error { return errors.New("create error") }
 
 	err := uc.Execute(ctx)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "ensure provider")
 	assert.Contains(t, err.Error(), "create webhook provider")
-	assert.True(t, keepClient.createWebhookCalled)
-	assert.False(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_GetWorkflowsError(t *testing.T) {
 	uc, keepClient := setupEnsureKeepSetupUseCase()
 	ctx := context.Background()
 
-	keepClient.getWorkflowsErr = errors.New("api error")
+	keepClient.GetWorkflowsFunc = func(context.Context) ([]port.KeepWorkflow, error) { return nil, errors.New("api error") }
 
 	err := uc.Execute(ctx)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "ensure workflow")
 	assert.Contains(t, err.Error(), "get workflows")
-	assert.True(t, keepClient.createWebhookCalled)
-	assert.False(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_CreateWorkflowError(t *testing.T) {
 	uc, keepClient := setupEnsureKeepSetupUseCase()
 	ctx := context.Background()
 
-	keepClient.createWorkflowErr = errors.New("create error")
+	keepClient.CreateWorkflowFunc = func(context.Context, port.WorkflowConfig) error { return errors.New("create error") }
 
 	err := uc.Execute(ctx)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "ensure workflow")
 	assert.Contains(t, err.Error(), "create workflow")
-	assert.True(t, keepClient.createWebhookCalled)
-	assert.True(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
+	assert.NotEmpty(t, keepClient.CreateWorkflowCalls())
 }
 
 func TestEnsureKeepSetupUseCase_DifferentProviderTypesIgnored(t *testing.T) {
@@ -166,7 +192,7 @@ func TestEnsureKeepSetupUseCase_DifferentProviderTypesIgnored(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, keepClient.createWebhookCalled)
+	assert.NotEmpty(t, keepClient.CreateWebhookProviderCalls())
 }
 
 func TestEnsureKeepSetupUseCase_DifferentWorkflowIDsIgnored(t *testing.T) {
@@ -180,5 +206,147 @@ func TestEnsureKeepSetupUseCase_DifferentWorkflowIDsIgnored(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, keepClient.createWorkflowCalled)
+	assert.NotEmpty(t, keepClient.CreateWorkflowCalls())
+}
+
+func TestEnsureKeepSetupUseCase_ProviderNotVisibleAfterCreate(t *testing.T) {
+	keepClient := &portmock.KeepClientMock{
+		GetProvidersFunc: func(ctx context.Context) ([]port.KeepProvider, error) {
+			return nil, nil
+		},
+		CreateWebhookProviderFunc: func(ctx context.Context, config port.WebhookProviderConfig) error {
+			return nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	uc := NewEnsureKeepSetupUseCase(keepClient, "https://kmbridge.example.com/webhook", logger)
+
+	err := uc.Execute(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "provider created but not found")
+	assert.Len(t, keepClient.GetProvidersCalls(), 2)
+	assert.Len(t, keepClient.CreateWebhookProviderCalls(), 1)
+	assert.Empty(t, keepClient.GetWorkflowsCalls())
+}
+
+func TestEnsureKeepSetupUseCase_ReserializedWorkflowIsUnchanged(t *testing.T) {
+	uc, keepClient := setupEnsureKeepSetupUseCase()
+	updater := &portmock.KeepWorkflowUpdaterMock{}
+	uc.SetWorkflowUpdater(updater)
+
+	// Keep returns the definition wrapped and re-serialized with other
+	// quoting and key order.
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(keepWorkflowYAML), &doc))
+	raw, err := yaml.Marshal(map[string]any{"workflow": doc})
+	require.NoError(t, err)
+	keepClient.providers = []port.KeepProvider{{ID: "provider-123", Type: "webhook", Name: "kmbridge"}}
+	keepClient.workflows = []port.KeepWorkflow{{ID: "workflow-123", WorkflowRawID: "kmbridge-webhook", Raw: string(raw)}}
+
+	result, err := uc.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, KeepSetupUnchanged, result.Workflow)
+	assert.Empty(t, result.WorkflowDiff)
+	assert.False(t, result.Changed())
+	assert.Empty(t, updater.UpdateWorkflowCalls())
+}
+
+func TestEnsureKeepSetupUseCase_OutdatedWorkflow(t *testing.T) {
+	outdated := strings.Replace(keepWorkflowYAML, "        lastReceived: \"{{ alert.lastReceived }}\"\n", "", 1)
+	require.NotEqual(t, keepWorkflowYAML, outdated)
+
+	t.Run("left alone without an updater", func(t *testing.T) {
+		uc, keepClient := setupEnsureKeepSetupUseCase()
+		keepClient.workflows = []port.KeepWorkflow{{ID: "workflow-123", WorkflowRawID: "kmbridge-webhook", Raw: outdated}}
+
+		result, err := uc.Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, KeepSetupOutdated, result.Workflow)
+		assert.Regexp(t, `\n\+ +lastReceived: '\{\{ alert.lastReceived \}\}'\n`, result.WorkflowDiff)
+		assert.Empty(t, keepClient.CreateWorkflowCalls())
+	})
+
+	t.Run("updated with an updater", func(t *testing.T) {
+		uc, keepClient := setupEnsureKeepSetupUseCase()
+		updater := &portmock.KeepWorkflowUpdaterMock{
+			UpdateWorkflowFunc: func(ctx context.Context, workflowID string, config port.WorkflowConfig) error { return nil },
+		}
+		uc.SetWorkflowUpdater(updater)
+		keepClient.workflows = []port.KeepWorkflow{{ID: "workflow-123", WorkflowRawID: "kmbridge-webhook", Raw: outdated}}
+
+		result, err := uc.Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, KeepSetupUpdated, result.Workflow)
+		assert.NotEmpty(t, result.WorkflowDiff)
+		require.Len(t, updater.UpdateWorkflowCalls(), 1)
+		assert.Equal(t, "workflow-123", updater.UpdateWorkflowCalls()[0].WorkflowID)
+		assert.Equal(t, keepWorkflowYAML, updater.UpdateWorkflowCalls()[0].Config.Workflow)
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		uc, keepClient := setupEnsureKeepSetupUseCase()
+		uc.SetWorkflowUpdater(&portmock.KeepWorkflowUpdaterMock{
+			UpdateWorkflowFunc: func(ctx context.Context, workflowID string, config port.WorkflowConfig) error {
+				return errors.New("status 500")
+			},
+		})
+		keepClient.workflows = []port.KeepWorkflow{{ID: "workflow-123", WorkflowRawID: "kmbridge-webhook", Raw: outdated}}
+
+		_, err := uc.Run(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "update workflow")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		uc, keepClient := setupEnsureKeepSetupUseCase()
+		updater := &portmock.KeepWorkflowUpdaterMock{}
+		uc.SetWorkflowUpdater(updater)
+		uc.SetDryRun(true)
+		keepClient.workflows = []port.KeepWorkflow{{ID: "workflow-123", WorkflowRawID: "kmbridge-webhook", Raw: outdated}}
+
+		result, err := uc.Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, KeepSetupMissing, result.Provider)
+		assert.Equal(t, KeepSetupOutdated, result.Workflow)
+		assert.NotEmpty(t, result.WorkflowDiff)
+		assert.True(t, result.Changed())
+		assert.Empty(t, keepClient.CreateWebhookProviderCalls())
+		assert.Empty(t, updater.UpdateWorkflowCalls())
+	})
+}
+
+func TestEnsureKeepSetupUseCase_DryRunCreatesNothing(t *testing.T) {
+	uc, keepClient := setupEnsureKeepSetupUseCase()
+	uc.SetDryRun(true)
+
+	result, err := uc.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, KeepSetupMissing, result.Provider)
+	assert.Equal(t, KeepSetupMissing, result.Workflow)
+	assert.Empty(t, keepClient.CreateWebhookProviderCalls())
+	assert.Empty(t, keepClient.CreateWorkflowCalls())
+}
+
+func TestEnsureKeepSetupUseCase_ProviderPointsElsewhere(t *testing.T) {
+	uc, keepClient := setupEnsureKeepSetupUseCase()
+	keepClient.providers = []port.KeepProvider{{
+		ID:      "provider-123",
+		Type:    "webhook",
+		Name:    "kmbridge",
+		Details: map[string]any{"authentication": map[string]any{"url": "https://old.example.com/webhook"}},
+	}}
+
+	result, err := uc.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, KeepSetupOutdated, result.Provider)
+	assert.Equal(t, "https://old.example.com/webhook", result.ProviderURL)
+	assert.Empty(t, keepClient.CreateWebhookProviderCalls())
 }
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// Error post reasons.
+const (
+	// ErrorPostRejected reports an alert the bridge cannot handle, e.g. one
+	// with an unknown severity.
+	ErrorPostRejected = "rejected"
+	// ErrorPostFailed reports an alert whose handling failed, e.g. because
+	// Mattermost did not take its post.
+	ErrorPostFailed = "failed"
+)
+
+// maxErrorPayloadBytes bounds the payload shown in an error post, well below
+// Mattermost's post size limit.
+const maxErrorPayloadBytes = 4000
+
+// ErrorPosts posts alerts the bridge failed to handle to an ops channel with
+// their payload, so failures show up where operators look instead of only as
+// errors answered to Keep. Each alert is posted at most once per
+// repeatInterval, as Keep and the ingest queue retry failed webhooks.
+type ErrorPosts struct {
+	mmClient       port.MattermostClient
+	msgBuilder     port.MessageBuilder
+	channelID      string
+	keepUIURL      string
+	repeatInterval time.Duration
+	clock          clock.Clock
+	logger         *slog.Logger
+
+	mu     sync.Mutex
+	posted map[string]time.Time // fingerprint -> last error post
+}
+
+func NewErrorPosts(mmClient port.MattermostClient, msgBuilder port.MessageBuilder, channelID, keepUIURL string, repeatInterval time.Duration, logger *slog.Logger) *ErrorPosts {
+	return &ErrorPosts{
+		mmClient:       mmClient,
+		msgBuilder:     msgBuilder,
+		channelID:      channelID,
+		keepUIURL:      keepUIURL,
+		repeatInterval: repeatInterval,
+		clock:          clock.Real(),
+		logger:         logger,
+		posted:         make(map[string]time.Time),
+	}
+}
+
+// SetClock replaces the clock that spaces out repeated error posts.
+func (p *ErrorPosts) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// WrapAlerts returns an alert use case that reports the alerts that alerts
+// fails to handle. Alerts kept by the dead-letter queue are left to it: it reports
+// them once they used up their attempts.
+func (p *ErrorPosts) WrapAlerts(alerts port.AlertUseCase) port.AlertUseCase {
+	return &errorPostAlerts{alerts: alerts, posts: p}
+}
+
+type errorPostAlerts struct {
+	alerts port.AlertUseCase
+	posts  *ErrorPosts
+}
+
+func (a *errorPostAlerts) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	err := a.alerts.Execute(ctx, input)
+	if err != nil && !errors.Is(err, ErrDeadLettered) {
+		a.posts.Report(ctx, input, err)
+	}
+	return err
+}
+
+// Report posts input and the error handling it returned to the ops channel,
+// unless the alert was reported within the repeat interval.
+func (p *ErrorPosts) Report(ctx context.Context, input dto.KeepAlertInput, cause error) {
+	reason := ErrorPostFailed
+	if isInvalidAlert(cause) {
+		reason = ErrorPostRejected
+	}
+	if !p.due(input.Fingerprint) {
+		return
+	}
+
+	payload, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		payload = []byte(err.Error())
+	}
+	if len(payload) > maxErrorPayloadBytes {
+		payload = append(payload[:maxErrorPayloadBytes], "\n…"...)
+	}
+	name := input.Name
+	if name == "" {
+		name = "Unnamed alert"
+	}
+	attachment := p.msgBuilder.ForChannel(p.channelID).BuildErrorAttachment(name, input.Fingerprint, p.keepUIURL, cause.Error())
+	// The error is shown as text: the post has nothing to click.
+	attachment.Actions = nil
+	attachment.Text = fmt.Sprintf("**Alert %s:** %s\n```json\n%s\n```", reason, cause.Error(), payload)
+
+	// Report even when the request that failed was canceled.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if _, err := p.mmClient.CreatePost(ctx, p.channelID, attachment); err != nil {
+		errorPostErrorsCounter.Inc()
+		p.mu.Lock()
+		delete(p.posted, input.Fingerprint)
+		p.mu.Unlock()
+		p.logger.Warn("Failed to post alert error",
+			slog.String("fingerprint", input.Fingerprint),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	errorPostsCounter(reason).Inc()
+	p.logger.Info("Alert error posted",
+		logger.ApplicationFields("alert_error_posted",
+			slog.String("fingerprint", input.Fingerprint),
+			slog.String("reason", reason),
+			slog.String("channel_id", p.channelID),
+		),
+	)
+}
+
+// due records an error post for fingerprint and reports whether it is due.
+func (p *ErrorPosts) due(fingerprint string) bool {
+	now := p.clock.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for fp, at := range p.posted {
+		if now.Sub(at) >= p.repeatInterval {
+			delete(p.posted, fp)
+		}
+	}
+	if _, ok := p.posted[fingerprint]; ok {
+		return false
+	}
+	p.posted[fingerprint] = now
+	return true
+}
+
+// isInvalidAlert reports whether err rejects the alert itself, so handling it
+// again would fail the same way.
+func isInvalidAlert(err error) bool {
+	return errors.Is(err, alert.ErrInvalidFingerprint) || errors.Is(err, alert.ErrInvalidSeverity) ||
+		errors.Is(err, alert.ErrInvalidStatus) || errors.Is(err, alert.ErrInvalidAlert)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func setupErrorPosts() (*ErrorPosts, *portmock.MattermostClientMock, *clock.Fake) {
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "error-post", nil
+		},
+	}
+	fakeClock := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	p := NewErrorPosts(mmClient, newMessageBuilderMock(), "ops-channel", "https://keep.example.com", time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	p.SetClock(fakeClock)
+	return p, mmClient, fakeClock
+}
+
+func TestErrorPosts_ReportsFailedAlerts(t *testing.T) {
+	p, mmClient, fakeClock := setupErrorPosts()
+	alerts := p.WrapAlerts(&portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			return fmt.Errorf("parse severity: %w: %s is not accepted", alert.ErrInvalidSeverity, input.Severity)
+		},
+	})
+	ctx := context.Background()
+	input := dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Disk full", Severity: "debug", Status: "firing"}
+
+	rejected := errorPostsCounter(ErrorPostRejected).Get()
+	require.Error(t, alerts.Execute(ctx, input), "the error is still returned")
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "ops-channel", calls[0].ChannelID)
+	assert.Equal(t, "Disk full", calls[0].Attachment.Title)
+	assert.Contains(t, calls[0].Attachment.Text, "**Alert rejected:** parse severity")
+	assert.Contains(t, calls[0].Attachment.Text, `"fingerprint": "fp-1"`)
+	assert.Equal(t, rejected+1, errorPostsCounter(ErrorPostRejected).Get())
+
+	require.Error(t, alerts.Execute(ctx, input))
+	assert.Len(t, mmClient.CreatePostCalls(), 1, "retries are not posted again")
+
+	fakeClock.Advance(time.Hour)
+	require.Error(t, alerts.Execute(ctx, input))
+	assert.Len(t, mmClient.CreatePostCalls(), 2, "failures are posted again after the repeat interval")
+}
+
+func TestErrorPosts_LeavesDeadLetteredAlertsToTheQueue(t *testing.T) {
+	p, mmClient, _ := setupErrorPosts()
+	q, _, _, alerts, _, down := setupDeadLetterQueue(2)
+	q.SetErrorPosts(p)
+	alerts = p.WrapAlerts(alerts)
+	ctx := context.Background()
+
+	*down = true
+	err := alerts.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Disk full", Severity: "high"})
+	require.ErrorIs(t, err, ErrDeadLettered)
+	assert.Empty(t, mmClient.CreatePostCalls(), "queued alerts are re-delivered first")
+
+	require.Error(t, q.Redeliver(ctx))
+	assert.Empty(t, mmClient.CreatePostCalls())
+	require.Error(t, q.Redeliver(ctx))
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 1, "alerts are posted once they used up their attempts")
+	assert.Contains(t, calls[0].Attachment.Text, "**Alert failed:** mattermost create post: status 502")
+}
+
+func TestErrorPosts_RetriesFailedErrorPosts(t *testing.T) {
+	p, mmClient, _ := setupErrorPosts()
+	mmClient.CreatePostFunc = func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+		return "", errors.New("mattermost down")
+	}
+	ctx := context.Background()
+	input := dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Disk full"}
+
+	p.Report(ctx, input, errors.New("boom"))
+	p.Report(ctx, input, errors.New("boom"))
+	assert.Len(t, mmClient.CreatePostCalls(), 2, "an error post that failed is not counted")
+}
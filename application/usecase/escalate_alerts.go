@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// EscalateAlertsUseCase escalates firing alerts that nobody acknowledged in
+// time. Each run applies at most one escalation step per alert: a mention in
+// the alert thread and, optionally, a copy of the alert in another channel.
+// The number of steps taken is stored on the post so restarts do not repeat
+// them.
+type EscalateAlertsUseCase struct {
+	postRepo      post.Repository
+	keepClient    port.KeepClient
+	mmClient      port.MattermostClient
+	msgBuilder    port.MessageBuilder
+	rules         map[string][]port.EscalationRule
+	mattermostURL string
+	keepUIURL     string
+	callbackURL   string
+	audit         *AuditTrail
+	clock         clock.Clock
+	logger        *slog.Logger
+}
+
+func NewEscalateAlertsUseCase(
+	postRepo post.Repository,
+	keepClient port.KeepClient,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	rules []port.EscalationRule,
+	mattermostURL string,
+	keepUIURL string,
+	callbackURL string,
+	logger *slog.Logger,
+) *EscalateAlertsUseCase {
+	bySeverity := make(map[string][]port.EscalationRule)
+	for _, rule := range rules {
+		severity := strings.ToLower(rule.Severity)
+		bySeverity[severity] = append(bySeverity[severity], rule)
+	}
+	for _, severityRules := range bySeverity {
+		sort.SliceStable(severityRules, func(i, j int) bool {
+			return severityRules[i].After < severityRules[j].After
+		})
+	}
+
+	return &EscalateAlertsUseCase{
+		postRepo:      postRepo,
+		keepClient:    keepClient,
+		mmClient:      mmClient,
+		msgBuilder:    msgBuilder,
+		rules:         bySeverity,
+		mattermostURL: strings.TrimRight(mattermostURL, "/"),
+		keepUIURL:     keepUIURL,
+		callbackURL:   callbackURL,
+		clock:         clock.Real(),
+		logger:        logger,
+	}
+}
+
+// SetClock replaces the clock used to measure how long alerts have been firing.
+func (uc *EscalateAlertsUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// SetAuditTrail records every escalation step. A nil trail, the default,
+// records nothing.
+func (uc *EscalateAlertsUseCase) SetAuditTrail(trail *AuditTrail) {
+	uc.audit = trail
+}
+
+// Execute checks every active post against the escalation rules for its
+// severity. Posts that fail are retried on the next run.
+func (uc *EscalateAlertsUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	now := uc.clock.Now()
+	var errs []error
+	for _, p := range posts {
+		rule, ok := uc.dueRule(p, now)
+		if !ok {
+			continue
+		}
+		if err := uc.escalate(ctx, p, rule, now); err != nil {
+			errs = append(errs, fmt.Errorf("escalate %s: %w", p.Fingerprint().Value(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dueRule returns the next escalation step for the post if its deadline has
+// passed. Snoozed posts and posts with a known assignee are never due.
+func (uc *EscalateAlertsUseCase) dueRule(p *post.Post, now time.Time) (port.EscalationRule, bool) {
+	rules := uc.rules[p.Severity().Value()]
+	level := p.EscalationLevel()
+	if level >= len(rules) || p.IsSnoozed(now) || p.LastKnownAssignee() != "" {
+		return port.EscalationRule{}, false
+	}
+	rule := rules[level]
+	if now.Sub(p.FiringStartTime()) < rule.After {
+		return port.EscalationRule{}, false
+	}
+	return rule, true
+}
+
+func (uc *EscalateAlertsUseCase) escalate(ctx context.Context, p *post.Post, rule port.EscalationRule, now time.Time) error {
+	fingerprint := p.Fingerprint()
+
+	// The post store does not track acknowledgement, so Keep is the source of
+	// truth for whether the alert still needs attention.
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		return fmt.Errorf("get alert from keep: %w", err)
+	}
+	if keepAlert.Status != alert.StatusFiring ||
+		keepAlert.Enrichments[EnrichmentKeyStatus] == alert.StatusAcknowledged ||
+		keepAlert.Enrichments[EnrichmentKeyAssignee] != "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("🚨 Escalation: not acknowledged for %s", formatEscalationAge(now.Sub(p.FiringStartTime())))
+	if rule.Mention != "" {
+		message += " " + rule.Mention
+	}
+
+	if rule.ChannelID != "" && rule.ChannelID != p.ChannelID() {
+		attachment := uc.buildEscalationAttachment(rule.ChannelID, p, keepAlert, message)
+		if _, err := uc.mmClient.CreatePost(ctx, rule.ChannelID, attachment); err != nil {
+			return fmt.Errorf("repost to escalation channel: %w", err)
+		}
+		message += fmt.Sprintf(" (reposted to ~%s)", rule.ChannelID)
+	}
+
+	if err := uc.mmClient.ReplyToThread(ctx, p.ChannelID(), p.PostID(), message); err != nil {
+		return fmt.Errorf("reply to thread: %w", err)
+	}
+
+	p.Escalate(now)
+	p.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, p); err != nil {
+		return fmt.Errorf("update post in store: %w", err)
+	}
+
+	uc.logger.Info("Alert escalated",
+		logger.ApplicationFields("alert_escalated",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("severity", p.Severity().Value()),
+			slog.Int("level", p.EscalationLevel()),
+			slog.String("mention", rule.Mention),
+			slog.String("channel_id", rule.ChannelID),
+		),
+	)
+	alertsEscalatedCounter(p.Severity().Value(), strconv.Itoa(p.EscalationLevel())).Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindEscalated, "", message)
+
+	return nil
+}
+
+// buildEscalationAttachment renders the alert for the escalation channel.
+// Buttons are dropped because callbacks act on the original post; the text
+// links back to the original thread instead.
+func (uc *EscalateAlertsUseCase) buildEscalationAttachment(channelID string, p *post.Post, keepAlert *port.KeepAlert, message string) post.Attachment {
+	severity, err := alert.NewSeverity(keepAlert.Severity)
+	if err != nil {
+		severity = p.Severity()
+	}
+	a := alert.RestoreAlert(
+		p.Fingerprint(),
+		keepAlert.Name,
+		severity,
+		alert.RestoreStatus(alert.StatusFiring),
+		keepAlert.Description,
+		strings.Join(keepAlert.Source, ", "),
+		keepAlert.Labels,
+		p.FiringStartTime(),
+	).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL).WithFireCount(p.FireCount())
+
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	attachment.Actions = nil
+
+	header := message
+	if uc.mattermostURL != "" {
+		header += fmt.Sprintf("\n[Open original thread](%s/_redirect/pl/%s)", uc.mattermostURL, p.PostID())
+	}
+	if attachment.Text != "" {
+		header += "\n\n" + attachment.Text
+	}
+	attachment.Text = header
+
+	return attachment
+}
+
+func formatEscalationAge(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	s := strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type escalateFixture struct {
+	uc         *EscalateAlertsUseCase
+	postRepo   *postStore
+	keepClient *portmock.KeepClientMock
+	mmClient   *portmock.MattermostClientMock
+	clock      *clock.Fake
+}
+
+var testEscalationRules = []port.EscalationRule{
+	{Severity: "critical", After: 30 * time.Minute, Mention: "@sre-leads", ChannelID: "ch-escalations"},
+	{Severity: "critical", After: 15 * time.Minute, Mention: "@sre-oncall"},
+}
+
+func setupEscalateAlertsUseCase(keepAlert *port.KeepAlert) *escalateFixture {
+	f := &escalateFixture{
+		postRepo: newPostStore(),
+		keepClient: &portmock.KeepClientMock{
+			GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+				return keepAlert, nil
+			},
+		},
+		mmClient: &portmock.MattermostClientMock{
+			CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+				return "copy-1", nil
+			},
+			ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+				return nil
+			},
+		},
+		clock: clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildFiringAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
+			return post.Attachment{
+				Title:   "FIRING: " + a.Name(),
+				Text:    a.Description(),
+				Actions: []post.Button{{ID: "acknowledge"}},
+			}
+		},
+	}
+	msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return msgBuilder }
+	f.uc = NewEscalateAlertsUseCase(
+		f.postRepo,
+		f.keepClient,
+		f.mmClient,
+		msgBuilder,
+		testEscalationRules,
+		"https://mm.example.com/",
+		"https://keep.example.com",
+		"https://callback.example.com",
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	f.uc.SetClock(f.clock)
+	return f
+}
+
+func (f *escalateFixture) addPost(fp, severity string, firingFor time.Duration) *post.Post {
+	p := post.NewPost("post-"+fp, "ch-alerts", alert.RestoreFingerprint(fp), "DB down", alert.RestoreSeverity(severity), f.clock.Now().Add(-firingFor), time.Now())
+	f.postRepo.posts[fp] = p
+	return p
+}
+
+func TestEscalateAlerts_FirstStepMentionsInThread(t *testing.T) {
+	f := setupEscalateAlertsUseCase(firingKeepAlert())
+	f.addPost("fp-1", "critical", 20*time.Minute)
+	f.addPost("fp-young", "critical", 5*time.Minute)
+	f.addPost("fp-high", "high", 2*time.Hour)
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "ch-alerts", replies[0].ChannelID)
+	assert.Equal(t, "post-fp-1", replies[0].RootID)
+	assert.Equal(t, "🚨 Escalation: not acknowledged for 20m @sre-oncall", replies[0].Message)
+	assert.Empty(t, f.mmClient.CreatePostCalls())
+
+	assert.Equal(t, 1, f.postRepo.posts["fp-1"].EscalationLevel())
+	assert.Equal(t, f.clock.Now(), f.postRepo.posts["fp-1"].EscalatedAt())
+	assert.Zero(t, f.postRepo.posts["fp-young"].EscalationLevel())
+}
+
+func TestEscalateAlerts_StepsRunOnce(t *testing.T) {
+	f := setupEscalateAlertsUseCase(firingKeepAlert())
+	f.addPost("fp-1", "critical", 20*time.Minute)
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+	require.NoError(t, f.uc.Execute(context.Background()))
+	assert.Len(t, f.mmClient.ReplyToThreadCalls(), 1, "first step is not repeated")
+
+	f.clock.Advance(15 * time.Minute)
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 2)
+	assert.Equal(t, "🚨 Escalation: not acknowledged for 35m @sre-leads (reposted to ~ch-escalations)", replies[1].Message)
+
+	creates := f.mmClient.CreatePostCalls()
+	require.Len(t, creates, 1)
+	assert.Equal(t, "ch-escalations", creates[0].ChannelID)
+	assert.Empty(t, creates[0].Attachment.Actions, "buttons only work on the original post")
+	assert.Equal(t, "FIRING: Disk full", creates[0].Attachment.Title)
+	assert.Contains(t, creates[0].Attachment.Text, "@sre-leads")
+	assert.Contains(t, creates[0].Attachment.Text, "(https://mm.example.com/_redirect/pl/post-fp-1)")
+
+	f.clock.Advance(time.Hour)
+	require.NoError(t, f.uc.Execute(context.Background()))
+	assert.Len(t, f.mmClient.ReplyToThreadCalls(), 2, "no rules left")
+	assert.Equal(t, 2, f.postRepo.posts["fp-1"].EscalationLevel())
+}
+
+func TestEscalateAlerts_SkipsAcknowledged(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepAlert func() *port.KeepAlert
+		post      func(p *post.Post, now time.Time)
+	}{
+		{name: "acknowledged in keep", keepAlert: func() *port.KeepAlert {
+			a := firingKeepAlert()
+			a.Status = alert.StatusAcknowledged
+			return a
+		}},
+		{name: "acknowledged enrichment", keepAlert: func() *port.KeepAlert {
+			a := firingKeepAlert()
+			a.Enrichments = map[string]string{EnrichmentKeyStatus: alert.StatusAcknowledged}
+			return a
+		}},
+		{name: "assignee in keep", keepAlert: func() *port.KeepAlert {
+			a := firingKeepAlert()
+			a.Enrichments = map[string]string{EnrichmentKeyAssignee: "alice@keep"}
+			return a
+		}},
+		{name: "known assignee", keepAlert: firingKeepAlert, post: func(p *post.Post, now time.Time) {
+			p.SetLastKnownAssignee("alice")
+		}},
+		{name: "snoozed", keepAlert: firingKeepAlert, post: func(p *post.Post, now time.Time) {
+			p.Snooze(now.Add(time.Hour))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupEscalateAlertsUseCase(tt.keepAlert())
+			p := f.addPost("fp-1", "critical", time.Hour)
+			if tt.post != nil {
+				tt.post(p, f.clock.Now())
+			}
+
+			require.NoError(t, f.uc.Execute(context.Background()))
+
+			assert.Empty(t, f.mmClient.ReplyToThreadCalls())
+			assert.Zero(t, p.EscalationLevel())
+		})
+	}
+}
+
+func TestEscalateAlerts_ReplyErrorRetriedNextRun(t *testing.T) {
+	f := setupEscalateAlertsUseCase(firingKeepAlert())
+	f.addPost("fp-1", "critical", 20*time.Minute)
+	f.mmClient.ReplyToThreadFunc = func(ctx context.Context, channelID, rootID, message string) error {
+		return errors.New("mattermost down")
+	}
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "escalate fp-1")
+	assert.Zero(t, f.postRepo.posts["fp-1"].EscalationLevel())
+	assert.Empty(t, f.postRepo.SaveCalls())
+}
+
+func TestEscalateAlerts_KeepError(t *testing.T) {
+	f := setupEscalateAlertsUseCase(nil)
+	f.keepClient.GetAlertFunc = func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+		return nil, errors.New("keep down")
+	}
+	f.addPost("fp-1", "critical", 20*time.Minute)
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "keep down")
+	assert.Empty(t, f.mmClient.ReplyToThreadCalls())
+}
+
+func TestFormatEscalationAge(t *testing.T) {
+	assert.Equal(t, "less than a minute", formatEscalationAge(30*time.Second))
+	assert.Equal(t, "15m", formatEscalationAge(15*time.Minute))
+	assert.Equal(t, "1h", formatEscalationAge(time.Hour))
+	assert.Equal(t, "1h30m", formatEscalationAge(90*time.Minute))
+}
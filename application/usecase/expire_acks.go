@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// ExpireAcksUseCase ends acknowledgements given for a limited time from the
+// Acknowledge for menu. When the deadline passes before the alert resolves,
+// the alert is unacknowledged in Keep, its post fires again and the user who
+// acknowledged it is pinged in the thread.
+type ExpireAcksUseCase struct {
+	postRepo    post.Repository
+	keepClient  port.KeepClient
+	mmClient    port.MattermostClient
+	msgBuilder  port.MessageBuilder
+	keepUIURL   string
+	callbackURL string
+	audit       *AuditTrail
+	clock       clock.Clock
+	logger      *slog.Logger
+}
+
+func NewExpireAcksUseCase(
+	postRepo post.Repository,
+	keepClient port.KeepClient,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	keepUIURL string,
+	callbackURL string,
+	logger *slog.Logger,
+) *ExpireAcksUseCase {
+	return &ExpireAcksUseCase{
+		postRepo:    postRepo,
+		keepClient:  keepClient,
+		mmClient:    mmClient,
+		msgBuilder:  msgBuilder,
+		keepUIURL:   keepUIURL,
+		callbackURL: callbackURL,
+		clock:       clock.Real(),
+		logger:      logger,
+	}
+}
+
+// SetAuditTrail records every expired acknowledgement. A nil trail, the
+// default, records nothing.
+func (uc *ExpireAcksUseCase) SetAuditTrail(trail *AuditTrail) {
+	uc.audit = trail
+}
+
+// SetClock replaces the clock used to decide whether a deadline has passed.
+func (uc *ExpireAcksUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Execute unacknowledges every alert whose acknowledgement deadline has
+// passed. Posts that fail keep their deadline and are retried on the next
+// run.
+func (uc *ExpireAcksUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	now := uc.clock.Now()
+	var errs []error
+	for _, p := range posts {
+		if !p.AckExpired(now) {
+			continue
+		}
+		if err := uc.expire(ctx, p); err != nil {
+			errs = append(errs, fmt.Errorf("expire ack %s: %w", p.Fingerprint().Value(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (uc *ExpireAcksUseCase) expire(ctx context.Context, p *post.Post) error {
+	fingerprint := p.Fingerprint()
+	ackedBy := p.AckedBy()
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		return fmt.Errorf("get alert from keep: %w", err)
+	}
+
+	// An alert unacknowledged or resolved since needs nothing more: the
+	// post already follows Keep.
+	acknowledged := keepAlert.Status == alert.StatusAcknowledged ||
+		keepAlert.Enrichments[EnrichmentKeyStatus] == alert.StatusAcknowledged
+	if acknowledged {
+		if err := uc.unacknowledge(ctx, p, keepAlert); err != nil {
+			return err
+		}
+	}
+
+	p.ClearAckTimer()
+	p.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, p); err != nil {
+		return fmt.Errorf("update post in store: %w", err)
+	}
+	if !acknowledged {
+		return nil
+	}
+
+	message, detail := "⏰ Acknowledgement expired before the alert resolved, it is firing again", "acknowledgement expired"
+	if ackedBy != "" {
+		message = fmt.Sprintf("⏰ @%s, your acknowledgement expired before the alert resolved, it is firing again", ackedBy)
+		detail = "acknowledgement by @" + ackedBy + " expired"
+	}
+	if err := uc.mmClient.ReplyToThread(ctx, p.ChannelID(), p.PostID(), message); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", p.PostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	uc.logger.Info("Acknowledgement expired",
+		logger.ApplicationFields("alert_ack_expired",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("acked_by", ackedBy),
+		),
+	)
+	alertAckExpiredCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindUnacknowledged, "", detail)
+	return nil
+}
+
+// unacknowledge removes the status and assignee enrichments in Keep and
+// restores the firing post.
+func (uc *ExpireAcksUseCase) unacknowledge(ctx context.Context, p *post.Post, keepAlert *port.KeepAlert) error {
+	fingerprint := p.Fingerprint()
+	if err := uc.keepClient.UnenrichAlert(ctx, fingerprint.Value(), []string{EnrichmentKeyStatus, EnrichmentKeyAssignee}); err != nil {
+		return fmt.Errorf("unenrich alert in keep: %w", err)
+	}
+
+	severity, err := alert.NewSeverity(keepAlert.Severity)
+	if err != nil {
+		severity = p.Severity()
+	}
+	a := alert.RestoreAlert(
+		fingerprint,
+		keepAlert.Name,
+		severity,
+		alert.RestoreStatus(alert.StatusFiring),
+		keepAlert.Description,
+		strings.Join(keepAlert.Source, ", "),
+		keepAlert.Labels,
+		p.FiringStartTime(),
+	).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL).WithFireCount(p.FireCount())
+
+	attachment := uc.msgBuilder.ForChannel(p.ChannelID()).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	if err := uc.mmClient.UpdatePost(ctx, p.PostID(), attachment); err != nil {
+		return fmt.Errorf("update post: %w", err)
+	}
+
+	p.ShowStatus(alert.StatusFiring)
+	p.SetLastKnownAssignee("")
+	return nil
+}
+
+// withAckDeadline adds the end of a limited acknowledgement to the footer of
+// the acknowledged post.
+func withAckDeadline(a post.Attachment, username string, until time.Time) post.Attachment {
+	a.Footer = fmt.Sprintf("Acknowledged by @%s until %s", username, until.UTC().Format("2006-01-02 15:04 UTC"))
+	return a
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type expireAcksFixture struct {
+	uc         *ExpireAcksUseCase
+	postRepo   *postStore
+	keepClient *portmock.KeepClientMock
+	mmClient   *portmock.MattermostClientMock
+	msgBuilder *portmock.MessageBuilderMock
+	clock      *clock.Fake
+}
+
+func setupExpireAcksUseCase(keepAlert *port.KeepAlert) *expireAcksFixture {
+	f := &expireAcksFixture{
+		postRepo: newPostStore(),
+		keepClient: &portmock.KeepClientMock{
+			GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+				return keepAlert, nil
+			},
+			UnenrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments []string) error {
+				return nil
+			},
+		},
+		mmClient: &portmock.MattermostClientMock{
+			UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+				return nil
+			},
+			ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+				return nil
+			},
+		},
+		msgBuilder: &portmock.MessageBuilderMock{
+			BuildFiringAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
+				return post.Attachment{Title: "FIRING: " + a.Name()}
+			},
+		},
+		clock: clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	f.msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return f.msgBuilder }
+	f.uc = NewExpireAcksUseCase(
+		f.postRepo,
+		f.keepClient,
+		f.mmClient,
+		f.msgBuilder,
+		"https://keep.example.com",
+		"https://callback.example.com",
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	f.uc.SetClock(f.clock)
+	return f
+}
+
+func (f *expireAcksFixture) addPost(fp string, ackUntil time.Time) *post.Post {
+	p := post.NewPost("post-"+fp, "channel-1", alert.RestoreFingerprint(fp), "Disk full", alert.RestoreSeverity("high"), f.clock.Now().Add(-time.Hour), time.Now())
+	p.ShowStatus(alert.StatusAcknowledged)
+	p.SetLastKnownAssignee("alice")
+	if !ackUntil.IsZero() {
+		p.AcknowledgeUntil(ackUntil, "alice")
+	}
+	f.postRepo.posts[fp] = p
+	return p
+}
+
+func acknowledgedKeepAlert() *port.KeepAlert {
+	keepAlert := firingKeepAlert()
+	keepAlert.Enrichments = map[string]string{"status": "acknowledged", "assignee": "alice@keep"}
+	return keepAlert
+}
+
+func TestExpireAcks_UnacknowledgesExpired(t *testing.T) {
+	f := setupExpireAcksUseCase(acknowledgedKeepAlert())
+	now := f.clock.Now()
+	f.addPost("fp-1", now.Add(-time.Second))
+	f.addPost("fp-active", now.Add(time.Hour))
+	f.addPost("fp-untimed", time.Time{})
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	unenriched := f.keepClient.UnenrichAlertCalls()
+	require.Len(t, unenriched, 1)
+	assert.Equal(t, "fp-1", unenriched[0].Fingerprint)
+	assert.Equal(t, []string{"status", "assignee"}, unenriched[0].Enrichments)
+
+	updates := f.mmClient.UpdatePostCalls()
+	require.Len(t, updates, 1)
+	assert.Equal(t, "post-fp-1", updates[0].PostID)
+	assert.Equal(t, "FIRING: Disk full", updates[0].Attachment.Title)
+
+	expired := f.postRepo.posts["fp-1"]
+	assert.True(t, expired.AckUntil().IsZero())
+	assert.Equal(t, alert.StatusFiring, expired.ShownStatus())
+	assert.Empty(t, expired.LastKnownAssignee())
+	assert.Equal(t, now.Add(time.Hour), f.postRepo.posts["fp-active"].AckUntil())
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "post-fp-1", replies[0].RootID)
+	assert.Contains(t, replies[0].Message, "@alice, your acknowledgement expired")
+}
+
+func TestExpireAcks_AlreadyUnacknowledgedInKeep(t *testing.T) {
+	f := setupExpireAcksUseCase(firingKeepAlert())
+	f.addPost("fp-1", f.clock.Now())
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	assert.Empty(t, f.keepClient.UnenrichAlertCalls())
+	assert.Empty(t, f.mmClient.UpdatePostCalls())
+	assert.Empty(t, f.mmClient.ReplyToThreadCalls())
+	assert.True(t, f.postRepo.posts["fp-1"].AckUntil().IsZero(), "the deadline is dropped")
+}
+
+func TestExpireAcks_KeepErrorKeepsDeadline(t *testing.T) {
+	f := setupExpireAcksUseCase(acknowledgedKeepAlert())
+	f.keepClient.UnenrichAlertFunc = func(ctx context.Context, fingerprint string, enrichments []string) error {
+		return errors.New("keep down")
+	}
+	until := f.clock.Now().Add(-time.Minute)
+	f.addPost("fp-1", until)
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expire ack fp-1")
+	assert.Contains(t, err.Error(), "keep down")
+
+	assert.Empty(t, f.mmClient.UpdatePostCalls())
+	assert.Empty(t, f.postRepo.SaveCalls())
+	assert.Equal(t, until, f.postRepo.posts["fp-1"].AckUntil(), "retried on the next run")
+}
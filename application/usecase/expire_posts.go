@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	// expiryBatchSize caps how many expired posts one run marks.
+	expiryBatchSize = 100
+	// expiryGiveUpAfter is how long marking an expired post is retried, e.g.
+	// when the post was deleted by hand.
+	expiryGiveUpAfter = 24 * time.Hour
+)
+
+// ExpirePostsUseCase marks Mattermost posts whose stored entry expired. The
+// bridge no longer follows such an alert, so its buttons would act on nothing;
+// the post is turned into an expired card instead.
+type ExpirePostsUseCase struct {
+	store      post.ExpiryStore
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	keepUIURL  string
+	clock      clock.Clock
+	logger     *slog.Logger
+}
+
+func NewExpirePostsUseCase(
+	store post.ExpiryStore,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	keepUIURL string,
+	logger *slog.Logger,
+) *ExpirePostsUseCase {
+	return &ExpirePostsUseCase{
+		store:      store,
+		mmClient:   mmClient,
+		msgBuilder: msgBuilder,
+		keepUIURL:  keepUIURL,
+		clock:      clock.Real(),
+		logger:     logger,
+	}
+}
+
+// SetClock replaces the clock that decides which posts expired.
+func (uc *ExpirePostsUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Execute marks every expired post. Posts that fail are retried on the next
+// run until expiryGiveUpAfter has passed.
+func (uc *ExpirePostsUseCase) Execute(ctx context.Context) error {
+	now := uc.clock.Now()
+	expired, err := uc.store.FindExpired(ctx, now, expiryBatchSize)
+	if err != nil {
+		return fmt.Errorf("find expired posts: %w", err)
+	}
+
+	var errs []error
+	for _, e := range expired {
+		p := e.Post
+		if err := uc.mark(ctx, p); err != nil {
+			postExpiryErrorsCounter.Inc()
+			if now.Sub(e.ExpiredAt) < expiryGiveUpAfter {
+				errs = append(errs, fmt.Errorf("mark post %s expired: %w", p.PostID(), err))
+				continue
+			}
+			uc.logger.Warn("Giving up on marking expired post",
+				slog.String("post_id", p.PostID()),
+				slog.String("fingerprint", p.Fingerprint().Value()),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			uc.logger.Info("Expired post marked",
+				logger.ApplicationFields("post_expired",
+					slog.String("post_id", p.PostID()),
+					slog.String("fingerprint", p.Fingerprint().Value()),
+					slog.String("severity", p.Severity().String()),
+				),
+			)
+			postsExpiredCounter(p.Severity().String()).Inc()
+		}
+		if err := uc.store.ForgetExpired(ctx, p.Fingerprint()); err != nil {
+			errs = append(errs, fmt.Errorf("forget expired post %s: %w", p.PostID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *ExpirePostsUseCase) mark(ctx context.Context, p *post.Post) error {
+	a := alert.RestoreAlert(
+		p.Fingerprint(), p.AlertName(), p.Severity(), alert.RestoreStatus(alert.StatusFiring),
+		"", "", p.Labels(),
+		p.FiringStartTime(),
+	)
+	return uc.mmClient.UpdatePost(ctx, p.PostID(), uc.msgBuilder.ForChannel(p.ChannelID()).BuildExpiredAttachment(a, uc.keepUIURL))
+}
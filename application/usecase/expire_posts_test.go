@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func TestExpirePostsUseCase(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fingerprint := alert.RestoreFingerprint("fp-1")
+	expired := []post.Expired{{
+		Post:      post.NewPost("post-1", "channel-1", fingerprint, "Disk full", alert.RestoreSeverity(alert.SeverityInfo), now.Add(-25*time.Hour), time.Now()),
+		ExpiredAt: now.Add(-time.Minute),
+	}}
+	store := &portmock.PostExpiryStoreMock{
+		FindExpiredFunc: func(ctx context.Context, at time.Time, limit int) ([]post.Expired, error) {
+			return expired, nil
+		},
+		ForgetExpiredFunc: func(ctx context.Context, fingerprint alert.Fingerprint) error {
+			expired = nil
+			return nil
+		},
+	}
+	updateErr := errors.New("mattermost update post: status 502")
+	var failUpdate bool
+	mmClient := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			if failUpdate {
+				return updateErr
+			}
+			return nil
+		},
+	}
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildExpiredAttachmentFunc: func(a *alert.Alert, keepUIURL string) post.Attachment {
+			return post.Attachment{Title: "EXPIRED: " + a.Name()}
+		},
+	}
+	msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return msgBuilder }
+	fake := clock.NewFake(now)
+	uc := NewExpirePostsUseCase(store, mmClient, msgBuilder, "https://keep.example.com", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	uc.SetClock(fake)
+	ctx := context.Background()
+
+	failUpdate = true
+	assert.ErrorIs(t, uc.Execute(ctx), updateErr)
+	assert.Empty(t, store.ForgetExpiredCalls(), "failed posts are retried")
+	assert.Equal(t, now, store.FindExpiredCalls()[0].Now)
+
+	failUpdate = false
+	require.NoError(t, uc.Execute(ctx))
+	require.Len(t, mmClient.UpdatePostCalls(), 2)
+	assert.Equal(t, "post-1", mmClient.UpdatePostCalls()[1].PostID)
+	assert.Equal(t, "EXPIRED: Disk full", mmClient.UpdatePostCalls()[1].Attachment.Title)
+	require.Len(t, store.ForgetExpiredCalls(), 1)
+	assert.Equal(t, "fp-1", store.ForgetExpiredCalls()[0].Fingerprint.Value())
+}
+
+func TestExpirePostsUseCaseGivesUp(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fingerprint := alert.RestoreFingerprint("fp-1")
+	store := &portmock.PostExpiryStoreMock{
+		FindExpiredFunc: func(ctx context.Context, at time.Time, limit int) ([]post.Expired, error) {
+			return []post.Expired{{
+				Post:      post.NewPost("post-1", "channel-1", fingerprint, "Disk full", alert.RestoreSeverity(alert.SeverityInfo), now, time.Now()),
+				ExpiredAt: now.Add(-expiryGiveUpAfter),
+			}}, nil
+		},
+		ForgetExpiredFunc: func(ctx context.Context, fingerprint alert.Fingerprint) error { return nil },
+	}
+	mmClient := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return errors.New("mattermost update post: status 404")
+		},
+	}
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildExpiredAttachmentFunc: func(a *alert.Alert, keepUIURL string) post.Attachment { return post.Attachment{} },
+	}
+	msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return msgBuilder }
+	uc := NewExpirePostsUseCase(store, mmClient, msgBuilder, "https://keep.example.com", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	uc.SetClock(clock.NewFake(now))
+
+	require.NoError(t, uc.Execute(context.Background()))
+	assert.Len(t, store.ForgetExpiredCalls(), 1)
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// FingerprintLocks lets several bridge replicas share the load: work that
+// creates or updates an alert's post runs under a lock on the alert's
+// fingerprint, so two replicas cannot both create a post for a new alert or
+// overwrite each other's update.
+type FingerprintLocks struct {
+	locker         port.Locker
+	ttl            time.Duration
+	idempotency    port.IdempotencyStore
+	idempotencyTTL time.Duration
+	logger         *slog.Logger
+}
+
+// NewFingerprintLocks returns locks that expire after ttl, which should
+// exceed the time a single alert takes to handle.
+func NewFingerprintLocks(locker port.Locker, ttl time.Duration, logger *slog.Logger) *FingerprintLocks {
+	return &FingerprintLocks{
+		locker: locker,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// SetIdempotency skips alert webhooks that were already handled within ttl,
+// such as a webhook Keep delivered to two replicas or retried after a
+// timeout. A nil store, the default, handles every delivery.
+func (l *FingerprintLocks) SetIdempotency(store port.IdempotencyStore, ttl time.Duration) {
+	l.idempotency = store
+	l.idempotencyTTL = ttl
+}
+
+// Do runs fn while holding the lock on fingerprint.
+func (l *FingerprintLocks) Do(ctx context.Context, fingerprint string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	release, err := l.locker.Lock(ctx, "alert:"+fingerprint, l.ttl)
+	if err != nil {
+		fingerprintLockErrorsCounter.Inc()
+		return fmt.Errorf("lock alert: %w", err)
+	}
+	defer release()
+	fingerprintLockWaitSeconds.UpdateDuration(start)
+
+	return fn(ctx)
+}
+
+// WrapAlerts returns an alert use case that handles each alert under its
+// fingerprint lock and skips deliveries already handled.
+func (l *FingerprintLocks) WrapAlerts(alerts port.AlertUseCase) port.AlertUseCase {
+	return &lockedAlerts{alerts: alerts, locks: l}
+}
+
+type lockedAlerts struct {
+	alerts port.AlertUseCase
+	locks  *FingerprintLocks
+}
+
+func (a *lockedAlerts) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	return a.locks.Do(ctx, input.Fingerprint, func(ctx context.Context) error {
+		store := a.locks.idempotency
+		if store == nil {
+			return a.alerts.Execute(ctx, input)
+		}
+
+		key := input.IdempotencyKey()
+		seen, err := store.Seen(ctx, key)
+		if err != nil {
+			// Handling the alert twice is better than not at all.
+			a.locks.logger.Warn("Failed to check idempotency key",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.String("error", err.Error()),
+			)
+		}
+		if seen {
+			duplicateAlertsCounter.Inc()
+			a.locks.logger.Info("Skipping alert delivery already handled",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.String("status", input.Status),
+			)
+			return nil
+		}
+
+		if err := a.alerts.Execute(ctx, input); err != nil {
+			return err
+		}
+
+		if err := store.Mark(ctx, key, a.locks.idempotencyTTL); err != nil {
+			a.locks.logger.Warn("Failed to record idempotency key",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.String("error", err.Error()),
+			)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+)
+
+// memoryIdempotencyStore keeps idempotency keys in memory, ignoring the TTL.
+type memoryIdempotencyStore struct {
+	keys map[string]bool
+}
+
+func (m *memoryIdempotencyStore) Seen(ctx context.Context, key string) (bool, error) {
+	return m.keys[key], nil
+}
+
+func (m *memoryIdempotencyStore) Mark(ctx context.Context, key string, ttl time.Duration) error {
+	m.keys[key] = true
+	return nil
+}
+
+// setupFingerprintLocks returns locks whose locker records whether a lock is
+// held, so tests can check that work ran under it.
+func setupFingerprintLocks() (*FingerprintLocks, *portmock.LockerMock, *bool) {
+	held := new(bool)
+	locker := &portmock.LockerMock{
+		LockFunc: func(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+			*held = true
+			return func() { *held = false }, nil
+		},
+	}
+	locks := NewFingerprintLocks(locker, 45*time.Second, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	return locks, locker, held
+}
+
+func TestFingerprintLocksWrapAlertsLocksFingerprint(t *testing.T) {
+	locks, locker, held := setupFingerprintLocks()
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			assert.True(t, *held, "the alert is handled under the lock")
+			return nil
+		},
+	}
+
+	require.NoError(t, locks.WrapAlerts(alerts).Execute(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}))
+
+	require.Len(t, locker.LockCalls(), 1)
+	assert.Equal(t, "alert:fp-1", locker.LockCalls()[0].Key)
+	assert.Equal(t, 45*time.Second, locker.LockCalls()[0].TTL)
+	assert.False(t, *held, "the lock is released")
+	assert.Len(t, alerts.ExecuteCalls(), 1)
+}
+
+func TestFingerprintLocksLockFailure(t *testing.T) {
+	locks, locker, _ := setupFingerprintLocks()
+	locker.LockFunc = func(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+		return nil, context.DeadlineExceeded
+	}
+	alerts := &portmock.AlertUseCaseMock{}
+
+	err := locks.WrapAlerts(alerts).Execute(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, alerts.ExecuteCalls())
+}
+
+func TestFingerprintLocksSkipDuplicateDeliveries(t *testing.T) {
+	locks, _, _ := setupFingerprintLocks()
+	store := &memoryIdempotencyStore{keys: map[string]bool{}}
+	locks.SetIdempotency(store, 10*time.Minute)
+
+	fail := true
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			if fail {
+				return errors.New("mattermost create post: status 502")
+			}
+			return nil
+		},
+	}
+	wrapped := locks.WrapAlerts(alerts)
+	input := dto.KeepAlertInput{Fingerprint: "fp-1", Status: "firing", LastReceived: "2024-01-15T10:35:00Z"}
+
+	require.Error(t, wrapped.Execute(context.Background(), input))
+	assert.Empty(t, store.keys, "failed deliveries are not recorded")
+
+	fail = false
+	require.NoError(t, wrapped.Execute(context.Background(), input))
+	require.NoError(t, wrapped.Execute(context.Background(), input))
+	assert.Len(t, alerts.ExecuteCalls(), 2, "the redelivered webhook is skipped")
+
+	input.LastReceived = "2024-01-15T10:40:00Z"
+	require.NoError(t, wrapped.Execute(context.Background(), input))
+	assert.Len(t, alerts.ExecuteCalls(), 3, "a new event is handled")
+}
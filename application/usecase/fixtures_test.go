@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// postStore backs the generated repository mock with a map, so tests can
+// seed posts and inspect what the use case stored.
+type postStore struct {
+	*portmock.RepositoryMock
+	posts map[string]*post.Post
+}
+
+func newPostStore() *postStore {
+	s := &postStore{posts: make(map[string]*post.Post)}
+	s.RepositoryMock = &portmock.RepositoryMock{
+		SaveFunc: func(_ context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
+			s.posts[fingerprint.Value()] = p
+			return nil
+		},
+		FindByFingerprintFunc: func(_ context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
+			p, ok := s.posts[fingerprint.Value()]
+			if !ok {
+				return nil, post.ErrNotFound
+			}
+			return p, nil
+		},
+		DeleteFunc: func(_ context.Context, fingerprint alert.Fingerprint) error {
+			delete(s.posts, fingerprint.Value())
+			return nil
+		},
+		FindAllActiveFunc: func(context.Context) ([]*post.Post, error) {
+			result := make([]*post.Post, 0, len(s.posts))
+			for _, p := range s.posts {
+				result = append(result, p)
+			}
+			return result, nil
+		},
+	}
+	return s
+}
+
+// userMap backs the generated user mapper mock with a Mattermost → Keep
+// username map.
+type userMap struct {
+	*portmock.UserMapperMock
+	mapping map[string]string
+}
+
+func newUserMap() *userMap {
+	m := &userMap{mapping: make(map[string]string)}
+	m.UserMapperMock = &portmock.UserMapperMock{
+		GetKeepUsernameFunc: func(mattermostUsername string) (string, bool) {
+			keepUser, ok := m.mapping[mattermostUsername]
+			return keepUser, ok
+		},
+		GetMattermostUsernameFunc: func(keepUsername string) (string, bool) {
+			for mmUser, keepUser := range m.mapping {
+				if keepUser == keepUsername {
+					return mmUser, true
+				}
+			}
+			return "", false
+		},
+	}
+	return m
+}
+
+func newMattermostClientMock() *portmock.MattermostClientMock {
+	return &portmock.MattermostClientMock{
+		CreatePostFunc: func(context.Context, string, post.Attachment) (string, error) {
+			return "post-123", nil
+		},
+		UpdatePostFunc: func(context.Context, string, post.Attachment) error {
+			return nil
+		},
+		DeletePostFunc: func(context.Context, string) error {
+			return nil
+		},
+		GetUserFunc: func(context.Context, string) (string, error) {
+			return "testuser", nil
+		},
+		ReplyToThreadFunc: func(context.Context, string, string, string) error {
+			return nil
+		},
+	}
+}
+
+// replyMessages returns the messages posted into threads, in order.
+func replyMessages(mm *portmock.MattermostClientMock) []string {
+	messages := make([]string, 0)
+	for _, call := range mm.ReplyToThreadCalls() {
+		messages = append(messages, call.Message)
+	}
+	return messages
+}
+
+// lastCall returns the most recent recorded call, or the zero value when
+// there was none.
+func lastCall[T any](calls []T) T {
+	var last T
+	if len(calls) > 0 {
+		last = calls[len(calls)-1]
+	}
+	return last
+}
+
+func testKeepAlert() *port.KeepAlert {
+	return &port.KeepAlert{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Status:      "firing",
+		Severity:    "high",
+	}
+}
+
+func newKeepClientMock() *portmock.KeepClientMock {
+	return &portmock.KeepClientMock{
+		EnrichAlertFunc: func(context.Context, string, map[string]string, port.EnrichOptions) error {
+			return nil
+		},
+		UnenrichAlertFunc: func(context.Context, string, []string) error {
+			return nil
+		},
+		GetAlertFunc: keepAlertSequence(testKeepAlert()),
+		GetAlertsFunc: func(context.Context, int, []string) ([]port.KeepAlert, error) {
+			return nil, nil
+		},
+		GetProvidersFunc: func(context.Context) ([]port.KeepProvider, error) {
+			return nil, nil
+		},
+		CreateWebhookProviderFunc: func(context.Context, port.WebhookProviderConfig) error {
+			return nil
+		},
+		GetWorkflowsFunc: func(context.Context) ([]port.KeepWorkflow, error) {
+			return nil, nil
+		},
+		CreateWorkflowFunc: func(context.Context, port.WorkflowConfig) error {
+			return nil
+		},
+	}
+}
+
+// keepAlertSequence answers successive GetAlert calls with the given alerts
+// and keeps returning the last one once they run out.
+func keepAlertSequence(alerts ...*port.KeepAlert) func(context.Context, string) (*port.KeepAlert, error) {
+	calls := 0
+	return func(context.Context, string) (*port.KeepAlert, error) {
+		idx := min(calls, len(alerts)-1)
+		calls++
+		return alerts[idx], nil
+	}
+}
+
+func keepAlertError(err error) func(context.Context, string) (*port.KeepAlert, error) {
+	return func(context.Context, string) (*port.KeepAlert, error) {
+		return nil, err
+	}
+}
+
+// enrichedWith merges the enrichments of every EnrichAlert call.
+func enrichedWith(keep *portmock.KeepClientMock) map[string]string {
+	merged := make(map[string]string)
+	for _, call := range keep.EnrichAlertCalls() {
+		for k, v := range call.Enrichments {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func newChannelResolverMock(channelIDs ...string) *portmock.ChannelResolverMock {
+	if len(channelIDs) == 0 {
+		channelIDs = []string{"channel-456"}
+	}
+	return &portmock.ChannelResolverMock{
+		ChannelIDsForAlertFunc: func(string, map[string]string) []string {
+			return channelIDs
+		},
+	}
+}
+
+// newMessageBuilderMock returns a builder whose attachments are titled after
+// the state they render, e.g. "FIRING: <alert name>".
+func newMessageBuilderMock() *portmock.MessageBuilderMock {
+	b := &portmock.MessageBuilderMock{
+		BuildMentionMessageFunc: func(*alert.Alert, string) string {
+			return ""
+		},
+		BuildFiringAttachmentFunc: func(a *alert.Alert, _, _ string) post.Attachment {
+			return post.Attachment{Color: "#FF0000", Title: "FIRING: " + a.Name()}
+		},
+		BuildFlappingAttachmentFunc: func(a *alert.Alert, _, _ string, changes int, _ time.Duration) post.Attachment {
+			return post.Attachment{
+				Color:  "#D35400",
+				Title:  "FLAPPING: " + a.Name(),
+				Footer: fmt.Sprintf("Flapping: %d state changes", changes),
+			}
+		},
+		BuildAssignedAttachmentFunc: func(a *alert.Alert, _, _, assignee string) post.Attachment {
+			return post.Attachment{Color: "#FF0000", Title: "FIRING: " + a.Name(), Footer: "Assigned to @" + assignee}
+		},
+		BuildAcknowledgedAttachmentFunc: func(a *alert.Alert, _, _, _ string) post.Attachment {
+			return post.Attachment{Color: "#FFA500", Title: "ACKNOWLEDGED: " + a.Name()}
+		},
+		BuildSnoozedAttachmentFunc: func(a *alert.Alert, _, _, _ string, _ time.Time) post.Attachment {
+			return post.Attachment{Color: "#B0A0D0", Title: "SNOOZED: " + a.Name()}
+		},
+		BuildResolvedAttachmentFunc: func(a *alert.Alert, _, _ string) post.Attachment {
+			return post.Attachment{Color: "#00CC00", Title: "RESOLVED: " + a.Name()}
+		},
+		BuildSuppressedAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#9370DB", Title: "SUPPRESSED: " + a.Name()}
+		},
+		BuildPendingAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#87CEEB", Title: "PENDING: " + a.Name()}
+		},
+		BuildMaintenanceAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#708090", Title: "MAINTENANCE: " + a.Name()}
+		},
+		BuildDismissedAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#A9A9A9", Title: "DISMISSED: " + a.Name()}
+		},
+		BuildMergedAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#6A5ACD", Title: "MERGED: " + a.Name()}
+		},
+		BuildExpiredAttachmentFunc: func(a *alert.Alert, _ string) post.Attachment {
+			return post.Attachment{Color: "#C0C0C0", Title: "EXPIRED: " + a.Name()}
+		},
+		BuildProcessingAttachmentFunc: func(string, string) (post.Attachment, error) {
+			return post.Attachment{Color: "#808080", Title: "Processing Alert"}, nil
+		},
+		BuildCollapsedAttachmentFunc: func(alertName, _, _ string, _ time.Time) post.Attachment {
+			return post.Attachment{Text: "COLLAPSED: " + alertName}
+		},
+		BuildErrorAttachmentFunc: func(alertName, _, _, errorMsg string) post.Attachment {
+			return post.Attachment{Color: "#FF0000", Title: alertName, Text: "Error: " + errorMsg}
+		},
+		BuildGroupRootAttachmentFunc: func(g *group.Group, _ string) post.Attachment {
+			return post.Attachment{Title: g.Key() + "=" + g.Value()}
+		},
+		BuildOverflowAttachmentFunc: func(held []port.HeldAlert, _ string) post.Attachment {
+			return post.Attachment{Title: fmt.Sprintf("OVERFLOW: %d", len(held))}
+		},
+		BuildQuietHoursDigestAttachmentFunc: func(held []port.DigestAlert, _ string) post.Attachment {
+			return post.Attachment{Title: fmt.Sprintf("DIGEST: %d", len(held))}
+		},
+		BuildAlertDigestAttachmentFunc: func(d port.AlertDigest, _ string) post.Attachment {
+			return post.Attachment{Title: fmt.Sprintf("ALERT DIGEST: %d", d.Fired)}
+		},
+		BuildSLOReportAttachmentFunc: func([]port.SLOResult, time.Time, time.Time) post.Attachment {
+			return post.Attachment{}
+		},
+		BuildStatusBoardAttachmentFunc: func([]port.BoardAlert, string) post.Attachment {
+			return post.Attachment{}
+		},
+	}
+	b.ForChannelFunc = func(string) port.MessageBuilder {
+		return b
+	}
+	return b
+}
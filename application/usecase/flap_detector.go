@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// FlapDetector counts how often each alert switches between firing and
+// resolved. An alert that switches threshold times within the window is
+// flapping: its post stops following every switch until the window holds
+// fewer switches again.
+//
+// State is kept in memory, per replica. After a restart alerts start with a
+// clean history.
+type FlapDetector struct {
+	window    time.Duration
+	threshold int
+	clock     clock.Clock
+
+	mu     sync.Mutex
+	alerts map[string]*flapHistory // fingerprint -> history
+}
+
+type flapHistory struct {
+	firing   bool
+	changes  []time.Time // switches within the window
+	flapping bool
+	last     *alert.Alert // latest alert received while flapping
+}
+
+// flapVerdict tells the alert handler what to do with a firing or resolved
+// alert.
+type flapVerdict int
+
+const (
+	flapSteady  flapVerdict = iota // handle as usual
+	flapStarted                    // the alert just started flapping
+	flapOngoing                    // the alert is flapping, skip the update
+)
+
+// NewFlapDetector creates a detector that marks an alert as flapping once it
+// switched threshold times within window.
+func NewFlapDetector(window time.Duration, threshold int) *FlapDetector {
+	return &FlapDetector{
+		window:    window,
+		threshold: threshold,
+		clock:     clock.Real(),
+		alerts:    make(map[string]*flapHistory),
+	}
+}
+
+// SetClock replaces the clock that drives the window.
+func (d *FlapDetector) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+// Window returns the window switches are counted in.
+func (d *FlapDetector) Window() time.Duration {
+	return d.window
+}
+
+// Observe records a firing or resolved alert and returns what to do with it,
+// along with the number of switches within the window.
+func (d *FlapDetector) Observe(a *alert.Alert) (flapVerdict, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	firing := a.Status().IsFiring()
+	h, ok := d.alerts[a.Fingerprint().Value()]
+	if !ok {
+		d.alerts[a.Fingerprint().Value()] = &flapHistory{firing: firing}
+		return flapSteady, 0
+	}
+
+	h.prune(now.Add(-d.window))
+	if h.firing != firing {
+		h.firing = firing
+		h.changes = append(h.changes, now)
+		alertFlapChangesCounter.Inc()
+	}
+
+	switch {
+	case h.flapping:
+		h.last = a
+		return flapOngoing, len(h.changes)
+	case len(h.changes) >= d.threshold:
+		h.flapping = true
+		h.last = a
+		d.updateGauge()
+		return flapStarted, len(h.changes)
+	default:
+		return flapSteady, len(h.changes)
+	}
+}
+
+// Settle ends flapping for alerts whose window holds fewer than threshold
+// switches and returns the alert each of them last received. Histories
+// without switches in the window are dropped.
+func (d *FlapDetector) Settle() []*alert.Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := d.clock.Now().Add(-d.window)
+	var settled []*alert.Alert
+	for fp, h := range d.alerts {
+		h.prune(cutoff)
+		if h.flapping && len(h.changes) < d.threshold {
+			h.flapping = false
+			settled = append(settled, h.last)
+			h.last = nil
+		}
+		if !h.flapping && len(h.changes) == 0 {
+			delete(d.alerts, fp)
+		}
+	}
+	d.updateGauge()
+	return settled
+}
+
+func (d *FlapDetector) updateGauge() {
+	var flapping int
+	for _, h := range d.alerts {
+		if h.flapping {
+			flapping++
+		}
+	}
+	alertsFlappingGauge.Set(float64(flapping))
+}
+
+// prune drops switches before cutoff.
+func (h *flapHistory) prune(cutoff time.Time) {
+	i := 0
+	for i < len(h.changes) && h.changes[i].Before(cutoff) {
+		i++
+	}
+	h.changes = h.changes[i:]
+}
+
+// inhibitFlapping observes a firing or resolved alert and reports whether it
+// was handled as a flapping alert.
+func (uc *HandleAlertUseCase) inhibitFlapping(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, existingPost *post.Post) (bool, error) {
+	if uc.flapping == nil {
+		return false, nil
+	}
+
+	verdict, changes := uc.flapping.Observe(a)
+	switch verdict {
+	case flapOngoing:
+		uc.logger.Info("Update skipped for flapping alert",
+			logger.ApplicationFields("alert_flapping_inhibited",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("status", a.Status().String()),
+			),
+		)
+		alertsFlappingInhibitedCounter.Inc()
+		return true, nil
+	case flapStarted:
+		return true, uc.startFlapping(ctx, a, fingerprint, existingPost, changes)
+	default:
+		return false, nil
+	}
+}
+
+// startFlapping shows the alert as flapping and says so once in the thread.
+// The post is kept while the alert flaps, so a firing alert without a post
+// gets one.
+func (uc *HandleAlertUseCase) startFlapping(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, existingPost *post.Post, changes int) error {
+	window := uc.flapping.Window()
+	if existingPost == nil {
+		if !a.Status().IsFiring() {
+			return nil
+		}
+		channelID, _ := uc.routeAlert(a)
+		attachment := uc.msgBuilder.ForChannel(channelID).BuildFlappingAttachment(a, uc.callbackURL, uc.keepUIURL, changes, window)
+		postID, err := uc.createPost(ctx, a, channelID, attachment)
+		if err != nil {
+			return fmt.Errorf("create flapping post: %w", err)
+		}
+		existingPost = post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+		existingPost.SetLabels(a.Labels())
+		alertsPostedCounter(a.Severity().String(), channelID).Inc()
+	} else {
+		alertWithStoredTime := alert.RestoreAlert(
+			fingerprint, a.Name(), a.Severity(), a.Status(),
+			a.Description(), a.Source(), a.Labels(),
+			existingPost.FiringStartTime(),
+		).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+		attachment := uc.msgBuilder.ForChannel(existingPost.ChannelID()).BuildFlappingAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL, changes, window)
+		if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
+			return fmt.Errorf("update post to flapping: %w", err)
+		}
+		existingPost.Touch(uc.clock.Now())
+	}
+	if err := uc.postRepo.Save(ctx, fingerprint, existingPost); err != nil {
+		return fmt.Errorf("save post to store: %w", err)
+	}
+
+	msg := fmt.Sprintf("🔁 Alert is flapping: %d state changes in %s. Updates are paused until it settles.", changes, formatEscalationAge(window))
+	if err := uc.mmClient.ReplyToThread(ctx, existingPost.ChannelID(), existingPost.PostID(), msg); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", existingPost.PostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	uc.logger.Info("Alert is flapping",
+		logger.ApplicationFields("alert_flapping_started",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("post_id", existingPost.PostID()),
+			slog.Int("changes", changes),
+		),
+	)
+	alertsFlappingStartedCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindFlapping, "", fmt.Sprintf("%d state changes in %s", changes, formatEscalationAge(window)))
+	return nil
+}
+
+// SettleFlapping resumes updates for alerts that stopped flapping. Each is
+// handled again as last received, so its post shows its current state.
+func (uc *HandleAlertUseCase) SettleFlapping(ctx context.Context) error {
+	if uc.flapping == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, a := range uc.flapping.Settle() {
+		fingerprint := a.Fingerprint()
+		existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+		if err != nil && !errors.Is(err, post.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("find post for %s: %w", fingerprint.Value(), err))
+			continue
+		}
+		if existingPost != nil {
+			msg := "🔁 Alert stopped flapping. Updates resumed."
+			if err := uc.mmClient.ReplyToThread(ctx, existingPost.ChannelID(), existingPost.PostID(), msg); err != nil {
+				uc.logger.Warn("Failed to reply to thread",
+					slog.String("post_id", existingPost.PostID()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+
+		uc.logger.Info("Alert stopped flapping",
+			logger.ApplicationFields("alert_flapping_stopped",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("status", a.Status().String()),
+			),
+		)
+		alertsFlappingStoppedCounter.Inc()
+		uc.audit.Record(ctx, fingerprint, audit.KindFlapping, "", "stopped")
+
+		if a.Status().IsFiring() {
+			err = uc.handleFiring(ctx, a, fingerprint)
+		} else {
+			err = uc.handleResolved(ctx, a, fingerprint)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("update %s: %w", fingerprint.Value(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func flapTestAlert(t *testing.T, status string) *alert.Alert {
+	t.Helper()
+	s, err := alert.NewStatus(status)
+	require.NoError(t, err)
+	a, err := alert.NewAlert(alert.RestoreFingerprint("fp-flap"), "Flappy", alert.RestoreSeverity("high"), s, "", "", nil, time.Time{})
+	require.NoError(t, err)
+	return a
+}
+
+func TestFlapDetector(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC))
+	d := NewFlapDetector(10*time.Minute, 3)
+	d.SetClock(fake)
+
+	verdict, changes := d.Observe(flapTestAlert(t, "firing"))
+	assert.Equal(t, flapSteady, verdict)
+	assert.Zero(t, changes)
+
+	verdict, _ = d.Observe(flapTestAlert(t, "firing"))
+	assert.Equal(t, flapSteady, verdict, "repeated firing is no state change")
+
+	fake.Advance(time.Minute)
+	d.Observe(flapTestAlert(t, "resolved"))
+	fake.Advance(time.Minute)
+	verdict, changes = d.Observe(flapTestAlert(t, "firing"))
+	assert.Equal(t, flapSteady, verdict)
+	assert.Equal(t, 2, changes)
+
+	fake.Advance(time.Minute)
+	verdict, changes = d.Observe(flapTestAlert(t, "resolved"))
+	assert.Equal(t, flapStarted, verdict)
+	assert.Equal(t, 3, changes)
+	assert.Equal(t, float64(1), alertsFlappingGauge.Get())
+
+	fake.Advance(time.Minute)
+	verdict, _ = d.Observe(flapTestAlert(t, "firing"))
+	assert.Equal(t, flapOngoing, verdict)
+
+	assert.Empty(t, d.Settle(), "still flapping within the window")
+
+	fake.Advance(9 * time.Minute)
+	settled := d.Settle()
+	require.Len(t, settled, 1)
+	assert.True(t, settled[0].Status().IsFiring(), "the last alert received is returned")
+	assert.Equal(t, float64(0), alertsFlappingGauge.Get())
+
+	fake.Advance(10 * time.Minute)
+	assert.Empty(t, d.Settle())
+	assert.Empty(t, d.alerts, "idle histories are dropped")
+}
+
+func TestHandleAlertUseCase_FlappingAlert(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC))
+	detector := NewFlapDetector(10*time.Minute, 3)
+	detector.SetClock(fake)
+	uc.SetFlapDetector(detector)
+	ctx := context.Background()
+
+	send := func(status string) {
+		t.Helper()
+		fake.Advance(time.Minute)
+		require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{
+			Fingerprint: "fp-flap",
+			Name:        "Flappy",
+			Severity:    "high",
+			Status:      status,
+		}))
+	}
+
+	send("firing")
+	send("resolved")
+	send("firing")
+	_, ok := postRepo.posts["fp-flap"]
+	require.True(t, ok, "alert is handled as usual below the threshold")
+
+	updates := len(mmClient.UpdatePostCalls())
+	send("resolved")
+	require.Greater(t, len(mmClient.UpdatePostCalls()), updates)
+	assert.Equal(t, "FLAPPING: Flappy", lastCall(mmClient.UpdatePostCalls()).Attachment.Title)
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "Alert is flapping: 3 state changes in 10m")
+	_, ok = postRepo.posts["fp-flap"]
+	assert.True(t, ok, "the post is kept while the alert flaps")
+
+	updates, replies := len(mmClient.UpdatePostCalls()), len(mmClient.ReplyToThreadCalls())
+	send("firing")
+	send("resolved")
+	send("firing")
+	assert.Len(t, mmClient.UpdatePostCalls(), updates, "updates are paused")
+	assert.Len(t, mmClient.ReplyToThreadCalls(), replies)
+
+	require.NoError(t, uc.SettleFlapping(ctx))
+	assert.Len(t, mmClient.UpdatePostCalls(), updates, "still flapping")
+
+	fake.Advance(10 * time.Minute)
+	require.NoError(t, uc.SettleFlapping(ctx))
+	require.Greater(t, len(mmClient.UpdatePostCalls()), updates)
+	assert.Equal(t, "FIRING: Flappy", lastCall(mmClient.UpdatePostCalls()).Attachment.Title, "the post shows the alert as last received")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "stopped flapping")
+}
+
+func TestHandleAlertUseCase_FlappingStartsWithoutPost(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC))
+	detector := NewFlapDetector(10*time.Minute, 3)
+	detector.SetClock(fake)
+	uc.SetFlapDetector(detector)
+	ctx := context.Background()
+
+	for _, status := range []string{"resolved", "firing", "resolved", "firing"} {
+		fake.Advance(time.Minute)
+		require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{
+			Fingerprint: "fp-flap",
+			Name:        "Flappy",
+			Severity:    "high",
+			Status:      status,
+		}))
+	}
+
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.Equal(t, "FLAPPING: Flappy", lastCall(mmClient.CreatePostCalls()).Attachment.Title)
+	_, ok := postRepo.posts["fp-flap"]
+	assert.True(t, ok)
+}
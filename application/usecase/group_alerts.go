@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// AlertGrouper threads related alerts under a shared root post. Alerts are
+// related when they carry the same value for the first configured grouping
+// label and are routed to the same channel, so a single outage firing dozens
+// of alerts produces one channel post instead of dozens.
+type AlertGrouper struct {
+	groupRepo    group.Repository
+	mmClient     port.MattermostClient
+	threadClient port.MattermostThreadClient
+	msgBuilder   port.MessageBuilder
+	groupBy      []string
+	keepUIURL    string
+	logger       *slog.Logger
+	clock        clock.Clock
+
+	// mu serializes group read-modify-write cycles so concurrent alerts of
+	// the same group do not create duplicate root posts.
+	mu sync.Mutex
+}
+
+func NewAlertGrouper(
+	groupRepo group.Repository,
+	mmClient port.MattermostClient,
+	threadClient port.MattermostThreadClient,
+	msgBuilder port.MessageBuilder,
+	groupBy []string,
+	keepUIURL string,
+	logger *slog.Logger,
+) *AlertGrouper {
+	return &AlertGrouper{
+		groupRepo:    groupRepo,
+		mmClient:     mmClient,
+		threadClient: threadClient,
+		msgBuilder:   msgBuilder,
+		groupBy:      groupBy,
+		keepUIURL:    keepUIURL,
+		logger:       logger,
+		clock:        clock.Real(),
+	}
+}
+
+// SetClock replaces the clock that timestamps group changes.
+func (g *AlertGrouper) SetClock(c clock.Clock) {
+	g.clock = c
+}
+
+// PostAlert publishes the attachment of a newly seen alert as a reply in its
+// group thread, creating the group root post first when needed. grouped is
+// false when the alert carries none of the grouping labels; the caller should
+// then post it standalone.
+func (g *AlertGrouper) PostAlert(ctx context.Context, a *alert.Alert, channelID string, attachment post.Attachment) (postID string, grouped bool, err error) {
+	key, value, ok := g.groupKey(a)
+	if !ok {
+		return "", false, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	grp, err := g.groupRepo.FindByID(ctx, group.ID(channelID, key, value))
+	if err != nil && !errors.Is(err, group.ErrNotFound) {
+		return "", true, fmt.Errorf("find alert group: %w", err)
+	}
+
+	created := grp == nil
+	if created {
+		grp = group.NewGroup(key, value, channelID, "", g.clock.Now())
+	}
+	grp.Add(a.Fingerprint().Value(), a.Severity(), g.clock.Now())
+
+	rootAttachment := g.msgBuilder.BuildGroupRootAttachment(grp, g.keepUIURL)
+	if created {
+		rootID, err := g.mmClient.CreatePost(ctx, channelID, rootAttachment)
+		if err != nil {
+			return "", true, fmt.Errorf("create group root post: %w", err)
+		}
+		grp.SetRootPostID(rootID)
+		alertGroupsCreatedCounter.Inc()
+	} else if err := g.mmClient.UpdatePost(ctx, grp.RootPostID(), rootAttachment); err != nil {
+		g.logger.Warn("Failed to update group root post",
+			slog.String("group_id", grp.ID()),
+			slog.String("post_id", grp.RootPostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// Persist before posting the reply so a failed reply never orphans the root post.
+	if err := g.groupRepo.Save(ctx, grp); err != nil {
+		return "", true, fmt.Errorf("save alert group: %w", err)
+	}
+
+	postID, err = g.threadClient.CreateThreadPost(ctx, channelID, grp.RootPostID(), attachment)
+	if err != nil {
+		return "", true, fmt.Errorf("create group thread post: %w", err)
+	}
+
+	g.logger.Info("Alert added to group",
+		logger.ApplicationFields("alert_grouped",
+			slog.String("fingerprint", a.Fingerprint().Value()),
+			slog.String("group_id", grp.ID()),
+			slog.String("root_post_id", grp.RootPostID()),
+			slog.Int("active", grp.ActiveCount()),
+		),
+	)
+	alertsGroupedCounter.Inc()
+
+	return postID, true, nil
+}
+
+// Resolve removes a resolved alert from its group and refreshes the root
+// post. The group is closed once it has no active members; a later alert with
+// the same key starts a new thread.
+func (g *AlertGrouper) Resolve(ctx context.Context, a *alert.Alert, channelID string) error {
+	key, value, ok := g.groupKey(a)
+	if !ok {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	grp, err := g.groupRepo.FindByID(ctx, group.ID(channelID, key, value))
+	if err != nil {
+		if errors.Is(err, group.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("find alert group: %w", err)
+	}
+
+	if !grp.Remove(a.Fingerprint().Value(), g.clock.Now()) {
+		return nil
+	}
+
+	if err := g.mmClient.UpdatePost(ctx, grp.RootPostID(), g.msgBuilder.BuildGroupRootAttachment(grp, g.keepUIURL)); err != nil {
+		g.logger.Warn("Failed to update group root post",
+			slog.String("group_id", grp.ID()),
+			slog.String("post_id", grp.RootPostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if grp.ActiveCount() > 0 {
+		if err := g.groupRepo.Save(ctx, grp); err != nil {
+			return fmt.Errorf("save alert group: %w", err)
+		}
+		return nil
+	}
+
+	if err := g.groupRepo.Delete(ctx, grp.ID()); err != nil {
+		return fmt.Errorf("delete alert group: %w", err)
+	}
+
+	g.logger.Info("Alert group closed",
+		logger.ApplicationFields("alert_group_closed",
+			slog.String("group_id", grp.ID()),
+			slog.String("root_post_id", grp.RootPostID()),
+			slog.Int("total", grp.Total()),
+		),
+	)
+	alertGroupsClosedCounter.Inc()
+
+	return nil
+}
+
+// groupKey returns the first configured grouping label present on the alert.
+func (g *AlertGrouper) groupKey(a *alert.Alert) (key, value string, ok bool) {
+	labels := a.Labels()
+	for _, key := range g.groupBy {
+		if value := labels[key]; value != "" {
+			return key, value, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+type memGroupRepository struct {
+	groups  map[string]*group.Group
+	findErr error
+}
+
+func newMemGroupRepository() *memGroupRepository {
+	return &memGroupRepository{groups: make(map[string]*group.Group)}
+}
+
+func (m *memGroupRepository) Save(ctx context.Context, g *group.Group) error {
+	m.groups[g.ID()] = g
+	return nil
+}
+
+func (m *memGroupRepository) FindByID(ctx context.Context, id string) (*group.Group, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	g, ok := m.groups[id]
+	if !ok {
+		return nil, group.ErrNotFound
+	}
+	return g, nil
+}
+
+func (m *memGroupRepository) Delete(ctx context.Context, id string) error {
+	delete(m.groups, id)
+	return nil
+}
+
+type grouperFixture struct {
+	grouper      *AlertGrouper
+	repo         *memGroupRepository
+	mmClient     *portmock.MattermostClientMock
+	threadClient *portmock.MattermostThreadClientMock
+}
+
+func newGrouperFixture() *grouperFixture {
+	f := &grouperFixture{
+		repo: newMemGroupRepository(),
+		mmClient: &portmock.MattermostClientMock{
+			CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+				return "root-1", nil
+			},
+			UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+				return nil
+			},
+		},
+		threadClient: &portmock.MattermostThreadClientMock{
+			CreateThreadPostFunc: func(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error) {
+				return "reply-" + attachment.Title, nil
+			},
+		},
+	}
+	f.grouper = NewAlertGrouper(
+		f.repo,
+		f.mmClient,
+		f.threadClient,
+		newMessageBuilderMock(),
+		[]string{"alertgroup", "service"},
+		"https://keep.example.com",
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+	return f
+}
+
+func newGroupTestAlert(t *testing.T, fingerprint, severity string, labels map[string]string) *alert.Alert {
+	t.Helper()
+	fp, err := alert.NewFingerprint(fingerprint)
+	require.NoError(t, err)
+	sev, err := alert.NewSeverity(severity)
+	require.NoError(t, err)
+	status, err := alert.NewStatus("firing")
+	require.NoError(t, err)
+	a, err := alert.NewAlert(fp, "Alert "+fingerprint, sev, status, "", "", labels, time.Time{})
+	require.NoError(t, err)
+	return a
+}
+
+func TestAlertGrouper_UngroupedAlert(t *testing.T) {
+	f := newGrouperFixture()
+	a := newGroupTestAlert(t, "fp-1", "critical", map[string]string{"env": "prod"})
+
+	postID, grouped, err := f.grouper.PostAlert(context.Background(), a, "channel-1", post.Attachment{Title: "a1"})
+	require.NoError(t, err)
+	assert.False(t, grouped)
+	assert.Empty(t, postID)
+	assert.Empty(t, f.mmClient.CreatePostCalls())
+	assert.Empty(t, f.threadClient.CreateThreadPostCalls())
+}
+
+func TestAlertGrouper_FirstAlertCreatesRoot(t *testing.T) {
+	f := newGrouperFixture()
+	a := newGroupTestAlert(t, "fp-1", "warning", map[string]string{"alertgroup": "database"})
+
+	postID, grouped, err := f.grouper.PostAlert(context.Background(), a, "channel-1", post.Attachment{Title: "a1"})
+	require.NoError(t, err)
+	assert.True(t, grouped)
+	assert.Equal(t, "reply-a1", postID)
+
+	require.Len(t, f.mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "alertgroup=database", f.mmClient.CreatePostCalls()[0].Attachment.Title)
+	require.Len(t, f.threadClient.CreateThreadPostCalls(), 1)
+	assert.Equal(t, "root-1", f.threadClient.CreateThreadPostCalls()[0].RootID)
+
+	g := f.repo.groups[group.ID("channel-1", "alertgroup", "database")]
+	require.NotNil(t, g)
+	assert.Equal(t, "root-1", g.RootPostID())
+	assert.Equal(t, 1, g.ActiveCount())
+}
+
+func TestAlertGrouper_SubsequentAlertsReplyInThread(t *testing.T) {
+	f := newGrouperFixture()
+	ctx := context.Background()
+
+	_, _, err := f.grouper.PostAlert(ctx, newGroupTestAlert(t, "fp-1", "warning", map[string]string{"service": "api"}), "channel-1", post.Attachment{Title: "a1"})
+	require.NoError(t, err)
+	_, _, err = f.grouper.PostAlert(ctx, newGroupTestAlert(t, "fp-2", "critical", map[string]string{"service": "api"}), "channel-1", post.Attachment{Title: "a2"})
+	require.NoError(t, err)
+
+	assert.Len(t, f.mmClient.CreatePostCalls(), 1, "root post is created once")
+	require.Len(t, f.mmClient.UpdatePostCalls(), 1, "root post is refreshed for later members")
+	assert.Equal(t, "root-1", f.mmClient.UpdatePostCalls()[0].PostID)
+	assert.Len(t, f.threadClient.CreateThreadPostCalls(), 2)
+
+	g := f.repo.groups[group.ID("channel-1", "service", "api")]
+	assert.Equal(t, 2, g.ActiveCount())
+	assert.Equal(t, "critical", g.HighestSeverity().String())
+}
+
+func TestAlertGrouper_GroupKeyPriority(t *testing.T) {
+	f := newGrouperFixture()
+	a := newGroupTestAlert(t, "fp-1", "info", map[string]string{"service": "api", "alertgroup": "database"})
+
+	_, _, err := f.grouper.PostAlert(context.Background(), a, "channel-1", post.Attachment{Title: "a1"})
+	require.NoError(t, err)
+
+	assert.Contains(t, f.repo.groups, group.ID("channel-1", "alertgroup", "database"))
+}
+
+func TestAlertGrouper_GroupsArePerChannel(t *testing.T) {
+	f := newGrouperFixture()
+	ctx := context.Background()
+	labels := map[string]string{"service": "api"}
+
+	_, _, err := f.grouper.PostAlert(ctx, newGroupTestAlert(t, "fp-1", "critical", labels), "channel-1", post.Attachment{})
+	require.NoError(t, err)
+	_, _, err = f.grouper.PostAlert(ctx, newGroupTestAlert(t, "fp-2", "info", labels), "channel-2", post.Attachment{})
+	require.NoError(t, err)
+
+	assert.Len(t, f.mmClient.CreatePostCalls(), 2)
+	assert.Len(t, f.repo.groups, 2)
+}
+
+func TestAlertGrouper_RootPostFailure(t *testing.T) {
+	f := newGrouperFixture()
+	f.mmClient.CreatePostFunc = func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+		return "", errors.New("mattermost down")
+	}
+
+	_, grouped, err := f.grouper.PostAlert(context.Background(), newGroupTestAlert(t, "fp-1", "critical", map[string]string{"service": "api"}), "channel-1", post.Attachment{})
+	require.Error(t, err)
+	assert.True(t, grouped)
+	assert.Contains(t, err.Error(), "create group root post")
+	assert.Empty(t, f.repo.groups)
+	assert.Empty(t, f.threadClient.CreateThreadPostCalls())
+}
+
+func TestAlertGrouper_RepositoryError(t *testing.T) {
+	f := newGrouperFixture()
+	f.repo.findErr = errors.New("redis down")
+
+	_, _, err := f.grouper.PostAlert(context.Background(), newGroupTestAlert(t, "fp-1", "critical", map[string]string{"service": "api"}), "channel-1", post.Attachment{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "find alert group")
+}
+
+func TestAlertGrouper_ResolveClosesGroup(t *testing.T) {
+	f := newGrouperFixture()
+	ctx := context.Background()
+	labels := map[string]string{"service": "api"}
+	a1 := newGroupTestAlert(t, "fp-1", "critical", labels)
+	a2 := newGroupTestAlert(t, "fp-2", "warning", labels)
+	id := group.ID("channel-1", "service", "api")
+
+	_, _, err := f.grouper.PostAlert(ctx, a1, "channel-1", post.Attachment{})
+	require.NoError(t, err)
+	_, _, err = f.grouper.PostAlert(ctx, a2, "channel-1", post.Attachment{})
+	require.NoError(t, err)
+
+	require.NoError(t, f.grouper.Resolve(ctx, a1, "channel-1"))
+	require.Contains(t, f.repo.groups, id)
+	assert.Equal(t, 1, f.repo.groups[id].ActiveCount())
+
+	require.NoError(t, f.grouper.Resolve(ctx, a2, "channel-1"))
+	assert.NotContains(t, f.repo.groups, id)
+	assert.Len(t, f.mmClient.UpdatePostCalls(), 3, "root refreshed on second add and each resolve")
+
+	// Resolving an alert that is no longer a member is a no-op.
+	require.NoError(t, f.grouper.Resolve(ctx, a2, "channel-1"))
+	assert.Len(t, f.mmClient.UpdatePostCalls(), 3)
+}
+
+func TestHandleAlertUseCase_GroupedFiringAlert(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	f := newGrouperFixture()
+	uc.SetGrouper(f.grouper)
+	ctx := context.Background()
+
+	err := uc.Execute(ctx, dto.KeepAlertInput{
+		Fingerprint: "fp-grouped",
+		Name:        "Grouped Alert",
+		Severity:    "critical",
+		Status:      "firing",
+		Labels:      map[string]string{"alertgroup": "database"},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, mmClient.CreatePostCalls(), "grouped alerts are not posted standalone")
+	require.Len(t, f.threadClient.CreateThreadPostCalls(), 1)
+
+	fp, _ := alert.NewFingerprint("fp-grouped")
+	saved, err := postRepo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	assert.Equal(t, "reply-FIRING: Grouped Alert", saved.PostID())
+
+	err = uc.Execute(ctx, dto.KeepAlertInput{
+		Fingerprint: "fp-grouped",
+		Name:        "Grouped Alert",
+		Severity:    "critical",
+		Status:      "resolved",
+		Labels:      map[string]string{"alertgroup": "database"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, f.repo.groups, "group closes when its last alert resolves")
+}
+
+func TestHandleAlertUseCase_UngroupedAlertWithGrouper(t *testing.T) {
+	uc, _, mmClient, _, _, _ := setupHandleAlertUseCase()
+	f := newGrouperFixture()
+	uc.SetGrouper(f.grouper)
+
+	err := uc.Execute(context.Background(), dto.KeepAlertInput{
+		Fingerprint: "fp-plain",
+		Name:        "Plain Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Labels:      map[string]string{"env": "prod"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, f.threadClient.CreateThreadPostCalls())
+}
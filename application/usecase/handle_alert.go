@@ -5,13 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/VictoriaMetrics/metrics"
+
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
 )
 
@@ -24,6 +31,28 @@ type HandleAlertUseCase struct {
 	userMapper      port.UserMapper
 	keepUIURL       string
 	callbackURL     string
+	grouper         *AlertGrouper
+	budget          *NotificationBudget
+	correlation     *CorrelationTracker
+	retention       *PostRetention
+	audit           *AuditTrail
+	timeline        *StatusTimeline
+	maintenance     port.MaintenanceSchedule
+	dropRules       port.DropRules
+	flapping        *FlapDetector
+	quiet           port.QuietHours
+	quietStore      quiethours.Repository
+	onCall          port.OnCallResolver
+	directClient    port.MattermostDirectClient
+	mattermostURL   string
+	dmChannelsMu    sync.Mutex
+	dmChannels      map[string]string // username -> direct channel ID
+	enrichments     bool
+	severities      alert.SeverityMap
+	accepted        map[string]bool // nil accepts every known severity
+	assigneeRetry   AssigneeRetryPolicy
+	jitterRand      func() float64
+	clock           clock.Clock
 	logger          *slog.Logger
 }
 
@@ -47,44 +76,142 @@ func NewHandleAlertUseCase(
 		userMapper:      userMapper,
 		keepUIURL:       keepUIURL,
 		callbackURL:     callbackURL,
+		assigneeRetry:   DefaultAssigneeRetryPolicy(),
+		jitterRand:      rand.Float64,
+		clock:           clock.Real(),
 		logger:          logger,
 	}
 }
 
-func (uc *HandleAlertUseCase) Execute(ctx context.Context, input dto.KeepAlertInput) error {
-	fingerprint, err := alert.NewFingerprint(input.Fingerprint)
-	if err != nil {
-		return fmt.Errorf("parse fingerprint: %w", err)
-	}
+// SetClock replaces the clock that timestamps posts and paces assignee
+// retries.
+func (uc *HandleAlertUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
 
-	severity, err := alert.NewSeverity(input.Severity)
-	if err != nil {
-		return fmt.Errorf("parse severity: %w", err)
-	}
+// SetAssigneeRetryPolicy replaces the policy used to wait for Keep to expose
+// the assignee of an acknowledged alert.
+func (uc *HandleAlertUseCase) SetAssigneeRetryPolicy(p AssigneeRetryPolicy) {
+	uc.assigneeRetry = p
+}
 
-	status, err := alert.NewStatus(input.Status)
-	if err != nil {
-		return fmt.Errorf("parse status: %w", err)
+// SetEnrichmentFetch looks up the Keep enrichments of new firing alerts, so
+// their posts show the configured enrichment fields right away. It costs a
+// Keep request per new alert; off, the default, new posts carry none.
+func (uc *HandleAlertUseCase) SetEnrichmentFetch(fetch bool) {
+	uc.enrichments = fetch
+}
+
+// SetSeverityMap maps the severities of incoming alerts onto the canonical
+// ones before they are parsed. A nil map, the default, accepts only the
+// canonical severities.
+func (uc *HandleAlertUseCase) SetSeverityMap(m alert.SeverityMap) {
+	uc.severities = m
+}
+
+// SetAcceptedSeverities rejects alerts whose severity is not listed, like
+// alerts with an unknown severity. Nil, the default, accepts every severity
+// the bridge knows.
+func (uc *HandleAlertUseCase) SetAcceptedSeverities(severities []string) {
+	if severities == nil {
+		uc.accepted = nil
+		return
+	}
+	uc.accepted = make(map[string]bool, len(severities))
+	for _, s := range severities {
+		uc.accepted[strings.ToLower(s)] = true
 	}
+}
 
-	source := strings.Join(input.Source, ", ")
+// SetGrouper enables threading of related alerts. A nil grouper posts every
+// alert standalone.
+func (uc *HandleAlertUseCase) SetGrouper(grouper *AlertGrouper) {
+	uc.grouper = grouper
+}
 
-	var firingStartTime time.Time
-	if input.FiringStartTime != "" {
-		var parseErr error
-		firingStartTime, parseErr = time.Parse(time.RFC3339, input.FiringStartTime)
-		if parseErr != nil {
-			uc.logger.Warn("Failed to parse firingStartTime, using zero value",
-				slog.String("value", input.FiringStartTime),
-				slog.String("error", parseErr.Error()),
-			)
-		}
+// SetNotificationBudget holds back new firing alerts once their channel has
+// used up its hourly post budget. A nil budget posts every alert at once.
+func (uc *HandleAlertUseCase) SetNotificationBudget(budget *NotificationBudget) {
+	uc.budget = budget
+}
+
+// SetCorrelationTracker records the posts, Keep incident and ticket of every
+// alert and shows the incident and ticket in the post footer. A nil tracker
+// keeps no correlation records.
+func (uc *HandleAlertUseCase) SetCorrelationTracker(tracker *CorrelationTracker) {
+	uc.correlation = tracker
+}
+
+// SetPostRetention queues resolved posts for the retention policy. A nil
+// retention leaves resolved posts as they are.
+func (uc *HandleAlertUseCase) SetPostRetention(retention *PostRetention) {
+	uc.retention = retention
+}
+
+// SetAuditTrail records the lifecycle of every alert. A nil trail, the
+// default, records nothing.
+func (uc *HandleAlertUseCase) SetAuditTrail(trail *AuditTrail) {
+	uc.audit = trail
+}
+
+// SetStatusTimeline appends every status transition as a thread reply. A
+// nil timeline, the default, only edits the post.
+func (uc *HandleAlertUseCase) SetStatusTimeline(timeline *StatusTimeline) {
+	uc.timeline = timeline
+}
+
+// SetMaintenance holds back firing alerts that match an open maintenance
+// window. A nil schedule, the default, never holds alerts back.
+func (uc *HandleAlertUseCase) SetMaintenance(schedule port.MaintenanceSchedule) {
+	uc.maintenance = schedule
+}
+
+// SetDropRules ignores alerts that match one of rules. Nil rules, the
+// default, drop nothing.
+func (uc *HandleAlertUseCase) SetDropRules(rules port.DropRules) {
+	uc.dropRules = rules
+}
+
+// SetFlapDetector pauses updates of alerts that keep switching between
+// firing and resolved. A nil detector, the default, follows every switch.
+func (uc *HandleAlertUseCase) SetFlapDetector(detector *FlapDetector) {
+	uc.flapping = detector
+}
+
+// SetDirectMessages also sends new firing alerts as direct messages to the
+// users onCall selects. mattermostURL is used to link back to the channel post.
+func (uc *HandleAlertUseCase) SetDirectMessages(onCall port.OnCallResolver, directClient port.MattermostDirectClient, mattermostURL string) {
+	uc.onCall = onCall
+	uc.directClient = directClient
+	uc.mattermostURL = strings.TrimRight(mattermostURL, "/")
+	uc.dmChannels = make(map[string]string)
+}
+
+// receivedAtKey carries when the alert being handled reached the bridge.
+type receivedAtKey struct{}
+
+// withReceivedAt records when an alert webhook reached the bridge. The ingest
+// queue and stream set it when the alert is enqueued, so the delivery
+// latency includes the time the alert waited.
+func withReceivedAt(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, receivedAtKey{}, t)
+}
+
+func receivedAt(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(receivedAtKey{}).(time.Time)
+	return t, ok
+}
+
+func (uc *HandleAlertUseCase) Execute(ctx context.Context, input dto.KeepAlertInput) error {
+	if _, ok := receivedAt(ctx); !ok {
+		ctx = withReceivedAt(ctx, uc.clock.Now())
 	}
 
-	a, err := alert.NewAlert(fingerprint, input.Name, severity, status, input.Description, source, input.Labels, firingStartTime)
+	a, err := uc.parseAlert(input)
 	if err != nil {
-		return fmt.Errorf("create alert: %w", err)
+		return err
 	}
+	fingerprint, severity, status := a.Fingerprint(), a.Severity(), a.Status()
 
 	uc.logger.Info("Alert received",
 		logger.ApplicationFields("alert_received",
@@ -96,6 +223,34 @@ func (uc *HandleAlertUseCase) Execute(ctx context.Context, input dto.KeepAlertIn
 	)
 	alertsReceivedCounter(severity.String(), status.String()).Inc()
 
+	if uc.dropRules != nil {
+		if rule, ok := uc.dropRules.RuleForAlert(a.Name(), severity.String(), a.Labels()); ok {
+			uc.logger.Debug("Alert dropped by rule",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("name", a.Name()),
+				slog.String("rule", rule),
+			)
+			alertsDroppedCounter(rule).Inc()
+			return nil
+		}
+	}
+
+	uc.audit.Record(ctx, fingerprint, audit.KindReceived, "", audit.ReceivedDetail(status, severity))
+
+	if uc.correlation != nil {
+		if err := uc.correlation.Link(ctx, fingerprint, input.IncidentID, input.TicketID, input.TicketURL); err != nil {
+			uc.logger.Warn("Failed to record alert correlation",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+			correlationErrorsCounter.Inc()
+		}
+	}
+
+	if uc.droppedByRouting(ctx, a, fingerprint) {
+		return nil
+	}
+
 	if status.IsFiring() {
 		return uc.handleFiring(ctx, a, fingerprint)
 	}
@@ -120,19 +275,109 @@ func (uc *HandleAlertUseCase) Execute(ctx context.Context, input dto.KeepAlertIn
 		return uc.handleMaintenance(ctx, a, fingerprint)
 	}
 
+	if status.IsDismissed() {
+		return uc.handleClosed(ctx, a, fingerprint, port.MessageBuilder.BuildDismissedAttachment, alertDismissedCounter)
+	}
+
+	if status.IsMerged() {
+		return uc.handleClosed(ctx, a, fingerprint, port.MessageBuilder.BuildMergedAttachment, alertMergedCounter)
+	}
+
 	return nil
 }
 
+// parseAlert returns the alert of a webhook, with its severity normalized.
+func (uc *HandleAlertUseCase) parseAlert(input dto.KeepAlertInput) (*alert.Alert, error) {
+	fingerprint, err := alert.NewFingerprint(input.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("parse fingerprint: %w", err)
+	}
+
+	severity, err := alert.NewSeverity(uc.severities.Normalize(input.Severity))
+	if err != nil {
+		return nil, fmt.Errorf("parse severity: %w", err)
+	}
+	if uc.accepted != nil && !uc.accepted[severity.Value()] {
+		return nil, fmt.Errorf("parse severity: %w: %s is not accepted", alert.ErrInvalidSeverity, severity)
+	}
+
+	status, err := alert.NewStatus(input.Status)
+	if err != nil {
+		return nil, fmt.Errorf("parse status: %w", err)
+	}
+
+	source := strings.Join(input.Source, ", ")
+
+	var firingStartTime time.Time
+	if input.FiringStartTime != "" {
+		var parseErr error
+		firingStartTime, parseErr = time.Parse(time.RFC3339, input.FiringStartTime)
+		if parseErr != nil {
+			uc.logger.Warn("Failed to parse firingStartTime, using zero value",
+				slog.String("value", input.FiringStartTime),
+				slog.String("error", parseErr.Error()),
+			)
+		}
+	}
+
+	a, err := alert.NewAlert(fingerprint, input.Name, severity, status, input.Description, source, input.Labels, firingStartTime)
+	if err != nil {
+		return nil, fmt.Errorf("create alert: %w", err)
+	}
+	return a.WithAnnotations(input.Annotations).WithGeneratorURL(input.SourceURL()), nil
+}
+
 func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint) error {
 	existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
 	if err != nil && !errors.Is(err, post.ErrNotFound) {
 		return fmt.Errorf("find existing post: %w", err)
 	}
 
-	channelID := uc.channelResolver.ChannelIDForSeverity(a.Severity().String())
+	if uc.maintenance != nil {
+		if window, ok := uc.maintenance.WindowForAlert(a.Severity().String(), a.Labels(), uc.clock.Now()); ok {
+			return uc.handleMaintenanceWindow(ctx, a, fingerprint, existingPost, window)
+		}
+	}
+
+	if handled, err := uc.inhibitFlapping(ctx, a, fingerprint, existingPost); handled {
+		return err
+	}
 
 	if existingPost == nil {
-		return uc.createFiringPost(ctx, a, fingerprint, channelID)
+		channelID, copyChannelIDs := uc.routeAlert(a)
+		switch decision := uc.quietDecision(a, channelID); decision.Action {
+		case port.QuietHold:
+			return uc.holdForQuietHours(ctx, a, channelID)
+		case port.QuietRoute:
+			uc.logger.Info("Alert routed for quiet hours",
+				logger.ApplicationFields("alert_quiet_routed",
+					slog.String("fingerprint", fingerprint.Value()),
+					slog.String("channel_id", decision.ChannelID),
+				),
+			)
+			quietHoursAlertsCounter(port.QuietRoute).Inc()
+			channelID, copyChannelIDs = decision.ChannelID, nil
+		case port.QuietMute:
+			quietHoursAlertsCounter(port.QuietMute).Inc()
+		}
+		if uc.budget != nil && !uc.budget.Admit(ctx, channelID, a) {
+			return nil
+		}
+		if uc.enrichments {
+			a = uc.withEnrichments(ctx, a)
+		}
+		return uc.createFiringPost(ctx, a, fingerprint, channelID, copyChannelIDs)
+	}
+
+	if existingPost.IsSnoozed(uc.clock.Now()) {
+		uc.logger.Info("Re-fire ignored for snoozed alert",
+			logger.ApplicationFields("alert_refire_snoozed",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.Time("snoozed_until", existingPost.SnoozedUntil()),
+			),
+		)
+		alertSnoozedRefireCounter.Inc()
+		return nil
 	}
 
 	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
@@ -146,6 +391,7 @@ func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert,
 	var wasAcknowledged bool
 	var assignee string
 	if keepAlert != nil {
+		a = a.WithEnrichments(keepAlert.Enrichments)
 		assignee = uc.resolveAssigneeUsername(keepAlert.Enrichments)
 		// Check both enrichment status and alert status from Keep
 		// Keep may report acknowledged in either field depending on the source
@@ -155,15 +401,19 @@ func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert,
 		wasAcknowledged = wasAcknowledged || keepAlert.Status == "acknowledged"
 	}
 
+	existingPost.Refire()
+
 	if wasAcknowledged || assignee != "" {
 		alertWithStoredTime := alert.RestoreAlert(
 			fingerprint, a.Name(), a.Severity(), a.Status(),
 			a.Description(), a.Source(), a.Labels(),
 			existingPost.FiringStartTime(),
-		)
-		attachment := uc.msgBuilder.BuildAcknowledgedAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL, assignee)
+		).WithEnrichments(a.Enrichments()).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL()).WithFireCount(existingPost.FireCount())
+		render := func(b port.MessageBuilder) post.Attachment {
+			return b.BuildAcknowledgedAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL, assignee)
+		}
 
-		if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
+		if err := uc.refirePost(ctx, a, fingerprint, existingPost, render); err != nil {
 			return fmt.Errorf("update post to acknowledged: %w", err)
 		}
 
@@ -187,12 +437,15 @@ func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert,
 			),
 		)
 
-		existingPost.Touch()
+		uc.announceLabelChanges(ctx, fingerprint, existingPost, a.Labels())
+		existingPost.ShowStatus(alert.StatusAcknowledged)
+		existingPost.Touch(uc.clock.Now())
 		if err := uc.postRepo.Save(ctx, fingerprint, existingPost); err != nil {
 			return fmt.Errorf("update post in store: %w", err)
 		}
 
 		alertReFireCounter.Inc()
+		uc.audit.Record(ctx, fingerprint, audit.KindRefired, "", "while acknowledged")
 		return nil
 	}
 
@@ -200,14 +453,19 @@ func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert,
 		fingerprint, a.Name(), a.Severity(), a.Status(),
 		a.Description(), a.Source(), a.Labels(),
 		existingPost.FiringStartTime(),
-	)
-	attachment := uc.msgBuilder.BuildFiringAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL)
+	).WithEnrichments(a.Enrichments()).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL()).WithFireCount(existingPost.FireCount())
+	render := func(b port.MessageBuilder) post.Attachment {
+		return b.BuildFiringAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL)
+	}
 
-	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
+	if err := uc.refirePost(ctx, a, fingerprint, existingPost, render); err != nil {
 		return fmt.Errorf("update existing post: %w", err)
 	}
 
-	existingPost.Touch()
+	uc.timeline.Append(ctx, fingerprint, existingPost.ChannelID(), existingPost.PostID(), alert.StatusFiring, "")
+	uc.announceLabelChanges(ctx, fingerprint, existingPost, a.Labels())
+	existingPost.ShowStatus(alert.StatusFiring)
+	existingPost.Touch(uc.clock.Now())
 	if err := uc.postRepo.Save(ctx, fingerprint, existingPost); err != nil {
 		return fmt.Errorf("update post in store: %w", err)
 	}
@@ -220,21 +478,125 @@ func (uc *HandleAlertUseCase) handleFiring(ctx context.Context, a *alert.Alert,
 		),
 	)
 	alertReFireCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindRefired, "", "")
+	return nil
+}
+
+// refirePost shows the re-fired alert's attachment, rendered by render for
+// the post's channel. When the severity changed and routing now picks another
+// channel, the alert moves: a new post is created there and the old one
+// deleted. A severity change is announced in the thread. The caller saves
+// existingPost.
+func (uc *HandleAlertUseCase) refirePost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, existingPost *post.Post, render func(port.MessageBuilder) post.Attachment) error {
+	previous := existingPost.Severity()
+	if previous.Value() == "" || previous.Value() == a.Severity().Value() {
+		return uc.mmClient.UpdatePost(ctx, existingPost.PostID(), render(uc.msgBuilder.ForChannel(existingPost.ChannelID())))
+	}
+
+	moved := false
+	if channelID, _ := uc.routeAlert(a); channelID != "" && channelID != existingPost.ChannelID() {
+		postID, err := uc.createPost(ctx, a, channelID, render(uc.msgBuilder.ForChannel(channelID)))
+		if err != nil {
+			return fmt.Errorf("move post to channel %s: %w", channelID, err)
+		}
+		if err := uc.mmClient.DeletePost(ctx, existingPost.PostID()); err != nil {
+			uc.logger.Warn("Failed to delete post after moving alert",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("post_id", existingPost.PostID()),
+				slog.String("error", err.Error()),
+			)
+		}
+		existingPost.Move(postID, channelID)
+		alertsMovedCounter.Inc()
+		moved = true
+	} else if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), render(uc.msgBuilder.ForChannel(existingPost.ChannelID()))); err != nil {
+		return err
+	}
+	existingPost.ChangeSeverity(a.Severity())
+
+	direction, verb, emoji := "up", "escalated", "⬆️"
+	if a.Severity().Rank() < previous.Rank() {
+		direction, verb, emoji = "down", "lowered", "⬇️"
+	}
+	change := fmt.Sprintf("%s → %s", previous, a.Severity())
+	msg := fmt.Sprintf("%s Severity %s %s", emoji, verb, change)
+	if moved {
+		msg += ", moved from another channel"
+	}
+	if err := uc.mmClient.ReplyToThread(ctx, existingPost.ChannelID(), existingPost.PostID(), msg); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", existingPost.PostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	uc.logger.Info("Alert severity changed",
+		logger.ApplicationFields("alert_severity_changed",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("from", previous.String()),
+			slog.String("to", a.Severity().String()),
+			slog.Bool("moved", moved),
+		),
+	)
+	alertSeverityChangesCounter(direction).Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindSeverityChange, "", change)
 	return nil
 }
 
-func (uc *HandleAlertUseCase) createFiringPost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string) error {
-	attachment := uc.msgBuilder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+// announceLabelChanges replies in the alert's thread when a re-fire carries
+// other labels than the previous webhook, and records the new labels on the
+// post. Posts stored before labels were recorded only start recording.
+func (uc *HandleAlertUseCase) announceLabelChanges(ctx context.Context, fingerprint alert.Fingerprint, existingPost *post.Post, labels map[string]string) {
+	previous := existingPost.Labels()
+	existingPost.SetLabels(labels)
+	if previous == nil {
+		return
+	}
+	diff := diffLabels(previous, labels)
+	if diff.empty() {
+		return
+	}
+	if err := uc.mmClient.ReplyToThread(ctx, existingPost.ChannelID(), existingPost.PostID(), diff.message()); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", existingPost.PostID()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	alertLabelChangesCounter.Inc()
+}
 
-	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+// withEnrichments returns a with its enrichments from Keep, or a itself when
+// Keep cannot be reached; the post then shows them once they next change.
+func (uc *HandleAlertUseCase) withEnrichments(ctx context.Context, a *alert.Alert) *alert.Alert {
+	keepAlert, err := uc.keepClient.GetAlert(ctx, a.Fingerprint().Value())
+	if err != nil {
+		uc.logger.Warn("Failed to get alert enrichments from Keep, posting without them",
+			slog.String("fingerprint", a.Fingerprint().Value()),
+			slog.String("error", err.Error()),
+		)
+		return a
+	}
+	return a.WithEnrichments(keepAlert.Enrichments)
+}
+
+func (uc *HandleAlertUseCase) createFiringPost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string, copyChannelIDs []string) error {
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+
+	mentioned := attachment
+	mentioned.Message = uc.mentionMessage(a, channelID)
+	postID, err := uc.createPost(ctx, a, channelID, mentioned)
 	if err != nil {
 		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime())
+	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+	newPost.SetLabels(a.Labels())
+	newPost.ShowStatus(alert.StatusFiring)
 	if err := uc.postRepo.Save(ctx, fingerprint, newPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
+	uc.timeline.Append(ctx, fingerprint, channelID, postID, alert.StatusFiring, "")
 
 	uc.logger.Info("Alert posted to Mattermost",
 		logger.ApplicationFields("alert_posted",
@@ -246,22 +608,276 @@ func (uc *HandleAlertUseCase) createFiringPost(ctx context.Context, a *alert.Ale
 	)
 	alertsPostedCounter(a.Severity().String(), channelID).Inc()
 
+	uc.postCopies(ctx, a, fingerprint, copyChannelIDs)
+	uc.sendDirectMessages(ctx, a, fingerprint, attachment, postID)
+
 	return nil
 }
 
-func (uc *HandleAlertUseCase) handleResolved(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint) error {
+// ReleaseHeldAlerts posts alerts held by the notification budget as their
+// channels regain budget. Each alert is fetched from Keep again so the post
+// shows its current state; alerts that stopped firing or were posted in the
+// meantime are dropped. Alerts Keep does not know, such as those received
+// from Alertmanager, are posted as last received; when Keep cannot be asked
+// the alert stays held until the next run.
+func (uc *HandleAlertUseCase) ReleaseHeldAlerts(ctx context.Context) error {
+	if uc.budget == nil {
+		return nil
+	}
+	return uc.budget.Release(ctx, uc.releaseHeldAlert)
+}
+
+func (uc *HandleAlertUseCase) releaseHeldAlert(ctx context.Context, channelID string, a *alert.Alert) (bool, error) {
+	fingerprint := a.Fingerprint()
 	existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, post.ErrNotFound) {
+		return false, fmt.Errorf("find existing post: %w", err)
+	}
+	if existingPost != nil {
+		return false, nil
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	switch {
+	case errors.Is(err, port.ErrKeepAlertNotFound):
+		uc.logger.Info("Held alert unknown to Keep, posting it as received",
+			slog.String("fingerprint", fingerprint.Value()),
+		)
+	case err != nil:
+		return false, fmt.Errorf("get held alert from keep: %w", err)
+	default:
+		status, err := alert.NewStatus(keepAlert.Status)
+		if err != nil || !status.IsFiring() {
+			return false, nil
+		}
+		severity, err := alert.NewSeverity(keepAlert.Severity)
+		if err != nil {
+			severity = a.Severity()
+		}
+		a = alert.RestoreAlert(
+			fingerprint,
+			keepAlert.Name,
+			severity,
+			status,
+			keepAlert.Description,
+			strings.Join(keepAlert.Source, ", "),
+			keepAlert.Labels,
+			keepAlert.FiringStartTime,
+		).WithEnrichments(keepAlert.Enrichments).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL)
+	}
+
+	_, copyChannelIDs := uc.routeAlert(a)
+	if err := uc.createFiringPost(ctx, a, fingerprint, channelID, copyChannelIDs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// droppedByRouting reports whether routing drops a: it routes the alert to no
+// channel and no post shows it yet. Posts created before a drop rule keep
+// being updated.
+func (uc *HandleAlertUseCase) droppedByRouting(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint) bool {
+	if channelID, _ := uc.routeAlert(a); channelID != "" {
+		return false
+	}
+	if _, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); !errors.Is(err, post.ErrNotFound) {
+		return false
+	}
+	uc.logger.Info("Alert dropped by routing",
+		logger.ApplicationFields("alert_dropped",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("severity", a.Severity().String()),
+		),
+	)
+	return true
+}
+
+// routeAlert returns the channel that holds the tracked post and any further
+// channels that receive a copy of new firing alerts.
+func (uc *HandleAlertUseCase) routeAlert(a *alert.Alert) (string, []string) {
+	channelIDs := uc.channelResolver.ChannelIDsForAlert(a.Severity().String(), a.Labels())
+	if len(channelIDs) == 0 {
+		return "", nil
+	}
+	return channelIDs[0], channelIDs[1:]
+}
+
+// postCopies posts a button-less copy of a new alert into extra routed
+// channels, each mentioning whom the mention rules pick for its channel.
+// Copies are not updated, so later status changes only change the original
+// post. Failures are logged and do not fail the webhook.
+func (uc *HandleAlertUseCase) postCopies(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelIDs []string) {
+	if len(channelIDs) == 0 {
+		return
+	}
+	var postIDs []string
+	for _, channelID := range channelIDs {
+		attachment := uc.msgBuilder.ForChannel(channelID).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+		attachment.Actions = nil
+		attachment.Message = uc.mentionMessage(a, channelID)
+		postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+		if err != nil {
+			uc.logger.Warn("Failed to post alert copy",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("channel_id", channelID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		postIDs = append(postIDs, postID)
+		alertCopiesPostedCounter(a.Severity().String(), channelID).Inc()
+	}
+	uc.trackPosts(ctx, fingerprint, postIDs...)
+}
+
+// sendDirectMessages sends a button-less copy of a new alert to each on-call
+// user, linking back to the channel post where the buttons work. Failures
+// are logged and do not fail the webhook.
+func (uc *HandleAlertUseCase) sendDirectMessages(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, attachment post.Attachment, postID string) {
+	if uc.onCall == nil {
+		return
+	}
+	users := uc.onCall.UsersForAlert(a.Severity().String(), a.Labels())
+	if len(users) == 0 {
+		return
+	}
+
+	attachment.Actions = nil
+	header := "You are on call for this alert."
+	if uc.mattermostURL != "" {
+		header += fmt.Sprintf(" [Open in channel](%s/_redirect/pl/%s)", uc.mattermostURL, postID)
+	}
+	if attachment.Text != "" {
+		header += "\n\n" + attachment.Text
+	}
+	attachment.Text = header
+
+	var postIDs []string
+	for _, username := range users {
+		var dmPostID string
+		channelID, err := uc.directChannel(ctx, username)
+		if err == nil {
+			dmPostID, err = uc.mmClient.CreatePost(ctx, channelID, attachment)
+		}
+		if err != nil {
+			uc.logger.Warn("Failed to send alert direct message",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("username", username),
+				slog.String("error", err.Error()),
+			)
+			directMessageErrorsCounter.Inc()
+			continue
+		}
+		postIDs = append(postIDs, dmPostID)
+		directMessagesSentCounter(a.Severity().String()).Inc()
+	}
+	uc.trackPosts(ctx, fingerprint, postIDs...)
+}
+
+// directChannel returns the direct message channel with username, opening
+// it on first use.
+func (uc *HandleAlertUseCase) directChannel(ctx context.Context, username string) (string, error) {
+	uc.dmChannelsMu.Lock()
+	channelID, ok := uc.dmChannels[username]
+	uc.dmChannelsMu.Unlock()
+	if ok {
+		return channelID, nil
+	}
+
+	userID, err := uc.directClient.GetUserIDByUsername(ctx, username)
 	if err != nil {
-		if errors.Is(err, post.ErrNotFound) {
-			uc.logger.Warn("Resolved alert without existing post",
+		return "", fmt.Errorf("look up user: %w", err)
+	}
+	channelID, err = uc.directClient.CreateDirectChannel(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("create direct channel: %w", err)
+	}
+
+	uc.dmChannelsMu.Lock()
+	uc.dmChannels[username] = channelID
+	uc.dmChannelsMu.Unlock()
+	return channelID, nil
+}
+
+// createPost publishes a new alert post, inside its group thread when
+// grouping is enabled and the alert carries a grouping label.
+func (uc *HandleAlertUseCase) createPost(ctx context.Context, a *alert.Alert, channelID string, attachment post.Attachment) (string, error) {
+	if uc.correlation != nil {
+		uc.correlation.Decorate(ctx, a.Fingerprint(), &attachment)
+	}
+	var postID string
+	var grouped bool
+	var err error
+	if uc.grouper != nil {
+		postID, grouped, err = uc.grouper.PostAlert(ctx, a, channelID, attachment)
+	}
+	if !grouped && err == nil {
+		postID, err = uc.mmClient.CreatePost(ctx, channelID, attachment)
+	}
+	if err != nil {
+		return "", err
+	}
+	if at, ok := receivedAt(ctx); ok {
+		alertDeliverySeconds.Update(uc.clock.Now().Sub(at).Seconds())
+	}
+	uc.trackPosts(ctx, a.Fingerprint(), postID)
+	uc.audit.Record(ctx, a.Fingerprint(), audit.KindPosted, "", fmt.Sprintf("post %s in channel %s", postID, channelID))
+	return postID, nil
+}
+
+// trackPosts adds posts to the alert's correlation record. Failures are
+// logged and do not fail the webhook.
+func (uc *HandleAlertUseCase) trackPosts(ctx context.Context, fingerprint alert.Fingerprint, postIDs ...string) {
+	if uc.correlation == nil || len(postIDs) == 0 {
+		return
+	}
+	if err := uc.correlation.AddPosts(ctx, fingerprint, postIDs...); err != nil {
+		uc.logger.Warn("Failed to record alert posts",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("error", err.Error()),
+		)
+		correlationErrorsCounter.Inc()
+	}
+}
+
+func (uc *HandleAlertUseCase) handleResolved(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint) error {
+	existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, post.ErrNotFound) {
+		return fmt.Errorf("find existing post: %w", err)
+	}
+
+	if handled, err := uc.inhibitFlapping(ctx, a, fingerprint, existingPost); handled {
+		return err
+	}
+
+	if existingPost == nil {
+		if uc.budget != nil && uc.budget.Drop(ctx, fingerprint.Value()) {
+			uc.logger.Info("Held alert resolved before it was posted",
 				logger.ApplicationFields("alert_resolved",
 					slog.String("fingerprint", fingerprint.Value()),
-					slog.String("status", "no_existing_post"),
+					slog.String("status", "held"),
 				),
 			)
 			return nil
 		}
-		return fmt.Errorf("find existing post: %w", err)
+		if held, err := uc.resolveQuietHeld(ctx, fingerprint); held || err != nil {
+			if err == nil {
+				uc.logger.Info("Alert held for quiet hours resolved before it was posted",
+					logger.ApplicationFields("alert_resolved",
+						slog.String("fingerprint", fingerprint.Value()),
+						slog.String("status", "quiet_held"),
+					),
+				)
+			}
+			return err
+		}
+		uc.logger.Warn("Resolved alert without existing post",
+			logger.ApplicationFields("alert_resolved",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("status", "no_existing_post"),
+			),
+		)
+		return nil
 	}
 
 	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
@@ -286,15 +902,17 @@ func (uc *HandleAlertUseCase) handleResolved(ctx context.Context, a *alert.Alert
 		a.Source(),
 		a.Labels(),
 		existingPost.FiringStartTime(),
-	)
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
 
-	attachment := uc.msgBuilder.BuildResolvedAttachment(resolvedAlert, uc.keepUIURL, assignee)
+	attachment := uc.msgBuilder.ForChannel(existingPost.ChannelID()).BuildResolvedAttachment(resolvedAlert, uc.keepUIURL, assignee)
 
 	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
 		return fmt.Errorf("update post to resolved: %w", err)
 	}
 
-	if assignee != "" {
+	if uc.timeline != nil {
+		uc.timeline.Append(ctx, fingerprint, existingPost.ChannelID(), existingPost.PostID(), alert.StatusResolved, "")
+	} else if assignee != "" {
 		msg := fmt.Sprintf("✅ Alert automatically resolved. Was acknowledged by @%s", assignee)
 		if err := uc.mmClient.ReplyToThread(ctx, existingPost.ChannelID(), existingPost.PostID(), msg); err != nil {
 			uc.logger.Warn("Failed to reply to thread",
@@ -304,10 +922,29 @@ func (uc *HandleAlertUseCase) handleResolved(ctx context.Context, a *alert.Alert
 		}
 	}
 
+	observeResolved(existingPost, uc.clock.Now())
 	if err := uc.postRepo.Delete(ctx, fingerprint); err != nil {
 		return fmt.Errorf("delete post from store: %w", err)
 	}
 
+	if uc.grouper != nil {
+		if err := uc.grouper.Resolve(ctx, resolvedAlert, existingPost.ChannelID()); err != nil {
+			uc.logger.Warn("Failed to update alert group",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	if uc.retention != nil {
+		if err := uc.retention.Schedule(ctx, existingPost.PostID(), existingPost.ChannelID(), resolvedAlert, attachment); err != nil {
+			uc.logger.Warn("Failed to schedule post retention",
+				slog.String("post_id", existingPost.PostID()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
 	uc.logger.Info("Alert resolved",
 		logger.ApplicationFields("alert_resolved",
 			slog.String("fingerprint", fingerprint.Value()),
@@ -316,6 +953,7 @@ func (uc *HandleAlertUseCase) handleResolved(ctx context.Context, a *alert.Alert
 		),
 	)
 	alertResolveCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindResolved, "", "in Keep")
 
 	return nil
 }
@@ -337,12 +975,21 @@ func (uc *HandleAlertUseCase) handleAcknowledged(ctx context.Context, a *alert.A
 		fingerprint, a.Name(), a.Severity(), a.Status(),
 		a.Description(), a.Source(), a.Labels(),
 		existingPost.FiringStartTime(),
-	)
-	attachment := uc.msgBuilder.BuildAcknowledgedAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL, assignee)
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	attachment := uc.msgBuilder.ForChannel(existingPost.ChannelID()).BuildAcknowledgedAttachment(alertWithStoredTime, uc.callbackURL, uc.keepUIURL, assignee)
 
 	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
 		return fmt.Errorf("update post to acknowledged: %w", err)
 	}
+	uc.timeline.Append(ctx, fingerprint, existingPost.ChannelID(), existingPost.PostID(), alert.StatusAcknowledged, assignee)
+
+	existingPost.SetLastKnownAssignee(assignee)
+	existingPost.ShowStatus(alert.StatusAcknowledged)
+	observeAcknowledged(existingPost, uc.clock.Now())
+	existingPost.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, existingPost); err != nil {
+		return fmt.Errorf("update post in store: %w", err)
+	}
 
 	uc.logger.Info("Alert acknowledged (from Keep)",
 		logger.ApplicationFields("alert_acknowledged",
@@ -352,6 +999,7 @@ func (uc *HandleAlertUseCase) handleAcknowledged(ctx context.Context, a *alert.A
 		),
 	)
 	alertAckCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindAcknowledged, assignee, "in Keep")
 
 	return nil
 }
@@ -360,19 +1008,22 @@ func (uc *HandleAlertUseCase) createAcknowledgedPost(ctx context.Context, a *ale
 	// Fetch assignee from Keep with retry - enrichments may not be available immediately
 	assignee := uc.fetchAssigneeWithRetry(ctx, fingerprint.Value())
 
-	attachment := uc.msgBuilder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+	channelID, _ := uc.routeAlert(a)
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
 
-	channelID := uc.channelResolver.ChannelIDForSeverity(a.Severity().String())
-
-	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+	postID, err := uc.createPost(ctx, a, channelID, attachment)
 	if err != nil {
 		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime())
+	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+	newPost.SetLabels(a.Labels())
+	newPost.SetLastKnownAssignee(assignee)
+	newPost.ShowStatus(alert.StatusAcknowledged)
 	if err := uc.postRepo.Save(ctx, fingerprint, newPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
+	uc.timeline.Append(ctx, fingerprint, channelID, postID, alert.StatusAcknowledged, assignee)
 
 	uc.logger.Info("Acknowledged alert posted to Mattermost",
 		logger.ApplicationFields("alert_posted_acknowledged",
@@ -406,11 +1057,13 @@ func (uc *HandleAlertUseCase) resolveAssigneeUsername(enrichments map[string]str
 
 // fetchAssigneeWithRetry fetches assignee from Keep API with exponential backoff retry.
 // This handles the race condition where webhook arrives before enrichments are set.
+// Attempts, delays, jitter and the overall deadline come from the assignee retry policy.
 func (uc *HandleAlertUseCase) fetchAssigneeWithRetry(ctx context.Context, fingerprint string) string {
-	// Exponential backoff: 100ms, 200ms, 400ms (total max ~700ms)
-	retryDelays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	policy := uc.assigneeRetry
+	attempts := max(policy.Attempts, 1)
+	start := uc.clock.Now()
 
-	for attempt := 0; attempt <= len(retryDelays); attempt++ {
+	for attempt := 0; attempt < attempts; attempt++ {
 		assigneeRetryAttempts(attempt + 1).Inc()
 
 		keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint)
@@ -421,6 +1074,7 @@ func (uc *HandleAlertUseCase) fetchAssigneeWithRetry(ctx context.Context, finger
 				slog.Int("attempt", attempt+1),
 			)
 			assigneeRetryError.Inc()
+			assigneeUnresolvedCounter("error").Inc()
 			return ""
 		}
 
@@ -434,31 +1088,48 @@ func (uc *HandleAlertUseCase) fetchAssigneeWithRetry(ctx context.Context, finger
 				)
 			}
 			assigneeRetrySuccess.Inc()
+			assigneeResolveSeconds.Update(uc.clock.Now().Sub(start).Seconds())
 			return assignee
 		}
 
 		// Assignee not set yet, wait and retry (unless last attempt)
-		if attempt < len(retryDelays) {
-			uc.logger.Debug("Assignee not found, retrying with backoff",
+		if attempt == attempts-1 {
+			break
+		}
+		delay := policy.delay(attempt, uc.jitterRand())
+		if policy.Deadline > 0 && uc.clock.Now().Sub(start)+delay > policy.Deadline {
+			uc.logger.Warn("Assignee not found before retry deadline",
 				slog.String("fingerprint", fingerprint),
-				slog.Int("attempt", attempt+1),
-				slog.Duration("delay", retryDelays[attempt]),
+				slog.Int("attempts", attempt+1),
+				slog.Duration("deadline", policy.Deadline),
 			)
-			select {
-			case <-ctx.Done():
-				assigneeRetryError.Inc()
-				return ""
-			case <-time.After(retryDelays[attempt]):
-				// continue to next attempt
-			}
+			assigneeRetryDeadline.Inc()
+			assigneeUnresolvedCounter("deadline").Inc()
+			return ""
+		}
+
+		uc.logger.Debug("Assignee not found, retrying with backoff",
+			slog.String("fingerprint", fingerprint),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+		)
+		select {
+		case <-ctx.Done():
+			assigneeRetryError.Inc()
+			assigneeUnresolvedCounter("canceled").Inc()
+			return ""
+		case <-uc.clock.After(delay):
+			// continue to next attempt
 		}
 	}
 
-	uc.logger.Debug("Assignee not found after retries",
+	uc.logger.Warn("Assignee not found after retries",
 		slog.String("fingerprint", fingerprint),
-		slog.Int("total_attempts", len(retryDelays)+1),
+		slog.Int("total_attempts", attempts),
+		slog.Duration("waited", uc.clock.Now().Sub(start)),
 	)
 	assigneeRetryExhausted.Inc()
+	assigneeUnresolvedCounter("exhausted").Inc()
 	return ""
 }
 
@@ -468,7 +1139,7 @@ func (uc *HandleAlertUseCase) handleSuppressed(ctx context.Context, a *alert.Ale
 		return fmt.Errorf("find existing post: %w", err)
 	}
 
-	channelID := uc.channelResolver.ChannelIDForSeverity(a.Severity().String())
+	channelID, _ := uc.routeAlert(a)
 
 	if existingPost == nil {
 		return uc.createSuppressedPost(ctx, a, fingerprint, channelID)
@@ -478,8 +1149,8 @@ func (uc *HandleAlertUseCase) handleSuppressed(ctx context.Context, a *alert.Ale
 		fingerprint, a.Name(), a.Severity(), a.Status(),
 		a.Description(), a.Source(), a.Labels(),
 		existingPost.FiringStartTime(),
-	)
-	attachment := uc.msgBuilder.BuildSuppressedAttachment(alertWithStoredTime, uc.keepUIURL)
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	attachment := uc.msgBuilder.ForChannel(existingPost.ChannelID()).BuildSuppressedAttachment(alertWithStoredTime, uc.keepUIURL)
 
 	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
 		return fmt.Errorf("update post to suppressed: %w", err)
@@ -497,14 +1168,15 @@ func (uc *HandleAlertUseCase) handleSuppressed(ctx context.Context, a *alert.Ale
 }
 
 func (uc *HandleAlertUseCase) createSuppressedPost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string) error {
-	attachment := uc.msgBuilder.BuildSuppressedAttachment(a, uc.keepUIURL)
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildSuppressedAttachment(a, uc.keepUIURL)
 
-	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+	postID, err := uc.createPost(ctx, a, channelID, attachment)
 	if err != nil {
 		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime())
+	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+	newPost.SetLabels(a.Labels())
 	if err := uc.postRepo.Save(ctx, fingerprint, newPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
@@ -528,7 +1200,7 @@ func (uc *HandleAlertUseCase) handlePending(ctx context.Context, a *alert.Alert,
 		return fmt.Errorf("find existing post: %w", err)
 	}
 
-	channelID := uc.channelResolver.ChannelIDForSeverity(a.Severity().String())
+	channelID, _ := uc.routeAlert(a)
 
 	if existingPost == nil {
 		return uc.createPendingPost(ctx, a, fingerprint, channelID)
@@ -538,8 +1210,8 @@ func (uc *HandleAlertUseCase) handlePending(ctx context.Context, a *alert.Alert,
 		fingerprint, a.Name(), a.Severity(), a.Status(),
 		a.Description(), a.Source(), a.Labels(),
 		existingPost.FiringStartTime(),
-	)
-	attachment := uc.msgBuilder.BuildPendingAttachment(alertWithStoredTime, uc.keepUIURL)
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	attachment := uc.msgBuilder.ForChannel(existingPost.ChannelID()).BuildPendingAttachment(alertWithStoredTime, uc.keepUIURL)
 
 	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
 		return fmt.Errorf("update post to pending: %w", err)
@@ -557,14 +1229,15 @@ func (uc *HandleAlertUseCase) handlePending(ctx context.Context, a *alert.Alert,
 }
 
 func (uc *HandleAlertUseCase) createPendingPost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string) error {
-	attachment := uc.msgBuilder.BuildPendingAttachment(a, uc.keepUIURL)
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildPendingAttachment(a, uc.keepUIURL)
 
-	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+	postID, err := uc.createPost(ctx, a, channelID, attachment)
 	if err != nil {
 		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime())
+	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+	newPost.SetLabels(a.Labels())
 	if err := uc.postRepo.Save(ctx, fingerprint, newPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
@@ -587,19 +1260,46 @@ func (uc *HandleAlertUseCase) handleMaintenance(ctx context.Context, a *alert.Al
 	if err != nil && !errors.Is(err, post.ErrNotFound) {
 		return fmt.Errorf("find existing post: %w", err)
 	}
+	return uc.postMaintenance(ctx, a, fingerprint, existingPost, nil)
+}
+
+// handleMaintenanceWindow handles a firing alert that matches an open
+// maintenance window: it is either dropped or shown as a maintenance card
+// without buttons, copies or direct messages.
+func (uc *HandleAlertUseCase) handleMaintenanceWindow(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, existingPost *post.Post, window port.MaintenanceWindow) error {
+	maintenanceAlertsCounter(window.Name, window.Action).Inc()
+	if window.Action == port.MaintenanceSuppress {
+		uc.logger.Info("Alert suppressed by maintenance window",
+			logger.ApplicationFields("alert_maintenance_suppressed",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("window", window.Name),
+			),
+		)
+		uc.audit.Record(ctx, fingerprint, audit.KindMaintenance, "", "suppressed by window "+window.Name)
+		return nil
+	}
+	return uc.postMaintenance(ctx, a, fingerprint, existingPost, &window)
+}
 
-	channelID := uc.channelResolver.ChannelIDForSeverity(a.Severity().String())
+// postMaintenance creates or updates the post of an alert under maintenance.
+// window is the bridge maintenance window holding the alert, or nil when Keep
+// reported the alert under maintenance.
+func (uc *HandleAlertUseCase) postMaintenance(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, existingPost *post.Post, window *port.MaintenanceWindow) error {
+	if window != nil {
+		uc.audit.Record(ctx, fingerprint, audit.KindMaintenance, "", "in window "+window.Name)
+	}
 
 	if existingPost == nil {
-		return uc.createMaintenancePost(ctx, a, fingerprint, channelID)
+		channelID, _ := uc.routeAlert(a)
+		return uc.createMaintenancePost(ctx, a, fingerprint, channelID, window)
 	}
 
 	alertWithStoredTime := alert.RestoreAlert(
 		fingerprint, a.Name(), a.Severity(), a.Status(),
 		a.Description(), a.Source(), a.Labels(),
 		existingPost.FiringStartTime(),
-	)
-	attachment := uc.msgBuilder.BuildMaintenanceAttachment(alertWithStoredTime, uc.keepUIURL)
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	attachment := uc.maintenanceAttachment(existingPost.ChannelID(), alertWithStoredTime, window)
 
 	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
 		return fmt.Errorf("update post to maintenance: %w", err)
@@ -616,15 +1316,16 @@ func (uc *HandleAlertUseCase) handleMaintenance(ctx context.Context, a *alert.Al
 	return nil
 }
 
-func (uc *HandleAlertUseCase) createMaintenancePost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string) error {
-	attachment := uc.msgBuilder.BuildMaintenanceAttachment(a, uc.keepUIURL)
+func (uc *HandleAlertUseCase) createMaintenancePost(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, channelID string, window *port.MaintenanceWindow) error {
+	attachment := uc.maintenanceAttachment(channelID, a, window)
 
-	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+	postID, err := uc.createPost(ctx, a, channelID, attachment)
 	if err != nil {
 		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime())
+	newPost := post.NewPost(postID, channelID, fingerprint, a.Name(), a.Severity(), a.FiringStartTime(), uc.clock.Now())
+	newPost.SetLabels(a.Labels())
 	if err := uc.postRepo.Save(ctx, fingerprint, newPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
@@ -641,3 +1342,75 @@ func (uc *HandleAlertUseCase) createMaintenancePost(ctx context.Context, a *aler
 
 	return nil
 }
+
+// maintenanceAttachment renders the maintenance card for channelID, naming
+// the bridge maintenance window and when it closes in the footer.
+func (uc *HandleAlertUseCase) maintenanceAttachment(channelID string, a *alert.Alert, window *port.MaintenanceWindow) post.Attachment {
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildMaintenanceAttachment(a, uc.keepUIURL)
+	if window != nil {
+		attachment.Footer = fmt.Sprintf("Maintenance window %s until %s", window.Name, window.Until.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	return attachment
+}
+
+// handleClosed renders a dismissed or merged alert without buttons and stops
+// tracking its post, which also ends escalation and polling for it. Alerts
+// closed before they were posted are not posted at all.
+func (uc *HandleAlertUseCase) handleClosed(
+	ctx context.Context,
+	a *alert.Alert,
+	fingerprint alert.Fingerprint,
+	build func(b port.MessageBuilder, a *alert.Alert, keepUIURL string) post.Attachment,
+	counter *metrics.Counter,
+) error {
+	existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		if errors.Is(err, post.ErrNotFound) {
+			if uc.budget != nil {
+				uc.budget.Drop(ctx, fingerprint.Value())
+			}
+			uc.logger.Info("Closed alert without existing post",
+				logger.ApplicationFields("alert_"+a.Status().String(),
+					slog.String("fingerprint", fingerprint.Value()),
+					slog.String("status", "no_existing_post"),
+				),
+			)
+			return nil
+		}
+		return fmt.Errorf("find existing post: %w", err)
+	}
+
+	alertWithStoredTime := alert.RestoreAlert(
+		fingerprint, a.Name(), a.Severity(), a.Status(),
+		a.Description(), a.Source(), a.Labels(),
+		existingPost.FiringStartTime(),
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	attachment := build(uc.msgBuilder.ForChannel(existingPost.ChannelID()), alertWithStoredTime, uc.keepUIURL)
+
+	if err := uc.mmClient.UpdatePost(ctx, existingPost.PostID(), attachment); err != nil {
+		return fmt.Errorf("update post to %s: %w", a.Status(), err)
+	}
+
+	if err := uc.postRepo.Delete(ctx, fingerprint); err != nil {
+		return fmt.Errorf("delete post from store: %w", err)
+	}
+
+	if uc.grouper != nil {
+		if err := uc.grouper.Resolve(ctx, alertWithStoredTime, existingPost.ChannelID()); err != nil {
+			uc.logger.Warn("Failed to update alert group",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	uc.logger.Info("Alert closed",
+		logger.ApplicationFields("alert_"+a.Status().String(),
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("post_id", existingPost.PostID()),
+		),
+	)
+	counter.Inc()
+
+	return nil
+}
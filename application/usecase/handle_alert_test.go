@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,353 +14,425 @@ import (
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 )
 
-type mockPostRepository struct {
-	posts        map[string]*post.Post
-	findErr      error
-	saveErr      error
-	deleteErr    error
-	saveCalled   bool
-	deleteCalled bool
-}
-
-func newMockPostRepository() *mockPostRepository {
-	return &mockPostRepository{
-		posts: make(map[string]*post.Post),
-	}
-}
+func setupHandleAlertUseCase() (*HandleAlertUseCase, *postStore, *portmock.MattermostClientMock, *portmock.KeepClientMock, *portmock.MessageBuilderMock, *userMap) {
+	postRepo := newPostStore()
+	mmClient := newMattermostClientMock()
+	keepClient := newKeepClientMock()
+	msgBuilder := newMessageBuilderMock()
+	channelResolver := newChannelResolverMock()
+	userMapper := newUserMap()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 
-func (m *mockPostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
-	m.saveCalled = true
-	if m.saveErr != nil {
-		return m.saveErr
-	}
-	m.posts[fingerprint.Value()] = p
-	return nil
-}
+	uc := NewHandleAlertUseCase(
+		postRepo,
+		mmClient,
+		keepClient,
+		msgBuilder,
+		channelResolver,
+		userMapper,
+		"https://keep.example.com",
+		"https://callback.example.com",
+		logger,
+	)
 
-func (m *mockPostRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
-	if m.findErr != nil {
-		return nil, m.findErr
-	}
-	p, ok := m.posts[fingerprint.Value()]
-	if !ok {
-		return nil, post.ErrNotFound
-	}
-	return p, nil
+	return uc, postRepo, mmClient, keepClient, msgBuilder, userMapper
 }
 
-func (m *mockPostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
-	m.deleteCalled = true
-	if m.deleteErr != nil {
-		return m.deleteErr
-	}
-	delete(m.posts, fingerprint.Value())
-	return nil
-}
+func TestHandleAlertUseCase_NewFiringAlert(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
 
-func (m *mockPostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error) {
-	result := make([]*post.Post, 0, len(m.posts))
-	for _, p := range m.posts {
-		result = append(result, p)
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Description: "Test description",
+		Source:      []string{"prometheus"},
+		Labels:      map[string]string{"env": "prod", "service": "api"},
 	}
-	return result, nil
-}
-
-type mockMattermostClient struct {
-	createPostErr       error
-	updatePostErr       error
-	createdPostID       string
-	updatedPostID       string
-	channelID           string
-	createPostCalled    bool
-	updatePostCalled    bool
-	replyToThreadCalled bool
-	lastReplyMessage    string
-}
 
-func newMockMattermostClient() *mockMattermostClient {
-	return &mockMattermostClient{
-		createdPostID: "post-123",
-		channelID:     "channel-456",
-	}
-}
+	err := uc.Execute(ctx, input)
 
-func (m *mockMattermostClient) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
-	m.createPostCalled = true
-	if m.createPostErr != nil {
-		return "", m.createPostErr
-	}
-	return m.createdPostID, nil
-}
+	require.NoError(t, err)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
 
-func (m *mockMattermostClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
-	m.updatePostCalled = true
-	m.updatedPostID = postID
-	if m.updatePostErr != nil {
-		return m.updatePostErr
-	}
-	return nil
+	fp, _ := alert.NewFingerprint("fp-12345")
+	savedPost, err := postRepo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	assert.Equal(t, "post-123", savedPost.PostID())
+	assert.Equal(t, "channel-456", savedPost.ChannelID())
+	assert.Equal(t, "fp-12345", savedPost.Fingerprint().Value())
+	assert.Equal(t, "Test Alert", savedPost.AlertName())
+	assert.Equal(t, "high", savedPost.Severity().Value())
 }
 
-func (m *mockMattermostClient) GetUser(ctx context.Context, userID string) (string, error) {
-	return "testuser", nil
-}
+func TestHandleAlertUseCase_NewFiringAlertUsesClock(t *testing.T) {
+	uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+	ctx := context.Background()
 
-func (m *mockMattermostClient) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
-	m.replyToThreadCalled = true
-	m.lastReplyMessage = message
-	return nil
-}
+	err := uc.Execute(ctx, dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+	})
 
-type mockKeepClientForAlert struct {
-	alert       *port.KeepAlert
-	alerts      []*port.KeepAlert // different responses per call (for retry testing)
-	getAlertErr error
-	callCount   int
+	require.NoError(t, err)
+	savedPost, err := postRepo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-12345"))
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now(), savedPost.CreatedAt())
+	assert.Equal(t, fake.Now(), savedPost.LastUpdated())
 }
 
-func newMockKeepClientForAlert() *mockKeepClientForAlert {
-	return &mockKeepClientForAlert{
-		alert: &port.KeepAlert{
-			Fingerprint: "fp-12345",
-			Name:        "Test Alert",
-			Status:      "firing",
-			Severity:    "high",
-			Enrichments: nil,
-		},
+func TestHandleAlertUseCase_NewFiringAlertFetchesEnrichments(t *testing.T) {
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
 	}
-}
 
-func (m *mockKeepClientForAlert) EnrichAlert(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
-	return nil
-}
+	t.Run("shows the enrichments from keep", func(t *testing.T) {
+		uc, _, mmClient, keepClient, msgBuilder, _ := setupHandleAlertUseCase()
+		uc.SetEnrichmentFetch(true)
+		keepAlert := testKeepAlert()
+		keepAlert.Enrichments = map[string]string{"ai_summary": "Disk is filling up"}
+		keepClient.GetAlertFunc = keepAlertSequence(keepAlert)
 
-func (m *mockKeepClientForAlert) UnenrichAlert(ctx context.Context, fingerprint string, enrichments []string) error {
-	return nil
-}
+		require.NoError(t, uc.Execute(context.Background(), input))
+		assert.NotEmpty(t, mmClient.CreatePostCalls())
+		assert.Equal(t, "Disk is filling up", lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.Enrichments()["ai_summary"])
+	})
 
-func (m *mockKeepClientForAlert) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
-	m.callCount++
-	if m.getAlertErr != nil {
-		return nil, m.getAlertErr
-	}
-	// If alerts slice is set, return based on call count (for retry testing)
-	if len(m.alerts) > 0 {
-		idx := m.callCount - 1
-		if idx >= len(m.alerts) {
-			idx = len(m.alerts) - 1
-		}
-		return m.alerts[idx], nil
-	}
-	return m.alert, nil
-}
+	t.Run("posts without them when keep fails", func(t *testing.T) {
+		uc, _, mmClient, keepClient, msgBuilder, _ := setupHandleAlertUseCase()
+		uc.SetEnrichmentFetch(true)
+		keepClient.GetAlertFunc = keepAlertError(errors.New("keep down"))
 
-func (m *mockKeepClientForAlert) GetProviders(ctx context.Context) ([]port.KeepProvider, error) {
-	return nil, nil
-}
+		require.NoError(t, uc.Execute(context.Background(), input))
+		assert.NotEmpty(t, mmClient.CreatePostCalls())
+		assert.Empty(t, lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.Enrichments())
+	})
 
-func (m *mockKeepClientForAlert) CreateWebhookProvider(ctx context.Context, config port.WebhookProviderConfig) error {
-	return nil
-}
+	t.Run("off by default", func(t *testing.T) {
+		uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
 
-func (m *mockKeepClientForAlert) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error) {
-	return nil, nil
+		require.NoError(t, uc.Execute(context.Background(), input))
+		assert.Zero(t, len(keepClient.GetAlertCalls()), "keep is not asked for new alerts")
+	})
 }
 
-func (m *mockKeepClientForAlert) CreateWorkflow(ctx context.Context, config port.WorkflowConfig) error {
-	return nil
-}
+func TestHandleAlertUseCase_NewFiringAlertKeepsAnnotations(t *testing.T) {
+	uc, _, _, _, msgBuilder, _ := setupHandleAlertUseCase()
 
-func (m *mockKeepClientForAlert) GetAlerts(ctx context.Context, limit int) ([]port.KeepAlert, error) {
-	return nil, nil
-}
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Labels:      map[string]string{"env": "prod"},
+		Annotations: map[string]string{"summary": "Disk almost full"},
+	}
 
-type mockMessageBuilder struct {
-	lastResolvedAlert        *alert.Alert
-	lastResolvedAssignee     string
-	lastAcknowledgedAssignee string
+	require.NoError(t, uc.Execute(context.Background(), input))
+	assert.Equal(t, map[string]string{"summary": "Disk almost full"}, lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.Annotations())
+	assert.Equal(t, map[string]string{"env": "prod"}, lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.Labels(), "annotations are not merged into labels")
 }
 
-func (m *mockMessageBuilder) BuildFiringAttachment(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#FF0000",
-		Title: "FIRING: " + a.Name(),
-	}
-}
+func TestHandleAlertUseCase_NewFiringAlertKeepsGeneratorURL(t *testing.T) {
+	uc, _, _, _, msgBuilder, _ := setupHandleAlertUseCase()
 
-func (m *mockMessageBuilder) BuildAcknowledgedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string) post.Attachment {
-	m.lastAcknowledgedAssignee = username
-	return post.Attachment{
-		Color: "#FFA500",
-		Title: "ACKNOWLEDGED: " + a.Name(),
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		URL:         "http://grafana/alerting/abc",
 	}
-}
+	require.NoError(t, uc.Execute(context.Background(), input))
+	assert.Equal(t, "http://grafana/alerting/abc", lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.GeneratorURL(), "Keep's url stands in for generatorURL")
 
-func (m *mockMessageBuilder) BuildResolvedAttachment(a *alert.Alert, keepUIURL, acknowledgedBy string) post.Attachment {
-	m.lastResolvedAlert = a
-	m.lastResolvedAssignee = acknowledgedBy
-	return post.Attachment{
-		Color: "#00CC00",
-		Title: "RESOLVED: " + a.Name(),
-	}
+	input.Fingerprint = "fp-67890"
+	input.GeneratorURL = "http://prometheus/graph?g0.expr=up"
+	require.NoError(t, uc.Execute(context.Background(), input))
+	assert.Equal(t, "http://prometheus/graph?g0.expr=up", lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.GeneratorURL())
 }
 
-func (m *mockMessageBuilder) BuildSuppressedAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#9370DB",
-		Title: "SUPPRESSED: " + a.Name(),
+func TestHandleAlertUseCase_NewFiringAlertPostsCopies(t *testing.T) {
+	uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
+	uc.channelResolver = newChannelResolverMock("ch-payments", "ch-prod", "ch-broken")
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			if channelID == "ch-broken" {
+				return "", errors.New("no permission")
+			}
+			return "post-" + channelID, nil
+		},
 	}
-}
+	uc.mmClient = mmClient
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildFiringAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
+			return post.Attachment{Title: a.Name(), Actions: []post.Button{{ID: "acknowledge"}}}
+		},
+		BuildMentionMessageFunc: func(a *alert.Alert, channelID string) string {
+			if channelID == "ch-payments" {
+				return "@payments-oncall"
+			}
+			return ""
+		},
+	}
+	msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return msgBuilder }
+	uc.msgBuilder = msgBuilder
 
-func (m *mockMessageBuilder) BuildPendingAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#87CEEB",
-		Title: "PENDING: " + a.Name(),
+	err := uc.Execute(context.Background(), dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Labels:      map[string]string{"team": "payments"},
+	})
+	require.NoError(t, err, "failed copies do not fail the webhook")
+
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "ch-payments", calls[0].ChannelID)
+	assert.NotEmpty(t, calls[0].Attachment.Actions)
+	assert.Equal(t, "ch-prod", calls[1].ChannelID)
+	assert.Equal(t, "@payments-oncall", calls[0].Attachment.Message)
+	assert.Empty(t, calls[1].Attachment.Actions, "copies have no buttons")
+	assert.Equal(t, "Test Alert", calls[1].Attachment.Title)
+	assert.Empty(t, calls[1].Attachment.Message, "copies mention per their own channel")
+
+	saved := postRepo.posts["fp-12345"]
+	require.NotNil(t, saved)
+	assert.Equal(t, "post-ch-payments", saved.PostID())
+	assert.Equal(t, "ch-payments", saved.ChannelID())
+}
+
+func TestHandleAlertUseCase_NewFiringAlertSendsDirectMessages(t *testing.T) {
+	uc, _, _, _, _, _ := setupHandleAlertUseCase()
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-" + channelID, nil
+		},
 	}
-}
+	uc.mmClient = mmClient
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildFiringAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
+			return post.Attachment{Title: a.Name(), Text: "Disk is full", Actions: []post.Button{{ID: "acknowledge"}}}
+		},
+		BuildMentionMessageFunc: func(a *alert.Alert, channelID string) string { return "@here" },
+	}
+	msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return msgBuilder }
+	uc.msgBuilder = msgBuilder
+	onCall := &portmock.OnCallResolverMock{
+		UsersForAlertFunc: func(severity string, labels map[string]string) []string {
+			assert.Equal(t, "critical", severity)
+			return []string{"alice", "ghost"}
+		},
+	}
+	directClient := &portmock.MattermostDirectClientMock{
+		GetUserIDByUsernameFunc: func(ctx context.Context, username string) (string, error) {
+			if username == "ghost" {
+				return "", errors.New("user not found")
+			}
+			return "id-" + username, nil
+		},
+		CreateDirectChannelFunc: func(ctx context.Context, userID string) (string, error) {
+			return "dm-" + userID, nil
+		},
+	}
+	uc.SetDirectMessages(onCall, directClient, "https://mm.example.com/")
 
-func (m *mockMessageBuilder) BuildMaintenanceAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#708090",
-		Title: "MAINTENANCE: " + a.Name(),
+	for _, fp := range []string{"fp-1", "fp-2"} {
+		err := uc.Execute(context.Background(), dto.KeepAlertInput{
+			Fingerprint: fp,
+			Name:        "Disk full",
+			Severity:    "critical",
+			Status:      "firing",
+		})
+		require.NoError(t, err, "failed direct messages do not fail the webhook")
 	}
-}
 
-func (m *mockMessageBuilder) BuildProcessingAttachment(attachmentJSON, action string) (post.Attachment, error) {
-	return post.Attachment{
-		Color: "#808080",
-		Title: "Processing",
-	}, nil
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, "channel-456", calls[0].ChannelID)
+	assert.NotEmpty(t, calls[0].Attachment.Actions)
+	assert.Equal(t, "@here", calls[0].Attachment.Message)
+
+	dm := calls[1]
+	assert.Equal(t, "dm-id-alice", dm.ChannelID)
+	assert.Empty(t, dm.Attachment.Actions, "buttons only work on the channel post")
+	assert.Empty(t, dm.Attachment.Message, "direct messages notify without mentions")
+	assert.Equal(t, "You are on call for this alert. [Open in channel](https://mm.example.com/_redirect/pl/post-channel-456)\n\nDisk is full", dm.Attachment.Text)
+	assert.Equal(t, "dm-id-alice", calls[3].ChannelID)
+
+	assert.Len(t, directClient.CreateDirectChannelCalls(), 1, "direct channels are cached")
+	assert.Len(t, directClient.GetUserIDByUsernameCalls(), 3, "failed lookups are retried")
 }
 
-func (m *mockMessageBuilder) BuildErrorAttachment(alertName, fingerprint, keepUIURL, errorMsg string) post.Attachment {
-	return post.Attachment{
-		Color: "#FF0000",
-		Title: alertName,
-		Text:  "Error: " + errorMsg,
+func TestHandleAlertUseCase_DirectMessagesOnlyForNewPosts(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	onCall := &portmock.OnCallResolverMock{
+		UsersForAlertFunc: func(severity string, labels map[string]string) []string {
+			return []string{"alice"}
+		},
 	}
-}
+	directClient := &portmock.MattermostDirectClientMock{}
+	uc.SetDirectMessages(onCall, directClient, "")
 
-type mockChannelResolver struct {
-	channel string
-}
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 
-func newMockChannelResolver() *mockChannelResolver {
-	return &mockChannelResolver{channel: "channel-456"}
+	err := uc.Execute(context.Background(), dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, onCall.UsersForAlertCalls())
 }
 
-func (m *mockChannelResolver) ChannelIDForSeverity(severity string) string {
-	return m.channel
-}
+func TestHandleAlertUseCase_RefireExistingAlert(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
 
-type mockUserMapperForAlert struct {
-	mapping map[string]string
-}
+	fp, _ := alert.NewFingerprint("fp-12345")
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	postRepo.posts[fp.Value()] = existingPost
 
-func newMockUserMapperForAlert() *mockUserMapperForAlert {
-	return &mockUserMapperForAlert{
-		mapping: make(map[string]string),
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Description: "Test description",
+		Source:      []string{"prometheus"},
+		Labels:      map[string]string{},
 	}
-}
 
-func (m *mockUserMapperForAlert) GetKeepUsername(mattermostUsername string) (string, bool) {
-	keepUser, ok := m.mapping[mattermostUsername]
-	return keepUser, ok
+	err := uc.Execute(ctx, input)
+
+	require.NoError(t, err)
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
 }
 
-func (m *mockUserMapperForAlert) GetMattermostUsername(keepUsername string) (string, bool) {
-	for mmUser, keepUser := range m.mapping {
-		if keepUser == keepUsername {
-			return mmUser, true
-		}
-	}
-	return "", false
+type stubDropRules map[string]string // alert name -> rule
+
+func (s stubDropRules) RuleForAlert(name, severity string, labels map[string]string) (string, bool) {
+	rule, ok := s[name]
+	return rule, ok
 }
 
-func setupHandleAlertUseCase() (*HandleAlertUseCase, *mockPostRepository, *mockMattermostClient, *mockKeepClientForAlert, *mockMessageBuilder, *mockUserMapperForAlert) {
-	postRepo := newMockPostRepository()
-	mmClient := newMockMattermostClient()
-	keepClient := newMockKeepClientForAlert()
-	msgBuilder := &mockMessageBuilder{}
-	channelResolver := newMockChannelResolver()
-	userMapper := newMockUserMapperForAlert()
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+func TestHandleAlertUseCase_DropRules(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.SetDropRules(stubDropRules{"Watchdog": "watchdog"})
+	ctx := context.Background()
 
-	uc := NewHandleAlertUseCase(
-		postRepo,
-		mmClient,
-		keepClient,
-		msgBuilder,
-		channelResolver,
-		userMapper,
-		"https://keep.example.com",
-		"https://callback.example.com",
-		logger,
-	)
+	dropped := alertsDroppedCounter("watchdog").Get()
+	require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Watchdog", Severity: "info", Status: "firing"}))
+	assert.Empty(t, mmClient.CreatePostCalls(), "dropped alerts are not posted")
+	assert.Empty(t, postRepo.SaveCalls())
+	assert.Equal(t, dropped+1, alertsDroppedCounter("watchdog").Get())
 
-	return uc, postRepo, mmClient, keepClient, msgBuilder, userMapper
+	require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-2", Name: "DiskFull", Severity: "info", Status: "firing"}))
+	assert.NotEmpty(t, mmClient.CreatePostCalls(), "other alerts are posted")
 }
 
-func TestHandleAlertUseCase_NewFiringAlert(t *testing.T) {
+func TestHandleAlertUseCase_DroppedByRouting(t *testing.T) {
 	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.channelResolver = &portmock.ChannelResolverMock{
+		ChannelIDsForAlertFunc: func(string, map[string]string) []string { return nil },
+	}
 	ctx := context.Background()
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
-		Severity:    "high",
+		Severity:    "info",
 		Status:      "firing",
-		Description: "Test description",
-		Source:      []string{"prometheus"},
-		Labels:      map[string]string{"env": "prod", "service": "api"},
 	}
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Empty(t, mmClient.CreatePostCalls(), "dropped alerts are not posted")
+	assert.Empty(t, postRepo.SaveCalls())
 
-	err := uc.Execute(ctx, input)
-
-	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
-	assert.False(t, mmClient.updatePostCalled)
-
-	fp, _ := alert.NewFingerprint("fp-12345")
-	savedPost, err := postRepo.FindByFingerprint(ctx, fp)
-	require.NoError(t, err)
-	assert.Equal(t, "post-123", savedPost.PostID())
-	assert.Equal(t, "channel-456", savedPost.ChannelID())
-	assert.Equal(t, "fp-12345", savedPost.Fingerprint().Value())
-	assert.Equal(t, "Test Alert", savedPost.AlertName())
-	assert.Equal(t, "high", savedPost.Severity().Value())
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("info"), time.Now(), time.Now())
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "posts created before the drop rule are still updated")
 }
 
-func TestHandleAlertUseCase_RefireExistingAlert(t *testing.T) {
-	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+func TestHandleAlertUseCase_RefireCountsFirings(t *testing.T) {
+	uc, postRepo, _, _, msgBuilder, _ := setupHandleAlertUseCase()
 	ctx := context.Background()
 
-	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
-	postRepo.posts[fp.Value()] = existingPost
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	postRepo.posts["fp-12345"] = existingPost
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Severity:    "high",
 		Status:      "firing",
-		Description: "Test description",
+	}
+	require.NoError(t, uc.Execute(ctx, input))
+	require.NoError(t, uc.Execute(ctx, input))
+
+	assert.Equal(t, 3, postRepo.posts["fp-12345"].FireCount(), "the creating firing and two re-fires")
+	assert.Equal(t, 3, lastCall(msgBuilder.BuildFiringAttachmentCalls()).A.FireCount())
+}
+
+func TestHandleAlertUseCase_RefireSnoozedAlert(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
 		Source:      []string{"prometheus"},
 		Labels:      map[string]string{},
 	}
 
-	err := uc.Execute(ctx, input)
+	t.Run("ignored while snoozed", func(t *testing.T) {
+		uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+		uc.SetClock(clock.NewFake(now))
 
-	require.NoError(t, err)
-	assert.False(t, mmClient.createPostCalled)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.True(t, postRepo.saveCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+		existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), now, time.Now())
+		existingPost.Snooze(now.Add(time.Hour))
+		postRepo.posts["fp-12345"] = existingPost
+
+		require.NoError(t, uc.Execute(context.Background(), input))
+		assert.Empty(t, mmClient.CreatePostCalls())
+		assert.Empty(t, mmClient.UpdatePostCalls())
+		assert.Empty(t, mmClient.ReplyToThreadCalls())
+	})
+
+	t.Run("updated once snooze ended", func(t *testing.T) {
+		uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+		uc.SetClock(clock.NewFake(now.Add(time.Hour)))
+
+		existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), now, time.Now())
+		existingPost.Snooze(now.Add(time.Hour))
+		postRepo.posts["fp-12345"] = existingPost
+
+		require.NoError(t, uc.Execute(context.Background(), input))
+		assert.NotEmpty(t, mmClient.UpdatePostCalls())
+		assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
+	})
 }
 
 func TestHandleAlertUseCase_ResolveExistingAlert(t *testing.T) {
@@ -367,7 +440,7 @@ func TestHandleAlertUseCase_ResolveExistingAlert(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.KeepAlertInput{
@@ -383,9 +456,9 @@ func TestHandleAlertUseCase_ResolveExistingAlert(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.True(t, postRepo.deleteCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.NotEmpty(t, postRepo.DeleteCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
 
 	_, err = postRepo.FindByFingerprint(ctx, fp)
 	assert.Equal(t, post.ErrNotFound, err)
@@ -408,8 +481,8 @@ func TestHandleAlertUseCase_ResolveWithoutExistingPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.updatePostCalled)
-	assert.False(t, postRepo.deleteCalled)
+	assert.Empty(t, mmClient.UpdatePostCalls())
+	assert.Empty(t, postRepo.DeleteCalls())
 }
 
 func TestHandleAlertUseCase_InvalidFingerprint(t *testing.T) {
@@ -452,11 +525,49 @@ func TestHandleAlertUseCase_InvalidSeverity(t *testing.T) {
 	assert.Contains(t, err.Error(), "parse severity")
 }
 
+func TestHandleAlertUseCase_SeverityMap(t *testing.T) {
+	uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
+	uc.SetSeverityMap(alert.NewSeverityMap(map[string]string{"sev1": "critical"}))
+	ctx := context.Background()
+
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "SEV1",
+		Status:      "firing",
+	}
+
+	require.NoError(t, uc.Execute(ctx, input))
+	savedPost, err := postRepo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-12345"))
+	require.NoError(t, err)
+	assert.Equal(t, alert.SeverityCritical, savedPost.Severity().Value())
+
+	input.Fingerprint, input.Severity = "fp-67890", "P2"
+	assert.ErrorContains(t, uc.Execute(ctx, input), "parse severity", "unmapped severities are still rejected")
+}
+
+func TestHandleAlertUseCase_AcceptedSeverities(t *testing.T) {
+	uc, _, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.SetAcceptedSeverities([]string{"critical", "low"})
+	ctx := context.Background()
+
+	input := dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Test Alert", Severity: "debug", Status: "firing"}
+	err := uc.Execute(ctx, input)
+	require.ErrorIs(t, err, alert.ErrInvalidSeverity)
+	assert.Empty(t, mmClient.CreatePostCalls())
+
+	input.Severity = "low"
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+}
+
 func TestHandleAlertUseCase_MattermostCreatePostError(t *testing.T) {
 	uc, _, mmClient, _, _, _ := setupHandleAlertUseCase()
 	ctx := context.Background()
 
-	mmClient.createPostErr = errors.New("mattermost error")
+	mmClient.CreatePostFunc = func(context.Context, string, post.Attachment) (string, error) {
+		return "", errors.New("mattermost error")
+	}
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -491,8 +602,8 @@ func TestHandleAlertUseCase_LabelParsingWithPythonDict(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_InvalidLabelsDoesNotFailAlert(t *testing.T) {
@@ -512,15 +623,15 @@ func TestHandleAlertUseCase_InvalidLabelsDoesNotFailAlert(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_RepositorySaveError(t *testing.T) {
 	uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
 	ctx := context.Background()
 
-	postRepo.saveErr = errors.New("database error")
+	postRepo.SaveFunc = func(context.Context, alert.Fingerprint, *post.Post) error { return errors.New("database error") }
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -543,9 +654,9 @@ func TestHandleAlertUseCase_RepositoryDeleteError(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
-	postRepo.deleteErr = errors.New("database error")
+	postRepo.DeleteFunc = func(context.Context, alert.Fingerprint) error { return errors.New("database error") }
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -568,16 +679,16 @@ func TestHandleAlertUseCase_AcknowledgedStatusUpdatesPost(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "john.doe"},
-	}
+	})
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -592,8 +703,9 @@ func TestHandleAlertUseCase_AcknowledgedStatusUpdatesPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
+	assert.False(t, postRepo.posts[fp.Value()].AcknowledgedAt().IsZero(), "the first acknowledge is recorded")
 }
 
 func TestHandleAlertUseCase_AcknowledgedWithoutExistingPostCreatesPost(t *testing.T) {
@@ -613,8 +725,8 @@ func TestHandleAlertUseCase_AcknowledgedWithoutExistingPostCreatesPost(t *testin
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_ResolveUsesStoredFiringStartTime(t *testing.T) {
@@ -623,7 +735,7 @@ func TestHandleAlertUseCase_ResolveUsesStoredFiringStartTime(t *testing.T) {
 
 	storedFiringTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), storedFiringTime)
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), storedFiringTime, time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.KeepAlertInput{
@@ -640,10 +752,10 @@ func TestHandleAlertUseCase_ResolveUsesStoredFiringStartTime(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 
-	require.NotNil(t, msgBuilder.lastResolvedAlert, "BuildResolvedAttachment should have been called")
-	assert.Equal(t, storedFiringTime, msgBuilder.lastResolvedAlert.FiringStartTime(),
+	require.NotNil(t, lastCall(msgBuilder.BuildResolvedAttachmentCalls()).A, "BuildResolvedAttachment should have been called")
+	assert.Equal(t, storedFiringTime, lastCall(msgBuilder.BuildResolvedAttachmentCalls()).A.FiringStartTime(),
 		"resolved alert should use firingStartTime from stored post, not from incoming alert")
 }
 
@@ -654,16 +766,16 @@ func TestHandleAlertUseCase_ResolveWithAssigneeShowsInFooter(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "resolved",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "john.doe@keep"}, // Keep username in enrichment
-	}
+	})
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -678,11 +790,11 @@ func TestHandleAlertUseCase_ResolveWithAssigneeShowsInFooter(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.True(t, mmClient.replyToThreadCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.NotEmpty(t, mmClient.ReplyToThreadCalls())
 	// Should use reverse-mapped Mattermost username, not Keep username
-	assert.Contains(t, mmClient.lastReplyMessage, "john.doe")
-	assert.Equal(t, "john.doe", msgBuilder.lastResolvedAssignee)
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "john.doe")
+	assert.Equal(t, "john.doe", lastCall(msgBuilder.BuildResolvedAttachmentCalls()).AcknowledgedBy)
 }
 
 func TestHandleAlertUseCase_ResolveWithUnmappedAssigneeFallsBackToKeepUsername(t *testing.T) {
@@ -691,16 +803,16 @@ func TestHandleAlertUseCase_ResolveWithUnmappedAssigneeFallsBackToKeepUsername(t
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "resolved",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "unmapped@keep"},
-	}
+	})
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -715,9 +827,9 @@ func TestHandleAlertUseCase_ResolveWithUnmappedAssigneeFallsBackToKeepUsername(t
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 	// Should use Keep username when no reverse mapping exists
-	assert.Equal(t, "unmapped@keep", msgBuilder.lastResolvedAssignee)
+	assert.Equal(t, "unmapped@keep", lastCall(msgBuilder.BuildResolvedAttachmentCalls()).AcknowledgedBy)
 }
 
 func TestHandleAlertUseCase_RefireAcknowledgedAlertStaysAcknowledged(t *testing.T) {
@@ -727,16 +839,16 @@ func TestHandleAlertUseCase_RefireAcknowledgedAlertStaysAcknowledged(t *testing.
 	userMapper.mapping["john.doe"] = "john.doe@keep"
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "john.doe@keep", "status": "acknowledged"},
-	}
+	})
 
 	input := dto.KeepAlertInput{
 		Fingerprint: "fp-12345",
@@ -751,10 +863,10 @@ func TestHandleAlertUseCase_RefireAcknowledgedAlertStaysAcknowledged(t *testing.
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.True(t, mmClient.replyToThreadCalled)
-	assert.Contains(t, mmClient.lastReplyMessage, "re-fired")
-	assert.Contains(t, mmClient.lastReplyMessage, "john.doe")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.NotEmpty(t, mmClient.ReplyToThreadCalls())
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "re-fired")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "john.doe")
 }
 
 // Tests for fetchAssigneeWithRetry
@@ -764,18 +876,18 @@ func TestFetchAssigneeWithRetry_SucceedsOnFirstAttempt(t *testing.T) {
 	userMapper.mapping["john.doe"] = "john.doe@keep"
 	ctx := context.Background()
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "john.doe@keep"},
-	}
+	})
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
 	assert.Equal(t, "john.doe", assignee)
-	assert.Equal(t, 1, keepClient.callCount, "should only make 1 API call when assignee found immediately")
+	assert.Equal(t, 1, len(keepClient.GetAlertCalls()), "should only make 1 API call when assignee found immediately")
 	_ = msgBuilder // silence unused
 }
 
@@ -785,7 +897,7 @@ func TestFetchAssigneeWithRetry_SucceedsOnSecondAttempt(t *testing.T) {
 	ctx := context.Background()
 
 	// First call: no assignee, second call: assignee present
-	keepClient.alerts = []*port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence([]*port.KeepAlert{
 		{
 			Fingerprint: "fp-12345",
 			Name:        "Test Alert",
@@ -800,12 +912,12 @@ func TestFetchAssigneeWithRetry_SucceedsOnSecondAttempt(t *testing.T) {
 			Severity:    "high",
 			Enrichments: map[string]string{"assignee": "john.doe@keep"},
 		},
-	}
+	}...)
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
 	assert.Equal(t, "john.doe", assignee)
-	assert.Equal(t, 2, keepClient.callCount, "should make 2 API calls")
+	assert.Equal(t, 2, len(keepClient.GetAlertCalls()), "should make 2 API calls")
 }
 
 func TestFetchAssigneeWithRetry_SucceedsOnThirdAttempt(t *testing.T) {
@@ -814,16 +926,16 @@ func TestFetchAssigneeWithRetry_SucceedsOnThirdAttempt(t *testing.T) {
 	ctx := context.Background()
 
 	// First two calls: no assignee, third call: assignee present
-	keepClient.alerts = []*port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence([]*port.KeepAlert{
 		{Fingerprint: "fp-12345", Name: "Test Alert", Status: "acknowledged", Severity: "high", Enrichments: nil},
 		{Fingerprint: "fp-12345", Name: "Test Alert", Status: "acknowledged", Severity: "high", Enrichments: nil},
 		{Fingerprint: "fp-12345", Name: "Test Alert", Status: "acknowledged", Severity: "high", Enrichments: map[string]string{"assignee": "john.doe@keep"}},
-	}
+	}...)
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
 	assert.Equal(t, "john.doe", assignee)
-	assert.Equal(t, 3, keepClient.callCount, "should make 3 API calls")
+	assert.Equal(t, 3, len(keepClient.GetAlertCalls()), "should make 3 API calls")
 }
 
 func TestFetchAssigneeWithRetry_ExhaustsRetriesReturnsEmpty(t *testing.T) {
@@ -831,43 +943,143 @@ func TestFetchAssigneeWithRetry_ExhaustsRetriesReturnsEmpty(t *testing.T) {
 	ctx := context.Background()
 
 	// All calls return no assignee
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: nil, // no assignee
-	}
+	})
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
 	assert.Equal(t, "", assignee, "should return empty when assignee not found after all retries")
-	assert.Equal(t, 4, keepClient.callCount, "should make 4 API calls (1 initial + 3 retries)")
+	assert.Equal(t, 4, len(keepClient.GetAlertCalls()), "should make 4 API calls (1 initial + 3 retries)")
+}
+
+func TestFetchAssigneeWithRetry_BackoffFollowsClock(t *testing.T) {
+	uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+
+	done := make(chan string)
+	go func() {
+		done <- uc.fetchAssigneeWithRetry(context.Background(), "fp-12345")
+	}()
+
+	// Each backoff waits on the clock; nothing happens until it is advanced.
+	for _, delay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		fake.BlockUntil(1)
+		fake.Advance(delay)
+	}
+
+	select {
+	case assignee := <-done:
+		assert.Equal(t, "", assignee)
+	case <-time.After(time.Second):
+		t.Fatal("retry loop did not finish after advancing the clock")
+	}
+	assert.Equal(t, 4, len(keepClient.GetAlertCalls()))
+}
+
+func TestFetchAssigneeWithRetry_CustomPolicy(t *testing.T) {
+	uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+	uc.SetAssigneeRetryPolicy(AssigneeRetryPolicy{
+		Attempts:     3,
+		InitialDelay: 50 * time.Millisecond,
+		Multiplier:   3,
+		MaxDelay:     100 * time.Millisecond,
+	})
+
+	done := make(chan string)
+	go func() {
+		done <- uc.fetchAssigneeWithRetry(context.Background(), "fp-12345")
+	}()
+
+	// 50ms, then 150ms capped at MaxDelay.
+	for _, delay := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond} {
+		fake.BlockUntil(1)
+		fake.Advance(delay)
+	}
+
+	select {
+	case assignee := <-done:
+		assert.Equal(t, "", assignee)
+	case <-time.After(time.Second):
+		t.Fatal("retry loop did not finish after advancing the clock")
+	}
+	assert.Equal(t, 3, len(keepClient.GetAlertCalls()))
+}
+
+func TestFetchAssigneeWithRetry_StopsAtDeadline(t *testing.T) {
+	uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+	policy := DefaultAssigneeRetryPolicy()
+	policy.Deadline = 350 * time.Millisecond
+	uc.SetAssigneeRetryPolicy(policy)
+
+	done := make(chan string)
+	go func() {
+		done <- uc.fetchAssigneeWithRetry(context.Background(), "fp-12345")
+	}()
+
+	// 100ms + 200ms fit the deadline; the 400ms wait would cross it.
+	for _, delay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		fake.BlockUntil(1)
+		fake.Advance(delay)
+	}
+
+	select {
+	case assignee := <-done:
+		assert.Equal(t, "", assignee)
+	case <-time.After(time.Second):
+		t.Fatal("retry loop did not stop at the deadline")
+	}
+	assert.Equal(t, 3, len(keepClient.GetAlertCalls()))
+}
+
+func TestAssigneeRetryPolicy_Delay(t *testing.T) {
+	p := AssigneeRetryPolicy{InitialDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: 300 * time.Millisecond}
+
+	assert.Equal(t, 100*time.Millisecond, p.delay(0, 0.9))
+	assert.Equal(t, 200*time.Millisecond, p.delay(1, 0.9))
+	assert.Equal(t, 300*time.Millisecond, p.delay(2, 0.9), "capped at MaxDelay")
+
+	p.Jitter = 0.2
+	assert.Equal(t, 80*time.Millisecond, p.delay(0, 0))
+	assert.Equal(t, 100*time.Millisecond, p.delay(0, 0.5))
+	assert.InDelta(t, float64(120*time.Millisecond), float64(p.delay(0, 0.999999)), float64(time.Microsecond))
+
+	p.Multiplier = 0
+	assert.Equal(t, 100*time.Millisecond, p.delay(3, 0.5), "multiplier below 1 keeps the delay constant")
 }
 
 func TestFetchAssigneeWithRetry_APIErrorAbortsRetry(t *testing.T) {
 	uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
 	ctx := context.Background()
 
-	keepClient.getAlertErr = errors.New("API error")
+	keepClient.GetAlertFunc = keepAlertError(errors.New("API error"))
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
 	assert.Equal(t, "", assignee, "should return empty on API error")
-	assert.Equal(t, 1, keepClient.callCount, "should only make 1 API call when error occurs")
+	assert.Equal(t, 1, len(keepClient.GetAlertCalls()), "should only make 1 API call when error occurs")
 }
 
 func TestFetchAssigneeWithRetry_RespectsContextCancellation(t *testing.T) {
 	uc, _, _, keepClient, _, _ := setupHandleAlertUseCase()
 
 	// All calls return no assignee - will trigger retries
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: nil,
-	}
+	})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	// Cancel immediately - should abort during first retry wait
@@ -887,13 +1099,13 @@ func TestFetchAssigneeWithRetry_FallsBackToKeepUsername(t *testing.T) {
 	// No user mapping configured - should return Keep username as-is
 	ctx := context.Background()
 
-	keepClient.alert = &port.KeepAlert{
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
 		Fingerprint: "fp-12345",
 		Name:        "Test Alert",
 		Status:      "acknowledged",
 		Severity:    "high",
 		Enrichments: map[string]string{"assignee": "unmapped@keep.local"},
-	}
+	})
 
 	assignee := uc.fetchAssigneeWithRetry(ctx, "fp-12345")
 
@@ -919,8 +1131,8 @@ func TestHandleAlertUseCase_SuppressedStatusCreatesPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_SuppressedStatusUpdatesExistingPost(t *testing.T) {
@@ -928,7 +1140,7 @@ func TestHandleAlertUseCase_SuppressedStatusUpdatesExistingPost(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.KeepAlertInput{
@@ -944,9 +1156,9 @@ func TestHandleAlertUseCase_SuppressedStatusUpdatesExistingPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.createPostCalled)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
 }
 
 func TestHandleAlertUseCase_PendingStatusCreatesPost(t *testing.T) {
@@ -966,8 +1178,8 @@ func TestHandleAlertUseCase_PendingStatusCreatesPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_PendingStatusUpdatesExistingPost(t *testing.T) {
@@ -975,7 +1187,7 @@ func TestHandleAlertUseCase_PendingStatusUpdatesExistingPost(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.KeepAlertInput{
@@ -991,9 +1203,9 @@ func TestHandleAlertUseCase_PendingStatusUpdatesExistingPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.createPostCalled)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
 }
 
 func TestHandleAlertUseCase_MaintenanceStatusCreatesPost(t *testing.T) {
@@ -1013,8 +1225,8 @@ func TestHandleAlertUseCase_MaintenanceStatusCreatesPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.createPostCalled)
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_MaintenanceStatusUpdatesExistingPost(t *testing.T) {
@@ -1022,7 +1234,7 @@ func TestHandleAlertUseCase_MaintenanceStatusUpdatesExistingPost(t *testing.T) {
 	ctx := context.Background()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.KeepAlertInput{
@@ -1038,9 +1250,203 @@ func TestHandleAlertUseCase_MaintenanceStatusUpdatesExistingPost(t *testing.T) {
 	err := uc.Execute(ctx, input)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.createPostCalled)
-	assert.True(t, mmClient.updatePostCalled)
-	assert.Equal(t, "existing-post-123", mmClient.updatedPostID)
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
+}
+
+func TestHandleAlertUseCase_RefireWithSeverityChange(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("warning"), time.Now(), time.Now())
+
+	input := dto.KeepAlertInput{Fingerprint: "fp-12345", Name: "Test Alert", Severity: "critical", Status: "firing"}
+	require.NoError(t, uc.Execute(ctx, input))
+
+	assert.Empty(t, mmClient.CreatePostCalls(), "same routing keeps the post")
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
+	assert.Equal(t, "⬆️ Severity escalated warning → critical", lastCall(mmClient.ReplyToThreadCalls()).Message)
+	assert.Equal(t, "critical", postRepo.posts["fp-12345"].Severity().Value())
+
+	replies := len(mmClient.ReplyToThreadCalls())
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Len(t, mmClient.ReplyToThreadCalls(), replies, "unchanged severity is not announced")
+
+	input.Severity = "high"
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Equal(t, "⬇️ Severity lowered critical → high", lastCall(mmClient.ReplyToThreadCalls()).Message)
+}
+
+func TestHandleAlertUseCase_RefireWithSeverityChangeMovesPost(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.channelResolver = newChannelResolverMock("channel-critical")
+	ctx := context.Background()
+	firingStart := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("warning"), firingStart, time.Now())
+
+	input := dto.KeepAlertInput{Fingerprint: "fp-12345", Name: "Test Alert", Severity: "critical", Status: "firing"}
+	require.NoError(t, uc.Execute(ctx, input))
+
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "existing-post-123", lastCall(mmClient.DeletePostCalls()).PostID)
+	assert.Equal(t, "⬆️ Severity escalated warning → critical, moved from another channel", lastCall(mmClient.ReplyToThreadCalls()).Message)
+
+	moved := postRepo.posts["fp-12345"]
+	assert.Equal(t, "post-123", moved.PostID())
+	assert.Equal(t, "channel-critical", moved.ChannelID())
+	assert.Equal(t, "critical", moved.Severity().Value())
+	assert.Equal(t, firingStart, moved.FiringStartTime())
+}
+
+func TestHandleAlertUseCase_RefireWithChangedLabels(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
+
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Labels:      map[string]string{"service": "api", "pod": "api-1"},
+	}
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Equal(t, map[string]string{"service": "api", "pod": "api-1"}, postRepo.posts["fp-12345"].Labels())
+
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Empty(t, mmClient.ReplyToThreadCalls(), "unchanged labels are not announced")
+
+	input.Labels = map[string]string{"service": "api", "pod": "api-2"}
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Equal(t, "🏷️ Labels changed: changed `pod`: `api-1` → `api-2`", lastCall(mmClient.ReplyToThreadCalls()).Message)
+	assert.Equal(t, "api-2", postRepo.posts["fp-12345"].Labels()["pod"])
+}
+
+func TestHandleAlertUseCase_RefireWithoutRecordedLabels(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+	input := dto.KeepAlertInput{Fingerprint: "fp-12345", Name: "Test Alert", Severity: "high", Status: "firing", Labels: map[string]string{"service": "api"}}
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Empty(t, mmClient.ReplyToThreadCalls(), "posts stored before labels were recorded only start recording")
+	assert.Equal(t, map[string]string{"service": "api"}, postRepo.posts["fp-12345"].Labels())
+}
+
+type fakeMaintenanceSchedule struct {
+	window port.MaintenanceWindow
+	match  string // value of the "service" label the window applies to
+}
+
+func (f *fakeMaintenanceSchedule) WindowForAlert(severity string, labels map[string]string, now time.Time) (port.MaintenanceWindow, bool) {
+	return f.window, labels["service"] == f.match
+}
+
+func (f *fakeMaintenanceSchedule) OpenWindows(now time.Time) []string {
+	return []string{f.window.Name}
+}
+
+func firingInput(service string) dto.KeepAlertInput {
+	return dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "firing",
+		Labels:      map[string]string{"service": service},
+	}
+}
+
+func TestHandleAlertUseCase_MaintenanceWindowSuppress(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.SetMaintenance(&fakeMaintenanceSchedule{
+		window: port.MaintenanceWindow{Name: "db-upgrade", Action: port.MaintenanceSuppress, Until: time.Now().Add(time.Hour)},
+		match:  "postgres",
+	})
+	ctx := context.Background()
+
+	require.NoError(t, uc.Execute(ctx, firingInput("postgres")))
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, postRepo.SaveCalls())
+
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	require.NoError(t, uc.Execute(ctx, firingInput("postgres")))
+	assert.Empty(t, mmClient.UpdatePostCalls(), "re-fires are ignored too")
+
+	require.NoError(t, uc.Execute(ctx, firingInput("api")))
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "alerts outside the window are handled as usual")
+}
+
+func TestHandleAlertUseCase_MaintenanceWindowAnnotate(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.SetMaintenance(&fakeMaintenanceSchedule{
+		window: port.MaintenanceWindow{Name: "db-upgrade", Action: port.MaintenanceAnnotate, Until: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)},
+		match:  "postgres",
+	})
+	ctx := context.Background()
+
+	require.NoError(t, uc.Execute(ctx, firingInput("postgres")))
+	assert.NotEmpty(t, mmClient.CreatePostCalls())
+	assert.NotEmpty(t, postRepo.SaveCalls())
+	created := lastCall(mmClient.CreatePostCalls()).Attachment
+	assert.Equal(t, "MAINTENANCE: Test Alert", created.Title)
+	assert.Equal(t, "Maintenance window db-upgrade until 2026-01-05 12:00 UTC", created.Footer)
+	assert.Empty(t, created.Actions, "maintenance cards have no buttons")
+
+	require.NoError(t, uc.Execute(ctx, firingInput("postgres")))
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "post-123", lastCall(mmClient.UpdatePostCalls()).PostID)
+	assert.Equal(t, "MAINTENANCE: Test Alert", lastCall(mmClient.UpdatePostCalls()).Attachment.Title, "re-fires keep the maintenance card")
+}
+
+func TestHandleAlertUseCase_ClosedStatusUpdatesAndUntracksPost(t *testing.T) {
+	for _, status := range []string{"dismissed", "merged"} {
+		t.Run(status, func(t *testing.T) {
+			uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
+			mmClient := &portmock.MattermostClientMock{
+				UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+					return nil
+				},
+			}
+			uc.mmClient = mmClient
+			ctx := context.Background()
+
+			existingPost := post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+			postRepo.posts["fp-12345"] = existingPost
+
+			input := dto.KeepAlertInput{
+				Fingerprint: "fp-12345",
+				Name:        "Test Alert",
+				Severity:    "high",
+				Status:      status,
+				Source:      []string{"prometheus"},
+			}
+
+			require.NoError(t, uc.Execute(ctx, input))
+			require.Len(t, mmClient.UpdatePostCalls(), 1)
+			assert.Equal(t, "existing-post-123", mmClient.UpdatePostCalls()[0].PostID)
+			assert.Equal(t, strings.ToUpper(status)+": Test Alert", mmClient.UpdatePostCalls()[0].Attachment.Title)
+			assert.Empty(t, mmClient.UpdatePostCalls()[0].Attachment.Actions)
+			assert.NotEmpty(t, postRepo.DeleteCalls(), "closed alerts are no longer tracked")
+		})
+	}
+}
+
+func TestHandleAlertUseCase_ClosedStatusWithoutPostIsIgnored(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	ctx := context.Background()
+
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "dismissed",
+		Source:      []string{"prometheus"},
+	}
+
+	require.NoError(t, uc.Execute(ctx, input))
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
+	assert.Empty(t, postRepo.SaveCalls())
 }
 
 func TestHandleAlertUseCase_InvalidStatusReturnsError(t *testing.T) {
@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,7 +14,9 @@ import (
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
 )
 
@@ -20,6 +25,10 @@ const (
 	EnrichmentKeyAssignee = "assignee"
 )
 
+// ErrNotPermitted is returned by ExecuteAction when the permission rules do
+// not admit the user.
+var ErrNotPermitted = errors.New("not permitted")
+
 type HandleCallbackUseCase struct {
 	postRepo    post.Repository
 	keepClient  port.KeepClient
@@ -28,6 +37,21 @@ type HandleCallbackUseCase struct {
 	userMapper  port.UserMapper
 	keepUIURL   string
 	callbackURL string
+	snoozeFor   time.Duration
+	ackFor      []time.Duration
+	retention   *PostRetention
+	permissions *CallbackPermissions
+	audit       *AuditTrail
+	timeline    *StatusTimeline
+	locks       *FingerprintLocks
+	watchdog    *CallbackWatchdog
+	dialog      *ResolveDialog
+	custom      *CustomActionRunner
+	workflows   *WorkflowMenu
+	queue       *actionQueue        // nil unless actions are queued while Keep is unavailable
+	pending     *PendingKeepActions // nil unless failed Keep updates are replayed
+	serverIDs   port.MattermostServerIDs
+	clock       clock.Clock
 	logger      *slog.Logger
 	wg          sync.WaitGroup
 }
@@ -50,11 +74,125 @@ func NewHandleCallbackUseCase(
 		userMapper:  userMapper,
 		keepUIURL:   keepUIURL,
 		callbackURL: callbackURL,
+		clock:       clock.Real(),
 		logger:      logger,
 	}
 }
 
+// SetSnoozeDuration enables the snooze action; zero, the default, rejects it.
+func (uc *HandleCallbackUseCase) SetSnoozeDuration(d time.Duration) {
+	uc.snoozeFor = d
+}
+
+// SetAckDurations enables the Acknowledge for menu with the durations it
+// offers; none, the default, rejects it.
+func (uc *HandleCallbackUseCase) SetAckDurations(durations []time.Duration) {
+	uc.ackFor = durations
+}
+
+// ackDuration returns the offered duration picked from the Acknowledge for
+// menu.
+func (uc *HandleCallbackUseCase) ackDuration(selected string) (time.Duration, bool) {
+	d, err := time.ParseDuration(selected)
+	if err != nil || !slices.Contains(uc.ackFor, d) {
+		return 0, false
+	}
+	return d, true
+}
+
+// SetPostRetention queues posts resolved from Mattermost for the retention
+// policy. A nil retention leaves them as they are.
+func (uc *HandleCallbackUseCase) SetPostRetention(retention *PostRetention) {
+	uc.retention = retention
+}
+
+// SetPermissions checks every action against the permission rules. A nil
+// permissions, the default, lets everyone apply every action.
+func (uc *HandleCallbackUseCase) SetPermissions(permissions *CallbackPermissions) {
+	uc.permissions = permissions
+}
+
+// SetAuditTrail records who acknowledged, unacknowledged, snoozed or
+// resolved each alert. A nil trail, the default, records nothing.
+func (uc *HandleCallbackUseCase) SetAuditTrail(trail *AuditTrail) {
+	uc.audit = trail
+}
+
+// SetStatusTimeline replies with a timestamped timeline entry instead of the
+// plain "Acknowledged by" style reply. A nil timeline, the default, keeps the
+// plain replies.
+func (uc *HandleCallbackUseCase) SetStatusTimeline(timeline *StatusTimeline) {
+	uc.timeline = timeline
+}
+
+// SetLocks applies actions under the alert's fingerprint lock, so they do not
+// race webhooks handled by another replica. Nil locks, the default, apply
+// actions without locking.
+func (uc *HandleCallbackUseCase) SetLocks(locks *FingerprintLocks) {
+	uc.locks = locks
+}
+
+// SetWatchdog tracks the asynchronous phase of callbacks with watchdog,
+// which rewrites posts the phase leaves processing. A nil watchdog, the
+// default, tracks nothing.
+func (uc *HandleCallbackUseCase) SetWatchdog(watchdog *CallbackWatchdog) {
+	uc.watchdog = watchdog
+	uc.mmClient = watchdog.WrapClient(uc.mmClient)
+}
+
+// SetResolveDialog asks for a resolution note in a dialog when Resolve is
+// clicked; the alert is resolved once the dialog is submitted. A nil dialog,
+// the default, resolves right away.
+func (uc *HandleCallbackUseCase) SetResolveDialog(dialog *ResolveDialog) {
+	uc.dialog = dialog
+}
+
+// SetCustomActions runs the custom action buttons of alert posts. Nil, the
+// default, rejects them.
+func (uc *HandleCallbackUseCase) SetCustomActions(runner *CustomActionRunner) {
+	uc.custom = runner
+}
+
+// SetWorkflowMenu runs the workflows picked from the Run workflow menu of
+// alert posts. Nil, the default, rejects them.
+func (uc *HandleCallbackUseCase) SetWorkflowMenu(menu *WorkflowMenu) {
+	uc.workflows = menu
+}
+
+// SetMattermostServers qualifies the IDs sent with callbacks with the
+// Mattermost server of their channel. Nil, the default, leaves them as sent.
+func (uc *HandleCallbackUseCase) SetMattermostServers(ids port.MattermostServerIDs) {
+	uc.serverIDs = ids
+}
+
+// qualifyIDs returns input with its post, user and trigger IDs qualified
+// with the server of its channel, and the user picked from a user menu.
+func (uc *HandleCallbackUseCase) qualifyIDs(input dto.MattermostCallbackInput) dto.MattermostCallbackInput {
+	if uc.serverIDs == nil {
+		return input
+	}
+	input.PostID = uc.serverIDs.QualifyID(input.ChannelID, input.PostID)
+	input.UserID = uc.serverIDs.QualifyID(input.ChannelID, input.UserID)
+	input.TriggerID = uc.serverIDs.QualifyID(input.ChannelID, input.TriggerID)
+	if input.Context[post.ContextKeyDataSource] == post.DataSourceUsers && input.Context[post.ContextKeySelectedOption] != post.AssignToMe {
+		callbackContext := maps.Clone(input.Context)
+		callbackContext[post.ContextKeySelectedOption] = uc.serverIDs.QualifyID(input.ChannelID, callbackContext[post.ContextKeySelectedOption])
+		input.Context = callbackContext
+	}
+	return input
+}
+
+// SetClock replaces the clock used to compute snooze deadlines.
+func (uc *HandleCallbackUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
 func (uc *HandleCallbackUseCase) ExecuteImmediate(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+	input = uc.qualifyIDs(input)
+	input, retried, err := retriedInput(input)
+	if err != nil {
+		return nil, err
+	}
 	action := input.Context[post.ContextKeyAction]
 	fingerprintStr := input.Context[post.ContextKeyFingerprint]
 	alertName := input.Context[post.ContextKeyAlertName]
@@ -69,16 +207,21 @@ func (uc *HandleCallbackUseCase) ExecuteImmediate(input dto.MattermostCallbackIn
 		),
 	)
 
-	validActions := map[string]bool{
-		post.ActionAcknowledge:   true,
-		post.ActionResolve:       true,
-		post.ActionUnacknowledge: true,
+	metricAction := callbackMetricAction(action)
+	callbacksReceivedCounter(metricAction).Inc()
+	if retried {
+		callbacksRetriedCounter(metricAction).Inc()
 	}
-	metricAction := "unknown"
-	if validActions[action] {
-		metricAction = action
+
+	// The commands were rendered when the post was built; the post itself
+	// stays unchanged.
+	if action == post.ActionCommands {
+		text := input.Context[post.ContextKeyCommands]
+		if text == "" {
+			return nil, fmt.Errorf("missing required context field: commands")
+		}
+		return &dto.CallbackOutput{Ephemeral: text}, nil
 	}
-	callbacksReceivedCounter(metricAction).Inc()
 
 	if _, err := alert.NewFingerprint(fingerprintStr); err != nil {
 		return nil, fmt.Errorf("parse fingerprint: %w", err)
@@ -88,8 +231,78 @@ func (uc *HandleCallbackUseCase) ExecuteImmediate(input dto.MattermostCallbackIn
 		return nil, fmt.Errorf("missing required context field: alert_name")
 	}
 
-	if attachmentJSON == "" {
-		return nil, fmt.Errorf("missing required context field: attachment_json")
+	// Custom actions and workflow runs leave the post unchanged, so their
+	// buttons carry no attachment.
+	switch action {
+	case post.ActionCustom:
+		if input.Context[post.ContextKeyCustomAction] == "" {
+			return nil, fmt.Errorf("missing required context field: custom_action")
+		}
+	case post.ActionRunWorkflow:
+	default:
+		if attachmentJSON == "" {
+			return nil, fmt.Errorf("missing required context field: attachment_json")
+		}
+	}
+
+	if (action == post.ActionAssign || action == post.ActionRunWorkflow || action == post.ActionAcknowledgeFor) && input.Context[post.ContextKeySelectedOption] == "" {
+		return nil, fmt.Errorf("missing required context field: selected_option")
+	}
+
+	if uc.permissions != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		allowed := uc.permissions.Allowed(ctx, input.UserID, permissionAction(action), input.Context[post.ContextKeySeverity])
+		cancel()
+		if !allowed {
+			callbacksDeniedCounter(metricAction).Inc()
+			uc.logger.Info("Callback denied by permission rules",
+				logger.ApplicationFields("callback_denied",
+					slog.String("action", action),
+					slog.String("fingerprint", fingerprintStr),
+					slog.String("user_id", input.UserID),
+				),
+			)
+			return &dto.CallbackOutput{Ephemeral: fmt.Sprintf("You are not permitted to %s this alert.", deniedActionText(action))}, nil
+		}
+	}
+
+	if output := uc.queueIfKeepUnavailable(input); output != nil {
+		return output, nil
+	}
+
+	if action == post.ActionCustom {
+		name := input.Context[post.ContextKeyCustomAction]
+		if uc.custom == nil {
+			return &dto.CallbackOutput{Ephemeral: "Custom actions are disabled."}, nil
+		}
+		return &dto.CallbackOutput{Ephemeral: fmt.Sprintf("Running **%s**…", name), RunAsync: true}, nil
+	}
+
+	if action == post.ActionRunWorkflow {
+		if uc.workflows == nil {
+			return &dto.CallbackOutput{Ephemeral: "The workflow menu is disabled."}, nil
+		}
+		workflow, ok := uc.workflows.Workflow(input.Context[post.ContextKeySelectedOption])
+		if !ok {
+			return &dto.CallbackOutput{Ephemeral: "This workflow is no longer available."}, nil
+		}
+		return &dto.CallbackOutput{Ephemeral: fmt.Sprintf("Starting workflow **%s**…", workflow.Name), RunAsync: true}, nil
+	}
+
+	if action == post.ActionAcknowledgeFor {
+		if len(uc.ackFor) == 0 {
+			return &dto.CallbackOutput{Ephemeral: "Acknowledging for a limited time is disabled."}, nil
+		}
+		if _, ok := uc.ackDuration(input.Context[post.ContextKeySelectedOption]); !ok {
+			return &dto.CallbackOutput{Ephemeral: "This duration is no longer offered."}, nil
+		}
+	}
+
+	// A retried resolve sends the resolution it was given the first time.
+	if action == post.ActionResolve && uc.dialog != nil && input.TriggerID != "" && !retried {
+		if uc.openResolveDialog(input, fingerprintStr, alertName) {
+			return &dto.CallbackOutput{DialogOpened: true}, nil
+		}
 	}
 
 	processingAttachment, err := uc.msgBuilder.BuildProcessingAttachment(attachmentJSON, action)
@@ -103,92 +316,324 @@ func (uc *HandleCallbackUseCase) ExecuteImmediate(input dto.MattermostCallbackIn
 }
 
 func (uc *HandleCallbackUseCase) ExecuteAsync(input dto.MattermostCallbackInput) {
+	buttonContext := input.Context
+	input = uc.qualifyIDs(input)
+	// ExecuteImmediate rejected invalid retries.
+	input, _, _ = retriedInput(input)
 	action := input.Context[post.ContextKeyAction]
 	fingerprintStr := input.Context[post.ContextKeyFingerprint]
 	alertName := input.Context[post.ContextKeyAlertName]
 
+	start := uc.clock.Now()
+
 	uc.wg.Add(1)
 	go func() {
 		defer uc.wg.Done()
 
 		asyncCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
+		if uc.watchdog != nil {
+			var op *callbackOp
+			asyncCtx, op = uc.watchdog.start(asyncCtx, input, buttonContext)
+			defer uc.watchdog.finish(op)
+		}
 
-		fingerprint, err := alert.NewFingerprint(fingerprintStr)
+		err := uc.withLock(asyncCtx, fingerprintStr, func(ctx context.Context) error {
+			uc.executeAsync(ctx, input, action, fingerprintStr, alertName)
+			return nil
+		})
 		if err != nil {
-			uc.logger.Error("Failed to parse fingerprint in async phase",
+			uc.logger.Error("Failed to lock alert in async phase",
 				slog.String("fingerprint", fingerprintStr),
 				slog.String("error", err.Error()),
 			)
-			uc.updatePostWithError(asyncCtx, input.PostID, alertName, fingerprintStr, "Invalid fingerprint")
-			return
+			uc.updatePostWithError(asyncCtx, input.PostID, alertName, fingerprintStr, "Alert is busy, try again")
 		}
+		callbackDurationSeconds(callbackMetricAction(action)).Update(uc.clock.Now().Sub(start).Seconds())
+	}()
+}
 
-		keepAlert, err := uc.keepClient.GetAlert(asyncCtx, fingerprintStr)
-		if err != nil {
-			uc.logger.Error("Failed to get alert from keep in async phase",
-				slog.String("fingerprint", fingerprintStr),
-				slog.String("error", err.Error()),
-			)
-			uc.updatePostWithError(asyncCtx, input.PostID, alertName, fingerprintStr, "Failed to get alert data")
-			return
+// retriedInput returns the input of the action a Retry button runs again, and
+// whether input came from one. Other input is returned as it is.
+func retriedInput(input dto.MattermostCallbackInput) (dto.MattermostCallbackInput, bool, error) {
+	if input.Context[post.ContextKeyAction] != post.ActionRetry {
+		return input, false, nil
+	}
+	action := input.Context[post.ContextKeyRetryAction]
+	if !retryableAction(action) {
+		return input, true, fmt.Errorf("invalid retry action: %q", action)
+	}
+	callbackContext := maps.Clone(input.Context)
+	callbackContext[post.ContextKeyAction] = action
+	delete(callbackContext, post.ContextKeyRetryAction)
+	input.Context = callbackContext
+	return input, true, nil
+}
+
+// retryableAction reports whether posts offer Retry when action fails in
+// Keep.
+func retryableAction(action string) bool {
+	switch action {
+	case post.ActionAcknowledge, post.ActionAcknowledgeFor, post.ActionResolve, post.ActionUnacknowledge:
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry adds a Retry button to attachment when keepErr says the action
+// failed in Keep and it is not kept for replay. retry is the context of the
+// button that ran the action; actions without a button get no Retry.
+func (uc *HandleCallbackUseCase) withRetry(attachment post.Attachment, keepErr error, retry map[string]string) post.Attachment {
+	if keepErr == nil || retry == nil || (uc.pending != nil && errors.Is(keepErr, port.ErrKeepUnavailable)) {
+		return attachment
+	}
+	retryContext := maps.Clone(retry)
+	// The retry shows "Processing..." on the post as it is now.
+	shown := attachment
+	shown.Actions = nil
+	if attachmentJSON, err := shown.ToJSON(); err == nil {
+		retryContext[post.ContextKeyAttachmentJSON] = attachmentJSON
+	}
+	attachment.Actions = append(slices.Clone(attachment.Actions), post.RetryButton(uc.callbackURL, retryContext))
+	return attachment
+}
+
+// permissionAction returns the action the permission rules know action by:
+// acknowledging for a limited time is acknowledging.
+func permissionAction(action string) string {
+	if action == post.ActionAcknowledgeFor {
+		return post.ActionAcknowledge
+	}
+	return action
+}
+
+// deniedActionText phrases action for the "not permitted" message.
+func deniedActionText(action string) string {
+	switch action {
+	case post.ActionAcknowledgeFor:
+		return post.ActionAcknowledge
+	case post.ActionCustom:
+		return "run custom actions on"
+	case post.ActionRunWorkflow:
+		return "run workflows on"
+	default:
+		return action
+	}
+}
+
+// callbackMetricAction returns the action label for callback metrics, so
+// forged actions cannot create new series.
+func callbackMetricAction(action string) string {
+	switch action {
+	case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge,
+		post.ActionSnooze, post.ActionCommands, post.ActionAssign, post.ActionCustom,
+		post.ActionRunWorkflow, post.ActionAcknowledgeFor:
+		return action
+	default:
+		return "unknown"
+	}
+}
+
+func (uc *HandleCallbackUseCase) executeAsync(ctx context.Context, input dto.MattermostCallbackInput, action, fingerprintStr, alertName string) {
+	if action == post.ActionCustom {
+		uc.handleCustomActionAsync(ctx, input, fingerprintStr)
+		return
+	}
+	if action == post.ActionRunWorkflow {
+		uc.handleRunWorkflowAsync(ctx, input, fingerprintStr)
+		return
+	}
+
+	fingerprint, err := alert.NewFingerprint(fingerprintStr)
+	if err != nil {
+		uc.logger.Error("Failed to parse fingerprint in async phase",
+			slog.String("fingerprint", fingerprintStr),
+			slog.String("error", err.Error()),
+		)
+		uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Invalid fingerprint")
+		return
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprintStr)
+	if err != nil && uc.pending != nil && replayableAction(action) && errors.Is(err, port.ErrKeepUnavailable) {
+		keepAlert, err = uc.trackedKeepAlert(ctx, fingerprint, input), nil
+	}
+	if err != nil {
+		uc.logger.Error("Failed to get alert from keep in async phase",
+			slog.String("fingerprint", fingerprintStr),
+			slog.String("error", err.Error()),
+		)
+		var retry []post.Button
+		if retryableAction(action) {
+			retry = append(retry, post.RetryButton(uc.callbackURL, input.Context))
 		}
+		uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Failed to get alert data", retry...)
+		return
+	}
 
-		severity, err := alert.NewSeverity(keepAlert.Severity)
-		if err != nil {
-			uc.logger.Error("Failed to parse severity in async phase",
-				slog.String("severity", keepAlert.Severity),
-				slog.String("error", err.Error()),
-			)
-			uc.updatePostWithError(asyncCtx, input.PostID, alertName, fingerprintStr, "Invalid severity")
+	a, err := restoreCallbackAlert(keepAlert, fingerprint, action)
+	if err != nil {
+		uc.logger.Error("Failed to parse severity in async phase",
+			slog.String("severity", keepAlert.Severity),
+			slog.String("error", err.Error()),
+		)
+		uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Invalid severity")
+		return
+	}
+	if existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); err == nil {
+		a = a.WithFireCount(existing.FireCount())
+	}
+
+	username := uc.lookupUsername(ctx, input.UserID)
+
+	switch action {
+	case post.ActionAcknowledge:
+		uc.handleAcknowledgeAsync(ctx, a, fingerprint, username, input.PostID, input.ChannelID, time.Time{}, input.Context)
+	case post.ActionAcknowledgeFor:
+		d, ok := uc.ackDuration(input.Context[post.ContextKeySelectedOption])
+		if !ok {
+			uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Duration not offered")
 			return
 		}
-
-		username, err := uc.mmClient.GetUser(asyncCtx, input.UserID)
+		uc.handleAcknowledgeAsync(ctx, a, fingerprint, username, input.PostID, input.ChannelID, uc.clock.Now().Add(d), input.Context)
+	case post.ActionResolve:
+		uc.handleResolveAsync(ctx, a, fingerprint, username, input.PostID, input.ChannelID, resolutionFromContext(input.Context), input.Context)
+	case post.ActionUnacknowledge:
+		uc.handleUnacknowledgeAsync(ctx, a, fingerprint, username, input.PostID, input.ChannelID, input.Context)
+	case post.ActionSnooze:
+		uc.handleSnoozeAsync(ctx, a, fingerprint, username, input.PostID, input.ChannelID)
+	case post.ActionAssign:
+		assignee, err := uc.assignee(ctx, input.Context, username)
 		if err != nil {
-			uc.logger.Warn("Failed to get username, using user_id",
-				slog.String("user_id", input.UserID),
+			uc.logger.Error("Failed to resolve assignee in async phase",
+				slog.String("selected_option", input.Context[post.ContextKeySelectedOption]),
 				slog.String("error", err.Error()),
 			)
-			username = input.UserID
+			uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Unknown user")
+			return
 		}
+		uc.handleAssignAsync(ctx, a, fingerprint, username, assignee, input.PostID, input.ChannelID)
+	default:
+		uc.logger.Error("Unknown action in async phase",
+			slog.String("action", action),
+		)
+		uc.updatePostWithError(ctx, input.PostID, alertName, fingerprintStr, "Unknown action")
+	}
+}
 
-		statusStr := action
-		if action == post.ActionAcknowledge {
-			statusStr = alert.StatusAcknowledged
-		}
+// withLock runs fn under the fingerprint lock when locks are set.
+func (uc *HandleCallbackUseCase) withLock(ctx context.Context, fingerprint string, fn func(ctx context.Context) error) error {
+	if uc.locks == nil {
+		return fn(ctx)
+	}
+	return uc.locks.Do(ctx, fingerprint, fn)
+}
 
-		source := strings.Join(keepAlert.Source, ", ")
-
-		a := alert.RestoreAlert(
-			fingerprint,
-			keepAlert.Name,
-			severity,
-			alert.RestoreStatus(statusStr),
-			keepAlert.Description,
-			source,
-			keepAlert.Labels,
-			keepAlert.FiringStartTime,
-		)
+// ExecuteAction applies an acknowledge, resolve or unacknowledge action to a
+// tracked alert on behalf of userID, without going through an interactive
+// button. It runs synchronously and reports failures to the caller.
+func (uc *HandleCallbackUseCase) ExecuteAction(ctx context.Context, action, fingerprintStr, userID string) error {
+	switch action {
+	case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge, post.ActionSnooze:
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
 
-		switch action {
-		case post.ActionAcknowledge:
-			uc.handleAcknowledgeAsync(asyncCtx, a, fingerprint, username, input.PostID, input.ChannelID)
-		case post.ActionResolve:
-			uc.handleResolveAsync(asyncCtx, a, fingerprint, username, input.PostID, input.ChannelID)
-		case post.ActionUnacknowledge:
-			uc.handleUnacknowledgeAsync(asyncCtx, a, fingerprint, username, input.PostID, input.ChannelID)
-		default:
-			uc.logger.Error("Unknown action in async phase",
-				slog.String("action", action),
-			)
-			uc.updatePostWithError(asyncCtx, input.PostID, alertName, fingerprintStr, "Unknown action")
-		}
-	}()
+	fingerprint, err := alert.NewFingerprint(fingerprintStr)
+	if err != nil {
+		return fmt.Errorf("parse fingerprint: %w", err)
+	}
+
+	return uc.withLock(ctx, fingerprintStr, func(ctx context.Context) error {
+		return uc.executeAction(ctx, action, fingerprint, userID)
+	})
+}
+
+func (uc *HandleCallbackUseCase) executeAction(ctx context.Context, action string, fingerprint alert.Fingerprint, userID string) error {
+	existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		return fmt.Errorf("find post: %w", err)
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		return fmt.Errorf("get alert from keep: %w", err)
+	}
+
+	a, err := restoreCallbackAlert(keepAlert, fingerprint, action)
+	if err != nil {
+		return err
+	}
+	a = a.WithFireCount(existing.FireCount())
+
+	callbacksReceivedCounter(action).Inc()
+	if uc.permissions != nil && !uc.permissions.Allowed(ctx, userID, action, a.Severity().String()) {
+		callbacksDeniedCounter(action).Inc()
+		return ErrNotPermitted
+	}
+	username := uc.lookupUsername(ctx, userID)
+
+	switch action {
+	case post.ActionAcknowledge:
+		uc.handleAcknowledgeAsync(ctx, a, fingerprint, username, existing.PostID(), existing.ChannelID(), time.Time{}, nil)
+	case post.ActionResolve:
+		uc.handleResolveAsync(ctx, a, fingerprint, username, existing.PostID(), existing.ChannelID(), resolution{}, nil)
+	case post.ActionUnacknowledge:
+		uc.handleUnacknowledgeAsync(ctx, a, fingerprint, username, existing.PostID(), existing.ChannelID(), nil)
+	case post.ActionSnooze:
+		uc.handleSnoozeAsync(ctx, a, fingerprint, username, existing.PostID(), existing.ChannelID())
+	}
+	return nil
 }
 
-func (uc *HandleCallbackUseCase) updatePostWithError(ctx context.Context, postID, alertName, fingerprint, errorMsg string) {
+// restoreCallbackAlert rebuilds the alert from Keep data with the status the
+// action moves it to.
+func restoreCallbackAlert(keepAlert *port.KeepAlert, fingerprint alert.Fingerprint, action string) (*alert.Alert, error) {
+	severity, err := alert.NewSeverity(keepAlert.Severity)
+	if err != nil {
+		return nil, fmt.Errorf("parse severity: %w", err)
+	}
+
+	statusStr := action
+	switch action {
+	case post.ActionAcknowledge, post.ActionAcknowledgeFor:
+		statusStr = alert.StatusAcknowledged
+	case post.ActionSnooze, post.ActionAssign:
+		statusStr = alert.StatusFiring
+	}
+
+	return alert.RestoreAlert(
+		fingerprint,
+		keepAlert.Name,
+		severity,
+		alert.RestoreStatus(statusStr),
+		keepAlert.Description,
+		strings.Join(keepAlert.Source, ", "),
+		keepAlert.Labels,
+		keepAlert.FiringStartTime,
+	).WithEnrichments(keepAlert.Enrichments).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL), nil
+}
+
+func (uc *HandleCallbackUseCase) lookupUsername(ctx context.Context, userID string) string {
+	username, err := uc.mmClient.GetUser(ctx, userID)
+	if err != nil {
+		uc.logger.Warn("Failed to get username, using user_id",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+		return userID
+	}
+	return username
+}
+
+// updatePostWithError shows errorMsg on the post, followed by buttons.
+func (uc *HandleCallbackUseCase) updatePostWithError(ctx context.Context, postID, alertName, fingerprint, errorMsg string, buttons ...post.Button) {
+	if uc.watchdog != nil {
+		uc.watchdog.fail(ctx, errorMsg)
+	}
 	attachment := uc.msgBuilder.BuildErrorAttachment(alertName, fingerprint, uc.keepUIURL, errorMsg)
+	attachment.Actions = append(attachment.Actions, buttons...)
 	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
 		uc.logger.Error("Failed to update post with error state",
 			slog.String("post_id", postID),
@@ -197,7 +642,22 @@ func (uc *HandleCallbackUseCase) updatePostWithError(ctx context.Context, postID
 	}
 }
 
-func (uc *HandleCallbackUseCase) enrichAssignee(ctx context.Context, fingerprint, mattermostUsername string) {
+// assignee returns the Mattermost username picked in the Assign menu:
+// username for "Assign to me", the looked-up user for the Mattermost user
+// picker, and the listed username otherwise.
+func (uc *HandleCallbackUseCase) assignee(ctx context.Context, callbackContext map[string]string, username string) (string, error) {
+	selected := callbackContext[post.ContextKeySelectedOption]
+	switch {
+	case selected == post.AssignToMe:
+		return username, nil
+	case callbackContext[post.ContextKeyDataSource] == post.DataSourceUsers:
+		return uc.mmClient.GetUser(ctx, selected)
+	default:
+		return selected, nil
+	}
+}
+
+func (uc *HandleCallbackUseCase) enrichAssignee(ctx context.Context, fingerprint, mattermostUsername string) error {
 	var keepUser string
 	if mappedUser, ok := uc.userMapper.GetKeepUsername(mattermostUsername); ok && mappedUser != "" {
 		keepUser = mappedUser
@@ -220,26 +680,43 @@ func (uc *HandleCallbackUseCase) enrichAssignee(ctx context.Context, fingerprint
 			slog.String("fingerprint", fingerprint),
 			slog.String("error", err.Error()),
 		)
+		return err
 	}
+	return nil
 }
 
-func (uc *HandleCallbackUseCase) handleAcknowledgeAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string) {
+// sendStatus sends the assignee and the status enrichments of an acknowledge
+// or resolve to Keep.
+func (uc *HandleCallbackUseCase) sendStatus(ctx context.Context, fingerprint, username string, statusEnrichment map[string]string) error {
 	// IMPORTANT: Set assignee BEFORE status to avoid race condition.
 	// Status change triggers Keep webhook, and assignee must be set by then.
-	uc.enrichAssignee(ctx, fingerprint.Value(), username)
+	assigneeErr := uc.enrichAssignee(ctx, fingerprint, username)
 
 	// Status enrichment auto-clears when alert re-fires from provider (DisposeOnNewAlert=true)
 	// This ensures resolved alerts from provider override acknowledged status
-	statusEnrichment := map[string]string{EnrichmentKeyStatus: "acknowledged"}
-	if err := uc.keepClient.EnrichAlert(ctx, fingerprint.Value(), statusEnrichment, port.EnrichOptions{DisposeOnNewAlert: true}); err != nil {
-		// Log error but continue - Mattermost UI update should proceed even if Keep enrichment fails
+	if err := uc.keepClient.EnrichAlert(ctx, fingerprint, statusEnrichment, port.EnrichOptions{DisposeOnNewAlert: true}); err != nil {
 		uc.logger.Error("Failed to enrich status in Keep",
-			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("fingerprint", fingerprint),
 			slog.String("error", err.Error()),
 		)
+		return errors.Join(assigneeErr, err)
 	}
+	return assigneeErr
+}
 
-	attachment := uc.msgBuilder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, username)
+// handleAcknowledgeAsync acknowledges the alert. A non-zero until limits the
+// acknowledgement: the alert fires again when it passes unresolved. retry is
+// the context of the clicked button, offered again when Keep fails.
+func (uc *HandleCallbackUseCase) handleAcknowledgeAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string, until time.Time, retry map[string]string) {
+	// Mattermost UI update should proceed even if Keep enrichment fails
+	statusEnrichment := map[string]string{EnrichmentKeyStatus: "acknowledged"}
+	keepErr := uc.sendStatus(ctx, fingerprint.Value(), username, statusEnrichment)
+
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, username)
+	if !until.IsZero() {
+		attachment = withAckDeadline(attachment, username, until)
+	}
+	attachment = uc.withRetry(attachment, keepErr, retry)
 
 	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
 		uc.logger.Error("Failed to update post",
@@ -247,15 +724,25 @@ func (uc *HandleCallbackUseCase) handleAcknowledgeAsync(ctx context.Context, a *
 			slog.String("error", err.Error()),
 		)
 	}
+	uc.recordShownStatus(ctx, fingerprint, alert.StatusAcknowledged, username, until)
 
-	replyMsg := fmt.Sprintf("Acknowledged by @%s", username)
-	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
-		uc.logger.Error("Failed to reply to thread",
-			slog.String("post_id", postID),
-			slog.String("error", err.Error()),
-		)
+	if uc.timeline != nil {
+		uc.timeline.Append(ctx, fingerprint, channelID, postID, alert.StatusAcknowledged, username)
+	} else {
+		replyMsg := fmt.Sprintf("Acknowledged by @%s", username)
+		if !until.IsZero() {
+			replyMsg += " until " + until.UTC().Format("2006-01-02 15:04 UTC")
+		}
+		if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", postID),
+				slog.String("error", err.Error()),
+			)
+		}
 	}
 
+	uc.trackKeepAction(ctx, keepErr, a, post.ActionAcknowledge, username, statusEnrichment, postID, channelID)
+
 	uc.logger.Info("Callback processed (async)",
 		logger.ApplicationFields("callback_processed_async",
 			slog.String("action", "acknowledge"),
@@ -264,24 +751,93 @@ func (uc *HandleCallbackUseCase) handleAcknowledgeAsync(ctx context.Context, a *
 		),
 	)
 	alertAckCounter.Inc()
+	detail := "in Mattermost"
+	if !until.IsZero() {
+		detail += ", until " + until.UTC().Format(time.RFC3339)
+	}
+	uc.audit.Record(ctx, fingerprint, audit.KindAcknowledged, username, detail)
 }
 
-func (uc *HandleCallbackUseCase) handleResolveAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string) {
-	// IMPORTANT: Set assignee BEFORE status to avoid race condition.
-	// Status change triggers Keep webhook, and assignee must be set by then.
-	uc.enrichAssignee(ctx, fingerprint.Value(), username)
+func (uc *HandleCallbackUseCase) handleResolveAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string, res resolution, retry map[string]string) {
+	// Mattermost UI update should proceed even if Keep enrichment fails
+	statusEnrichment := res.enrichments()
+	statusEnrichment[EnrichmentKeyStatus] = "resolved"
+	keepErr := uc.sendStatus(ctx, fingerprint.Value(), username, statusEnrichment)
 
-	// Status enrichment for manual resolve (DisposeOnNewAlert=true for consistency)
-	statusEnrichment := map[string]string{EnrichmentKeyStatus: "resolved"}
-	if err := uc.keepClient.EnrichAlert(ctx, fingerprint.Value(), statusEnrichment, port.EnrichOptions{DisposeOnNewAlert: true}); err != nil {
-		// Log error but continue - Mattermost UI update should proceed even if Keep enrichment fails
-		uc.logger.Error("Failed to enrich status in Keep",
+	attachment := withResolution(uc.msgBuilder.ForChannel(channelID).BuildResolvedAttachment(a, uc.keepUIURL, username), res)
+	attachment = uc.withRetry(attachment, keepErr, retry)
+
+	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
+		uc.logger.Error("Failed to update post",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+	} else if uc.retention != nil {
+		if err := uc.retention.Schedule(ctx, postID, channelID, a, attachment); err != nil {
+			uc.logger.Warn("Failed to schedule post retention",
+				slog.String("post_id", postID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	if uc.timeline != nil {
+		uc.timeline.Append(ctx, fingerprint, channelID, postID, alert.StatusResolved, username)
+	} else {
+		replyMsg := fmt.Sprintf("Resolved by @%s", username)
+		if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", postID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	if existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); err == nil {
+		observeResolved(existing, uc.clock.Now())
+	}
+	if err := uc.postRepo.Delete(ctx, fingerprint); err != nil {
+		uc.logger.Error("Failed to delete post from store",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	uc.trackKeepAction(ctx, keepErr, a, post.ActionResolve, username, statusEnrichment, postID, channelID)
+
+	uc.logger.Info("Callback processed (async)",
+		logger.ApplicationFields("callback_processed_async",
+			slog.String("action", "resolve"),
 			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("username", username),
+		),
+	)
+	alertResolveCounter.Inc()
+	detail := "in Mattermost"
+	if summary := res.summary(); summary != "" {
+		detail += ": " + summary
+	}
+	uc.audit.Record(ctx, fingerprint, audit.KindResolved, username, detail)
+}
+
+// sendUnacknowledge removes the status and assignee enrichments in Keep.
+func (uc *HandleCallbackUseCase) sendUnacknowledge(ctx context.Context, fingerprint string) error {
+	enrichmentsToRemove := []string{EnrichmentKeyStatus, EnrichmentKeyAssignee}
+	if err := uc.keepClient.UnenrichAlert(ctx, fingerprint, enrichmentsToRemove); err != nil {
+		uc.logger.Error("Failed to unenrich alert in Keep",
+			slog.String("fingerprint", fingerprint),
 			slog.String("error", err.Error()),
 		)
+		return err
 	}
+	return nil
+}
 
-	attachment := uc.msgBuilder.BuildResolvedAttachment(a, uc.keepUIURL, username)
+func (uc *HandleCallbackUseCase) handleUnacknowledgeAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string, retry map[string]string) {
+	keepErr := uc.sendUnacknowledge(ctx, fingerprint.Value())
+
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	attachment = uc.withRetry(attachment, keepErr, retry)
 
 	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
 		uc.logger.Error("Failed to update post",
@@ -289,42 +845,156 @@ func (uc *HandleCallbackUseCase) handleResolveAsync(ctx context.Context, a *aler
 			slog.String("error", err.Error()),
 		)
 	}
+	uc.recordShownStatus(ctx, fingerprint, alert.StatusFiring, "", time.Time{})
 
-	replyMsg := fmt.Sprintf("Resolved by @%s", username)
-	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
-		uc.logger.Error("Failed to reply to thread",
-			slog.String("post_id", postID),
+	if uc.timeline != nil {
+		uc.timeline.Append(ctx, fingerprint, channelID, postID, alert.StatusFiring, username)
+	} else {
+		replyMsg := fmt.Sprintf("Unacknowledged by @%s", username)
+		if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", postID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	uc.trackKeepAction(ctx, keepErr, a, post.ActionUnacknowledge, username, nil, postID, channelID)
+
+	uc.logger.Info("Callback processed (async)",
+		logger.ApplicationFields("callback_processed_async",
+			slog.String("action", "unacknowledge"),
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("username", username),
+		),
+	)
+	alertUnackCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindUnacknowledged, username, "in Mattermost")
+}
+
+// recordShownStatus records the status and assignee the alert's post now
+// shows, so the poller does not take them for changes made in Keep, and the
+// deadline of the acknowledgement, if any.
+func (uc *HandleCallbackUseCase) recordShownStatus(ctx context.Context, fingerprint alert.Fingerprint, status, assignee string, ackUntil time.Time) {
+	existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		if !errors.Is(err, post.ErrNotFound) {
+			uc.logger.Warn("Failed to find post to record its status",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+	existing.ShowStatus(status)
+	existing.SetLastKnownAssignee(assignee)
+	if ackUntil.IsZero() {
+		existing.ClearAckTimer()
+	} else {
+		existing.AcknowledgeUntil(ackUntil, assignee)
+	}
+	if status == alert.StatusAcknowledged {
+		observeAcknowledged(existing, uc.clock.Now())
+	}
+	existing.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, existing); err != nil {
+		uc.logger.Warn("Failed to record post status",
+			slog.String("fingerprint", fingerprint.Value()),
 			slog.String("error", err.Error()),
 		)
 	}
+}
 
-	if err := uc.postRepo.Delete(ctx, fingerprint); err != nil {
-		uc.logger.Error("Failed to delete post from store",
+// handleAssignAsync sets the assignee in Keep and leaves the alert firing;
+// unlike acknowledging it sends no status enrichment.
+func (uc *HandleCallbackUseCase) handleAssignAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, assignee, postID, channelID string) {
+	if err := uc.enrichAssignee(ctx, fingerprint.Value(), assignee); err != nil {
+		uc.updatePostWithError(ctx, postID, a.Name(), fingerprint.Value(), "Failed to assign")
+		return
+	}
+
+	// The poller would otherwise take the new assignee for a change made in
+	// the Keep UI.
+	if existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); err != nil {
+		uc.logger.Warn("Failed to find post for assignee",
 			slog.String("fingerprint", fingerprint.Value()),
 			slog.String("error", err.Error()),
 		)
+	} else {
+		existing.SetLastKnownAssignee(assignee)
+		existing.Touch(uc.clock.Now())
+		if err := uc.postRepo.Save(ctx, fingerprint, existing); err != nil {
+			uc.logger.Warn("Failed to save assignee",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildAssignedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+
+	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
+		uc.logger.Error("Failed to update post",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	replyMsg := fmt.Sprintf("Assigned to @%s by @%s", assignee, username)
+	if assignee == username {
+		replyMsg = fmt.Sprintf("Assigned to @%s", assignee)
+	}
+	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
+		uc.logger.Error("Failed to reply to thread",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
 	}
 
 	uc.logger.Info("Callback processed (async)",
 		logger.ApplicationFields("callback_processed_async",
-			slog.String("action", "resolve"),
+			slog.String("action", "assign"),
 			slog.String("fingerprint", fingerprint.Value()),
 			slog.String("username", username),
+			slog.String("assignee", assignee),
 		),
 	)
-	alertResolveCounter.Inc()
+	alertAssignCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindAssigned, username, "to @"+assignee)
 }
 
-func (uc *HandleCallbackUseCase) handleUnacknowledgeAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string) {
-	enrichmentsToRemove := []string{EnrichmentKeyStatus, EnrichmentKeyAssignee}
-	if err := uc.keepClient.UnenrichAlert(ctx, fingerprint.Value(), enrichmentsToRemove); err != nil {
-		uc.logger.Error("Failed to unenrich alert in Keep",
+func (uc *HandleCallbackUseCase) handleSnoozeAsync(ctx context.Context, a *alert.Alert, fingerprint alert.Fingerprint, username, postID, channelID string) {
+	if uc.snoozeFor <= 0 {
+		uc.logger.Error("Snooze requested but snoozing is disabled",
+			slog.String("fingerprint", fingerprint.Value()),
+		)
+		uc.updatePostWithError(ctx, postID, a.Name(), fingerprint.Value(), "Snooze is disabled")
+		return
+	}
+
+	existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		uc.logger.Error("Failed to find post for snooze",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("error", err.Error()),
+		)
+		uc.updatePostWithError(ctx, postID, a.Name(), fingerprint.Value(), "Alert is no longer tracked")
+		return
+	}
+
+	until := uc.clock.Now().Add(uc.snoozeFor)
+	existing.Snooze(until)
+	existing.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, existing); err != nil {
+		uc.logger.Error("Failed to save snoozed post",
 			slog.String("fingerprint", fingerprint.Value()),
 			slog.String("error", err.Error()),
 		)
+		uc.updatePostWithError(ctx, postID, a.Name(), fingerprint.Value(), "Failed to snooze")
+		return
 	}
 
-	attachment := uc.msgBuilder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	attachment := uc.msgBuilder.ForChannel(channelID).BuildSnoozedAttachment(a, uc.callbackURL, uc.keepUIURL, username, until)
 
 	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
 		uc.logger.Error("Failed to update post",
@@ -333,7 +1003,7 @@ func (uc *HandleCallbackUseCase) handleUnacknowledgeAsync(ctx context.Context, a
 		)
 	}
 
-	replyMsg := fmt.Sprintf("Unacknowledged by @%s", username)
+	replyMsg := fmt.Sprintf("Snoozed by @%s until %s", username, until.UTC().Format("2006-01-02 15:04 UTC"))
 	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, replyMsg); err != nil {
 		uc.logger.Error("Failed to reply to thread",
 			slog.String("post_id", postID),
@@ -343,12 +1013,123 @@ func (uc *HandleCallbackUseCase) handleUnacknowledgeAsync(ctx context.Context, a
 
 	uc.logger.Info("Callback processed (async)",
 		logger.ApplicationFields("callback_processed_async",
-			slog.String("action", "unacknowledge"),
+			slog.String("action", "snooze"),
 			slog.String("fingerprint", fingerprint.Value()),
 			slog.String("username", username),
+			slog.Time("snoozed_until", until),
 		),
 	)
-	alertUnackCounter.Inc()
+	alertSnoozeCounter.Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindSnoozed, username, "until "+until.UTC().Format(time.RFC3339))
+}
+
+// handleCustomActionAsync runs a custom action for the alert as Keep has it
+// now and reports the outcome in the post's thread. The post itself is left
+// alone, whether or not the action succeeds.
+func (uc *HandleCallbackUseCase) handleCustomActionAsync(ctx context.Context, input dto.MattermostCallbackInput, fingerprintStr string) {
+	name := input.Context[post.ContextKeyCustomAction]
+	username := uc.lookupUsername(ctx, input.UserID)
+	reply := func(msg string) {
+		if err := uc.mmClient.ReplyToThread(ctx, input.ChannelID, input.PostID, msg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", input.PostID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	fingerprint, err := alert.NewFingerprint(fingerprintStr)
+	if err != nil {
+		uc.logger.Error("Failed to parse fingerprint for custom action",
+			slog.String("fingerprint", fingerprintStr),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	a, err := uc.currentAlert(ctx, fingerprint)
+	if err == nil {
+		var detail string
+		detail, err = uc.custom.Run(ctx, name, a, username)
+		if err == nil {
+			msg := fmt.Sprintf("▶️ @%s ran **%s**", username, name)
+			if detail != "" {
+				msg += fmt.Sprintf(" (%s)", detail)
+			}
+			reply(msg)
+			uc.audit.Record(ctx, fingerprint, audit.KindCustomAction, username, strings.TrimSpace(name+" "+detail))
+			return
+		}
+	}
+
+	uc.logger.Error("Custom action failed",
+		slog.String("action", name),
+		slog.String("fingerprint", fingerprintStr),
+		slog.String("error", err.Error()),
+	)
+	reply(fmt.Sprintf("⚠️ **%s** failed for @%s, see the bridge logs", name, username))
+}
+
+// handleRunWorkflowAsync starts the workflow picked from the Run workflow
+// menu for the alert as Keep has it now and says so in the post's thread. The
+// workflow menu replies again once the run ends.
+func (uc *HandleCallbackUseCase) handleRunWorkflowAsync(ctx context.Context, input dto.MattermostCallbackInput, fingerprintStr string) {
+	username := uc.lookupUsername(ctx, input.UserID)
+	reply := func(msg string) {
+		if err := uc.mmClient.ReplyToThread(ctx, input.ChannelID, input.PostID, msg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", input.PostID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	fingerprint, err := alert.NewFingerprint(fingerprintStr)
+	if err != nil {
+		uc.logger.Error("Failed to parse fingerprint for workflow run",
+			slog.String("fingerprint", fingerprintStr),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	workflow, ok := uc.workflows.Workflow(input.Context[post.ContextKeySelectedOption])
+	if !ok {
+		reply(fmt.Sprintf("⚠️ The workflow picked by @%s is no longer available", username))
+		return
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err == nil {
+		var executionID string
+		executionID, err = uc.workflows.Run(ctx, workflow, *keepAlert, input.ChannelID, input.PostID, username)
+		if err == nil {
+			reply(fmt.Sprintf("▶️ @%s started workflow **%s** (execution %s)", username, workflow.Name, executionID))
+			uc.audit.Record(ctx, fingerprint, audit.KindWorkflowRun, username, fmt.Sprintf("%s, execution %s", workflow.Name, executionID))
+			return
+		}
+	}
+
+	uc.logger.Error("Workflow run failed",
+		slog.String("workflow_id", workflow.ID),
+		slog.String("fingerprint", fingerprintStr),
+		slog.String("error", err.Error()),
+	)
+	reply(fmt.Sprintf("⚠️ Workflow **%s** could not be started for @%s, see the bridge logs", workflow.Name, username))
+}
+
+// currentAlert returns the alert as Keep has it now.
+func (uc *HandleCallbackUseCase) currentAlert(ctx context.Context, fingerprint alert.Fingerprint) (*alert.Alert, error) {
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		return nil, fmt.Errorf("get alert from keep: %w", err)
+	}
+	// restoreCallbackAlert keeps statuses that are not button actions.
+	status := keepAlert.Status
+	if status == "" {
+		status = alert.StatusFiring
+	}
+	return restoreCallbackAlert(keepAlert, fingerprint, status)
 }
 
 func (uc *HandleCallbackUseCase) Wait() {
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"log/slog"
 	"os"
-	"sync"
 	"testing"
 	"time"
 
@@ -14,312 +13,19 @@ import (
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 )
 
-type enrichCall struct {
-	Fingerprint       string
-	Enrichments       map[string]string
-	DisposeOnNewAlert bool
-}
-
-type mockKeepClient struct {
-	enrichAlertErr            error
-	enrichAlertErrOnFirstCall bool // return error only on first EnrichAlert call
-	enrichAlertCalled         bool
-	enrichedEnrichments       map[string]string
-	enrichedFingerprint       string
-	enrichCalls               []enrichCall
-	unenrichAlertErr          error
-	unenrichAlertCalled       bool
-	unenrichFingerprint       string
-	unenrichedEnrichments     []string
-	getAlertErr               error
-	getAlertResponse          *port.KeepAlert
-	providers                 []port.KeepProvider
-	workflows                 []port.KeepWorkflow
-	getProvidersErr           error
-	createWebhookErr          error
-	getWorkflowsErr           error
-	createWorkflowErr         error
-	createWebhookCalled       bool
-	createWorkflowCalled      bool
-	createdWebhookConfig      port.WebhookProviderConfig
-	createdWorkflowConfig     port.WorkflowConfig
-	mu                        sync.Mutex
-}
-
-func newMockKeepClient() *mockKeepClient {
-	return &mockKeepClient{
-		getAlertResponse: &port.KeepAlert{
-			Fingerprint:     "fp-12345",
-			Name:            "Test Alert",
-			Status:          "firing",
-			Severity:        "high",
-			Description:     "Test description",
-			Source:          []string{"prometheus"},
-			Labels:          map[string]string{"env": "test"},
-			FiringStartTime: time.Time{},
-		},
-	}
-}
-
-func (m *mockKeepClient) EnrichAlert(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.enrichAlertCalled = true
-	m.enrichedFingerprint = fingerprint
-	if m.enrichedEnrichments == nil {
-		m.enrichedEnrichments = make(map[string]string)
-	}
-	for k, v := range enrichments {
-		m.enrichedEnrichments[k] = v
-	}
-	// Track each call with its options
-	enrichmentsCopy := make(map[string]string)
-	for k, v := range enrichments {
-		enrichmentsCopy[k] = v
-	}
-	m.enrichCalls = append(m.enrichCalls, enrichCall{
-		Fingerprint:       fingerprint,
-		Enrichments:       enrichmentsCopy,
-		DisposeOnNewAlert: opts.DisposeOnNewAlert,
-	})
-	if m.enrichAlertErr != nil {
-		// If enrichAlertErrOnFirstCall is set, only return error on first call
-		if m.enrichAlertErrOnFirstCall && len(m.enrichCalls) > 1 {
-			return nil
-		}
-		return m.enrichAlertErr
-	}
-	return nil
-}
-
-func (m *mockKeepClient) wasEnrichAlertCalled() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.enrichAlertCalled
-}
-
-func (m *mockKeepClient) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
-	if m.getAlertErr != nil {
-		return nil, m.getAlertErr
-	}
-	resp := *m.getAlertResponse
-	resp.Fingerprint = fingerprint
-	return &resp, nil
-}
-
-func (m *mockKeepClient) UnenrichAlert(ctx context.Context, fingerprint string, enrichments []string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.unenrichAlertCalled = true
-	m.unenrichFingerprint = fingerprint
-	m.unenrichedEnrichments = enrichments
-	if m.unenrichAlertErr != nil {
-		return m.unenrichAlertErr
-	}
-	return nil
-}
-
-func (m *mockKeepClient) getUnenrichedEnrichments() []string {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.unenrichedEnrichments
-}
-
-func (m *mockKeepClient) wasUnenrichAlertCalled() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.unenrichAlertCalled
-}
-
-func (m *mockKeepClient) GetProviders(ctx context.Context) ([]port.KeepProvider, error) {
-	if m.getProvidersErr != nil {
-		return nil, m.getProvidersErr
-	}
-	return m.providers, nil
-}
-
-func (m *mockKeepClient) CreateWebhookProvider(ctx context.Context, config port.WebhookProviderConfig) error {
-	m.createWebhookCalled = true
-	m.createdWebhookConfig = config
-	if m.createWebhookErr != nil {
-		return m.createWebhookErr
-	}
-	m.providers = append(m.providers, port.KeepProvider{
-		ID:   "created-provider-id",
-		Type: "webhook",
-		Name: config.Name,
-	})
-	return nil
-}
-
-func (m *mockKeepClient) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error) {
-	if m.getWorkflowsErr != nil {
-		return nil, m.getWorkflowsErr
-	}
-	return m.workflows, nil
-}
-
-func (m *mockKeepClient) CreateWorkflow(ctx context.Context, config port.WorkflowConfig) error {
-	m.createWorkflowCalled = true
-	m.createdWorkflowConfig = config
-	return m.createWorkflowErr
-}
-
-func (m *mockKeepClient) GetAlerts(ctx context.Context, limit int) ([]port.KeepAlert, error) {
-	return nil, nil
-}
-
-type mockUserMapper struct {
-	mapping map[string]string
-}
-
-func newMockUserMapper() *mockUserMapper {
-	return &mockUserMapper{
-		mapping: make(map[string]string),
-	}
-}
-
-func (m *mockUserMapper) GetKeepUsername(mattermostUsername string) (string, bool) {
-	keepUser, ok := m.mapping[mattermostUsername]
-	return keepUser, ok
-}
-
-func (m *mockUserMapper) GetMattermostUsername(keepUsername string) (string, bool) {
-	for mmUser, keepUser := range m.mapping {
-		if keepUser == keepUsername {
-			return mmUser, true
-		}
-	}
-	return "", false
-}
-
-type mockMattermostClientCallback struct {
-	getUserErr         error
-	getUserFunc        func(ctx context.Context, userID string) (string, error)
-	getUserCalled      bool
-	updatePostCalled   bool
-	updatePostErr      error
-	replyToThreadErr   error
-	replyToThreadCalls []string
-	mu                 sync.Mutex
-}
-
-func newMockMattermostClientCallback() *mockMattermostClientCallback {
-	return &mockMattermostClientCallback{
-		replyToThreadCalls: make([]string, 0),
-	}
-}
-
-func (m *mockMattermostClientCallback) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
-	return "post-123", nil
-}
-
-func (m *mockMattermostClientCallback) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
-	m.mu.Lock()
-	m.updatePostCalled = true
-	m.mu.Unlock()
-	return m.updatePostErr
-}
-
-func (m *mockMattermostClientCallback) wasUpdatePostCalled() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.updatePostCalled
-}
-
-func (m *mockMattermostClientCallback) GetUser(ctx context.Context, userID string) (string, error) {
-	m.getUserCalled = true
-	if m.getUserFunc != nil {
-		return m.getUserFunc(ctx, userID)
-	}
-	if m.getUserErr != nil {
-		return "", m.getUserErr
-	}
-	return "testuser", nil
-}
-
-func (m *mockMattermostClientCallback) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
-	m.mu.Lock()
-	m.replyToThreadCalls = append(m.replyToThreadCalls, message)
-	m.mu.Unlock()
-	return m.replyToThreadErr
-}
-
-func (m *mockMattermostClientCallback) getReplyToThreadCalls() []string {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.replyToThreadCalls
-}
-
-type mockMessageBuilderCallback struct{}
-
-func (m *mockMessageBuilderCallback) BuildFiringAttachment(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#FF0000",
-		Title: "FIRING: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildAcknowledgedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string) post.Attachment {
-	return post.Attachment{
-		Color: "#FFA500",
-		Title: "ACKNOWLEDGED: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildResolvedAttachment(a *alert.Alert, keepUIURL, acknowledgedBy string) post.Attachment {
-	return post.Attachment{
-		Color: "#00CC00",
-		Title: "RESOLVED: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildSuppressedAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#9370DB",
-		Title: "SUPPRESSED: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildPendingAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#87CEEB",
-		Title: "PENDING: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildMaintenanceAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{
-		Color: "#708090",
-		Title: "MAINTENANCE: " + a.Name(),
-	}
-}
-
-func (m *mockMessageBuilderCallback) BuildProcessingAttachment(attachmentJSON, action string) (post.Attachment, error) {
-	return post.Attachment{
-		Color: "#808080",
-		Title: "Processing Alert",
-	}, nil
-}
-
-func (m *mockMessageBuilderCallback) BuildErrorAttachment(alertName, fingerprint, keepUIURL, errorMsg string) post.Attachment {
-	return post.Attachment{
-		Color: "#FF0000",
-		Title: alertName,
-		Text:  "Error: " + errorMsg,
-	}
-}
-
-func setupHandleCallbackUseCase() (*HandleCallbackUseCase, *mockPostRepository, *mockKeepClient, *mockMattermostClientCallback, *mockUserMapper) {
-	postRepo := newMockPostRepository()
-	keepClient := newMockKeepClient()
-	mmClient := newMockMattermostClientCallback()
-	msgBuilder := &mockMessageBuilderCallback{}
-	userMapper := newMockUserMapper()
+func setupHandleCallbackUseCase() (*HandleCallbackUseCase, *postStore, *portmock.KeepClientMock, *portmock.MattermostClientMock, *userMap) {
+	postRepo := newPostStore()
+	keepClient := newKeepClientMock()
+	keepClient.GetAlertFunc = keepAlertFor("high")
+	mmClient := newMattermostClientMock()
+	msgBuilder := newMessageBuilderMock()
+	userMapper := newUserMap()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	uc := NewHandleCallbackUseCase(
@@ -336,6 +42,22 @@ func setupHandleCallbackUseCase() (*HandleCallbackUseCase, *mockPostRepository,
 	return uc, postRepo, keepClient, mmClient, userMapper
 }
 
+// keepAlertFor answers GetAlert with the test alert at the given severity,
+// under whichever fingerprint was asked for.
+func keepAlertFor(severity string) func(context.Context, string) (*port.KeepAlert, error) {
+	return func(_ context.Context, fingerprint string) (*port.KeepAlert, error) {
+		return &port.KeepAlert{
+			Fingerprint: fingerprint,
+			Name:        "Test Alert",
+			Status:      "firing",
+			Severity:    severity,
+			Description: "Test description",
+			Source:      []string{"prometheus"},
+			Labels:      map[string]string{"env": "test"},
+		}, nil
+	}
+}
+
 func TestHandleCallbackUseCase_ExecuteImmediate_ReturnsLoadingState(t *testing.T) {
 	uc, _, _, _, _ := setupHandleCallbackUseCase()
 
@@ -376,6 +98,31 @@ func TestHandleCallbackUseCase_ExecuteImmediate_MissingFingerprint(t *testing.T)
 	assert.Contains(t, err.Error(), "parse fingerprint")
 }
 
+func TestHandleCallbackUseCase_ExecuteImmediate_Commands(t *testing.T) {
+	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+
+	input := dto.MattermostCallbackInput{
+		UserID: "user-123",
+		PostID: "post-456",
+		Context: map[string]string{
+			post.ContextKeyAction:      post.ActionCommands,
+			post.ContextKeyFingerprint: "fp-12345",
+			post.ContextKeyCommands:    "**kubectl**",
+		},
+	}
+
+	result, err := uc.ExecuteImmediate(input)
+	require.NoError(t, err)
+	assert.Equal(t, "**kubectl**", result.Ephemeral)
+	assert.Empty(t, keepClient.EnrichAlertCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
+
+	delete(input.Context, post.ContextKeyCommands)
+	_, err = uc.ExecuteImmediate(input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "commands")
+}
+
 func TestHandleCallbackUseCase_ExecuteAsync_Acknowledge(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 	input := dto.MattermostCallbackInput{
@@ -393,11 +140,11 @@ func TestHandleCallbackUseCase_ExecuteAsync_Acknowledge(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.Equal(t, "fp-12345", keepClient.enrichedFingerprint)
-	assert.Equal(t, "acknowledged", keepClient.enrichedEnrichments["status"])
-	assert.True(t, mmClient.wasUpdatePostCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.Equal(t, "fp-12345", lastCall(keepClient.EnrichAlertCalls()).Fingerprint)
+	assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
 	require.Len(t, replies, 1)
 	assert.Contains(t, replies[0], "Acknowledged by @testuser")
 }
@@ -406,7 +153,7 @@ func TestHandleCallbackUseCase_ExecuteAsync_Resolve(t *testing.T) {
 	uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
 	fp, _ := alert.NewFingerprint("fp-12345")
-	existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = existingPost
 
 	input := dto.MattermostCallbackInput{
@@ -424,11 +171,11 @@ func TestHandleCallbackUseCase_ExecuteAsync_Resolve(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.Equal(t, "resolved", keepClient.enrichedEnrichments["status"])
-	assert.True(t, postRepo.deleteCalled)
-	assert.True(t, mmClient.wasUpdatePostCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.Equal(t, "resolved", enrichedWith(keepClient)["status"])
+	assert.NotEmpty(t, postRepo.DeleteCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
 	require.Len(t, replies, 1)
 	assert.Contains(t, replies[0], "Resolved by @testuser")
 }
@@ -450,11 +197,11 @@ func TestHandleCallbackUseCase_ExecuteAsync_Unacknowledge(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasUnenrichAlertCalled())
-	assert.Equal(t, "fp-12345", keepClient.unenrichFingerprint)
-	assert.ElementsMatch(t, []string{EnrichmentKeyStatus, EnrichmentKeyAssignee}, keepClient.getUnenrichedEnrichments())
-	assert.True(t, mmClient.wasUpdatePostCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.UnenrichAlertCalls())
+	assert.Equal(t, "fp-12345", lastCall(keepClient.UnenrichAlertCalls()).Fingerprint)
+	assert.ElementsMatch(t, []string{EnrichmentKeyStatus, EnrichmentKeyAssignee}, lastCall(keepClient.UnenrichAlertCalls()).Enrichments)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
 	require.Len(t, replies, 1)
 	assert.Contains(t, replies[0], "Unacknowledged by @testuser")
 }
@@ -462,7 +209,7 @@ func TestHandleCallbackUseCase_ExecuteAsync_Unacknowledge(t *testing.T) {
 func TestHandleCallbackUseCase_ExecuteAsync_GetAlertError(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	keepClient.getAlertErr = errors.New("keep api error")
+	keepClient.GetAlertFunc = keepAlertError(errors.New("keep api error"))
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -478,14 +225,14 @@ func TestHandleCallbackUseCase_ExecuteAsync_GetAlertError(t *testing.T) {
 
 	uc.ExecuteAsync(input)
 
-	assert.False(t, keepClient.wasEnrichAlertCalled())
-	assert.False(t, mmClient.wasUpdatePostCalled())
+	assert.Empty(t, keepClient.EnrichAlertCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_InvalidSeverity(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	keepClient.getAlertResponse.Severity = "invalid"
+	keepClient.GetAlertFunc = keepAlertFor("invalid")
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -501,14 +248,14 @@ func TestHandleCallbackUseCase_ExecuteAsync_InvalidSeverity(t *testing.T) {
 
 	uc.ExecuteAsync(input)
 
-	assert.False(t, keepClient.wasEnrichAlertCalled())
-	assert.False(t, mmClient.wasUpdatePostCalled())
+	assert.Empty(t, keepClient.EnrichAlertCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_GetUserError(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	mmClient.getUserErr = errors.New("user not found")
+	mmClient.GetUserFunc = func(context.Context, string) (string, error) { return "", errors.New("user not found") }
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -525,16 +272,55 @@ func TestHandleCallbackUseCase_ExecuteAsync_GetUserError(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	replies := replyMessages(mmClient)
 	require.Len(t, replies, 1)
 	assert.Contains(t, replies[0], "user-123")
 }
 
+type prefixServerIDs map[string]string
+
+func (ids prefixServerIDs) QualifyID(channelID, id string) string {
+	if server, ok := ids[channelID]; ok {
+		return server + ":" + id
+	}
+	return id
+}
+
+func TestHandleCallbackUseCase_ExecuteAsync_QualifiesServerIDs(t *testing.T) {
+	uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+	uc.SetMattermostServers(prefixServerIDs{"customer-alerts": "customer"})
+
+	var lookedUp string
+	mmClient.GetUserFunc = func(ctx context.Context, userID string) (string, error) {
+		lookedUp = userID
+		return "", errors.New("user not found")
+	}
+
+	input := dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "customer-alerts",
+		Context: map[string]string{
+			"action":          "acknowledge",
+			"fingerprint":     "fp-12345",
+			"alert_name":      "Test Alert",
+			"attachment_json": `{"Color":"#808080","Title":"Test Alert","TitleLink":"","Text":"","Fields":null,"Actions":null,"Footer":"","FooterIcon":""}`,
+		},
+	}
+
+	uc.ExecuteAsync(input)
+	uc.Wait()
+
+	assert.Equal(t, "customer:user-123", lookedUp)
+}
+
 func TestHandleCallbackUseCase_ExecuteAsync_EnrichAPIError(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	keepClient.enrichAlertErr = errors.New("keep api error")
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+		return errors.New("keep api error")
+	}
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -551,8 +337,8 @@ func TestHandleCallbackUseCase_ExecuteAsync_EnrichAPIError(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.True(t, mmClient.wasUpdatePostCalled())
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_UnknownAction(t *testing.T) {
@@ -571,8 +357,8 @@ func TestHandleCallbackUseCase_ExecuteAsync_UnknownAction(t *testing.T) {
 
 	uc.ExecuteAsync(input)
 
-	assert.False(t, keepClient.wasEnrichAlertCalled())
-	assert.False(t, mmClient.wasUpdatePostCalled())
+	assert.Empty(t, keepClient.EnrichAlertCalls())
+	assert.Empty(t, mmClient.UpdatePostCalls())
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_AcknowledgeWithUserMapping(t *testing.T) {
@@ -595,9 +381,9 @@ func TestHandleCallbackUseCase_ExecuteAsync_AcknowledgeWithUserMapping(t *testin
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.Equal(t, "acknowledged", keepClient.enrichedEnrichments["status"])
-	assert.Equal(t, "keep-user", keepClient.enrichedEnrichments["assignee"])
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
+	assert.Equal(t, "keep-user", enrichedWith(keepClient)["assignee"])
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_AcknowledgeWithoutUserMapping(t *testing.T) {
@@ -617,10 +403,10 @@ func TestHandleCallbackUseCase_ExecuteAsync_AcknowledgeWithoutUserMapping(t *tes
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.Equal(t, "acknowledged", keepClient.enrichedEnrichments["status"])
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
 	// Without user mapping, assignee falls back to Mattermost username
-	assert.Equal(t, "testuser", keepClient.enrichedEnrichments["assignee"], "should use Mattermost username as fallback")
+	assert.Equal(t, "testuser", enrichedWith(keepClient)["assignee"], "should use Mattermost username as fallback")
 }
 
 func TestHandleCallbackUseCase_Wait(t *testing.T) {
@@ -642,7 +428,7 @@ func TestHandleCallbackUseCase_Wait(t *testing.T) {
 		uc.ExecuteAsync(input)
 		uc.Wait()
 
-		assert.True(t, keepClient.wasEnrichAlertCalled())
+		assert.NotEmpty(t, keepClient.EnrichAlertCalls())
 	})
 
 	t.Run("wait returns immediately when no goroutines pending", func(t *testing.T) {
@@ -669,7 +455,7 @@ func TestHandleCallbackUseCase_ExecuteAsync_DifferentSeverities(t *testing.T) {
 		t.Run("severity_"+severity, func(t *testing.T) {
 			uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
 
-			keepClient.getAlertResponse.Severity = severity
+			keepClient.GetAlertFunc = keepAlertFor(severity)
 
 			input := dto.MattermostCallbackInput{
 				UserID:    "user-123",
@@ -686,7 +472,7 @@ func TestHandleCallbackUseCase_ExecuteAsync_DifferentSeverities(t *testing.T) {
 			uc.ExecuteAsync(input)
 			uc.Wait()
 
-			assert.True(t, keepClient.wasEnrichAlertCalled())
+			assert.NotEmpty(t, keepClient.EnrichAlertCalls())
 		})
 	}
 }
@@ -694,7 +480,7 @@ func TestHandleCallbackUseCase_ExecuteAsync_DifferentSeverities(t *testing.T) {
 func TestHandleCallbackUseCase_ExecuteAsync_UpdatePostError(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	mmClient.updatePostErr = errors.New("update post error")
+	mmClient.UpdatePostFunc = func(context.Context, string, post.Attachment) error { return errors.New("update post error") }
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -711,16 +497,16 @@ func TestHandleCallbackUseCase_ExecuteAsync_UpdatePostError(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.True(t, mmClient.wasUpdatePostCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
 	assert.Len(t, replies, 1, "should still attempt to reply to thread even if update fails")
 }
 
 func TestHandleCallbackUseCase_ExecuteAsync_ReplyToThreadError(t *testing.T) {
 	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
-	mmClient.replyToThreadErr = errors.New("reply to thread error")
+	mmClient.ReplyToThreadFunc = func(context.Context, string, string, string) error { return errors.New("reply to thread error") }
 
 	input := dto.MattermostCallbackInput{
 		UserID:    "user-123",
@@ -737,9 +523,9 @@ func TestHandleCallbackUseCase_ExecuteAsync_ReplyToThreadError(t *testing.T) {
 	uc.ExecuteAsync(input)
 	uc.Wait()
 
-	assert.True(t, keepClient.wasEnrichAlertCalled())
-	assert.True(t, mmClient.wasUpdatePostCalled())
-	replies := mmClient.getReplyToThreadCalls()
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
 	assert.Len(t, replies, 1, "should attempt to reply even if it will fail")
 }
 
@@ -798,26 +584,26 @@ func TestHandleCallbackUseCase_ExecuteAsync_DisposeOnNewAlertOptions(t *testing.
 		uc.ExecuteAsync(input)
 		uc.Wait()
 
-		require.Len(t, keepClient.enrichCalls, 2, "should make 2 enrich calls")
+		require.Len(t, keepClient.EnrichAlertCalls(), 2, "should make 2 enrich calls")
 
 		// First call: assignee with dispose=false (must be set before status to avoid race condition)
-		assigneeCall := keepClient.enrichCalls[0]
+		assigneeCall := keepClient.EnrichAlertCalls()[0]
 		assert.Equal(t, "fp-12345", assigneeCall.Fingerprint)
 		assert.Equal(t, "keep-user", assigneeCall.Enrichments["assignee"])
-		assert.False(t, assigneeCall.DisposeOnNewAlert, "assignee enrichment should have dispose=false")
+		assert.False(t, assigneeCall.Opts.DisposeOnNewAlert, "assignee enrichment should have dispose=false")
 
 		// Second call: status with dispose=true (triggers Keep webhook)
-		statusCall := keepClient.enrichCalls[1]
+		statusCall := keepClient.EnrichAlertCalls()[1]
 		assert.Equal(t, "fp-12345", statusCall.Fingerprint)
 		assert.Equal(t, "acknowledged", statusCall.Enrichments["status"])
-		assert.True(t, statusCall.DisposeOnNewAlert, "status enrichment should have dispose=true")
+		assert.True(t, statusCall.Opts.DisposeOnNewAlert, "status enrichment should have dispose=true")
 	})
 
 	t.Run("resolve sets assignee then status with dispose=true", func(t *testing.T) {
 		uc, postRepo, keepClient, _, _ := setupHandleCallbackUseCase()
 
 		fp, _ := alert.NewFingerprint("fp-12345")
-		existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+		existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 		postRepo.posts[fp.Value()] = existingPost
 
 		input := dto.MattermostCallbackInput{
@@ -835,17 +621,17 @@ func TestHandleCallbackUseCase_ExecuteAsync_DisposeOnNewAlertOptions(t *testing.
 		uc.ExecuteAsync(input)
 		uc.Wait()
 
-		require.Len(t, keepClient.enrichCalls, 2, "should make 2 enrich calls")
+		require.Len(t, keepClient.EnrichAlertCalls(), 2, "should make 2 enrich calls")
 
 		// First call: assignee with dispose=false
-		assigneeCall := keepClient.enrichCalls[0]
+		assigneeCall := keepClient.EnrichAlertCalls()[0]
 		assert.Equal(t, "testuser", assigneeCall.Enrichments["assignee"], "should use Mattermost username as fallback")
-		assert.False(t, assigneeCall.DisposeOnNewAlert, "assignee enrichment should have dispose=false")
+		assert.False(t, assigneeCall.Opts.DisposeOnNewAlert, "assignee enrichment should have dispose=false")
 
 		// Second call: status with dispose=true
-		statusCall := keepClient.enrichCalls[1]
+		statusCall := keepClient.EnrichAlertCalls()[1]
 		assert.Equal(t, "resolved", statusCall.Enrichments["status"])
-		assert.True(t, statusCall.DisposeOnNewAlert, "status enrichment should have dispose=true")
+		assert.True(t, statusCall.Opts.DisposeOnNewAlert, "status enrichment should have dispose=true")
 	})
 }
 
@@ -854,8 +640,12 @@ func TestHandleCallbackUseCase_ExecuteAsync_AssigneeEnrichFailsButFlowContinues(
 		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
 		// First enrich call (assignee) fails, second (status) succeeds
-		keepClient.enrichAlertErr = errors.New("assignee enrichment failed")
-		keepClient.enrichAlertErrOnFirstCall = true
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+			if len(keepClient.EnrichAlertCalls()) == 1 {
+				return errors.New("assignee enrichment failed")
+			}
+			return nil
+		}
 
 		input := dto.MattermostCallbackInput{
 			UserID:    "user-123",
@@ -873,26 +663,30 @@ func TestHandleCallbackUseCase_ExecuteAsync_AssigneeEnrichFailsButFlowContinues(
 		uc.Wait()
 
 		// Both enrich calls should be attempted
-		assert.Len(t, keepClient.enrichCalls, 2, "should attempt both enrich calls")
+		assert.Len(t, keepClient.EnrichAlertCalls(), 2, "should attempt both enrich calls")
 
 		// Mattermost post should still be updated with username (local update doesn't depend on Keep)
-		assert.True(t, mmClient.wasUpdatePostCalled(), "should update Mattermost post even if assignee enrichment fails")
+		assert.NotEmpty(t, mmClient.UpdatePostCalls(), "should update Mattermost post even if assignee enrichment fails")
 
 		// Reply to thread should still be sent
-		assert.Len(t, mmClient.replyToThreadCalls, 1, "should reply to thread")
-		assert.Contains(t, mmClient.replyToThreadCalls[0], "testuser", "reply should contain username")
+		assert.Len(t, replyMessages(mmClient), 1, "should reply to thread")
+		assert.Contains(t, replyMessages(mmClient)[0], "testuser", "reply should contain username")
 	})
 
 	t.Run("resolve continues when assignee enrichment fails", func(t *testing.T) {
 		uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
 
 		fp, _ := alert.NewFingerprint("fp-12345")
-		existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now())
+		existingPost := post.NewPost("post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 		postRepo.posts[fp.Value()] = existingPost
 
 		// First enrich call (assignee) fails, second (status) succeeds
-		keepClient.enrichAlertErr = errors.New("assignee enrichment failed")
-		keepClient.enrichAlertErrOnFirstCall = true
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+			if len(keepClient.EnrichAlertCalls()) == 1 {
+				return errors.New("assignee enrichment failed")
+			}
+			return nil
+		}
 
 		input := dto.MattermostCallbackInput{
 			UserID:    "user-123",
@@ -910,13 +704,654 @@ func TestHandleCallbackUseCase_ExecuteAsync_AssigneeEnrichFailsButFlowContinues(
 		uc.Wait()
 
 		// Both enrich calls should be attempted
-		assert.Len(t, keepClient.enrichCalls, 2, "should attempt both enrich calls")
+		assert.Len(t, keepClient.EnrichAlertCalls(), 2, "should attempt both enrich calls")
 
 		// Mattermost post should still be updated
-		assert.True(t, mmClient.wasUpdatePostCalled(), "should update Mattermost post even if assignee enrichment fails")
+		assert.NotEmpty(t, mmClient.UpdatePostCalls(), "should update Mattermost post even if assignee enrichment fails")
 
 		// Reply to thread should still be sent
-		assert.Len(t, mmClient.replyToThreadCalls, 1, "should reply to thread")
-		assert.Contains(t, mmClient.replyToThreadCalls[0], "testuser", "reply should contain username")
+		assert.Len(t, replyMessages(mmClient), 1, "should reply to thread")
+		assert.Contains(t, replyMessages(mmClient)[0], "testuser", "reply should contain username")
+	})
+}
+
+func TestHandleCallbackUseCase_ExecuteAction(t *testing.T) {
+	t.Run("acknowledge tracked alert", func(t *testing.T) {
+		uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+		err := uc.ExecuteAction(context.Background(), post.ActionAcknowledge, "fp-12345", "user-123")
+		require.NoError(t, err)
+
+		assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
+		assert.NotEmpty(t, mmClient.UpdatePostCalls())
+		replies := replyMessages(mmClient)
+		require.Len(t, replies, 1)
+		assert.Contains(t, replies[0], "Acknowledged by @testuser")
+	})
+
+	t.Run("resolve removes tracked post", func(t *testing.T) {
+		uc, postRepo, _, _, _ := setupHandleCallbackUseCase()
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+		err := uc.ExecuteAction(context.Background(), post.ActionResolve, "fp-12345", "user-123")
+		require.NoError(t, err)
+		assert.NotEmpty(t, postRepo.DeleteCalls())
+	})
+
+	t.Run("untracked alert", func(t *testing.T) {
+		uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+
+		err := uc.ExecuteAction(context.Background(), post.ActionAcknowledge, "fp-unknown", "user-123")
+		require.ErrorIs(t, err, post.ErrNotFound)
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+
+		err := uc.ExecuteAction(context.Background(), "explode", "fp-12345", "user-123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown action")
+	})
+
+	t.Run("keep error", func(t *testing.T) {
+		uc, postRepo, keepClient, _, _ := setupHandleCallbackUseCase()
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+		keepClient.GetAlertFunc = keepAlertError(errors.New("keep down"))
+
+		err := uc.ExecuteAction(context.Background(), post.ActionResolve, "fp-12345", "user-123")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "get alert from keep")
+	})
+}
+
+func TestHandleCallbackUseCase_Locks(t *testing.T) {
+	t.Run("action runs under the fingerprint lock", func(t *testing.T) {
+		uc, postRepo, keepClient, _, _ := setupHandleCallbackUseCase()
+		locks, locker, held := setupFingerprintLocks()
+		uc.SetLocks(locks)
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+		require.NoError(t, uc.ExecuteAction(context.Background(), post.ActionAcknowledge, "fp-12345", "user-123"))
+
+		require.Len(t, locker.LockCalls(), 1)
+		assert.Equal(t, "alert:fp-12345", locker.LockCalls()[0].Key)
+		assert.False(t, *held)
+		assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
+	})
+
+	t.Run("busy alert restores the card with an error", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		locks, locker, _ := setupFingerprintLocks()
+		locker.LockFunc = func(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+			return nil, context.DeadlineExceeded
+		}
+		uc.SetLocks(locks)
+
+		uc.ExecuteAsync(snoozeCallbackInput())
+		uc.Wait()
+
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+		assert.NotEmpty(t, mmClient.UpdatePostCalls(), "the card shows the error")
+	})
+}
+
+func snoozeCallbackInput() dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			"action":          "snooze",
+			"fingerprint":     "fp-12345",
+			"alert_name":      "Test Alert",
+			"attachment_json": `{"Color":"#808080","Title":"Test Alert","TitleLink":"","Text":"","Fields":null,"Actions":null,"Footer":"","FooterIcon":""}`,
+		},
+	}
+}
+
+func TestHandleCallbackUseCase_ExecuteAsync_Snooze(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc.SetClock(clock.NewFake(now))
+	uc.SetSnoozeDuration(2 * time.Hour)
+
+	fp := alert.RestoreFingerprint("fp-12345")
+	postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), now, time.Now())
+
+	uc.ExecuteAsync(snoozeCallbackInput())
+	uc.Wait()
+
+	saved := postRepo.posts["fp-12345"]
+	assert.Equal(t, now.Add(2*time.Hour), saved.SnoozedUntil())
+	assert.True(t, saved.IsSnoozed(now))
+	assert.Empty(t, keepClient.EnrichAlertCalls(), "snooze is local to the bridge")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	replies := replyMessages(mmClient)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Snoozed by @testuser until 2024-03-01 14:00 UTC", replies[0])
+}
+
+func TestHandleCallbackUseCase_ExecuteAsync_SnoozeDisabled(t *testing.T) {
+	uc, postRepo, _, mmClient, _ := setupHandleCallbackUseCase()
+	fp := alert.RestoreFingerprint("fp-12345")
+	postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+	uc.ExecuteAsync(snoozeCallbackInput())
+	uc.Wait()
+
+	assert.True(t, postRepo.posts["fp-12345"].SnoozedUntil().IsZero())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "post should show the error state")
+	assert.Empty(t, replyMessages(mmClient))
+}
+
+func TestHandleCallbackUseCase_ExecuteAsync_SnoozeUntracked(t *testing.T) {
+	uc, postRepo, _, mmClient, _ := setupHandleCallbackUseCase()
+	uc.SetSnoozeDuration(time.Hour)
+
+	uc.ExecuteAsync(snoozeCallbackInput())
+	uc.Wait()
+
+	assert.Empty(t, postRepo.SaveCalls())
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "post should show the error state")
+	assert.Empty(t, replyMessages(mmClient))
+}
+
+func ackForCallbackInput(duration string) dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			"action":          "acknowledge_for",
+			"fingerprint":     "fp-12345",
+			"alert_name":      "Test Alert",
+			"selected_option": duration,
+			"attachment_json": `{"Color":"#808080","Title":"Test Alert","TitleLink":"","Text":"","Fields":null,"Actions":null,"Footer":"","FooterIcon":""}`,
+		},
+	}
+}
+
+func TestHandleCallbackUseCase_AcknowledgeFor(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc.SetClock(clock.NewFake(now))
+
+	result, err := uc.ExecuteImmediate(ackForCallbackInput("2h"))
+	require.NoError(t, err)
+	assert.Equal(t, "Acknowledging for a limited time is disabled.", result.Ephemeral)
+
+	uc.SetAckDurations([]time.Duration{30 * time.Minute, 2 * time.Hour})
+	result, err = uc.ExecuteImmediate(ackForCallbackInput("3h"))
+	require.NoError(t, err)
+	assert.Equal(t, "This duration is no longer offered.", result.Ephemeral)
+
+	result, err = uc.ExecuteImmediate(ackForCallbackInput("2h"))
+	require.NoError(t, err)
+	assert.Empty(t, result.Ephemeral)
+	assert.Equal(t, "Processing Alert", result.Attachment.Title)
+
+	fp := alert.RestoreFingerprint("fp-12345")
+	postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), now, time.Now())
+
+	uc.ExecuteAsync(ackForCallbackInput("2h"))
+	uc.Wait()
+
+	saved := postRepo.posts["fp-12345"]
+	assert.Equal(t, now.Add(2*time.Hour), saved.AckUntil())
+	assert.Equal(t, "testuser", saved.AckedBy())
+	assert.Equal(t, alert.StatusAcknowledged, saved.ShownStatus())
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls())
+	assert.Equal(t, "Acknowledged by @testuser until 2024-03-01 14:00 UTC", lastCall(mmClient.UpdatePostCalls()).Attachment.Footer)
+	replies := replyMessages(mmClient)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Acknowledged by @testuser until 2024-03-01 14:00 UTC", replies[0])
+
+	// Unacknowledging drops the deadline.
+	input := ackForCallbackInput("")
+	input.Context["action"] = "unacknowledge"
+	uc.ExecuteAsync(input)
+	uc.Wait()
+	assert.True(t, postRepo.posts["fp-12345"].AckUntil().IsZero())
+}
+
+func TestHandleCallbackUseCase_ExecuteImmediate_AcknowledgeForRequiresSelection(t *testing.T) {
+	uc, _, _, _, _ := setupHandleCallbackUseCase()
+	uc.SetAckDurations([]time.Duration{time.Hour})
+
+	_, err := uc.ExecuteImmediate(ackForCallbackInput(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "selected_option")
+}
+
+func TestHandleCallbackUseCase_Permissions(t *testing.T) {
+	rules := []port.PermissionRule{{Actions: []string{post.ActionResolve}, Severities: []string{"high"}, Users: []string{"alice"}}}
+	input := dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			"action":          "resolve",
+			"fingerprint":     "fp-12345",
+			"alert_name":      "Test Alert",
+			"severity":        "high",
+			"attachment_json": `{"Color":"#808080","Title":"Test Alert","TitleLink":"","Text":"","Fields":null,"Actions":null,"Footer":"","FooterIcon":""}`,
+		},
+	}
+
+	t.Run("button click is denied with an ephemeral message", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		uc.SetPermissions(NewCallbackPermissions(rules, mmClient, &portmock.MattermostMembershipClientMock{}, uc.logger))
+
+		result, err := uc.ExecuteImmediate(input)
+		require.NoError(t, err)
+		assert.Equal(t, "You are not permitted to resolve this alert.", result.Ephemeral)
+	})
+
+	t.Run("permitted user gets the processing state", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		mmClient.GetUserFunc = func(ctx context.Context, userID string) (string, error) { return "alice", nil }
+		uc.SetPermissions(NewCallbackPermissions(rules, mmClient, &portmock.MattermostMembershipClientMock{}, uc.logger))
+
+		result, err := uc.ExecuteImmediate(input)
+		require.NoError(t, err)
+		assert.Empty(t, result.Ephemeral)
+		assert.Equal(t, "Processing Alert", result.Attachment.Title)
+	})
+
+	t.Run("slash command action is denied", func(t *testing.T) {
+		uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+		uc.SetPermissions(NewCallbackPermissions(rules, mmClient, &portmock.MattermostMembershipClientMock{}, uc.logger))
+
+		err := uc.ExecuteAction(context.Background(), post.ActionResolve, "fp-12345", "user-123")
+		require.ErrorIs(t, err, ErrNotPermitted)
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+		assert.Empty(t, postRepo.DeleteCalls())
+	})
+}
+
+func assignCallbackInput(selected string) dto.MattermostCallbackInput {
+	input := snoozeCallbackInput()
+	input.Context[post.ContextKeyAction] = post.ActionAssign
+	input.Context[post.ContextKeySelectedOption] = selected
+	return input
+}
+
+func TestHandleCallbackUseCase_ExecuteImmediate_AssignRequiresSelection(t *testing.T) {
+	uc, _, _, _, _ := setupHandleCallbackUseCase()
+
+	result, err := uc.ExecuteImmediate(assignCallbackInput("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "Processing Alert", result.Attachment.Title)
+
+	_, err = uc.ExecuteImmediate(assignCallbackInput(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "selected_option")
+}
+
+func TestHandleCallbackUseCase_ExecuteAsync_Assign(t *testing.T) {
+	t.Run("listed user is assigned without acknowledging", func(t *testing.T) {
+		uc, postRepo, keepClient, mmClient, userMapper := setupHandleCallbackUseCase()
+		userMapper.mapping["alice"] = "alice_keep"
+		fp := alert.RestoreFingerprint("fp-12345")
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+		uc.ExecuteAsync(assignCallbackInput("alice"))
+		uc.Wait()
+
+		assert.Equal(t, map[string]string{EnrichmentKeyAssignee: "alice_keep"}, enrichedWith(keepClient))
+		assert.Equal(t, "alice", postRepo.posts["fp-12345"].LastKnownAssignee())
+		assert.Equal(t, "Assigned to @alice", lastCall(mmClient.UpdatePostCalls()).Attachment.Footer)
+		assert.Equal(t, []string{"Assigned to @alice by @testuser"}, replyMessages(mmClient))
+	})
+
+	t.Run("assign to me uses the clicking user", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+
+		uc.ExecuteAsync(assignCallbackInput(post.AssignToMe))
+		uc.Wait()
+
+		assert.Equal(t, "testuser", enrichedWith(keepClient)[EnrichmentKeyAssignee])
+		assert.Equal(t, []string{"Assigned to @testuser"}, replyMessages(mmClient))
+	})
+
+	t.Run("user picker value is looked up", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		mmClient.GetUserFunc = func(ctx context.Context, userID string) (string, error) {
+			if userID == "user-bob" {
+				return "bob", nil
+			}
+			return "testuser", nil
+		}
+		input := assignCallbackInput("user-bob")
+		input.Context[post.ContextKeyDataSource] = post.DataSourceUsers
+
+		uc.ExecuteAsync(input)
+		uc.Wait()
+
+		assert.Equal(t, "bob", enrichedWith(keepClient)[EnrichmentKeyAssignee])
+	})
+
+	t.Run("enrich failure shows the error", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+			return errors.New("keep down")
+		}
+
+		uc.ExecuteAsync(assignCallbackInput("alice"))
+		uc.Wait()
+
+		assert.Equal(t, "Error: Failed to assign", lastCall(mmClient.UpdatePostCalls()).Attachment.Text)
+		assert.Empty(t, replyMessages(mmClient))
+	})
+}
+
+func customActionCallbackInput(name string) dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			post.ContextKeyAction:       post.ActionCustom,
+			post.ContextKeyCustomAction: name,
+			post.ContextKeyFingerprint:  "fp-12345",
+			post.ContextKeyAlertName:    "Test Alert",
+			post.ContextKeySeverity:     "high",
+		},
+	}
+}
+
+func TestHandleCallbackUseCase_CustomAction(t *testing.T) {
+	newRunner := func(workflowErr error) (*CustomActionRunner, *portmock.KeepWorkflowRunnerMock, *portmock.WebhookSenderMock) {
+		actions := &portmock.CustomActionsMock{
+			RequestFunc: func(name string, a *alert.Alert, username string) (port.CustomActionRequest, error) {
+				switch name {
+				case "Restart pod":
+					return port.CustomActionRequest{WorkflowID: "restart-pod", Body: []byte(`{"env":"` + a.Labels()["env"] + `","user":"` + username + `"}`)}, nil
+				case "Page SRE":
+					return port.CustomActionRequest{URL: "https://hooks.example.com/page", Body: []byte(`{}`)}, nil
+				}
+				return port.CustomActionRequest{}, errors.New("unknown custom action")
+			},
+		}
+		workflows := &portmock.KeepWorkflowRunnerMock{
+			RunWorkflowFunc: func(ctx context.Context, workflowID string, body []byte) (string, error) {
+				return "exec-1", workflowErr
+			},
+		}
+		webhooks := &portmock.WebhookSenderMock{
+			SendFunc: func(ctx context.Context, url string, body []byte) error { return nil },
+		}
+		return NewCustomActionRunner(actions, workflows, webhooks, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))), workflows, webhooks
+	}
+
+	t.Run("immediate phase leaves the post alone", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+		runner, _, _ := newRunner(nil)
+		uc.SetCustomActions(runner)
+
+		result, err := uc.ExecuteImmediate(customActionCallbackInput("Restart pod"))
+		require.NoError(t, err)
+		assert.Equal(t, "Running **Restart pod**…", result.Ephemeral)
+		assert.True(t, result.RunAsync)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+
+		result, err := uc.ExecuteImmediate(customActionCallbackInput("Restart pod"))
+		require.NoError(t, err)
+		assert.Equal(t, "Custom actions are disabled.", result.Ephemeral)
+		assert.False(t, result.RunAsync)
+	})
+
+	t.Run("runs a keep workflow", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		runner, workflows, _ := newRunner(nil)
+		uc.SetCustomActions(runner)
+
+		uc.ExecuteAsync(customActionCallbackInput("Restart pod"))
+		uc.Wait()
+
+		calls := workflows.RunWorkflowCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "restart-pod", calls[0].WorkflowID)
+		assert.JSONEq(t, `{"env":"test","user":"testuser"}`, string(calls[0].Body))
+		assert.Equal(t, []string{"▶️ @testuser ran **Restart pod** (Keep workflow restart-pod, execution exec-1)"}, replyMessages(mmClient))
+		assert.Empty(t, mmClient.UpdatePostCalls())
+	})
+
+	t.Run("calls a url", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		runner, _, webhooks := newRunner(nil)
+		uc.SetCustomActions(runner)
+
+		uc.ExecuteAsync(customActionCallbackInput("Page SRE"))
+		uc.Wait()
+
+		require.Len(t, webhooks.SendCalls(), 1)
+		assert.Equal(t, "https://hooks.example.com/page", webhooks.SendCalls()[0].URL)
+		assert.Equal(t, []string{"▶️ @testuser ran **Page SRE**"}, replyMessages(mmClient))
+	})
+
+	t.Run("failure is reported in the thread", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		runner, _, _ := newRunner(errors.New("keep down"))
+		uc.SetCustomActions(runner)
+
+		uc.ExecuteAsync(customActionCallbackInput("Restart pod"))
+		uc.Wait()
+
+		assert.Equal(t, []string{"⚠️ **Restart pod** failed for @testuser, see the bridge logs"}, replyMessages(mmClient))
+		assert.Empty(t, mmClient.UpdatePostCalls(), "the post keeps its buttons")
+	})
+}
+
+func runWorkflowCallbackInput(workflowID string) dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			post.ContextKeyAction:         post.ActionRunWorkflow,
+			post.ContextKeyFingerprint:    "fp-12345",
+			post.ContextKeyAlertName:      "Test Alert",
+			post.ContextKeySeverity:       "high",
+			post.ContextKeySelectedOption: workflowID,
+		},
+	}
+}
+
+func TestHandleCallbackUseCase_RunWorkflow(t *testing.T) {
+	newMenu := func(t *testing.T, runErr error) (*WorkflowMenu, *portmock.KeepWorkflowRunnerMock) {
+		keepClient := newKeepClientMock()
+		keepClient.GetWorkflowsFunc = func(context.Context) ([]port.KeepWorkflow, error) {
+			return []port.KeepWorkflow{{ID: "wf-1", Name: "Restart pod", WorkflowRawID: "restart-pod"}}, nil
+		}
+		runner := &portmock.KeepWorkflowRunnerMock{
+			RunAlertWorkflowFunc: func(ctx context.Context, workflowID string, a port.KeepAlert) (string, error) {
+				return "exec-1", runErr
+			},
+		}
+		menu := NewWorkflowMenu(keepClient, runner, nil, nil, time.Minute, slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})))
+		require.NoError(t, menu.Refresh(context.Background()))
+		return menu, runner
+	}
+
+	t.Run("immediate phase leaves the post alone", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+		menu, _ := newMenu(t, nil)
+		uc.SetWorkflowMenu(menu)
+
+		result, err := uc.ExecuteImmediate(runWorkflowCallbackInput("wf-1"))
+		require.NoError(t, err)
+		assert.Equal(t, "Starting workflow **Restart pod**…", result.Ephemeral)
+		assert.True(t, result.RunAsync)
+	})
+
+	t.Run("unknown workflow", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+		menu, _ := newMenu(t, nil)
+		uc.SetWorkflowMenu(menu)
+
+		result, err := uc.ExecuteImmediate(runWorkflowCallbackInput("wf-gone"))
+		require.NoError(t, err)
+		assert.Equal(t, "This workflow is no longer available.", result.Ephemeral)
+		assert.False(t, result.RunAsync)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+
+		result, err := uc.ExecuteImmediate(runWorkflowCallbackInput("wf-1"))
+		require.NoError(t, err)
+		assert.Equal(t, "The workflow menu is disabled.", result.Ephemeral)
+	})
+
+	t.Run("missing selected option", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+
+		_, err := uc.ExecuteImmediate(runWorkflowCallbackInput(""))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "selected_option")
+	})
+
+	t.Run("runs the workflow for the alert", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		menu, runner := newMenu(t, nil)
+		uc.SetWorkflowMenu(menu)
+
+		uc.ExecuteAsync(runWorkflowCallbackInput("wf-1"))
+		uc.Wait()
+
+		calls := runner.RunAlertWorkflowCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "wf-1", calls[0].WorkflowID)
+		assert.Equal(t, "fp-12345", calls[0].Alert.Fingerprint)
+		assert.Equal(t, []string{"▶️ @testuser started workflow **Restart pod** (execution exec-1)"}, replyMessages(mmClient))
+		assert.Empty(t, mmClient.UpdatePostCalls())
+	})
+
+	t.Run("failure is reported in the thread", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+		menu, _ := newMenu(t, errors.New("keep down"))
+		uc.SetWorkflowMenu(menu)
+
+		uc.ExecuteAsync(runWorkflowCallbackInput("wf-1"))
+		uc.Wait()
+
+		assert.Equal(t, []string{"⚠️ Workflow **Restart pod** could not be started for @testuser, see the bridge logs"}, replyMessages(mmClient))
+	})
+}
+
+func TestHandleCallbackUseCase_RetryOnKeepFailure(t *testing.T) {
+	t.Run("failed actions offer Retry", func(t *testing.T) {
+		for _, action := range []string{post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge} {
+			t.Run(action, func(t *testing.T) {
+				uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+				keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+					return errors.New("keep enrich alert: status 500")
+				}
+				keepClient.UnenrichAlertFunc = func(context.Context, string, []string) error { return errors.New("keep unenrich alert: status 500") }
+
+				uc.ExecuteAsync(queuedActionInput(action))
+				uc.Wait()
+
+				actions := lastCall(mmClient.UpdatePostCalls()).Attachment.Actions
+				require.NotEmpty(t, actions)
+				retry := actions[len(actions)-1]
+				assert.Equal(t, "Retry", retry.Name)
+				assert.Equal(t, "https://callback.example.com", retry.Integration.URL)
+				assert.Equal(t, post.ActionRetry, retry.Integration.Context[post.ContextKeyAction])
+				assert.Equal(t, action, retry.Integration.Context[post.ContextKeyRetryAction])
+				assert.Equal(t, "fp-12345", retry.Integration.Context[post.ContextKeyFingerprint])
+
+				shown, err := post.AttachmentFromJSON(retry.Integration.Context[post.ContextKeyAttachmentJSON])
+				require.NoError(t, err)
+				assert.Equal(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Title, shown.Title, "the retry shows the post as it is now")
+				assert.Empty(t, shown.Actions)
+			})
+		}
+	})
+
+	t.Run("succeeded actions offer no Retry", func(t *testing.T) {
+		uc, _, _, mmClient, _ := setupHandleCallbackUseCase()
+
+		uc.ExecuteAsync(queuedActionInput(post.ActionResolve))
+		uc.Wait()
+
+		assert.Empty(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Actions)
+	})
+
+	t.Run("actions kept for replay offer no Retry", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		setupPendingActions(uc)
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return errKeepDown }
+
+		uc.ExecuteAsync(queuedActionInput(post.ActionResolve))
+		uc.Wait()
+
+		assert.Empty(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Actions)
+	})
+
+	t.Run("failed alert lookups offer Retry", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		keepClient.GetAlertFunc = keepAlertError(errors.New("keep get alert: status 500"))
+		input := queuedActionInput(post.ActionAcknowledge)
+
+		uc.ExecuteAsync(input)
+		uc.Wait()
+
+		assert.Equal(t, "Error: Failed to get alert data", lastCall(mmClient.UpdatePostCalls()).Attachment.Text)
+		require.Len(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Actions, 1)
+		retry := lastCall(mmClient.UpdatePostCalls()).Attachment.Actions[0]
+		assert.Equal(t, post.ActionAcknowledge, retry.Integration.Context[post.ContextKeyRetryAction])
+		assert.Equal(t, input.Context[post.ContextKeyAttachmentJSON], retry.Integration.Context[post.ContextKeyAttachmentJSON])
+	})
+
+	t.Run("Retry runs the action again", func(t *testing.T) {
+		uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+			return errors.New("keep enrich alert: status 500")
+		}
+		uc.ExecuteAsync(queuedActionInput(post.ActionAcknowledge))
+		uc.Wait()
+		actions := lastCall(mmClient.UpdatePostCalls()).Attachment.Actions
+		retry := dto.MattermostCallbackInput{
+			UserID:    "user-123",
+			PostID:    "post-456",
+			ChannelID: "channel-789",
+			Context:   actions[len(actions)-1].Integration.Context,
+		}
+
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return nil }
+		enriched := len(keepClient.EnrichAlertCalls())
+		result, err := uc.ExecuteImmediate(retry)
+		require.NoError(t, err)
+		assert.Equal(t, "Processing Alert", result.Attachment.Title)
+		uc.ExecuteAsync(retry)
+		uc.Wait()
+
+		retried := keepClient.EnrichAlertCalls()[enriched:]
+		require.Len(t, retried, 2)
+		assert.Equal(t, "acknowledged", retried[1].Enrichments["status"])
+		assert.Equal(t, "ACKNOWLEDGED: Test Alert", lastCall(mmClient.UpdatePostCalls()).Attachment.Title)
+		assert.Empty(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Actions, "the post no longer offers Retry")
+	})
+
+	t.Run("Retry runs retryable actions only", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+		input := queuedActionInput(post.ActionRetry)
+		input.Context[post.ContextKeyRetryAction] = post.ActionSnooze
+
+		_, err := uc.ExecuteImmediate(input)
+		assert.ErrorContains(t, err, "invalid retry action")
 	})
 }
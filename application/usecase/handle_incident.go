@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// keepIncidentTimeLayout is how Keep formats incident times without a zone;
+// they are in UTC.
+const keepIncidentTimeLayout = "2006-01-02T15:04:05.999999"
+
+// HandleIncidentUseCase posts Keep incidents to Mattermost, keeps the post in
+// step with every incident webhook and applies the Acknowledge and Resolve
+// buttons of the post to the incident in Keep.
+type HandleIncidentUseCase struct {
+	incidentRepo    incident.Repository
+	keepClient      port.KeepIncidentClient
+	mmClient        port.MattermostClient
+	msgBuilder      port.IncidentMessageBuilder
+	channelResolver port.ChannelResolver
+	channelID       string
+	keepUIURL       string
+	callbackURL     string
+	permissions     *CallbackPermissions
+	severities      alert.SeverityMap
+	logger          *slog.Logger
+	clock           clock.Clock
+	wg              sync.WaitGroup
+}
+
+// NewHandleIncidentUseCase returns a use case that routes incidents by
+// severity through channelResolver. callbackURL is where the incident
+// buttons post to.
+func NewHandleIncidentUseCase(
+	incidentRepo incident.Repository,
+	keepClient port.KeepIncidentClient,
+	mmClient port.MattermostClient,
+	msgBuilder port.IncidentMessageBuilder,
+	channelResolver port.ChannelResolver,
+	keepUIURL string,
+	callbackURL string,
+	logger *slog.Logger,
+) *HandleIncidentUseCase {
+	return &HandleIncidentUseCase{
+		incidentRepo:    incidentRepo,
+		keepClient:      keepClient,
+		mmClient:        mmClient,
+		msgBuilder:      msgBuilder,
+		channelResolver: channelResolver,
+		keepUIURL:       keepUIURL,
+		callbackURL:     callbackURL,
+		logger:          logger,
+		clock:           clock.Real(),
+	}
+}
+
+// SetClock replaces the clock that timestamps incident changes.
+func (uc *HandleIncidentUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// SetChannel posts every incident to channelID. An empty channelID, the
+// default, routes incidents by severity like alerts.
+func (uc *HandleIncidentUseCase) SetChannel(channelID string) {
+	uc.channelID = channelID
+}
+
+// SetSeverityMap maps incident severities onto the canonical ones before
+// they are parsed, like HandleAlertUseCase.SetSeverityMap.
+func (uc *HandleIncidentUseCase) SetSeverityMap(m alert.SeverityMap) {
+	uc.severities = m
+}
+
+// SetPermissions checks incident actions against the permission rules, with
+// the incident severity standing in for the alert severity. A nil
+// permissions, the default, lets everyone apply every action.
+func (uc *HandleIncidentUseCase) SetPermissions(permissions *CallbackPermissions) {
+	uc.permissions = permissions
+}
+
+// Execute creates or updates the post of the incident Keep reported. Closed
+// incidents that were never posted are ignored, and posts of incidents that
+// close stop being tracked.
+func (uc *HandleIncidentUseCase) Execute(ctx context.Context, input dto.KeepIncidentInput) error {
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		return errors.New("incident id is empty")
+	}
+
+	severity, err := alert.NewSeverity(uc.severities.Normalize(input.Severity))
+	if err != nil {
+		return fmt.Errorf("parse severity: %w", err)
+	}
+
+	status := strings.ToLower(input.Status)
+	if !incident.IsValidStatus(status) {
+		return fmt.Errorf("parse status: unknown incident status %q", input.Status)
+	}
+
+	startedAt := uc.parseStartTime(input.StartTime)
+
+	uc.logger.Info("Incident received",
+		logger.ApplicationFields("incident_received",
+			slog.String("incident_id", id),
+			slog.String("severity", severity.String()),
+			slog.String("status", status),
+			slog.Int("alerts_count", input.AlertsCount),
+		),
+	)
+	incidentsReceivedCounter(status).Inc()
+
+	existing, err := uc.incidentRepo.FindByID(ctx, id)
+	if err != nil && !errors.Is(err, incident.ErrNotFound) {
+		return fmt.Errorf("find incident: %w", err)
+	}
+
+	if existing == nil {
+		inc := incident.NewIncident(id, input.Name(), severity, status, input.AlertsCount, startedAt, uc.clock.Now())
+		if inc.IsClosed() {
+			uc.logger.Debug("Closed incident without post ignored",
+				slog.String("incident_id", id),
+				slog.String("status", status),
+			)
+			return nil
+		}
+		return uc.createPost(ctx, inc)
+	}
+
+	existing.Update(input.Name(), severity, status, input.AlertsCount, uc.clock.Now())
+	attachment := uc.msgBuilder.BuildIncidentAttachment(existing, uc.callbackURL, uc.keepUIURL)
+	if err := uc.mmClient.UpdatePost(ctx, existing.PostID(), attachment); err != nil {
+		return fmt.Errorf("update incident post: %w", err)
+	}
+
+	if existing.IsClosed() {
+		if err := uc.incidentRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("delete incident: %w", err)
+		}
+		return nil
+	}
+	if err := uc.incidentRepo.Save(ctx, existing); err != nil {
+		return fmt.Errorf("save incident: %w", err)
+	}
+	return nil
+}
+
+func (uc *HandleIncidentUseCase) createPost(ctx context.Context, inc *incident.Incident) error {
+	channelID := uc.channelID
+	if channelID == "" {
+		if channelIDs := uc.channelResolver.ChannelIDsForAlert(inc.Severity().String(), nil); len(channelIDs) > 0 {
+			channelID = channelIDs[0]
+		}
+	}
+
+	attachment := uc.msgBuilder.BuildIncidentAttachment(inc, uc.callbackURL, uc.keepUIURL)
+	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
+	if err != nil {
+		return fmt.Errorf("create incident post: %w", err)
+	}
+
+	inc.Attach(postID, channelID)
+	if err := uc.incidentRepo.Save(ctx, inc); err != nil {
+		return fmt.Errorf("save incident: %w", err)
+	}
+
+	uc.logger.Info("Incident posted",
+		logger.ApplicationFields("incident_posted",
+			slog.String("incident_id", inc.ID()),
+			slog.String("post_id", postID),
+			slog.String("channel_id", channelID),
+		),
+	)
+	incidentsPostedCounter.Inc()
+	return nil
+}
+
+func (uc *HandleIncidentUseCase) parseStartTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	t, err := time.Parse(keepIncidentTimeLayout, value)
+	if err != nil {
+		uc.logger.Warn("Failed to parse incident start_time, using zero value",
+			slog.String("value", value),
+			slog.String("error", err.Error()),
+		)
+		return time.Time{}
+	}
+	return t
+}
+
+// ExecuteImmediate answers a click on an incident button with the post in its
+// processing state. The action itself runs in ExecuteAsync.
+func (uc *HandleIncidentUseCase) ExecuteImmediate(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+	action := input.Context[post.ContextKeyAction]
+	incidentID := input.Context[post.ContextKeyIncidentID]
+	attachmentJSON := input.Context[post.ContextKeyAttachmentJSON]
+
+	uc.logger.Info("Incident callback received (immediate phase)",
+		logger.ApplicationFields("incident_callback_received",
+			slog.String("action", action),
+			slog.String("incident_id", incidentID),
+			slog.String("user_id", input.UserID),
+			slog.String("post_id", input.PostID),
+		),
+	)
+
+	if action != post.ActionAcknowledge && action != post.ActionResolve {
+		return nil, fmt.Errorf("unsupported incident action: %q", action)
+	}
+	if incidentID == "" {
+		return nil, fmt.Errorf("missing required context field: incident_id")
+	}
+	if attachmentJSON == "" {
+		return nil, fmt.Errorf("missing required context field: attachment_json")
+	}
+
+	if uc.permissions != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		allowed := uc.permissions.Allowed(ctx, input.UserID, action, input.Context[post.ContextKeySeverity])
+		cancel()
+		if !allowed {
+			uc.logger.Info("Incident callback denied by permission rules",
+				logger.ApplicationFields("incident_callback_denied",
+					slog.String("action", action),
+					slog.String("incident_id", incidentID),
+					slog.String("user_id", input.UserID),
+				),
+			)
+			return &dto.CallbackOutput{Ephemeral: fmt.Sprintf("You are not permitted to %s this incident.", action)}, nil
+		}
+	}
+
+	processingAttachment, err := uc.msgBuilder.BuildProcessingAttachment(attachmentJSON, action)
+	if err != nil {
+		return nil, fmt.Errorf("build processing attachment: %w", err)
+	}
+
+	return &dto.CallbackOutput{
+		Attachment: dto.NewAttachmentDTO(processingAttachment),
+	}, nil
+}
+
+// ExecuteAsync changes the incident status in Keep and updates the post. On
+// failure the post gets its buttons back and the thread says what went wrong.
+func (uc *HandleIncidentUseCase) ExecuteAsync(input dto.MattermostCallbackInput) {
+	action := input.Context[post.ContextKeyAction]
+	incidentID := input.Context[post.ContextKeyIncidentID]
+	attachmentJSON := input.Context[post.ContextKeyAttachmentJSON]
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+
+		asyncCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		inc, err := uc.incidentRepo.FindByID(asyncCtx, incidentID)
+		if err != nil {
+			uc.logger.Error("Failed to find incident in async phase",
+				slog.String("incident_id", incidentID),
+				slog.String("error", err.Error()),
+			)
+			uc.restorePost(asyncCtx, input.PostID, attachmentJSON)
+			uc.reply(asyncCtx, input.ChannelID, input.PostID, "⚠️ This incident is no longer tracked by the bridge.")
+			return
+		}
+
+		status := incident.StatusAcknowledged
+		if action == post.ActionResolve {
+			status = incident.StatusResolved
+		}
+
+		username := uc.lookupUsername(asyncCtx, input.UserID)
+
+		if err := uc.keepClient.ChangeIncidentStatus(asyncCtx, incidentID, status); err != nil {
+			uc.logger.Error("Failed to change incident status in Keep",
+				slog.String("incident_id", incidentID),
+				slog.String("status", status),
+				slog.String("error", err.Error()),
+			)
+			uc.updatePost(asyncCtx, input.PostID, uc.msgBuilder.BuildIncidentAttachment(inc, uc.callbackURL, uc.keepUIURL))
+			uc.reply(asyncCtx, input.ChannelID, input.PostID, fmt.Sprintf("⚠️ Failed to %s the incident in Keep, please try again.", action))
+			return
+		}
+
+		var replyMsg string
+		if action == post.ActionResolve {
+			inc.Resolve(uc.clock.Now())
+			replyMsg = fmt.Sprintf("Incident resolved by @%s", username)
+		} else {
+			inc.Acknowledge(username, uc.clock.Now())
+			replyMsg = fmt.Sprintf("Incident acknowledged by @%s", username)
+		}
+
+		uc.updatePost(asyncCtx, input.PostID, uc.msgBuilder.BuildIncidentAttachment(inc, uc.callbackURL, uc.keepUIURL))
+		uc.reply(asyncCtx, input.ChannelID, input.PostID, replyMsg)
+
+		if inc.IsClosed() {
+			err = uc.incidentRepo.Delete(asyncCtx, incidentID)
+		} else {
+			err = uc.incidentRepo.Save(asyncCtx, inc)
+		}
+		if err != nil {
+			uc.logger.Error("Failed to store incident",
+				slog.String("incident_id", incidentID),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		uc.logger.Info("Incident callback processed (async)",
+			logger.ApplicationFields("incident_callback_processed_async",
+				slog.String("action", action),
+				slog.String("incident_id", incidentID),
+				slog.String("username", username),
+			),
+		)
+		incidentActionsCounter(action).Inc()
+	}()
+}
+
+// Wait blocks until every ExecuteAsync has finished.
+func (uc *HandleIncidentUseCase) Wait() {
+	uc.wg.Wait()
+}
+
+func (uc *HandleIncidentUseCase) lookupUsername(ctx context.Context, userID string) string {
+	username, err := uc.mmClient.GetUser(ctx, userID)
+	if err != nil {
+		uc.logger.Warn("Failed to get username, using user_id",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+		return userID
+	}
+	return username
+}
+
+// restorePost shows the attachment the buttons were rendered from, without
+// the buttons.
+func (uc *HandleIncidentUseCase) restorePost(ctx context.Context, postID, attachmentJSON string) {
+	attachment, err := post.AttachmentFromJSON(attachmentJSON)
+	if err != nil {
+		uc.logger.Error("Failed to restore incident post",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	uc.updatePost(ctx, postID, *attachment)
+}
+
+func (uc *HandleIncidentUseCase) updatePost(ctx context.Context, postID string, attachment post.Attachment) {
+	if err := uc.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
+		uc.logger.Error("Failed to update incident post",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (uc *HandleIncidentUseCase) reply(ctx context.Context, channelID, postID, message string) {
+	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, message); err != nil {
+		uc.logger.Error("Failed to reply to thread",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+type memoryIncidentRepository struct {
+	incidents map[string]*incident.Incident
+}
+
+func newMemoryIncidentRepository() *memoryIncidentRepository {
+	return &memoryIncidentRepository{incidents: make(map[string]*incident.Incident)}
+}
+
+func (m *memoryIncidentRepository) Save(ctx context.Context, inc *incident.Incident) error {
+	m.incidents[inc.ID()] = inc
+	return nil
+}
+
+func (m *memoryIncidentRepository) FindByID(ctx context.Context, id string) (*incident.Incident, error) {
+	inc, ok := m.incidents[id]
+	if !ok {
+		return nil, incident.ErrNotFound
+	}
+	return inc, nil
+}
+
+func (m *memoryIncidentRepository) Delete(ctx context.Context, id string) error {
+	delete(m.incidents, id)
+	return nil
+}
+
+type incidentReply struct {
+	channelID, rootID, message string
+}
+
+// setupHandleIncidentUseCase records created and updated posts and thread
+// replies. The message builder titles each card with the incident status.
+func setupHandleIncidentUseCase() (*HandleIncidentUseCase, *memoryIncidentRepository, *portmock.MattermostClientMock, *portmock.KeepIncidentClientMock, *[]incidentReply) {
+	repo := newMemoryIncidentRepository()
+	var replies []incidentReply
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-1", nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+		ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+			replies = append(replies, incidentReply{channelID, rootID, message})
+			return nil
+		},
+		GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+			return "alice", nil
+		},
+	}
+	keepClient := &portmock.KeepIncidentClientMock{
+		ChangeIncidentStatusFunc: func(ctx context.Context, incidentID, status string) error {
+			return nil
+		},
+	}
+	msgBuilder := &portmock.IncidentMessageBuilderMock{
+		BuildIncidentAttachmentFunc: func(inc *incident.Incident, callbackURL, keepUIURL string) post.Attachment {
+			return post.Attachment{Title: inc.Status(), TitleLink: callbackURL}
+		},
+		BuildProcessingAttachmentFunc: func(attachmentJSON, action string) (post.Attachment, error) {
+			return post.Attachment{Title: "processing " + action}, nil
+		},
+	}
+	uc := NewHandleIncidentUseCase(
+		repo,
+		keepClient,
+		mmClient,
+		msgBuilder,
+		newChannelResolverMock("channel-critical"),
+		"http://keep.ui",
+		"http://bridge/api/v1/callback/incident",
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+	return uc, repo, mmClient, keepClient, &replies
+}
+
+func incidentInput(status string) dto.KeepIncidentInput {
+	return dto.KeepIncidentInput{
+		ID:                "inc-1",
+		UserGeneratedName: "Database outage",
+		Severity:          "critical",
+		Status:            status,
+		AlertsCount:       3,
+		StartTime:         "2024-06-01T10:00:00.123456",
+	}
+}
+
+func TestHandleIncidentCreatesPost(t *testing.T) {
+	uc, repo, mmClient, _, _ := setupHandleIncidentUseCase()
+
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("firing")))
+
+	require.Len(t, mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "channel-critical", mmClient.CreatePostCalls()[0].ChannelID)
+	assert.Equal(t, "http://bridge/api/v1/callback/incident", mmClient.CreatePostCalls()[0].Attachment.TitleLink)
+
+	inc := repo.incidents["inc-1"]
+	require.NotNil(t, inc)
+	assert.Equal(t, "post-1", inc.PostID())
+	assert.Equal(t, "channel-critical", inc.ChannelID())
+	assert.Equal(t, "Database outage", inc.Name())
+	assert.Equal(t, 3, inc.AlertsCount())
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 123456000, time.UTC), inc.StartedAt())
+}
+
+func TestHandleIncidentUsesConfiguredChannel(t *testing.T) {
+	uc, _, mmClient, _, _ := setupHandleIncidentUseCase()
+	uc.SetChannel("channel-incidents")
+
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("firing")))
+
+	require.Len(t, mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "channel-incidents", mmClient.CreatePostCalls()[0].ChannelID)
+}
+
+func TestHandleIncidentUpdatesExistingPost(t *testing.T) {
+	uc, repo, mmClient, _, _ := setupHandleIncidentUseCase()
+	ctx := context.Background()
+	require.NoError(t, uc.Execute(ctx, incidentInput("firing")))
+
+	input := incidentInput("acknowledged")
+	input.AlertsCount = 5
+	require.NoError(t, uc.Execute(ctx, input))
+
+	assert.Len(t, mmClient.CreatePostCalls(), 1)
+	require.Len(t, mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, "post-1", mmClient.UpdatePostCalls()[0].PostID)
+	assert.Equal(t, incident.StatusAcknowledged, mmClient.UpdatePostCalls()[0].Attachment.Title)
+	assert.Equal(t, 5, repo.incidents["inc-1"].AlertsCount())
+}
+
+func TestHandleIncidentClosedStopsTracking(t *testing.T) {
+	uc, repo, mmClient, _, _ := setupHandleIncidentUseCase()
+	ctx := context.Background()
+	require.NoError(t, uc.Execute(ctx, incidentInput("firing")))
+
+	require.NoError(t, uc.Execute(ctx, incidentInput("resolved")))
+
+	require.Len(t, mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, incident.StatusResolved, mmClient.UpdatePostCalls()[0].Attachment.Title)
+	assert.Empty(t, repo.incidents)
+}
+
+func TestHandleIncidentClosedWithoutPostIgnored(t *testing.T) {
+	uc, repo, mmClient, _, _ := setupHandleIncidentUseCase()
+
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("deleted")))
+
+	assert.Empty(t, mmClient.CreatePostCalls())
+	assert.Empty(t, repo.incidents)
+}
+
+func TestHandleIncidentRejectsInvalidInput(t *testing.T) {
+	uc, _, _, _, _ := setupHandleIncidentUseCase()
+	ctx := context.Background()
+
+	input := incidentInput("firing")
+	input.Severity = "urgent"
+	assert.Error(t, uc.Execute(ctx, input))
+
+	input = incidentInput("exploded")
+	assert.Error(t, uc.Execute(ctx, input))
+
+	input = incidentInput("firing")
+	input.ID = " "
+	assert.Error(t, uc.Execute(ctx, input))
+}
+
+func incidentCallback(action string) dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-1",
+		PostID:    "post-1",
+		ChannelID: "channel-critical",
+		Context: map[string]string{
+			post.ContextKeyAction:         action,
+			post.ContextKeyIncidentID:     "inc-1",
+			post.ContextKeySeverity:       "critical",
+			post.ContextKeyAttachmentJSON: `{"Title":"firing"}`,
+		},
+	}
+}
+
+func TestHandleIncidentCallbackImmediate(t *testing.T) {
+	uc, _, _, _, _ := setupHandleIncidentUseCase()
+
+	result, err := uc.ExecuteImmediate(incidentCallback(post.ActionAcknowledge))
+	require.NoError(t, err)
+	assert.Equal(t, "processing acknowledge", result.Attachment.Title)
+
+	_, err = uc.ExecuteImmediate(incidentCallback(post.ActionSnooze))
+	assert.Error(t, err, "incidents cannot be snoozed")
+
+	input := incidentCallback(post.ActionResolve)
+	delete(input.Context, post.ContextKeyIncidentID)
+	_, err = uc.ExecuteImmediate(input)
+	assert.Error(t, err)
+}
+
+func TestHandleIncidentCallbackAcknowledge(t *testing.T) {
+	uc, repo, mmClient, keepClient, replies := setupHandleIncidentUseCase()
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("firing")))
+
+	uc.ExecuteAsync(incidentCallback(post.ActionAcknowledge))
+	uc.Wait()
+
+	require.Len(t, keepClient.ChangeIncidentStatusCalls(), 1)
+	assert.Equal(t, "inc-1", keepClient.ChangeIncidentStatusCalls()[0].IncidentID)
+	assert.Equal(t, incident.StatusAcknowledged, keepClient.ChangeIncidentStatusCalls()[0].Status)
+
+	require.Len(t, mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, incident.StatusAcknowledged, mmClient.UpdatePostCalls()[0].Attachment.Title)
+	assert.Equal(t, []incidentReply{{"channel-critical", "post-1", "Incident acknowledged by @alice"}}, *replies)
+	assert.Equal(t, "alice", repo.incidents["inc-1"].AcknowledgedBy())
+}
+
+func TestHandleIncidentCallbackResolve(t *testing.T) {
+	uc, repo, _, keepClient, replies := setupHandleIncidentUseCase()
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("firing")))
+
+	uc.ExecuteAsync(incidentCallback(post.ActionResolve))
+	uc.Wait()
+
+	require.Len(t, keepClient.ChangeIncidentStatusCalls(), 1)
+	assert.Equal(t, incident.StatusResolved, keepClient.ChangeIncidentStatusCalls()[0].Status)
+	assert.Equal(t, "Incident resolved by @alice", (*replies)[0].message)
+	assert.Empty(t, repo.incidents)
+}
+
+func TestHandleIncidentCallbackKeepFailure(t *testing.T) {
+	uc, repo, mmClient, keepClient, replies := setupHandleIncidentUseCase()
+	require.NoError(t, uc.Execute(context.Background(), incidentInput("firing")))
+	keepClient.ChangeIncidentStatusFunc = func(ctx context.Context, incidentID, status string) error {
+		return errors.New("keep unavailable")
+	}
+
+	uc.ExecuteAsync(incidentCallback(post.ActionAcknowledge))
+	uc.Wait()
+
+	require.Len(t, mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, incident.StatusFiring, mmClient.UpdatePostCalls()[0].Attachment.Title, "the buttons come back")
+	require.Len(t, *replies, 1)
+	assert.Contains(t, (*replies)[0].message, "Failed to acknowledge the incident")
+	assert.Equal(t, incident.StatusFiring, repo.incidents["inc-1"].Status())
+}
+
+func TestHandleIncidentCallbackUnknownIncident(t *testing.T) {
+	uc, _, mmClient, keepClient, replies := setupHandleIncidentUseCase()
+
+	uc.ExecuteAsync(incidentCallback(post.ActionResolve))
+	uc.Wait()
+
+	assert.Empty(t, keepClient.ChangeIncidentStatusCalls())
+	require.Len(t, mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, "firing", mmClient.UpdatePostCalls()[0].Attachment.Title)
+	assert.Empty(t, mmClient.UpdatePostCalls()[0].Attachment.Actions)
+	require.Len(t, *replies, 1)
+	assert.Contains(t, (*replies)[0].message, "no longer tracked")
+}
+
+func TestHandleIncidentStartTimeFormats(t *testing.T) {
+	uc, _, _, _, _ := setupHandleIncidentUseCase()
+
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), uc.parseStartTime("2024-06-01T10:00:00Z").UTC())
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), uc.parseStartTime("2024-06-01T10:00:00"))
+	assert.True(t, uc.parseStartTime("yesterday").IsZero())
+	assert.True(t, uc.parseStartTime("").IsZero())
+}
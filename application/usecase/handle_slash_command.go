@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	EnrichmentKeyDismissed    = "dismissed"
+	EnrichmentKeyDismissUntil = "dismissUntil"
+
+	// slashListLimit bounds /keep list output so the response stays readable.
+	slashListLimit = 50
+)
+
+const slashCommandUsage = "Usage:\n" +
+	"* `/keep ack <fingerprint>` - acknowledge an alert\n" +
+	"* `/keep resolve <fingerprint>` - resolve an alert\n" +
+	"* `/keep ack-all [severity] [label=value...]` - acknowledge the active alerts of this channel\n" +
+	"* `/keep resolve-all [severity] [label=value...]` - resolve the active alerts of this channel\n" +
+	"* `/keep silence <fingerprint> <duration>` - dismiss an alert in Keep for a while, e.g. `2h`\n" +
+	"* `/keep info <fingerprint>` - show an alert's status and labels\n" +
+	"* `/keep list` - show active alerts"
+
+// HandleSlashCommandUseCase implements the /keep slash command. Acknowledge
+// and resolve go through the same path as the post buttons, for one alert or
+// for all alerts of a channel; silence dismisses the alert in Keep until the
+// given time.
+type HandleSlashCommandUseCase struct {
+	postRepo   post.Repository
+	keepClient port.KeepClient
+	mmClient   port.MattermostClient
+	actions    port.AlertActionUseCase
+	clock      clock.Clock
+	logger     *slog.Logger
+	wg         sync.WaitGroup
+}
+
+func NewHandleSlashCommandUseCase(
+	postRepo post.Repository,
+	keepClient port.KeepClient,
+	mmClient port.MattermostClient,
+	actions port.AlertActionUseCase,
+	logger *slog.Logger,
+) *HandleSlashCommandUseCase {
+	return &HandleSlashCommandUseCase{
+		postRepo:   postRepo,
+		keepClient: keepClient,
+		mmClient:   mmClient,
+		actions:    actions,
+		clock:      clock.Real(),
+		logger:     logger,
+	}
+}
+
+// SetClock replaces the clock used to compute silence deadlines.
+func (uc *HandleSlashCommandUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Execute runs a /keep subcommand. Errors are reserved for failures the
+// caller cannot explain to the user; bad input produces an ephemeral reply.
+func (uc *HandleSlashCommandUseCase) Execute(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error) {
+	args := strings.Fields(input.Text)
+	sub := "help"
+	if len(args) > 0 {
+		sub = strings.ToLower(args[0])
+		args = args[1:]
+	}
+
+	uc.logger.Info("Slash command received",
+		logger.ApplicationFields("slash_command_received",
+			slog.String("subcommand", sub),
+			slog.String("user_id", input.UserID),
+			slog.String("channel_id", input.ChannelID),
+		),
+	)
+
+	var (
+		out *dto.SlashCommandOutput
+		err error
+	)
+	switch sub {
+	case "ack", "acknowledge":
+		sub = "ack"
+		out, err = uc.runAction(ctx, post.ActionAcknowledge, "acknowledged", args, input.UserID)
+	case "resolve":
+		out, err = uc.runAction(ctx, post.ActionResolve, "resolved", args, input.UserID)
+	case "ack-all", "acknowledge-all":
+		sub = "ack-all"
+		out, err = uc.runBulkAction(ctx, post.ActionAcknowledge, "acknowledged", args, input)
+	case "resolve-all":
+		out, err = uc.runBulkAction(ctx, post.ActionResolve, "resolved", args, input)
+	case "silence":
+		out, err = uc.silence(ctx, args, input.UserID)
+	case "info":
+		out, err = uc.info(ctx, args)
+	case "list":
+		out, err = uc.list(ctx)
+	case "help":
+		out = ephemeral(slashCommandUsage)
+	default:
+		out = ephemeral(fmt.Sprintf("Unknown subcommand `%s`.\n%s", sub, slashCommandUsage))
+		sub = "unknown"
+	}
+
+	slashCommandsCounter(sub).Inc()
+	return out, err
+}
+
+func (uc *HandleSlashCommandUseCase) runAction(ctx context.Context, action, verb string, args []string, userID string) (*dto.SlashCommandOutput, error) {
+	if len(args) != 1 {
+		return ephemeral(slashCommandUsage), nil
+	}
+	fingerprint := args[0]
+
+	if err := uc.actions.ExecuteAction(ctx, action, fingerprint, userID); err != nil {
+		if errors.Is(err, post.ErrNotFound) {
+			return ephemeral(fmt.Sprintf("No active alert with fingerprint `%s`.", fingerprint)), nil
+		}
+		if errors.Is(err, ErrNotPermitted) {
+			return ephemeral(fmt.Sprintf("You are not permitted to %s alert `%s`.", action, fingerprint)), nil
+		}
+		return nil, fmt.Errorf("%s alert: %w", action, err)
+	}
+
+	return ephemeral(fmt.Sprintf("Alert `%s` %s.", fingerprint, verb)), nil
+}
+
+func (uc *HandleSlashCommandUseCase) silence(ctx context.Context, args []string, userID string) (*dto.SlashCommandOutput, error) {
+	if len(args) != 2 {
+		return ephemeral(slashCommandUsage), nil
+	}
+	fingerprintStr := args[0]
+
+	fingerprint, err := alert.NewFingerprint(fingerprintStr)
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Invalid fingerprint `%s`.", fingerprintStr)), nil
+	}
+
+	duration, err := time.ParseDuration(args[1])
+	if err != nil || duration <= 0 {
+		return ephemeral(fmt.Sprintf("Invalid duration `%s`, expected something like `30m` or `2h`.", args[1])), nil
+	}
+	until := uc.clock.Now().Add(duration).UTC()
+
+	enrichments := map[string]string{
+		EnrichmentKeyDismissed:    "true",
+		EnrichmentKeyDismissUntil: until.Format(time.RFC3339),
+	}
+	if err := uc.keepClient.EnrichAlert(ctx, fingerprintStr, enrichments, port.EnrichOptions{DisposeOnNewAlert: false}); err != nil {
+		return nil, fmt.Errorf("silence alert: %w", err)
+	}
+
+	username, err := uc.mmClient.GetUser(ctx, userID)
+	if err != nil {
+		username = userID
+	}
+
+	if existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); err == nil {
+		replyMsg := fmt.Sprintf("Silenced for %s by @%s", duration, username)
+		if err := uc.mmClient.ReplyToThread(ctx, existing.ChannelID(), existing.PostID(), replyMsg); err != nil {
+			uc.logger.Error("Failed to reply to thread",
+				slog.String("post_id", existing.PostID()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	uc.logger.Info("Alert silenced",
+		logger.ApplicationFields("alert_silenced",
+			slog.String("fingerprint", fingerprintStr),
+			slog.String("username", username),
+			slog.Time("until", until),
+		),
+	)
+
+	return ephemeral(fmt.Sprintf("Alert `%s` silenced until %s.", fingerprintStr, until.Format(time.RFC3339))), nil
+}
+
+func (uc *HandleSlashCommandUseCase) info(ctx context.Context, args []string) (*dto.SlashCommandOutput, error) {
+	if len(args) != 1 {
+		return ephemeral(slashCommandUsage), nil
+	}
+	fingerprintStr := args[0]
+
+	if _, err := alert.NewFingerprint(fingerprintStr); err != nil {
+		return ephemeral(fmt.Sprintf("Invalid fingerprint `%s`.", fingerprintStr)), nil
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprintStr)
+	if err != nil {
+		return nil, fmt.Errorf("get alert from keep: %w", err)
+	}
+
+	status := keepAlert.Status
+	if enriched := keepAlert.Enrichments[EnrichmentKeyStatus]; enriched != "" {
+		status = enriched
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n\n", keepAlert.Name)
+	fmt.Fprintf(&b, "* Fingerprint: `%s`\n", fingerprintStr)
+	fmt.Fprintf(&b, "* Severity: %s\n", keepAlert.Severity)
+	fmt.Fprintf(&b, "* Status: %s\n", status)
+	if assignee := keepAlert.Enrichments[EnrichmentKeyAssignee]; assignee != "" {
+		fmt.Fprintf(&b, "* Assignee: %s\n", assignee)
+	}
+	if !keepAlert.FiringStartTime.IsZero() {
+		fmt.Fprintf(&b, "* Firing since: %s\n", keepAlert.FiringStartTime.UTC().Format(time.RFC3339))
+	}
+
+	if len(keepAlert.Labels) > 0 {
+		keys := make([]string, 0, len(keepAlert.Labels))
+		for k := range keepAlert.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("\n| Label | Value |\n|:--|:--|\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "| %s | `%s` |\n", strings.ReplaceAll(k, "|", `\|`), strings.ReplaceAll(keepAlert.Labels[k], "|", `\|`))
+		}
+	}
+
+	return ephemeral(strings.TrimRight(b.String(), "\n")), nil
+}
+
+func (uc *HandleSlashCommandUseCase) list(ctx context.Context) (*dto.SlashCommandOutput, error) {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find all active posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return ephemeral("No active alerts."), nil
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		ri, rj := posts[i].Severity().Rank(), posts[j].Severity().Rank()
+		if ri != rj {
+			return ri > rj
+		}
+		return posts[i].FiringStartTime().Before(posts[j].FiringStartTime())
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Active alerts: %d**\n\n", len(posts))
+	b.WriteString("| Severity | Alert | Fingerprint | Firing since |\n")
+	b.WriteString("|:--|:--|:--|:--|\n")
+	for i, p := range posts {
+		if i == slashListLimit {
+			fmt.Fprintf(&b, "\n…and %d more", len(posts)-slashListLimit)
+			break
+		}
+		fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n",
+			p.Severity().Value(),
+			strings.ReplaceAll(p.AlertName(), "|", `\|`),
+			p.Fingerprint().Value(),
+			p.FiringStartTime().UTC().Format(time.RFC3339),
+		)
+	}
+
+	return ephemeral(strings.TrimRight(b.String(), "\n")), nil
+}
+
+func ephemeral(text string) *dto.SlashCommandOutput {
+	return &dto.SlashCommandOutput{ResponseType: dto.SlashResponseEphemeral, Text: text}
+}
@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type slashCommandFixture struct {
+	uc         *HandleSlashCommandUseCase
+	postRepo   *postStore
+	keepClient *portmock.KeepClientMock
+	mmClient   *portmock.MattermostClientMock
+	actions    *portmock.AlertActionUseCaseMock
+	clock      *clock.Fake
+}
+
+func setupSlashCommandUseCase() *slashCommandFixture {
+	f := &slashCommandFixture{
+		postRepo: newPostStore(),
+		keepClient: &portmock.KeepClientMock{
+			EnrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+				return nil
+			},
+		},
+		mmClient: &portmock.MattermostClientMock{
+			GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+				return "alice", nil
+			},
+			ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+				return nil
+			},
+			CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+				return "bulk-root", nil
+			},
+		},
+		actions: &portmock.AlertActionUseCaseMock{
+			ExecuteActionFunc: func(ctx context.Context, action, fingerprint, userID string) error {
+				return nil
+			},
+		},
+		clock: clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	f.uc = NewHandleSlashCommandUseCase(f.postRepo, f.keepClient, f.mmClient, f.actions, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	f.uc.SetClock(f.clock)
+	return f
+}
+
+func (f *slashCommandFixture) run(t *testing.T, text string) *dto.SlashCommandOutput {
+	t.Helper()
+	out, err := f.uc.Execute(context.Background(), dto.SlashCommandInput{UserID: "user-1", ChannelID: "ch-1", Command: "/keep", Text: text})
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, dto.SlashResponseEphemeral, out.ResponseType)
+	return out
+}
+
+func TestHandleSlashCommand_Ack(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	out := f.run(t, "ack fp-1")
+
+	calls := f.actions.ExecuteActionCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, post.ActionAcknowledge, calls[0].Action)
+	assert.Equal(t, "fp-1", calls[0].Fingerprint)
+	assert.Equal(t, "user-1", calls[0].UserID)
+	assert.Equal(t, "Alert `fp-1` acknowledged.", out.Text)
+}
+
+func TestHandleSlashCommand_Resolve(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	out := f.run(t, "RESOLVE fp-1")
+
+	calls := f.actions.ExecuteActionCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, post.ActionResolve, calls[0].Action)
+	assert.Equal(t, "Alert `fp-1` resolved.", out.Text)
+}
+
+func TestHandleSlashCommand_ActionUntracked(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	f.actions.ExecuteActionFunc = func(ctx context.Context, action, fingerprint, userID string) error {
+		return fmt.Errorf("find post: %w", post.ErrNotFound)
+	}
+
+	out := f.run(t, "ack fp-missing")
+	assert.Contains(t, out.Text, "No active alert with fingerprint `fp-missing`")
+}
+
+func TestHandleSlashCommand_ActionError(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	f.actions.ExecuteActionFunc = func(ctx context.Context, action, fingerprint, userID string) error {
+		return errors.New("keep down")
+	}
+
+	_, err := f.uc.Execute(context.Background(), dto.SlashCommandInput{Text: "resolve fp-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "keep down")
+}
+
+func TestHandleSlashCommand_Silence(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	fp := alert.RestoreFingerprint("fp-1")
+	f.postRepo.posts["fp-1"] = post.NewPost("post-1", "ch-alerts", fp, "Disk full", alert.RestoreSeverity("high"), time.Now(), time.Now())
+
+	out := f.run(t, "silence fp-1 2h")
+
+	enrichCalls := f.keepClient.EnrichAlertCalls()
+	require.Len(t, enrichCalls, 1)
+	assert.Equal(t, "fp-1", enrichCalls[0].Fingerprint)
+	assert.Equal(t, map[string]string{
+		EnrichmentKeyDismissed:    "true",
+		EnrichmentKeyDismissUntil: "2024-03-01T14:00:00Z",
+	}, enrichCalls[0].Enrichments)
+	assert.False(t, enrichCalls[0].Opts.DisposeOnNewAlert)
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "post-1", replies[0].RootID)
+	assert.Equal(t, "Silenced for 2h0m0s by @alice", replies[0].Message)
+
+	assert.Equal(t, "Alert `fp-1` silenced until 2024-03-01T14:00:00Z.", out.Text)
+}
+
+func TestHandleSlashCommand_SilenceUntrackedSkipsReply(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	f.run(t, "silence fp-other 30m")
+
+	assert.Len(t, f.keepClient.EnrichAlertCalls(), 1)
+	assert.Empty(t, f.mmClient.ReplyToThreadCalls())
+}
+
+func TestHandleSlashCommand_SilenceInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "missing duration", text: "silence fp-1", want: "Usage:"},
+		{name: "bad duration", text: "silence fp-1 soon", want: "Invalid duration `soon`"},
+		{name: "negative duration", text: "silence fp-1 -1h", want: "Invalid duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setupSlashCommandUseCase()
+			out := f.run(t, tt.text)
+			assert.Contains(t, out.Text, tt.want)
+			assert.Empty(t, f.keepClient.EnrichAlertCalls())
+		})
+	}
+}
+
+func TestHandleSlashCommand_SilenceKeepError(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	f.keepClient.EnrichAlertFunc = func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+		return errors.New("keep down")
+	}
+
+	_, err := f.uc.Execute(context.Background(), dto.SlashCommandInput{Text: "silence fp-1 1h"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "silence alert")
+}
+
+func TestHandleSlashCommand_List(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	base := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, p := range []struct {
+		fp, name, severity string
+		start              time.Time
+	}{
+		{"fp-low", "Low disk", "low", base},
+		{"fp-crit-late", "DB down", "critical", base.Add(time.Hour)},
+		{"fp-crit-early", "API | errors", "critical", base},
+	} {
+		f.postRepo.posts[p.fp] = post.NewPost("post-"+p.fp, "ch", alert.RestoreFingerprint(p.fp), p.name, alert.RestoreSeverity(p.severity), p.start, time.Now())
+	}
+
+	out := f.run(t, "list")
+
+	lines := strings.Split(out.Text, "\n")
+	require.Len(t, lines, 7)
+	assert.Equal(t, "**Active alerts: 3**", lines[0])
+	assert.Equal(t, "| critical | API \\| errors | `fp-crit-early` | 2024-03-01T10:00:00Z |", lines[4])
+	assert.Contains(t, lines[5], "`fp-crit-late`")
+	assert.Contains(t, lines[6], "`fp-low`")
+}
+
+func TestHandleSlashCommand_ListEmpty(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	out := f.run(t, "list")
+	assert.Equal(t, "No active alerts.", out.Text)
+}
+
+func TestHandleSlashCommand_ListTruncates(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	for i := 0; i < slashListLimit+5; i++ {
+		fp := fmt.Sprintf("fp-%d", i)
+		f.postRepo.posts[fp] = post.NewPost("post", "ch", alert.RestoreFingerprint(fp), "Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	}
+
+	out := f.run(t, "list")
+	assert.True(t, strings.HasSuffix(out.Text, "…and 5 more"))
+}
+
+func TestHandleSlashCommand_Info(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	keepAlert := firingKeepAlert()
+	keepAlert.FiringStartTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	keepAlert.Labels["team"] = "a|b"
+	keepAlert.Enrichments = map[string]string{EnrichmentKeyStatus: alert.StatusAcknowledged, EnrichmentKeyAssignee: "alice"}
+	f.keepClient.GetAlertFunc = func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+		assert.Equal(t, "fp-1", fingerprint)
+		return keepAlert, nil
+	}
+
+	out := f.run(t, "info fp-1")
+
+	assert.Equal(t, "**Disk full**\n\n"+
+		"* Fingerprint: `fp-1`\n"+
+		"* Severity: critical\n"+
+		"* Status: acknowledged\n"+
+		"* Assignee: alice\n"+
+		"* Firing since: 2024-03-01T10:00:00Z\n"+
+		"\n| Label | Value |\n|:--|:--|\n"+
+		"| host | `db-1` |\n"+
+		"| team | `a\\|b` |", out.Text)
+}
+
+func TestHandleSlashCommand_InfoErrors(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	assert.True(t, strings.HasPrefix(f.run(t, "info").Text, "Usage:"))
+
+	f.keepClient.GetAlertFunc = func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+		return nil, errors.New("keep down")
+	}
+	_, err := f.uc.Execute(context.Background(), dto.SlashCommandInput{Text: "info fp-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get alert from keep")
+}
+
+func TestHandleSlashCommand_HelpAndUnknown(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	assert.True(t, strings.HasPrefix(f.run(t, "").Text, "Usage:"))
+	assert.True(t, strings.HasPrefix(f.run(t, "help").Text, "Usage:"))
+
+	out := f.run(t, "reboot prod")
+	assert.True(t, strings.HasPrefix(out.Text, "Unknown subcommand `reboot`."))
+	assert.Empty(t, f.actions.ExecuteActionCalls())
+}
+
+func TestHandleSlashCommand_ResolveAll(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	base := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 12; i++ {
+		fp := fmt.Sprintf("fp-%02d", i)
+		f.postRepo.posts[fp] = post.NewPost("post-"+fp, "ch-1", alert.RestoreFingerprint(fp), "Alert", alert.RestoreSeverity("high"), base.Add(time.Duration(i)*time.Minute), time.Now())
+	}
+	f.postRepo.posts["fp-other"] = post.NewPost("post-other", "ch-2", alert.RestoreFingerprint("fp-other"), "Alert", alert.RestoreSeverity("high"), base, time.Now())
+	f.actions.ExecuteActionFunc = func(ctx context.Context, action, fingerprint, userID string) error {
+		switch fingerprint {
+		case "fp-03":
+			return fmt.Errorf("find post: %w", post.ErrNotFound)
+		case "fp-04":
+			return ErrNotPermitted
+		case "fp-05":
+			return errors.New("keep down")
+		}
+		return nil
+	}
+
+	out := f.run(t, "resolve-all")
+	f.uc.Wait()
+
+	assert.Equal(t, "Started to resolve 12 alerts, see the thread for progress.", out.Text)
+	posts := f.mmClient.CreatePostCalls()
+	require.Len(t, posts, 1)
+	assert.Equal(t, "ch-1", posts[0].ChannelID)
+	assert.Equal(t, "@alice started to resolve 12 alerts in this channel.", posts[0].Attachment.Text)
+
+	calls := f.actions.ExecuteActionCalls()
+	require.Len(t, calls, 12, "alerts of other channels are left alone")
+	assert.Equal(t, "fp-00", calls[0].Fingerprint, "oldest first")
+	for _, call := range calls {
+		assert.Equal(t, post.ActionResolve, call.Action)
+		assert.Equal(t, "user-1", call.UserID)
+	}
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 2)
+	assert.Equal(t, "bulk-root", replies[0].RootID)
+	assert.Equal(t, "10 of 12 alerts handled…", replies[0].Message)
+	assert.Equal(t, "Done: 9 resolved, 1 already gone, 1 not permitted, 1 failed: `fp-05`.", replies[1].Message)
+}
+
+func TestHandleSlashCommand_AckAllFilters(t *testing.T) {
+	f := setupSlashCommandUseCase()
+	base := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	add := func(fp, severity string, labels map[string]string, acknowledged bool) {
+		p := post.NewPost("post-"+fp, "ch-1", alert.RestoreFingerprint(fp), "Alert", alert.RestoreSeverity(severity), base, time.Now())
+		p.SetLabels(labels)
+		if acknowledged {
+			p.ShowStatus(alert.StatusAcknowledged)
+		}
+		f.postRepo.posts[fp] = p
+	}
+	add("fp-match", "critical", map[string]string{"env": "prod"}, false)
+	add("fp-acked", "critical", map[string]string{"env": "prod"}, true)
+	add("fp-staging", "critical", map[string]string{"env": "staging"}, false)
+	add("fp-high", "high", map[string]string{"env": "prod"}, false)
+
+	out := f.run(t, "ack-all CRITICAL env=prod")
+	f.uc.Wait()
+
+	assert.Equal(t, "Started to acknowledge 1 critical alert with env=prod, see the thread for progress.", out.Text)
+	calls := f.actions.ExecuteActionCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, post.ActionAcknowledge, calls[0].Action)
+	assert.Equal(t, "fp-match", calls[0].Fingerprint)
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Done: 1 acknowledged.", replies[0].Message)
+}
+
+func TestHandleSlashCommand_BulkActionInvalidInput(t *testing.T) {
+	f := setupSlashCommandUseCase()
+
+	out := f.run(t, "resolve-all urgent")
+	assert.True(t, strings.HasPrefix(out.Text, "Invalid severity `urgent`."))
+
+	out = f.run(t, "resolve-all =prod")
+	assert.True(t, strings.HasPrefix(out.Text, "Invalid label selector `=prod`."))
+
+	out = f.run(t, "resolve-all critical")
+	assert.Equal(t, "No active critical alerts to resolve in this channel.", out.Text)
+	assert.Empty(t, f.mmClient.CreatePostCalls())
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxLabelDiffEntries caps how many label changes one thread reply lists.
+const maxLabelDiffEntries = 10
+
+// labelDiff lists how an alert's labels changed between two webhooks, each
+// entry ready to render and sorted by label name.
+type labelDiff struct {
+	added   []string
+	removed []string
+	changed []string
+}
+
+func diffLabels(previous, current map[string]string) labelDiff {
+	var d labelDiff
+	for name, value := range current {
+		old, ok := previous[name]
+		switch {
+		case !ok:
+			d.added = append(d.added, fmt.Sprintf("`%s=%s`", name, value))
+		case old != value:
+			d.changed = append(d.changed, fmt.Sprintf("`%s`: `%s` → `%s`", name, old, value))
+		}
+	}
+	for name, value := range previous {
+		if _, ok := current[name]; !ok {
+			d.removed = append(d.removed, fmt.Sprintf("`%s=%s`", name, value))
+		}
+	}
+	slices.Sort(d.added)
+	slices.Sort(d.removed)
+	slices.Sort(d.changed)
+	return d
+}
+
+func (d labelDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+// message renders the diff as one compact thread reply.
+func (d labelDiff) message() string {
+	var parts []string
+	listed := 0
+	section := func(title string, entries []string) {
+		if len(entries) == 0 || listed >= maxLabelDiffEntries {
+			return
+		}
+		entries = entries[:min(len(entries), maxLabelDiffEntries-listed)]
+		listed += len(entries)
+		parts = append(parts, title+" "+strings.Join(entries, ", "))
+	}
+	section("added", d.added)
+	section("removed", d.removed)
+	section("changed", d.changed)
+
+	msg := "🏷️ Labels changed: " + strings.Join(parts, "; ")
+	if total := len(d.added) + len(d.removed) + len(d.changed); total > listed {
+		msg += fmt.Sprintf(" and %d more", total-listed)
+	}
+	return msg
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLabels(t *testing.T) {
+	previous := map[string]string{"team": "payments", "pod": "api-1", "replica": "1"}
+	current := map[string]string{"team": "payments", "replica": "2", "node": "n-7"}
+
+	diff := diffLabels(previous, current)
+	assert.False(t, diff.empty())
+	assert.Equal(t, "🏷️ Labels changed: added `node=n-7`; removed `pod=api-1`; changed `replica`: `1` → `2`", diff.message())
+
+	assert.True(t, diffLabels(previous, previous).empty())
+	assert.True(t, diffLabels(map[string]string{}, map[string]string{}).empty())
+}
+
+func TestDiffLabelsCapsEntries(t *testing.T) {
+	current := make(map[string]string)
+	for i := range 12 {
+		current[fmt.Sprintf("l%02d", i)] = "x"
+	}
+	msg := diffLabels(map[string]string{}, current).message()
+	assert.Contains(t, msg, "`l09=x`")
+	assert.NotContains(t, msg, "`l10=x`")
+	assert.Contains(t, msg, " and 2 more")
+}
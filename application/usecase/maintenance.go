@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// MaintenanceMonitor reports which maintenance windows are open, for the
+// readiness endpoint and the maintenance_windows_open gauge, and logs when
+// windows open and close.
+type MaintenanceMonitor struct {
+	schedule port.MaintenanceSchedule
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	mu   sync.Mutex
+	open []string
+}
+
+func NewMaintenanceMonitor(schedule port.MaintenanceSchedule, logger *slog.Logger) *MaintenanceMonitor {
+	return &MaintenanceMonitor{
+		schedule: schedule,
+		clock:    clock.Real(),
+		logger:   logger,
+	}
+}
+
+// SetClock replaces the clock windows are evaluated against.
+func (m *MaintenanceMonitor) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// OpenWindows returns the names of the windows open now.
+func (m *MaintenanceMonitor) OpenWindows() []string {
+	return m.schedule.OpenWindows(m.clock.Now())
+}
+
+// Refresh updates the gauge and logs windows that opened or closed since the
+// last run. It runs as a background job.
+func (m *MaintenanceMonitor) Refresh(ctx context.Context) error {
+	open := m.OpenWindows()
+	maintenanceWindowsOpenGauge.Set(float64(len(open)))
+
+	m.mu.Lock()
+	previous := m.open
+	m.open = open
+	m.mu.Unlock()
+
+	for _, name := range open {
+		if !slices.Contains(previous, name) {
+			m.logger.Info("Maintenance window opened",
+				logger.ApplicationFields("maintenance_window_opened", slog.String("window", name)),
+			)
+		}
+	}
+	for _, name := range previous {
+		if !slices.Contains(open, name) {
+			m.logger.Info("Maintenance window closed",
+				logger.ApplicationFields("maintenance_window_closed", slog.String("window", name)),
+			)
+		}
+	}
+	return nil
+}
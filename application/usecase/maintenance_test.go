@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type openWindowsSchedule struct {
+	open map[time.Time][]string
+}
+
+func (s *openWindowsSchedule) WindowForAlert(severity string, labels map[string]string, now time.Time) (port.MaintenanceWindow, bool) {
+	return port.MaintenanceWindow{}, false
+}
+
+func (s *openWindowsSchedule) OpenWindows(now time.Time) []string {
+	return s.open[now]
+}
+
+func TestMaintenanceMonitor(t *testing.T) {
+	start := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	schedule := &openWindowsSchedule{open: map[time.Time][]string{
+		start:                      {"db-upgrade", "nightly-backup"},
+		start.Add(time.Minute):     {"nightly-backup"},
+		start.Add(2 * time.Minute): nil,
+	}}
+	fake := clock.NewFake(start)
+	monitor := NewMaintenanceMonitor(schedule, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	monitor.SetClock(fake)
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"db-upgrade", "nightly-backup"}, monitor.OpenWindows())
+	require.NoError(t, monitor.Refresh(ctx))
+	assert.Equal(t, float64(2), maintenanceWindowsOpenGauge.Get())
+
+	fake.Advance(time.Minute)
+	require.NoError(t, monitor.Refresh(ctx))
+	assert.Equal(t, float64(1), maintenanceWindowsOpenGauge.Get())
+
+	fake.Advance(time.Minute)
+	require.NoError(t, monitor.Refresh(ctx))
+	assert.Equal(t, float64(0), maintenanceWindowsOpenGauge.Get())
+}
@@ -11,9 +11,23 @@ var (
 	alertResolveCounter     = metrics.NewCounter(`alerts_updated_total{action="resolve"}`)
 	alertAckCounter         = metrics.NewCounter(`alerts_updated_total{action="acknowledge"}`)
 	alertUnackCounter       = metrics.NewCounter(`alerts_updated_total{action="unacknowledge"}`)
+	alertAssignCounter      = metrics.NewCounter(`alerts_updated_total{action="assign"}`)
 	alertSuppressedCounter  = metrics.NewCounter(`alerts_updated_total{action="suppressed"}`)
 	alertPendingCounter     = metrics.NewCounter(`alerts_updated_total{action="pending"}`)
 	alertMaintenanceCounter = metrics.NewCounter(`alerts_updated_total{action="maintenance"}`)
+	alertSnoozeCounter      = metrics.NewCounter(`alerts_updated_total{action="snooze"}`)
+	alertUnsnoozeCounter    = metrics.NewCounter(`alerts_updated_total{action="unsnooze"}`)
+	alertAckExpiredCounter  = metrics.NewCounter(`alerts_updated_total{action="ack_expired"}`)
+	alertDismissedCounter   = metrics.NewCounter(`alerts_updated_total{action="dismissed"}`)
+	alertMergedCounter      = metrics.NewCounter(`alerts_updated_total{action="merged"}`)
+
+	alertSnoozedRefireCounter = metrics.NewCounter(`alerts_snoozed_refires_total`)
+
+	alertSeverityChangesCounter = func(direction string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alert_severity_changes_total{direction="` + direction + `"}`)
+	}
+	alertsMovedCounter       = metrics.NewCounter(`alerts_moved_total`)
+	alertLabelChangesCounter = metrics.NewCounter(`alert_label_changes_total`)
 
 	alertsReceivedCounter = func(severity, status string) *metrics.Counter {
 		return metrics.GetOrCreateCounter(`alerts_received_total{severity="` + severity + `",status="` + status + `"}`)
@@ -21,9 +35,33 @@ var (
 	alertsPostedCounter = func(severity, channel string) *metrics.Counter {
 		return metrics.GetOrCreateCounter(`alerts_posted_total{severity="` + severity + `",channel="` + channel + `"}`)
 	}
+	alertCopiesPostedCounter = func(severity, channel string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alert_copies_posted_total{severity="` + severity + `",channel="` + channel + `"}`)
+	}
 	callbacksReceivedCounter = func(action string) *metrics.Counter {
 		return metrics.GetOrCreateCounter(`callbacks_received_total{action="` + action + `"}`)
 	}
+	callbacksDeniedCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`callbacks_denied_total{action="` + action + `"}`)
+	}
+	// Retry buttons clicked, by the action they run again.
+	callbacksRetriedCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`callbacks_retried_total{action="` + action + `"}`)
+	}
+	// Asynchronous callback phases per outcome: succeeded, failed or
+	// timed_out.
+	callbacksAsyncCounter = func(status string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`callbacks_async_total{status="` + status + `"}`)
+	}
+	// Actions clicked while Keep was unavailable; result is queued, rejected
+	// or applied.
+	callbacksQueuedCounter = func(result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`callbacks_keep_unavailable_total{result="` + result + `"}`)
+	}
+	callbackQueueLengthGauge  = metrics.NewGauge(`callbacks_queued`, nil)
+	pendingKeepActionsCounter = func(result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`keep_pending_actions_total{result="` + result + `"}`)
+	}
 
 	// Retry metrics for assignee fetching
 	assigneeRetryAttempts = func(attempt int) *metrics.Counter {
@@ -32,6 +70,12 @@ var (
 	assigneeRetrySuccess   = metrics.NewCounter(`assignee_retry_result_total{result="success"}`)
 	assigneeRetryExhausted = metrics.NewCounter(`assignee_retry_result_total{result="exhausted"}`)
 	assigneeRetryError     = metrics.NewCounter(`assignee_retry_result_total{result="error"}`)
+	assigneeRetryDeadline  = metrics.NewCounter(`assignee_retry_result_total{result="deadline"}`)
+	assigneeResolveSeconds = metrics.NewHistogram(`assignee_resolve_duration_seconds`)
+
+	assigneeUnresolvedCounter = func(reason string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`assignee_unresolved_total{reason="` + reason + `"}`)
+	}
 
 	// Polling metrics
 	pollExecutionsCounter      = metrics.NewCounter(`poll_executions_total`)
@@ -40,4 +84,230 @@ var (
 	pollErrorsCounter          = metrics.NewCounter(`poll_errors_total`)
 	pollActivePostsGauge       = metrics.NewGauge(`poll_active_posts_count`, nil)
 	pollDurationSeconds        = metrics.NewHistogram(`poll_duration_seconds`)
+	pollAlertsComparedCounter  = metrics.NewCounter(`poll_alerts_compared_total`)
+	pollBackoffSecondsGauge    = metrics.NewGauge(`poll_backoff_seconds`, nil)
+	pollEnrichmentsRefreshed   = metrics.NewCounter(`poll_enrichments_refreshed_total`)
+	pollPostsRecreated         = metrics.NewCounter(`poll_posts_recreated_total`)
+	pollSkippedCounter         = func(reason string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`poll_skipped_total{reason="` + reason + `"}`)
+	}
+	pollDeletedPostsCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`poll_deleted_posts_total{action="` + action + `"}`)
+	}
+	pollStatusChangedCounter = func(status string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`poll_status_changes_detected_total{status="` + status + `"}`)
+	}
+
+	// Badge metrics
+	badgeUpdatesCounter = metrics.NewCounter(`badge_updates_total`)
+	badgeErrorsCounter  = metrics.NewCounter(`badge_errors_total`)
+	badgeCriticalGauge  = metrics.NewGauge(`badge_active_critical_alerts`, nil)
+
+	// Alert grouping metrics
+	alertGroupsCreatedCounter = metrics.NewCounter(`alert_groups_created_total`)
+	alertGroupsClosedCounter  = metrics.NewCounter(`alert_groups_closed_total`)
+	alertsGroupedCounter      = metrics.NewCounter(`alerts_grouped_total`)
+
+	// Escalation metrics
+	alertsEscalatedCounter = func(severity, level string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alerts_escalated_total{severity="` + severity + `",level="` + level + `"}`)
+	}
+
+	// Direct message metrics
+	directMessagesSentCounter = func(severity string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`direct_messages_sent_total{severity="` + severity + `"}`)
+	}
+	directMessageErrorsCounter = metrics.NewCounter(`direct_message_errors_total`)
+
+	// Notification budget metrics
+	alertsHeldCounter = func(channelID string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alerts_held_total{channel="` + channelID + `"}`)
+	}
+	alertsReleasedCounter = func(channelID string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alerts_released_total{channel="` + channelID + `"}`)
+	}
+	budgetHeldAlertsGauge = metrics.NewGauge(`budget_held_alerts`, nil)
+
+	// Correlation metrics
+	correlationErrorsCounter = metrics.NewCounter(`correlation_errors_total`)
+
+	// Retention metrics
+	retentionActionsCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`retention_actions_total{action="` + action + `"}`)
+	}
+	retentionErrorsCounter = metrics.NewCounter(`retention_errors_total`)
+
+	// Post expiry metrics
+	postsExpiredCounter = func(severity string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`posts_expired_total{severity="` + severity + `"}`)
+	}
+	postExpiryErrorsCounter = metrics.NewCounter(`post_expiry_errors_total`)
+
+	// Alert digest metrics
+	alertDigestsCounter      = metrics.NewCounter(`alert_digests_total`)
+	alertDigestErrorsCounter = metrics.NewCounter(`alert_digest_errors_total`)
+
+	// Quiet hours metrics
+	quietHoursAlertsCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`quiet_hours_alerts_total{action="` + action + `"}`)
+	}
+	quietHoursDigestsCounter = metrics.NewCounter(`quiet_hours_digests_total`)
+	quietHoursErrorsCounter  = metrics.NewCounter(`quiet_hours_errors_total`)
+
+	// Dead-letter queue metrics
+	deadLetterEnqueuedCounter = func(kind string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`dead_letter_enqueued_total{kind="` + kind + `"}`)
+	}
+	deadLetterRedeliveredCounter = func(kind string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`dead_letter_redelivered_total{kind="` + kind + `"}`)
+	}
+	deadLetterErrorsCounter = metrics.NewCounter(`dead_letter_errors_total`)
+
+	// Error post metrics
+	errorPostsCounter = func(reason string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`error_posts_total{reason="` + reason + `"}`)
+	}
+	errorPostErrorsCounter = metrics.NewCounter(`error_post_errors_total`)
+
+	// Audit trail metrics
+	auditEventsCounter = func(kind string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`audit_events_total{kind="` + kind + `"}`)
+	}
+	auditErrorsCounter = metrics.NewCounter(`audit_errors_total`)
+
+	// Status timeline metrics
+	timelineEntriesCounter = func(status string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`timeline_entries_total{status="` + status + `"}`)
+	}
+	timelineErrorsCounter = metrics.NewCounter(`timeline_errors_total`)
+
+	// Drop rule metrics
+	alertsDroppedCounter = func(rule string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`alerts_dropped_total{rule="` + rule + `"}`)
+	}
+
+	// Maintenance window metrics
+	maintenanceAlertsCounter = func(window, action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`maintenance_alerts_total{window="` + window + `",action="` + action + `"}`)
+	}
+	maintenanceWindowsOpenGauge = metrics.NewGauge(`maintenance_windows_open`, nil)
+
+	// Incident metrics
+	incidentsReceivedCounter = func(status string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`incidents_received_total{status="` + status + `"}`)
+	}
+	incidentsPostedCounter = metrics.NewCounter(`incidents_posted_total`)
+	incidentActionsCounter = func(action string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`incident_actions_total{action="` + action + `"}`)
+	}
+
+	// Alert queue metrics
+	alertQueueDepthGauge      = metrics.NewGauge(`alert_queue_depth`, nil)
+	alertQueueWaitSeconds     = metrics.NewHistogram(`alert_queue_wait_seconds`)
+	alertQueueRejectedCounter = metrics.NewCounter(`alert_queue_rejected_total`)
+	alertQueueRetriesCounter  = metrics.NewCounter(`alert_queue_retries_total`)
+	alertQueueFailedCounter   = metrics.NewCounter(`alert_queue_failed_total`)
+	alertQueueDroppedCounter  = metrics.NewCounter(`alert_queue_dropped_total`)
+
+	// Alert stream metrics
+	alertStreamAppendedCounter     = metrics.NewCounter(`alert_stream_appended_total`)
+	alertStreamAppendErrorsCounter = metrics.NewCounter(`alert_stream_append_errors_total`)
+	alertStreamProcessedCounter    = metrics.NewCounter(`alert_stream_processed_total`)
+	alertStreamClaimedCounter      = metrics.NewCounter(`alert_stream_claimed_total`)
+	alertStreamFailedCounter       = metrics.NewCounter(`alert_stream_failed_total`)
+
+	// Fingerprint lock metrics
+	fingerprintLockWaitSeconds   = metrics.NewHistogram(`fingerprint_lock_wait_seconds`)
+	fingerprintLockErrorsCounter = metrics.NewCounter(`fingerprint_lock_errors_total`)
+	duplicateAlertsCounter       = metrics.NewCounter(`alert_duplicates_skipped_total`)
+
+	// Callback watchdog metrics
+	callbackWatchdogTimeoutsCounter = metrics.NewCounter(`callback_watchdog_timeouts_total`)
+	callbackWatchdogErrorsCounter   = metrics.NewCounter(`callback_watchdog_errors_total`)
+
+	// Alert order metrics
+	alertOrderWaitSeconds     = metrics.NewHistogram(`alert_order_wait_seconds`)
+	alertOrderTimeoutsCounter = metrics.NewCounter(`alert_order_timeouts_total`)
+
+	// Update coalescing metrics
+	updatesCoalescedCounter = metrics.NewCounter(`mattermost_updates_coalesced_total`)
+
+	// Reconciliation metrics
+	reconcileDriftCounter = func(kind string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`reconcile_drift_total{kind="` + kind + `"}`)
+	}
+	reconcileErrorsCounter = metrics.NewCounter(`reconcile_errors_total`)
+
+	// Reaction sync metrics
+	reactionSyncEnrichCounter = metrics.NewCounter(`reaction_sync_enrichments_total`)
+	reactionSyncErrorsCounter = metrics.NewCounter(`reaction_sync_errors_total`)
+
+	// Reaction action metrics
+	reactionActionsCounter = func(action, result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`reaction_actions_total{action="` + action + `",result="` + result + `"}`)
+	}
+	reactionListenerReconnectsCounter = metrics.NewCounter(`reaction_listener_reconnects_total`)
+
+	// Flapping metrics
+	alertFlapChangesCounter        = metrics.NewCounter(`alert_flap_state_changes_total`)
+	alertsFlappingStartedCounter   = metrics.NewCounter(`alerts_flapping_started_total`)
+	alertsFlappingStoppedCounter   = metrics.NewCounter(`alerts_flapping_stopped_total`)
+	alertsFlappingInhibitedCounter = metrics.NewCounter(`alerts_flapping_inhibited_updates_total`)
+	alertsFlappingGauge            = metrics.NewGauge(`alerts_flapping`, nil)
+
+	// Alert lifecycle metrics, from posting to the first acknowledge and to
+	// the resolve
+	alertTimeToAcknowledgeSeconds = func(severity, channel string) *metrics.Histogram {
+		return metrics.GetOrCreateHistogram(`alert_time_to_acknowledge_seconds{severity="` + severity + `",channel="` + channel + `"}`)
+	}
+	alertTimeToResolveSeconds = func(severity, channel string) *metrics.Histogram {
+		return metrics.GetOrCreateHistogram(`alert_time_to_resolve_seconds{severity="` + severity + `",channel="` + channel + `"}`)
+	}
+
+	// Latency metrics
+	alertDeliverySeconds    = metrics.NewHistogram(`alert_delivery_duration_seconds`)
+	callbackDurationSeconds = func(action string) *metrics.Histogram {
+		return metrics.GetOrCreateHistogram(`callback_duration_seconds{action="` + action + `"}`)
+	}
+
+	// Slash command metrics
+	slashCommandsCounter = func(subcommand string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`slash_commands_total{subcommand="` + subcommand + `"}`)
+	}
+	// Alerts handled by ack-all and resolve-all; result is applied, gone,
+	// denied or failed.
+	bulkActionAlertsCounter = func(action, result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`slash_bulk_alerts_total{action="` + action + `",result="` + result + `"}`)
+	}
+
+	// Channels the config names, by where their ID came from: cache, lookup
+	// or created.
+	channelNamesResolvedCounter = func(source string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`channel_names_resolved_total{source="` + source + `"}`)
+	}
+
+	// Custom action metrics
+	customActionsCounter = func(target, result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`custom_actions_total{target="` + target + `",result="` + result + `"}`)
+	}
+
+	// Workflow menu metrics
+	workflowMenuRunsCounter = func(result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`workflow_menu_runs_total{result="` + result + `"}`)
+	}
+	workflowMenuResultsCounter = func(status string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`workflow_menu_results_total{status="` + status + `"}`)
+	}
+	workflowMenuPendingGauge = metrics.NewGauge(`workflow_menu_pending_runs`, nil)
+
+	// Status board posts written; result is created, updated or failed.
+	statusBoardWritesCounter = func(result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`status_board_writes_total{result="` + result + `"}`)
+	}
+
+	// Thread replies sent to Keep as notes; result is forwarded or failed.
+	threadNotesCounter = func(result string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`thread_notes_total{result="` + result + `"}`)
+	}
+	threadReplyListenerReconnectsCounter = metrics.NewCounter(`thread_reply_listener_reconnects_total`)
 )
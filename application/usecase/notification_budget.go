@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const budgetWindow = time.Hour
+
+// NotificationBudget caps how many new alert posts each channel receives per
+// hour. Alerts over budget are held back and listed in a rolling overflow
+// summary post in their channel, then released one at a time as older posts
+// leave the one-hour window.
+//
+// State is kept in memory. Alerts held when the bridge restarts are posted
+// when Keep sends them again.
+type NotificationBudget struct {
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	limitFor   func(channelID string) int
+	keepUIURL  string
+	clock      clock.Clock
+	logger     *slog.Logger
+
+	// mu guards the maps and serializes summary post writes so concurrent
+	// webhooks do not create duplicate summary posts.
+	mu       sync.Mutex
+	sent     map[string][]time.Time // channel -> post times within the window
+	overflow map[string]*overflowSummary
+}
+
+type overflowSummary struct {
+	postID string
+	held   []port.HeldAlert
+	alerts map[string]*alert.Alert // fingerprint -> alert as last received
+}
+
+// NewNotificationBudget creates a budget; limitFor returns the hourly post
+// limit of a channel, zero or less meaning unlimited.
+func NewNotificationBudget(
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	limitFor func(channelID string) int,
+	keepUIURL string,
+	logger *slog.Logger,
+) *NotificationBudget {
+	return &NotificationBudget{
+		mmClient:   mmClient,
+		msgBuilder: msgBuilder,
+		limitFor:   limitFor,
+		keepUIURL:  keepUIURL,
+		clock:      clock.Real(),
+		logger:     logger,
+		sent:       make(map[string][]time.Time),
+		overflow:   make(map[string]*overflowSummary),
+	}
+}
+
+// SetClock replaces the clock that drives the one-hour window.
+func (b *NotificationBudget) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// Admit reports whether a new alert may be posted to channelID now and, if
+// so, counts the post against the budget. Otherwise the alert is held and
+// the channel's overflow summary is updated. Alerts that are already held
+// stay in line behind older ones.
+func (b *NotificationBudget) Admit(ctx context.Context, channelID string, a *alert.Alert) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	summary := b.overflow[channelID]
+	if summary != nil && summary.indexOf(a.Fingerprint().Value()) >= 0 {
+		summary.alerts[a.Fingerprint().Value()] = a
+		return false
+	}
+	if (summary == nil || len(summary.held) == 0) && b.take(channelID, now) {
+		return true
+	}
+
+	if summary == nil {
+		summary = &overflowSummary{alerts: make(map[string]*alert.Alert)}
+		b.overflow[channelID] = summary
+	}
+	summary.alerts[a.Fingerprint().Value()] = a
+	summary.held = append(summary.held, port.HeldAlert{
+		Fingerprint: a.Fingerprint().Value(),
+		Name:        a.Name(),
+		Severity:    a.Severity().String(),
+		HeldAt:      now,
+	})
+	b.writeSummary(ctx, channelID, summary)
+
+	b.logger.Info("Alert held by notification budget",
+		logger.ApplicationFields("alert_held",
+			slog.String("fingerprint", a.Fingerprint().Value()),
+			slog.String("channel_id", channelID),
+			slog.Int("held", len(summary.held)),
+		),
+	)
+	alertsHeldCounter(channelID).Inc()
+	b.updateHeldGauge()
+
+	return false
+}
+
+// Drop removes a held alert, e.g. because it resolved before it was
+// released, and reports whether it was held.
+func (b *NotificationBudget) Drop(ctx context.Context, fingerprint string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for channelID, summary := range b.overflow {
+		if i := summary.indexOf(fingerprint); i >= 0 {
+			summary.held = slices.Delete(summary.held, i, i+1)
+			delete(summary.alerts, fingerprint)
+			b.writeSummary(ctx, channelID, summary)
+			b.updateHeldGauge()
+			return true
+		}
+	}
+	return false
+}
+
+// Release posts held alerts, oldest first, while their channel has budget
+// left. post is called with each released alert as last received and
+// reports whether it created a post; alerts it skips do not use up budget.
+// When post fails the alert stays held and the channel is retried on the
+// next run.
+func (b *NotificationBudget) Release(ctx context.Context, post func(ctx context.Context, channelID string, a *alert.Alert) (bool, error)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	var errs []error
+	for channelID, summary := range b.overflow {
+		changed := false
+		for len(summary.held) > 0 && b.take(channelID, now) {
+			held := summary.held[0]
+			posted, err := post(ctx, channelID, summary.alerts[held.Fingerprint])
+			if err != nil {
+				b.untake(channelID)
+				errs = append(errs, fmt.Errorf("release %s: %w", held.Fingerprint, err))
+				break
+			}
+			summary.held = summary.held[1:]
+			delete(summary.alerts, held.Fingerprint)
+			changed = true
+			if !posted {
+				b.untake(channelID)
+				continue
+			}
+			alertsReleasedCounter(channelID).Inc()
+		}
+		if changed {
+			b.writeSummary(ctx, channelID, summary)
+		}
+	}
+	b.updateHeldGauge()
+
+	return errors.Join(errs...)
+}
+
+// take counts a post against the channel's budget if there is room.
+func (b *NotificationBudget) take(channelID string, now time.Time) bool {
+	limit := b.limitFor(channelID)
+	if limit <= 0 {
+		return true
+	}
+
+	cutoff := now.Add(-budgetWindow)
+	sent := slices.DeleteFunc(b.sent[channelID], func(t time.Time) bool {
+		return !t.After(cutoff)
+	})
+	if len(sent) >= limit {
+		b.sent[channelID] = sent
+		return false
+	}
+	b.sent[channelID] = append(sent, now)
+	return true
+}
+
+func (b *NotificationBudget) untake(channelID string) {
+	if sent := b.sent[channelID]; len(sent) > 0 {
+		b.sent[channelID] = sent[:len(sent)-1]
+	}
+}
+
+// writeSummary creates or refreshes the channel's overflow summary post.
+// Once nothing is held the post is finalized and forgotten, so the next
+// overflow starts a new one. Failures are logged; the summary is rewritten
+// on the next change.
+func (b *NotificationBudget) writeSummary(ctx context.Context, channelID string, summary *overflowSummary) {
+	attachment := b.msgBuilder.BuildOverflowAttachment(summary.held, b.keepUIURL)
+
+	if summary.postID == "" {
+		if len(summary.held) == 0 {
+			delete(b.overflow, channelID)
+			return
+		}
+		postID, err := b.mmClient.CreatePost(ctx, channelID, attachment)
+		if err != nil {
+			b.logger.Warn("Failed to create overflow summary post",
+				slog.String("channel_id", channelID),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		summary.postID = postID
+	} else if err := b.mmClient.UpdatePost(ctx, summary.postID, attachment); err != nil {
+		b.logger.Warn("Failed to update overflow summary post",
+			slog.String("channel_id", channelID),
+			slog.String("post_id", summary.postID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if len(summary.held) == 0 {
+		delete(b.overflow, channelID)
+	}
+}
+
+func (b *NotificationBudget) updateHeldGauge() {
+	total := 0
+	for _, summary := range b.overflow {
+		total += len(summary.held)
+	}
+	budgetHeldAlertsGauge.Set(float64(total))
+}
+
+func (s *overflowSummary) indexOf(fingerprint string) int {
+	return slices.IndexFunc(s.held, func(h port.HeldAlert) bool {
+		return h.Fingerprint == fingerprint
+	})
+}
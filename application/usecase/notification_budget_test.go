@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type budgetFixture struct {
+	budget   *NotificationBudget
+	mmClient *portmock.MattermostClientMock
+	clock    *clock.Fake
+}
+
+func newBudgetFixture(limits map[string]int) *budgetFixture {
+	f := &budgetFixture{
+		mmClient: &portmock.MattermostClientMock{
+			CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+				return "summary-" + channelID, nil
+			},
+			UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+				return nil
+			},
+		},
+		clock: clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	f.budget = NewNotificationBudget(
+		f.mmClient,
+		newMessageBuilderMock(),
+		func(channelID string) int { return limits[channelID] },
+		"https://keep.example.com",
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+	f.budget.SetClock(f.clock)
+	return f
+}
+
+func TestNotificationBudget_HoldsAlertsOverBudget(t *testing.T) {
+	f := newBudgetFixture(map[string]int{"channel-1": 2})
+	ctx := context.Background()
+
+	assert.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-1", "high", nil)))
+	assert.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-2", "high", nil)))
+	assert.Empty(t, f.mmClient.CreatePostCalls())
+
+	assert.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-3", "high", nil)))
+	require.Len(t, f.mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "channel-1", f.mmClient.CreatePostCalls()[0].ChannelID)
+	assert.Equal(t, "OVERFLOW: 1", f.mmClient.CreatePostCalls()[0].Attachment.Title)
+
+	assert.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-4", "critical", nil)))
+	require.Len(t, f.mmClient.UpdatePostCalls(), 1)
+	assert.Equal(t, "summary-channel-1", f.mmClient.UpdatePostCalls()[0].PostID)
+	assert.Equal(t, "OVERFLOW: 2", f.mmClient.UpdatePostCalls()[0].Attachment.Title)
+
+	assert.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-3", "high", nil)), "held alerts stay held")
+	assert.Len(t, f.mmClient.UpdatePostCalls(), 1)
+
+	assert.True(t, f.budget.Admit(ctx, "channel-2", newGroupTestAlert(t, "fp-5", "high", nil)), "channels without a limit are unlimited")
+}
+
+func TestNotificationBudget_ReleaseAsBudgetRecovers(t *testing.T) {
+	f := newBudgetFixture(map[string]int{"channel-1": 1})
+	ctx := context.Background()
+
+	require.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-1", "high", nil)))
+	f.clock.Advance(10 * time.Minute)
+	require.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-2", "high", nil)))
+	require.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-3", "high", nil)))
+
+	var released []string
+	post := func(ctx context.Context, channelID string, a *alert.Alert) (bool, error) {
+		released = append(released, channelID+"/"+a.Fingerprint().Value())
+		return true, nil
+	}
+
+	require.NoError(t, f.budget.Release(ctx, post))
+	assert.Empty(t, released, "budget still used up")
+
+	f.clock.Advance(50 * time.Minute)
+	require.NoError(t, f.budget.Release(ctx, post))
+	assert.Equal(t, []string{"channel-1/fp-2"}, released, "one slot freed, oldest first")
+	assert.Equal(t, "OVERFLOW: 1", f.mmClient.UpdatePostCalls()[len(f.mmClient.UpdatePostCalls())-1].Attachment.Title)
+
+	assert.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-4", "high", nil)), "new alerts queue behind held ones")
+
+	f.clock.Advance(time.Hour)
+	require.NoError(t, f.budget.Release(ctx, post))
+	assert.Equal(t, []string{"channel-1/fp-2", "channel-1/fp-3"}, released)
+
+	f.clock.Advance(time.Hour)
+	require.NoError(t, f.budget.Release(ctx, post))
+	assert.Equal(t, []string{"channel-1/fp-2", "channel-1/fp-3", "channel-1/fp-4"}, released)
+	assert.Equal(t, "OVERFLOW: 0", f.mmClient.UpdatePostCalls()[len(f.mmClient.UpdatePostCalls())-1].Attachment.Title)
+
+	f.clock.Advance(time.Hour)
+	assert.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-5", "high", nil)))
+	assert.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-6", "high", nil)))
+	assert.Len(t, f.mmClient.CreatePostCalls(), 2, "a new overflow starts a new summary post")
+}
+
+func TestNotificationBudget_ReleaseSkipsAndFailures(t *testing.T) {
+	f := newBudgetFixture(map[string]int{"channel-1": 1})
+	ctx := context.Background()
+
+	require.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-1", "high", nil)))
+	require.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-2", "high", nil)))
+	require.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-3", "high", nil)))
+	f.clock.Advance(time.Hour)
+
+	err := f.budget.Release(ctx, func(ctx context.Context, channelID string, a *alert.Alert) (bool, error) {
+		return false, errors.New("keep unavailable")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "release fp-2: keep unavailable")
+
+	var released []string
+	err = f.budget.Release(ctx, func(ctx context.Context, channelID string, a *alert.Alert) (bool, error) {
+		released = append(released, a.Fingerprint().Value())
+		return a.Fingerprint().Value() != "fp-2", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fp-2", "fp-3"}, released, "failed and skipped alerts do not use up budget")
+}
+
+func TestNotificationBudget_Drop(t *testing.T) {
+	f := newBudgetFixture(map[string]int{"channel-1": 1})
+	ctx := context.Background()
+
+	require.True(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-1", "high", nil)))
+	require.False(t, f.budget.Admit(ctx, "channel-1", newGroupTestAlert(t, "fp-2", "high", nil)))
+
+	assert.False(t, f.budget.Drop(ctx, "fp-1"), "posted alerts are not held")
+	assert.True(t, f.budget.Drop(ctx, "fp-2"))
+	assert.Equal(t, "OVERFLOW: 0", f.mmClient.UpdatePostCalls()[0].Attachment.Title)
+
+	f.clock.Advance(time.Hour)
+	require.NoError(t, f.budget.Release(ctx, func(ctx context.Context, channelID string, a *alert.Alert) (bool, error) {
+		t.Fatalf("dropped alert %s released", a.Fingerprint().Value())
+		return false, nil
+	}))
+}
+
+func TestHandleAlertUseCase_NotificationBudget(t *testing.T) {
+	uc, postRepo, _, keepClient, _, _ := setupHandleAlertUseCase()
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-" + attachment.Title, nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+	}
+	uc.mmClient = mmClient
+	fakeClock := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	budget := NewNotificationBudget(mmClient, uc.msgBuilder, func(string) int { return 1 }, "https://keep.example.com", uc.logger)
+	budget.SetClock(fakeClock)
+	uc.SetNotificationBudget(budget)
+	ctx := context.Background()
+
+	for _, fp := range []string{"fp-1", "fp-2", "fp-3"} {
+		require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: fp, Name: "Alert " + fp, Severity: "high", Status: "firing"}))
+	}
+	assert.Contains(t, postRepo.posts, "fp-1")
+	assert.NotContains(t, postRepo.posts, "fp-2")
+	assert.NotContains(t, postRepo.posts, "fp-3")
+	require.Len(t, mmClient.CreatePostCalls(), 2)
+	assert.Equal(t, "OVERFLOW: 1", mmClient.CreatePostCalls()[1].Attachment.Title)
+
+	require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-3", Name: "Alert fp-3", Severity: "high", Status: "resolved"}))
+	assert.NotContains(t, postRepo.posts, "fp-3")
+
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{Fingerprint: "fp-2", Name: "Alert fp-2 (updated)", Status: "firing", Severity: "critical"})
+	fakeClock.Advance(time.Hour)
+	require.NoError(t, uc.ReleaseHeldAlerts(ctx))
+
+	require.Contains(t, postRepo.posts, "fp-2")
+	assert.Equal(t, "channel-456", postRepo.posts["fp-2"].ChannelID())
+	assert.Equal(t, "critical", postRepo.posts["fp-2"].Severity().String(), "released alerts show Keep's current state")
+	last := mmClient.UpdatePostCalls()[len(mmClient.UpdatePostCalls())-1]
+	assert.Equal(t, "OVERFLOW: 0", last.Attachment.Title)
+
+	require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: "fp-4", Name: "Alert fp-4", Severity: "warning", Status: "firing"}))
+	require.NotContains(t, postRepo.posts, "fp-4")
+	keepClient.GetAlertFunc = keepAlertError(errors.New("connection refused"))
+	fakeClock.Advance(time.Hour)
+	require.Error(t, uc.ReleaseHeldAlerts(ctx))
+	require.NotContains(t, postRepo.posts, "fp-4", "alerts stay held while Keep cannot be asked")
+
+	keepClient.GetAlertFunc = keepAlertError(fmt.Errorf("keep get alert: %w", port.ErrKeepAlertNotFound))
+	require.NoError(t, uc.ReleaseHeldAlerts(ctx))
+	require.Contains(t, postRepo.posts, "fp-4", "alerts unknown to Keep are posted as received")
+	assert.Equal(t, "Alert fp-4", postRepo.posts["fp-4"].AlertName())
+}
@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// PendingKeepActions keeps acknowledge, resolve and unacknowledge actions
+// whose Keep update failed because Keep was unreachable. The post shows the
+// action at once; HandleCallbackUseCase.ReplayPendingActions sends it to Keep
+// once Keep answers again. Actions older than maxAge are dropped instead, as
+// the alert has likely moved on by then.
+type PendingKeepActions struct {
+	repo   pendingaction.Repository
+	maxAge time.Duration
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+func NewPendingKeepActions(repo pendingaction.Repository, maxAge time.Duration, logger *slog.Logger) *PendingKeepActions {
+	return &PendingKeepActions{
+		repo:   repo,
+		maxAge: maxAge,
+		clock:  clock.Real(),
+		logger: logger,
+	}
+}
+
+// SetClock replaces the clock that timestamps and ages actions.
+func (p *PendingKeepActions) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+func (p *PendingKeepActions) save(ctx context.Context, a *pendingaction.Action) bool {
+	// Keep the action even if the callback that failed was canceled.
+	if err := p.repo.Save(context.WithoutCancel(ctx), a); err != nil {
+		pendingKeepActionsCounter("lost").Inc()
+		p.logger.Error("Failed to keep action for Keep, it is lost",
+			slog.String("fingerprint", a.Fingerprint().Value()),
+			slog.String("action", a.Action()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return true
+}
+
+func (p *PendingKeepActions) drop(ctx context.Context, fingerprint string) {
+	if err := p.repo.Delete(context.WithoutCancel(ctx), fingerprint); err != nil {
+		p.logger.Warn("Failed to drop pending Keep action",
+			slog.String("fingerprint", fingerprint),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// SetPendingActions keeps acknowledge, resolve and unacknowledge actions that
+// could not reach Keep for ReplayPendingActions. Nil, the default, only logs
+// the failure.
+func (uc *HandleCallbackUseCase) SetPendingActions(pending *PendingKeepActions) {
+	uc.pending = pending
+}
+
+// replayableAction reports whether action can be kept for replay.
+func replayableAction(action string) bool {
+	switch action {
+	case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge:
+		return true
+	default:
+		return false
+	}
+}
+
+// trackedKeepAlert stands in for the alert Keep could not return, so an
+// action kept for replay still updates its post. It carries what the bridge
+// tracked for the alert; the post is rendered from Keep again once the action
+// is replayed.
+func (uc *HandleCallbackUseCase) trackedKeepAlert(ctx context.Context, fingerprint alert.Fingerprint, input dto.MattermostCallbackInput) *port.KeepAlert {
+	keepAlert := &port.KeepAlert{
+		Fingerprint: fingerprint.Value(),
+		Name:        input.Context[post.ContextKeyAlertName],
+		Severity:    input.Context[post.ContextKeySeverity],
+	}
+	if existing, err := uc.postRepo.FindByFingerprint(ctx, fingerprint); err == nil {
+		keepAlert.Name = existing.AlertName()
+		keepAlert.Severity = existing.Severity().Value()
+		keepAlert.FiringStartTime = existing.FiringStartTime()
+	}
+	return keepAlert
+}
+
+// trackKeepAction keeps action for replay when keepErr says Keep was
+// unreachable, and tells the thread. Once Keep took the action, an older
+// pending action for the alert is dropped, so it cannot overwrite this one.
+func (uc *HandleCallbackUseCase) trackKeepAction(ctx context.Context, keepErr error, a *alert.Alert, action, username string, enrichments map[string]string, postID, channelID string) {
+	if uc.pending == nil {
+		return
+	}
+	fingerprint := a.Fingerprint()
+	if keepErr == nil {
+		uc.pending.drop(ctx, fingerprint.Value())
+		return
+	}
+	if !errors.Is(keepErr, port.ErrKeepUnavailable) {
+		return
+	}
+
+	pending := pendingaction.NewAction(fingerprint, action, username, enrichments, postID, channelID, a.Name(), keepErr.Error(), uc.pending.clock.Now())
+	if !uc.pending.save(ctx, pending) {
+		return
+	}
+	pendingKeepActionsCounter("queued").Inc()
+	uc.logger.Warn("Keep unreachable, action kept for replay",
+		logger.ApplicationFields("keep_action_pending",
+			slog.String("action", action),
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("username", username),
+		),
+	)
+
+	reply := fmt.Sprintf("Keep is unreachable: the %s by @%s is sent to Keep once it is back.", pendingActionNoun(action), username)
+	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, reply); err != nil {
+		uc.logger.Error("Failed to reply to thread",
+			slog.String("post_id", postID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// ReplayPendingActions sends the actions kept while Keep was unreachable to
+// Keep, oldest first, and refreshes their posts. It stops at the first action
+// Keep is still unreachable for; the rest wait for the next run. Actions Keep
+// rejects are dropped, since they would fail again.
+func (uc *HandleCallbackUseCase) ReplayPendingActions(ctx context.Context) error {
+	if uc.pending == nil {
+		return nil
+	}
+	actions, err := uc.pending.repo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("find pending actions: %w", err)
+	}
+
+	for _, pa := range actions {
+		if ctx.Err() != nil {
+			return nil
+		}
+		fingerprint := pa.Fingerprint().Value()
+		if age := uc.pending.clock.Now().Sub(pa.RequestedAt()); age > uc.pending.maxAge {
+			uc.pending.drop(ctx, fingerprint)
+			pendingKeepActionsCounter("expired").Inc()
+			uc.logger.Warn("Pending Keep action expired before Keep came back",
+				logger.ApplicationFields("keep_action_expired",
+					slog.String("action", pa.Action()),
+					slog.String("fingerprint", fingerprint),
+					slog.String("username", pa.Actor()),
+					slog.Duration("age", age),
+				),
+			)
+			continue
+		}
+
+		var keepErr error
+		lockErr := uc.withLock(ctx, fingerprint, func(ctx context.Context) error {
+			keepErr = uc.replayAction(ctx, pa)
+			return nil
+		})
+		switch {
+		case lockErr != nil:
+			uc.logger.Warn("Failed to lock alert for pending Keep action",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", lockErr.Error()),
+			)
+		case errors.Is(keepErr, port.ErrKeepUnavailable):
+			pa.RecordFailure(keepErr.Error())
+			if err := uc.pending.repo.Save(ctx, pa); err != nil {
+				return fmt.Errorf("save pending action %s: %w", fingerprint, err)
+			}
+			return nil
+		case keepErr != nil:
+			uc.pending.drop(ctx, fingerprint)
+			pendingKeepActionsCounter("failed").Inc()
+			uc.logger.Error("Keep rejected pending action, dropping it",
+				logger.ApplicationFields("keep_action_failed",
+					slog.String("action", pa.Action()),
+					slog.String("fingerprint", fingerprint),
+					slog.String("error", keepErr.Error()),
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func (uc *HandleCallbackUseCase) replayAction(ctx context.Context, pa *pendingaction.Action) error {
+	fingerprint := pa.Fingerprint().Value()
+	var err error
+	switch pa.Action() {
+	case post.ActionAcknowledge, post.ActionResolve:
+		err = uc.sendStatus(ctx, fingerprint, pa.Actor(), pa.Enrichments())
+	case post.ActionUnacknowledge:
+		err = uc.sendUnacknowledge(ctx, fingerprint)
+	default:
+		err = fmt.Errorf("unknown action %q", pa.Action())
+	}
+	if err != nil {
+		return err
+	}
+
+	uc.pending.drop(ctx, fingerprint)
+	pendingKeepActionsCounter("replayed").Inc()
+	uc.logger.Info("Pending Keep action replayed",
+		logger.ApplicationFields("keep_action_replayed",
+			slog.String("action", pa.Action()),
+			slog.String("fingerprint", fingerprint),
+			slog.String("username", pa.Actor()),
+			slog.Duration("delay", uc.pending.clock.Now().Sub(pa.RequestedAt())),
+		),
+	)
+	uc.refreshReplayedPost(ctx, pa)
+	return nil
+}
+
+// refreshReplayedPost renders the post of a replayed action from the alert as
+// Keep now has it and tells the thread the action reached Keep.
+func (uc *HandleCallbackUseCase) refreshReplayedPost(ctx context.Context, pa *pendingaction.Action) {
+	fingerprint := pa.Fingerprint()
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		uc.logger.Warn("Failed to get alert for replayed action",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	a, err := restoreCallbackAlert(keepAlert, fingerprint, pa.Action())
+	if err != nil {
+		uc.logger.Warn("Failed to restore alert for replayed action",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	builder := uc.msgBuilder.ForChannel(pa.ChannelID())
+	var attachment post.Attachment
+	switch pa.Action() {
+	case post.ActionAcknowledge:
+		attachment = builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, pa.Actor())
+	case post.ActionResolve:
+		attachment = withResolution(builder.BuildResolvedAttachment(a, uc.keepUIURL, pa.Actor()), resolutionFromContext(pa.Enrichments()))
+	default:
+		attachment = builder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	}
+	// A resolved post may have been collapsed or deleted by the retention
+	// policy already; rendering it again would undo that.
+	if pa.Action() != post.ActionResolve || uc.retention == nil {
+		if err := uc.mmClient.UpdatePost(ctx, pa.PostID(), attachment); err != nil {
+			uc.logger.Warn("Failed to update post for replayed action",
+				slog.String("post_id", pa.PostID()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	reply := fmt.Sprintf("Keep is back: the %s by @%s was sent to Keep.", pendingActionNoun(pa.Action()), pa.Actor())
+	if err := uc.mmClient.ReplyToThread(ctx, pa.ChannelID(), pa.PostID(), reply); err != nil {
+		uc.logger.Error("Failed to reply to thread",
+			slog.String("post_id", pa.PostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func pendingActionNoun(action string) string {
+	switch action {
+	case post.ActionAcknowledge:
+		return "acknowledgement"
+	case post.ActionResolve:
+		return "resolution"
+	default:
+		return "unacknowledgement"
+	}
+}
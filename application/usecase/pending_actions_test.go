@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type memoryPendingActionRepository struct {
+	actions map[string]*pendingaction.Action
+}
+
+func (m *memoryPendingActionRepository) Save(ctx context.Context, a *pendingaction.Action) error {
+	m.actions[a.Fingerprint().Value()] = a
+	return nil
+}
+
+func (m *memoryPendingActionRepository) FindAll(ctx context.Context) ([]*pendingaction.Action, error) {
+	actions := make([]*pendingaction.Action, 0, len(m.actions))
+	for _, a := range m.actions {
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].RequestedAt().Before(actions[j].RequestedAt()) })
+	return actions, nil
+}
+
+func (m *memoryPendingActionRepository) Delete(ctx context.Context, fingerprint string) error {
+	delete(m.actions, fingerprint)
+	return nil
+}
+
+func setupPendingActions(uc *HandleCallbackUseCase) (*memoryPendingActionRepository, *clock.Fake) {
+	repo := &memoryPendingActionRepository{actions: make(map[string]*pendingaction.Action)}
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	pending := NewPendingKeepActions(repo, time.Hour, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	pending.SetClock(fake)
+	uc.SetPendingActions(pending)
+	return repo, fake
+}
+
+var errKeepDown = fmt.Errorf("keep enrich alert: %w: connection refused", port.ErrKeepUnavailable)
+
+func TestHandleCallbackUseCase_ReplaysActionOnceKeepIsBack(t *testing.T) {
+	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	repo, _ := setupPendingActions(uc)
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return errKeepDown }
+
+	uc.ExecuteAsync(queuedActionInput(post.ActionAcknowledge))
+	uc.Wait()
+
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "the post shows the action at once")
+	require.Contains(t, repo.actions, "fp-12345")
+	kept := repo.actions["fp-12345"]
+	assert.Equal(t, post.ActionAcknowledge, kept.Action())
+	assert.Equal(t, "testuser", kept.Actor())
+	assert.Equal(t, "acknowledged", kept.Enrichments()["status"])
+	replies := replyMessages(mmClient)
+	require.Len(t, replies, 2)
+	assert.Equal(t, "Keep is unreachable: the acknowledgement by @testuser is sent to Keep once it is back.", replies[1])
+
+	require.NoError(t, uc.ReplayPendingActions(context.Background()))
+	assert.Equal(t, 1, repo.actions["fp-12345"].Attempts(), "the action waits while Keep is down")
+
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return nil }
+	enriched, updates := len(keepClient.EnrichAlertCalls()), len(mmClient.UpdatePostCalls())
+	require.NoError(t, uc.ReplayPendingActions(context.Background()))
+
+	assert.Empty(t, repo.actions)
+	replayed := keepClient.EnrichAlertCalls()[enriched:]
+	require.Len(t, replayed, 2)
+	assert.Equal(t, "testuser", replayed[0].Enrichments["assignee"])
+	assert.Equal(t, "acknowledged", replayed[1].Enrichments["status"])
+	assert.Greater(t, len(mmClient.UpdatePostCalls()), updates, "the post is refreshed after the replay")
+	replies = replyMessages(mmClient)
+	assert.Equal(t, "Keep is back: the acknowledgement by @testuser was sent to Keep.", replies[len(replies)-1])
+}
+
+func TestHandleCallbackUseCase_PendingActionRules(t *testing.T) {
+	t.Run("rejected updates are not kept", func(t *testing.T) {
+		uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+		repo, _ := setupPendingActions(uc)
+		keepClient.UnenrichAlertFunc = func(context.Context, string, []string) error { return errors.New("keep unenrich alert: status 400") }
+
+		uc.ExecuteAsync(queuedActionInput(post.ActionUnacknowledge))
+		uc.Wait()
+		assert.Empty(t, repo.actions)
+	})
+
+	t.Run("a newer action Keep took drops the pending one", func(t *testing.T) {
+		uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+		repo, _ := setupPendingActions(uc)
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return errKeepDown }
+		uc.ExecuteAsync(queuedActionInput(post.ActionAcknowledge))
+		uc.Wait()
+		require.Len(t, repo.actions, 1)
+
+		uc.ExecuteAsync(queuedActionInput(post.ActionUnacknowledge))
+		uc.Wait()
+		assert.Empty(t, repo.actions)
+	})
+
+	t.Run("old actions expire", func(t *testing.T) {
+		uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+		repo, fake := setupPendingActions(uc)
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return errKeepDown }
+		uc.ExecuteAsync(queuedActionInput(post.ActionResolve))
+		uc.Wait()
+		require.Len(t, repo.actions, 1)
+
+		keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return nil }
+		enriched := len(keepClient.EnrichAlertCalls())
+		fake.Advance(2 * time.Hour)
+		require.NoError(t, uc.ReplayPendingActions(context.Background()))
+		assert.Empty(t, repo.actions)
+		assert.Len(t, keepClient.EnrichAlertCalls(), enriched, "an expired action is not sent")
+	})
+}
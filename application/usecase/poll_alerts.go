@@ -2,15 +2,20 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"strings"
-	"time"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
 type PollAlertsUseCase struct {
@@ -23,6 +28,23 @@ type PollAlertsUseCase struct {
 	callbackURL string
 	alertsLimit int
 	logger      *slog.Logger
+	clock       clock.Clock
+
+	alerts   port.AlertUseCase       // nil unless status sync is enabled
+	statuses map[string]alert.Status // Keep status seen in the last cycle, per fingerprint
+
+	enrichmentKeys []string          // nil unless enrichment fields are shown
+	enrichments    map[string]string // shown enrichments seen in the last cycle, per fingerprint
+
+	postReader   port.MattermostPostReader // nil unless posts are checked for deletion
+	deletedPosts string                    // port.DeletedPostRecreate, DeletedPostUntrack or DeletedPostResolve
+
+	querier  port.KeepAlertQuerier // nil unless alerts are queried page by page
+	pageSize int
+
+	backoff    retry.Policy
+	failures   int // consecutive failed cycles
+	skipCycles int // cycles left to skip after a failure
 }
 
 func NewPollAlertsUseCase(
@@ -46,13 +68,91 @@ func NewPollAlertsUseCase(
 		callbackURL: callbackURL,
 		alertsLimit: alertsLimit,
 		logger:      logger,
+		clock:       clock.Real(),
 	}
 }
 
+// SetClock replaces the clock that timestamps acknowledgements seen in Keep.
+func (uc *PollAlertsUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// SetStatusSync makes the poller follow status changes made in Keep UI as
+// well. A tracked alert whose Keep status differs from the previous cycle is
+// replayed through alerts as if its webhook had arrived; one closed in Keep is
+// replayed as closed, which resolves the post and stops tracking it.
+func (uc *PollAlertsUseCase) SetStatusSync(alerts port.AlertUseCase) {
+	uc.alerts = alerts
+	uc.statuses = make(map[string]alert.Status)
+}
+
+// SetEnrichmentRefresh makes the poller re-render firing and acknowledged
+// posts whose Keep enrichments among keys changed since the previous cycle,
+// so the enrichment fields of the posts stay current. The enrichments first
+// seen for an alert are only recorded, as the post may already show them.
+func (uc *PollAlertsUseCase) SetEnrichmentRefresh(keys []string) {
+	uc.enrichmentKeys = keys
+	uc.enrichments = make(map[string]string)
+}
+
+// SetDeletedPosts makes the poller check that the posts of firing and
+// acknowledged alerts still exist. When someone deleted the post of an
+// alert, action posts it again, stops tracking it, or resolves it in Keep.
+// Each check is a Mattermost request per post and cycle. Posts reader cannot
+// read back, such as those on Teams or Telegram, are left alone.
+func (uc *PollAlertsUseCase) SetDeletedPosts(reader port.MattermostPostReader, action string) {
+	uc.postReader = reader
+	uc.deletedPosts = action
+}
+
+// SetPagedQueries makes the poller ask Keep for the tracked alerts only,
+// pageSize alerts per request, instead of fetching up to the alerts limit of
+// every alert and filtering locally. Every tracked alert is found however
+// many alerts Keep holds.
+func (uc *PollAlertsUseCase) SetPagedQueries(querier port.KeepAlertQuerier, pageSize int) {
+	uc.querier = querier
+	uc.pageSize = pageSize
+}
+
+// SetBackoff makes the poller skip cycles after cycles that failed to read
+// the tracked posts or the Keep alerts. The policy's InitialDelay is the
+// polling interval: after n consecutive failures the next cycle runs once
+// policy.Delay(n-1) has passed. Without a backoff every cycle runs.
+func (uc *PollAlertsUseCase) SetBackoff(policy retry.Policy) {
+	uc.backoff = policy
+}
+
+// Execute runs one polling cycle. The job runner calls it from a single
+// goroutine, so the backoff state needs no locking.
 func (uc *PollAlertsUseCase) Execute(ctx context.Context) error {
-	startTime := time.Now()
+	if uc.skipCycles > 0 {
+		uc.skipCycles--
+		pollSkippedCounter("backoff").Inc()
+		uc.logger.Debug("Polling cycle skipped after failures",
+			slog.Int("failures", uc.failures),
+			slog.Int("cycles_left", uc.skipCycles),
+		)
+		return nil
+	}
+
+	if err := uc.poll(ctx); err != nil {
+		uc.failures++
+		if uc.backoff.InitialDelay > 0 {
+			delay := uc.backoff.Delay(uc.failures-1, rand.Float64())
+			uc.skipCycles = int(delay/uc.backoff.InitialDelay) - 1
+			pollBackoffSecondsGauge.Set(delay.Seconds())
+		}
+		return err
+	}
+	uc.failures = 0
+	pollBackoffSecondsGauge.Set(0)
+	return nil
+}
+
+func (uc *PollAlertsUseCase) poll(ctx context.Context) error {
+	startTime := uc.clock.Now()
 	defer func() {
-		pollDurationSeconds.UpdateDuration(startTime)
+		pollDurationSeconds.Update(uc.clock.Now().Sub(startTime).Seconds())
 	}()
 
 	pollExecutionsCounter.Inc()
@@ -66,11 +166,17 @@ func (uc *PollAlertsUseCase) Execute(ctx context.Context) error {
 	pollActivePostsGauge.Set(float64(len(trackedPosts)))
 
 	if len(trackedPosts) == 0 {
+		pollSkippedCounter("no_active_posts").Inc()
 		uc.logger.Debug("No active posts to poll")
 		return nil
 	}
 
-	keepAlerts, err := uc.keepClient.GetAlerts(ctx, uc.alertsLimit)
+	fingerprints := make([]string, 0, len(trackedPosts))
+	for _, p := range trackedPosts {
+		fingerprints = append(fingerprints, p.Fingerprint().Value())
+	}
+
+	keepAlerts, err := uc.fetchAlerts(ctx, fingerprints)
 	if err != nil {
 		pollErrorsCounter.Inc()
 		return fmt.Errorf("get alerts from Keep: %w", err)
@@ -86,6 +192,15 @@ func (uc *PollAlertsUseCase) Execute(ctx context.Context) error {
 		slog.Int("keep_alerts", len(keepAlerts)),
 	)
 
+	previousStatuses := uc.statuses
+	if uc.alerts != nil {
+		uc.statuses = make(map[string]alert.Status, len(trackedPosts))
+	}
+	previousEnrichments := uc.enrichments
+	if uc.enrichmentKeys != nil {
+		uc.enrichments = make(map[string]string, len(trackedPosts))
+	}
+
 	for _, trackedPost := range trackedPosts {
 		pollAlertsCheckedCounter.Inc()
 
@@ -98,13 +213,58 @@ func (uc *PollAlertsUseCase) Execute(ctx context.Context) error {
 			continue
 		}
 
-		if keepAlert.Status == alert.StatusResolved {
-			uc.logger.Debug("Skipping resolved alert",
+		if uc.alerts != nil {
+			if uc.syncStatus(ctx, keepAlert, previousStatuses) {
+				continue
+			}
+		} else if alert.RestoreStatus(keepAlert.Status).IsClosed() {
+			uc.logger.Debug("Skipping closed alert",
 				slog.String("fingerprint", fingerprint),
 			)
 			continue
 		}
 
+		// Assignee changes are shown on firing and acknowledged posts only;
+		// re-rendering would drop e.g. the suppressed card.
+		if status := keepStatus(keepAlert); !status.IsFiring() && !status.IsAcknowledged() {
+			continue
+		}
+
+		if uc.postReader != nil {
+			deleted, err := uc.handleIfDeleted(ctx, trackedPost, keepAlert)
+			if err != nil {
+				uc.logger.Error("Failed to handle deleted post",
+					logger.ApplicationFields("poll_deleted_post_failed",
+						slog.String("fingerprint", fingerprint),
+						slog.String("post_id", trackedPost.PostID()),
+						slog.String("action", uc.deletedPosts),
+						slog.Any("error", err),
+					),
+				)
+				pollErrorsCounter.Inc()
+				continue
+			}
+			if deleted {
+				continue
+			}
+		}
+
+		synced, err := uc.syncAcknowledgement(ctx, trackedPost, keepAlert)
+		if err != nil {
+			uc.logger.Error("Failed to sync acknowledgement",
+				logger.ApplicationFields("poll_ack_sync_failed",
+					slog.String("fingerprint", fingerprint),
+					slog.Any("error", err),
+				),
+			)
+			pollErrorsCounter.Inc()
+			continue
+		}
+		if synced {
+			continue
+		}
+
+		pollAlertsComparedCounter.Inc()
 		currentAssignee := uc.resolveAssigneeUsername(keepAlert.Enrichments)
 		lastKnownAssignee := trackedPost.LastKnownAssignee()
 
@@ -137,48 +297,336 @@ func (uc *PollAlertsUseCase) Execute(ctx context.Context) error {
 				pollErrorsCounter.Inc()
 				continue
 			}
+		} else if uc.enrichmentsChanged(keepAlert, previousEnrichments) && trackedPost.SnoozedUntil().IsZero() {
+			if err := uc.refreshEnrichments(ctx, trackedPost, keepAlert, currentAssignee); err != nil {
+				uc.logger.Error("Failed to refresh enrichments",
+					logger.ApplicationFields("poll_enrichments_refresh_failed",
+						slog.String("fingerprint", fingerprint),
+						slog.Any("error", err),
+					),
+				)
+				pollErrorsCounter.Inc()
+				// Retry on the next cycle.
+				uc.enrichments[fingerprint] = previousEnrichments[fingerprint]
+				continue
+			}
 		}
 	}
 
 	return nil
 }
 
-func (uc *PollAlertsUseCase) handleAssigneeChange(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert, newAssignee string) error {
-	fingerprint := trackedPost.Fingerprint()
+// enrichmentsChanged records the shown enrichments of keepAlert and reports
+// whether they differ from the previous cycle's. It is false for alerts seen
+// for the first time and when enrichment refresh is off.
+func (uc *PollAlertsUseCase) enrichmentsChanged(keepAlert port.KeepAlert, previous map[string]string) bool {
+	if uc.enrichmentKeys == nil {
+		return false
+	}
+	var sb strings.Builder
+	for _, key := range uc.enrichmentKeys {
+		sb.WriteString(key)
+		sb.WriteByte(0)
+		sb.WriteString(keepAlert.Enrichments[key])
+		sb.WriteByte(0)
+	}
+	sum := sha256.Sum256([]byte(sb.String()))
+	current := hex.EncodeToString(sum[:])
+	uc.enrichments[keepAlert.Fingerprint] = current
 
-	severity, err := alert.NewSeverity(keepAlert.Severity)
+	last, seen := previous[keepAlert.Fingerprint]
+	return seen && last != current
+}
+
+// refreshEnrichments re-renders the post of keepAlert with its current
+// enrichments, keeping it firing or acknowledged as it is in Keep. Unlike an
+// assignee change it is not announced in the thread.
+func (uc *PollAlertsUseCase) refreshEnrichments(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert, assignee string) error {
+	attachment := uc.currentAttachment(trackedPost, keepAlert, assignee)
+	if err := uc.mmClient.UpdatePost(ctx, trackedPost.PostID(), attachment); err != nil {
+		return fmt.Errorf("update mattermost post: %w", err)
+	}
+
+	uc.logger.Info("Enrichments refreshed via polling",
+		logger.ApplicationFields("poll_enrichments_refreshed",
+			slog.String("fingerprint", trackedPost.Fingerprint().Value()),
+			slog.String("post_id", trackedPost.PostID()),
+		),
+	)
+	pollEnrichmentsRefreshed.Inc()
+	return nil
+}
+
+// fetchAlerts returns the Keep alerts with the given fingerprints.
+func (uc *PollAlertsUseCase) fetchAlerts(ctx context.Context, fingerprints []string) ([]port.KeepAlert, error) {
+	if uc.querier == nil {
+		return uc.keepClient.GetAlerts(ctx, uc.alertsLimit, fingerprints)
+	}
+	var alerts []port.KeepAlert
+	err := uc.querier.QueryAlerts(ctx, port.AlertQuery{Fingerprints: fingerprints, PageSize: uc.pageSize}, func(page []port.KeepAlert) error {
+		alerts = append(alerts, page...)
+		return nil
+	})
+	return alerts, err
+}
+
+// currentAttachment renders the post of keepAlert as it is in Keep: firing,
+// assigned or acknowledged, and snoozed while the post is.
+func (uc *PollAlertsUseCase) currentAttachment(trackedPost *post.Post, keepAlert port.KeepAlert, assignee string) post.Attachment {
+	a := uc.restoreAlert(trackedPost, keepAlert)
+	builder := uc.msgBuilder.ForChannel(trackedPost.ChannelID())
+	switch {
+	case !trackedPost.SnoozedUntil().IsZero():
+		return builder.BuildSnoozedAttachment(a, uc.callbackURL, uc.keepUIURL, "", trackedPost.SnoozedUntil())
+	case keepStatus(keepAlert).IsAcknowledged():
+		return builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+	case assignee != "":
+		return builder.BuildAssignedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+	default:
+		return builder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	}
+}
+
+// handleIfDeleted applies the deleted posts action to keepAlert when its
+// post was deleted in Mattermost, and reports whether the post was deleted.
+func (uc *PollAlertsUseCase) handleIfDeleted(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert) (bool, error) {
+	_, err := uc.postReader.GetPost(ctx, trackedPost.PostID())
+	if err == nil || errors.Is(err, port.ErrMattermostPostUnreadable) {
+		// Posts on platforms that cannot read them back are never checked.
+		return false, nil
+	}
+	if !errors.Is(err, port.ErrMattermostPostNotFound) {
+		return false, fmt.Errorf("get mattermost post: %w", err)
+	}
+
+	switch uc.deletedPosts {
+	case port.DeletedPostUntrack, port.DeletedPostResolve:
+		err = uc.forgetDeleted(ctx, trackedPost)
+	default:
+		err = uc.recreateDeleted(ctx, trackedPost, keepAlert)
+	}
 	if err != nil {
-		severity = trackedPost.Severity()
+		return false, err
 	}
+	pollDeletedPostsCounter(uc.deletedPosts).Inc()
+	return true, nil
+}
 
-	status, err := alert.NewStatus(keepAlert.Status)
+// recreateDeleted posts keepAlert again. The new post takes the place of the
+// deleted one in the store, and its thread notes that it was recreated.
+func (uc *PollAlertsUseCase) recreateDeleted(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert) error {
+	assignee := uc.resolveAssigneeUsername(keepAlert.Enrichments)
+	attachment := uc.currentAttachment(trackedPost, keepAlert, assignee)
+	channelID := trackedPost.ChannelID()
+	postID, err := uc.mmClient.CreatePost(ctx, channelID, attachment)
 	if err != nil {
-		status = alert.RestoreStatus(alert.StatusFiring)
+		return fmt.Errorf("create mattermost post: %w", err)
 	}
 
-	source := strings.Join(keepAlert.Source, ", ")
+	deletedPostID := trackedPost.PostID()
+	trackedPost.Move(postID, channelID)
+	trackedPost.SetLastKnownAssignee(assignee)
+	if trackedPost.SnoozedUntil().IsZero() {
+		trackedPost.ShowStatus(ackStatus(keepAlert))
+	}
+	trackedPost.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, trackedPost.Fingerprint(), trackedPost); err != nil {
+		return fmt.Errorf("save post to store: %w", err)
+	}
 
-	a := alert.RestoreAlert(
-		fingerprint,
-		keepAlert.Name,
-		severity,
-		status,
-		keepAlert.Description,
-		source,
-		keepAlert.Labels,
-		trackedPost.FiringStartTime(),
+	if err := uc.mmClient.ReplyToThread(ctx, channelID, postID, "Post was recreated after deletion"); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", postID),
+			slog.Any("error", err),
+		)
+	}
+
+	uc.logger.Info("Deleted post recreated via polling",
+		logger.ApplicationFields("poll_post_recreated",
+			slog.String("fingerprint", trackedPost.Fingerprint().Value()),
+			slog.String("deleted_post_id", deletedPostID),
+			slog.String("post_id", postID),
+		),
+	)
+	pollPostsRecreated.Inc()
+	return nil
+}
+
+// forgetDeleted stops tracking the alert of trackedPost, after resolving it
+// in Keep when deleted posts resolve their alert. The alert is posted anew
+// when it fires again.
+func (uc *PollAlertsUseCase) forgetDeleted(ctx context.Context, trackedPost *post.Post) error {
+	fingerprint := trackedPost.Fingerprint()
+	if uc.deletedPosts == port.DeletedPostResolve {
+		// Like the Resolve button, the status enrichment clears when the
+		// alert fires again.
+		enrichments := map[string]string{EnrichmentKeyStatus: alert.StatusResolved}
+		if err := uc.keepClient.EnrichAlert(ctx, fingerprint.Value(), enrichments, port.EnrichOptions{DisposeOnNewAlert: true}); err != nil {
+			return fmt.Errorf("resolve alert in keep: %w", err)
+		}
+	}
+	if err := uc.postRepo.Delete(ctx, fingerprint); err != nil {
+		return fmt.Errorf("delete post from store: %w", err)
+	}
+
+	uc.logger.Info("Alert of deleted post no longer tracked",
+		logger.ApplicationFields("poll_post_deleted",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("post_id", trackedPost.PostID()),
+			slog.String("action", uc.deletedPosts),
+		),
+	)
+	return nil
+}
+
+// syncStatus replays keepAlert when its Keep status changed since the previous
+// cycle, or when it was closed in Keep, and reports whether it did. The first
+// status seen for an alert is only recorded, as the post may already show it.
+func (uc *PollAlertsUseCase) syncStatus(ctx context.Context, keepAlert port.KeepAlert, previousStatuses map[string]alert.Status) bool {
+	fingerprint := keepAlert.Fingerprint
+	status := keepStatus(keepAlert)
+	previous, seen := previousStatuses[fingerprint]
+
+	closed := status.IsClosed()
+	if !closed {
+		uc.statuses[fingerprint] = status
+		if !seen || previous == status {
+			return false
+		}
+	}
+
+	uc.logger.Info("Status change detected via polling",
+		logger.ApplicationFields("poll_status_changed",
+			slog.String("fingerprint", fingerprint),
+			slog.String("previous_status", previous.String()),
+			slog.String("new_status", status.String()),
+		),
 	)
+	pollStatusChangedCounter(status.String()).Inc()
+
+	input := keepAlertInput(keepAlert)
+	input.Status = status.Value()
+	if err := uc.alerts.Execute(ctx, input); err != nil {
+		uc.logger.Error("Failed to handle status change",
+			logger.ApplicationFields("poll_status_change_failed",
+				slog.String("fingerprint", fingerprint),
+				slog.Any("error", err),
+			),
+		)
+		pollErrorsCounter.Inc()
+		// Retry on the next cycle.
+		if seen {
+			uc.statuses[fingerprint] = previous
+		} else {
+			delete(uc.statuses, fingerprint)
+		}
+	}
+	return true
+}
+
+// syncAcknowledgement re-renders the post of keepAlert when it was
+// acknowledged or unacknowledged in Keep UI, so its buttons match Keep, and
+// reports whether it did. Snoozed posts are left alone; posts saved before
+// the shown status was recorded only record Keep's.
+func (uc *PollAlertsUseCase) syncAcknowledgement(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert) (bool, error) {
+	if !trackedPost.SnoozedUntil().IsZero() {
+		return false, nil
+	}
+	status := ackStatus(keepAlert)
+	shown := trackedPost.ShownStatus()
+	if shown == status {
+		return false, nil
+	}
+	fingerprint := trackedPost.Fingerprint()
+	if shown == "" {
+		trackedPost.ShowStatus(status)
+		if err := uc.postRepo.Save(ctx, fingerprint, trackedPost); err != nil {
+			uc.logger.Warn("Failed to record post status",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.Any("error", err),
+			)
+		}
+		return false, nil
+	}
 
+	assignee := uc.resolveAssigneeUsername(keepAlert.Enrichments)
+	a := uc.restoreAlert(trackedPost, keepAlert)
+	builder := uc.msgBuilder.ForChannel(trackedPost.ChannelID())
 	var attachment post.Attachment
-	var replyMsg string
+	replyMsg := "Unacknowledged (via Keep UI)"
+	switch {
+	case status == alert.StatusAcknowledged:
+		attachment = builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+		replyMsg = "Acknowledged (via Keep UI)"
+		if assignee != "" {
+			replyMsg = fmt.Sprintf("Acknowledged by @%s (via Keep UI)", assignee)
+		}
+	case assignee != "":
+		attachment = builder.BuildAssignedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+	default:
+		attachment = builder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	}
+
+	if err := uc.mmClient.UpdatePost(ctx, trackedPost.PostID(), attachment); err != nil {
+		return false, fmt.Errorf("update mattermost post: %w", err)
+	}
+	if err := uc.mmClient.ReplyToThread(ctx, trackedPost.ChannelID(), trackedPost.PostID(), replyMsg); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", trackedPost.PostID()),
+			slog.Any("error", err),
+		)
+	}
 
+	uc.logger.Info("Acknowledgement change detected via polling",
+		logger.ApplicationFields("poll_ack_synced",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("previous_status", shown),
+			slog.String("new_status", status),
+			slog.String("assignee", assignee),
+		),
+	)
+	pollStatusChangedCounter(status).Inc()
+
+	trackedPost.ShowStatus(status)
+	trackedPost.SetLastKnownAssignee(assignee)
+	if status == alert.StatusAcknowledged {
+		observeAcknowledged(trackedPost, uc.clock.Now())
+	}
+	trackedPost.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, trackedPost); err != nil {
+		return true, fmt.Errorf("save post to store: %w", err)
+	}
+	return true, nil
+}
+
+// ackStatus returns the status the post of keepAlert shows: acknowledged or
+// firing.
+func ackStatus(keepAlert port.KeepAlert) string {
+	if keepStatus(keepAlert).IsAcknowledged() {
+		return alert.StatusAcknowledged
+	}
+	return alert.StatusFiring
+}
+
+func (uc *PollAlertsUseCase) handleAssigneeChange(ctx context.Context, trackedPost *post.Post, keepAlert port.KeepAlert, newAssignee string) error {
+	fingerprint := trackedPost.Fingerprint()
+	a := uc.restoreAlert(trackedPost, keepAlert)
+
+	builder := uc.msgBuilder.ForChannel(trackedPost.ChannelID())
+	acknowledged := keepStatus(keepAlert).IsAcknowledged()
+
+	var attachment post.Attachment
+	replyMsg := fmt.Sprintf("Assignee changed to @%s (via Keep UI)", newAssignee)
+	switch {
+	case acknowledged:
+		attachment = builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, newAssignee)
+	case newAssignee != "":
+		attachment = builder.BuildAssignedAttachment(a, uc.callbackURL, uc.keepUIURL, newAssignee)
+	default:
+		attachment = builder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	}
 	if newAssignee == "" {
-		// Assignee was removed - show as firing alert
-		attachment = uc.msgBuilder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
 		replyMsg = "Assignee removed (via Keep UI)"
-	} else {
-		attachment = uc.msgBuilder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, newAssignee)
-		replyMsg = fmt.Sprintf("Assignee changed to @%s (via Keep UI)", newAssignee)
 	}
 
 	if err := uc.mmClient.UpdatePost(ctx, trackedPost.PostID(), attachment); err != nil {
@@ -193,7 +641,7 @@ func (uc *PollAlertsUseCase) handleAssigneeChange(ctx context.Context, trackedPo
 	}
 
 	trackedPost.SetLastKnownAssignee(newAssignee)
-	trackedPost.Touch()
+	trackedPost.Touch(uc.clock.Now())
 	if err := uc.postRepo.Save(ctx, fingerprint, trackedPost); err != nil {
 		return fmt.Errorf("save post to store: %w", err)
 	}
@@ -201,6 +649,31 @@ func (uc *PollAlertsUseCase) handleAssigneeChange(ctx context.Context, trackedPo
 	return nil
 }
 
+// restoreAlert returns keepAlert, with its enrichments, as shown in
+// trackedPost.
+func (uc *PollAlertsUseCase) restoreAlert(trackedPost *post.Post, keepAlert port.KeepAlert) *alert.Alert {
+	severity, err := alert.NewSeverity(keepAlert.Severity)
+	if err != nil {
+		severity = trackedPost.Severity()
+	}
+
+	status, err := alert.NewStatus(keepAlert.Status)
+	if err != nil {
+		status = alert.RestoreStatus(alert.StatusFiring)
+	}
+
+	return alert.RestoreAlert(
+		trackedPost.Fingerprint(),
+		keepAlert.Name,
+		severity,
+		status,
+		keepAlert.Description,
+		strings.Join(keepAlert.Source, ", "),
+		keepAlert.Labels,
+		trackedPost.FiringStartTime(),
+	).WithEnrichments(keepAlert.Enrichments).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL).WithFireCount(trackedPost.FireCount())
+}
+
 func (uc *PollAlertsUseCase) resolveAssigneeUsername(enrichments map[string]string) string {
 	if enrichments == nil {
 		return ""
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"testing"
@@ -11,189 +12,44 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
-type mockPollPostRepository struct {
-	posts      map[string]*post.Post
-	findAllErr error
-	saveErr    error
-	saveCalled bool
+// keepAlerts backs the generated Keep client mock with the alerts Keep
+// currently reports.
+type keepAlerts struct {
+	*portmock.KeepClientMock
+	alerts []port.KeepAlert
 }
 
-func newMockPollPostRepository() *mockPollPostRepository {
-	return &mockPollPostRepository{
-		posts: make(map[string]*post.Post),
+func newKeepAlerts() *keepAlerts {
+	k := &keepAlerts{KeepClientMock: newKeepClientMock()}
+	k.GetAlertsFunc = func(context.Context, int, []string) ([]port.KeepAlert, error) {
+		return k.alerts, nil
 	}
-}
-
-func (m *mockPollPostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
-	m.saveCalled = true
-	if m.saveErr != nil {
-		return m.saveErr
-	}
-	m.posts[fingerprint.Value()] = p
-	return nil
-}
-
-func (m *mockPollPostRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
-	p, ok := m.posts[fingerprint.Value()]
-	if !ok {
-		return nil, post.ErrNotFound
-	}
-	return p, nil
-}
-
-func (m *mockPollPostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
-	delete(m.posts, fingerprint.Value())
-	return nil
-}
-
-func (m *mockPollPostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error) {
-	if m.findAllErr != nil {
-		return nil, m.findAllErr
-	}
-	result := make([]*post.Post, 0, len(m.posts))
-	for _, p := range m.posts {
-		result = append(result, p)
-	}
-	return result, nil
-}
-
-type mockPollKeepClient struct {
-	alerts       []port.KeepAlert
-	getAlertsErr error
-}
-
-func (m *mockPollKeepClient) EnrichAlert(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
-	return nil
-}
-
-func (m *mockPollKeepClient) UnenrichAlert(ctx context.Context, fingerprint string, enrichments []string) error {
-	return nil
-}
-
-func (m *mockPollKeepClient) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
-	for _, a := range m.alerts {
-		if a.Fingerprint == fingerprint {
-			return &a, nil
+	k.GetAlertFunc = func(_ context.Context, fingerprint string) (*port.KeepAlert, error) {
+		for _, a := range k.alerts {
+			if a.Fingerprint == fingerprint {
+				return &a, nil
+			}
 		}
+		return nil, errors.New("alert not found")
 	}
-	return nil, errors.New("alert not found")
-}
-
-func (m *mockPollKeepClient) GetAlerts(ctx context.Context, limit int) ([]port.KeepAlert, error) {
-	if m.getAlertsErr != nil {
-		return nil, m.getAlertsErr
-	}
-	return m.alerts, nil
-}
-
-func (m *mockPollKeepClient) GetProviders(ctx context.Context) ([]port.KeepProvider, error) {
-	return nil, nil
-}
-
-func (m *mockPollKeepClient) CreateWebhookProvider(ctx context.Context, config port.WebhookProviderConfig) error {
-	return nil
-}
-
-func (m *mockPollKeepClient) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error) {
-	return nil, nil
-}
-
-func (m *mockPollKeepClient) CreateWorkflow(ctx context.Context, config port.WorkflowConfig) error {
-	return nil
-}
-
-type mockPollMattermostClient struct {
-	updatePostCalled bool
-	updatePostErr    error
-	replyMessage     string
-	replyErr         error
-}
-
-func (m *mockPollMattermostClient) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
-	return "post-123", nil
-}
-
-func (m *mockPollMattermostClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
-	m.updatePostCalled = true
-	return m.updatePostErr
-}
-
-func (m *mockPollMattermostClient) GetUser(ctx context.Context, userID string) (string, error) {
-	return "testuser", nil
-}
-
-func (m *mockPollMattermostClient) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
-	m.replyMessage = message
-	return m.replyErr
-}
-
-type mockPollMessageBuilder struct{}
-
-func (m *mockPollMessageBuilder) BuildFiringAttachment(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
-	return post.Attachment{Color: "#FF0000", Title: "FIRING: " + a.Name()}
-}
-
-func (m *mockPollMessageBuilder) BuildAcknowledgedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string) post.Attachment {
-	return post.Attachment{Color: "#FFA500", Title: "ACK: " + a.Name(), Footer: "Ack by " + username}
-}
-
-func (m *mockPollMessageBuilder) BuildResolvedAttachment(a *alert.Alert, keepUIURL, acknowledgedBy string) post.Attachment {
-	return post.Attachment{Color: "#00FF00", Title: "RESOLVED: " + a.Name()}
-}
-
-func (m *mockPollMessageBuilder) BuildSuppressedAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{}
+	return k
 }
 
-func (m *mockPollMessageBuilder) BuildPendingAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{}
-}
-
-func (m *mockPollMessageBuilder) BuildMaintenanceAttachment(a *alert.Alert, keepUIURL string) post.Attachment {
-	return post.Attachment{}
-}
-
-func (m *mockPollMessageBuilder) BuildProcessingAttachment(attachmentJSON, action string) (post.Attachment, error) {
-	return post.Attachment{}, nil
-}
-
-func (m *mockPollMessageBuilder) BuildErrorAttachment(alertName, fingerprint, keepUIURL, errorMsg string) post.Attachment {
-	return post.Attachment{}
-}
-
-type mockPollUserMapper struct {
-	mapping map[string]string
-}
-
-func (m *mockPollUserMapper) GetKeepUsername(mattermostUsername string) (string, bool) {
-	for mm, keep := range m.mapping {
-		if mm == mattermostUsername {
-			return keep, true
-		}
-	}
-	return "", false
-}
-
-func (m *mockPollUserMapper) GetMattermostUsername(keepUsername string) (string, bool) {
-	for mm, keep := range m.mapping {
-		if keep == keepUsername {
-			return mm, true
-		}
-	}
-	return "", false
-}
-
-func setupPollAlertsUseCase() (*PollAlertsUseCase, *mockPollPostRepository, *mockPollKeepClient, *mockPollMattermostClient, *mockPollUserMapper) {
-	postRepo := newMockPollPostRepository()
-	keepClient := &mockPollKeepClient{}
-	mmClient := &mockPollMattermostClient{}
-	msgBuilder := &mockPollMessageBuilder{}
-	userMapper := &mockPollUserMapper{mapping: make(map[string]string)}
+func setupPollAlertsUseCase() (*PollAlertsUseCase, *postStore, *keepAlerts, *portmock.MattermostClientMock, *userMap) {
+	postRepo := newPostStore()
+	keepClient := newKeepAlerts()
+	mmClient := newMattermostClientMock()
+	msgBuilder := newMessageBuilderMock()
+	userMapper := newUserMap()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	uc := NewPollAlertsUseCase(
@@ -218,14 +74,14 @@ func TestPollAlertsUseCase_NoActivePosts(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.updatePostCalled)
+	assert.Empty(t, mmClient.UpdatePostCalls())
 }
 
 func TestPollAlertsUseCase_FindAllActiveError(t *testing.T) {
 	uc, postRepo, _, _, _ := setupPollAlertsUseCase()
 	ctx := context.Background()
 
-	postRepo.findAllErr = errors.New("redis error")
+	postRepo.FindAllActiveFunc = func(context.Context) ([]*post.Post, error) { return nil, errors.New("redis error") }
 
 	err := uc.Execute(ctx)
 
@@ -238,10 +94,12 @@ func TestPollAlertsUseCase_GetAlertsError(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
-	keepClient.getAlertsErr = errors.New("keep api error")
+	keepClient.GetAlertsFunc = func(context.Context, int, []string) ([]port.KeepAlert, error) {
+		return nil, errors.New("keep api error")
+	}
 
 	err := uc.Execute(ctx)
 
@@ -249,12 +107,25 @@ func TestPollAlertsUseCase_GetAlertsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "get alerts from Keep")
 }
 
+func TestPollAlertsUseCase_RequestsOnlyTrackedFingerprints(t *testing.T) {
+	uc, postRepo, keepClient, _, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	for _, v := range []string{"fp-1", "fp-2"} {
+		fp := alert.RestoreFingerprint(v)
+		postRepo.posts[v] = post.NewPost("post-"+v, "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.ElementsMatch(t, []string{"fp-1", "fp-2"}, lastCall(keepClient.GetAlertsCalls()).Fingerprints)
+}
+
 func TestPollAlertsUseCase_AlertNotFoundInKeep(t *testing.T) {
 	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
 	// Keep returns different alert
@@ -265,7 +136,7 @@ func TestPollAlertsUseCase_AlertNotFoundInKeep(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.updatePostCalled, "should not update post if alert not found in Keep")
+	assert.Empty(t, mmClient.UpdatePostCalls(), "should not update post if alert not found in Keep")
 }
 
 func TestPollAlertsUseCase_SkipResolvedAlert(t *testing.T) {
@@ -273,7 +144,7 @@ func TestPollAlertsUseCase_SkipResolvedAlert(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
 	keepClient.alerts = []port.KeepAlert{
@@ -283,7 +154,7 @@ func TestPollAlertsUseCase_SkipResolvedAlert(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.updatePostCalled, "should skip resolved alerts")
+	assert.Empty(t, mmClient.UpdatePostCalls(), "should skip resolved alerts")
 }
 
 func TestPollAlertsUseCase_NoAssigneeChange(t *testing.T) {
@@ -291,7 +162,7 @@ func TestPollAlertsUseCase_NoAssigneeChange(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	p.SetLastKnownAssignee("existinguser")
 	postRepo.posts[fp.Value()] = p
 
@@ -308,7 +179,7 @@ func TestPollAlertsUseCase_NoAssigneeChange(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.False(t, mmClient.updatePostCalled, "should not update when assignee unchanged")
+	assert.Empty(t, mmClient.UpdatePostCalls(), "should not update when assignee unchanged")
 }
 
 func TestPollAlertsUseCase_DetectAssigneeChange(t *testing.T) {
@@ -316,7 +187,7 @@ func TestPollAlertsUseCase_DetectAssigneeChange(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	p.SetLastKnownAssignee("olduser")
 	postRepo.posts[fp.Value()] = p
 
@@ -333,9 +204,9 @@ func TestPollAlertsUseCase_DetectAssigneeChange(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled, "should update post when assignee changed")
-	assert.Contains(t, mmClient.replyMessage, "newuser")
-	assert.Contains(t, mmClient.replyMessage, "Keep UI")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "should update post when assignee changed")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "newuser")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "Keep UI")
 
 	// Verify assignee was saved
 	savedPost := postRepo.posts[fp.Value()]
@@ -347,7 +218,7 @@ func TestPollAlertsUseCase_DetectAssigneeRemoval(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	p.SetLastKnownAssignee("previoususer")
 	postRepo.posts[fp.Value()] = p
 
@@ -364,8 +235,8 @@ func TestPollAlertsUseCase_DetectAssigneeRemoval(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled, "should update post when assignee removed")
-	assert.Contains(t, mmClient.replyMessage, "removed")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "should update post when assignee removed")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "removed")
 
 	// Verify empty assignee was saved
 	savedPost := postRepo.posts[fp.Value()]
@@ -380,7 +251,7 @@ func TestPollAlertsUseCase_UserMapping(t *testing.T) {
 	userMapper.mapping["johnd"] = "john.doe"
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
 	keepClient.alerts = []port.KeepAlert{
@@ -396,9 +267,9 @@ func TestPollAlertsUseCase_UserMapping(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 	// Should use Mattermost username in reply
-	assert.Contains(t, mmClient.replyMessage, "johnd")
+	assert.Contains(t, lastCall(mmClient.ReplyToThreadCalls()).Message, "johnd")
 }
 
 func TestPollAlertsUseCase_UpdatePostError(t *testing.T) {
@@ -406,7 +277,7 @@ func TestPollAlertsUseCase_UpdatePostError(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
 	keepClient.alerts = []port.KeepAlert{
@@ -419,13 +290,13 @@ func TestPollAlertsUseCase_UpdatePostError(t *testing.T) {
 		},
 	}
 
-	mmClient.updatePostErr = errors.New("mattermost error")
+	mmClient.UpdatePostFunc = func(context.Context, string, post.Attachment) error { return errors.New("mattermost error") }
 
 	err := uc.Execute(ctx)
 
 	// Should not return error, but continue processing
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 	// Assignee should NOT be updated if post update failed
 	assert.Equal(t, "", postRepo.posts[fp.Value()].LastKnownAssignee())
 }
@@ -436,17 +307,17 @@ func TestPollAlertsUseCase_MultipleAlerts(t *testing.T) {
 
 	// Set up 3 tracked posts
 	fp1 := alert.RestoreFingerprint("fp-1")
-	p1 := post.NewPost("post-1", "channel-1", fp1, "Alert 1", alert.RestoreSeverity("high"), time.Now())
+	p1 := post.NewPost("post-1", "channel-1", fp1, "Alert 1", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	p1.SetLastKnownAssignee("user1")
 	postRepo.posts[fp1.Value()] = p1
 
 	fp2 := alert.RestoreFingerprint("fp-2")
-	p2 := post.NewPost("post-2", "channel-1", fp2, "Alert 2", alert.RestoreSeverity("critical"), time.Now())
+	p2 := post.NewPost("post-2", "channel-1", fp2, "Alert 2", alert.RestoreSeverity("critical"), time.Now(), time.Now())
 	p2.SetLastKnownAssignee("user2")
 	postRepo.posts[fp2.Value()] = p2
 
 	fp3 := alert.RestoreFingerprint("fp-3")
-	p3 := post.NewPost("post-3", "channel-1", fp3, "Alert 3", alert.RestoreSeverity("warning"), time.Now())
+	p3 := post.NewPost("post-3", "channel-1", fp3, "Alert 3", alert.RestoreSeverity("warning"), time.Now(), time.Now())
 	postRepo.posts[fp3.Value()] = p3
 
 	keepClient.alerts = []port.KeepAlert{
@@ -458,7 +329,7 @@ func TestPollAlertsUseCase_MultipleAlerts(t *testing.T) {
 	err := uc.Execute(ctx)
 
 	require.NoError(t, err)
-	assert.True(t, mmClient.updatePostCalled, "should update at least one post")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "should update at least one post")
 	// fp-2 should have updated assignee
 	assert.Equal(t, "newuser", postRepo.posts[fp2.Value()].LastKnownAssignee())
 	// fp-1 should remain unchanged
@@ -473,7 +344,7 @@ func TestPollAlertsUseCase_ContextCancellation(t *testing.T) {
 	cancel()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	postRepo.posts[fp.Value()] = p
 
 	keepClient.alerts = []port.KeepAlert{
@@ -496,7 +367,7 @@ func TestPollAlertsUseCase_SaveFailureAfterUpdateSuccess(t *testing.T) {
 	ctx := context.Background()
 
 	fp := alert.RestoreFingerprint("fp-123")
-	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now())
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	p.SetLastKnownAssignee("olduser")
 	postRepo.posts[fp.Value()] = p
 
@@ -511,18 +382,376 @@ func TestPollAlertsUseCase_SaveFailureAfterUpdateSuccess(t *testing.T) {
 	}
 
 	// Mattermost update succeeds, but repository save fails
-	postRepo.saveErr = errors.New("redis connection error")
+	postRepo.SaveFunc = func(context.Context, alert.Fingerprint, *post.Post) error {
+		return errors.New("redis connection error")
+	}
 
 	err := uc.Execute(ctx)
 
 	// Execute should not return error (continues processing other alerts)
 	require.NoError(t, err)
 	// Mattermost was updated (user sees the change)
-	assert.True(t, mmClient.updatePostCalled)
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
 	// Save was attempted but failed
-	assert.True(t, postRepo.saveCalled)
+	assert.NotEmpty(t, postRepo.SaveCalls())
 	// Self-healing behavior: in-memory object was modified, but Redis wasn't updated.
 	// On next poll cycle, data will be re-fetched from Redis (with old assignee),
 	// and the change will be re-detected and re-applied.
 	// This is acceptable eventual consistency behavior.
 }
+
+func TestPollAlertsUseCase_BackoffAfterFailures(t *testing.T) {
+	uc, postRepo, keepClient, _, _ := setupPollAlertsUseCase()
+	uc.SetBackoff(retry.Policy{InitialDelay: time.Minute, Multiplier: 2, MaxDelay: 4 * time.Minute})
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-123")
+	postRepo.posts[fp.Value()] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.GetAlertsFunc = func(context.Context, int, []string) ([]port.KeepAlert, error) {
+		return nil, errors.New("keep api error")
+	}
+
+	// ran reports whether a cycle reached Keep.
+	ran := func() bool {
+		calls := len(keepClient.GetAlertsCalls())
+		_ = uc.Execute(ctx)
+		return len(keepClient.GetAlertsCalls()) > calls
+	}
+
+	assert.True(t, ran(), "the first failure waits one interval")
+	assert.True(t, ran())
+	assert.False(t, ran(), "the second failure waits two intervals")
+	assert.True(t, ran())
+	assert.Equal(t, []bool{false, false, false, true}, []bool{ran(), ran(), ran(), ran()}, "the third failure waits four intervals")
+	assert.Equal(t, []bool{false, false, false, true}, []bool{ran(), ran(), ran(), ran()}, "the wait is capped")
+
+	keepClient.GetAlertsFunc = func(context.Context, int, []string) ([]port.KeepAlert, error) { return nil, nil }
+	assert.Equal(t, []bool{false, false, false, true}, []bool{ran(), ran(), ran(), ran()})
+	assert.True(t, ran(), "a successful cycle resets the backoff")
+}
+
+func TestPollAlertsUseCase_StatusSync(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error { return nil },
+	}
+	uc.SetStatusSync(alerts)
+	ctx := context.Background()
+
+	for _, v := range []string{"fp-1", "fp-2"} {
+		fp := alert.RestoreFingerprint(v)
+		postRepo.posts[v] = post.NewPost("post-"+v, "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	}
+	keepClient.alerts = []port.KeepAlert{
+		{Fingerprint: "fp-1", Name: "Test Alert", Status: "firing", Severity: "high"},
+		{Fingerprint: "fp-2", Name: "Test Alert", Status: "firing", Severity: "high"},
+	}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, alerts.ExecuteCalls(), "the first status seen is only recorded")
+
+	keepClient.alerts[0].Enrichments = map[string]string{"status": "suppressed"}
+	require.NoError(t, uc.Execute(ctx))
+	require.Len(t, alerts.ExecuteCalls(), 1)
+	assert.Equal(t, "fp-1", alerts.ExecuteCalls()[0].Input.Fingerprint)
+	assert.Equal(t, alert.StatusSuppressed, alerts.ExecuteCalls()[0].Input.Status)
+	assert.Empty(t, mmClient.UpdatePostCalls(), "the alert use case renders the change")
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, alerts.ExecuteCalls(), 1, "an unchanged status is not replayed")
+
+	keepClient.alerts[1].Status = "resolved"
+	require.NoError(t, uc.Execute(ctx))
+	require.Len(t, alerts.ExecuteCalls(), 2)
+	assert.Equal(t, "fp-2", alerts.ExecuteCalls()[1].Input.Fingerprint)
+	assert.Equal(t, alert.StatusResolved, alerts.ExecuteCalls()[1].Input.Status)
+}
+
+func TestPollAlertsUseCase_StatusSyncResolvesClosedAlertAtOnce(t *testing.T) {
+	uc, postRepo, keepClient, _, _ := setupPollAlertsUseCase()
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			delete(postRepo.posts, input.Fingerprint)
+			return nil
+		},
+	}
+	uc.SetStatusSync(alerts)
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{
+		{Fingerprint: "fp-1", Name: "Test Alert", Status: "firing", Severity: "high", Enrichments: map[string]string{"status": "resolved"}},
+	}
+
+	require.NoError(t, uc.Execute(ctx))
+	require.Len(t, alerts.ExecuteCalls(), 1, "a tracked alert closed in Keep is resolved without a previous cycle")
+	assert.Equal(t, alert.StatusResolved, alerts.ExecuteCalls()[0].Input.Status)
+	assert.Empty(t, postRepo.posts)
+}
+
+func TestPollAlertsUseCase_StatusSyncRetriesFailedReplay(t *testing.T) {
+	uc, postRepo, keepClient, _, _ := setupPollAlertsUseCase()
+	replayErr := errors.New("mattermost down")
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error { return replayErr },
+	}
+	uc.SetStatusSync(alerts)
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-1", Name: "Test Alert", Status: "firing", Severity: "high"}}
+	require.NoError(t, uc.Execute(ctx))
+
+	keepClient.alerts[0].Status = "acknowledged"
+	require.NoError(t, uc.Execute(ctx), "a failed replay does not fail the cycle")
+	replayErr = nil
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, alerts.ExecuteCalls(), 2, "the change is replayed again on the next cycle")
+}
+
+func TestPollAlertsUseCase_EnrichmentRefresh(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	uc.SetEnrichmentRefresh([]string{"ai_summary"})
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{
+		Fingerprint: "fp-1",
+		Name:        "Test Alert",
+		Status:      "firing",
+		Severity:    "high",
+		Enrichments: map[string]string{"ai_summary": "Disk is filling up"},
+	}}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, mmClient.UpdatePostCalls(), "the first enrichments seen are only recorded")
+
+	keepClient.alerts[0].Enrichments = map[string]string{"ai_summary": "Disk is filling up", "note": "unrelated"}
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, mmClient.UpdatePostCalls(), "enrichments that are not shown are ignored")
+
+	keepClient.alerts[0].Enrichments = map[string]string{"ai_summary": "Disk full in 10 minutes"}
+	mmClient.UpdatePostFunc = func(context.Context, string, post.Attachment) error { return errors.New("mattermost down") }
+	require.NoError(t, uc.Execute(ctx))
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+
+	mmClient.UpdatePostFunc = func(context.Context, string, post.Attachment) error { return nil }
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, mmClient.UpdatePostCalls(), 2, "a failed refresh is retried")
+	assert.Empty(t, mmClient.ReplyToThreadCalls(), "refreshes are not announced")
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, mmClient.UpdatePostCalls(), 2)
+}
+
+func TestPollAlertsUseCase_RecreatesDeletedPost(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	for _, v := range []string{"fp-deleted", "fp-kept"} {
+		fp := alert.RestoreFingerprint(v)
+		postRepo.posts[v] = post.NewPost("post-"+v, "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+		keepClient.alerts = append(keepClient.alerts, port.KeepAlert{Fingerprint: v, Name: "Test Alert", Severity: "high", Status: "firing"})
+	}
+	reader := deletedPostReader()
+	uc.SetDeletedPosts(reader, port.DeletedPostRecreate)
+
+	require.NoError(t, uc.Execute(ctx))
+
+	assert.Len(t, reader.GetPostCalls(), 2)
+	recreated := postRepo.posts["fp-deleted"]
+	assert.Equal(t, "post-123", recreated.PostID(), "the new post replaces the deleted one")
+	assert.Equal(t, "channel-1", recreated.ChannelID())
+	assert.Equal(t, "Post was recreated after deletion", lastCall(mmClient.ReplyToThreadCalls()).Message)
+	assert.Equal(t, "post-fp-kept", postRepo.posts["fp-kept"].PostID())
+}
+
+func TestPollAlertsUseCase_PostCheckFails(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-123")
+	postRepo.posts[fp.Value()] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-123", Name: "Test Alert", Severity: "high", Status: "firing"}}
+	uc.SetDeletedPosts(&portmock.MattermostPostReaderMock{
+		GetPostFunc: func(ctx context.Context, postID string) (port.MattermostPost, error) {
+			return port.MattermostPost{}, errors.New("mattermost GetPost: status 500")
+		},
+	}, port.DeletedPostResolve)
+
+	require.NoError(t, uc.Execute(ctx), "a failed check does not fail the cycle")
+	assert.Equal(t, "post-1", postRepo.posts["fp-123"].PostID())
+	assert.Empty(t, mmClient.ReplyToThreadCalls())
+	assert.Empty(t, keepClient.EnrichAlertCalls(), "alerts are only resolved when their post is gone")
+}
+
+func TestPollAlertsUseCase_SkipsPostsThatCannotBeRead(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-123")
+	postRepo.posts[fp.Value()] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-123", Name: "Test Alert", Severity: "high", Status: "firing", Enrichments: map[string]string{"assignee": "john"}}}
+	uc.SetDeletedPosts(&portmock.MattermostPostReaderMock{
+		GetPostFunc: func(ctx context.Context, postID string) (port.MattermostPost, error) {
+			return port.MattermostPost{}, fmt.Errorf("read post: %w: not supported on teams", port.ErrMattermostPostUnreadable)
+		},
+	}, port.DeletedPostRecreate)
+	errorsBefore := pollErrorsCounter.Get()
+
+	require.NoError(t, uc.Execute(ctx))
+
+	assert.Equal(t, errorsBefore, pollErrorsCounter.Get(), "an unreadable post is not a failed check")
+	assert.Empty(t, mmClient.CreatePostCalls(), "the post is not recreated")
+	assert.Equal(t, "post-1", postRepo.posts["fp-123"].PostID())
+	assert.Equal(t, "john", postRepo.posts["fp-123"].LastKnownAssignee(), "the alert is still compared with Keep")
+}
+
+// deletedPostReader reports the post of fp-deleted as deleted.
+func deletedPostReader() *portmock.MattermostPostReaderMock {
+	return &portmock.MattermostPostReaderMock{
+		GetPostFunc: func(ctx context.Context, postID string) (port.MattermostPost, error) {
+			if postID == "post-fp-deleted" {
+				return port.MattermostPost{}, port.ErrMattermostPostNotFound
+			}
+			return port.MattermostPost{ID: postID, ChannelID: "channel-1"}, nil
+		},
+	}
+}
+
+func TestPollAlertsUseCase_UntracksDeletedPost(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	for _, v := range []string{"fp-deleted", "fp-kept"} {
+		fp := alert.RestoreFingerprint(v)
+		postRepo.posts[v] = post.NewPost("post-"+v, "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+		keepClient.alerts = append(keepClient.alerts, port.KeepAlert{Fingerprint: v, Name: "Test Alert", Severity: "high", Status: "firing"})
+	}
+	uc.SetDeletedPosts(deletedPostReader(), port.DeletedPostUntrack)
+
+	require.NoError(t, uc.Execute(ctx))
+
+	assert.NotContains(t, postRepo.posts, "fp-deleted")
+	assert.Contains(t, postRepo.posts, "fp-kept")
+	assert.Empty(t, keepClient.EnrichAlertCalls(), "the alert stays firing in Keep")
+	assert.Empty(t, mmClient.ReplyToThreadCalls())
+}
+
+func TestPollAlertsUseCase_ResolvesAlertOfDeletedPost(t *testing.T) {
+	uc, postRepo, keepClient, _, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-deleted")
+	postRepo.posts["fp-deleted"] = post.NewPost("post-fp-deleted", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-deleted", Name: "Test Alert", Severity: "high", Status: "acknowledged"}}
+	uc.SetDeletedPosts(deletedPostReader(), port.DeletedPostResolve)
+
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+		return errors.New("keep down")
+	}
+	require.NoError(t, uc.Execute(ctx))
+	assert.Contains(t, postRepo.posts, "fp-deleted", "the alert stays tracked until Keep resolved it")
+
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error { return nil }
+	require.NoError(t, uc.Execute(ctx))
+	assert.Equal(t, map[string]string{EnrichmentKeyStatus: alert.StatusResolved}, lastCall(keepClient.EnrichAlertCalls()).Enrichments)
+	assert.NotContains(t, postRepo.posts, "fp-deleted")
+}
+
+func TestPollAlertsUseCase_PagedQueries(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	for _, v := range []string{"fp-1", "fp-2"} {
+		fp := alert.RestoreFingerprint(v)
+		postRepo.posts[v] = post.NewPost("post-"+v, "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	}
+	querier := &portmock.KeepAlertQuerierMock{
+		QueryAlertsFunc: func(ctx context.Context, query port.AlertQuery, handle func(page []port.KeepAlert) error) error {
+			if err := handle([]port.KeepAlert{{Fingerprint: "fp-1", Name: "Test Alert", Severity: "high", Status: "firing"}}); err != nil {
+				return err
+			}
+			return handle([]port.KeepAlert{{Fingerprint: "fp-2", Name: "Test Alert", Severity: "high", Status: "acknowledged", Enrichments: map[string]string{"assignee": "john"}}})
+		},
+	}
+	uc.SetPagedQueries(querier, 50)
+
+	require.NoError(t, uc.Execute(ctx))
+
+	require.Len(t, querier.QueryAlertsCalls(), 1)
+	query := querier.QueryAlertsCalls()[0].Query
+	assert.ElementsMatch(t, []string{"fp-1", "fp-2"}, query.Fingerprints)
+	assert.Equal(t, 50, query.PageSize)
+	assert.Empty(t, keepClient.GetAlertsCalls(), "GetAlerts is not used")
+	assert.NotEmpty(t, mmClient.UpdatePostCalls(), "alerts of later pages are compared too")
+}
+
+func TestPollAlertsUseCase_SyncsAcknowledgementFromKeep(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	p.ShowStatus(alert.StatusFiring)
+	postRepo.posts["fp-1"] = p
+	keepClient.alerts = []port.KeepAlert{{
+		Fingerprint: "fp-1",
+		Name:        "Test Alert",
+		Status:      "firing",
+		Severity:    "high",
+		Enrichments: map[string]string{"status": "acknowledged", "assignee": "john"},
+	}}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, "Acknowledged by @john (via Keep UI)", lastCall(mmClient.ReplyToThreadCalls()).Message)
+	assert.Equal(t, alert.StatusAcknowledged, postRepo.posts["fp-1"].ShownStatus())
+	assert.Equal(t, "john", postRepo.posts["fp-1"].LastKnownAssignee())
+	assert.Equal(t, fake.Now(), postRepo.posts["fp-1"].AcknowledgedAt(), "the acknowledgement is timed by the use-case clock")
+
+	updates := len(mmClient.UpdatePostCalls())
+	require.NoError(t, uc.Execute(ctx))
+	assert.Len(t, mmClient.UpdatePostCalls(), updates, "a post showing Keep's status is left alone")
+
+	keepClient.alerts[0].Enrichments = map[string]string{}
+	require.NoError(t, uc.Execute(ctx))
+	assert.Greater(t, len(mmClient.UpdatePostCalls()), updates)
+	assert.Equal(t, "Unacknowledged (via Keep UI)", lastCall(mmClient.ReplyToThreadCalls()).Message)
+	assert.Equal(t, alert.StatusFiring, postRepo.posts["fp-1"].ShownStatus())
+	assert.Empty(t, postRepo.posts["fp-1"].LastKnownAssignee())
+}
+
+func TestPollAlertsUseCase_RecordsStatusOfLegacyPost(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-1", Name: "Test Alert", Status: "acknowledged", Severity: "high"}}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, mmClient.UpdatePostCalls(), "a post without a recorded status only records Keep's")
+	assert.Equal(t, alert.StatusAcknowledged, postRepo.posts["fp-1"].ShownStatus())
+}
+
+func TestPollAlertsUseCase_AckSyncSkipsSnoozedPost(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupPollAlertsUseCase()
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-1")
+	p := post.NewPost("post-1", "channel-1", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	p.ShowStatus(alert.StatusFiring)
+	p.Snooze(time.Now().Add(time.Hour))
+	postRepo.posts["fp-1"] = p
+	keepClient.alerts = []port.KeepAlert{{Fingerprint: "fp-1", Name: "Test Alert", Status: "acknowledged", Severity: "high"}}
+
+	require.NoError(t, uc.Execute(ctx))
+	assert.Empty(t, mmClient.UpdatePostCalls())
+	assert.Equal(t, alert.StatusFiring, postRepo.posts["fp-1"].ShownStatus())
+}
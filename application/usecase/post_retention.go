@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	// retentionBatchSize caps how many due posts one run processes.
+	retentionBatchSize = 100
+	// retentionGiveUpAfter is how long a failing retention action is retried
+	// before the entry is dropped, e.g. because the post was deleted by hand.
+	retentionGiveUpAfter = 24 * time.Hour
+)
+
+// RetentionPolicy says what happens to resolved alert posts and when.
+type RetentionPolicy struct {
+	Action           string // one of the retention.Action* constants
+	Delay            time.Duration
+	ArchiveChannelID string // target channel for retention.ActionArchive
+}
+
+// PostRetention deletes, collapses or archives resolved alert posts once the
+// policy delay has passed. Posts are queued by Schedule when they resolve and
+// processed by Apply, which runs as a background job.
+type PostRetention struct {
+	repo       retention.Repository
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	policy     RetentionPolicy
+	keepUIURL  string
+	clock      clock.Clock
+	logger     *slog.Logger
+}
+
+func NewPostRetention(
+	repo retention.Repository,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	policy RetentionPolicy,
+	keepUIURL string,
+	logger *slog.Logger,
+) *PostRetention {
+	return &PostRetention{
+		repo:       repo,
+		mmClient:   mmClient,
+		msgBuilder: msgBuilder,
+		policy:     policy,
+		keepUIURL:  keepUIURL,
+		clock:      clock.Real(),
+		logger:     logger,
+	}
+}
+
+// SetClock replaces the clock that stamps resolved posts and decides when
+// they are due.
+func (r *PostRetention) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// Schedule queues the resolved post of a for the retention action. attachment
+// is the resolved post as rendered, reposted as is when archiving.
+func (r *PostRetention) Schedule(ctx context.Context, postID, channelID string, a *alert.Alert, attachment post.Attachment) error {
+	now := r.clock.Now()
+	entry := retention.NewEntry(postID, channelID, a.Fingerprint(), a.Name(), attachment, now, now.Add(r.policy.Delay))
+	if err := r.repo.Save(ctx, entry); err != nil {
+		return fmt.Errorf("schedule retention: %w", err)
+	}
+	return nil
+}
+
+// Apply runs the retention action on every post that is due. Failed actions
+// are retried on the next run until retentionGiveUpAfter has passed.
+func (r *PostRetention) Apply(ctx context.Context) error {
+	now := r.clock.Now()
+	entries, err := r.repo.FindDue(ctx, now, retentionBatchSize)
+	if err != nil {
+		return fmt.Errorf("find due posts: %w", err)
+	}
+
+	var errs []error
+	for _, e := range entries {
+		if err := r.apply(ctx, e); err != nil {
+			retentionErrorsCounter.Inc()
+			if now.Sub(e.DueAt()) < retentionGiveUpAfter {
+				errs = append(errs, fmt.Errorf("%s post %s: %w", r.policy.Action, e.PostID(), err))
+				continue
+			}
+			r.logger.Warn("Giving up on retention action",
+				slog.String("post_id", e.PostID()),
+				slog.String("fingerprint", e.Fingerprint().Value()),
+				slog.String("action", r.policy.Action),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			r.logger.Info("Retention action applied",
+				logger.ApplicationFields("retention_applied",
+					slog.String("post_id", e.PostID()),
+					slog.String("fingerprint", e.Fingerprint().Value()),
+					slog.String("action", r.policy.Action),
+				),
+			)
+			retentionActionsCounter(r.policy.Action).Inc()
+		}
+		if err := r.repo.Delete(ctx, e.PostID()); err != nil {
+			errs = append(errs, fmt.Errorf("remove retention entry %s: %w", e.PostID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *PostRetention) apply(ctx context.Context, e *retention.Entry) error {
+	switch r.policy.Action {
+	case retention.ActionDelete:
+		return r.mmClient.DeletePost(ctx, e.PostID())
+	case retention.ActionCollapse:
+		attachment := r.msgBuilder.BuildCollapsedAttachment(e.AlertName(), e.Fingerprint().Value(), r.keepUIURL, e.ResolvedAt())
+		return r.mmClient.UpdatePost(ctx, e.PostID(), attachment)
+	case retention.ActionArchive:
+		if e.ArchivedPostID() == "" {
+			archivedPostID, err := r.mmClient.CreatePost(ctx, r.policy.ArchiveChannelID, e.Attachment())
+			if err != nil {
+				return fmt.Errorf("post archive copy: %w", err)
+			}
+			e.MarkArchived(archivedPostID)
+			if err := r.repo.Save(ctx, e); err != nil {
+				return fmt.Errorf("save archived post: %w", err)
+			}
+		}
+		return r.mmClient.DeletePost(ctx, e.PostID())
+	default:
+		return fmt.Errorf("unknown retention action %q", r.policy.Action)
+	}
+}
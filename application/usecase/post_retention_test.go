@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// memoryRetentionRepository keeps entries by post ID.
+type memoryRetentionRepository struct {
+	entries map[string]*retention.Entry
+}
+
+func newMemoryRetentionRepository() *memoryRetentionRepository {
+	return &memoryRetentionRepository{entries: make(map[string]*retention.Entry)}
+}
+
+func (m *memoryRetentionRepository) Save(ctx context.Context, e *retention.Entry) error {
+	m.entries[e.PostID()] = e
+	return nil
+}
+
+func (m *memoryRetentionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*retention.Entry, error) {
+	var due []*retention.Entry
+	for _, e := range m.entries {
+		if !e.DueAt().After(now) {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].DueAt().Before(due[j].DueAt()) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *memoryRetentionRepository) Delete(ctx context.Context, postID string) error {
+	delete(m.entries, postID)
+	return nil
+}
+
+func setupPostRetention(action string) (*PostRetention, *memoryRetentionRepository, *portmock.MattermostClientMock, *clock.Fake) {
+	repo := newMemoryRetentionRepository()
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "archived-1", nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+		DeletePostFunc: func(ctx context.Context, postID string) error {
+			return nil
+		},
+	}
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildCollapsedAttachmentFunc: func(alertName, fingerprint, keepUIURL string, resolvedAt time.Time) post.Attachment {
+			return post.Attachment{Text: "collapsed " + alertName}
+		},
+	}
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := NewPostRetention(repo, mmClient, msgBuilder, RetentionPolicy{
+		Action:           action,
+		Delay:            time.Hour,
+		ArchiveChannelID: "archive-channel",
+	}, "https://keep.example.com", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	r.SetClock(fake)
+	return r, repo, mmClient, fake
+}
+
+func scheduleTestPost(t *testing.T, r *PostRetention) {
+	t.Helper()
+	a := alert.RestoreAlert(alert.RestoreFingerprint("fp-1"), "Disk full", alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusResolved), "", "", nil, time.Time{})
+	require.NoError(t, r.Schedule(context.Background(), "post-1", "channel-1", a, post.Attachment{Text: "resolved"}))
+}
+
+func TestPostRetention_Actions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delete", func(t *testing.T) {
+		r, repo, mmClient, fake := setupPostRetention(retention.ActionDelete)
+		scheduleTestPost(t, r)
+
+		require.NoError(t, r.Apply(ctx))
+		assert.Empty(t, mmClient.DeletePostCalls(), "not due yet")
+
+		fake.Advance(time.Hour)
+		require.NoError(t, r.Apply(ctx))
+		require.Len(t, mmClient.DeletePostCalls(), 1)
+		assert.Equal(t, "post-1", mmClient.DeletePostCalls()[0].PostID)
+		assert.Empty(t, repo.entries)
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		r, repo, mmClient, fake := setupPostRetention(retention.ActionCollapse)
+		scheduleTestPost(t, r)
+
+		fake.Advance(time.Hour)
+		require.NoError(t, r.Apply(ctx))
+		require.Len(t, mmClient.UpdatePostCalls(), 1)
+		assert.Equal(t, "post-1", mmClient.UpdatePostCalls()[0].PostID)
+		assert.Equal(t, "collapsed Disk full", mmClient.UpdatePostCalls()[0].Attachment.Text)
+		assert.Empty(t, mmClient.DeletePostCalls())
+		assert.Empty(t, repo.entries)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		r, repo, mmClient, fake := setupPostRetention(retention.ActionArchive)
+		scheduleTestPost(t, r)
+
+		fake.Advance(time.Hour)
+		require.NoError(t, r.Apply(ctx))
+		require.Len(t, mmClient.CreatePostCalls(), 1)
+		assert.Equal(t, "archive-channel", mmClient.CreatePostCalls()[0].ChannelID)
+		assert.Equal(t, "resolved", mmClient.CreatePostCalls()[0].Attachment.Text)
+		require.Len(t, mmClient.DeletePostCalls(), 1)
+		assert.Equal(t, "post-1", mmClient.DeletePostCalls()[0].PostID)
+		assert.Empty(t, repo.entries)
+	})
+}
+
+func TestPostRetention_ArchiveRetryDoesNotRepost(t *testing.T) {
+	ctx := context.Background()
+	r, repo, mmClient, fake := setupPostRetention(retention.ActionArchive)
+	deleteErr := errors.New("mattermost down")
+	mmClient.DeletePostFunc = func(ctx context.Context, postID string) error {
+		return deleteErr
+	}
+	scheduleTestPost(t, r)
+
+	fake.Advance(time.Hour)
+	err := r.Apply(ctx)
+	require.ErrorIs(t, err, deleteErr)
+	require.Contains(t, repo.entries, "post-1", "kept for retry")
+	assert.Equal(t, "archived-1", repo.entries["post-1"].ArchivedPostID())
+
+	mmClient.DeletePostFunc = func(ctx context.Context, postID string) error {
+		return nil
+	}
+	require.NoError(t, r.Apply(ctx))
+	assert.Len(t, mmClient.CreatePostCalls(), 1, "archive copy is posted once")
+	assert.Len(t, mmClient.DeletePostCalls(), 2)
+	assert.Empty(t, repo.entries)
+}
+
+func TestPostRetention_GivesUp(t *testing.T) {
+	ctx := context.Background()
+	r, repo, mmClient, fake := setupPostRetention(retention.ActionDelete)
+	mmClient.DeletePostFunc = func(ctx context.Context, postID string) error {
+		return errors.New("not found")
+	}
+	scheduleTestPost(t, r)
+
+	fake.Advance(time.Hour)
+	require.Error(t, r.Apply(ctx))
+	require.Contains(t, repo.entries, "post-1")
+
+	fake.Advance(retentionGiveUpAfter)
+	require.NoError(t, r.Apply(ctx))
+	assert.Empty(t, repo.entries)
+}
+
+func TestHandleAlertUseCase_ResolvedSchedulesRetention(t *testing.T) {
+	uc, postRepo, _, _, _, _ := setupHandleAlertUseCase()
+	r, repo, _, _ := setupPostRetention(retention.ActionDelete)
+	uc.SetPostRetention(r)
+	fp := alert.RestoreFingerprint("fp-1")
+	postRepo.posts[fp.Value()] = post.RestorePost("post-1", "channel-1", fp, "Disk full", alert.RestoreSeverity(alert.SeverityCritical), time.Now(), time.Now(), time.Now(), "")
+
+	a := alert.RestoreAlert(fp, "Disk full", alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusResolved), "", "", nil, time.Time{})
+	require.NoError(t, uc.handleResolved(context.Background(), a, fp))
+
+	require.Contains(t, repo.entries, "post-1")
+	assert.Equal(t, "channel-1", repo.entries["post-1"].ChannelID())
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// actionQueue holds the alert actions clicked while Keep was unavailable,
+// oldest first. It lives in memory, so a restart drops it.
+type actionQueue struct {
+	keep port.KeepAvailability
+	size int
+
+	mu     sync.Mutex
+	inputs []dto.MattermostCallbackInput
+}
+
+func (q *actionQueue) push(input dto.MattermostCallbackInput) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.inputs) >= q.size {
+		return false
+	}
+	q.inputs = append(q.inputs, input)
+	callbackQueueLengthGauge.Set(float64(len(q.inputs)))
+	return true
+}
+
+func (q *actionQueue) pop() (dto.MattermostCallbackInput, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.inputs) == 0 {
+		return dto.MattermostCallbackInput{}, false
+	}
+	input := q.inputs[0]
+	q.inputs = q.inputs[1:]
+	callbackQueueLengthGauge.Set(float64(len(q.inputs)))
+	return input, true
+}
+
+// pushFront puts back an action that could not be applied yet.
+func (q *actionQueue) pushFront(input dto.MattermostCallbackInput) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inputs = append([]dto.MattermostCallbackInput{input}, q.inputs...)
+	callbackQueueLengthGauge.Set(float64(len(q.inputs)))
+}
+
+// SetKeepAvailability answers alert actions clicked while keep reports Keep
+// unavailable at once, with an ephemeral message, instead of letting them
+// time out. Acknowledge, resolve, unacknowledge, snooze and assign are
+// queued, up to size, and applied by DrainQueuedActions once Keep is back;
+// other actions are turned down. With SetPendingActions, acknowledge, resolve
+// and unacknowledge are kept for ReplayPendingActions instead. Nil, the
+// default, always calls Keep.
+func (uc *HandleCallbackUseCase) SetKeepAvailability(keep port.KeepAvailability, size int) {
+	if keep == nil {
+		uc.queue = nil
+		return
+	}
+	uc.queue = &actionQueue{keep: keep, size: size}
+}
+
+// queueIfKeepUnavailable answers the immediate phase of input while Keep is
+// unavailable. It returns nil when Keep is available.
+func (uc *HandleCallbackUseCase) queueIfKeepUnavailable(input dto.MattermostCallbackInput) *dto.CallbackOutput {
+	if uc.queue == nil || uc.queue.keep.Available() {
+		return nil
+	}
+
+	action := input.Context[post.ContextKeyAction]
+	if uc.pending != nil && replayableAction(action) {
+		// The action runs as usual: its Keep update fails on the open
+		// circuit and is kept for replay, which outlives a restart.
+		return nil
+	}
+	switch action {
+	case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge, post.ActionSnooze, post.ActionAssign:
+	default:
+		callbacksQueuedCounter("rejected").Inc()
+		return &dto.CallbackOutput{Ephemeral: "Keep is unavailable, try again later."}
+	}
+	if !uc.queue.push(input) {
+		callbacksQueuedCounter("rejected").Inc()
+		uc.logger.Warn("Callback queue full while Keep is unavailable",
+			logger.ApplicationFields("callback_queue_full",
+				slog.String("action", action),
+				slog.String("fingerprint", input.Context[post.ContextKeyFingerprint]),
+			),
+		)
+		return &dto.CallbackOutput{Ephemeral: "Keep is unavailable and too many actions are waiting, try again later."}
+	}
+
+	callbacksQueuedCounter("queued").Inc()
+	uc.logger.Info("Callback queued while Keep is unavailable",
+		logger.ApplicationFields("callback_queued",
+			slog.String("action", action),
+			slog.String("fingerprint", input.Context[post.ContextKeyFingerprint]),
+			slog.String("user_id", input.UserID),
+		),
+	)
+	return &dto.CallbackOutput{Ephemeral: "Keep is unavailable, action queued. It is applied once Keep is back."}
+}
+
+// DrainQueuedActions applies the actions queued while Keep was unavailable,
+// oldest first, as if they had just been clicked. Each action first fetches
+// its alert from Keep; while Keep is unavailable the action and those after
+// it stay queued for the next run. An action whose alert Keep does not return
+// for any other reason, such as a 404, is dropped and its post shows the
+// error.
+func (uc *HandleCallbackUseCase) DrainQueuedActions(ctx context.Context) error {
+	if uc.queue == nil {
+		return nil
+	}
+	for uc.queue.keep.Available() {
+		input, ok := uc.queue.pop()
+		if !ok {
+			return nil
+		}
+		action := input.Context[post.ContextKeyAction]
+		fingerprint := input.Context[post.ContextKeyFingerprint]
+		alertName := input.Context[post.ContextKeyAlertName]
+
+		if _, err := uc.keepClient.GetAlert(ctx, fingerprint); err != nil {
+			if errors.Is(err, port.ErrKeepUnavailable) {
+				uc.queue.pushFront(input)
+				uc.logger.Warn("Keep still unavailable, queued callbacks wait",
+					logger.ApplicationFields("callback_queue_waiting",
+						slog.String("error", err.Error()),
+					),
+				)
+				return nil
+			}
+			callbacksQueuedCounter("dropped").Inc()
+			uc.logger.Error("Queued callback dropped",
+				logger.ApplicationFields("callback_queue_dropped",
+					slog.String("action", action),
+					slog.String("fingerprint", fingerprint),
+					slog.String("error", err.Error()),
+				),
+			)
+			uc.updatePostWithError(ctx, input.PostID, alertName, fingerprint, "Failed to get alert data")
+			continue
+		}
+
+		err := uc.withLock(ctx, fingerprint, func(ctx context.Context) error {
+			uc.executeAsync(ctx, input, action, fingerprint, alertName)
+			return nil
+		})
+		if err != nil {
+			uc.logger.Error("Failed to lock alert for queued callback",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+			uc.updatePostWithError(ctx, input.PostID, alertName, fingerprint, "Alert is busy, try again")
+			continue
+		}
+		callbacksQueuedCounter("applied").Inc()
+		uc.logger.Info("Queued callback applied",
+			logger.ApplicationFields("callback_queue_applied",
+				slog.String("action", action),
+				slog.String("fingerprint", fingerprint),
+				slog.String("user_id", input.UserID),
+			),
+		)
+	}
+	return nil
+}
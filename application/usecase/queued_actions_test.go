@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// keepSwitch reports Keep as available while up is true.
+type keepSwitch struct{ up bool }
+
+func (k *keepSwitch) Available() bool { return k.up }
+
+func queuedActionInput(action string) dto.MattermostCallbackInput {
+	return dto.MattermostCallbackInput{
+		UserID:    "user-123",
+		PostID:    "post-456",
+		ChannelID: "channel-789",
+		Context: map[string]string{
+			post.ContextKeyAction:         action,
+			post.ContextKeyFingerprint:    "fp-12345",
+			post.ContextKeyAlertName:      "Test Alert",
+			post.ContextKeyAttachmentJSON: `{"Color":"#808080","Title":"Test Alert"}`,
+		},
+	}
+}
+
+func TestHandleCallbackUseCase_QueuesActionsWhileKeepUnavailable(t *testing.T) {
+	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	keep := &keepSwitch{}
+	uc.SetKeepAvailability(keep, 1)
+
+	result, err := uc.ExecuteImmediate(queuedActionInput(post.ActionAcknowledge))
+	require.NoError(t, err)
+	assert.Contains(t, result.Ephemeral, "action queued")
+	assert.False(t, result.RunAsync)
+
+	result, err = uc.ExecuteImmediate(queuedActionInput(post.ActionResolve))
+	require.NoError(t, err)
+	assert.Contains(t, result.Ephemeral, "too many actions", "the queue is full")
+
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+	assert.Empty(t, keepClient.EnrichAlertCalls(), "nothing is applied while Keep is unavailable")
+
+	keep.up = true
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+	assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"])
+	assert.NotEmpty(t, mmClient.UpdatePostCalls())
+
+	result, err = uc.ExecuteImmediate(queuedActionInput(post.ActionAcknowledge))
+	require.NoError(t, err)
+	assert.Empty(t, result.Ephemeral, "actions run as usual once Keep is back")
+}
+
+func TestHandleCallbackUseCase_DrainKeepsActionsWhileKeepFails(t *testing.T) {
+	uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+	keep := &keepSwitch{}
+	uc.SetKeepAvailability(keep, 10)
+
+	_, err := uc.ExecuteImmediate(queuedActionInput(post.ActionAcknowledge))
+	require.NoError(t, err)
+
+	// The circuit is due for a probe, but Keep still fails.
+	keep.up = true
+	keepClient.GetAlertFunc = keepAlertError(fmt.Errorf("keep get alert: %w: circuit breaker open", port.ErrKeepUnavailable))
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+	assert.Empty(t, keepClient.EnrichAlertCalls())
+
+	keepClient.GetAlertFunc = keepAlertFor("high")
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+	assert.NotEmpty(t, keepClient.EnrichAlertCalls(), "the action stayed queued")
+}
+
+func TestHandleCallbackUseCase_DrainDropsActionsKeepRejects(t *testing.T) {
+	uc, _, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	keep := &keepSwitch{}
+	uc.SetKeepAvailability(keep, 10)
+
+	gone := queuedActionInput(post.ActionAcknowledge)
+	gone.PostID = "post-gone"
+	gone.Context[post.ContextKeyFingerprint] = "fp-gone"
+	_, err := uc.ExecuteImmediate(gone)
+	require.NoError(t, err)
+	_, err = uc.ExecuteImmediate(queuedActionInput(post.ActionAcknowledge))
+	require.NoError(t, err)
+
+	keep.up = true
+	found := keepAlertFor("high")
+	keepClient.GetAlertFunc = func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+		if fingerprint == "fp-gone" {
+			return nil, fmt.Errorf("keep get alert: %w: status 404", port.ErrKeepAlertNotFound)
+		}
+		return found(ctx, fingerprint)
+	}
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+
+	require.NotEmpty(t, mmClient.UpdatePostCalls())
+	first := mmClient.UpdatePostCalls()[0]
+	assert.Equal(t, "post-gone", first.PostID)
+	assert.Contains(t, first.Attachment.Text, "Failed to get alert data")
+	assert.Equal(t, "acknowledged", enrichedWith(keepClient)["status"], "the action after the rejected one is applied")
+	for _, call := range keepClient.EnrichAlertCalls() {
+		assert.Equal(t, "fp-12345", call.Fingerprint)
+	}
+
+	enriched := len(keepClient.EnrichAlertCalls())
+	require.NoError(t, uc.DrainQueuedActions(context.Background()))
+	assert.Len(t, keepClient.EnrichAlertCalls(), enriched, "the rejected action is not queued again")
+}
+
+func TestHandleCallbackUseCase_KeepsActionsForReplayWhileCircuitOpen(t *testing.T) {
+	uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+	uc.SetKeepAvailability(&keepSwitch{}, 10)
+	repo, _ := setupPendingActions(uc)
+	circuitOpen := fmt.Errorf("%w: circuit breaker open", port.ErrKeepUnavailable)
+	keepClient.GetAlertFunc = keepAlertError(fmt.Errorf("keep get alert: %w", circuitOpen))
+	keepClient.EnrichAlertFunc = func(context.Context, string, map[string]string, port.EnrichOptions) error {
+		return fmt.Errorf("keep enrich alert: %w", circuitOpen)
+	}
+	fp := alert.RestoreFingerprint("fp-12345")
+	postRepo.posts[fp.Value()] = post.NewPost("post-456", "channel-789", fp, "Test Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+
+	input := queuedActionInput(post.ActionAcknowledge)
+	result, err := uc.ExecuteImmediate(input)
+	require.NoError(t, err)
+	assert.Empty(t, result.Ephemeral, "the action is not held in memory")
+	assert.Equal(t, "Processing Alert", result.Attachment.Title)
+	uc.ExecuteAsync(input)
+	uc.Wait()
+
+	require.Contains(t, repo.actions, "fp-12345", "the action is kept for replay")
+	assert.Equal(t, post.ActionAcknowledge, repo.actions["fp-12345"].Action())
+	require.NotEmpty(t, mmClient.UpdatePostCalls(), "the post shows the action at once")
+	assert.Equal(t, "ACKNOWLEDGED: Test Alert", lastCall(mmClient.UpdatePostCalls()).Attachment.Title)
+
+	result, err = uc.ExecuteImmediate(queuedActionInput(post.ActionSnooze))
+	require.NoError(t, err)
+	assert.Contains(t, result.Ephemeral, "action queued", "actions that cannot be replayed still wait in memory")
+}
+
+func TestHandleCallbackUseCase_RejectsOtherActionsWhileKeepUnavailable(t *testing.T) {
+	uc, _, _, _, _ := setupHandleCallbackUseCase()
+	uc.SetKeepAvailability(&keepSwitch{}, 10)
+	uc.SetWorkflowMenu(&WorkflowMenu{})
+
+	input := queuedActionInput(post.ActionRunWorkflow)
+	input.Context[post.ContextKeySelectedOption] = "wf-1"
+	result, err := uc.ExecuteImmediate(input)
+	require.NoError(t, err)
+	assert.Equal(t, "Keep is unavailable, try again later.", result.Ephemeral)
+	assert.False(t, result.RunAsync)
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// SetQuietHours applies policy to new firing alerts: during quiet hours they
+// can be posted without mentions, routed to another channel or held in store
+// until quiet hours end. A nil policy, the default, posts every alert as
+// usual.
+func (uc *HandleAlertUseCase) SetQuietHours(policy port.QuietHours, store quiethours.Repository) {
+	uc.quiet = policy
+	uc.quietStore = store
+}
+
+// quietDecision returns what quiet hours do with a new firing alert routed
+// to channelID.
+func (uc *HandleAlertUseCase) quietDecision(a *alert.Alert, channelID string) port.QuietDecision {
+	if uc.quiet == nil {
+		return port.QuietDecision{Action: port.QuietNotify}
+	}
+	decision, ok := uc.quiet.Decide(a.Severity().String(), channelID, uc.clock.Now())
+	if !ok {
+		return port.QuietDecision{Action: port.QuietNotify}
+	}
+	return decision
+}
+
+// mentionMessage returns the mentions for a new post of a in channelID, or ""
+// when quiet hours mute the channel.
+func (uc *HandleAlertUseCase) mentionMessage(a *alert.Alert, channelID string) string {
+	if uc.quietDecision(a, channelID).Action == port.QuietMute {
+		return ""
+	}
+	return uc.msgBuilder.BuildMentionMessage(a, channelID)
+}
+
+// holdForQuietHours stores a new firing alert until quiet hours end. A re-fire
+// of an alert already held replaces it.
+func (uc *HandleAlertUseCase) holdForQuietHours(ctx context.Context, a *alert.Alert, channelID string) error {
+	fingerprint := a.Fingerprint()
+	held, err := uc.quietStore.FindByFingerprint(ctx, fingerprint)
+	switch {
+	case errors.Is(err, quiethours.ErrNotFound):
+		held = quiethours.NewHeld(channelID, a, uc.clock.Now())
+	case err != nil:
+		return fmt.Errorf("find held alert: %w", err)
+	default:
+		held.Refire(a)
+	}
+	if err := uc.quietStore.Save(ctx, held); err != nil {
+		return fmt.Errorf("hold alert for quiet hours: %w", err)
+	}
+
+	uc.logger.Info("Alert held for quiet hours",
+		logger.ApplicationFields("alert_quiet_held",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("severity", a.Severity().String()),
+			slog.String("channel_id", channelID),
+		),
+	)
+	quietHoursAlertsCounter(port.QuietHold).Inc()
+	uc.audit.Record(ctx, fingerprint, audit.KindQuietHours, "", "held")
+	return nil
+}
+
+// resolveQuietHeld records that an alert held for quiet hours resolved, so
+// the digest lists it as resolved and it is not posted. It reports whether
+// the alert was held.
+func (uc *HandleAlertUseCase) resolveQuietHeld(ctx context.Context, fingerprint alert.Fingerprint) (bool, error) {
+	if uc.quietStore == nil {
+		return false, nil
+	}
+	held, err := uc.quietStore.FindByFingerprint(ctx, fingerprint)
+	if errors.Is(err, quiethours.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find held alert: %w", err)
+	}
+	if !held.IsResolved() {
+		held.MarkResolved(uc.clock.Now())
+		if err := uc.quietStore.Save(ctx, held); err != nil {
+			return false, fmt.Errorf("save held alert: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// ReleaseQuietHours posts the digest of alerts held during quiet hours once
+// they end, then posts those still firing the way ReleaseHeldAlerts does.
+// Digests go to the digest channel, or to the channel each alert was routed
+// to. Alerts that fail to post stay held and are retried on the next run.
+func (uc *HandleAlertUseCase) ReleaseQuietHours(ctx context.Context) error {
+	if uc.quiet == nil || uc.quietStore == nil || uc.quiet.Active(uc.clock.Now()) {
+		return nil
+	}
+
+	held, err := uc.quietStore.FindAll(ctx)
+	if err != nil {
+		quietHoursErrorsCounter.Inc()
+		return fmt.Errorf("list held alerts: %w", err)
+	}
+	if len(held) == 0 {
+		return nil
+	}
+
+	var errs []error
+	var order []string
+	digests := make(map[string][]*quiethours.Held)
+	for _, h := range held {
+		if h.IsDigested() {
+			continue
+		}
+		channelID := uc.quiet.DigestChannelID()
+		if channelID == "" {
+			channelID = h.ChannelID()
+		}
+		if _, ok := digests[channelID]; !ok {
+			order = append(order, channelID)
+		}
+		digests[channelID] = append(digests[channelID], h)
+	}
+	for _, channelID := range order {
+		if err := uc.postQuietDigest(ctx, channelID, digests[channelID]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, h := range held {
+		if !h.IsDigested() {
+			continue
+		}
+		if !h.IsResolved() {
+			if _, err := uc.releaseHeldAlert(ctx, h.ChannelID(), h.Alert()); err != nil {
+				errs = append(errs, fmt.Errorf("release %s: %w", h.Fingerprint().Value(), err))
+				continue
+			}
+			quietHoursAlertsCounter("released").Inc()
+		}
+		if err := uc.quietStore.Delete(ctx, h.Fingerprint()); err != nil {
+			errs = append(errs, fmt.Errorf("delete held alert %s: %w", h.Fingerprint().Value(), err))
+		}
+	}
+	if len(errs) > 0 {
+		quietHoursErrorsCounter.Inc()
+	}
+	return errors.Join(errs...)
+}
+
+// postQuietDigest posts the digest of held into channelID and marks them
+// digested.
+func (uc *HandleAlertUseCase) postQuietDigest(ctx context.Context, channelID string, held []*quiethours.Held) error {
+	alerts := make([]port.DigestAlert, 0, len(held))
+	for _, h := range held {
+		a := h.Alert()
+		alerts = append(alerts, port.DigestAlert{
+			Fingerprint: a.Fingerprint().Value(),
+			Name:        a.Name(),
+			Severity:    a.Severity().String(),
+			HeldAt:      h.HeldAt(),
+			ResolvedAt:  h.ResolvedAt(),
+		})
+	}
+	attachment := uc.msgBuilder.BuildQuietHoursDigestAttachment(alerts, uc.keepUIURL)
+	if _, err := uc.mmClient.CreatePost(ctx, channelID, attachment); err != nil {
+		return fmt.Errorf("post quiet hours digest to %s: %w", channelID, err)
+	}
+
+	for _, h := range held {
+		h.MarkDigested()
+		if err := uc.quietStore.Save(ctx, h); err != nil {
+			return fmt.Errorf("save held alert %s: %w", h.Fingerprint().Value(), err)
+		}
+	}
+
+	uc.logger.Info("Quiet hours digest posted",
+		logger.ApplicationFields("quiet_hours_digest",
+			slog.String("channel_id", channelID),
+			slog.Int("alerts", len(held)),
+		),
+	)
+	quietHoursDigestsCounter.Inc()
+	return nil
+}
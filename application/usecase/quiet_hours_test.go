@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type fakeQuietHoursStore struct {
+	held map[string]*quiethours.Held
+}
+
+func (s *fakeQuietHoursStore) Save(ctx context.Context, h *quiethours.Held) error {
+	s.held[h.Fingerprint().Value()] = h
+	return nil
+}
+
+func (s *fakeQuietHoursStore) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*quiethours.Held, error) {
+	h, ok := s.held[fingerprint.Value()]
+	if !ok {
+		return nil, quiethours.ErrNotFound
+	}
+	return h, nil
+}
+
+func (s *fakeQuietHoursStore) FindAll(ctx context.Context) ([]*quiethours.Held, error) {
+	all := make([]*quiethours.Held, 0, len(s.held))
+	for _, h := range s.held {
+		all = append(all, h)
+	}
+	slices.SortFunc(all, func(a, b *quiethours.Held) int { return a.HeldAt().Compare(b.HeldAt()) })
+	return all, nil
+}
+
+func (s *fakeQuietHoursStore) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	delete(s.held, fingerprint.Value())
+	return nil
+}
+
+func TestHandleAlertUseCase_QuietHours(t *testing.T) {
+	uc, postRepo, _, keepClient, msgBuilder, _ := setupHandleAlertUseCase()
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "post-" + attachment.Title, nil
+		},
+	}
+	uc.mmClient = mmClient
+	msgBuilder.BuildMentionMessageFunc = func(_ *alert.Alert, channelID string) string {
+		return map[string]string{"channel-456": "@oncall"}[channelID]
+	}
+	fakeClock := clock.NewFake(time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC))
+	uc.SetClock(fakeClock)
+
+	active := true
+	policy := &portmock.QuietHoursMock{
+		ActiveFunc: func(now time.Time) bool { return active },
+		DecideFunc: func(severity, channelID string, now time.Time) (port.QuietDecision, bool) {
+			if !active {
+				return port.QuietDecision{}, false
+			}
+			switch severity {
+			case "high":
+				return port.QuietDecision{Action: port.QuietRoute, ChannelID: "night-channel"}, true
+			case "warning":
+				return port.QuietDecision{Action: port.QuietHold}, true
+			case "low":
+				return port.QuietDecision{Action: port.QuietMute}, true
+			}
+			return port.QuietDecision{}, false
+		},
+		DigestChannelIDFunc: func() string { return "" },
+	}
+	store := &fakeQuietHoursStore{held: make(map[string]*quiethours.Held)}
+	uc.SetQuietHours(policy, store)
+	ctx := context.Background()
+
+	fire := func(fp, severity, status string) {
+		t.Helper()
+		require.NoError(t, uc.Execute(ctx, dto.KeepAlertInput{Fingerprint: fp, Name: "Alert " + fp, Severity: severity, Status: status}))
+	}
+
+	fire("fp-critical", "critical", "firing")
+	fire("fp-high", "high", "firing")
+	fire("fp-low", "low", "firing")
+	fire("fp-warn-1", "warning", "firing")
+	fakeClock.Advance(time.Minute)
+	fire("fp-warn-2", "warning", "firing")
+	fire("fp-warn-2", "warning", "resolved")
+
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "channel-456", calls[0].ChannelID)
+	assert.Equal(t, "@oncall", calls[0].Attachment.Message, "alerts without a matching rule are posted as usual")
+	assert.Equal(t, "night-channel", postRepo.posts["fp-high"].ChannelID())
+	assert.Equal(t, "channel-456", calls[2].ChannelID)
+	assert.Empty(t, calls[2].Attachment.Message, "muted alerts are posted without mentions")
+	assert.NotContains(t, postRepo.posts, "fp-warn-1")
+	require.Len(t, store.held, 2)
+	assert.True(t, store.held["fp-warn-2"].IsResolved())
+
+	require.NoError(t, uc.ReleaseQuietHours(ctx))
+	assert.Len(t, mmClient.CreatePostCalls(), 3, "nothing is released during quiet hours")
+
+	active = false
+	keepClient.GetAlertFunc = keepAlertError(fmt.Errorf("keep get alert: %w", port.ErrKeepAlertNotFound))
+	fakeClock.Advance(8 * time.Hour)
+	require.NoError(t, uc.ReleaseQuietHours(ctx))
+
+	calls = mmClient.CreatePostCalls()
+	require.Len(t, calls, 5)
+	assert.Equal(t, "DIGEST: 2", calls[3].Attachment.Title)
+	assert.Equal(t, "channel-456", calls[3].ChannelID)
+	assert.Equal(t, "FIRING: Alert fp-warn-1", calls[4].Attachment.Title)
+	assert.Equal(t, "@oncall", calls[4].Attachment.Message, "released alerts mention as usual")
+	assert.Contains(t, postRepo.posts, "fp-warn-1")
+	assert.NotContains(t, postRepo.posts, "fp-warn-2", "alerts resolved while held are not posted")
+	assert.Empty(t, store.held)
+}
+
+func TestHandleAlertUseCase_QuietHoursDigestRetry(t *testing.T) {
+	uc, postRepo, _, keepClient, _, _ := setupHandleAlertUseCase()
+	digestErr := errors.New("mattermost down")
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			if digestErr != nil {
+				return "", digestErr
+			}
+			return "post-" + attachment.Title, nil
+		},
+	}
+	uc.mmClient = mmClient
+	fakeClock := clock.NewFake(time.Date(2026, 1, 2, 7, 0, 0, 0, time.UTC))
+	uc.SetClock(fakeClock)
+	policy := &portmock.QuietHoursMock{
+		ActiveFunc:          func(now time.Time) bool { return false },
+		DigestChannelIDFunc: func() string { return "digest-channel" },
+	}
+	store := &fakeQuietHoursStore{held: make(map[string]*quiethours.Held)}
+	uc.SetQuietHours(policy, store)
+	ctx := context.Background()
+
+	a, err := alert.NewAlert(alert.RestoreFingerprint("fp-held"), "Held", alert.RestoreSeverity("warning"), alert.RestoreStatus(alert.StatusFiring), "", "", nil, time.Time{})
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, quiethours.NewHeld("channel-456", a, fakeClock.Now().Add(-time.Hour))))
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{Fingerprint: "fp-held", Name: "Held", Status: "resolved", Severity: "warning"})
+
+	require.Error(t, uc.ReleaseQuietHours(ctx))
+	require.Contains(t, store.held, "fp-held", "alerts stay held until the digest is posted")
+	assert.False(t, store.held["fp-held"].IsDigested())
+
+	digestErr = nil
+	require.NoError(t, uc.ReleaseQuietHours(ctx))
+	calls := mmClient.CreatePostCalls()
+	require.Len(t, calls, 2, "the failed digest and its retry")
+	assert.Equal(t, "digest-channel", calls[1].ChannelID)
+	assert.NotContains(t, postRepo.posts, "fp-held", "alerts Keep reports resolved are not posted")
+	assert.Empty(t, store.held)
+}
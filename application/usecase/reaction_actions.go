@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	// reactionActionTimeout bounds the Keep and Mattermost calls of one
+	// reaction.
+	reactionActionTimeout = 30 * time.Second
+	// reactionReconnectDelay is how long Run waits before reconnecting after
+	// the event stream failed.
+	reactionReconnectDelay = 5 * time.Second
+)
+
+// ReactionActionsUseCase applies alert actions for emoji reactions on alert
+// posts, e.g. 👀 acknowledges and ✅ resolves, going through the same path as
+// the post buttons on behalf of the reacting user.
+type ReactionActionsUseCase struct {
+	postRepo post.Repository
+	events   port.MattermostReactionEvents
+	actions  port.AlertActionUseCase
+	emojis   map[string]string
+	clock    clock.Clock
+	logger   *slog.Logger
+}
+
+// NewReactionActionsUseCase creates the use case. emojis maps emoji names,
+// with or without colons, to the action a reaction with them applies.
+func NewReactionActionsUseCase(
+	postRepo post.Repository,
+	events port.MattermostReactionEvents,
+	actions port.AlertActionUseCase,
+	emojis map[string]string,
+	logger *slog.Logger,
+) *ReactionActionsUseCase {
+	byName := make(map[string]string, len(emojis))
+	for emoji, action := range emojis {
+		byName[strings.Trim(emoji, ":")] = action
+	}
+	return &ReactionActionsUseCase{
+		postRepo: postRepo,
+		events:   events,
+		actions:  actions,
+		emojis:   byName,
+		clock:    clock.Real(),
+		logger:   logger,
+	}
+}
+
+// SetClock replaces the clock used to wait between reconnects.
+func (uc *ReactionActionsUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Run follows reactions until ctx is cancelled, reconnecting whenever the
+// event stream fails.
+func (uc *ReactionActionsUseCase) Run(ctx context.Context) {
+	uc.logger.Info("Reaction listener started")
+	for ctx.Err() == nil {
+		err := uc.events.Listen(ctx, uc.Handle)
+		if ctx.Err() != nil {
+			break
+		}
+		reactionListenerReconnectsCounter.Inc()
+		uc.logger.Error("Reaction listener disconnected",
+			logger.ApplicationFields("reaction_listener_disconnected",
+				slog.String("error", fmt.Sprint(err)),
+			),
+		)
+		select {
+		case <-uc.clock.After(reactionReconnectDelay):
+		case <-ctx.Done():
+		}
+	}
+	uc.logger.Info("Reaction listener stopped")
+}
+
+// Handle applies the action of event's emoji to the alert of the post it was
+// added to. Reactions with other emoji and on other posts are ignored.
+func (uc *ReactionActionsUseCase) Handle(ctx context.Context, event port.ReactionEvent) {
+	action, ok := uc.emojis[event.EmojiName]
+	if !ok {
+		return
+	}
+
+	// A reaction already received is applied even when shutdown starts.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reactionActionTimeout)
+	defer cancel()
+
+	p, err := findPostByID(ctx, uc.postRepo, event.PostID)
+	if err != nil {
+		reactionActionsCounter(action, "error").Inc()
+		uc.logger.Error("Failed to look up reacted post",
+			logger.ApplicationFields("reaction_action_failed",
+				slog.String("post_id", event.PostID),
+				slog.String("error", err.Error()),
+			),
+		)
+		return
+	}
+	if p == nil {
+		return
+	}
+
+	fingerprint := p.Fingerprint().Value()
+	err = uc.actions.ExecuteAction(ctx, action, fingerprint, event.UserID)
+	switch {
+	case err == nil:
+		reactionActionsCounter(action, "applied").Inc()
+		uc.logger.Info("Reaction applied",
+			logger.ApplicationFields("reaction_action_applied",
+				slog.String("fingerprint", fingerprint),
+				slog.String("action", action),
+				slog.String("emoji", event.EmojiName),
+				slog.String("user_id", event.UserID),
+			),
+		)
+	case errors.Is(err, ErrNotPermitted):
+		reactionActionsCounter(action, "denied").Inc()
+		uc.logger.Warn("Reaction denied",
+			logger.ApplicationFields("reaction_action_denied",
+				slog.String("fingerprint", fingerprint),
+				slog.String("action", action),
+				slog.String("user_id", event.UserID),
+			),
+		)
+	default:
+		reactionActionsCounter(action, "error").Inc()
+		uc.logger.Error("Failed to apply reaction",
+			logger.ApplicationFields("reaction_action_failed",
+				slog.String("fingerprint", fingerprint),
+				slog.String("action", action),
+				slog.String("error", err.Error()),
+			),
+		)
+	}
+}
+
+// findPostByID returns the tracked alert post with postID, or nil when the
+// post is not one. Repositories that cannot look posts up by ID are scanned.
+func findPostByID(ctx context.Context, postRepo post.Repository, postID string) (*post.Post, error) {
+	if finder, ok := postRepo.(post.PostIDFinder); ok {
+		p, err := finder.FindByPostID(ctx, postID)
+		if errors.Is(err, post.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find post by id: %w", err)
+		}
+		return p, nil
+	}
+	posts, err := postRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find all active posts: %w", err)
+	}
+	for _, p := range posts {
+		if p.PostID() == postID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func setupReactionActionsUseCase(actionErr error) (*ReactionActionsUseCase, *portmock.AlertActionUseCaseMock, *portmock.MattermostReactionEventsMock) {
+	postRepo := newPostStore()
+	fingerprint := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fingerprint, "Disk full", alert.RestoreSeverity(alert.SeverityCritical), time.Now(), time.Now())
+
+	actions := &portmock.AlertActionUseCaseMock{
+		ExecuteActionFunc: func(ctx context.Context, action, fingerprint, userID string) error {
+			return actionErr
+		},
+	}
+	events := &portmock.MattermostReactionEventsMock{}
+	uc := NewReactionActionsUseCase(
+		postRepo,
+		events,
+		actions,
+		map[string]string{"eyes": post.ActionAcknowledge, ":white_check_mark:": post.ActionResolve},
+		slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	)
+	return uc, actions, events
+}
+
+func TestReactionActionsUseCase_Handle(t *testing.T) {
+	t.Run("mapped emoji applies its action", func(t *testing.T) {
+		uc, actions, _ := setupReactionActionsUseCase(nil)
+
+		uc.Handle(context.Background(), port.ReactionEvent{PostID: "post-1", UserID: "user-1", EmojiName: "white_check_mark"})
+
+		calls := actions.ExecuteActionCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, post.ActionResolve, calls[0].Action)
+		assert.Equal(t, "fp-1", calls[0].Fingerprint)
+		assert.Equal(t, "user-1", calls[0].UserID)
+	})
+
+	t.Run("other emoji are ignored", func(t *testing.T) {
+		uc, actions, _ := setupReactionActionsUseCase(nil)
+
+		uc.Handle(context.Background(), port.ReactionEvent{PostID: "post-1", UserID: "user-1", EmojiName: "thumbsup"})
+
+		assert.Empty(t, actions.ExecuteActionCalls())
+	})
+
+	t.Run("posts that are not alert posts are ignored", func(t *testing.T) {
+		uc, actions, _ := setupReactionActionsUseCase(nil)
+
+		uc.Handle(context.Background(), port.ReactionEvent{PostID: "post-other", UserID: "user-1", EmojiName: "eyes"})
+
+		assert.Empty(t, actions.ExecuteActionCalls())
+	})
+
+	t.Run("denied reactions change nothing", func(t *testing.T) {
+		uc, actions, _ := setupReactionActionsUseCase(ErrNotPermitted)
+
+		uc.Handle(context.Background(), port.ReactionEvent{PostID: "post-1", UserID: "user-1", EmojiName: "eyes"})
+
+		require.Len(t, actions.ExecuteActionCalls(), 1)
+	})
+}
+
+func TestReactionActionsUseCase_RunReconnects(t *testing.T) {
+	uc, actions, events := setupReactionActionsUseCase(nil)
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events.ListenFunc = func(ctx context.Context, handle func(ctx context.Context, event port.ReactionEvent)) error {
+		if len(events.ListenCalls()) == 1 {
+			return errors.New("connection reset")
+		}
+		handle(ctx, port.ReactionEvent{PostID: "post-1", UserID: "user-1", EmojiName: "eyes"})
+		cancel()
+		return ctx.Err()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		uc.Run(ctx)
+		close(done)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(reactionReconnectDelay)
+	<-done
+
+	assert.Len(t, events.ListenCalls(), 2)
+	require.Len(t, actions.ExecuteActionCalls(), 1)
+	assert.Equal(t, post.ActionAcknowledge, actions.ExecuteActionCalls()[0].Action)
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// Drift kinds reported by ReconcileUseCase.
+const (
+	DriftAlertGone      = "alert_gone"
+	DriftMissingPost    = "missing_post"
+	DriftAcknowledged   = "acknowledged"
+	DriftUnacknowledged = "unacknowledged"
+)
+
+// ReconcileUseCase heals webhooks missed while the bridge was down. It
+// compares the tracked posts with the alerts Keep reports and replays every
+// difference through the alert use case as if its webhook had arrived:
+//   - posts whose alert is gone from Keep, or closed there, are resolved
+//   - firing alerts without a post get one
+//   - posts whose acknowledged state differs from Keep are re-rendered
+type ReconcileUseCase struct {
+	postRepo    post.Repository
+	keepClient  port.KeepClient
+	alerts      port.AlertUseCase
+	alertsLimit int
+	logger      *slog.Logger
+}
+
+func NewReconcileUseCase(
+	postRepo post.Repository,
+	keepClient port.KeepClient,
+	alerts port.AlertUseCase,
+	alertsLimit int,
+	logger *slog.Logger,
+) *ReconcileUseCase {
+	return &ReconcileUseCase{
+		postRepo:    postRepo,
+		keepClient:  keepClient,
+		alerts:      alerts,
+		alertsLimit: alertsLimit,
+		logger:      logger,
+	}
+}
+
+// Execute runs one reconciliation pass. Drift that fails to replay is
+// reported in the returned error; the rest is still applied.
+func (uc *ReconcileUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	keepAlerts, err := uc.keepClient.GetAlerts(ctx, uc.alertsLimit, nil)
+	if err != nil {
+		return fmt.Errorf("get alerts from Keep: %w", err)
+	}
+
+	// A full page may have cut off alerts that are still open, so absence
+	// from it proves nothing.
+	complete := len(keepAlerts) < uc.alertsLimit
+	if !complete {
+		uc.logger.Warn("Keep returned a full page of alerts, not resolving posts missing from it",
+			slog.Int("alerts_limit", uc.alertsLimit),
+		)
+	}
+
+	alertMap := make(map[string]port.KeepAlert, len(keepAlerts))
+	for _, ka := range keepAlerts {
+		alertMap[ka.Fingerprint] = ka
+	}
+
+	var errs []error
+	replay := func(kind string, input dto.KeepAlertInput) {
+		uc.logger.Info("Drift detected",
+			logger.ApplicationFields("reconcile_drift",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.String("kind", kind),
+				slog.String("status", input.Status),
+			),
+		)
+		reconcileDriftCounter(kind).Inc()
+		if err := uc.alerts.Execute(ctx, input); err != nil {
+			reconcileErrorsCounter.Inc()
+			errs = append(errs, fmt.Errorf("reconcile %s %s: %w", kind, input.Fingerprint, err))
+		}
+	}
+
+	tracked := make(map[string]bool, len(posts))
+	for _, p := range posts {
+		fingerprint := p.Fingerprint().Value()
+		tracked[fingerprint] = true
+
+		ka, exists := alertMap[fingerprint]
+		if !exists {
+			if complete {
+				replay(DriftAlertGone, dto.KeepAlertInput{
+					Fingerprint: fingerprint,
+					Name:        p.AlertName(),
+					Severity:    p.Severity().Value(),
+					Status:      alert.StatusResolved,
+				})
+			}
+			continue
+		}
+
+		status := keepStatus(ka)
+		switch {
+		case status.IsClosed():
+			input := keepAlertInput(ka)
+			input.Status = status.Value()
+			replay(DriftAlertGone, input)
+		case isKeepAcknowledged(ka) && p.LastKnownAssignee() == "":
+			input := keepAlertInput(ka)
+			input.Status = alert.StatusAcknowledged
+			replay(DriftAcknowledged, input)
+		case status.IsFiring() && !isKeepAcknowledged(ka) && p.LastKnownAssignee() != "":
+			replay(DriftUnacknowledged, keepAlertInput(ka))
+		}
+	}
+
+	for _, ka := range keepAlerts {
+		if tracked[ka.Fingerprint] || !keepStatus(ka).IsFiring() || isKeepAcknowledged(ka) {
+			continue
+		}
+		replay(DriftMissingPost, keepAlertInput(ka))
+	}
+
+	uc.logger.Info("Reconciliation completed",
+		logger.ApplicationFields("reconcile_completed",
+			slog.Int("tracked_posts", len(posts)),
+			slog.Int("keep_alerts", len(keepAlerts)),
+			slog.Int("errors", len(errs)),
+		),
+	)
+
+	return errors.Join(errs...)
+}
+
+// keepStatus returns the status Keep shows for ka. Status changes made in Keep
+// UI or by the bridge are stored as a status enrichment that overrides the
+// status reported by the alert source.
+func keepStatus(ka port.KeepAlert) alert.Status {
+	if s := ka.Enrichments["status"]; s != "" {
+		return alert.RestoreStatus(strings.ToLower(s))
+	}
+	return alert.RestoreStatus(ka.Status)
+}
+
+// isKeepAcknowledged checks both enrichment status and alert status; Keep
+// may report acknowledged in either field depending on the source.
+func isKeepAcknowledged(ka port.KeepAlert) bool {
+	return ka.Status == alert.StatusAcknowledged || ka.Enrichments["status"] == alert.StatusAcknowledged
+}
+
+func keepAlertInput(ka port.KeepAlert) dto.KeepAlertInput {
+	input := dto.KeepAlertInput{
+		Fingerprint: ka.Fingerprint,
+		Name:        ka.Name,
+		Status:      ka.Status,
+		Severity:    ka.Severity,
+		Source:      ka.Source,
+		Description: ka.Description,
+		Labels:      ka.Labels,
+	}
+	if !ka.FiringStartTime.IsZero() {
+		input.FiringStartTime = ka.FiringStartTime.Format(time.RFC3339)
+	}
+	return input
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func newReconcileTestPost(fingerprint, assignee string) *post.Post {
+	now := time.Now()
+	return post.RestorePost("post-"+fingerprint, "channel-1", alert.RestoreFingerprint(fingerprint), "Alert "+fingerprint,
+		alert.RestoreSeverity(alert.SeverityHigh), now, now, now, assignee)
+}
+
+func setupReconcile(posts []*post.Post, keepAlerts []port.KeepAlert, limit int) (*ReconcileUseCase, *portmock.AlertUseCaseMock) {
+	postRepo := &portmock.RepositoryMock{
+		FindAllActiveFunc: func(ctx context.Context) ([]*post.Post, error) {
+			return posts, nil
+		},
+	}
+	keepClient := &portmock.KeepClientMock{
+		GetAlertsFunc: func(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error) {
+			return keepAlerts, nil
+		},
+	}
+	alerts := &portmock.AlertUseCaseMock{
+		ExecuteFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			return nil
+		},
+	}
+	uc := NewReconcileUseCase(postRepo, keepClient, alerts, limit, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	return uc, alerts
+}
+
+func replayedStatuses(alerts *portmock.AlertUseCaseMock) map[string]string {
+	got := make(map[string]string)
+	for _, call := range alerts.ExecuteCalls() {
+		got[call.Input.Fingerprint] = call.Input.Status
+	}
+	return got
+}
+
+func TestReconcileUseCase_ReplaysDrift(t *testing.T) {
+	firingStart := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	posts := []*post.Post{
+		newReconcileTestPost("gone", ""),
+		newReconcileTestPost("closed-in-keep", ""),
+		newReconcileTestPost("resolved-by-enrichment", ""),
+		newReconcileTestPost("acked-in-keep", ""),
+		newReconcileTestPost("unacked-in-keep", "alice"),
+		newReconcileTestPost("in-sync", "bob"),
+	}
+	keepAlerts := []port.KeepAlert{
+		{Fingerprint: "closed-in-keep", Name: "Closed", Status: "dismissed", Severity: "high"},
+		{Fingerprint: "resolved-by-enrichment", Name: "Manual", Status: "firing", Severity: "high", Enrichments: map[string]string{"status": "resolved"}},
+		{Fingerprint: "acked-in-keep", Name: "Acked", Status: "firing", Severity: "high", Enrichments: map[string]string{"status": "acknowledged"}},
+		{Fingerprint: "unacked-in-keep", Name: "Unacked", Status: "firing", Severity: "high"},
+		{Fingerprint: "in-sync", Name: "In sync", Status: "acknowledged", Severity: "high"},
+		{Fingerprint: "new", Name: "New", Status: "firing", Severity: "critical", FiringStartTime: firingStart},
+		{Fingerprint: "new-but-acked", Name: "New acked", Status: "acknowledged", Severity: "critical"},
+		{Fingerprint: "old", Name: "Old", Status: "resolved", Severity: "critical"},
+	}
+	uc, alerts := setupReconcile(posts, keepAlerts, 1000)
+
+	require.NoError(t, uc.Execute(context.Background()))
+
+	assert.Equal(t, map[string]string{
+		"gone":                   "resolved",
+		"closed-in-keep":         "dismissed",
+		"resolved-by-enrichment": "resolved",
+		"acked-in-keep":          "acknowledged",
+		"unacked-in-keep":        "firing",
+		"new":                    "firing",
+	}, replayedStatuses(alerts))
+
+	for _, call := range alerts.ExecuteCalls() {
+		switch call.Input.Fingerprint {
+		case "gone":
+			assert.Equal(t, "Alert gone", call.Input.Name, "gone alerts are resolved with the stored name")
+			assert.Equal(t, "high", call.Input.Severity)
+		case "new":
+			assert.Equal(t, "New", call.Input.Name)
+			assert.Equal(t, "2024-03-01T12:00:00Z", call.Input.FiringStartTime)
+		}
+	}
+}
+
+func TestReconcileUseCase_FullPageKeepsMissingPosts(t *testing.T) {
+	posts := []*post.Post{newReconcileTestPost("beyond-page", "")}
+	keepAlerts := []port.KeepAlert{
+		{Fingerprint: "a", Name: "A", Status: "resolved", Severity: "high"},
+		{Fingerprint: "b", Name: "B", Status: "resolved", Severity: "high"},
+	}
+	uc, alerts := setupReconcile(posts, keepAlerts, 2)
+
+	require.NoError(t, uc.Execute(context.Background()))
+	assert.Empty(t, alerts.ExecuteCalls(), "absence from a full page does not prove the alert is gone")
+}
+
+func TestReconcileUseCase_ContinuesAfterReplayError(t *testing.T) {
+	keepAlerts := []port.KeepAlert{
+		{Fingerprint: "bad", Name: "Bad", Status: "firing", Severity: "bogus"},
+		{Fingerprint: "good", Name: "Good", Status: "firing", Severity: "high"},
+	}
+	uc, alerts := setupReconcile(nil, keepAlerts, 1000)
+	alerts.ExecuteFunc = func(ctx context.Context, input dto.KeepAlertInput) error {
+		if input.Fingerprint == "bad" {
+			return errors.New("parse severity: invalid severity")
+		}
+		return nil
+	}
+
+	err := uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconcile missing_post bad")
+
+	var replayed []string
+	for fp := range replayedStatuses(alerts) {
+		replayed = append(replayed, fp)
+	}
+	sort.Strings(replayed)
+	assert.Equal(t, []string{"bad", "good"}, replayed)
+}
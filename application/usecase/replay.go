@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Preview reports what Execute would do with input without doing it: the
+// drop rule, route, maintenance window and quiet hours that apply, whether
+// the alert's post is created, updated or closed, and the attachment shown.
+// Keep is not asked for enrichments or the assignee, and flapping and the
+// notification budget are not taken into account, so the rendered
+// attachment may differ in those details.
+func (uc *HandleAlertUseCase) Preview(ctx context.Context, input dto.KeepAlertInput) (dto.ReplayOutput, error) {
+	a, err := uc.parseAlert(input)
+	if err != nil {
+		return dto.ReplayOutput{}, err
+	}
+	fingerprint, status := a.Fingerprint(), a.Status()
+	out := dto.ReplayOutput{
+		Fingerprint: fingerprint.Value(),
+		Status:      status.String(),
+		Severity:    a.Severity().String(),
+	}
+
+	if uc.dropRules != nil {
+		if rule, ok := uc.dropRules.RuleForAlert(a.Name(), a.Severity().String(), a.Labels()); ok {
+			out.Action, out.DropRule, out.Reason = dto.ReplayDrop, rule, "matches drop rule "+rule
+			return out, nil
+		}
+	}
+
+	existingPost, err := uc.postRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil && !errors.Is(err, post.ErrNotFound) {
+		return out, fmt.Errorf("find existing post: %w", err)
+	}
+
+	if existingPost == nil {
+		out.ChannelID, out.CopyChannelIDs = uc.routeAlert(a)
+		if out.ChannelID == "" {
+			out.Action, out.Reason = dto.ReplayDrop, "routing drops the alert"
+			return out, nil
+		}
+		uc.previewNew(&out, a)
+		return out, nil
+	}
+
+	out.ChannelID, out.PostID = existingPost.ChannelID(), existingPost.PostID()
+	stored := alert.RestoreAlert(
+		fingerprint, a.Name(), a.Severity(), status,
+		a.Description(), a.Source(), a.Labels(),
+		existingPost.FiringStartTime(),
+	).WithAnnotations(a.Annotations()).WithGeneratorURL(a.GeneratorURL())
+	uc.previewExisting(&out, stored, existingPost)
+	return out, nil
+}
+
+// previewNew fills out for an alert without a post, routed to out.ChannelID.
+func (uc *HandleAlertUseCase) previewNew(out *dto.ReplayOutput, a *alert.Alert) {
+	status := a.Status()
+	out.Action = dto.ReplayCreate
+	switch {
+	case status.IsFiring():
+		if uc.previewMaintenance(out, a, out.ChannelID) {
+			return
+		}
+		decision := uc.quietDecision(a, out.ChannelID)
+		if decision.Action != port.QuietNotify {
+			out.QuietHours = decision.Action
+		}
+		switch decision.Action {
+		case port.QuietHold:
+			out.Action, out.Reason = dto.ReplayHold, "held until quiet hours end"
+			return
+		case port.QuietRoute:
+			out.ChannelID, out.CopyChannelIDs = decision.ChannelID, nil
+		}
+		out.Attachment = rendered(uc.msgBuilder.ForChannel(out.ChannelID).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL))
+	case status.IsAcknowledged():
+		out.Attachment = rendered(uc.msgBuilder.ForChannel(out.ChannelID).BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, ""))
+	case status.IsSuppressed():
+		out.CopyChannelIDs = nil
+		out.Attachment = rendered(uc.msgBuilder.ForChannel(out.ChannelID).BuildSuppressedAttachment(a, uc.keepUIURL))
+	case status.IsPending():
+		out.CopyChannelIDs = nil
+		out.Attachment = rendered(uc.msgBuilder.ForChannel(out.ChannelID).BuildPendingAttachment(a, uc.keepUIURL))
+	case status.IsMaintenance():
+		out.CopyChannelIDs = nil
+		out.Attachment = rendered(uc.maintenanceAttachment(out.ChannelID, a, nil))
+	default:
+		// Resolved, dismissed and merged alerts that were never posted are
+		// not posted at all.
+		out.ChannelID, out.CopyChannelIDs = "", nil
+		out.Action, out.Reason = dto.ReplayIgnore, "the alert has no post"
+	}
+}
+
+// previewExisting fills out for an alert shown by existingPost; a carries the
+// post's firing start time.
+func (uc *HandleAlertUseCase) previewExisting(out *dto.ReplayOutput, a *alert.Alert, existingPost *post.Post) {
+	builder := uc.msgBuilder.ForChannel(existingPost.ChannelID())
+	status := a.Status()
+	out.Action = dto.ReplayUpdate
+	switch {
+	case status.IsFiring():
+		if uc.previewMaintenance(out, a, existingPost.ChannelID()) {
+			return
+		}
+		if existingPost.IsSnoozed(uc.clock.Now()) {
+			out.Action, out.Reason = dto.ReplayIgnore, "snoozed until "+existingPost.SnoozedUntil().UTC().Format("2006-01-02 15:04 UTC")
+			return
+		}
+		a = a.WithFireCount(existingPost.FireCount() + 1)
+		if existingPost.ShownStatus() == alert.StatusAcknowledged || existingPost.LastKnownAssignee() != "" {
+			out.Attachment = rendered(builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, existingPost.LastKnownAssignee()))
+			return
+		}
+		out.Attachment = rendered(builder.BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL))
+	case status.IsResolved():
+		out.Action = dto.ReplayResolve
+		out.Attachment = rendered(builder.BuildResolvedAttachment(a, uc.keepUIURL, existingPost.LastKnownAssignee()))
+	case status.IsAcknowledged():
+		out.Attachment = rendered(builder.BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, existingPost.LastKnownAssignee()))
+	case status.IsSuppressed():
+		out.Attachment = rendered(builder.BuildSuppressedAttachment(a, uc.keepUIURL))
+	case status.IsPending():
+		out.Attachment = rendered(builder.BuildPendingAttachment(a, uc.keepUIURL))
+	case status.IsMaintenance():
+		out.Attachment = rendered(uc.maintenanceAttachment(existingPost.ChannelID(), a, nil))
+	case status.IsDismissed():
+		out.Action = dto.ReplayClose
+		out.Attachment = rendered(builder.BuildDismissedAttachment(a, uc.keepUIURL))
+	case status.IsMerged():
+		out.Action = dto.ReplayClose
+		out.Attachment = rendered(builder.BuildMergedAttachment(a, uc.keepUIURL))
+	}
+}
+
+// previewMaintenance fills out for a firing alert in a maintenance window and
+// reports whether one applies.
+func (uc *HandleAlertUseCase) previewMaintenance(out *dto.ReplayOutput, a *alert.Alert, channelID string) bool {
+	if uc.maintenance == nil {
+		return false
+	}
+	window, ok := uc.maintenance.WindowForAlert(a.Severity().String(), a.Labels(), uc.clock.Now())
+	if !ok {
+		return false
+	}
+	out.MaintenanceWindow = window.Name
+	out.CopyChannelIDs = nil
+	if window.Action == port.MaintenanceSuppress {
+		out.Action, out.Reason = dto.ReplayIgnore, "suppressed by maintenance window "+window.Name
+		return true
+	}
+	out.Attachment = rendered(uc.maintenanceAttachment(channelID, a, &window))
+	return true
+}
+
+func rendered(attachment post.Attachment) *post.Attachment {
+	return &attachment
+}
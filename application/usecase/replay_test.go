@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func TestHandleAlertUseCase_Preview(t *testing.T) {
+	uc, postRepo, mmClient, _, _, _ := setupHandleAlertUseCase()
+	uc.SetDropRules(stubDropRules{"Watchdog": "watchdog"})
+	ctx := context.Background()
+	input := dto.KeepAlertInput{Fingerprint: "fp-12345", Name: "Test Alert", Severity: "high", Status: "firing"}
+
+	out, err := uc.Preview(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReplayCreate, out.Action)
+	assert.Equal(t, "channel-456", out.ChannelID)
+	require.NotNil(t, out.Attachment)
+	assert.Equal(t, "FIRING: Test Alert", out.Attachment.Title)
+	assert.Empty(t, mmClient.CreatePostCalls(), "previews post nothing")
+	assert.Empty(t, postRepo.SaveCalls())
+
+	postRepo.posts["fp-12345"] = post.NewPost("existing-post-123", "channel-456", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	out, err = uc.Preview(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReplayUpdate, out.Action)
+	assert.Equal(t, "existing-post-123", out.PostID)
+
+	input.Status = "resolved"
+	out, err = uc.Preview(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReplayResolve, out.Action)
+	assert.Equal(t, "RESOLVED: Test Alert", out.Attachment.Title)
+	assert.Empty(t, mmClient.UpdatePostCalls())
+	assert.Empty(t, postRepo.DeleteCalls())
+
+	out, err = uc.Preview(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Watchdog", Severity: "info", Status: "firing"})
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReplayDrop, out.Action)
+	assert.Equal(t, "watchdog", out.DropRule)
+	assert.Nil(t, out.Attachment)
+
+	_, err = uc.Preview(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "Bad", Severity: "bogus", Status: "firing"})
+	assert.ErrorIs(t, err, alert.ErrInvalidSeverity)
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// Keep enrichments holding what was entered in the resolve dialog. They are
+// also the names of the dialog elements and of the callback context keys the
+// submission is handed to the async phase with.
+const (
+	EnrichmentKeyResolutionNote     = "resolution_note"
+	EnrichmentKeyResolutionCategory = "resolution_category"
+)
+
+const (
+	resolveDialogCallbackID = "resolve"
+	// maxResolutionNoteLength is the Mattermost limit for textarea elements.
+	maxResolutionNoteLength = 3000
+)
+
+// ResolveDialog asks for a resolution note and a root cause category when an
+// alert's Resolve button is clicked. The alert is resolved once the dialog is
+// submitted.
+type ResolveDialog struct {
+	client      port.MattermostDialogClient
+	submitURL   string
+	requireNote bool
+	categories  []string
+}
+
+// NewResolveDialog returns a dialog whose submissions Mattermost posts to
+// submitURL. An empty categories list leaves the category field out.
+func NewResolveDialog(client port.MattermostDialogClient, submitURL string, requireNote bool, categories []string) *ResolveDialog {
+	return &ResolveDialog{
+		client:      client,
+		submitURL:   submitURL,
+		requireNote: requireNote,
+		categories:  slices.Clone(categories),
+	}
+}
+
+// resolveDialogState is carried through the dialog so the submission knows
+// which alert and post it is for.
+type resolveDialogState struct {
+	Fingerprint string `json:"fingerprint"`
+	AlertName   string `json:"alert_name"`
+	Severity    string `json:"severity"`
+	PostID      string `json:"post_id"`
+	ChannelID   string `json:"channel_id"`
+}
+
+func (d *ResolveDialog) open(ctx context.Context, triggerID string, state resolveDialogState) error {
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode dialog state: %w", err)
+	}
+
+	elements := []port.DialogElement{{
+		Name:        EnrichmentKeyResolutionNote,
+		DisplayName: "Resolution note",
+		Type:        port.DialogElementTextarea,
+		Placeholder: "What was done to resolve the alert?",
+		Optional:    !d.requireNote,
+		MaxLength:   maxResolutionNoteLength,
+	}}
+	if len(d.categories) > 0 {
+		options := make([]port.DialogOption, len(d.categories))
+		for i, category := range d.categories {
+			options[i] = port.DialogOption{Text: category, Value: category}
+		}
+		elements = append(elements, port.DialogElement{
+			Name:        EnrichmentKeyResolutionCategory,
+			DisplayName: "Root cause",
+			Type:        port.DialogElementSelect,
+			Optional:    true,
+			Options:     options,
+		})
+	}
+
+	return d.client.OpenDialog(ctx, triggerID, d.submitURL, port.Dialog{
+		CallbackID:       resolveDialogCallbackID,
+		Title:            "Resolve alert",
+		IntroductionText: fmt.Sprintf("Resolving **%s**.", state.AlertName),
+		SubmitLabel:      "Resolve",
+		State:            string(stateJSON),
+		Elements:         elements,
+	})
+}
+
+// openResolveDialog opens the resolve dialog for a Resolve click. It reports
+// false when the dialog could not be opened, so the click resolves the alert
+// right away instead.
+func (uc *HandleCallbackUseCase) openResolveDialog(input dto.MattermostCallbackInput, fingerprint, alertName string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	state := resolveDialogState{
+		Fingerprint: fingerprint,
+		AlertName:   alertName,
+		Severity:    input.Context[post.ContextKeySeverity],
+		PostID:      input.PostID,
+		ChannelID:   input.ChannelID,
+	}
+	if err := uc.dialog.open(ctx, input.TriggerID, state); err != nil {
+		uc.logger.Warn("Failed to open resolve dialog, resolving without it",
+			slog.String("fingerprint", fingerprint),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return true
+}
+
+// ExecuteDialogSubmission resolves the alert the resolve dialog was opened
+// for. Invalid input is returned as element errors so the user can fix it;
+// the alert itself is resolved asynchronously, like a Resolve click.
+func (uc *HandleCallbackUseCase) ExecuteDialogSubmission(input dto.MattermostDialogSubmission) (*dto.DialogSubmissionOutput, error) {
+	if input.Cancelled {
+		return &dto.DialogSubmissionOutput{}, nil
+	}
+	if uc.serverIDs != nil {
+		input.UserID = uc.serverIDs.QualifyID(input.ChannelID, input.UserID)
+	}
+	if uc.dialog == nil || input.CallbackID != resolveDialogCallbackID {
+		return nil, fmt.Errorf("unknown dialog %q", input.CallbackID)
+	}
+
+	var state resolveDialogState
+	if err := json.Unmarshal([]byte(input.State), &state); err != nil {
+		return nil, fmt.Errorf("decode dialog state: %w", err)
+	}
+	if _, err := alert.NewFingerprint(state.Fingerprint); err != nil {
+		return nil, fmt.Errorf("parse fingerprint: %w", err)
+	}
+
+	res := resolutionFromSubmission(input.Submission)
+	if errs := uc.dialog.validate(res); errs != nil {
+		return &dto.DialogSubmissionOutput{Errors: errs}, nil
+	}
+
+	// The dialog may have stayed open while the permission rules changed.
+	if uc.permissions != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		allowed := uc.permissions.Allowed(ctx, input.UserID, post.ActionResolve, state.Severity)
+		cancel()
+		if !allowed {
+			callbacksDeniedCounter(post.ActionResolve).Inc()
+			return &dto.DialogSubmissionOutput{Error: "You are not permitted to resolve this alert."}, nil
+		}
+	}
+
+	uc.logger.Info("Resolve dialog submitted",
+		logger.ApplicationFields("resolve_dialog_submitted",
+			slog.String("fingerprint", state.Fingerprint),
+			slog.String("user_id", input.UserID),
+			slog.String("post_id", state.PostID),
+		),
+	)
+
+	callbackContext := map[string]string{
+		post.ContextKeyAction:      post.ActionResolve,
+		post.ContextKeyFingerprint: state.Fingerprint,
+		post.ContextKeyAlertName:   state.AlertName,
+	}
+	res.addToContext(callbackContext)
+	uc.ExecuteAsync(dto.MattermostCallbackInput{
+		UserID:    input.UserID,
+		PostID:    state.PostID,
+		ChannelID: state.ChannelID,
+		Context:   callbackContext,
+	})
+	return &dto.DialogSubmissionOutput{}, nil
+}
+
+// validate returns the submission errors to show next to the dialog
+// elements, or nil when res can be applied.
+func (d *ResolveDialog) validate(res resolution) map[string]string {
+	errs := make(map[string]string)
+	if d.requireNote && res.note == "" {
+		errs[EnrichmentKeyResolutionNote] = "Describe how the alert was resolved."
+	}
+	if res.category != "" && !slices.Contains(d.categories, res.category) {
+		errs[EnrichmentKeyResolutionCategory] = "Pick one of the listed root causes."
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// resolution is what the resolve dialog collected. The zero value stands for
+// an alert resolved without the dialog.
+type resolution struct {
+	note     string
+	category string
+}
+
+func resolutionFromSubmission(submission map[string]any) resolution {
+	value := func(name string) string {
+		s, _ := submission[name].(string)
+		return strings.TrimSpace(s)
+	}
+	return resolution{
+		note:     value(EnrichmentKeyResolutionNote),
+		category: value(EnrichmentKeyResolutionCategory),
+	}
+}
+
+func resolutionFromContext(callbackContext map[string]string) resolution {
+	return resolution{
+		note:     callbackContext[EnrichmentKeyResolutionNote],
+		category: callbackContext[EnrichmentKeyResolutionCategory],
+	}
+}
+
+// addToContext hands the resolution to the async phase.
+func (r resolution) addToContext(callbackContext map[string]string) {
+	if r.note != "" {
+		callbackContext[EnrichmentKeyResolutionNote] = r.note
+	}
+	if r.category != "" {
+		callbackContext[EnrichmentKeyResolutionCategory] = r.category
+	}
+}
+
+func (r resolution) enrichments() map[string]string {
+	enrichments := make(map[string]string)
+	r.addToContext(enrichments)
+	return enrichments
+}
+
+// summary renders the resolution as "category: note", or "" when nothing
+// was entered.
+func (r resolution) summary() string {
+	switch {
+	case r.category != "" && r.note != "":
+		return r.category + ": " + r.note
+	case r.category != "":
+		return r.category
+	default:
+		return r.note
+	}
+}
+
+// withResolution appends the resolution to a resolved attachment.
+func withResolution(a post.Attachment, r resolution) post.Attachment {
+	if r.note == "" && r.category == "" {
+		return a
+	}
+	var lines []string
+	if r.category != "" {
+		lines = append(lines, "**Root cause:** "+r.category)
+	}
+	if r.note != "" {
+		lines = append(lines, r.note)
+	}
+	a.Fields = append(slices.Clone(a.Fields), post.AttachmentField{
+		Title: "Resolution",
+		Value: strings.Join(lines, "\n"),
+	})
+	return a
+}
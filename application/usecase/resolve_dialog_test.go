@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func resolveCallbackInput() dto.MattermostCallbackInput {
+	input := snoozeCallbackInput()
+	input.TriggerID = "trigger-1"
+	input.Context[post.ContextKeyAction] = post.ActionResolve
+	return input
+}
+
+func resolveDialogSubmission(t *testing.T, submission map[string]any) dto.MattermostDialogSubmission {
+	t.Helper()
+	state, err := json.Marshal(resolveDialogState{
+		Fingerprint: "fp-12345",
+		AlertName:   "Test Alert",
+		Severity:    "high",
+		PostID:      "post-456",
+		ChannelID:   "channel-789",
+	})
+	require.NoError(t, err)
+	return dto.MattermostDialogSubmission{
+		CallbackID: resolveDialogCallbackID,
+		State:      string(state),
+		UserID:     "user-123",
+		ChannelID:  "channel-789",
+		Submission: submission,
+	}
+}
+
+func TestHandleCallbackUseCase_ExecuteImmediate_ResolveDialog(t *testing.T) {
+	t.Run("resolve click opens the dialog", func(t *testing.T) {
+		uc, _, keepClient, _, _ := setupHandleCallbackUseCase()
+		client := &portmock.MattermostDialogClientMock{
+			OpenDialogFunc: func(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error { return nil },
+		}
+		uc.SetResolveDialog(NewResolveDialog(client, "https://bridge/callback/dialog", true, []string{"deploy", "capacity"}))
+
+		result, err := uc.ExecuteImmediate(resolveCallbackInput())
+		require.NoError(t, err)
+		assert.True(t, result.DialogOpened)
+
+		calls := client.OpenDialogCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "trigger-1", calls[0].TriggerID)
+		assert.Equal(t, "https://bridge/callback/dialog", calls[0].SubmitURL)
+		dialog := calls[0].Dialog
+		require.Len(t, dialog.Elements, 2)
+		assert.Equal(t, EnrichmentKeyResolutionNote, dialog.Elements[0].Name)
+		assert.False(t, dialog.Elements[0].Optional)
+		assert.Equal(t, []port.DialogOption{{Text: "deploy", Value: "deploy"}, {Text: "capacity", Value: "capacity"}}, dialog.Elements[1].Options)
+
+		var state resolveDialogState
+		require.NoError(t, json.Unmarshal([]byte(dialog.State), &state))
+		assert.Equal(t, "fp-12345", state.Fingerprint)
+		assert.Equal(t, "post-456", state.PostID)
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("dialog failure falls back to resolving", func(t *testing.T) {
+		uc, _, _, _, _ := setupHandleCallbackUseCase()
+		client := &portmock.MattermostDialogClientMock{
+			OpenDialogFunc: func(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+				return errors.New("expired trigger")
+			},
+		}
+		uc.SetResolveDialog(NewResolveDialog(client, "https://bridge/callback/dialog", false, nil))
+
+		result, err := uc.ExecuteImmediate(resolveCallbackInput())
+		require.NoError(t, err)
+		assert.False(t, result.DialogOpened)
+		assert.Equal(t, "Processing Alert", result.Attachment.Title)
+	})
+}
+
+func TestHandleCallbackUseCase_ExecuteDialogSubmission(t *testing.T) {
+	newUseCase := func(requireNote bool) (*HandleCallbackUseCase, *postStore, *portmock.KeepClientMock, *portmock.MattermostClientMock) {
+		uc, postRepo, keepClient, mmClient, _ := setupHandleCallbackUseCase()
+		postRepo.posts["fp-12345"] = post.NewPost("post-456", "channel-789", alert.RestoreFingerprint("fp-12345"), "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+		uc.SetResolveDialog(NewResolveDialog(&portmock.MattermostDialogClientMock{}, "https://bridge/callback/dialog", requireNote, []string{"deploy", "capacity"}))
+		return uc, postRepo, keepClient, mmClient
+	}
+
+	t.Run("submission resolves with the note", func(t *testing.T) {
+		uc, postRepo, keepClient, mmClient := newUseCase(true)
+
+		result, err := uc.ExecuteDialogSubmission(resolveDialogSubmission(t, map[string]any{
+			EnrichmentKeyResolutionNote:     " Rolled back the release ",
+			EnrichmentKeyResolutionCategory: "deploy",
+		}))
+		require.NoError(t, err)
+		assert.Empty(t, result.Error)
+		assert.Empty(t, result.Errors)
+		uc.Wait()
+
+		assert.Equal(t, "resolved", enrichedWith(keepClient)[EnrichmentKeyStatus])
+		assert.Equal(t, "Rolled back the release", enrichedWith(keepClient)[EnrichmentKeyResolutionNote])
+		assert.Equal(t, "deploy", enrichedWith(keepClient)[EnrichmentKeyResolutionCategory])
+		require.NotEmpty(t, lastCall(mmClient.UpdatePostCalls()).Attachment.Fields)
+		field := lastCall(mmClient.UpdatePostCalls()).Attachment.Fields[len(lastCall(mmClient.UpdatePostCalls()).Attachment.Fields)-1]
+		assert.Equal(t, "Resolution", field.Title)
+		assert.Equal(t, "**Root cause:** deploy\nRolled back the release", field.Value)
+		assert.NotEmpty(t, postRepo.DeleteCalls())
+	})
+
+	t.Run("missing required note keeps the dialog open", func(t *testing.T) {
+		uc, _, keepClient, _ := newUseCase(true)
+
+		result, err := uc.ExecuteDialogSubmission(resolveDialogSubmission(t, map[string]any{}))
+		require.NoError(t, err)
+		assert.Contains(t, result.Errors, EnrichmentKeyResolutionNote)
+		uc.Wait()
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("unlisted category is rejected", func(t *testing.T) {
+		uc, _, _, _ := newUseCase(false)
+
+		result, err := uc.ExecuteDialogSubmission(resolveDialogSubmission(t, map[string]any{
+			EnrichmentKeyResolutionCategory: "cosmic rays",
+		}))
+		require.NoError(t, err)
+		assert.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors, EnrichmentKeyResolutionCategory)
+	})
+
+	t.Run("denied user gets an error", func(t *testing.T) {
+		uc, _, keepClient, mmClient := newUseCase(false)
+		rules := []port.PermissionRule{{Actions: []string{post.ActionResolve}, Users: []string{"alice"}}}
+		uc.SetPermissions(NewCallbackPermissions(rules, mmClient, &portmock.MattermostMembershipClientMock{}, uc.logger))
+
+		result, err := uc.ExecuteDialogSubmission(resolveDialogSubmission(t, map[string]any{}))
+		require.NoError(t, err)
+		assert.Equal(t, "You are not permitted to resolve this alert.", result.Error)
+		uc.Wait()
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("unknown dialog is an error", func(t *testing.T) {
+		uc, _, _, _ := newUseCase(false)
+		input := resolveDialogSubmission(t, nil)
+		input.CallbackID = "other"
+
+		_, err := uc.ExecuteDialogSubmission(input)
+		require.Error(t, err)
+	})
+}
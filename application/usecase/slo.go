@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// SLObjective asks for Target of the requests of one kind, e.g. webhooks, to
+// complete within Threshold.
+type SLObjective struct {
+	Name      string
+	Target    float64
+	Threshold time.Duration
+}
+
+type sloWindow struct {
+	SLObjective
+	total, good uint64
+	totalMetric *metrics.Counter
+	goodMetric  *metrics.Counter
+}
+
+// SLOTracker counts requests against response time objectives. Cumulative
+// counters are exported for burn rate alerts; the share of error budget used
+// since the last report is exported as a gauge and, when a report channel is
+// set, posted there by Report.
+type SLOTracker struct {
+	mu          sync.Mutex
+	windows     []*sloWindow
+	windowStart time.Time
+
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	channelID  string
+
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+func NewSLOTracker(objectives []SLObjective, logger *slog.Logger) *SLOTracker {
+	t := &SLOTracker{
+		clock:  clock.Real(),
+		logger: logger,
+	}
+	t.windowStart = t.clock.Now()
+	for _, o := range objectives {
+		w := &sloWindow{
+			SLObjective: o,
+			totalMetric: metrics.GetOrCreateCounter(`slo_requests_total{slo="` + o.Name + `"}`),
+			goodMetric:  metrics.GetOrCreateCounter(`slo_good_requests_total{slo="` + o.Name + `"}`),
+		}
+		t.windows = append(t.windows, w)
+		metrics.GetOrCreateGauge(`slo_target_ratio{slo="`+o.Name+`"}`, func() float64 { return o.Target })
+		metrics.GetOrCreateGauge(`slo_threshold_seconds{slo="`+o.Name+`"}`, func() float64 { return o.Threshold.Seconds() })
+		metrics.GetOrCreateGauge(`slo_error_budget_used_ratio{slo="`+o.Name+`"}`, func() float64 {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			return w.result().BudgetUsed()
+		})
+	}
+	return t
+}
+
+// SetClock replaces the clock that delimits report periods.
+func (t *SLOTracker) SetClock(c clock.Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clock = c
+	t.windowStart = c.Now()
+}
+
+// SetReport makes Report post the SLO report card to channelID.
+func (t *SLOTracker) SetReport(mmClient port.MattermostClient, msgBuilder port.MessageBuilder, channelID string) {
+	t.mmClient = mmClient
+	t.msgBuilder = msgBuilder
+	t.channelID = channelID
+}
+
+// Observe records a request of kind slo that took d. Failed requests count
+// against the objective whatever their duration. Unknown kinds are ignored.
+func (t *SLOTracker) Observe(slo string, d time.Duration, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, w := range t.windows {
+		if w.Name != slo {
+			continue
+		}
+		w.total++
+		w.totalMetric.Inc()
+		if !failed && d <= w.Threshold {
+			w.good++
+			w.goodMetric.Inc()
+		}
+		return
+	}
+}
+
+// Results returns how each objective fared since the last report.
+func (t *SLOTracker) Results() []port.SLOResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	results := make([]port.SLOResult, 0, len(t.windows))
+	for _, w := range t.windows {
+		results = append(results, w.result())
+	}
+	return results
+}
+
+// Report posts the results since the last report and starts a new period.
+// The period is kept when posting fails, so the next report covers it.
+func (t *SLOTracker) Report(ctx context.Context) error {
+	if t.mmClient == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	from, to := t.windowStart, t.clock.Now()
+	results := make([]port.SLOResult, 0, len(t.windows))
+	for _, w := range t.windows {
+		results = append(results, w.result())
+	}
+	t.mu.Unlock()
+
+	if _, err := t.mmClient.CreatePost(ctx, t.channelID, t.msgBuilder.BuildSLOReportAttachment(results, from, to)); err != nil {
+		return fmt.Errorf("post SLO report: %w", err)
+	}
+
+	// Requests observed while posting belong to the next period.
+	t.mu.Lock()
+	for i, w := range t.windows {
+		w.total -= results[i].Total
+		w.good -= results[i].Good
+	}
+	t.windowStart = to
+	t.mu.Unlock()
+
+	attrs := []slog.Attr{slog.Time("from", from), slog.Time("to", to)}
+	for _, r := range results {
+		attrs = append(attrs, slog.Float64(r.Name+"_ratio", r.Ratio()))
+	}
+	t.logger.Info("SLO report posted", logger.ApplicationFields("slo_report", attrs...))
+
+	return nil
+}
+
+func (w *sloWindow) result() port.SLOResult {
+	return port.SLOResult{
+		Name:      w.Name,
+		Target:    w.Target,
+		Threshold: w.Threshold,
+		Total:     w.total,
+		Good:      w.good,
+	}
+}
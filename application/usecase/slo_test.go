@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func setupSLOTracker() (*SLOTracker, *portmock.MattermostClientMock, *clock.Fake) {
+	tracker := NewSLOTracker([]SLObjective{
+		{Name: "webhook", Target: 0.99, Threshold: 2 * time.Second},
+		{Name: "callback", Target: 0.99, Threshold: time.Second},
+	}, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	tracker.SetClock(fake)
+
+	mmClient := &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "report-1", nil
+		},
+	}
+	msgBuilder := &portmock.MessageBuilderMock{
+		BuildSLOReportAttachmentFunc: func(results []port.SLOResult, from, to time.Time) post.Attachment {
+			return post.Attachment{Title: "SLO report"}
+		},
+	}
+	tracker.SetReport(mmClient, msgBuilder, "ops")
+	return tracker, mmClient, fake
+}
+
+func TestSLOTracker_Observe(t *testing.T) {
+	tracker, _, _ := setupSLOTracker()
+
+	tracker.Observe("webhook", time.Second, false)
+	tracker.Observe("webhook", 2*time.Second, false)
+	tracker.Observe("webhook", 3*time.Second, false)
+	tracker.Observe("webhook", time.Millisecond, true)
+	tracker.Observe("callback", 500*time.Millisecond, false)
+	tracker.Observe("unknown", time.Millisecond, false)
+
+	results := tracker.Results()
+	require.Len(t, results, 2)
+	assert.Equal(t, port.SLOResult{Name: "webhook", Target: 0.99, Threshold: 2 * time.Second, Total: 4, Good: 2}, results[0])
+	assert.Equal(t, port.SLOResult{Name: "callback", Target: 0.99, Threshold: time.Second, Total: 1, Good: 1}, results[1])
+}
+
+func TestSLOTracker_ReportStartsNewPeriod(t *testing.T) {
+	tracker, mmClient, fake := setupSLOTracker()
+	start := fake.Now()
+	tracker.Observe("webhook", 3*time.Second, false)
+	fake.Advance(7 * 24 * time.Hour)
+
+	require.NoError(t, tracker.Report(context.Background()))
+
+	require.Len(t, mmClient.CreatePostCalls(), 1)
+	assert.Equal(t, "ops", mmClient.CreatePostCalls()[0].ChannelID)
+	builds := tracker.msgBuilder.(*portmock.MessageBuilderMock).BuildSLOReportAttachmentCalls()
+	require.Len(t, builds, 1)
+	assert.Equal(t, start, builds[0].From)
+	assert.Equal(t, fake.Now(), builds[0].To)
+	assert.Equal(t, uint64(1), builds[0].Results[0].Total)
+
+	for _, r := range tracker.Results() {
+		assert.Zero(t, r.Total, "%s period reset after report", r.Name)
+	}
+}
+
+func TestSLOTracker_ReportFailureKeepsPeriod(t *testing.T) {
+	tracker, mmClient, _ := setupSLOTracker()
+	mmClient.CreatePostFunc = func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+		return "", errors.New("mattermost down")
+	}
+	tracker.Observe("webhook", time.Second, false)
+
+	err := tracker.Report(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "post SLO report")
+	assert.Equal(t, uint64(1), tracker.Results()[0].Total)
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// StatusBoard keeps a single post per channel listing the channel's active
+// alerts grouped by severity, like a pinned dashboard. Alert transitions
+// save or delete tracked posts through the repository WrapRepository
+// returns, which marks the boards stale; Refresh then rewrites the boards
+// whose content changed. Board post IDs are kept in the store, so restarts
+// keep updating the same posts.
+type StatusBoard struct {
+	postRepo   post.Repository
+	store      port.StatusBoardStore
+	mmClient   port.MattermostClient
+	msgBuilder port.MessageBuilder
+	keepUIURL  string
+	channels   []string // nil: every channel with active alerts or a board
+	pinClient  port.MattermostPinClient
+	clock      clock.Clock
+	logger     *slog.Logger
+
+	stale atomic.Bool
+
+	mu       sync.Mutex        // serializes refreshes
+	rendered map[string]string // the board last written, by channel ID
+}
+
+// NewStatusBoard returns a status board for channels, or for every channel
+// with active alerts when channels is empty. It starts stale, so the first
+// Refresh writes every board.
+func NewStatusBoard(
+	postRepo post.Repository,
+	store port.StatusBoardStore,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	keepUIURL string,
+	channels []string,
+	logger *slog.Logger,
+) *StatusBoard {
+	sb := &StatusBoard{
+		postRepo:   postRepo,
+		store:      store,
+		mmClient:   mmClient,
+		msgBuilder: msgBuilder,
+		keepUIURL:  keepUIURL,
+		clock:      clock.Real(),
+		logger:     logger,
+		rendered:   make(map[string]string),
+	}
+	if len(channels) > 0 {
+		sb.channels = channels
+	}
+	sb.stale.Store(true)
+	return sb
+}
+
+// SetPinClient makes new boards pinned to their channel.
+func (sb *StatusBoard) SetPinClient(client port.MattermostPinClient) {
+	sb.pinClient = client
+}
+
+// SetClock replaces the clock that decides which alerts are snoozed.
+func (sb *StatusBoard) SetClock(c clock.Clock) {
+	sb.clock = c
+}
+
+// WrapRepository returns repo with every saved or deleted post marking the
+// boards stale.
+func (sb *StatusBoard) WrapRepository(repo post.Repository) post.Repository {
+	return &boardRepository{Repository: repo, board: sb}
+}
+
+type boardRepository struct {
+	post.Repository
+	board *StatusBoard
+}
+
+func (r *boardRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
+	if err := r.Repository.Save(ctx, fingerprint, p); err != nil {
+		return err
+	}
+	r.board.Touch()
+	return nil
+}
+
+func (r *boardRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	if err := r.Repository.Delete(ctx, fingerprint); err != nil {
+		return err
+	}
+	r.board.Touch()
+	return nil
+}
+
+// Touch marks the boards stale, so the next Refresh rewrites them.
+func (sb *StatusBoard) Touch() {
+	sb.stale.Store(true)
+}
+
+// Refresh rewrites the boards when an alert changed since the last refresh.
+// Boards that failed to be written are tried again on the next one.
+func (sb *StatusBoard) Refresh(ctx context.Context) error {
+	if !sb.stale.Swap(false) {
+		return nil
+	}
+	if err := sb.refresh(ctx); err != nil {
+		sb.Touch()
+		return err
+	}
+	return nil
+}
+
+// Resync rewrites the boards whether or not an alert changed, picking up
+// changes made by other replicas.
+func (sb *StatusBoard) Resync(ctx context.Context) error {
+	sb.Touch()
+	return sb.Refresh(ctx)
+}
+
+func (sb *StatusBoard) refresh(ctx context.Context) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	posts, err := sb.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+	boards, err := sb.store.GetStatusBoards(ctx)
+	if err != nil {
+		return fmt.Errorf("get status boards: %w", err)
+	}
+
+	now := sb.clock.Now()
+	alerts := make(map[string][]port.BoardAlert)
+	for _, p := range posts {
+		alerts[p.ChannelID()] = append(alerts[p.ChannelID()], port.BoardAlert{
+			Fingerprint: p.Fingerprint().Value(),
+			Name:        p.AlertName(),
+			Severity:    p.Severity().Value(),
+			Status:      p.ShownStatus(),
+			Snoozed:     p.IsSnoozed(now),
+			FiringSince: p.FiringStartTime(),
+		})
+	}
+
+	channels := sb.channels
+	if channels == nil {
+		channels = slices.Sorted(maps.Keys(alerts))
+		for channelID := range boards {
+			if _, ok := alerts[channelID]; !ok {
+				channels = append(channels, channelID)
+			}
+		}
+	}
+
+	var errs []error
+	for _, channelID := range channels {
+		if err := sb.write(ctx, channelID, boards[channelID], alerts[channelID]); err != nil {
+			statusBoardWritesCounter("failed").Inc()
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// write creates the board of channelID, or updates postID when its content
+// changed since it was last written.
+func (sb *StatusBoard) write(ctx context.Context, channelID, postID string, alerts []port.BoardAlert) error {
+	attachment := sb.msgBuilder.ForChannel(channelID).BuildStatusBoardAttachment(alerts, sb.keepUIURL)
+	content := attachment.Color + "\x00" + attachment.Title + "\x00" + attachment.Text
+
+	if postID != "" {
+		if sb.rendered[channelID] == content {
+			return nil
+		}
+		if err := sb.mmClient.UpdatePost(ctx, postID, attachment); err != nil {
+			return fmt.Errorf("update status board of channel %s: %w", channelID, err)
+		}
+		sb.rendered[channelID] = content
+		statusBoardWritesCounter("updated").Inc()
+		return nil
+	}
+
+	postID, err := sb.mmClient.CreatePost(ctx, channelID, attachment)
+	if err != nil {
+		return fmt.Errorf("create status board of channel %s: %w", channelID, err)
+	}
+	if err := sb.store.SetStatusBoard(ctx, channelID, postID); err != nil {
+		return fmt.Errorf("save status board of channel %s: %w", channelID, err)
+	}
+	sb.rendered[channelID] = content
+	statusBoardWritesCounter("created").Inc()
+
+	if sb.pinClient != nil {
+		if err := sb.pinClient.PinPost(ctx, postID); err != nil {
+			sb.logger.Warn("Failed to pin status board",
+				slog.String("channel_id", channelID),
+				slog.String("post_id", postID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	sb.logger.Info("Status board created",
+		logger.ApplicationFields("status_board_created",
+			slog.String("channel_id", channelID),
+			slog.String("post_id", postID),
+			slog.Int("alerts", len(alerts)),
+		),
+	)
+	return nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type statusBoardTest struct {
+	board    *StatusBoard
+	repo     post.Repository
+	posts    *postStore
+	boards   map[string]string
+	mmClient *portmock.MattermostClientMock
+	built    map[string][]port.BoardAlert
+}
+
+func newStatusBoardTest(t *testing.T, channels []string) *statusBoardTest {
+	t.Helper()
+	bt := &statusBoardTest{
+		posts:  newPostStore(),
+		boards: make(map[string]string),
+		built:  make(map[string][]port.BoardAlert),
+	}
+	store := &portmock.StatusBoardStoreMock{
+		GetStatusBoardsFunc: func(ctx context.Context) (map[string]string, error) {
+			boards := make(map[string]string, len(bt.boards))
+			for channelID, postID := range bt.boards {
+				boards[channelID] = postID
+			}
+			return boards, nil
+		},
+		SetStatusBoardFunc: func(ctx context.Context, channelID, postID string) error {
+			bt.boards[channelID] = postID
+			return nil
+		},
+	}
+	bt.mmClient = &portmock.MattermostClientMock{
+		CreatePostFunc: func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+			return "board-" + channelID, nil
+		},
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+	}
+	msgBuilder := &portmock.MessageBuilderMock{}
+	msgBuilder.ForChannelFunc = func(channelID string) port.MessageBuilder {
+		return &portmock.MessageBuilderMock{
+			BuildStatusBoardAttachmentFunc: func(alerts []port.BoardAlert, keepUIURL string) post.Attachment {
+				bt.built[channelID] = alerts
+				return post.Attachment{Title: fmt.Sprintf("%d active", len(alerts))}
+			},
+		}
+	}
+	bt.board = NewStatusBoard(bt.posts, store, bt.mmClient, msgBuilder, "https://keep.example.com", channels, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	bt.board.SetClock(clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)))
+	bt.repo = bt.board.WrapRepository(bt.posts)
+	return bt
+}
+
+func (bt *statusBoardTest) save(t *testing.T, fingerprint, channelID, severity string) {
+	t.Helper()
+	fp, err := alert.NewFingerprint(fingerprint)
+	require.NoError(t, err)
+	p := post.NewPost("post-"+fingerprint, channelID, fp, "Alert "+fingerprint, alert.RestoreSeverity(severity), time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC), time.Now())
+	p.ShowStatus(alert.StatusFiring)
+	require.NoError(t, bt.repo.Save(context.Background(), fp, p))
+}
+
+func TestStatusBoard_CreatesAndUpdatesBoards(t *testing.T) {
+	bt := newStatusBoardTest(t, nil)
+	ctx := context.Background()
+	bt.save(t, "fp-1", "channel-1", "critical")
+	bt.save(t, "fp-2", "channel-2", "warning")
+
+	require.NoError(t, bt.board.Refresh(ctx))
+	assert.Equal(t, map[string]string{"channel-1": "board-channel-1", "channel-2": "board-channel-2"}, bt.boards)
+	require.Len(t, bt.mmClient.CreatePostCalls(), 2)
+	assert.Equal(t, []port.BoardAlert{{
+		Fingerprint: "fp-1",
+		Name:        "Alert fp-1",
+		Severity:    "critical",
+		Status:      alert.StatusFiring,
+		FiringSince: time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC),
+	}}, bt.built["channel-1"])
+
+	require.NoError(t, bt.board.Refresh(ctx))
+	assert.Empty(t, bt.mmClient.UpdatePostCalls(), "boards are left alone until an alert changes")
+
+	fp, _ := alert.NewFingerprint("fp-1")
+	require.NoError(t, bt.repo.Delete(ctx, fp))
+	require.NoError(t, bt.board.Refresh(ctx))
+	require.Len(t, bt.mmClient.UpdatePostCalls(), 1, "only the board that changed is rewritten")
+	assert.Equal(t, "board-channel-1", bt.mmClient.UpdatePostCalls()[0].PostID)
+	assert.Equal(t, "0 active", bt.mmClient.UpdatePostCalls()[0].Attachment.Title, "a channel keeps its board once it is clear")
+	assert.Len(t, bt.mmClient.CreatePostCalls(), 2)
+}
+
+func TestStatusBoard_ConfiguredChannels(t *testing.T) {
+	bt := newStatusBoardTest(t, []string{"channel-1"})
+	pins := &portmock.MattermostPinClientMock{
+		PinPostFunc: func(ctx context.Context, postID string) error {
+			return errors.New("forbidden")
+		},
+	}
+	bt.board.SetPinClient(pins)
+	bt.save(t, "fp-2", "channel-2", "warning")
+
+	require.NoError(t, bt.board.Refresh(context.Background()), "a board that cannot be pinned is still kept")
+	assert.Equal(t, map[string]string{"channel-1": "board-channel-1"}, bt.boards)
+	assert.Empty(t, bt.built["channel-1"])
+	require.Len(t, pins.PinPostCalls(), 1)
+	assert.Equal(t, "board-channel-1", pins.PinPostCalls()[0].PostID)
+}
+
+func TestStatusBoard_RetriesFailedWrites(t *testing.T) {
+	bt := newStatusBoardTest(t, nil)
+	ctx := context.Background()
+	bt.save(t, "fp-1", "channel-1", "critical")
+	bt.mmClient.CreatePostFunc = func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+		return "", errors.New("mattermost down")
+	}
+
+	require.Error(t, bt.board.Refresh(ctx))
+	assert.Empty(t, bt.boards)
+
+	bt.mmClient.CreatePostFunc = func(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+		return "board-" + channelID, nil
+	}
+	require.NoError(t, bt.board.Refresh(ctx))
+	assert.Equal(t, map[string]string{"channel-1": "board-channel-1"}, bt.boards)
+}
+
+func TestStatusBoard_Resync(t *testing.T) {
+	bt := newStatusBoardTest(t, nil)
+	ctx := context.Background()
+	bt.boards["channel-1"] = "board-1"
+
+	require.NoError(t, bt.board.Refresh(ctx))
+	require.Len(t, bt.mmClient.UpdatePostCalls(), 1, "a stored board is rewritten once after a restart")
+
+	// Another replica changed the alerts.
+	fp, _ := alert.NewFingerprint("fp-1")
+	require.NoError(t, bt.posts.Save(ctx, fp, post.NewPost("post-1", "channel-1", fp, "Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())))
+	require.NoError(t, bt.board.Refresh(ctx))
+	assert.Len(t, bt.mmClient.UpdatePostCalls(), 1)
+
+	require.NoError(t, bt.board.Resync(ctx))
+	assert.Len(t, bt.mmClient.UpdatePostCalls(), 2)
+	assert.Empty(t, bt.mmClient.CreatePostCalls())
+}
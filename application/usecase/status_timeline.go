@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// timelineMemory is how long the timeline remembers an alert's last status,
+// matching how long the post stores keep an untouched post.
+const timelineMemory = 7 * 24 * time.Hour
+
+var timelineLabels = map[string]string{
+	alert.StatusFiring:       "🔥 **Firing**",
+	alert.StatusAcknowledged: "👀 **Acknowledged**",
+	alert.StatusResolved:     "✅ **Resolved**",
+}
+
+type timelineEntry struct {
+	status string
+	at     time.Time
+}
+
+// StatusTimeline appends every status transition of an alert (firing,
+// acknowledged, resolved) as a thread reply under its post, stamped with the
+// time and the actor, so the thread keeps the history that post edits
+// overwrite. The last status of each alert is remembered in memory, so the
+// same status reported by both a button and Keep's webhook is posted once.
+// Reply failures are logged and never fail the caller.
+type StatusTimeline struct {
+	mmClient port.MattermostClient
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	mu   sync.Mutex
+	last map[string]timelineEntry // fingerprint -> last appended status
+}
+
+func NewStatusTimeline(mmClient port.MattermostClient, logger *slog.Logger) *StatusTimeline {
+	return &StatusTimeline{
+		mmClient: mmClient,
+		clock:    clock.Real(),
+		logger:   logger,
+		last:     make(map[string]timelineEntry),
+	}
+}
+
+// SetClock replaces the clock that timestamps entries.
+func (t *StatusTimeline) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// Append replies to the alert's post with its new status unless that is
+// the status last appended for the alert. An empty actor means the change
+// came from Keep. A nil timeline appends nothing, so use cases can call it
+// whether or not the timeline is enabled.
+func (t *StatusTimeline) Append(ctx context.Context, fingerprint alert.Fingerprint, channelID, postID, status, actor string) {
+	if t == nil {
+		return
+	}
+	label, ok := timelineLabels[status]
+	if !ok {
+		return
+	}
+
+	now := t.clock.Now()
+	t.mu.Lock()
+	for fp, e := range t.last {
+		if now.Sub(e.at) > timelineMemory {
+			delete(t.last, fp)
+		}
+	}
+	if e, ok := t.last[fingerprint.Value()]; ok && e.status == status {
+		t.mu.Unlock()
+		return
+	}
+	t.last[fingerprint.Value()] = timelineEntry{status: status, at: now}
+	t.mu.Unlock()
+
+	by := "Keep"
+	if actor != "" {
+		by = "@" + actor
+	}
+	msg := fmt.Sprintf("`%s` · %s · %s", now.UTC().Format("2006-01-02 15:04:05 UTC"), label, by)
+	if err := t.mmClient.ReplyToThread(ctx, channelID, postID, msg); err != nil {
+		t.logger.Warn("Failed to append status to timeline",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.String("post_id", postID),
+			slog.String("status", status),
+			slog.String("error", err.Error()),
+		)
+		timelineErrorsCounter.Inc()
+		return
+	}
+	timelineEntriesCounter(status).Inc()
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func newTestTimeline(now time.Time) (*StatusTimeline, *portmock.MattermostClientMock, *clock.Fake) {
+	mmClient := &portmock.MattermostClientMock{
+		ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+			return nil
+		},
+	}
+	fake := clock.NewFake(now)
+	timeline := NewStatusTimeline(mmClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	timeline.SetClock(fake)
+	return timeline, mmClient, fake
+}
+
+func TestStatusTimeline(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeline, mmClient, fake := newTestTimeline(now)
+	fp := alert.RestoreFingerprint("fp-1")
+	ctx := context.Background()
+
+	timeline.Append(ctx, fp, "channel-1", "post-1", alert.StatusFiring, "")
+	fake.Advance(5 * time.Minute)
+	timeline.Append(ctx, fp, "channel-1", "post-1", alert.StatusAcknowledged, "alice")
+	timeline.Append(ctx, fp, "channel-1", "post-1", alert.StatusAcknowledged, "alice")
+	fake.Advance(time.Hour)
+	timeline.Append(ctx, fp, "channel-1", "post-1", alert.StatusResolved, "")
+
+	calls := mmClient.ReplyToThreadCalls()
+	require.Len(t, calls, 3, "a repeated status is appended once")
+	assert.Equal(t, "post-1", calls[0].RootID)
+	assert.Equal(t, "`2026-01-01 12:00:00 UTC` · 🔥 **Firing** · Keep", calls[0].Message)
+	assert.Equal(t, "`2026-01-01 12:05:00 UTC` · 👀 **Acknowledged** · @alice", calls[1].Message)
+	assert.Equal(t, "`2026-01-01 13:05:00 UTC` · ✅ **Resolved** · Keep", calls[2].Message)
+}
+
+func TestStatusTimelineIgnoresOtherStatuses(t *testing.T) {
+	timeline, mmClient, _ := newTestTimeline(time.Now())
+
+	timeline.Append(context.Background(), alert.RestoreFingerprint("fp-1"), "channel-1", "post-1", alert.StatusSuppressed, "")
+
+	assert.Empty(t, mmClient.ReplyToThreadCalls())
+}
+
+func TestStatusTimelineForgetsOldAlerts(t *testing.T) {
+	timeline, mmClient, fake := newTestTimeline(time.Now())
+	fp := alert.RestoreFingerprint("fp-1")
+	ctx := context.Background()
+
+	timeline.Append(ctx, fp, "channel-1", "post-1", alert.StatusFiring, "")
+	fake.Advance(timelineMemory + time.Hour)
+	timeline.Append(ctx, alert.RestoreFingerprint("fp-2"), "channel-1", "post-2", alert.StatusFiring, "")
+
+	assert.Len(t, mmClient.ReplyToThreadCalls(), 2)
+	assert.NotContains(t, timeline.last, "fp-1")
+}
+
+func TestStatusTimelineReplyFailureIsLogged(t *testing.T) {
+	timeline, mmClient, _ := newTestTimeline(time.Now())
+	mmClient.ReplyToThreadFunc = func(ctx context.Context, channelID, rootID, message string) error {
+		return errors.New("mattermost down")
+	}
+
+	assert.NotPanics(t, func() {
+		timeline.Append(context.Background(), alert.RestoreFingerprint("fp-1"), "channel-1", "post-1", alert.StatusFiring, "")
+	})
+}
+
+func TestStatusTimelineNilAppendsNothing(t *testing.T) {
+	var timeline *StatusTimeline
+	assert.NotPanics(t, func() {
+		timeline.Append(context.Background(), alert.RestoreFingerprint("fp-1"), "channel-1", "post-1", alert.StatusFiring, "")
+	})
+}
+
+func TestHandleAlertUseCase_TimelineRecordsKeepTransitions(t *testing.T) {
+	uc, postRepo, mmClient, keepClient, _, _ := setupHandleAlertUseCase()
+	timeline, timelineClient, _ := newTestTimeline(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uc.SetStatusTimeline(timeline)
+	ctx := context.Background()
+
+	fp := alert.RestoreFingerprint("fp-12345")
+	postRepo.posts[fp.Value()] = post.NewPost("existing-post-123", "channel-456", fp, "Test Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	keepClient.GetAlertFunc = keepAlertSequence(&port.KeepAlert{
+		Fingerprint: "fp-12345",
+		Status:      "acknowledged",
+		Enrichments: map[string]string{"assignee": "john.doe"},
+	})
+
+	input := dto.KeepAlertInput{
+		Fingerprint: "fp-12345",
+		Name:        "Test Alert",
+		Severity:    "high",
+		Status:      "acknowledged",
+		Source:      []string{"prometheus"},
+	}
+	require.NoError(t, uc.Execute(ctx, input))
+	input.Status = "resolved"
+	require.NoError(t, uc.Execute(ctx, input))
+
+	calls := timelineClient.ReplyToThreadCalls()
+	require.Len(t, calls, 2)
+	assert.Contains(t, calls[0].Message, "**Acknowledged** · @john.doe")
+	assert.Contains(t, calls[1].Message, "**Resolved** · Keep")
+	assert.Empty(t, mmClient.ReplyToThreadCalls(), "the timeline replaces the auto-resolve reply")
+}
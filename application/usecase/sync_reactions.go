@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	EnrichmentKeyReactions     = "reactions"
+	EnrichmentKeyReactionCount = "reactionCount"
+	EnrichmentKeyReactionUsers = "reactionUsers"
+)
+
+// SyncReactionsUseCase summarizes emoji reactions on tracked alert posts and
+// writes the summary to Keep as enrichments, so responder engagement can be
+// charted on the Keep side.
+type SyncReactionsUseCase struct {
+	postRepo       post.Repository
+	reactionClient port.MattermostReactionClient
+	keepClient     port.KeepClient
+	emojis         map[string]bool
+	lastSummary    map[string]string
+	logger         *slog.Logger
+}
+
+// NewSyncReactionsUseCase creates the use case. emojis limits the summary to
+// the listed emoji names; an empty list counts every reaction.
+func NewSyncReactionsUseCase(
+	postRepo post.Repository,
+	reactionClient port.MattermostReactionClient,
+	keepClient port.KeepClient,
+	emojis []string,
+	logger *slog.Logger,
+) *SyncReactionsUseCase {
+	var allowed map[string]bool
+	if len(emojis) > 0 {
+		allowed = make(map[string]bool, len(emojis))
+		for _, e := range emojis {
+			allowed[strings.Trim(e, ":")] = true
+		}
+	}
+	return &SyncReactionsUseCase{
+		postRepo:       postRepo,
+		reactionClient: reactionClient,
+		keepClient:     keepClient,
+		emojis:         allowed,
+		lastSummary:    make(map[string]string),
+		logger:         logger,
+	}
+}
+
+// Execute collects reactions on every active post and enriches the alerts
+// whose summary changed since the last successful sync. Not safe for
+// concurrent use.
+func (uc *SyncReactionsUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		reactionSyncErrorsCounter.Inc()
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	active := make(map[string]bool, len(posts))
+	var errs []error
+	for _, p := range posts {
+		fingerprint := p.Fingerprint().Value()
+		active[fingerprint] = true
+		if err := uc.sync(ctx, p); err != nil {
+			reactionSyncErrorsCounter.Inc()
+			errs = append(errs, fmt.Errorf("sync reactions for %s: %w", fingerprint, err))
+		}
+	}
+
+	for fingerprint := range uc.lastSummary {
+		if !active[fingerprint] {
+			delete(uc.lastSummary, fingerprint)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (uc *SyncReactionsUseCase) sync(ctx context.Context, p *post.Post) error {
+	fingerprint := p.Fingerprint().Value()
+
+	reactions, err := uc.reactionClient.GetReactions(ctx, p.PostID())
+	if err != nil {
+		return fmt.Errorf("get reactions: %w", err)
+	}
+
+	enrichments := uc.summarize(reactions)
+	summary := enrichments[EnrichmentKeyReactions] + "|" + enrichments[EnrichmentKeyReactionUsers]
+	last, seen := uc.lastSummary[fingerprint]
+	if summary == last || (!seen && enrichments[EnrichmentKeyReactionCount] == "0") {
+		return nil
+	}
+
+	if err := uc.keepClient.EnrichAlert(ctx, fingerprint, enrichments, port.EnrichOptions{DisposeOnNewAlert: false}); err != nil {
+		return fmt.Errorf("enrich alert: %w", err)
+	}
+	uc.lastSummary[fingerprint] = summary
+	reactionSyncEnrichCounter.Inc()
+
+	uc.logger.Info("Reaction summary sent to Keep",
+		logger.ApplicationFields("reactions_synced",
+			slog.String("fingerprint", fingerprint),
+			slog.String("reactions", enrichments[EnrichmentKeyReactions]),
+		),
+	)
+
+	return nil
+}
+
+// summarize renders reactions as "emoji:count" pairs ordered by count, then
+// name, together with the total and the number of distinct reacting users.
+func (uc *SyncReactionsUseCase) summarize(reactions []port.Reaction) map[string]string {
+	counts := make(map[string]int)
+	users := make(map[string]bool)
+	total := 0
+	for _, r := range reactions {
+		if uc.emojis != nil && !uc.emojis[r.EmojiName] {
+			continue
+		}
+		counts[r.EmojiName]++
+		users[r.UserID] = true
+		total++
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+":"+strconv.Itoa(counts[name]))
+	}
+
+	return map[string]string{
+		EnrichmentKeyReactions:     strings.Join(pairs, ","),
+		EnrichmentKeyReactionCount: strconv.Itoa(total),
+		EnrichmentKeyReactionUsers: strconv.Itoa(len(users)),
+	}
+}
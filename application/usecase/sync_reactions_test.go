@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+type syncReactionsFixture struct {
+	uc             *SyncReactionsUseCase
+	postRepo       *postStore
+	reactionClient *portmock.MattermostReactionClientMock
+	keepClient     *portmock.KeepClientMock
+	reactions      map[string][]port.Reaction
+}
+
+func setupSyncReactionsUseCase(emojis []string) *syncReactionsFixture {
+	f := &syncReactionsFixture{
+		postRepo:  newPostStore(),
+		reactions: make(map[string][]port.Reaction),
+	}
+	f.reactionClient = &portmock.MattermostReactionClientMock{
+		GetReactionsFunc: func(ctx context.Context, postID string) ([]port.Reaction, error) {
+			return f.reactions[postID], nil
+		},
+	}
+	f.keepClient = &portmock.KeepClientMock{
+		EnrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+			return nil
+		},
+	}
+	f.uc = NewSyncReactionsUseCase(f.postRepo, f.reactionClient, f.keepClient, emojis, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *syncReactionsFixture) addPost(fp string) {
+	f.postRepo.posts[fp] = post.NewPost("post-"+fp, "ch", alert.RestoreFingerprint(fp), "Disk full", alert.RestoreSeverity("high"), time.Now(), time.Now())
+}
+
+func TestSyncReactions_EnrichesSummary(t *testing.T) {
+	f := setupSyncReactionsUseCase(nil)
+	f.addPost("fp-1")
+	f.reactions["post-fp-1"] = []port.Reaction{
+		{UserID: "u1", EmojiName: "white_check_mark"},
+		{UserID: "u1", EmojiName: "+1"},
+		{UserID: "u2", EmojiName: "+1"},
+	}
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	calls := f.keepClient.EnrichAlertCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "fp-1", calls[0].Fingerprint)
+	assert.Equal(t, map[string]string{
+		EnrichmentKeyReactions:     "+1:2,white_check_mark:1",
+		EnrichmentKeyReactionCount: "3",
+		EnrichmentKeyReactionUsers: "2",
+	}, calls[0].Enrichments)
+	assert.False(t, calls[0].Opts.DisposeOnNewAlert)
+}
+
+func TestSyncReactions_OnlyWritesChanges(t *testing.T) {
+	f := setupSyncReactionsUseCase(nil)
+	f.addPost("fp-1")
+	f.addPost("fp-quiet")
+	f.reactions["post-fp-1"] = []port.Reaction{{UserID: "u1", EmojiName: "eyes"}}
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+	require.NoError(t, f.uc.Execute(context.Background()))
+	require.Len(t, f.keepClient.EnrichAlertCalls(), 1, "unchanged summary and posts without reactions are skipped")
+
+	f.reactions["post-fp-1"] = nil
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	calls := f.keepClient.EnrichAlertCalls()
+	require.Len(t, calls, 2, "removing the last reaction clears the summary")
+	assert.Equal(t, "0", calls[1].Enrichments[EnrichmentKeyReactionCount])
+	assert.Equal(t, "", calls[1].Enrichments[EnrichmentKeyReactions])
+}
+
+func TestSyncReactions_FiltersEmojis(t *testing.T) {
+	f := setupSyncReactionsUseCase([]string{":white_check_mark:", "+1"})
+	f.addPost("fp-1")
+	f.reactions["post-fp-1"] = []port.Reaction{
+		{UserID: "u1", EmojiName: "white_check_mark"},
+		{UserID: "u2", EmojiName: "joy"},
+	}
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	calls := f.keepClient.EnrichAlertCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "white_check_mark:1", calls[0].Enrichments[EnrichmentKeyReactions])
+	assert.Equal(t, "1", calls[0].Enrichments[EnrichmentKeyReactionUsers])
+}
+
+func TestSyncReactions_EnrichErrorRetriedNextRun(t *testing.T) {
+	f := setupSyncReactionsUseCase(nil)
+	f.addPost("fp-1")
+	f.reactions["post-fp-1"] = []port.Reaction{{UserID: "u1", EmojiName: "+1"}}
+	f.keepClient.EnrichAlertFunc = func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+		return errors.New("keep down")
+	}
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sync reactions for fp-1")
+
+	f.keepClient.EnrichAlertFunc = func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+		return nil
+	}
+	require.NoError(t, f.uc.Execute(context.Background()))
+	assert.Len(t, f.keepClient.EnrichAlertCalls(), 2)
+}
+
+func TestSyncReactions_GetReactionsErrorContinues(t *testing.T) {
+	f := setupSyncReactionsUseCase(nil)
+	f.addPost("fp-1")
+	f.addPost("fp-2")
+	f.reactions["post-fp-2"] = []port.Reaction{{UserID: "u1", EmojiName: "+1"}}
+	f.reactionClient.GetReactionsFunc = func(ctx context.Context, postID string) ([]port.Reaction, error) {
+		if postID == "post-fp-1" {
+			return nil, errors.New("mattermost down")
+		}
+		return f.reactions[postID], nil
+	}
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mattermost down")
+
+	calls := f.keepClient.EnrichAlertCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "fp-2", calls[0].Fingerprint)
+}
+
+func TestSyncReactions_FindAllActiveError(t *testing.T) {
+	f := setupSyncReactionsUseCase(nil)
+	f.uc.postRepo = &portmock.RepositoryMock{
+		FindAllActiveFunc: func(ctx context.Context) ([]*post.Post, error) {
+			return nil, errors.New("valkey down")
+		},
+	}
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "find all active posts")
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// threadNoteTimeout bounds the Keep and Mattermost calls of one reply.
+const threadNoteTimeout = 30 * time.Second
+
+// ThreadNotesUseCase forwards the replies users post in alert threads to
+// Keep, appending each to a note enrichment of the alert, so the incident
+// context discussed in Mattermost is kept with the alert. Replies by bots
+// and incoming webhooks, among them the bridge's own thread replies, are
+// ignored, so nothing the bridge posts comes back to Keep as a note.
+type ThreadNotesUseCase struct {
+	postRepo      post.Repository
+	events        port.MattermostReplyEvents
+	mmClient      port.MattermostClient
+	keepClient    port.KeepClient
+	enrichmentKey string
+	maxLength     int
+	locks         *FingerprintLocks
+	clock         clock.Clock
+	logger        *slog.Logger
+}
+
+// NewThreadNotesUseCase creates the use case. Replies are appended to the
+// enrichmentKey enrichment, cut to maxLength characters.
+func NewThreadNotesUseCase(
+	postRepo post.Repository,
+	events port.MattermostReplyEvents,
+	mmClient port.MattermostClient,
+	keepClient port.KeepClient,
+	enrichmentKey string,
+	maxLength int,
+	logger *slog.Logger,
+) *ThreadNotesUseCase {
+	return &ThreadNotesUseCase{
+		postRepo:      postRepo,
+		events:        events,
+		mmClient:      mmClient,
+		keepClient:    keepClient,
+		enrichmentKey: enrichmentKey,
+		maxLength:     maxLength,
+		clock:         clock.Real(),
+		logger:        logger,
+	}
+}
+
+// SetLocks makes replies be forwarded under the fingerprint lock of their
+// alert, and only once when every replica receives them, provided locks
+// remember handled deliveries.
+func (uc *ThreadNotesUseCase) SetLocks(locks *FingerprintLocks) {
+	uc.locks = locks
+}
+
+// SetClock replaces the clock that stamps notes and waits between
+// reconnects.
+func (uc *ThreadNotesUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Run follows thread replies until ctx is cancelled, reconnecting whenever
+// the event stream fails.
+func (uc *ThreadNotesUseCase) Run(ctx context.Context) {
+	uc.logger.Info("Thread reply listener started")
+	for ctx.Err() == nil {
+		err := uc.events.ListenReplies(ctx, uc.Handle)
+		if ctx.Err() != nil {
+			break
+		}
+		threadReplyListenerReconnectsCounter.Inc()
+		uc.logger.Error("Thread reply listener disconnected",
+			logger.ApplicationFields("thread_reply_listener_disconnected",
+				slog.String("error", fmt.Sprint(err)),
+			),
+		)
+		select {
+		case <-uc.clock.After(reactionReconnectDelay):
+		case <-ctx.Done():
+		}
+	}
+	uc.logger.Info("Thread reply listener stopped")
+}
+
+// Handle forwards event to Keep when it is a user's reply to a tracked alert
+// post. Other replies are ignored.
+func (uc *ThreadNotesUseCase) Handle(ctx context.Context, event port.ReplyEvent) {
+	message := strings.TrimSpace(event.Message)
+	if event.FromBot || message == "" {
+		return
+	}
+
+	// A reply already received is forwarded even when shutdown starts.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), threadNoteTimeout)
+	defer cancel()
+
+	p, err := findPostByID(ctx, uc.postRepo, event.RootID)
+	if err != nil {
+		threadNotesCounter("failed").Inc()
+		uc.logger.Error("Failed to look up thread root post",
+			logger.ApplicationFields("thread_note_failed",
+				slog.String("post_id", event.RootID),
+				slog.String("error", err.Error()),
+			),
+		)
+		return
+	}
+	if p == nil {
+		return
+	}
+
+	fingerprint := p.Fingerprint().Value()
+	forward := func(ctx context.Context) error { return uc.forward(ctx, fingerprint, event, message) }
+	if uc.locks != nil {
+		err = uc.locks.Do(ctx, fingerprint, forward)
+	} else {
+		err = forward(ctx)
+	}
+	if err != nil {
+		threadNotesCounter("failed").Inc()
+		uc.logger.Error("Failed to forward thread reply to Keep",
+			logger.ApplicationFields("thread_note_failed",
+				slog.String("fingerprint", fingerprint),
+				slog.String("post_id", event.PostID),
+				slog.String("error", err.Error()),
+			),
+		)
+	}
+}
+
+// forward appends the reply to the alert's note in Keep, unless another
+// replica already did.
+func (uc *ThreadNotesUseCase) forward(ctx context.Context, fingerprint string, event port.ReplyEvent, message string) error {
+	var store port.IdempotencyStore
+	if uc.locks != nil {
+		store = uc.locks.idempotency
+	}
+	key := "thread-note:" + event.PostID
+	if store != nil {
+		if seen, err := store.Seen(ctx, key); err != nil {
+			// Forwarding a reply twice is better than not at all.
+			uc.logger.Warn("Failed to check idempotency key",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+		} else if seen {
+			return nil
+		}
+	}
+
+	username, err := uc.mmClient.GetUser(ctx, event.UserID)
+	if err != nil {
+		uc.logger.Warn("Failed to look up reply author, using user ID",
+			slog.String("user_id", event.UserID),
+			slog.String("error", err.Error()),
+		)
+		username = event.UserID
+	}
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint)
+	if err != nil {
+		return fmt.Errorf("get alert: %w", err)
+	}
+	note := keepAlert.Enrichments[uc.enrichmentKey]
+	if note != "" {
+		note += "\n"
+	}
+	note += fmt.Sprintf("[%s] @%s: %s", uc.clock.Now().UTC().Format("2006-01-02 15:04 UTC"), username, truncateRunes(message, uc.maxLength))
+	if err := uc.keepClient.EnrichAlert(ctx, fingerprint, map[string]string{uc.enrichmentKey: note}, port.EnrichOptions{}); err != nil {
+		return fmt.Errorf("enrich alert: %w", err)
+	}
+
+	if store != nil {
+		if err := store.Mark(ctx, key, uc.locks.idempotencyTTL); err != nil {
+			uc.logger.Warn("Failed to record idempotency key",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	threadNotesCounter("forwarded").Inc()
+	uc.logger.Info("Thread reply forwarded to Keep",
+		logger.ApplicationFields("thread_note_forwarded",
+			slog.String("fingerprint", fingerprint),
+			slog.String("post_id", event.PostID),
+			slog.String("user", username),
+		),
+	)
+	return nil
+}
+
+// truncateRunes cuts s to at most n characters, marking the cut with an
+// ellipsis.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimRight(string(runes[:n-1]), " ") + "…"
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func setupThreadNotesUseCase(note string) (*ThreadNotesUseCase, *portmock.KeepClientMock, *portmock.MattermostReplyEventsMock) {
+	postRepo := newPostStore()
+	fingerprint := alert.RestoreFingerprint("fp-1")
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", fingerprint, "Disk full", alert.RestoreSeverity(alert.SeverityCritical), time.Now(), time.Now())
+
+	keepClient := &portmock.KeepClientMock{
+		GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+			return &port.KeepAlert{Fingerprint: fingerprint, Enrichments: map[string]string{"note": note}}, nil
+		},
+		EnrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+			return nil
+		},
+	}
+	mmClient := &portmock.MattermostClientMock{
+		GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+			return "john", nil
+		},
+	}
+	events := &portmock.MattermostReplyEventsMock{}
+	uc := NewThreadNotesUseCase(postRepo, events, mmClient, keepClient, "note", 20, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	uc.SetClock(clock.NewFake(time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)))
+	return uc, keepClient, events
+}
+
+func TestThreadNotesUseCase_Handle(t *testing.T) {
+	t.Run("replies are appended to the note", func(t *testing.T) {
+		uc, keepClient, _ := setupThreadNotesUseCase("[2026-01-01 12:00 UTC] @jane: Looking")
+
+		uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "user-1", Message: " Restarted the pod \n"})
+
+		calls := keepClient.EnrichAlertCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "fp-1", calls[0].Fingerprint)
+		assert.Equal(t, map[string]string{"note": "[2026-01-01 12:00 UTC] @jane: Looking\n[2026-01-01 12:30 UTC] @john: Restarted the pod"}, calls[0].Enrichments)
+	})
+
+	t.Run("long replies are cut", func(t *testing.T) {
+		uc, keepClient, _ := setupThreadNotesUseCase("")
+
+		uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "user-1", Message: "The disk filled up with old logs again"})
+
+		require.Len(t, keepClient.EnrichAlertCalls(), 1)
+		assert.Equal(t, "[2026-01-01 12:30 UTC] @john: The disk filled up…", keepClient.EnrichAlertCalls()[0].Enrichments["note"])
+	})
+
+	t.Run("bot replies are ignored", func(t *testing.T) {
+		uc, keepClient, _ := setupThreadNotesUseCase("")
+
+		uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "bot", Message: "Alert resolved", FromBot: true})
+
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("replies to other posts are ignored", func(t *testing.T) {
+		uc, keepClient, _ := setupThreadNotesUseCase("")
+
+		uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-1", RootID: "post-other", UserID: "user-1", Message: "Hi"})
+
+		assert.Empty(t, keepClient.EnrichAlertCalls())
+	})
+
+	t.Run("replies forwarded by another replica are skipped", func(t *testing.T) {
+		uc, keepClient, _ := setupThreadNotesUseCase("")
+		locks, locker, held := setupFingerprintLocks()
+		locks.SetIdempotency(&memoryIdempotencyStore{keys: make(map[string]bool)}, time.Hour)
+		uc.SetLocks(locks)
+		keepClient.EnrichAlertFunc = func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+			assert.True(t, *held, "the note is written under the lock")
+			return nil
+		}
+		event := port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "user-1", Message: "Restarted the pod"}
+
+		uc.Handle(context.Background(), event)
+		uc.Handle(context.Background(), event)
+
+		assert.Len(t, locker.LockCalls(), 2)
+		assert.Len(t, keepClient.EnrichAlertCalls(), 1)
+	})
+}
+
+// postIDStore adds the post ID lookup some repositories offer to postStore.
+type postIDStore struct {
+	*postStore
+}
+
+func (s postIDStore) FindByPostID(_ context.Context, postID string) (*post.Post, error) {
+	for _, p := range s.posts {
+		if p.PostID() == postID {
+			return p, nil
+		}
+	}
+	return nil, post.ErrNotFound
+}
+
+func TestThreadNotesUseCase_HandleFindsPostByID(t *testing.T) {
+	postRepo := newPostStore()
+	postRepo.posts["fp-1"] = post.NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Disk full", alert.RestoreSeverity(alert.SeverityCritical), time.Now(), time.Now())
+	keepClient := &portmock.KeepClientMock{
+		GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+			return &port.KeepAlert{Fingerprint: fingerprint}, nil
+		},
+		EnrichAlertFunc: func(ctx context.Context, fingerprint string, enrichments map[string]string, opts port.EnrichOptions) error {
+			return nil
+		},
+	}
+	mmClient := &portmock.MattermostClientMock{
+		GetUserFunc: func(ctx context.Context, userID string) (string, error) {
+			return "john", nil
+		},
+	}
+	uc := NewThreadNotesUseCase(postIDStore{postRepo}, &portmock.MattermostReplyEventsMock{}, mmClient, keepClient, "note", 20, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "user-1", Message: "Restarted"})
+	uc.Handle(context.Background(), port.ReplyEvent{PostID: "reply-2", RootID: "post-other", UserID: "user-1", Message: "Hi"})
+
+	require.Len(t, keepClient.EnrichAlertCalls(), 1)
+	assert.Equal(t, "fp-1", keepClient.EnrichAlertCalls()[0].Fingerprint)
+	assert.Empty(t, postRepo.FindAllActiveCalls(), "active posts are not scanned")
+}
+
+func TestThreadNotesUseCase_RunReconnects(t *testing.T) {
+	uc, keepClient, events := setupThreadNotesUseCase("")
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uc.SetClock(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events.ListenRepliesFunc = func(ctx context.Context, handle func(ctx context.Context, event port.ReplyEvent)) error {
+		if len(events.ListenRepliesCalls()) == 1 {
+			return errors.New("connection reset")
+		}
+		handle(ctx, port.ReplyEvent{PostID: "reply-1", RootID: "post-1", UserID: "user-1", Message: "On it"})
+		cancel()
+		return ctx.Err()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		uc.Run(ctx)
+		close(done)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(reactionReconnectDelay)
+	<-done
+
+	assert.Len(t, events.ListenRepliesCalls(), 2)
+	assert.Len(t, keepClient.EnrichAlertCalls(), 1)
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// UnsnoozeAlertsUseCase restores posts whose snooze has ended. The post is
+// re-rendered from current Keep data, so an alert acknowledged while snoozed
+// comes back as acknowledged rather than firing.
+type UnsnoozeAlertsUseCase struct {
+	postRepo    post.Repository
+	keepClient  port.KeepClient
+	mmClient    port.MattermostClient
+	msgBuilder  port.MessageBuilder
+	userMapper  port.UserMapper
+	keepUIURL   string
+	callbackURL string
+	clock       clock.Clock
+	logger      *slog.Logger
+}
+
+func NewUnsnoozeAlertsUseCase(
+	postRepo post.Repository,
+	keepClient port.KeepClient,
+	mmClient port.MattermostClient,
+	msgBuilder port.MessageBuilder,
+	userMapper port.UserMapper,
+	keepUIURL string,
+	callbackURL string,
+	logger *slog.Logger,
+) *UnsnoozeAlertsUseCase {
+	return &UnsnoozeAlertsUseCase{
+		postRepo:    postRepo,
+		keepClient:  keepClient,
+		mmClient:    mmClient,
+		msgBuilder:  msgBuilder,
+		userMapper:  userMapper,
+		keepUIURL:   keepUIURL,
+		callbackURL: callbackURL,
+		clock:       clock.Real(),
+		logger:      logger,
+	}
+}
+
+// SetClock replaces the clock used to decide whether a snooze has ended.
+func (uc *UnsnoozeAlertsUseCase) SetClock(c clock.Clock) {
+	uc.clock = c
+}
+
+// Execute restores every post with an expired snooze. Posts that fail are
+// left snoozed and retried on the next run.
+func (uc *UnsnoozeAlertsUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	now := uc.clock.Now()
+	var errs []error
+	for _, p := range posts {
+		if !p.SnoozeExpired(now) {
+			continue
+		}
+		if err := uc.restore(ctx, p); err != nil {
+			errs = append(errs, fmt.Errorf("unsnooze %s: %w", p.Fingerprint().Value(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (uc *UnsnoozeAlertsUseCase) restore(ctx context.Context, p *post.Post) error {
+	fingerprint := p.Fingerprint()
+
+	keepAlert, err := uc.keepClient.GetAlert(ctx, fingerprint.Value())
+	if err != nil {
+		return fmt.Errorf("get alert from keep: %w", err)
+	}
+
+	severity, err := alert.NewSeverity(keepAlert.Severity)
+	if err != nil {
+		severity = p.Severity()
+	}
+
+	assignee := uc.resolveAssigneeUsername(keepAlert.Enrichments)
+	acknowledged := keepAlert.Status == alert.StatusAcknowledged ||
+		keepAlert.Enrichments[EnrichmentKeyStatus] == alert.StatusAcknowledged
+
+	status := alert.StatusFiring
+	if acknowledged {
+		status = alert.StatusAcknowledged
+	}
+
+	a := alert.RestoreAlert(
+		fingerprint,
+		keepAlert.Name,
+		severity,
+		alert.RestoreStatus(status),
+		keepAlert.Description,
+		strings.Join(keepAlert.Source, ", "),
+		keepAlert.Labels,
+		p.FiringStartTime(),
+	).WithAnnotations(keepAlert.Annotations).WithGeneratorURL(keepAlert.GeneratorURL).WithFireCount(p.FireCount())
+
+	var attachment post.Attachment
+	if acknowledged || assignee != "" {
+		attachment = uc.msgBuilder.ForChannel(p.ChannelID()).BuildAcknowledgedAttachment(a, uc.callbackURL, uc.keepUIURL, assignee)
+	} else {
+		attachment = uc.msgBuilder.ForChannel(p.ChannelID()).BuildFiringAttachment(a, uc.callbackURL, uc.keepUIURL)
+	}
+
+	if err := uc.mmClient.UpdatePost(ctx, p.PostID(), attachment); err != nil {
+		return fmt.Errorf("update post: %w", err)
+	}
+
+	p.Unsnooze()
+	p.Touch(uc.clock.Now())
+	if err := uc.postRepo.Save(ctx, fingerprint, p); err != nil {
+		return fmt.Errorf("update post in store: %w", err)
+	}
+
+	if err := uc.mmClient.ReplyToThread(ctx, p.ChannelID(), p.PostID(), "⏰ Snooze expired"); err != nil {
+		uc.logger.Warn("Failed to reply to thread",
+			slog.String("post_id", p.PostID()),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	uc.logger.Info("Snoozed alert restored",
+		logger.ApplicationFields("alert_unsnoozed",
+			slog.String("fingerprint", fingerprint.Value()),
+			slog.Bool("acknowledged", acknowledged || assignee != ""),
+		),
+	)
+	alertUnsnoozeCounter.Inc()
+
+	return nil
+}
+
+func (uc *UnsnoozeAlertsUseCase) resolveAssigneeUsername(enrichments map[string]string) string {
+	keepUser := enrichments[EnrichmentKeyAssignee]
+	if keepUser == "" {
+		return ""
+	}
+	if mmUser, ok := uc.userMapper.GetMattermostUsername(keepUser); ok {
+		return mmUser
+	}
+	return keepUser
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type unsnoozeFixture struct {
+	uc         *UnsnoozeAlertsUseCase
+	postRepo   *postStore
+	keepClient *portmock.KeepClientMock
+	mmClient   *portmock.MattermostClientMock
+	msgBuilder *portmock.MessageBuilderMock
+	clock      *clock.Fake
+}
+
+func setupUnsnoozeAlertsUseCase(keepAlert *port.KeepAlert) *unsnoozeFixture {
+	f := &unsnoozeFixture{
+		postRepo: newPostStore(),
+		keepClient: &portmock.KeepClientMock{
+			GetAlertFunc: func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+				return keepAlert, nil
+			},
+		},
+		mmClient: &portmock.MattermostClientMock{
+			UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+				return nil
+			},
+			ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+				return nil
+			},
+		},
+		msgBuilder: &portmock.MessageBuilderMock{
+			BuildFiringAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL string) post.Attachment {
+				return post.Attachment{Title: "FIRING: " + a.Name()}
+			},
+			BuildAcknowledgedAttachmentFunc: func(a *alert.Alert, callbackURL, keepUIURL, username string) post.Attachment {
+				return post.Attachment{Title: "ACK: " + a.Name(), Footer: username}
+			},
+		},
+		clock: clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	f.msgBuilder.ForChannelFunc = func(string) port.MessageBuilder { return f.msgBuilder }
+	userMapper := &portmock.UserMapperMock{
+		GetMattermostUsernameFunc: func(keepUsername string) (string, bool) {
+			if keepUsername == "alice@keep" {
+				return "alice", true
+			}
+			return "", false
+		},
+	}
+	f.uc = NewUnsnoozeAlertsUseCase(
+		f.postRepo,
+		f.keepClient,
+		f.mmClient,
+		f.msgBuilder,
+		userMapper,
+		"https://keep.example.com",
+		"https://callback.example.com",
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	f.uc.SetClock(f.clock)
+	return f
+}
+
+func (f *unsnoozeFixture) addPost(fp string, snoozedUntil time.Time) *post.Post {
+	p := post.NewPost("post-"+fp, "channel-1", alert.RestoreFingerprint(fp), "Disk full", alert.RestoreSeverity("high"), f.clock.Now().Add(-time.Hour), time.Now())
+	if !snoozedUntil.IsZero() {
+		p.Snooze(snoozedUntil)
+	}
+	f.postRepo.posts[fp] = p
+	return p
+}
+
+func firingKeepAlert() *port.KeepAlert {
+	return &port.KeepAlert{
+		Fingerprint: "fp-1",
+		Name:        "Disk full",
+		Status:      alert.StatusFiring,
+		Severity:    "critical",
+		Labels:      map[string]string{"host": "db-1"},
+	}
+}
+
+func TestUnsnoozeAlerts_RestoresExpiredToFiring(t *testing.T) {
+	f := setupUnsnoozeAlertsUseCase(firingKeepAlert())
+	now := f.clock.Now()
+	f.addPost("fp-1", now.Add(-time.Second))
+	f.addPost("fp-active", now.Add(time.Hour))
+	f.addPost("fp-never", time.Time{})
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	updates := f.mmClient.UpdatePostCalls()
+	require.Len(t, updates, 1)
+	assert.Equal(t, "post-fp-1", updates[0].PostID)
+	assert.Equal(t, "FIRING: Disk full", updates[0].Attachment.Title)
+
+	firing := f.msgBuilder.BuildFiringAttachmentCalls()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "critical", firing[0].A.Severity().Value())
+	assert.Equal(t, now.Add(-time.Hour), firing[0].A.FiringStartTime(), "firing start comes from the stored post")
+
+	assert.True(t, f.postRepo.posts["fp-1"].SnoozedUntil().IsZero())
+	assert.True(t, f.postRepo.posts["fp-active"].IsSnoozed(now))
+
+	replies := f.mmClient.ReplyToThreadCalls()
+	require.Len(t, replies, 1)
+	assert.Equal(t, "post-fp-1", replies[0].RootID)
+	assert.Contains(t, replies[0].Message, "Snooze expired")
+}
+
+func TestUnsnoozeAlerts_RestoresAcknowledged(t *testing.T) {
+	keepAlert := firingKeepAlert()
+	keepAlert.Enrichments = map[string]string{"status": "acknowledged", "assignee": "alice@keep"}
+	f := setupUnsnoozeAlertsUseCase(keepAlert)
+	f.addPost("fp-1", f.clock.Now())
+
+	require.NoError(t, f.uc.Execute(context.Background()))
+
+	assert.Empty(t, f.msgBuilder.BuildFiringAttachmentCalls())
+	acks := f.msgBuilder.BuildAcknowledgedAttachmentCalls()
+	require.Len(t, acks, 1)
+	assert.Equal(t, "alice", acks[0].Username)
+}
+
+func TestUnsnoozeAlerts_KeepErrorKeepsSnooze(t *testing.T) {
+	f := setupUnsnoozeAlertsUseCase(nil)
+	f.keepClient.GetAlertFunc = func(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
+		return nil, errors.New("keep down")
+	}
+	until := f.clock.Now().Add(-time.Minute)
+	f.addPost("fp-1", until)
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsnooze fp-1")
+	assert.Contains(t, err.Error(), "keep down")
+
+	assert.Empty(t, f.mmClient.UpdatePostCalls())
+	assert.Equal(t, until, f.postRepo.posts["fp-1"].SnoozedUntil(), "retried on the next run")
+}
+
+func TestUnsnoozeAlerts_UpdatePostErrorKeepsSnooze(t *testing.T) {
+	f := setupUnsnoozeAlertsUseCase(firingKeepAlert())
+	f.mmClient.UpdatePostFunc = func(ctx context.Context, postID string, attachment post.Attachment) error {
+		return errors.New("mattermost down")
+	}
+	f.addPost("fp-1", f.clock.Now().Add(-time.Minute))
+
+	require.Error(t, f.uc.Execute(context.Background()))
+	assert.Empty(t, f.postRepo.SaveCalls())
+	assert.False(t, f.postRepo.posts["fp-1"].SnoozedUntil().IsZero())
+}
+
+func TestUnsnoozeAlerts_FindAllActiveError(t *testing.T) {
+	f := setupUnsnoozeAlertsUseCase(firingKeepAlert())
+	repo := &portmock.RepositoryMock{
+		FindAllActiveFunc: func(ctx context.Context) ([]*post.Post, error) {
+			return nil, errors.New("valkey down")
+		},
+	}
+	f.uc.postRepo = repo
+
+	err := f.uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "find all active posts")
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	badgeEmojiActive = "rotating_light"
+	badgeEmojiClear  = "white_check_mark"
+)
+
+// UpdateAlertBadgeUseCase mirrors the number of active critical alerts into
+// the bot's custom status or a channel purpose, so the count is visible
+// without opening the alerts channel.
+type UpdateAlertBadgeUseCase struct {
+	postRepo     post.Repository
+	statusClient port.MattermostStatusClient
+	target       string
+	channelID    string
+	lastBadge    string
+	logger       *slog.Logger
+}
+
+func NewUpdateAlertBadgeUseCase(
+	postRepo post.Repository,
+	statusClient port.MattermostStatusClient,
+	target string,
+	channelID string,
+	logger *slog.Logger,
+) *UpdateAlertBadgeUseCase {
+	return &UpdateAlertBadgeUseCase{
+		postRepo:     postRepo,
+		statusClient: statusClient,
+		target:       target,
+		channelID:    channelID,
+		logger:       logger,
+	}
+}
+
+// Execute recounts active criticals and pushes the badge only when it changed
+// since the last successful update. Not safe for concurrent use.
+func (uc *UpdateAlertBadgeUseCase) Execute(ctx context.Context) error {
+	posts, err := uc.postRepo.FindAllActive(ctx)
+	if err != nil {
+		badgeErrorsCounter.Inc()
+		return fmt.Errorf("find all active posts: %w", err)
+	}
+
+	critical := 0
+	for _, p := range posts {
+		if p.Severity().IsCritical() {
+			critical++
+		}
+	}
+	badgeCriticalGauge.Set(float64(critical))
+
+	emoji, text := badgeContent(critical)
+	badge := emoji + "|" + text
+	if badge == uc.lastBadge {
+		return nil
+	}
+
+	switch uc.target {
+	case port.BadgeTargetChannelPurpose:
+		err = uc.statusClient.SetChannelPurpose(ctx, uc.channelID, fmt.Sprintf(":%s: %s", emoji, text))
+	default:
+		err = uc.statusClient.SetCustomStatus(ctx, emoji, text)
+	}
+	if err != nil {
+		badgeErrorsCounter.Inc()
+		return fmt.Errorf("update %s badge: %w", uc.target, err)
+	}
+
+	uc.lastBadge = badge
+	badgeUpdatesCounter.Inc()
+
+	uc.logger.Info("Alert badge updated",
+		logger.ApplicationFields("badge_updated",
+			slog.String("target", uc.target),
+			slog.Int("critical", critical),
+		),
+	)
+
+	return nil
+}
+
+func badgeContent(critical int) (emoji, text string) {
+	switch critical {
+	case 0:
+		return badgeEmojiClear, "No active critical alerts"
+	case 1:
+		return badgeEmojiActive, "1 active critical alert"
+	default:
+		return badgeEmojiActive, fmt.Sprintf("%d active critical alerts", critical)
+	}
+}
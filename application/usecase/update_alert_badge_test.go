@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func newBadgeStatusClientMock() *portmock.MattermostStatusClientMock {
+	return &portmock.MattermostStatusClientMock{
+		SetCustomStatusFunc: func(ctx context.Context, emoji, text string) error {
+			return nil
+		},
+		SetChannelPurposeFunc: func(ctx context.Context, channelID, purpose string) error {
+			return nil
+		},
+	}
+}
+
+func addBadgeTestPost(t *testing.T, repo *postStore, fingerprint, severity string) {
+	t.Helper()
+	fp, err := alert.NewFingerprint(fingerprint)
+	require.NoError(t, err)
+	repo.posts[fingerprint] = post.NewPost("post-"+fingerprint, "channel-1", fp, "Alert", alert.RestoreSeverity(severity), time.Now(), time.Now())
+}
+
+func TestUpdateAlertBadge_CustomStatus(t *testing.T) {
+	repo := newPostStore()
+	addBadgeTestPost(t, repo, "fp-1", "critical")
+	addBadgeTestPost(t, repo, "fp-2", "critical")
+	addBadgeTestPost(t, repo, "fp-3", "warning")
+	client := newBadgeStatusClientMock()
+
+	uc := NewUpdateAlertBadgeUseCase(repo, client, port.BadgeTargetStatus, "", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, uc.Execute(context.Background()))
+	require.Len(t, client.SetCustomStatusCalls(), 1)
+	assert.Equal(t, "rotating_light", client.SetCustomStatusCalls()[0].Emoji)
+	assert.Equal(t, "2 active critical alerts", client.SetCustomStatusCalls()[0].Text)
+	assert.Empty(t, client.SetChannelPurposeCalls())
+}
+
+func TestUpdateAlertBadge_ChannelPurpose(t *testing.T) {
+	repo := newPostStore()
+	addBadgeTestPost(t, repo, "fp-1", "critical")
+	client := newBadgeStatusClientMock()
+
+	uc := NewUpdateAlertBadgeUseCase(repo, client, port.BadgeTargetChannelPurpose, "ops-channel", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, uc.Execute(context.Background()))
+	require.Len(t, client.SetChannelPurposeCalls(), 1)
+	assert.Equal(t, "ops-channel", client.SetChannelPurposeCalls()[0].ChannelID)
+	assert.Equal(t, ":rotating_light: 1 active critical alert", client.SetChannelPurposeCalls()[0].Purpose)
+	assert.Empty(t, client.SetCustomStatusCalls())
+}
+
+func TestUpdateAlertBadge_SkipsUnchanged(t *testing.T) {
+	repo := newPostStore()
+	client := newBadgeStatusClientMock()
+
+	uc := NewUpdateAlertBadgeUseCase(repo, client, port.BadgeTargetStatus, "", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, uc.Execute(context.Background()))
+	require.NoError(t, uc.Execute(context.Background()))
+	require.Len(t, client.SetCustomStatusCalls(), 1)
+	assert.Equal(t, "No active critical alerts", client.SetCustomStatusCalls()[0].Text)
+
+	addBadgeTestPost(t, repo, "fp-1", "critical")
+	require.NoError(t, uc.Execute(context.Background()))
+	assert.Len(t, client.SetCustomStatusCalls(), 2)
+}
+
+func TestUpdateAlertBadge_ClientErrorRetriesNextRun(t *testing.T) {
+	repo := newPostStore()
+	client := newBadgeStatusClientMock()
+	client.SetCustomStatusFunc = func(ctx context.Context, emoji, text string) error {
+		return errors.New("mattermost down")
+	}
+
+	uc := NewUpdateAlertBadgeUseCase(repo, client, port.BadgeTargetStatus, "", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := uc.Execute(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "update status badge")
+
+	client.SetCustomStatusFunc = func(ctx context.Context, emoji, text string) error { return nil }
+	require.NoError(t, uc.Execute(context.Background()))
+	assert.Len(t, client.SetCustomStatusCalls(), 2)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// UpdateCoalescer merges bursts of updates to the same Mattermost post, e.g.
+// an alert that re-fires many times during a storm. The first update of a post
+// is sent at once and opens a window; updates that arrive within the window
+// are held and only the last one is sent when it closes. Every held caller
+// waits for that send and gets its result.
+type UpdateCoalescer struct {
+	window time.Duration
+	clock  clock.Clock
+
+	mu    sync.Mutex
+	posts map[string]*coalescedPost
+
+	holds     sync.WaitGroup // running hold goroutines
+	flushing  chan struct{}  // closed by Flush
+	flushOnce sync.Once
+}
+
+// coalescedPost is a post whose update window is open.
+type coalescedPost struct {
+	pending *pendingUpdate
+}
+
+type pendingUpdate struct {
+	ctx        context.Context
+	attachment post.Attachment
+	done       chan struct{}
+	err        error
+}
+
+func NewUpdateCoalescer(window time.Duration) *UpdateCoalescer {
+	return &UpdateCoalescer{
+		window:   window,
+		clock:    clock.Real(),
+		posts:    make(map[string]*coalescedPost),
+		flushing: make(chan struct{}),
+	}
+}
+
+// SetClock replaces the clock that times the update windows.
+func (c *UpdateCoalescer) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// WrapClient returns a client whose post updates are coalesced.
+func (c *UpdateCoalescer) WrapClient(client port.MattermostClient) port.MattermostClient {
+	return &coalescingClient{MattermostClient: client, coalescer: c}
+}
+
+type coalescingClient struct {
+	port.MattermostClient
+	coalescer *UpdateCoalescer
+}
+
+func (cc *coalescingClient) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	c := cc.coalescer
+	c.mu.Lock()
+	p, open := c.posts[postID]
+	if !open {
+		c.posts[postID] = &coalescedPost{}
+		c.holds.Add(1)
+		c.mu.Unlock()
+		err := cc.MattermostClient.UpdatePost(ctx, postID, attachment)
+		// The window opens once the update is sent, so a held update can
+		// never overtake it.
+		go c.hold(cc.MattermostClient, postID)
+		return err
+	}
+
+	if p.pending == nil {
+		p.pending = &pendingUpdate{done: make(chan struct{})}
+	} else {
+		updatesCoalescedCounter.Inc()
+	}
+	pending := p.pending
+	pending.ctx = ctx
+	pending.attachment = attachment
+	c.mu.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hold keeps the window of postID open, sending the last held update each
+// time it closes, until a window passes without updates.
+func (c *UpdateCoalescer) hold(client port.MattermostClient, postID string) {
+	defer c.holds.Done()
+	for {
+		select {
+		case <-c.clock.After(c.window):
+		case <-c.flushing:
+		}
+
+		c.mu.Lock()
+		p := c.posts[postID]
+		pending := p.pending
+		if pending == nil {
+			delete(c.posts, postID)
+			c.mu.Unlock()
+			return
+		}
+		p.pending = nil
+		c.mu.Unlock()
+
+		// The held callers may have given up; the update is still due.
+		pending.err = client.UpdatePost(context.WithoutCancel(pending.ctx), postID, pending.attachment)
+		close(pending.done)
+	}
+}
+
+// Flush closes every open window, sending the held updates at once, and waits
+// until they are sent or ctx ends. Windows opened afterwards close as soon as
+// their first update is sent, so nothing is held any more.
+func (c *UpdateCoalescer) Flush(ctx context.Context) error {
+	c.flushOnce.Do(func() { close(c.flushing) })
+
+	done := make(chan struct{})
+	go func() {
+		c.holds.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
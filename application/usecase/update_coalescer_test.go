@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func TestUpdateCoalescer(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	updateErr := errors.New("mattermost update post: status 502")
+	inner := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, postID+"="+attachment.Title)
+			if attachment.Title == "broken" {
+				return updateErr
+			}
+			return nil
+		},
+	}
+	sentUpdates := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), sent...)
+	}
+
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	coalescer := NewUpdateCoalescer(time.Second)
+	coalescer.SetClock(fake)
+	client := coalescer.WrapClient(inner)
+	ctx := context.Background()
+
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "first"}))
+	require.NoError(t, client.UpdatePost(ctx, "post-2", post.Attachment{Title: "other"}))
+	assert.Equal(t, []string{"post-1=first", "post-2=other"}, sentUpdates(), "the first update of a post is sent at once")
+	fake.BlockUntil(2)
+
+	var wg sync.WaitGroup
+	results := make([]error, 3)
+	for i, title := range []string{"second", "third", "broken"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = client.UpdatePost(ctx, "post-1", post.Attachment{Title: title})
+		}()
+		// Keep the arrival order so the last write is known.
+		require.Eventually(t, func() bool {
+			coalescer.mu.Lock()
+			defer coalescer.mu.Unlock()
+			p := coalescer.posts["post-1"].pending
+			return p != nil && p.attachment.Title == title
+		}, time.Second, time.Millisecond)
+	}
+	assert.Len(t, sentUpdates(), 2, "updates inside the window are held")
+
+	fake.Advance(time.Second)
+	wg.Wait()
+	assert.Equal(t, []string{"post-1=first", "post-2=other", "post-1=broken"}, sentUpdates(), "only the last held update is sent")
+	for _, err := range results {
+		assert.ErrorIs(t, err, updateErr, "every held caller gets the result of the send")
+	}
+
+	// A window without updates closes the post; the next update is sent at once.
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool {
+		coalescer.mu.Lock()
+		defer coalescer.mu.Unlock()
+		return len(coalescer.posts) == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "later"}))
+	assert.Equal(t, "post-1=later", sentUpdates()[3])
+}
+
+func TestUpdateCoalescerCanceledCaller(t *testing.T) {
+	inner := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return ctx.Err()
+		},
+	}
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	coalescer := NewUpdateCoalescer(time.Second)
+	coalescer.SetClock(fake)
+	client := coalescer.WrapClient(inner)
+
+	require.NoError(t, client.UpdatePost(context.Background(), "post-1", post.Attachment{Title: "first"}))
+	fake.BlockUntil(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	assert.ErrorIs(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "second"}), context.Canceled)
+
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool { return len(inner.UpdatePostCalls()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, "second", inner.UpdatePostCalls()[1].Attachment.Title, "the update is still sent")
+}
+
+func TestUpdateCoalescerFlush(t *testing.T) {
+	inner := &portmock.MattermostClientMock{
+		UpdatePostFunc: func(ctx context.Context, postID string, attachment post.Attachment) error {
+			return nil
+		},
+	}
+	fake := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	coalescer := NewUpdateCoalescer(time.Minute)
+	coalescer.SetClock(fake)
+	client := coalescer.WrapClient(inner)
+	ctx := context.Background()
+
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "first"}))
+	fake.BlockUntil(1)
+
+	held := make(chan error, 1)
+	go func() { held <- client.UpdatePost(ctx, "post-1", post.Attachment{Title: "second"}) }()
+	require.Eventually(t, func() bool {
+		coalescer.mu.Lock()
+		defer coalescer.mu.Unlock()
+		return coalescer.posts["post-1"].pending != nil
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, coalescer.Flush(ctx), "held updates are sent without waiting for the window")
+	require.NoError(t, <-held)
+	require.Len(t, inner.UpdatePostCalls(), 2)
+	assert.Equal(t, "second", inner.UpdatePostCalls()[1].Attachment.Title)
+	assert.Empty(t, coalescer.posts)
+
+	require.NoError(t, client.UpdatePost(ctx, "post-1", post.Attachment{Title: "after"}))
+	require.NoError(t, coalescer.Flush(ctx), "windows opened after a flush close at once")
+	assert.Len(t, inner.UpdatePostCalls(), 3)
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// UserAutoMapping maps Keep users to the Mattermost users with the same
+// email. The mapping is rebuilt by Refresh; lookups never call an API, and
+// users without a match fall back to the static mapping.
+type UserAutoMapping struct {
+	keepUsers port.KeepUserClient
+	directory port.MattermostUserDirectory
+	static    port.UserMapper
+	cache     port.UserEmailCache
+	cacheTTL  time.Duration
+	logger    *slog.Logger
+	mu        sync.RWMutex
+	keepByMM  map[string]string
+	mmByKeep  map[string]string
+	mmByEmail map[string]string
+}
+
+func NewUserAutoMapping(
+	keepUsers port.KeepUserClient,
+	directory port.MattermostUserDirectory,
+	static port.UserMapper,
+	logger *slog.Logger,
+) *UserAutoMapping {
+	return &UserAutoMapping{
+		keepUsers: keepUsers,
+		directory: directory,
+		static:    static,
+		logger:    logger,
+	}
+}
+
+// SetCache keeps email lookups for ttl, so refreshes only look up emails not
+// seen within ttl. Without a cache every refresh looks up every Keep user.
+func (m *UserAutoMapping) SetCache(cache port.UserEmailCache, ttl time.Duration) {
+	m.cache = cache
+	m.cacheTTL = ttl
+}
+
+// Refresh lists the Keep users and finds the Mattermost user of each email.
+// An email whose lookup fails keeps the user it was mapped to before.
+func (m *UserAutoMapping) Refresh(ctx context.Context) error {
+	users, err := m.keepUsers.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("get keep users: %w", err)
+	}
+
+	m.mu.RLock()
+	previous := m.mmByEmail
+	m.mu.RUnlock()
+
+	keepByMM := make(map[string]string, len(users))
+	mmByKeep := make(map[string]string, len(users))
+	mmByEmail := make(map[string]string, len(users))
+	var failed int
+	for _, u := range users {
+		email := strings.ToLower(strings.TrimSpace(u.Email))
+		if !strings.Contains(email, "@") {
+			continue
+		}
+		username, err := m.mattermostUsername(ctx, email)
+		if err != nil {
+			failed++
+			m.logger.Warn("Failed to look up Mattermost user by email",
+				slog.String("email", email),
+				slog.String("error", err.Error()),
+			)
+			username = previous[email]
+		}
+		if username == "" {
+			continue
+		}
+		mmByEmail[email] = username
+		keepByMM[username] = u.Email
+		mmByKeep[strings.ToLower(u.Email)] = username
+	}
+
+	m.mu.Lock()
+	m.keepByMM, m.mmByKeep, m.mmByEmail = keepByMM, mmByKeep, mmByEmail
+	m.mu.Unlock()
+
+	m.logger.Info("User auto-mapping refreshed",
+		logger.ApplicationFields("user_auto_mapping_refreshed",
+			slog.Int("keep_users", len(users)),
+			slog.Int("mapped", len(mmByEmail)),
+			slog.Int("failed", failed),
+		),
+	)
+	return nil
+}
+
+// mattermostUsername returns the username of the Mattermost user with email,
+// or "" when there is none.
+func (m *UserAutoMapping) mattermostUsername(ctx context.Context, email string) (string, error) {
+	if m.cache != nil {
+		username, ok, err := m.cache.GetUsername(ctx, email)
+		if err != nil {
+			m.logger.Warn("Failed to read user email cache",
+				slog.String("email", email),
+				slog.String("error", err.Error()),
+			)
+		} else if ok {
+			return username, nil
+		}
+	}
+
+	username, err := m.directory.GetUsernameByEmail(ctx, email)
+	if errors.Is(err, port.ErrMattermostUserNotFound) {
+		username, err = "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if m.cache != nil {
+		if err := m.cache.SetUsername(ctx, email, username, m.cacheTTL); err != nil {
+			m.logger.Warn("Failed to write user email cache",
+				slog.String("email", email),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return username, nil
+}
+
+func (m *UserAutoMapping) GetKeepUsername(mattermostUsername string) (string, bool) {
+	m.mu.RLock()
+	keepUser, ok := m.keepByMM[mattermostUsername]
+	m.mu.RUnlock()
+	if ok {
+		return keepUser, true
+	}
+	return m.static.GetKeepUsername(mattermostUsername)
+}
+
+func (m *UserAutoMapping) GetMattermostUsername(keepUsername string) (string, bool) {
+	m.mu.RLock()
+	mmUser, ok := m.mmByKeep[strings.ToLower(keepUsername)]
+	m.mu.RUnlock()
+	if ok {
+		return mmUser, true
+	}
+	return m.static.GetMattermostUsername(keepUsername)
+}
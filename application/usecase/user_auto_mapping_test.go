@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+)
+
+func newTestUserAutoMapping(users []port.KeepUser, mattermost map[string]string) (*UserAutoMapping, *portmock.MattermostUserDirectoryMock) {
+	keepUsers := &portmock.KeepUserClientMock{
+		GetUsersFunc: func(ctx context.Context) ([]port.KeepUser, error) { return users, nil },
+	}
+	directory := &portmock.MattermostUserDirectoryMock{
+		GetUsernameByEmailFunc: func(ctx context.Context, email string) (string, error) {
+			if username, ok := mattermost[email]; ok {
+				return username, nil
+			}
+			return "", port.ErrMattermostUserNotFound
+		},
+	}
+	static := &portmock.UserMapperMock{
+		GetKeepUsernameFunc: func(mattermostUsername string) (string, bool) {
+			if mattermostUsername == "carol" {
+				return "carol_keep", true
+			}
+			return "", false
+		},
+		GetMattermostUsernameFunc: func(keepUsername string) (string, bool) {
+			if keepUsername == "carol_keep" {
+				return "carol", true
+			}
+			return "", false
+		},
+	}
+	return NewUserAutoMapping(keepUsers, directory, static, slog.New(slog.NewTextHandler(io.Discard, nil))), directory
+}
+
+func TestUserAutoMapping_Refresh(t *testing.T) {
+	m, _ := newTestUserAutoMapping(
+		[]port.KeepUser{{Email: "Alice@Example.com", Name: "Alice"}, {Email: "bob@example.com"}, {Email: "admin"}},
+		map[string]string{"alice@example.com": "alice"},
+	)
+
+	_, ok := m.GetKeepUsername("alice")
+	assert.False(t, ok, "nothing is mapped before the first refresh")
+
+	require.NoError(t, m.Refresh(context.Background()))
+
+	keepUser, ok := m.GetKeepUsername("alice")
+	assert.True(t, ok)
+	assert.Equal(t, "Alice@Example.com", keepUser, "Keep gets the email as Keep lists it")
+	mmUser, ok := m.GetMattermostUsername("alice@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", mmUser)
+
+	_, ok = m.GetMattermostUsername("bob@example.com")
+	assert.False(t, ok, "no Mattermost user has the email")
+
+	keepUser, ok = m.GetKeepUsername("carol")
+	assert.True(t, ok)
+	assert.Equal(t, "carol_keep", keepUser, "static mapping is the fallback")
+	mmUser, ok = m.GetMattermostUsername("carol_keep")
+	assert.True(t, ok)
+	assert.Equal(t, "carol", mmUser)
+}
+
+func TestUserAutoMapping_RefreshKeepsMappingOnLookupFailure(t *testing.T) {
+	m, directory := newTestUserAutoMapping(
+		[]port.KeepUser{{Email: "alice@example.com"}},
+		map[string]string{"alice@example.com": "alice"},
+	)
+	require.NoError(t, m.Refresh(context.Background()))
+
+	directory.GetUsernameByEmailFunc = func(ctx context.Context, email string) (string, error) {
+		return "", errors.New("mattermost down")
+	}
+	require.NoError(t, m.Refresh(context.Background()))
+
+	keepUser, ok := m.GetKeepUsername("alice")
+	assert.True(t, ok)
+	assert.Equal(t, "alice@example.com", keepUser)
+}
+
+func TestUserAutoMapping_RefreshKeepError(t *testing.T) {
+	m, _ := newTestUserAutoMapping(nil, nil)
+	m.keepUsers = &portmock.KeepUserClientMock{
+		GetUsersFunc: func(ctx context.Context) ([]port.KeepUser, error) { return nil, errors.New("forbidden") },
+	}
+
+	err := m.Refresh(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get keep users")
+}
+
+func TestUserAutoMapping_Cache(t *testing.T) {
+	m, directory := newTestUserAutoMapping(
+		[]port.KeepUser{{Email: "alice@example.com"}, {Email: "bob@example.com"}},
+		map[string]string{"alice@example.com": "alice"},
+	)
+	cached := map[string]string{}
+	cache := &portmock.UserEmailCacheMock{
+		GetUsernameFunc: func(ctx context.Context, email string) (string, bool, error) {
+			username, ok := cached[email]
+			return username, ok, nil
+		},
+		SetUsernameFunc: func(ctx context.Context, email, username string, ttl time.Duration) error {
+			assert.Equal(t, time.Hour, ttl)
+			cached[email] = username
+			return nil
+		},
+	}
+	m.SetCache(cache, time.Hour)
+
+	require.NoError(t, m.Refresh(context.Background()))
+	assert.Equal(t, map[string]string{"alice@example.com": "alice", "bob@example.com": ""}, cached)
+	assert.Len(t, directory.GetUsernameByEmailCalls(), 2)
+
+	require.NoError(t, m.Refresh(context.Background()))
+	assert.Len(t, directory.GetUsernameByEmailCalls(), 2, "cached emails, found or not, are not looked up again")
+	_, ok := m.GetKeepUsername("alice")
+	assert.True(t, ok)
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// workflowRun is a workflow started from the menu whose outcome is not yet
+// reported.
+type workflowRun struct {
+	workflow    port.Workflow
+	executionID string
+	channelID   string
+	postID      string
+	username    string
+	startedAt   time.Time
+}
+
+// WorkflowMenu offers Keep workflows in the Run workflow menu of alert posts,
+// runs the picked one for the alert and reports its outcome in the post's
+// thread. Runs being followed are kept in memory, so a restart drops them
+// without a result reply.
+type WorkflowMenu struct {
+	keepClient port.KeepClient
+	runner     port.KeepWorkflowRunner
+	mmClient   port.MattermostClient
+	allowed    []string // workflow IDs or names; empty offers every workflow
+	timeout    time.Duration
+	clock      clock.Clock
+	logger     *slog.Logger
+
+	mu        sync.RWMutex
+	workflows []port.Workflow
+	pending   []workflowRun
+}
+
+func NewWorkflowMenu(
+	keepClient port.KeepClient,
+	runner port.KeepWorkflowRunner,
+	mmClient port.MattermostClient,
+	allowed []string,
+	timeout time.Duration,
+	logger *slog.Logger,
+) *WorkflowMenu {
+	return &WorkflowMenu{
+		keepClient: keepClient,
+		runner:     runner,
+		mmClient:   mmClient,
+		allowed:    allowed,
+		timeout:    timeout,
+		clock:      clock.Real(),
+		logger:     logger,
+	}
+}
+
+// SetClock replaces the clock that times runs out.
+func (m *WorkflowMenu) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// Workflows returns the workflows offered in the menu.
+func (m *WorkflowMenu) Workflows() []port.Workflow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.workflows
+}
+
+// Workflow returns the offered workflow with the given ID.
+func (m *WorkflowMenu) Workflow(id string) (port.Workflow, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.workflows {
+		if w.ID == id {
+			return w, true
+		}
+	}
+	return port.Workflow{}, false
+}
+
+// Refresh reloads the offered workflows from Keep: the allowed ones in the
+// configured order, or every workflow by name when none are configured.
+// Disabled workflows and the bridge's own are never offered. On failure the
+// previous list is kept.
+func (m *WorkflowMenu) Refresh(ctx context.Context) error {
+	keepWorkflows, err := m.keepClient.GetWorkflows(ctx)
+	if err != nil {
+		return fmt.Errorf("get workflows: %w", err)
+	}
+
+	var workflows []port.Workflow
+	if len(m.allowed) > 0 {
+		for _, name := range m.allowed {
+			for _, w := range keepWorkflows {
+				if offered(w) && (w.ID == name || w.Name == name || w.WorkflowRawID == name) {
+					workflows = append(workflows, port.Workflow{ID: w.ID, Name: w.Name})
+					break
+				}
+			}
+		}
+	} else {
+		for _, w := range keepWorkflows {
+			if offered(w) {
+				workflows = append(workflows, port.Workflow{ID: w.ID, Name: w.Name})
+			}
+		}
+		slices.SortFunc(workflows, func(a, b port.Workflow) int {
+			return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+		})
+	}
+
+	m.mu.Lock()
+	m.workflows = workflows
+	m.mu.Unlock()
+	return nil
+}
+
+func offered(w port.KeepWorkflow) bool {
+	return !w.Disabled && w.WorkflowRawID != workflowRawID
+}
+
+// Run starts workflow for a and follows the run until CheckResults reports
+// its outcome in the thread of postID. It returns the execution ID.
+func (m *WorkflowMenu) Run(ctx context.Context, workflow port.Workflow, a port.KeepAlert, channelID, postID, username string) (string, error) {
+	executionID, err := m.runner.RunAlertWorkflow(ctx, workflow.ID, a)
+	if err != nil {
+		workflowMenuRunsCounter("error").Inc()
+		return "", fmt.Errorf("run keep workflow %s: %w", workflow.ID, err)
+	}
+
+	m.mu.Lock()
+	m.pending = append(m.pending, workflowRun{
+		workflow:    workflow,
+		executionID: executionID,
+		channelID:   channelID,
+		postID:      postID,
+		username:    username,
+		startedAt:   m.clock.Now(),
+	})
+	workflowMenuPendingGauge.Set(float64(len(m.pending)))
+	m.mu.Unlock()
+
+	m.logger.Info("Workflow run from menu",
+		logger.ApplicationFields("workflow_menu_run",
+			slog.String("workflow_id", workflow.ID),
+			slog.String("execution_id", executionID),
+			slog.String("fingerprint", a.Fingerprint),
+			slog.String("username", username),
+		),
+	)
+	workflowMenuRunsCounter("ok").Inc()
+	return executionID, nil
+}
+
+// CheckResults asks Keep how the followed runs are doing and replies in the
+// thread of each run that ended or has run longer than the result timeout.
+// A run whose status cannot be read is checked again next time.
+func (m *WorkflowMenu) CheckResults(ctx context.Context) error {
+	m.mu.Lock()
+	runs := m.pending
+	m.pending = nil
+	m.mu.Unlock()
+
+	var remaining []workflowRun
+	for _, run := range runs {
+		if ctx.Err() != nil {
+			remaining = append(remaining, run)
+			continue
+		}
+		if !m.checkResult(ctx, run) {
+			remaining = append(remaining, run)
+		}
+	}
+
+	m.mu.Lock()
+	m.pending = append(remaining, m.pending...)
+	workflowMenuPendingGauge.Set(float64(len(m.pending)))
+	m.mu.Unlock()
+	return nil
+}
+
+// checkResult reports whether run is done with.
+func (m *WorkflowMenu) checkResult(ctx context.Context, run workflowRun) bool {
+	execution, err := m.runner.GetWorkflowExecution(ctx, run.workflow.ID, run.executionID)
+	timedOut := m.clock.Now().Sub(run.startedAt) >= m.timeout
+	if err != nil {
+		m.logger.Warn("Failed to get workflow execution from Keep",
+			slog.String("workflow_id", run.workflow.ID),
+			slog.String("execution_id", run.executionID),
+			slog.String("error", err.Error()),
+		)
+		if !timedOut {
+			return false
+		}
+		execution.Status = port.WorkflowExecutionInProgress
+	}
+
+	var msg, status string
+	switch {
+	case execution.Status == port.WorkflowExecutionSuccess:
+		status = "success"
+		msg = fmt.Sprintf("✅ Workflow **%s** run by @%s succeeded", run.workflow.Name, run.username)
+	case execution.Status == port.WorkflowExecutionInProgress || execution.Status == "":
+		if !timedOut {
+			return false
+		}
+		status = "timeout"
+		msg = fmt.Sprintf("⌛ Workflow **%s** run by @%s is still running after %s (execution %s)", run.workflow.Name, run.username, m.timeout, run.executionID)
+	default:
+		status = "error"
+		msg = fmt.Sprintf("❌ Workflow **%s** run by @%s failed", run.workflow.Name, run.username)
+		if execution.Error != "" {
+			msg += ": " + execution.Error
+		}
+	}
+
+	if err := m.mmClient.ReplyToThread(ctx, run.channelID, run.postID, msg); err != nil {
+		m.logger.Error("Failed to reply to thread",
+			slog.String("post_id", run.postID),
+			slog.String("error", err.Error()),
+		)
+		return timedOut
+	}
+	workflowMenuResultsCounter(status).Inc()
+	return true
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func workflowMenuKeepClient(workflows []port.KeepWorkflow, err error) *portmock.KeepClientMock {
+	return &portmock.KeepClientMock{
+		GetWorkflowsFunc: func(ctx context.Context) ([]port.KeepWorkflow, error) {
+			return workflows, err
+		},
+	}
+}
+
+func TestWorkflowMenuRefresh(t *testing.T) {
+	keepWorkflows := []port.KeepWorkflow{
+		{ID: "wf-3", Name: "Restart pod", WorkflowRawID: "restart-pod"},
+		{ID: "wf-1", Name: "Collect logs", WorkflowRawID: "collect-logs"},
+		{ID: "wf-2", Name: "Old", WorkflowRawID: "old", Disabled: true},
+		{ID: "wf-0", Name: "Bridge", WorkflowRawID: workflowRawID},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	t.Run("every workflow by name", func(t *testing.T) {
+		menu := NewWorkflowMenu(workflowMenuKeepClient(keepWorkflows, nil), nil, nil, nil, time.Minute, logger)
+		require.NoError(t, menu.Refresh(ctx))
+		assert.Equal(t, []port.Workflow{{ID: "wf-1", Name: "Collect logs"}, {ID: "wf-3", Name: "Restart pod"}}, menu.Workflows())
+
+		w, ok := menu.Workflow("wf-3")
+		assert.True(t, ok)
+		assert.Equal(t, "Restart pod", w.Name)
+		_, ok = menu.Workflow("wf-2")
+		assert.False(t, ok, "disabled workflows are not offered")
+	})
+
+	t.Run("configured workflows in order", func(t *testing.T) {
+		allowed := []string{"restart-pod", "Old", "missing", "wf-1", workflowRawID}
+		menu := NewWorkflowMenu(workflowMenuKeepClient(keepWorkflows, nil), nil, nil, allowed, time.Minute, logger)
+		require.NoError(t, menu.Refresh(ctx))
+		assert.Equal(t, []port.Workflow{{ID: "wf-3", Name: "Restart pod"}, {ID: "wf-1", Name: "Collect logs"}}, menu.Workflows())
+	})
+
+	t.Run("failure keeps the previous list", func(t *testing.T) {
+		keepClient := workflowMenuKeepClient(keepWorkflows, nil)
+		menu := NewWorkflowMenu(keepClient, nil, nil, nil, time.Minute, logger)
+		require.NoError(t, menu.Refresh(ctx))
+
+		keepClient.GetWorkflowsFunc = func(ctx context.Context) ([]port.KeepWorkflow, error) {
+			return nil, errors.New("keep down")
+		}
+		require.Error(t, menu.Refresh(ctx))
+		assert.Len(t, menu.Workflows(), 2)
+	})
+}
+
+func TestWorkflowMenuCheckResults(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	statuses := map[string]port.KeepWorkflowExecution{
+		"exec-1": {Status: port.WorkflowExecutionInProgress},
+		"exec-2": {Status: port.WorkflowExecutionInProgress},
+		"exec-3": {Status: port.WorkflowExecutionInProgress},
+	}
+	runner := &portmock.KeepWorkflowRunnerMock{
+		RunAlertWorkflowFunc: func(ctx context.Context, workflowID string, a port.KeepAlert) (string, error) {
+			return "exec-" + a.Fingerprint, nil
+		},
+		GetWorkflowExecutionFunc: func(ctx context.Context, workflowID, executionID string) (port.KeepWorkflowExecution, error) {
+			return statuses[executionID], nil
+		},
+	}
+	var replies []string
+	mmClient := &portmock.MattermostClientMock{
+		ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+			replies = append(replies, rootID+": "+message)
+			return nil
+		},
+	}
+	menu := NewWorkflowMenu(nil, runner, mmClient, nil, 2*time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	menu.SetClock(fakeClock)
+	ctx := context.Background()
+	restart := port.Workflow{ID: "wf-1", Name: "Restart pod"}
+
+	for _, fp := range []string{"1", "2", "3"} {
+		executionID, err := menu.Run(ctx, restart, port.KeepAlert{Fingerprint: fp}, "channel-1", "post-"+fp, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "exec-"+fp, executionID)
+	}
+
+	require.NoError(t, menu.CheckResults(ctx))
+	assert.Empty(t, replies, "running workflows are not reported")
+
+	statuses["exec-1"] = port.KeepWorkflowExecution{Status: port.WorkflowExecutionSuccess}
+	statuses["exec-2"] = port.KeepWorkflowExecution{Status: "error", Error: "pod not found"}
+	require.NoError(t, menu.CheckResults(ctx))
+	assert.Equal(t, []string{
+		"post-1: ✅ Workflow **Restart pod** run by @alice succeeded",
+		"post-2: ❌ Workflow **Restart pod** run by @alice failed: pod not found",
+	}, replies)
+
+	replies = nil
+	fakeClock.Advance(2 * time.Minute)
+	require.NoError(t, menu.CheckResults(ctx))
+	assert.Equal(t, []string{"post-3: ⌛ Workflow **Restart pod** run by @alice is still running after 2m0s (execution exec-3)"}, replies)
+
+	replies = nil
+	require.NoError(t, menu.CheckResults(ctx))
+	assert.Empty(t, replies, "each run is reported once")
+	assert.Len(t, runner.GetWorkflowExecutionCalls(), 7)
+}
@@ -0,0 +1,1620 @@
+// Package bridge assembles the Keep ↔ Mattermost bridge (repository, clients,
+// use cases, HTTP router and background jobs) so it can be embedded in other
+// Go programs. cmd/server is a thin wrapper around it.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/usecase"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/keep"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/mattermost"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/messagebuilder"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/postgres"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/slack"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/storage"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/teams"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/telegram"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/valkey"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/webhook"
+	httpInterface "github.com/alexmorbo/keep-mattermost-bridge/interface/http"
+	"github.com/alexmorbo/keep-mattermost-bridge/interface/http/handler"
+	"github.com/alexmorbo/keep-mattermost-bridge/interface/http/middleware"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/breaker"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/ratelimit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+// PostRepository is the storage the bridge needs: post mappings plus a health
+// check used by the readiness probe.
+type PostRepository interface {
+	post.Repository
+	handler.HealthChecker
+}
+
+// postExpiryStore is a post repository that can expire posts per severity,
+// as the Valkey one does.
+type postExpiryStore interface {
+	post.ExpiryStore
+	SetSeverityTTLs(defaultTTL time.Duration, severities map[string]time.Duration)
+}
+
+// job is a periodic background task started by Run.
+type job struct {
+	name      string
+	interval  time.Duration
+	timeout   time.Duration
+	immediate bool // run once before the first tick
+	run       func(ctx context.Context) error
+}
+
+type Bridge struct {
+	cfg     *config.Config
+	fileCfg *config.FileConfig
+	log     *slog.Logger
+	clock   clock.Clock
+
+	postRepo         PostRepository
+	groupRepo        group.Repository
+	correlationRepo  correlation.Repository
+	retentionRepo    retention.Repository
+	deadLetterRepo   deadletter.Repository
+	quietHoursRepo   quiethours.Repository
+	auditRepo        audit.Repository
+	incidentRepo     incident.Repository
+	pendingRepo      pendingaction.Repository
+	statusBoardStore port.StatusBoardStore
+	alertStream      port.AlertStream
+	locker           port.Locker
+	idempotency      port.IdempotencyStore
+	redisClient      *redis.Client // nil when the repository was supplied via WithPostRepository
+	storageBackend   string        // set when New opened a built-in backend other than Valkey
+	boltRepo         *storage.BoltPostRepository
+	pgPool           *pgxpool.Pool // set when STORAGE_BACKEND is postgres
+	keepClient       *keep.Client
+	routes           []func(router *gin.Engine)
+
+	router           *gin.Engine
+	handleCallbackUC *usecase.HandleCallbackUseCase
+	handleIncidentUC *usecase.HandleIncidentUseCase     // nil unless incidents are enabled
+	slashCommandUC   *usecase.HandleSlashCommandUseCase // nil unless the slash command is enabled
+	alertQueue       *usecase.AlertQueue                // nil unless the ingest queue is enabled
+	streamConsumer   *usecase.AlertStreamConsumer       // nil unless INGEST_MODE is stream
+	reconcileUC      *usecase.ReconcileUseCase          // nil unless reconciliation on start is enabled
+	coalescer        *usecase.UpdateCoalescer           // nil unless update coalescing is enabled
+	reactionActions  *usecase.ReactionActionsUseCase    // nil unless reaction actions are enabled
+	threadNotes      *usecase.ThreadNotesUseCase        // nil unless thread notes are enabled
+	jobs             []job
+	tenants          []*Bridge
+}
+
+// New wires the bridge from already loaded and validated configuration.
+// Unless WithPostRepository is given, it connects to Valkey and fails if the
+// server is unreachable.
+func New(cfg *config.Config, fileCfg *config.FileConfig, opts ...Option) (*Bridge, error) {
+	b := &Bridge{
+		cfg:     cfg,
+		fileCfg: fileCfg,
+		log:     slog.Default(),
+		clock:   clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.postRepo == nil {
+		if err := b.openStorage(); err != nil {
+			return nil, err
+		}
+	}
+
+	b.keepClient = keep.NewClient(cfg.Keep.URL, cfg.Keep.APIKey, b.log.With("component", "keep_client"))
+	if err := b.keepClient.SetOutbound(cfg.Keep.Outbound); err != nil {
+		_ = b.Close()
+		return nil, fmt.Errorf("keep client: %w", err)
+	}
+	if cfg.Keep.Outbound.InsecureSkipVerify {
+		b.log.Warn("Keep TLS certificate verification disabled")
+	}
+	b.keepClient.SetMaxAlertsResponseBytes(int64(cfg.Polling.MaxResponseMB) << 20)
+	b.keepClient.SetSeverityMap(fileCfg.SeverityNormalization())
+	apiRetry := retry.Policy{
+		MaxAttempts:  fileCfg.APIRetry.Attempts,
+		InitialDelay: fileCfg.APIRetryInitialDelay(),
+		Multiplier:   fileCfg.APIRetry.Multiplier,
+		MaxDelay:     fileCfg.APIRetryMaxDelay(),
+		Jitter:       fileCfg.APIRetry.Jitter,
+	}
+	b.keepClient.SetRetryPolicy(apiRetry)
+	chatRateLimit := ratelimit.Limit{
+		RequestsPerSecond: fileCfg.RateLimit.RequestsPerSecond,
+		Burst:             fileCfg.RateLimit.Burst,
+	}
+	newMattermostClient := func(url, token string, log *slog.Logger) (*mattermost.Client, error) {
+		client := mattermost.NewClient(url, token, log)
+		if err := client.SetOutbound(cfg.Mattermost.Outbound); err != nil {
+			return nil, fmt.Errorf("mattermost client: %w", err)
+		}
+		client.SetBotIdentity(fileCfg.Message.Bot.Username, fileCfg.Message.Bot.IconURL)
+		client.SetRetryPolicy(apiRetry)
+		if fileCfg.RateLimit.Enabled {
+			client.SetRateLimit(chatRateLimit)
+		}
+		return client, nil
+	}
+	if cfg.Mattermost.Outbound.InsecureSkipVerify {
+		b.log.Warn("Mattermost TLS certificate verification disabled")
+	}
+	mainClient, err := newMattermostClient(cfg.Mattermost.URL, cfg.Mattermost.Token, b.log.With("component", "mattermost_client"))
+	if err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+	mainClient.SetClusterHosts(cfg.Mattermost.ClusterHosts)
+	if names := fileCfg.NamedChannels(); len(names) > 0 {
+		fileCfg, err = b.resolveChannelNames(mainClient, names)
+		if err != nil {
+			_ = b.Close()
+			return nil, err
+		}
+		b.fileCfg = fileCfg
+	}
+	// mmClient posts to MATTERMOST_URL, and to the server of mattermost_servers
+	// a routing rule names for its channel.
+	mmClient := mattermost.NewRegistry(mainClient)
+	// slackSecrets holds the signing secret of each Slack server, which its
+	// button clicks are checked against.
+	slackSecrets := make(map[string]string)
+	// teamsAuthenticators checks the activities each Teams bot is sent.
+	teamsAuthenticators := make(map[string]handler.TeamsAuthenticator)
+	// telegramBots and telegramSecrets are the Telegram servers whose button
+	// clicks arrive on their webhook, and the secret token it is called with.
+	telegramBots := make(map[string]handler.TelegramBot)
+	telegramSecrets := make(map[string]string)
+	if len(fileCfg.MattermostServers) > 0 {
+		channels := make(map[string][]string)
+		for channelID, server := range fileCfg.MattermostServerChannels() {
+			channels[server] = append(channels[server], channelID)
+		}
+		for _, server := range fileCfg.MattermostServers {
+			token, err := server.Token()
+			if err != nil {
+				_ = b.Close()
+				return nil, err
+			}
+			switch server.Kind {
+			case config.ServerKindSlack:
+				secret, err := server.SigningSecret()
+				if err != nil {
+					_ = b.Close()
+					return nil, err
+				}
+				slackSecrets[server.Name] = secret
+				baseURL := server.URL
+				if baseURL == "" {
+					baseURL = slack.DefaultURL
+				}
+				client := slack.NewClient(baseURL, token, b.log.With("component", "slack_client", "server", server.Name))
+				client.SetBotIdentity(fileCfg.Message.Bot.Username, fileCfg.Message.Bot.IconURL)
+				client.SetRetryPolicy(apiRetry)
+				if fileCfg.RateLimit.Enabled {
+					client.SetRateLimit(chatRateLimit)
+				}
+				mmClient.Add(server.Name, client, channels[server.Name])
+			case config.ServerKindTeams:
+				teamsAuthenticators[server.Name] = teams.NewAuthenticator(server.AppID, teams.DefaultOpenIDMetadataURL)
+				serviceURL := server.URL
+				if serviceURL == "" {
+					serviceURL = teams.DefaultServiceURL
+				}
+				tokenURL := teams.DefaultTokenURL
+				if server.TenantID != "" {
+					tokenURL = teams.TenantTokenURL(server.TenantID)
+				}
+				client := teams.NewClient(serviceURL, tokenURL, server.AppID, token, b.log.With("component", "teams_client", "server", server.Name))
+				client.SetRetryPolicy(apiRetry)
+				if fileCfg.RateLimit.Enabled {
+					client.SetRateLimit(chatRateLimit)
+				}
+				mmClient.Add(server.Name, client, channels[server.Name])
+			case config.ServerKindTelegram:
+				secret, err := server.SigningSecret()
+				if err != nil {
+					_ = b.Close()
+					return nil, err
+				}
+				baseURL := server.URL
+				if baseURL == "" {
+					baseURL = telegram.DefaultURL
+				}
+				client := telegram.NewClient(baseURL, token, b.log.With("component", "telegram_client", "server", server.Name))
+				client.SetRetryPolicy(apiRetry)
+				if fileCfg.RateLimit.Enabled {
+					client.SetRateLimit(chatRateLimit)
+				}
+				telegramBots[server.Name], telegramSecrets[server.Name] = client, secret
+				mmClient.Add(server.Name, client, channels[server.Name])
+			default:
+				client, err := newMattermostClient(server.URL, token, b.log.With("component", "mattermost_client", "server", server.Name))
+				if err != nil {
+					_ = b.Close()
+					return nil, err
+				}
+				mmClient.Add(server.Name, client, channels[server.Name])
+			}
+		}
+		b.log.Info("mattermost servers enabled", "servers", len(fileCfg.MattermostServers))
+	}
+	if fileCfg.KeepBreaker.Enabled {
+		b.keepClient.SetCircuitBreaker(breaker.Settings{
+			Failures: fileCfg.KeepBreaker.Failures,
+			OpenFor:  fileCfg.KeepBreakerOpenFor(),
+		})
+	}
+	if cfg.Keep.SigningKeyFile != "" {
+		signer, err := keep.LoadSigner(cfg.Keep.SigningKeyFile, cfg.Keep.SigningKeyID)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("load keep signing key: %w", err)
+		}
+		b.keepClient.SetSigner(signer)
+		b.log.Info("keep enrichment signing enabled", "alg", signer.Algorithm(), "kid", cfg.Keep.SigningKeyID)
+	}
+
+	channelRouter, err := config.NewLabelRouter(fileCfg, b.log.With("component", "channel_router"))
+	if err != nil {
+		_ = b.Close()
+		return nil, fmt.Errorf("build channel router: %w", err)
+	}
+
+	builderOpts := []attachment.Option{attachment.WithClock(b.clock)}
+	var workflowMenu *usecase.WorkflowMenu
+	if fileCfg.WorkflowMenu.Enabled {
+		workflowMenu = usecase.NewWorkflowMenu(
+			b.keepClient,
+			b.keepClient,
+			mmClient,
+			fileCfg.WorkflowMenu.Workflows,
+			fileCfg.WorkflowMenuResultTimeout(),
+			b.log.With("component", "workflow_menu"),
+		)
+		workflowMenu.SetClock(b.clock)
+		builderOpts = append(builderOpts, attachment.WithWorkflowMenu(workflowMenu))
+		b.jobs = append(b.jobs,
+			job{
+				name:      "workflow menu refresh",
+				interval:  fileCfg.WorkflowMenuRefreshInterval(),
+				timeout:   fileCfg.WorkflowMenuRefreshInterval(),
+				immediate: true,
+				run:       workflowMenu.Refresh,
+			},
+			job{
+				name:     "workflow run results",
+				interval: 10 * time.Second,
+				timeout:  10 * time.Second,
+				run:      workflowMenu.CheckResults,
+			},
+		)
+		b.log.Info("workflow menu enabled", "workflows", len(fileCfg.WorkflowMenu.Workflows))
+	}
+
+	msgBuilder, err := messagebuilder.NewBuilder(fileCfg, cfg.Keep.URL, builderOpts...)
+	if err != nil {
+		_ = b.Close()
+		return nil, fmt.Errorf("build message builder: %w", err)
+	}
+
+	// postClient renders alert posts. With correlation enabled it adds the
+	// correlation footer line to every updated post; with update coalescing
+	// it merges bursts of updates to the same post.
+	var postClient port.MattermostClient = mmClient
+	var correlationTracker *usecase.CorrelationTracker
+	if fileCfg.Correlation.Enabled {
+		if b.correlationRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("correlation", "WithCorrelationRepository")
+			}
+			b.correlationRepo = valkey.NewCorrelationRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		correlationTracker = usecase.NewCorrelationTracker(b.correlationRepo, b.log.With("component", "correlation_tracker"))
+		correlationTracker.SetClock(b.clock)
+		postClient = correlationTracker.WrapClient(mmClient)
+		b.log.Info("alert correlation enabled")
+	}
+
+	if fileCfg.UpdateCoalesce.Enabled {
+		b.coalescer = usecase.NewUpdateCoalescer(fileCfg.UpdateCoalesceWindow())
+		b.coalescer.SetClock(b.clock)
+		postClient = b.coalescer.WrapClient(postClient)
+	}
+
+	// deadLetters keeps alert posts Mattermost failed to create or update.
+	var deadLetters *usecase.DeadLetterQueue
+	if fileCfg.DeadLetter.Enabled {
+		if b.deadLetterRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("dead-letter queue", "WithDeadLetterRepository")
+			}
+			b.deadLetterRepo = valkey.NewDeadLetterRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		deadLetters = usecase.NewDeadLetterQueue(b.deadLetterRepo, fileCfg.DeadLetter.MaxAttempts, b.log.With("component", "dead_letter_queue"))
+		deadLetters.SetClock(b.clock)
+		postClient = deadLetters.WrapClient(postClient)
+	}
+
+	// auditTrail records the lifecycle of every alert; nil when disabled.
+	var auditTrail *usecase.AuditTrail
+	if fileCfg.Audit.Enabled {
+		switch {
+		case b.auditRepo != nil:
+		case b.pgPool != nil:
+			b.auditRepo = postgres.NewAuditRepository(b.pgPool, fileCfg.Audit.MaxEvents, fileCfg.AuditRetention(), b.log.With("component", "postgres"))
+		case b.redisClient != nil:
+			b.auditRepo = valkey.NewAuditRepository(b.redisClient, fileCfg.Audit.MaxEvents, fileCfg.AuditRetention(), b.log.With("component", "valkey"))
+		default:
+			return nil, b.missingStore("audit trail", "WithAuditRepository")
+		}
+		auditTrail = usecase.NewAuditTrail(b.auditRepo, b.log.With("component", "audit_trail"))
+		auditTrail.SetClock(b.clock)
+		b.log.Info("audit trail enabled", "max_events", fileCfg.Audit.MaxEvents, "retention", fileCfg.AuditRetention())
+	}
+
+	// postRepo is the repository use cases track posts in. With the status
+	// board enabled, every saved or deleted post marks the boards stale.
+	var postRepo post.Repository = b.postRepo
+	if fileCfg.StatusBoard.Enabled {
+		if b.statusBoardStore == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("status board", "WithStatusBoardStore")
+			}
+			b.statusBoardStore = valkey.NewStatusBoardStore(b.redisClient, b.log.With("component", "valkey"))
+		}
+		statusBoard := usecase.NewStatusBoard(b.postRepo, b.statusBoardStore, mmClient, msgBuilder, cfg.Keep.UIURL, fileCfg.StatusBoard.Channels, b.log.With("component", "status_board"))
+		statusBoard.SetClock(b.clock)
+		if fileCfg.StatusBoard.Pin {
+			statusBoard.SetPinClient(mmClient)
+		}
+		postRepo = statusBoard.WrapRepository(b.postRepo)
+		b.jobs = append(b.jobs,
+			job{
+				name:     "status board",
+				interval: fileCfg.StatusBoardUpdateInterval(),
+				timeout:  30 * time.Second,
+				run:      statusBoard.Refresh,
+			},
+			job{
+				name:      "status board resync",
+				interval:  fileCfg.StatusBoardResyncInterval(),
+				timeout:   30 * time.Second,
+				immediate: true,
+				run:       statusBoard.Resync,
+			},
+		)
+		b.log.Info("status board enabled", "channels", len(fileCfg.StatusBoard.Channels), "update_interval", fileCfg.StatusBoardUpdateInterval())
+	}
+
+	// timeline is shared by webhooks and buttons, so a status reported by
+	// both is appended once; nil when disabled.
+	var timeline *usecase.StatusTimeline
+	if fileCfg.Message.Timeline.Enabled {
+		timeline = usecase.NewStatusTimeline(postClient, b.log.With("component", "status_timeline"))
+		timeline.SetClock(b.clock)
+		b.log.Info("status timeline enabled")
+	}
+
+	// userMapper maps between Mattermost and Keep usernames: users.mapping,
+	// extended by email matches when auto-mapping is enabled.
+	var userMapper port.UserMapper = fileCfg
+	if fileCfg.Users.AutoMapping.Enabled {
+		autoMapping := usecase.NewUserAutoMapping(b.keepClient, mmClient, fileCfg, b.log.With("component", "user_auto_mapping"))
+		if b.redisClient != nil {
+			autoMapping.SetCache(valkey.NewUserEmailCache(b.redisClient, b.log.With("component", "valkey")), fileCfg.UserAutoMappingCacheTTL())
+		}
+		userMapper = autoMapping
+		b.jobs = append(b.jobs, job{
+			name:      "user auto-mapping",
+			interval:  fileCfg.UserAutoMappingRefreshInterval(),
+			timeout:   fileCfg.UserAutoMappingRefreshInterval(),
+			immediate: true,
+			run:       autoMapping.Refresh,
+		})
+		b.log.Info("user auto-mapping enabled", "refresh_interval", fileCfg.UserAutoMappingRefreshInterval())
+	}
+
+	handleAlertUC := usecase.NewHandleAlertUseCase(
+		postRepo,
+		postClient,
+		b.keepClient,
+		msgBuilder,
+		channelRouter,
+		userMapper,
+		cfg.Keep.UIURL,
+		cfg.CallbackURL,
+		b.log.With("component", "handle_alert_usecase"),
+	)
+	handleAlertUC.SetClock(b.clock)
+	handleAlertUC.SetAuditTrail(auditTrail)
+	handleAlertUC.SetStatusTimeline(timeline)
+	handleAlertUC.SetEnrichmentFetch(fileCfg.Enrichments.Enabled)
+	handleAlertUC.SetSeverityMap(fileCfg.SeverityNormalization())
+	handleAlertUC.SetAcceptedSeverities(fileCfg.AcceptedSeverities)
+	if fileCfg.DropRules.Enabled {
+		dropRules, err := config.NewDropRules(fileCfg)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("build drop rules: %w", err)
+		}
+		handleAlertUC.SetDropRules(dropRules)
+		b.log.Info("drop rules enabled", "rules", len(fileCfg.DropRules.Rules))
+	}
+	var maintenanceMonitor *usecase.MaintenanceMonitor
+	if fileCfg.Maintenance.Enabled {
+		schedule, err := config.NewMaintenanceSchedule(fileCfg)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("build maintenance schedule: %w", err)
+		}
+		handleAlertUC.SetMaintenance(schedule)
+		maintenanceMonitor = usecase.NewMaintenanceMonitor(schedule, b.log.With("component", "maintenance_monitor"))
+		maintenanceMonitor.SetClock(b.clock)
+		b.jobs = append(b.jobs, job{
+			name:      "maintenance status",
+			interval:  time.Minute,
+			timeout:   time.Minute,
+			immediate: true,
+			run:       maintenanceMonitor.Refresh,
+		})
+		b.log.Info("maintenance windows enabled", "windows", len(fileCfg.Maintenance.Windows))
+	}
+	if fileCfg.Flapping.Enabled {
+		flapDetector := usecase.NewFlapDetector(fileCfg.FlappingWindow(), fileCfg.Flapping.Threshold)
+		flapDetector.SetClock(b.clock)
+		handleAlertUC.SetFlapDetector(flapDetector)
+		b.jobs = append(b.jobs, job{
+			name:     "flapping settle",
+			interval: fileCfg.FlappingCheckInterval(),
+			timeout:  fileCfg.FlappingCheckInterval(),
+			run:      handleAlertUC.SettleFlapping,
+		})
+		b.log.Info("flapping detection enabled", "window", fileCfg.FlappingWindow(), "threshold", fileCfg.Flapping.Threshold)
+	}
+	if fileCfg.QuietHours.Enabled {
+		policy, err := config.NewQuietHoursPolicy(fileCfg)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("build quiet hours policy: %w", err)
+		}
+		if b.quietHoursRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("quiet hours", "WithQuietHoursRepository")
+			}
+			b.quietHoursRepo = valkey.NewQuietHoursRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		handleAlertUC.SetQuietHours(policy, b.quietHoursRepo)
+		b.jobs = append(b.jobs, job{
+			name:     "quiet hours release",
+			interval: fileCfg.QuietHoursCheckInterval(),
+			timeout:  fileCfg.QuietHoursCheckInterval(),
+			run:      handleAlertUC.ReleaseQuietHours,
+		})
+		b.log.Info("quiet hours enabled", "start", fileCfg.QuietHours.Start, "end", fileCfg.QuietHours.End, "timezone", fileCfg.QuietHours.Timezone)
+	}
+	if correlationTracker != nil {
+		handleAlertUC.SetCorrelationTracker(correlationTracker)
+	}
+	if fileCfg.DirectMessages.Enabled {
+		onCall, err := config.NewOnCallResolver(fileCfg)
+		if err != nil {
+			_ = b.Close()
+			return nil, fmt.Errorf("build on-call resolver: %w", err)
+		}
+		onCall.SetUserMapper(userMapper)
+		handleAlertUC.SetDirectMessages(onCall, mmClient, cfg.Mattermost.URL)
+	}
+	handleAlertUC.SetAssigneeRetryPolicy(usecase.AssigneeRetryPolicy{
+		Attempts:     fileCfg.AssigneeRetry.Attempts,
+		InitialDelay: fileCfg.AssigneeRetryInitialDelay(),
+		Multiplier:   fileCfg.AssigneeRetry.Multiplier,
+		MaxDelay:     fileCfg.AssigneeRetryMaxDelay(),
+		Jitter:       fileCfg.AssigneeRetry.Jitter,
+		Deadline:     fileCfg.AssigneeRetryDeadline(),
+	})
+
+	b.handleCallbackUC = usecase.NewHandleCallbackUseCase(
+		postRepo,
+		b.keepClient,
+		postClient,
+		msgBuilder,
+		userMapper,
+		cfg.Keep.UIURL,
+		cfg.CallbackURL,
+		b.log.With("component", "handle_callback_usecase"),
+	)
+	b.handleCallbackUC.SetClock(b.clock)
+	b.handleCallbackUC.SetAuditTrail(auditTrail)
+	b.handleCallbackUC.SetStatusTimeline(timeline)
+	b.handleCallbackUC.SetSnoozeDuration(fileCfg.SnoozeDuration())
+	b.handleCallbackUC.SetAckDurations(fileCfg.AckDurations())
+	if len(fileCfg.MattermostServers) > 0 {
+		b.handleCallbackUC.SetMattermostServers(mmClient)
+	}
+	var callbackStatusHandler *handler.CallbackStatusHandler
+	if fileCfg.CallbackWatch.Enabled {
+		watchdog := usecase.NewCallbackWatchdog(postClient, msgBuilder, cfg.Keep.UIURL, cfg.CallbackURL, fileCfg.CallbackWatchTimeout(), fileCfg.CallbackWatchRetention(), b.log.With("component", "callback_watchdog"))
+		watchdog.SetClock(b.clock)
+		b.handleCallbackUC.SetWatchdog(watchdog)
+		if cfg.Server.AdminToken != "" {
+			callbackStatusHandler = handler.NewCallbackStatusHandler(watchdog, b.log.With("component", "callback_status_handler"))
+		}
+		b.log.Info("callback watchdog enabled", "timeout", fileCfg.CallbackWatchTimeout(), "retention", fileCfg.CallbackWatchRetention())
+	}
+
+	// locks serialize the work on each fingerprint across bridge replicas.
+	var locks *usecase.FingerprintLocks
+	if fileCfg.Locking.Enabled {
+		if b.locker == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("locking", "WithLocks")
+			}
+			valkeyLocks := valkey.NewLocks(b.redisClient, b.log.With("component", "valkey"))
+			b.locker, b.idempotency = valkeyLocks, valkeyLocks
+		}
+		locks = usecase.NewFingerprintLocks(b.locker, fileCfg.LockingTTL(), b.log.With("component", "fingerprint_locks"))
+		if b.idempotency != nil {
+			locks.SetIdempotency(b.idempotency, fileCfg.LockingIdempotencyTTL())
+		}
+		b.handleCallbackUC.SetLocks(locks)
+		b.log.Info("fingerprint locking enabled", "ttl", fileCfg.LockingTTL())
+	}
+	var permissions *usecase.CallbackPermissions
+	if fileCfg.Permissions.Enabled {
+		permissions = usecase.NewCallbackPermissions(
+			fileCfg.PermissionRules(),
+			mmClient,
+			mmClient,
+			b.log.With("component", "callback_permissions"),
+		)
+		b.handleCallbackUC.SetPermissions(permissions)
+		b.log.Info("alert action permissions enabled", "rules", len(fileCfg.Permissions.Rules))
+	}
+
+	if fileCfg.CustomActions.Enabled {
+		customActions, err := config.NewCustomActionPolicy(fileCfg)
+		if err != nil {
+			return nil, fmt.Errorf("build custom actions: %w", err)
+		}
+		b.handleCallbackUC.SetCustomActions(usecase.NewCustomActionRunner(
+			customActions,
+			b.keepClient,
+			webhook.NewClient(b.log.With("component", "webhook_client")),
+			b.log.With("component", "custom_actions"),
+		))
+		b.log.Info("custom actions enabled", "actions", len(fileCfg.CustomActions.Actions))
+	}
+
+	if workflowMenu != nil {
+		b.handleCallbackUC.SetWorkflowMenu(workflowMenu)
+	}
+
+	if fileCfg.KeepBreaker.Enabled {
+		b.handleCallbackUC.SetKeepAvailability(b.keepClient, fileCfg.KeepBreaker.QueueSize)
+		b.jobs = append(b.jobs, job{
+			name:     "queued callbacks",
+			interval: 10 * time.Second,
+			timeout:  time.Minute,
+			run:      b.handleCallbackUC.DrainQueuedActions,
+		})
+		b.log.Info("keep circuit breaker enabled", "failures", fileCfg.KeepBreaker.Failures, "open_for", fileCfg.KeepBreakerOpenFor())
+	}
+	if fileCfg.OfflineActions.Enabled {
+		if b.pendingRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("offline actions", "WithPendingActionRepository")
+			}
+			b.pendingRepo = valkey.NewPendingActionRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		pending := usecase.NewPendingKeepActions(b.pendingRepo, fileCfg.OfflineActionsMaxAge(), b.log.With("component", "pending_keep_actions"))
+		pending.SetClock(b.clock)
+		b.handleCallbackUC.SetPendingActions(pending)
+		b.jobs = append(b.jobs, job{
+			name:     "offline actions replay",
+			interval: fileCfg.OfflineActionsReplayInterval(),
+			timeout:  fileCfg.OfflineActionsReplayInterval(),
+			run:      b.handleCallbackUC.ReplayPendingActions,
+		})
+		b.log.Info("offline actions enabled", "replay_interval", fileCfg.OfflineActionsReplayInterval(), "max_age", fileCfg.OfflineActionsMaxAge())
+	}
+
+	var dialogHandler *handler.DialogHandler
+	if fileCfg.ResolveDialog.Enabled {
+		b.handleCallbackUC.SetResolveDialog(usecase.NewResolveDialog(
+			mmClient,
+			strings.TrimRight(cfg.CallbackURL, "/")+"/dialog",
+			fileCfg.ResolveDialog.RequireNote,
+			fileCfg.ResolveDialog.Categories,
+		))
+		dialogHandler = handler.NewDialogHandler(b.handleCallbackUC, b.log.With("component", "dialog_handler"))
+		b.log.Info("resolve dialog enabled", "categories", len(fileCfg.ResolveDialog.Categories))
+	}
+
+	var incidentHandler *handler.IncidentHandler
+	if fileCfg.Incidents.Enabled {
+		if b.incidentRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("incident tracking", "WithIncidentRepository")
+			}
+			b.incidentRepo = valkey.NewIncidentRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		b.handleIncidentUC = usecase.NewHandleIncidentUseCase(
+			b.incidentRepo,
+			b.keepClient,
+			mmClient,
+			msgBuilder,
+			channelRouter,
+			cfg.Keep.UIURL,
+			strings.TrimRight(cfg.CallbackURL, "/")+"/incident",
+			b.log.With("component", "handle_incident_usecase"),
+		)
+		b.handleIncidentUC.SetClock(b.clock)
+		b.handleIncidentUC.SetChannel(fileCfg.Incidents.ChannelID)
+		b.handleIncidentUC.SetSeverityMap(fileCfg.SeverityNormalization())
+		b.handleIncidentUC.SetPermissions(permissions)
+		incidentHandler = handler.NewIncidentHandler(b.handleIncidentUC, b.log.With("component", "incident_handler"))
+		b.log.Info("Keep incidents enabled", "channel_id", fileCfg.Incidents.ChannelID)
+	}
+
+	if fileCfg.AlertGrouping.Enabled {
+		switch {
+		case b.groupRepo != nil:
+		case b.pgPool != nil:
+			b.groupRepo = postgres.NewGroupRepository(b.pgPool, b.log.With("component", "postgres"))
+		case b.redisClient != nil:
+			b.groupRepo = valkey.NewGroupRepository(b.redisClient, b.log.With("component", "valkey"))
+		default:
+			return nil, b.missingStore("alert grouping", "WithGroupRepository")
+		}
+		grouper := usecase.NewAlertGrouper(
+			b.groupRepo,
+			mmClient,
+			mmClient,
+			msgBuilder,
+			fileCfg.AlertGrouping.GroupBy,
+			cfg.Keep.UIURL,
+			b.log.With("component", "alert_grouper"),
+		)
+		grouper.SetClock(b.clock)
+		handleAlertUC.SetGrouper(grouper)
+		b.log.Info("alert grouping enabled", "group_by", fileCfg.AlertGrouping.GroupBy)
+	}
+
+	if fileCfg.Budget.Enabled {
+		budget := usecase.NewNotificationBudget(
+			mmClient,
+			msgBuilder,
+			fileCfg.BudgetPostsPerHour,
+			cfg.Keep.UIURL,
+			b.log.With("component", "notification_budget"),
+		)
+		budget.SetClock(b.clock)
+		handleAlertUC.SetNotificationBudget(budget)
+		b.jobs = append(b.jobs, job{
+			name:     "budget release",
+			interval: fileCfg.BudgetReleaseInterval(),
+			timeout:  fileCfg.BudgetReleaseInterval(),
+			run:      handleAlertUC.ReleaseHeldAlerts,
+		})
+	}
+
+	if fileCfg.Retention.Enabled {
+		if b.retentionRepo == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("retention", "WithRetentionRepository")
+			}
+			b.retentionRepo = valkey.NewRetentionRepository(b.redisClient, b.log.With("component", "valkey"))
+		}
+		postRetention := usecase.NewPostRetention(
+			b.retentionRepo,
+			mmClient,
+			msgBuilder,
+			usecase.RetentionPolicy{
+				Action:           fileCfg.Retention.Action,
+				Delay:            fileCfg.RetentionDelay(),
+				ArchiveChannelID: fileCfg.Retention.ArchiveChannelID,
+			},
+			cfg.Keep.UIURL,
+			b.log.With("component", "post_retention"),
+		)
+		postRetention.SetClock(b.clock)
+		handleAlertUC.SetPostRetention(postRetention)
+		b.handleCallbackUC.SetPostRetention(postRetention)
+		b.jobs = append(b.jobs, job{
+			name:     "retention",
+			interval: fileCfg.RetentionCheckInterval(),
+			timeout:  fileCfg.RetentionCheckInterval(),
+			run:      postRetention.Apply,
+		})
+		b.log.Info("post retention enabled", "action", fileCfg.Retention.Action, "delay", fileCfg.RetentionDelay())
+	}
+
+	if fileCfg.PostTTL.Enabled {
+		store, ok := b.postRepo.(postExpiryStore)
+		if !ok {
+			_ = b.Close()
+			return nil, fmt.Errorf("post_ttl requires STORAGE_BACKEND=%s or a post repository with per-severity TTLs", config.StorageBackendValkey)
+		}
+		store.SetSeverityTTLs(fileCfg.PostTTLDefault(), fileCfg.PostTTLSeverities())
+		expirePosts := usecase.NewExpirePostsUseCase(store, postClient, msgBuilder, cfg.Keep.UIURL, b.log.With("component", "post_expiry"))
+		expirePosts.SetClock(b.clock)
+		b.jobs = append(b.jobs, job{
+			name:     "post expiry",
+			interval: fileCfg.PostTTLCheckInterval(),
+			timeout:  fileCfg.PostTTLCheckInterval(),
+			run:      expirePosts.Execute,
+		})
+		b.log.Info("post expiry enabled", "default_ttl", fileCfg.PostTTLDefault(), "severities", fileCfg.PostTTLSeverities())
+	}
+
+	var alerts port.AlertUseCase = handleAlertUC
+	if locks != nil {
+		alerts = locks.WrapAlerts(alerts)
+	}
+	// Webhooks for the same alert are applied in the order they arrived,
+	// before they contend for the fingerprint lock.
+	alerts = usecase.NewAlertOrder().WrapAlerts(alerts)
+	var deadLetterHandler *handler.DeadLetterHandler
+	if deadLetters != nil {
+		// Re-deliveries go through the locks as well.
+		alerts = deadLetters.WrapAlerts(alerts)
+		b.jobs = append(b.jobs, job{
+			name:     "dead-letter redelivery",
+			interval: fileCfg.DeadLetterRedeliverInterval(),
+			timeout:  fileCfg.DeadLetterRedeliverInterval(),
+			run:      deadLetters.Redeliver,
+		})
+		if cfg.Server.AdminToken != "" {
+			deadLetterHandler = handler.NewDeadLetterHandler(deadLetters, b.log.With("component", "dead_letter_handler"))
+		} else {
+			b.log.Warn("dead-letter admin API disabled: ADMIN_TOKEN is not set")
+		}
+		b.log.Info("dead-letter queue enabled", "max_attempts", fileCfg.DeadLetter.MaxAttempts)
+	}
+	if fileCfg.ErrorPosts.Enabled {
+		// Error posts go straight to Mattermost and are not re-delivered.
+		errorPosts := usecase.NewErrorPosts(mmClient, msgBuilder, fileCfg.ErrorPosts.ChannelID, cfg.Keep.UIURL, fileCfg.ErrorPostsRepeatInterval(), b.log.With("component", "error_posts"))
+		errorPosts.SetClock(b.clock)
+		if deadLetters != nil {
+			deadLetters.SetErrorPosts(errorPosts)
+		}
+		alerts = errorPosts.WrapAlerts(alerts)
+		b.log.Info("error posts enabled", "channel", fileCfg.ErrorPosts.ChannelID, "repeat_interval", fileCfg.ErrorPostsRepeatInterval())
+	}
+
+	var alertsHandler *handler.AlertsHandler
+	var statsHandler *handler.StatsHandler
+	if cfg.Server.AdminToken != "" {
+		activeAlerts := usecase.NewActiveAlerts(postRepo, b.log.With("component", "active_alerts"))
+		activeAlerts.SetClock(b.clock)
+		alertsHandler = handler.NewAlertsHandler(activeAlerts, b.log.With("component", "alerts_handler"))
+
+		// Throughput counts every webhook once, however it was handled.
+		alertStats := usecase.NewAlertStats(postRepo)
+		alertStats.SetClock(b.clock)
+		alerts = alertStats.WrapAlerts(alerts)
+		statsHandler = handler.NewStatsHandler(alertStats, b.log.With("component", "stats_handler"))
+	}
+
+	var replayHandler *handler.ReplayHandler
+	if cfg.Server.AdminToken != "" {
+		// Live replays go through the locks and the dead-letter queue like
+		// webhooks do.
+		var archive handler.ArchivedAlerts
+		if deadLetters != nil {
+			archive = deadLetters
+		}
+		replayHandler = handler.NewReplayHandler(handleAlertUC, alerts, archive, b.log.With("component", "replay_handler"))
+	}
+
+	var auditHandler *handler.AuditHandler
+	if auditTrail != nil {
+		if cfg.Server.AdminToken != "" {
+			auditHandler = handler.NewAuditHandler(auditTrail, b.log.With("component", "audit_handler"))
+		} else {
+			b.log.Warn("alert history API disabled: ADMIN_TOKEN is not set")
+		}
+	}
+
+	if cfg.Reconcile.OnStart {
+		b.reconcileUC = usecase.NewReconcileUseCase(
+			postRepo,
+			b.keepClient,
+			alerts,
+			cfg.Polling.AlertsLimit,
+			b.log.With("component", "reconcile_usecase"),
+		)
+	}
+
+	// Assigned only when enabled: a nil *SLOTracker in the interface would
+	// not compare equal to nil in the router.
+	var sloObserver middleware.SLOObserver
+	if fileCfg.SLO.Enabled {
+		sloTracker := usecase.NewSLOTracker([]usecase.SLObjective{
+			{Name: middleware.SLOWebhook, Target: fileCfg.SLO.Webhook.Target, Threshold: fileCfg.SLOWebhookThreshold()},
+			{Name: middleware.SLOCallback, Target: fileCfg.SLO.Callback.Target, Threshold: fileCfg.SLOCallbackThreshold()},
+		}, b.log.With("component", "slo_tracker"))
+		sloTracker.SetClock(b.clock)
+		sloObserver = sloTracker
+		if fileCfg.SLO.Report.ChannelID != "" {
+			sloTracker.SetReport(mmClient, msgBuilder, fileCfg.SLO.Report.ChannelID)
+			b.jobs = append(b.jobs, job{
+				name:     "slo report",
+				interval: fileCfg.SLOReportInterval(),
+				timeout:  30 * time.Second,
+				run:      sloTracker.Report,
+			})
+		}
+		b.log.Info("SLO tracking enabled", "report_channel", fileCfg.SLO.Report.ChannelID)
+	}
+
+	if fileCfg.Digest.Enabled {
+		schedule, err := fileCfg.DigestSchedule()
+		if err != nil {
+			_ = b.Close()
+			return nil, err
+		}
+		digest := usecase.NewAlertDigestUseCase(b.auditRepo, b.keepClient, mmClient, msgBuilder, usecase.AlertDigestPolicy{
+			ChannelID: fileCfg.Digest.ChannelID,
+			Schedule:  schedule,
+			Period:    fileCfg.DigestPeriod(),
+			Top:       fileCfg.Digest.Top,
+		}, cfg.Keep.UIURL, b.log.With("component", "alert_digest"))
+		digest.SetClock(b.clock)
+		b.jobs = append(b.jobs, job{
+			name:      "alert digest",
+			interval:  time.Minute,
+			timeout:   time.Minute,
+			immediate: true,
+			run:       digest.Execute,
+		})
+		b.log.Info("alert digest enabled", "schedule", fileCfg.Digest.Schedule, "channel", fileCfg.Digest.ChannelID)
+	}
+
+	webhookHandler := handler.NewWebhookHandler(alerts, b.log.With("component", "webhook_handler"))
+	switch {
+	case cfg.Ingest.Mode == config.IngestModeStream:
+		if fileCfg.IngestQueue.Enabled {
+			_ = b.Close()
+			return nil, errors.New("ingest_queue cannot be combined with INGEST_MODE=stream")
+		}
+		if b.alertStream == nil {
+			if b.redisClient == nil {
+				return nil, b.missingStore("INGEST_MODE=stream", "WithAlertStream")
+			}
+			b.alertStream = valkey.NewAlertStream(b.redisClient, fileCfg.IngestStream.MaxLen, b.log.With("component", "valkey"))
+		}
+		b.streamConsumer = usecase.NewAlertStreamConsumer(
+			b.alertStream,
+			alerts,
+			cfg.Ingest.Consumer,
+			fileCfg.IngestStream.Batch,
+			fileCfg.IngestStreamClaimIdle(),
+			fileCfg.IngestStream.MaxDeliveries,
+			b.log.With("component", "alert_stream"),
+		)
+		b.streamConsumer.SetClock(b.clock)
+		webhookHandler.SetQueue(b.streamConsumer)
+		b.log.Info("Alert stream ingestion enabled", "consumer", cfg.Ingest.Consumer)
+	case fileCfg.IngestQueue.Enabled:
+		b.alertQueue = usecase.NewAlertQueue(alerts, fileCfg.IngestQueue.Size, fileCfg.IngestQueue.Workers, retry.Policy{
+			MaxAttempts:  fileCfg.IngestQueue.Attempts,
+			InitialDelay: fileCfg.IngestQueueInitialDelay(),
+			Multiplier:   2,
+			MaxDelay:     fileCfg.IngestQueueMaxDelay(),
+			Jitter:       0.2,
+		}, b.log.With("component", "alert_queue"))
+		b.alertQueue.SetClock(b.clock)
+		webhookHandler.SetQueue(b.alertQueue)
+		b.log.Info("Alert ingest queue enabled",
+			"size", fileCfg.IngestQueue.Size,
+			"workers", fileCfg.IngestQueue.Workers,
+		)
+	}
+	callbackHandler := handler.NewCallbackHandler(b.handleCallbackUC)
+	healthHandler := handler.NewHealthHandler(b.postRepo)
+	if maintenanceMonitor != nil {
+		healthHandler.SetMaintenance(maintenanceMonitor)
+	}
+	if fileCfg.KeepBreaker.Enabled {
+		healthHandler.SetKeepCircuit(b.keepClient)
+	}
+
+	var slashCommandHandler *handler.SlashCommandHandler
+	if cfg.Mattermost.SlashCommandToken != "" {
+		b.slashCommandUC = usecase.NewHandleSlashCommandUseCase(
+			postRepo,
+			b.keepClient,
+			postClient,
+			b.handleCallbackUC,
+			b.log.With("component", "handle_slash_command_usecase"),
+		)
+		b.slashCommandUC.SetClock(b.clock)
+		slashCommandHandler = handler.NewSlashCommandHandler(
+			b.slashCommandUC,
+			cfg.Mattermost.SlashCommandToken,
+			b.log.With("component", "slash_command_handler"),
+		)
+	}
+
+	var correlationHandler *handler.CorrelationHandler
+	if correlationTracker != nil {
+		correlationHandler = handler.NewCorrelationHandler(correlationTracker, b.log.With("component", "correlation_handler"))
+	}
+
+	var slackActionsHandler *handler.SlackActionsHandler
+	if len(slackSecrets) > 0 {
+		slackActionsHandler = handler.NewSlackActionsHandler(b.handleCallbackUC, slackSecrets, b.log.With("component", "slack_actions_handler"))
+	}
+	var teamsActionsHandler *handler.TeamsActionsHandler
+	if len(teamsAuthenticators) > 0 {
+		teamsActionsHandler = handler.NewTeamsActionsHandler(b.handleCallbackUC, teamsAuthenticators, mmClient, mmClient, b.log.With("component", "teams_actions_handler"))
+	}
+	var telegramUpdatesHandler *handler.TelegramUpdatesHandler
+	if len(telegramBots) > 0 {
+		telegramUpdatesHandler = handler.NewTelegramUpdatesHandler(b.handleCallbackUC, telegramBots, telegramSecrets, b.log.With("component", "telegram_updates_handler"))
+	}
+	b.router = httpInterface.NewRouter(b.log, httpInterface.RouterConfig{
+		BasePath:            cfg.Server.BasePath,
+		AccessLogSampleRate: cfg.Server.AccessLogSampleRate,
+		LogBodies:           cfg.Server.LogRequestBodies,
+		Limits:              requestLimits(cfg.Server),
+		SLO:                 sloObserver,
+		AdminToken:          cfg.Server.AdminToken,
+	}, httpInterface.Handlers{
+		Webhook:         webhookHandler,
+		Callback:        callbackHandler,
+		Health:          healthHandler,
+		SlashCommand:    slashCommandHandler,
+		Correlation:     correlationHandler,
+		DeadLetter:      deadLetterHandler,
+		Audit:           auditHandler,
+		Alerts:          alertsHandler,
+		Incident:        incidentHandler,
+		Dialog:          dialogHandler,
+		SlackActions:    slackActionsHandler,
+		TeamsActions:    teamsActionsHandler,
+		TelegramUpdates: telegramUpdatesHandler,
+		Replay:          replayHandler,
+		CallbackStatus:  callbackStatusHandler,
+		Stats:           statsHandler,
+	})
+	for _, register := range b.routes {
+		register(b.router)
+	}
+
+	if cfg.Polling.Enabled {
+		pollAlertsUC := usecase.NewPollAlertsUseCase(
+			postRepo,
+			b.keepClient,
+			postClient,
+			msgBuilder,
+			userMapper,
+			cfg.Keep.UIURL,
+			cfg.CallbackURL,
+			cfg.Polling.AlertsLimit,
+			b.log.With("component", "poll_alerts_usecase"),
+		)
+		pollAlertsUC.SetClock(b.clock)
+		pollAlertsUC.SetStatusSync(alerts)
+		if fileCfg.Enrichments.Enabled {
+			pollAlertsUC.SetEnrichmentRefresh(fileCfg.EnrichmentKeys())
+		}
+		if fileCfg.Polling.PageSize > 0 {
+			pollAlertsUC.SetPagedQueries(b.keepClient, fileCfg.Polling.PageSize)
+		}
+		if action := fileCfg.DeletedPostsAction(); action != "" {
+			pollAlertsUC.SetDeletedPosts(mmClient, action)
+		}
+		pollAlertsUC.SetBackoff(retry.Policy{
+			InitialDelay: cfg.Polling.Interval,
+			Multiplier:   cfg.Polling.BackoffMultiplier,
+			MaxDelay:     cfg.Polling.BackoffMax,
+		})
+		b.jobs = append(b.jobs, job{
+			name:     "polling",
+			interval: cfg.Polling.Interval,
+			timeout:  cfg.Polling.Timeout,
+			run:      pollAlertsUC.Execute,
+		})
+	} else {
+		b.log.Info("polling disabled")
+	}
+
+	if fileCfg.Snooze.Enabled {
+		unsnoozeUC := usecase.NewUnsnoozeAlertsUseCase(
+			postRepo,
+			b.keepClient,
+			postClient,
+			msgBuilder,
+			userMapper,
+			cfg.Keep.UIURL,
+			cfg.CallbackURL,
+			b.log.With("component", "unsnooze_alerts_usecase"),
+		)
+		unsnoozeUC.SetClock(b.clock)
+		b.jobs = append(b.jobs, job{
+			name:     "unsnooze",
+			interval: fileCfg.SnoozeCheckInterval(),
+			timeout:  fileCfg.SnoozeCheckInterval(),
+			run:      unsnoozeUC.Execute,
+		})
+	}
+
+	if fileCfg.TimedAck.Enabled {
+		expireAcksUC := usecase.NewExpireAcksUseCase(
+			postRepo,
+			b.keepClient,
+			postClient,
+			msgBuilder,
+			cfg.Keep.UIURL,
+			cfg.CallbackURL,
+			b.log.With("component", "expire_acks_usecase"),
+		)
+		expireAcksUC.SetAuditTrail(auditTrail)
+		expireAcksUC.SetClock(b.clock)
+		b.jobs = append(b.jobs, job{
+			name:     "expire_acks",
+			interval: fileCfg.TimedAckCheckInterval(),
+			timeout:  fileCfg.TimedAckCheckInterval(),
+			run:      expireAcksUC.Execute,
+		})
+	}
+
+	if fileCfg.Escalation.Enabled {
+		escalateUC := usecase.NewEscalateAlertsUseCase(
+			postRepo,
+			b.keepClient,
+			postClient,
+			msgBuilder,
+			fileCfg.EscalationRules(),
+			cfg.Mattermost.URL,
+			cfg.Keep.UIURL,
+			cfg.CallbackURL,
+			b.log.With("component", "escalate_alerts_usecase"),
+		)
+		escalateUC.SetClock(b.clock)
+		escalateUC.SetAuditTrail(auditTrail)
+		b.jobs = append(b.jobs, job{
+			name:     "escalation",
+			interval: fileCfg.EscalationCheckInterval(),
+			timeout:  fileCfg.EscalationCheckInterval(),
+			run:      escalateUC.Execute,
+		})
+	}
+
+	if fileCfg.Reactions.Enabled {
+		syncReactionsUC := usecase.NewSyncReactionsUseCase(
+			postRepo,
+			mmClient,
+			b.keepClient,
+			fileCfg.Reactions.Emojis,
+			b.log.With("component", "sync_reactions_usecase"),
+		)
+		b.jobs = append(b.jobs, job{
+			name:     "reaction sync",
+			interval: fileCfg.ReactionsInterval(),
+			timeout:  fileCfg.ReactionsInterval(),
+			run:      syncReactionsUC.Execute,
+		})
+	}
+
+	if fileCfg.ReactionAction.Enabled {
+		b.reactionActions = usecase.NewReactionActionsUseCase(
+			postRepo,
+			mmClient,
+			b.handleCallbackUC,
+			fileCfg.ReactionAction.Emojis,
+			b.log.With("component", "reaction_actions_usecase"),
+		)
+		b.reactionActions.SetClock(b.clock)
+	}
+
+	if fileCfg.ThreadNotes.Enabled {
+		b.threadNotes = usecase.NewThreadNotesUseCase(
+			postRepo,
+			mmClient,
+			mmClient,
+			b.keepClient,
+			fileCfg.ThreadNotes.EnrichmentKey,
+			fileCfg.ThreadNotes.MaxLength,
+			b.log.With("component", "thread_notes_usecase"),
+		)
+		if locks != nil {
+			b.threadNotes.SetLocks(locks)
+		}
+		b.threadNotes.SetClock(b.clock)
+	}
+
+	if fileCfg.Badge.Enabled {
+		updateBadgeUC := usecase.NewUpdateAlertBadgeUseCase(
+			postRepo,
+			mmClient,
+			fileCfg.Badge.Target,
+			fileCfg.Badge.ChannelID,
+			b.log.With("component", "update_alert_badge_usecase"),
+		)
+		b.jobs = append(b.jobs, job{
+			name:      "alert badge",
+			interval:  fileCfg.BadgeInterval(),
+			timeout:   fileCfg.BadgeInterval(),
+			immediate: true,
+			run:       updateBadgeUC.Execute,
+		})
+	}
+
+	if err := b.newTenants(); err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// openStorage opens the post repository selected by STORAGE_BACKEND.
+func (b *Bridge) openStorage() error {
+	switch b.cfg.Storage.Backend {
+	case config.StorageBackendMemory:
+		b.postRepo = storage.NewMemoryPostRepository()
+	case config.StorageBackendBolt:
+		repo, err := storage.OpenBoltPostRepository(b.cfg.Storage.Path, b.log.With("component", "bolt"))
+		if err != nil {
+			return err
+		}
+		b.boltRepo = repo
+		b.postRepo = repo
+	case config.StorageBackendPostgres:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		pool, err := postgres.Connect(ctx, b.cfg.Storage.DatabaseURL, b.log.With("component", "postgres"))
+		if err != nil {
+			return fmt.Errorf("connect to postgres: %w", err)
+		}
+		b.pgPool = pool
+		b.postRepo = postgres.NewPostRepository(pool, b.log.With("component", "postgres"))
+	default:
+		return b.connectValkey()
+	}
+	b.storageBackend = b.cfg.Storage.Backend
+	b.log.Info("using storage backend", "backend", b.storageBackend)
+	return nil
+}
+
+// missingStore closes what New opened and explains that feature needs a
+// store only the Valkey backend provides.
+func (b *Bridge) missingStore(feature, option string) error {
+	_ = b.Close()
+	if b.storageBackend != "" {
+		return fmt.Errorf("%s requires STORAGE_BACKEND=%s, got %q", feature, config.StorageBackendValkey, b.storageBackend)
+	}
+	return fmt.Errorf("%s requires %s when a custom post repository is used", feature, option)
+}
+
+// resolveChannelNames returns the config file with the channels it gives by
+// name replaced by their IDs in the team of MATTERMOST_TEAM_ID, creating the
+// missing ones when channel_names.create is set.
+func (b *Bridge) resolveChannelNames(directory port.MattermostChannelDirectory, names []string) (*config.FileConfig, error) {
+	if b.cfg.Mattermost.TeamID == "" {
+		return nil, fmt.Errorf("channels given by name require MATTERMOST_TEAM_ID, or mattermost.team_id for tenants")
+	}
+	resolver := usecase.NewChannelNames(directory, b.cfg.Mattermost.TeamID, b.log.With("component", "channel_names"))
+	if b.fileCfg.ChannelNames.Create {
+		resolver.SetCreate(b.fileCfg.ChannelNames.Private)
+	}
+	if b.redisClient != nil {
+		resolver.SetCache(valkey.NewChannelIDCache(b.redisClient, b.log.With("component", "valkey")), b.fileCfg.ChannelNameCacheTTL())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ids, err := resolver.Resolve(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("resolve channel names: %w", err)
+	}
+	b.log.Info("channel names resolved", "channels", len(ids), "team_id", b.cfg.Mattermost.TeamID)
+	return b.fileCfg.WithChannelIDs(ids), nil
+}
+
+func (b *Bridge) connectValkey() error {
+	b.redisClient = redis.NewClient(&redis.Options{
+		Addr:     b.cfg.Redis.Addr,
+		Password: b.cfg.Redis.Password,
+		DB:       b.cfg.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.redisClient.Ping(ctx).Err(); err != nil {
+		_ = b.redisClient.Close()
+		b.redisClient = nil
+		return fmt.Errorf("connect to valkey: %w", err)
+	}
+	b.log.Info("connected to valkey", "addr", b.cfg.Redis.Addr)
+
+	b.postRepo = valkey.NewPostRepository(b.redisClient, b.log.With("component", "valkey"))
+	return nil
+}
+
+// Handler returns the HTTP handler serving the webhook, callback, health and
+// metrics endpoints, and those of every tenant under its base path, for
+// programs that run their own http.Server.
+func (b *Bridge) Handler() http.Handler {
+	if len(b.tenants) == 0 {
+		return b.router
+	}
+	return http.HandlerFunc(b.serveTenants)
+}
+
+// Router exposes the underlying gin engine for adding routes after New.
+// Tenants have routers of their own.
+func (b *Bridge) Router() *gin.Engine {
+	return b.router
+}
+
+// EnsureKeepSetup creates the Keep webhook provider and workflow, in the Keep
+// of every tenant too, when setup is enabled. Failures are logged and do not
+// stop the bridge.
+func (b *Bridge) EnsureKeepSetup(ctx context.Context) {
+	for _, tenant := range b.tenants {
+		tenant.EnsureKeepSetup(ctx)
+	}
+	if !b.cfg.Setup.Enabled {
+		b.log.Info("Keep setup disabled, skipping provider/workflow creation")
+		return
+	}
+
+	ensureSetupUC := usecase.NewEnsureKeepSetupUseCase(
+		b.keepClient,
+		keepWebhookURL(b.cfg),
+		b.log.With("component", "ensure_keep_setup"),
+	)
+
+	setupCtx, setupCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer setupCancel()
+	if err := ensureSetupUC.Execute(setupCtx); err != nil {
+		b.log.Warn("Failed to ensure Keep setup, continuing anyway", "error", err)
+	}
+}
+
+// Bootstrap installs the Keep webhook provider and workflow the bridge needs
+// and replaces a workflow that differs from the expected one. With dryRun it
+// only reports what it would change. It needs no store, so it can run before
+// the bridge is deployed.
+func Bootstrap(ctx context.Context, cfg *config.Config, dryRun bool, log *slog.Logger) (usecase.KeepSetupResult, error) {
+	keepClient := keep.NewClient(cfg.Keep.URL, cfg.Keep.APIKey, log.With("component", "keep_client"))
+	if err := keepClient.SetOutbound(cfg.Keep.Outbound); err != nil {
+		return usecase.KeepSetupResult{}, fmt.Errorf("keep client: %w", err)
+	}
+	uc := usecase.NewEnsureKeepSetupUseCase(keepClient, keepWebhookURL(cfg), log.With("component", "ensure_keep_setup"))
+	uc.SetWorkflowUpdater(keepClient)
+	uc.SetDryRun(dryRun)
+	return uc.Run(ctx)
+}
+
+// keepWebhookURL derives the URL Keep posts alerts to from the callback URL
+// by replacing /callback with /webhook/alert.
+func keepWebhookURL(cfg *config.Config) string {
+	return strings.Replace(cfg.CallbackURL, "/callback", "/webhook/alert", 1)
+}
+
+// requestLimits returns the webhook and callback limits of cfg.
+func requestLimits(cfg config.ServerConfig) middleware.RequestLimits {
+	return middleware.RequestLimits{
+		Webhook:  middleware.EndpointLimit{MaxBodyBytes: cfg.WebhookMaxBodyBytes, Timeout: cfg.WebhookTimeout},
+		Callback: middleware.EndpointLimit{MaxBodyBytes: cfg.CallbackMaxBodyBytes, Timeout: cfg.CallbackTimeout},
+	}
+}
+
+// Reconcile heals webhooks missed while the bridge was down when
+// reconciliation on start is enabled. Failures are logged and do not stop the
+// bridge.
+func (b *Bridge) Reconcile(ctx context.Context) {
+	for _, tenant := range b.tenants {
+		tenant.Reconcile(ctx)
+	}
+	if b.reconcileUC == nil {
+		return
+	}
+
+	reconcileCtx, reconcileCancel := context.WithTimeout(ctx, b.cfg.Reconcile.Timeout)
+	defer reconcileCancel()
+	if err := b.reconcileUC.Execute(reconcileCtx); err != nil {
+		b.log.Warn("Failed to reconcile with Keep, continuing anyway", "error", err)
+	}
+}
+
+// StartJobs launches the background jobs, and the reaction and thread reply
+// listeners when reaction actions and thread notes are enabled, of b and its
+// tenants, and returns a function that stops them and waits for in-flight
+// runs to finish.
+func (b *Bridge) StartJobs() (stop func()) {
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	var stopTenants []func()
+	for _, tenant := range b.tenants {
+		stopTenants = append(stopTenants, tenant.StartJobs())
+	}
+
+	for _, j := range b.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.runJob(j, done)
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if b.reactionActions != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.reactionActions.Run(ctx)
+		}()
+	}
+	if b.threadNotes != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.threadNotes.Run(ctx)
+		}()
+	}
+
+	return func() {
+		close(done)
+		cancel()
+		wg.Wait()
+		for _, stop := range stopTenants {
+			stop()
+		}
+	}
+}
+
+func (b *Bridge) runJob(j job, done <-chan struct{}) {
+	ticker := b.clock.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	b.log.Info(j.name+" started", "interval", j.interval, "timeout", j.timeout)
+
+	execute := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+		defer cancel()
+		if err := j.run(ctx); err != nil {
+			b.log.Error(j.name+" failed", "error", err)
+		}
+	}
+
+	if j.immediate {
+		execute()
+	}
+
+	for {
+		select {
+		case <-ticker.C():
+			execute()
+		case <-done:
+			b.log.Info(j.name + " stopped")
+			return
+		}
+	}
+}
+
+// Run performs Keep setup, starts the background jobs and serves HTTP on the
+// configured address until ctx is cancelled or the server fails, then shuts
+// everything down gracefully: the jobs and the server stop first and the work
+// already accepted is drained within the drain timeout. It does not close the
+// repository; call Close.
+func (b *Bridge) Run(ctx context.Context) error {
+	b.EnsureKeepSetup(ctx)
+	b.Reconcile(ctx)
+
+	srv := &http.Server{
+		Addr:              b.cfg.Server.Addr(),
+		Handler:           b.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+	}
+
+	stopJobs := b.StartJobs()
+	bridges := b.all()
+	stopStreams := make([]func(), len(bridges))
+	for i, bridge := range bridges {
+		if bridge.alertQueue != nil {
+			bridge.alertQueue.Start()
+		}
+		stopStreams[i] = bridge.startStreamConsumer()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	b.log.Info("server started", "addr", b.cfg.Server.Addr())
+
+	var serveErr error
+	select {
+	case serveErr = <-errCh:
+		b.log.Error("server error", "error", serveErr)
+	case <-ctx.Done():
+		b.log.Info("shutting down...")
+	}
+
+	// Stopping the jobs stops the polling loop before anything is drained.
+	stopJobs()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		b.log.Error("server forced to shutdown", "error", err)
+	}
+
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), b.cfg.Server.DrainTimeout)
+	defer drainCancel()
+
+	for i, bridge := range bridges {
+		start := bridge.clock.Now()
+		drainErr := bridge.drain(drainCtx, stopStreams[i])
+		if drainErr != nil {
+			bridge.log.Error("shutdown drain incomplete", "error", drainErr, "duration", bridge.clock.Now().Sub(start))
+		} else {
+			bridge.log.Info("shutdown drain complete", "duration", bridge.clock.Now().Sub(start))
+		}
+		bridge.writeShutdownMarker(drainErr == nil)
+	}
+
+	return serveErr
+}
+
+// drain finishes the work accepted before shutdown: the alert in flight on
+// the stream, queued alerts, callbacks, incident updates and bulk actions,
+// and finally the Mattermost updates held by the coalescer. The server no
+// longer accepts requests, so nothing new arrives. It returns what ctx cut
+// short.
+func (b *Bridge) drain(ctx context.Context, stopStream func()) error {
+	stopStream()
+
+	var errs []error
+	if b.alertQueue != nil {
+		if err := b.alertQueue.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("alert queue: %w", err))
+		}
+	}
+	if err := waitContext(ctx, b.handleCallbackUC.Wait); err != nil {
+		errs = append(errs, fmt.Errorf("callbacks: %w", err))
+	}
+	if b.handleIncidentUC != nil {
+		if err := waitContext(ctx, b.handleIncidentUC.Wait); err != nil {
+			errs = append(errs, fmt.Errorf("incidents: %w", err))
+		}
+	}
+	if b.slashCommandUC != nil {
+		if err := waitContext(ctx, b.slashCommandUC.Wait); err != nil {
+			errs = append(errs, fmt.Errorf("bulk actions: %w", err))
+		}
+	}
+	// Callbacks and queued alerts update posts, so held updates are flushed
+	// last.
+	if b.coalescer != nil {
+		if err := b.coalescer.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("held post updates: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitContext runs wait and returns once it does or ctx ends.
+func waitContext(ctx context.Context, wait func()) error {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeShutdownMarker records in Valkey that this replica shut down and
+// whether the drain completed. Without Valkey there is nowhere to write it.
+func (b *Bridge) writeShutdownMarker(drained bool) {
+	if b.redisClient == nil {
+		return
+	}
+	instance := b.cfg.Ingest.Consumer
+	if instance == "" {
+		instance = "kmbridge"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := valkey.NewShutdownMarker(b.redisClient).Write(ctx, instance, b.clock.Now(), drained); err != nil {
+		b.log.Error("failed to write shutdown marker", "error", err)
+	}
+}
+
+// startStreamConsumer consumes the alert stream in the background until the
+// returned function is called, which waits for the alert in flight.
+func (b *Bridge) startStreamConsumer() (stop func()) {
+	if b.streamConsumer == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.streamConsumer.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Close releases the Valkey connection, bolt database or Postgres pool opened
+// by New, and those of its tenants. It is a no-op when the repository was
+// supplied via WithPostRepository and there are no tenants.
+func (b *Bridge) Close() error {
+	for _, tenant := range b.tenants {
+		if err := tenant.Close(); err != nil {
+			return fmt.Errorf("close tenant: %w", err)
+		}
+	}
+	b.tenants = nil
+	if b.pgPool != nil {
+		b.pgPool.Close()
+		b.pgPool = nil
+	}
+	if b.boltRepo != nil {
+		if err := b.boltRepo.Close(); err != nil {
+			return fmt.Errorf("close bolt database: %w", err)
+		}
+		b.boltRepo = nil
+	}
+	if b.redisClient == nil {
+		return nil
+	}
+	if err := b.redisClient.Close(); err != nil {
+		return fmt.Errorf("close redis client: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,648 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/usecase"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type fakeRepository struct {
+	portmock.RepositoryMock
+	pingErr error
+}
+
+func (f *fakeRepository) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func testConfig() (*config.Config, *config.FileConfig) {
+	cfg := &config.Config{
+		Mattermost:  config.MattermostConfig{URL: "http://mattermost.invalid", Token: "token"},
+		Keep:        config.KeepConfig{URL: "http://keep.invalid", APIKey: "key", UIURL: "http://keep-ui.invalid"},
+		CallbackURL: "http://bridge.invalid/api/v1/callback",
+	}
+	return cfg, config.DefaultFileConfig()
+}
+
+func newTestBridge(t *testing.T, repo PostRepository, opts ...Option) *Bridge {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg, fileCfg := testConfig()
+
+	opts = append([]Option{
+		WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		WithPostRepository(repo),
+	}, opts...)
+
+	b, err := New(cfg, fileCfg, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, b.Close()) })
+	return b
+}
+
+func TestNewWithCustomRepository(t *testing.T) {
+	repo := &fakeRepository{}
+	b := newTestBridge(t, repo)
+
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	repo.pingErr = errors.New("down")
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestWithRoutes(t *testing.T) {
+	var order []string
+	b := newTestBridge(t, &fakeRepository{},
+		WithRoutes(func(router *gin.Engine) {
+			order = append(order, "first")
+			router.GET("/custom", func(c *gin.Context) { c.String(http.StatusTeapot, "custom") })
+		}),
+		WithRoutes(func(router *gin.Engine) { order = append(order, "second") }),
+	)
+
+	assert.Equal(t, []string{"first", "second"}, order)
+
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/custom", nil))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "custom", w.Body.String())
+}
+
+func TestNewInvalidSigningKey(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	cfg.Keep.SigningKeyFile = "/nonexistent/key.pem"
+
+	_, err := New(cfg, fileCfg,
+		WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		WithPostRepository(&fakeRepository{}),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "load keep signing key")
+}
+
+func TestStartJobs(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := newTestBridge(t, &fakeRepository{}, WithClock(fake))
+
+	runs := make(chan string, 10)
+	b.jobs = []job{
+		{
+			name:      "immediate job",
+			interval:  time.Minute,
+			timeout:   time.Second,
+			immediate: true,
+			run: func(ctx context.Context) error {
+				runs <- "immediate"
+				return nil
+			},
+		},
+		{
+			name:     "ticking job",
+			interval: time.Minute,
+			timeout:  time.Second,
+			run: func(ctx context.Context) error {
+				runs <- "ticking"
+				return nil
+			},
+		},
+	}
+
+	stop := b.StartJobs()
+	defer stop()
+
+	assert.Equal(t, "immediate", receiveRun(t, runs))
+
+	fake.BlockUntil(2)
+	fake.Advance(time.Minute)
+	got := []string{receiveRun(t, runs), receiveRun(t, runs)}
+	assert.ElementsMatch(t, []string{"immediate", "ticking"}, got)
+
+	select {
+	case extra := <-runs:
+		t.Fatalf("unexpected extra run %q before the next tick", extra)
+	default:
+	}
+}
+
+func receiveRun(t *testing.T, runs <-chan string) string {
+	t.Helper()
+	select {
+	case name := <-runs:
+		return name
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+		return ""
+	}
+}
+
+func TestDrain(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.IngestQueue.Enabled = true
+	fileCfg.UpdateCoalesce.Enabled = true
+	b, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))), WithPostRepository(&fakeRepository{}))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+	b.alertQueue.Start()
+
+	var streamStopped bool
+	require.NoError(t, b.drain(context.Background(), func() { streamStopped = true }))
+	assert.True(t, streamStopped)
+	assert.Error(t, b.alertQueue.Enqueue(context.Background(), dto.KeepAlertInput{Fingerprint: "fp-1"}), "the queue is closed")
+}
+
+func TestWaitContext(t *testing.T) {
+	require.NoError(t, waitContext(context.Background(), func() {}))
+
+	release := make(chan struct{})
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, waitContext(ctx, func() { <-release }), context.DeadlineExceeded)
+}
+
+func TestReconcileOnStart(t *testing.T) {
+	var calls int
+	repo := &fakeRepository{}
+	repo.FindAllActiveFunc = func(ctx context.Context) ([]*post.Post, error) {
+		calls++
+		return nil, errors.New("valkey down")
+	}
+
+	b := newTestBridge(t, repo)
+	b.Reconcile(context.Background())
+	assert.Zero(t, calls, "reconciliation is off by default")
+
+	cfg, fileCfg := testConfig()
+	cfg.Reconcile = config.ReconcileConfig{OnStart: true, Timeout: time.Second}
+	b, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))), WithPostRepository(repo))
+	require.NoError(t, err)
+	b.Reconcile(context.Background())
+	assert.Equal(t, 1, calls, "failures are logged, not returned")
+	assert.NoError(t, b.Close())
+}
+
+func TestNewAlertGroupingRequiresGroupRepository(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.AlertGrouping = config.AlertGroupingConfig{Enabled: true, GroupBy: []string{"service"}}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithGroupRepository")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithGroupRepository(&portmock.GroupRepositoryMock{}))
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+}
+
+func TestNewCorrelationRequiresCorrelationRepository(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.Correlation = config.CorrelationConfig{Enabled: true}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithCorrelationRepository")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithCorrelationRepository(&portmock.CorrelationRepositoryMock{}))
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+}
+
+func TestNewStatusBoardRequiresStatusBoardStore(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.StatusBoard = config.StatusBoardConfig{Enabled: true, UpdateInterval: "10s", ResyncInterval: "5m"}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithStatusBoardStore")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithStatusBoardStore(&portmock.StatusBoardStoreMock{}))
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+}
+
+func TestNewRetentionRequiresRetentionRepository(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.Retention = config.RetentionConfig{Enabled: true, Action: "collapse", Delay: "1h", CheckInterval: "1m"}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithRetentionRepository")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithRetentionRepository(&portmock.RetentionRepositoryMock{}))
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+}
+
+func TestNewIncidentsRequireIncidentRepository(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.Incidents = config.IncidentsConfig{Enabled: true}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithIncidentRepository")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithIncidentRepository(&portmock.IncidentRepositoryMock{}))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/webhook/incident", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "the incident webhook is registered")
+}
+
+func TestIngestQueueAcceptsWebhooks(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.IngestQueue.Enabled = true
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	body := `{"id":"alert-1","name":"DiskFull","status":"firing","severity":"critical","fingerprint":"fp-1"}`
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/webhook/alert", strings.NewReader(body)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, b.alertQueue.Depth())
+}
+
+func TestStreamIngestAppendsWebhooks(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	cfg.Ingest = config.IngestConfig{Mode: config.IngestModeStream, Consumer: "bridge-1"}
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithAlertStream")
+
+	stream := &portmock.AlertStreamMock{
+		AppendFunc: func(ctx context.Context, input dto.KeepAlertInput) error { return nil },
+	}
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithAlertStream(stream))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	body := `{"id":"alert-1","name":"DiskFull","status":"firing","severity":"critical","fingerprint":"fp-1"}`
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/webhook/alert", strings.NewReader(body)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, stream.AppendCalls(), 1)
+	assert.Equal(t, "fp-1", stream.AppendCalls()[0].Input.Fingerprint)
+
+	fileCfg.IngestQueue.Enabled = true
+	_, err = New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithAlertStream(stream))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ingest_queue cannot be combined")
+}
+
+func TestNewLockingRequiresLocks(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	fileCfg.Locking.Enabled = true
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithLocks")
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithLocks(&portmock.LockerMock{}, nil))
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+}
+
+func TestNewWithBuiltInStorageBackends(t *testing.T) {
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	for _, backend := range []string{config.StorageBackendMemory, config.StorageBackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			cfg, fileCfg := testConfig()
+			cfg.Storage = config.StorageConfig{Backend: backend, Path: filepath.Join(t.TempDir(), "kmbridge.db")}
+
+			b, err := New(cfg, fileCfg, WithLogger(log))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			require.NoError(t, b.Close())
+
+			fileCfg.Correlation.Enabled = true
+			_, err = New(cfg, fileCfg, WithLogger(log))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "correlation requires STORAGE_BACKEND=valkey")
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	keepServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method + " " + r.URL.Path {
+		case "GET /providers":
+			if len(writes) > 0 {
+				_, _ = w.Write([]byte(`{"installed_providers":[{"id":"p-1","type":"webhook","details":{"name":"kmbridge"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"installed_providers":[]}`))
+		case "GET /workflows":
+			_, _ = w.Write([]byte(`[{"id":"wf-1","workflow_raw_id":"kmbridge-webhook","workflow_raw":"id: kmbridge-webhook\n"}]`))
+		default:
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+	}))
+	defer keepServer.Close()
+
+	cfg, _ := testConfig()
+	cfg.Keep.URL = keepServer.URL
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	result, err := Bootstrap(context.Background(), cfg, true, log)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.KeepSetupMissing, result.Provider)
+	assert.Equal(t, usecase.KeepSetupOutdated, result.Workflow)
+	assert.Contains(t, result.WorkflowDiff, "+++ kmbridge")
+	assert.Empty(t, writes, "a dry run changes nothing")
+
+	result, err = Bootstrap(context.Background(), cfg, false, log)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.KeepSetupCreated, result.Provider)
+	assert.Equal(t, usecase.KeepSetupUpdated, result.Workflow)
+	assert.Equal(t, []string{"POST /providers/install", "PUT /workflows/wf-1"}, writes)
+}
+
+func TestNewPostgresUnreachable(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	cfg.Storage = config.StorageConfig{
+		Backend:     config.StorageBackendPostgres,
+		DatabaseURL: "postgres://kmbridge@127.0.0.1:1/kmbridge?connect_timeout=1",
+	}
+
+	_, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connect to postgres")
+}
+
+func TestDeadLetterRoutesRequireAdminToken(t *testing.T) {
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	cfg, fileCfg := testConfig()
+	fileCfg.DeadLetter = config.DeadLetterConfig{Enabled: true, RedeliverInterval: "1m", MaxAttempts: 3}
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithDeadLetterRepository")
+
+	repo := &portmock.DeadLetterRepositoryMock{
+		FindAllFunc: func(ctx context.Context) ([]*deadletter.Entry, error) { return nil, nil },
+	}
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/deadletter", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithDeadLetterRepository(repo))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest(""))
+	assert.Equal(t, http.StatusNotFound, w.Code, "no admin API without ADMIN_TOKEN")
+	assert.NoError(t, b.Close())
+
+	cfg.Server.AdminToken = "admin-token"
+	b, err = New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithDeadLetterRepository(repo))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest("wrong"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest("admin-token"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"entries":[]}`, w.Body.String())
+}
+
+func TestAuditHistoryRouteRequiresAdminToken(t *testing.T) {
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	cfg, fileCfg := testConfig()
+	fileCfg.Audit = config.AuditConfig{Enabled: true, MaxEvents: 100, Retention: "720h"}
+
+	_, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithAuditRepository")
+
+	repo := &portmock.AuditRepositoryMock{
+		FindByFingerprintFunc: func(ctx context.Context, fp alert.Fingerprint) ([]*audit.Event, error) { return nil, nil },
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts/fp-1/history", nil)
+		req.Header.Set("Authorization", "Bearer admin-token")
+		return req
+	}
+
+	b, err := New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithAuditRepository(repo))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusNotFound, w.Code, "no history API without ADMIN_TOKEN")
+	assert.NoError(t, b.Close())
+
+	cfg.Server.AdminToken = "admin-token"
+	b, err = New(cfg, fileCfg, WithLogger(log), WithPostRepository(&fakeRepository{}), WithAuditRepository(repo))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"fingerprint":"fp-1","events":[]}`, w.Body.String())
+}
+
+func TestActiveAlertsRouteRequiresAdminToken(t *testing.T) {
+	repo := &fakeRepository{}
+	repo.FindAllActiveFunc = func(ctx context.Context) ([]*post.Post, error) { return nil, nil }
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+
+	b := newTestBridge(t, repo)
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest("admin-token"))
+	assert.Equal(t, http.StatusNotFound, w.Code, "no admin API without ADMIN_TOKEN")
+
+	cfg, fileCfg := testConfig()
+	cfg.Server.AdminToken = "admin-token"
+	b, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))), WithPostRepository(repo))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest(""))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest("admin-token"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"alerts":[]}`, w.Body.String())
+}
+
+func TestSlashCommandRouteRequiresToken(t *testing.T) {
+	form := url.Values{"token": {"cmd-token"}, "text": {"help"}}.Encode()
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/command", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	b := newTestBridge(t, &fakeRepository{})
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	cfg, fileCfg := testConfig()
+	cfg.Mattermost.SlashCommandToken = "cmd-token"
+	b, err := New(cfg, fileCfg,
+		WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		WithPostRepository(&fakeRepository{}),
+	)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Usage:")
+}
+
+func TestTenantsServeUnderTheirBasePath(t *testing.T) {
+	t.Setenv("STAGING_KEEP_API_KEY", "staging-key")
+	t.Setenv("STAGING_MATTERMOST_TOKEN", "staging-token")
+	cfg, fileCfg := testConfig()
+	cfg.Storage = config.StorageConfig{Backend: config.StorageBackendMemory}
+	fileCfg.IngestQueue.Enabled = true
+	fileCfg.Tenants = []config.TenantConfig{{
+		Name:       "staging",
+		Keep:       config.TenantKeepConfig{URL: "http://keep-staging.invalid", APIKeyEnv: "STAGING_KEEP_API_KEY"},
+		Mattermost: config.TenantMattermostConfig{TokenEnv: "STAGING_MATTERMOST_TOKEN"},
+		Channels:   config.ChannelsConfig{DefaultChannelID: "staging-alerts"},
+	}}
+	require.NoError(t, fileCfg.Validate())
+
+	b, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+	require.Len(t, b.tenants, 1)
+	tenant := b.tenants[0]
+	assert.Equal(t, "/staging", tenant.cfg.Server.BasePath)
+	assert.Equal(t, "http://bridge.invalid/staging/api/v1/callback", tenant.cfg.CallbackURL)
+	assert.Equal(t, "staging-alerts", tenant.fileCfg.Channels.DefaultChannelID)
+
+	body := `{"id":"alert-1","name":"DiskFull","status":"firing","severity":"critical","fingerprint":"fp-1"}`
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/staging/api/v1/webhook/alert", strings.NewReader(body)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, tenant.alertQueue.Depth())
+	assert.Zero(t, b.alertQueue.Depth())
+
+	w = httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/webhook/alert", strings.NewReader(body)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, b.alertQueue.Depth())
+}
+
+func TestTenantsRequireSecrets(t *testing.T) {
+	cfg, fileCfg := testConfig()
+	cfg.Storage = config.StorageConfig{Backend: config.StorageBackendMemory}
+	fileCfg.Tenants = []config.TenantConfig{{
+		Name:       "staging",
+		Keep:       config.TenantKeepConfig{URL: "http://keep-staging.invalid", APIKeyEnv: "KMBRIDGE_TEST_UNSET_KEY"},
+		Mattermost: config.TenantMattermostConfig{TokenEnv: "KMBRIDGE_TEST_UNSET_TOKEN"},
+		Channels:   config.ChannelsConfig{DefaultChannelID: "staging-alerts"},
+	}}
+
+	_, err := New(cfg, fileCfg, WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant staging: KMBRIDGE_TEST_UNSET_KEY is not set")
+}
+
+func TestNewResolvesChannelNames(t *testing.T) {
+	var created []string
+	mattermostServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/teams/team-1/channels/name/alerts":
+			_, _ = w.Write([]byte(`{"id":"channel-alerts"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/channels":
+			created = append(created, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"channel-critical"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mattermostServer.Close()
+
+	cfg, fileCfg := testConfig()
+	cfg.Mattermost.URL = mattermostServer.URL
+	fileCfg.Channels.DefaultChannelID = "~alerts"
+	fileCfg.Channels.Routing = []config.RoutingRule{{Severity: "critical", ChannelID: "~alerts-critical"}}
+	require.NoError(t, fileCfg.Validate())
+	opts := []Option{
+		WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		WithPostRepository(&fakeRepository{}),
+	}
+
+	_, err := New(cfg, fileCfg, opts...)
+	require.ErrorContains(t, err, "channels given by name require MATTERMOST_TEAM_ID")
+
+	cfg.Mattermost.TeamID = "team-1"
+	_, err = New(cfg, fileCfg, opts...)
+	require.ErrorContains(t, err, "channel ~alerts-critical: no such channel in team team-1")
+
+	fileCfg.ChannelNames.Create = true
+	b, err := New(cfg, fileCfg, opts...)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, b.Close()) }()
+	assert.Len(t, created, 1)
+	assert.Equal(t, "channel-alerts", b.fileCfg.Channels.DefaultChannelID)
+	assert.Equal(t, "channel-critical", b.fileCfg.Channels.Routing[0].ChannelID)
+	assert.Equal(t, "~alerts", fileCfg.Channels.DefaultChannelID, "the given config is left alone")
+}
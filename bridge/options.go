@@ -0,0 +1,145 @@
+package bridge
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// Option customizes a Bridge built by New.
+type Option func(*Bridge)
+
+// WithLogger sets the base logger. Components derive their own loggers from it
+// with a "component" attribute. Defaults to slog.Default().
+func WithLogger(log *slog.Logger) Option {
+	return func(b *Bridge) {
+		b.log = log
+	}
+}
+
+// WithClock sets the clock used for job scheduling, retry backoff and
+// rendered durations. Defaults to the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(b *Bridge) {
+		b.clock = c
+	}
+}
+
+// WithPostRepository replaces the Valkey-backed post repository. When set, New
+// does not connect to Redis and Close leaves the repository untouched.
+func WithPostRepository(repo PostRepository) Option {
+	return func(b *Bridge) {
+		b.postRepo = repo
+	}
+}
+
+// WithGroupRepository replaces the Valkey-backed alert group repository. It is
+// required for alert grouping when WithPostRepository is used.
+func WithGroupRepository(repo group.Repository) Option {
+	return func(b *Bridge) {
+		b.groupRepo = repo
+	}
+}
+
+// WithCorrelationRepository replaces the Valkey-backed correlation repository.
+// It is required for correlation when WithPostRepository is used.
+func WithCorrelationRepository(repo correlation.Repository) Option {
+	return func(b *Bridge) {
+		b.correlationRepo = repo
+	}
+}
+
+// WithRetentionRepository replaces the Valkey-backed retention repository. It
+// is required for post retention when WithPostRepository is used.
+func WithRetentionRepository(repo retention.Repository) Option {
+	return func(b *Bridge) {
+		b.retentionRepo = repo
+	}
+}
+
+// WithDeadLetterRepository replaces the Valkey-backed dead-letter repository.
+// It is required for the dead-letter queue when WithPostRepository is used.
+func WithDeadLetterRepository(repo deadletter.Repository) Option {
+	return func(b *Bridge) {
+		b.deadLetterRepo = repo
+	}
+}
+
+// WithPendingActionRepository replaces the Valkey-backed store of actions
+// waiting for Keep. It is required for offline actions when
+// WithPostRepository is used.
+func WithPendingActionRepository(repo pendingaction.Repository) Option {
+	return func(b *Bridge) {
+		b.pendingRepo = repo
+	}
+}
+
+// WithQuietHoursRepository replaces the Valkey-backed store of alerts held
+// during quiet hours. It is required for quiet hours when WithPostRepository
+// is used.
+func WithQuietHoursRepository(repo quiethours.Repository) Option {
+	return func(b *Bridge) {
+		b.quietHoursRepo = repo
+	}
+}
+
+// WithAuditRepository replaces the Valkey-backed audit repository. It is
+// required for the audit trail when WithPostRepository is used.
+func WithAuditRepository(repo audit.Repository) Option {
+	return func(b *Bridge) {
+		b.auditRepo = repo
+	}
+}
+
+// WithIncidentRepository replaces the Valkey-backed incident repository. It
+// is required for incidents when WithPostRepository is used.
+func WithIncidentRepository(repo incident.Repository) Option {
+	return func(b *Bridge) {
+		b.incidentRepo = repo
+	}
+}
+
+// WithStatusBoardStore replaces the Valkey-backed store of status board
+// posts. It is required for the status board when WithPostRepository is used.
+func WithStatusBoardStore(store port.StatusBoardStore) Option {
+	return func(b *Bridge) {
+		b.statusBoardStore = store
+	}
+}
+
+// WithAlertStream replaces the Valkey stream used with INGEST_MODE=stream. It
+// is required for that mode when WithPostRepository is used.
+func WithAlertStream(stream port.AlertStream) Option {
+	return func(b *Bridge) {
+		b.alertStream = stream
+	}
+}
+
+// WithLocks replaces the Valkey-backed fingerprint locks and idempotency
+// keys. It is required for locking when WithPostRepository is used; a nil
+// idempotency store handles every webhook delivery.
+func WithLocks(locker port.Locker, idempotency port.IdempotencyStore) Option {
+	return func(b *Bridge) {
+		b.locker = locker
+		b.idempotency = idempotency
+	}
+}
+
+// WithRoutes registers additional routes on the router after the built-in
+// ones. It may be passed multiple times; registrars run in order.
+func WithRoutes(register func(router *gin.Engine)) Option {
+	return func(b *Bridge) {
+		b.routes = append(b.routes, register)
+	}
+}
@@ -0,0 +1,48 @@
+package bridge
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// newTenants wires a bridge for each tenant of the config file. Tenants share
+// the logger and clock but none of the repositories or clients given as
+// options: each opens its own store.
+func (b *Bridge) newTenants() error {
+	for _, t := range b.fileCfg.Tenants {
+		cfg, err := b.cfg.ForTenant(t)
+		if err != nil {
+			return err
+		}
+		fileCfg, err := b.fileCfg.ForTenant(t)
+		if err != nil {
+			return err
+		}
+		tenant, err := New(cfg, fileCfg, WithLogger(b.log.With("tenant", t.Name)), WithClock(b.clock))
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", t.Name, err)
+		}
+		b.tenants = append(b.tenants, tenant)
+		b.log.Info("tenant enabled", "tenant", t.Name, "base_path", cfg.Server.BasePath, "keep_url", cfg.Keep.URL)
+	}
+	return nil
+}
+
+// all returns b followed by its tenants.
+func (b *Bridge) all() []*Bridge {
+	return append([]*Bridge{b}, b.tenants...)
+}
+
+// serveTenants sends requests under a tenant's base path to the tenant's
+// router and the rest to b's.
+func (b *Bridge) serveTenants(w http.ResponseWriter, r *http.Request) {
+	for _, tenant := range b.tenants {
+		prefix := tenant.cfg.Server.BasePath
+		if r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, prefix+"/") {
+			tenant.router.ServeHTTP(w, r)
+			return
+		}
+	}
+	b.router.ServeHTTP(w, r)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/usecase"
+	"github.com/alexmorbo/keep-mattermost-bridge/bridge"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
+)
+
+// runBootstrap implements `kmbridge bootstrap`: it installs the Keep provider
+// and workflow, prints what it did and returns the exit code. With -dry-run
+// nothing is changed and the exit code is 3 when Keep differs from what the
+// bridge expects.
+func runBootstrap(cfg *config.Config, args []string, out io.Writer, log *slog.Logger) int {
+	fs := flag.NewFlagSet("bootstrap", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "show what would change in Keep without changing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := bridge.Bootstrap(ctx, cfg, *dryRun, log)
+	if err != nil {
+		log.Error("bootstrap failed", "error", err)
+		return 1
+	}
+
+	printBootstrapResult(out, result)
+	if *dryRun && result.Changed() {
+		return 3
+	}
+	return 0
+}
+
+func printBootstrapResult(out io.Writer, result usecase.KeepSetupResult) {
+	_, _ = fmt.Fprintf(out, "provider kmbridge: %s\n", result.Provider)
+	if result.ProviderURL != "" {
+		_, _ = fmt.Fprintf(out, "  it sends alerts to %s; update or delete it in Keep and run bootstrap again\n", result.ProviderURL)
+	}
+	_, _ = fmt.Fprintf(out, "workflow kmbridge-webhook: %s\n", result.Workflow)
+	if result.WorkflowDiff != "" {
+		_, _ = fmt.Fprint(out, result.WorkflowDiff)
+	}
+}
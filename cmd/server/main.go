@@ -2,31 +2,32 @@ package main
 
 import (
 	"context"
-	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
-	"strings"
-	"sync"
 	"syscall"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 
-	"github.com/alexmorbo/keep-mattermost-bridge/application/usecase"
+	"github.com/alexmorbo/keep-mattermost-bridge/bridge"
 	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
-	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/keep"
-	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/mattermost"
-	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/messagebuilder"
-	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/valkey"
-	httpInterface "github.com/alexmorbo/keep-mattermost-bridge/interface/http"
-	"github.com/alexmorbo/keep-mattermost-bridge/interface/http/handler"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
 )
 
 func main() {
+	reconcileOnStart := flag.Bool("reconcile-on-start", false, "reconcile Mattermost posts with Keep alerts before serving (same as RECONCILE_ON_START=true)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [bootstrap [-dry-run]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 0 && flag.Arg(0) != "bootstrap" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	log := logger.New("info")
 	slog.SetDefault(log)
 
@@ -39,9 +40,7 @@ func main() {
 	log = logger.New(cfg.Server.LogLevel)
 	slog.SetDefault(log)
 
-	log.Info("starting keep-mattermost-bridge", "addr", cfg.Server.Addr())
-
-	fileCfg, err := config.LoadFromFile(cfg.ConfigPath)
+	fileCfg, err := config.LoadFromFileAndEnv(cfg.ConfigPath, os.Environ())
 	if err != nil {
 		log.Error("failed to load file config", "error", err)
 		os.Exit(1)
@@ -50,169 +49,40 @@ func main() {
 	// Apply file config settings (env variables have priority)
 	cfg.ApplyFileConfig(fileCfg)
 
+	if *reconcileOnStart {
+		cfg.Reconcile.OnStart = true
+	}
+
 	// Re-validate config after applying file config
 	if err := cfg.Validate(); err != nil {
 		log.Error("invalid config after applying file config", "error", err)
 		os.Exit(1)
 	}
 
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.Redis.Addr,
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
-	})
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	if err := redisClient.Ping(ctx).Err(); err != nil {
-		cancel()
-		log.Error("failed to connect to valkey", "error", err)
-		os.Exit(1)
-	}
-	cancel()
-	log.Info("connected to valkey", "addr", cfg.Redis.Addr)
-
-	postRepo := valkey.NewPostRepository(redisClient, log.With("component", "valkey"))
-
-	mmClient := mattermost.NewClient(cfg.Mattermost.URL, cfg.Mattermost.Token, log.With("component", "mattermost_client"))
-
-	keepClient := keep.NewClient(cfg.Keep.URL, cfg.Keep.APIKey, log.With("component", "keep_client"))
-
-	// Ensure Keep setup (provider and workflow) if enabled
-	if cfg.Setup.Enabled {
-		// Webhook URL is derived from callback URL by replacing /callback with /webhook/alert
-		webhookURL := strings.Replace(cfg.CallbackURL, "/callback", "/webhook/alert", 1)
-		ensureSetupUC := usecase.NewEnsureKeepSetupUseCase(
-			keepClient,
-			webhookURL,
-			log.With("component", "ensure_keep_setup"),
-		)
-
-		setupCtx, setupCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		if err := ensureSetupUC.Execute(setupCtx); err != nil {
-			log.Warn("Failed to ensure Keep setup, continuing anyway", "error", err)
-		}
-		setupCancel()
-	} else {
-		log.Info("Keep setup disabled, skipping provider/workflow creation")
+	if flag.Arg(0) == "bootstrap" {
+		os.Exit(runBootstrap(cfg, flag.Args()[1:], os.Stdout, log))
 	}
 
-	msgBuilder := messagebuilder.NewBuilder(fileCfg)
-
-	handleAlertUC := usecase.NewHandleAlertUseCase(
-		postRepo,
-		mmClient,
-		keepClient,
-		msgBuilder,
-		fileCfg, // ChannelResolver - routes alerts to channels by severity
-		fileCfg, // UserMapper - maps between Mattermost and Keep usernames
-		cfg.Keep.UIURL,
-		cfg.CallbackURL,
-		log.With("component", "handle_alert_usecase"),
-	)
-
-	handleCallbackUC := usecase.NewHandleCallbackUseCase(
-		postRepo,
-		keepClient,
-		mmClient,
-		msgBuilder,
-		fileCfg,
-		cfg.Keep.UIURL,
-		cfg.CallbackURL,
-		log.With("component", "handle_callback_usecase"),
-	)
-
-	webhookHandler := handler.NewWebhookHandler(handleAlertUC, log.With("component", "webhook_handler"))
-	callbackHandler := handler.NewCallbackHandler(handleCallbackUC)
-	healthHandler := handler.NewHealthHandler(postRepo)
+	log.Info("starting keep-mattermost-bridge", "addr", cfg.Server.Addr())
 
 	gin.SetMode(gin.ReleaseMode)
-	router := httpInterface.NewRouter(log, webhookHandler, callbackHandler, healthHandler)
-
-	srv := &http.Server{
-		Addr:              cfg.Server.Addr(),
-		Handler:           router,
-		ReadHeaderTimeout: 10 * time.Second,
-		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      60 * time.Second,
-		IdleTimeout:       120 * time.Second,
-		MaxHeaderBytes:    1 << 20,
-	}
-
-	var pollWg sync.WaitGroup
-	pollDone := make(chan struct{})
-
-	if cfg.Polling.Enabled {
-		pollAlertsUC := usecase.NewPollAlertsUseCase(
-			postRepo,
-			keepClient,
-			mmClient,
-			msgBuilder,
-			fileCfg,
-			cfg.Keep.UIURL,
-			cfg.CallbackURL,
-			cfg.Polling.AlertsLimit,
-			log.With("component", "poll_alerts_usecase"),
-		)
-
-		pollWg.Add(1)
-		go func() {
-			defer pollWg.Done()
-			ticker := time.NewTicker(cfg.Polling.Interval)
-			defer ticker.Stop()
-
-			log.Info("polling started", "interval", cfg.Polling.Interval, "timeout", cfg.Polling.Timeout)
-
-			for {
-				select {
-				case <-ticker.C:
-					pollCtx, pollCancel := context.WithTimeout(context.Background(), cfg.Polling.Timeout)
-					if err := pollAlertsUC.Execute(pollCtx); err != nil {
-						log.Error("polling failed", "error", err)
-					}
-					pollCancel()
-				case <-pollDone:
-					log.Info("polling stopped")
-					return
-				}
-			}
-		}()
-	} else {
-		log.Info("polling disabled")
-	}
-
-	errCh := make(chan error, 1)
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errCh <- err
-		}
-	}()
-
-	log.Info("server started", "addr", cfg.Server.Addr())
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	select {
-	case err := <-errCh:
-		log.Error("server error", "error", err)
-	case <-quit:
-		log.Info("shutting down...")
+	b, err := bridge.New(cfg, fileCfg, bridge.WithLogger(log))
+	if err != nil {
+		log.Error("failed to initialize bridge", "error", err)
+		os.Exit(1)
 	}
 
-	close(pollDone)
-	pollWg.Wait()
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	runErr := b.Run(ctx)
+	stop()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Error("server forced to shutdown", "error", err)
+	if err := b.Close(); err != nil {
+		log.Error("failed to close bridge", "error", err)
 	}
 
-	handleCallbackUC.Wait()
-
-	if err := redisClient.Close(); err != nil {
-		log.Error("failed to close redis client", "error", err)
+	if runErr != nil {
+		os.Exit(1)
 	}
 
 	log.Info("server stopped")
@@ -14,6 +14,10 @@ type Alert struct {
 	source          string
 	labels          map[string]string
 	firingStartTime time.Time
+	enrichments     map[string]string
+	annotations     map[string]string
+	generatorURL    string
+	fireCount       int
 }
 
 func NewAlert(
@@ -86,3 +90,65 @@ func (a *Alert) Labels() map[string]string {
 	}
 	return result
 }
+
+// WithEnrichments returns a copy of a carrying the Keep enrichments of the
+// alert, e.g. an AI summary or notes added in Keep.
+func (a *Alert) WithEnrichments(enrichments map[string]string) *Alert {
+	copied := *a
+	copied.enrichments = make(map[string]string, len(enrichments))
+	for k, v := range enrichments {
+		copied.enrichments[k] = v
+	}
+	return &copied
+}
+
+func (a *Alert) Enrichments() map[string]string {
+	result := make(map[string]string, len(a.enrichments))
+	for k, v := range a.enrichments {
+		result[k] = v
+	}
+	return result
+}
+
+// WithAnnotations returns a copy of a carrying its annotations, e.g. summary
+// or runbook_url, which describe the alert rather than identify it as labels
+// do.
+func (a *Alert) WithAnnotations(annotations map[string]string) *Alert {
+	copied := *a
+	copied.annotations = make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		copied.annotations[k] = v
+	}
+	return &copied
+}
+
+func (a *Alert) Annotations() map[string]string {
+	result := make(map[string]string, len(a.annotations))
+	for k, v := range a.annotations {
+		result[k] = v
+	}
+	return result
+}
+
+// WithGeneratorURL returns a copy of a carrying the URL of the alert in the
+// system that generated it, e.g. the Prometheus expression or the Grafana
+// alert rule.
+func (a *Alert) WithGeneratorURL(url string) *Alert {
+	copied := *a
+	copied.generatorURL = url
+	return &copied
+}
+
+// GeneratorURL returns the URL set by WithGeneratorURL, or "".
+func (a *Alert) GeneratorURL() string { return a.generatorURL }
+
+// WithFireCount returns a copy of a carrying how many times the alert fired
+// since its post was created.
+func (a *Alert) WithFireCount(count int) *Alert {
+	copied := *a
+	copied.fireCount = count
+	return &copied
+}
+
+// FireCount returns the count set by WithFireCount, or 0 when unknown.
+func (a *Alert) FireCount() int { return a.fireCount }
@@ -147,6 +147,18 @@ func TestNewSeverity(t *testing.T) {
 			expected:    SeverityInfo,
 			expectError: false,
 		},
+		{
+			name:        "low severity",
+			value:       SeverityLow,
+			expected:    SeverityLow,
+			expectError: false,
+		},
+		{
+			name:        "debug severity",
+			value:       "Debug",
+			expected:    SeverityDebug,
+			expectError: false,
+		},
 		{
 			name:        "invalid severity",
 			value:       "invalid",
@@ -229,6 +241,26 @@ func TestSeverityIsInfo(t *testing.T) {
 	assert.True(t, info.IsInfo())
 }
 
+func TestSeverityRank(t *testing.T) {
+	assert.Greater(t, RestoreSeverity(SeverityCritical).Rank(), RestoreSeverity(SeverityHigh).Rank())
+	assert.Greater(t, RestoreSeverity(SeverityHigh).Rank(), RestoreSeverity(SeverityWarning).Rank())
+	assert.Greater(t, RestoreSeverity(SeverityWarning).Rank(), RestoreSeverity(SeverityInfo).Rank())
+	assert.Greater(t, RestoreSeverity(SeverityInfo).Rank(), RestoreSeverity(SeverityLow).Rank())
+	assert.Greater(t, RestoreSeverity(SeverityLow).Rank(), RestoreSeverity(SeverityDebug).Rank())
+	assert.Equal(t, 0, RestoreSeverity("unknown").Rank())
+}
+
+func TestSeverityMapNormalize(t *testing.T) {
+	m := NewSeverityMap(map[string]string{"SEV1": "Critical", "p2": SeverityHigh})
+
+	assert.Equal(t, SeverityCritical, m.Normalize("sev1"))
+	assert.Equal(t, SeverityHigh, m.Normalize(" P2 "))
+	assert.Equal(t, "warning", m.Normalize("warning"), "unmapped values are kept")
+
+	var none SeverityMap
+	assert.Equal(t, "sev1", none.Normalize("sev1"))
+}
+
 // Status tests
 func TestNewStatus(t *testing.T) {
 	tests := []struct {
@@ -303,6 +335,18 @@ func TestNewStatus(t *testing.T) {
 			expected:    StatusMaintenance,
 			expectError: false,
 		},
+		{
+			name:        "dismissed status",
+			value:       StatusDismissed,
+			expected:    StatusDismissed,
+			expectError: false,
+		},
+		{
+			name:        "merged status uppercase",
+			value:       "MERGED",
+			expected:    StatusMerged,
+			expectError: false,
+		},
 		{
 			name:        "invalid status",
 			value:       "invalid",
@@ -391,6 +435,31 @@ func TestStatusIsMaintenance(t *testing.T) {
 	assert.True(t, maintenance.IsMaintenance())
 }
 
+func TestStatusIsDismissed(t *testing.T) {
+	firing := RestoreStatus(StatusFiring)
+	dismissed := RestoreStatus(StatusDismissed)
+
+	assert.False(t, firing.IsDismissed())
+	assert.True(t, dismissed.IsDismissed())
+}
+
+func TestStatusIsMerged(t *testing.T) {
+	firing := RestoreStatus(StatusFiring)
+	merged := RestoreStatus(StatusMerged)
+
+	assert.False(t, firing.IsMerged())
+	assert.True(t, merged.IsMerged())
+}
+
+func TestStatusIsClosed(t *testing.T) {
+	for _, value := range []string{StatusResolved, StatusDismissed, StatusMerged} {
+		assert.True(t, RestoreStatus(value).IsClosed(), value)
+	}
+	for _, value := range []string{StatusFiring, StatusAcknowledged, StatusSuppressed, StatusPending, StatusMaintenance} {
+		assert.False(t, RestoreStatus(value).IsClosed(), value)
+	}
+}
+
 // Alert entity tests
 func TestNewAlert(t *testing.T) {
 	validFingerprint := RestoreFingerprint("fp-123")
@@ -640,3 +709,47 @@ func TestAlertFiringStartTime(t *testing.T) {
 		assert.False(t, alert.FiringStartTime().IsZero())
 	})
 }
+
+func TestAlertWithEnrichments(t *testing.T) {
+	original := RestoreAlert(
+		RestoreFingerprint("fp-enriched"),
+		"Alert",
+		RestoreSeverity(SeverityCritical),
+		RestoreStatus(StatusFiring),
+		"Description",
+		"source",
+		map[string]string{"host": "db-1"},
+		time.Time{},
+	)
+	enrichments := map[string]string{"ai_summary": "Disk is filling up"}
+
+	enriched := original.WithEnrichments(enrichments)
+	enrichments["ai_summary"] = "changed"
+
+	assert.Equal(t, map[string]string{"ai_summary": "Disk is filling up"}, enriched.Enrichments())
+	assert.Empty(t, original.Enrichments(), "the original alert is left unchanged")
+	assert.Equal(t, original.Labels(), enriched.Labels())
+	assert.Equal(t, original.Name(), enriched.Name())
+}
+
+func TestAlertWithAnnotations(t *testing.T) {
+	original := RestoreAlert(
+		RestoreFingerprint("fp-annotated"),
+		"Alert",
+		RestoreSeverity(SeverityCritical),
+		RestoreStatus(StatusFiring),
+		"Description",
+		"source",
+		map[string]string{"host": "db-1"},
+		time.Time{},
+	).WithEnrichments(map[string]string{"note": "n"})
+	annotations := map[string]string{"summary": "Disk is filling up"}
+
+	annotated := original.WithAnnotations(annotations)
+	annotations["summary"] = "changed"
+
+	assert.Equal(t, map[string]string{"summary": "Disk is filling up"}, annotated.Annotations())
+	assert.Empty(t, original.Annotations(), "the original alert is left unchanged")
+	assert.Equal(t, original.Labels(), annotated.Labels(), "annotations are kept apart from labels")
+	assert.Equal(t, original.Enrichments(), annotated.Enrichments())
+}
@@ -15,6 +15,7 @@ const (
 	SeverityWarning  = "warning"
 	SeverityInfo     = "info"
 	SeverityLow      = "low"
+	SeverityDebug    = "debug"
 )
 
 var validSeverities = map[string]bool{
@@ -23,6 +24,7 @@ var validSeverities = map[string]bool{
 	SeverityWarning:  true,
 	SeverityInfo:     true,
 	SeverityLow:      true,
+	SeverityDebug:    true,
 }
 
 func NewSeverity(value string) (Severity, error) {
@@ -60,3 +62,42 @@ func (s Severity) IsWarning() bool {
 func (s Severity) IsInfo() bool {
 	return s.value == SeverityInfo
 }
+
+var severityRanks = map[string]int{
+	SeverityDebug:    1,
+	SeverityLow:      2,
+	SeverityInfo:     3,
+	SeverityWarning:  4,
+	SeverityHigh:     5,
+	SeverityCritical: 6,
+}
+
+// Rank orders severities from least (1) to most (6) urgent. Unknown values rank 0.
+func (s Severity) Rank() int {
+	return severityRanks[s.value]
+}
+
+// SeverityMap maps severities that sources send in their own formats, e.g.
+// "sev1" or "P2", onto the canonical ones. Keys match case-insensitively.
+type SeverityMap map[string]string
+
+// NewSeverityMap returns m keyed by lower-cased input severities.
+func NewSeverityMap(m map[string]string) SeverityMap {
+	if len(m) == 0 {
+		return nil
+	}
+	normalized := make(SeverityMap, len(m))
+	for from, to := range m {
+		normalized[strings.ToLower(strings.TrimSpace(from))] = strings.ToLower(to)
+	}
+	return normalized
+}
+
+// Normalize returns the canonical severity value maps to, or value unchanged
+// when it is not mapped.
+func (m SeverityMap) Normalize(value string) string {
+	if to, ok := m[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return to
+	}
+	return value
+}
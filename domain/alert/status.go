@@ -16,6 +16,8 @@ const (
 	StatusSuppressed   = "suppressed"
 	StatusPending      = "pending"
 	StatusMaintenance  = "maintenance"
+	StatusDismissed    = "dismissed"
+	StatusMerged       = "merged"
 )
 
 var validStatuses = map[string]bool{
@@ -25,6 +27,8 @@ var validStatuses = map[string]bool{
 	StatusSuppressed:   true,
 	StatusPending:      true,
 	StatusMaintenance:  true,
+	StatusDismissed:    true,
+	StatusMerged:       true,
 }
 
 func NewStatus(value string) (Status, error) {
@@ -70,3 +74,17 @@ func (s Status) IsPending() bool {
 func (s Status) IsMaintenance() bool {
 	return s.value == StatusMaintenance
 }
+
+func (s Status) IsDismissed() bool {
+	return s.value == StatusDismissed
+}
+
+func (s Status) IsMerged() bool {
+	return s.value == StatusMerged
+}
+
+// IsClosed reports whether the alert needs no further action: it is resolved,
+// dismissed or merged into another alert.
+func (s Status) IsClosed() bool {
+	return s.IsResolved() || s.IsDismissed() || s.IsMerged()
+}
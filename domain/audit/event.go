@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Kinds of lifecycle events recorded for an alert.
+const (
+	KindReceived       = "received"
+	KindPosted         = "posted"
+	KindRefired        = "refired"
+	KindAcknowledged   = "acknowledged"
+	KindAssigned       = "assigned"
+	KindUnacknowledged = "unacknowledged"
+	KindSnoozed        = "snoozed"
+	KindResolved       = "resolved"
+	KindEscalated      = "escalated"
+	KindSeverityChange = "severity_changed"
+	KindMaintenance    = "maintenance"
+	KindFlapping       = "flapping"
+	KindQuietHours     = "quiet_hours"
+	KindCustomAction   = "custom_action"
+	KindWorkflowRun    = "workflow_run"
+)
+
+// Event is one step in the life of an alert. Actor is the Mattermost user
+// who caused it, or empty when it came from Keep or the bridge itself.
+type Event struct {
+	fingerprint alert.Fingerprint
+	kind        string
+	actor       string
+	detail      string
+	at          time.Time
+}
+
+func NewEvent(fingerprint alert.Fingerprint, kind, actor, detail string, at time.Time) *Event {
+	return &Event{
+		fingerprint: fingerprint,
+		kind:        kind,
+		actor:       actor,
+		detail:      detail,
+		at:          at,
+	}
+}
+
+func (e *Event) Fingerprint() alert.Fingerprint { return e.fingerprint }
+func (e *Event) Kind() string                   { return e.kind }
+func (e *Event) Actor() string                  { return e.actor }
+func (e *Event) Detail() string                 { return e.detail }
+func (e *Event) At() time.Time                  { return e.at }
+
+// ReceivedDetail is the detail of a KindReceived event.
+func ReceivedDetail(status alert.Status, severity alert.Severity) string {
+	return fmt.Sprintf("status %s, severity %s", status, severity)
+}
+
+// ParseReceivedDetail returns the status and severity of a KindReceived
+// event's detail. It reports false for details not written by
+// ReceivedDetail.
+func ParseReceivedDetail(detail string) (alert.Status, alert.Severity, bool) {
+	var status, severity string
+	if _, err := fmt.Sscanf(detail, "status %s severity %s", &status, &severity); err != nil {
+		return alert.Status{}, alert.Severity{}, false
+	}
+	return alert.RestoreStatus(strings.TrimSuffix(status, ",")), alert.RestoreSeverity(severity), true
+}
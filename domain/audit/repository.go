@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type Repository interface {
+	// Append adds the event to the end of its alert's history.
+	Append(ctx context.Context, e *Event) error
+	// FindByFingerprint returns the history of an alert, oldest event first.
+	// An alert without recorded events has an empty history.
+	FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) ([]*Event, error)
+	// FindBetween returns the events of every alert recorded from from up to
+	// but excluding to, oldest first.
+	FindBetween(ctx context.Context, from, to time.Time) ([]*Event, error)
+}
@@ -0,0 +1,5 @@
+package correlation
+
+import "errors"
+
+var ErrNotFound = errors.New("correlation not found")
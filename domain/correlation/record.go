@@ -0,0 +1,107 @@
+package correlation
+
+import (
+	"slices"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Record links an alert to everything created for it in other systems: the
+// Mattermost posts showing it, the Keep incident it belongs to and the ticket
+// opened for it. Any of these identifiers leads back to the record.
+type Record struct {
+	fingerprint alert.Fingerprint
+	postIDs     []string
+	incidentID  string
+	ticketKey   string
+	ticketURL   string
+	updatedAt   time.Time
+}
+
+// NewRecord returns an empty record updated at now.
+func NewRecord(fingerprint alert.Fingerprint, now time.Time) *Record {
+	return &Record{
+		fingerprint: fingerprint,
+		updatedAt:   now,
+	}
+}
+
+func RestoreRecord(fingerprint alert.Fingerprint, postIDs []string, incidentID, ticketKey, ticketURL string, updatedAt time.Time) *Record {
+	return &Record{
+		fingerprint: fingerprint,
+		postIDs:     postIDs,
+		incidentID:  incidentID,
+		ticketKey:   ticketKey,
+		ticketURL:   ticketURL,
+		updatedAt:   updatedAt,
+	}
+}
+
+func (r *Record) Fingerprint() alert.Fingerprint { return r.fingerprint }
+func (r *Record) IncidentID() string             { return r.incidentID }
+func (r *Record) TicketKey() string              { return r.ticketKey }
+func (r *Record) TicketURL() string              { return r.ticketURL }
+func (r *Record) UpdatedAt() time.Time           { return r.updatedAt }
+
+// PostIDs returns a copy of the Mattermost post IDs in the order they were
+// added; the tracked channel post comes first.
+func (r *Record) PostIDs() []string { return slices.Clone(r.postIDs) }
+
+// AddPostID records a post showing the alert at now. It returns false if the
+// post was already recorded.
+func (r *Record) AddPostID(postID string, now time.Time) bool {
+	if postID == "" || slices.Contains(r.postIDs, postID) {
+		return false
+	}
+	r.postIDs = append(r.postIDs, postID)
+	r.updatedAt = now
+	return true
+}
+
+// SetIncident records the Keep incident the alert belongs to at now. An
+// empty ID keeps the current one. It returns false if nothing changed.
+func (r *Record) SetIncident(incidentID string, now time.Time) bool {
+	if incidentID == "" || incidentID == r.incidentID {
+		return false
+	}
+	r.incidentID = incidentID
+	r.updatedAt = now
+	return true
+}
+
+// SetTicket records the ticket opened for the alert at now. An empty key
+// keeps the current ticket; an empty URL keeps the current URL. It returns
+// false if nothing changed.
+func (r *Record) SetTicket(key, url string, now time.Time) bool {
+	changed := false
+	if key != "" && key != r.ticketKey {
+		r.ticketKey = key
+		changed = true
+	}
+	if url != "" && url != r.ticketURL {
+		r.ticketURL = url
+		changed = true
+	}
+	if changed {
+		r.updatedAt = now
+	}
+	return changed
+}
+
+// IDs returns every identifier the record can be found by.
+func (r *Record) IDs() []string {
+	ids := append([]string{r.fingerprint.Value()}, r.postIDs...)
+	if r.incidentID != "" {
+		ids = append(ids, r.incidentID)
+	}
+	if r.ticketKey != "" {
+		ids = append(ids, r.ticketKey)
+	}
+	return ids
+}
+
+// Has reports whether id is one of the record's identifiers.
+func (r *Record) Has(id string) bool {
+	return id != "" && slices.Contains(r.IDs(), id)
+}
@@ -0,0 +1,43 @@
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestRecordLinks(t *testing.T) {
+	r := NewRecord(alert.RestoreFingerprint("fp-1"), time.Now())
+
+	assert.True(t, r.AddPostID("post-1", time.Now()))
+	assert.True(t, r.AddPostID("post-2", time.Now()))
+	assert.False(t, r.AddPostID("post-1", time.Now()))
+	assert.False(t, r.AddPostID("", time.Now()))
+	assert.Equal(t, []string{"post-1", "post-2"}, r.PostIDs())
+
+	assert.False(t, r.SetIncident("", time.Now()))
+	assert.True(t, r.SetIncident("inc-1", time.Now()))
+	assert.False(t, r.SetIncident("inc-1", time.Now()))
+
+	assert.True(t, r.SetTicket("OPS-42", "", time.Now()))
+	assert.True(t, r.SetTicket("", "https://jira.example.com/browse/OPS-42", time.Now()))
+	assert.False(t, r.SetTicket("OPS-42", "https://jira.example.com/browse/OPS-42", time.Now()))
+	assert.Equal(t, "OPS-42", r.TicketKey())
+	assert.Equal(t, "https://jira.example.com/browse/OPS-42", r.TicketURL())
+
+	assert.Equal(t, []string{"fp-1", "post-1", "post-2", "inc-1", "OPS-42"}, r.IDs())
+	assert.True(t, r.Has("post-2"))
+	assert.True(t, r.Has("OPS-42"))
+	assert.False(t, r.Has("OPS-43"))
+	assert.False(t, r.Has(""))
+}
+
+func TestRecordPostIDsCopy(t *testing.T) {
+	r := RestoreRecord(alert.RestoreFingerprint("fp-1"), []string{"post-1"}, "", "", "", time.Time{})
+	ids := r.PostIDs()
+	ids[0] = "changed"
+	assert.Equal(t, []string{"post-1"}, r.PostIDs())
+}
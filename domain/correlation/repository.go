@@ -0,0 +1,15 @@
+package correlation
+
+import (
+	"context"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type Repository interface {
+	Save(ctx context.Context, r *Record) error
+	FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*Record, error)
+	// FindByID returns the record holding id as its fingerprint, one of its
+	// post IDs, its incident ID or its ticket key.
+	FindByID(ctx context.Context, id string) (*Record, error)
+}
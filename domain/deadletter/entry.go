@@ -0,0 +1,90 @@
+package deadletter
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Kinds of failed deliveries kept in the dead-letter queue.
+const (
+	// KindAlert is a Keep alert webhook whose post could not be created or
+	// updated. It is re-delivered by processing the alert again.
+	KindAlert = "alert"
+	// KindUpdate is a post update, e.g. from a button press, that Mattermost
+	// rejected. It is re-delivered by updating the post again.
+	KindUpdate = "update"
+)
+
+// Entry is a failed Mattermost delivery waiting to be re-delivered. There is
+// at most one entry per alert fingerprint or post: a newer failure replaces
+// the older one, since only the latest state is worth delivering.
+type Entry struct {
+	kind          string
+	key           string
+	payload       []byte
+	attachment    post.Attachment
+	lastError     string
+	attempts      int
+	failedAt      time.Time
+	lastAttemptAt time.Time
+}
+
+// NewAlertEntry keeps the webhook payload of the alert with fingerprint.
+func NewAlertEntry(fingerprint string, payload []byte, lastError string, failedAt time.Time) *Entry {
+	return &Entry{
+		kind:          KindAlert,
+		key:           fingerprint,
+		payload:       payload,
+		lastError:     lastError,
+		failedAt:      failedAt,
+		lastAttemptAt: failedAt,
+	}
+}
+
+// NewUpdateEntry keeps the attachment postID should have been updated to.
+func NewUpdateEntry(postID string, attachment post.Attachment, lastError string, failedAt time.Time) *Entry {
+	return &Entry{
+		kind:          KindUpdate,
+		key:           postID,
+		attachment:    attachment,
+		lastError:     lastError,
+		failedAt:      failedAt,
+		lastAttemptAt: failedAt,
+	}
+}
+
+func RestoreEntry(kind, key string, payload []byte, attachment post.Attachment, lastError string, attempts int, failedAt, lastAttemptAt time.Time) *Entry {
+	return &Entry{
+		kind:          kind,
+		key:           key,
+		payload:       payload,
+		attachment:    attachment,
+		lastError:     lastError,
+		attempts:      attempts,
+		failedAt:      failedAt,
+		lastAttemptAt: lastAttemptAt,
+	}
+}
+
+// EntryID returns the ID of the entry of kind for key.
+func EntryID(kind, key string) string {
+	return kind + ":" + key
+}
+
+func (e *Entry) ID() string                  { return EntryID(e.kind, e.key) }
+func (e *Entry) Kind() string                { return e.kind }
+func (e *Entry) Key() string                 { return e.key }
+func (e *Entry) Payload() []byte             { return e.payload }
+func (e *Entry) Attachment() post.Attachment { return e.attachment }
+func (e *Entry) LastError() string           { return e.lastError }
+func (e *Entry) Attempts() int               { return e.attempts }
+func (e *Entry) FailedAt() time.Time         { return e.failedAt }
+func (e *Entry) LastAttemptAt() time.Time    { return e.lastAttemptAt }
+
+// RecordFailure counts a failed re-delivery attempt.
+func (e *Entry) RecordFailure(lastError string, at time.Time) {
+	e.attempts++
+	e.lastError = lastError
+	e.lastAttemptAt = at
+}
@@ -0,0 +1,5 @@
+package deadletter
+
+import "errors"
+
+var ErrNotFound = errors.New("dead-letter entry not found")
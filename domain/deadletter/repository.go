@@ -0,0 +1,12 @@
+package deadletter
+
+import "context"
+
+type Repository interface {
+	// Save stores the entry, replacing any entry with the same ID.
+	Save(ctx context.Context, e *Entry) error
+	FindByID(ctx context.Context, id string) (*Entry, error)
+	// FindAll returns every entry, oldest failure first.
+	FindAll(ctx context.Context) ([]*Entry, error)
+	Delete(ctx context.Context, id string) error
+}
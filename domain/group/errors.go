@@ -0,0 +1,5 @@
+package group
+
+import "errors"
+
+var ErrNotFound = errors.New("group not found")
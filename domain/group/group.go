@@ -0,0 +1,129 @@
+package group
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Group collects related alerts under a single root Mattermost post. Alerts
+// belong to the same group when they share a grouping label value and are
+// routed to the same channel.
+type Group struct {
+	key         string
+	value       string
+	channelID   string
+	rootPostID  string
+	members     map[string]alert.Severity // active alerts by fingerprint
+	total       int
+	createdAt   time.Time
+	lastUpdated time.Time
+}
+
+// ID derives the storage identifier of the group for a channel and label pair.
+func ID(channelID, key, value string) string {
+	return channelID + ":" + key + "=" + value
+}
+
+// NewGroup returns an empty group created and last updated at now.
+func NewGroup(key, value, channelID, rootPostID string, now time.Time) *Group {
+	return &Group{
+		key:         key,
+		value:       value,
+		channelID:   channelID,
+		rootPostID:  rootPostID,
+		members:     make(map[string]alert.Severity),
+		createdAt:   now,
+		lastUpdated: now,
+	}
+}
+
+func RestoreGroup(key, value, channelID, rootPostID string, members map[string]alert.Severity, total int, createdAt, lastUpdated time.Time) *Group {
+	if members == nil {
+		members = make(map[string]alert.Severity)
+	}
+	return &Group{
+		key:         key,
+		value:       value,
+		channelID:   channelID,
+		rootPostID:  rootPostID,
+		members:     members,
+		total:       total,
+		createdAt:   createdAt,
+		lastUpdated: lastUpdated,
+	}
+}
+
+func (g *Group) ID() string             { return ID(g.channelID, g.key, g.value) }
+func (g *Group) Key() string            { return g.key }
+func (g *Group) Value() string          { return g.value }
+func (g *Group) ChannelID() string      { return g.channelID }
+func (g *Group) RootPostID() string     { return g.rootPostID }
+func (g *Group) Total() int             { return g.total }
+func (g *Group) ActiveCount() int       { return len(g.members) }
+func (g *Group) CreatedAt() time.Time   { return g.createdAt }
+func (g *Group) LastUpdated() time.Time { return g.lastUpdated }
+
+// Members returns a copy of the active alerts keyed by fingerprint.
+func (g *Group) Members() map[string]alert.Severity {
+	result := make(map[string]alert.Severity, len(g.members))
+	for fp, sev := range g.members {
+		result[fp] = sev
+	}
+	return result
+}
+
+// Fingerprints returns the active member fingerprints in sorted order.
+func (g *Group) Fingerprints() []string {
+	result := make([]string, 0, len(g.members))
+	for fp := range g.members {
+		result = append(result, fp)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Add records an active alert at now. It returns false if the fingerprint
+// was already active, in which case only its severity is refreshed.
+func (g *Group) Add(fingerprint string, severity alert.Severity, now time.Time) bool {
+	_, exists := g.members[fingerprint]
+	g.members[fingerprint] = severity
+	if !exists {
+		g.total++
+	}
+	g.lastUpdated = now
+	return !exists
+}
+
+// Remove drops an alert from the active set at now. It returns false if the
+// fingerprint was not an active member.
+func (g *Group) Remove(fingerprint string, now time.Time) bool {
+	if _, exists := g.members[fingerprint]; !exists {
+		return false
+	}
+	delete(g.members, fingerprint)
+	g.lastUpdated = now
+	return true
+}
+
+func (g *Group) SetRootPostID(postID string) {
+	g.rootPostID = postID
+}
+
+func (g *Group) Has(fingerprint string) bool {
+	_, exists := g.members[fingerprint]
+	return exists
+}
+
+// HighestSeverity returns the most urgent severity among active members, or
+// the zero Severity when the group has no active alerts.
+func (g *Group) HighestSeverity() alert.Severity {
+	var highest alert.Severity
+	for _, sev := range g.members {
+		if sev.Rank() > highest.Rank() {
+			highest = sev
+		}
+	}
+	return highest
+}
@@ -0,0 +1,73 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestNewGroup(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	g := NewGroup("alertgroup", "database", "channel-1", "root-1", now)
+
+	assert.Equal(t, "alertgroup", g.Key())
+	assert.Equal(t, "database", g.Value())
+	assert.Equal(t, "channel-1", g.ChannelID())
+	assert.Equal(t, "root-1", g.RootPostID())
+	assert.Equal(t, "channel-1:alertgroup=database", g.ID())
+	assert.Equal(t, 0, g.ActiveCount())
+	assert.Equal(t, 0, g.Total())
+	assert.Equal(t, now, g.CreatedAt())
+	assert.Equal(t, now, g.LastUpdated())
+}
+
+func TestGroupAddRemove(t *testing.T) {
+	g := NewGroup("service", "api", "channel-1", "root-1", time.Now())
+
+	assert.True(t, g.Add("fp-1", alert.RestoreSeverity("warning"), time.Now()))
+	assert.True(t, g.Add("fp-2", alert.RestoreSeverity("critical"), time.Now()))
+	assert.False(t, g.Add("fp-1", alert.RestoreSeverity("high"), time.Now()), "re-adding an active member is not new")
+
+	assert.Equal(t, 2, g.ActiveCount())
+	assert.Equal(t, 2, g.Total())
+	assert.Equal(t, []string{"fp-1", "fp-2"}, g.Fingerprints())
+	assert.Equal(t, "critical", g.HighestSeverity().String())
+
+	assert.True(t, g.Remove("fp-2", time.Now()))
+	assert.False(t, g.Remove("fp-2", time.Now()))
+	assert.Equal(t, 1, g.ActiveCount())
+	assert.Equal(t, 2, g.Total(), "total counts every alert ever added")
+	assert.Equal(t, "high", g.HighestSeverity().String())
+	assert.True(t, g.Has("fp-1"))
+	assert.False(t, g.Has("fp-2"))
+}
+
+func TestGroupHighestSeverityEmpty(t *testing.T) {
+	g := NewGroup("service", "api", "channel-1", "root-1", time.Now())
+	assert.Equal(t, "", g.HighestSeverity().String())
+}
+
+func TestRestoreGroup(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	lastUpdated := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	members := map[string]alert.Severity{"fp-1": alert.RestoreSeverity("info")}
+
+	g := RestoreGroup("alertgroup", "db", "channel-1", "root-1", members, 3, createdAt, lastUpdated)
+
+	assert.Equal(t, 1, g.ActiveCount())
+	assert.Equal(t, 3, g.Total())
+	assert.Equal(t, createdAt, g.CreatedAt())
+	assert.Equal(t, lastUpdated, g.LastUpdated())
+
+	copied := g.Members()
+	delete(copied, "fp-1")
+	assert.Equal(t, 1, g.ActiveCount(), "Members returns a copy")
+}
+
+func TestRestoreGroupNilMembers(t *testing.T) {
+	g := RestoreGroup("k", "v", "c", "r", nil, 0, time.Time{}, time.Time{})
+	assert.True(t, g.Add("fp-1", alert.RestoreSeverity("low"), time.Now()))
+}
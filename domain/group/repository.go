@@ -0,0 +1,9 @@
+package group
+
+import "context"
+
+type Repository interface {
+	Save(ctx context.Context, g *Group) error
+	FindByID(ctx context.Context, id string) (*Group, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,5 @@
+package incident
+
+import "errors"
+
+var ErrNotFound = errors.New("incident not found")
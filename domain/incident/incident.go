@@ -0,0 +1,128 @@
+package incident
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Statuses of a Keep incident.
+const (
+	StatusFiring       = "firing"
+	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
+	StatusMerged       = "merged"
+	StatusDeleted      = "deleted"
+)
+
+var validStatuses = map[string]bool{
+	StatusFiring:       true,
+	StatusAcknowledged: true,
+	StatusResolved:     true,
+	StatusMerged:       true,
+	StatusDeleted:      true,
+}
+
+// IsValidStatus reports whether status is one Keep reports for incidents.
+func IsValidStatus(status string) bool {
+	return validStatuses[status]
+}
+
+// Incident is a Keep incident and the Mattermost post that shows it. Keep
+// groups related alerts into incidents; the post tracks how many are linked.
+type Incident struct {
+	id             string
+	name           string
+	severity       alert.Severity
+	status         string
+	alertsCount    int
+	startedAt      time.Time
+	postID         string
+	channelID      string
+	acknowledgedBy string
+	createdAt      time.Time
+	lastUpdated    time.Time
+}
+
+// NewIncident returns an incident created and last updated at now.
+func NewIncident(id, name string, severity alert.Severity, status string, alertsCount int, startedAt, now time.Time) *Incident {
+	return &Incident{
+		id:          id,
+		name:        name,
+		severity:    severity,
+		status:      status,
+		alertsCount: alertsCount,
+		startedAt:   startedAt,
+		createdAt:   now,
+		lastUpdated: now,
+	}
+}
+
+func RestoreIncident(id, name string, severity alert.Severity, status string, alertsCount int, startedAt time.Time, postID, channelID, acknowledgedBy string, createdAt, lastUpdated time.Time) *Incident {
+	return &Incident{
+		id:             id,
+		name:           name,
+		severity:       severity,
+		status:         status,
+		alertsCount:    alertsCount,
+		startedAt:      startedAt,
+		postID:         postID,
+		channelID:      channelID,
+		acknowledgedBy: acknowledgedBy,
+		createdAt:      createdAt,
+		lastUpdated:    lastUpdated,
+	}
+}
+
+func (i *Incident) ID() string               { return i.id }
+func (i *Incident) Name() string             { return i.name }
+func (i *Incident) Severity() alert.Severity { return i.severity }
+func (i *Incident) Status() string           { return i.status }
+func (i *Incident) AlertsCount() int         { return i.alertsCount }
+func (i *Incident) StartedAt() time.Time     { return i.startedAt }
+func (i *Incident) PostID() string           { return i.postID }
+func (i *Incident) ChannelID() string        { return i.channelID }
+func (i *Incident) AcknowledgedBy() string   { return i.acknowledgedBy }
+func (i *Incident) CreatedAt() time.Time     { return i.createdAt }
+func (i *Incident) LastUpdated() time.Time   { return i.lastUpdated }
+
+// IsClosed reports whether Keep no longer considers the incident active.
+func (i *Incident) IsClosed() bool {
+	switch i.status {
+	case StatusResolved, StatusMerged, StatusDeleted:
+		return true
+	}
+	return false
+}
+
+// Attach records the post that shows the incident.
+func (i *Incident) Attach(postID, channelID string) {
+	i.postID = postID
+	i.channelID = channelID
+}
+
+// Update applies the latest state Keep reported for the incident at now.
+// Returning to firing clears the acknowledgement.
+func (i *Incident) Update(name string, severity alert.Severity, status string, alertsCount int, now time.Time) {
+	i.name = name
+	i.severity = severity
+	if status == StatusFiring {
+		i.acknowledgedBy = ""
+	}
+	i.status = status
+	i.alertsCount = alertsCount
+	i.lastUpdated = now
+}
+
+// Acknowledge records that username acknowledged the incident at now.
+func (i *Incident) Acknowledge(username string, now time.Time) {
+	i.status = StatusAcknowledged
+	i.acknowledgedBy = username
+	i.lastUpdated = now
+}
+
+// Resolve records that the incident was resolved from Mattermost at now.
+func (i *Incident) Resolve(now time.Time) {
+	i.status = StatusResolved
+	i.lastUpdated = now
+}
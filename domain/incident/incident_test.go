@@ -0,0 +1,59 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestNewIncident(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	inc := NewIncident("inc-1", "Database outage", alert.RestoreSeverity("critical"), StatusFiring, 3, startedAt, time.Now())
+
+	assert.Equal(t, "inc-1", inc.ID())
+	assert.Equal(t, "Database outage", inc.Name())
+	assert.Equal(t, "critical", inc.Severity().String())
+	assert.Equal(t, StatusFiring, inc.Status())
+	assert.Equal(t, 3, inc.AlertsCount())
+	assert.Equal(t, startedAt, inc.StartedAt())
+	assert.Empty(t, inc.PostID())
+	assert.False(t, inc.IsClosed())
+}
+
+func TestIncidentAcknowledgeAndRefire(t *testing.T) {
+	inc := NewIncident("inc-1", "Database outage", alert.RestoreSeverity("high"), StatusFiring, 1, time.Now(), time.Now())
+
+	inc.Acknowledge("alice", time.Now())
+	assert.Equal(t, StatusAcknowledged, inc.Status())
+	assert.Equal(t, "alice", inc.AcknowledgedBy())
+
+	inc.Update("Database outage", alert.RestoreSeverity("critical"), StatusAcknowledged, 2, time.Now())
+	assert.Equal(t, "alice", inc.AcknowledgedBy(), "an update that keeps the status keeps the acknowledgement")
+	assert.Equal(t, 2, inc.AlertsCount())
+	assert.Equal(t, "critical", inc.Severity().String())
+
+	inc.Update("Database outage", alert.RestoreSeverity("critical"), StatusFiring, 2, time.Now())
+	assert.Empty(t, inc.AcknowledgedBy())
+}
+
+func TestIncidentIsClosed(t *testing.T) {
+	for status, closed := range map[string]bool{
+		StatusFiring:       false,
+		StatusAcknowledged: false,
+		StatusResolved:     true,
+		StatusMerged:       true,
+		StatusDeleted:      true,
+	} {
+		inc := NewIncident("inc-1", "x", alert.RestoreSeverity("info"), status, 0, time.Now(), time.Now())
+		assert.Equal(t, closed, inc.IsClosed(), status)
+	}
+}
+
+func TestIsValidStatus(t *testing.T) {
+	assert.True(t, IsValidStatus(StatusFiring))
+	assert.False(t, IsValidStatus("pending"))
+	assert.False(t, IsValidStatus(""))
+}
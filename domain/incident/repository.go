@@ -0,0 +1,9 @@
+package incident
+
+import "context"
+
+type Repository interface {
+	Save(ctx context.Context, inc *Incident) error
+	FindByID(ctx context.Context, id string) (*Incident, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,64 @@
+package pendingaction
+
+import (
+	"maps"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Action is an acknowledge, resolve or unacknowledge whose Keep enrichment
+// failed because Keep was unreachable. The post already shows the action; the
+// entry waits until Keep can be told. There is at most one action per alert:
+// a newer one replaces the older, since only the latest intent counts.
+type Action struct {
+	fingerprint alert.Fingerprint
+	action      string
+	actor       string
+	enrichments map[string]string
+	postID      string
+	channelID   string
+	alertName   string
+	requestedAt time.Time
+	attempts    int
+	lastError   string
+}
+
+// NewAction keeps action on fingerprint by actor, the Mattermost username,
+// with the status enrichments to send; unacknowledge sends none.
+func NewAction(fingerprint alert.Fingerprint, action, actor string, enrichments map[string]string, postID, channelID, alertName, lastError string, requestedAt time.Time) *Action {
+	return &Action{
+		fingerprint: fingerprint,
+		action:      action,
+		actor:       actor,
+		enrichments: maps.Clone(enrichments),
+		postID:      postID,
+		channelID:   channelID,
+		alertName:   alertName,
+		requestedAt: requestedAt,
+		lastError:   lastError,
+	}
+}
+
+func RestoreAction(fingerprint alert.Fingerprint, action, actor string, enrichments map[string]string, postID, channelID, alertName string, requestedAt time.Time, attempts int, lastError string) *Action {
+	a := NewAction(fingerprint, action, actor, enrichments, postID, channelID, alertName, lastError, requestedAt)
+	a.attempts = attempts
+	return a
+}
+
+func (a *Action) Fingerprint() alert.Fingerprint { return a.fingerprint }
+func (a *Action) Action() string                 { return a.action }
+func (a *Action) Actor() string                  { return a.actor }
+func (a *Action) Enrichments() map[string]string { return maps.Clone(a.enrichments) }
+func (a *Action) PostID() string                 { return a.postID }
+func (a *Action) ChannelID() string              { return a.channelID }
+func (a *Action) AlertName() string              { return a.alertName }
+func (a *Action) RequestedAt() time.Time         { return a.requestedAt }
+func (a *Action) Attempts() int                  { return a.attempts }
+func (a *Action) LastError() string              { return a.lastError }
+
+// RecordFailure counts a failed replay.
+func (a *Action) RecordFailure(lastError string) {
+	a.attempts++
+	a.lastError = lastError
+}
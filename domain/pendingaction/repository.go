@@ -0,0 +1,13 @@
+package pendingaction
+
+import "context"
+
+type Repository interface {
+	// Save stores the action, replacing any action for the same alert.
+	Save(ctx context.Context, a *Action) error
+	// FindAll returns every action, oldest request first.
+	FindAll(ctx context.Context) ([]*Action, error)
+	// Delete removes the action for fingerprint; it is not an error when
+	// there is none.
+	Delete(ctx context.Context, fingerprint string) error
+}
@@ -1,36 +1,21 @@
 package post
 
-import "encoding/json"
+import "github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
 
-type Attachment struct {
-	Color      string
-	Title      string
-	TitleLink  string
-	Text       string
-	Fields     []AttachmentField
-	Actions    []Button
-	Footer     string
-	FooterIcon string
-}
-
-type AttachmentField struct {
-	Title string
-	Value string
-	Short bool
-}
+// The attachment model lives in pkg/attachment so other tools can render
+// the same alert cards; these aliases keep the domain vocabulary.
+type (
+	Attachment        = attachment.Attachment
+	AttachmentField   = attachment.Field
+	Button            = attachment.Button
+	ButtonIntegration = attachment.ButtonIntegration
+	SelectOption      = attachment.SelectOption
+)
 
-func (a *Attachment) ToJSON() (string, error) {
-	data, err := json.Marshal(a)
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+func AttachmentFromJSON(data string) (*Attachment, error) {
+	return attachment.FromJSON(data)
 }
 
-func AttachmentFromJSON(data string) (*Attachment, error) {
-	var attachment Attachment
-	if err := json.Unmarshal([]byte(data), &attachment); err != nil {
-		return nil, err
-	}
-	return &attachment, nil
+func RetryButton(callbackURL string, actionContext map[string]string) Button {
+	return attachment.RetryButton(callbackURL, actionContext)
 }
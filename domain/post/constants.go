@@ -1,27 +1,51 @@
 package post
 
+import "github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
+
+const (
+	ActionAcknowledge   = attachment.ActionAcknowledge
+	ActionResolve       = attachment.ActionResolve
+	ActionUnacknowledge = attachment.ActionUnacknowledge
+	ActionSnooze        = attachment.ActionSnooze
+	ActionCommands      = attachment.ActionCommands
+	ActionAssign        = attachment.ActionAssign
+	ActionCustom        = attachment.ActionCustom
+	ActionRunWorkflow   = attachment.ActionRunWorkflow
+	// ActionAcknowledgeFor carries the picked duration under
+	// ContextKeySelectedOption.
+	ActionAcknowledgeFor = attachment.ActionAcknowledgeFor
+	ActionRetry          = attachment.ActionRetry
+)
+
 const (
-	ActionAcknowledge   = "acknowledge"
-	ActionResolve       = "resolve"
-	ActionUnacknowledge = "unacknowledge"
+	ButtonTypeButton = attachment.ButtonTypeButton
+	ButtonTypeSelect = attachment.ButtonTypeSelect
+	DataSourceUsers  = attachment.DataSourceUsers
+	AssignToMe       = attachment.AssignToMe
 )
 
 const (
-	ButtonStyleDefault = "default"
-	ButtonStyleSuccess = "success"
-	ButtonStyleDanger  = "danger"
+	ButtonStyleDefault = attachment.ButtonStyleDefault
+	ButtonStyleSuccess = attachment.ButtonStyleSuccess
+	ButtonStyleDanger  = attachment.ButtonStyleDanger
 )
 
 const (
-	ContextKeyAction         = "action"
-	ContextKeyFingerprint    = "fingerprint"
-	ContextKeyAlertName      = "alert_name"
-	ContextKeySeverity       = "severity"
-	ContextKeyAttachmentJSON = "attachment_json"
+	ContextKeyAction         = attachment.ContextKeyAction
+	ContextKeyFingerprint    = attachment.ContextKeyFingerprint
+	ContextKeyAlertName      = attachment.ContextKeyAlertName
+	ContextKeySeverity       = attachment.ContextKeySeverity
+	ContextKeyAttachmentJSON = attachment.ContextKeyAttachmentJSON
+	ContextKeyCommands       = attachment.ContextKeyCommands
+	ContextKeyIncidentID     = attachment.ContextKeyIncidentID
+	ContextKeyDataSource     = attachment.ContextKeyDataSource
+	ContextKeyCustomAction   = attachment.ContextKeyCustomAction
+	ContextKeyRetryAction    = attachment.ContextKeyRetryAction
+	ContextKeySelectedOption = attachment.ContextKeySelectedOption
 )
 
 const (
-	SeverityPositionFirst        = "first"
-	SeverityPositionAfterDisplay = "after_display"
-	SeverityPositionLast         = "last"
+	SeverityPositionFirst        = attachment.SeverityPositionFirst
+	SeverityPositionAfterDisplay = attachment.SeverityPositionAfterDisplay
+	SeverityPositionLast         = attachment.SeverityPositionLast
 )
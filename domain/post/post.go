@@ -1,6 +1,7 @@
 package post
 
 import (
+	"maps"
 	"time"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
@@ -16,10 +17,20 @@ type Post struct {
 	createdAt         time.Time
 	lastUpdated       time.Time
 	lastKnownAssignee string
+	shownStatus       string
+	snoozedUntil      time.Time
+	ackUntil          time.Time
+	ackedBy           string
+	escalationLevel   int
+	escalatedAt       time.Time
+	acknowledgedAt    time.Time
+	resolvedAt        time.Time
+	refires           int
+	labels            map[string]string
 }
 
-func NewPost(postID, channelID string, fingerprint alert.Fingerprint, alertName string, severity alert.Severity, firingStartTime time.Time) *Post {
-	now := time.Now()
+// NewPost returns a post created and last updated at now.
+func NewPost(postID, channelID string, fingerprint alert.Fingerprint, alertName string, severity alert.Severity, firingStartTime, now time.Time) *Post {
 	return &Post{
 		postID:          postID,
 		channelID:       channelID,
@@ -56,10 +67,163 @@ func (p *Post) CreatedAt() time.Time           { return p.createdAt }
 func (p *Post) LastUpdated() time.Time         { return p.lastUpdated }
 func (p *Post) LastKnownAssignee() string      { return p.lastKnownAssignee }
 
-func (p *Post) Touch() {
-	p.lastUpdated = time.Now()
+// Touch records that the post was updated at now.
+func (p *Post) Touch(now time.Time) {
+	p.lastUpdated = now
 }
 
+// ChangeSeverity records that the alert re-fired with another severity.
+func (p *Post) ChangeSeverity(severity alert.Severity) {
+	p.severity = severity
+}
+
+// Move records that the alert is now shown by another post, e.g. after a
+// severity change routed it to another channel.
+func (p *Post) Move(postID, channelID string) {
+	p.postID = postID
+	p.channelID = channelID
+}
+
+// SetLabels records the labels of the alert's latest webhook, so a re-fire
+// can tell which labels changed.
+func (p *Post) SetLabels(labels map[string]string) {
+	p.labels = maps.Clone(labels)
+}
+
+// Labels returns the labels recorded by SetLabels, or nil when none were.
+func (p *Post) Labels() map[string]string { return maps.Clone(p.labels) }
+
 func (p *Post) SetLastKnownAssignee(assignee string) {
 	p.lastKnownAssignee = assignee
 }
+
+// ShowStatus records that the post now shows the alert as firing or
+// acknowledged, so the poller can tell when Keep disagrees.
+func (p *Post) ShowStatus(status string) {
+	p.shownStatus = status
+}
+
+// ShownStatus returns the status recorded by ShowStatus, or "" when none was,
+// e.g. for posts saved before the status was recorded.
+func (p *Post) ShownStatus() string { return p.shownStatus }
+
+// Snooze suppresses re-fire updates to the post until the given time.
+func (p *Post) Snooze(until time.Time) {
+	p.snoozedUntil = until
+}
+
+func (p *Post) Unsnooze() {
+	p.snoozedUntil = time.Time{}
+}
+
+// SnoozedUntil returns the end of the current snooze, or the zero time when
+// the post has never been snoozed or was unsnoozed.
+func (p *Post) SnoozedUntil() time.Time { return p.snoozedUntil }
+
+func (p *Post) IsSnoozed(now time.Time) bool {
+	return !p.snoozedUntil.IsZero() && now.Before(p.snoozedUntil)
+}
+
+// SnoozeExpired reports whether the post was snoozed and the snooze has ended
+// without being cleared yet.
+func (p *Post) SnoozeExpired(now time.Time) bool {
+	return !p.snoozedUntil.IsZero() && !now.Before(p.snoozedUntil)
+}
+
+// AcknowledgeUntil records that username acknowledged the alert for a
+// limited time: once until passes without the alert resolving, it is
+// unacknowledged again.
+func (p *Post) AcknowledgeUntil(until time.Time, username string) {
+	p.ackUntil = until
+	p.ackedBy = username
+}
+
+// ClearAckTimer drops the deadline set by AcknowledgeUntil.
+func (p *Post) ClearAckTimer() {
+	p.ackUntil = time.Time{}
+	p.ackedBy = ""
+}
+
+// AckUntil returns the deadline set by AcknowledgeUntil, or the zero time
+// when the acknowledgement, if any, has none.
+func (p *Post) AckUntil() time.Time { return p.ackUntil }
+
+// AckedBy returns the user who set the acknowledgement deadline.
+func (p *Post) AckedBy() string { return p.ackedBy }
+
+// AckExpired reports whether the acknowledgement deadline has passed without
+// being cleared yet.
+func (p *Post) AckExpired(now time.Time) bool {
+	return !p.ackUntil.IsZero() && !now.Before(p.ackUntil)
+}
+
+// Escalate records that the next escalation step ran at the given time.
+func (p *Post) Escalate(at time.Time) {
+	p.escalationLevel++
+	p.escalatedAt = at
+}
+
+// RestoreEscalation sets the escalation state loaded from storage.
+func (p *Post) RestoreEscalation(level int, at time.Time) {
+	p.escalationLevel = level
+	p.escalatedAt = at
+}
+
+// EscalationLevel returns how many escalation steps have run for the post.
+func (p *Post) EscalationLevel() int { return p.escalationLevel }
+
+// EscalatedAt returns when the last escalation step ran, or the zero time.
+func (p *Post) EscalatedAt() time.Time { return p.escalatedAt }
+
+// Acknowledge records that the alert was acknowledged at the given time and
+// reports whether it was the first acknowledgement of the post; later ones,
+// e.g. after an unacknowledge, keep the first time.
+func (p *Post) Acknowledge(at time.Time) bool {
+	if !p.acknowledgedAt.IsZero() {
+		return false
+	}
+	p.acknowledgedAt = at
+	return true
+}
+
+// Resolve records that the alert was resolved at the given time and reports
+// whether it was the first resolution of the post. It resets the fire count.
+func (p *Post) Resolve(at time.Time) bool {
+	p.refires = 0
+	if !p.resolvedAt.IsZero() {
+		return false
+	}
+	p.resolvedAt = at
+	return true
+}
+
+// RestoreLifecycle sets the lifecycle timestamps loaded from storage.
+func (p *Post) RestoreLifecycle(acknowledgedAt, resolvedAt time.Time) {
+	p.acknowledgedAt = acknowledgedAt
+	p.resolvedAt = resolvedAt
+}
+
+// AcknowledgedAt returns when the alert was first acknowledged, or the zero
+// time.
+func (p *Post) AcknowledgedAt() time.Time { return p.acknowledgedAt }
+
+// ResolvedAt returns when the alert was resolved, or the zero time.
+func (p *Post) ResolvedAt() time.Time { return p.resolvedAt }
+
+// Refire records that the alert fired again while the post showed it.
+func (p *Post) Refire() {
+	p.refires++
+}
+
+// RestoreRefires sets the re-fire count loaded from storage.
+func (p *Post) RestoreRefires(refires int) {
+	p.refires = refires
+}
+
+// Refires returns how many times the alert re-fired since the post was
+// created or the alert last resolved.
+func (p *Post) Refires() int { return p.refires }
+
+// FireCount returns how many times the alert fired, counting the firing that
+// created the post.
+func (p *Post) FireCount() int { return p.refires + 1 }
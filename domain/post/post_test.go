@@ -19,9 +19,8 @@ func TestNewPost(t *testing.T) {
 	severity := alert.RestoreSeverity("critical")
 	firingStartTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
 
-	beforeCreate := time.Now()
-	p := NewPost(postID, channelID, fingerprint, alertName, severity, firingStartTime)
-	afterCreate := time.Now()
+	now := time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC)
+	p := NewPost(postID, channelID, fingerprint, alertName, severity, firingStartTime, now)
 
 	require.NotNil(t, p)
 	assert.Equal(t, postID, p.PostID())
@@ -31,15 +30,9 @@ func TestNewPost(t *testing.T) {
 	assert.Equal(t, severity, p.Severity())
 	assert.Equal(t, firingStartTime, p.FiringStartTime())
 
-	// Timestamps should be set to approximately now
-	assert.True(t, p.CreatedAt().After(beforeCreate) || p.CreatedAt().Equal(beforeCreate))
-	assert.True(t, p.CreatedAt().Before(afterCreate) || p.CreatedAt().Equal(afterCreate))
-
-	assert.True(t, p.LastUpdated().After(beforeCreate) || p.LastUpdated().Equal(beforeCreate))
-	assert.True(t, p.LastUpdated().Before(afterCreate) || p.LastUpdated().Equal(afterCreate))
-
-	// CreatedAt and LastUpdated should be equal for new posts
-	assert.Equal(t, p.CreatedAt(), p.LastUpdated())
+	// New posts are created and last updated at now
+	assert.Equal(t, now, p.CreatedAt())
+	assert.Equal(t, now, p.LastUpdated())
 }
 
 func TestRestorePost(t *testing.T) {
@@ -79,20 +72,14 @@ func TestPostTouch(t *testing.T) {
 	assert.Equal(t, lastUpdated, p.LastUpdated())
 
 	// Touch the post
-	beforeTouch := time.Now()
-	time.Sleep(1 * time.Millisecond) // Ensure time passes
-	p.Touch()
-	afterTouch := time.Now()
+	touchedAt := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	p.Touch(touchedAt)
 
 	// CreatedAt should remain unchanged
 	assert.Equal(t, createdAt, p.CreatedAt())
 
-	// LastUpdated should be updated to now
-	assert.True(t, p.LastUpdated().After(beforeTouch))
-	assert.True(t, p.LastUpdated().Before(afterTouch) || p.LastUpdated().Equal(afterTouch))
-
-	// LastUpdated should be after the original value
-	assert.True(t, p.LastUpdated().After(lastUpdated))
+	// LastUpdated should be set to the touch time
+	assert.Equal(t, touchedAt, p.LastUpdated())
 }
 
 func TestSetLastKnownAssignee(t *testing.T) {
@@ -111,6 +98,15 @@ func TestSetLastKnownAssignee(t *testing.T) {
 	assert.Equal(t, "anotheruser", p.LastKnownAssignee())
 }
 
+func TestPostShowStatus(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("info"), time.Now(), time.Now())
+
+	assert.Equal(t, "", p.ShownStatus(), "unknown until recorded")
+
+	p.ShowStatus(alert.StatusAcknowledged)
+	assert.Equal(t, alert.StatusAcknowledged, p.ShownStatus())
+}
+
 func TestPostGetters(t *testing.T) {
 	postID := "post-xyz"
 	channelID := "channel-uvw"
@@ -308,3 +304,106 @@ func TestButtonIntegration(t *testing.T) {
 		assert.Equal(t, "value3", integration.Context["key3"])
 	})
 }
+
+func TestPostSnooze(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("high"), now, time.Now())
+
+	assert.False(t, p.IsSnoozed(now))
+	assert.False(t, p.SnoozeExpired(now))
+	assert.True(t, p.SnoozedUntil().IsZero())
+
+	until := now.Add(time.Hour)
+	p.Snooze(until)
+	assert.Equal(t, until, p.SnoozedUntil())
+	assert.True(t, p.IsSnoozed(now))
+	assert.False(t, p.SnoozeExpired(now.Add(59*time.Minute)))
+
+	assert.False(t, p.IsSnoozed(until))
+	assert.True(t, p.SnoozeExpired(until))
+
+	p.Unsnooze()
+	assert.False(t, p.IsSnoozed(now))
+	assert.False(t, p.SnoozeExpired(until))
+}
+
+func TestPostEscalate(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	assert.Zero(t, p.EscalationLevel())
+	assert.True(t, p.EscalatedAt().IsZero())
+
+	first := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	p.Escalate(first)
+	p.Escalate(first.Add(15 * time.Minute))
+	assert.Equal(t, 2, p.EscalationLevel())
+	assert.Equal(t, first.Add(15*time.Minute), p.EscalatedAt())
+
+	p.RestoreEscalation(1, first)
+	assert.Equal(t, 1, p.EscalationLevel())
+	assert.Equal(t, first, p.EscalatedAt())
+}
+
+func TestPostLifecycle(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	assert.True(t, p.AcknowledgedAt().IsZero())
+	assert.True(t, p.ResolvedAt().IsZero())
+
+	acked := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	assert.True(t, p.Acknowledge(acked))
+	assert.False(t, p.Acknowledge(acked.Add(time.Hour)), "later acknowledgements keep the first time")
+	assert.Equal(t, acked, p.AcknowledgedAt())
+
+	assert.True(t, p.Resolve(acked.Add(time.Hour)))
+	assert.False(t, p.Resolve(acked.Add(2*time.Hour)))
+	assert.Equal(t, acked.Add(time.Hour), p.ResolvedAt())
+
+	p.RestoreLifecycle(acked, time.Time{})
+	assert.Equal(t, acked, p.AcknowledgedAt())
+	assert.True(t, p.ResolvedAt().IsZero())
+}
+
+func TestPostAckTimer(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	assert.False(t, p.AckExpired(now), "no deadline, nothing expires")
+
+	p.AcknowledgeUntil(now.Add(2*time.Hour), "alice")
+	assert.Equal(t, now.Add(2*time.Hour), p.AckUntil())
+	assert.Equal(t, "alice", p.AckedBy())
+	assert.False(t, p.AckExpired(now.Add(time.Hour)))
+	assert.True(t, p.AckExpired(now.Add(2*time.Hour)))
+
+	p.ClearAckTimer()
+	assert.True(t, p.AckUntil().IsZero())
+	assert.Empty(t, p.AckedBy())
+	assert.False(t, p.AckExpired(now.Add(3*time.Hour)))
+}
+
+func TestPostFireCount(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	assert.Equal(t, 1, p.FireCount(), "the firing that created the post counts")
+
+	p.Refire()
+	p.Refire()
+	assert.Equal(t, 2, p.Refires())
+	assert.Equal(t, 3, p.FireCount())
+
+	p.Resolve(time.Now())
+	assert.Equal(t, 1, p.FireCount(), "resolution resets the count")
+
+	p.RestoreRefires(6)
+	assert.Equal(t, 7, p.FireCount())
+}
+
+func TestPostChangeSeverityAndMove(t *testing.T) {
+	p := NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Alert", alert.RestoreSeverity("warning"), time.Now(), time.Now())
+	p.SetLastKnownAssignee("john")
+
+	p.ChangeSeverity(alert.RestoreSeverity("critical"))
+	p.Move("post-2", "channel-2")
+
+	assert.Equal(t, "critical", p.Severity().Value())
+	assert.Equal(t, "post-2", p.PostID())
+	assert.Equal(t, "channel-2", p.ChannelID())
+	assert.Equal(t, "john", p.LastKnownAssignee(), "moving keeps the alert state")
+}
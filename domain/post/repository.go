@@ -2,6 +2,7 @@ package post
 
 import (
 	"context"
+	"time"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 )
@@ -12,3 +13,26 @@ type Repository interface {
 	FindAllActive(ctx context.Context) ([]*Post, error)
 	Delete(ctx context.Context, fingerprint alert.Fingerprint) error
 }
+
+// PostIDFinder is implemented by repositories that can find a post by its
+// chat post ID without reading every active post.
+type PostIDFinder interface {
+	// FindByPostID returns the post with postID, or ErrNotFound.
+	FindByPostID(ctx context.Context, postID string) (*Post, error)
+}
+
+// Expired is a post whose stored entry lapsed at ExpiredAt.
+type Expired struct {
+	Post      *Post
+	ExpiredAt time.Time
+}
+
+// ExpiryStore keeps the posts whose stored entry expires, so their
+// Mattermost post can be marked once the entry is gone.
+type ExpiryStore interface {
+	// FindExpired returns up to limit posts whose entry expired by now,
+	// oldest first.
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]Expired, error)
+	// ForgetExpired drops an expired post once it was handled.
+	ForgetExpired(ctx context.Context, fingerprint alert.Fingerprint) error
+}
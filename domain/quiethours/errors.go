@@ -0,0 +1,5 @@
+package quiethours
+
+import "errors"
+
+var ErrNotFound = errors.New("held alert not found")
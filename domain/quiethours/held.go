@@ -0,0 +1,65 @@
+package quiethours
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Held is a new firing alert held back during quiet hours. It is listed in
+// the digest once quiet hours end and posted then if it is still firing.
+type Held struct {
+	channelID  string
+	alert      *alert.Alert
+	heldAt     time.Time
+	resolvedAt time.Time
+	digested   bool
+}
+
+// NewHeld holds a, which would have been posted in channelID.
+func NewHeld(channelID string, a *alert.Alert, heldAt time.Time) *Held {
+	return &Held{
+		channelID: channelID,
+		alert:     a,
+		heldAt:    heldAt,
+	}
+}
+
+func RestoreHeld(channelID string, a *alert.Alert, heldAt, resolvedAt time.Time, digested bool) *Held {
+	h := NewHeld(channelID, a, heldAt)
+	h.resolvedAt = resolvedAt
+	h.digested = digested
+	return h
+}
+
+func (h *Held) ChannelID() string              { return h.channelID }
+func (h *Held) Fingerprint() alert.Fingerprint { return h.alert.Fingerprint() }
+func (h *Held) HeldAt() time.Time              { return h.heldAt }
+
+// Alert returns the alert as last received.
+func (h *Held) Alert() *alert.Alert { return h.alert }
+
+// Refire replaces the alert with a re-fire received while it was held. An
+// alert that resolved and fired again counts as firing.
+func (h *Held) Refire(a *alert.Alert) {
+	h.alert = a
+	h.resolvedAt = time.Time{}
+}
+
+// MarkResolved records that the alert resolved while held.
+func (h *Held) MarkResolved(at time.Time) {
+	h.resolvedAt = at
+}
+
+// ResolvedAt returns when the alert resolved, or zero while it is firing.
+func (h *Held) ResolvedAt() time.Time { return h.resolvedAt }
+
+func (h *Held) IsResolved() bool { return !h.resolvedAt.IsZero() }
+
+// MarkDigested records that the alert was listed in a digest, so a retry
+// after a failed post does not list it again.
+func (h *Held) MarkDigested() {
+	h.digested = true
+}
+
+func (h *Held) IsDigested() bool { return h.digested }
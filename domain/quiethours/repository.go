@@ -0,0 +1,17 @@
+package quiethours
+
+import (
+	"context"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type Repository interface {
+	// Save stores the held alert, replacing any held alert with the same
+	// fingerprint.
+	Save(ctx context.Context, h *Held) error
+	FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*Held, error)
+	// FindAll returns every held alert, oldest first.
+	FindAll(ctx context.Context) ([]*Held, error)
+	Delete(ctx context.Context, fingerprint alert.Fingerprint) error
+}
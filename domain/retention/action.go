@@ -0,0 +1,12 @@
+package retention
+
+// Actions the retention policy can take on a resolved alert post.
+const (
+	// ActionDelete removes the post together with its thread.
+	ActionDelete = "delete"
+	// ActionCollapse replaces the post with a one-line summary.
+	ActionCollapse = "collapse"
+	// ActionArchive reposts the post into an archive channel and deletes the
+	// original.
+	ActionArchive = "archive"
+)
@@ -0,0 +1,59 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Entry is a resolved alert post waiting for the retention policy to delete,
+// collapse or archive it.
+type Entry struct {
+	postID         string
+	channelID      string
+	fingerprint    alert.Fingerprint
+	alertName      string
+	attachment     post.Attachment
+	resolvedAt     time.Time
+	dueAt          time.Time
+	archivedPostID string
+}
+
+func NewEntry(postID, channelID string, fingerprint alert.Fingerprint, alertName string, attachment post.Attachment, resolvedAt, dueAt time.Time) *Entry {
+	return &Entry{
+		postID:      postID,
+		channelID:   channelID,
+		fingerprint: fingerprint,
+		alertName:   alertName,
+		attachment:  attachment,
+		resolvedAt:  resolvedAt,
+		dueAt:       dueAt,
+	}
+}
+
+func RestoreEntry(postID, channelID string, fingerprint alert.Fingerprint, alertName string, attachment post.Attachment, resolvedAt, dueAt time.Time, archivedPostID string) *Entry {
+	e := NewEntry(postID, channelID, fingerprint, alertName, attachment, resolvedAt, dueAt)
+	e.archivedPostID = archivedPostID
+	return e
+}
+
+func (e *Entry) PostID() string                 { return e.postID }
+func (e *Entry) ChannelID() string              { return e.channelID }
+func (e *Entry) Fingerprint() alert.Fingerprint { return e.fingerprint }
+func (e *Entry) AlertName() string              { return e.alertName }
+func (e *Entry) ResolvedAt() time.Time          { return e.resolvedAt }
+func (e *Entry) DueAt() time.Time               { return e.dueAt }
+
+// Attachment returns the resolved post as it was rendered, used to repost it
+// into the archive channel.
+func (e *Entry) Attachment() post.Attachment { return e.attachment }
+
+// MarkArchived records the copy posted into the archive channel, so a retry
+// after a failed delete does not post a second copy.
+func (e *Entry) MarkArchived(postID string) {
+	e.archivedPostID = postID
+}
+
+// ArchivedPostID returns the archive copy's post ID, or "" before archiving.
+func (e *Entry) ArchivedPostID() string { return e.archivedPostID }
@@ -0,0 +1,14 @@
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Save stores the entry, replacing any entry for the same post.
+	Save(ctx context.Context, e *Entry) error
+	// FindDue returns up to limit entries due at or before now, earliest first.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*Entry, error)
+	Delete(ctx context.Context, postID string) error
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChannelNamePrefix marks a channel given by name instead of ID, as in
+// Mattermost's ~channel links, e.g. "~alerts-prod". Names are looked up in
+// MATTERMOST_TEAM_ID when the bridge starts.
+const ChannelNamePrefix = "~"
+
+// channelNamePattern matches the names Mattermost gives channels: the handle
+// in their URL, not the display name.
+var channelNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ChannelNamesConfig controls channels given by name anywhere a channel ID
+// goes. With Create, channels that do not exist yet are created, private
+// ones with Private. Found IDs are cached in Valkey for CacheTTL.
+type ChannelNamesConfig struct {
+	Create   bool   `yaml:"create"`
+	Private  bool   `yaml:"private"`
+	CacheTTL string `yaml:"cache_ttl"` // default: 24h
+}
+
+func (c *FileConfig) validateChannelNames() error {
+	if c.ChannelNames.CacheTTL != "" {
+		ttl, err := time.ParseDuration(c.ChannelNames.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid channel_names.cache_ttl %q: %w", c.ChannelNames.CacheTTL, err)
+		}
+		if ttl < time.Minute {
+			return fmt.Errorf("channel_names.cache_ttl must be at least 1m, got %s", ttl)
+		}
+	}
+	for _, name := range c.NamedChannels() {
+		if !channelNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid channel %q: names must be lowercase letters, digits, dashes and underscores", ChannelNamePrefix+name)
+		}
+	}
+	for i, rule := range c.Channels.Routing {
+		if rule.Server != "" && isChannelName(rule.ChannelID) {
+			return fmt.Errorf("channels.routing[%d]: channels on mattermost_servers must be given by ID, got %q", i, rule.ChannelID)
+		}
+	}
+	for i, rule := range c.Channels.LabelRouting.Rules {
+		if rule.Server != "" && isChannelName(rule.ChannelID) {
+			return fmt.Errorf("channels.label_routing.rules[%d]: channels on mattermost_servers must be given by ID, got %q", i, rule.ChannelID)
+		}
+	}
+	return nil
+}
+
+// ChannelNameCacheTTL returns the parsed lifetime of cached channel IDs,
+// falling back to one day.
+func (c *FileConfig) ChannelNameCacheTTL() time.Duration {
+	return parseDurationOr(c.ChannelNames.CacheTTL, 24*time.Hour)
+}
+
+// NamedChannels returns the names, without the prefix, of the channels the
+// config gives by name, sorted and without duplicates.
+func (c *FileConfig) NamedChannels() []string {
+	var names []string
+	c.mapChannels(func(channel string) string {
+		if isChannelName(channel) {
+			names = append(names, strings.TrimPrefix(channel, ChannelNamePrefix))
+		}
+		return channel
+	})
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// WithChannelIDs returns a copy of c with each channel given by name replaced
+// by its ID in ids, keyed by name without the prefix. c is left alone.
+func (c *FileConfig) WithChannelIDs(ids map[string]string) *FileConfig {
+	return c.mapChannels(func(channel string) string {
+		if id, ok := ids[strings.TrimPrefix(channel, ChannelNamePrefix)]; ok && isChannelName(channel) {
+			return id
+		}
+		return channel
+	})
+}
+
+func isChannelName(channel string) bool {
+	return strings.HasPrefix(channel, ChannelNamePrefix)
+}
+
+// mapChannels returns a copy of c with fn applied to every channel of every
+// section. The slices and maps holding channels are copied, as tenants share
+// them with the main config.
+func (c *FileConfig) mapChannels(fn func(string) string) *FileConfig {
+	m := *c
+
+	m.Channels.DefaultChannelID = fn(m.Channels.DefaultChannelID)
+	m.Channels.Routing = slices.Clone(m.Channels.Routing)
+	for i := range m.Channels.Routing {
+		m.Channels.Routing[i].ChannelID = fn(m.Channels.Routing[i].ChannelID)
+	}
+	m.Channels.LabelRouting.Rules = slices.Clone(m.Channels.LabelRouting.Rules)
+	for i := range m.Channels.LabelRouting.Rules {
+		m.Channels.LabelRouting.Rules[i].ChannelID = fn(m.Channels.LabelRouting.Rules[i].ChannelID)
+	}
+	if m.Channels.TeamRouting.Channels != nil {
+		channels := make(map[string]string, len(m.Channels.TeamRouting.Channels))
+		for team, channel := range m.Channels.TeamRouting.Channels {
+			channels[team] = fn(channel)
+		}
+		m.Channels.TeamRouting.Channels = channels
+	}
+
+	m.Mentions = m.Mentions.mapChannels(fn)
+	m.Message.Profiles = slices.Clone(m.Message.Profiles)
+	for i := range m.Message.Profiles {
+		p := &m.Message.Profiles[i]
+		p.Channels = mapEach(p.Channels, fn)
+		if p.Mentions != nil {
+			mentions := p.Mentions.mapChannels(fn)
+			p.Mentions = &mentions
+		}
+	}
+
+	m.QuietHours.Rules = slices.Clone(m.QuietHours.Rules)
+	for i := range m.QuietHours.Rules {
+		m.QuietHours.Rules[i].Channels = mapEach(m.QuietHours.Rules[i].Channels, fn)
+		m.QuietHours.Rules[i].ChannelID = fn(m.QuietHours.Rules[i].ChannelID)
+	}
+	m.QuietHours.DigestChannelID = fn(m.QuietHours.DigestChannelID)
+
+	m.Permissions.Rules = slices.Clone(m.Permissions.Rules)
+	for i := range m.Permissions.Rules {
+		m.Permissions.Rules[i].Channels = mapEach(m.Permissions.Rules[i].Channels, fn)
+	}
+	m.Escalation.Rules = slices.Clone(m.Escalation.Rules)
+	for i := range m.Escalation.Rules {
+		m.Escalation.Rules[i].ChannelID = fn(m.Escalation.Rules[i].ChannelID)
+	}
+	if m.Budget.Channels != nil {
+		budgets := make(map[string]int, len(m.Budget.Channels))
+		for channel, perHour := range m.Budget.Channels {
+			budgets[fn(channel)] = perHour
+		}
+		m.Budget.Channels = budgets
+	}
+
+	m.Incidents.ChannelID = fn(m.Incidents.ChannelID)
+	m.ErrorPosts.ChannelID = fn(m.ErrorPosts.ChannelID)
+	m.SLO.Report.ChannelID = fn(m.SLO.Report.ChannelID)
+	m.Digest.ChannelID = fn(m.Digest.ChannelID)
+	m.Retention.ArchiveChannelID = fn(m.Retention.ArchiveChannelID)
+	m.Badge.ChannelID = fn(m.Badge.ChannelID)
+	m.StatusBoard.Channels = mapEach(m.StatusBoard.Channels, fn)
+	return &m
+}
+
+func (m MentionsConfig) mapChannels(fn func(string) string) MentionsConfig {
+	m.Rules = slices.Clone(m.Rules)
+	for i := range m.Rules {
+		m.Rules[i].Channels = mapEach(m.Rules[i].Channels, fn)
+	}
+	return m
+}
+
+func mapEach(channels []string, fn func(string) string) []string {
+	if channels == nil {
+		return nil
+	}
+	mapped := make([]string, len(channels))
+	for i, channel := range channels {
+		mapped[i] = fn(channel)
+	}
+	return mapped
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateChannelNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     func(c *FileConfig)
+		wantErr string
+	}{
+		{name: "ids only", cfg: func(c *FileConfig) {}},
+		{name: "names", cfg: func(c *FileConfig) {
+			c.Channels.DefaultChannelID = "~alerts"
+			c.Channels.Routing = []RoutingRule{{Severity: "critical", ChannelID: "~alerts-critical"}}
+		}},
+		{name: "uppercase name", cfg: func(c *FileConfig) { c.Channels.DefaultChannelID = "~Alerts" }, wantErr: `invalid channel "~Alerts"`},
+		{name: "empty name", cfg: func(c *FileConfig) { c.Digest.ChannelID = "~" }, wantErr: `invalid channel "~"`},
+		{name: "name on another server", cfg: func(c *FileConfig) {
+			c.Channels.Routing = []RoutingRule{{Severity: "critical", ChannelID: "~alerts", Server: "customer"}}
+		}, wantErr: "channels.routing[0]: channels on mattermost_servers must be given by ID"},
+		{name: "label route name on another server", cfg: func(c *FileConfig) {
+			c.Channels.LabelRouting.Rules = []LabelRouteRule{{Match: []string{"team=payments"}, ChannelID: "~payments", Server: "customer"}}
+		}, wantErr: "channels.label_routing.rules[0]: channels on mattermost_servers must be given by ID"},
+		{name: "invalid cache ttl", cfg: func(c *FileConfig) { c.ChannelNames.CacheTTL = "soon" }, wantErr: `invalid channel_names.cache_ttl "soon"`},
+		{name: "short cache ttl", cfg: func(c *FileConfig) { c.ChannelNames.CacheTTL = "30s" }, wantErr: "channel_names.cache_ttl must be at least 1m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{}
+			tt.cfg(cfg)
+			err := cfg.validateChannelNames()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWithChannelIDs(t *testing.T) {
+	cfg := &FileConfig{
+		Channels: ChannelsConfig{
+			DefaultChannelID: "~alerts",
+			Routing:          []RoutingRule{{Severity: "critical", ChannelID: "~alerts-critical"}, {Severity: "info", ChannelID: "channel-info"}},
+			LabelRouting:     LabelRoutingConfig{Rules: []LabelRouteRule{{Match: []string{"team=payments"}, ChannelID: "~payments"}}},
+			TeamRouting:      TeamRoutingConfig{Channels: map[string]string{"payments": "~payments", "noise": DropChannel}},
+		},
+		Mentions:    MentionsConfig{Rules: []MentionRule{{Channels: []string{"~alerts", "channel-info"}}}},
+		QuietHours:  QuietHoursPolicyConfig{Rules: []QuietHoursRule{{Action: "route", ChannelID: "~night"}}, DigestChannelID: "~night"},
+		Budget:      BudgetConfig{Channels: map[string]int{"~alerts": 10}},
+		Retention:   RetentionConfig{ArchiveChannelID: "~archive"},
+		StatusBoard: StatusBoardConfig{Channels: []string{"~alerts"}},
+		Message: MessageConfig{Profiles: []MessageProfileConfig{
+			{Name: "compact", Channels: []string{"~payments"}, Mentions: &MentionsConfig{Rules: []MentionRule{{Channels: []string{"~payments"}}}}},
+		}},
+	}
+	assert.Equal(t, []string{"alerts", "alerts-critical", "archive", "night", "payments"}, cfg.NamedChannels())
+
+	resolved := cfg.WithChannelIDs(map[string]string{
+		"alerts":          "channel-alerts",
+		"alerts-critical": "channel-critical",
+		"archive":         "channel-archive",
+		"night":           "channel-night",
+		"payments":        "channel-payments",
+	})
+	assert.Empty(t, resolved.NamedChannels())
+	assert.Equal(t, "channel-alerts", resolved.Channels.DefaultChannelID)
+	assert.Equal(t, "channel-critical", resolved.Channels.Routing[0].ChannelID)
+	assert.Equal(t, "channel-info", resolved.Channels.Routing[1].ChannelID)
+	assert.Equal(t, "channel-payments", resolved.Channels.LabelRouting.Rules[0].ChannelID)
+	assert.Equal(t, map[string]string{"payments": "channel-payments", "noise": DropChannel}, resolved.Channels.TeamRouting.Channels)
+	assert.Equal(t, []string{"channel-alerts", "channel-info"}, resolved.Mentions.Rules[0].Channels)
+	assert.Equal(t, "channel-night", resolved.QuietHours.Rules[0].ChannelID)
+	assert.Equal(t, "channel-night", resolved.QuietHours.DigestChannelID)
+	assert.Equal(t, map[string]int{"channel-alerts": 10}, resolved.Budget.Channels)
+	assert.Equal(t, "channel-archive", resolved.Retention.ArchiveChannelID)
+	assert.Equal(t, []string{"channel-alerts"}, resolved.StatusBoard.Channels)
+	assert.Equal(t, []string{"channel-payments"}, resolved.Message.Profiles[0].Channels)
+	assert.Equal(t, []string{"channel-payments"}, resolved.Message.Profiles[0].Mentions.Rules[0].Channels)
+
+	assert.Equal(t, "~alerts-critical", cfg.Channels.Routing[0].ChannelID, "the config is left alone")
+	assert.Equal(t, []string{"~alerts", "channel-info"}, cfg.Mentions.Rules[0].Channels)
+	assert.Equal(t, []string{"~payments"}, cfg.Message.Profiles[0].Mentions.Rules[0].Channels)
+}
+
+func TestChannelNameCacheTTL(t *testing.T) {
+	cfg := &FileConfig{}
+	assert.Equal(t, 24*time.Hour, cfg.ChannelNameCacheTTL())
+	cfg.ChannelNames.CacheTTL = "1h"
+	assert.Equal(t, time.Hour, cfg.ChannelNameCacheTTL())
+}
@@ -2,18 +2,29 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/outbound"
 )
 
+// callbackRoute is where the router serves Mattermost button callbacks,
+// relative to the base path.
+const callbackRoute = "/api/v1/callback"
+
 type Config struct {
 	Server      ServerConfig
 	Mattermost  MattermostConfig
 	Keep        KeepConfig
 	Redis       RedisConfig
 	Polling     PollingConfig
+	Reconcile   ReconcileConfig
 	Setup       SetupConfig
+	Ingest      IngestConfig
+	Storage     StorageConfig
 	ConfigPath  string
 	CallbackURL string
 }
@@ -26,15 +37,80 @@ type SetupConfig struct {
 // PollingConfig configures background polling for detecting assignee changes
 // made directly in Keep UI, which bypass webhook notifications.
 type PollingConfig struct {
-	Enabled     bool          // Enable background polling
-	Interval    time.Duration // Interval between polling cycles (minimum 10s)
-	AlertsLimit int           // Maximum alerts to fetch from Keep API per poll (default 1000)
-	Timeout     time.Duration // Timeout for each polling cycle (default 30s)
+	Enabled       bool          // Enable background polling
+	Interval      time.Duration // Interval between polling cycles (minimum 10s)
+	AlertsLimit   int           // Maximum alerts to fetch from Keep API per poll (default 1000)
+	Timeout       time.Duration // Timeout for each polling cycle (default 30s)
+	MaxResponseMB int           // Maximum size of a Keep alerts response in MiB (default 64)
+	// BackoffMultiplier grows the wait after each consecutive failed cycle,
+	// starting at Interval. 1 keeps polling at Interval (default 2).
+	BackoffMultiplier float64
+	BackoffMax        time.Duration // Longest wait between cycles after failures (default 10m)
+}
+
+// ReconcileConfig configures the startup pass that heals webhooks missed
+// while the bridge was down.
+type ReconcileConfig struct {
+	OnStart bool          // Reconcile posts with Keep before serving (default false)
+	Timeout time.Duration // Timeout for the reconciliation pass (default 2m)
+}
+
+// Storage backends hold the alert-to-post mappings.
+const (
+	StorageBackendValkey   = "valkey"
+	StorageBackendMemory   = "memory"
+	StorageBackendBolt     = "bolt"
+	StorageBackendPostgres = "postgres"
+)
+
+// StorageConfig selects where posts are stored.
+type StorageConfig struct {
+	Backend     string // StorageBackendValkey (default), StorageBackendMemory, StorageBackendBolt or StorageBackendPostgres
+	Path        string // Database file of the bolt backend (default /var/lib/kmbridge/kmbridge.db)
+	DatabaseURL string // Connection URL of the postgres backend
+}
+
+// Ingest modes say how alert webhooks reach the alert use case.
+const (
+	// IngestModeDirect handles alerts in the process that received them.
+	IngestModeDirect = "direct"
+	// IngestModeStream appends alerts to a Valkey stream that every bridge
+	// replica consumes as one consumer group.
+	IngestModeStream = "stream"
+)
+
+// IngestConfig configures how alert webhooks are handed to the bridge.
+type IngestConfig struct {
+	Mode     string // IngestModeDirect (default) or IngestModeStream
+	Consumer string // Name of this replica in the stream consumer group (default: hostname)
 }
 
 type ServerConfig struct {
 	Port     int
 	LogLevel string
+	// BasePath prefixes every route, e.g. "/bridge" behind a shared ingress.
+	// Empty serves from the root.
+	BasePath string
+	// AdminToken guards the admin API as a bearer token. The admin API is
+	// disabled when empty.
+	AdminToken string
+	// AccessLogSampleRate is the share of successful API requests written to
+	// the access log. Failed requests are always logged.
+	AccessLogSampleRate float64
+	// LogRequestBodies logs webhook and callback request bodies, with
+	// secrets redacted, when LogLevel is debug.
+	LogRequestBodies bool
+	// DrainTimeout bounds how long shutdown waits, after the HTTP server
+	// stopped, for queued alerts, callbacks and held Mattermost updates.
+	DrainTimeout time.Duration
+	// WebhookMaxBodyBytes and CallbackMaxBodyBytes bound the request bodies
+	// of webhooks and callbacks. Zero allows 1 MiB.
+	WebhookMaxBodyBytes  int64
+	CallbackMaxBodyBytes int64
+	// WebhookTimeout and CallbackTimeout bound reading and handling a
+	// webhook or callback request. Zero leaves them to the server timeouts.
+	WebhookTimeout  time.Duration
+	CallbackTimeout time.Duration
 }
 
 func (c *ServerConfig) Addr() string {
@@ -44,12 +120,26 @@ func (c *ServerConfig) Addr() string {
 type MattermostConfig struct {
 	URL   string
 	Token string
+	// SlashCommandToken is the token Mattermost sends with /keep slash
+	// command requests. The command endpoint is disabled when empty.
+	SlashCommandToken string
+	// TeamID is the team whose channels the config file may name instead of
+	// giving their IDs.
+	TeamID string
+	// Outbound holds the proxy and TLS settings for every Mattermost server.
+	Outbound outbound.Settings
+	// ClusterHosts are the hosts of the other nodes of an HA cluster, which
+	// redirects may send the bot token to.
+	ClusterHosts []string
 }
 
 type KeepConfig struct {
-	URL    string
-	APIKey string
-	UIURL  string
+	URL            string
+	APIKey         string
+	UIURL          string
+	SigningKeyFile string // PEM private key used to sign enrichment payloads (optional)
+	SigningKeyID   string // "kid" advertised in the signature header (optional)
+	Outbound       outbound.Settings
 }
 
 type RedisConfig struct {
@@ -89,24 +179,127 @@ func LoadFromEnv() (*Config, error) {
 		return nil, err
 	}
 
+	pollingMaxResponseMB, err := getEnvOrDefaultInt("POLLING_MAX_RESPONSE_MB", 64)
+	if err != nil {
+		return nil, err
+	}
+
+	pollingBackoffMultiplier, err := getEnvOrDefaultFloat("POLLING_BACKOFF_MULTIPLIER", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	pollingBackoffMax, err := getEnvOrDefaultDuration("POLLING_BACKOFF_MAX", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	reconcileOnStart, err := getEnvOrDefaultBool("RECONCILE_ON_START", false)
+	if err != nil {
+		return nil, err
+	}
+
+	reconcileTimeout, err := getEnvOrDefaultDuration("RECONCILE_TIMEOUT", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	setupEnabled, err := getEnvOrDefaultBool("KEEP_SETUP_ENABLED", true)
 	if err != nil {
 		return nil, err
 	}
 
+	accessLogSampleRate, err := getEnvOrDefaultFloat("ACCESS_LOG_SAMPLE_RATE", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	logRequestBodies, err := getEnvOrDefaultBool("LOG_REQUEST_BODIES", false)
+	if err != nil {
+		return nil, err
+	}
+
+	drainTimeout, err := getEnvOrDefaultDuration("SHUTDOWN_DRAIN_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookMaxBodyBytes, err := getEnvOrDefaultInt("WEBHOOK_MAX_BODY_BYTES", 1<<20)
+	if err != nil {
+		return nil, err
+	}
+
+	callbackMaxBodyBytes, err := getEnvOrDefaultInt("CALLBACK_MAX_BODY_BYTES", 64<<10)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookTimeout, err := getEnvOrDefaultDuration("WEBHOOK_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	callbackTimeout, err := getEnvOrDefaultDuration("CALLBACK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	mattermostInsecure, err := getEnvOrDefaultBool("MATTERMOST_TLS_INSECURE_SKIP_VERIFY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	keepInsecure, err := getEnvOrDefaultBool("KEEP_TLS_INSECURE_SKIP_VERIFY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	basePath := normalizeBasePath(os.Getenv("BASE_PATH"))
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "kmbridge"
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:     serverPort,
-			LogLevel: getEnvOrDefault("LOG_LEVEL", "info"),
+			Port:       serverPort,
+			LogLevel:   getEnvOrDefault("LOG_LEVEL", "info"),
+			BasePath:   basePath,
+			AdminToken: os.Getenv("ADMIN_TOKEN"),
+
+			AccessLogSampleRate: accessLogSampleRate,
+			LogRequestBodies:    logRequestBodies,
+			DrainTimeout:        drainTimeout,
+
+			WebhookMaxBodyBytes:  int64(webhookMaxBodyBytes),
+			CallbackMaxBodyBytes: int64(callbackMaxBodyBytes),
+			WebhookTimeout:       webhookTimeout,
+			CallbackTimeout:      callbackTimeout,
 		},
 		Mattermost: MattermostConfig{
-			URL:   os.Getenv("MATTERMOST_URL"),
-			Token: os.Getenv("MATTERMOST_TOKEN"),
+			URL:               os.Getenv("MATTERMOST_URL"),
+			Token:             os.Getenv("MATTERMOST_TOKEN"),
+			SlashCommandToken: os.Getenv("MATTERMOST_SLASH_COMMAND_TOKEN"),
+			TeamID:            os.Getenv("MATTERMOST_TEAM_ID"),
+			ClusterHosts:      splitList(os.Getenv("MATTERMOST_CLUSTER_HOSTS")),
+			Outbound: outbound.Settings{
+				ProxyURL:           os.Getenv("MATTERMOST_PROXY_URL"),
+				CAFile:             os.Getenv("MATTERMOST_CA_FILE"),
+				InsecureSkipVerify: mattermostInsecure,
+			},
 		},
 		Keep: KeepConfig{
-			URL:    os.Getenv("KEEP_URL"),
-			APIKey: os.Getenv("KEEP_API_KEY"),
-			UIURL:  os.Getenv("KEEP_UI_URL"),
+			URL:            os.Getenv("KEEP_URL"),
+			APIKey:         os.Getenv("KEEP_API_KEY"),
+			UIURL:          os.Getenv("KEEP_UI_URL"),
+			SigningKeyFile: os.Getenv("KEEP_SIGNING_KEY_FILE"),
+			SigningKeyID:   os.Getenv("KEEP_SIGNING_KEY_ID"),
+			Outbound: outbound.Settings{
+				ProxyURL:           os.Getenv("KEEP_PROXY_URL"),
+				CAFile:             os.Getenv("KEEP_CA_FILE"),
+				InsecureSkipVerify: keepInsecure,
+			},
 		},
 		Redis: RedisConfig{
 			Addr:     getEnvOrDefault("REDIS_ADDR", "localhost:6379"),
@@ -114,16 +307,33 @@ func LoadFromEnv() (*Config, error) {
 			DB:       redisDB,
 		},
 		Polling: PollingConfig{
-			Enabled:     pollingEnabled,
-			Interval:    pollingInterval,
-			AlertsLimit: pollingAlertsLimit,
-			Timeout:     pollingTimeout,
+			Enabled:       pollingEnabled,
+			Interval:      pollingInterval,
+			AlertsLimit:   pollingAlertsLimit,
+			Timeout:       pollingTimeout,
+			MaxResponseMB: pollingMaxResponseMB,
+
+			BackoffMultiplier: pollingBackoffMultiplier,
+			BackoffMax:        pollingBackoffMax,
+		},
+		Reconcile: ReconcileConfig{
+			OnStart: reconcileOnStart,
+			Timeout: reconcileTimeout,
 		},
 		Setup: SetupConfig{
 			Enabled: setupEnabled,
 		},
+		Storage: StorageConfig{
+			Backend:     getEnvOrDefault("STORAGE_BACKEND", StorageBackendValkey),
+			Path:        getEnvOrDefault("STORAGE_PATH", "/var/lib/kmbridge/kmbridge.db"),
+			DatabaseURL: os.Getenv("DATABASE_URL"),
+		},
+		Ingest: IngestConfig{
+			Mode:     getEnvOrDefault("INGEST_MODE", IngestModeDirect),
+			Consumer: getEnvOrDefault("INGEST_CONSUMER", hostname),
+		},
 		ConfigPath:  getEnvOrDefault("CONFIG_PATH", "/etc/kmbridge/config.yaml"),
-		CallbackURL: os.Getenv("CALLBACK_URL"),
+		CallbackURL: resolveCallbackURL(os.Getenv("CALLBACK_URL"), basePath),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -133,6 +343,31 @@ func LoadFromEnv() (*Config, error) {
 	return cfg, nil
 }
 
+// normalizeBasePath turns "bridge", "/bridge/" and "/bridge" into "/bridge",
+// and an empty value or "/" into "".
+func normalizeBasePath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// resolveCallbackURL completes a CALLBACK_URL given as a bare origin, such as
+// https://ingress.example.com, with the base path and callback route when a
+// base path is set. URLs that already carry a path are used as-is.
+func resolveCallbackURL(raw, basePath string) string {
+	if basePath == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+		return raw
+	}
+	u.Path = basePath + callbackRoute
+	return u.String()
+}
+
 // ApplyFileConfig applies settings from FileConfig if env variables are not set.
 // Env variables have priority over file config.
 func (c *Config) ApplyFileConfig(fc *FileConfig) {
@@ -153,6 +388,17 @@ func (c *Config) ApplyFileConfig(fc *FileConfig) {
 			c.Polling.Timeout = d
 		}
 	}
+	if os.Getenv("POLLING_MAX_RESPONSE_MB") == "" && fc.Polling.MaxResponseMB != nil {
+		c.Polling.MaxResponseMB = *fc.Polling.MaxResponseMB
+	}
+	if os.Getenv("POLLING_BACKOFF_MULTIPLIER") == "" && fc.Polling.BackoffMultiplier != nil {
+		c.Polling.BackoffMultiplier = *fc.Polling.BackoffMultiplier
+	}
+	if os.Getenv("POLLING_BACKOFF_MAX") == "" && fc.Polling.BackoffMax != "" {
+		if d, err := time.ParseDuration(fc.Polling.BackoffMax); err == nil {
+			c.Polling.BackoffMax = d
+		}
+	}
 
 	// Setup: use file config if env not set
 	if os.Getenv("KEEP_SETUP_ENABLED") == "" && fc.Setup.Enabled != nil {
@@ -164,6 +410,27 @@ func (c *Config) Validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port)
 	}
+	if c.Server.AccessLogSampleRate < 0 || c.Server.AccessLogSampleRate > 1 {
+		return fmt.Errorf("ACCESS_LOG_SAMPLE_RATE must be between 0 and 1, got %g", c.Server.AccessLogSampleRate)
+	}
+	if c.Server.DrainTimeout < 0 {
+		return fmt.Errorf("SHUTDOWN_DRAIN_TIMEOUT must not be negative, got %s", c.Server.DrainTimeout)
+	}
+	if c.Server.WebhookMaxBodyBytes < 0 {
+		return fmt.Errorf("WEBHOOK_MAX_BODY_BYTES must not be negative, got %d", c.Server.WebhookMaxBodyBytes)
+	}
+	if c.Server.CallbackMaxBodyBytes < 0 {
+		return fmt.Errorf("CALLBACK_MAX_BODY_BYTES must not be negative, got %d", c.Server.CallbackMaxBodyBytes)
+	}
+	if c.Server.WebhookTimeout < 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must not be negative, got %s", c.Server.WebhookTimeout)
+	}
+	if c.Server.CallbackTimeout < 0 {
+		return fmt.Errorf("CALLBACK_TIMEOUT must not be negative, got %s", c.Server.CallbackTimeout)
+	}
+	if strings.ContainsAny(c.Server.BasePath, "?# ") {
+		return fmt.Errorf("BASE_PATH must be a plain URL path, got %q", c.Server.BasePath)
+	}
 	if c.Mattermost.URL == "" {
 		return fmt.Errorf("MATTERMOST_URL is required")
 	}
@@ -182,6 +449,16 @@ func (c *Config) Validate() error {
 	if c.CallbackURL == "" {
 		return fmt.Errorf("CALLBACK_URL is required")
 	}
+	if c.Mattermost.Outbound.ProxyURL != "" {
+		if _, err := outbound.ParseProxyURL(c.Mattermost.Outbound.ProxyURL); err != nil {
+			return fmt.Errorf("invalid MATTERMOST_PROXY_URL: %w", err)
+		}
+	}
+	if c.Keep.Outbound.ProxyURL != "" {
+		if _, err := outbound.ParseProxyURL(c.Keep.Outbound.ProxyURL); err != nil {
+			return fmt.Errorf("invalid KEEP_PROXY_URL: %w", err)
+		}
+	}
 	if c.Polling.Enabled {
 		if c.Polling.Interval < 10*time.Second {
 			return fmt.Errorf("POLLING_INTERVAL must be at least 10s when polling is enabled, got %s", c.Polling.Interval)
@@ -189,10 +466,61 @@ func (c *Config) Validate() error {
 		if c.Polling.AlertsLimit < 1 {
 			return fmt.Errorf("POLLING_ALERTS_LIMIT must be at least 1, got %d", c.Polling.AlertsLimit)
 		}
+		if c.Polling.MaxResponseMB < 1 {
+			return fmt.Errorf("POLLING_MAX_RESPONSE_MB must be at least 1, got %d", c.Polling.MaxResponseMB)
+		}
+		if c.Polling.BackoffMultiplier < 1 {
+			return fmt.Errorf("POLLING_BACKOFF_MULTIPLIER must be at least 1, got %g", c.Polling.BackoffMultiplier)
+		}
+		if c.Polling.BackoffMax < c.Polling.Interval {
+			return fmt.Errorf("POLLING_BACKOFF_MAX must be at least POLLING_INTERVAL (%s), got %s", c.Polling.Interval, c.Polling.BackoffMax)
+		}
+	}
+	switch c.Storage.Backend {
+	case "", StorageBackendValkey, StorageBackendMemory:
+	case StorageBackendBolt:
+		if c.Storage.Path == "" {
+			return fmt.Errorf("STORAGE_PATH is required when STORAGE_BACKEND is %q", StorageBackendBolt)
+		}
+	case StorageBackendPostgres:
+		if c.Storage.DatabaseURL == "" {
+			return fmt.Errorf("DATABASE_URL is required when STORAGE_BACKEND is %q", StorageBackendPostgres)
+		}
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be %q, %q, %q or %q, got %q", StorageBackendValkey, StorageBackendMemory, StorageBackendBolt, StorageBackendPostgres, c.Storage.Backend)
+	}
+	switch c.Ingest.Mode {
+	case "", IngestModeDirect:
+	case IngestModeStream:
+		if c.Ingest.Consumer == "" {
+			return fmt.Errorf("INGEST_CONSUMER is required when INGEST_MODE is %q", IngestModeStream)
+		}
+	default:
+		return fmt.Errorf("INGEST_MODE must be %q or %q, got %q", IngestModeDirect, IngestModeStream, c.Ingest.Mode)
+	}
+	if c.Reconcile.OnStart {
+		if c.Reconcile.Timeout <= 0 {
+			return fmt.Errorf("RECONCILE_TIMEOUT must be positive, got %s", c.Reconcile.Timeout)
+		}
+		// Reconciliation fetches the same Keep alerts page as polling.
+		if c.Polling.AlertsLimit < 1 {
+			return fmt.Errorf("POLLING_ALERTS_LIMIT must be at least 1, got %d", c.Polling.AlertsLimit)
+		}
 	}
 	return nil
 }
 
+// splitList returns the non-empty items of a comma-separated list.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -224,6 +552,18 @@ func getEnvOrDefaultBool(key string, defaultValue bool) (bool, error) {
 	return b, nil
 }
 
+func getEnvOrDefaultFloat(key string, defaultValue float64) (float64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return defaultValue, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s=%q: %w", key, v, err)
+	}
+	return f, nil
+}
+
 func getEnvOrDefaultDuration(key string, defaultValue time.Duration) (time.Duration, error) {
 	v := os.Getenv(key)
 	if v == "" {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestApplyFileConfig(t *testing.T) {
@@ -331,4 +332,368 @@ func TestApplyFileConfig(t *testing.T) {
 		assert.Equal(t, 2000, cfg.Polling.AlertsLimit, "file config should be applied when env is empty")
 		assert.False(t, cfg.Setup.Enabled, "file config should be applied when env is empty")
 	})
+
+	t.Run("max response size from file unless env set", func(t *testing.T) {
+		fileMaxMB := 16
+		fileConfig := &FileConfig{
+			Polling: FilePollingConfig{MaxResponseMB: &fileMaxMB},
+		}
+
+		t.Setenv("POLLING_MAX_RESPONSE_MB", "")
+		cfg := &Config{Polling: PollingConfig{MaxResponseMB: 64}}
+		cfg.ApplyFileConfig(fileConfig)
+		assert.Equal(t, 16, cfg.Polling.MaxResponseMB)
+
+		t.Setenv("POLLING_MAX_RESPONSE_MB", "128")
+		cfg = &Config{Polling: PollingConfig{MaxResponseMB: 128}}
+		cfg.ApplyFileConfig(fileConfig)
+		assert.Equal(t, 128, cfg.Polling.MaxResponseMB, "env var should take precedence")
+	})
+}
+
+func TestValidatePollingMaxResponse(t *testing.T) {
+	cfg := &Config{
+		Server:      ServerConfig{Port: 8080},
+		Mattermost:  MattermostConfig{URL: "http://mm", Token: "token"},
+		Keep:        KeepConfig{URL: "http://keep", APIKey: "key", UIURL: "http://keep-ui"},
+		CallbackURL: "http://bridge/callback",
+		Polling: PollingConfig{
+			Enabled:       true,
+			Interval:      time.Minute,
+			AlertsLimit:   1000,
+			MaxResponseMB: 64,
+
+			BackoffMultiplier: 2,
+			BackoffMax:        10 * time.Minute,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Polling.MaxResponseMB = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POLLING_MAX_RESPONSE_MB")
+
+	cfg.Polling.Enabled = false
+	assert.NoError(t, cfg.Validate(), "size cap is only checked when polling is enabled")
+}
+
+func TestValidatePollingBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiplier float64
+		max        time.Duration
+		wantErr    string
+	}{
+		{name: "exponential", multiplier: 2, max: 10 * time.Minute},
+		{name: "constant", multiplier: 1, max: time.Minute},
+		{name: "multiplier below 1", multiplier: 0.5, max: 10 * time.Minute, wantErr: "POLLING_BACKOFF_MULTIPLIER"},
+		{name: "max below interval", multiplier: 2, max: 30 * time.Second, wantErr: "POLLING_BACKOFF_MAX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server:      ServerConfig{Port: 8080},
+				Mattermost:  MattermostConfig{URL: "http://mm", Token: "token"},
+				Keep:        KeepConfig{URL: "http://keep", APIKey: "key", UIURL: "http://keep-ui"},
+				CallbackURL: "http://bridge/callback",
+				Polling: PollingConfig{
+					Enabled:           true,
+					Interval:          time.Minute,
+					AlertsLimit:       1000,
+					MaxResponseMB:     64,
+					BackoffMultiplier: tt.multiplier,
+					BackoffMax:        tt.max,
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplyFilePollingBackoff(t *testing.T) {
+	t.Setenv("POLLING_BACKOFF_MULTIPLIER", "")
+	t.Setenv("POLLING_BACKOFF_MAX", "")
+
+	cfg := &Config{Polling: PollingConfig{BackoffMultiplier: 2, BackoffMax: 10 * time.Minute}}
+	multiplier := 1.5
+	cfg.ApplyFileConfig(&FileConfig{Polling: FilePollingConfig{BackoffMultiplier: &multiplier, BackoffMax: "30m"}})
+	assert.Equal(t, 1.5, cfg.Polling.BackoffMultiplier)
+	assert.Equal(t, 30*time.Minute, cfg.Polling.BackoffMax)
+
+	t.Setenv("POLLING_BACKOFF_MULTIPLIER", "3")
+	cfg = &Config{Polling: PollingConfig{BackoffMultiplier: 3, BackoffMax: 10 * time.Minute}}
+	cfg.ApplyFileConfig(&FileConfig{Polling: FilePollingConfig{BackoffMultiplier: &multiplier}})
+	assert.Equal(t, 3.0, cfg.Polling.BackoffMultiplier, "env var should take precedence")
+}
+
+func TestValidateReconcile(t *testing.T) {
+	cfg := &Config{
+		Server:      ServerConfig{Port: 8080},
+		Mattermost:  MattermostConfig{URL: "http://mm", Token: "token"},
+		Keep:        KeepConfig{URL: "http://keep", APIKey: "key", UIURL: "http://keep-ui"},
+		CallbackURL: "http://bridge/callback",
+		Polling:     PollingConfig{AlertsLimit: 1000},
+		Reconcile:   ReconcileConfig{OnStart: true, Timeout: 2 * time.Minute},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Reconcile.Timeout = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RECONCILE_TIMEOUT")
+
+	cfg.Reconcile.Timeout = time.Minute
+	cfg.Polling.AlertsLimit = 0
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POLLING_ALERTS_LIMIT", "reconciliation uses the polling alerts limit")
+
+	cfg.Reconcile.OnStart = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":          "",
+		"/":         "",
+		"bridge":    "/bridge",
+		"/bridge/":  "/bridge",
+		" /a/b/ ":   "/a/b",
+		"/kmbridge": "/kmbridge",
+	} {
+		assert.Equal(t, want, normalizeBasePath(in), "input %q", in)
+	}
+}
+
+func TestResolveCallbackURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		basePath string
+		want     string
+	}{
+		{name: "no base path", raw: "https://bridge.example.com", want: "https://bridge.example.com"},
+		{name: "bare origin with base path", raw: "https://ingress.example.com/", basePath: "/bridge", want: "https://ingress.example.com/bridge/api/v1/callback"},
+		{name: "full url kept", raw: "https://ingress.example.com/bridge/api/v1/callback", basePath: "/bridge", want: "https://ingress.example.com/bridge/api/v1/callback"},
+		{name: "empty", raw: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveCallbackURL(tt.raw, tt.basePath))
+		})
+	}
+}
+
+func TestLoadFromEnvBasePath(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://ingress.example.com")
+	t.Setenv("BASE_PATH", "bridge/")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "/bridge", cfg.Server.BasePath)
+	assert.Equal(t, "https://ingress.example.com/bridge/api/v1/callback", cfg.CallbackURL)
+
+	t.Setenv("BASE_PATH", "/bridge?x=1")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BASE_PATH")
+}
+
+func TestLoadFromEnvAccessLog(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://bridge.example.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, cfg.Server.AccessLogSampleRate)
+	assert.False(t, cfg.Server.LogRequestBodies)
+
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "0.1")
+	t.Setenv("LOG_REQUEST_BODIES", "true")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, cfg.Server.AccessLogSampleRate)
+	assert.True(t, cfg.Server.LogRequestBodies)
+
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "2")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ACCESS_LOG_SAMPLE_RATE")
+}
+
+func TestLoadFromEnvDrainTimeout(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://bridge.example.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Server.DrainTimeout)
+
+	t.Setenv("SHUTDOWN_DRAIN_TIMEOUT", "2m")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.Server.DrainTimeout)
+
+	t.Setenv("SHUTDOWN_DRAIN_TIMEOUT", "-1s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SHUTDOWN_DRAIN_TIMEOUT")
+}
+
+func TestValidateIngestMode(t *testing.T) {
+	cfg := &Config{
+		Server:      ServerConfig{Port: 8080},
+		Mattermost:  MattermostConfig{URL: "http://mm", Token: "token"},
+		Keep:        KeepConfig{URL: "http://keep", APIKey: "key", UIURL: "http://keep-ui"},
+		CallbackURL: "http://bridge/callback",
+		Ingest:      IngestConfig{Mode: IngestModeStream, Consumer: "bridge-1"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Ingest.Consumer = ""
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INGEST_CONSUMER")
+
+	cfg.Ingest.Mode = "kafka"
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INGEST_MODE")
+
+	cfg.Ingest.Mode = IngestModeDirect
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadFromEnvIngest(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://bridge.example.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, IngestModeDirect, cfg.Ingest.Mode)
+	assert.NotEmpty(t, cfg.Ingest.Consumer, "defaults to the hostname")
+
+	t.Setenv("INGEST_MODE", "stream")
+	t.Setenv("INGEST_CONSUMER", "bridge-1")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, IngestModeStream, cfg.Ingest.Mode)
+	assert.Equal(t, "bridge-1", cfg.Ingest.Consumer)
+}
+
+func TestLoadFromEnvOutbound(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://bridge.example.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Mattermost.Outbound)
+	assert.Empty(t, cfg.Keep.Outbound)
+	assert.Empty(t, cfg.Mattermost.ClusterHosts)
+
+	t.Setenv("MATTERMOST_PROXY_URL", "http://proxy.corp:3128")
+	t.Setenv("MATTERMOST_CA_FILE", "/etc/ssl/corp-ca.pem")
+	t.Setenv("MATTERMOST_CLUSTER_HOSTS", "mm-1.corp, mm-2.corp:8065,")
+	t.Setenv("KEEP_TLS_INSECURE_SKIP_VERIFY", "true")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.corp:3128", cfg.Mattermost.Outbound.ProxyURL)
+	assert.Equal(t, "/etc/ssl/corp-ca.pem", cfg.Mattermost.Outbound.CAFile)
+	assert.Equal(t, []string{"mm-1.corp", "mm-2.corp:8065"}, cfg.Mattermost.ClusterHosts)
+	assert.False(t, cfg.Mattermost.Outbound.InsecureSkipVerify)
+	assert.True(t, cfg.Keep.Outbound.InsecureSkipVerify)
+
+	t.Setenv("KEEP_PROXY_URL", "proxy.corp:3128")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "KEEP_PROXY_URL")
+}
+
+func TestLoadFromEnvRequestLimits(t *testing.T) {
+	t.Setenv("MATTERMOST_URL", "http://mm")
+	t.Setenv("MATTERMOST_TOKEN", "token")
+	t.Setenv("KEEP_URL", "http://keep")
+	t.Setenv("KEEP_API_KEY", "key")
+	t.Setenv("KEEP_UI_URL", "http://keep-ui")
+	t.Setenv("CALLBACK_URL", "https://bridge.example.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<20), cfg.Server.WebhookMaxBodyBytes)
+	assert.Equal(t, int64(64<<10), cfg.Server.CallbackMaxBodyBytes)
+	assert.Equal(t, 30*time.Second, cfg.Server.WebhookTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.CallbackTimeout)
+
+	t.Setenv("WEBHOOK_MAX_BODY_BYTES", "4194304")
+	t.Setenv("CALLBACK_TIMEOUT", "3s")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4<<20), cfg.Server.WebhookMaxBodyBytes)
+	assert.Equal(t, 3*time.Second, cfg.Server.CallbackTimeout)
+
+	t.Setenv("WEBHOOK_TIMEOUT", "-1s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WEBHOOK_TIMEOUT")
+}
+
+func TestValidateStorageBackend(t *testing.T) {
+	cfg := &Config{
+		Server:      ServerConfig{Port: 8080},
+		Mattermost:  MattermostConfig{URL: "http://mm", Token: "token"},
+		Keep:        KeepConfig{URL: "http://keep", APIKey: "key", UIURL: "http://keep-ui"},
+		CallbackURL: "http://bridge/callback",
+		Storage:     StorageConfig{Backend: StorageBackendBolt, Path: "/data/kmbridge.db"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Storage.Path = ""
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STORAGE_PATH")
+
+	cfg.Storage.Backend = "sqlite"
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STORAGE_BACKEND")
+
+	cfg.Storage.Backend = StorageBackendPostgres
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DATABASE_URL")
+
+	cfg.Storage.DatabaseURL = "postgres://kmbridge@db/kmbridge"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Storage.Backend = StorageBackendMemory
+	assert.NoError(t, cfg.Validate())
 }
@@ -0,0 +1,12 @@
+package config
+
+import "github.com/alexmorbo/keep-mattermost-bridge/application/port"
+
+// Compile-time contract: FileConfig, LabelRouter and OnCallResolver back several ports in cmd/server.
+var (
+	_ port.MessageConfig   = (*FileConfig)(nil)
+	_ port.ChannelResolver = (*LabelRouter)(nil)
+	_ port.OnCallResolver  = (*OnCallResolver)(nil)
+	_ port.UserMapper      = (*FileConfig)(nil)
+	_ port.DropRules       = (*DropRules)(nil)
+)
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	// 2026-01-03 is a Saturday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr  string
+		match []time.Time
+		miss  []time.Time
+	}{
+		{expr: "0 2 * * 6", match: []time.Time{at(3, 2, 0), at(10, 2, 0)}, miss: []time.Time{at(3, 2, 1), at(4, 2, 0)}},
+		{expr: "*/15 * * * *", match: []time.Time{at(1, 0, 0), at(1, 13, 45)}, miss: []time.Time{at(1, 13, 50)}},
+		{expr: "30 22-23 * * 1-5", match: []time.Time{at(5, 22, 30), at(9, 23, 30)}, miss: []time.Time{at(3, 22, 30), at(5, 21, 30)}},
+		{expr: "0 0 1,15 * *", match: []time.Time{at(1, 0, 0), at(15, 0, 0)}, miss: []time.Time{at(2, 0, 0)}},
+		{expr: "0 0 * * 7", match: []time.Time{at(4, 0, 0)}, miss: []time.Time{at(3, 0, 0)}},
+		// Both day fields restricted: either one matches.
+		{expr: "0 0 13 * 5", match: []time.Time{at(2, 0, 0), at(13, 0, 0)}, miss: []time.Time{at(14, 0, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			for _, m := range tt.match {
+				assert.True(t, s.matches(m), "should fire at %s", m)
+			}
+			for _, m := range tt.miss {
+				assert.False(t, s.matches(m), "should not fire at %s", m)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronLastFire(t *testing.T) {
+	s, err := parseCron("0 2 * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 3, 3, 30, 20, 0, time.UTC)
+	fired, ok := s.lastFire(now, 2*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 3, 2, 0, 0, 0, time.UTC), fired)
+
+	_, ok = s.lastFire(now, time.Hour)
+	assert.False(t, ok)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+var buttonStyles = []string{"default", "primary", "success", "good", "warning", "danger"}
+
+type customAction struct {
+	name       string
+	style      string
+	workflowID string
+	url        string
+	context    *template.Template
+	selector   labelSelector
+}
+
+// customActionData is what custom action payload templates are rendered with.
+type customActionData struct {
+	Fingerprint string            `json:"fingerprint"`
+	Name        string            `json:"name"`
+	Severity    string            `json:"severity"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	User        string            `json:"user"`
+}
+
+var customActionFuncs = template.FuncMap{"json": jsonValue}
+
+func jsonValue(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CustomActionPolicy picks the custom action buttons of firing alert posts
+// and renders what they do from the actions in custom_actions.
+type CustomActionPolicy struct {
+	actions []customAction
+}
+
+// NewCustomActionPolicy compiles the custom actions of cfg.
+func NewCustomActionPolicy(cfg *FileConfig) (*CustomActionPolicy, error) {
+	if len(cfg.CustomActions.Actions) == 0 {
+		return nil, fmt.Errorf("custom_actions.actions must list at least one action when custom actions are enabled")
+	}
+	actions := make([]customAction, 0, len(cfg.CustomActions.Actions))
+	for i, action := range cfg.CustomActions.Actions {
+		name := strings.TrimSpace(action.Name)
+		if name == "" {
+			return nil, fmt.Errorf("custom_actions.actions[%d].name is required", i)
+		}
+		if slices.ContainsFunc(actions, func(a customAction) bool { return a.name == name }) {
+			return nil, fmt.Errorf("custom_actions.actions[%d]: duplicate name %q", i, name)
+		}
+		if action.Style != "" && !slices.Contains(buttonStyles, action.Style) {
+			return nil, fmt.Errorf("custom_actions.actions[%d]: invalid style %q, must be one of %s", i, action.Style, strings.Join(buttonStyles, ", "))
+		}
+		if (action.WorkflowID == "") == (action.URL == "") {
+			return nil, fmt.Errorf("custom_actions.actions[%d] must set exactly one of workflow_id and url", i)
+		}
+		if action.URL != "" {
+			u, err := url.Parse(action.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("custom_actions.actions[%d]: invalid url %q, must be an http or https URL", i, action.URL)
+			}
+		}
+		selector, err := parseLabelSelector(action.Match)
+		if err != nil {
+			return nil, fmt.Errorf("custom_actions.actions[%d]: %w", i, err)
+		}
+		var context *template.Template
+		if action.Context != "" {
+			context, err = template.New(name).Funcs(customActionFuncs).Option("missingkey=error").Parse(action.Context)
+			if err != nil {
+				return nil, fmt.Errorf("invalid custom_actions.actions[%d].context: %w", i, err)
+			}
+		}
+		actions = append(actions, customAction{
+			name:       name,
+			style:      action.Style,
+			workflowID: action.WorkflowID,
+			url:        action.URL,
+			context:    context,
+			selector:   selector,
+		})
+	}
+	return &CustomActionPolicy{actions: actions}, nil
+}
+
+// CustomActions returns the actions whose matchers hold for a and whose
+// payload renders for it, in configured order.
+func (p *CustomActionPolicy) CustomActions(a *alert.Alert) []port.CustomAction {
+	var actions []port.CustomAction
+	for _, action := range p.actions {
+		if !action.selector.matches(a.Severity().String(), a.Labels()) {
+			continue
+		}
+		if _, err := action.render(a, ""); err != nil {
+			continue
+		}
+		actions = append(actions, port.CustomAction{Name: action.name, Style: action.style})
+	}
+	return actions
+}
+
+// Request renders the request of the action named name for a, clicked by
+// username.
+func (p *CustomActionPolicy) Request(name string, a *alert.Alert, username string) (port.CustomActionRequest, error) {
+	i := slices.IndexFunc(p.actions, func(action customAction) bool { return action.name == name })
+	if i < 0 {
+		return port.CustomActionRequest{}, fmt.Errorf("unknown custom action %q", name)
+	}
+	action := p.actions[i]
+	if !action.selector.matches(a.Severity().String(), a.Labels()) {
+		return port.CustomActionRequest{}, fmt.Errorf("custom action %q does not apply to the alert", name)
+	}
+	body, err := action.render(a, username)
+	if err != nil {
+		return port.CustomActionRequest{}, err
+	}
+	return port.CustomActionRequest{WorkflowID: action.workflowID, URL: action.url, Body: body}, nil
+}
+
+// render returns the JSON payload of the action for a. It fails when the
+// template uses a label a lacks or does not render a JSON object.
+func (action customAction) render(a *alert.Alert, username string) ([]byte, error) {
+	data := customActionData{
+		Fingerprint: a.Fingerprint().Value(),
+		Name:        a.Name(),
+		Severity:    a.Severity().String(),
+		Status:      a.Status().String(),
+		Labels:      a.Labels(),
+		User:        username,
+	}
+	if action.context == nil {
+		return json.Marshal(data)
+	}
+	var sb strings.Builder
+	if err := action.context.Execute(&sb, data); err != nil {
+		return nil, fmt.Errorf("render custom action %q: %w", action.name, err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &payload); err != nil {
+		return nil, fmt.Errorf("custom action %q did not render a JSON object: %w", action.name, err)
+	}
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestCustomActionPolicy(t *testing.T) {
+	cfg := &FileConfig{
+		CustomActions: CustomActionsConfig{
+			Enabled: true,
+			Actions: []CustomActionConfig{
+				{Name: "Restart pod", Style: "danger", WorkflowID: "restart-pod", Context: `{"namespace": {{json .Labels.namespace}}, "pod": {{json .Labels.pod}}, "by": {{json .User}}}`},
+				{Name: "Page SRE", URL: "https://hooks.example.com/page", Match: []string{"severity=critical"}},
+			},
+		},
+	}
+	policy, err := NewCustomActionPolicy(cfg)
+	require.NoError(t, err)
+
+	newAlert := func(severity string, labels map[string]string) *alert.Alert {
+		return alert.RestoreAlert(alert.RestoreFingerprint("fp-1"), "PodCrashLooping", alert.RestoreSeverity(severity), alert.RestoreStatus(alert.StatusFiring), "", "prometheus", labels, time.Time{})
+	}
+	pod := newAlert("critical", map[string]string{"namespace": "prod", "pod": `api "1"`})
+	node := newAlert("warning", map[string]string{"node": "node-1"})
+
+	assert.Equal(t, []port.CustomAction{{Name: "Restart pod", Style: "danger"}, {Name: "Page SRE"}}, policy.CustomActions(pod))
+	assert.Empty(t, policy.CustomActions(node), "actions whose labels or matchers do not apply are hidden")
+
+	req, err := policy.Request("Restart pod", pod, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "restart-pod", req.WorkflowID)
+	assert.JSONEq(t, `{"namespace": "prod", "pod": "api \"1\"", "by": "alice"}`, string(req.Body))
+
+	req, err = policy.Request("Page SRE", pod, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/page", req.URL)
+	assert.JSONEq(t, `{"fingerprint": "fp-1", "name": "PodCrashLooping", "severity": "critical", "status": "firing", "labels": {"namespace": "prod", "pod": "api \"1\""}, "user": "alice"}`, string(req.Body))
+
+	_, err = policy.Request("Page SRE", node, "alice")
+	assert.Error(t, err)
+	_, err = policy.Request("Drain node", pod, "alice")
+	assert.Error(t, err)
+}
+
+func TestValidateCustomActions(t *testing.T) {
+	workflow := CustomActionConfig{Name: "Restart pod", WorkflowID: "restart-pod"}
+
+	tests := []struct {
+		name    string
+		actions []CustomActionConfig
+		wantErr string
+	}{
+		{name: "valid", actions: []CustomActionConfig{workflow, {Name: "Page SRE", Style: "primary", URL: "https://hooks.example.com/page"}}},
+		{name: "no actions", wantErr: "custom_actions.actions must list at least one action"},
+		{name: "no name", actions: []CustomActionConfig{{WorkflowID: "restart-pod"}}, wantErr: "custom_actions.actions[0].name is required"},
+		{name: "duplicate name", actions: []CustomActionConfig{workflow, workflow}, wantErr: `custom_actions.actions[1]: duplicate name "Restart pod"`},
+		{name: "bad style", actions: []CustomActionConfig{{Name: "Restart pod", Style: "red", WorkflowID: "restart-pod"}}, wantErr: `invalid style "red"`},
+		{name: "no target", actions: []CustomActionConfig{{Name: "Restart pod"}}, wantErr: "must set exactly one of workflow_id and url"},
+		{name: "two targets", actions: []CustomActionConfig{{Name: "Restart pod", WorkflowID: "restart-pod", URL: "https://hooks.example.com"}}, wantErr: "must set exactly one of workflow_id and url"},
+		{name: "bad url", actions: []CustomActionConfig{{Name: "Restart pod", URL: "ftp://hooks.example.com"}}, wantErr: `invalid url "ftp://hooks.example.com"`},
+		{name: "bad context", actions: []CustomActionConfig{{Name: "Restart pod", WorkflowID: "restart-pod", Context: "{{.Labels.pod"}}, wantErr: "invalid custom_actions.actions[0].context"},
+		{name: "bad matcher", actions: []CustomActionConfig{{Name: "Restart pod", WorkflowID: "restart-pod", Match: []string{"pod"}}}, wantErr: "invalid label matcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{CustomActions: CustomActionsConfig{Enabled: true, Actions: tt.actions}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type dropRule struct {
+	name       string
+	alerts     []*regexp.Regexp
+	severities []string
+	selector   labelSelector
+}
+
+// DropRules evaluates the rules in drop_rules.
+type DropRules struct {
+	rules []dropRule
+}
+
+// NewDropRules compiles the drop rules of cfg.
+func NewDropRules(cfg *FileConfig) (*DropRules, error) {
+	rules, err := compileDropRules(cfg.DropRules.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return &DropRules{rules: rules}, nil
+}
+
+func compileDropRules(rules []DropRuleConfig) ([]dropRule, error) {
+	compiled := make([]dropRule, 0, len(rules))
+	names := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("drop_rules.rules[%d].name is required", i)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("drop_rules.rules[%d]: duplicate name %q", i, r.Name)
+		}
+		names[r.Name] = true
+		if len(r.Alerts)+len(r.Severities)+len(r.Match) == 0 {
+			return nil, fmt.Errorf("drop_rules.rules[%d] needs alerts, severities or match", i)
+		}
+
+		rule := dropRule{name: r.Name, severities: r.Severities}
+		for _, pattern := range r.Alerts {
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid drop_rules.rules[%d].alerts pattern %q: %w", i, pattern, err)
+			}
+			rule.alerts = append(rule.alerts, re)
+		}
+		for _, severity := range r.Severities {
+			if _, err := alert.NewSeverity(severity); err != nil {
+				return nil, fmt.Errorf("drop_rules.rules[%d].severities: %w", i, err)
+			}
+		}
+		selector, err := parseLabelSelector(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("drop_rules.rules[%d]: %w", i, err)
+		}
+		rule.selector = selector
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+func (r dropRule) matches(name, severity string, labels map[string]string) bool {
+	if len(r.alerts) > 0 && !slices.ContainsFunc(r.alerts, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+		return false
+	}
+	if len(r.severities) > 0 && !slices.Contains(r.severities, severity) {
+		return false
+	}
+	return r.selector.matches(severity, labels)
+}
+
+// RuleForAlert returns the name of the first rule, in configuration order,
+// that drops the alert.
+func (d *DropRules) RuleForAlert(name, severity string, labels map[string]string) (string, bool) {
+	for _, r := range d.rules {
+		if r.matches(name, severity, labels) {
+			return r.name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropRules(t *testing.T) {
+	cfg := &FileConfig{DropRules: DropRulesConfig{Enabled: true, Rules: []DropRuleConfig{
+		{Name: "watchdog", Alerts: []string{"Watchdog", "InfoInhibitor"}},
+		{Name: "dev-info", Severities: []string{"info", "low"}, Match: []string{"namespace=dev"}},
+		{Name: "cpu-throttling", Alerts: []string{"CPUThrottling.*"}, Match: []string{`namespace=~"kube-.*"`}},
+	}}}
+	require.NoError(t, cfg.Validate())
+	rules, err := NewDropRules(cfg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		alert    string
+		severity string
+		labels   map[string]string
+		want     string
+	}{
+		{name: "name", alert: "Watchdog", severity: "critical", want: "watchdog"},
+		{name: "name matches whole", alert: "WatchdogDown", severity: "critical"},
+		{name: "severity and labels", alert: "DiskFull", severity: "info", labels: map[string]string{"namespace": "dev"}, want: "dev-info"},
+		{name: "severity only", alert: "DiskFull", severity: "info", labels: map[string]string{"namespace": "prod"}},
+		{name: "labels only", alert: "DiskFull", severity: "critical", labels: map[string]string{"namespace": "dev"}},
+		{name: "name and labels", alert: "CPUThrottlingHigh", severity: "warning", labels: map[string]string{"namespace": "kube-system"}, want: "cpu-throttling"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := rules.RuleForAlert(tt.alert, tt.severity, tt.labels)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+func TestValidateDropRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []DropRuleConfig
+		wantErr string
+	}{
+		{name: "no rules", wantErr: "drop_rules.rules must list at least one rule"},
+		{name: "no name", rules: []DropRuleConfig{{Severities: []string{"info"}}}, wantErr: "name is required"},
+		{name: "duplicate name", rules: []DropRuleConfig{{Name: "r", Severities: []string{"info"}}, {Name: "r", Severities: []string{"low"}}}, wantErr: "duplicate name"},
+		{name: "matches everything", rules: []DropRuleConfig{{Name: "r"}}, wantErr: "needs alerts, severities or match"},
+		{name: "bad regex", rules: []DropRuleConfig{{Name: "r", Alerts: []string{"Watch("}}}, wantErr: "invalid drop_rules.rules[0].alerts pattern"},
+		{name: "bad severity", rules: []DropRuleConfig{{Name: "r", Severities: []string{"sev9"}}}, wantErr: "drop_rules.rules[0].severities"},
+		{name: "bad matcher", rules: []DropRuleConfig{{Name: "r", Match: []string{"team"}}}, wantErr: "invalid label matcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{DropRules: DropRulesConfig{Enabled: true, Rules: tt.rules}}
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envOverridePrefix starts the names of the environment variables that
+// override file config keys.
+const envOverridePrefix = "KMB_"
+
+// envOverride is a file config key environment variables can set.
+type envOverride struct {
+	key   string // dotted YAML path, e.g. message.footer.text
+	index []int  // field path in FileConfig
+}
+
+// envOverrides maps environment variable names to the file config keys they
+// override. Names are derived from the YAML keys: KMB_ followed by the path
+// in upper case, joined by underscores, so message.footer.text is
+// KMB_MESSAGE_FOOTER_TEXT. Nested sections are followed down to keys that
+// hold a value, a list or a map.
+func envOverrides() (map[string]envOverride, error) {
+	overrides := make(map[string]envOverride)
+	var walk func(t reflect.Type, keys []string, index []int) error
+	walk = func(t reflect.Type, keys []string, index []int) error {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			tag := field.Tag.Get("yaml")
+			name, opts, _ := strings.Cut(tag, ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			fieldIndex := append(slices.Clone(index), i)
+			if opts == "inline" {
+				if err := walk(field.Type, keys, fieldIndex); err != nil {
+					return err
+				}
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fieldKeys := append(slices.Clone(keys), name)
+			if field.Type.Kind() == reflect.Struct {
+				if err := walk(field.Type, fieldKeys, fieldIndex); err != nil {
+					return err
+				}
+				continue
+			}
+			env := envOverridePrefix + strings.ToUpper(strings.Join(fieldKeys, "_"))
+			key := strings.Join(fieldKeys, ".")
+			if other, ok := overrides[env]; ok {
+				return fmt.Errorf("%s would override both %s and %s", env, other.key, key)
+			}
+			overrides[env] = envOverride{key: key, index: fieldIndex}
+		}
+		return nil
+	}
+	if err := walk(reflect.TypeFor[FileConfig](), nil, nil); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// applyEnvOverrides sets the file config keys named by the KMB_ variables
+// of environ, given as KEY=value pairs, over the values of the config file.
+// Text keys take the value as is; other keys parse it as YAML, so lists and
+// maps are written inline, e.g. ["a", "b"] or {critical: "#FF0000"}. A
+// variable set to an empty value clears its key, so the default applies.
+// Unknown KMB_ variables are an error, so typos do not go unnoticed.
+func (c *FileConfig) applyEnvOverrides(environ []string) error {
+	var overrides map[string]envOverride
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, envOverridePrefix) {
+			continue
+		}
+		if overrides == nil {
+			var err error
+			if overrides, err = envOverrides(); err != nil {
+				return err
+			}
+		}
+		override, ok := overrides[name]
+		if !ok {
+			return fmt.Errorf("%s does not override any config key", name)
+		}
+		field := reflect.ValueOf(c).Elem().FieldByIndex(override.index)
+		if value == "" {
+			field.SetZero()
+			continue
+		}
+		if field.Kind() == reflect.String {
+			field.SetString(value)
+			continue
+		}
+		parsed := reflect.New(field.Type())
+		if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+			return fmt.Errorf("parse %s for %s: %w", name, override.key, err)
+		}
+		field.Set(parsed.Elem())
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	overrides, err := envOverrides()
+	require.NoError(t, err, "every file config key has its own variable")
+
+	for env, key := range map[string]string{
+		"KMB_MESSAGE_FOOTER_TEXT":         "message.footer.text",
+		"KMB_CHANNELS_DEFAULT_CHANNEL_ID": "channels.default_channel_id",
+		"KMB_CHANNELS_ROUTING":            "channels.routing",
+		"KMB_POLLING_ENABLED":             "polling.enabled",
+		"KMB_QUIET_HOURS_START":           "quiet_hours.start",
+		"KMB_SEVERITY_MAP":                "severity_map",
+	} {
+		require.Contains(t, overrides, env)
+		assert.Equal(t, key, overrides[env].key)
+	}
+	assert.NotContains(t, overrides, "KMB_MESSAGE_FOOTER", "sections are not overridden as a whole")
+}
+
+func TestLoadFromFileAndEnv(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+channels:
+  default_channel_id: "general"
+message:
+  footer:
+    text: "From file"
+    icon_url: "https://example.com/icon.png"
+polling:
+  interval: "30s"
+labels:
+  exclude: ["pod"]
+`), 0600))
+
+	cfg, err := LoadFromFileAndEnv(configPath, []string{
+		"PATH=/usr/bin",
+		"KMB_MESSAGE_FOOTER_TEXT=On call: #ops",
+		"KMB_CHANNELS_ROUTING=[{severity: critical, channel_id: pager}]",
+		"KMB_POLLING_ENABLED=false",
+		"KMB_POLLING_ALERTS_LIMIT=500",
+		"KMB_MESSAGE_COLORS={critical: \"#000000\"}",
+		"KMB_LABELS_EXCLUDE=[pod, instance]",
+		"KMB_CHANNELS_DEFAULT_CHANNEL_ID=",
+		"KMB_POLLING_INTERVAL=",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "On call: #ops", cfg.Message.Footer.Text, "text keys take the value as is")
+	assert.Equal(t, "https://example.com/icon.png", cfg.Message.Footer.IconURL, "keys without a variable keep the file's value")
+	assert.Empty(t, cfg.Channels.DefaultChannelID, "empty variables clear their key")
+	assert.Empty(t, cfg.Polling.Interval)
+	assert.Equal(t, []RoutingRule{{Severity: "critical", ChannelID: "pager"}}, cfg.Channels.Routing)
+	require.NotNil(t, cfg.Polling.Enabled)
+	assert.False(t, *cfg.Polling.Enabled)
+	require.NotNil(t, cfg.Polling.AlertsLimit)
+	assert.Equal(t, 500, *cfg.Polling.AlertsLimit)
+	assert.Equal(t, "#000000", cfg.Message.Colors["critical"])
+	assert.Equal(t, []string{"pod", "instance"}, cfg.Labels.Exclude)
+}
+
+func TestLoadFromFileAndEnvWithoutFile(t *testing.T) {
+	cfg, err := LoadFromFileAndEnv("/nonexistent/path/config.yaml", []string{"KMB_CHANNELS_DEFAULT_CHANNEL_ID=alerts", "KMB_MESSAGE_FOOTER_TEXT="})
+	require.NoError(t, err)
+	assert.Equal(t, "alerts", cfg.Channels.DefaultChannelID)
+	assert.Equal(t, "Keep AIOps", cfg.Message.Footer.Text, "a cleared key falls back to its default")
+
+	_, err = LoadFromFileAndEnv("/nonexistent/path/config.yaml", []string{"KMB_STATUS_BOARD_ENABLED=true", "KMB_STATUS_BOARD_UPDATE_INTERVAL=1ms"})
+	require.Error(t, err, "without a file the overrides are validated")
+	assert.Contains(t, err.Error(), "status_board.update_interval must be at least 1s")
+}
+
+func TestLoadFromFileAndEnvErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		wantErr string
+	}{
+		{name: "unknown key", env: "KMB_MESSAGE_FOOTER_TXT=x", wantErr: "KMB_MESSAGE_FOOTER_TXT does not override any config key"},
+		{name: "malformed value", env: "KMB_POLLING_ALERTS_LIMIT=many", wantErr: "parse KMB_POLLING_ALERTS_LIMIT for polling.alerts_limit"},
+		{name: "not a bool", env: "KMB_STATUS_BOARD_ENABLED=maybe", wantErr: "parse KMB_STATUS_BOARD_ENABLED for status_board.enabled"},
+		{name: "validated", env: "KMB_LABELS_EXCLUDE=[\"[\"]", wantErr: "invalid label exclude pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFromFileAndEnv("/nonexistent/path/config.yaml", []string{tt.env})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadFromFileIgnoresEnv(t *testing.T) {
+	t.Setenv("KMB_MESSAGE_FOOTER_TEXT", "From env")
+	cfg, err := LoadFromFile("/nonexistent/path/config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "Keep AIOps", cfg.Message.Footer.Text, "tenant config files are not overridden")
+}
@@ -6,27 +6,650 @@ import (
 	"path"
 	"path/filepath"
 	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/cron"
 )
 
 type FileConfig struct {
-	Channels ChannelsConfig    `yaml:"channels"`
-	Message  MessageConfig     `yaml:"message"`
-	Labels   LabelsConfig      `yaml:"labels"`
-	Users    UsersConfig       `yaml:"users"`
-	Polling  FilePollingConfig `yaml:"polling"`
-	Setup    FileSetupConfig   `yaml:"setup"`
+	Channels       ChannelsConfig         `yaml:"channels"`
+	Message        MessageConfig          `yaml:"message"`
+	Labels         LabelsConfig           `yaml:"labels"`
+	Users          UsersConfig            `yaml:"users"`
+	Polling        FilePollingConfig      `yaml:"polling"`
+	Setup          FileSetupConfig        `yaml:"setup"`
+	Badge          BadgeConfig            `yaml:"badge"`
+	AlertGrouping  AlertGroupingConfig    `yaml:"alert_grouping"`
+	Snooze         SnoozeConfig           `yaml:"snooze"`
+	TimedAck       TimedAckConfig         `yaml:"timed_ack"`
+	Flapping       FlappingConfig         `yaml:"flapping"`
+	Assign         AssignConfig           `yaml:"assign"`
+	ResolveDialog  ResolveDialogConfig    `yaml:"resolve_dialog"`
+	AssigneeRetry  AssigneeRetryConfig    `yaml:"assignee_retry"`
+	APIRetry       APIRetryConfig         `yaml:"api_retry"`
+	RateLimit      RateLimitConfig        `yaml:"rate_limit"`
+	KeepBreaker    KeepBreakerConfig      `yaml:"keep_circuit_breaker"`
+	OfflineActions OfflineActionsConfig   `yaml:"offline_actions"`
+	UpdateCoalesce UpdateCoalesceConfig   `yaml:"update_coalescing"`
+	Reactions      ReactionsConfig        `yaml:"reactions"`
+	ReactionAction ReactionActionsConfig  `yaml:"reaction_actions"`
+	Escalation     EscalationConfig       `yaml:"escalation"`
+	CopyCommands   CopyCommandsConfig     `yaml:"copy_commands"`
+	DirectMessages DirectMessagesConfig   `yaml:"direct_messages"`
+	Budget         BudgetConfig           `yaml:"budget"`
+	Correlation    CorrelationConfig      `yaml:"correlation"`
+	Retention      RetentionConfig        `yaml:"retention"`
+	SLO            SLOConfig              `yaml:"slo"`
+	Digest         DigestConfig           `yaml:"digest"`
+	DeadLetter     DeadLetterConfig       `yaml:"dead_letter"`
+	ErrorPosts     ErrorPostsConfig       `yaml:"error_posts"`
+	CallbackWatch  CallbackWatchConfig    `yaml:"callback_watchdog"`
+	Permissions    PermissionsConfig      `yaml:"permissions"`
+	Audit          AuditConfig            `yaml:"audit"`
+	Maintenance    MaintenanceConfig      `yaml:"maintenance"`
+	DropRules      DropRulesConfig        `yaml:"drop_rules"`
+	Incidents      IncidentsConfig        `yaml:"incidents"`
+	IngestQueue    IngestQueueConfig      `yaml:"ingest_queue"`
+	IngestStream   IngestStreamConfig     `yaml:"ingest_stream"`
+	Locking        LockingConfig          `yaml:"locking"`
+	PostTTL        PostTTLConfig          `yaml:"post_ttl"`
+	Mentions       MentionsConfig         `yaml:"mentions"`
+	QuietHours     QuietHoursPolicyConfig `yaml:"quiet_hours"`
+	Links          LinksConfig            `yaml:"links"`
+	CustomActions  CustomActionsConfig    `yaml:"custom_actions"`
+	WorkflowMenu   WorkflowMenuConfig     `yaml:"workflow_menu"`
+	Enrichments    EnrichmentFieldsConfig `yaml:"enrichment_fields"`
+	ChannelNames   ChannelNamesConfig     `yaml:"channel_names"`
+	StatusBoard    StatusBoardConfig      `yaml:"status_board"`
+	ThreadNotes    ThreadNotesConfig      `yaml:"thread_notes"`
+	Annotations    AnnotationsConfig      `yaml:"annotations"`
+	SourceLinks    SourceLinksConfig      `yaml:"source_links"`
+	// SeverityMap maps severities sent in other formats, e.g. "sev1", "P2"
+	// or "5", onto critical, high, warning, info or low. Keys match
+	// case-insensitively.
+	SeverityMap map[string]string `yaml:"severity_map"`
+	// AcceptedSeverities lists the severities alerts may have; alerts with
+	// another one are rejected. Default: critical, high, warning, info and
+	// low; add debug to accept debug alerts.
+	AcceptedSeverities []string `yaml:"accepted_severities"`
+	// MattermostServers are further Mattermost servers alert posts can go
+	// to, picked by the server of the routing rule of their channel.
+	MattermostServers []MattermostServerConfig `yaml:"mattermost_servers"`
+	// Tenants are further Keep and Mattermost pairs served by this bridge,
+	// each with its own routes, store and channels.
+	Tenants []TenantConfig `yaml:"tenants"`
+}
+
+// EnrichmentFieldsConfig shows Keep enrichments of alerts, e.g. an AI summary
+// or notes, as fields of their posts in the listed order. They are fetched
+// from Keep when an alert is posted and refreshed by the poller when they
+// change.
+type EnrichmentFieldsConfig struct {
+	Enabled bool                    `yaml:"enabled"`
+	Fields  []EnrichmentFieldConfig `yaml:"fields"`
+}
+
+// SourceLinksConfig adds a Source field to the posts of alerts from one of
+// Sources, linking the source name to the alert in the system that generated
+// it: the generatorURL of the webhook, Keep's url, or else the first of
+// Annotations the alert carries.
+type SourceLinksConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Sources     []string `yaml:"sources"`     // e.g. prometheus, grafana; empty: every source
+	Annotations []string `yaml:"annotations"` // default: generatorURL, generator_url
+}
+
+// EnrichmentFieldConfig shows the enrichment Key under Title.
+type EnrichmentFieldConfig struct {
+	Key   string `yaml:"key"`   // e.g. ai_summary
+	Title string `yaml:"title"` // default: the key
+	Short bool   `yaml:"short"` // shown side by side with other short fields
+}
+
+// AnnotationsConfig shows the annotations of alerts, e.g. summary or
+// runbook_url from Alertmanager, as full-width fields after their labels.
+// Labels identify an alert; annotations describe it and are never used for
+// routing or grouping.
+type AnnotationsConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	Display []string          `yaml:"display"` // shown in this order; default: every annotation not excluded, sorted
+	Exclude []string          `yaml:"exclude"` // default: description, already shown as the description
+	Rename  map[string]string `yaml:"rename"`  // field titles, e.g. runbook_url: Runbook
+}
+
+// WorkflowMenuConfig adds a Run workflow menu to firing alert posts listing
+// Keep workflows, refreshed every RefreshInterval. Picking one runs it for
+// the alert and replies in the thread once the run ends, or once it is still
+// running after ResultTimeout.
+type WorkflowMenuConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	Workflows       []string `yaml:"workflows"`        // IDs or names; default: every enabled workflow
+	RefreshInterval string   `yaml:"refresh_interval"` // default: 5m
+	ResultTimeout   string   `yaml:"result_timeout"`   // default: 2m
+}
+
+// CustomActionsConfig adds a button per action to firing alert posts, after
+// the built-in buttons. Clicking one runs a Keep workflow or POSTs to a URL.
+type CustomActionsConfig struct {
+	Enabled bool                 `yaml:"enabled"`
+	Actions []CustomActionConfig `yaml:"actions"`
+}
+
+// CustomActionConfig runs the Keep workflow WorkflowID, or POSTs to URL, for
+// alerts whose labels match every matcher in Match. Context is a Go
+// text/template rendering the JSON payload from .Fingerprint, .Name,
+// .Severity, .Status, .Labels and .User, the clicking Mattermost user; the
+// json function encodes a value as JSON. Without Context the payload holds
+// all of them.
+type CustomActionConfig struct {
+	Name       string   `yaml:"name"`        // button label, unique
+	Style      string   `yaml:"style"`       // default, primary, success, good, warning or danger
+	WorkflowID string   `yaml:"workflow_id"` // Keep workflow to run
+	URL        string   `yaml:"url"`         // or URL to POST to
+	Context    string   `yaml:"context"`     // e.g. {"pod": {{json .Labels.pod}}}
+	Match      []string `yaml:"match"`       // e.g. "pod=~.+"
+}
+
+// LinksConfig adds runbook and dashboard links to alert posts. For each kind
+// of link the first rule matching the alert that sets it applies.
+type LinksConfig struct {
+	Enabled bool       `yaml:"enabled"`
+	Rules   []LinkRule `yaml:"rules"`
+}
+
+// LinkRule matches alerts whose name matches one of Alerts and whose labels
+// match every matcher in Match; an empty list matches every alert. Runbook
+// and Dashboard are Go text/templates with .Fingerprint, .Name, .Severity,
+// .Status and .Labels, e.g. "https://grafana/d/pods?var-pod={{.Labels.pod}}".
+type LinkRule struct {
+	Alerts    []string `yaml:"alerts"` // alert name globs, e.g. "KubePod*"
+	Match     []string `yaml:"match"`  // e.g. "team=payments", "env!=dev"
+	Runbook   string   `yaml:"runbook"`
+	Dashboard string   `yaml:"dashboard"`
+}
+
+// MentionsConfig mentions users or groups in the text of new firing alert
+// posts. The first rule matching the alert's severity and channel applies.
+// During QuietHours only rules with IgnoreQuietHours mention anybody.
+type MentionsConfig struct {
+	Enabled    bool             `yaml:"enabled"`
+	Rules      []MentionRule    `yaml:"rules"`
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+}
+
+// MentionRule mentions Mention for alerts with one of Severities posted in
+// one of Channels. An empty list matches every severity or channel.
+type MentionRule struct {
+	Severities       []string `yaml:"severities"`
+	Channels         []string `yaml:"channels"` // channel IDs
+	Mention          []string `yaml:"mention"`  // e.g. "@here", "@channel", "@sre-oncall"
+	IgnoreQuietHours bool     `yaml:"ignore_quiet_hours"`
+}
+
+// QuietHoursConfig is a daily period from Start to End, spanning midnight
+// when End is earlier than Start. With Weekends, Saturdays and Sundays are
+// quiet all day. Unset Start and End mean no quiet hours.
+type QuietHoursConfig struct {
+	Start    string `yaml:"start"`    // "22:00"
+	End      string `yaml:"end"`      // "07:00"
+	Timezone string `yaml:"timezone"` // IANA name; default: UTC
+	Weekends bool   `yaml:"weekends"`
+}
+
+// QuietHoursPolicyConfig changes how new firing alerts are posted during
+// quiet hours. The first rule matching an alert's severity and channel
+// decides; alerts no rule matches are posted as usual. Held alerts are
+// checked every CheckInterval and released once quiet hours end.
+type QuietHoursPolicyConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	QuietHoursConfig `yaml:",inline"`
+	Rules            []QuietHoursRule `yaml:"rules"`
+	DigestChannelID  string           `yaml:"digest_channel_id"` // optional; default: the held alerts' channels
+	CheckInterval    string           `yaml:"check_interval"`    // default: 1m
+}
+
+// QuietHoursRule applies Action to alerts with one of Severities routed to
+// one of Channels. An empty list matches every severity or channel.
+type QuietHoursRule struct {
+	Severities []string `yaml:"severities"`
+	Channels   []string `yaml:"channels"`   // channel IDs
+	Action     string   `yaml:"action"`     // notify | mute | route | hold
+	ChannelID  string   `yaml:"channel_id"` // required for route
+}
+
+// PostTTLConfig sets how long the bridge keeps track of an alert post, per
+// alert severity. Severities not listed use Default; a TTL of 0 keeps posts
+// until their alert resolves. Posts whose TTL lapsed are marked as expired in
+// Mattermost, checked every CheckInterval. Requires the Valkey post store.
+type PostTTLConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	Default       string            `yaml:"default"`        // default: 168h
+	Severities    map[string]string `yaml:"severities"`     // severity -> TTL, "0" for never
+	CheckInterval string            `yaml:"check_interval"` // default: 1m
+}
+
+// LockingConfig lets several bridge replicas run against the same Valkey.
+// Alert webhooks and button actions for one fingerprint are handled under a
+// lock that expires after TTL, and webhooks delivered again within
+// IdempotencyTTL are skipped.
+type LockingConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	TTL            string `yaml:"ttl"`             // default: 45s
+	IdempotencyTTL string `yaml:"idempotency_ttl"` // default: 10m
+}
+
+// IngestStreamConfig tunes the Valkey stream used with INGEST_MODE=stream.
+// Alerts a replica took but did not finish are claimed by another replica
+// after ClaimIdle; an alert that failed MaxDeliveries times is dropped.
+type IngestStreamConfig struct {
+	MaxLen        int64  `yaml:"max_len"`        // default: 100000, approximate
+	Batch         int    `yaml:"batch"`          // default: 10
+	ClaimIdle     string `yaml:"claim_idle"`     // default: 1m
+	MaxDeliveries int    `yaml:"max_deliveries"` // default: 5
+}
+
+// IngestQueueConfig answers alert webhooks with 202 Accepted as soon as the
+// alert is queued and leaves posting to a pool of workers. Failed alerts are
+// retried Attempts times, waiting longer before each retry.
+type IngestQueueConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Size         int    `yaml:"size"`          // default: 1000
+	Workers      int    `yaml:"workers"`       // default: 4
+	Attempts     int    `yaml:"attempts"`      // default: 3, including the first
+	InitialDelay string `yaml:"initial_delay"` // default: 1s
+	MaxDelay     string `yaml:"max_delay"`     // default: 30s
+}
+
+// IncidentsConfig posts Keep incidents, which group related alerts, with
+// buttons to acknowledge or resolve them. Keep sends incidents to
+// /api/v1/webhook/incident.
+type IncidentsConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	ChannelID string `yaml:"channel_id"` // default: routed by severity like alerts
+}
+
+// MaintenanceConfig silences alerts during planned work. While a window is
+// open, new firing alerts that match it are either posted as maintenance
+// cards without buttons or direct messages, or not posted at all.
+type MaintenanceConfig struct {
+	Enabled bool                      `yaml:"enabled"`
+	Windows []MaintenanceWindowConfig `yaml:"windows"`
+}
+
+// MaintenanceWindowConfig is either a fixed interval from Start to End or a
+// recurring window that opens whenever Schedule fires and stays open for
+// Duration.
+type MaintenanceWindowConfig struct {
+	Name     string   `yaml:"name"`
+	Match    []string `yaml:"match"`    // label matchers like channels.label_routing; empty matches every alert
+	Action   string   `yaml:"action"`   // "annotate" or "suppress"; default: annotate
+	Start    string   `yaml:"start"`    // RFC3339
+	End      string   `yaml:"end"`      // RFC3339
+	Schedule string   `yaml:"schedule"` // cron expression, e.g. "0 2 * * 6"
+	Duration string   `yaml:"duration"` // at most 24h
+	Timezone string   `yaml:"timezone"` // IANA name the schedule runs in; default: UTC
+}
+
+// DropRulesConfig ignores alerts nobody wants to see in Mattermost. Alerts
+// matching one of Rules are dropped when they arrive, before they are posted
+// or looked up anywhere.
+type DropRulesConfig struct {
+	Enabled bool             `yaml:"enabled"`
+	Rules   []DropRuleConfig `yaml:"rules"`
+}
+
+// DropRuleConfig matches alerts whose name matches one of Alerts, whose
+// severity is one of Severities and whose labels match every matcher in
+// Match. An empty list matches every alert, but a rule needs at least one.
+type DropRuleConfig struct {
+	Name       string   `yaml:"name"`
+	Alerts     []string `yaml:"alerts"`     // regular expressions matching the whole alert name
+	Severities []string `yaml:"severities"` // e.g. info, low
+	Match      []string `yaml:"match"`      // label matchers like channels.label_routing
+}
+
+// AuditConfig records who did what to each alert, from the first webhook to
+// its resolution, and keeps the last MaxEvents events of an alert for
+// Retention after its latest event.
+type AuditConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	MaxEvents int    `yaml:"max_events"` // default: 100
+	Retention string `yaml:"retention"`  // default: 720h
+}
+
+// PermissionsConfig restricts who may acknowledge, resolve, unacknowledge,
+// snooze or assign alerts, from buttons and slash commands alike, or click
+// custom action buttons. Actions that no rule applies to stay open to
+// everyone.
+type PermissionsConfig struct {
+	Enabled bool                   `yaml:"enabled"`
+	Rules   []PermissionRuleConfig `yaml:"rules"`
+}
+
+// PermissionRuleConfig applies to the listed actions and severities, or to
+// all of them when the list is empty, and admits the users who match any of
+// Users, Teams, Channels or Groups.
+type PermissionRuleConfig struct {
+	Actions    []string `yaml:"actions"`
+	Severities []string `yaml:"severities"`
+	Users      []string `yaml:"users"`    // Mattermost usernames
+	Teams      []string `yaml:"teams"`    // team IDs
+	Channels   []string `yaml:"channels"` // channel IDs
+	Groups     []string `yaml:"groups"`   // user group names, e.g. "sre"
+}
+
+// DeadLetterConfig keeps alert posts that Mattermost failed to create or
+// update and re-delivers them every RedeliverInterval. Entries that still
+// fail after MaxAttempts are kept for manual replay through the admin API.
+type DeadLetterConfig struct {
+	Enabled           bool   `yaml:"enabled"`
+	RedeliverInterval string `yaml:"redeliver_interval"` // default: 1m
+	MaxAttempts       int    `yaml:"max_attempts"`       // default: 10
+}
+
+// ErrorPostsConfig posts alerts the bridge failed to handle to ChannelID
+// with their payload: alerts it rejects, e.g. for an unknown severity, and
+// alerts Mattermost failed to post. With the dead-letter queue, failed posts
+// are reported once they used up dead_letter.max_attempts. An alert is
+// reported at most once per RepeatInterval.
+type ErrorPostsConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	ChannelID      string `yaml:"channel_id"`      // required
+	RepeatInterval string `yaml:"repeat_interval"` // default: 1h
+}
+
+// CallbackWatchConfig tracks the asynchronous phase of button callbacks. A
+// post still showing "Processing..." Timeout after the click, or left so by
+// a phase that failed, is rewritten with the error and a Retry button. The
+// outcome of each callback is kept for Retention.
+type CallbackWatchConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Timeout   string `yaml:"timeout"`   // default: 45s, at least 5s
+	Retention string `yaml:"retention"` // default: 1h, at least 1m
+}
+
+// SLOConfig tracks how fast the bridge answers Keep webhooks and Mattermost
+// button callbacks against response time objectives. A weekly report is
+// posted to Report.ChannelID when it is set.
+type SLOConfig struct {
+	Enabled  bool               `yaml:"enabled"`
+	Webhook  SLOObjectiveConfig `yaml:"webhook"`  // default: 99% under 2s
+	Callback SLOObjectiveConfig `yaml:"callback"` // default: 99% under 1s
+	Report   SLOReportConfig    `yaml:"report"`
+}
+
+// SLOObjectiveConfig asks for Target of the requests to complete within
+// Threshold.
+type SLOObjectiveConfig struct {
+	Target    float64 `yaml:"target"`    // fraction of good requests, e.g. 0.99
+	Threshold string  `yaml:"threshold"` // e.g. "2s"
+}
+
+type SLOReportConfig struct {
+	ChannelID string `yaml:"channel_id"` // optional; no report when empty
+	Interval  string `yaml:"interval"`   // default: 168h
+}
+
+// DigestConfig posts a summary of alert activity over the last Period to
+// ChannelID whenever the cron expression Schedule comes due. The summary is
+// built from the audit trail, which must be enabled.
+type DigestConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	ChannelID string `yaml:"channel_id"` // required
+	Schedule  string `yaml:"schedule"`   // default: "0 9 * * *"
+	Timezone  string `yaml:"timezone"`   // default: UTC
+	Period    string `yaml:"period"`     // default: 24h
+	Top       int    `yaml:"top"`        // default: 5
+}
+
+// RetentionConfig cleans up resolved alert posts once Delay has passed since
+// they resolved: delete removes the post and its thread, collapse shrinks it
+// to a one-line summary and archive moves it into ArchiveChannelID. Due posts
+// are processed every CheckInterval.
+type RetentionConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	Action           string `yaml:"action"`             // delete | collapse (default) | archive
+	Delay            string `yaml:"delay"`              // default: 24h
+	ArchiveChannelID string `yaml:"archive_channel_id"` // required for archive
+	CheckInterval    string `yaml:"check_interval"`     // default: 1m
+}
+
+// CorrelationConfig keeps a record per alert linking its fingerprint, the
+// Mattermost posts showing it, its Keep incident and its ticket. Incident and
+// ticket come from the incident_id, ticket_id and ticket_url enrichments and
+// are shown in the footer of the alert post.
+type CorrelationConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// BudgetConfig limits how many new alert posts a channel receives per hour.
+// Further alerts are listed in an overflow summary post in the channel and
+// posted one by one as the budget recovers, checked every ReleaseInterval.
+// Updates to existing posts are never held back.
+type BudgetConfig struct {
+	Enabled         bool           `yaml:"enabled"`
+	PostsPerHour    int            `yaml:"posts_per_hour"`   // default: 30
+	Channels        map[string]int `yaml:"channels"`         // channel ID -> posts per hour, overrides PostsPerHour
+	ReleaseInterval string         `yaml:"release_interval"` // default: 1m
+}
+
+// DirectMessagesConfig also sends new firing alerts to on-call users as
+// direct messages. Users come from every rule whose matchers all hold and
+// from the alert label named by UserLabel. Names are Mattermost usernames or
+// Keep usernames listed in users.mapping.
+type DirectMessagesConfig struct {
+	Enabled   bool                `yaml:"enabled"`
+	UserLabel string              `yaml:"user_label"` // e.g. "oncall"; optional
+	Rules     []DirectMessageRule `yaml:"rules"`
+}
+
+// DirectMessageRule matches like a channels.label_routing rule.
+type DirectMessageRule struct {
+	Match []string `yaml:"match"`
+	Users []string `yaml:"users"`
+}
+
+// CopyCommandsConfig adds a Commands button to alert posts. Clicking it shows
+// the clicking user an ephemeral message with the listed commands rendered
+// from the alert, ready to copy. Commands whose template references a label
+// the alert does not have are left out.
+type CopyCommandsConfig struct {
+	Enabled  bool                `yaml:"enabled"`
+	Commands []CopyCommandConfig `yaml:"commands"` // default: Keep API, kubectl and silence commands
+}
+
+// CopyCommandConfig is a Go text/template with .Fingerprint, .Name,
+// .Severity, .Status, .Labels, .KeepURL and .KeepUIURL; the quote function
+// shell-quotes a value when needed.
+type CopyCommandConfig struct {
+	Name     string `yaml:"name"`
+	Template string `yaml:"template"`
+}
+
+// EscalationConfig escalates firing alerts that nobody acknowledged in time.
+// Rules for the same severity form successive steps ordered by After; a
+// background job checks them every CheckInterval.
+type EscalationConfig struct {
+	Enabled       bool                   `yaml:"enabled"`
+	CheckInterval string                 `yaml:"check_interval"` // default: 1m
+	Rules         []EscalationRuleConfig `yaml:"rules"`
+}
+
+type EscalationRuleConfig struct {
+	Severity  string `yaml:"severity"`
+	After     string `yaml:"after"`      // time since the alert started firing, at least 1m
+	Mention   string `yaml:"mention"`    // e.g. "@sre-oncall", posted in the alert thread
+	ChannelID string `yaml:"channel_id"` // optional channel that receives a copy of the alert
+}
+
+// ReactionsConfig periodically summarizes emoji reactions on alert posts and
+// writes the summary to Keep as enrichments. Emojis restricts the summary to
+// the listed emoji names; an empty list counts every reaction.
+type ReactionsConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Interval string   `yaml:"interval"` // default: 5m
+	Emojis   []string `yaml:"emojis"`
+}
+
+// ReactionActionsConfig applies alert actions when users react to alert
+// posts, e.g. eyes: acknowledge. Reactions arrive over the Mattermost
+// WebSocket API.
+type ReactionActionsConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	Emojis  map[string]string `yaml:"emojis"` // emoji name -> acknowledge | resolve | unacknowledge | snooze
+}
+
+// AssigneeRetryConfig tunes how long acknowledge webhooks wait for Keep to
+// return the assignee enrichment. Delays start at InitialDelay and grow by
+// Multiplier up to MaxDelay; Jitter randomizes each delay by up to that
+// fraction. Retrying stops once Deadline would be exceeded.
+type AssigneeRetryConfig struct {
+	Attempts     int     `yaml:"attempts"`      // default: 4, including the first request
+	InitialDelay string  `yaml:"initial_delay"` // default: 100ms
+	Multiplier   float64 `yaml:"multiplier"`    // default: 2
+	MaxDelay     string  `yaml:"max_delay"`     // default: 2s
+	Jitter       float64 `yaml:"jitter"`        // 0-1, default: 0
+	Deadline     string  `yaml:"deadline"`      // default: 2s
+}
+
+// APIRetryConfig retries Mattermost and Keep API requests that fail with a
+// network error, a 429 or a 5xx response, waiting longer before each retry.
+type APIRetryConfig struct {
+	Attempts     int     `yaml:"attempts"`      // default: 3, including the first request; 1 disables retries
+	InitialDelay string  `yaml:"initial_delay"` // default: 200ms
+	Multiplier   float64 `yaml:"multiplier"`    // default: 2
+	MaxDelay     string  `yaml:"max_delay"`     // default: 5s
+	Jitter       float64 `yaml:"jitter"`        // 0-1, default: 0
+}
+
+// RateLimitConfig caps the rate of Mattermost API requests. While enabled the
+// bridge also pauses requests whenever Mattermost reports through its
+// X-Ratelimit headers that no requests are left.
+type RateLimitConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"` // default: 10
+	Burst             int     `yaml:"burst"`               // default: 20
+}
+
+// KeepBreakerConfig stops calling Keep after Failures failed requests in a
+// row, for OpenFor, instead of letting every request wait for its timeout.
+// Alert actions clicked meanwhile are queued, up to QueueSize, and applied
+// once Keep answers again.
+type KeepBreakerConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Failures  int    `yaml:"failures"`   // default: 5
+	OpenFor   string `yaml:"open_for"`   // default: 30s
+	QueueSize int    `yaml:"queue_size"` // default: 100
+}
+
+// OfflineActionsConfig keeps acknowledge, resolve and unacknowledge actions
+// that could not reach Keep in Valkey and replays them every ReplayInterval
+// until Keep takes them. Actions older than MaxAge are dropped.
+type OfflineActionsConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	ReplayInterval string `yaml:"replay_interval"` // default: 30s
+	MaxAge         string `yaml:"max_age"`         // default: 24h
+}
+
+// UpdateCoalesceConfig merges repeated updates of the same Mattermost post.
+// The first update is sent at once; later ones within Window are held and
+// only the last of them is sent when the window closes.
+type UpdateCoalesceConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Window  string `yaml:"window"` // default: 1s, at most 1m
+}
+
+// SnoozeConfig adds a Snooze button to firing alerts. A snoozed post ignores
+// re-fire updates for Duration and is restored to firing by a background job
+// that runs every CheckInterval.
+type SnoozeConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Duration      string `yaml:"duration"`       // default: 1h, at most 168h
+	CheckInterval string `yaml:"check_interval"` // default: 1m
+}
+
+// TimedAckConfig adds an Acknowledge for menu offering Durations to firing
+// alerts. An alert acknowledged from it that is still unresolved when the
+// duration ends is unacknowledged in Keep and fires again; a background job
+// checks for that every CheckInterval.
+type TimedAckConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Durations     []string `yaml:"durations"`      // default: 30m, 1h, 2h, 4h; each at most 168h
+	CheckInterval string   `yaml:"check_interval"` // default: 1m
+}
+
+// FlappingConfig pauses the updates of alerts that switch between firing and
+// resolved Threshold times within Window. Their post is marked as flapping
+// and follows the alert again once a check, run every CheckInterval, finds
+// fewer switches in the window.
+type FlappingConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Window        string `yaml:"window"`         // default: 30m
+	Threshold     int    `yaml:"threshold"`      // default: 6
+	CheckInterval string `yaml:"check_interval"` // default: 1m
+}
+
+// AssignConfig adds an Assign menu to firing alerts. Picking a user sets the
+// alert's assignee in Keep without acknowledging it. The menu offers "Assign
+// to me" and the users in users.mapping, or every Mattermost user when the
+// mapping is empty.
+type AssignConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ResolveDialogConfig makes the Resolve button open a dialog that asks for a
+// resolution note and a root cause category. Both are sent to Keep as the
+// resolution_note and resolution_category enrichments and shown on the
+// resolved post.
+type ResolveDialogConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	RequireNote bool     `yaml:"require_note"`
+	Categories  []string `yaml:"categories"` // optional; no category field when empty
+}
+
+// AlertGroupingConfig collapses related alerts into one Mattermost thread.
+// GroupBy lists label names tried in order; the first one present on an
+// alert becomes its grouping key.
+type AlertGroupingConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	GroupBy []string `yaml:"group_by"`
+}
+
+// BadgeConfig configures the periodic active-critical count indicator.
+type BadgeConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Target    string `yaml:"target"`     // status | channel_purpose (default: status)
+	ChannelID string `yaml:"channel_id"` // required for channel_purpose
+	Interval  string `yaml:"interval"`   // default: 1m
 }
 
 type FilePollingConfig struct {
-	Enabled     *bool  `yaml:"enabled"`
-	Interval    string `yaml:"interval"`
-	AlertsLimit *int   `yaml:"alerts_limit"`
-	Timeout     string `yaml:"timeout"`
+	Enabled       *bool  `yaml:"enabled"`
+	Interval      string `yaml:"interval"`
+	AlertsLimit   *int   `yaml:"alerts_limit"`
+	Timeout       string `yaml:"timeout"`
+	MaxResponseMB *int   `yaml:"max_response_mb"`
+
+	BackoffMultiplier *float64 `yaml:"backoff_multiplier"`
+	BackoffMax        string   `yaml:"backoff_max"`
+
+	// PageSize > 0 queries Keep for the tracked alerts only, this many per
+	// request, instead of fetching alerts_limit alerts.
+	PageSize int `yaml:"page_size"`
+
+	// DeletedPosts is what happens to tracked alerts whose post was deleted
+	// in Mattermost: recreate, untrack or resolve. Posts are not checked
+	// when empty.
+	DeletedPosts string `yaml:"deleted_posts"`
+	// RecreateDeletedPosts is the former spelling of deleted_posts: recreate.
+	RecreateDeletedPosts bool `yaml:"recreate_deleted_posts"`
 }
 
 type FileSetupConfig struct {
@@ -34,33 +657,111 @@ type FileSetupConfig struct {
 }
 
 type ChannelsConfig struct {
-	Routing          []RoutingRule `yaml:"routing"`
-	DefaultChannelID string        `yaml:"default_channel_id"`
+	Routing          []RoutingRule      `yaml:"routing"`
+	LabelRouting     LabelRoutingConfig `yaml:"label_routing"`
+	TeamRouting      TeamRoutingConfig  `yaml:"team_routing"`
+	DefaultChannelID string             `yaml:"default_channel_id"`
+}
+
+// TeamRoutingConfig routes alerts no label or severity rule matched by the
+// value of one label, e.g. their team or namespace, before the default
+// channel is used.
+type TeamRoutingConfig struct {
+	Label    string            `yaml:"label"`    // default: team
+	Channels map[string]string `yaml:"channels"` // label value -> channel ID, or "drop"
+}
+
+// LabelRoutingConfig routes alerts by label matchers before severity routing
+// is consulted. In first_match mode the first matching rule wins; in
+// all_match mode every matching rule's channel gets the alert, the first one
+// holding the tracked post and the rest a copy.
+type LabelRoutingConfig struct {
+	Mode  string           `yaml:"mode"` // first_match (default) | all_match
+	Rules []LabelRouteRule `yaml:"rules"`
+}
+
+// LabelRouteRule matches when every matcher in Match holds, e.g.
+// `team=payments` or `namespace=~"prod-.*"`.
+type LabelRouteRule struct {
+	Name      string   `yaml:"name"` // shown in logs and metrics; default: label_routing.rules[i]
+	Match     []string `yaml:"match"`
+	ChannelID string   `yaml:"channel_id"`
+	Profile   string   `yaml:"profile"` // message profile of the channel
+	Server    string   `yaml:"server"`  // mattermost_servers entry the channel is on; default: MATTERMOST_URL
 }
 
 type RoutingRule struct {
 	Severity  string `yaml:"severity"`
 	ChannelID string `yaml:"channel_id"`
+	Profile   string `yaml:"profile"` // message profile of the channel
+	Server    string `yaml:"server"`  // mattermost_servers entry the channel is on; default: MATTERMOST_URL
 }
 
 type MessageConfig struct {
-	Colors map[string]string `yaml:"colors"`
-	Emoji  map[string]string `yaml:"emoji"`
-	Footer FooterConfig      `yaml:"footer"`
-	Fields FieldsConfig      `yaml:"fields"`
+	Colors   map[string]string `yaml:"colors"`
+	Emoji    map[string]string `yaml:"emoji"`
+	Footer   FooterConfig      `yaml:"footer"`
+	Fields   FieldsConfig      `yaml:"fields"`
+	Timeline TimelineConfig    `yaml:"timeline"`
+	Sanitize SanitizeConfig    `yaml:"sanitize"`
+	// Style renders alert posts of a severity full (default) or compact.
+	Style map[string]string `yaml:"style"`
+	// Bot overrides the name and avatar the bot posts with.
+	Bot BotConfig `yaml:"bot"`
+	// Profiles override the look of alert posts per channel.
+	Profiles []MessageProfileConfig `yaml:"profiles"`
+}
+
+// TimelineConfig also appends every status transition of an alert as a
+// timestamped thread reply, so the thread under the post keeps the full
+// history that post edits overwrite.
+type TimelineConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// SanitizeConfig escapes Markdown, @mentions and emoji shortcodes in the
+// description, label and enrichment values of alert posts, so a value such as
+// "@channel" or "a|b" is shown as written.
+type SanitizeConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	RawMarkdown []string `yaml:"raw_markdown"` // "description", label or enrichment keys shown as Markdown
 }
 
 type FieldsConfig struct {
 	ShowSeverity     *bool  `yaml:"show_severity"`
 	ShowDescription  *bool  `yaml:"show_description"`
+	ShowFingerprint  bool   `yaml:"show_fingerprint"`
 	SeverityPosition string `yaml:"severity_position"`
+	Compact          bool   `yaml:"compact"` // every severity in the compact style
 }
 
+// Message styles of message.style.
+const (
+	MessageStyleFull    = "full"
+	MessageStyleCompact = "compact"
+)
+
 type FooterConfig struct {
 	Text    string `yaml:"text"`
 	IconURL string `yaml:"icon_url"`
 }
 
+// BotConfig shows posts under another name and avatar than the bot
+// account's, e.g. "Alerts" with a red avatar for critical alerts. Severity
+// overrides are merged over the default. Mattermost only shows them with
+// EnablePostUsernameOverride and EnablePostIconOverride.
+type BotConfig struct {
+	Username string                       `yaml:"username"`
+	IconURL  string                       `yaml:"icon_url"`
+	Severity map[string]BotIdentityConfig `yaml:"severity"`
+}
+
+// BotIdentityConfig is the name and avatar of the posts of one severity.
+type BotIdentityConfig struct {
+	Username string `yaml:"username"`
+	IconURL  string `yaml:"icon_url"`
+}
+
 type LabelsConfig struct {
 	Display  []string            `yaml:"display"`
 	Rename   map[string]string   `yaml:"rename"`
@@ -81,7 +782,17 @@ type LabelGroupRule struct {
 }
 
 type UsersConfig struct {
-	Mapping map[string]string `yaml:"mapping"`
+	Mapping     map[string]string     `yaml:"mapping"`
+	AutoMapping UserAutoMappingConfig `yaml:"auto_mapping"`
+}
+
+// UserAutoMappingConfig matches Keep users to Mattermost users by email every
+// RefreshInterval. Lookups are cached in Valkey for CacheTTL; Mapping still
+// applies to users without a match.
+type UserAutoMappingConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	RefreshInterval string `yaml:"refresh_interval"` // default: 1h
+	CacheTTL        string `yaml:"cache_ttl"`        // default: 24h
 }
 
 func LoadFromFile(path string) (*FileConfig, error) {
@@ -89,7 +800,7 @@ func LoadFromFile(path string) (*FileConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return defaultFileConfig(), nil
+			return DefaultFileConfig(), nil
 		}
 		return nil, err
 	}
@@ -108,7 +819,39 @@ func LoadFromFile(path string) (*FileConfig, error) {
 	return &cfg, nil
 }
 
-func defaultFileConfig() *FileConfig {
+// LoadFromFileAndEnv loads the config file at path like LoadFromFile, with
+// the keys named by the KMB_ variables of environ set over the file's
+// values. Without a file, the variables are set over the defaults, and
+// unlike LoadFromFile the result is validated, as the variables may set
+// invalid values.
+func LoadFromFileAndEnv(path string, environ []string) (*FileConfig, error) {
+	var cfg FileConfig
+	data, err := os.ReadFile(filepath.Clean(path))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnvOverrides(environ); err != nil {
+		return nil, err
+	}
+
+	cfg.applyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// DefaultFileConfig returns the configuration used when no config file exists.
+func DefaultFileConfig() *FileConfig {
 	cfg := &FileConfig{}
 	cfg.applyDefaults()
 	return cfg
@@ -120,192 +863,1508 @@ func (c *FileConfig) Validate() error {
 			return fmt.Errorf("invalid label exclude pattern %q: %w", pattern, err)
 		}
 	}
-	return nil
-}
-
-func (c *FileConfig) applyDefaults() {
-	if c.Message.Colors == nil {
-		c.Message.Colors = map[string]string{
-			"critical":     "#CC0000",
-			"high":         "#FF6600",
-			"warning":      "#EDA200",
-			"info":         "#0066FF",
-			"low":          "#808080",
-			"acknowledged": "#FFA500",
-			"resolved":     "#00CC00",
-			"suppressed":   "#9370DB",
-			"pending":      "#87CEEB",
-			"maintenance":  "#708090",
-		}
+	switch c.Channels.LabelRouting.Mode {
+	case "", RoutingModeFirstMatch, RoutingModeAllMatch:
+	default:
+		return fmt.Errorf("invalid channels.label_routing.mode %q: must be %q or %q", c.Channels.LabelRouting.Mode, RoutingModeFirstMatch, RoutingModeAllMatch)
 	}
-	if c.Message.Emoji == nil {
-		c.Message.Emoji = map[string]string{
-			"critical": "🔴",
-			"high":     "🟠",
-			"warning":  "🟡",
-			"info":     "🔵",
-			"low":      "⚪",
+	if _, err := compileLabelRoutes(c.Channels.LabelRouting.Rules); err != nil {
+		return err
+	}
+	for team, channelID := range c.Channels.TeamRouting.Channels {
+		if channelID == "" {
+			return fmt.Errorf("channels.team_routing.channels[%q] needs a channel ID or %q", team, DropChannel)
 		}
 	}
-	if c.Message.Footer.Text == "" {
-		c.Message.Footer.Text = "Keep AIOps"
+	if err := validateMessageStyle("message.style", c.Message.Style); err != nil {
+		return err
 	}
-	if c.Message.Footer.IconURL == "" {
-		c.Message.Footer.IconURL = "https://avatars.githubusercontent.com/u/109032290?v=4"
+	if err := c.Message.Bot.validate(); err != nil {
+		return err
 	}
-	if c.Message.Fields.SeverityPosition == "" {
-		c.Message.Fields.SeverityPosition = post.SeverityPositionFirst
+	if err := c.validateSeverityMap(); err != nil {
+		return err
 	}
-	if c.Labels.Display == nil {
-		c.Labels.Display = []string{
-			"alertgroup",
-			"container",
-			"node",
-			"namespace",
-			"pod",
+	if c.AcceptedSeverities != nil && len(c.AcceptedSeverities) == 0 {
+		return fmt.Errorf("accepted_severities must list at least one severity")
+	}
+	for _, severity := range c.AcceptedSeverities {
+		if _, err := alert.NewSeverity(severity); err != nil {
+			return fmt.Errorf("accepted_severities: %w", err)
 		}
 	}
-	if c.Labels.Exclude == nil {
-		c.Labels.Exclude = []string{
-			"__name__",
-			"prometheus",
-			"alertname",
-			"job",
-			"instance",
+	if err := c.validateMessageProfiles(); err != nil {
+		return err
+	}
+	if c.AlertGrouping.Enabled {
+		if len(c.AlertGrouping.GroupBy) == 0 {
+			return fmt.Errorf("alert_grouping.group_by must list at least one label when alert grouping is enabled")
+		}
+		for _, label := range c.AlertGrouping.GroupBy {
+			if label == "" {
+				return fmt.Errorf("alert_grouping.group_by must not contain empty labels")
+			}
 		}
 	}
-	if c.Labels.Rename == nil {
-		c.Labels.Rename = map[string]string{
-			"alertgroup": "Alert Group",
+	if c.Snooze.Enabled {
+		d, err := time.ParseDuration(c.Snooze.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid snooze.duration %q: %w", c.Snooze.Duration, err)
+		}
+		if d < time.Minute || d > 168*time.Hour {
+			return fmt.Errorf("snooze.duration must be between 1m and 168h, got %s", d)
+		}
+		d, err = time.ParseDuration(c.Snooze.CheckInterval)
+		if err != nil {
+			return fmt.Errorf("invalid snooze.check_interval %q: %w", c.Snooze.CheckInterval, err)
+		}
+		if d < 10*time.Second {
+			return fmt.Errorf("snooze.check_interval must be at least 10s, got %s", d)
 		}
 	}
-	// Default grouping config
-	if c.Labels.Grouping.Threshold == 0 {
-		c.Labels.Grouping.Threshold = 2
+	if c.TimedAck.Enabled {
+		if err := c.TimedAck.validate(); err != nil {
+			return err
+		}
 	}
-	if c.Labels.Grouping.Groups == nil && c.Labels.Grouping.Enabled {
-		c.Labels.Grouping.Groups = []LabelGroupRule{
-			{
-				Prefixes:  []string{"topology_"},
-				GroupName: "Topology",
-				Priority:  100,
-			},
-			{
-				Prefixes:  []string{"kubernetes_io_", "beta_kubernetes_io_", "failure_domain_beta_kubernetes_io_"},
-				GroupName: "Kubernetes",
-				Priority:  90,
-			},
-			{
-				Prefixes:  []string{"extensions_talos_dev_"},
-				GroupName: "Talos",
-				Priority:  80,
-			},
+	if c.Flapping.Enabled {
+		if err := c.Flapping.validate(); err != nil {
+			return err
 		}
 	}
-	if c.Users.Mapping == nil {
-		c.Users.Mapping = make(map[string]string)
+	if c.Reactions.Enabled {
+		d, err := time.ParseDuration(c.Reactions.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid reactions.interval %q: %w", c.Reactions.Interval, err)
+		}
+		if d < 30*time.Second {
+			return fmt.Errorf("reactions.interval must be at least 30s, got %s", d)
+		}
 	}
-}
-
-func (c *FileConfig) ChannelIDForSeverity(severity string) string {
-	for _, rule := range c.Channels.Routing {
-		if rule.Severity == severity {
-			return rule.ChannelID
+	if c.ReactionAction.Enabled {
+		if err := c.validateReactionActions(); err != nil {
+			return err
 		}
 	}
-	return c.Channels.DefaultChannelID
-}
-
-func (c *FileConfig) ColorForSeverity(severity string) string {
-	if color, ok := c.Message.Colors[severity]; ok {
-		return color
+	if c.Polling.PageSize < 0 {
+		return fmt.Errorf("polling.page_size must not be negative, got %d", c.Polling.PageSize)
 	}
-	return "#808080"
-}
-
-func (c *FileConfig) EmojiForSeverity(severity string) string {
-	if emoji, ok := c.Message.Emoji[severity]; ok {
-		return emoji
+	switch c.Polling.DeletedPosts {
+	case "", port.DeletedPostRecreate:
+	case port.DeletedPostUntrack, port.DeletedPostResolve:
+		if c.Polling.RecreateDeletedPosts {
+			return fmt.Errorf("polling.recreate_deleted_posts contradicts polling.deleted_posts %q; remove it", c.Polling.DeletedPosts)
+		}
+	default:
+		return fmt.Errorf("invalid polling.deleted_posts %q: must be %q, %q or %q", c.Polling.DeletedPosts, port.DeletedPostRecreate, port.DeletedPostUntrack, port.DeletedPostResolve)
+	}
+	if c.Users.AutoMapping.Enabled {
+		d, err := time.ParseDuration(c.Users.AutoMapping.RefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid users.auto_mapping.refresh_interval %q: %w", c.Users.AutoMapping.RefreshInterval, err)
+		}
+		if d < time.Minute {
+			return fmt.Errorf("users.auto_mapping.refresh_interval must be at least 1m, got %s", d)
+		}
+		ttl, err := time.ParseDuration(c.Users.AutoMapping.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid users.auto_mapping.cache_ttl %q: %w", c.Users.AutoMapping.CacheTTL, err)
+		}
+		if ttl < d {
+			return fmt.Errorf("users.auto_mapping.cache_ttl must be at least refresh_interval (%s), got %s", d, ttl)
+		}
+	}
+	if c.Escalation.Enabled {
+		if err := c.Escalation.validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.AssigneeRetry.validate(); err != nil {
+		return err
+	}
+	if err := c.APIRetry.validate(); err != nil {
+		return err
+	}
+	if c.RateLimit.Enabled {
+		if err := c.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+	if c.KeepBreaker.Enabled {
+		if err := c.KeepBreaker.validate(); err != nil {
+			return err
+		}
+	}
+	if c.OfflineActions.Enabled {
+		if err := c.OfflineActions.validate(); err != nil {
+			return err
+		}
+	}
+	if c.UpdateCoalesce.Enabled {
+		if err := c.UpdateCoalesce.validate(); err != nil {
+			return err
+		}
+	}
+	if c.DirectMessages.Enabled {
+		if c.DirectMessages.UserLabel == "" && len(c.DirectMessages.Rules) == 0 {
+			return fmt.Errorf("direct_messages needs user_label or at least one rule when enabled")
+		}
+		if _, err := compileOnCallRules(c.DirectMessages.Rules); err != nil {
+			return err
+		}
+	}
+	if c.Budget.Enabled {
+		if c.Budget.PostsPerHour < 1 {
+			return fmt.Errorf("budget.posts_per_hour must be at least 1, got %d", c.Budget.PostsPerHour)
+		}
+		for channelID, limit := range c.Budget.Channels {
+			if limit < 1 {
+				return fmt.Errorf("budget.channels.%s must be at least 1, got %d", channelID, limit)
+			}
+		}
+		d, err := time.ParseDuration(c.Budget.ReleaseInterval)
+		if err != nil {
+			return fmt.Errorf("invalid budget.release_interval %q: %w", c.Budget.ReleaseInterval, err)
+		}
+		if d < 10*time.Second {
+			return fmt.Errorf("budget.release_interval must be at least 10s, got %s", d)
+		}
+	}
+	if c.Retention.Enabled {
+		if err := c.Retention.validate(); err != nil {
+			return err
+		}
+	}
+	if c.SLO.Enabled {
+		if err := c.SLO.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Digest.Enabled {
+		if err := c.validateDigest(); err != nil {
+			return err
+		}
+	}
+	if c.DeadLetter.Enabled {
+		if err := c.DeadLetter.validate(); err != nil {
+			return err
+		}
+	}
+	if c.ErrorPosts.Enabled {
+		if err := c.ErrorPosts.validate(); err != nil {
+			return err
+		}
+	}
+	if c.CallbackWatch.Enabled {
+		if err := c.CallbackWatch.validate(); err != nil {
+			return err
+		}
+	}
+	if c.IngestQueue.Enabled {
+		if err := c.IngestQueue.validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.IngestStream.validate(); err != nil {
+		return err
+	}
+	if c.Locking.Enabled {
+		if err := c.Locking.validate(); err != nil {
+			return err
+		}
+	}
+	if c.PostTTL.Enabled {
+		if err := c.PostTTL.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Mentions.Enabled {
+		if _, err := NewMentionPolicy(c); err != nil {
+			return err
+		}
+	}
+	if c.QuietHours.Enabled {
+		if _, err := NewQuietHoursPolicy(c); err != nil {
+			return err
+		}
+		d, err := time.ParseDuration(c.QuietHours.CheckInterval)
+		if err != nil {
+			return fmt.Errorf("invalid quiet_hours.check_interval %q: %w", c.QuietHours.CheckInterval, err)
+		}
+		if d < 10*time.Second {
+			return fmt.Errorf("quiet_hours.check_interval must be at least 10s, got %s", d)
+		}
+	}
+	if c.Links.Enabled {
+		if _, err := NewLinkPolicy(c); err != nil {
+			return err
+		}
+	}
+	if c.CustomActions.Enabled {
+		if _, err := NewCustomActionPolicy(c); err != nil {
+			return err
+		}
+	}
+	if c.WorkflowMenu.Enabled {
+		if err := c.WorkflowMenu.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Enrichments.Enabled {
+		if err := c.Enrichments.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Annotations.Enabled {
+		if err := c.Annotations.validate(); err != nil {
+			return err
+		}
+	}
+	if c.SourceLinks.Enabled {
+		for i, source := range c.SourceLinks.Sources {
+			if strings.TrimSpace(source) == "" {
+				return fmt.Errorf("source_links.sources[%d] must not be empty", i)
+			}
+		}
+		for i, key := range c.SourceLinks.Annotations {
+			if key == "" {
+				return fmt.Errorf("source_links.annotations[%d] must not be empty", i)
+			}
+		}
+	}
+	if err := c.validateTenants(); err != nil {
+		return err
+	}
+	if err := c.validateMattermostServers(); err != nil {
+		return err
+	}
+	if err := c.validateChannelNames(); err != nil {
+		return err
+	}
+	if err := c.validateStatusBoard(); err != nil {
+		return err
+	}
+	if err := c.validateThreadNotes(); err != nil {
+		return err
+	}
+	if c.Permissions.Enabled {
+		if err := c.Permissions.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Audit.Enabled {
+		if err := c.Audit.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Maintenance.Enabled {
+		if len(c.Maintenance.Windows) == 0 {
+			return fmt.Errorf("maintenance.windows must list at least one window when maintenance is enabled")
+		}
+		if _, err := compileMaintenanceWindows(c.Maintenance.Windows); err != nil {
+			return err
+		}
+	}
+	if c.DropRules.Enabled {
+		if len(c.DropRules.Rules) == 0 {
+			return fmt.Errorf("drop_rules.rules must list at least one rule when drop rules are enabled")
+		}
+		if _, err := compileDropRules(c.DropRules.Rules); err != nil {
+			return err
+		}
+	}
+	if c.ResolveDialog.Enabled {
+		for i, category := range c.ResolveDialog.Categories {
+			if strings.TrimSpace(category) == "" {
+				return fmt.Errorf("resolve_dialog.categories[%d] must not be empty", i)
+			}
+			if slices.Contains(c.ResolveDialog.Categories[:i], category) {
+				return fmt.Errorf("resolve_dialog.categories lists %q twice", category)
+			}
+		}
+	}
+	if c.CopyCommands.Enabled {
+		for i, cmd := range c.CopyCommands.Commands {
+			if cmd.Name == "" || cmd.Template == "" {
+				return fmt.Errorf("copy_commands.commands[%d] needs both name and template", i)
+			}
+		}
+	}
+	if c.Badge.Enabled {
+		switch c.Badge.Target {
+		case port.BadgeTargetStatus:
+		case port.BadgeTargetChannelPurpose:
+			if c.Badge.ChannelID == "" {
+				return fmt.Errorf("badge.channel_id is required when badge.target is %q", port.BadgeTargetChannelPurpose)
+			}
+		default:
+			return fmt.Errorf("invalid badge.target %q: must be %q or %q", c.Badge.Target, port.BadgeTargetStatus, port.BadgeTargetChannelPurpose)
+		}
+		d, err := time.ParseDuration(c.Badge.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid badge.interval %q: %w", c.Badge.Interval, err)
+		}
+		if d < 10*time.Second {
+			return fmt.Errorf("badge.interval must be at least 10s, got %s", d)
+		}
+	}
+	return nil
+}
+
+func (c *FileConfig) applyDefaults() {
+	if c.Message.Colors == nil {
+		c.Message.Colors = map[string]string{
+			"critical":     "#CC0000",
+			"high":         "#FF6600",
+			"warning":      "#EDA200",
+			"info":         "#0066FF",
+			"low":          "#808080",
+			"debug":        "#B0B0B0",
+			"acknowledged": "#FFA500",
+			"resolved":     "#00CC00",
+			"suppressed":   "#9370DB",
+			"snoozed":      "#B0A0D0",
+			"flapping":     "#D35400",
+			"pending":      "#87CEEB",
+			"maintenance":  "#708090",
+			"dismissed":    "#A9A9A9",
+			"merged":       "#6A5ACD",
+			"expired":      "#C0C0C0",
+		}
+	}
+	if c.Message.Emoji == nil {
+		c.Message.Emoji = map[string]string{
+			"critical": "🔴",
+			"high":     "🟠",
+			"warning":  "🟡",
+			"info":     "🔵",
+			"low":      "⚪",
+			"debug":    "🐞",
+		}
+	}
+	if c.Annotations.Exclude == nil {
+		c.Annotations.Exclude = []string{"description"}
+	}
+	if c.AcceptedSeverities == nil {
+		c.AcceptedSeverities = []string{alert.SeverityCritical, alert.SeverityHigh, alert.SeverityWarning, alert.SeverityInfo, alert.SeverityLow}
+	}
+	if c.Message.Footer.Text == "" {
+		c.Message.Footer.Text = "Keep AIOps"
+	}
+	if c.Message.Footer.IconURL == "" {
+		c.Message.Footer.IconURL = "https://avatars.githubusercontent.com/u/109032290?v=4"
+	}
+	if c.Message.Fields.SeverityPosition == "" {
+		c.Message.Fields.SeverityPosition = post.SeverityPositionFirst
+	}
+	if c.Labels.Display == nil {
+		c.Labels.Display = []string{
+			"alertgroup",
+			"container",
+			"node",
+			"namespace",
+			"pod",
+		}
+	}
+	if c.Labels.Exclude == nil {
+		c.Labels.Exclude = []string{
+			"__name__",
+			"prometheus",
+			"alertname",
+			"job",
+			"instance",
+		}
+	}
+	if c.Labels.Rename == nil {
+		c.Labels.Rename = map[string]string{
+			"alertgroup": "Alert Group",
+		}
+	}
+	// Default grouping config
+	if c.Labels.Grouping.Threshold == 0 {
+		c.Labels.Grouping.Threshold = 2
+	}
+	if c.Labels.Grouping.Groups == nil && c.Labels.Grouping.Enabled {
+		c.Labels.Grouping.Groups = []LabelGroupRule{
+			{
+				Prefixes:  []string{"topology_"},
+				GroupName: "Topology",
+				Priority:  100,
+			},
+			{
+				Prefixes:  []string{"kubernetes_io_", "beta_kubernetes_io_", "failure_domain_beta_kubernetes_io_"},
+				GroupName: "Kubernetes",
+				Priority:  90,
+			},
+			{
+				Prefixes:  []string{"extensions_talos_dev_"},
+				GroupName: "Talos",
+				Priority:  80,
+			},
+		}
+	}
+	if c.Users.Mapping == nil {
+		c.Users.Mapping = make(map[string]string)
+	}
+	if c.Channels.TeamRouting.Label == "" {
+		c.Channels.TeamRouting.Label = "team"
+	}
+	if c.Channels.LabelRouting.Mode == "" {
+		c.Channels.LabelRouting.Mode = RoutingModeFirstMatch
+	}
+	if c.Badge.Target == "" {
+		c.Badge.Target = port.BadgeTargetStatus
+	}
+	if c.Badge.Interval == "" {
+		c.Badge.Interval = "1m"
+	}
+	if c.Snooze.Duration == "" {
+		c.Snooze.Duration = "1h"
+	}
+	if c.Snooze.CheckInterval == "" {
+		c.Snooze.CheckInterval = "1m"
+	}
+	if len(c.SourceLinks.Annotations) == 0 {
+		c.SourceLinks.Annotations = []string{"generatorURL", "generator_url"}
+	}
+	if len(c.TimedAck.Durations) == 0 {
+		c.TimedAck.Durations = []string{"30m", "1h", "2h", "4h"}
+	}
+	if c.TimedAck.CheckInterval == "" {
+		c.TimedAck.CheckInterval = "1m"
+	}
+	if c.Flapping.Window == "" {
+		c.Flapping.Window = "30m"
+	}
+	if c.Flapping.Threshold == 0 {
+		c.Flapping.Threshold = 6
+	}
+	if c.Flapping.CheckInterval == "" {
+		c.Flapping.CheckInterval = "1m"
+	}
+	if c.Escalation.CheckInterval == "" {
+		c.Escalation.CheckInterval = "1m"
+	}
+	if c.Reactions.Interval == "" {
+		c.Reactions.Interval = "5m"
+	}
+	if c.Users.AutoMapping.RefreshInterval == "" {
+		c.Users.AutoMapping.RefreshInterval = "1h"
+	}
+	if c.Users.AutoMapping.CacheTTL == "" {
+		c.Users.AutoMapping.CacheTTL = "24h"
+	}
+	if c.ChannelNames.CacheTTL == "" {
+		c.ChannelNames.CacheTTL = "24h"
+	}
+	if c.StatusBoard.UpdateInterval == "" {
+		c.StatusBoard.UpdateInterval = "10s"
+	}
+	if c.StatusBoard.ResyncInterval == "" {
+		c.StatusBoard.ResyncInterval = "5m"
+	}
+	if c.ThreadNotes.EnrichmentKey == "" {
+		c.ThreadNotes.EnrichmentKey = "note"
+	}
+	if c.ThreadNotes.MaxLength == 0 {
+		c.ThreadNotes.MaxLength = 1000
+	}
+	if c.Budget.PostsPerHour == 0 {
+		c.Budget.PostsPerHour = 30
+	}
+	if c.Budget.ReleaseInterval == "" {
+		c.Budget.ReleaseInterval = "1m"
+	}
+	if c.Retention.Action == "" {
+		c.Retention.Action = retention.ActionCollapse
+	}
+	if c.Retention.Delay == "" {
+		c.Retention.Delay = "24h"
+	}
+	if c.Retention.CheckInterval == "" {
+		c.Retention.CheckInterval = "1m"
+	}
+	if c.SLO.Webhook.Target == 0 {
+		c.SLO.Webhook.Target = 0.99
+	}
+	if c.SLO.Webhook.Threshold == "" {
+		c.SLO.Webhook.Threshold = "2s"
+	}
+	if c.SLO.Callback.Target == 0 {
+		c.SLO.Callback.Target = 0.99
+	}
+	if c.SLO.Callback.Threshold == "" {
+		c.SLO.Callback.Threshold = "1s"
+	}
+	if c.SLO.Report.Interval == "" {
+		c.SLO.Report.Interval = "168h"
+	}
+	if c.Digest.Schedule == "" {
+		c.Digest.Schedule = "0 9 * * *"
+	}
+	if c.Digest.Period == "" {
+		c.Digest.Period = "24h"
+	}
+	if c.Digest.Top == 0 {
+		c.Digest.Top = 5
+	}
+	if c.DeadLetter.RedeliverInterval == "" {
+		c.DeadLetter.RedeliverInterval = "1m"
+	}
+	if c.DeadLetter.MaxAttempts == 0 {
+		c.DeadLetter.MaxAttempts = 10
+	}
+	if c.ErrorPosts.RepeatInterval == "" {
+		c.ErrorPosts.RepeatInterval = "1h"
+	}
+	if c.CallbackWatch.Timeout == "" {
+		c.CallbackWatch.Timeout = "45s"
+	}
+	if c.CallbackWatch.Retention == "" {
+		c.CallbackWatch.Retention = "1h"
+	}
+	if c.IngestQueue.Size == 0 {
+		c.IngestQueue.Size = 1000
+	}
+	if c.IngestQueue.Workers == 0 {
+		c.IngestQueue.Workers = 4
+	}
+	if c.IngestQueue.Attempts == 0 {
+		c.IngestQueue.Attempts = 3
+	}
+	if c.IngestQueue.InitialDelay == "" {
+		c.IngestQueue.InitialDelay = "1s"
+	}
+	if c.IngestQueue.MaxDelay == "" {
+		c.IngestQueue.MaxDelay = "30s"
+	}
+	if c.IngestStream.MaxLen == 0 {
+		c.IngestStream.MaxLen = 100000
+	}
+	if c.IngestStream.Batch == 0 {
+		c.IngestStream.Batch = 10
+	}
+	if c.IngestStream.ClaimIdle == "" {
+		c.IngestStream.ClaimIdle = "1m"
+	}
+	if c.IngestStream.MaxDeliveries == 0 {
+		c.IngestStream.MaxDeliveries = 5
+	}
+	if c.Locking.TTL == "" {
+		c.Locking.TTL = "45s"
+	}
+	if c.Locking.IdempotencyTTL == "" {
+		c.Locking.IdempotencyTTL = "10m"
+	}
+	if c.PostTTL.Default == "" {
+		c.PostTTL.Default = "168h"
+	}
+	if c.PostTTL.CheckInterval == "" {
+		c.PostTTL.CheckInterval = "1m"
+	}
+	if c.QuietHours.CheckInterval == "" {
+		c.QuietHours.CheckInterval = "1m"
+	}
+	if c.WorkflowMenu.RefreshInterval == "" {
+		c.WorkflowMenu.RefreshInterval = "5m"
+	}
+	if c.WorkflowMenu.ResultTimeout == "" {
+		c.WorkflowMenu.ResultTimeout = "2m"
+	}
+	if c.Audit.MaxEvents == 0 {
+		c.Audit.MaxEvents = 100
+	}
+	if c.Audit.Retention == "" {
+		c.Audit.Retention = "720h"
+	}
+	if c.AssigneeRetry.Attempts == 0 {
+		c.AssigneeRetry.Attempts = 4
+	}
+	if c.AssigneeRetry.InitialDelay == "" {
+		c.AssigneeRetry.InitialDelay = "100ms"
+	}
+	if c.AssigneeRetry.Multiplier == 0 {
+		c.AssigneeRetry.Multiplier = 2
+	}
+	if c.AssigneeRetry.MaxDelay == "" {
+		c.AssigneeRetry.MaxDelay = "2s"
+	}
+	if c.AssigneeRetry.Deadline == "" {
+		c.AssigneeRetry.Deadline = "2s"
+	}
+	if c.APIRetry.Attempts == 0 {
+		c.APIRetry.Attempts = 3
+	}
+	if c.APIRetry.InitialDelay == "" {
+		c.APIRetry.InitialDelay = "200ms"
+	}
+	if c.APIRetry.Multiplier == 0 {
+		c.APIRetry.Multiplier = 2
+	}
+	if c.APIRetry.MaxDelay == "" {
+		c.APIRetry.MaxDelay = "5s"
+	}
+	if c.RateLimit.RequestsPerSecond == 0 {
+		c.RateLimit.RequestsPerSecond = 10
+	}
+	if c.RateLimit.Burst == 0 {
+		c.RateLimit.Burst = 20
+	}
+	if c.KeepBreaker.Failures == 0 {
+		c.KeepBreaker.Failures = 5
+	}
+	if c.KeepBreaker.OpenFor == "" {
+		c.KeepBreaker.OpenFor = "30s"
+	}
+	if c.KeepBreaker.QueueSize == 0 {
+		c.KeepBreaker.QueueSize = 100
+	}
+	if c.OfflineActions.ReplayInterval == "" {
+		c.OfflineActions.ReplayInterval = "30s"
+	}
+	if c.OfflineActions.MaxAge == "" {
+		c.OfflineActions.MaxAge = "24h"
+	}
+	if c.UpdateCoalesce.Window == "" {
+		c.UpdateCoalesce.Window = "1s"
+	}
+	if c.CopyCommands.Commands == nil {
+		c.CopyCommands.Commands = []CopyCommandConfig{
+			{Name: "Keep API", Template: `curl -s -H "X-API-KEY: $KEEP_API_KEY" {{quote (print .KeepURL "/alerts/" .Fingerprint)}}`},
+			{Name: "kubectl", Template: `kubectl --namespace {{quote .Labels.namespace}} describe pod {{quote .Labels.pod}}`},
+			{Name: "Silence for 1h", Template: `/keep silence {{.Fingerprint}} 1h`},
+		}
+	}
+}
+
+func (e EscalationConfig) validate() error {
+	d, err := time.ParseDuration(e.CheckInterval)
+	if err != nil {
+		return fmt.Errorf("invalid escalation.check_interval %q: %w", e.CheckInterval, err)
+	}
+	if d < 10*time.Second {
+		return fmt.Errorf("escalation.check_interval must be at least 10s, got %s", d)
+	}
+	if len(e.Rules) == 0 {
+		return fmt.Errorf("escalation.rules must list at least one rule when escalation is enabled")
+	}
+	for i, rule := range e.Rules {
+		if _, err := alert.NewSeverity(rule.Severity); err != nil {
+			return fmt.Errorf("escalation.rules[%d]: %w", i, err)
+		}
+		after, err := time.ParseDuration(rule.After)
+		if err != nil {
+			return fmt.Errorf("invalid escalation.rules[%d].after %q: %w", i, rule.After, err)
+		}
+		if after < time.Minute {
+			return fmt.Errorf("escalation.rules[%d].after must be at least 1m, got %s", i, after)
+		}
+		if rule.Mention == "" && rule.ChannelID == "" {
+			return fmt.Errorf("escalation.rules[%d] needs a mention or a channel_id", i)
+		}
+	}
+	return nil
+}
+
+// validate checks the fields that are set; zero values are filled in by applyDefaults.
+func (r AssigneeRetryConfig) validate() error {
+	if r.Attempts < 0 || r.Attempts > 20 {
+		return fmt.Errorf("assignee_retry.attempts must be between 1 and 20, got %d", r.Attempts)
+	}
+	if r.Multiplier != 0 && r.Multiplier < 1 {
+		return fmt.Errorf("assignee_retry.multiplier must be at least 1, got %g", r.Multiplier)
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("assignee_retry.jitter must be between 0 and 1, got %g", r.Jitter)
+	}
+	for _, d := range []struct{ name, value string }{
+		{"initial_delay", r.InitialDelay},
+		{"max_delay", r.MaxDelay},
+		{"deadline", r.Deadline},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid assignee_retry.%s %q: %w", d.name, d.value, err)
+		}
+		if parsed <= 0 {
+			return fmt.Errorf("assignee_retry.%s must be positive, got %s", d.name, parsed)
+		}
+	}
+	return nil
+}
+
+func (r APIRetryConfig) validate() error {
+	if r.Attempts < 0 || r.Attempts > 10 {
+		return fmt.Errorf("api_retry.attempts must be between 1 and 10, got %d", r.Attempts)
+	}
+	if r.Multiplier != 0 && r.Multiplier < 1 {
+		return fmt.Errorf("api_retry.multiplier must be at least 1, got %g", r.Multiplier)
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("api_retry.jitter must be between 0 and 1, got %g", r.Jitter)
+	}
+	for _, d := range []struct{ name, value string }{
+		{"initial_delay", r.InitialDelay},
+		{"max_delay", r.MaxDelay},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid api_retry.%s %q: %w", d.name, d.value, err)
+		}
+		if parsed <= 0 {
+			return fmt.Errorf("api_retry.%s must be positive, got %s", d.name, parsed)
+		}
+	}
+	return nil
+}
+
+func (e EnrichmentFieldsConfig) validate() error {
+	if len(e.Fields) == 0 {
+		return fmt.Errorf("enrichment_fields.fields must list at least one field when enrichment fields are enabled")
+	}
+	seen := make(map[string]bool, len(e.Fields))
+	for i, field := range e.Fields {
+		if strings.TrimSpace(field.Key) == "" {
+			return fmt.Errorf("enrichment_fields.fields[%d].key is required", i)
+		}
+		if seen[field.Key] {
+			return fmt.Errorf("enrichment_fields.fields[%d]: duplicate key %q", i, field.Key)
+		}
+		seen[field.Key] = true
+	}
+	return nil
+}
+
+func (a AnnotationsConfig) validate() error {
+	seen := make(map[string]bool, len(a.Display))
+	for i, key := range a.Display {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("annotations.display[%d] must not be empty", i)
+		}
+		if seen[key] {
+			return fmt.Errorf("annotations.display[%d]: duplicate annotation %q", i, key)
+		}
+		seen[key] = true
+	}
+	for key, title := range a.Rename {
+		if strings.TrimSpace(title) == "" {
+			return fmt.Errorf("annotations.rename.%s must not be empty", key)
+		}
+	}
+	return nil
+}
+
+func (w WorkflowMenuConfig) validate() error {
+	for i, workflow := range w.Workflows {
+		if strings.TrimSpace(workflow) == "" {
+			return fmt.Errorf("workflow_menu.workflows[%d] must not be empty", i)
+		}
+	}
+	d, err := time.ParseDuration(w.RefreshInterval)
+	if err != nil {
+		return fmt.Errorf("invalid workflow_menu.refresh_interval %q: %w", w.RefreshInterval, err)
+	}
+	if d < 10*time.Second {
+		return fmt.Errorf("workflow_menu.refresh_interval must be at least 10s, got %s", d)
+	}
+	d, err = time.ParseDuration(w.ResultTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid workflow_menu.result_timeout %q: %w", w.ResultTimeout, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("workflow_menu.result_timeout must be positive, got %s", d)
+	}
+	return nil
+}
+
+func (p PermissionsConfig) validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("permissions.rules must list at least one rule when permissions are enabled")
+	}
+	for i, rule := range p.Rules {
+		for _, action := range rule.Actions {
+			switch strings.ToLower(action) {
+			case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge, post.ActionSnooze, post.ActionAssign, post.ActionCustom, post.ActionRunWorkflow:
+			default:
+				return fmt.Errorf("permissions.rules[%d]: unknown action %q", i, action)
+			}
+		}
+		for _, severity := range rule.Severities {
+			if _, err := alert.NewSeverity(severity); err != nil {
+				return fmt.Errorf("permissions.rules[%d]: %w", i, err)
+			}
+		}
+		if len(rule.Users)+len(rule.Teams)+len(rule.Channels)+len(rule.Groups) == 0 {
+			return fmt.Errorf("permissions.rules[%d] needs users, teams, channels or groups", i)
+		}
+	}
+	return nil
+}
+
+func (r RateLimitConfig) validate() error {
+	if r.RequestsPerSecond <= 0 {
+		return fmt.Errorf("rate_limit.requests_per_second must be positive, got %g", r.RequestsPerSecond)
+	}
+	if r.Burst < 1 {
+		return fmt.Errorf("rate_limit.burst must be at least 1, got %d", r.Burst)
+	}
+	return nil
+}
+
+func (b KeepBreakerConfig) validate() error {
+	if b.Failures < 1 {
+		return fmt.Errorf("keep_circuit_breaker.failures must be at least 1, got %d", b.Failures)
+	}
+	d, err := time.ParseDuration(b.OpenFor)
+	if err != nil {
+		return fmt.Errorf("invalid keep_circuit_breaker.open_for %q: %w", b.OpenFor, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("keep_circuit_breaker.open_for must be positive, got %s", d)
+	}
+	if b.QueueSize < 1 {
+		return fmt.Errorf("keep_circuit_breaker.queue_size must be at least 1, got %d", b.QueueSize)
+	}
+	return nil
+}
+
+func (o OfflineActionsConfig) validate() error {
+	interval, err := time.ParseDuration(o.ReplayInterval)
+	if err != nil {
+		return fmt.Errorf("invalid offline_actions.replay_interval %q: %w", o.ReplayInterval, err)
+	}
+	if interval < 5*time.Second {
+		return fmt.Errorf("offline_actions.replay_interval must be at least 5s, got %s", interval)
+	}
+	maxAge, err := time.ParseDuration(o.MaxAge)
+	if err != nil {
+		return fmt.Errorf("invalid offline_actions.max_age %q: %w", o.MaxAge, err)
+	}
+	if maxAge < interval {
+		return fmt.Errorf("offline_actions.max_age must be at least replay_interval (%s), got %s", interval, maxAge)
+	}
+	return nil
+}
+
+func (u UpdateCoalesceConfig) validate() error {
+	d, err := time.ParseDuration(u.Window)
+	if err != nil {
+		return fmt.Errorf("invalid update_coalescing.window %q: %w", u.Window, err)
+	}
+	if d <= 0 || d > time.Minute {
+		return fmt.Errorf("update_coalescing.window must be between 0s and 1m, got %s", d)
+	}
+	return nil
+}
+
+func (c *FileConfig) ChannelIDForSeverity(severity string) string {
+	for _, rule := range c.Channels.Routing {
+		if rule.Severity == severity {
+			return rule.ChannelID
+		}
+	}
+	return c.Channels.DefaultChannelID
+}
+
+// fallbackRoute returns the route of an alert no label rule matched and the
+// name of the rule picking it: the severity rule, the team routing entry of
+// the alert's team label, or the default channel.
+func (c *FileConfig) fallbackRoute(severity string, labels map[string]string) (string, string) {
+	for _, rule := range c.Channels.Routing {
+		if rule.Severity == severity {
+			return "routing[" + severity + "]", rule.ChannelID
+		}
+	}
+	if team, ok := labels[c.Channels.TeamRouting.Label]; ok {
+		if channelID, ok := c.Channels.TeamRouting.Channels[team]; ok {
+			return "team_routing[" + team + "]", channelID
+		}
+	}
+	return "default_channel_id", c.Channels.DefaultChannelID
+}
+
+func (c *FileConfig) ColorForSeverity(severity string) string {
+	if color, ok := c.Message.Colors[severity]; ok {
+		return color
+	}
+	return "#808080"
+}
+
+func (c *FileConfig) EmojiForSeverity(severity string) string {
+	if emoji, ok := c.Message.Emoji[severity]; ok {
+		return emoji
+	}
+	return ""
+}
+
+func (c *FileConfig) IsLabelExcluded(label string) bool {
+	for _, pattern := range c.Labels.Exclude {
+		matched, err := path.Match(pattern, label)
+		if err != nil {
+			if pattern == label {
+				return true
+			}
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *FileConfig) IsLabelDisplayed(label string) bool {
+	if len(c.Labels.Display) == 0 {
+		return !c.Labels.Grouping.Enabled
+	}
+	return slices.Contains(c.Labels.Display, label)
+}
+
+func (c *FileConfig) RenameLabel(label string) string {
+	if renamed, ok := c.Labels.Rename[label]; ok {
+		return renamed
+	}
+	return label
+}
+
+func (c *FileConfig) FooterText() string {
+	return c.Message.Footer.Text
+}
+
+func (c *FileConfig) FooterIconURL() string {
+	return c.Message.Footer.IconURL
+}
+
+func (c *FileConfig) GetKeepUsername(mattermostUsername string) (string, bool) {
+	if c.Users.Mapping == nil {
+		return "", false
+	}
+	keepUser, ok := c.Users.Mapping[mattermostUsername]
+	return keepUser, ok
+}
+
+func (c *FileConfig) GetMattermostUsername(keepUsername string) (string, bool) {
+	if c.Users.Mapping == nil {
+		return "", false
+	}
+	for mmUser, keepUser := range c.Users.Mapping {
+		if keepUser == keepUsername {
+			return mmUser, true
+		}
+	}
+	return "", false
+}
+
+func (c *FileConfig) IsLabelGroupingEnabled() bool {
+	return c.Labels.Grouping.Enabled
+}
+
+func (c *FileConfig) GetLabelGroupingThreshold() int {
+	return c.Labels.Grouping.Threshold
+}
+
+func (c *FileConfig) GetLabelGroups() []port.LabelGroupConfig {
+	result := make([]port.LabelGroupConfig, len(c.Labels.Grouping.Groups))
+	for i, g := range c.Labels.Grouping.Groups {
+		result[i] = port.LabelGroupConfig{
+			Prefixes:  g.Prefixes,
+			GroupName: g.GroupName,
+			Priority:  g.Priority,
+		}
+	}
+	return result
+}
+
+// BadgeInterval returns the parsed badge refresh interval, falling back to one minute.
+func (c *FileConfig) BadgeInterval() time.Duration {
+	d, err := time.ParseDuration(c.Badge.Interval)
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// CopyCommandTemplates returns the commands offered by the Commands button, or nil
+// when the button is disabled.
+func (c *FileConfig) CopyCommandTemplates() []port.CopyCommand {
+	if !c.CopyCommands.Enabled {
+		return nil
+	}
+	commands := make([]port.CopyCommand, 0, len(c.CopyCommands.Commands))
+	for _, cmd := range c.CopyCommands.Commands {
+		commands = append(commands, port.CopyCommand{Name: cmd.Name, Template: cmd.Template})
+	}
+	return commands
+}
+
+// BudgetPostsPerHour returns the hourly post budget of a channel, or zero
+// (unlimited) when the budget is disabled.
+func (c *FileConfig) BudgetPostsPerHour(channelID string) int {
+	if !c.Budget.Enabled {
+		return 0
+	}
+	if limit, ok := c.Budget.Channels[channelID]; ok {
+		return limit
+	}
+	return c.Budget.PostsPerHour
+}
+
+// BudgetReleaseInterval returns the parsed held alert release interval, falling back to one minute.
+func (c *FileConfig) BudgetReleaseInterval() time.Duration {
+	return parseDurationOr(c.Budget.ReleaseInterval, time.Minute)
+}
+
+// RetentionDelay returns how long resolved posts are kept before the
+// retention action runs, falling back to 24 hours.
+func (c *FileConfig) RetentionDelay() time.Duration {
+	return parseDurationOr(c.Retention.Delay, 24*time.Hour)
+}
+
+// RetentionCheckInterval returns the parsed retention job interval, falling back to one minute.
+func (c *FileConfig) RetentionCheckInterval() time.Duration {
+	return parseDurationOr(c.Retention.CheckInterval, time.Minute)
+}
+
+// SLOWebhookThreshold returns the parsed webhook response time objective, falling back to two seconds.
+func (c *FileConfig) SLOWebhookThreshold() time.Duration {
+	return parseDurationOr(c.SLO.Webhook.Threshold, 2*time.Second)
+}
+
+// SLOCallbackThreshold returns the parsed callback response time objective, falling back to one second.
+func (c *FileConfig) SLOCallbackThreshold() time.Duration {
+	return parseDurationOr(c.SLO.Callback.Threshold, time.Second)
+}
+
+// SLOReportInterval returns the parsed SLO report period, falling back to one week.
+func (c *FileConfig) SLOReportInterval() time.Duration {
+	return parseDurationOr(c.SLO.Report.Interval, 7*24*time.Hour)
+}
+
+// DigestSchedule parses digest.schedule in digest.timezone.
+func (c *FileConfig) DigestSchedule() (*cron.Schedule, error) {
+	location := time.UTC
+	if c.Digest.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(c.Digest.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid digest.timezone %q: %w", c.Digest.Timezone, err)
+		}
+	}
+	schedule, err := cron.Parse(c.Digest.Schedule, location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest.schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// DigestPeriod returns the parsed period each digest covers, falling back to one day.
+func (c *FileConfig) DigestPeriod() time.Duration {
+	return parseDurationOr(c.Digest.Period, 24*time.Hour)
+}
+
+// IngestQueueInitialDelay returns the parsed wait before the first retry of
+// a queued alert, falling back to one second.
+func (c *FileConfig) IngestQueueInitialDelay() time.Duration {
+	return parseDurationOr(c.IngestQueue.InitialDelay, time.Second)
+}
+
+// IngestQueueMaxDelay returns the parsed cap for a single queued alert retry
+// delay, falling back to 30 seconds.
+func (c *FileConfig) IngestQueueMaxDelay() time.Duration {
+	return parseDurationOr(c.IngestQueue.MaxDelay, 30*time.Second)
+}
+
+// IngestStreamClaimIdle returns the parsed time after which a pending stream
+// alert is claimed by another replica, falling back to one minute.
+func (c *FileConfig) IngestStreamClaimIdle() time.Duration {
+	return parseDurationOr(c.IngestStream.ClaimIdle, time.Minute)
+}
+
+// LockingTTL returns the parsed fingerprint lock expiry, falling back to 45
+// seconds.
+func (c *FileConfig) LockingTTL() time.Duration {
+	return parseDurationOr(c.Locking.TTL, 45*time.Second)
+}
+
+// LockingIdempotencyTTL returns the parsed time a handled webhook is
+// remembered, falling back to ten minutes.
+func (c *FileConfig) LockingIdempotencyTTL() time.Duration {
+	return parseDurationOr(c.Locking.IdempotencyTTL, 10*time.Minute)
+}
+
+// QuietHoursCheckInterval returns the parsed held alert release interval, falling back to one minute.
+func (c *FileConfig) QuietHoursCheckInterval() time.Duration {
+	return parseDurationOr(c.QuietHours.CheckInterval, time.Minute)
+}
+
+// WorkflowMenuRefreshInterval returns the parsed interval the Run workflow
+// menu is refreshed at, falling back to five minutes.
+func (c *FileConfig) WorkflowMenuRefreshInterval() time.Duration {
+	return parseDurationOr(c.WorkflowMenu.RefreshInterval, 5*time.Minute)
+}
+
+// WorkflowMenuResultTimeout returns how long a workflow run from the menu is
+// followed, falling back to two minutes.
+func (c *FileConfig) WorkflowMenuResultTimeout() time.Duration {
+	return parseDurationOr(c.WorkflowMenu.ResultTimeout, 2*time.Minute)
+}
+
+// PostTTLDefault returns how long posts of severities without their own TTL
+// are kept, falling back to seven days.
+func (c *FileConfig) PostTTLDefault() time.Duration {
+	return parsePostTTL(c.PostTTL.Default, 7*24*time.Hour)
+}
+
+// PostTTLSeverities returns the parsed TTL of each listed severity. Zero
+// means posts of that severity never expire.
+func (c *FileConfig) PostTTLSeverities() map[string]time.Duration {
+	ttls := make(map[string]time.Duration, len(c.PostTTL.Severities))
+	for severity, ttl := range c.PostTTL.Severities {
+		ttls[strings.ToLower(severity)] = parsePostTTL(ttl, c.PostTTLDefault())
+	}
+	return ttls
+}
+
+// PostTTLCheckInterval returns the parsed expired post job interval, falling back to one minute.
+func (c *FileConfig) PostTTLCheckInterval() time.Duration {
+	return parseDurationOr(c.PostTTL.CheckInterval, time.Minute)
+}
+
+// ErrorPostsRepeatInterval returns how long an alert's failures are not
+// posted again, falling back to one hour.
+func (c *FileConfig) ErrorPostsRepeatInterval() time.Duration {
+	return parseDurationOr(c.ErrorPosts.RepeatInterval, time.Hour)
+}
+
+// CallbackWatchTimeout returns how long a callback may show "Processing..."
+// before its post is rewritten, falling back to 45 seconds.
+func (c *FileConfig) CallbackWatchTimeout() time.Duration {
+	return parseDurationOr(c.CallbackWatch.Timeout, 45*time.Second)
+}
+
+// CallbackWatchRetention returns how long callback outcomes are kept, falling
+// back to one hour.
+func (c *FileConfig) CallbackWatchRetention() time.Duration {
+	return parseDurationOr(c.CallbackWatch.Retention, time.Hour)
+}
+
+// DeadLetterRedeliverInterval returns the parsed re-delivery job interval, falling back to one minute.
+func (c *FileConfig) DeadLetterRedeliverInterval() time.Duration {
+	return parseDurationOr(c.DeadLetter.RedeliverInterval, time.Minute)
+}
+
+// AuditRetention returns how long an alert's history is kept after its latest event, falling back to 30 days.
+func (c *FileConfig) AuditRetention() time.Duration {
+	return parseDurationOr(c.Audit.Retention, 30*24*time.Hour)
+}
+
+// SnoozeDuration returns how long the Snooze button silences an alert, or zero
+// when snoozing is disabled.
+func (c *FileConfig) SnoozeDuration() time.Duration {
+	if !c.Snooze.Enabled {
+		return 0
+	}
+	d, err := time.ParseDuration(c.Snooze.Duration)
+	if err != nil || d <= 0 {
+		return time.Hour
+	}
+	return d
+}
+
+// AckDurations returns the durations offered by the Acknowledge for menu, or
+// nil when timed acknowledgements are disabled.
+func (c *FileConfig) AckDurations() []time.Duration {
+	if !c.TimedAck.Enabled {
+		return nil
+	}
+	durations := make([]time.Duration, 0, len(c.TimedAck.Durations))
+	for _, s := range c.TimedAck.Durations {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			durations = append(durations, d)
+		}
+	}
+	return durations
+}
+
+// TimedAckCheckInterval returns the parsed expired acknowledgement job interval, falling back to one minute.
+func (c *FileConfig) TimedAckCheckInterval() time.Duration {
+	return parseDurationOr(c.TimedAck.CheckInterval, time.Minute)
+}
+
+// AssignableUsers returns the Mattermost usernames of users.mapping in
+// alphabetical order, as listed in the Assign menu.
+func (c *FileConfig) AssignableUsers() []string {
+	users := make([]string, 0, len(c.Users.Mapping))
+	for mmUser := range c.Users.Mapping {
+		users = append(users, mmUser)
+	}
+	slices.Sort(users)
+	return users
+}
+
+// EnrichmentFields returns the Keep enrichments shown as fields of alert
+// posts, titled by their key unless renamed.
+func (c *FileConfig) EnrichmentFields() []port.EnrichmentField {
+	fields := make([]port.EnrichmentField, 0, len(c.Enrichments.Fields))
+	for _, f := range c.Enrichments.Fields {
+		title := f.Title
+		if title == "" {
+			title = f.Key
+		}
+		fields = append(fields, port.EnrichmentField{Key: f.Key, Title: title, Short: f.Short})
+	}
+	return fields
+}
+
+// SourceLinkFields returns the Source field settings of alert posts.
+func (c *FileConfig) SourceLinkFields() port.SourceLinks {
+	sources := make([]string, len(c.SourceLinks.Sources))
+	for i, source := range c.SourceLinks.Sources {
+		sources[i] = strings.TrimSpace(source)
+	}
+	return port.SourceLinks{Sources: sources, Annotations: c.SourceLinks.Annotations}
+}
+
+// EnrichmentKeys returns the keys of the enrichments shown in alert posts.
+func (c *FileConfig) EnrichmentKeys() []string {
+	keys := make([]string, 0, len(c.Enrichments.Fields))
+	for _, f := range c.Enrichments.Fields {
+		keys = append(keys, f.Key)
 	}
-	return ""
+	return keys
 }
 
-func (c *FileConfig) IsLabelExcluded(label string) bool {
-	for _, pattern := range c.Labels.Exclude {
-		matched, err := path.Match(pattern, label)
-		if err != nil {
-			if pattern == label {
-				return true
+// ShownAnnotations returns the keys of the annotations shown in an alert
+// post, in order: those of annotations.display the alert has, or else all
+// of its annotations not excluded, sorted. Annotations with an empty value
+// are left out. It implements attachment.AnnotationPolicy.
+func (c *FileConfig) ShownAnnotations(annotations map[string]string) []string {
+	var keys []string
+	if len(c.Annotations.Display) > 0 {
+		for _, key := range c.Annotations.Display {
+			if strings.TrimSpace(annotations[key]) != "" && !slices.Contains(c.Annotations.Exclude, key) {
+				keys = append(keys, key)
 			}
-			continue
 		}
-		if matched {
-			return true
+		return keys
+	}
+	for key, value := range annotations {
+		if strings.TrimSpace(value) != "" && !slices.Contains(c.Annotations.Exclude, key) {
+			keys = append(keys, key)
 		}
 	}
-	return false
+	slices.Sort(keys)
+	return keys
 }
 
-func (c *FileConfig) IsLabelDisplayed(label string) bool {
-	if len(c.Labels.Display) == 0 {
-		return !c.Labels.Grouping.Enabled
+// AnnotationTitle returns the field title of the annotation key: its
+// annotations.rename entry, or the key itself. It implements
+// attachment.AnnotationPolicy.
+func (c *FileConfig) AnnotationTitle(key string) string {
+	if title := c.Annotations.Rename[key]; title != "" {
+		return title
 	}
-	return slices.Contains(c.Labels.Display, label)
+	return key
 }
 
-func (c *FileConfig) RenameLabel(label string) string {
-	if renamed, ok := c.Labels.Rename[label]; ok {
-		return renamed
+// SnoozeCheckInterval returns the parsed unsnooze job interval, falling back to one minute.
+func (c *FileConfig) SnoozeCheckInterval() time.Duration {
+	d, err := time.ParseDuration(c.Snooze.CheckInterval)
+	if err != nil || d <= 0 {
+		return time.Minute
 	}
-	return label
+	return d
 }
 
-func (c *FileConfig) FooterText() string {
-	return c.Message.Footer.Text
+// EscalationCheckInterval returns the parsed escalation job interval, falling back to one minute.
+func (c *FileConfig) EscalationCheckInterval() time.Duration {
+	return parseDurationOr(c.Escalation.CheckInterval, time.Minute)
 }
 
-func (c *FileConfig) FooterIconURL() string {
-	return c.Message.Footer.IconURL
+// EscalationRules returns the configured escalation steps with parsed delays.
+// Rules with an unparsable delay are skipped; Validate rejects them upfront.
+func (c *FileConfig) EscalationRules() []port.EscalationRule {
+	rules := make([]port.EscalationRule, 0, len(c.Escalation.Rules))
+	for _, rule := range c.Escalation.Rules {
+		after, err := time.ParseDuration(rule.After)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, port.EscalationRule{
+			Severity:  strings.ToLower(rule.Severity),
+			After:     after,
+			Mention:   rule.Mention,
+			ChannelID: rule.ChannelID,
+		})
+	}
+	return rules
 }
 
-func (c *FileConfig) GetKeepUsername(mattermostUsername string) (string, bool) {
-	if c.Users.Mapping == nil {
-		return "", false
+// PermissionRules returns the configured permission rules with actions and
+// severities in lower case and usernames and group names without the
+// leading @.
+func (c *FileConfig) PermissionRules() []port.PermissionRule {
+	lower := func(values []string) []string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = strings.ToLower(v)
+		}
+		return out
 	}
-	keepUser, ok := c.Users.Mapping[mattermostUsername]
-	return keepUser, ok
+	trimAt := func(values []string) []string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = strings.TrimPrefix(v, "@")
+		}
+		return out
+	}
+
+	rules := make([]port.PermissionRule, 0, len(c.Permissions.Rules))
+	for _, rule := range c.Permissions.Rules {
+		rules = append(rules, port.PermissionRule{
+			Actions:    lower(rule.Actions),
+			Severities: lower(rule.Severities),
+			Users:      trimAt(rule.Users),
+			Teams:      rule.Teams,
+			Channels:   rule.Channels,
+			Groups:     trimAt(rule.Groups),
+		})
+	}
+	return rules
 }
 
-func (c *FileConfig) GetMattermostUsername(keepUsername string) (string, bool) {
-	if c.Users.Mapping == nil {
-		return "", false
+// ReactionsInterval returns the parsed reaction sync interval, falling back to five minutes.
+func (c *FileConfig) ReactionsInterval() time.Duration {
+	return parseDurationOr(c.Reactions.Interval, 5*time.Minute)
+}
+
+func (c *FileConfig) validateReactionActions() error {
+	if len(c.ReactionAction.Emojis) == 0 {
+		return fmt.Errorf("reaction_actions.emojis must map at least one emoji when reaction actions are enabled")
 	}
-	for mmUser, keepUser := range c.Users.Mapping {
-		if keepUser == keepUsername {
-			return mmUser, true
+	for emoji, action := range c.ReactionAction.Emojis {
+		if strings.Trim(emoji, ":") == "" {
+			return fmt.Errorf("reaction_actions.emojis must not contain empty emoji names")
+		}
+		switch action {
+		case post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge:
+		case post.ActionSnooze:
+			if !c.Snooze.Enabled {
+				return fmt.Errorf("reaction_actions.emojis.%s: snooze needs snooze.enabled", emoji)
+			}
+		default:
+			return fmt.Errorf("invalid reaction_actions.emojis.%s %q: must be %s, %s, %s or %s", emoji, action, post.ActionAcknowledge, post.ActionResolve, post.ActionUnacknowledge, post.ActionSnooze)
 		}
 	}
-	return "", false
+	return nil
 }
 
-func (c *FileConfig) IsLabelGroupingEnabled() bool {
-	return c.Labels.Grouping.Enabled
+// UserAutoMappingRefreshInterval returns the parsed user auto-mapping refresh
+// interval, falling back to one hour.
+func (c *FileConfig) UserAutoMappingRefreshInterval() time.Duration {
+	return parseDurationOr(c.Users.AutoMapping.RefreshInterval, time.Hour)
 }
 
-func (c *FileConfig) GetLabelGroupingThreshold() int {
-	return c.Labels.Grouping.Threshold
+// DeletedPostsAction returns what the poller does about alerts whose post
+// was deleted, or "" when it does not check posts.
+func (c *FileConfig) DeletedPostsAction() string {
+	if c.Polling.DeletedPosts == "" && c.Polling.RecreateDeletedPosts {
+		return port.DeletedPostRecreate
+	}
+	return c.Polling.DeletedPosts
 }
 
-func (c *FileConfig) GetLabelGroups() []port.LabelGroupConfig {
-	result := make([]port.LabelGroupConfig, len(c.Labels.Grouping.Groups))
-	for i, g := range c.Labels.Grouping.Groups {
-		result[i] = port.LabelGroupConfig{
-			Prefixes:  g.Prefixes,
-			GroupName: g.GroupName,
-			Priority:  g.Priority,
-		}
+// UserAutoMappingCacheTTL returns the parsed lifetime of cached email
+// lookups, falling back to 24 hours.
+func (c *FileConfig) UserAutoMappingCacheTTL() time.Duration {
+	return parseDurationOr(c.Users.AutoMapping.CacheTTL, 24*time.Hour)
+}
+
+// FlappingWindow returns the parsed window state changes are counted in,
+// falling back to 30 minutes.
+func (c *FileConfig) FlappingWindow() time.Duration {
+	return parseDurationOr(c.Flapping.Window, 30*time.Minute)
+}
+
+// FlappingCheckInterval returns the parsed interval of the job that ends
+// flapping, falling back to one minute.
+func (c *FileConfig) FlappingCheckInterval() time.Duration {
+	return parseDurationOr(c.Flapping.CheckInterval, time.Minute)
+}
+
+// AssigneeRetryInitialDelay returns the parsed wait before the first assignee
+// retry, falling back to 100ms.
+func (c *FileConfig) AssigneeRetryInitialDelay() time.Duration {
+	return parseDurationOr(c.AssigneeRetry.InitialDelay, 100*time.Millisecond)
+}
+
+// AssigneeRetryMaxDelay returns the parsed cap for a single assignee retry
+// delay, falling back to two seconds.
+func (c *FileConfig) AssigneeRetryMaxDelay() time.Duration {
+	return parseDurationOr(c.AssigneeRetry.MaxDelay, 2*time.Second)
+}
+
+// AssigneeRetryDeadline returns the parsed overall assignee wait budget,
+// falling back to two seconds.
+func (c *FileConfig) AssigneeRetryDeadline() time.Duration {
+	return parseDurationOr(c.AssigneeRetry.Deadline, 2*time.Second)
+}
+
+// APIRetryInitialDelay returns the parsed wait before the first API retry,
+// falling back to 200ms.
+func (c *FileConfig) APIRetryInitialDelay() time.Duration {
+	return parseDurationOr(c.APIRetry.InitialDelay, 200*time.Millisecond)
+}
+
+// KeepBreakerOpenFor returns how long the Keep circuit breaker stays open,
+// defaulting to 30s.
+func (c *FileConfig) KeepBreakerOpenFor() time.Duration {
+	return parseDurationOr(c.KeepBreaker.OpenFor, 30*time.Second)
+}
+
+// OfflineActionsReplayInterval returns how often actions kept while Keep was
+// unreachable are replayed, defaulting to 30s.
+func (c *FileConfig) OfflineActionsReplayInterval() time.Duration {
+	return parseDurationOr(c.OfflineActions.ReplayInterval, 30*time.Second)
+}
+
+// OfflineActionsMaxAge returns how long an action waits for Keep before it is
+// dropped, defaulting to 24h.
+func (c *FileConfig) OfflineActionsMaxAge() time.Duration {
+	return parseDurationOr(c.OfflineActions.MaxAge, 24*time.Hour)
+}
+
+// APIRetryMaxDelay returns the parsed cap for a single API retry delay,
+// falling back to five seconds.
+func (c *FileConfig) APIRetryMaxDelay() time.Duration {
+	return parseDurationOr(c.APIRetry.MaxDelay, 5*time.Second)
+}
+
+// UpdateCoalesceWindow returns the parsed post update window, falling back to one second.
+func (c *FileConfig) UpdateCoalesceWindow() time.Duration {
+	return parseDurationOr(c.UpdateCoalesce.Window, time.Second)
+}
+
+func parseDurationOr(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
 	}
-	return result
+	return d
+}
+
+// parsePostTTL is parseDurationOr for TTLs, where 0 stands for never.
+func parsePostTTL(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
 }
 
 func (c *FileConfig) ShowSeverityField() bool {
@@ -322,6 +2381,10 @@ func (c *FileConfig) ShowDescriptionField() bool {
 	return *c.Message.Fields.ShowDescription
 }
 
+func (c *FileConfig) ShowFingerprintField() bool {
+	return c.Message.Fields.ShowFingerprint
+}
+
 func (c *FileConfig) SeverityFieldPosition() string {
 	pos := c.Message.Fields.SeverityPosition
 	if pos == "" {
@@ -334,3 +2397,365 @@ func (c *FileConfig) SeverityFieldPosition() string {
 		return post.SeverityPositionFirst
 	}
 }
+
+// CompactSeverities returns the severities message.style renders compact,
+// sorted.
+func (c *FileConfig) CompactSeverities() []string {
+	var severities []string
+	for severity, style := range c.Message.Style {
+		if strings.EqualFold(style, MessageStyleCompact) {
+			severities = append(severities, strings.ToLower(severity))
+		}
+	}
+	slices.Sort(severities)
+	return severities
+}
+
+// BotIdentity returns the name and avatar alert posts of severity are shown
+// with: message.bot.severity's, falling back to message.bot's. It implements
+// attachment.BotIdentityPolicy.
+func (c *FileConfig) BotIdentity(severity string) (username, iconURL string) {
+	username, iconURL = c.Message.Bot.Username, c.Message.Bot.IconURL
+	for s, identity := range c.Message.Bot.Severity {
+		if !strings.EqualFold(s, severity) {
+			continue
+		}
+		if identity.Username != "" {
+			username = identity.Username
+		}
+		if identity.IconURL != "" {
+			iconURL = identity.IconURL
+		}
+	}
+	return username, iconURL
+}
+
+// SeverityNormalization returns the severity map, or nil when none is
+// configured.
+func (c *FileConfig) SeverityNormalization() alert.SeverityMap {
+	return alert.NewSeverityMap(c.SeverityMap)
+}
+
+func (c *FileConfig) validateSeverityMap() error {
+	seen := make(map[string]string, len(c.SeverityMap))
+	for from, to := range c.SeverityMap {
+		key := strings.ToLower(strings.TrimSpace(from))
+		if key == "" {
+			return fmt.Errorf("severity_map: empty input severity")
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("severity_map: %q and %q are the same input severity", other, from)
+		}
+		seen[key] = from
+		if _, err := alert.NewSeverity(to); err != nil {
+			return fmt.Errorf("severity_map[%q]: %w", from, err)
+		}
+	}
+	return nil
+}
+
+func (b BotConfig) validate() error {
+	for severity := range b.Severity {
+		if _, err := alert.NewSeverity(severity); err != nil {
+			return fmt.Errorf("message.bot.severity: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateMessageStyle(field string, style map[string]string) error {
+	for severity, s := range style {
+		if _, err := alert.NewSeverity(severity); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		switch strings.ToLower(s) {
+		case MessageStyleFull, MessageStyleCompact:
+		default:
+			return fmt.Errorf("invalid %s.%s %q: must be %q or %q", field, severity, s, MessageStyleFull, MessageStyleCompact)
+		}
+	}
+	return nil
+}
+
+func (r RetentionConfig) validate() error {
+	switch r.Action {
+	case retention.ActionDelete, retention.ActionCollapse:
+	case retention.ActionArchive:
+		if r.ArchiveChannelID == "" {
+			return fmt.Errorf("retention.archive_channel_id is required when retention.action is %q", retention.ActionArchive)
+		}
+	default:
+		return fmt.Errorf("invalid retention.action %q: must be %q, %q or %q", r.Action, retention.ActionDelete, retention.ActionCollapse, retention.ActionArchive)
+	}
+	d, err := time.ParseDuration(r.Delay)
+	if err != nil {
+		return fmt.Errorf("invalid retention.delay %q: %w", r.Delay, err)
+	}
+	if d < time.Minute {
+		return fmt.Errorf("retention.delay must be at least 1m, got %s", d)
+	}
+	d, err = time.ParseDuration(r.CheckInterval)
+	if err != nil {
+		return fmt.Errorf("invalid retention.check_interval %q: %w", r.CheckInterval, err)
+	}
+	if d < 10*time.Second {
+		return fmt.Errorf("retention.check_interval must be at least 10s, got %s", d)
+	}
+	return nil
+}
+
+func (s SLOConfig) validate() error {
+	for name, o := range map[string]SLOObjectiveConfig{"webhook": s.Webhook, "callback": s.Callback} {
+		if o.Target <= 0 || o.Target >= 1 {
+			return fmt.Errorf("slo.%s.target must be between 0 and 1 exclusive, got %g", name, o.Target)
+		}
+		d, err := time.ParseDuration(o.Threshold)
+		if err != nil {
+			return fmt.Errorf("invalid slo.%s.threshold %q: %w", name, o.Threshold, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("slo.%s.threshold must be positive, got %s", name, d)
+		}
+	}
+	d, err := time.ParseDuration(s.Report.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid slo.report.interval %q: %w", s.Report.Interval, err)
+	}
+	if d < time.Hour {
+		return fmt.Errorf("slo.report.interval must be at least 1h, got %s", d)
+	}
+	return nil
+}
+
+func (c *FileConfig) validateDigest() error {
+	if c.Digest.ChannelID == "" {
+		return fmt.Errorf("digest.channel_id is required when the digest is enabled")
+	}
+	if !c.Audit.Enabled {
+		return fmt.Errorf("digest requires audit.enabled, its statistics come from the audit trail")
+	}
+	if _, err := c.DigestSchedule(); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(c.Digest.Period)
+	if err != nil {
+		return fmt.Errorf("invalid digest.period %q: %w", c.Digest.Period, err)
+	}
+	if d < time.Hour {
+		return fmt.Errorf("digest.period must be at least 1h, got %s", d)
+	}
+	if c.Digest.Top < 1 || c.Digest.Top > 20 {
+		return fmt.Errorf("digest.top must be between 1 and 20, got %d", c.Digest.Top)
+	}
+	return nil
+}
+
+func (d DeadLetterConfig) validate() error {
+	interval, err := time.ParseDuration(d.RedeliverInterval)
+	if err != nil {
+		return fmt.Errorf("invalid dead_letter.redeliver_interval %q: %w", d.RedeliverInterval, err)
+	}
+	if interval < 10*time.Second {
+		return fmt.Errorf("dead_letter.redeliver_interval must be at least 10s, got %s", interval)
+	}
+	if d.MaxAttempts < 1 {
+		return fmt.Errorf("dead_letter.max_attempts must be at least 1, got %d", d.MaxAttempts)
+	}
+	return nil
+}
+
+func (e ErrorPostsConfig) validate() error {
+	if e.ChannelID == "" {
+		return fmt.Errorf("error_posts.channel_id is required when error posts are enabled")
+	}
+	interval, err := time.ParseDuration(e.RepeatInterval)
+	if err != nil {
+		return fmt.Errorf("invalid error_posts.repeat_interval %q: %w", e.RepeatInterval, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("error_posts.repeat_interval must be positive, got %s", interval)
+	}
+	return nil
+}
+
+func (w CallbackWatchConfig) validate() error {
+	timeout, err := time.ParseDuration(w.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid callback_watchdog.timeout %q: %w", w.Timeout, err)
+	}
+	if timeout < 5*time.Second {
+		return fmt.Errorf("callback_watchdog.timeout must be at least 5s, got %s", timeout)
+	}
+	retention, err := time.ParseDuration(w.Retention)
+	if err != nil {
+		return fmt.Errorf("invalid callback_watchdog.retention %q: %w", w.Retention, err)
+	}
+	if retention < time.Minute {
+		return fmt.Errorf("callback_watchdog.retention must be at least 1m, got %s", retention)
+	}
+	return nil
+}
+
+func (q IngestQueueConfig) validate() error {
+	if q.Size < 1 || q.Size > 100000 {
+		return fmt.Errorf("ingest_queue.size must be between 1 and 100000, got %d", q.Size)
+	}
+	if q.Workers < 1 || q.Workers > 64 {
+		return fmt.Errorf("ingest_queue.workers must be between 1 and 64, got %d", q.Workers)
+	}
+	if q.Attempts < 1 || q.Attempts > 10 {
+		return fmt.Errorf("ingest_queue.attempts must be between 1 and 10, got %d", q.Attempts)
+	}
+	for _, d := range []struct{ name, value string }{
+		{"initial_delay", q.InitialDelay},
+		{"max_delay", q.MaxDelay},
+	} {
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid ingest_queue.%s %q: %w", d.name, d.value, err)
+		}
+		if parsed <= 0 {
+			return fmt.Errorf("ingest_queue.%s must be positive, got %s", d.name, parsed)
+		}
+	}
+	return nil
+}
+
+func (s IngestStreamConfig) validate() error {
+	if s.MaxLen < 0 {
+		return fmt.Errorf("ingest_stream.max_len must not be negative, got %d", s.MaxLen)
+	}
+	if s.Batch < 0 || s.Batch > 1000 {
+		return fmt.Errorf("ingest_stream.batch must be between 1 and 1000, got %d", s.Batch)
+	}
+	if s.MaxDeliveries < 0 || s.MaxDeliveries > 100 {
+		return fmt.Errorf("ingest_stream.max_deliveries must be between 1 and 100, got %d", s.MaxDeliveries)
+	}
+	if s.ClaimIdle != "" {
+		d, err := time.ParseDuration(s.ClaimIdle)
+		if err != nil {
+			return fmt.Errorf("invalid ingest_stream.claim_idle %q: %w", s.ClaimIdle, err)
+		}
+		if d < 5*time.Second {
+			return fmt.Errorf("ingest_stream.claim_idle must be at least 5s, got %s", d)
+		}
+	}
+	return nil
+}
+
+func (l LockingConfig) validate() error {
+	ttl, err := time.ParseDuration(l.TTL)
+	if err != nil {
+		return fmt.Errorf("invalid locking.ttl %q: %w", l.TTL, err)
+	}
+	// Handling an alert may take up to the 30s webhook timeout.
+	if ttl < 30*time.Second {
+		return fmt.Errorf("locking.ttl must be at least 30s, got %s", ttl)
+	}
+	idempotencyTTL, err := time.ParseDuration(l.IdempotencyTTL)
+	if err != nil {
+		return fmt.Errorf("invalid locking.idempotency_ttl %q: %w", l.IdempotencyTTL, err)
+	}
+	if idempotencyTTL < time.Minute {
+		return fmt.Errorf("locking.idempotency_ttl must be at least 1m, got %s", idempotencyTTL)
+	}
+	return nil
+}
+
+func (p PostTTLConfig) validate() error {
+	if err := validatePostTTL("post_ttl.default", p.Default); err != nil {
+		return err
+	}
+	for severity, ttl := range p.Severities {
+		if _, err := alert.NewSeverity(severity); err != nil {
+			return fmt.Errorf("post_ttl.severities: %w", err)
+		}
+		if err := validatePostTTL("post_ttl.severities."+severity, ttl); err != nil {
+			return err
+		}
+	}
+	d, err := time.ParseDuration(p.CheckInterval)
+	if err != nil {
+		return fmt.Errorf("invalid post_ttl.check_interval %q: %w", p.CheckInterval, err)
+	}
+	if d < 10*time.Second {
+		return fmt.Errorf("post_ttl.check_interval must be at least 10s, got %s", d)
+	}
+	return nil
+}
+
+// validatePostTTL accepts 0 for never, or a TTL of at least one minute.
+func validatePostTTL(name, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	if d != 0 && d < time.Minute {
+		return fmt.Errorf("%s must be 0 or at least 1m, got %s", name, d)
+	}
+	return nil
+}
+
+func (t TimedAckConfig) validate() error {
+	if len(t.Durations) > 10 {
+		return fmt.Errorf("timed_ack.durations must list at most 10 durations, got %d", len(t.Durations))
+	}
+	seen := make(map[time.Duration]bool, len(t.Durations))
+	for _, s := range t.Durations {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid timed_ack.durations entry %q: %w", s, err)
+		}
+		if d < time.Minute || d > 168*time.Hour {
+			return fmt.Errorf("timed_ack.durations must be between 1m and 168h, got %s", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("timed_ack.durations lists %s twice", d)
+		}
+		seen[d] = true
+	}
+	interval, err := time.ParseDuration(t.CheckInterval)
+	if err != nil {
+		return fmt.Errorf("invalid timed_ack.check_interval %q: %w", t.CheckInterval, err)
+	}
+	if interval < 10*time.Second {
+		return fmt.Errorf("timed_ack.check_interval must be at least 10s, got %s", interval)
+	}
+	return nil
+}
+
+func (f FlappingConfig) validate() error {
+	window, err := time.ParseDuration(f.Window)
+	if err != nil {
+		return fmt.Errorf("invalid flapping.window %q: %w", f.Window, err)
+	}
+	if window < time.Minute {
+		return fmt.Errorf("flapping.window must be at least 1m, got %s", window)
+	}
+	// One fire and one resolve are an ordinary alert, not a flapping one.
+	if f.Threshold < 3 {
+		return fmt.Errorf("flapping.threshold must be at least 3, got %d", f.Threshold)
+	}
+	interval, err := time.ParseDuration(f.CheckInterval)
+	if err != nil {
+		return fmt.Errorf("invalid flapping.check_interval %q: %w", f.CheckInterval, err)
+	}
+	if interval < 10*time.Second {
+		return fmt.Errorf("flapping.check_interval must be at least 10s, got %s", interval)
+	}
+	return nil
+}
+
+func (a AuditConfig) validate() error {
+	if a.MaxEvents < 1 || a.MaxEvents > 10000 {
+		return fmt.Errorf("audit.max_events must be between 1 and 10000, got %d", a.MaxEvents)
+	}
+	retention, err := time.ParseDuration(a.Retention)
+	if err != nil {
+		return fmt.Errorf("invalid audit.retention %q: %w", a.Retention, err)
+	}
+	if retention < time.Hour {
+		return fmt.Errorf("audit.retention must be at least 1h, got %s", retention)
+	}
+	return nil
+}
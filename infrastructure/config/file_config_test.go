@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 )
 
 func TestLoadFromFileValid(t *testing.T) {
@@ -31,6 +34,8 @@ message:
   footer:
     text: "Custom Footer"
     icon_url: "https://custom.com/icon.png"
+  timeline:
+    enabled: true
   bot:
     username: "CustomBot"
     icon_url: "https://custom.com/bot.png"
@@ -65,6 +70,7 @@ labels:
 	assert.Equal(t, "🚨", cfg.Message.Emoji["critical"])
 	assert.Equal(t, "Custom Footer", cfg.Message.Footer.Text)
 	assert.Equal(t, "https://custom.com/icon.png", cfg.Message.Footer.IconURL)
+	assert.True(t, cfg.Message.Timeline.Enabled)
 
 	assert.Contains(t, cfg.Labels.Display, "host")
 	assert.Contains(t, cfg.Labels.Display, "service")
@@ -81,6 +87,7 @@ func TestLoadFromFileNonExistent(t *testing.T) {
 	assert.NotNil(t, cfg.Message.Colors)
 	assert.NotNil(t, cfg.Message.Emoji)
 	assert.Equal(t, "Keep AIOps", cfg.Message.Footer.Text)
+	assert.False(t, cfg.Message.Timeline.Enabled)
 }
 
 func TestLoadFromFileInvalidYAML(t *testing.T) {
@@ -532,7 +539,7 @@ func TestApplyDefaults(t *testing.T) {
 }
 
 func TestDefaultFileConfig(t *testing.T) {
-	cfg := defaultFileConfig()
+	cfg := DefaultFileConfig()
 
 	require.NotNil(t, cfg)
 	assert.Equal(t, "", cfg.Channels.DefaultChannelID)
@@ -918,6 +925,17 @@ func TestGetMattermostUsername(t *testing.T) {
 	})
 }
 
+func TestAssignableUsers(t *testing.T) {
+	cfg := &FileConfig{Users: UsersConfig{Mapping: map[string]string{
+		"jane.smith": "jane_keep",
+		"john.doe":   "john_keep",
+		"alice":      "alice_keep",
+	}}}
+	assert.Equal(t, []string{"alice", "jane.smith", "john.doe"}, cfg.AssignableUsers())
+
+	assert.Empty(t, (&FileConfig{}).AssignableUsers())
+}
+
 func TestServerConfigAddr(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1365,6 +1383,1333 @@ labels:
 	assert.Contains(t, err.Error(), "invalid label exclude pattern")
 }
 
+func TestValidateBadge(t *testing.T) {
+	tests := []struct {
+		name    string
+		badge   BadgeConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", badge: BadgeConfig{Target: "bogus"}},
+		{name: "status target", badge: BadgeConfig{Enabled: true, Target: "status", Interval: "1m"}},
+		{name: "channel purpose target", badge: BadgeConfig{Enabled: true, Target: "channel_purpose", ChannelID: "ch-1", Interval: "30s"}},
+		{name: "unknown target", badge: BadgeConfig{Enabled: true, Target: "header", Interval: "1m"}, wantErr: "invalid badge.target"},
+		{name: "channel purpose without channel", badge: BadgeConfig{Enabled: true, Target: "channel_purpose", Interval: "1m"}, wantErr: "badge.channel_id is required"},
+		{name: "bad interval", badge: BadgeConfig{Enabled: true, Target: "status", Interval: "soon"}, wantErr: "invalid badge.interval"},
+		{name: "interval too short", badge: BadgeConfig{Enabled: true, Target: "status", Interval: "5s"}, wantErr: "at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Badge: tt.badge}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateAlertGrouping(t *testing.T) {
+	tests := []struct {
+		name     string
+		grouping AlertGroupingConfig
+		wantErr  string
+	}{
+		{name: "disabled", grouping: AlertGroupingConfig{}},
+		{name: "enabled with labels", grouping: AlertGroupingConfig{Enabled: true, GroupBy: []string{"alertgroup", "service"}}},
+		{name: "enabled without labels", grouping: AlertGroupingConfig{Enabled: true}, wantErr: "must list at least one label"},
+		{name: "empty label", grouping: AlertGroupingConfig{Enabled: true, GroupBy: []string{"service", ""}}, wantErr: "must not contain empty labels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{AlertGrouping: tt.grouping}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBadgeDefaults(t *testing.T) {
+	cfg := &FileConfig{Badge: BadgeConfig{Enabled: true}}
+	cfg.applyDefaults()
+
+	assert.Equal(t, "status", cfg.Badge.Target)
+	assert.Equal(t, time.Minute, cfg.BadgeInterval())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateSnooze(t *testing.T) {
+	tests := []struct {
+		name    string
+		snooze  SnoozeConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", snooze: SnoozeConfig{Duration: "forever"}},
+		{name: "valid", snooze: SnoozeConfig{Enabled: true, Duration: "2h", CheckInterval: "30s"}},
+		{name: "bad duration", snooze: SnoozeConfig{Enabled: true, Duration: "later", CheckInterval: "1m"}, wantErr: "invalid snooze.duration"},
+		{name: "duration too short", snooze: SnoozeConfig{Enabled: true, Duration: "30s", CheckInterval: "1m"}, wantErr: "between 1m and 168h"},
+		{name: "duration too long", snooze: SnoozeConfig{Enabled: true, Duration: "200h", CheckInterval: "1m"}, wantErr: "between 1m and 168h"},
+		{name: "bad check interval", snooze: SnoozeConfig{Enabled: true, Duration: "1h", CheckInterval: "often"}, wantErr: "invalid snooze.check_interval"},
+		{name: "check interval too short", snooze: SnoozeConfig{Enabled: true, Duration: "1h", CheckInterval: "1s"}, wantErr: "at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Snooze: tt.snooze}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFlapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		flapping FlappingConfig
+		wantErr  string
+	}{
+		{name: "disabled ignores fields", flapping: FlappingConfig{Window: "a while"}},
+		{name: "valid", flapping: FlappingConfig{Enabled: true, Window: "15m", Threshold: 4, CheckInterval: "30s"}},
+		{name: "bad window", flapping: FlappingConfig{Enabled: true, Window: "a while", Threshold: 6, CheckInterval: "1m"}, wantErr: "invalid flapping.window"},
+		{name: "window too short", flapping: FlappingConfig{Enabled: true, Window: "30s", Threshold: 6, CheckInterval: "1m"}, wantErr: "flapping.window must be at least 1m"},
+		{name: "threshold too low", flapping: FlappingConfig{Enabled: true, Window: "30m", Threshold: 2, CheckInterval: "1m"}, wantErr: "flapping.threshold must be at least 3"},
+		{name: "bad check interval", flapping: FlappingConfig{Enabled: true, Window: "30m", Threshold: 6, CheckInterval: "often"}, wantErr: "invalid flapping.check_interval"},
+		{name: "check interval too short", flapping: FlappingConfig{Enabled: true, Window: "30m", Threshold: 6, CheckInterval: "1s"}, wantErr: "flapping.check_interval must be at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Flapping: tt.flapping}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestFlappingDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Flapping.Enabled)
+	assert.Equal(t, 6, cfg.Flapping.Threshold)
+	assert.Equal(t, 30*time.Minute, cfg.FlappingWindow())
+	assert.Equal(t, time.Minute, cfg.FlappingCheckInterval())
+	assert.Equal(t, "#D35400", cfg.Message.Colors["flapping"])
+
+	cfg.Flapping.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateResolveDialog(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialog  ResolveDialogConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", dialog: ResolveDialogConfig{Categories: []string{""}}},
+		{name: "valid", dialog: ResolveDialogConfig{Enabled: true, RequireNote: true, Categories: []string{"Network", "Deploy"}}},
+		{name: "no categories", dialog: ResolveDialogConfig{Enabled: true}},
+		{name: "empty category", dialog: ResolveDialogConfig{Enabled: true, Categories: []string{"Network", " "}}, wantErr: "resolve_dialog.categories[1]"},
+		{name: "duplicate category", dialog: ResolveDialogConfig{Enabled: true, Categories: []string{"Network", "Network"}}, wantErr: `lists "Network" twice`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{ResolveDialog: tt.dialog}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSnoozeDefaults(t *testing.T) {
+	cfg := &FileConfig{}
+	cfg.applyDefaults()
+	assert.Zero(t, cfg.SnoozeDuration(), "disabled snooze has no duration")
+
+	cfg.Snooze.Enabled = true
+	assert.Equal(t, time.Hour, cfg.SnoozeDuration())
+	assert.Equal(t, time.Minute, cfg.SnoozeCheckInterval())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateTimedAck(t *testing.T) {
+	tests := []struct {
+		name     string
+		timedAck TimedAckConfig
+		wantErr  string
+	}{
+		{name: "disabled ignores fields", timedAck: TimedAckConfig{Durations: []string{"forever"}}},
+		{name: "valid", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"30m", "2h"}, CheckInterval: "30s"}},
+		{name: "bad duration", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"later"}, CheckInterval: "1m"}, wantErr: "invalid timed_ack.durations entry"},
+		{name: "duration too short", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"30s"}, CheckInterval: "1m"}, wantErr: "between 1m and 168h"},
+		{name: "duration too long", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"200h"}, CheckInterval: "1m"}, wantErr: "between 1m and 168h"},
+		{name: "duplicate duration", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"60m", "1h"}, CheckInterval: "1m"}, wantErr: "lists 1h0m0s twice"},
+		{name: "bad check interval", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"1h"}, CheckInterval: "often"}, wantErr: "invalid timed_ack.check_interval"},
+		{name: "check interval too short", timedAck: TimedAckConfig{Enabled: true, Durations: []string{"1h"}, CheckInterval: "1s"}, wantErr: "at least 10s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{TimedAck: tt.timedAck}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTimedAckDefaults(t *testing.T) {
+	cfg := &FileConfig{}
+	cfg.applyDefaults()
+	assert.Nil(t, cfg.AckDurations(), "disabled timed acknowledgements offer no durations")
+
+	cfg.TimedAck.Enabled = true
+	assert.Equal(t, []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour, 4 * time.Hour}, cfg.AckDurations())
+	assert.Equal(t, time.Minute, cfg.TimedAckCheckInterval())
+	assert.NoError(t, cfg.Validate())
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestValidateEscalation(t *testing.T) {
+	validRule := EscalationRuleConfig{Severity: "critical", After: "15m", Mention: "@sre-oncall"}
+	tests := []struct {
+		name       string
+		escalation EscalationConfig
+		wantErr    string
+	}{
+		{name: "disabled ignores fields", escalation: EscalationConfig{Rules: []EscalationRuleConfig{{Severity: "bogus"}}}},
+		{name: "valid", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m", Rules: []EscalationRuleConfig{
+			validRule,
+			{Severity: "critical", After: "1h", ChannelID: "ch-leads"},
+		}}},
+		{name: "no rules", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m"}, wantErr: "at least one rule"},
+		{name: "bad check interval", escalation: EscalationConfig{Enabled: true, CheckInterval: "often", Rules: []EscalationRuleConfig{validRule}}, wantErr: "invalid escalation.check_interval"},
+		{name: "check interval too short", escalation: EscalationConfig{Enabled: true, CheckInterval: "1s", Rules: []EscalationRuleConfig{validRule}}, wantErr: "at least 10s"},
+		{name: "unknown severity", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m", Rules: []EscalationRuleConfig{{Severity: "urgent", After: "15m", Mention: "@x"}}}, wantErr: "escalation.rules[0]"},
+		{name: "bad after", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m", Rules: []EscalationRuleConfig{{Severity: "high", After: "soon", Mention: "@x"}}}, wantErr: "invalid escalation.rules[0].after"},
+		{name: "after too short", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m", Rules: []EscalationRuleConfig{{Severity: "high", After: "30s", Mention: "@x"}}}, wantErr: "at least 1m"},
+		{name: "no target", escalation: EscalationConfig{Enabled: true, CheckInterval: "1m", Rules: []EscalationRuleConfig{{Severity: "high", After: "15m"}}}, wantErr: "needs a mention or a channel_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Escalation: tt.escalation}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestEscalationRules(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Equal(t, time.Minute, cfg.EscalationCheckInterval())
+	assert.Empty(t, cfg.EscalationRules())
+
+	cfg.Escalation.Rules = []EscalationRuleConfig{
+		{Severity: "Critical", After: "15m", Mention: "@sre-oncall"},
+		{Severity: "high", After: "1h", ChannelID: "ch-leads"},
+	}
+	assert.Equal(t, []port.EscalationRule{
+		{Severity: "critical", After: 15 * time.Minute, Mention: "@sre-oncall"},
+		{Severity: "high", After: time.Hour, ChannelID: "ch-leads"},
+	}, cfg.EscalationRules())
+}
+
+func TestValidateReactions(t *testing.T) {
+	tests := []struct {
+		name      string
+		reactions ReactionsConfig
+		wantErr   string
+	}{
+		{name: "disabled ignores fields", reactions: ReactionsConfig{Interval: "often"}},
+		{name: "valid", reactions: ReactionsConfig{Enabled: true, Interval: "1m", Emojis: []string{"+1"}}},
+		{name: "bad interval", reactions: ReactionsConfig{Enabled: true, Interval: "often"}, wantErr: "invalid reactions.interval"},
+		{name: "interval too short", reactions: ReactionsConfig{Enabled: true, Interval: "10s"}, wantErr: "at least 30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Reactions: tt.reactions}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestReactionsDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Reactions.Enabled)
+	assert.Equal(t, 5*time.Minute, cfg.ReactionsInterval())
+}
+
+func TestValidateUserAutoMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoMapping UserAutoMappingConfig
+		wantErr     string
+	}{
+		{name: "disabled ignores fields", autoMapping: UserAutoMappingConfig{RefreshInterval: "often"}},
+		{name: "valid", autoMapping: UserAutoMappingConfig{Enabled: true, RefreshInterval: "30m", CacheTTL: "12h"}},
+		{name: "bad interval", autoMapping: UserAutoMappingConfig{Enabled: true, RefreshInterval: "often", CacheTTL: "1h"}, wantErr: "invalid users.auto_mapping.refresh_interval"},
+		{name: "interval too short", autoMapping: UserAutoMappingConfig{Enabled: true, RefreshInterval: "10s", CacheTTL: "1h"}, wantErr: "at least 1m"},
+		{name: "bad cache ttl", autoMapping: UserAutoMappingConfig{Enabled: true, RefreshInterval: "1h", CacheTTL: "long"}, wantErr: "invalid users.auto_mapping.cache_ttl"},
+		{name: "cache ttl below interval", autoMapping: UserAutoMappingConfig{Enabled: true, RefreshInterval: "1h", CacheTTL: "30m"}, wantErr: "at least refresh_interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Users: UsersConfig{AutoMapping: tt.autoMapping}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestUserAutoMappingDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Users.AutoMapping.Enabled)
+	assert.Equal(t, time.Hour, cfg.UserAutoMappingRefreshInterval())
+	assert.Equal(t, 24*time.Hour, cfg.UserAutoMappingCacheTTL())
+}
+
+func TestValidateCopyCommands(t *testing.T) {
+	cfg := &FileConfig{CopyCommands: CopyCommandsConfig{Commands: []CopyCommandConfig{{Name: "empty"}}}}
+	assert.NoError(t, cfg.Validate(), "disabled ignores commands")
+
+	cfg.CopyCommands.Enabled = true
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "copy_commands.commands[0] needs both name and template")
+}
+
+func TestCopyCommandDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.ShowFingerprintField())
+	assert.Nil(t, cfg.CopyCommandTemplates(), "disabled by default")
+
+	cfg.CopyCommands.Enabled = true
+	commands := cfg.CopyCommandTemplates()
+	require.Len(t, commands, 3)
+	assert.Equal(t, "Keep API", commands[0].Name)
+	assert.Contains(t, commands[1].Template, ".Labels.namespace")
+	assert.Equal(t, "/keep silence {{.Fingerprint}} 1h", commands[2].Template)
+}
+
+func TestValidateAssigneeRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   AssigneeRetryConfig
+		wantErr string
+	}{
+		{name: "unset uses defaults", retry: AssigneeRetryConfig{}},
+		{name: "valid", retry: AssigneeRetryConfig{Attempts: 6, InitialDelay: "250ms", Multiplier: 1.5, MaxDelay: "1s", Jitter: 0.2, Deadline: "5s"}},
+		{name: "too many attempts", retry: AssigneeRetryConfig{Attempts: 50}, wantErr: "assignee_retry.attempts"},
+		{name: "negative attempts", retry: AssigneeRetryConfig{Attempts: -1}, wantErr: "assignee_retry.attempts"},
+		{name: "multiplier below one", retry: AssigneeRetryConfig{Multiplier: 0.5}, wantErr: "assignee_retry.multiplier"},
+		{name: "jitter above one", retry: AssigneeRetryConfig{Jitter: 1.5}, wantErr: "assignee_retry.jitter"},
+		{name: "negative jitter", retry: AssigneeRetryConfig{Jitter: -0.1}, wantErr: "assignee_retry.jitter"},
+		{name: "bad initial delay", retry: AssigneeRetryConfig{InitialDelay: "soon"}, wantErr: "invalid assignee_retry.initial_delay"},
+		{name: "zero deadline", retry: AssigneeRetryConfig{Deadline: "0s"}, wantErr: "assignee_retry.deadline must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{AssigneeRetry: tt.retry}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAssigneeRetryDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+
+	assert.Equal(t, 4, cfg.AssigneeRetry.Attempts)
+	assert.Equal(t, 2.0, cfg.AssigneeRetry.Multiplier)
+	assert.Zero(t, cfg.AssigneeRetry.Jitter)
+	assert.Equal(t, 100*time.Millisecond, cfg.AssigneeRetryInitialDelay())
+	assert.Equal(t, 2*time.Second, cfg.AssigneeRetryMaxDelay())
+	assert.Equal(t, 2*time.Second, cfg.AssigneeRetryDeadline())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  BudgetConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", budget: BudgetConfig{PostsPerHour: -1, ReleaseInterval: "often"}},
+		{name: "valid", budget: BudgetConfig{Enabled: true, PostsPerHour: 20, Channels: map[string]int{"ch-1": 5}, ReleaseInterval: "30s"}},
+		{name: "no posts", budget: BudgetConfig{Enabled: true, ReleaseInterval: "1m"}, wantErr: "budget.posts_per_hour must be at least 1"},
+		{name: "bad channel limit", budget: BudgetConfig{Enabled: true, PostsPerHour: 20, Channels: map[string]int{"ch-1": 0}, ReleaseInterval: "1m"}, wantErr: "budget.channels.ch-1"},
+		{name: "bad release interval", budget: BudgetConfig{Enabled: true, PostsPerHour: 20, ReleaseInterval: "often"}, wantErr: "invalid budget.release_interval"},
+		{name: "release interval too short", budget: BudgetConfig{Enabled: true, PostsPerHour: 20, ReleaseInterval: "1s"}, wantErr: "at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Budget: tt.budget}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBudgetDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Budget.Enabled)
+	assert.Zero(t, cfg.BudgetPostsPerHour("ch-1"), "unlimited while disabled")
+	assert.Equal(t, time.Minute, cfg.BudgetReleaseInterval())
+
+	cfg.Budget.Enabled = true
+	cfg.Budget.Channels = map[string]int{"ch-2": 5}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 30, cfg.BudgetPostsPerHour("ch-1"))
+	assert.Equal(t, 5, cfg.BudgetPostsPerHour("ch-2"))
+}
+
+func TestValidateRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention RetentionConfig
+		wantErr   string
+	}{
+		{name: "disabled ignores fields", retention: RetentionConfig{Action: "shred", Delay: "soon"}},
+		{name: "valid collapse", retention: RetentionConfig{Enabled: true, Action: "collapse", Delay: "12h", CheckInterval: "30s"}},
+		{name: "valid archive", retention: RetentionConfig{Enabled: true, Action: "archive", ArchiveChannelID: "archive-ch", Delay: "1h", CheckInterval: "1m"}},
+		{name: "bad action", retention: RetentionConfig{Enabled: true, Action: "shred", Delay: "1h", CheckInterval: "1m"}, wantErr: "invalid retention.action"},
+		{name: "archive without channel", retention: RetentionConfig{Enabled: true, Action: "archive", Delay: "1h", CheckInterval: "1m"}, wantErr: "retention.archive_channel_id is required"},
+		{name: "bad delay", retention: RetentionConfig{Enabled: true, Action: "delete", Delay: "soon", CheckInterval: "1m"}, wantErr: "invalid retention.delay"},
+		{name: "delay too short", retention: RetentionConfig{Enabled: true, Action: "delete", Delay: "10s", CheckInterval: "1m"}, wantErr: "at least 1m"},
+		{name: "check interval too short", retention: RetentionConfig{Enabled: true, Action: "delete", Delay: "1h", CheckInterval: "1s"}, wantErr: "at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Retention: tt.retention}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRetentionDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Retention.Enabled)
+	assert.Equal(t, "collapse", cfg.Retention.Action)
+	assert.Equal(t, 24*time.Hour, cfg.RetentionDelay())
+	assert.Equal(t, time.Minute, cfg.RetentionCheckInterval())
+
+	cfg.Retention.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidatePostTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		postTTL PostTTLConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", postTTL: PostTTLConfig{Default: "forever"}},
+		{name: "valid", postTTL: PostTTLConfig{Enabled: true, Default: "168h", Severities: map[string]string{"info": "24h", "Critical": "0"}, CheckInterval: "1m"}},
+		{name: "never by default", postTTL: PostTTLConfig{Enabled: true, Default: "0", CheckInterval: "1m"}},
+		{name: "bad default", postTTL: PostTTLConfig{Enabled: true, Default: "forever", CheckInterval: "1m"}, wantErr: "invalid post_ttl.default"},
+		{name: "default too short", postTTL: PostTTLConfig{Enabled: true, Default: "30s", CheckInterval: "1m"}, wantErr: "post_ttl.default must be 0 or at least 1m"},
+		{name: "unknown severity", postTTL: PostTTLConfig{Enabled: true, Default: "168h", Severities: map[string]string{"urgent": "1h"}, CheckInterval: "1m"}, wantErr: "post_ttl.severities"},
+		{name: "bad severity ttl", postTTL: PostTTLConfig{Enabled: true, Default: "168h", Severities: map[string]string{"info": "-1h"}, CheckInterval: "1m"}, wantErr: "post_ttl.severities.info must be 0 or at least 1m"},
+		{name: "check interval too short", postTTL: PostTTLConfig{Enabled: true, Default: "168h", CheckInterval: "1s"}, wantErr: "at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{PostTTL: tt.postTTL}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPostTTLDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.PostTTL.Enabled)
+	assert.Equal(t, 7*24*time.Hour, cfg.PostTTLDefault())
+	assert.Equal(t, time.Minute, cfg.PostTTLCheckInterval())
+	assert.Empty(t, cfg.PostTTLSeverities())
+
+	cfg.PostTTL.Enabled = true
+	cfg.PostTTL.Severities = map[string]string{"Info": "24h", "critical": "0"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, map[string]time.Duration{"info": 24 * time.Hour, "critical": 0}, cfg.PostTTLSeverities())
+}
+
+func TestValidateSLO(t *testing.T) {
+	valid := func() SLOConfig {
+		return SLOConfig{
+			Enabled:  true,
+			Webhook:  SLOObjectiveConfig{Target: 0.99, Threshold: "2s"},
+			Callback: SLOObjectiveConfig{Target: 0.999, Threshold: "500ms"},
+			Report:   SLOReportConfig{Interval: "24h"},
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(s *SLOConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(s *SLOConfig) {}},
+		{name: "disabled ignores fields", modify: func(s *SLOConfig) { s.Enabled = false; s.Webhook.Target = 2 }},
+		{name: "target of one", modify: func(s *SLOConfig) { s.Webhook.Target = 1 }, wantErr: "slo.webhook.target must be between 0 and 1"},
+		{name: "negative target", modify: func(s *SLOConfig) { s.Callback.Target = -0.5 }, wantErr: "slo.callback.target"},
+		{name: "bad threshold", modify: func(s *SLOConfig) { s.Callback.Threshold = "fast" }, wantErr: "invalid slo.callback.threshold"},
+		{name: "zero threshold", modify: func(s *SLOConfig) { s.Webhook.Threshold = "0s" }, wantErr: "slo.webhook.threshold must be positive"},
+		{name: "report interval too short", modify: func(s *SLOConfig) { s.Report.Interval = "5m" }, wantErr: "at least 1h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := valid()
+			tt.modify(&slo)
+			cfg := &FileConfig{SLO: slo}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSLODefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.SLO.Enabled)
+	assert.Equal(t, 0.99, cfg.SLO.Webhook.Target)
+	assert.Equal(t, 2*time.Second, cfg.SLOWebhookThreshold())
+	assert.Equal(t, 0.99, cfg.SLO.Callback.Target)
+	assert.Equal(t, time.Second, cfg.SLOCallbackThreshold())
+	assert.Equal(t, 7*24*time.Hour, cfg.SLOReportInterval())
+
+	cfg.SLO.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateDigest(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *FileConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(c *FileConfig) {}},
+		{name: "disabled ignores fields", modify: func(c *FileConfig) { c.Digest.Enabled = false; c.Digest.Schedule = "daily" }},
+		{name: "weekly descriptor", modify: func(c *FileConfig) { c.Digest.Schedule = "@weekly"; c.Digest.Period = "168h" }},
+		{name: "no channel", modify: func(c *FileConfig) { c.Digest.ChannelID = "" }, wantErr: "digest.channel_id is required"},
+		{name: "audit disabled", modify: func(c *FileConfig) { c.Audit.Enabled = false }, wantErr: "digest requires audit.enabled"},
+		{name: "bad schedule", modify: func(c *FileConfig) { c.Digest.Schedule = "0 25 * * *" }, wantErr: "invalid digest.schedule"},
+		{name: "bad timezone", modify: func(c *FileConfig) { c.Digest.Timezone = "Mars/Olympus" }, wantErr: "invalid digest.timezone"},
+		{name: "short period", modify: func(c *FileConfig) { c.Digest.Period = "30m" }, wantErr: "digest.period must be at least 1h"},
+		{name: "top too large", modify: func(c *FileConfig) { c.Digest.Top = 50 }, wantErr: "digest.top must be between 1 and 20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultFileConfig()
+			cfg.Audit.Enabled = true
+			cfg.Digest.Enabled = true
+			cfg.Digest.ChannelID = "digest-channel"
+			cfg.Digest.Timezone = "Europe/Berlin"
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDigestDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Digest.Enabled)
+	assert.Equal(t, "0 9 * * *", cfg.Digest.Schedule)
+	assert.Equal(t, 24*time.Hour, cfg.DigestPeriod())
+	assert.Equal(t, 5, cfg.Digest.Top)
+
+	schedule, err := cfg.DigestSchedule()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC), schedule.Next(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestValidateDeadLetter(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DeadLetterConfig
+		wantErr string
+	}{
+		{name: "valid", config: DeadLetterConfig{Enabled: true, RedeliverInterval: "30s", MaxAttempts: 3}},
+		{name: "disabled ignores fields", config: DeadLetterConfig{RedeliverInterval: "soon"}},
+		{name: "bad interval", config: DeadLetterConfig{Enabled: true, RedeliverInterval: "soon", MaxAttempts: 3}, wantErr: "invalid dead_letter.redeliver_interval"},
+		{name: "interval too short", config: DeadLetterConfig{Enabled: true, RedeliverInterval: "1s", MaxAttempts: 3}, wantErr: "at least 10s"},
+		{name: "no attempts", config: DeadLetterConfig{Enabled: true, RedeliverInterval: "1m", MaxAttempts: -1}, wantErr: "dead_letter.max_attempts must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{DeadLetter: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateErrorPosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ErrorPostsConfig
+		wantErr string
+	}{
+		{name: "valid", config: ErrorPostsConfig{Enabled: true, ChannelID: "ops", RepeatInterval: "30m"}},
+		{name: "disabled ignores fields", config: ErrorPostsConfig{RepeatInterval: "soon"}},
+		{name: "no channel", config: ErrorPostsConfig{Enabled: true, RepeatInterval: "1h"}, wantErr: "error_posts.channel_id is required"},
+		{name: "bad interval", config: ErrorPostsConfig{Enabled: true, ChannelID: "ops", RepeatInterval: "soon"}, wantErr: "invalid error_posts.repeat_interval"},
+		{name: "zero interval", config: ErrorPostsConfig{Enabled: true, ChannelID: "ops", RepeatInterval: "0s"}, wantErr: "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{ErrorPosts: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateDeletedPosts(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     FilePollingConfig
+		wantAction string
+		wantErr    string
+	}{
+		{name: "not checked", config: FilePollingConfig{}},
+		{name: "recreate", config: FilePollingConfig{DeletedPosts: "recreate"}, wantAction: "recreate"},
+		{name: "former spelling", config: FilePollingConfig{RecreateDeletedPosts: true}, wantAction: "recreate"},
+		{name: "untrack", config: FilePollingConfig{DeletedPosts: "untrack"}, wantAction: "untrack"},
+		{name: "resolve", config: FilePollingConfig{DeletedPosts: "resolve"}, wantAction: "resolve"},
+		{name: "both spellings agree", config: FilePollingConfig{DeletedPosts: "recreate", RecreateDeletedPosts: true}, wantAction: "recreate"},
+		{name: "both spellings differ", config: FilePollingConfig{DeletedPosts: "resolve", RecreateDeletedPosts: true}, wantErr: "polling.recreate_deleted_posts contradicts"},
+		{name: "unknown", config: FilePollingConfig{DeletedPosts: "ignore"}, wantErr: `invalid polling.deleted_posts "ignore"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Polling: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantAction, cfg.DeletedPostsAction())
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateCallbackWatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CallbackWatchConfig
+		wantErr string
+	}{
+		{name: "valid", config: CallbackWatchConfig{Enabled: true, Timeout: "1m", Retention: "2h"}},
+		{name: "disabled ignores fields", config: CallbackWatchConfig{Timeout: "soon"}},
+		{name: "bad timeout", config: CallbackWatchConfig{Enabled: true, Timeout: "soon", Retention: "1h"}, wantErr: "invalid callback_watchdog.timeout"},
+		{name: "short timeout", config: CallbackWatchConfig{Enabled: true, Timeout: "1s", Retention: "1h"}, wantErr: "timeout must be at least 5s"},
+		{name: "short retention", config: CallbackWatchConfig{Enabled: true, Timeout: "45s", Retention: "10s"}, wantErr: "retention must be at least 1m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{CallbackWatch: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	cfg := DefaultFileConfig()
+	assert.Equal(t, 45*time.Second, cfg.CallbackWatchTimeout())
+	assert.Equal(t, time.Hour, cfg.CallbackWatchRetention())
+}
+
+func TestDeadLetterDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.DeadLetter.Enabled)
+	assert.Equal(t, time.Minute, cfg.DeadLetterRedeliverInterval())
+	assert.Equal(t, 10, cfg.DeadLetter.MaxAttempts)
+
+	cfg.DeadLetter.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AuditConfig
+		wantErr string
+	}{
+		{name: "valid", config: AuditConfig{Enabled: true, MaxEvents: 50, Retention: "168h"}},
+		{name: "disabled ignores fields", config: AuditConfig{Retention: "forever"}},
+		{name: "no events", config: AuditConfig{Enabled: true, MaxEvents: -1, Retention: "168h"}, wantErr: "audit.max_events must be between 1 and 10000"},
+		{name: "too many events", config: AuditConfig{Enabled: true, MaxEvents: 10001, Retention: "168h"}, wantErr: "audit.max_events must be between 1 and 10000"},
+		{name: "bad retention", config: AuditConfig{Enabled: true, MaxEvents: 50, Retention: "forever"}, wantErr: "invalid audit.retention"},
+		{name: "retention too short", config: AuditConfig{Enabled: true, MaxEvents: 50, Retention: "10m"}, wantErr: "at least 1h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Audit: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAuditDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Audit.Enabled)
+	assert.Equal(t, 100, cfg.Audit.MaxEvents)
+	assert.Equal(t, 30*24*time.Hour, cfg.AuditRetention())
+
+	cfg.Audit.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateAPIRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   APIRetryConfig
+		wantErr string
+	}{
+		{name: "unset uses defaults", retry: APIRetryConfig{}},
+		{name: "valid", retry: APIRetryConfig{Attempts: 5, InitialDelay: "100ms", Multiplier: 3, MaxDelay: "10s", Jitter: 0.2}},
+		{name: "retries disabled", retry: APIRetryConfig{Attempts: 1}},
+		{name: "too many attempts", retry: APIRetryConfig{Attempts: 11}, wantErr: "api_retry.attempts"},
+		{name: "multiplier below one", retry: APIRetryConfig{Multiplier: 0.5}, wantErr: "api_retry.multiplier"},
+		{name: "jitter above one", retry: APIRetryConfig{Jitter: 2}, wantErr: "api_retry.jitter"},
+		{name: "bad max delay", retry: APIRetryConfig{MaxDelay: "later"}, wantErr: "invalid api_retry.max_delay"},
+		{name: "zero initial delay", retry: APIRetryConfig{InitialDelay: "0s"}, wantErr: "api_retry.initial_delay must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{APIRetry: tt.retry}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAPIRetryDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+
+	assert.Equal(t, 3, cfg.APIRetry.Attempts)
+	assert.Equal(t, 2.0, cfg.APIRetry.Multiplier)
+	assert.Zero(t, cfg.APIRetry.Jitter)
+	assert.Equal(t, 200*time.Millisecond, cfg.APIRetryInitialDelay())
+	assert.Equal(t, 5*time.Second, cfg.APIRetryMaxDelay())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateRateLimitAndUpdateCoalescing(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    RateLimitConfig
+		coalesce UpdateCoalesceConfig
+		wantErr  string
+	}{
+		{name: "disabled", limit: RateLimitConfig{RequestsPerSecond: -1}, coalesce: UpdateCoalesceConfig{Window: "later"}},
+		{name: "valid", limit: RateLimitConfig{Enabled: true, RequestsPerSecond: 0.5, Burst: 1}, coalesce: UpdateCoalesceConfig{Enabled: true, Window: "500ms"}},
+		{name: "negative rate", limit: RateLimitConfig{Enabled: true, RequestsPerSecond: -1, Burst: 1}, wantErr: "rate_limit.requests_per_second"},
+		{name: "negative burst", limit: RateLimitConfig{Enabled: true, RequestsPerSecond: 1, Burst: -1}, wantErr: "rate_limit.burst"},
+		{name: "bad window", coalesce: UpdateCoalesceConfig{Enabled: true, Window: "later"}, wantErr: "invalid update_coalescing.window"},
+		{name: "window too long", coalesce: UpdateCoalesceConfig{Enabled: true, Window: "5m"}, wantErr: "update_coalescing.window must be between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{RateLimit: tt.limit, UpdateCoalesce: tt.coalesce}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRateLimitAndUpdateCoalescingDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+
+	assert.False(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, 10.0, cfg.RateLimit.RequestsPerSecond)
+	assert.Equal(t, 20, cfg.RateLimit.Burst)
+	assert.False(t, cfg.UpdateCoalesce.Enabled)
+	assert.Equal(t, time.Second, cfg.UpdateCoalesceWindow())
+
+	cfg.RateLimit.Enabled = true
+	cfg.UpdateCoalesce.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestKeepBreakerConfig(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.KeepBreaker.Enabled)
+	assert.Equal(t, 5, cfg.KeepBreaker.Failures)
+	assert.Equal(t, 30*time.Second, cfg.KeepBreakerOpenFor())
+	assert.Equal(t, 100, cfg.KeepBreaker.QueueSize)
+	cfg.KeepBreaker.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	tests := []struct {
+		name    string
+		breaker KeepBreakerConfig
+		wantErr string
+	}{
+		{name: "no failures", breaker: KeepBreakerConfig{Enabled: true, Failures: -1, OpenFor: "30s", QueueSize: 1}, wantErr: "keep_circuit_breaker.failures"},
+		{name: "bad open_for", breaker: KeepBreakerConfig{Enabled: true, Failures: 1, OpenFor: "soon", QueueSize: 1}, wantErr: "invalid keep_circuit_breaker.open_for"},
+		{name: "negative open_for", breaker: KeepBreakerConfig{Enabled: true, Failures: 1, OpenFor: "-1s", QueueSize: 1}, wantErr: "keep_circuit_breaker.open_for must be positive"},
+		{name: "no queue", breaker: KeepBreakerConfig{Enabled: true, Failures: 1, OpenFor: "30s", QueueSize: -1}, wantErr: "keep_circuit_breaker.queue_size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{KeepBreaker: tt.breaker}
+			assert.ErrorContains(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestSeverityMapConfig(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Nil(t, cfg.SeverityNormalization())
+
+	cfg.SeverityMap = map[string]string{"sev1": "critical", "P2": "High"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "high", cfg.SeverityNormalization().Normalize("p2"))
+
+	tests := []struct {
+		name    string
+		m       map[string]string
+		wantErr string
+	}{
+		{name: "unknown target", m: map[string]string{"sev1": "urgent"}, wantErr: `severity_map["sev1"]`},
+		{name: "empty input", m: map[string]string{" ": "low"}, wantErr: "empty input severity"},
+		{name: "duplicate input", m: map[string]string{"P1": "critical", "p1": "high"}, wantErr: "same input severity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{SeverityMap: tt.m}
+			assert.ErrorContains(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestAcceptedSeveritiesConfig(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Equal(t, []string{"critical", "high", "warning", "info", "low"}, cfg.AcceptedSeverities)
+	assert.Equal(t, "#B0B0B0", cfg.ColorForSeverity("debug"))
+	assert.Equal(t, "🐞", cfg.EmojiForSeverity("debug"))
+
+	cfg.AcceptedSeverities = append(cfg.AcceptedSeverities, "debug")
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AcceptedSeverities = []string{"trace"}
+	assert.ErrorContains(t, cfg.Validate(), "accepted_severities")
+	cfg.AcceptedSeverities = []string{}
+	assert.ErrorContains(t, cfg.Validate(), "at least one severity")
+}
+
+func TestOfflineActionsConfig(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Equal(t, 30*time.Second, cfg.OfflineActionsReplayInterval())
+	assert.Equal(t, 24*time.Hour, cfg.OfflineActionsMaxAge())
+	cfg.OfflineActions.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	tests := []struct {
+		name    string
+		actions OfflineActionsConfig
+		wantErr string
+	}{
+		{name: "bad interval", actions: OfflineActionsConfig{Enabled: true, ReplayInterval: "often", MaxAge: "1h"}, wantErr: "invalid offline_actions.replay_interval"},
+		{name: "short interval", actions: OfflineActionsConfig{Enabled: true, ReplayInterval: "1s", MaxAge: "1h"}, wantErr: "at least 5s"},
+		{name: "bad max_age", actions: OfflineActionsConfig{Enabled: true, ReplayInterval: "30s", MaxAge: "forever"}, wantErr: "invalid offline_actions.max_age"},
+		{name: "max_age below interval", actions: OfflineActionsConfig{Enabled: true, ReplayInterval: "1m", MaxAge: "30s"}, wantErr: "offline_actions.max_age must be at least replay_interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{OfflineActions: tt.actions}
+			assert.ErrorContains(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidatePermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		perms   PermissionsConfig
+		wantErr string
+	}{
+		{name: "disabled", perms: PermissionsConfig{}},
+		{name: "valid", perms: PermissionsConfig{Enabled: true, Rules: []PermissionRuleConfig{
+			{Teams: []string{"team-ops"}},
+			{Actions: []string{"Resolve"}, Severities: []string{"critical"}, Groups: []string{"@sre"}},
+			{Actions: []string{"assign"}, Users: []string{"alice"}},
+		}}},
+		{name: "no rules", perms: PermissionsConfig{Enabled: true}, wantErr: "at least one rule"},
+		{name: "unknown action", perms: PermissionsConfig{Enabled: true, Rules: []PermissionRuleConfig{{Actions: []string{"delete"}, Users: []string{"alice"}}}}, wantErr: `unknown action "delete"`},
+		{name: "unknown severity", perms: PermissionsConfig{Enabled: true, Rules: []PermissionRuleConfig{{Severities: []string{"urgent"}, Users: []string{"alice"}}}}, wantErr: "permissions.rules[0]"},
+		{name: "nobody admitted", perms: PermissionsConfig{Enabled: true, Rules: []PermissionRuleConfig{{Actions: []string{"resolve"}}}}, wantErr: "needs users, teams, channels or groups"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Permissions: tt.perms}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPermissionRules(t *testing.T) {
+	cfg := &FileConfig{Permissions: PermissionsConfig{Enabled: true, Rules: []PermissionRuleConfig{
+		{Actions: []string{"Resolve"}, Severities: []string{"CRITICAL"}, Users: []string{"@alice"}, Groups: []string{"@sre"}, Teams: []string{"team-ops"}},
+	}}}
+
+	assert.Equal(t, []port.PermissionRule{{
+		Actions:    []string{"resolve"},
+		Severities: []string{"critical"},
+		Users:      []string{"alice"},
+		Teams:      []string{"team-ops"},
+		Channels:   nil,
+		Groups:     []string{"sre"},
+	}}, cfg.PermissionRules())
+}
+
+func TestValidateIngestQueue(t *testing.T) {
+	valid := IngestQueueConfig{Enabled: true, Size: 1000, Workers: 4, Attempts: 3, InitialDelay: "1s", MaxDelay: "30s"}
+	tests := []struct {
+		name    string
+		mutate  func(q *IngestQueueConfig)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(q *IngestQueueConfig) {}},
+		{name: "disabled ignores fields", mutate: func(q *IngestQueueConfig) { q.Enabled = false; q.Size = -1 }},
+		{name: "size too large", mutate: func(q *IngestQueueConfig) { q.Size = 200000 }, wantErr: "ingest_queue.size must be between 1 and 100000"},
+		{name: "no workers", mutate: func(q *IngestQueueConfig) { q.Workers = -1 }, wantErr: "ingest_queue.workers must be between 1 and 64"},
+		{name: "too many attempts", mutate: func(q *IngestQueueConfig) { q.Attempts = 11 }, wantErr: "ingest_queue.attempts must be between 1 and 10"},
+		{name: "bad delay", mutate: func(q *IngestQueueConfig) { q.InitialDelay = "soon" }, wantErr: "invalid ingest_queue.initial_delay"},
+		{name: "negative delay", mutate: func(q *IngestQueueConfig) { q.MaxDelay = "-1s" }, wantErr: "ingest_queue.max_delay must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := valid
+			tt.mutate(&q)
+			cfg := &FileConfig{IngestQueue: q}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestIngestQueueDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.IngestQueue.Enabled)
+	assert.Equal(t, 1000, cfg.IngestQueue.Size)
+	assert.Equal(t, 4, cfg.IngestQueue.Workers)
+	assert.Equal(t, 3, cfg.IngestQueue.Attempts)
+	assert.Equal(t, time.Second, cfg.IngestQueueInitialDelay())
+	assert.Equal(t, 30*time.Second, cfg.IngestQueueMaxDelay())
+
+	cfg.IngestQueue.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateIngestStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  IngestStreamConfig
+		wantErr string
+	}{
+		{name: "valid", config: IngestStreamConfig{MaxLen: 1000, Batch: 10, ClaimIdle: "30s", MaxDeliveries: 5}},
+		{name: "negative max len", config: IngestStreamConfig{MaxLen: -1}, wantErr: "ingest_stream.max_len must not be negative"},
+		{name: "batch too large", config: IngestStreamConfig{Batch: 5000}, wantErr: "ingest_stream.batch must be between 1 and 1000"},
+		{name: "bad claim idle", config: IngestStreamConfig{ClaimIdle: "soon"}, wantErr: "invalid ingest_stream.claim_idle"},
+		{name: "claim idle too short", config: IngestStreamConfig{ClaimIdle: "1s"}, wantErr: "at least 5s"},
+		{name: "too many deliveries", config: IngestStreamConfig{MaxDeliveries: 500}, wantErr: "ingest_stream.max_deliveries must be between 1 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{IngestStream: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestIngestStreamDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Equal(t, int64(100000), cfg.IngestStream.MaxLen)
+	assert.Equal(t, 10, cfg.IngestStream.Batch)
+	assert.Equal(t, time.Minute, cfg.IngestStreamClaimIdle())
+	assert.Equal(t, 5, cfg.IngestStream.MaxDeliveries)
+}
+
+func TestValidateLocking(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LockingConfig
+		wantErr string
+	}{
+		{name: "valid", config: LockingConfig{Enabled: true, TTL: "1m", IdempotencyTTL: "1h"}},
+		{name: "disabled ignores fields", config: LockingConfig{TTL: "soon"}},
+		{name: "bad ttl", config: LockingConfig{Enabled: true, TTL: "soon", IdempotencyTTL: "10m"}, wantErr: "invalid locking.ttl"},
+		{name: "ttl too short", config: LockingConfig{Enabled: true, TTL: "5s", IdempotencyTTL: "10m"}, wantErr: "locking.ttl must be at least 30s"},
+		{name: "idempotency ttl too short", config: LockingConfig{Enabled: true, TTL: "45s", IdempotencyTTL: "10s"}, wantErr: "locking.idempotency_ttl must be at least 1m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Locking: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLockingDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.Locking.Enabled)
+	assert.Equal(t, 45*time.Second, cfg.LockingTTL())
+	assert.Equal(t, 10*time.Minute, cfg.LockingIdempotencyTTL())
+
+	cfg.Locking.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateWorkflowMenu(t *testing.T) {
+	tests := []struct {
+		name    string
+		menu    WorkflowMenuConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", menu: WorkflowMenuConfig{RefreshInterval: "often"}},
+		{name: "valid", menu: WorkflowMenuConfig{Enabled: true, Workflows: []string{"restart-pod"}, RefreshInterval: "5m", ResultTimeout: "2m"}},
+		{name: "empty workflow", menu: WorkflowMenuConfig{Enabled: true, Workflows: []string{" "}, RefreshInterval: "5m", ResultTimeout: "2m"}, wantErr: "workflow_menu.workflows[0] must not be empty"},
+		{name: "bad refresh interval", menu: WorkflowMenuConfig{Enabled: true, RefreshInterval: "often", ResultTimeout: "2m"}, wantErr: "invalid workflow_menu.refresh_interval"},
+		{name: "refresh interval too short", menu: WorkflowMenuConfig{Enabled: true, RefreshInterval: "1s", ResultTimeout: "2m"}, wantErr: "at least 10s"},
+		{name: "bad result timeout", menu: WorkflowMenuConfig{Enabled: true, RefreshInterval: "5m", ResultTimeout: "0s"}, wantErr: "workflow_menu.result_timeout must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{WorkflowMenu: tt.menu}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWorkflowMenuDefaults(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.False(t, cfg.WorkflowMenu.Enabled)
+	assert.Equal(t, 5*time.Minute, cfg.WorkflowMenuRefreshInterval())
+	assert.Equal(t, 2*time.Minute, cfg.WorkflowMenuResultTimeout())
+
+	cfg.WorkflowMenu.Enabled = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateEnrichmentFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  EnrichmentFieldsConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", config: EnrichmentFieldsConfig{Fields: []EnrichmentFieldConfig{{}}}},
+		{name: "valid", config: EnrichmentFieldsConfig{Enabled: true, Fields: []EnrichmentFieldConfig{{Key: "ai_summary", Title: "AI summary"}, {Key: "notes", Short: true}}}},
+		{name: "no fields", config: EnrichmentFieldsConfig{Enabled: true}, wantErr: "enrichment_fields.fields must list at least one field"},
+		{name: "no key", config: EnrichmentFieldsConfig{Enabled: true, Fields: []EnrichmentFieldConfig{{Title: "Notes"}}}, wantErr: "enrichment_fields.fields[0].key is required"},
+		{name: "duplicate key", config: EnrichmentFieldsConfig{Enabled: true, Fields: []EnrichmentFieldConfig{{Key: "notes"}, {Key: "notes"}}}, wantErr: `enrichment_fields.fields[1]: duplicate key "notes"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Enrichments: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestEnrichmentFields(t *testing.T) {
+	cfg := &FileConfig{Enrichments: EnrichmentFieldsConfig{
+		Enabled: true,
+		Fields:  []EnrichmentFieldConfig{{Key: "ai_summary", Title: "AI summary"}, {Key: "notes", Short: true}},
+	}}
+	assert.Equal(t, []port.EnrichmentField{
+		{Key: "ai_summary", Title: "AI summary"},
+		{Key: "notes", Title: "notes", Short: true},
+	}, cfg.EnrichmentFields())
+	assert.Equal(t, []string{"ai_summary", "notes"}, cfg.EnrichmentKeys())
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AnnotationsConfig
+		wantErr string
+	}{
+		{name: "disabled ignores display", config: AnnotationsConfig{Display: []string{""}}},
+		{name: "valid", config: AnnotationsConfig{Enabled: true, Display: []string{"summary", "runbook_url"}, Rename: map[string]string{"runbook_url": "Runbook"}}},
+		{name: "empty display entry", config: AnnotationsConfig{Enabled: true, Display: []string{" "}}, wantErr: "annotations.display[0] must not be empty"},
+		{name: "duplicate display entry", config: AnnotationsConfig{Enabled: true, Display: []string{"summary", "summary"}}, wantErr: `annotations.display[1]: duplicate annotation "summary"`},
+		{name: "empty title", config: AnnotationsConfig{Enabled: true, Rename: map[string]string{"summary": ""}}, wantErr: "annotations.rename.summary must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Annotations: tt.config}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSourceLinksConfig(t *testing.T) {
+	cfg := &FileConfig{SourceLinks: SourceLinksConfig{Enabled: true, Sources: []string{" prometheus ", "grafana"}}}
+	cfg.applyDefaults()
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, port.SourceLinks{Sources: []string{"prometheus", "grafana"}, Annotations: []string{"generatorURL", "generator_url"}}, cfg.SourceLinkFields())
+
+	cfg.SourceLinks.Sources = []string{"prometheus", " "}
+	assert.ErrorContains(t, cfg.Validate(), "source_links.sources[1] must not be empty")
+	cfg.SourceLinks.Sources = nil
+	cfg.SourceLinks.Annotations = []string{""}
+	assert.ErrorContains(t, cfg.Validate(), "source_links.annotations[0] must not be empty")
+}
+
+func TestShownAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"summary":     "Disk almost full",
+		"description": "Disk above 90%",
+		"runbook_url": "https://runbooks/disk",
+		"dashboard":   " ",
+	}
+
+	cfg := &FileConfig{Annotations: AnnotationsConfig{Enabled: true, Rename: map[string]string{"runbook_url": "Runbook"}}}
+	cfg.applyDefaults()
+	assert.Equal(t, []string{"runbook_url", "summary"}, cfg.ShownAnnotations(annotations), "all but description and blank ones, sorted")
+	assert.Equal(t, "Runbook", cfg.AnnotationTitle("runbook_url"))
+	assert.Equal(t, "summary", cfg.AnnotationTitle("summary"))
+
+	cfg.Annotations.Display = []string{"summary", "missing", "description", "runbook_url"}
+	assert.Equal(t, []string{"summary", "runbook_url"}, cfg.ShownAnnotations(annotations), "display order, still without excluded ones")
+
+	cfg.Annotations.Exclude = []string{}
+	assert.Equal(t, []string{"summary", "description", "runbook_url"}, cfg.ShownAnnotations(annotations))
+}
+
+func TestCompactSeverities(t *testing.T) {
+	cfg := &FileConfig{Message: MessageConfig{Style: map[string]string{"warning": "compact", "Info": "COMPACT", "critical": "full"}}}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, []string{"info", "warning"}, cfg.CompactSeverities())
+	assert.Empty(t, (&FileConfig{}).CompactSeverities())
+
+	cfg = &FileConfig{Message: MessageConfig{Style: map[string]string{"info": "tiny"}}}
+	assert.ErrorContains(t, cfg.Validate(), `invalid message.style.info "tiny"`)
+
+	cfg = &FileConfig{Message: MessageConfig{Style: map[string]string{"urgent": "compact"}}}
+	assert.ErrorContains(t, cfg.Validate(), "message.style: invalid severity")
+
+	cfg = &FileConfig{Message: MessageConfig{Profiles: []MessageProfileConfig{{Name: "exec", Style: map[string]string{"info": "tiny"}}}}}
+	assert.ErrorContains(t, cfg.Validate(), `invalid message.profiles[0].style.info "tiny"`)
+}
+
+func TestBotIdentity(t *testing.T) {
+	cfg := &FileConfig{Message: MessageConfig{Bot: BotConfig{
+		Username: "Alerts",
+		IconURL:  "https://example.com/bot.png",
+		Severity: map[string]BotIdentityConfig{"Critical": {IconURL: "https://example.com/red.png"}},
+	}}}
+	require.NoError(t, cfg.Validate())
+
+	username, iconURL := cfg.BotIdentity("critical")
+	assert.Equal(t, "Alerts", username, "severity overrides fall back to the default")
+	assert.Equal(t, "https://example.com/red.png", iconURL)
+
+	username, iconURL = cfg.BotIdentity("warning")
+	assert.Equal(t, "Alerts", username)
+	assert.Equal(t, "https://example.com/bot.png", iconURL)
+
+	cfg = &FileConfig{Message: MessageConfig{Bot: BotConfig{Severity: map[string]BotIdentityConfig{"urgent": {Username: "x"}}}}}
+	assert.ErrorContains(t, cfg.Validate(), "message.bot.severity: invalid severity")
+}
+
+func TestValidateReactionActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     FileConfig
+		wantErr string
+	}{
+		{name: "disabled ignores emojis", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Emojis: map[string]string{"eyes": "explode"}}}},
+		{name: "valid", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Enabled: true, Emojis: map[string]string{"eyes": "acknowledge", ":white_check_mark:": "resolve"}}}},
+		{name: "no emojis", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Enabled: true}}, wantErr: "reaction_actions.emojis must map at least one emoji"},
+		{name: "empty emoji", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Enabled: true, Emojis: map[string]string{"::": "resolve"}}}, wantErr: "must not contain empty emoji names"},
+		{name: "unknown action", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Enabled: true, Emojis: map[string]string{"eyes": "ack"}}}, wantErr: `invalid reaction_actions.emojis.eyes "ack"`},
+		{name: "snooze without snooze", cfg: FileConfig{ReactionAction: ReactionActionsConfig{Enabled: true, Emojis: map[string]string{"zzz": "snooze"}}}, wantErr: "snooze needs snooze.enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
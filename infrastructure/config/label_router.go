@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/metrics"
+)
+
+const (
+	RoutingModeFirstMatch = "first_match"
+	RoutingModeAllMatch   = "all_match"
+
+	// DropChannel is the channel ID of routes that ignore the alerts they
+	// match.
+	DropChannel = "drop"
+)
+
+func alertRoutesCounter(rule, action string) *metrics.Counter {
+	return metrics.GetOrCreateCounter(fmt.Sprintf(`alert_routes_total{rule=%q,action=%q}`, rule, action))
+}
+
+// matcherPattern splits `name<op>value` where op is one of the Prometheus
+// label matcher operators.
+var matcherPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_./-]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+type labelMatcher struct {
+	name  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// parseLabelMatcher parses expressions like `team=payments` or
+// `namespace=~"prod-.*"`. Regular expressions are anchored, so they must
+// match the whole label value.
+func parseLabelMatcher(expr string) (labelMatcher, error) {
+	parts := matcherPattern.FindStringSubmatch(expr)
+	if parts == nil {
+		return labelMatcher{}, fmt.Errorf("invalid label matcher %q: expected name=value, name!=value, name=~regex or name!~regex", expr)
+	}
+
+	m := labelMatcher{name: parts[1], op: parts[2], value: parts[3]}
+	if strings.HasPrefix(m.value, `"`) {
+		unquoted, err := strconv.Unquote(m.value)
+		if err != nil {
+			return labelMatcher{}, fmt.Errorf("invalid label matcher %q: %w", expr, err)
+		}
+		m.value = unquoted
+	}
+
+	if m.op == "=~" || m.op == "!~" {
+		re, err := regexp.Compile("^(?:" + m.value + ")$")
+		if err != nil {
+			return labelMatcher{}, fmt.Errorf("invalid label matcher %q: %w", expr, err)
+		}
+		m.re = re
+	}
+
+	return m, nil
+}
+
+// matches reports whether the label value satisfies the matcher. A missing
+// label has the empty value, as in Prometheus.
+func (m labelMatcher) matches(value string) bool {
+	switch m.op {
+	case "=":
+		return value == m.value
+	case "!=":
+		return value != m.value
+	case "=~":
+		return m.re.MatchString(value)
+	default:
+		return !m.re.MatchString(value)
+	}
+}
+
+// labelSelector matches alerts whose labels satisfy every matcher. The
+// severity pseudo-label stands in for alerts without a severity label.
+type labelSelector []labelMatcher
+
+func parseLabelSelector(exprs []string) (labelSelector, error) {
+	selector := make(labelSelector, 0, len(exprs))
+	for _, expr := range exprs {
+		m, err := parseLabelMatcher(expr)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, m)
+	}
+	return selector, nil
+}
+
+func (s labelSelector) matches(severity string, labels map[string]string) bool {
+	for _, m := range s {
+		value, ok := labels[m.name]
+		if !ok && m.name == "severity" {
+			value = severity
+		}
+		if !m.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+type labelRoute struct {
+	name      string
+	selector  labelSelector
+	channelID string
+}
+
+// LabelRouter routes alerts to channels by the label rules in
+// channels.label_routing. Alerts no rule matches fall back to severity
+// routing, then to team routing and then to the default channel. A route to
+// DropChannel routes the alert nowhere.
+type LabelRouter struct {
+	cfg    *FileConfig
+	mode   string
+	routes []labelRoute
+	logger *slog.Logger
+}
+
+// NewLabelRouter compiles the label routing rules of cfg.
+func NewLabelRouter(cfg *FileConfig, logger *slog.Logger) (*LabelRouter, error) {
+	routes, err := compileLabelRoutes(cfg.Channels.LabelRouting.Rules)
+	if err != nil {
+		return nil, err
+	}
+	mode := cfg.Channels.LabelRouting.Mode
+	if mode == "" {
+		mode = RoutingModeFirstMatch
+	}
+	return &LabelRouter{cfg: cfg, mode: mode, routes: routes, logger: logger}, nil
+}
+
+func compileLabelRoutes(rules []LabelRouteRule) ([]labelRoute, error) {
+	routes := make([]labelRoute, 0, len(rules))
+	for i, rule := range rules {
+		if rule.ChannelID == "" {
+			return nil, fmt.Errorf("channels.label_routing.rules[%d].channel_id is required", i)
+		}
+		if len(rule.Match) == 0 {
+			return nil, fmt.Errorf("channels.label_routing.rules[%d].match must list at least one matcher", i)
+		}
+		selector, err := parseLabelSelector(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("channels.label_routing.rules[%d]: %w", i, err)
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("label_routing.rules[%d]", i)
+		}
+		routes = append(routes, labelRoute{name: name, selector: selector, channelID: rule.ChannelID})
+	}
+	return routes, nil
+}
+
+// ChannelIDsForAlert returns the channel for the first matching rule, or for
+// every matching rule in all_match mode, without duplicates. Alerts a
+// matching rule drops, in either mode, get no channel.
+func (r *LabelRouter) ChannelIDsForAlert(severity string, labels map[string]string) []string {
+	var channels, rules []string
+	for _, route := range r.routes {
+		if !route.selector.matches(severity, labels) {
+			continue
+		}
+		if route.channelID == DropChannel {
+			return r.routed([]string{route.name}, nil)
+		}
+		if r.mode != RoutingModeAllMatch {
+			return r.routed([]string{route.name}, []string{route.channelID})
+		}
+		rules = append(rules, route.name)
+		if !slices.Contains(channels, route.channelID) {
+			channels = append(channels, route.channelID)
+		}
+	}
+	if len(channels) > 0 {
+		return r.routed(rules, channels)
+	}
+
+	rule, channelID := r.cfg.fallbackRoute(severity, labels)
+	if channelID == DropChannel {
+		return r.routed([]string{rule}, nil)
+	}
+	return r.routed([]string{rule}, []string{channelID})
+}
+
+// routed records that rules routed an alert to channels, or dropped it when
+// there are none.
+func (r *LabelRouter) routed(rules, channels []string) []string {
+	action := "post"
+	if len(channels) == 0 {
+		action = "drop"
+	}
+	for _, rule := range rules {
+		alertRoutesCounter(rule, action).Inc()
+	}
+	r.logger.Debug("Alert routed",
+		slog.Any("rules", rules),
+		slog.String("action", action),
+		slog.Any("channel_ids", channels),
+	)
+	return channels
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelMatcher(t *testing.T) {
+	tests := []struct {
+		expr  string
+		value string
+		want  bool
+	}{
+		{expr: "team=payments", value: "payments", want: true},
+		{expr: "team = payments", value: "search", want: false},
+		{expr: `team="payments"`, value: "payments", want: true},
+		{expr: "team!=payments", value: "search", want: true},
+		{expr: "team!=payments", value: "", want: true},
+		{expr: `namespace=~"prod-.*"`, value: "prod-eu", want: true},
+		{expr: `namespace=~"prod-.*"`, value: "preprod-eu", want: false},
+		{expr: "namespace=~prod-.*", value: "prod-us", want: true},
+		{expr: `namespace!~"prod-.*"`, value: "staging", want: true},
+		{expr: `namespace!~"prod-.*"`, value: "prod-eu", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+"/"+tt.value, func(t *testing.T) {
+			m, err := parseLabelMatcher(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.matches(tt.value))
+		})
+	}
+}
+
+func TestParseLabelMatcherInvalid(t *testing.T) {
+	for _, expr := range []string{"payments", "=payments", `team="payments`, "namespace=~prod-(", "1team=x"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseLabelMatcher(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, nil))
+}
+
+func labelRoutingConfig(mode string) *FileConfig {
+	cfg := &FileConfig{
+		Channels: ChannelsConfig{
+			DefaultChannelID: "ch-default",
+			Routing:          []RoutingRule{{Severity: "critical", ChannelID: "ch-critical"}},
+			LabelRouting: LabelRoutingConfig{
+				Mode: mode,
+				Rules: []LabelRouteRule{
+					{Match: []string{"team=payments"}, ChannelID: "ch-payments"},
+					{Match: []string{`namespace=~"prod-.*"`, "severity=critical"}, ChannelID: "ch-prod"},
+					{Match: []string{`namespace=~"prod-.*"`}, ChannelID: "ch-payments"},
+				},
+			},
+		},
+	}
+	cfg.applyDefaults()
+	return cfg
+}
+
+func TestLabelRouterFirstMatch(t *testing.T) {
+	router, err := NewLabelRouter(labelRoutingConfig(RoutingModeFirstMatch), testLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ch-payments"}, router.ChannelIDsForAlert("critical", map[string]string{"team": "payments", "namespace": "prod-eu"}))
+	assert.Equal(t, []string{"ch-prod"}, router.ChannelIDsForAlert("critical", map[string]string{"namespace": "prod-eu"}))
+	assert.Equal(t, []string{"ch-payments"}, router.ChannelIDsForAlert("high", map[string]string{"namespace": "prod-eu"}))
+	assert.Equal(t, []string{"ch-critical"}, router.ChannelIDsForAlert("critical", map[string]string{"namespace": "staging"}), "falls back to severity routing")
+	assert.Equal(t, []string{"ch-default"}, router.ChannelIDsForAlert("low", nil))
+}
+
+func TestLabelRouterAllMatch(t *testing.T) {
+	router, err := NewLabelRouter(labelRoutingConfig(RoutingModeAllMatch), testLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ch-payments", "ch-prod"}, router.ChannelIDsForAlert("critical", map[string]string{"team": "payments", "namespace": "prod-eu"}), "duplicates removed, rule order kept")
+	assert.Equal(t, []string{"ch-payments"}, router.ChannelIDsForAlert("high", map[string]string{"team": "payments"}))
+	assert.Equal(t, []string{"ch-default"}, router.ChannelIDsForAlert("info", map[string]string{"team": "search"}))
+}
+
+func TestLabelRouterSeverityLabelTakesPrecedence(t *testing.T) {
+	cfg := &FileConfig{Channels: ChannelsConfig{
+		DefaultChannelID: "ch-default",
+		LabelRouting: LabelRoutingConfig{Rules: []LabelRouteRule{
+			{Match: []string{"severity=page"}, ChannelID: "ch-page"},
+		}},
+	}}
+	router, err := NewLabelRouter(cfg, testLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ch-page"}, router.ChannelIDsForAlert("critical", map[string]string{"severity": "page"}))
+	assert.Equal(t, []string{"ch-default"}, router.ChannelIDsForAlert("page", map[string]string{"severity": "critical"}))
+}
+
+func TestLabelRouterFallbackAndDrop(t *testing.T) {
+	cfg := &FileConfig{Channels: ChannelsConfig{
+		DefaultChannelID: "ch-default",
+		Routing:          []RoutingRule{{Severity: "critical", ChannelID: "ch-critical"}, {Severity: "info", ChannelID: DropChannel}},
+		LabelRouting: LabelRoutingConfig{Rules: []LabelRouteRule{
+			{Name: "dev", Match: []string{"namespace=dev"}, ChannelID: DropChannel},
+			{Match: []string{"team=payments"}, ChannelID: "ch-payments"},
+		}},
+		TeamRouting: TeamRoutingConfig{Label: "namespace", Channels: map[string]string{"search": "ch-search", "sandbox": DropChannel}},
+	}}
+	router, err := NewLabelRouter(cfg, testLogger())
+	require.NoError(t, err)
+
+	dropped := alertRoutesCounter("dev", "drop").Get()
+	assert.Empty(t, router.ChannelIDsForAlert("critical", map[string]string{"namespace": "dev", "team": "payments"}), "drop rules win")
+	assert.Equal(t, dropped+1, alertRoutesCounter("dev", "drop").Get(), "routes are counted by rule name")
+	assert.Equal(t, []string{"ch-payments"}, router.ChannelIDsForAlert("info", map[string]string{"team": "payments"}), "label rules come before severity rules")
+	assert.Empty(t, router.ChannelIDsForAlert("info", map[string]string{"namespace": "search"}))
+	assert.Equal(t, []string{"ch-critical"}, router.ChannelIDsForAlert("critical", map[string]string{"namespace": "search"}), "severity rules come before team routing")
+	assert.Equal(t, []string{"ch-search"}, router.ChannelIDsForAlert("high", map[string]string{"namespace": "search"}))
+	assert.Empty(t, router.ChannelIDsForAlert("high", map[string]string{"namespace": "sandbox"}))
+	assert.Equal(t, []string{"ch-default"}, router.ChannelIDsForAlert("high", map[string]string{"namespace": "other"}))
+
+	allMatch := *cfg
+	allMatch.Channels.LabelRouting.Mode = RoutingModeAllMatch
+	router, err = NewLabelRouter(&allMatch, testLogger())
+	require.NoError(t, err)
+	assert.Empty(t, router.ChannelIDsForAlert("high", map[string]string{"namespace": "dev", "team": "payments"}), "a matching drop rule drops the alert in all_match mode")
+}
+
+func TestValidateTeamRouting(t *testing.T) {
+	cfg := DefaultFileConfig()
+	assert.Equal(t, "team", cfg.Channels.TeamRouting.Label)
+
+	cfg.Channels.TeamRouting.Channels = map[string]string{"payments": "ch-payments", "dev": DropChannel}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Channels.TeamRouting.Channels["search"] = ""
+	assert.ErrorContains(t, cfg.Validate(), `channels.team_routing.channels["search"] needs a channel ID`)
+}
+
+func TestValidateLabelRouting(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing LabelRoutingConfig
+		wantErr string
+	}{
+		{name: "empty", routing: LabelRoutingConfig{}},
+		{name: "valid", routing: LabelRoutingConfig{Mode: RoutingModeAllMatch, Rules: []LabelRouteRule{{Match: []string{"team=payments"}, ChannelID: "ch"}}}},
+		{name: "unknown mode", routing: LabelRoutingConfig{Mode: "any"}, wantErr: "invalid channels.label_routing.mode"},
+		{name: "missing channel", routing: LabelRoutingConfig{Rules: []LabelRouteRule{{Match: []string{"team=payments"}}}}, wantErr: "rules[0].channel_id is required"},
+		{name: "missing matchers", routing: LabelRoutingConfig{Rules: []LabelRouteRule{{ChannelID: "ch"}}}, wantErr: "at least one matcher"},
+		{name: "bad regex", routing: LabelRoutingConfig{Rules: []LabelRouteRule{{Match: []string{"team=~pay("}, ChannelID: "ch"}}}, wantErr: "invalid label matcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Channels: ChannelsConfig{LabelRouting: tt.routing}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type linkRule struct {
+	alerts    []string
+	selector  labelSelector
+	runbook   *template.Template
+	dashboard *template.Template
+}
+
+// linkData is what link templates are rendered with.
+type linkData struct {
+	Fingerprint string
+	Name        string
+	Severity    string
+	Status      string
+	Labels      map[string]string
+}
+
+// LinkPolicy picks the runbook and dashboard links of alert posts from the
+// rules in links.
+type LinkPolicy struct {
+	rules []linkRule
+}
+
+// NewLinkPolicy compiles the link rules of cfg.
+func NewLinkPolicy(cfg *FileConfig) (*LinkPolicy, error) {
+	if len(cfg.Links.Rules) == 0 {
+		return nil, fmt.Errorf("links.rules must list at least one rule when links are enabled")
+	}
+	rules := make([]linkRule, 0, len(cfg.Links.Rules))
+	for i, rule := range cfg.Links.Rules {
+		if rule.Runbook == "" && rule.Dashboard == "" {
+			return nil, fmt.Errorf("links.rules[%d] must set runbook or dashboard", i)
+		}
+		for _, pattern := range rule.Alerts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("links.rules[%d]: invalid alert pattern %q: %w", i, pattern, err)
+			}
+		}
+		selector, err := parseLabelSelector(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("links.rules[%d]: %w", i, err)
+		}
+		runbook, err := parseLinkTemplate(fmt.Sprintf("links.rules[%d].runbook", i), rule.Runbook)
+		if err != nil {
+			return nil, err
+		}
+		dashboard, err := parseLinkTemplate(fmt.Sprintf("links.rules[%d].dashboard", i), rule.Dashboard)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, linkRule{
+			alerts:    rule.Alerts,
+			selector:  selector,
+			runbook:   runbook,
+			dashboard: dashboard,
+		})
+	}
+	return &LinkPolicy{rules: rules}, nil
+}
+
+func parseLinkTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// Links returns the runbook and dashboard links of a. For each kind the first
+// matching rule that sets it applies; a link whose template fails to render,
+// e.g. for a missing label, or does not render an http(s) URL is left out.
+func (p *LinkPolicy) Links(a *alert.Alert) []port.Link {
+	data := linkData{
+		Fingerprint: a.Fingerprint().Value(),
+		Name:        a.Name(),
+		Severity:    a.Severity().String(),
+		Status:      a.Status().String(),
+		Labels:      a.Labels(),
+	}
+
+	var runbook, dashboard *template.Template
+	for _, rule := range p.rules {
+		if !rule.matches(a.Name(), data.Severity, data.Labels) {
+			continue
+		}
+		if runbook == nil {
+			runbook = rule.runbook
+		}
+		if dashboard == nil {
+			dashboard = rule.dashboard
+		}
+	}
+
+	var links []port.Link
+	if url, ok := renderLink(runbook, data); ok {
+		links = append(links, port.Link{Title: "Runbook", URL: url})
+	}
+	if url, ok := renderLink(dashboard, data); ok {
+		links = append(links, port.Link{Title: "Dashboard", URL: url})
+	}
+	return links
+}
+
+func (r linkRule) matches(name, severity string, labels map[string]string) bool {
+	if len(r.alerts) > 0 {
+		matched := false
+		for _, pattern := range r.alerts {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return r.selector.matches(severity, labels)
+}
+
+func renderLink(tmpl *template.Template, data linkData) (string, bool) {
+	if tmpl == nil {
+		return "", false
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", false
+	}
+	url := strings.TrimSpace(sb.String())
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return "", false
+	}
+	return url, true
+}
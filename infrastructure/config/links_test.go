@@ -0,0 +1,95 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestLinkPolicyLinks(t *testing.T) {
+	cfg := &FileConfig{
+		Links: LinksConfig{
+			Enabled: true,
+			Rules: []LinkRule{
+				{Alerts: []string{"KubePod*"}, Match: []string{"team=payments"}, Runbook: "https://wiki.example.com/payments/{{.Name}}"},
+				{Alerts: []string{"KubePod*"}, Runbook: "https://wiki.example.com/k8s/{{.Name}}", Dashboard: "https://grafana.example.com/d/pods?var-namespace={{.Labels.namespace}}&var-pod={{.Labels.pod | urlquery}}"},
+				{Match: []string{"severity=critical"}, Dashboard: "https://grafana.example.com/d/overview?var-fp={{.Fingerprint}}"},
+			},
+		},
+	}
+	policy, err := NewLinkPolicy(cfg)
+	require.NoError(t, err)
+
+	newAlert := func(name, severity string, labels map[string]string) *alert.Alert {
+		return alert.RestoreAlert(alert.RestoreFingerprint("fp-1"), name, alert.RestoreSeverity(severity), alert.RestoreStatus(alert.StatusFiring), "", "prometheus", labels, time.Time{})
+	}
+
+	tests := []struct {
+		name  string
+		alert *alert.Alert
+		want  []port.Link
+	}{
+		{
+			name:  "first rule setting each link",
+			alert: newAlert("KubePodCrashLooping", "warning", map[string]string{"team": "payments", "namespace": "prod", "pod": "api 1"}),
+			want: []port.Link{
+				{Title: "Runbook", URL: "https://wiki.example.com/payments/KubePodCrashLooping"},
+				{Title: "Dashboard", URL: "https://grafana.example.com/d/pods?var-namespace=prod&var-pod=api+1"},
+			},
+		},
+		{
+			name:  "missing label skips the link",
+			alert: newAlert("KubePodNotReady", "warning", map[string]string{"namespace": "prod"}),
+			want:  []port.Link{{Title: "Runbook", URL: "https://wiki.example.com/k8s/KubePodNotReady"}},
+		},
+		{
+			name:  "label matcher only",
+			alert: newAlert("DiskFull", "critical", nil),
+			want:  []port.Link{{Title: "Dashboard", URL: "https://grafana.example.com/d/overview?var-fp=fp-1"}},
+		},
+		{
+			name:  "no rule",
+			alert: newAlert("DiskFull", "warning", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Links(tt.alert))
+		})
+	}
+}
+
+func TestValidateLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		links   LinksConfig
+		wantErr string
+	}{
+		{name: "disabled ignores rules", links: LinksConfig{Rules: []LinkRule{{}}}},
+		{name: "valid", links: LinksConfig{Enabled: true, Rules: []LinkRule{{Alerts: []string{"Kube*"}, Runbook: "https://wiki/{{.Name}}"}}}},
+		{name: "no rules", links: LinksConfig{Enabled: true}, wantErr: "links.rules must list at least one rule"},
+		{name: "no links", links: LinksConfig{Enabled: true, Rules: []LinkRule{{Alerts: []string{"Kube*"}}}}, wantErr: "links.rules[0] must set runbook or dashboard"},
+		{name: "bad pattern", links: LinksConfig{Enabled: true, Rules: []LinkRule{{Alerts: []string{"[Kube"}, Runbook: "https://wiki"}}}, wantErr: "invalid alert pattern"},
+		{name: "bad matcher", links: LinksConfig{Enabled: true, Rules: []LinkRule{{Match: []string{"team"}, Runbook: "https://wiki"}}}, wantErr: "invalid label matcher"},
+		{name: "bad template", links: LinksConfig{Enabled: true, Rules: []LinkRule{{Dashboard: "https://grafana/{{.Labels.pod"}}}, wantErr: "invalid links.rules[0].dashboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Links: tt.links}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// maxMaintenanceDuration caps how long a recurring window stays open, which
+// also bounds how far back its schedule is searched.
+const maxMaintenanceDuration = 24 * time.Hour
+
+type maintenanceWindow struct {
+	name     string
+	action   string
+	selector labelSelector
+
+	// Fixed windows are open from start to end.
+	start, end time.Time
+
+	// Recurring windows open when schedule fires and stay open for duration.
+	schedule *cronSchedule
+	duration time.Duration
+	location *time.Location
+}
+
+// MaintenanceSchedule evaluates the windows in maintenance.
+type MaintenanceSchedule struct {
+	windows []maintenanceWindow
+}
+
+// NewMaintenanceSchedule compiles the maintenance windows of cfg.
+func NewMaintenanceSchedule(cfg *FileConfig) (*MaintenanceSchedule, error) {
+	windows, err := compileMaintenanceWindows(cfg.Maintenance.Windows)
+	if err != nil {
+		return nil, err
+	}
+	return &MaintenanceSchedule{windows: windows}, nil
+}
+
+func compileMaintenanceWindows(windows []MaintenanceWindowConfig) ([]maintenanceWindow, error) {
+	compiled := make([]maintenanceWindow, 0, len(windows))
+	names := make(map[string]bool, len(windows))
+	for i, w := range windows {
+		if w.Name == "" {
+			return nil, fmt.Errorf("maintenance.windows[%d].name is required", i)
+		}
+		if names[w.Name] {
+			return nil, fmt.Errorf("maintenance.windows[%d]: duplicate name %q", i, w.Name)
+		}
+		names[w.Name] = true
+
+		mw := maintenanceWindow{name: w.Name, action: w.Action}
+		switch w.Action {
+		case "":
+			mw.action = port.MaintenanceAnnotate
+		case port.MaintenanceAnnotate, port.MaintenanceSuppress:
+		default:
+			return nil, fmt.Errorf("maintenance.windows[%d].action must be %q or %q, got %q", i, port.MaintenanceAnnotate, port.MaintenanceSuppress, w.Action)
+		}
+
+		selector, err := parseLabelSelector(w.Match)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance.windows[%d]: %w", i, err)
+		}
+		mw.selector = selector
+
+		fixed := w.Start != "" || w.End != ""
+		recurring := w.Schedule != "" || w.Duration != ""
+		switch {
+		case fixed && recurring:
+			return nil, fmt.Errorf("maintenance.windows[%d] must use either start/end or schedule/duration, not both", i)
+		case fixed:
+			if mw.start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+				return nil, fmt.Errorf("invalid maintenance.windows[%d].start %q: %w", i, w.Start, err)
+			}
+			if mw.end, err = time.Parse(time.RFC3339, w.End); err != nil {
+				return nil, fmt.Errorf("invalid maintenance.windows[%d].end %q: %w", i, w.End, err)
+			}
+			if !mw.end.After(mw.start) {
+				return nil, fmt.Errorf("maintenance.windows[%d].end must be after start", i)
+			}
+		case recurring:
+			schedule, err := parseCron(w.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("maintenance.windows[%d].schedule: %w", i, err)
+			}
+			mw.schedule = &schedule
+			if mw.duration, err = time.ParseDuration(w.Duration); err != nil {
+				return nil, fmt.Errorf("invalid maintenance.windows[%d].duration %q: %w", i, w.Duration, err)
+			}
+			if mw.duration < time.Minute || mw.duration > maxMaintenanceDuration {
+				return nil, fmt.Errorf("maintenance.windows[%d].duration must be between 1m and %s, got %s", i, maxMaintenanceDuration, mw.duration)
+			}
+			mw.location = time.UTC
+			if w.Timezone != "" {
+				if mw.location, err = time.LoadLocation(w.Timezone); err != nil {
+					return nil, fmt.Errorf("invalid maintenance.windows[%d].timezone %q: %w", i, w.Timezone, err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("maintenance.windows[%d] needs start and end, or schedule and duration", i)
+		}
+		compiled = append(compiled, mw)
+	}
+	return compiled, nil
+}
+
+// openUntil reports whether the window is open at now and when it closes.
+func (w maintenanceWindow) openUntil(now time.Time) (time.Time, bool) {
+	if w.schedule == nil {
+		return w.end, !now.Before(w.start) && now.Before(w.end)
+	}
+	fired, ok := w.schedule.lastFire(now.In(w.location), w.duration)
+	if !ok {
+		return time.Time{}, false
+	}
+	return fired.Add(w.duration), true
+}
+
+// WindowForAlert returns the first open window, in configuration order,
+// whose matchers all hold for the alert. A window without matchers applies to
+// every alert.
+func (s *MaintenanceSchedule) WindowForAlert(severity string, labels map[string]string, now time.Time) (port.MaintenanceWindow, bool) {
+	for _, w := range s.windows {
+		until, open := w.openUntil(now)
+		if open && w.selector.matches(severity, labels) {
+			return port.MaintenanceWindow{Name: w.name, Action: w.action, Until: until}, true
+		}
+	}
+	return port.MaintenanceWindow{}, false
+}
+
+// OpenWindows returns the names of the windows open at now.
+func (s *MaintenanceSchedule) OpenWindows(now time.Time) []string {
+	var names []string
+	for _, w := range s.windows {
+		if _, open := w.openUntil(now); open {
+			names = append(names, w.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func TestMaintenanceSchedule(t *testing.T) {
+	cfg := &FileConfig{Maintenance: MaintenanceConfig{
+		Enabled: true,
+		Windows: []MaintenanceWindowConfig{
+			{
+				Name:   "db-upgrade",
+				Match:  []string{"service=postgres"},
+				Action: "suppress",
+				Start:  "2026-01-05T10:00:00Z",
+				End:    "2026-01-05T12:00:00Z",
+			},
+			{
+				Name:     "nightly-backup",
+				Match:    []string{`namespace=~"prod-.*"`},
+				Schedule: "0 2 * * *",
+				Duration: "1h",
+				Timezone: "Europe/Berlin",
+			},
+		},
+	}}
+	schedule, err := NewMaintenanceSchedule(cfg)
+	require.NoError(t, err)
+
+	postgres := map[string]string{"service": "postgres", "namespace": "prod-eu"}
+
+	window, ok := schedule.WindowForAlert("critical", postgres, time.Date(2026, 1, 5, 11, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, port.MaintenanceWindow{Name: "db-upgrade", Action: port.MaintenanceSuppress, Until: time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)}, window)
+
+	_, ok = schedule.WindowForAlert("critical", postgres, time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "the end is exclusive")
+
+	// 02:30 in Berlin is 01:30 UTC in winter.
+	window, ok = schedule.WindowForAlert("critical", postgres, time.Date(2026, 1, 6, 1, 30, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, "nightly-backup", window.Name)
+	assert.Equal(t, port.MaintenanceAnnotate, window.Action, "annotate is the default")
+	assert.True(t, window.Until.Equal(time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC)))
+
+	_, ok = schedule.WindowForAlert("critical", map[string]string{"namespace": "staging"}, time.Date(2026, 1, 6, 1, 30, 0, 0, time.UTC))
+	assert.False(t, ok, "matchers must hold")
+
+	assert.Equal(t, []string{"nightly-backup"}, schedule.OpenWindows(time.Date(2026, 1, 6, 1, 30, 0, 0, time.UTC)))
+	assert.Empty(t, schedule.OpenWindows(time.Date(2026, 1, 6, 2, 30, 0, 0, time.UTC)))
+}
+
+func TestValidateMaintenance(t *testing.T) {
+	fixed := MaintenanceWindowConfig{Name: "w", Start: "2026-01-05T10:00:00Z", End: "2026-01-05T12:00:00Z"}
+	tests := []struct {
+		name    string
+		windows []MaintenanceWindowConfig
+		wantErr string
+	}{
+		{name: "valid", windows: []MaintenanceWindowConfig{fixed}},
+		{name: "no windows", wantErr: "maintenance.windows must list at least one window"},
+		{name: "no name", windows: []MaintenanceWindowConfig{{Start: fixed.Start, End: fixed.End}}, wantErr: "name is required"},
+		{name: "duplicate name", windows: []MaintenanceWindowConfig{fixed, fixed}, wantErr: "duplicate name"},
+		{name: "bad action", windows: []MaintenanceWindowConfig{{Name: "w", Action: "mute", Start: fixed.Start, End: fixed.End}}, wantErr: "action must be"},
+		{name: "bad matcher", windows: []MaintenanceWindowConfig{{Name: "w", Match: []string{"team"}, Start: fixed.Start, End: fixed.End}}, wantErr: "invalid label matcher"},
+		{name: "no time", windows: []MaintenanceWindowConfig{{Name: "w"}}, wantErr: "needs start and end, or schedule and duration"},
+		{name: "both kinds", windows: []MaintenanceWindowConfig{{Name: "w", Start: fixed.Start, End: fixed.End, Schedule: "0 2 * * *", Duration: "1h"}}, wantErr: "not both"},
+		{name: "bad start", windows: []MaintenanceWindowConfig{{Name: "w", Start: "tomorrow", End: fixed.End}}, wantErr: "invalid maintenance.windows[0].start"},
+		{name: "end before start", windows: []MaintenanceWindowConfig{{Name: "w", Start: fixed.End, End: fixed.Start}}, wantErr: "end must be after start"},
+		{name: "bad schedule", windows: []MaintenanceWindowConfig{{Name: "w", Schedule: "0 2 * *", Duration: "1h"}}, wantErr: "expected 5 fields"},
+		{name: "duration too long", windows: []MaintenanceWindowConfig{{Name: "w", Schedule: "0 2 * * *", Duration: "25h"}}, wantErr: "duration must be between 1m and 24h"},
+		{name: "bad timezone", windows: []MaintenanceWindowConfig{{Name: "w", Schedule: "0 2 * * *", Duration: "1h", Timezone: "Mars/Olympus"}}, wantErr: "invalid maintenance.windows[0].timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Maintenance: MaintenanceConfig{Enabled: true, Windows: tt.windows}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// serverNamePattern keeps server names apart from the Mattermost IDs they
+// prefix.
+var serverNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+const (
+	ServerKindMattermost = "mattermost"
+	ServerKindSlack      = "slack"
+	ServerKindTeams      = "teams"
+	ServerKindTelegram   = "telegram"
+)
+
+// MattermostServerConfig is a Mattermost server besides MATTERMOST_URL, e.g.
+// a customer-facing instance, or a Slack, Microsoft Teams or Telegram bot.
+// Routing rules naming it in server post their alerts there. The bot token is
+// read from the environment variable named.
+type MattermostServerConfig struct {
+	Name     string `yaml:"name"`      // e.g. customer
+	Kind     string `yaml:"kind"`      // mattermost (default), slack, teams or telegram
+	URL      string `yaml:"url"`       // optional for slack, teams and telegram
+	TokenEnv string `yaml:"token_env"` // e.g. CUSTOMER_MATTERMOST_TOKEN; the app password for teams
+	// SigningSecretEnv names the variable holding the Slack app's signing
+	// secret, or the secret token of a Telegram bot's webhook, which button
+	// clicks are checked against. Required for slack and telegram.
+	SigningSecretEnv string `yaml:"signing_secret_env"`
+	// AppID is the Microsoft app ID of a Teams bot, and TenantID its tenant
+	// when the bot is single-tenant. AppID is required for teams.
+	AppID    string `yaml:"app_id"`
+	TenantID string `yaml:"tenant_id"`
+}
+
+func (c *FileConfig) validateMattermostServers() error {
+	names := make(map[string]bool, len(c.MattermostServers))
+	for i, server := range c.MattermostServers {
+		if !serverNamePattern.MatchString(server.Name) {
+			return fmt.Errorf("mattermost_servers[%d].name must be lowercase letters, digits and dashes, got %q", i, server.Name)
+		}
+		if names[server.Name] {
+			return fmt.Errorf("mattermost_servers[%d]: duplicate server %q", i, server.Name)
+		}
+		names[server.Name] = true
+		switch server.Kind {
+		case "", ServerKindMattermost:
+			if server.URL == "" {
+				return fmt.Errorf("mattermost_servers[%d].url is required", i)
+			}
+		case ServerKindSlack, ServerKindTelegram:
+			if server.SigningSecretEnv == "" {
+				return fmt.Errorf("mattermost_servers[%d].signing_secret_env is required for %s", i, server.Kind)
+			}
+		case ServerKindTeams:
+			if server.AppID == "" {
+				return fmt.Errorf("mattermost_servers[%d].app_id is required for teams", i)
+			}
+		default:
+			return fmt.Errorf("mattermost_servers[%d].kind must be %s, %s, %s or %s, got %q", i, ServerKindMattermost, ServerKindSlack, ServerKindTeams, ServerKindTelegram, server.Kind)
+		}
+		if server.TokenEnv == "" {
+			return fmt.Errorf("mattermost_servers[%d].token_env is required", i)
+		}
+	}
+
+	// Every post of a channel lives on one server.
+	servers := make(map[string]string)
+	assign := func(kind string, i int, channelID, server string) error {
+		if server != "" && !names[server] {
+			return fmt.Errorf("%s[%d]: unknown mattermost server %q", kind, i, server)
+		}
+		if other, ok := servers[channelID]; ok && other != server {
+			return fmt.Errorf("%s[%d]: channel %s is already on mattermost server %q", kind, i, channelID, displayServer(other))
+		}
+		servers[channelID] = server
+		return nil
+	}
+	if c.Channels.DefaultChannelID != "" {
+		servers[c.Channels.DefaultChannelID] = ""
+	}
+	for i, rule := range c.Channels.Routing {
+		if err := assign("channels.routing", i, rule.ChannelID, rule.Server); err != nil {
+			return err
+		}
+	}
+	for i, rule := range c.Channels.LabelRouting.Rules {
+		if err := assign("channels.label_routing.rules", i, rule.ChannelID, rule.Server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func displayServer(name string) string {
+	if name == "" {
+		return "main"
+	}
+	return name
+}
+
+// MattermostServerChannels returns, for each channel routed to a server of
+// mattermost_servers, the name of the server.
+func (c *FileConfig) MattermostServerChannels() map[string]string {
+	channels := make(map[string]string)
+	for _, rule := range c.Channels.Routing {
+		if rule.Server != "" {
+			channels[rule.ChannelID] = rule.Server
+		}
+	}
+	for _, rule := range c.Channels.LabelRouting.Rules {
+		if rule.Server != "" {
+			channels[rule.ChannelID] = rule.Server
+		}
+	}
+	return channels
+}
+
+// Token returns the bot token of the server, read from its token_env, or an
+// error when the variable is unset.
+func (s MattermostServerConfig) Token() (string, error) {
+	return s.env(s.TokenEnv)
+}
+
+// SigningSecret returns the signing secret of a Slack server, or the webhook
+// secret token of a Telegram server, read from its signing_secret_env, or an
+// error when the variable is unset.
+func (s MattermostServerConfig) SigningSecret() (string, error) {
+	return s.env(s.SigningSecretEnv)
+}
+
+func (s MattermostServerConfig) env(name string) (string, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("mattermost server %s: %s is not set", s.Name, name)
+	}
+	return value, nil
+}
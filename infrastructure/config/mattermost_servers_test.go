@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func customerServerConfig() *FileConfig {
+	cfg := DefaultFileConfig()
+	cfg.Channels.DefaultChannelID = "ops-alerts"
+	cfg.MattermostServers = []MattermostServerConfig{
+		{Name: "customer", URL: "https://chat.customer.example.com", TokenEnv: "CUSTOMER_MATTERMOST_TOKEN"},
+	}
+	cfg.Channels.Routing = []RoutingRule{
+		{Severity: "critical", ChannelID: "customer-critical", Server: "customer"},
+	}
+	cfg.Channels.LabelRouting.Rules = []LabelRouteRule{
+		{Match: []string{"team=payments"}, ChannelID: "payments-alerts"},
+		{Match: []string{"customer=acme"}, ChannelID: "acme-alerts", Server: "customer"},
+	}
+	return cfg
+}
+
+func TestValidateMattermostServers(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *FileConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(cfg *FileConfig) {}},
+		{name: "bad name", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].Name = "Customer" }, wantErr: `mattermost_servers[0].name must be lowercase letters, digits and dashes, got "Customer"`},
+		{name: "duplicate", modify: func(cfg *FileConfig) { cfg.MattermostServers = append(cfg.MattermostServers, cfg.MattermostServers[0]) }, wantErr: `mattermost_servers[1]: duplicate server "customer"`},
+		{name: "no url", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].URL = "" }, wantErr: "mattermost_servers[0].url is required"},
+		{name: "no token", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].TokenEnv = "" }, wantErr: "mattermost_servers[0].token_env is required"},
+		{name: "unknown server", modify: func(cfg *FileConfig) { cfg.Channels.Routing[0].Server = "partner" }, wantErr: `channels.routing[0]: unknown mattermost server "partner"`},
+		{name: "default channel on another server", modify: func(cfg *FileConfig) { cfg.Channels.Routing[0].ChannelID = "ops-alerts" }, wantErr: `channels.routing[0]: channel ops-alerts is already on mattermost server "main"`},
+		{name: "unknown kind", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].Kind = "discord" }, wantErr: `mattermost_servers[0].kind must be mattermost, slack, teams or telegram, got "discord"`},
+		{name: "slack without url", modify: func(cfg *FileConfig) {
+			cfg.MattermostServers[0].Kind, cfg.MattermostServers[0].URL, cfg.MattermostServers[0].SigningSecretEnv = ServerKindSlack, "", "CUSTOMER_SLACK_SIGNING_SECRET"
+		}},
+		{name: "slack without signing secret", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].Kind = ServerKindSlack }, wantErr: "mattermost_servers[0].signing_secret_env is required for slack"},
+		{name: "teams", modify: func(cfg *FileConfig) {
+			cfg.MattermostServers[0].Kind, cfg.MattermostServers[0].URL, cfg.MattermostServers[0].AppID = ServerKindTeams, "", "00000000-0000-0000-0000-000000000001"
+		}},
+		{name: "teams without app id", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].Kind = ServerKindTeams }, wantErr: "mattermost_servers[0].app_id is required for teams"},
+		{name: "telegram without signing secret", modify: func(cfg *FileConfig) { cfg.MattermostServers[0].Kind = ServerKindTelegram }, wantErr: "mattermost_servers[0].signing_secret_env is required for telegram"},
+		{name: "channel on two servers", modify: func(cfg *FileConfig) { cfg.Channels.LabelRouting.Rules[0].ChannelID = "customer-critical" }, wantErr: `channels.label_routing.rules[0]: channel customer-critical is already on mattermost server "customer"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := customerServerConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMattermostServerChannels(t *testing.T) {
+	cfg := customerServerConfig()
+	assert.Equal(t, map[string]string{"customer-critical": "customer", "acme-alerts": "customer"}, cfg.MattermostServerChannels())
+
+	server := cfg.MattermostServers[0]
+	_, err := server.Token()
+	assert.ErrorContains(t, err, "mattermost server customer: CUSTOMER_MATTERMOST_TOKEN is not set")
+	t.Setenv("CUSTOMER_MATTERMOST_TOKEN", "customer-token")
+	token, err := server.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "customer-token", token)
+
+	server.SigningSecretEnv = "CUSTOMER_SLACK_SIGNING_SECRET"
+	_, err = server.SigningSecret()
+	assert.ErrorContains(t, err, "mattermost server customer: CUSTOMER_SLACK_SIGNING_SECRET is not set")
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type mentionRule struct {
+	severities       []string
+	channels         []string
+	mentions         []string
+	ignoreQuietHours bool
+}
+
+// MentionPolicy picks who is mentioned in new firing alert posts from the
+// rules in mentions.
+type MentionPolicy struct {
+	rules []mentionRule
+	quiet *quietHours
+}
+
+// NewMentionPolicy compiles the mention rules and quiet hours of cfg.
+func NewMentionPolicy(cfg *FileConfig) (*MentionPolicy, error) {
+	rules, err := compileMentionRules(cfg.Mentions.Rules)
+	if err != nil {
+		return nil, err
+	}
+	quiet, err := compileQuietHours("mentions.quiet_hours", cfg.Mentions.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+	return &MentionPolicy{rules: rules, quiet: quiet}, nil
+}
+
+func compileMentionRules(rules []MentionRule) ([]mentionRule, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("mentions.rules must list at least one rule when mentions are enabled")
+	}
+	compiled := make([]mentionRule, 0, len(rules))
+	for i, rule := range rules {
+		severities := make([]string, 0, len(rule.Severities))
+		for _, severity := range rule.Severities {
+			s, err := alert.NewSeverity(severity)
+			if err != nil {
+				return nil, fmt.Errorf("mentions.rules[%d]: %w", i, err)
+			}
+			severities = append(severities, s.String())
+		}
+		// A rule without mentions is allowed: it keeps matching alerts from
+		// falling through to later rules.
+		for _, mention := range rule.Mention {
+			if !strings.HasPrefix(mention, "@") || len(mention) < 2 || strings.ContainsAny(mention, " \t\n") {
+				return nil, fmt.Errorf("mentions.rules[%d]: invalid mention %q, must look like @name", i, mention)
+			}
+		}
+		compiled = append(compiled, mentionRule{
+			severities:       severities,
+			channels:         rule.Channels,
+			mentions:         rule.Mention,
+			ignoreQuietHours: rule.IgnoreQuietHours,
+		})
+	}
+	return compiled, nil
+}
+
+// Mentions returns the mentions of the first rule matching severity and
+// channelID, or nil when no rule matches or the rule is silenced by quiet
+// hours at now.
+func (p *MentionPolicy) Mentions(severity, channelID string, now time.Time) []string {
+	for _, rule := range p.rules {
+		if len(rule.severities) > 0 && !slices.Contains(rule.severities, strings.ToLower(severity)) {
+			continue
+		}
+		if len(rule.channels) > 0 && !slices.Contains(rule.channels, channelID) {
+			continue
+		}
+		if p.quiet != nil && !rule.ignoreQuietHours && p.quiet.contains(now) {
+			return nil
+		}
+		return rule.mentions
+	}
+	return nil
+}
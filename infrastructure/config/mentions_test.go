@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionPolicyMentions(t *testing.T) {
+	cfg := &FileConfig{
+		Mentions: MentionsConfig{
+			Enabled: true,
+			Rules: []MentionRule{
+				{Severities: []string{"critical"}, Channels: []string{"ch-payments"}, Mention: []string{"@channel", "@payments-oncall"}, IgnoreQuietHours: true},
+				{Severities: []string{"Critical"}, Mention: []string{"@here"}},
+				{Severities: []string{"warning", "high"}, Mention: []string{"@sre-team"}},
+			},
+			QuietHours: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"},
+		},
+	}
+	policy, err := NewMentionPolicy(cfg)
+	require.NoError(t, err)
+
+	day := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 1, 5, 22, 30, 0, 0, time.UTC) // 23:30 in Berlin
+
+	tests := []struct {
+		name      string
+		severity  string
+		channelID string
+		now       time.Time
+		want      []string
+	}{
+		{name: "channel rule first", severity: "critical", channelID: "ch-payments", now: day, want: []string{"@channel", "@payments-oncall"}},
+		{name: "severity rule", severity: "critical", channelID: "ch-ops", now: day, want: []string{"@here"}},
+		{name: "team handle", severity: "warning", channelID: "ch-ops", now: day, want: []string{"@sre-team"}},
+		{name: "no rule", severity: "info", channelID: "ch-ops", now: day, want: nil},
+		{name: "quiet hours", severity: "critical", channelID: "ch-ops", now: night, want: nil},
+		{name: "rule ignoring quiet hours", severity: "critical", channelID: "ch-payments", now: night, want: []string{"@channel", "@payments-oncall"}},
+		{name: "quiet hours end", severity: "warning", channelID: "ch-ops", now: time.Date(2026, 1, 5, 6, 0, 0, 0, time.UTC), want: []string{"@sre-team"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Mentions(tt.severity, tt.channelID, tt.now))
+		})
+	}
+}
+
+func TestValidateMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		mentions MentionsConfig
+		wantErr  string
+	}{
+		{name: "disabled ignores fields", mentions: MentionsConfig{Rules: []MentionRule{{Mention: []string{"here"}}}}},
+		{name: "valid", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Severities: []string{"critical"}, Mention: []string{"@here"}}}, QuietHours: QuietHoursConfig{Start: "22:00", End: "07:00"}}},
+		{name: "no rules", mentions: MentionsConfig{Enabled: true}, wantErr: "mentions.rules must list at least one rule"},
+		{name: "unknown severity", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Severities: []string{"urgent"}, Mention: []string{"@here"}}}}, wantErr: "mentions.rules[0]"},
+		{name: "mention without @", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Mention: []string{"here"}}}}, wantErr: `invalid mention "here"`},
+		{name: "quiet hours without end", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Mention: []string{"@here"}}}, QuietHours: QuietHoursConfig{Start: "22:00"}}, wantErr: "invalid mentions.quiet_hours.end"},
+		{name: "empty quiet hours", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Mention: []string{"@here"}}}, QuietHours: QuietHoursConfig{Start: "22:00", End: "22:00"}}, wantErr: "must differ"},
+		{name: "bad timezone", mentions: MentionsConfig{Enabled: true, Rules: []MentionRule{{Mention: []string{"@here"}}}, QuietHours: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}}, wantErr: "invalid mentions.quiet_hours.timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{Mentions: tt.mentions}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+type onCallRule struct {
+	selector labelSelector
+	users    []string
+}
+
+// OnCallResolver picks the users who get a direct message about a new firing
+// alert from the rules in direct_messages and from the alert's UserLabel.
+type OnCallResolver struct {
+	users     port.UserMapper
+	userLabel string
+	rules     []onCallRule
+}
+
+// NewOnCallResolver compiles the direct message rules of cfg.
+func NewOnCallResolver(cfg *FileConfig) (*OnCallResolver, error) {
+	rules, err := compileOnCallRules(cfg.DirectMessages.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return &OnCallResolver{users: cfg, userLabel: cfg.DirectMessages.UserLabel, rules: rules}, nil
+}
+
+// SetUserMapper replaces users.mapping as the source of Keep to Mattermost
+// username translations.
+func (r *OnCallResolver) SetUserMapper(users port.UserMapper) {
+	r.users = users
+}
+
+func compileOnCallRules(rules []DirectMessageRule) ([]onCallRule, error) {
+	compiled := make([]onCallRule, 0, len(rules))
+	for i, rule := range rules {
+		if len(rule.Users) == 0 {
+			return nil, fmt.Errorf("direct_messages.rules[%d].users must list at least one user", i)
+		}
+		if len(rule.Match) == 0 {
+			return nil, fmt.Errorf("direct_messages.rules[%d].match must list at least one matcher", i)
+		}
+		selector, err := parseLabelSelector(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("direct_messages.rules[%d]: %w", i, err)
+		}
+		compiled = append(compiled, onCallRule{selector: selector, users: rule.Users})
+	}
+	return compiled, nil
+}
+
+// UsersForAlert returns the users of every matching rule followed by the
+// user named in UserLabel, without duplicates. Keep usernames found in
+// users.mapping are replaced by their Mattermost usernames.
+func (r *OnCallResolver) UsersForAlert(severity string, labels map[string]string) []string {
+	var users []string
+	add := func(name string) {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" {
+			return
+		}
+		if mapped, ok := r.users.GetMattermostUsername(name); ok && mapped != "" {
+			name = mapped
+		}
+		if !slices.Contains(users, name) {
+			users = append(users, name)
+		}
+	}
+
+	for _, rule := range r.rules {
+		if rule.selector.matches(severity, labels) {
+			for _, user := range rule.users {
+				add(user)
+			}
+		}
+	}
+	if r.userLabel != "" {
+		add(labels[r.userLabel])
+	}
+	return users
+}
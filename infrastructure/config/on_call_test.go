@@ -0,0 +1,108 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnCallResolverUsersForAlert(t *testing.T) {
+	cfg := &FileConfig{
+		Users: UsersConfig{Mapping: map[string]string{"alice.mm": "alice@keep"}},
+		DirectMessages: DirectMessagesConfig{
+			Enabled:   true,
+			UserLabel: "oncall",
+			Rules: []DirectMessageRule{
+				{Match: []string{"severity=critical", "team=payments"}, Users: []string{"@bob", "alice@keep"}},
+				{Match: []string{`namespace=~"prod-.*"`}, Users: []string{"carol", "bob"}},
+			},
+		},
+	}
+	resolver, err := NewOnCallResolver(cfg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		severity string
+		labels   map[string]string
+		want     []string
+	}{
+		{
+			name:     "rules in order without duplicates, keep users mapped",
+			severity: "critical",
+			labels:   map[string]string{"team": "payments", "namespace": "prod-eu"},
+			want:     []string{"bob", "alice.mm", "carol"},
+		},
+		{
+			name:     "user label",
+			severity: "high",
+			labels:   map[string]string{"team": "payments", "oncall": "@dave"},
+			want:     []string{"dave"},
+		},
+		{
+			name:     "user label with keep username",
+			severity: "high",
+			labels:   map[string]string{"oncall": "alice@keep"},
+			want:     []string{"alice.mm"},
+		},
+		{
+			name:     "no match",
+			severity: "warning",
+			labels:   map[string]string{"team": "payments"},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolver.UsersForAlert(tt.severity, tt.labels))
+		})
+	}
+}
+
+type staticUserMapper map[string]string
+
+func (m staticUserMapper) GetKeepUsername(string) (string, bool) { return "", false }
+
+func (m staticUserMapper) GetMattermostUsername(keepUsername string) (string, bool) {
+	mmUser, ok := m[keepUsername]
+	return mmUser, ok
+}
+
+func TestOnCallResolverSetUserMapper(t *testing.T) {
+	cfg := &FileConfig{DirectMessages: DirectMessagesConfig{Enabled: true, UserLabel: "oncall"}}
+	resolver, err := NewOnCallResolver(cfg)
+	require.NoError(t, err)
+	resolver.SetUserMapper(staticUserMapper{"alice@example.com": "alice"})
+
+	assert.Equal(t, []string{"alice"}, resolver.UsersForAlert("high", map[string]string{"oncall": "alice@example.com"}))
+}
+
+func TestValidateDirectMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		dm      DirectMessagesConfig
+		wantErr string
+	}{
+		{name: "disabled ignores rules", dm: DirectMessagesConfig{Rules: []DirectMessageRule{{}}}},
+		{name: "user label only", dm: DirectMessagesConfig{Enabled: true, UserLabel: "oncall"}},
+		{name: "nothing to match", dm: DirectMessagesConfig{Enabled: true}, wantErr: "needs user_label or at least one rule"},
+		{name: "rule without users", dm: DirectMessagesConfig{Enabled: true, Rules: []DirectMessageRule{{Match: []string{"team=a"}}}}, wantErr: "direct_messages.rules[0].users"},
+		{name: "rule without match", dm: DirectMessagesConfig{Enabled: true, Rules: []DirectMessageRule{{Users: []string{"bob"}}}}, wantErr: "direct_messages.rules[0].match"},
+		{name: "bad matcher", dm: DirectMessagesConfig{Enabled: true, Rules: []DirectMessageRule{{Match: []string{"team"}, Users: []string{"bob"}}}}, wantErr: "invalid label matcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{DirectMessages: tt.dm}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
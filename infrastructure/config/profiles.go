@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path"
+)
+
+// MessageProfileConfig changes how alert posts look in some channels: those
+// listed under channels and those of routing rules naming the profile.
+// Colors, emoji and style are merged over message's; every other setting left out
+// keeps its global value.
+type MessageProfileConfig struct {
+	Name     string              `yaml:"name"`
+	Channels []string            `yaml:"channels"` // channel IDs
+	Colors   map[string]string   `yaml:"colors"`
+	Emoji    map[string]string   `yaml:"emoji"`
+	Style    map[string]string   `yaml:"style"`
+	Footer   *FooterConfig       `yaml:"footer"`
+	Fields   ProfileFieldsConfig `yaml:"fields"`
+	Labels   ProfileLabelsConfig `yaml:"labels"`
+	Mentions *MentionsConfig     `yaml:"mentions"` // replaces the mentions section
+}
+
+// ProfileFieldsConfig overrides message.fields for a profile's channels.
+type ProfileFieldsConfig struct {
+	ShowSeverity     *bool  `yaml:"show_severity"`
+	ShowDescription  *bool  `yaml:"show_description"`
+	ShowFingerprint  *bool  `yaml:"show_fingerprint"`
+	SeverityPosition string `yaml:"severity_position"`
+	Compact          *bool  `yaml:"compact"`
+}
+
+// ProfileLabelsConfig overrides the labels shown for a profile's channels.
+// An empty display list shows every label, as in labels.display.
+type ProfileLabelsConfig struct {
+	Display []string          `yaml:"display"`
+	Exclude []string          `yaml:"exclude"`
+	Rename  map[string]string `yaml:"rename"`
+}
+
+// profileChannels maps each channel with a message profile to the index of
+// the profile.
+func (c *FileConfig) profileChannels() (map[string]int, error) {
+	byName := make(map[string]int, len(c.Message.Profiles))
+	byChannel := make(map[string]int)
+	assign := func(channelID string, i int) error {
+		if j, ok := byChannel[channelID]; ok && j != i {
+			return fmt.Errorf("channel %s is in message profiles %q and %q", channelID, c.Message.Profiles[j].Name, c.Message.Profiles[i].Name)
+		}
+		byChannel[channelID] = i
+		return nil
+	}
+
+	for i, p := range c.Message.Profiles {
+		if p.Name == "" {
+			return nil, fmt.Errorf("message.profiles[%d].name is required", i)
+		}
+		if _, ok := byName[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate message profile %q", p.Name)
+		}
+		byName[p.Name] = i
+		for _, channelID := range p.Channels {
+			if err := assign(channelID, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	routed := func(kind string, ruleIndex int, profile, channelID string) error {
+		if profile == "" {
+			return nil
+		}
+		i, ok := byName[profile]
+		if !ok {
+			return fmt.Errorf("%s[%d]: unknown message profile %q", kind, ruleIndex, profile)
+		}
+		return assign(channelID, i)
+	}
+	for i, rule := range c.Channels.Routing {
+		if err := routed("channels.routing", i, rule.Profile, rule.ChannelID); err != nil {
+			return nil, err
+		}
+	}
+	for i, rule := range c.Channels.LabelRouting.Rules {
+		if err := routed("channels.label_routing.rules", i, rule.Profile, rule.ChannelID); err != nil {
+			return nil, err
+		}
+	}
+	return byChannel, nil
+}
+
+func (c *FileConfig) validateMessageProfiles() error {
+	if _, err := c.profileChannels(); err != nil {
+		return err
+	}
+	for i, p := range c.Message.Profiles {
+		if err := validateMessageStyle(fmt.Sprintf("message.profiles[%d].style", i), p.Style); err != nil {
+			return err
+		}
+		for _, pattern := range p.Labels.Exclude {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("message.profiles[%d]: invalid label exclude pattern %q: %w", i, pattern, err)
+			}
+		}
+		if p.Mentions != nil && p.Mentions.Enabled {
+			if _, err := NewMentionPolicy(c.withProfile(p)); err != nil {
+				return fmt.Errorf("message.profiles[%d].mentions: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MessageProfiles returns, by channel ID, the config alert posts in that
+// channel are rendered with: this config with the channel's message profile
+// applied. Channels without a profile are not listed.
+func (c *FileConfig) MessageProfiles() map[string]*FileConfig {
+	byChannel, err := c.profileChannels()
+	if err != nil || len(byChannel) == 0 {
+		return nil
+	}
+	derived := make([]*FileConfig, len(c.Message.Profiles))
+	profiles := make(map[string]*FileConfig, len(byChannel))
+	for channelID, i := range byChannel {
+		if derived[i] == nil {
+			derived[i] = c.withProfile(c.Message.Profiles[i])
+		}
+		profiles[channelID] = derived[i]
+	}
+	return profiles
+}
+
+// withProfile returns a copy of c with p applied.
+func (c *FileConfig) withProfile(p MessageProfileConfig) *FileConfig {
+	d := *c
+	d.Message.Profiles = nil
+
+	d.Message.Colors = mergeStrings(c.Message.Colors, p.Colors)
+	d.Message.Emoji = mergeStrings(c.Message.Emoji, p.Emoji)
+	d.Message.Style = mergeStrings(c.Message.Style, p.Style)
+	if p.Footer != nil {
+		d.Message.Footer = *p.Footer
+	}
+
+	if p.Fields.ShowSeverity != nil {
+		d.Message.Fields.ShowSeverity = p.Fields.ShowSeverity
+	}
+	if p.Fields.ShowDescription != nil {
+		d.Message.Fields.ShowDescription = p.Fields.ShowDescription
+	}
+	if p.Fields.ShowFingerprint != nil {
+		d.Message.Fields.ShowFingerprint = *p.Fields.ShowFingerprint
+	}
+	if p.Fields.SeverityPosition != "" {
+		d.Message.Fields.SeverityPosition = p.Fields.SeverityPosition
+	}
+	if p.Fields.Compact != nil {
+		d.Message.Fields.Compact = *p.Fields.Compact
+	}
+
+	if p.Labels.Display != nil {
+		d.Labels.Display = p.Labels.Display
+	}
+	if p.Labels.Exclude != nil {
+		d.Labels.Exclude = p.Labels.Exclude
+	}
+	if p.Labels.Rename != nil {
+		d.Labels.Rename = p.Labels.Rename
+	}
+
+	if p.Mentions != nil {
+		d.Mentions = *p.Mentions
+	}
+	return &d
+}
+
+func mergeStrings(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	maps.Copy(merged, base)
+	maps.Copy(merged, override)
+	return merged
+}
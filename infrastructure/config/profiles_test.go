@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageProfiles(t *testing.T) {
+	cfg := DefaultFileConfig()
+	cfg.Channels.Routing = []RoutingRule{{Severity: "critical", ChannelID: "ch-oncall", Profile: "pager"}}
+	cfg.Channels.LabelRouting.Rules = []LabelRouteRule{{Match: []string{"team=payments"}, ChannelID: "ch-payments"}}
+	cfg.Message.Profiles = []MessageProfileConfig{
+		{
+			Name:     "pager",
+			Channels: []string{"ch-mobile"},
+			Colors:   map[string]string{"critical": "#FF0000"},
+			Fields:   ProfileFieldsConfig{Compact: boolPtr(true)},
+			Labels:   ProfileLabelsConfig{Display: []string{"host"}},
+			Mentions: &MentionsConfig{Enabled: true, Rules: []MentionRule{{Mention: []string{"@here"}}}},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	profiles := cfg.MessageProfiles()
+	require.Len(t, profiles, 2)
+	assert.Same(t, profiles["ch-mobile"], profiles["ch-oncall"], "channels of a profile share its config")
+	assert.NotContains(t, profiles, "ch-payments")
+
+	pager := profiles["ch-oncall"]
+	assert.Equal(t, "#FF0000", pager.ColorForSeverity("critical"))
+	assert.Equal(t, cfg.ColorForSeverity("warning"), pager.ColorForSeverity("warning"), "colors left out keep their global value")
+	assert.True(t, pager.Message.Fields.Compact)
+	assert.Equal(t, []string{"host"}, pager.Labels.Display)
+	assert.True(t, pager.Mentions.Enabled)
+	assert.Empty(t, pager.Message.Profiles)
+
+	assert.False(t, cfg.Message.Fields.Compact, "the global config is left alone")
+	assert.NotEqual(t, "#FF0000", cfg.ColorForSeverity("critical"))
+	assert.Nil(t, DefaultFileConfig().MessageProfiles())
+}
+
+func TestValidateMessageProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []MessageProfileConfig
+		routing  []RoutingRule
+		wantErr  string
+	}{
+		{name: "valid", profiles: []MessageProfileConfig{{Name: "compact", Channels: []string{"ch-1"}}}},
+		{name: "missing name", profiles: []MessageProfileConfig{{Channels: []string{"ch-1"}}}, wantErr: "message.profiles[0].name is required"},
+		{name: "duplicate name", profiles: []MessageProfileConfig{{Name: "a"}, {Name: "a"}}, wantErr: `duplicate message profile "a"`},
+		{
+			name:     "channel in two profiles",
+			profiles: []MessageProfileConfig{{Name: "a", Channels: []string{"ch-1"}}, {Name: "b", Channels: []string{"ch-1"}}},
+			wantErr:  `channel ch-1 is in message profiles "a" and "b"`,
+		},
+		{
+			name:     "routing rule conflicts with profile channels",
+			profiles: []MessageProfileConfig{{Name: "a", Channels: []string{"ch-1"}}, {Name: "b"}},
+			routing:  []RoutingRule{{Severity: "critical", ChannelID: "ch-1", Profile: "b"}},
+			wantErr:  `channel ch-1 is in message profiles "a" and "b"`,
+		},
+		{
+			name:     "unknown profile",
+			profiles: []MessageProfileConfig{{Name: "a"}},
+			routing:  []RoutingRule{{Severity: "critical", ChannelID: "ch-1", Profile: "pager"}},
+			wantErr:  `channels.routing[0]: unknown message profile "pager"`,
+		},
+		{
+			name:     "bad exclude pattern",
+			profiles: []MessageProfileConfig{{Name: "a", Labels: ProfileLabelsConfig{Exclude: []string{"[bad"}}}},
+			wantErr:  "message.profiles[0]: invalid label exclude pattern",
+		},
+		{
+			name:     "bad mentions",
+			profiles: []MessageProfileConfig{{Name: "a", Mentions: &MentionsConfig{Enabled: true}}},
+			wantErr:  "message.profiles[0].mentions: mentions.rules must list at least one rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{
+				Channels: ChannelsConfig{Routing: tt.routing},
+				Message:  MessageConfig{Profiles: tt.profiles},
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type quietHoursRule struct {
+	severities []string
+	channels   []string
+	decision   port.QuietDecision
+}
+
+// quietHours is a daily period between two offsets from midnight in
+// location, plus all of Saturday and Sunday with weekends.
+type quietHours struct {
+	start, end time.Duration
+	location   *time.Location
+	weekends   bool
+}
+
+// QuietHoursPolicy decides how new firing alerts are posted during the quiet
+// hours in quiet_hours.
+type QuietHoursPolicy struct {
+	hours           *quietHours
+	rules           []quietHoursRule
+	digestChannelID string
+}
+
+// NewQuietHoursPolicy compiles the quiet hours and rules of cfg.
+func NewQuietHoursPolicy(cfg *FileConfig) (*QuietHoursPolicy, error) {
+	qh := cfg.QuietHours
+	hours, err := compileQuietHours("quiet_hours", qh.QuietHoursConfig)
+	if err != nil {
+		return nil, err
+	}
+	if hours == nil {
+		return nil, fmt.Errorf("quiet_hours.start and quiet_hours.end are required when quiet hours are enabled")
+	}
+	if len(qh.Rules) == 0 {
+		return nil, fmt.Errorf("quiet_hours.rules must list at least one rule when quiet hours are enabled")
+	}
+
+	rules := make([]quietHoursRule, 0, len(qh.Rules))
+	for i, rule := range qh.Rules {
+		severities := make([]string, 0, len(rule.Severities))
+		for _, severity := range rule.Severities {
+			s, err := alert.NewSeverity(severity)
+			if err != nil {
+				return nil, fmt.Errorf("quiet_hours.rules[%d]: %w", i, err)
+			}
+			severities = append(severities, s.String())
+		}
+		switch rule.Action {
+		case port.QuietNotify, port.QuietMute, port.QuietHold:
+		case port.QuietRoute:
+			if rule.ChannelID == "" {
+				return nil, fmt.Errorf("quiet_hours.rules[%d].channel_id is required when action is %q", i, port.QuietRoute)
+			}
+		default:
+			return nil, fmt.Errorf("invalid quiet_hours.rules[%d].action %q: must be %q, %q, %q or %q",
+				i, rule.Action, port.QuietNotify, port.QuietMute, port.QuietRoute, port.QuietHold)
+		}
+		rules = append(rules, quietHoursRule{
+			severities: severities,
+			channels:   rule.Channels,
+			decision:   port.QuietDecision{Action: rule.Action, ChannelID: rule.ChannelID},
+		})
+	}
+	return &QuietHoursPolicy{hours: hours, rules: rules, digestChannelID: qh.DigestChannelID}, nil
+}
+
+// Active reports whether quiet hours are on at now.
+func (p *QuietHoursPolicy) Active(now time.Time) bool {
+	return p.hours.contains(now)
+}
+
+// Decide returns the decision of the first rule matching severity and
+// channelID, or false outside quiet hours or when no rule matches.
+func (p *QuietHoursPolicy) Decide(severity, channelID string, now time.Time) (port.QuietDecision, bool) {
+	if !p.hours.contains(now) {
+		return port.QuietDecision{}, false
+	}
+	for _, rule := range p.rules {
+		if len(rule.severities) > 0 && !slices.Contains(rule.severities, strings.ToLower(severity)) {
+			continue
+		}
+		if len(rule.channels) > 0 && !slices.Contains(rule.channels, channelID) {
+			continue
+		}
+		return rule.decision, true
+	}
+	return port.QuietDecision{}, false
+}
+
+// DigestChannelID returns quiet_hours.digest_channel_id.
+func (p *QuietHoursPolicy) DigestChannelID() string {
+	return p.digestChannelID
+}
+
+// compileQuietHours compiles cfg, returning nil when it sets no quiet hours.
+// Errors name the settings after prefix.
+func compileQuietHours(prefix string, cfg QuietHoursConfig) (*quietHours, error) {
+	if cfg.Start == "" && cfg.End == "" {
+		return nil, nil
+	}
+	start, err := parseTimeOfDay(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s.start %q: %w", prefix, cfg.Start, err)
+	}
+	end, err := parseTimeOfDay(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s.end %q: %w", prefix, cfg.End, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("%s.start and end must differ", prefix)
+	}
+	q := &quietHours{start: start, end: end, location: time.UTC, weekends: cfg.Weekends}
+	if cfg.Timezone != "" {
+		if q.location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid %s.timezone %q: %w", prefix, cfg.Timezone, err)
+		}
+	}
+	return q, nil
+}
+
+// parseTimeOfDay parses "15:04" into the offset from midnight.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains reports whether now falls into the quiet hours. Start is
+// inclusive, end exclusive.
+func (q *quietHours) contains(now time.Time) bool {
+	local := now.In(q.location)
+	if q.weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return true
+	}
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if q.start < q.end {
+		return offset >= q.start && offset < q.end
+	}
+	return offset >= q.start || offset < q.end
+}
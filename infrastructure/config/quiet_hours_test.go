@@ -0,0 +1,97 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func TestQuietHoursPolicyDecide(t *testing.T) {
+	cfg := &FileConfig{
+		QuietHours: QuietHoursPolicyConfig{
+			Enabled:          true,
+			QuietHoursConfig: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin", Weekends: true},
+			Rules: []QuietHoursRule{
+				{Severities: []string{"critical"}, Action: "notify"},
+				{Channels: []string{"ch-payments"}, Action: "route", ChannelID: "ch-night"},
+				{Severities: []string{"high"}, Action: "mute"},
+				{Severities: []string{"warning", "low"}, Action: "hold"},
+			},
+			DigestChannelID: "ch-digest",
+		},
+	}
+	policy, err := NewQuietHoursPolicy(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ch-digest", policy.DigestChannelID())
+
+	day := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)      // Monday
+	night := time.Date(2026, 1, 5, 22, 30, 0, 0, time.UTC)   // 23:30 in Berlin
+	weekend := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC) // Saturday
+
+	tests := []struct {
+		name      string
+		severity  string
+		channelID string
+		now       time.Time
+		want      port.QuietDecision
+		wantOK    bool
+	}{
+		{name: "daytime", severity: "warning", channelID: "ch-ops", now: day},
+		{name: "critical notifies", severity: "critical", channelID: "ch-payments", now: night, want: port.QuietDecision{Action: "notify"}, wantOK: true},
+		{name: "channel routed", severity: "warning", channelID: "ch-payments", now: night, want: port.QuietDecision{Action: "route", ChannelID: "ch-night"}, wantOK: true},
+		{name: "muted", severity: "HIGH", channelID: "ch-ops", now: night, want: port.QuietDecision{Action: "mute"}, wantOK: true},
+		{name: "held", severity: "low", channelID: "ch-ops", now: night, want: port.QuietDecision{Action: "hold"}, wantOK: true},
+		{name: "no rule", severity: "info", channelID: "ch-ops", now: night},
+		{name: "weekend", severity: "warning", channelID: "ch-ops", now: weekend, want: port.QuietDecision{Action: "hold"}, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policy.Decide(tt.severity, tt.channelID, tt.now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.False(t, policy.Active(day))
+	assert.True(t, policy.Active(night))
+	assert.True(t, policy.Active(weekend))
+}
+
+func TestValidateQuietHours(t *testing.T) {
+	hours := QuietHoursConfig{Start: "22:00", End: "07:00"}
+	hold := []QuietHoursRule{{Action: "hold"}}
+
+	tests := []struct {
+		name    string
+		quiet   QuietHoursPolicyConfig
+		wantErr string
+	}{
+		{name: "disabled ignores fields", quiet: QuietHoursPolicyConfig{Rules: []QuietHoursRule{{Action: "sleep"}}}},
+		{name: "valid", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, Rules: hold, CheckInterval: "1m"}},
+		{name: "no hours", quiet: QuietHoursPolicyConfig{Enabled: true, Rules: hold, CheckInterval: "1m"}, wantErr: "quiet_hours.start and quiet_hours.end are required"},
+		{name: "no rules", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, CheckInterval: "1m"}, wantErr: "quiet_hours.rules must list at least one rule"},
+		{name: "unknown action", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, Rules: []QuietHoursRule{{Action: "sleep"}}, CheckInterval: "1m"}, wantErr: `invalid quiet_hours.rules[0].action "sleep"`},
+		{name: "route without channel", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, Rules: []QuietHoursRule{{Action: "route"}}, CheckInterval: "1m"}, wantErr: "quiet_hours.rules[0].channel_id is required"},
+		{name: "unknown severity", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, Rules: []QuietHoursRule{{Severities: []string{"urgent"}, Action: "hold"}}, CheckInterval: "1m"}, wantErr: "quiet_hours.rules[0]"},
+		{name: "bad start", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: QuietHoursConfig{Start: "25:00", End: "07:00"}, Rules: hold, CheckInterval: "1m"}, wantErr: "invalid quiet_hours.start"},
+		{name: "short interval", quiet: QuietHoursPolicyConfig{Enabled: true, QuietHoursConfig: hours, Rules: hold, CheckInterval: "1s"}, wantErr: "quiet_hours.check_interval must be at least 10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{QuietHours: tt.quiet}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
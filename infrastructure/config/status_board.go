@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// StatusBoardConfig keeps a status board post in channels, listing their
+// active alerts grouped by severity. Without Channels every channel with
+// active alerts gets one. Boards follow alert transitions within
+// UpdateInterval and are rewritten every ResyncInterval to pick up changes
+// made by other replicas.
+type StatusBoardConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Channels       []string `yaml:"channels"`
+	Pin            bool     `yaml:"pin"`
+	UpdateInterval string   `yaml:"update_interval"` // default: 10s
+	ResyncInterval string   `yaml:"resync_interval"` // default: 5m
+}
+
+func (c *FileConfig) validateStatusBoard() error {
+	if !c.StatusBoard.Enabled {
+		return nil
+	}
+	for i, channel := range c.StatusBoard.Channels {
+		if channel == "" {
+			return fmt.Errorf("status_board.channels[%d] must not be empty", i)
+		}
+		if slices.Contains(c.StatusBoard.Channels[:i], channel) {
+			return fmt.Errorf("status_board.channels lists %q twice", channel)
+		}
+	}
+	update, err := time.ParseDuration(c.StatusBoard.UpdateInterval)
+	if err != nil {
+		return fmt.Errorf("invalid status_board.update_interval %q: %w", c.StatusBoard.UpdateInterval, err)
+	}
+	if update < time.Second {
+		return fmt.Errorf("status_board.update_interval must be at least 1s, got %s", update)
+	}
+	resync, err := time.ParseDuration(c.StatusBoard.ResyncInterval)
+	if err != nil {
+		return fmt.Errorf("invalid status_board.resync_interval %q: %w", c.StatusBoard.ResyncInterval, err)
+	}
+	if resync < update {
+		return fmt.Errorf("status_board.resync_interval must be at least update_interval (%s), got %s", update, resync)
+	}
+	return nil
+}
+
+// StatusBoardUpdateInterval returns how often boards follow alert
+// transitions, falling back to ten seconds.
+func (c *FileConfig) StatusBoardUpdateInterval() time.Duration {
+	return parseDurationOr(c.StatusBoard.UpdateInterval, 10*time.Second)
+}
+
+// StatusBoardResyncInterval returns how often boards are rewritten whether
+// or not an alert changed, falling back to five minutes.
+func (c *FileConfig) StatusBoardResyncInterval() time.Duration {
+	return parseDurationOr(c.StatusBoard.ResyncInterval, 5*time.Minute)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStatusBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		board   StatusBoardConfig
+		wantErr string
+	}{
+		{name: "disabled", board: StatusBoardConfig{UpdateInterval: "soon"}},
+		{name: "every channel", board: StatusBoardConfig{Enabled: true, UpdateInterval: "10s", ResyncInterval: "5m"}},
+		{name: "channels", board: StatusBoardConfig{Enabled: true, Channels: []string{"channel-1", "~alerts"}, UpdateInterval: "10s", ResyncInterval: "5m"}},
+		{name: "empty channel", board: StatusBoardConfig{Enabled: true, Channels: []string{""}, UpdateInterval: "10s", ResyncInterval: "5m"}, wantErr: "status_board.channels[0] must not be empty"},
+		{name: "duplicate channel", board: StatusBoardConfig{Enabled: true, Channels: []string{"channel-1", "channel-1"}, UpdateInterval: "10s", ResyncInterval: "5m"}, wantErr: `status_board.channels lists "channel-1" twice`},
+		{name: "invalid update interval", board: StatusBoardConfig{Enabled: true, UpdateInterval: "soon", ResyncInterval: "5m"}, wantErr: `invalid status_board.update_interval "soon"`},
+		{name: "short update interval", board: StatusBoardConfig{Enabled: true, UpdateInterval: "100ms", ResyncInterval: "5m"}, wantErr: "status_board.update_interval must be at least 1s"},
+		{name: "invalid resync interval", board: StatusBoardConfig{Enabled: true, UpdateInterval: "10s", ResyncInterval: "never"}, wantErr: `invalid status_board.resync_interval "never"`},
+		{name: "resync before update", board: StatusBoardConfig{Enabled: true, UpdateInterval: "1m", ResyncInterval: "30s"}, wantErr: "status_board.resync_interval must be at least update_interval (1m0s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{StatusBoard: tt.board}
+			err := cfg.validateStatusBoard()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestStatusBoardIntervals(t *testing.T) {
+	cfg := &FileConfig{}
+	assert.Equal(t, 10*time.Second, cfg.StatusBoardUpdateInterval())
+	assert.Equal(t, 5*time.Minute, cfg.StatusBoardResyncInterval())
+
+	cfg.applyDefaults()
+	assert.Equal(t, "10s", cfg.StatusBoard.UpdateInterval)
+	assert.Equal(t, "5m", cfg.StatusBoard.ResyncInterval)
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tenantNamePattern keeps tenant names usable as a URL path segment.
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// TenantConfig serves another Keep and Mattermost pair from the same bridge,
+// e.g. staging next to prod, or another organization. A tenant gets its own
+// clients, store and use cases, and its routes are served under
+// {BASE_PATH}/{name}: Keep posts its alerts to
+// {BASE_PATH}/{name}/api/v1/webhook/alert. Every setting of the config file
+// other than channels applies to it too, unless ConfigFile gives it a config
+// file of its own. Secrets are read from the environment variables named.
+type TenantConfig struct {
+	Name       string                 `yaml:"name"` // e.g. staging
+	Keep       TenantKeepConfig       `yaml:"keep"`
+	Mattermost TenantMattermostConfig `yaml:"mattermost"`
+	Channels   ChannelsConfig         `yaml:"channels"`
+	Storage    TenantStorageConfig    `yaml:"storage"`
+	ConfigFile string                 `yaml:"config_file"` // replaces the main config file, channels included
+}
+
+// TenantKeepConfig is the Keep instance of a tenant.
+type TenantKeepConfig struct {
+	URL       string `yaml:"url"`
+	UIURL     string `yaml:"ui_url"`      // default: url
+	APIKeyEnv string `yaml:"api_key_env"` // e.g. STAGING_KEEP_API_KEY
+}
+
+// TenantMattermostConfig is the Mattermost server and bot of a tenant.
+type TenantMattermostConfig struct {
+	URL      string `yaml:"url"`       // default: MATTERMOST_URL
+	TokenEnv string `yaml:"token_env"` // e.g. STAGING_MATTERMOST_TOKEN
+	TeamID   string `yaml:"team_id"`   // team of channels named with ~; default: MATTERMOST_TEAM_ID unless url is set
+}
+
+// TenantStorageConfig keeps a tenant's posts apart from the others'.
+type TenantStorageConfig struct {
+	RedisDB *int   `yaml:"redis_db"` // Valkey database, required with the valkey backend
+	Path    string `yaml:"path"`     // bolt file; default: STORAGE_PATH with -{name} before the extension
+}
+
+func (c *FileConfig) validateTenants() error {
+	names := make(map[string]bool, len(c.Tenants))
+	redisDBs := make(map[int]string, len(c.Tenants))
+	for i, t := range c.Tenants {
+		if !tenantNamePattern.MatchString(t.Name) {
+			return fmt.Errorf("tenants[%d].name must be lowercase letters, digits and dashes, got %q", i, t.Name)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("tenants[%d]: duplicate tenant %q", i, t.Name)
+		}
+		names[t.Name] = true
+		if t.Keep.URL == "" {
+			return fmt.Errorf("tenants[%d].keep.url is required", i)
+		}
+		if t.Keep.APIKeyEnv == "" {
+			return fmt.Errorf("tenants[%d].keep.api_key_env is required", i)
+		}
+		if t.Mattermost.TokenEnv == "" {
+			return fmt.Errorf("tenants[%d].mattermost.token_env is required", i)
+		}
+		if t.ConfigFile == "" && t.Channels.DefaultChannelID == "" {
+			return fmt.Errorf("tenants[%d].channels.default_channel_id is required", i)
+		}
+		if t.Storage.RedisDB != nil {
+			if other, ok := redisDBs[*t.Storage.RedisDB]; ok {
+				return fmt.Errorf("tenants[%d]: redis_db %d is already used by tenant %q", i, *t.Storage.RedisDB, other)
+			}
+			redisDBs[*t.Storage.RedisDB] = t.Name
+		}
+	}
+	return nil
+}
+
+// ForTenant returns the file config of tenant t: t.ConfigFile when set,
+// otherwise a copy of c with t's channels.
+func (c *FileConfig) ForTenant(t TenantConfig) (*FileConfig, error) {
+	if t.ConfigFile != "" {
+		cfg, err := LoadFromFile(t.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: load %s: %w", t.Name, t.ConfigFile, err)
+		}
+		if len(cfg.Tenants) > 0 {
+			return nil, fmt.Errorf("tenant %s: %s must not list tenants", t.Name, t.ConfigFile)
+		}
+		return cfg, nil
+	}
+	derived := *c
+	derived.Channels = t.Channels
+	derived.Tenants = nil
+	if derived.Channels.LabelRouting.Mode == "" {
+		derived.Channels.LabelRouting.Mode = RoutingModeFirstMatch
+	}
+	if err := derived.Validate(); err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", t.Name, err)
+	}
+	return &derived, nil
+}
+
+// ForTenant returns the settings of tenant t: c with t's Keep, Mattermost
+// and storage, and its routes under {BASE_PATH}/{name}. Proxy and TLS
+// settings carry over; slash commands and enrichment signing stay with the
+// main bridge.
+func (c *Config) ForTenant(t TenantConfig) (*Config, error) {
+	derived := *c
+	derived.Server.BasePath = c.Server.BasePath + "/" + t.Name
+
+	derived.Keep = KeepConfig{
+		URL:      t.Keep.URL,
+		APIKey:   os.Getenv(t.Keep.APIKeyEnv),
+		UIURL:    t.Keep.UIURL,
+		Outbound: c.Keep.Outbound,
+	}
+	if derived.Keep.UIURL == "" {
+		derived.Keep.UIURL = t.Keep.URL
+	}
+	if derived.Keep.APIKey == "" {
+		return nil, fmt.Errorf("tenant %s: %s is not set", t.Name, t.Keep.APIKeyEnv)
+	}
+
+	derived.Mattermost = MattermostConfig{
+		URL:      c.Mattermost.URL,
+		Token:    os.Getenv(t.Mattermost.TokenEnv),
+		TeamID:   t.Mattermost.TeamID,
+		Outbound: c.Mattermost.Outbound,
+	}
+	if t.Mattermost.URL != "" {
+		derived.Mattermost.URL = t.Mattermost.URL
+	} else if derived.Mattermost.TeamID == "" {
+		derived.Mattermost.TeamID = c.Mattermost.TeamID
+	}
+	if derived.Mattermost.Token == "" {
+		return nil, fmt.Errorf("tenant %s: %s is not set", t.Name, t.Mattermost.TokenEnv)
+	}
+
+	prefix, ok := strings.CutSuffix(c.CallbackURL, callbackRoute)
+	if !ok {
+		return nil, fmt.Errorf("tenant %s: CALLBACK_URL must end with %s to derive the tenant's callback URL", t.Name, callbackRoute)
+	}
+	derived.CallbackURL = prefix + "/" + t.Name + callbackRoute
+
+	switch c.Storage.Backend {
+	case "", StorageBackendValkey:
+		if t.Storage.RedisDB == nil {
+			return nil, fmt.Errorf("tenant %s: storage.redis_db is required with STORAGE_BACKEND=%s", t.Name, StorageBackendValkey)
+		}
+		if *t.Storage.RedisDB == c.Redis.DB {
+			return nil, fmt.Errorf("tenant %s: storage.redis_db %d is REDIS_DB of the main bridge", t.Name, c.Redis.DB)
+		}
+		derived.Redis.DB = *t.Storage.RedisDB
+	case StorageBackendBolt:
+		derived.Storage.Path = t.Storage.Path
+		if derived.Storage.Path == "" {
+			ext := filepath.Ext(c.Storage.Path)
+			derived.Storage.Path = strings.TrimSuffix(c.Storage.Path, ext) + "-" + t.Name + ext
+		}
+	case StorageBackendPostgres:
+		return nil, fmt.Errorf("tenant %s: tenants are not supported with STORAGE_BACKEND=%s", t.Name, StorageBackendPostgres)
+	}
+	return &derived, nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stagingTenant() TenantConfig {
+	db := 1
+	return TenantConfig{
+		Name:       "staging",
+		Keep:       TenantKeepConfig{URL: "https://keep-staging.example.com", APIKeyEnv: "STAGING_KEEP_API_KEY"},
+		Mattermost: TenantMattermostConfig{TokenEnv: "STAGING_MATTERMOST_TOKEN"},
+		Channels:   ChannelsConfig{DefaultChannelID: "staging-alerts"},
+		Storage:    TenantStorageConfig{RedisDB: &db},
+	}
+}
+
+func TestValidateTenants(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  func(t *TenantConfig)
+		wantErr string
+	}{
+		{name: "valid", tenant: func(t *TenantConfig) {}},
+		{name: "name with a slash", tenant: func(t *TenantConfig) { t.Name = "eu/prod" }, wantErr: `tenants[0].name must be lowercase letters, digits and dashes, got "eu/prod"`},
+		{name: "no keep url", tenant: func(t *TenantConfig) { t.Keep.URL = "" }, wantErr: "tenants[0].keep.url is required"},
+		{name: "no api key", tenant: func(t *TenantConfig) { t.Keep.APIKeyEnv = "" }, wantErr: "tenants[0].keep.api_key_env is required"},
+		{name: "no token", tenant: func(t *TenantConfig) { t.Mattermost.TokenEnv = "" }, wantErr: "tenants[0].mattermost.token_env is required"},
+		{name: "no channel", tenant: func(t *TenantConfig) { t.Channels.DefaultChannelID = "" }, wantErr: "tenants[0].channels.default_channel_id is required"},
+		{name: "own config file", tenant: func(t *TenantConfig) { t.Channels.DefaultChannelID, t.ConfigFile = "", "/etc/kmbridge/staging.yaml" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := stagingTenant()
+			tt.tenant(&tenant)
+			cfg := &FileConfig{Tenants: []TenantConfig{tenant}}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	cfg := &FileConfig{Tenants: []TenantConfig{stagingTenant(), stagingTenant()}}
+	assert.ErrorContains(t, cfg.Validate(), `tenants[1]: duplicate tenant "staging"`)
+	other := stagingTenant()
+	other.Name = "qa"
+	cfg = &FileConfig{Tenants: []TenantConfig{stagingTenant(), other}}
+	assert.ErrorContains(t, cfg.Validate(), `tenants[1]: redis_db 1 is already used by tenant "staging"`)
+}
+
+func TestConfigForTenant(t *testing.T) {
+	t.Setenv("STAGING_KEEP_API_KEY", "staging-key")
+	t.Setenv("STAGING_MATTERMOST_TOKEN", "staging-token")
+	cfg := &Config{
+		Server:      ServerConfig{BasePath: "/bridge"},
+		Mattermost:  MattermostConfig{URL: "https://mattermost.example.com", Token: "token", SlashCommandToken: "slash", TeamID: "team-main"},
+		Keep:        KeepConfig{URL: "https://keep.example.com", APIKey: "key", UIURL: "https://keep-ui.example.com", SigningKeyFile: "/keys/keep.pem"},
+		CallbackURL: "https://bridge.example.com/bridge/api/v1/callback",
+	}
+
+	tenant, err := cfg.ForTenant(stagingTenant())
+	require.NoError(t, err)
+	assert.Equal(t, "/bridge/staging", tenant.Server.BasePath)
+	assert.Equal(t, "https://bridge.example.com/bridge/staging/api/v1/callback", tenant.CallbackURL)
+	assert.Equal(t, KeepConfig{URL: "https://keep-staging.example.com", APIKey: "staging-key", UIURL: "https://keep-staging.example.com"}, tenant.Keep)
+	assert.Equal(t, MattermostConfig{URL: "https://mattermost.example.com", Token: "staging-token", TeamID: "team-main"}, tenant.Mattermost)
+	assert.Equal(t, 1, tenant.Redis.DB)
+	assert.Equal(t, "/bridge", cfg.Server.BasePath, "the main config is left alone")
+
+	ownServer := stagingTenant()
+	ownServer.Mattermost.URL = "https://mattermost-staging.example.com"
+	tenant, err = cfg.ForTenant(ownServer)
+	require.NoError(t, err)
+	assert.Empty(t, tenant.Mattermost.TeamID, "the main team is on another server")
+
+	sameDB := stagingTenant()
+	*sameDB.Storage.RedisDB = 0
+	_, err = cfg.ForTenant(sameDB)
+	assert.ErrorContains(t, err, "storage.redis_db 0 is REDIS_DB of the main bridge")
+
+	cfg.Storage = StorageConfig{Backend: StorageBackendBolt, Path: "/var/lib/kmbridge/kmbridge.db"}
+	tenant, err = cfg.ForTenant(stagingTenant())
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/kmbridge/kmbridge-staging.db", tenant.Storage.Path)
+
+	cfg.Storage = StorageConfig{Backend: StorageBackendPostgres, DatabaseURL: "postgres://db"}
+	_, err = cfg.ForTenant(stagingTenant())
+	assert.ErrorContains(t, err, "tenants are not supported with STORAGE_BACKEND=postgres")
+}
+
+func TestFileConfigForTenant(t *testing.T) {
+	cfg := DefaultFileConfig()
+	cfg.Channels.DefaultChannelID = "prod-alerts"
+	cfg.Tenants = []TenantConfig{stagingTenant()}
+	require.NoError(t, cfg.Validate())
+
+	tenant, err := cfg.ForTenant(stagingTenant())
+	require.NoError(t, err)
+	assert.Equal(t, "staging-alerts", tenant.Channels.DefaultChannelID)
+	assert.Empty(t, tenant.Tenants)
+	assert.Equal(t, "prod-alerts", cfg.Channels.DefaultChannelID)
+
+	path := filepath.Join(t.TempDir(), "staging.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("channels:\n  default_channel_id: own-alerts\n"), 0o600))
+	withFile := stagingTenant()
+	withFile.ConfigFile = path
+	tenant, err = cfg.ForTenant(withFile)
+	require.NoError(t, err)
+	assert.Equal(t, "own-alerts", tenant.Channels.DefaultChannelID)
+}
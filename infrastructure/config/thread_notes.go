@@ -0,0 +1,26 @@
+package config
+
+import "fmt"
+
+// ThreadNotesConfig forwards the replies users post in alert threads to
+// Keep, appending them to the EnrichmentKey enrichment of the alert. Replies
+// longer than MaxLength characters are cut. Replies by bots and incoming
+// webhooks, including the bridge's own thread replies, are never forwarded.
+type ThreadNotesConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	EnrichmentKey string `yaml:"enrichment_key"` // default: note
+	MaxLength     int    `yaml:"max_length"`     // default: 1000
+}
+
+func (c *FileConfig) validateThreadNotes() error {
+	if !c.ThreadNotes.Enabled {
+		return nil
+	}
+	if c.ThreadNotes.EnrichmentKey == "" {
+		return fmt.Errorf("thread_notes.enrichment_key must not be empty")
+	}
+	if c.ThreadNotes.MaxLength < 1 {
+		return fmt.Errorf("thread_notes.max_length must be at least 1, got %d", c.ThreadNotes.MaxLength)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateThreadNotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		notes   ThreadNotesConfig
+		wantErr string
+	}{
+		{name: "disabled", notes: ThreadNotesConfig{}},
+		{name: "enabled", notes: ThreadNotesConfig{Enabled: true, EnrichmentKey: "note", MaxLength: 1000}},
+		{name: "empty enrichment key", notes: ThreadNotesConfig{Enabled: true, MaxLength: 1000}, wantErr: "thread_notes.enrichment_key must not be empty"},
+		{name: "zero max length", notes: ThreadNotesConfig{Enabled: true, EnrichmentKey: "note"}, wantErr: "thread_notes.max_length must be at least 1, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &FileConfig{ThreadNotes: tt.notes}
+			err := cfg.validateThreadNotes()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestThreadNotesDefaults(t *testing.T) {
+	cfg := &FileConfig{}
+	cfg.applyDefaults()
+	assert.Equal(t, "note", cfg.ThreadNotes.EnrichmentKey)
+	assert.Equal(t, 1000, cfg.ThreadNotes.MaxLength)
+	assert.NoError(t, cfg.validateThreadNotes())
+}
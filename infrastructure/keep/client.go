@@ -4,62 +4,162 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/breaker"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/outbound"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
 var (
 	keepEnrichOK          = metrics.NewCounter(`keep_api_calls_total{operation="enrich",status="ok"}`)
 	keepEnrichErr         = metrics.NewCounter(`keep_api_calls_total{operation="enrich",status="error"}`)
+	keepEnrichDur         = metrics.NewHistogram(`keep_api_duration_seconds{operation="enrich"}`)
 	keepUnenrichOK        = metrics.NewCounter(`keep_api_calls_total{operation="unenrich",status="ok"}`)
 	keepUnenrichErr       = metrics.NewCounter(`keep_api_calls_total{operation="unenrich",status="error"}`)
+	keepUnenrichDur       = metrics.NewHistogram(`keep_api_duration_seconds{operation="unenrich"}`)
 	keepGetAlertOK        = metrics.NewCounter(`keep_api_calls_total{operation="get_alert",status="ok"}`)
 	keepGetAlertErr       = metrics.NewCounter(`keep_api_calls_total{operation="get_alert",status="error"}`)
+	keepGetAlertDur       = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_alert"}`)
 	keepGetAlertsOK       = metrics.NewCounter(`keep_api_calls_total{operation="get_alerts",status="ok"}`)
 	keepGetAlertsErr      = metrics.NewCounter(`keep_api_calls_total{operation="get_alerts",status="error"}`)
+	keepGetAlertsDur      = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_alerts"}`)
 	keepGetProvidersOK    = metrics.NewCounter(`keep_api_calls_total{operation="get_providers",status="ok"}`)
 	keepGetProvidersErr   = metrics.NewCounter(`keep_api_calls_total{operation="get_providers",status="error"}`)
+	keepGetProvidersDur   = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_providers"}`)
 	keepCreateProviderOK  = metrics.NewCounter(`keep_api_calls_total{operation="create_provider",status="ok"}`)
 	keepCreateProviderErr = metrics.NewCounter(`keep_api_calls_total{operation="create_provider",status="error"}`)
+	keepCreateProviderDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="create_provider"}`)
 	keepGetWorkflowsOK    = metrics.NewCounter(`keep_api_calls_total{operation="get_workflows",status="ok"}`)
 	keepGetWorkflowsErr   = metrics.NewCounter(`keep_api_calls_total{operation="get_workflows",status="error"}`)
+	keepGetWorkflowsDur   = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_workflows"}`)
 	keepCreateWorkflowOK  = metrics.NewCounter(`keep_api_calls_total{operation="create_workflow",status="ok"}`)
 	keepCreateWorkflowErr = metrics.NewCounter(`keep_api_calls_total{operation="create_workflow",status="error"}`)
+	keepCreateWorkflowDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="create_workflow"}`)
+	keepUpdateWorkflowOK  = metrics.NewCounter(`keep_api_calls_total{operation="update_workflow",status="ok"}`)
+	keepUpdateWorkflowErr = metrics.NewCounter(`keep_api_calls_total{operation="update_workflow",status="error"}`)
+	keepUpdateWorkflowDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="update_workflow"}`)
+	keepRunWorkflowOK     = metrics.NewCounter(`keep_api_calls_total{operation="run_workflow",status="ok"}`)
+	keepRunWorkflowErr    = metrics.NewCounter(`keep_api_calls_total{operation="run_workflow",status="error"}`)
+	keepRunWorkflowDur    = metrics.NewHistogram(`keep_api_duration_seconds{operation="run_workflow"}`)
+	keepGetExecutionOK    = metrics.NewCounter(`keep_api_calls_total{operation="get_workflow_execution",status="ok"}`)
+	keepGetExecutionErr   = metrics.NewCounter(`keep_api_calls_total{operation="get_workflow_execution",status="error"}`)
+	keepGetExecutionDur   = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_workflow_execution"}`)
 )
 
 type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	transport  *http.Transport
+	retry      *retry.Transport
+	breaker    *breaker.Transport
+	signer     *Signer
+	maxAlerts  int64 // GetAlerts response size cap in bytes
+	severities alert.SeverityMap
 	logger     *slog.Logger
 }
 
 func NewClient(baseURL, apiKey string, logger *slog.Logger) *Client {
+	base := &http.Transport{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	transport := retry.NewTransport(base, "keep", retry.Policy{MaxAttempts: 1})
+	// A request counts once against the breaker, however often it was retried.
+	circuit := breaker.NewTransport(transport, "keep")
 	return &Client{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        20,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   30 * time.Second,
+			Transport: circuit,
 		},
-		logger: logger,
+		transport: base,
+		retry:     transport,
+		breaker:   circuit,
+		maxAlerts: DefaultMaxAlertsResponseBytes,
+		logger:    logger,
 	}
 }
 
+// SetOutbound connects to Keep through the proxy and with the TLS settings
+// of s. It must be called before the first request.
+func (c *Client) SetOutbound(s outbound.Settings) error {
+	return s.Apply(c.transport)
+}
+
+// SetRetryPolicy retries requests that fail with a network error, 429 or 5xx
+// response. Requests are not retried until it is called.
+func (c *Client) SetRetryPolicy(p retry.Policy) {
+	c.retry.SetPolicy(p)
+}
+
+// SetCircuitBreaker fails requests at once with breaker.ErrOpen while Keep
+// keeps failing, as s says. Requests are always sent until it is called.
+func (c *Client) SetCircuitBreaker(s breaker.Settings) {
+	c.breaker.SetSettings(s)
+}
+
+// CircuitState returns the state of the circuit breaker, breaker.StateClosed
+// unless it is enabled.
+func (c *Client) CircuitState() string {
+	return c.breaker.State()
+}
+
+// Available reports whether requests are sent to Keep, i.e. the circuit
+// breaker is not open.
+func (c *Client) Available() bool {
+	return c.breaker.Available()
+}
+
+// SetMaxAlertsResponseBytes caps the size of the GetAlerts response body.
+// Non-positive values restore the default.
+func (c *Client) SetMaxAlertsResponseBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxAlertsResponseBytes
+	}
+	c.maxAlerts = n
+}
+
+// SetSeverityMap maps the severities of alerts read from Keep onto the
+// canonical ones. A nil map, the default, returns them as Keep sends them.
+func (c *Client) SetSeverityMap(m alert.SeverityMap) {
+	c.severities = m
+}
+
+// SetSigner enables detached JWS signing of enrichment payloads.
+// A nil signer disables signing.
+func (c *Client) SetSigner(signer *Signer) {
+	c.signer = signer
+}
+
+func (c *Client) signRequest(req *http.Request, body []byte) error {
+	if c.signer == nil {
+		return nil
+	}
+	signature, err := c.signer.Sign(body)
+	if err != nil {
+		return fmt.Errorf("sign request body: %w", err)
+	}
+	req.Header.Set(SignatureHeader, signature)
+	return nil
+}
+
 type enrichRequest struct {
 	Fingerprint string            `json:"fingerprint"`
 	Enrichments map[string]string `json:"enrichments"`
@@ -76,6 +176,7 @@ func (c *Client) EnrichAlert(ctx context.Context, fingerprint string, enrichment
 	}
 
 	start := time.Now()
+	defer keepEnrichDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/alerts/enrich"
 	if opts.DisposeOnNewAlert {
 		reqURL += "?dispose_on_new_alert=true"
@@ -91,12 +192,15 @@ func (c *Client) EnrichAlert(ctx context.Context, fingerprint string, enrichment
 		return fmt.Errorf("marshal enrich body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "enrich"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("X-API-KEY", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	if err := c.signRequest(req, jsonBody); err != nil {
+		return err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -105,6 +209,9 @@ func (c *Client) EnrichAlert(ctx context.Context, fingerprint string, enrichment
 			logger.ExternalFieldsWithError("keep", reqURL, "POST", 0, duration, err.Error()),
 		)
 		keepEnrichErr.Inc()
+		if ctx.Err() == nil {
+			err = fmt.Errorf("%w: %w", port.ErrKeepUnavailable, err)
+		}
 		return fmt.Errorf("keep enrich alert: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -117,6 +224,9 @@ func (c *Client) EnrichAlert(ctx context.Context, fingerprint string, enrichment
 			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, string(respBody)),
 		)
 		keepEnrichErr.Inc()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("keep enrich alert: %w: status %d, body: %s", port.ErrKeepUnavailable, resp.StatusCode, respBody)
+		}
 		return fmt.Errorf("keep enrich alert: status %d, body: %s", resp.StatusCode, respBody)
 	}
 
@@ -136,6 +246,7 @@ func (c *Client) UnenrichAlert(ctx context.Context, fingerprint string, enrichme
 	}
 
 	start := time.Now()
+	defer keepUnenrichDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/alerts/unenrich"
 
 	body := unenrichRequest{
@@ -148,12 +259,15 @@ func (c *Client) UnenrichAlert(ctx context.Context, fingerprint string, enrichme
 		return fmt.Errorf("marshal unenrich body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "unenrich"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("X-API-KEY", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	if err := c.signRequest(req, jsonBody); err != nil {
+		return err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -162,6 +276,9 @@ func (c *Client) UnenrichAlert(ctx context.Context, fingerprint string, enrichme
 			logger.ExternalFieldsWithError("keep", reqURL, "POST", 0, duration, err.Error()),
 		)
 		keepUnenrichErr.Inc()
+		if ctx.Err() == nil {
+			err = fmt.Errorf("%w: %w", port.ErrKeepUnavailable, err)
+		}
 		return fmt.Errorf("keep unenrich alert: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -174,6 +291,9 @@ func (c *Client) UnenrichAlert(ctx context.Context, fingerprint string, enrichme
 			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, string(respBody)),
 		)
 		keepUnenrichErr.Inc()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("keep unenrich alert: %w: status %d, body: %s", port.ErrKeepUnavailable, resp.StatusCode, respBody)
+		}
 		return fmt.Errorf("keep unenrich alert: status %d, body: %s", resp.StatusCode, respBody)
 	}
 
@@ -195,6 +315,9 @@ type alertResponse struct {
 	Description     string         `json:"description"`
 	Source          []string       `json:"source"`
 	Labels          map[string]any `json:"labels"`
+	Annotations     map[string]any `json:"annotations"`
+	GeneratorURL    string         `json:"generatorURL"`
+	URL             string         `json:"url"`
 	Enrichments     map[string]any `json:"enrichments"`
 	FiringStartTime string         `json:"firingStartTime"`
 	LastReceived    string         `json:"lastReceived"`
@@ -212,6 +335,18 @@ func (c *Client) parseAlertResponse(alertResp alertResponse) port.KeepAlert {
 		}
 	}
 
+	var annotations map[string]string
+	if len(alertResp.Annotations) > 0 {
+		annotations = make(map[string]string, len(alertResp.Annotations))
+		for k, v := range alertResp.Annotations {
+			if s, ok := v.(string); ok {
+				annotations[k] = s
+			} else if v != nil {
+				annotations[k] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+
 	var firingStartTime time.Time
 	if alertResp.FiringStartTime != "" {
 		var parseErr error
@@ -224,6 +359,11 @@ func (c *Client) parseAlertResponse(alertResp alertResponse) port.KeepAlert {
 		}
 	}
 
+	generatorURL := alertResp.GeneratorURL
+	if generatorURL == "" {
+		generatorURL = alertResp.URL
+	}
+
 	source := alertResp.Source
 	if source == nil {
 		source = []string{}
@@ -246,10 +386,12 @@ func (c *Client) parseAlertResponse(alertResp alertResponse) port.KeepAlert {
 		Fingerprint:     alertResp.Fingerprint,
 		Name:            alertResp.Name,
 		Status:          alertResp.Status,
-		Severity:        alertResp.Severity,
+		Severity:        c.severities.Normalize(alertResp.Severity),
 		Description:     alertResp.Description,
 		Source:          source,
 		Labels:          labels,
+		Annotations:     annotations,
+		GeneratorURL:    generatorURL,
 		FiringStartTime: firingStartTime,
 		Enrichments:     enrichments,
 	}
@@ -257,9 +399,10 @@ func (c *Client) parseAlertResponse(alertResp alertResponse) port.KeepAlert {
 
 func (c *Client) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAlert, error) {
 	start := time.Now()
+	defer keepGetAlertDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/alerts/" + url.PathEscape(fingerprint)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_alert"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -272,6 +415,9 @@ func (c *Client) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAl
 			logger.ExternalFieldsWithError("keep", reqURL, "GET", 0, duration, err.Error()),
 		)
 		keepGetAlertErr.Inc()
+		if ctx.Err() == nil {
+			err = fmt.Errorf("%w: %w", port.ErrKeepUnavailable, err)
+		}
 		return nil, fmt.Errorf("keep get alert: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -284,6 +430,12 @@ func (c *Client) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAl
 			logger.ExternalFieldsWithError("keep", reqURL, "GET", resp.StatusCode, duration, string(respBody)),
 		)
 		keepGetAlertErr.Inc()
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("keep get alert: %w: status %d, body: %s", port.ErrKeepAlertNotFound, resp.StatusCode, respBody)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			return nil, fmt.Errorf("keep get alert: %w: status %d, body: %s", port.ErrKeepUnavailable, resp.StatusCode, respBody)
+		}
 		return nil, fmt.Errorf("keep get alert: status %d, body: %s", resp.StatusCode, respBody)
 	}
 
@@ -305,11 +457,15 @@ func (c *Client) GetAlert(ctx context.Context, fingerprint string) (*port.KeepAl
 	return &result, nil
 }
 
-func (c *Client) GetAlerts(ctx context.Context, limit int) ([]port.KeepAlert, error) {
+// GetAlerts fetches up to limit alerts. When fingerprints is non-empty only
+// alerts with those fingerprints are returned; the rest are discarded while
+// the response is streamed so they never accumulate in memory.
+func (c *Client) GetAlerts(ctx context.Context, limit int, fingerprints []string) ([]port.KeepAlert, error) {
 	start := time.Now()
+	defer keepGetAlertsDur.UpdateDuration(start)
 	reqURL := fmt.Sprintf("%s/alerts?limit=%d", c.baseURL, limit)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_alerts"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -337,18 +493,34 @@ func (c *Client) GetAlerts(ctx context.Context, limit int) ([]port.KeepAlert, er
 		return nil, fmt.Errorf("keep get alerts: status %d, body: %s", resp.StatusCode, respBody)
 	}
 
-	var alertsResp []alertResponse
-	if err := json.NewDecoder(resp.Body).Decode(&alertsResp); err != nil {
+	var keep func(string) bool
+	if len(fingerprints) > 0 {
+		wanted := make(map[string]struct{}, len(fingerprints))
+		for _, fp := range fingerprints {
+			wanted[fp] = struct{}{}
+		}
+		keep = func(fp string) bool {
+			_, ok := wanted[fp]
+			return ok
+		}
+	}
+
+	alertsResp, seen, err := decodeAlertsStream(newCappedReader(resp.Body, c.maxAlerts), keep)
+	if err != nil {
 		c.logger.Error("Keep GetAlerts decode failed",
 			logger.ExternalFieldsWithError("keep", reqURL, "GET", resp.StatusCode, duration, err.Error()),
 		)
 		keepGetAlertsErr.Inc()
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, fmt.Errorf("keep get alerts: %w; lower the alerts limit (%d) or raise the response size cap", err, limit)
+		}
 		return nil, fmt.Errorf("decode alerts response: %w", err)
 	}
 
 	c.logger.Debug("Keep GetAlerts completed",
 		logger.ExternalFields("keep", reqURL, "GET", resp.StatusCode, duration),
-		slog.Int("count", len(alertsResp)),
+		slog.Int("count", seen),
+		slog.Int("matched", len(alertsResp)),
 	)
 	keepGetAlertsOK.Inc()
 
@@ -372,9 +544,10 @@ type providerResponse struct {
 
 func (c *Client) GetProviders(ctx context.Context) ([]port.KeepProvider, error) {
 	start := time.Now()
+	defer keepGetProvidersDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/providers"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_providers"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -446,6 +619,7 @@ type webhookProviderRequest struct {
 
 func (c *Client) CreateWebhookProvider(ctx context.Context, config port.WebhookProviderConfig) error {
 	start := time.Now()
+	defer keepCreateProviderDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/providers/install"
 
 	body := webhookProviderRequest{
@@ -462,7 +636,7 @@ func (c *Client) CreateWebhookProvider(ctx context.Context, config port.WebhookP
 		return fmt.Errorf("marshal webhook provider body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "create_provider"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -505,14 +679,16 @@ type workflowResponse struct {
 	ID            string `json:"id"`
 	Name          string `json:"name"`
 	WorkflowRawID string `json:"workflow_raw_id"`
+	WorkflowRaw   string `json:"workflow_raw"`
 	Disabled      bool   `json:"disabled"`
 }
 
 func (c *Client) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error) {
 	start := time.Now()
+	defer keepGetWorkflowsDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/workflows"
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_workflows"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -561,6 +737,7 @@ func (c *Client) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error)
 			Name:          w.Name,
 			WorkflowRawID: w.WorkflowRawID,
 			Disabled:      w.Disabled,
+			Raw:           w.WorkflowRaw,
 		})
 	}
 
@@ -569,6 +746,7 @@ func (c *Client) GetWorkflows(ctx context.Context) ([]port.KeepWorkflow, error)
 
 func (c *Client) CreateWorkflow(ctx context.Context, config port.WorkflowConfig) error {
 	start := time.Now()
+	defer keepCreateWorkflowDur.UpdateDuration(start)
 	reqURL := c.baseURL + "/workflows"
 
 	// Keep API requires multipart/form-data with file field
@@ -588,7 +766,7 @@ func (c *Client) CreateWorkflow(ctx context.Context, config port.WorkflowConfig)
 		return fmt.Errorf("close multipart writer: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, &buf)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "create_workflow"), http.MethodPost, reqURL, &buf)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -626,3 +804,208 @@ func (c *Client) CreateWorkflow(ctx context.Context, config port.WorkflowConfig)
 
 	return nil
 }
+
+// UpdateWorkflow replaces the definition of the workflow with the given Keep
+// ID. Keep keeps the previous definition as an earlier revision.
+func (c *Client) UpdateWorkflow(ctx context.Context, workflowID string, config port.WorkflowConfig) error {
+	start := time.Now()
+	defer keepUpdateWorkflowDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/workflows/" + url.PathEscape(workflowID)
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "update_workflow"), http.MethodPut, reqURL, strings.NewReader(config.Workflow))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", c.apiKey)
+	req.Header.Set("Content-Type", "application/yaml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep UpdateWorkflow failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "PUT", 0, duration, err.Error()),
+		)
+		keepUpdateWorkflowErr.Inc()
+		return fmt.Errorf("keep update workflow: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep UpdateWorkflow non-2xx",
+			logger.ExternalFieldsWithError("keep", reqURL, "PUT", resp.StatusCode, duration, string(respBody)),
+		)
+		keepUpdateWorkflowErr.Inc()
+		return fmt.Errorf("keep update workflow: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	c.logger.Debug("Keep UpdateWorkflow completed",
+		logger.ExternalFields("keep", reqURL, "PUT", resp.StatusCode, duration),
+	)
+	keepUpdateWorkflowOK.Inc()
+
+	return nil
+}
+
+type runWorkflowResponse struct {
+	WorkflowExecutionID string `json:"workflow_execution_id"`
+}
+
+// RunWorkflow starts workflowID with body as its event. The request is never
+// retried: a run Keep started but failed to answer would run twice.
+func (c *Client) RunWorkflow(ctx context.Context, workflowID string, body []byte) (string, error) {
+	start := time.Now()
+	defer keepRunWorkflowDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/workflows/" + url.PathEscape(workflowID) + "/run"
+
+	// Without GetBody the retry transport leaves the request alone.
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "run_workflow"), http.MethodPost, reqURL, io.NopCloser(bytes.NewReader(body)))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("X-API-KEY", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep RunWorkflow failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", 0, duration, err.Error()),
+		)
+		keepRunWorkflowErr.Inc()
+		return "", fmt.Errorf("keep run workflow: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep RunWorkflow non-2xx",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, string(respBody)),
+		)
+		keepRunWorkflowErr.Inc()
+		return "", fmt.Errorf("keep run workflow: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	var result runWorkflowResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Keep RunWorkflow decode failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, err.Error()),
+		)
+		keepRunWorkflowErr.Inc()
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	c.logger.Debug("Keep RunWorkflow completed",
+		logger.ExternalFields("keep", reqURL, "POST", resp.StatusCode, duration),
+	)
+	keepRunWorkflowOK.Inc()
+
+	return result.WorkflowExecutionID, nil
+}
+
+// alertEvent is the alert a manually started workflow runs for.
+type alertEvent struct {
+	Type string         `json:"type"`
+	Body alertEventBody `json:"body"`
+}
+
+type alertEventBody struct {
+	ID           string            `json:"id"`
+	Fingerprint  string            `json:"fingerprint"`
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	Severity     string            `json:"severity"`
+	Description  string            `json:"description,omitempty"`
+	Source       []string          `json:"source"`
+	Labels       map[string]string `json:"labels"`
+	LastReceived string            `json:"lastReceived"`
+}
+
+// RunAlertWorkflow starts workflowID with alert as its triggering event.
+func (c *Client) RunAlertWorkflow(ctx context.Context, workflowID string, alert port.KeepAlert) (string, error) {
+	source := alert.Source
+	if source == nil {
+		source = []string{}
+	}
+	body, err := json.Marshal(alertEvent{
+		Type: "alert",
+		Body: alertEventBody{
+			ID:           alert.Fingerprint,
+			Fingerprint:  alert.Fingerprint,
+			Name:         alert.Name,
+			Status:       alert.Status,
+			Severity:     alert.Severity,
+			Description:  alert.Description,
+			Source:       source,
+			Labels:       alert.Labels,
+			LastReceived: time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal alert event: %w", err)
+	}
+	return c.RunWorkflow(ctx, workflowID, body)
+}
+
+type workflowExecutionResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// GetWorkflowExecution returns the status of a workflow run.
+func (c *Client) GetWorkflowExecution(ctx context.Context, workflowID, executionID string) (port.KeepWorkflowExecution, error) {
+	start := time.Now()
+	defer keepGetExecutionDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/workflows/" + url.PathEscape(workflowID) + "/runs/" + url.PathEscape(executionID)
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_workflow_execution"), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return port.KeepWorkflowExecution{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep GetWorkflowExecution failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "GET", 0, duration, err.Error()),
+		)
+		keepGetExecutionErr.Inc()
+		return port.KeepWorkflowExecution{}, fmt.Errorf("keep get workflow execution: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep GetWorkflowExecution non-200",
+			logger.ExternalFieldsWithError("keep", reqURL, "GET", resp.StatusCode, duration, string(respBody)),
+		)
+		keepGetExecutionErr.Inc()
+		return port.KeepWorkflowExecution{}, fmt.Errorf("keep get workflow execution: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	var result workflowExecutionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Keep GetWorkflowExecution decode failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "GET", resp.StatusCode, duration, err.Error()),
+		)
+		keepGetExecutionErr.Inc()
+		return port.KeepWorkflowExecution{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.logger.Debug("Keep GetWorkflowExecution completed",
+		logger.ExternalFields("keep", reqURL, "GET", resp.StatusCode, duration),
+	)
+	keepGetExecutionOK.Inc()
+
+	return port.KeepWorkflowExecution{Status: result.Status, Error: result.Error}, nil
+}
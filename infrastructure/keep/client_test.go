@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	domainalert "github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
 func TestEnrichAlertSuccess(t *testing.T) {
@@ -46,6 +49,29 @@ func TestEnrichAlertSuccess(t *testing.T) {
 	assert.Equal(t, "acknowledged", capturedRequest.Enrichments["status"])
 }
 
+func TestEnrichAlertRetriesRateLimit(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req enrichRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "fp-1", req.Fingerprint, "body is resent on retry")
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetRetryPolicy(retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	err := client.EnrichAlert(context.Background(), "fp-1", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
 func TestEnrichAlertWithDisposeOnNewAlert(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/alerts/enrich", r.URL.Path)
@@ -100,6 +126,7 @@ func TestEnrichAlertServerError(t *testing.T) {
 	err := client.EnrichAlert(context.Background(), "fp-123", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 500")
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestEnrichAlertBadRequest(t *testing.T) {
@@ -116,6 +143,7 @@ func TestEnrichAlertBadRequest(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 400")
 	assert.Contains(t, err.Error(), "invalid fingerprint")
+	assert.NotErrorIs(t, err, port.ErrKeepUnavailable, "a rejected request would fail again")
 }
 
 func TestEnrichAlertNetworkError(t *testing.T) {
@@ -125,6 +153,7 @@ func TestEnrichAlertNetworkError(t *testing.T) {
 	err := client.EnrichAlert(context.Background(), "fp-123", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "keep enrich alert")
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestEnrichAlertContextCanceled(t *testing.T) {
@@ -141,6 +170,7 @@ func TestEnrichAlertContextCanceled(t *testing.T) {
 
 	err := client.EnrichAlert(ctx, "fp-123", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
 	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestEnrichAlertNilEnrichments(t *testing.T) {
@@ -193,6 +223,7 @@ func TestGetAlertSuccess(t *testing.T) {
 			"description":     "CPU usage is above 90%",
 			"source":          []string{"prometheus", "grafana"},
 			"labels":          map[string]any{"host": "server1", "env": "prod"},
+			"url":             "http://prometheus/graph?g0.expr=cpu",
 			"firingStartTime": "2024-01-15T10:30:00Z",
 			"lastReceived":    "2024-01-15T10:35:00Z",
 		}
@@ -217,6 +248,7 @@ func TestGetAlertSuccess(t *testing.T) {
 	assert.Equal(t, "critical", alert.Severity)
 	assert.Equal(t, "CPU usage is above 90%", alert.Description)
 	assert.Equal(t, []string{"prometheus", "grafana"}, alert.Source)
+	assert.Equal(t, "http://prometheus/graph?g0.expr=cpu", alert.GeneratorURL, "Keep's url stands in for generatorURL")
 	assert.Equal(t, map[string]string{"host": "server1", "env": "prod"}, alert.Labels)
 	assert.Equal(t, 2024, alert.FiringStartTime.Year())
 	assert.Equal(t, 1, int(alert.FiringStartTime.Month()))
@@ -231,6 +263,22 @@ func TestGetAlertNetworkError(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, alert)
 	assert.Contains(t, err.Error(), "keep get alert")
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
+}
+
+func TestGetAlertServerErrorIsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetRetryPolicy(retry.Policy{MaxAttempts: 1})
+
+	_, err := client.GetAlert(context.Background(), "fp-123")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
+	assert.NotErrorIs(t, err, port.ErrKeepAlertNotFound)
 }
 
 func TestGetAlertNon200StatusCode(t *testing.T) {
@@ -248,6 +296,8 @@ func TestGetAlertNon200StatusCode(t *testing.T) {
 	assert.Nil(t, alert)
 	assert.Contains(t, err.Error(), "status 404")
 	assert.Contains(t, err.Error(), "alert not found")
+	assert.ErrorIs(t, err, port.ErrKeepAlertNotFound)
+	assert.NotErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestGetAlertJSONDecodeError(t *testing.T) {
@@ -293,6 +343,20 @@ func TestGetAlertEmptySource(t *testing.T) {
 	assert.Equal(t, []string{}, alert.Source)
 }
 
+func TestGetAlertSeverityMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"fingerprint": "fp-123", "name": "TestAlert", "status": "firing", "severity": "P1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetSeverityMap(domainalert.NewSeverityMap(map[string]string{"p1": "critical"}))
+
+	got, err := client.GetAlert(context.Background(), "fp-123")
+	require.NoError(t, err)
+	assert.Equal(t, "critical", got.Severity)
+}
+
 func TestGetAlertWithEnrichments(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
@@ -527,6 +591,7 @@ func TestUnenrichAlertServerError(t *testing.T) {
 	err := client.UnenrichAlert(context.Background(), "fp-123", []string{"status"})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 500")
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestUnenrichAlertBadRequest(t *testing.T) {
@@ -543,6 +608,7 @@ func TestUnenrichAlertBadRequest(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 400")
 	assert.Contains(t, err.Error(), "invalid fingerprint")
+	assert.NotErrorIs(t, err, port.ErrKeepUnavailable, "a rejected request would fail again")
 }
 
 func TestUnenrichAlertNetworkError(t *testing.T) {
@@ -552,6 +618,7 @@ func TestUnenrichAlertNetworkError(t *testing.T) {
 	err := client.UnenrichAlert(context.Background(), "fp-123", []string{"status"})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "keep unenrich alert")
+	assert.ErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestUnenrichAlertContextCanceled(t *testing.T) {
@@ -568,6 +635,7 @@ func TestUnenrichAlertContextCanceled(t *testing.T) {
 
 	err := client.UnenrichAlert(ctx, "fp-123", []string{"status"})
 	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrKeepUnavailable)
 }
 
 func TestGetProvidersSuccess(t *testing.T) {
@@ -840,6 +908,7 @@ func TestGetWorkflowsSuccess(t *testing.T) {
 				"id":              "workflow-1",
 				"name":            "Alert Notification",
 				"workflow_raw_id": "alert-notification-workflow",
+				"workflow_raw":    "id: alert-notification-workflow\n",
 				"disabled":        false,
 			},
 			{
@@ -868,6 +937,7 @@ func TestGetWorkflowsSuccess(t *testing.T) {
 	assert.Equal(t, "Alert Notification", workflows[0].Name)
 	assert.Equal(t, "alert-notification-workflow", workflows[0].WorkflowRawID)
 	assert.False(t, workflows[0].Disabled)
+	assert.Equal(t, "id: alert-notification-workflow\n", workflows[0].Raw)
 	assert.Equal(t, "workflow-2", workflows[1].ID)
 	assert.Equal(t, "Escalation", workflows[1].Name)
 	assert.Equal(t, "escalation-workflow", workflows[1].WorkflowRawID)
@@ -1041,3 +1111,139 @@ func TestCreateWorkflowBadRequest(t *testing.T) {
 	assert.Contains(t, err.Error(), "status 400")
 	assert.Contains(t, err.Error(), "invalid workflow yaml")
 }
+
+func TestUpdateWorkflow(t *testing.T) {
+	var capturedPath, capturedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		capturedPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		capturedBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+
+	err := client.UpdateWorkflow(context.Background(), "wf-123", port.WorkflowConfig{Workflow: "id: kmbridge-webhook\n"})
+	require.NoError(t, err)
+	assert.Equal(t, "/workflows/wf-123", capturedPath)
+	assert.Equal(t, "id: kmbridge-webhook\n", capturedBody)
+}
+
+func TestUpdateWorkflowNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail": "Workflow not found"}`))
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+
+	err := client.UpdateWorkflow(context.Background(), "wf-missing", port.WorkflowConfig{Workflow: "id: x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestRunWorkflow(t *testing.T) {
+	var capturedPath, capturedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		capturedPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		capturedBody = string(body)
+		_, _ = w.Write([]byte(`{"workflow_execution_id": "exec-1", "status": "success"}`))
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+
+	executionID, err := client.RunWorkflow(context.Background(), "restart-pod", []byte(`{"pod":"api-1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "exec-1", executionID)
+	assert.Equal(t, "/workflows/restart-pod/run", capturedPath)
+	assert.Equal(t, `{"pod":"api-1"}`, capturedBody)
+}
+
+func TestRunWorkflowIsNotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+	client.SetRetryPolicy(retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	_, err := client.RunWorkflow(context.Background(), "restart-pod", []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+	assert.Equal(t, 1, calls)
+}
+
+func TestRunAlertWorkflow(t *testing.T) {
+	var event struct {
+		Type string         `json:"type"`
+		Body map[string]any `json:"body"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/workflows/wf-1/run", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		_, _ = w.Write([]byte(`{"workflow_execution_id": "exec-1"}`))
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+
+	executionID, err := client.RunAlertWorkflow(context.Background(), "wf-1", port.KeepAlert{
+		Fingerprint: "fp-1",
+		Name:        "Disk full",
+		Status:      "firing",
+		Severity:    "critical",
+		Labels:      map[string]string{"host": "db-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "exec-1", executionID)
+	assert.Equal(t, "alert", event.Type)
+	assert.Equal(t, "fp-1", event.Body["fingerprint"])
+	assert.Equal(t, "fp-1", event.Body["id"])
+	assert.Equal(t, "Disk full", event.Body["name"])
+	assert.Equal(t, "critical", event.Body["severity"])
+	assert.Equal(t, []any{}, event.Body["source"])
+	assert.Equal(t, map[string]any{"host": "db-1"}, event.Body["labels"])
+	assert.NotEmpty(t, event.Body["lastReceived"])
+}
+
+func TestGetWorkflowExecution(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		switch r.URL.Path {
+		case "/workflows/wf-1/runs/exec-1":
+			_, _ = w.Write([]byte(`{"id": "exec-1", "status": "error", "error": "step restart failed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	client := NewClient(server.URL, "test-key", logger)
+
+	execution, err := client.GetWorkflowExecution(context.Background(), "wf-1", "exec-1")
+	require.NoError(t, err)
+	assert.Equal(t, port.KeepWorkflowExecution{Status: "error", Error: "step restart failed"}, execution)
+
+	_, err = client.GetWorkflowExecution(context.Background(), "wf-1", "exec-2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
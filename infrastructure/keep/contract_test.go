@@ -0,0 +1,16 @@
+package keep
+
+import "github.com/alexmorbo/keep-mattermost-bridge/application/port"
+
+// Compile-time contracts: Client is wired into use cases as port.KeepClient,
+// when incidents are enabled as port.KeepIncidentClient, by the bootstrap
+// command as port.KeepWorkflowUpdater, for user auto-mapping as
+// port.KeepUserClient, and by the poller with paged queries as
+// port.KeepAlertQuerier.
+var (
+	_ port.KeepClient          = (*Client)(nil)
+	_ port.KeepIncidentClient  = (*Client)(nil)
+	_ port.KeepWorkflowUpdater = (*Client)(nil)
+	_ port.KeepUserClient      = (*Client)(nil)
+	_ port.KeepAlertQuerier    = (*Client)(nil)
+)
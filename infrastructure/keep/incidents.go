@@ -0,0 +1,81 @@
+package keep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+var (
+	keepIncidentStatusOK  = metrics.NewCounter(`keep_api_calls_total{operation="incident_status",status="ok"}`)
+	keepIncidentStatusErr = metrics.NewCounter(`keep_api_calls_total{operation="incident_status",status="error"}`)
+	keepIncidentStatusDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="incident_status"}`)
+)
+
+type incidentStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// ChangeIncidentStatus sets the status of a Keep incident. Keep notifies the
+// incident webhook of the change like any other incident update.
+func (c *Client) ChangeIncidentStatus(ctx context.Context, incidentID, status string) error {
+	start := time.Now()
+	defer keepIncidentStatusDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/incidents/" + url.PathEscape(incidentID) + "/status"
+
+	jsonBody, err := json.Marshal(incidentStatusRequest{Status: status})
+	if err != nil {
+		return fmt.Errorf("marshal incident status body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "incident_status"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	if err := c.signRequest(req, jsonBody); err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep ChangeIncidentStatus failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", 0, duration, err.Error()),
+		)
+		keepIncidentStatusErr.Inc()
+		return fmt.Errorf("keep change incident status: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep ChangeIncidentStatus non-2xx",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, string(respBody)),
+		)
+		keepIncidentStatusErr.Inc()
+		return fmt.Errorf("keep change incident status: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	c.logger.Debug("Keep ChangeIncidentStatus completed",
+		logger.ExternalFields("keep", reqURL, "POST", resp.StatusCode, duration),
+	)
+	keepIncidentStatusOK.Inc()
+
+	return nil
+}
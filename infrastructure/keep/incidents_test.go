@@ -0,0 +1,59 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeIncidentStatusSuccess(t *testing.T) {
+	var captured incidentStatusRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incidents/inc-1/status", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.ChangeIncidentStatus(context.Background(), "inc-1", "acknowledged"))
+	assert.Equal(t, "acknowledged", captured.Status)
+}
+
+func TestChangeIncidentStatusEscapesID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incidents/a%2Fb/status", r.URL.EscapedPath())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.ChangeIncidentStatus(context.Background(), "a/b", "resolved"))
+}
+
+func TestChangeIncidentStatusServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail":"Incident not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.ChangeIncidentStatus(context.Background(), "inc-1", "resolved")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+	assert.Contains(t, err.Error(), "Incident not found")
+}
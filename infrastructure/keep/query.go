@@ -0,0 +1,188 @@
+package keep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+var (
+	keepQueryAlertsOK  = metrics.NewCounter(`keep_api_calls_total{operation="query_alerts",status="ok"}`)
+	keepQueryAlertsErr = metrics.NewCounter(`keep_api_calls_total{operation="query_alerts",status="error"}`)
+	keepQueryAlertsDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="query_alerts"}`)
+)
+
+const (
+	// DefaultQueryPageSize is the page size of QueryAlerts when the query
+	// sets none.
+	DefaultQueryPageSize = 250
+	// maxQueryFingerprints bounds the fingerprints of one CEL filter, so a
+	// long list is queried in several requests instead of one huge one.
+	maxQueryFingerprints = 200
+)
+
+type queryAlertsRequest struct {
+	CEL     string `json:"cel,omitempty"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+	SortBy  string `json:"sort_by"`
+	SortDir string `json:"sort_dir"`
+}
+
+type queryAlertsResponse struct {
+	Results []alertResponse `json:"results"`
+	Count   int             `json:"count"`
+}
+
+// QueryAlerts pages through the alerts matching query with Keep's
+// POST /alerts/query, filtering on the Keep side with a CEL expression, and
+// calls handle with each page. Only one page is held in memory at a time.
+func (c *Client) QueryAlerts(ctx context.Context, query port.AlertQuery, handle func(page []port.KeepAlert) error) error {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultQueryPageSize
+	}
+
+	if len(query.Fingerprints) == 0 {
+		return c.queryPages(ctx, alertsCEL(query, nil), pageSize, handle)
+	}
+	for chunk := range slices.Chunk(query.Fingerprints, maxQueryFingerprints) {
+		if err := c.queryPages(ctx, alertsCEL(query, chunk), pageSize, handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) queryPages(ctx context.Context, cel string, pageSize int, handle func(page []port.KeepAlert) error) error {
+	for offset := 0; ; {
+		page, total, err := c.queryPage(ctx, queryAlertsRequest{
+			CEL:     cel,
+			Limit:   pageSize,
+			Offset:  offset,
+			SortBy:  "timestamp",
+			SortDir: "desc",
+		})
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := handle(page); err != nil {
+				return err
+			}
+		}
+		offset += len(page)
+		if len(page) < pageSize || offset >= total {
+			return nil
+		}
+	}
+}
+
+func (c *Client) queryPage(ctx context.Context, body queryAlertsRequest) ([]port.KeepAlert, int, error) {
+	start := time.Now()
+	defer keepQueryAlertsDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/alerts/query"
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal query body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "query_alerts"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep QueryAlerts failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", 0, duration, err.Error()),
+		)
+		keepQueryAlertsErr.Inc()
+		return nil, 0, fmt.Errorf("keep query alerts: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep QueryAlerts non-200",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, string(respBody)),
+		)
+		keepQueryAlertsErr.Inc()
+		return nil, 0, fmt.Errorf("keep query alerts: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	var result queryAlertsResponse
+	if err := json.NewDecoder(newCappedReader(resp.Body, c.maxAlerts)).Decode(&result); err != nil {
+		c.logger.Error("Keep QueryAlerts decode failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "POST", resp.StatusCode, duration, err.Error()),
+		)
+		keepQueryAlertsErr.Inc()
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, 0, fmt.Errorf("keep query alerts: %w; lower the page size (%d) or raise the response size cap", err, body.Limit)
+		}
+		return nil, 0, fmt.Errorf("decode query response: %w", err)
+	}
+
+	c.logger.Debug("Keep QueryAlerts completed",
+		logger.ExternalFields("keep", reqURL, "POST", resp.StatusCode, duration),
+		slog.Int("offset", body.Offset),
+		slog.Int("count", len(result.Results)),
+		slog.Int("total", result.Count),
+	)
+	keepQueryAlertsOK.Inc()
+
+	alerts := make([]port.KeepAlert, 0, len(result.Results))
+	for _, alertResp := range result.Results {
+		alerts = append(alerts, c.parseAlertResponse(alertResp))
+	}
+	return alerts, result.Count, nil
+}
+
+// alertsCEL returns the CEL expression selecting the alerts of query with
+// one of fingerprints, or "" when nothing is filtered.
+func alertsCEL(query port.AlertQuery, fingerprints []string) string {
+	var terms []string
+	for _, filter := range []struct {
+		field  string
+		values []string
+	}{
+		{"fingerprint", fingerprints},
+		{"status", query.Statuses},
+		{"severity", query.Severities},
+	} {
+		if len(filter.values) == 0 {
+			continue
+		}
+		quoted := make([]string, len(filter.values))
+		for i, v := range filter.values {
+			quoted[i] = celString(v)
+		}
+		terms = append(terms, fmt.Sprintf("%s in [%s]", filter.field, strings.Join(quoted, ", ")))
+	}
+	return strings.Join(terms, " && ")
+}
+
+// celString quotes s as a CEL string literal.
+func celString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,115 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func TestQueryAlertsPages(t *testing.T) {
+	var requests []queryAlertsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/alerts/query", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		var req queryAlertsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		// Five matching alerts in total.
+		var results []alertResponse
+		for i := req.Offset; i < min(req.Offset+req.Limit, 5); i++ {
+			results = append(results, alertResponse{Fingerprint: fmt.Sprintf("fp-%d", i), Name: "Disk full", Status: "firing"})
+		}
+		_ = json.NewEncoder(w).Encode(queryAlertsResponse{Results: results, Count: 5})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	var pages [][]string
+	err := client.QueryAlerts(context.Background(), port.AlertQuery{Statuses: []string{"firing"}, PageSize: 2}, func(page []port.KeepAlert) error {
+		var fingerprints []string
+		for _, a := range page {
+			fingerprints = append(fingerprints, a.Fingerprint)
+		}
+		pages = append(pages, fingerprints)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"fp-0", "fp-1"}, {"fp-2", "fp-3"}, {"fp-4"}}, pages)
+	require.Len(t, requests, 3)
+	assert.Equal(t, []int{0, 2, 4}, []int{requests[0].Offset, requests[1].Offset, requests[2].Offset})
+	assert.Equal(t, "status in ['firing']", requests[0].CEL)
+}
+
+func TestQueryAlertsStopsOnHandleError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(queryAlertsResponse{Results: []alertResponse{{Fingerprint: "fp-1"}}, Count: 10})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	stop := errors.New("stop")
+	err := client.QueryAlerts(context.Background(), port.AlertQuery{PageSize: 1}, func(page []port.KeepAlert) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestQueryAlertsServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.QueryAlerts(context.Background(), port.AlertQuery{}, func(page []port.KeepAlert) error { return nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestAlertsCEL(t *testing.T) {
+	query := port.AlertQuery{Severities: []string{"critical", "high"}}
+	assert.Equal(t, "fingerprint in ['a', 'it\\'s'] && severity in ['critical', 'high']", alertsCEL(query, []string{"a", "it's"}))
+	assert.Empty(t, alertsCEL(port.AlertQuery{}, nil))
+}
+
+func TestQueryAlertsChunksFingerprints(t *testing.T) {
+	var cels []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req queryAlertsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		cels = append(cels, req.CEL)
+		_ = json.NewEncoder(w).Encode(queryAlertsResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	fingerprints := make([]string, maxQueryFingerprints+1)
+	for i := range fingerprints {
+		fingerprints[i] = fmt.Sprintf("fp-%d", i)
+	}
+	err := client.QueryAlerts(context.Background(), port.AlertQuery{Fingerprints: fingerprints}, func(page []port.KeepAlert) error { return nil })
+	require.NoError(t, err)
+	require.Len(t, cels, 2)
+	assert.Equal(t, fmt.Sprintf("fingerprint in ['fp-%d']", maxQueryFingerprints), cels[1])
+}
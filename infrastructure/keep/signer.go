@@ -0,0 +1,156 @@
+package keep
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SignatureHeader carries the detached JWS (RFC 7515 Appendix F) computed over
+// the raw enrichment request body. Keep-side workflows can verify it with the
+// bridge public key to confirm a status change originated from the bridge.
+const SignatureHeader = "X-Kmbridge-Signature"
+
+const (
+	algEdDSA = "EdDSA"
+	algES256 = "ES256"
+	algRS256 = "RS256"
+)
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ"`
+	Iat int64  `json:"iat"`
+}
+
+// Signer produces detached JWS signatures for outbound Keep payloads.
+type Signer struct {
+	key   crypto.Signer
+	alg   string
+	keyID string
+	now   func() time.Time
+}
+
+// LoadSigner reads a PKCS#8, PKCS#1 or SEC1 PEM-encoded private key from path.
+// Supported key types are Ed25519, ECDSA P-256 and RSA.
+func LoadSigner(path, keyID string) (*Signer, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+	return NewSignerFromPEM(data, keyID)
+}
+
+func NewSignerFromPEM(data []byte, keyID string) (*Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key: no PEM block found")
+	}
+
+	key, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %w", err)
+	}
+
+	var alg string
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		alg = algEdDSA
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().BitSize != 256 {
+			return nil, fmt.Errorf("signing key: unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+		alg = algES256
+	case *rsa.PrivateKey:
+		alg = algRS256
+	default:
+		return nil, fmt.Errorf("signing key: unsupported key type %T", key)
+	}
+
+	return &Signer{key: key, alg: alg, keyID: keyID, now: time.Now}, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// Algorithm returns the JWS "alg" value used by this signer.
+func (s *Signer) Algorithm() string { return s.alg }
+
+// Sign returns a compact JWS with the payload segment omitted
+// ("<header>..<signature>"), computed over the given payload bytes.
+func (s *Signer) Sign(payload []byte) (string, error) {
+	header, err := json.Marshal(jwsHeader{
+		Alg: s.alg,
+		Kid: s.keyID,
+		Typ: "JOSE",
+		Iat: s.now().Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal jws header: %w", err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	sig, err := s.signInput([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (s *Signer) signInput(input []byte) ([]byte, error) {
+	switch s.alg {
+	case algEdDSA:
+		return s.key.Sign(rand.Reader, input, crypto.Hash(0))
+	case algES256:
+		digest := sha256.Sum256(input)
+		k, _ := s.key.(*ecdsa.PrivateKey)
+		r, ss, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return nil, fmt.Errorf("ecdsa sign: %w", err)
+		}
+		// JWS uses the fixed-width R||S encoding rather than ASN.1 DER.
+		return append(padTo32(r), padTo32(ss)...), nil
+	case algRS256:
+		digest := sha256.Sum256(input)
+		return s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("unsupported jws algorithm %q", s.alg)
+	}
+}
+
+func padTo32(n *big.Int) []byte {
+	out := make([]byte, 32)
+	return n.FillBytes(out)
+}
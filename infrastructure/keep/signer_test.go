@@ -0,0 +1,189 @@
+package keep
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func pkcs8PEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// verifyDetachedJWS checks a detached compact JWS against payload using pub.
+func verifyDetachedJWS(t *testing.T, jws string, payload []byte, pub crypto.PublicKey) jwsHeader {
+	t.Helper()
+	parts := strings.Split(jws, ".")
+	require.Len(t, parts, 3)
+	assert.Empty(t, parts[1], "payload segment must be detached")
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	var header jwsHeader
+	require.NoError(t, json.Unmarshal(headerJSON, &header))
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	input := []byte(parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload))
+	digest := sha256.Sum256(input)
+
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		assert.True(t, ed25519.Verify(k, input, sig))
+	case *ecdsa.PublicKey:
+		require.Len(t, sig, 64)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		assert.True(t, ecdsa.Verify(k, digest[:], r, s))
+	case *rsa.PublicKey:
+		assert.NoError(t, rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig))
+	default:
+		t.Fatalf("unexpected public key type %T", pub)
+	}
+	return header
+}
+
+func TestSignerAlgorithms(t *testing.T) {
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		pem     []byte
+		pub     crypto.PublicKey
+		wantAlg string
+	}{
+		{name: "ed25519 pkcs8", pem: pkcs8PEM(t, edPriv), pub: edPub, wantAlg: "EdDSA"},
+		{name: "ecdsa p256 pkcs8", pem: pkcs8PEM(t, ecPriv), pub: &ecPriv.PublicKey, wantAlg: "ES256"},
+		{name: "rsa pkcs1", pem: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaPriv)}), pub: &rsaPriv.PublicKey, wantAlg: "RS256"},
+	}
+
+	payload := []byte(`{"fingerprint":"fp-1","enrichments":{"status":"acknowledged"}}`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSignerFromPEM(tt.pem, "bridge-1")
+			require.NoError(t, err)
+			signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+			assert.Equal(t, tt.wantAlg, signer.Algorithm())
+
+			jws, err := signer.Sign(payload)
+			require.NoError(t, err)
+
+			header := verifyDetachedJWS(t, jws, payload, tt.pub)
+			assert.Equal(t, tt.wantAlg, header.Alg)
+			assert.Equal(t, "bridge-1", header.Kid)
+			assert.Equal(t, int64(1700000000), header.Iat)
+		})
+	}
+}
+
+func TestNewSignerFromPEMErrors(t *testing.T) {
+	t.Run("no pem block", func(t *testing.T) {
+		_, err := NewSignerFromPEM([]byte("not a key"), "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no PEM block")
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		_, err = NewSignerFromPEM(pkcs8PEM(t, key), "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported ECDSA curve")
+	})
+
+	t.Run("garbage der", func(t *testing.T) {
+		data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")})
+		_, err := NewSignerFromPEM(data, "")
+		require.Error(t, err)
+	})
+}
+
+func TestLoadSigner(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pkcs8PEM(t, priv), 0o600))
+
+	signer, err := LoadSigner(path, "kid")
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", signer.Algorithm())
+
+	_, err = LoadSigner(filepath.Join(t.TempDir(), "missing.pem"), "kid")
+	require.Error(t, err)
+}
+
+func TestEnrichAlertSignsBody(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := NewSignerFromPEM(pkcs8PEM(t, priv), "bridge")
+	require.NoError(t, err)
+
+	var capturedBody []byte
+	var capturedSig string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedSig = r.Header.Get(SignatureHeader)
+		capturedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetSigner(signer)
+
+	err = client.EnrichAlert(context.Background(), "fp-1", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, capturedSig)
+	verifyDetachedJWS(t, capturedSig, capturedBody, pub)
+
+	err = client.UnenrichAlert(context.Background(), "fp-1", []string{"status"})
+	require.NoError(t, err)
+	verifyDetachedJWS(t, capturedSig, capturedBody, pub)
+}
+
+func TestEnrichAlertWithoutSignerSendsNoSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(SignatureHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.EnrichAlert(context.Background(), "fp-1", map[string]string{"status": "acknowledged"}, port.EnrichOptions{})
+	require.NoError(t, err)
+}
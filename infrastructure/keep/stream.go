@@ -0,0 +1,78 @@
+package keep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxAlertsResponseBytes caps the GetAlerts response body unless
+// overridden with SetMaxAlertsResponseBytes.
+const DefaultMaxAlertsResponseBytes int64 = 64 << 20
+
+// ErrResponseTooLarge is returned when a Keep response exceeds the configured
+// size cap. The body is abandoned at that point instead of being buffered.
+var ErrResponseTooLarge = errors.New("keep response exceeds size limit")
+
+// cappedReader fails with ErrResponseTooLarge once more than limit bytes have
+// been read, unlike io.LimitReader which silently truncates.
+type cappedReader struct {
+	r         io.Reader
+	remaining int64
+	limit     int64
+}
+
+func newCappedReader(r io.Reader, limit int64) *cappedReader {
+	return &cappedReader{r: r, remaining: limit, limit: limit}
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.remaining < 0 {
+		return 0, fmt.Errorf("%w (%d bytes)", ErrResponseTooLarge, c.limit)
+	}
+	// Read one byte past the limit so an exactly-full body is still accepted.
+	if int64(len(p)) > c.remaining+1 {
+		p = p[:c.remaining+1]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining < 0 {
+		return n, fmt.Errorf("%w (%d bytes)", ErrResponseTooLarge, c.limit)
+	}
+	return n, err
+}
+
+// decodeAlertsStream decodes a JSON array of alerts one element at a time,
+// keeping only those accepted by keep so that untracked alerts never pile up
+// in memory. A nil keep accepts every alert.
+func decodeAlertsStream(r io.Reader, keep func(fingerprint string) bool) ([]alertResponse, int, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read array start: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, 0, fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	var result []alertResponse
+	seen := 0
+	for dec.More() {
+		var alertResp alertResponse
+		if err := dec.Decode(&alertResp); err != nil {
+			return nil, seen, fmt.Errorf("decode alert %d: %w", seen, err)
+		}
+		seen++
+		if keep == nil || keep(alertResp.Fingerprint) {
+			result = append(result, alertResp)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, seen, fmt.Errorf("read array end: %w", err)
+	}
+
+	return result, seen, nil
+}
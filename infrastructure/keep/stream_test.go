@@ -0,0 +1,110 @@
+package keep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func alertsJSON(fingerprints ...string) string {
+	items := make([]string, len(fingerprints))
+	for i, fp := range fingerprints {
+		items[i] = fmt.Sprintf(`{"fingerprint":%q,"name":"Alert %s","status":"firing","severity":"high","labels":{"env":"prod"}}`, fp, fp)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestDecodeAlertsStream(t *testing.T) {
+	t.Run("keeps everything without filter", func(t *testing.T) {
+		alerts, seen, err := decodeAlertsStream(strings.NewReader(alertsJSON("a", "b", "c")), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, seen)
+		assert.Len(t, alerts, 3)
+	})
+
+	t.Run("filters while decoding", func(t *testing.T) {
+		keep := func(fp string) bool { return fp == "b" }
+		alerts, seen, err := decodeAlertsStream(strings.NewReader(alertsJSON("a", "b", "c")), keep)
+		require.NoError(t, err)
+		assert.Equal(t, 3, seen)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "b", alerts[0].Fingerprint)
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		alerts, seen, err := decodeAlertsStream(strings.NewReader(`[]`), nil)
+		require.NoError(t, err)
+		assert.Zero(t, seen)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		_, _, err := decodeAlertsStream(strings.NewReader(`{"error":"nope"}`), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected JSON array")
+	})
+
+	t.Run("malformed element", func(t *testing.T) {
+		_, _, err := decodeAlertsStream(strings.NewReader(`[{"fingerprint":"a"},{"fingerprint":`), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decode alert 1")
+	})
+}
+
+func TestCappedReader(t *testing.T) {
+	t.Run("exactly at limit", func(t *testing.T) {
+		data, err := io.ReadAll(newCappedReader(strings.NewReader("12345"), 5))
+		require.NoError(t, err)
+		assert.Equal(t, "12345", string(data))
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		_, err := io.ReadAll(newCappedReader(strings.NewReader("123456"), 5))
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+}
+
+func TestGetAlertsFiltersByFingerprint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/alerts", r.URL.Path)
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(alertsJSON("fp-1", "fp-2", "fp-3")))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	alerts, err := client.GetAlerts(context.Background(), 50, []string{"fp-3", "fp-missing"})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "fp-3", alerts[0].Fingerprint)
+	assert.Equal(t, "prod", alerts[0].Labels["env"])
+
+	alerts, err = client.GetAlerts(context.Background(), 50, nil)
+	require.NoError(t, err)
+	assert.Len(t, alerts, 3)
+}
+
+func TestGetAlertsResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(alertsJSON("fp-1", "fp-2", "fp-3")))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetMaxAlertsResponseBytes(64)
+
+	_, err := client.GetAlerts(context.Background(), 1000, nil)
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Contains(t, err.Error(), "lower the alerts limit (1000)")
+}
@@ -0,0 +1,81 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+var (
+	keepGetUsersOK  = metrics.NewCounter(`keep_api_calls_total{operation="get_users",status="ok"}`)
+	keepGetUsersErr = metrics.NewCounter(`keep_api_calls_total{operation="get_users",status="error"}`)
+	keepGetUsersDur = metrics.NewHistogram(`keep_api_duration_seconds{operation="get_users"}`)
+)
+
+type userResponse struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// GetUsers lists the users of the Keep UI. The API key needs a role allowed
+// to read users.
+func (c *Client) GetUsers(ctx context.Context) ([]port.KeepUser, error) {
+	start := time.Now()
+	defer keepGetUsersDur.UpdateDuration(start)
+	reqURL := c.baseURL + "/auth/users"
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_users"), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-KEY", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Keep GetUsers failed",
+			logger.ExternalFieldsWithError("keep", reqURL, "GET", 0, duration, err.Error()),
+		)
+		keepGetUsersErr.Inc()
+		return nil, fmt.Errorf("keep get users: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Keep GetUsers non-200",
+			logger.ExternalFieldsWithError("keep", reqURL, "GET", resp.StatusCode, duration, string(respBody)),
+		)
+		keepGetUsersErr.Inc()
+		return nil, fmt.Errorf("keep get users: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	var usersResp []userResponse
+	if err := json.NewDecoder(resp.Body).Decode(&usersResp); err != nil {
+		keepGetUsersErr.Inc()
+		return nil, fmt.Errorf("decode users response: %w", err)
+	}
+
+	users := make([]port.KeepUser, len(usersResp))
+	for i, u := range usersResp {
+		users[i] = port.KeepUser{Email: u.Email, Name: u.Name}
+	}
+
+	c.logger.Debug("Keep GetUsers completed",
+		logger.ExternalFields("keep", reqURL, "GET", resp.StatusCode, duration),
+	)
+	keepGetUsersOK.Inc()
+
+	return users, nil
+}
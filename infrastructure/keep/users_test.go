@@ -0,0 +1,48 @@
+package keep
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func TestGetUsersSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/users", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		_, _ = w.Write([]byte(`[{"email":"alice@example.com","name":"Alice","role":"admin"},{"email":"bob@example.com","name":"Bob"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	users, err := client.GetUsers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []port.KeepUser{
+		{Email: "alice@example.com", Name: "Alice"},
+		{Email: "bob@example.com", Name: "Bob"},
+	}, users)
+}
+
+func TestGetUsersServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"detail":"Forbidden"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.GetUsers(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
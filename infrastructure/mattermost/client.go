@@ -4,17 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/VictoriaMetrics/metrics"
 
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/outbound"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/ratelimit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
 var (
@@ -29,33 +37,116 @@ var (
 	mmReplyToThreadOK  = metrics.NewCounter(`mattermost_api_calls_total{operation="reply_to_thread",status="ok"}`)
 	mmReplyToThreadErr = metrics.NewCounter(`mattermost_api_calls_total{operation="reply_to_thread",status="error"}`)
 	mmReplyToThreadDur = metrics.NewHistogram(`mattermost_api_duration_seconds{operation="reply_to_thread"}`)
+
+	mmAPIDur = func(operation string) *metrics.Histogram {
+		return metrics.GetOrCreateHistogram(`mattermost_api_duration_seconds{operation="` + operation + `"}`)
+	}
 )
 
 type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+	transport  *http.Transport
+	retry      *retry.Transport
+	rateLimit  *ratelimit.Transport
 	logger     *slog.Logger
+
+	botUserIDMu sync.Mutex
+	botUserID   string
+
+	botUsername string
+	botIconURL  string
+
+	clusterHosts []string // hosts redirects may send the token to
 }
 
 func NewClient(baseURL, token string, logger *slog.Logger) *Client {
-	return &Client{
+	// Every attempt of a retried request passes the rate limiter.
+	base := &http.Transport{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	limiter := ratelimit.NewTransport(base, "mattermost")
+	transport := retry.NewTransport(limiter, "mattermost", retry.Policy{MaxAttempts: 1})
+	c := &Client{
 		baseURL: baseURL,
 		token:   token,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        20,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
-		logger: logger,
+		transport: base,
+		retry:     transport,
+		rateLimit: limiter,
+		logger:    logger,
+	}
+	c.httpClient.CheckRedirect = c.checkRedirect
+	return c
+}
+
+// SetOutbound connects to Mattermost through the proxy and with the TLS
+// settings of s. It must be called before the first request.
+func (c *Client) SetOutbound(s outbound.Settings) error {
+	return s.Apply(c.transport)
+}
+
+// SetRetryPolicy retries requests that fail with a network error, 429 or 5xx
+// response. Requests are not retried until it is called.
+func (c *Client) SetRetryPolicy(p retry.Policy) {
+	c.retry.SetPolicy(p)
+}
+
+// SetRateLimit spaces out requests to the Mattermost API and pauses them
+// while Mattermost reports its rate limit as used up. Requests are not
+// limited until it is called.
+func (c *Client) SetRateLimit(l ratelimit.Limit) {
+	c.rateLimit.SetLimit(l)
+}
+
+// SetBotIdentity shows posts under username and with the avatar at iconURL
+// instead of the bot account's, unless an attachment sets its own. Either may
+// be empty to keep the account's. Mattermost must allow username and profile
+// picture overrides for them to show.
+func (c *Client) SetBotIdentity(username, iconURL string) {
+	c.botUsername = username
+	c.botIconURL = iconURL
+}
+
+// postProps returns the props of a post showing attachment.
+func (c *Client) postProps(attachment post.Attachment) map[string]any {
+	props := map[string]any{
+		"attachments": []wireAttachment{toWireAttachment(attachment)},
+	}
+	c.addIdentity(props, attachment.Username, attachment.IconURL)
+	return props
+}
+
+// addIdentity adds the name and avatar overrides of a post to props,
+// falling back to the ones set by SetBotIdentity.
+func (c *Client) addIdentity(props map[string]any, username, iconURL string) {
+	if username == "" {
+		username = c.botUsername
+	}
+	if iconURL == "" {
+		iconURL = c.botIconURL
+	}
+	if username == "" && iconURL == "" {
+		return
+	}
+	props["from_webhook"] = "true"
+	if username != "" {
+		props["override_username"] = username
+	}
+	if iconURL != "" {
+		props["override_icon_url"] = iconURL
 	}
 }
 
 type createPostRequest struct {
 	ChannelID string         `json:"channel_id"`
+	RootID    string         `json:"root_id,omitempty"`
 	Message   string         `json:"message"`
 	Props     map[string]any `json:"props,omitempty"`
 }
@@ -71,15 +162,69 @@ type updatePostRequest struct {
 }
 
 type replyPostRequest struct {
-	ChannelID string `json:"channel_id"`
-	RootID    string `json:"root_id"`
-	Message   string `json:"message"`
+	ChannelID string         `json:"channel_id"`
+	RootID    string         `json:"root_id"`
+	Message   string         `json:"message"`
+	Props     map[string]any `json:"props,omitempty"`
 }
 
 type userResponse struct {
 	Username string `json:"username"`
 }
 
+type userIDResponse struct {
+	ID string `json:"id"`
+}
+
+type channelResponse struct {
+	ID string `json:"id"`
+}
+
+type memberResponse struct {
+	UserID string `json:"user_id"`
+}
+
+type groupResponse struct {
+	Name string `json:"name"`
+}
+
+type openDialogRequest struct {
+	TriggerID string     `json:"trigger_id"`
+	URL       string     `json:"url"`
+	Dialog    wireDialog `json:"dialog"`
+}
+
+type wireDialog struct {
+	CallbackID       string              `json:"callback_id"`
+	Title            string              `json:"title"`
+	IntroductionText string              `json:"introduction_text,omitempty"`
+	Elements         []wireDialogElement `json:"elements"`
+	SubmitLabel      string              `json:"submit_label,omitempty"`
+	State            string              `json:"state,omitempty"`
+}
+
+type wireDialogElement struct {
+	DisplayName string             `json:"display_name"`
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Placeholder string             `json:"placeholder,omitempty"`
+	Optional    bool               `json:"optional"`
+	MaxLength   int                `json:"max_length,omitempty"`
+	Options     []wireSelectOption `json:"options,omitempty"`
+}
+
+type postResponse struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	DeleteAt  int64  `json:"delete_at"`
+}
+
+type reactionResponse struct {
+	PostID    string `json:"post_id"`
+	UserID    string `json:"user_id"`
+	EmojiName string `json:"emoji_name"`
+}
+
 type wireAttachment struct {
 	Color      string       `json:"color,omitempty"`
 	Title      string       `json:"title,omitempty"`
@@ -102,9 +247,16 @@ type wireButton struct {
 	ID          string                `json:"id"`
 	Name        string                `json:"name"`
 	Style       string                `json:"style,omitempty"`
+	DataSource  string                `json:"data_source,omitempty"`
+	Options     []wireSelectOption    `json:"options,omitempty"`
 	Integration wireButtonIntegration `json:"integration"`
 }
 
+type wireSelectOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
 type wireButtonIntegration struct {
 	URL     string            `json:"url"`
 	Context map[string]string `json:"context"`
@@ -118,11 +270,21 @@ func toWireAttachment(a post.Attachment) wireAttachment {
 
 	buttons := make([]wireButton, len(a.Actions))
 	for i, b := range a.Actions {
+		buttonType := b.Type
+		if buttonType == "" {
+			buttonType = post.ButtonTypeButton
+		}
+		var options []wireSelectOption
+		for _, o := range b.Options {
+			options = append(options, wireSelectOption{Text: o.Text, Value: o.Value})
+		}
 		buttons[i] = wireButton{
-			Type:  "button",
-			ID:    b.ID,
-			Name:  b.Name,
-			Style: b.Style,
+			Type:       buttonType,
+			ID:         b.ID,
+			Name:       b.Name,
+			Style:      b.Style,
+			DataSource: b.DataSource,
+			Options:    options,
 			Integration: wireButtonIntegration{
 				URL:     b.Integration.URL,
 				Context: b.Integration.Context,
@@ -143,15 +305,23 @@ func toWireAttachment(a post.Attachment) wireAttachment {
 }
 
 func (c *Client) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+	return c.createPost(ctx, channelID, "", attachment)
+}
+
+// CreateThreadPost posts an attachment as a reply in the thread of rootID.
+func (c *Client) CreateThreadPost(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error) {
+	return c.createPost(ctx, channelID, rootID, attachment)
+}
+
+func (c *Client) createPost(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error) {
 	start := time.Now()
 	reqURL := c.baseURL + "/api/v4/posts"
 
 	body := createPostRequest{
 		ChannelID: channelID,
-		Message:   "",
-		Props: map[string]any{
-			"attachments": []wireAttachment{toWireAttachment(attachment)},
-		},
+		RootID:    rootID,
+		Message:   attachment.Message,
+		Props:     c.postProps(attachment),
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -159,7 +329,7 @@ func (c *Client) CreatePost(ctx context.Context, channelID string, attachment po
 		return "", fmt.Errorf("marshal post body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "create_post"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return "", fmt.Errorf("create request: %w", err)
 	}
@@ -208,10 +378,8 @@ func (c *Client) UpdatePost(ctx context.Context, postID string, attachment post.
 
 	body := updatePostRequest{
 		ID:      postID,
-		Message: "",
-		Props: map[string]any{
-			"attachments": []wireAttachment{toWireAttachment(attachment)},
-		},
+		Message: attachment.Message,
+		Props:   c.postProps(attachment),
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -219,7 +387,7 @@ func (c *Client) UpdatePost(ctx context.Context, postID string, attachment post.
 		return fmt.Errorf("marshal update body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "update_post"), http.MethodPut, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -257,11 +425,17 @@ func (c *Client) UpdatePost(ctx context.Context, postID string, attachment post.
 	return nil
 }
 
+// DeletePost removes a post and, for a root post, its thread.
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	return c.doJSON(ctx, "DeletePost", http.MethodDelete, c.baseURL+"/api/v4/posts/"+url.PathEscape(postID), nil, nil)
+}
+
 func (c *Client) GetUser(ctx context.Context, userID string) (string, error) {
 	start := time.Now()
+	defer mmAPIDur("get_user").UpdateDuration(start)
 	reqURL := c.baseURL + "/api/v4/users/" + url.PathEscape(userID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_user"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("create request: %w", err)
 	}
@@ -296,6 +470,70 @@ func (c *Client) GetUser(ctx context.Context, userID string) (string, error) {
 	return result.Username, nil
 }
 
+// GetPost returns the post with postID. Mattermost answers 404 for deleted
+// posts, unless the token may see them, in which case delete_at is set.
+func (c *Client) GetPost(ctx context.Context, postID string) (port.MattermostPost, error) {
+	reqURL := c.baseURL + "/api/v4/posts/" + url.PathEscape(postID)
+	var result postResponse
+	if err := c.doJSON(ctx, "GetPost", http.MethodGet, reqURL, nil, &result); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+			return port.MattermostPost{}, port.ErrMattermostPostNotFound
+		}
+		return port.MattermostPost{}, err
+	}
+	if result.DeleteAt > 0 {
+		return port.MattermostPost{}, port.ErrMattermostPostNotFound
+	}
+	return port.MattermostPost{ID: result.ID, ChannelID: result.ChannelID}, nil
+}
+
+// GetReactions lists the emoji reactions on a post. Mattermost returns null
+// rather than an empty list for posts without reactions.
+func (c *Client) GetReactions(ctx context.Context, postID string) ([]port.Reaction, error) {
+	start := time.Now()
+	defer mmAPIDur("get_reactions").UpdateDuration(start)
+	reqURL := c.baseURL + "/api/v4/posts/" + url.PathEscape(postID) + "/reactions"
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_reactions"), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Mattermost GetReactions failed",
+			logger.ExternalFieldsWithError("mattermost", reqURL, "GET", 0, duration, err.Error()),
+		)
+		return nil, fmt.Errorf("mattermost get reactions: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("mattermost get reactions: status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	var result []reactionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode reactions response: %w", err)
+	}
+
+	c.logger.Debug("Mattermost GetReactions completed",
+		logger.ExternalFields("mattermost", reqURL, "GET", resp.StatusCode, duration),
+	)
+
+	reactions := make([]port.Reaction, 0, len(result))
+	for _, r := range result {
+		reactions = append(reactions, port.Reaction{UserID: r.UserID, EmojiName: r.EmojiName})
+	}
+	return reactions, nil
+}
+
 func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
 	start := time.Now()
 	reqURL := c.baseURL + "/api/v4/posts"
@@ -305,13 +543,17 @@ func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, message s
 		RootID:    rootID,
 		Message:   message,
 	}
+	if c.botUsername != "" || c.botIconURL != "" {
+		body.Props = make(map[string]any)
+		c.addIdentity(body.Props, "", "")
+	}
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("marshal reply body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "reply_to_thread"), http.MethodPost, reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -348,3 +590,291 @@ func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, message s
 
 	return nil
 }
+
+type customStatusRequest struct {
+	Emoji string `json:"emoji"`
+	Text  string `json:"text"`
+}
+
+type channelPatchRequest struct {
+	Purpose string `json:"purpose"`
+}
+
+func (c *Client) SetCustomStatus(ctx context.Context, emoji, text string) error {
+	if text == "" {
+		return c.doJSON(ctx, "SetCustomStatus", http.MethodDelete, c.baseURL+"/api/v4/users/me/status/custom", nil, nil)
+	}
+	return c.doJSON(ctx, "SetCustomStatus", http.MethodPut, c.baseURL+"/api/v4/users/me/status/custom", customStatusRequest{
+		Emoji: emoji,
+		Text:  text,
+	}, nil)
+}
+
+func (c *Client) SetChannelPurpose(ctx context.Context, channelID, purpose string) error {
+	reqURL := c.baseURL + "/api/v4/channels/" + url.PathEscape(channelID) + "/patch"
+	return c.doJSON(ctx, "SetChannelPurpose", http.MethodPut, reqURL, channelPatchRequest{Purpose: purpose}, nil)
+}
+
+// PinPost pins postID to its channel.
+func (c *Client) PinPost(ctx context.Context, postID string) error {
+	return c.doJSON(ctx, "PinPost", http.MethodPost, c.baseURL+"/api/v4/posts/"+url.PathEscape(postID)+"/pin", nil, nil)
+}
+
+// OpenDialog opens an interactive dialog for the user whose click produced
+// triggerID. Submissions are posted to submitURL.
+func (c *Client) OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+	elements := make([]wireDialogElement, len(dialog.Elements))
+	for i, e := range dialog.Elements {
+		var options []wireSelectOption
+		for _, o := range e.Options {
+			options = append(options, wireSelectOption{Text: o.Text, Value: o.Value})
+		}
+		elements[i] = wireDialogElement{
+			DisplayName: e.DisplayName,
+			Name:        e.Name,
+			Type:        e.Type,
+			Placeholder: e.Placeholder,
+			Optional:    e.Optional,
+			MaxLength:   e.MaxLength,
+			Options:     options,
+		}
+	}
+	return c.doJSON(ctx, "OpenDialog", http.MethodPost, c.baseURL+"/api/v4/actions/dialogs/open", openDialogRequest{
+		TriggerID: triggerID,
+		URL:       submitURL,
+		Dialog: wireDialog{
+			CallbackID:       dialog.CallbackID,
+			Title:            dialog.Title,
+			IntroductionText: dialog.IntroductionText,
+			Elements:         elements,
+			SubmitLabel:      dialog.SubmitLabel,
+			State:            dialog.State,
+		},
+	}, nil)
+}
+
+// GetUserIDByUsername resolves a username, with or without a leading @, to
+// a user ID.
+func (c *Client) GetUserIDByUsername(ctx context.Context, username string) (string, error) {
+	reqURL := c.baseURL + "/api/v4/users/username/" + url.PathEscape(strings.TrimPrefix(username, "@"))
+	var result userIDResponse
+	if err := c.doJSON(ctx, "GetUserIDByUsername", http.MethodGet, reqURL, nil, &result); err != nil {
+		return "", err
+	}
+	if result.ID == "" {
+		return "", fmt.Errorf("mattermost GetUserIDByUsername: empty id for %q", username)
+	}
+	return result.ID, nil
+}
+
+// GetUsernameByEmail returns the username of the user with email. The token
+// must be allowed to see emails, e.g. belong to a system admin, unless the
+// server shows email addresses to everyone.
+func (c *Client) GetUsernameByEmail(ctx context.Context, email string) (string, error) {
+	reqURL := c.baseURL + "/api/v4/users/email/" + url.PathEscape(email)
+	var result userResponse
+	if err := c.doJSON(ctx, "GetUserByEmail", http.MethodGet, reqURL, nil, &result); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+			return "", port.ErrMattermostUserNotFound
+		}
+		return "", err
+	}
+	if result.Username == "" {
+		return "", fmt.Errorf("mattermost GetUserByEmail: empty username for %q", email)
+	}
+	return result.Username, nil
+}
+
+// GetChannelIDByName returns the ID of the channel of teamID with name, the
+// handle in its URL.
+func (c *Client) GetChannelIDByName(ctx context.Context, teamID, name string) (string, error) {
+	reqURL := c.baseURL + "/api/v4/teams/" + url.PathEscape(teamID) + "/channels/name/" + url.PathEscape(name)
+	var result channelResponse
+	if err := c.doJSON(ctx, "GetChannelByName", http.MethodGet, reqURL, nil, &result); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+			return "", port.ErrMattermostChannelNotFound
+		}
+		return "", err
+	}
+	return result.ID, nil
+}
+
+// CreateChannel creates a channel in teamID named name, shown under the same
+// name, and returns its ID.
+func (c *Client) CreateChannel(ctx context.Context, teamID, name string, private bool) (string, error) {
+	channelType := "O"
+	if private {
+		channelType = "P"
+	}
+	body := map[string]string{
+		"team_id":      teamID,
+		"name":         name,
+		"display_name": name,
+		"type":         channelType,
+	}
+	var result channelResponse
+	if err := c.doJSON(ctx, "CreateChannel", http.MethodPost, c.baseURL+"/api/v4/channels", body, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+// CreateDirectChannel returns the direct message channel between the bot and
+// userID. Mattermost returns the existing channel when there already is one.
+func (c *Client) CreateDirectChannel(ctx context.Context, userID string) (string, error) {
+	botUserID, err := c.getBotUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+	var result channelResponse
+	if err := c.doJSON(ctx, "CreateDirectChannel", http.MethodPost, c.baseURL+"/api/v4/channels/direct", []string{botUserID, userID}, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+// IsTeamMember reports whether userID belongs to teamID.
+func (c *Client) IsTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
+	reqURL := c.baseURL + "/api/v4/teams/" + url.PathEscape(teamID) + "/members/ids"
+	return c.isMember(ctx, "GetTeamMembersByIDs", reqURL, userID)
+}
+
+// IsChannelMember reports whether userID belongs to channelID.
+func (c *Client) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	reqURL := c.baseURL + "/api/v4/channels/" + url.PathEscape(channelID) + "/members/ids"
+	return c.isMember(ctx, "GetChannelMembersByIDs", reqURL, userID)
+}
+
+// isMember asks for the members among a single user ID, which answers
+// non-members with an empty list instead of a 404.
+func (c *Client) isMember(ctx context.Context, operation, reqURL, userID string) (bool, error) {
+	var members []memberResponse
+	if err := c.doJSON(ctx, operation, http.MethodPost, reqURL, []string{userID}, &members); err != nil {
+		return false, err
+	}
+	for _, m := range members {
+		if m.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetUserGroups returns the names of the user groups userID belongs to, as
+// used in @group mentions.
+func (c *Client) GetUserGroups(ctx context.Context, userID string) ([]string, error) {
+	var groups []groupResponse
+	if err := c.doJSON(ctx, "GetUserGroups", http.MethodGet, c.baseURL+"/api/v4/users/"+url.PathEscape(userID)+"/groups", nil, &groups); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names, nil
+}
+
+// getBotUserID returns the ID of the user the token belongs to, fetching it
+// once and retrying on the next call after a failure.
+func (c *Client) getBotUserID(ctx context.Context) (string, error) {
+	c.botUserIDMu.Lock()
+	defer c.botUserIDMu.Unlock()
+	if c.botUserID != "" {
+		return c.botUserID, nil
+	}
+	var result userIDResponse
+	if err := c.doJSON(ctx, "GetMe", http.MethodGet, c.baseURL+"/api/v4/users/me", nil, &result); err != nil {
+		return "", err
+	}
+	c.botUserID = result.ID
+	return c.botUserID, nil
+}
+
+// doJSON sends an optional JSON body and expects a 200 or 201 response. The
+// response body is decoded into out when it is non-nil and discarded otherwise.
+func (c *Client) doJSON(ctx context.Context, operation, method, reqURL string, body, out any) error {
+	start := time.Now()
+	defer mmAPIDur(snakeCase(operation)).UpdateDuration(start)
+
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal %s body: %w", operation, err)
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, snakeCase(operation)), method, reqURL, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Mattermost "+operation+" failed",
+			logger.ExternalFieldsWithError("mattermost", reqURL, method, 0, duration, err.Error()),
+		)
+		return fmt.Errorf("mattermost %s: %w", operation, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Mattermost "+operation+" non-2xx",
+			logger.ExternalFieldsWithError("mattermost", reqURL, method, resp.StatusCode, duration, string(respBody)),
+		)
+		return &statusError{operation: operation, status: resp.StatusCode, body: respBody}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode %s response: %w", operation, err)
+		}
+	} else {
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+
+	c.logger.Debug("Mattermost "+operation+" completed",
+		logger.ExternalFields("mattermost", reqURL, method, resp.StatusCode, duration),
+	)
+
+	return nil
+}
+
+// statusError is returned by doJSON for a non-2xx response, so callers can
+// tell a missing resource from a failure.
+type statusError struct {
+	operation string
+	status    int
+	body      []byte
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("mattermost %s: status %d, body: %s", e.operation, e.status, e.body)
+}
+
+// snakeCase turns an operation name such as "DeletePost" into the
+// "delete_post" form used by metric labels.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
 )
 
 func TestCreatePostSuccess(t *testing.T) {
@@ -59,6 +62,63 @@ func TestCreatePostSuccess(t *testing.T) {
 	assert.Contains(t, capturedRequest.Props, "attachments")
 }
 
+func TestCreatePostWithMentions(t *testing.T) {
+	var capturedRequest createPostRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&capturedRequest))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	_, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test Alert", Message: "@here @oncall"})
+	require.NoError(t, err)
+	assert.Equal(t, "@here @oncall", capturedRequest.Message, "mentions go in the message, where Mattermost notifies them")
+	assert.Contains(t, capturedRequest.Props, "attachments")
+}
+
+func TestBotIdentity(t *testing.T) {
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetBotIdentity("Alerts", "https://example.com/bot.png")
+
+	ctx := context.Background()
+	_, err := client.CreatePost(ctx, "channel-1", post.Attachment{Title: "Disk full", Username: "Critical Alerts", IconURL: "https://example.com/red.png"})
+	require.NoError(t, err)
+	_, err = client.CreatePost(ctx, "channel-1", post.Attachment{Title: "Disk full", Username: "Critical Alerts"})
+	require.NoError(t, err)
+	require.NoError(t, client.ReplyToThread(ctx, "channel-1", "post-123", "Acknowledged"))
+
+	require.Len(t, bodies, 3)
+	props := bodies[0]["props"].(map[string]any)
+	assert.Equal(t, "true", props["from_webhook"])
+	assert.Equal(t, "Critical Alerts", props["override_username"])
+	assert.Equal(t, "https://example.com/red.png", props["override_icon_url"])
+
+	props = bodies[1]["props"].(map[string]any)
+	assert.Equal(t, "Critical Alerts", props["override_username"])
+	assert.Equal(t, "https://example.com/bot.png", props["override_icon_url"], "the client's avatar fills in")
+
+	props = bodies[2]["props"].(map[string]any)
+	assert.Equal(t, "Alerts", props["override_username"], "thread replies use the client's identity")
+
+	plain := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	_, err = plain.CreatePost(ctx, "channel-1", post.Attachment{Title: "Disk full"})
+	require.NoError(t, err)
+	assert.NotContains(t, bodies[3]["props"], "override_username")
+	assert.NotContains(t, bodies[3]["props"], "from_webhook")
+}
+
 func TestCreatePostServerError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -76,6 +136,31 @@ func TestCreatePostServerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "status 500")
 }
 
+func TestCreatePostRetriesServerError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req createPostRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "channel-123", req.ChannelID, "body is resent on retry")
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetRetryPolicy(retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	postID, err := client.CreatePost(context.Background(), "channel-123", post.Attachment{Title: "Test"})
+	require.NoError(t, err)
+	assert.Equal(t, "post-123", postID)
+	assert.Equal(t, 2, calls)
+}
+
 func TestCreatePostNetworkError(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
 	client := NewClient("http://localhost:1", "test-token", logger)
@@ -177,6 +262,331 @@ func TestGetUserError(t *testing.T) {
 	assert.Contains(t, err.Error(), "status 404")
 }
 
+func TestGetReactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/posts/post-1/reactions", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[{"user_id":"u1","post_id":"post-1","emoji_name":"+1","create_at":1},{"user_id":"u2","post_id":"post-1","emoji_name":"eyes","create_at":2}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	reactions, err := client.GetReactions(context.Background(), "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, []port.Reaction{{UserID: "u1", EmojiName: "+1"}, {UserID: "u2", EmojiName: "eyes"}}, reactions)
+}
+
+func TestGetReactionsNullBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`null`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	reactions, err := client.GetReactions(context.Background(), "post-1")
+	require.NoError(t, err)
+	assert.Empty(t, reactions)
+}
+
+func TestGetReactionsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.GetReactions(context.Background(), "post-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestGetUserIDByUsername(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/users/username/alice", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte(`{"id":"user-alice","username":"alice"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	userID, err := client.GetUserIDByUsername(context.Background(), "@alice")
+	require.NoError(t, err)
+	assert.Equal(t, "user-alice", userID)
+}
+
+func TestOpenDialog(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/actions/dialogs/open", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.OpenDialog(context.Background(), "trigger-1", "https://bridge/api/v1/callback/dialog", port.Dialog{
+		CallbackID:  "resolve",
+		Title:       "Resolve alert",
+		SubmitLabel: "Resolve",
+		State:       `{"fingerprint":"fp-1"}`,
+		Elements: []port.DialogElement{
+			{Name: "note", DisplayName: "Resolution note", Type: port.DialogElementTextarea},
+			{Name: "category", DisplayName: "Root cause", Type: port.DialogElementSelect, Optional: true, Options: []port.DialogOption{{Text: "Network", Value: "Network"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "trigger-1", body["trigger_id"])
+	assert.Equal(t, "https://bridge/api/v1/callback/dialog", body["url"])
+	dialog := body["dialog"].(map[string]any)
+	assert.Equal(t, "resolve", dialog["callback_id"])
+	assert.Equal(t, `{"fingerprint":"fp-1"}`, dialog["state"])
+	elements := dialog["elements"].([]any)
+	require.Len(t, elements, 2)
+	assert.Equal(t, map[string]any{"display_name": "Resolution note", "name": "note", "type": "textarea", "optional": false}, elements[0])
+	assert.Equal(t, []any{map[string]any{"text": "Network", "value": "Network"}}, elements[1].(map[string]any)["options"])
+}
+
+func TestGetUsernameByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/users/email/alice@example.com":
+			_, _ = w.Write([]byte(`{"id":"user-alice","username":"alice"}`))
+		case "/api/v4/users/email/broken@example.com":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	username, err := client.GetUsernameByEmail(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", username)
+
+	_, err = client.GetUsernameByEmail(context.Background(), "nobody@example.com")
+	require.ErrorIs(t, err, port.ErrMattermostUserNotFound)
+
+	_, err = client.GetUsernameByEmail(context.Background(), "broken@example.com")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrMattermostUserNotFound)
+	assert.Contains(t, err.Error(), "status 500")
+}
+
+func TestGetChannelIDByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/teams/team-1/channels/name/alerts-prod":
+			_, _ = w.Write([]byte(`{"id":"channel-1","name":"alerts-prod"}`))
+		case "/api/v4/teams/team-1/channels/name/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	id, err := client.GetChannelIDByName(context.Background(), "team-1", "alerts-prod")
+	require.NoError(t, err)
+	assert.Equal(t, "channel-1", id)
+
+	_, err = client.GetChannelIDByName(context.Background(), "team-1", "missing")
+	require.ErrorIs(t, err, port.ErrMattermostChannelNotFound)
+
+	_, err = client.GetChannelIDByName(context.Background(), "team-1", "broken")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrMattermostChannelNotFound)
+}
+
+func TestCreateChannel(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v4/channels", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"channel-new"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	id, err := client.CreateChannel(context.Background(), "team-1", "alerts-prod", true)
+	require.NoError(t, err)
+	assert.Equal(t, "channel-new", id)
+	assert.Equal(t, map[string]string{
+		"team_id":      "team-1",
+		"name":         "alerts-prod",
+		"display_name": "alerts-prod",
+		"type":         "P",
+	}, body)
+}
+
+func TestGetPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v4/posts/post-1":
+			_, _ = w.Write([]byte(`{"id":"post-1","channel_id":"channel-1","delete_at":0}`))
+		case "/api/v4/posts/post-deleted":
+			_, _ = w.Write([]byte(`{"id":"post-deleted","channel_id":"channel-1","delete_at":1717236000000}`))
+		case "/api/v4/posts/post-broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	p, err := client.GetPost(context.Background(), "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, port.MattermostPost{ID: "post-1", ChannelID: "channel-1"}, p)
+
+	_, err = client.GetPost(context.Background(), "post-gone")
+	require.ErrorIs(t, err, port.ErrMattermostPostNotFound)
+
+	_, err = client.GetPost(context.Background(), "post-deleted")
+	require.ErrorIs(t, err, port.ErrMattermostPostNotFound, "deleted posts visible to admins count as deleted")
+
+	_, err = client.GetPost(context.Background(), "post-broken")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrMattermostPostNotFound)
+}
+
+func TestGetUserIDByUsernameNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.GetUserIDByUsername(context.Background(), "ghost")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestMembership(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/teams/team-ops/members/ids", "/api/v4/channels/chan-oncall/members/ids":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var ids []string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ids))
+			if ids[0] == "user-bob" {
+				_, _ = w.Write([]byte(`[{"user_id":"user-bob"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v4/users/user-bob/groups":
+			assert.Equal(t, http.MethodGet, r.Method)
+			_, _ = w.Write([]byte(`[{"id":"g1","name":"sre"},{"id":"g2","name":"dba"}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	ok, err := client.IsTeamMember(ctx, "team-ops", "user-bob")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = client.IsTeamMember(ctx, "team-ops", "user-dave")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = client.IsChannelMember(ctx, "chan-oncall", "user-bob")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	groups, err := client.GetUserGroups(ctx, "user-bob")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sre", "dba"}, groups)
+}
+
+func TestCreateDirectChannel(t *testing.T) {
+	meCalls := 0
+	var members [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/users/me":
+			meCalls++
+			_, _ = w.Write([]byte(`{"id":"bot-id"}`))
+		case "/api/v4/channels/direct":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var ids []string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ids))
+			members = append(members, ids)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"dm-` + ids[1] + `","type":"D"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	channelID, err := client.CreateDirectChannel(context.Background(), "user-alice")
+	require.NoError(t, err)
+	assert.Equal(t, "dm-user-alice", channelID)
+
+	channelID, err = client.CreateDirectChannel(context.Background(), "user-bob")
+	require.NoError(t, err)
+	assert.Equal(t, "dm-user-bob", channelID)
+
+	assert.Equal(t, 1, meCalls, "bot user ID is cached")
+	assert.Equal(t, [][]string{{"bot-id", "user-alice"}, {"bot-id", "user-bob"}}, members)
+}
+
+func TestCreateDirectChannelBotLookupError(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/users/me" && fail {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/api/v4/users/me" {
+			_, _ = w.Write([]byte(`{"id":"bot-id"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"dm-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.CreateDirectChannel(context.Background(), "user-alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+
+	fail = false
+	channelID, err := client.CreateDirectChannel(context.Background(), "user-alice")
+	require.NoError(t, err, "failed lookups are not cached")
+	assert.Equal(t, "dm-1", channelID)
+}
+
 func TestNewClient(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
 	client := NewClient("https://mattermost.example.com", "token-123", logger)
@@ -297,6 +707,39 @@ func TestToWireAttachment_AllFieldsPopulated(t *testing.T) {
 	assert.Equal(t, "Resolve", wire.Actions[1].Name)
 }
 
+func TestToWireAttachment_SelectMenus(t *testing.T) {
+	attachment := post.Attachment{
+		Actions: []post.Button{
+			{
+				ID:      "assign",
+				Name:    "Assign",
+				Type:    post.ButtonTypeSelect,
+				Options: []post.SelectOption{{Text: "@alice", Value: "alice"}},
+			},
+			{
+				ID:         "assign-any",
+				Name:       "Assign",
+				Type:       post.ButtonTypeSelect,
+				DataSource: post.DataSourceUsers,
+			},
+		},
+	}
+
+	data, err := json.Marshal(toWireAttachment(attachment))
+	require.NoError(t, err)
+
+	var decoded struct {
+		Actions []map[string]any `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Actions, 2)
+	assert.Equal(t, "select", decoded.Actions[0]["type"])
+	assert.Equal(t, []any{map[string]any{"text": "@alice", "value": "alice"}}, decoded.Actions[0]["options"])
+	assert.NotContains(t, decoded.Actions[0], "data_source")
+	assert.Equal(t, "users", decoded.Actions[1]["data_source"])
+	assert.NotContains(t, decoded.Actions[1], "options")
+}
+
 func TestUpdatePostNetworkError(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
 	client := NewClient("http://localhost:1", "test-token", logger)
@@ -521,3 +964,142 @@ func TestToWireAttachment_ButtonWithStyle(t *testing.T) {
 	assert.Equal(t, "Processing...", wire.Actions[0].Name)
 	assert.Equal(t, "success", wire.Actions[0].Style)
 }
+
+func TestSetCustomStatus(t *testing.T) {
+	var capturedMethod string
+	var captured customStatusRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/users/me/status/custom", r.URL.Path)
+		capturedMethod = r.Method
+		if r.Method == http.MethodPut {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.SetCustomStatus(context.Background(), "rotating_light", "2 active critical alerts"))
+	assert.Equal(t, http.MethodPut, capturedMethod)
+	assert.Equal(t, "rotating_light", captured.Emoji)
+	assert.Equal(t, "2 active critical alerts", captured.Text)
+
+	require.NoError(t, client.SetCustomStatus(context.Background(), "", ""))
+	assert.Equal(t, http.MethodDelete, capturedMethod)
+}
+
+func TestSetChannelPurpose(t *testing.T) {
+	var captured channelPatchRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/channels/channel-1/patch", r.URL.Path)
+		assert.Equal(t, http.MethodPut, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.SetChannelPurpose(context.Background(), "channel-1", ":white_check_mark: No active critical alerts"))
+	assert.Equal(t, ":white_check_mark: No active critical alerts", captured.Purpose)
+}
+
+func TestSetChannelPurposeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.SetChannelPurpose(context.Background(), "channel-1", "purpose")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestPinPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/posts/post-1/pin", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.PinPost(context.Background(), "post-1"))
+}
+
+func TestDeletePost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/posts/post-1", r.URL.Path)
+		assert.Equal(t, http.MethodDelete, r.Method)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.DeletePost(context.Background(), "post-1"))
+}
+
+func TestDeletePostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.DeletePost(context.Background(), "post-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestCreateThreadPost(t *testing.T) {
+	var captured createPostRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/posts", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "reply-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	postID, err := client.CreateThreadPost(context.Background(), "channel-1", "root-1", post.Attachment{Title: "Grouped"})
+	require.NoError(t, err)
+	assert.Equal(t, "reply-1", postID)
+	assert.Equal(t, "channel-1", captured.ChannelID)
+	assert.Equal(t, "root-1", captured.RootID)
+	assert.Contains(t, captured.Props, "attachments")
+}
+
+func TestCreatePostOmitsRootID(t *testing.T) {
+	var raw map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.CreatePost(context.Background(), "channel-1", post.Attachment{Title: "Standalone"})
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "root_id")
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "delete_post", snakeCase("DeletePost"))
+	assert.Equal(t, "get_me", snakeCase("GetMe"))
+	assert.Equal(t, "create_direct_channel", snakeCase("CreateDirectChannel"))
+}
@@ -0,0 +1,18 @@
+package mattermost
+
+import "github.com/alexmorbo/keep-mattermost-bridge/application/port"
+
+// Compile-time contract: Client is wired into use cases as these ports.
+var (
+	_ port.MattermostClient         = (*Client)(nil)
+	_ port.MattermostStatusClient   = (*Client)(nil)
+	_ port.MattermostThreadClient   = (*Client)(nil)
+	_ port.MattermostPostReader     = (*Client)(nil)
+	_ port.MattermostReactionClient = (*Client)(nil)
+	_ port.MattermostReactionEvents = (*Client)(nil)
+	_ port.MattermostReplyEvents    = (*Client)(nil)
+	_ port.MattermostDirectClient   = (*Client)(nil)
+	_ port.MattermostDialogClient   = (*Client)(nil)
+	_ port.MattermostUserDirectory  = (*Client)(nil)
+	_ port.MattermostPinClient      = (*Client)(nil)
+)
@@ -0,0 +1,216 @@
+package mattermost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"golang.org/x/net/websocket"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	// eventPingInterval is how often Listen pings Mattermost over the
+	// WebSocket. A connection that sends nothing, not even the replies, for
+	// eventReadTimeout is taken as dead.
+	eventPingInterval = 30 * time.Second
+	eventReadTimeout  = 2 * eventPingInterval
+
+	eventReactionAdded = "reaction_added"
+	eventPosted        = "posted"
+)
+
+var mmEventsReceived = metrics.NewCounter(`mattermost_websocket_events_total`)
+
+// wsEvent is a message of the Mattermost WebSocket API: an event, or the
+// reply to a ping, which has no event name.
+type wsEvent struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// reactionEventData is the data of a reaction_added event, which carries the
+// reaction as a JSON string.
+type reactionEventData struct {
+	Reaction string `json:"reaction"`
+}
+
+// postedEventData is the data of a posted event, which carries the post as a
+// JSON string.
+type postedEventData struct {
+	Post string `json:"post"`
+}
+
+type wsPost struct {
+	ID      string         `json:"id"`
+	RootID  string         `json:"root_id"`
+	UserID  string         `json:"user_id"`
+	Type    string         `json:"type"`
+	Message string         `json:"message"`
+	Props   map[string]any `json:"props"`
+}
+
+type wsAction struct {
+	Seq    int64  `json:"seq"`
+	Action string `json:"action"`
+}
+
+// Listen connects to the Mattermost WebSocket API with the bot token and
+// calls handle for every reaction added to a post the bot can see, in turn.
+// It returns when ctx ends or the connection fails.
+func (c *Client) Listen(ctx context.Context, handle func(ctx context.Context, event port.ReactionEvent)) error {
+	return c.listen(ctx, eventReactionAdded, func(ctx context.Context, data json.RawMessage) {
+		reaction, err := decodeReactionEvent(data)
+		if err != nil {
+			c.logger.Warn("Skipping malformed reaction event", slog.String("error", err.Error()))
+			return
+		}
+		handle(ctx, reaction)
+	})
+}
+
+// ListenReplies connects to the Mattermost WebSocket API with the bot token
+// and calls handle for every reply posted in a thread the bot can see, in
+// turn. System messages are skipped. It returns when ctx ends or the
+// connection fails.
+func (c *Client) ListenReplies(ctx context.Context, handle func(ctx context.Context, event port.ReplyEvent)) error {
+	botUserID, err := c.getBotUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("get bot user: %w", err)
+	}
+	return c.listen(ctx, eventPosted, func(ctx context.Context, data json.RawMessage) {
+		reply, ok, err := decodeReplyEvent(data, botUserID)
+		if err != nil {
+			c.logger.Warn("Skipping malformed posted event", slog.String("error", err.Error()))
+			return
+		}
+		if ok {
+			handle(ctx, reply)
+		}
+	})
+}
+
+// listen follows the Mattermost WebSocket API and calls handle with the data
+// of every event named name, in turn.
+func (c *Client) listen(ctx context.Context, name string, handle func(ctx context.Context, data json.RawMessage)) error {
+	wsURL, err := websocketURL(c.baseURL)
+	if err != nil {
+		return err
+	}
+	cfg, err := websocket.NewConfig(wsURL, c.baseURL)
+	if err != nil {
+		return fmt.Errorf("websocket config: %w", err)
+	}
+	cfg.Header.Set("Authorization", "Bearer "+c.token)
+
+	conn, err := cfg.DialContext(ctx)
+	if err != nil {
+		return fmt.Errorf("connect to mattermost websocket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Closing the connection ends a Receive blocked when ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	go c.pingEvents(ctx, conn)
+
+	c.logger.Info("Mattermost WebSocket connected",
+		logger.ApplicationFields("mattermost_websocket_connected"),
+	)
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(eventReadTimeout)); err != nil {
+			return fmt.Errorf("set websocket read deadline: %w", err)
+		}
+		var event wsEvent
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read mattermost websocket: %w", err)
+		}
+		if event.Event == "" {
+			continue
+		}
+		mmEventsReceived.Inc()
+		if event.Event == name {
+			handle(ctx, event.Data)
+		}
+	}
+}
+
+// pingEvents pings Mattermost until ctx ends, so that a connection that went
+// away without closing runs into the read deadline.
+func (c *Client) pingEvents(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(eventPingInterval)
+	defer ticker.Stop()
+	var seq int64
+	for {
+		select {
+		case <-ticker.C:
+			seq++
+			if err := websocket.JSON.Send(conn, wsAction{Seq: seq, Action: "ping"}); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func decodeReactionEvent(data json.RawMessage) (port.ReactionEvent, error) {
+	var payload reactionEventData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return port.ReactionEvent{}, fmt.Errorf("decode event data: %w", err)
+	}
+	var r reactionResponse
+	if err := json.Unmarshal([]byte(payload.Reaction), &r); err != nil {
+		return port.ReactionEvent{}, fmt.Errorf("decode reaction: %w", err)
+	}
+	return port.ReactionEvent{PostID: r.PostID, UserID: r.UserID, EmojiName: r.EmojiName}, nil
+}
+
+// decodeReplyEvent returns the thread reply of a posted event, or false when
+// the post starts a thread or is a system message. Replies by the bot itself,
+// other bots and incoming webhooks are marked FromBot.
+func decodeReplyEvent(data json.RawMessage, botUserID string) (port.ReplyEvent, bool, error) {
+	var payload postedEventData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return port.ReplyEvent{}, false, fmt.Errorf("decode event data: %w", err)
+	}
+	var p wsPost
+	if err := json.Unmarshal([]byte(payload.Post), &p); err != nil {
+		return port.ReplyEvent{}, false, fmt.Errorf("decode post: %w", err)
+	}
+	if p.RootID == "" || p.Type != "" {
+		return port.ReplyEvent{}, false, nil
+	}
+	return port.ReplyEvent{
+		PostID:  p.ID,
+		RootID:  p.RootID,
+		UserID:  p.UserID,
+		Message: p.Message,
+		FromBot: p.UserID == botUserID || p.Props["from_bot"] == "true" || p.Props["from_webhook"] == "true",
+	}, true, nil
+}
+
+// websocketURL returns the WebSocket API endpoint of the Mattermost server at
+// baseURL.
+func websocketURL(baseURL string) (string, error) {
+	switch {
+	case strings.HasPrefix(baseURL, "https://"):
+		return "wss://" + strings.TrimPrefix(baseURL, "https://") + "/api/v4/websocket", nil
+	case strings.HasPrefix(baseURL, "http://"):
+		return "ws://" + strings.TrimPrefix(baseURL, "http://") + "/api/v4/websocket", nil
+	default:
+		return "", fmt.Errorf("mattermost url %q must start with http:// or https://", baseURL)
+	}
+}
@@ -0,0 +1,123 @@
+package mattermost
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+func TestListenReactions(t *testing.T) {
+	authHeader := make(chan string, 1)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		authHeader <- conn.Request().Header.Get("Authorization")
+		for _, msg := range []string{
+			`{"event":"hello","data":{"server_version":"9.11.0"}}`,
+			`{"event":"posted","data":{"post":"{}"}}`,
+			`{"status":"OK","seq_reply":1}`,
+			`{"event":"reaction_added","data":{"reaction":"{\"user_id\":\"user-1\",\"post_id\":\"post-1\",\"emoji_name\":\"eyes\"}"}}`,
+			`{"event":"reaction_added","data":{"reaction":"not json"}}`,
+			`{"event":"reaction_added","data":{"reaction":"{\"user_id\":\"user-2\",\"post_id\":\"post-2\",\"emoji_name\":\"white_check_mark\"}"}}`,
+		} {
+			require.NoError(t, websocket.Message.Send(conn, msg))
+		}
+		// Hold the connection open until the client goes away.
+		var discard string
+		_ = websocket.Message.Receive(conn, &discard)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []port.ReactionEvent
+	err := client.Listen(ctx, func(ctx context.Context, event port.ReactionEvent) {
+		events = append(events, event)
+		if len(events) == 2 {
+			cancel()
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "Bearer test-token", <-authHeader)
+	assert.Equal(t, []port.ReactionEvent{
+		{PostID: "post-1", UserID: "user-1", EmojiName: "eyes"},
+		{PostID: "post-2", UserID: "user-2", EmojiName: "white_check_mark"},
+	}, events)
+}
+
+func TestListenReplies(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/users/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"bot-user"}`))
+	})
+	mux.Handle("/api/v4/websocket", websocket.Handler(func(conn *websocket.Conn) {
+		for _, msg := range []string{
+			`{"event":"reaction_added","data":{"reaction":"{}"}}`,
+			`{"event":"posted","data":{"post":"{\"id\":\"root-1\",\"user_id\":\"user-1\",\"message\":\"not a reply\"}"}}`,
+			`{"event":"posted","data":{"post":"{\"id\":\"sys-1\",\"root_id\":\"root-1\",\"type\":\"system_join_channel\"}"}}`,
+			`{"event":"posted","data":{"post":"not json"}}`,
+			`{"event":"posted","data":{"post":"{\"id\":\"reply-1\",\"root_id\":\"root-1\",\"user_id\":\"user-1\",\"message\":\"Restarted the pod\"}"}}`,
+			`{"event":"posted","data":{"post":"{\"id\":\"reply-2\",\"root_id\":\"root-1\",\"user_id\":\"bot-user\",\"message\":\"Alert resolved\"}"}}`,
+			`{"event":"posted","data":{"post":"{\"id\":\"reply-3\",\"root_id\":\"root-1\",\"user_id\":\"hook-user\",\"message\":\"From CI\",\"props\":{\"from_webhook\":\"true\"}}"}}`,
+		} {
+			require.NoError(t, websocket.Message.Send(conn, msg))
+		}
+		var discard string
+		_ = websocket.Message.Receive(conn, &discard)
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []port.ReplyEvent
+	err := client.ListenReplies(ctx, func(ctx context.Context, event port.ReplyEvent) {
+		events = append(events, event)
+		if len(events) == 3 {
+			cancel()
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []port.ReplyEvent{
+		{PostID: "reply-1", RootID: "root-1", UserID: "user-1", Message: "Restarted the pod"},
+		{PostID: "reply-2", RootID: "root-1", UserID: "bot-user", Message: "Alert resolved", FromBot: true},
+		{PostID: "reply-3", RootID: "root-1", UserID: "hook-user", Message: "From CI", FromBot: true},
+	}, events)
+}
+
+func TestListenConnectionClosed(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	err := client.Listen(context.Background(), func(ctx context.Context, event port.ReactionEvent) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read mattermost websocket")
+}
+
+func TestWebsocketURL(t *testing.T) {
+	u, err := websocketURL("https://mm.example.com/base")
+	require.NoError(t, err)
+	assert.Equal(t, "wss://mm.example.com/base/api/v4/websocket", u)
+
+	u, err = websocketURL("http://localhost:8065")
+	require.NoError(t, err)
+	assert.Equal(t, "ws://localhost:8065/api/v4/websocket", u)
+
+	_, err = websocketURL("mm.example.com")
+	assert.Error(t, err)
+}
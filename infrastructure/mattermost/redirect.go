@@ -0,0 +1,75 @@
+package mattermost
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/VictoriaMetrics/metrics"
+)
+
+const maxRedirects = 10
+
+var mmRedirects = metrics.NewCounter(`mattermost_redirects_total`)
+
+// checkRedirect is the redirect policy for Mattermost HA clusters, where a
+// node may answer with 307/308 to send the request to another node.
+//
+// net/http already replays the body for 307/308 when the request has
+// GetBody, but it drops the Authorization header once the redirect leaves
+// the original host. Cluster nodes usually have their own hostnames, so the
+// header is copied from the original request to the nodes SetClusterHosts
+// lists, and to no other host. Redirects that would downgrade
+// to plain HTTP are refused so the token never travels in clear text, and
+// 301/302/303 redirects of write requests are refused because net/http turns
+// them into a GET and the write would be silently lost.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	orig := via[0]
+	prev := via[len(via)-1]
+	if prev.URL.Scheme == "https" && req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing redirect from https to %s", req.URL.Redacted())
+	}
+	if req.Method != orig.Method {
+		return fmt.Errorf("refusing redirect that changes %s to %s", orig.Method, req.Method)
+	}
+
+	if auth := orig.Header.Get("Authorization"); auth != "" && c.isClusterHost(req.URL) {
+		req.Header.Set("Authorization", auth)
+	}
+
+	mmRedirects.Inc()
+	c.logger.Debug("Following Mattermost redirect",
+		slog.String("method", req.Method),
+		slog.String("from", prev.URL.Redacted()),
+		slog.String("to", req.URL.Redacted()),
+	)
+
+	return nil
+}
+
+// SetClusterHosts lists the hosts of the other nodes of a Mattermost HA
+// cluster, as "host" or "host:port", that redirects may send the bot token
+// to. Redirects to the host of the client's URL keep the token as well.
+func (c *Client) SetClusterHosts(hosts []string) {
+	c.clusterHosts = hosts
+}
+
+// isClusterHost reports whether u is on the host of the client's URL or one
+// of the cluster hosts.
+func (c *Client) isClusterHost(u *url.URL) bool {
+	if base, err := url.Parse(c.baseURL); err == nil && strings.EqualFold(base.Host, u.Host) {
+		return true
+	}
+	for _, host := range c.clusterHosts {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
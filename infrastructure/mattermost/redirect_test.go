@@ -0,0 +1,152 @@
+package mattermost
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// newRedirectingServer answers every request with the given redirect status
+// pointing at target, keeping the request path.
+func newRedirectingServer(t *testing.T, status int, target string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target+r.URL.RequestURI(), status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCreatePostFollowsHARedirect(t *testing.T) {
+	for _, status := range []int{http.StatusTemporaryRedirect, http.StatusPermanentRedirect} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var captured createPostRequest
+			var capturedAuth string
+			// 127.0.0.1 and localhost are different hosts, so net/http would
+			// drop the Authorization header without the redirect policy.
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/api/v4/posts", r.URL.Path)
+				capturedAuth = r.Header.Get("Authorization")
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(body, &captured))
+
+				w.WriteHeader(http.StatusCreated)
+				_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-123"})
+			}))
+			defer target.Close()
+			targetURL := "http://localhost:" + target.URL[len("http://127.0.0.1:"):]
+
+			node := newRedirectingServer(t, status, targetURL)
+			client := NewClient(node.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+			client.SetClusterHosts([]string{"localhost"})
+
+			postID, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test"})
+			require.NoError(t, err)
+			assert.Equal(t, "post-123", postID)
+			assert.Equal(t, "Bearer test-token", capturedAuth)
+			assert.Equal(t, "channel-abc", captured.ChannelID)
+		})
+	}
+}
+
+func TestRedirectToForeignHostDropsToken(t *testing.T) {
+	var capturedAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: "post-123"})
+	}))
+	defer target.Close()
+	// localhost is not the node's host 127.0.0.1 and not a cluster host.
+	targetURL := "http://localhost:" + target.URL[len("http://127.0.0.1:"):]
+
+	node := newRedirectingServer(t, http.StatusTemporaryRedirect, targetURL)
+	client := NewClient(node.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.SetClusterHosts([]string{"mm-node-2.example.com"})
+
+	_, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test"})
+	require.NoError(t, err)
+	assert.Empty(t, capturedAuth, "the bot token is not sent to hosts outside the cluster")
+}
+
+func TestUpdatePostFollowsHARedirect(t *testing.T) {
+	var capturedBody []byte
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		capturedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	node := newRedirectingServer(t, http.StatusTemporaryRedirect, target.URL)
+	client := NewClient(node.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.UpdatePost(context.Background(), "post-1", post.Attachment{Title: "Updated"}))
+
+	var req updatePostRequest
+	require.NoError(t, json.Unmarshal(capturedBody, &req))
+	assert.Equal(t, "post-1", req.ID)
+}
+
+func TestRedirectRefusesMethodChange(t *testing.T) {
+	targetCalled := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetCalled = true
+	}))
+	defer target.Close()
+
+	node := newRedirectingServer(t, http.StatusFound, target.URL)
+	client := NewClient(node.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing redirect that changes POST to GET")
+	assert.False(t, targetCalled)
+}
+
+func TestRedirectRefusesHTTPSDowngrade(t *testing.T) {
+	targetCalled := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetCalled = true
+	}))
+	defer target.Close()
+
+	node := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	defer node.Close()
+
+	client := NewClient(node.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	client.httpClient.Transport = node.Client().Transport
+
+	_, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing redirect from https")
+	assert.False(t, targetCalled)
+}
+
+func TestRedirectLoopStops(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	_, err := client.CreatePost(context.Background(), "channel-abc", post.Attachment{Title: "Test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stopped after 10 redirects")
+}
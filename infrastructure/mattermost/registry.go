@@ -0,0 +1,198 @@
+package mattermost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// idSeparator joins a server name and the ID of a post, user or dialog
+// trigger on that server. Mattermost IDs never contain it; Teams IDs may.
+const idSeparator = ":"
+
+// Server is a chat server a registry posts to: another Mattermost server, or
+// an adapter for another chat system such as Slack.
+type Server interface {
+	CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error)
+	CreateThreadPost(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error)
+	UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error
+	DeletePost(ctx context.Context, postID string) error
+	ReplyToThread(ctx context.Context, channelID, rootID, message string) error
+	GetPost(ctx context.Context, postID string) (port.MattermostPost, error)
+	GetReactions(ctx context.Context, postID string) ([]port.Reaction, error)
+	SetChannelPurpose(ctx context.Context, channelID, purpose string) error
+	GetUser(ctx context.Context, userID string) (string, error)
+	IsTeamMember(ctx context.Context, teamID, userID string) (bool, error)
+	IsChannelMember(ctx context.Context, channelID, userID string) (bool, error)
+	GetUserGroups(ctx context.Context, userID string) ([]string, error)
+	OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error
+}
+
+// Registry is a Mattermost client for several servers: the main one and
+// named others. Posts go to the server of their channel, the main one unless
+// the channel was added with another server. IDs from a named server are
+// returned as "name:id", so post records remember the server their post lives
+// on, and calls given such an ID go to its server. Users, statuses, direct
+// messages, channel lookups by name and reaction and reply events are those
+// of the main server: the methods for them take no post or channel IDs, and
+// CreateDirectChannel refuses a user of another server.
+type Registry struct {
+	*Client
+	servers  map[string]Server
+	channels map[string]string // channel ID -> server name
+}
+
+// NewRegistry returns a registry posting everything to main until servers are
+// added.
+func NewRegistry(main *Client) *Registry {
+	return &Registry{
+		Client:   main,
+		servers:  make(map[string]Server),
+		channels: make(map[string]string),
+	}
+}
+
+// Add registers the server name, whose posts go to the given channels.
+func (r *Registry) Add(name string, client Server, channelIDs []string) {
+	r.servers[name] = client
+	for _, channelID := range channelIDs {
+		r.channels[channelID] = name
+	}
+}
+
+// QualifyID returns id, an ID Mattermost sent with a request from channelID,
+// as the registry returns IDs of that channel's server. It implements
+// port.MattermostServerIDs.
+func (r *Registry) QualifyID(channelID, id string) string {
+	if id == "" {
+		return id
+	}
+	// Some chat systems use the separator in their own IDs, so only an ID
+	// starting with a known server is taken as qualified.
+	if name, _, ok := strings.Cut(id, idSeparator); ok && r.servers[name] != nil {
+		return id
+	}
+	return qualify(r.channels[channelID], id)
+}
+
+func qualify(server, id string) string {
+	if server == "" || id == "" {
+		return id
+	}
+	return server + idSeparator + id
+}
+
+// byChannel returns the client of the server channelID is on, and its name.
+func (r *Registry) byChannel(channelID string) (Server, string) {
+	if name, ok := r.channels[channelID]; ok {
+		return r.servers[name], name
+	}
+	return r.Client, ""
+}
+
+// byID returns the client of the server id is from, its name, and id
+// without the server.
+func (r *Registry) byID(id string) (Server, string, string) {
+	if name, raw, ok := strings.Cut(id, idSeparator); ok {
+		if client, known := r.servers[name]; known {
+			return client, name, raw
+		}
+	}
+	return r.Client, "", id
+}
+
+func (r *Registry) CreatePost(ctx context.Context, channelID string, attachment post.Attachment) (string, error) {
+	client, name := r.byChannel(channelID)
+	postID, err := client.CreatePost(ctx, channelID, attachment)
+	return qualify(name, postID), err
+}
+
+func (r *Registry) CreateThreadPost(ctx context.Context, channelID, rootID string, attachment post.Attachment) (string, error) {
+	client, name, rootID := r.byID(rootID)
+	postID, err := client.CreateThreadPost(ctx, channelID, rootID, attachment)
+	return qualify(name, postID), err
+}
+
+func (r *Registry) UpdatePost(ctx context.Context, postID string, attachment post.Attachment) error {
+	client, _, postID := r.byID(postID)
+	return client.UpdatePost(ctx, postID, attachment)
+}
+
+func (r *Registry) DeletePost(ctx context.Context, postID string) error {
+	client, _, postID := r.byID(postID)
+	return client.DeletePost(ctx, postID)
+}
+
+func (r *Registry) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
+	client, _, rootID := r.byID(rootID)
+	return client.ReplyToThread(ctx, channelID, rootID, message)
+}
+
+func (r *Registry) GetPost(ctx context.Context, postID string) (port.MattermostPost, error) {
+	client, name, postID := r.byID(postID)
+	p, err := client.GetPost(ctx, postID)
+	p.ID = qualify(name, p.ID)
+	return p, err
+}
+
+func (r *Registry) GetReactions(ctx context.Context, postID string) ([]port.Reaction, error) {
+	client, name, postID := r.byID(postID)
+	reactions, err := client.GetReactions(ctx, postID)
+	for i := range reactions {
+		reactions[i].UserID = qualify(name, reactions[i].UserID)
+	}
+	return reactions, err
+}
+
+// PinPost pins postID on its server. Servers that cannot pin posts return
+// an error.
+func (r *Registry) PinPost(ctx context.Context, postID string) error {
+	client, name, postID := r.byID(postID)
+	pinner, ok := client.(port.MattermostPinClient)
+	if !ok {
+		return fmt.Errorf("pinning posts is not supported on server %s", name)
+	}
+	return pinner.PinPost(ctx, postID)
+}
+
+func (r *Registry) SetChannelPurpose(ctx context.Context, channelID, purpose string) error {
+	client, _ := r.byChannel(channelID)
+	return client.SetChannelPurpose(ctx, channelID, purpose)
+}
+
+func (r *Registry) GetUser(ctx context.Context, userID string) (string, error) {
+	client, _, userID := r.byID(userID)
+	return client.GetUser(ctx, userID)
+}
+
+func (r *Registry) IsTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
+	client, _, userID := r.byID(userID)
+	return client.IsTeamMember(ctx, teamID, userID)
+}
+
+func (r *Registry) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	client, _, userID := r.byID(userID)
+	return client.IsChannelMember(ctx, channelID, userID)
+}
+
+func (r *Registry) GetUserGroups(ctx context.Context, userID string) ([]string, error) {
+	client, _, userID := r.byID(userID)
+	return client.GetUserGroups(ctx, userID)
+}
+
+func (r *Registry) OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+	client, _, triggerID := r.byID(triggerID)
+	return client.OpenDialog(ctx, triggerID, submitURL, dialog)
+}
+
+// CreateDirectChannel opens a direct message channel on the main server,
+// which other servers' users are not on.
+func (r *Registry) CreateDirectChannel(ctx context.Context, userID string) (string, error) {
+	if _, name, _ := r.byID(userID); name != "" {
+		return "", fmt.Errorf("direct messages are not supported on server %s", name)
+	}
+	return r.Client.CreateDirectChannel(ctx, userID)
+}
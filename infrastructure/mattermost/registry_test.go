@@ -0,0 +1,107 @@
+package mattermost
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// fakeServer answers post creation with postID and records the paths it was
+// asked for.
+func fakeServer(t *testing.T, postID string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_ = json.NewEncoder(w).Encode(createPostResponse{ID: postID})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+func TestRegistryRoutesByChannelAndPostID(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	mainServer, mainPaths := fakeServer(t, "main-post")
+	customerServer, customerPaths := fakeServer(t, "customer-post")
+
+	registry := NewRegistry(NewClient(mainServer.URL, "main-token", logger))
+	registry.Add("customer", NewClient(customerServer.URL, "customer-token", logger), []string{"customer-alerts"})
+	ctx := context.Background()
+
+	postID, err := registry.CreatePost(ctx, "ops-alerts", post.Attachment{Title: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "main-post", postID)
+
+	postID, err = registry.CreatePost(ctx, "customer-alerts", post.Attachment{Title: "customer"})
+	require.NoError(t, err)
+	assert.Equal(t, "customer:customer-post", postID)
+
+	require.NoError(t, registry.UpdatePost(ctx, postID, post.Attachment{Title: "updated"}))
+	require.NoError(t, registry.UpdatePost(ctx, "main-post", post.Attachment{Title: "updated"}))
+
+	assert.Equal(t, []string{"POST /api/v4/posts", "PUT /api/v4/posts/main-post"}, mainPaths())
+	assert.Equal(t, []string{"POST /api/v4/posts", "PUT /api/v4/posts/customer-post"}, customerPaths())
+}
+
+func TestRegistryQualifyID(t *testing.T) {
+	registry := NewRegistry(&Client{})
+	registry.Add("customer", &Client{}, []string{"customer-alerts"})
+
+	assert.Equal(t, "customer:user-1", registry.QualifyID("customer-alerts", "user-1"))
+	assert.Equal(t, "customer:user-1", registry.QualifyID("customer-alerts", "customer:user-1"), "qualified IDs are left alone")
+	assert.Equal(t, "user-1", registry.QualifyID("ops-alerts", "user-1"))
+	assert.Equal(t, "customer:29:user-1", registry.QualifyID("customer-alerts", "29:user-1"), "IDs containing the separator are qualified")
+	assert.Equal(t, "", registry.QualifyID("customer-alerts", ""))
+}
+
+func TestRegistryDirectChannelsOnMainServerOnly(t *testing.T) {
+	registry := NewRegistry(&Client{})
+	registry.Add("customer", &Client{}, []string{"customer-alerts"})
+
+	_, err := registry.CreateDirectChannel(context.Background(), "customer:user-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "direct messages are not supported on server customer")
+}
+
+func TestRegistryPinsPostsOnTheirServer(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	mainServer, mainPaths := fakeServer(t, "main-post")
+	customerServer, customerPaths := fakeServer(t, "customer-post")
+
+	registry := NewRegistry(NewClient(mainServer.URL, "main-token", logger))
+	registry.Add("customer", NewClient(customerServer.URL, "customer-token", logger), []string{"customer-alerts"})
+	registry.Add("chat", struct{ Server }{}, []string{"chat-alerts"})
+	ctx := context.Background()
+
+	boardID, err := registry.CreatePost(ctx, "customer-alerts", post.Attachment{Title: "Status board"})
+	require.NoError(t, err)
+	require.NoError(t, registry.PinPost(ctx, boardID))
+	require.NoError(t, registry.PinPost(ctx, "main-post"))
+
+	assert.Equal(t, []string{"POST /api/v4/posts/main-post/pin"}, mainPaths())
+	assert.Equal(t, []string{"POST /api/v4/posts", "POST /api/v4/posts/customer-post/pin"}, customerPaths())
+
+	err = registry.PinPost(ctx, "chat:post-1")
+	require.Error(t, err, "servers that cannot pin say so")
+	assert.Contains(t, err.Error(), "pinning posts is not supported on server chat")
+}
@@ -8,8 +8,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
 	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
 	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
 )
 
 func TestBuildFiringAttachment(t *testing.T) {
@@ -125,7 +128,7 @@ func TestBuildFiringAttachment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			builder := NewBuilder(tt.fileConfig)
+			builder := newTestBuilder(t, tt.fileConfig)
 
 			severity, err := alert.NewSeverity(tt.alertSeverity)
 			require.NoError(t, err)
@@ -193,7 +196,7 @@ func TestBuildAcknowledgedAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("critical")
 	require.NoError(t, err)
@@ -244,7 +247,7 @@ func TestBuildResolvedAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("high")
 	require.NoError(t, err)
@@ -288,7 +291,7 @@ func TestBuildResolvedAttachmentWithAcknowledgedBy(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("high")
 	require.NoError(t, err)
@@ -331,7 +334,7 @@ func TestBuildAcknowledgedAttachmentWithFooter(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("critical")
 	require.NoError(t, err)
@@ -443,7 +446,7 @@ func TestBuildFieldsFiltering(t *testing.T) {
 				},
 			}
 
-			builder := NewBuilder(fileConfig)
+			builder := newTestBuilder(t, fileConfig)
 
 			severity, err := alert.NewSeverity("info")
 			require.NoError(t, err)
@@ -478,62 +481,6 @@ func TestBuildFieldsFiltering(t *testing.T) {
 	}
 }
 
-func TestFormatDuration(t *testing.T) {
-	tests := []struct {
-		name     string
-		start    time.Time
-		expected string
-	}{
-		{
-			name:     "zero time returns empty string",
-			start:    time.Time{},
-			expected: "",
-		},
-		{
-			name:     "future time returns empty string",
-			start:    time.Now().Add(1 * time.Hour),
-			expected: "",
-		},
-		{
-			name:     "less than 1 minute ago",
-			start:    time.Now().Add(-30 * time.Second),
-			expected: "<1m",
-		},
-		{
-			name:     "45 minutes ago",
-			start:    time.Now().Add(-45 * time.Minute),
-			expected: "45m",
-		},
-		{
-			name:     "2 hours 15 minutes ago",
-			start:    time.Now().Add(-2*time.Hour - 15*time.Minute),
-			expected: "2h 15m",
-		},
-		{
-			name:     "3 days 12 hours ago",
-			start:    time.Now().Add(-3*24*time.Hour - 12*time.Hour),
-			expected: "3d 12h",
-		},
-		{
-			name:     "exactly 1 hour",
-			start:    time.Now().Add(-1 * time.Hour),
-			expected: "1h 0m",
-		},
-		{
-			name:     "exactly 1 day",
-			start:    time.Now().Add(-24 * time.Hour),
-			expected: "1d 0h",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := formatDuration(tt.start)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestDifferentSeveritiesProduceDifferentColorsAndEmojis(t *testing.T) {
 	fileConfig := &config.FileConfig{
 		Message: config.MessageConfig{
@@ -556,7 +503,7 @@ func TestDifferentSeveritiesProduceDifferentColorsAndEmojis(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severities := []struct {
 		severity      string
@@ -608,7 +555,7 @@ func TestBuildProcessingAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	testAttachment := post.Attachment{
 		Color:     "#CC0000",
@@ -671,7 +618,7 @@ func TestBuildProcessingAttachment_InvalidJSON(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	_, err := builder.BuildProcessingAttachment("invalid json", "acknowledge")
 	require.Error(t, err)
@@ -689,7 +636,7 @@ func TestBuildFiringAttachmentHasButtonStyles(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, _ := alert.NewSeverity("high")
 	fingerprint := alert.RestoreFingerprint("test-fp")
@@ -726,7 +673,7 @@ func TestBuildAcknowledgedAttachmentHasButtonStyles(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, _ := alert.NewSeverity("high")
 	fingerprint := alert.RestoreFingerprint("test-fp")
@@ -752,10 +699,95 @@ func TestBuildAcknowledgedAttachmentHasButtonStyles(t *testing.T) {
 	assert.Equal(t, "success", attachment.Actions[1].Style, "resolve button should have success style")
 }
 
+func TestBuildFiringAttachmentSnoozeButton(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Colors: map[string]string{"high": "#FF6600"},
+			Emoji:  map[string]string{"high": "🟠"},
+		},
+	}
+
+	builder := newTestBuilder(t, fileConfig)
+	snoozing := newTestBuilder(t, fileConfig, attachment.WithSnoozeDuration(90*time.Minute))
+
+	severity, _ := alert.NewSeverity("high")
+	testAlert := alert.RestoreAlert(
+		alert.RestoreFingerprint("test-fp"),
+		"Test Alert",
+		severity,
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"prometheus",
+		map[string]string{},
+		time.Time{},
+	)
+
+	attachment := builder.BuildFiringAttachment(testAlert, "http://callback.url", "http://keep.ui")
+	require.Len(t, attachment.Actions, 2, "snooze button is off by default")
+
+	attachment = snoozing.BuildFiringAttachment(testAlert, "http://callback.url", "http://keep.ui")
+
+	require.Len(t, attachment.Actions, 3)
+	snooze := attachment.Actions[2]
+	assert.Equal(t, post.ActionSnooze, snooze.ID)
+	assert.Equal(t, "Snooze 1h30m", snooze.Name)
+	assert.Equal(t, "default", snooze.Style)
+	assert.Equal(t, "http://callback.url", snooze.Integration.URL)
+	assert.Equal(t, post.ActionSnooze, snooze.Integration.Context[post.ContextKeyAction])
+	assert.Equal(t, "test-fp", snooze.Integration.Context[post.ContextKeyFingerprint])
+	assert.NotEmpty(t, snooze.Integration.Context[post.ContextKeyAttachmentJSON])
+}
+
+func TestBuildSnoozedAttachment(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Colors: map[string]string{"snoozed": "#B0A0D0"},
+			Emoji:  map[string]string{},
+			Footer: config.FooterConfig{Text: "Keep AIOps", IconURL: "https://test.com/icon.png"},
+		},
+	}
+
+	builder := newTestBuilder(t, fileConfig)
+
+	severity, _ := alert.NewSeverity("critical")
+	testAlert := alert.RestoreAlert(
+		alert.RestoreFingerprint("snoozed-fp"),
+		"Snoozed Alert",
+		severity,
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"prometheus",
+		map[string]string{"env": "production"},
+		time.Time{},
+	)
+	until := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	attachment := builder.BuildSnoozedAttachment(testAlert, "http://callback.url", "http://keep.ui", "alice", until)
+
+	assert.Equal(t, "#B0A0D0", attachment.Color)
+	assert.Contains(t, attachment.Title, "💤")
+	assert.Contains(t, attachment.Title, "Snoozed Alert")
+	assert.Equal(t, "Snoozed by @alice until 2024-03-01 14:30 UTC", attachment.Footer)
+	assert.Equal(t, "https://test.com/icon.png", attachment.FooterIcon)
+	require.Len(t, attachment.Actions, 1)
+	assert.Equal(t, post.ActionResolve, attachment.Actions[0].ID)
+	assert.Equal(t, "snoozed-fp", attachment.Actions[0].Integration.Context[post.ContextKeyFingerprint])
+
+	attachment = builder.BuildSnoozedAttachment(testAlert, "http://callback.url", "http://keep.ui", "", until)
+	assert.Equal(t, "Snoozed until 2024-03-01 14:30 UTC", attachment.Footer)
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
 
+func newTestBuilder(t *testing.T, cfg *config.FileConfig, opts ...attachment.Option) *Builder {
+	t.Helper()
+	builder, err := NewBuilder(cfg, "", opts...)
+	require.NoError(t, err)
+	return builder
+}
+
 func TestBuildFiringAttachment_SeverityFieldDisabled(t *testing.T) {
 	fileConfig := &config.FileConfig{
 		Message: config.MessageConfig{
@@ -774,7 +806,7 @@ func TestBuildFiringAttachment_SeverityFieldDisabled(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("critical")
 	require.NoError(t, err)
@@ -827,7 +859,7 @@ func TestBuildFiringAttachment_DescriptionFieldDisabled(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("warning")
 	require.NoError(t, err)
@@ -912,7 +944,7 @@ func TestBuildFiringAttachment_SeverityPositions(t *testing.T) {
 				},
 			}
 
-			builder := NewBuilder(fileConfig)
+			builder := newTestBuilder(t, fileConfig)
 
 			severity, err := alert.NewSeverity("high")
 			require.NoError(t, err)
@@ -1073,7 +1105,7 @@ func TestBuildFieldsWithGrouping(t *testing.T) {
 				},
 			}
 
-			builder := NewBuilder(fileConfig)
+			builder := newTestBuilder(t, fileConfig)
 
 			severity, _ := alert.NewSeverity("info")
 			fingerprint := alert.RestoreFingerprint("test-fp")
@@ -1148,7 +1180,7 @@ func TestBuildSuppressedAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("critical")
 	require.NoError(t, err)
@@ -1193,7 +1225,7 @@ func TestBuildPendingAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("warning")
 	require.NoError(t, err)
@@ -1238,7 +1270,7 @@ func TestBuildMaintenanceAttachment(t *testing.T) {
 		},
 	}
 
-	builder := NewBuilder(fileConfig)
+	builder := newTestBuilder(t, fileConfig)
 
 	severity, err := alert.NewSeverity("high")
 	require.NoError(t, err)
@@ -1267,3 +1299,182 @@ func TestBuildMaintenanceAttachment(t *testing.T) {
 	assert.Equal(t, "Under maintenance", attachment.Footer)
 	assert.Equal(t, "https://test.com/icon.png", attachment.FooterIcon)
 }
+
+func TestBuildClosedAttachments(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Colors: map[string]string{"dismissed": "#A9A9A9", "merged": "#6A5ACD", "expired": "#C0C0C0"},
+			Emoji:  map[string]string{},
+			Footer: config.FooterConfig{Text: "Keep AIOps", IconURL: "https://test.com/icon.png"},
+		},
+	}
+
+	builder := newTestBuilder(t, fileConfig)
+
+	tests := []struct {
+		status string
+		build  func(a *alert.Alert, keepUIURL string) post.Attachment
+		color  string
+		emoji  string
+		footer string
+	}{
+		{alert.StatusDismissed, builder.BuildDismissedAttachment, "#A9A9A9", "🚫", "Alert dismissed"},
+		{alert.StatusMerged, builder.BuildMergedAttachment, "#6A5ACD", "🔀", "Merged into another alert"},
+		{alert.StatusFiring, builder.BuildExpiredAttachment, "#C0C0C0", "⌛", "Expired: no longer tracked by the bridge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			testAlert := alert.RestoreAlert(
+				alert.RestoreFingerprint("closed-fingerprint"),
+				"Closed Alert",
+				alert.RestoreSeverity(alert.SeverityHigh),
+				alert.RestoreStatus(tt.status),
+				"",
+				"prometheus",
+				nil,
+				time.Time{},
+			)
+
+			attachment := tt.build(testAlert, "http://keep.ui")
+
+			assert.Equal(t, tt.color, attachment.Color)
+			assert.Contains(t, attachment.Title, tt.emoji)
+			assert.Contains(t, attachment.Title, "Closed Alert")
+			assert.Contains(t, attachment.TitleLink, "http://keep.ui/alerts/feed?fingerprint=closed-fingerprint")
+			assert.Len(t, attachment.Actions, 0, "should have no buttons")
+			assert.Equal(t, tt.footer, attachment.Footer)
+		})
+	}
+}
+
+func TestBuildGroupRootAttachment(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Colors: map[string]string{"critical": "#CC0000", "warning": "#EDA200", "resolved": "#00CC00"},
+			Emoji:  map[string]string{"critical": "🔴", "warning": "⚠️"},
+			Footer: config.FooterConfig{Text: "Keep AIOps", IconURL: "https://test.com/icon.png"},
+		},
+	}
+	builder := newTestBuilder(t, fileConfig)
+
+	g := group.NewGroup("alertgroup", "database", "channel-1", "root-1", time.Now())
+	g.Add("fp-1", alert.RestoreSeverity("warning"), time.Now())
+	g.Add("fp-2", alert.RestoreSeverity("critical"), time.Now())
+
+	attachment := builder.BuildGroupRootAttachment(g, "http://keep.ui")
+	assert.Equal(t, "#CC0000", attachment.Color, "colored by most severe member")
+	assert.Equal(t, "🔴 2 active alerts · alertgroup=database", attachment.Title)
+	assert.Equal(t, "http://keep.ui/alerts/feed", attachment.TitleLink)
+	require.Len(t, attachment.Fields, 2)
+	assert.Equal(t, post.AttachmentField{Title: "alertgroup", Value: "database", Short: true}, attachment.Fields[0])
+	assert.Equal(t, "2 of 2", attachment.Fields[1].Value)
+	assert.Empty(t, attachment.Actions)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	g.Remove("fp-2", time.Now())
+	attachment = builder.BuildGroupRootAttachment(g, "http://keep.ui")
+	assert.Equal(t, "#EDA200", attachment.Color)
+	assert.Equal(t, "⚠️ 1 active alert · alertgroup=database", attachment.Title)
+	assert.Equal(t, "1 of 2", attachment.Fields[1].Value)
+
+	g.Remove("fp-1", time.Now())
+	attachment = builder.BuildGroupRootAttachment(g, "http://keep.ui")
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Equal(t, "✅ All alerts resolved · alertgroup=database", attachment.Title)
+	assert.Equal(t, "0 of 2", attachment.Fields[1].Value)
+}
+
+func TestBuilderUsesClockForDuration(t *testing.T) {
+	firingStart := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(firingStart.Add(2*time.Hour + 5*time.Minute))
+
+	builder := newTestBuilder(t, &config.FileConfig{}, attachment.WithClock(fake))
+
+	testAlert := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-clock"),
+		"Replayed Alert",
+		alert.RestoreSeverity("critical"),
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"",
+		nil,
+		firingStart,
+	)
+
+	attachment := builder.BuildFiringAttachment(testAlert, "http://callback", "http://keep.ui")
+	assert.Contains(t, attachment.Title, "(2h 5m)")
+
+	fake.Advance(24 * time.Hour)
+	attachment = builder.BuildResolvedAttachment(testAlert, "http://keep.ui", "")
+	assert.Contains(t, attachment.Title, "(1d 2h)")
+}
+
+func TestBuilderForChannel(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Colors: map[string]string{"critical": "#CC0000"},
+			Profiles: []config.MessageProfileConfig{{
+				Name:     "pager",
+				Channels: []string{"ch-mobile"},
+				Colors:   map[string]string{"critical": "#FF0000"},
+				Fields:   config.ProfileFieldsConfig{Compact: boolPtr(true)},
+				Mentions: &config.MentionsConfig{Enabled: true, Rules: []config.MentionRule{{Mention: []string{"@here"}}}},
+			}},
+		},
+	}
+	builder := newTestBuilder(t, fileConfig)
+
+	testAlert := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-profile"),
+		"Disk full",
+		alert.RestoreSeverity("critical"),
+		alert.RestoreStatus(alert.StatusFiring),
+		"The disk is full",
+		"prometheus",
+		map[string]string{"host": "db-1"},
+		time.Time{},
+	)
+
+	full := builder.ForChannel("ch-ops").BuildFiringAttachment(testAlert, "http://callback", "http://keep.ui")
+	assert.Equal(t, "#CC0000", full.Color)
+	assert.NotEmpty(t, full.Fields)
+
+	compact := builder.ForChannel("ch-mobile").BuildFiringAttachment(testAlert, "http://callback", "http://keep.ui")
+	assert.Equal(t, "#FF0000", compact.Color)
+	assert.Empty(t, compact.Fields, "compact posts have no fields")
+	assert.NotEmpty(t, compact.Actions)
+
+	assert.Equal(t, "@here", builder.BuildMentionMessage(testAlert, "ch-mobile"))
+	assert.Empty(t, builder.BuildMentionMessage(testAlert, "ch-ops"), "mentions are off outside the profile")
+}
+
+func TestBuildFiringAttachment_CompactStyle(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{
+			Style: map[string]string{"info": "compact", "warning": "full"},
+		},
+		Labels: config.LabelsConfig{Display: []string{"host"}},
+	}
+	builder := newTestBuilder(t, fileConfig)
+
+	newAlert := func(severity string) *alert.Alert {
+		return alert.RestoreAlert(
+			alert.RestoreFingerprint("fp-"+severity),
+			"Disk full",
+			alert.RestoreSeverity(severity),
+			alert.RestoreStatus(alert.StatusFiring),
+			"The disk is full",
+			"prometheus",
+			map[string]string{"host": "db-1"},
+			time.Time{},
+		)
+	}
+
+	compact := builder.BuildFiringAttachment(newAlert("info"), "http://callback", "http://keep.ui")
+	assert.Empty(t, compact.Fields)
+	assert.Len(t, compact.Actions, 1)
+
+	full := builder.BuildFiringAttachment(newAlert("warning"), "http://callback", "http://keep.ui")
+	assert.NotEmpty(t, full.Fields)
+	assert.Len(t, full.Actions, 2)
+}
@@ -0,0 +1,99 @@
+package messagebuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/infrastructure/config"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/attachment"
+)
+
+func newCommandsTestAlert(labels map[string]string) *alert.Alert {
+	return alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-1"),
+		"Pod crashlooping",
+		alert.RestoreSeverity("high"),
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"prometheus",
+		labels,
+		time.Time{},
+	)
+}
+
+func TestBuildFiringAttachmentCommandsButton(t *testing.T) {
+	fileConfig := &config.FileConfig{CopyCommands: config.CopyCommandsConfig{
+		Enabled: true,
+		Commands: []config.CopyCommandConfig{
+			{Name: "Keep API", Template: `curl {{quote (print .KeepURL "/alerts/" .Fingerprint)}}`},
+			{Name: "kubectl", Template: `kubectl -n {{quote .Labels.namespace}} logs {{quote .Labels.pod}}`},
+			{Name: "Runbook", Template: `{{.Labels.runbook}}`},
+		},
+	}}
+	builder, err := NewBuilder(fileConfig, "https://keep-api.example.com/")
+	require.NoError(t, err)
+
+	a := newCommandsTestAlert(map[string]string{"namespace": "prod", "pod": "api 1"})
+	attachment := builder.BuildFiringAttachment(a, "http://callback.url", "http://keep.ui")
+
+	require.Len(t, attachment.Actions, 3)
+	button := attachment.Actions[2]
+	assert.Equal(t, post.ActionCommands, button.ID)
+	assert.Equal(t, "Commands", button.Name)
+	assert.Equal(t, "http://callback.url", button.Integration.URL)
+	assert.Equal(t, post.ActionCommands, button.Integration.Context[post.ContextKeyAction])
+	assert.Equal(t, "fp-1", button.Integration.Context[post.ContextKeyFingerprint])
+	assert.Equal(t, "Commands for **Pod crashlooping** (`fp-1`):\n\n"+
+		"**Keep API**\n```\ncurl https://keep-api.example.com/alerts/fp-1\n```\n"+
+		"**kubectl**\n```\nkubectl -n prod logs 'api 1'\n```",
+		button.Integration.Context[post.ContextKeyCommands], "commands with missing labels are left out")
+
+	ack := builder.BuildAcknowledgedAttachment(a, "http://callback.url", "http://keep.ui", "alice")
+	assert.Equal(t, post.ActionCommands, ack.Actions[len(ack.Actions)-1].ID)
+}
+
+func TestBuildFiringAttachmentCommandsButtonOmitted(t *testing.T) {
+	fileConfig := &config.FileConfig{}
+	a := newCommandsTestAlert(map[string]string{})
+
+	builder := newTestBuilder(t, fileConfig)
+	assert.Len(t, builder.BuildFiringAttachment(a, "http://callback.url", "http://keep.ui").Actions, 2, "disabled by default")
+
+	builder = newTestBuilder(t, fileConfig, attachment.WithCopyCommands([]attachment.CopyCommand{{Name: "kubectl", Template: "kubectl -n {{.Labels.namespace}} get pods"}}, ""))
+	assert.Len(t, builder.BuildFiringAttachment(a, "http://callback.url", "http://keep.ui").Actions, 2, "no command applies")
+}
+
+func TestNewBuilderInvalidCopyCommand(t *testing.T) {
+	_, err := NewBuilder(&config.FileConfig{CopyCommands: config.CopyCommandsConfig{
+		Enabled:  true,
+		Commands: []config.CopyCommandConfig{{Name: "broken", Template: "{{.Labels"}},
+	}}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `parse copy command "broken"`)
+}
+
+func TestBuildAttachmentFingerprintField(t *testing.T) {
+	fileConfig := &config.FileConfig{
+		Message: config.MessageConfig{Fields: config.FieldsConfig{ShowSeverity: boolPtr(false), ShowFingerprint: true}},
+	}
+	builder := newTestBuilder(t, fileConfig)
+	a := newCommandsTestAlert(map[string]string{})
+
+	for _, attachment := range []post.Attachment{
+		builder.BuildFiringAttachment(a, "http://callback.url", "http://keep.ui"),
+		builder.BuildResolvedAttachment(a, "http://keep.ui", ""),
+		builder.BuildSuppressedAttachment(a, "http://keep.ui"),
+	} {
+		require.Len(t, attachment.Fields, 1)
+		assert.Equal(t, post.AttachmentField{
+			Title: "Fingerprint",
+			Value: "`fp-1`\nDetails: `/keep info fp-1`",
+			Short: true,
+		}, attachment.Fields[0])
+	}
+}
@@ -0,0 +1,12 @@
+package messagebuilder
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// Compile-time contracts: the builder NewBuilder returns is wired into use
+// cases as port.MessageBuilder and, for incidents, port.IncidentMessageBuilder.
+var (
+	_ port.MessageBuilder         = (*Builder)(nil)
+	_ port.IncidentMessageBuilder = (*Builder)(nil)
+)
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+)
+
+const auditTable = "kmbridge_audit_events"
+
+// AuditRepository keeps every event as a row in kmbridge_audit_events, so the
+// history can be queried across alerts. Rows older than retention are
+// removed as new events arrive. An alert's history returns its latest
+// maxEvents events, like the Valkey repository.
+type AuditRepository struct {
+	pool      *pgxpool.Pool
+	maxEvents int
+	retention time.Duration
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+func NewAuditRepository(pool *pgxpool.Pool, maxEvents int, retention time.Duration, logger *slog.Logger) *AuditRepository {
+	return &AuditRepository{
+		pool:      pool,
+		maxEvents: maxEvents,
+		retention: retention,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *audit.Event) error {
+	start := time.Now()
+	_, err := r.pool.Exec(ctx, `INSERT INTO kmbridge_audit_events (fingerprint, kind, actor, detail, at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.Fingerprint().Value(), e.Kind(), e.Actor(), e.Detail(), e.At())
+	observe(r.logger, "insert", auditTable, start, err)
+	if err != nil {
+		return fmt.Errorf("postgres insert audit event: %w", err)
+	}
+
+	start = time.Now()
+	_, err = r.pool.Exec(ctx, `DELETE FROM kmbridge_audit_events WHERE at < $1`, r.now().Add(-r.retention))
+	observe(r.logger, "prune", auditTable, start, err)
+	if err != nil {
+		// The event is stored; old rows go with the next append.
+		r.logger.Warn("Failed to prune audit events", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+func (r *AuditRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) ([]*audit.Event, error) {
+	start := time.Now()
+	rows, err := r.pool.Query(ctx, `SELECT kind, actor, detail, at FROM (
+			SELECT id, kind, actor, detail, at FROM kmbridge_audit_events
+			WHERE fingerprint = $1 AND at >= $2
+			ORDER BY id DESC LIMIT $3
+		) latest ORDER BY id`,
+		fingerprint.Value(), r.now().Add(-r.retention), r.maxEvents)
+	if err != nil {
+		observe(r.logger, "select", auditTable, start, err)
+		return nil, fmt.Errorf("postgres select audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*audit.Event, 0)
+	for rows.Next() {
+		var kind, actor, detail string
+		var at time.Time
+		if err := rows.Scan(&kind, &actor, &detail, &at); err != nil {
+			observe(r.logger, "select", auditTable, start, err)
+			return nil, fmt.Errorf("postgres scan audit event: %w", err)
+		}
+		events = append(events, audit.NewEvent(fingerprint, kind, actor, detail, at))
+	}
+	err = rows.Err()
+	observe(r.logger, "select", auditTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select audit events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *AuditRepository) FindBetween(ctx context.Context, from, to time.Time) ([]*audit.Event, error) {
+	start := time.Now()
+	rows, err := r.pool.Query(ctx, `SELECT fingerprint, kind, actor, detail, at FROM kmbridge_audit_events
+		WHERE at >= $1 AND at < $2
+		ORDER BY at, id`,
+		from, to)
+	if err != nil {
+		observe(r.logger, "select", auditTable, start, err)
+		return nil, fmt.Errorf("postgres select audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*audit.Event, 0)
+	for rows.Next() {
+		var fingerprint, kind, actor, detail string
+		var at time.Time
+		if err := rows.Scan(&fingerprint, &kind, &actor, &detail, &at); err != nil {
+			observe(r.logger, "select", auditTable, start, err)
+			return nil, fmt.Errorf("postgres scan audit event: %w", err)
+		}
+		events = append(events, audit.NewEvent(alert.RestoreFingerprint(fingerprint), kind, actor, detail, at))
+	}
+	err = rows.Err()
+	observe(r.logger, "select", auditTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select audit events: %w", err)
+	}
+	return events, nil
+}
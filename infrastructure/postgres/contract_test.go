@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Compile-time contracts: the repositories are wired into use cases through
+// the domain interfaces.
+var (
+	_ post.Repository   = (*PostRepository)(nil)
+	_ post.PostIDFinder = (*PostRepository)(nil)
+	_ audit.Repository  = (*AuditRepository)(nil)
+	_ group.Repository  = (*GroupRepository)(nil)
+)
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+)
+
+const groupsTable = "kmbridge_groups"
+
+// GroupRepository keeps alert groups in kmbridge_groups. Like posts, a group
+// not saved for a week is forgotten.
+type GroupRepository struct {
+	pool   *pgxpool.Pool
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func NewGroupRepository(pool *pgxpool.Pool, logger *slog.Logger) *GroupRepository {
+	return &GroupRepository{
+		pool:   pool,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+func (r *GroupRepository) Save(ctx context.Context, g *group.Group) error {
+	start := time.Now()
+
+	members := make(map[string]string, g.ActiveCount())
+	for fp, sev := range g.Members() {
+		members[fp] = sev.String()
+	}
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO kmbridge_groups
+			(id, group_key, group_value, channel_id, root_post_id, members, total, created_at, last_updated, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			root_post_id = EXCLUDED.root_post_id,
+			members = EXCLUDED.members,
+			total = EXCLUDED.total,
+			created_at = EXCLUDED.created_at,
+			last_updated = EXCLUDED.last_updated,
+			expires_at = EXCLUDED.expires_at`,
+		g.ID(), g.Key(), g.Value(), g.ChannelID(), g.RootPostID(), members, g.Total(),
+		g.CreatedAt(), g.LastUpdated(), r.now().Add(postTTL))
+	observe(r.logger, "upsert", groupsTable, start, err)
+	if err != nil {
+		return fmt.Errorf("postgres upsert group: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupRepository) FindByID(ctx context.Context, id string) (*group.Group, error) {
+	start := time.Now()
+
+	var (
+		key, value, channelID, rootPostID string
+		members                           map[string]string
+		total                             int
+		createdAt, lastUpdated            time.Time
+	)
+	err := r.pool.QueryRow(ctx, `SELECT group_key, group_value, channel_id, root_post_id, members, total, created_at, last_updated
+		FROM kmbridge_groups WHERE id = $1 AND expires_at > $2`,
+		id, r.now()).Scan(&key, &value, &channelID, &rootPostID, &members, &total, &createdAt, &lastUpdated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		observe(r.logger, "select", groupsTable, start, nil)
+		return nil, group.ErrNotFound
+	}
+	observe(r.logger, "select", groupsTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select group: %w", err)
+	}
+
+	severities := make(map[string]alert.Severity, len(members))
+	for fp, sev := range members {
+		severities[fp] = alert.RestoreSeverity(sev)
+	}
+	return group.RestoreGroup(key, value, channelID, rootPostID, severities, total, createdAt, lastUpdated), nil
+}
+
+func (r *GroupRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	_, err := r.pool.Exec(ctx, `DELETE FROM kmbridge_groups WHERE id = $1`, id)
+	observe(r.logger, "delete", groupsTable, start, err)
+	if err != nil {
+		return fmt.Errorf("postgres delete group: %w", err)
+	}
+	return nil
+}
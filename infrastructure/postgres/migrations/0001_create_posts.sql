@@ -0,0 +1,18 @@
+CREATE TABLE kmbridge_posts (
+    fingerprint         text PRIMARY KEY,
+    post_id             text NOT NULL,
+    channel_id          text NOT NULL,
+    alert_name          text NOT NULL,
+    severity            text NOT NULL,
+    firing_start_time   timestamptz NOT NULL,
+    created_at          timestamptz NOT NULL,
+    last_updated        timestamptz NOT NULL,
+    last_known_assignee text NOT NULL DEFAULT '',
+    snoozed_until       timestamptz,
+    escalation_level    integer NOT NULL DEFAULT 0,
+    escalated_at        timestamptz,
+    labels              jsonb NOT NULL DEFAULT '{}',
+    expires_at          timestamptz NOT NULL
+);
+
+CREATE INDEX kmbridge_posts_expires_at_idx ON kmbridge_posts (expires_at);
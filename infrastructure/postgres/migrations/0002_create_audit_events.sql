@@ -0,0 +1,11 @@
+CREATE TABLE kmbridge_audit_events (
+    id          bigserial PRIMARY KEY,
+    fingerprint text NOT NULL,
+    kind        text NOT NULL,
+    actor       text NOT NULL DEFAULT '',
+    detail      text NOT NULL DEFAULT '',
+    at          timestamptz NOT NULL
+);
+
+CREATE INDEX kmbridge_audit_events_fingerprint_idx ON kmbridge_audit_events (fingerprint, id);
+CREATE INDEX kmbridge_audit_events_at_idx ON kmbridge_audit_events (at);
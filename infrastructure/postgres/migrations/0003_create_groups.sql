@@ -0,0 +1,14 @@
+CREATE TABLE kmbridge_groups (
+    id           text PRIMARY KEY,
+    group_key    text NOT NULL,
+    group_value  text NOT NULL,
+    channel_id   text NOT NULL,
+    root_post_id text NOT NULL,
+    members      jsonb NOT NULL DEFAULT '{}',
+    total        integer NOT NULL,
+    created_at   timestamptz NOT NULL,
+    last_updated timestamptz NOT NULL,
+    expires_at   timestamptz NOT NULL
+);
+
+CREATE INDEX kmbridge_groups_expires_at_idx ON kmbridge_groups (expires_at);
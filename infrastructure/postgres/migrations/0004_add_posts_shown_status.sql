@@ -0,0 +1 @@
+ALTER TABLE kmbridge_posts ADD COLUMN shown_status text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE kmbridge_posts ADD COLUMN acknowledged_at timestamptz, ADD COLUMN resolved_at timestamptz;
@@ -0,0 +1 @@
+ALTER TABLE kmbridge_posts ADD COLUMN ack_until timestamptz, ADD COLUMN acked_by text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE kmbridge_posts ADD COLUMN refires integer NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+CREATE INDEX kmbridge_posts_post_id_idx ON kmbridge_posts (post_id);
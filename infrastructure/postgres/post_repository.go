@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+const (
+	postsTable = "kmbridge_posts"
+	// postTTL matches the Valkey repository: posts not updated for a week are
+	// forgotten.
+	postTTL = 7 * 24 * time.Hour
+)
+
+const postColumns = `post_id, channel_id, fingerprint, alert_name, severity, firing_start_time,
+	created_at, last_updated, last_known_assignee, snoozed_until, escalation_level, escalated_at, labels, shown_status,
+	acknowledged_at, resolved_at, ack_until, acked_by, refires`
+
+// PostRepository keeps one row per tracked alert in kmbridge_posts. Expired
+// rows are hidden from reads and removed by FindAllActive.
+type PostRepository struct {
+	pool   *pgxpool.Pool
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func NewPostRepository(pool *pgxpool.Pool, logger *slog.Logger) *PostRepository {
+	return &PostRepository{
+		pool:   pool,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+func (r *PostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
+	start := time.Now()
+	now := r.now()
+
+	// A snoozed post must outlive its snooze so the unsnooze job can still
+	// restore it.
+	expiresAt := now.Add(postTTL)
+	if p.SnoozedUntil().After(now) {
+		expiresAt = p.SnoozedUntil().Add(postTTL)
+	}
+	labels := p.Labels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	_, err := r.pool.Exec(ctx, `INSERT INTO kmbridge_posts (`+postColumns+`, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (fingerprint) DO UPDATE SET
+			post_id = EXCLUDED.post_id,
+			channel_id = EXCLUDED.channel_id,
+			alert_name = EXCLUDED.alert_name,
+			severity = EXCLUDED.severity,
+			firing_start_time = EXCLUDED.firing_start_time,
+			created_at = EXCLUDED.created_at,
+			last_updated = EXCLUDED.last_updated,
+			last_known_assignee = EXCLUDED.last_known_assignee,
+			snoozed_until = EXCLUDED.snoozed_until,
+			escalation_level = EXCLUDED.escalation_level,
+			escalated_at = EXCLUDED.escalated_at,
+			labels = EXCLUDED.labels,
+			shown_status = EXCLUDED.shown_status,
+			acknowledged_at = EXCLUDED.acknowledged_at,
+			resolved_at = EXCLUDED.resolved_at,
+			ack_until = EXCLUDED.ack_until,
+			acked_by = EXCLUDED.acked_by,
+			refires = EXCLUDED.refires,
+			expires_at = EXCLUDED.expires_at`,
+		p.PostID(),
+		p.ChannelID(),
+		fingerprint.Value(),
+		p.AlertName(),
+		p.Severity().String(),
+		p.FiringStartTime(),
+		p.CreatedAt(),
+		p.LastUpdated(),
+		p.LastKnownAssignee(),
+		nullTime(p.SnoozedUntil()),
+		p.EscalationLevel(),
+		nullTime(p.EscalatedAt()),
+		labels,
+		p.ShownStatus(),
+		nullTime(p.AcknowledgedAt()),
+		nullTime(p.ResolvedAt()),
+		nullTime(p.AckUntil()),
+		p.AckedBy(),
+		p.Refires(),
+		expiresAt,
+	)
+	observe(r.logger, "upsert", postsTable, start, err)
+	if err != nil {
+		return fmt.Errorf("postgres upsert post: %w", err)
+	}
+	return nil
+}
+
+func (r *PostRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
+	start := time.Now()
+
+	row := r.pool.QueryRow(ctx, `SELECT `+postColumns+` FROM kmbridge_posts
+		WHERE fingerprint = $1 AND expires_at > $2`,
+		fingerprint.Value(), r.now())
+	p, err := scanPost(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		observe(r.logger, "select", postsTable, start, nil)
+		return nil, post.ErrNotFound
+	}
+	observe(r.logger, "select", postsTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select post: %w", err)
+	}
+	return p, nil
+}
+
+// FindByPostID returns the post with postID that has not expired.
+func (r *PostRepository) FindByPostID(ctx context.Context, postID string) (*post.Post, error) {
+	start := time.Now()
+
+	row := r.pool.QueryRow(ctx, `SELECT `+postColumns+` FROM kmbridge_posts
+		WHERE post_id = $1 AND expires_at > $2`,
+		postID, r.now())
+	p, err := scanPost(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		observe(r.logger, "select", postsTable, start, nil)
+		return nil, post.ErrNotFound
+	}
+	observe(r.logger, "select", postsTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select post: %w", err)
+	}
+	return p, nil
+}
+
+// FindAllActive returns every post that has not expired, ordered by
+// fingerprint, and removes the expired ones.
+func (r *PostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error) {
+	now := r.now()
+
+	start := time.Now()
+	_, err := r.pool.Exec(ctx, `DELETE FROM kmbridge_posts WHERE expires_at <= $1`, now)
+	observe(r.logger, "prune", postsTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres prune posts: %w", err)
+	}
+
+	start = time.Now()
+	rows, err := r.pool.Query(ctx, `SELECT `+postColumns+` FROM kmbridge_posts
+		WHERE expires_at > $1 ORDER BY fingerprint`, now)
+	if err != nil {
+		observe(r.logger, "scan", postsTable, start, err)
+		return nil, fmt.Errorf("postgres select posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*post.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			observe(r.logger, "scan", postsTable, start, err)
+			return nil, fmt.Errorf("postgres scan post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	err = rows.Err()
+	observe(r.logger, "scan", postsTable, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("postgres select posts: %w", err)
+	}
+	return posts, nil
+}
+
+func (r *PostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	start := time.Now()
+	_, err := r.pool.Exec(ctx, `DELETE FROM kmbridge_posts WHERE fingerprint = $1`, fingerprint.Value())
+	observe(r.logger, "delete", postsTable, start, err)
+	if err != nil {
+		return fmt.Errorf("postgres delete post: %w", err)
+	}
+	return nil
+}
+
+// Ping reports whether the database answers; the readiness probe uses it.
+func (r *PostRepository) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+func scanPost(row pgx.Row) (*post.Post, error) {
+	var (
+		postID, channelID, fingerprint, alertName, severity, assignee, shownStatus, ackedBy string
+		firingStartTime, createdAt, lastUpdated                                             time.Time
+		snoozedUntil, escalatedAt, acknowledgedAt, resolvedAt, ackUntil                     *time.Time
+		escalationLevel, refires                                                            int
+		labels                                                                              map[string]string
+	)
+	if err := row.Scan(
+		&postID, &channelID, &fingerprint, &alertName, &severity, &firingStartTime,
+		&createdAt, &lastUpdated, &assignee, &snoozedUntil, &escalationLevel, &escalatedAt, &labels, &shownStatus,
+		&acknowledgedAt, &resolvedAt, &ackUntil, &ackedBy, &refires,
+	); err != nil {
+		return nil, err
+	}
+
+	p := post.RestorePost(
+		postID,
+		channelID,
+		alert.RestoreFingerprint(fingerprint),
+		alertName,
+		alert.RestoreSeverity(severity),
+		firingStartTime,
+		createdAt,
+		lastUpdated,
+		assignee,
+	)
+	p.ShowStatus(shownStatus)
+	if snoozedUntil != nil {
+		p.Snooze(*snoozedUntil)
+	}
+	if escalationLevel > 0 {
+		p.RestoreEscalation(escalationLevel, timeOrZero(escalatedAt))
+	}
+	p.RestoreLifecycle(timeOrZero(acknowledgedAt), timeOrZero(resolvedAt))
+	if ackUntil != nil {
+		p.AcknowledgeUntil(*ackUntil, ackedBy)
+	}
+	p.RestoreRefires(refires)
+	if len(labels) > 0 {
+		p.SetLabels(labels)
+	}
+	return p, nil
+}
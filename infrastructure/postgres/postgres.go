@@ -0,0 +1,66 @@
+// Package postgres keeps posts, alert audit history and alert groups in
+// PostgreSQL, for installs that already run a highly available Postgres and
+// want to query the bridge's history with SQL.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+// Connect opens a connection pool to databaseURL, checks that the server
+// answers and applies the migrations the database has not seen yet.
+func Connect(ctx context.Context, databaseURL string, logger *slog.Logger) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	if err := Migrate(ctx, pool, logger); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// observe logs and counts one query against table.
+func observe(log *slog.Logger, operation, table string, start time.Time, err error) {
+	duration := time.Since(start)
+	if err != nil {
+		log.Error("Postgres query failed",
+			logger.PostgresFieldsWithError(operation, table, duration.Milliseconds(), err.Error()),
+		)
+		metrics.GetOrCreateCounter(`postgres_queries_total{operation="` + operation + `",status="error"}`).Inc()
+		return
+	}
+	log.Debug("Postgres query completed",
+		logger.PostgresFields(operation, table, duration.Milliseconds()),
+	)
+	metrics.GetOrCreateCounter(`postgres_queries_total{operation="` + operation + `",status="ok"}`).Inc()
+	metrics.GetOrCreateHistogram(`postgres_query_duration_seconds{operation="` + operation + `"}`).Update(duration.Seconds())
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// setupTestPostgres connects to the database in KMBRIDGE_TEST_DATABASE_URL
+// and empties the bridge's tables. Tests are skipped when it is not set.
+func setupTestPostgres(t *testing.T) (*pgxpool.Pool, *slog.Logger) {
+	t.Helper()
+	url := os.Getenv("KMBRIDGE_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("KMBRIDGE_TEST_DATABASE_URL is not set")
+	}
+
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	pool, err := Connect(context.Background(), url, log)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	_, err = pool.Exec(context.Background(), `TRUNCATE kmbridge_posts, kmbridge_audit_events, kmbridge_groups`)
+	require.NoError(t, err)
+	return pool, log
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	pool, log := setupTestPostgres(t)
+	ctx := context.Background()
+
+	require.NoError(t, Migrate(ctx, pool, log))
+
+	var version int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT MAX(version) FROM kmbridge_schema_migrations`).Scan(&version))
+	migrations, err := loadMigrations()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), version)
+}
+
+func TestPostRepository(t *testing.T) {
+	pool, log := setupTestPostgres(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewPostRepository(pool, log)
+	repo.now = func() time.Time { return now }
+
+	for _, fp := range []string{"fp-2", "fp-1"} {
+		p := post.NewPost("post-"+fp, "channel-1", alert.RestoreFingerprint(fp), "DiskFull", alert.RestoreSeverity("critical"), now, now)
+		p.SetLabels(map[string]string{"host": "db-1"})
+		p.SetLastKnownAssignee("alice")
+		p.ShowStatus("acknowledged")
+		p.Acknowledge(now.Add(time.Minute))
+		p.AcknowledgeUntil(now.Add(2*time.Hour), "alice")
+		p.Refire()
+		require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint(fp), p))
+	}
+	snoozed := post.NewPost("post-fp-3", "channel-1", alert.RestoreFingerprint("fp-3"), "DiskFull", alert.RestoreSeverity("warning"), now, now)
+	snoozed.Snooze(now.Add(48 * time.Hour))
+	snoozed.RestoreEscalation(1, now)
+	require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint("fp-3"), snoozed))
+
+	found, err := repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "post-fp-1", found.PostID())
+	assert.Equal(t, "critical", found.Severity().String())
+	assert.Equal(t, "alice", found.LastKnownAssignee())
+	assert.Equal(t, "acknowledged", found.ShownStatus())
+	assert.True(t, now.Add(time.Minute).Equal(found.AcknowledgedAt()))
+	assert.True(t, now.Add(2*time.Hour).Equal(found.AckUntil()))
+	assert.Equal(t, "alice", found.AckedBy())
+	assert.Equal(t, 2, found.FireCount())
+	assert.Equal(t, map[string]string{"host": "db-1"}, found.Labels())
+	assert.True(t, found.SnoozedUntil().IsZero())
+
+	found, err = repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-3"))
+	require.NoError(t, err)
+	assert.True(t, found.SnoozedUntil().Equal(now.Add(48*time.Hour)))
+	assert.Equal(t, 1, found.EscalationLevel())
+
+	_, err = repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-missing"))
+	assert.ErrorIs(t, err, post.ErrNotFound)
+
+	found, err = repo.FindByPostID(ctx, "post-fp-3")
+	require.NoError(t, err)
+	assert.Equal(t, "fp-3", found.Fingerprint().Value())
+	_, err = repo.FindByPostID(ctx, "post-missing")
+	assert.ErrorIs(t, err, post.ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, alert.RestoreFingerprint("fp-2")))
+	posts, err := repo.FindAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "fp-1", posts[0].Fingerprint().Value())
+	assert.Equal(t, "fp-3", posts[1].Fingerprint().Value())
+
+	now = now.Add(postTTL + time.Hour)
+	posts, err = repo.FindAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, posts, 1, "a snoozed post outlives its snooze")
+	assert.Equal(t, "fp-3", posts[0].Fingerprint().Value())
+	assert.NoError(t, repo.Ping(ctx))
+}
+
+func TestAuditRepository(t *testing.T) {
+	pool, log := setupTestPostgres(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewAuditRepository(pool, 2, 24*time.Hour, log)
+	repo.now = func() time.Time { return now }
+	fp := alert.RestoreFingerprint("fp-1")
+
+	events, err := repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	for i, kind := range []string{audit.KindReceived, audit.KindPosted, audit.KindAcknowledged} {
+		require.NoError(t, repo.Append(ctx, audit.NewEvent(fp, kind, "alice", "", now.Add(time.Duration(i)*time.Minute))))
+	}
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-2"), audit.KindReceived, "", "", now)))
+
+	events, err = repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	require.Len(t, events, 2, "only the latest max events are returned")
+	assert.Equal(t, audit.KindPosted, events[0].Kind())
+	assert.Equal(t, audit.KindAcknowledged, events[1].Kind())
+	assert.Equal(t, "alice", events[1].Actor())
+
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT count(*) FROM kmbridge_audit_events`).Scan(&count))
+	assert.Equal(t, 4, count, "older events stay queryable")
+
+	events, err = repo.FindBetween(ctx, now, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, events, 3, "events of every alert in the range")
+	assert.Equal(t, audit.KindReceived, events[0].Kind())
+	assert.Equal(t, audit.KindPosted, events[2].Kind())
+	assert.Equal(t, "fp-1", events[2].Fingerprint().Value())
+
+	now = now.Add(48 * time.Hour)
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(fp, audit.KindResolved, "", "", now)))
+	events, err = repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, audit.KindResolved, events[0].Kind())
+	require.NoError(t, pool.QueryRow(ctx, `SELECT count(*) FROM kmbridge_audit_events`).Scan(&count))
+	assert.Equal(t, 1, count, "events past retention are pruned")
+}
+
+func TestGroupRepository(t *testing.T) {
+	pool, log := setupTestPostgres(t)
+	ctx := context.Background()
+	repo := NewGroupRepository(pool, log)
+
+	g := group.NewGroup("service", "api", "channel-1", "root-1", time.Now())
+	g.Add("fp-1", alert.RestoreSeverity("critical"), time.Now())
+	require.NoError(t, repo.Save(ctx, g))
+
+	found, err := repo.FindByID(ctx, g.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "service", found.Key())
+	assert.Equal(t, "api", found.Value())
+	assert.Equal(t, "root-1", found.RootPostID())
+	assert.Equal(t, map[string]alert.Severity{"fp-1": alert.RestoreSeverity("critical")}, found.Members())
+
+	require.NoError(t, repo.Delete(ctx, g.ID()))
+	_, err = repo.FindByID(ctx, g.ID())
+	assert.ErrorIs(t, err, group.ErrNotFound)
+}
@@ -0,0 +1,164 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Block Kit limits the bridge runs into with alert cards.
+const (
+	maxTextLength     = 3000
+	maxFieldsPerBlock = 10
+	maxActionValue    = 2000
+)
+
+// attachment is a legacy attachment holding Block Kit blocks, so the card
+// keeps the colored bar of its severity.
+type attachment struct {
+	Color    string  `json:"color,omitempty"`
+	Fallback string  `json:"fallback"`
+	Blocks   []block `json:"blocks"`
+}
+
+type block map[string]any
+
+func textObject(kind, text string) map[string]any {
+	return map[string]any{"type": kind, "text": text}
+}
+
+// toAttachment renders an alert card as blocks: the title, the text, the
+// fields, the buttons and the footer. Message menus, such as assign and the
+// workflow menu, have no context to hand back in Slack and are left out.
+func toAttachment(a post.Attachment) attachment {
+	var blocks []block
+
+	title := "*" + escape(a.Title) + "*"
+	if a.TitleLink != "" {
+		title = "*<" + a.TitleLink + "|" + escape(a.Title) + ">*"
+	}
+	blocks = append(blocks, block{"type": "section", "text": textObject("mrkdwn", truncate(title))})
+
+	if a.Text != "" {
+		blocks = append(blocks, block{"type": "section", "text": textObject("mrkdwn", truncate(mrkdwn(a.Text)))})
+	}
+
+	// Short fields are laid out two per row, as in Mattermost; long fields
+	// get a section of their own.
+	var short []map[string]any
+	flush := func() {
+		for len(short) > 0 {
+			n := min(len(short), maxFieldsPerBlock)
+			blocks = append(blocks, block{"type": "section", "fields": short[:n]})
+			short = short[n:]
+		}
+	}
+	for _, f := range a.Fields {
+		text := truncate("*" + escape(f.Title) + "*\n" + mrkdwn(f.Value))
+		if f.Short {
+			short = append(short, textObject("mrkdwn", text))
+			continue
+		}
+		flush()
+		blocks = append(blocks, block{"type": "section", "text": textObject("mrkdwn", text)})
+	}
+	flush()
+
+	var elements []map[string]any
+	for i, b := range a.Actions {
+		if b.Type != "" && b.Type != post.ButtonTypeButton {
+			continue
+		}
+		value, ok := actionValue(b.Integration.Context)
+		if !ok {
+			continue
+		}
+		element := map[string]any{
+			"type":      "button",
+			"action_id": fmt.Sprintf("%s-%d", b.ID, i),
+			"text":      textObject("plain_text", b.Name),
+			"value":     value,
+		}
+		switch b.Style {
+		case post.ButtonStyleSuccess:
+			element["style"] = "primary"
+		case post.ButtonStyleDanger:
+			element["style"] = "danger"
+		}
+		elements = append(elements, element)
+	}
+	if len(elements) > 0 {
+		blocks = append(blocks, block{"type": "actions", "elements": elements})
+	}
+
+	if a.Footer != "" {
+		var footer []map[string]any
+		if a.FooterIcon != "" {
+			footer = append(footer, map[string]any{"type": "image", "image_url": a.FooterIcon, "alt_text": "icon"})
+		}
+		footer = append(footer, textObject("mrkdwn", escape(a.Footer)))
+		blocks = append(blocks, block{"type": "context", "elements": footer})
+	}
+
+	return attachment{Color: a.Color, Fallback: a.Title, Blocks: blocks}
+}
+
+// actionValue returns the button context as the value Slack hands back on a
+// click. The rendered post it carries for Mattermost does not fit, and
+// buttons whose context still does not fit are left out.
+func actionValue(context map[string]string) (string, bool) {
+	trimmed := make(map[string]string, len(context))
+	for k, v := range context {
+		if k != post.ContextKeyAttachmentJSON {
+			trimmed[k] = v
+		}
+	}
+	value, err := json.Marshal(trimmed)
+	if err != nil || len(value) > maxActionValue {
+		return "", false
+	}
+	return string(value), true
+}
+
+var (
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold   = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownStrike = regexp.MustCompile(`~~(.+?)~~`)
+	mentionPattern = regexp.MustCompile(`(^|\s)@(here|channel|all)\b`)
+)
+
+// mrkdwn converts the Markdown of alert cards to Slack's mrkdwn.
+func mrkdwn(markdown string) string {
+	text := escape(markdown)
+	text = markdownLink.ReplaceAllString(text, "<$2|$1>")
+	text = markdownBold.ReplaceAllString(text, "*$1*")
+	text = markdownStrike.ReplaceAllString(text, "~$1~")
+	return text
+}
+
+// mentions converts the text above an alert card, turning Mattermost's
+// channel-wide mentions into Slack's.
+func mentions(message string) string {
+	return mentionPattern.ReplaceAllStringFunc(mrkdwn(message), func(m string) string {
+		prefix, name, _ := strings.Cut(m, "@")
+		if name == "all" {
+			name = "channel"
+		}
+		return prefix + "<!" + name + ">"
+	})
+}
+
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func truncate(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxTextLength {
+		return text
+	}
+	return string(runes[:maxTextLength-1]) + "…"
+}
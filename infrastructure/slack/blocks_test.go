@@ -0,0 +1,59 @@
+package slack
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func TestToAttachment(t *testing.T) {
+	a := post.Attachment{
+		Color:     "#FF0000",
+		Title:     "CPU <high>",
+		TitleLink: "https://keep.example.com/alerts/fp-1",
+		Text:      "**Node** is [overloaded](https://grafana.example.com)",
+		Fields: []post.AttachmentField{
+			{Title: "Severity", Value: "critical", Short: true},
+			{Title: "Host", Value: "node-1", Short: true},
+			{Title: "Description", Value: "long text"},
+		},
+		Actions: []post.Button{
+			{ID: "ack", Name: "Acknowledge", Style: post.ButtonStyleDefault, Integration: post.ButtonIntegration{Context: map[string]string{
+				post.ContextKeyAction:         post.ActionAcknowledge,
+				post.ContextKeyFingerprint:    "fp-1",
+				post.ContextKeyAttachmentJSON: "{...}",
+			}}},
+			{ID: "resolve", Name: "Resolve", Style: post.ButtonStyleSuccess, Integration: post.ButtonIntegration{Context: map[string]string{post.ContextKeyAction: post.ActionResolve}}},
+			{ID: "assign", Name: "Assign", Type: post.ButtonTypeSelect},
+			{ID: "big", Name: "Commands", Integration: post.ButtonIntegration{Context: map[string]string{post.ContextKeyCommands: strings.Repeat("x", 3000)}}},
+		},
+		Footer: "Keep",
+	}
+
+	got := toAttachment(a)
+	assert.Equal(t, "#FF0000", got.Color)
+	require.Len(t, got.Blocks, 6)
+	assert.Equal(t, "*<https://keep.example.com/alerts/fp-1|CPU &lt;high&gt;>*", got.Blocks[0]["text"].(map[string]any)["text"])
+	assert.Equal(t, "*Node* is <https://grafana.example.com|overloaded>", got.Blocks[1]["text"].(map[string]any)["text"])
+	assert.Len(t, got.Blocks[2]["fields"], 2, "short fields share a section")
+	assert.Equal(t, "*Description*\nlong text", got.Blocks[3]["text"].(map[string]any)["text"])
+
+	elements := got.Blocks[4]["elements"].([]map[string]any)
+	require.Len(t, elements, 2, "menus and buttons whose context does not fit are left out")
+	var value map[string]string
+	require.NoError(t, json.Unmarshal([]byte(elements[0]["value"].(string)), &value))
+	assert.Equal(t, map[string]string{post.ContextKeyAction: post.ActionAcknowledge, post.ContextKeyFingerprint: "fp-1"}, value)
+	assert.NotContains(t, elements[0], "style")
+	assert.Equal(t, "primary", elements[1]["style"])
+	assert.Equal(t, "context", got.Blocks[5]["type"])
+}
+
+func TestMentions(t *testing.T) {
+	assert.Equal(t, "<!here> <!channel> <!channel> @jane", mentions("@here @channel @all @jane"))
+	assert.Equal(t, "a ~b~ &amp; c", mrkdwn("a ~~b~~ & c"))
+}
@@ -0,0 +1,365 @@
+// Package slack posts alert cards to Slack with the same calls the bridge
+// makes to Mattermost, so a routing rule can target a Slack workspace.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/ratelimit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+// DefaultURL is the Slack Web API.
+const DefaultURL = "https://slack.com/api"
+
+// ErrUnsupported is returned for Mattermost features Slack has no
+// counterpart for, such as teams, groups and interactive dialogs.
+var ErrUnsupported = errors.New("not supported on slack")
+
+var slackAPIDur = func(method string) *metrics.Histogram {
+	return metrics.GetOrCreateHistogram(`slack_api_duration_seconds{method="` + method + `"}`)
+}
+
+var slackAPICalls = func(method, status string) *metrics.Counter {
+	return metrics.GetOrCreateCounter(`slack_api_calls_total{method="` + method + `",status="` + status + `"}`)
+}
+
+// Client calls the Slack Web API with a bot token. Post IDs it returns are
+// "{channel}/{ts}", since Slack identifies a message by its channel and
+// timestamp.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	retry      *retry.Transport
+	rateLimit  *ratelimit.Transport
+	logger     *slog.Logger
+
+	botUsername string
+	botIconURL  string
+}
+
+func NewClient(baseURL, token string, logger *slog.Logger) *Client {
+	limiter := ratelimit.NewTransport(&http.Transport{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}, "slack")
+	transport := retry.NewTransport(limiter, "slack", retry.Policy{MaxAttempts: 1})
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		retry:     transport,
+		rateLimit: limiter,
+		logger:    logger,
+	}
+}
+
+// SetRetryPolicy retries requests that fail with a network error, 429 or 5xx
+// response. Requests are not retried until it is called.
+func (c *Client) SetRetryPolicy(p retry.Policy) {
+	c.retry.SetPolicy(p)
+}
+
+// SetRateLimit spaces out requests to the Slack API. Requests are not limited
+// until it is called.
+func (c *Client) SetRateLimit(l ratelimit.Limit) {
+	c.rateLimit.SetLimit(l)
+}
+
+// SetBotIdentity shows posts under username and with the avatar at iconURL,
+// unless an attachment sets its own. The app needs the chat:write.customize
+// scope for them to show.
+func (c *Client) SetBotIdentity(username, iconURL string) {
+	c.botUsername = username
+	c.botIconURL = iconURL
+}
+
+// PostID returns the ID the client uses for the message ts of channelID.
+func PostID(channelID, ts string) string {
+	return channelID + "/" + ts
+}
+
+func splitPostID(postID string) (string, string, error) {
+	channelID, ts, ok := strings.Cut(postID, "/")
+	if !ok || channelID == "" || ts == "" {
+		return "", "", fmt.Errorf("invalid slack post id %q", postID)
+	}
+	return channelID, ts, nil
+}
+
+type response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+type postMessageRequest struct {
+	Channel     string       `json:"channel"`
+	TS          string       `json:"ts,omitempty"`
+	ThreadTS    string       `json:"thread_ts,omitempty"`
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	IconURL     string       `json:"icon_url,omitempty"`
+}
+
+type postMessageResponse struct {
+	response
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+func (c *Client) message(channelID string, a post.Attachment) postMessageRequest {
+	req := postMessageRequest{
+		Channel:     channelID,
+		Text:        mentions(a.Message),
+		Attachments: []attachment{toAttachment(a)},
+		Username:    a.Username,
+		IconURL:     a.IconURL,
+	}
+	if req.Username == "" {
+		req.Username = c.botUsername
+	}
+	if req.IconURL == "" {
+		req.IconURL = c.botIconURL
+	}
+	return req
+}
+
+func (c *Client) CreatePost(ctx context.Context, channelID string, a post.Attachment) (string, error) {
+	return c.CreateThreadPost(ctx, channelID, "", a)
+}
+
+// CreateThreadPost posts an attachment as a reply in the thread of rootID,
+// or as a new message when rootID is empty.
+func (c *Client) CreateThreadPost(ctx context.Context, channelID, rootID string, a post.Attachment) (string, error) {
+	req := c.message(channelID, a)
+	if rootID != "" {
+		_, ts, err := splitPostID(rootID)
+		if err != nil {
+			return "", err
+		}
+		req.ThreadTS = ts
+	}
+	var resp postMessageResponse
+	if err := c.call(ctx, "chat.postMessage", req, &resp); err != nil {
+		return "", err
+	}
+	return PostID(resp.Channel, resp.TS), nil
+}
+
+func (c *Client) UpdatePost(ctx context.Context, postID string, a post.Attachment) error {
+	channelID, ts, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	req := c.message(channelID, a)
+	req.TS = ts
+	return c.call(ctx, "chat.update", req, &response{})
+}
+
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	channelID, ts, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "chat.delete", map[string]string{"channel": channelID, "ts": ts}, &response{})
+}
+
+func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
+	_, ts, err := splitPostID(rootID)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "chat.postMessage", postMessageRequest{
+		Channel:  channelID,
+		ThreadTS: ts,
+		Text:     mrkdwn(message),
+		Username: c.botUsername,
+		IconURL:  c.botIconURL,
+	}, &response{})
+}
+
+// GetUser returns the Slack handle of userID.
+func (c *Client) GetUser(ctx context.Context, userID string) (string, error) {
+	var resp struct {
+		response
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	if err := c.callForm(ctx, "users.info", url.Values{"user": {userID}}, &resp); err != nil {
+		return "", err
+	}
+	return resp.User.Name, nil
+}
+
+// GetPost returns port.ErrMattermostPostNotFound when the message was
+// deleted.
+func (c *Client) GetPost(ctx context.Context, postID string) (port.MattermostPost, error) {
+	channelID, ts, err := splitPostID(postID)
+	if err != nil {
+		return port.MattermostPost{}, err
+	}
+	var resp struct {
+		response
+		Messages []struct {
+			TS string `json:"ts"`
+		} `json:"messages"`
+	}
+	params := url.Values{"channel": {channelID}, "latest": {ts}, "oldest": {ts}, "inclusive": {"true"}, "limit": {"1"}}
+	if err := c.callForm(ctx, "conversations.history", params, &resp); err != nil {
+		return port.MattermostPost{}, err
+	}
+	if len(resp.Messages) == 0 || resp.Messages[0].TS != ts {
+		return port.MattermostPost{}, port.ErrMattermostPostNotFound
+	}
+	return port.MattermostPost{ID: postID, ChannelID: channelID}, nil
+}
+
+// GetReactions lists the emoji reactions on a message, one per user.
+func (c *Client) GetReactions(ctx context.Context, postID string) ([]port.Reaction, error) {
+	channelID, ts, err := splitPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		response
+		Message struct {
+			Reactions []struct {
+				Name  string   `json:"name"`
+				Users []string `json:"users"`
+			} `json:"reactions"`
+		} `json:"message"`
+	}
+	if err := c.callForm(ctx, "reactions.get", url.Values{"channel": {channelID}, "timestamp": {ts}, "full": {"true"}}, &resp); err != nil {
+		return nil, err
+	}
+	var reactions []port.Reaction
+	for _, r := range resp.Message.Reactions {
+		for _, userID := range r.Users {
+			reactions = append(reactions, port.Reaction{UserID: userID, EmojiName: r.Name})
+		}
+	}
+	return reactions, nil
+}
+
+func (c *Client) SetChannelPurpose(ctx context.Context, channelID, purpose string) error {
+	return c.call(ctx, "conversations.setPurpose", map[string]string{"channel": channelID, "purpose": purpose}, &response{})
+}
+
+// IsTeamMember always fails: Slack workspaces have no Mattermost teams.
+func (c *Client) IsTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
+	return false, fmt.Errorf("team membership: %w", ErrUnsupported)
+}
+
+// IsChannelMember always fails: permission rules naming channels refer to
+// Mattermost channels.
+func (c *Client) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	return false, fmt.Errorf("channel membership: %w", ErrUnsupported)
+}
+
+// GetUserGroups always fails: Slack user groups are not Mattermost groups.
+func (c *Client) GetUserGroups(ctx context.Context, userID string) ([]string, error) {
+	return nil, fmt.Errorf("user groups: %w", ErrUnsupported)
+}
+
+// OpenDialog always fails, so Resolve resolves right away.
+func (c *Client) OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+	return fmt.Errorf("dialogs: %w", ErrUnsupported)
+}
+
+// call posts body as JSON to the Web API method and decodes the answer into
+// out, whose embedded response reports Slack's own errors.
+func (c *Client) call(ctx context.Context, method string, body any, out interface{ err() error }) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal %s body: %w", method, err)
+	}
+	return c.do(ctx, method, "application/json; charset=utf-8", jsonBody, out)
+}
+
+// callForm is call for the read methods, which take form-encoded arguments.
+func (c *Client) callForm(ctx context.Context, method string, params url.Values, out interface{ err() error }) error {
+	return c.do(ctx, method, "application/x-www-form-urlencoded", []byte(params.Encode()), out)
+}
+
+func (r *response) err() error {
+	if !r.OK {
+		return errors.New(r.Error)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, contentType string, body []byte, out interface{ err() error }) error {
+	start := time.Now()
+	defer slackAPIDur(method).UpdateDuration(start)
+	reqURL := c.baseURL + "/" + method
+
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, method), http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Slack "+method+" failed",
+			logger.ExternalFieldsWithError("slack", reqURL, http.MethodPost, 0, duration, err.Error()),
+		)
+		slackAPICalls(method, "error").Inc()
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Slack "+method+" non-200",
+			logger.ExternalFieldsWithError("slack", reqURL, http.MethodPost, resp.StatusCode, duration, string(respBody)),
+		)
+		slackAPICalls(method, "error").Inc()
+		return fmt.Errorf("slack %s: status %d, body: %s", method, resp.StatusCode, respBody)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		slackAPICalls(method, "error").Inc()
+		return fmt.Errorf("decode %s response: %w", method, err)
+	}
+	// Slack answers 200 with ok=false for its own errors.
+	if err := out.err(); err != nil {
+		c.logger.Error("Slack "+method+" not ok",
+			logger.ExternalFieldsWithError("slack", reqURL, http.MethodPost, resp.StatusCode, duration, err.Error()),
+		)
+		slackAPICalls(method, "error").Inc()
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+
+	c.logger.Debug("Slack "+method+" completed",
+		logger.ExternalFields("slack", reqURL, http.MethodPost, resp.StatusCode, duration),
+	)
+	slackAPICalls(method, "ok").Inc()
+	return nil
+}
@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "xoxb-test", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestCreateAndUpdatePost(t *testing.T) {
+	var requests []postMessageRequest
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.Path)
+		var req postMessageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+	})
+	client.SetBotIdentity("Alerts", "")
+
+	postID, err := client.CreatePost(context.Background(), "C123", post.Attachment{Title: "CPU high", Color: "#FF0000", Message: "@here"})
+	require.NoError(t, err)
+	assert.Equal(t, "C123/1700000000.000100", postID)
+
+	require.NoError(t, client.UpdatePost(context.Background(), postID, post.Attachment{Title: "CPU high"}))
+	require.NoError(t, client.ReplyToThread(context.Background(), "C123", postID, "Acknowledged by **@jane**"))
+
+	assert.Equal(t, []string{"/chat.postMessage", "/chat.update", "/chat.postMessage"}, paths)
+	assert.Equal(t, "C123", requests[0].Channel)
+	assert.Equal(t, "<!here>", requests[0].Text)
+	assert.Equal(t, "Alerts", requests[0].Username)
+	require.Len(t, requests[0].Attachments, 1)
+	assert.Equal(t, "#FF0000", requests[0].Attachments[0].Color)
+	assert.Equal(t, "1700000000.000100", requests[1].TS)
+	assert.Equal(t, "1700000000.000100", requests[2].ThreadTS)
+	assert.Equal(t, "Acknowledged by *@jane*", requests[2].Text)
+}
+
+func TestSlackErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	})
+
+	_, err := client.CreatePost(context.Background(), "C404", post.Attachment{Title: "CPU high"})
+	assert.ErrorContains(t, err, "slack chat.postMessage: channel_not_found")
+
+	assert.ErrorContains(t, client.UpdatePost(context.Background(), "not-a-slack-id", post.Attachment{}), `invalid slack post id "not-a-slack-id"`)
+
+	_, err = client.IsTeamMember(context.Background(), "team", "U1")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestGetUserAndReactions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/users.info":
+			assert.Equal(t, "U1", r.PostForm.Get("user"))
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"name":"jane"}}`))
+		case "/reactions.get":
+			assert.Equal(t, "C123", r.PostForm.Get("channel"))
+			assert.Equal(t, "1700000000.000100", r.PostForm.Get("timestamp"))
+			_, _ = w.Write([]byte(`{"ok":true,"message":{"reactions":[{"name":"eyes","users":["U1","U2"]}]}}`))
+		case "/conversations.history":
+			_, _ = w.Write([]byte(`{"ok":true,"messages":[]}`))
+		}
+	})
+
+	name, err := client.GetUser(context.Background(), "U1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", name)
+
+	reactions, err := client.GetReactions(context.Background(), "C123/1700000000.000100")
+	require.NoError(t, err)
+	assert.Equal(t, []port.Reaction{{UserID: "U1", EmojiName: "eyes"}, {UserID: "U2", EmojiName: "eyes"}}, reactions)
+
+	_, err = client.GetPost(context.Background(), "C123/1700000000.000100")
+	assert.ErrorIs(t, err, port.ErrMattermostPostNotFound)
+}
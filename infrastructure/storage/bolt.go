@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+var postsBucket = []byte("posts")
+
+// BoltPostRepository keeps posts in an embedded bbolt file. Only one process
+// can open the file at a time, so it suits single-replica installs.
+type BoltPostRepository struct {
+	db     *bolt.DB
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// OpenBoltPostRepository opens or creates the database file at path.
+func OpenBoltPostRepository(path string, logger *slog.Logger) (*BoltPostRepository, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt database %s: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(postsBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create posts bucket: %w", err)
+	}
+
+	return &BoltPostRepository{
+		db:     db,
+		now:    time.Now,
+		logger: logger,
+	}, nil
+}
+
+func (r *BoltPostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
+	data, err := json.Marshal(newPostRecord(p, r.now()))
+	if err != nil {
+		return fmt.Errorf("marshal post data: %w", err)
+	}
+	if err := r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(postsBucket).Put([]byte(fingerprint.Value()), data)
+	}); err != nil {
+		return fmt.Errorf("bolt put: %w", err)
+	}
+	return nil
+}
+
+func (r *BoltPostRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
+	var rec postRecord
+	var found bool
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(postsBucket).Get([]byte(fingerprint.Value()))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &rec)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bolt get: %w", err)
+	}
+	if !found || rec.expired(r.now()) {
+		return nil, post.ErrNotFound
+	}
+	return rec.toPost(), nil
+}
+
+// FindAllActive returns every post that has not expired and removes the
+// expired ones.
+func (r *BoltPostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error) {
+	now := r.now()
+	var posts []*post.Post
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(postsBucket)
+		var expired [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			var rec postRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				r.logger.Warn("Skipping unreadable post",
+					slog.String("fingerprint", string(k)),
+					slog.String("error", err.Error()),
+				)
+				return nil
+			}
+			if rec.expired(now) {
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			posts = append(posts, rec.toPost())
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bolt scan: %w", err)
+	}
+	return posts, nil
+}
+
+func (r *BoltPostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	if err := r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(postsBucket).Delete([]byte(fingerprint.Value()))
+	}); err != nil {
+		return fmt.Errorf("bolt delete: %w", err)
+	}
+	return nil
+}
+
+// Ping fails once the database has been closed.
+func (r *BoltPostRepository) Ping(ctx context.Context) error {
+	return r.db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+func (r *BoltPostRepository) Close() error {
+	return r.db.Close()
+}
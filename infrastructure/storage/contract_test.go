@@ -0,0 +1,12 @@
+package storage
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Compile-time contracts: the repositories are wired into use cases through
+// the domain interfaces.
+var (
+	_ post.Repository = (*MemoryPostRepository)(nil)
+	_ post.Repository = (*BoltPostRepository)(nil)
+)
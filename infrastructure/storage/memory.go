@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// MemoryPostRepository keeps posts in process memory. They are lost on
+// restart and are not shared between replicas.
+type MemoryPostRepository struct {
+	mu      sync.RWMutex
+	records map[string]postRecord
+	now     func() time.Time
+}
+
+func NewMemoryPostRepository() *MemoryPostRepository {
+	return &MemoryPostRepository{
+		records: make(map[string]postRecord),
+		now:     time.Now,
+	}
+}
+
+func (r *MemoryPostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[fingerprint.Value()] = newPostRecord(p, r.now())
+	return nil
+}
+
+func (r *MemoryPostRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*post.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rec, ok := r.records[fingerprint.Value()]
+	if !ok || rec.expired(r.now()) {
+		return nil, post.ErrNotFound
+	}
+	return rec.toPost(), nil
+}
+
+func (r *MemoryPostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	posts := make([]*post.Post, 0, len(r.records))
+	for key, rec := range r.records {
+		if rec.expired(now) {
+			delete(r.records, key)
+			continue
+		}
+		posts = append(posts, rec.toPost())
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].Fingerprint().Value() < posts[j].Fingerprint().Value() })
+	return posts, nil
+}
+
+func (r *MemoryPostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, fingerprint.Value())
+	return nil
+}
+
+// Ping always succeeds; memory is always reachable.
+func (r *MemoryPostRepository) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package storage provides post repositories that need no Valkey server: an
+// in-memory one for tests and single-replica installs that can afford to lose
+// their posts on restart, and one backed by an embedded bbolt file.
+package storage
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// postTTL matches the Valkey repository: posts not updated for a week are
+// forgotten.
+const postTTL = 7 * 24 * time.Hour
+
+type postRecord struct {
+	PostID            string            `json:"post_id"`
+	ChannelID         string            `json:"channel_id"`
+	Fingerprint       string            `json:"fingerprint"`
+	AlertName         string            `json:"alert_name"`
+	Severity          string            `json:"severity"`
+	FiringStartTime   time.Time         `json:"firing_start_time"`
+	CreatedAt         time.Time         `json:"created_at"`
+	LastUpdated       time.Time         `json:"last_updated"`
+	LastKnownAssignee string            `json:"last_known_assignee,omitempty"`
+	ShownStatus       string            `json:"shown_status,omitempty"`
+	SnoozedUntil      time.Time         `json:"snoozed_until,omitzero"`
+	EscalationLevel   int               `json:"escalation_level,omitempty"`
+	EscalatedAt       time.Time         `json:"escalated_at,omitzero"`
+	AcknowledgedAt    time.Time         `json:"acknowledged_at,omitzero"`
+	ResolvedAt        time.Time         `json:"resolved_at,omitzero"`
+	AckUntil          time.Time         `json:"ack_until,omitzero"`
+	AckedBy           string            `json:"acked_by,omitempty"`
+	Refires           int               `json:"refires,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	ExpiresAt         time.Time         `json:"expires_at"`
+}
+
+func newPostRecord(p *post.Post, now time.Time) postRecord {
+	// A snoozed post must outlive its snooze so the unsnooze job can still
+	// restore it.
+	expiresAt := now.Add(postTTL)
+	if p.SnoozedUntil().After(now) {
+		expiresAt = p.SnoozedUntil().Add(postTTL)
+	}
+
+	labels := make(map[string]string, len(p.Labels()))
+	for k, v := range p.Labels() {
+		labels[k] = v
+	}
+
+	return postRecord{
+		PostID:            p.PostID(),
+		ChannelID:         p.ChannelID(),
+		Fingerprint:       p.Fingerprint().Value(),
+		AlertName:         p.AlertName(),
+		Severity:          p.Severity().String(),
+		FiringStartTime:   p.FiringStartTime(),
+		CreatedAt:         p.CreatedAt(),
+		LastUpdated:       p.LastUpdated(),
+		LastKnownAssignee: p.LastKnownAssignee(),
+		ShownStatus:       p.ShownStatus(),
+		SnoozedUntil:      p.SnoozedUntil(),
+		EscalationLevel:   p.EscalationLevel(),
+		EscalatedAt:       p.EscalatedAt(),
+		AcknowledgedAt:    p.AcknowledgedAt(),
+		ResolvedAt:        p.ResolvedAt(),
+		AckUntil:          p.AckUntil(),
+		AckedBy:           p.AckedBy(),
+		Refires:           p.Refires(),
+		Labels:            labels,
+		ExpiresAt:         expiresAt,
+	}
+}
+
+func (r postRecord) expired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+func (r postRecord) toPost() *post.Post {
+	p := post.RestorePost(
+		r.PostID,
+		r.ChannelID,
+		alert.RestoreFingerprint(r.Fingerprint),
+		r.AlertName,
+		alert.RestoreSeverity(r.Severity),
+		r.FiringStartTime,
+		r.CreatedAt,
+		r.LastUpdated,
+		r.LastKnownAssignee,
+	)
+	p.ShowStatus(r.ShownStatus)
+	if !r.SnoozedUntil.IsZero() {
+		p.Snooze(r.SnoozedUntil)
+	}
+	if r.EscalationLevel > 0 {
+		p.RestoreEscalation(r.EscalationLevel, r.EscalatedAt)
+	}
+	p.RestoreLifecycle(r.AcknowledgedAt, r.ResolvedAt)
+	if !r.AckUntil.IsZero() {
+		p.AcknowledgeUntil(r.AckUntil, r.AckedBy)
+	}
+	p.RestoreRefires(r.Refires)
+	if len(r.Labels) > 0 {
+		labels := make(map[string]string, len(r.Labels))
+		for k, v := range r.Labels {
+			labels[k] = v
+		}
+		p.SetLabels(labels)
+	}
+	return p
+}
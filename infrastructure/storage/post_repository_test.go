@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+type testPostRepository interface {
+	post.Repository
+	Ping(ctx context.Context) error
+}
+
+// forEachBackend runs test against every backend, each with its clock set to
+// *now.
+func forEachBackend(t *testing.T, test func(t *testing.T, repo testPostRepository, now *time.Time)) {
+	t.Run("memory", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		repo := NewMemoryPostRepository()
+		repo.now = func() time.Time { return now }
+		test(t, repo, &now)
+	})
+	t.Run("bolt", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		repo, err := OpenBoltPostRepository(filepath.Join(t.TempDir(), "kmbridge.db"), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = repo.Close() })
+		repo.now = func() time.Time { return now }
+		test(t, repo, &now)
+	})
+}
+
+func newTestPost(fingerprint string, now time.Time) *post.Post {
+	p := post.NewPost("post-"+fingerprint, "channel-1", alert.RestoreFingerprint(fingerprint), "DiskFull", alert.RestoreSeverity("critical"), now, time.Now())
+	p.SetLabels(map[string]string{"host": "db-1"})
+	return p
+}
+
+func TestPostRepositorySaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo testPostRepository, now *time.Time) {
+		ctx := context.Background()
+		fp := alert.RestoreFingerprint("fp-1")
+		p := newTestPost("fp-1", *now)
+		p.SetLastKnownAssignee("alice")
+		p.ShowStatus("acknowledged")
+		p.RestoreEscalation(2, *now)
+		p.Acknowledge(now.Add(time.Minute))
+		p.AcknowledgeUntil(now.Add(2*time.Hour), "alice")
+		p.Refire()
+		require.NoError(t, repo.Save(ctx, fp, p))
+
+		found, err := repo.FindByFingerprint(ctx, fp)
+		require.NoError(t, err)
+		assert.Equal(t, "post-fp-1", found.PostID())
+		assert.Equal(t, "channel-1", found.ChannelID())
+		assert.Equal(t, "DiskFull", found.AlertName())
+		assert.Equal(t, "critical", found.Severity().String())
+		assert.Equal(t, "alice", found.LastKnownAssignee())
+		assert.Equal(t, "acknowledged", found.ShownStatus())
+		assert.Equal(t, 2, found.EscalationLevel())
+		assert.True(t, now.Add(time.Minute).Equal(found.AcknowledgedAt()))
+		assert.True(t, found.ResolvedAt().IsZero())
+		assert.True(t, now.Add(2*time.Hour).Equal(found.AckUntil()))
+		assert.Equal(t, "alice", found.AckedBy())
+		assert.Equal(t, 2, found.FireCount())
+		assert.Equal(t, map[string]string{"host": "db-1"}, found.Labels())
+
+		found.SetLabels(map[string]string{"host": "changed"})
+		again, err := repo.FindByFingerprint(ctx, fp)
+		require.NoError(t, err)
+		assert.Equal(t, "db-1", again.Labels()["host"], "returned posts are copies")
+
+		_, err = repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-missing"))
+		assert.ErrorIs(t, err, post.ErrNotFound)
+		assert.NoError(t, repo.Ping(ctx))
+	})
+}
+
+func TestPostRepositoryFindAllActiveAndDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo testPostRepository, now *time.Time) {
+		ctx := context.Background()
+		for _, fp := range []string{"fp-2", "fp-1"} {
+			require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint(fp), newTestPost(fp, *now)))
+		}
+
+		posts, err := repo.FindAllActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, posts, 2)
+		assert.Equal(t, "fp-1", posts[0].Fingerprint().Value())
+		assert.Equal(t, "fp-2", posts[1].Fingerprint().Value())
+
+		require.NoError(t, repo.Delete(ctx, alert.RestoreFingerprint("fp-1")))
+		posts, err = repo.FindAllActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		assert.Equal(t, "fp-2", posts[0].Fingerprint().Value())
+
+		_, err = repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+		assert.ErrorIs(t, err, post.ErrNotFound)
+	})
+}
+
+func TestPostRepositoryExpiry(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repo testPostRepository, now *time.Time) {
+		ctx := context.Background()
+		require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint("fp-1"), newTestPost("fp-1", *now)))
+		snoozed := newTestPost("fp-2", *now)
+		snoozed.Snooze(now.Add(48 * time.Hour))
+		require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint("fp-2"), snoozed))
+
+		*now = now.Add(postTTL + time.Hour)
+
+		_, err := repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+		assert.ErrorIs(t, err, post.ErrNotFound)
+		posts, err := repo.FindAllActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, posts, 1, "a snoozed post outlives its snooze")
+		assert.Equal(t, "fp-2", posts[0].Fingerprint().Value())
+	})
+}
+
+func TestBoltPostRepositoryPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kmbridge.db")
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	repo, err := OpenBoltPostRepository(path, log)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, alert.RestoreFingerprint("fp-1"), newTestPost("fp-1", time.Now())))
+	require.NoError(t, repo.Close())
+	assert.Error(t, repo.Ping(ctx))
+
+	repo, err = OpenBoltPostRepository(path, log)
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+	found, err := repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "post-fp-1", found.PostID())
+}
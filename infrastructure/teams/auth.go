@@ -0,0 +1,198 @@
+package teams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+const (
+	// DefaultOpenIDMetadataURL lists the keys the Bot Connector signs its
+	// requests to bots with.
+	DefaultOpenIDMetadataURL = "https://login.botframework.com/v1/.well-known/openidconfiguration"
+
+	tokenIssuer = "https://api.botframework.com"
+	// keysMaxAge is how long signing keys are used before they are fetched
+	// again; unknown key IDs refetch them at most every keysMinRefresh.
+	keysMaxAge     = 24 * time.Hour
+	keysMinRefresh = 5 * time.Minute
+	clockSkew      = 5 * time.Minute
+)
+
+// Authenticator checks the bearer tokens the Bot Connector sends with
+// activities: RS256 tokens issued by the Bot Framework for the bot's app ID.
+type Authenticator struct {
+	appID       string
+	metadataURL string
+	httpClient  *http.Client
+	clock       clock.Clock
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+func NewAuthenticator(appID, metadataURL string) *Authenticator {
+	return &Authenticator{
+		appID:       appID,
+		metadataURL: metadataURL,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		clock:       clock.Real(),
+	}
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type tokenClaims struct {
+	Iss string `json:"iss"`
+	Aud string `json:"aud"`
+	Exp int64  `json:"exp"`
+	Nbf int64  `json:"nbf"`
+}
+
+// Authenticate returns an error unless authorization is "Bearer <token>" with
+// a valid Bot Framework token for the bot.
+func (a *Authenticator) Authenticate(ctx context.Context, authorization string) error {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return errors.New("missing bearer token")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return fmt.Errorf("token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("unexpected token algorithm %q", header.Alg)
+	}
+	key, err := a.key(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return errors.New("invalid token signature")
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return fmt.Errorf("token claims: %w", err)
+	}
+	now := a.clock.Now()
+	switch {
+	case claims.Iss != tokenIssuer:
+		return fmt.Errorf("unexpected token issuer %q", claims.Iss)
+	case claims.Aud != a.appID:
+		return fmt.Errorf("token is for another app %q", claims.Aud)
+	case now.After(time.Unix(claims.Exp, 0).Add(clockSkew)):
+		return errors.New("token expired")
+	case claims.Nbf != 0 && now.Before(time.Unix(claims.Nbf, 0).Add(-clockSkew)):
+		return errors.New("token not yet valid")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// key returns the signing key kid, fetching the keys when they are old or
+// kid is unknown.
+func (a *Authenticator) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.clock.Now()
+	key, ok := a.keys[kid]
+	stale := now.Sub(a.fetched) > keysMaxAge
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && now.Sub(a.fetched) < keysMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.keys, a.fetched = keys, now
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (a *Authenticator) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var metadata struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := a.getJSON(ctx, a.metadataURL, &metadata); err != nil {
+		return nil, fmt.Errorf("fetch openid metadata: %w", err)
+	}
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := a.getJSON(ctx, metadata.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("fetch signing keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+func (a *Authenticator) getJSON(ctx context.Context, reqURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
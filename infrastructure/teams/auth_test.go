@@ -0,0 +1,126 @@
+package teams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+var authEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestAuthenticator serves OpenID metadata and the public key of key as
+// "key-1".
+func newTestAuthenticator(t *testing.T, key *rsa.PrivateKey) (*Authenticator, *clock.Fake) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/metadata", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "key-1",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	fake := clock.NewFake(authEpoch)
+	a := NewAuthenticator("app-id", server.URL+"/metadata")
+	a.clock = fake
+	return a, fake
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims tokenClaims) string {
+	t.Helper()
+	header, err := json.Marshal(tokenHeader{Alg: "RS256", Kid: kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return "Bearer " + signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestAuthenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	valid := tokenClaims{Iss: tokenIssuer, Aud: "app-id", Exp: authEpoch.Add(time.Hour).Unix(), Nbf: authEpoch.Add(-time.Minute).Unix()}
+
+	tests := []struct {
+		name          string
+		authorization func() string
+		wantErr       string
+	}{
+		{name: "valid", authorization: func() string { return signToken(t, key, "key-1", valid) }},
+		{name: "no bearer", authorization: func() string { return "Basic abc" }, wantErr: "missing bearer token"},
+		{name: "malformed", authorization: func() string { return "Bearer abc" }, wantErr: "malformed token"},
+		{name: "unknown key", authorization: func() string { return signToken(t, key, "key-2", valid) }, wantErr: `unknown signing key "key-2"`},
+		{name: "wrong signature", authorization: func() string { return signToken(t, other, "key-1", valid) }, wantErr: "invalid token signature"},
+		{name: "wrong issuer", authorization: func() string {
+			claims := valid
+			claims.Iss = "https://example.com"
+			return signToken(t, key, "key-1", claims)
+		}, wantErr: "unexpected token issuer"},
+		{name: "other app", authorization: func() string {
+			claims := valid
+			claims.Aud = "other-app"
+			return signToken(t, key, "key-1", claims)
+		}, wantErr: "token is for another app"},
+		{name: "expired", authorization: func() string {
+			claims := valid
+			claims.Exp = authEpoch.Add(-time.Hour).Unix()
+			return signToken(t, key, "key-1", claims)
+		}, wantErr: "token expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newTestAuthenticator(t, key)
+			err := a.Authenticate(context.Background(), tt.authorization())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAuthenticateRefetchesKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	a, fake := newTestAuthenticator(t, key)
+	claims := tokenClaims{Iss: tokenIssuer, Aud: "app-id", Exp: authEpoch.Add(48 * time.Hour).Unix()}
+
+	require.NoError(t, a.Authenticate(context.Background(), signToken(t, key, "key-1", claims)))
+	fetched := a.fetched
+
+	fake.Advance(time.Hour)
+	require.NoError(t, a.Authenticate(context.Background(), signToken(t, key, "key-1", claims)))
+	assert.Equal(t, fetched, a.fetched, "cached keys are reused")
+
+	fake.Advance(keysMaxAge)
+	require.NoError(t, a.Authenticate(context.Background(), signToken(t, key, "key-1", claims)))
+	assert.True(t, a.fetched.After(fetched), "old keys are fetched again")
+}
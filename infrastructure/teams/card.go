@@ -0,0 +1,139 @@
+package teams
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// cardVersion is the newest Adaptive Card version Teams renders everywhere.
+const cardVersion = "1.4"
+
+type card map[string]any
+
+type element map[string]any
+
+// actionData is the data of the buttons of an alert card, sent back as the
+// value of the message activity of a click. Context is the button's callback
+// context.
+type actionData struct {
+	Context map[string]string `json:"kmbridge"`
+}
+
+// toCard renders an alert card as an Adaptive Card: the mentions, the title,
+// the text, the fields, the footer and the buttons. Message menus, such as
+// assign and the workflow menu, are left out.
+func toCard(a post.Attachment) card {
+	var items []element
+	if a.Message != "" {
+		items = append(items, element{"type": "TextBlock", "text": a.Message, "wrap": true})
+	}
+	title := a.Title
+	if a.TitleLink != "" {
+		title = "[" + a.Title + "](" + a.TitleLink + ")"
+	}
+	items = append(items, element{"type": "TextBlock", "text": title, "weight": "Bolder", "size": "Medium", "wrap": true})
+	if a.Text != "" {
+		items = append(items, element{"type": "TextBlock", "text": a.Text, "wrap": true})
+	}
+
+	// Short fields form a fact list; long fields get a block of their own.
+	var facts []element
+	flush := func() {
+		if len(facts) > 0 {
+			items = append(items, element{"type": "FactSet", "facts": facts})
+			facts = nil
+		}
+	}
+	for _, f := range a.Fields {
+		if f.Short {
+			facts = append(facts, element{"title": f.Title, "value": f.Value})
+			continue
+		}
+		flush()
+		items = append(items, element{"type": "TextBlock", "text": "**" + f.Title + "**\n\n" + f.Value, "wrap": true})
+	}
+	flush()
+
+	if a.Footer != "" {
+		items = append(items, element{"type": "TextBlock", "text": a.Footer, "size": "Small", "isSubtle": true, "wrap": true})
+	}
+
+	var actions []element
+	for _, b := range a.Actions {
+		if b.Type != "" && b.Type != post.ButtonTypeButton {
+			continue
+		}
+		action := element{"type": "Action.Submit", "title": b.Name, "data": actionData{Context: actionContext(b.Integration.Context)}}
+		switch b.Style {
+		case post.ButtonStyleSuccess:
+			action["style"] = "positive"
+		case post.ButtonStyleDanger:
+			action["style"] = "destructive"
+		}
+		actions = append(actions, action)
+	}
+
+	c := card{
+		"type":    "AdaptiveCard",
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"version": cardVersion,
+		"msteams": map[string]any{"width": "Full"},
+		// A container styled after the severity color stands in for the
+		// colored bar of Mattermost attachments.
+		"body": []element{{"type": "Container", "style": containerStyle(a.Color), "bleed": true, "items": items}},
+	}
+	if len(actions) > 0 {
+		c["actions"] = actions
+	}
+	return c
+}
+
+// actionContext drops the rendered post from a button context: cards are
+// updated in place, so it is not needed to restore them.
+func actionContext(context map[string]string) map[string]string {
+	trimmed := make(map[string]string, len(context))
+	for k, v := range context {
+		if k != post.ContextKeyAttachmentJSON {
+			trimmed[k] = v
+		}
+	}
+	return trimmed
+}
+
+// containerStyle maps a hex color onto the closest Adaptive Card container
+// style by hue.
+func containerStyle(color string) string {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(color, "#")) != 6 {
+		return "default"
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	if maxC-minC < 32 {
+		return "emphasis" // grey: resolved or unknown
+	}
+	var hue float64
+	switch maxC {
+	case r:
+		hue = 60 * (g - b) / (maxC - minC)
+	case g:
+		hue = 60*(b-r)/(maxC-minC) + 120
+	default:
+		hue = 60*(r-g)/(maxC-minC) + 240
+	}
+	if hue < 0 {
+		hue += 360
+	}
+	switch {
+	case hue < 20 || hue >= 330:
+		return "attention"
+	case hue < 70:
+		return "warning"
+	case hue < 170:
+		return "good"
+	default:
+		return "accent"
+	}
+}
@@ -0,0 +1,84 @@
+package teams
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func TestToCard(t *testing.T) {
+	a := post.Attachment{
+		Color:     "#CC0000",
+		Title:     "CPU high",
+		TitleLink: "https://keep/alerts/fp-1",
+		Text:      "load above 90%",
+		Fields: []post.AttachmentField{
+			{Title: "Severity", Value: "critical", Short: true},
+			{Title: "Host", Value: "web-1", Short: true},
+			{Title: "Description", Value: "long text"},
+		},
+		Footer: "Keep",
+		Actions: []post.Button{
+			{Name: "Acknowledge", Style: post.ButtonStyleSuccess, Integration: post.ButtonIntegration{Context: map[string]string{post.ContextKeyAction: post.ActionAcknowledge, post.ContextKeyAttachmentJSON: "{}"}}},
+			{Name: "Assign", Type: post.ButtonTypeSelect},
+		},
+	}
+
+	data, err := json.Marshal(toCard(a))
+	require.NoError(t, err)
+	var got struct {
+		Version string `json:"version"`
+		Body    []struct {
+			Style string `json:"style"`
+			Items []struct {
+				Type  string `json:"type"`
+				Text  string `json:"text"`
+				Facts []struct {
+					Title string `json:"title"`
+				} `json:"facts"`
+			} `json:"items"`
+		} `json:"body"`
+		Actions []struct {
+			Type  string     `json:"type"`
+			Title string     `json:"title"`
+			Style string     `json:"style"`
+			Data  actionData `json:"data"`
+		} `json:"actions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, cardVersion, got.Version)
+	require.Len(t, got.Body, 1)
+	assert.Equal(t, "attention", got.Body[0].Style)
+	items := got.Body[0].Items
+	require.Len(t, items, 5)
+	assert.Equal(t, "[CPU high](https://keep/alerts/fp-1)", items[0].Text)
+	assert.Equal(t, "FactSet", items[2].Type)
+	assert.Len(t, items[2].Facts, 2)
+	assert.Equal(t, "**Description**\n\nlong text", items[3].Text)
+	assert.Equal(t, "Keep", items[4].Text)
+
+	require.Len(t, got.Actions, 1, "menus are left out")
+	assert.Equal(t, "Action.Submit", got.Actions[0].Type)
+	assert.Equal(t, "positive", got.Actions[0].Style)
+	assert.Equal(t, map[string]string{post.ContextKeyAction: post.ActionAcknowledge}, got.Actions[0].Data.Context)
+}
+
+func TestContainerStyle(t *testing.T) {
+	tests := map[string]string{
+		"#CC0000": "attention",
+		"#FF9900": "warning",
+		"#36A64F": "good",
+		"#2196F3": "accent",
+		"#808080": "emphasis",
+		"red":     "default",
+		"":        "default",
+	}
+	for color, want := range tests {
+		assert.Equal(t, want, containerStyle(color), color)
+	}
+}
@@ -0,0 +1,361 @@
+// Package teams posts alert cards to Microsoft Teams as Adaptive Cards through
+// the Bot Connector API, with the same calls the bridge makes to Mattermost,
+// so a routing rule can target a Teams channel.
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/ratelimit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+const (
+	// DefaultServiceURL is the global Bot Connector endpoint of Teams.
+	DefaultServiceURL = "https://smba.trafficmanager.net/teams"
+	// DefaultTokenURL issues tokens to multi-tenant bots.
+	DefaultTokenURL = "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token"
+
+	tokenScope = "https://api.botframework.com/.default"
+)
+
+// TenantTokenURL is the token endpoint of single-tenant bots of tenantID.
+func TenantTokenURL(tenantID string) string {
+	return "https://login.microsoftonline.com/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+}
+
+// ErrUnsupported is returned for Mattermost features Teams bots have no
+// counterpart for, such as channel purposes, teams, groups and dialogs.
+var ErrUnsupported = errors.New("not supported on teams")
+
+var teamsAPIDur = func(operation string) *metrics.Histogram {
+	return metrics.GetOrCreateHistogram(`teams_api_duration_seconds{operation="` + operation + `"}`)
+}
+
+var teamsAPICalls = func(operation, status string) *metrics.Counter {
+	return metrics.GetOrCreateCounter(`teams_api_calls_total{operation="` + operation + `",status="` + status + `"}`)
+}
+
+// Client posts as a Bot Framework bot. Channel IDs are Teams conversation
+// IDs, and post IDs it returns are "{conversation}/{activity}".
+type Client struct {
+	serviceURL  string
+	tokenURL    string
+	appID       string
+	appPassword string
+	httpClient  *http.Client
+	retry       *retry.Transport
+	rateLimit   *ratelimit.Transport
+	logger      *slog.Logger
+
+	tokenMu sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewClient returns a client of the bot appID. tokenURL is DefaultTokenURL,
+// or the token endpoint of the bot's tenant for single-tenant bots.
+func NewClient(serviceURL, tokenURL, appID, appPassword string, logger *slog.Logger) *Client {
+	limiter := ratelimit.NewTransport(&http.Transport{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}, "teams")
+	transport := retry.NewTransport(limiter, "teams", retry.Policy{MaxAttempts: 1})
+	return &Client{
+		serviceURL:  strings.TrimSuffix(serviceURL, "/"),
+		tokenURL:    tokenURL,
+		appID:       appID,
+		appPassword: appPassword,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		retry:     transport,
+		rateLimit: limiter,
+		logger:    logger,
+	}
+}
+
+// SetRetryPolicy retries requests that fail with a network error, 429 or 5xx
+// response. Requests are not retried until it is called.
+func (c *Client) SetRetryPolicy(p retry.Policy) {
+	c.retry.SetPolicy(p)
+}
+
+// SetRateLimit spaces out requests to the Bot Connector. Requests are not
+// limited until it is called.
+func (c *Client) SetRateLimit(l ratelimit.Limit) {
+	c.rateLimit.SetLimit(l)
+}
+
+// PostID returns the ID the client uses for activityID in conversationID.
+func PostID(conversationID, activityID string) string {
+	return conversationID + "/" + activityID
+}
+
+// splitPostID splits at the last slash: activity IDs never contain one.
+func splitPostID(postID string) (string, string, error) {
+	i := strings.LastIndex(postID, "/")
+	if i <= 0 || i == len(postID)-1 {
+		return "", "", fmt.Errorf("invalid teams post id %q", postID)
+	}
+	return postID[:i], postID[i+1:], nil
+}
+
+type activity struct {
+	Type        string           `json:"type"`
+	ID          string           `json:"id,omitempty"`
+	Text        string           `json:"text,omitempty"`
+	TextFormat  string           `json:"textFormat,omitempty"`
+	ReplyToID   string           `json:"replyToId,omitempty"`
+	Attachments []cardAttachment `json:"attachments,omitempty"`
+}
+
+type cardAttachment struct {
+	ContentType string `json:"contentType"`
+	Content     card   `json:"content"`
+}
+
+type resourceResponse struct {
+	ID string `json:"id"`
+}
+
+func cardActivity(a post.Attachment) activity {
+	return activity{
+		Type:        "message",
+		Attachments: []cardAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: toCard(a)}},
+	}
+}
+
+func (c *Client) activitiesURL(conversationID string) string {
+	return c.serviceURL + "/v3/conversations/" + url.PathEscape(conversationID) + "/activities"
+}
+
+func (c *Client) CreatePost(ctx context.Context, channelID string, a post.Attachment) (string, error) {
+	var resp resourceResponse
+	if err := c.do(ctx, "create_post", http.MethodPost, c.activitiesURL(channelID), cardActivity(a), &resp); err != nil {
+		return "", err
+	}
+	return PostID(channelID, resp.ID), nil
+}
+
+// CreateThreadPost posts an attachment as a reply in the thread of rootID.
+func (c *Client) CreateThreadPost(ctx context.Context, channelID, rootID string, a post.Attachment) (string, error) {
+	conversationID, activityID, err := splitPostID(rootID)
+	if err != nil {
+		return "", err
+	}
+	body := cardActivity(a)
+	body.ReplyToID = activityID
+	var resp resourceResponse
+	if err := c.do(ctx, "create_thread_post", http.MethodPost, c.activitiesURL(conversationID)+"/"+url.PathEscape(activityID), body, &resp); err != nil {
+		return "", err
+	}
+	return PostID(conversationID, resp.ID), nil
+}
+
+func (c *Client) UpdatePost(ctx context.Context, postID string, a post.Attachment) error {
+	conversationID, activityID, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	body := cardActivity(a)
+	body.ID = activityID
+	return c.do(ctx, "update_post", http.MethodPut, c.activitiesURL(conversationID)+"/"+url.PathEscape(activityID), body, nil)
+}
+
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	conversationID, activityID, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, "delete_post", http.MethodDelete, c.activitiesURL(conversationID)+"/"+url.PathEscape(activityID), nil, nil)
+}
+
+func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
+	conversationID, activityID, err := splitPostID(rootID)
+	if err != nil {
+		return err
+	}
+	body := activity{Type: "message", Text: message, TextFormat: "markdown", ReplyToID: activityID}
+	return c.do(ctx, "reply_to_thread", http.MethodPost, c.activitiesURL(conversationID)+"/"+url.PathEscape(activityID), body, &resourceResponse{})
+}
+
+// GetUser returns the user principal name of a member, falling back to the
+// display name. userID is "{conversation}/{member}", as the Teams actions
+// handler sends it, since the Bot Connector looks members up in a
+// conversation.
+func (c *Client) GetUser(ctx context.Context, userID string) (string, error) {
+	conversationID, memberID, err := splitPostID(userID)
+	if err != nil {
+		return "", err
+	}
+	var member struct {
+		Name              string `json:"name"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	reqURL := c.serviceURL + "/v3/conversations/" + url.PathEscape(conversationID) + "/members/" + url.PathEscape(memberID)
+	if err := c.do(ctx, "get_user", http.MethodGet, reqURL, nil, &member); err != nil {
+		return "", err
+	}
+	if member.UserPrincipalName != "" {
+		return member.UserPrincipalName, nil
+	}
+	return member.Name, nil
+}
+
+// GetPost fails: the Bot Connector cannot read messages back, so deleted
+// cards go unnoticed.
+func (c *Client) GetPost(ctx context.Context, postID string) (port.MattermostPost, error) {
+	return port.MattermostPost{}, fmt.Errorf("read post: %w: %w", port.ErrMattermostPostUnreadable, ErrUnsupported)
+}
+
+// GetReactions returns no reactions: the Bot Connector does not expose them.
+func (c *Client) GetReactions(ctx context.Context, postID string) ([]port.Reaction, error) {
+	return nil, nil
+}
+
+func (c *Client) SetChannelPurpose(ctx context.Context, channelID, purpose string) error {
+	return fmt.Errorf("channel purpose: %w", ErrUnsupported)
+}
+
+func (c *Client) IsTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
+	return false, fmt.Errorf("team membership: %w", ErrUnsupported)
+}
+
+func (c *Client) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	return false, fmt.Errorf("channel membership: %w", ErrUnsupported)
+}
+
+func (c *Client) GetUserGroups(ctx context.Context, userID string) ([]string, error) {
+	return nil, fmt.Errorf("user groups: %w", ErrUnsupported)
+}
+
+// OpenDialog fails, so Resolve resolves right away.
+func (c *Client) OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+	return fmt.Errorf("dialogs: %w", ErrUnsupported)
+}
+
+// accessToken returns a cached bot token, fetching a new one a minute before
+// the cached one expires.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token != "" && time.Now().Before(c.expires) {
+		return c.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.appID},
+		"client_secret": {c.appPassword},
+		"scope":         {tokenScope},
+	}
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, "get_token"), http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("teams get token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("teams get token: status %d, body: %s", resp.StatusCode, respBody)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+	c.token = token.AccessToken
+	c.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+// do sends an optional JSON body and expects a 2xx response, decoded into
+// out when it is non-nil.
+func (c *Client) do(ctx context.Context, operation, method, reqURL string, body, out any) error {
+	start := time.Now()
+	defer teamsAPIDur(operation).UpdateDuration(start)
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		teamsAPICalls(operation, "error").Inc()
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal %s body: %w", operation, err)
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, operation), method, reqURL, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		c.logger.Error("Teams "+operation+" failed",
+			logger.ExternalFieldsWithError("teams", reqURL, method, 0, duration, err.Error()),
+		)
+		teamsAPICalls(operation, "error").Inc()
+		return fmt.Errorf("teams %s: %w", operation, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Teams "+operation+" non-2xx",
+			logger.ExternalFieldsWithError("teams", reqURL, method, resp.StatusCode, duration, string(respBody)),
+		)
+		teamsAPICalls(operation, "error").Inc()
+		return fmt.Errorf("teams %s: status %d, body: %s", operation, resp.StatusCode, respBody)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode %s response: %w", operation, err)
+		}
+	} else {
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+
+	c.logger.Debug("Teams "+operation+" completed",
+		logger.ExternalFields("teams", reqURL, method, resp.StatusCode, duration),
+	)
+	teamsAPICalls(operation, "ok").Inc()
+	return nil
+}
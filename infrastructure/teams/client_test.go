@@ -0,0 +1,123 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// newTestClient serves both the token endpoint, at /token, and the Bot
+// Connector with handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func() int) {
+	t.Helper()
+	var mu sync.Mutex
+	var tokens int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "app-id", r.PostForm.Get("client_id"))
+			assert.Equal(t, "app-password", r.PostForm.Get("client_secret"))
+			mu.Lock()
+			tokens++
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"access_token":"bot-token","expires_in":3600}`))
+			return
+		}
+		assert.Equal(t, "Bearer bot-token", r.Header.Get("Authorization"))
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL, server.URL+"/token", "app-id", "app-password", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	return client, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return tokens
+	}
+}
+
+func TestCreateUpdateAndDeletePost(t *testing.T) {
+	var requests []string
+	var bodies []activity
+	client, tokens := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		var body activity
+		if r.Method != http.MethodDelete {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"id":"1700000000001"}`))
+	})
+	ctx := context.Background()
+
+	postID, err := client.CreatePost(ctx, "19:abc@thread.tacv2", post.Attachment{Title: "CPU high", Color: "#FF0000"})
+	require.NoError(t, err)
+	assert.Equal(t, "19:abc@thread.tacv2/1700000000001", postID)
+
+	require.NoError(t, client.UpdatePost(ctx, postID, post.Attachment{Title: "CPU high"}))
+	require.NoError(t, client.ReplyToThread(ctx, "19:abc@thread.tacv2", postID, "Acknowledged by **@jane**"))
+	require.NoError(t, client.DeletePost(ctx, postID))
+
+	assert.Equal(t, []string{
+		"POST /v3/conversations/19:abc@thread.tacv2/activities",
+		"PUT /v3/conversations/19:abc@thread.tacv2/activities/1700000000001",
+		"POST /v3/conversations/19:abc@thread.tacv2/activities/1700000000001",
+		"DELETE /v3/conversations/19:abc@thread.tacv2/activities/1700000000001",
+	}, requests)
+	require.Len(t, bodies[0].Attachments, 1)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", bodies[0].Attachments[0].ContentType)
+	assert.Equal(t, "1700000000001", bodies[1].ID)
+	assert.Equal(t, "1700000000001", bodies[2].ReplyToID)
+	assert.Equal(t, "Acknowledged by **@jane**", bodies[2].Text)
+	assert.Equal(t, 1, tokens(), "the bot token is cached")
+}
+
+func TestGetUser(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/conversations/19:abc@thread.tacv2/members/29:user-1", r.URL.Path)
+		_, _ = w.Write([]byte(`{"id":"29:user-1","name":"Jane Doe","userPrincipalName":"jane@example.com"}`))
+	})
+
+	username, err := client.GetUser(context.Background(), "19:abc@thread.tacv2/29:user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", username)
+}
+
+func TestTeamsErrors(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":"BotNotInConversationRoster"}}`))
+	})
+	ctx := context.Background()
+
+	_, err := client.CreatePost(ctx, "19:abc@thread.tacv2", post.Attachment{Title: "CPU high"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BotNotInConversationRoster")
+
+	err = client.UpdatePost(ctx, "no-activity", post.Attachment{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid teams post id")
+
+	err = client.OpenDialog(ctx, "trigger", "https://bridge/dialog", port.Dialog{})
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	_, err = client.GetPost(ctx, "19:abc@thread.tacv2;messageid=1")
+	assert.True(t, errors.Is(err, ErrUnsupported))
+	assert.True(t, errors.Is(err, port.ErrMattermostPostUnreadable))
+}
+
+func TestTenantTokenURL(t *testing.T) {
+	assert.Equal(t, "https://login.microsoftonline.com/contoso.onmicrosoft.com/oauth2/v2.0/token", TenantTokenURL("contoso.onmicrosoft.com"))
+}
@@ -0,0 +1,340 @@
+// Package telegram posts alert cards to Telegram chats through the Bot API,
+// with the same calls the bridge makes to Mattermost, so a routing rule can
+// target a Telegram group or channel.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/ratelimit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/retry"
+)
+
+// DefaultURL is the Telegram Bot API.
+const DefaultURL = "https://api.telegram.org"
+
+// maxAnswerLength is the longest text a callback answer can show.
+const maxAnswerLength = 200
+
+// ErrUnsupported is returned for Mattermost features Telegram bots have no
+// counterpart for, such as reading messages back, teams, groups and dialogs.
+var ErrUnsupported = errors.New("not supported on telegram")
+
+var telegramAPIDur = func(method string) *metrics.Histogram {
+	return metrics.GetOrCreateHistogram(`telegram_api_duration_seconds{method="` + method + `"}`)
+}
+
+var telegramAPICalls = func(method, status string) *metrics.Counter {
+	return metrics.GetOrCreateCounter(`telegram_api_calls_total{method="` + method + `",status="` + status + `"}`)
+}
+
+// Client calls the Bot API with a bot token. Channel IDs are chat IDs, and
+// post IDs it returns are "{chat}/{message}".
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	retry      *retry.Transport
+	rateLimit  *ratelimit.Transport
+	logger     *slog.Logger
+
+	buttons *buttonStore
+}
+
+func NewClient(baseURL, token string, logger *slog.Logger) *Client {
+	limiter := ratelimit.NewTransport(&http.Transport{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}, "telegram")
+	transport := retry.NewTransport(limiter, "telegram", retry.Policy{MaxAttempts: 1})
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		retry:     transport,
+		rateLimit: limiter,
+		logger:    logger,
+		buttons:   newButtonStore(maxButtons),
+	}
+}
+
+// SetRetryPolicy retries requests that fail with a network error, 429 or 5xx
+// response. Requests are not retried until it is called.
+func (c *Client) SetRetryPolicy(p retry.Policy) {
+	c.retry.SetPolicy(p)
+}
+
+// SetRateLimit spaces out requests to the Bot API. Requests are not limited
+// until it is called.
+func (c *Client) SetRateLimit(l ratelimit.Limit) {
+	c.rateLimit.SetLimit(l)
+}
+
+// PostID returns the ID the client uses for messageID in chatID.
+func PostID(chatID string, messageID int64) string {
+	return chatID + "/" + strconv.FormatInt(messageID, 10)
+}
+
+func splitPostID(postID string) (string, int64, error) {
+	chatID, message, ok := strings.Cut(postID, "/")
+	messageID, err := strconv.ParseInt(message, 10, 64)
+	if !ok || chatID == "" || err != nil {
+		return "", 0, fmt.Errorf("invalid telegram post id %q", postID)
+	}
+	return chatID, messageID, nil
+}
+
+type response struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+type message struct {
+	MessageID int64 `json:"message_id"`
+}
+
+type sendMessageRequest struct {
+	ChatID             string              `json:"chat_id"`
+	MessageID          int64               `json:"message_id,omitempty"`
+	Text               string              `json:"text"`
+	ParseMode          string              `json:"parse_mode"`
+	ReplyParameters    *replyParameters    `json:"reply_parameters,omitempty"`
+	ReplyMarkup        *inlineKeyboard     `json:"reply_markup,omitempty"`
+	LinkPreviewOptions *linkPreviewOptions `json:"link_preview_options,omitempty"`
+}
+
+type replyParameters struct {
+	MessageID                int64 `json:"message_id"`
+	AllowSendingWithoutReply bool  `json:"allow_sending_without_reply"`
+}
+
+type linkPreviewOptions struct {
+	IsDisabled bool `json:"is_disabled"`
+}
+
+func (c *Client) card(chatID string, a post.Attachment) sendMessageRequest {
+	text, keyboard := toMessage(a, c.buttons)
+	return sendMessageRequest{
+		ChatID:             chatID,
+		Text:               text,
+		ParseMode:          "HTML",
+		ReplyMarkup:        keyboard,
+		LinkPreviewOptions: &linkPreviewOptions{IsDisabled: true},
+	}
+}
+
+func (c *Client) CreatePost(ctx context.Context, channelID string, a post.Attachment) (string, error) {
+	return c.send(ctx, c.card(channelID, a))
+}
+
+// CreateThreadPost posts an attachment as a reply to rootID.
+func (c *Client) CreateThreadPost(ctx context.Context, channelID, rootID string, a post.Attachment) (string, error) {
+	_, messageID, err := splitPostID(rootID)
+	if err != nil {
+		return "", err
+	}
+	req := c.card(channelID, a)
+	req.ReplyParameters = &replyParameters{MessageID: messageID, AllowSendingWithoutReply: true}
+	return c.send(ctx, req)
+}
+
+func (c *Client) send(ctx context.Context, req sendMessageRequest) (string, error) {
+	var sent message
+	if err := c.call(ctx, "sendMessage", req, &sent); err != nil {
+		return "", err
+	}
+	return PostID(req.ChatID, sent.MessageID), nil
+}
+
+func (c *Client) UpdatePost(ctx context.Context, postID string, a post.Attachment) error {
+	chatID, messageID, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	req := c.card(chatID, a)
+	req.MessageID = messageID
+	err = c.call(ctx, "editMessageText", req, nil)
+	// Re-rendering an unchanged card is not an error for the bridge.
+	if err != nil && strings.Contains(err.Error(), "message is not modified") {
+		return nil
+	}
+	return err
+}
+
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	chatID, messageID, err := splitPostID(postID)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "deleteMessage", map[string]any{"chat_id": chatID, "message_id": messageID}, nil)
+}
+
+func (c *Client) ReplyToThread(ctx context.Context, channelID, rootID, text string) error {
+	_, messageID, err := splitPostID(rootID)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(ctx, sendMessageRequest{
+		ChatID:          channelID,
+		Text:            html(text),
+		ParseMode:       "HTML",
+		ReplyParameters: &replyParameters{MessageID: messageID, AllowSendingWithoutReply: true},
+	})
+	return err
+}
+
+// GetUser returns the username of a chat member, falling back to their
+// name. userID is "{chat}/{user}", as the Telegram updates handler sends it,
+// since the Bot API looks users up in a chat.
+func (c *Client) GetUser(ctx context.Context, userID string) (string, error) {
+	chatID, id, err := splitPostID(userID)
+	if err != nil {
+		return "", err
+	}
+	var member struct {
+		User struct {
+			Username  string `json:"username"`
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+		} `json:"user"`
+	}
+	if err := c.call(ctx, "getChatMember", map[string]any{"chat_id": chatID, "user_id": id}, &member); err != nil {
+		return "", err
+	}
+	if member.User.Username != "" {
+		return member.User.Username, nil
+	}
+	return strings.TrimSpace(member.User.FirstName + " " + member.User.LastName), nil
+}
+
+// ButtonContext returns the callback context of the button whose callback
+// data is data, if the client still remembers it.
+func (c *Client) ButtonContext(data string) (map[string]string, bool) {
+	return c.buttons.get(data)
+}
+
+// AnswerCallback answers a button click, showing text, when set, to the user
+// who clicked. Telegram shows a progress indicator until a click is answered.
+func (c *Client) AnswerCallback(ctx context.Context, callbackQueryID, text string) error {
+	req := map[string]any{"callback_query_id": callbackQueryID}
+	if text != "" {
+		req["text"] = truncate(plain(text), maxAnswerLength)
+		req["show_alert"] = true
+	}
+	return c.call(ctx, "answerCallbackQuery", req, nil)
+}
+
+// GetPost fails: bots cannot read messages back, so deleted cards go
+// unnoticed.
+func (c *Client) GetPost(ctx context.Context, postID string) (port.MattermostPost, error) {
+	return port.MattermostPost{}, fmt.Errorf("read post: %w: %w", port.ErrMattermostPostUnreadable, ErrUnsupported)
+}
+
+// GetReactions returns no reactions: bots only see them as updates.
+func (c *Client) GetReactions(ctx context.Context, postID string) ([]port.Reaction, error) {
+	return nil, nil
+}
+
+// SetChannelPurpose sets the chat description. The bot must be an
+// administrator allowed to change chat info.
+func (c *Client) SetChannelPurpose(ctx context.Context, channelID, purpose string) error {
+	return c.call(ctx, "setChatDescription", map[string]string{"chat_id": channelID, "description": purpose}, nil)
+}
+
+func (c *Client) IsTeamMember(ctx context.Context, teamID, userID string) (bool, error) {
+	return false, fmt.Errorf("team membership: %w", ErrUnsupported)
+}
+
+func (c *Client) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	return false, fmt.Errorf("channel membership: %w", ErrUnsupported)
+}
+
+func (c *Client) GetUserGroups(ctx context.Context, userID string) ([]string, error) {
+	return nil, fmt.Errorf("user groups: %w", ErrUnsupported)
+}
+
+// OpenDialog fails, so Resolve resolves right away.
+func (c *Client) OpenDialog(ctx context.Context, triggerID, submitURL string, dialog port.Dialog) error {
+	return fmt.Errorf("dialogs: %w", ErrUnsupported)
+}
+
+// call posts body as JSON to the Bot API method and decodes its result into
+// out when it is non-nil.
+func (c *Client) call(ctx context.Context, method string, body, out any) error {
+	start := time.Now()
+	defer telegramAPIDur(method).UpdateDuration(start)
+	// The token is part of the request URL, so logs name the method only.
+	logURL := c.baseURL + "/bot<token>/" + method
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal %s body: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(retry.WithOperation(ctx, method), http.MethodPost, c.baseURL+"/bot"+c.token+"/"+method, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		// Errors of the HTTP client quote the URL, token included.
+		reason := strings.ReplaceAll(err.Error(), c.token, "<token>")
+		c.logger.Error("Telegram "+method+" failed",
+			logger.ExternalFieldsWithError("telegram", logURL, http.MethodPost, 0, duration, reason),
+		)
+		telegramAPICalls(method, "error").Inc()
+		return fmt.Errorf("telegram %s: %s", method, reason)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+
+	// The Bot API describes its errors in the body of 4xx responses too.
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var result response
+	if err := json.Unmarshal(respBody, &result); err != nil || !result.OK {
+		reason := result.Description
+		if reason == "" {
+			reason = fmt.Sprintf("status %d, body: %s", resp.StatusCode, truncate(string(respBody), 4096))
+		}
+		c.logger.Error("Telegram "+method+" not ok",
+			logger.ExternalFieldsWithError("telegram", logURL, http.MethodPost, resp.StatusCode, duration, reason),
+		)
+		telegramAPICalls(method, "error").Inc()
+		return fmt.Errorf("telegram %s: %s", method, reason)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.Result, out); err != nil {
+			telegramAPICalls(method, "error").Inc()
+			return fmt.Errorf("decode %s response: %w", method, err)
+		}
+	}
+
+	c.logger.Debug("Telegram "+method+" completed",
+		logger.ExternalFields("telegram", logURL, http.MethodPost, resp.StatusCode, duration),
+	)
+	telegramAPICalls(method, "ok").Inc()
+	return nil
+}
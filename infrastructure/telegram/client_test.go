@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "123:secret", slog.New(slog.NewJSONHandler(io.Discard, nil)))
+}
+
+func TestCreateAndUpdatePost(t *testing.T) {
+	var methods []string
+	var requests []sendMessageRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, ok := strings.CutPrefix(r.URL.Path, "/bot123:secret/")
+		require.True(t, ok, r.URL.Path)
+		methods = append(methods, method)
+		var req sendMessageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+	})
+	ctx := context.Background()
+	card := post.Attachment{
+		Title: "CPU high",
+		Actions: []post.Button{{Name: "Acknowledge", Integration: post.ButtonIntegration{Context: map[string]string{
+			post.ContextKeyAction:      post.ActionAcknowledge,
+			post.ContextKeyFingerprint: "fp-1",
+		}}}},
+	}
+
+	postID, err := client.CreatePost(ctx, "-1001234567890", card)
+	require.NoError(t, err)
+	assert.Equal(t, "-1001234567890/42", postID)
+
+	require.NoError(t, client.UpdatePost(ctx, postID, post.Attachment{Title: "CPU high"}))
+	require.NoError(t, client.ReplyToThread(ctx, "-1001234567890", postID, "Acknowledged by **@jane**"))
+
+	assert.Equal(t, []string{"sendMessage", "editMessageText", "sendMessage"}, methods)
+	assert.Equal(t, "HTML", requests[0].ParseMode)
+	require.NotNil(t, requests[0].ReplyMarkup)
+	data := requests[0].ReplyMarkup.InlineKeyboard[0][0].CallbackData
+	assert.LessOrEqual(t, len(data), 64, "callback data is limited to 64 bytes")
+	buttonContext, ok := client.ButtonContext(data)
+	require.True(t, ok)
+	assert.Equal(t, "fp-1", buttonContext[post.ContextKeyFingerprint])
+	assert.Equal(t, int64(42), requests[1].MessageID)
+	assert.Nil(t, requests[1].ReplyMarkup, "cards without buttons lose their keyboard")
+	require.NotNil(t, requests[2].ReplyParameters)
+	assert.Equal(t, int64(42), requests[2].ReplyParameters.MessageID)
+	assert.Equal(t, "Acknowledged by <b>@jane</b>", requests[2].Text)
+}
+
+func TestGetUser(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "-100123", req["chat_id"])
+		assert.Equal(t, float64(777), req["user_id"])
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"status":"member","user":{"id":777,"username":"jane"}}}`))
+	})
+
+	username, err := client.GetUser(context.Background(), "-100123/777")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", username)
+}
+
+func TestTelegramErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		if strings.HasSuffix(r.URL.Path, "/editMessageText") {
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: message is not modified: specified new message content and reply markup are exactly the same"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	})
+	ctx := context.Background()
+
+	_, err := client.CreatePost(ctx, "-100123", post.Attachment{Title: "CPU high"})
+	require.Error(t, err)
+	assert.Equal(t, "telegram sendMessage: Bad Request: chat not found", err.Error())
+	assert.NotContains(t, err.Error(), "123:secret")
+
+	assert.NoError(t, client.UpdatePost(ctx, "-100123/42", post.Attachment{Title: "CPU high"}), "unchanged cards are not an error")
+
+	err = client.DeletePost(ctx, "-100123")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid telegram post id")
+
+	err = client.OpenDialog(ctx, "trigger", "https://bridge/dialog", port.Dialog{})
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	_, err = client.GetPost(ctx, "-100123/42")
+	assert.True(t, errors.Is(err, ErrUnsupported))
+	assert.True(t, errors.Is(err, port.ErrMattermostPostUnreadable), "the poller skips the deleted-post check")
+}
@@ -0,0 +1,223 @@
+package telegram
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// Bot API limits the bridge runs into with alert cards.
+const (
+	maxMessageLength = 4096
+	maxPartLength    = 1000 // of the text and each field, so a card fits a message
+	buttonsPerRow    = 3
+	// maxButtons bounds the button contexts a client remembers.
+	maxButtons = 20000
+)
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// toMessage renders an alert card as an HTML message and its inline
+// keyboard: the mentions, the title, the text, the fields, the footer and the
+// buttons. Message menus, such as assign and the workflow menu, are left out.
+func toMessage(a post.Attachment, buttons *buttonStore) (string, *inlineKeyboard) {
+	var parts []string
+	if a.Message != "" {
+		parts = append(parts, html(a.Message))
+	}
+	// Messages have no colored bar; a colored dot stands in for it.
+	title := "<b>" + escape(a.Title) + "</b>"
+	if a.TitleLink != "" {
+		title = `<b><a href="` + escape(a.TitleLink) + `">` + escape(a.Title) + "</a></b>"
+	}
+	if dot := colorDot(a.Color); dot != "" {
+		title = dot + " " + title
+	}
+	parts = append(parts, title)
+	if a.Text != "" {
+		parts = append(parts, html(truncate(a.Text, maxPartLength)))
+	}
+
+	var fields []string
+	for _, f := range a.Fields {
+		value := html(truncate(f.Value, maxPartLength))
+		if f.Short {
+			fields = append(fields, "<b>"+escape(f.Title)+":</b> "+value)
+			continue
+		}
+		fields = append(fields, "<b>"+escape(f.Title)+"</b>\n"+value)
+	}
+	if len(fields) > 0 {
+		parts = append(parts, strings.Join(fields, "\n"))
+	}
+	if a.Footer != "" {
+		parts = append(parts, "<i>"+escape(a.Footer)+"</i>")
+	}
+
+	var rows [][]inlineButton
+	for _, b := range a.Actions {
+		if b.Type != "" && b.Type != post.ButtonTypeButton {
+			continue
+		}
+		button := inlineButton{Text: b.Name, CallbackData: buttons.put(b.Integration.Context)}
+		if len(rows) == 0 || len(rows[len(rows)-1]) == buttonsPerRow {
+			rows = append(rows, nil)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], button)
+	}
+
+	text := strings.Join(parts, "\n\n")
+	// Cutting HTML would leave tags open, so an oversized card loses its
+	// fields rather than being cut.
+	if len([]rune(text)) > maxMessageLength {
+		text = title
+	}
+	if len(rows) == 0 {
+		return text, nil
+	}
+	return text, &inlineKeyboard{InlineKeyboard: rows}
+}
+
+// buttonStore remembers the callback contexts of the buttons of posted cards:
+// callback data is limited to 64 bytes, too short for a context, so buttons
+// carry a key derived from their context instead. Contexts are kept in
+// memory, the oldest dropped first.
+type buttonStore struct {
+	mu       sync.Mutex
+	contexts map[string]map[string]string
+	order    []string
+	max      int
+}
+
+func newButtonStore(max int) *buttonStore {
+	return &buttonStore{contexts: make(map[string]map[string]string), max: max}
+}
+
+// put stores context and returns its key. The rendered post the context
+// carries for Mattermost is dropped: messages are edited in place, so it is
+// not needed to restore them.
+func (s *buttonStore) put(context map[string]string) string {
+	trimmed := make(map[string]string, len(context))
+	for k, v := range context {
+		if k != post.ContextKeyAttachmentJSON {
+			trimmed[k] = v
+		}
+	}
+	data, _ := json.Marshal(trimmed) // map keys are sorted, so equal contexts share a key
+	sum := sha256.Sum256(data)
+	key := base64.RawURLEncoding.EncodeToString(sum[:16])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.contexts[key]; ok {
+		return key
+	}
+	s.contexts[key] = trimmed
+	s.order = append(s.order, key)
+	if len(s.order) > s.max {
+		delete(s.contexts, s.order[0])
+		s.order = s.order[1:]
+	}
+	return key
+}
+
+func (s *buttonStore) get(key string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	context, ok := s.contexts[key]
+	return context, ok
+}
+
+// colorDot maps a hex color onto the closest colored circle emoji by hue.
+func colorDot(color string) string {
+	hex := strings.TrimPrefix(color, "#")
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return ""
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	maxC, minC := max(r, g, b), min(r, g, b)
+	if maxC-minC < 32 {
+		return "⚪"
+	}
+	var hue float64
+	switch maxC {
+	case r:
+		hue = 60 * (g - b) / (maxC - minC)
+	case g:
+		hue = 60*(b-r)/(maxC-minC) + 120
+	default:
+		hue = 60*(r-g)/(maxC-minC) + 240
+	}
+	if hue < 0 {
+		hue += 360
+	}
+	switch {
+	case hue < 20 || hue >= 330:
+		return "🔴"
+	case hue < 45:
+		return "🟠"
+	case hue < 70:
+		return "🟡"
+	case hue < 170:
+		return "🟢"
+	case hue < 260:
+		return "🔵"
+	default:
+		return "🟣"
+	}
+}
+
+var (
+	markdownCodeBlock = regexp.MustCompile("(?s)```[a-z]*\n?(.*?)```")
+	markdownCode      = regexp.MustCompile("`([^`\n]+)`")
+	markdownLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold      = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownStrike    = regexp.MustCompile(`~~(.+?)~~`)
+)
+
+// html converts the Markdown of alert cards and answers to Telegram's HTML.
+func html(markdown string) string {
+	text := escape(markdown)
+	text = markdownCodeBlock.ReplaceAllString(text, "<pre>$1</pre>")
+	text = markdownCode.ReplaceAllString(text, "<code>$1</code>")
+	text = markdownLink.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = markdownBold.ReplaceAllString(text, "<b>$1</b>")
+	text = markdownStrike.ReplaceAllString(text, "<s>$1</s>")
+	return text
+}
+
+// plain strips Markdown for callback answers, which show plain text.
+func plain(markdown string) string {
+	text := markdownCodeBlock.ReplaceAllString(markdown, "$1")
+	text = markdownCode.ReplaceAllString(text, "$1")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownBold.ReplaceAllString(text, "$1")
+	text = markdownStrike.ReplaceAllString(text, "$1")
+	return text
+}
+
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(text)
+}
+
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
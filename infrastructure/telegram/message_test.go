@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func TestToMessage(t *testing.T) {
+	buttons := newButtonStore(10)
+	a := post.Attachment{
+		Color:     "#CC0000",
+		Title:     "CPU <high>",
+		TitleLink: "https://keep.example.com/alerts/fp-1",
+		Text:      "**Node** is [overloaded](https://grafana.example.com)",
+		Fields: []post.AttachmentField{
+			{Title: "Severity", Value: "critical", Short: true},
+			{Title: "Description", Value: "long text"},
+		},
+		Footer: "Keep",
+		Actions: []post.Button{
+			{Name: "Acknowledge", Integration: post.ButtonIntegration{Context: map[string]string{post.ContextKeyAction: post.ActionAcknowledge, post.ContextKeyAttachmentJSON: "{}"}}},
+			{Name: "Resolve", Integration: post.ButtonIntegration{Context: map[string]string{post.ContextKeyAction: post.ActionResolve}}},
+			{Name: "Assign", Type: post.ButtonTypeSelect},
+		},
+	}
+
+	text, keyboard := toMessage(a, buttons)
+
+	assert.Equal(t, strings.Join([]string{
+		`🔴 <b><a href="https://keep.example.com/alerts/fp-1">CPU &lt;high&gt;</a></b>`,
+		`<b>Node</b> is <a href="https://grafana.example.com">overloaded</a>`,
+		"<b>Severity:</b> critical\n<b>Description</b>\nlong text",
+		"<i>Keep</i>",
+	}, "\n\n"), text)
+	require.NotNil(t, keyboard)
+	require.Len(t, keyboard.InlineKeyboard, 1)
+	require.Len(t, keyboard.InlineKeyboard[0], 2, "menus are left out")
+	buttonContext, ok := buttons.get(keyboard.InlineKeyboard[0][0].CallbackData)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{post.ContextKeyAction: post.ActionAcknowledge}, buttonContext)
+}
+
+func TestButtonStore(t *testing.T) {
+	buttons := newButtonStore(2)
+	first := buttons.put(map[string]string{"action": "acknowledge"})
+	assert.Equal(t, first, buttons.put(map[string]string{"action": "acknowledge"}), "equal contexts share a key")
+	buttons.put(map[string]string{"action": "resolve"})
+	buttons.put(map[string]string{"action": "unacknowledge"})
+
+	_, ok := buttons.get(first)
+	assert.False(t, ok, "the oldest context is dropped")
+	assert.Len(t, buttons.contexts, 2)
+}
+
+func TestHTMLAndPlain(t *testing.T) {
+	markdown := "Run:\n```\nkubectl get pods\n```\nor `k9s` as **@jane** <3"
+	assert.Equal(t, "Run:\n<pre>kubectl get pods\n</pre>\nor <code>k9s</code> as <b>@jane</b> &lt;3", html(markdown))
+	assert.Equal(t, "Run:\nkubectl get pods\n\nor k9s as @jane <3", plain(markdown))
+}
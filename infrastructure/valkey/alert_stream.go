@@ -0,0 +1,224 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	alertStreamKey   = "kmbridge:ingest:alerts"
+	alertStreamGroup = "kmbridge"
+	alertStreamField = "payload"
+	// alertStreamReceivedField holds when the alert was appended, in Unix
+	// milliseconds. Entries appended by older versions lack it.
+	alertStreamReceivedField = "received_at"
+)
+
+var (
+	redisXAddOK   = metrics.NewCounter(`redis_operations_total{operation="xadd",status="ok"}`)
+	redisXAddErr  = metrics.NewCounter(`redis_operations_total{operation="xadd",status="error"}`)
+	redisXReadOK  = metrics.NewCounter(`redis_operations_total{operation="xreadgroup",status="ok"}`)
+	redisXReadErr = metrics.NewCounter(`redis_operations_total{operation="xreadgroup",status="error"}`)
+
+	alertStreamMalformedCounter = metrics.NewCounter(`alert_stream_malformed_total`)
+)
+
+// AlertStream keeps alert webhooks in a single Valkey stream read by the
+// "kmbridge" consumer group, so every bridge replica shares one backlog. The
+// group is created on first read and starts at the beginning of the stream,
+// so alerts appended before any consumer ran are not lost.
+type AlertStream struct {
+	client *redis.Client
+	maxLen int64
+	logger *slog.Logger
+}
+
+// NewAlertStream returns a stream trimmed to roughly maxLen entries; zero
+// keeps every entry.
+func NewAlertStream(client *redis.Client, maxLen int64, logger *slog.Logger) *AlertStream {
+	return &AlertStream{
+		client: client,
+		maxLen: maxLen,
+		logger: logger,
+	}
+}
+
+func (s *AlertStream) Append(ctx context.Context, input dto.KeepAlertInput) error {
+	start := time.Now()
+
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	err = s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: alertStreamKey,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]any{
+			alertStreamField:         payload,
+			alertStreamReceivedField: start.UnixMilli(),
+		},
+	}).Err()
+	duration := time.Since(start).Milliseconds()
+	if err != nil {
+		s.logger.Error("Redis XADD failed",
+			logger.RedisFieldsWithError("xadd", alertStreamKey, duration, err.Error()),
+		)
+		redisXAddErr.Inc()
+		return fmt.Errorf("redis xadd: %w", err)
+	}
+
+	redisXAddOK.Inc()
+	s.logger.Debug("Redis XADD succeeded",
+		logger.RedisFields("xadd", alertStreamKey, duration),
+	)
+	return nil
+}
+
+func (s *AlertStream) Read(ctx context.Context, consumer string, count int, block time.Duration) ([]port.StreamedAlert, error) {
+	start := time.Now()
+	var streams []redis.XStream
+	err := s.withGroup(ctx, func() error {
+		var err error
+		streams, err = s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    alertStreamGroup,
+			Consumer: consumer,
+			Streams:  []string{alertStreamKey, ">"},
+			Count:    int64(count),
+			Block:    block,
+		}).Result()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("Redis XREADGROUP failed",
+			logger.RedisFieldsWithError("xreadgroup", alertStreamKey, time.Since(start).Milliseconds(), err.Error()),
+		)
+		redisXReadErr.Inc()
+		return nil, fmt.Errorf("redis xreadgroup: %w", err)
+	}
+	redisXReadOK.Inc()
+
+	var alerts []port.StreamedAlert
+	for _, stream := range streams {
+		alerts = append(alerts, s.decode(ctx, stream.Messages, nil)...)
+	}
+	return alerts, nil
+}
+
+func (s *AlertStream) Claim(ctx context.Context, consumer string, minIdle time.Duration, count int) ([]port.StreamedAlert, error) {
+	var pending []redis.XPendingExt
+	err := s.withGroup(ctx, func() error {
+		var err error
+		pending, err = s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: alertStreamKey,
+			Group:  alertStreamGroup,
+			Idle:   minIdle,
+			Start:  "-",
+			End:    "+",
+			Count:  int64(count),
+		}).Result()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis xpending: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(pending))
+	deliveries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+		deliveries[p.ID] = p.RetryCount + 1
+	}
+
+	messages, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   alertStreamKey,
+		Group:    alertStreamGroup,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis xclaim: %w", err)
+	}
+	return s.decode(ctx, messages, deliveries), nil
+}
+
+func (s *AlertStream) Ack(ctx context.Context, id string) error {
+	if err := s.client.XAck(ctx, alertStreamKey, alertStreamGroup, id).Err(); err != nil {
+		return fmt.Errorf("redis xack: %w", err)
+	}
+	return nil
+}
+
+// decode turns stream messages into alerts. Messages that cannot be decoded
+// would fail on every delivery, so they are acknowledged and skipped.
+func (s *AlertStream) decode(ctx context.Context, messages []redis.XMessage, deliveries map[string]int64) []port.StreamedAlert {
+	alerts := make([]port.StreamedAlert, 0, len(messages))
+	for _, msg := range messages {
+		var input dto.KeepAlertInput
+		payload, ok := msg.Values[alertStreamField].(string)
+		if !ok || json.Unmarshal([]byte(payload), &input) != nil {
+			alertStreamMalformedCounter.Inc()
+			s.logger.Error("Skipping malformed alert stream entry", slog.String("id", msg.ID))
+			if err := s.Ack(ctx, msg.ID); err != nil {
+				s.logger.Error("Failed to acknowledge malformed alert stream entry",
+					slog.String("id", msg.ID),
+					slog.String("error", err.Error()),
+				)
+			}
+			continue
+		}
+
+		delivered := int64(1)
+		if n, ok := deliveries[msg.ID]; ok {
+			delivered = n
+		}
+		var receivedAt time.Time
+		if s, ok := msg.Values[alertStreamReceivedField].(string); ok {
+			if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+				receivedAt = time.UnixMilli(ms)
+			}
+		}
+		alerts = append(alerts, port.StreamedAlert{ID: msg.ID, Input: input, Deliveries: delivered, ReceivedAt: receivedAt})
+	}
+	return alerts
+}
+
+// withGroup runs fn and, if the consumer group does not exist yet, creates it
+// and runs fn once more.
+func (s *AlertStream) withGroup(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || !strings.HasPrefix(err.Error(), "NOGROUP") {
+		return err
+	}
+
+	err = s.client.XGroupCreateMkStream(ctx, alertStreamKey, alertStreamGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("redis xgroup create: %w", err)
+	}
+	s.logger.Info("Created alert stream consumer group",
+		slog.String("stream", alertStreamKey),
+		slog.String("group", alertStreamGroup),
+	)
+	return fn()
+}
@@ -0,0 +1,111 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+func setupTestAlertStream(t *testing.T) (*AlertStream, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewAlertStream(client, 1000, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestAlertStreamAppendAndRead(t *testing.T) {
+	stream, _ := setupTestAlertStream(t)
+	ctx := context.Background()
+	before := time.Now().Truncate(time.Millisecond)
+
+	require.NoError(t, stream.Append(ctx, dto.KeepAlertInput{Fingerprint: "fp-1", Name: "DiskFull", Status: "firing"}))
+	require.NoError(t, stream.Append(ctx, dto.KeepAlertInput{Fingerprint: "fp-2", Name: "HighCPU", Status: "firing"}))
+
+	alerts, err := stream.Read(ctx, "bridge-1", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "fp-1", alerts[0].Input.Fingerprint)
+	assert.Equal(t, "DiskFull", alerts[0].Input.Name)
+	assert.Equal(t, int64(1), alerts[0].Deliveries)
+	assert.False(t, alerts[0].ReceivedAt.Before(before), "append time is kept")
+	assert.Equal(t, "fp-2", alerts[1].Input.Fingerprint)
+
+	alerts, err = stream.Read(ctx, "bridge-2", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, alerts, "entries are handed to one consumer only")
+}
+
+func TestAlertStreamReadEmpty(t *testing.T) {
+	stream, _ := setupTestAlertStream(t)
+
+	alerts, err := stream.Read(context.Background(), "bridge-1", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestAlertStreamClaimPendingAlerts(t *testing.T) {
+	stream, _ := setupTestAlertStream(t)
+	ctx := context.Background()
+
+	require.NoError(t, stream.Append(ctx, dto.KeepAlertInput{Fingerprint: "fp-1"}))
+	require.NoError(t, stream.Append(ctx, dto.KeepAlertInput{Fingerprint: "fp-2"}))
+	read, err := stream.Read(ctx, "bridge-1", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	require.NoError(t, stream.Ack(ctx, read[0].ID))
+
+	claimed, err := stream.Claim(ctx, "bridge-2", time.Hour, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "entries are not idle long enough")
+
+	claimed, err = stream.Claim(ctx, "bridge-2", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "acknowledged entries are not claimed")
+	assert.Equal(t, read[1].ID, claimed[0].ID)
+	assert.Equal(t, "fp-2", claimed[0].Input.Fingerprint)
+	assert.Equal(t, int64(2), claimed[0].Deliveries)
+
+	require.NoError(t, stream.Ack(ctx, claimed[0].ID))
+	claimed, err = stream.Claim(ctx, "bridge-2", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
+
+func TestAlertStreamSkipsMalformedEntries(t *testing.T) {
+	stream, mr := setupTestAlertStream(t)
+	ctx := context.Background()
+
+	_, err := mr.XAdd(alertStreamKey, "*", []string{alertStreamField, "not json"})
+	require.NoError(t, err)
+	require.NoError(t, stream.Append(ctx, dto.KeepAlertInput{Fingerprint: "fp-1"}))
+
+	alerts, err := stream.Read(ctx, "bridge-1", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "fp-1", alerts[0].Input.Fingerprint)
+
+	claimed, err := stream.Claim(ctx, "bridge-1", 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1, "the malformed entry was acknowledged")
+}
+
+func TestAlertStreamCreatesGroupBeforeRead(t *testing.T) {
+	stream, mr := setupTestAlertStream(t)
+	ctx := context.Background()
+
+	claimed, err := stream.Claim(ctx, "bridge-1", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+	assert.True(t, mr.Exists(alertStreamKey))
+}
@@ -0,0 +1,192 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	auditKeyPrefix = "kmbridge:audit:"
+	// auditIndexKey scores each fingerprint by the time of its latest event,
+	// so FindBetween only reads the histories of alerts active since from.
+	auditIndexKey = "kmbridge:audit-index"
+)
+
+type auditData struct {
+	Kind   string    `json:"kind"`
+	Actor  string    `json:"actor,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// AuditRepository keeps the history of each alert in a list of its own. Only
+// the latest maxEvents events are kept, and a history expires ttl after its
+// last event. An index of the alerts with recent events serves queries
+// across alerts.
+type AuditRepository struct {
+	client    *redis.Client
+	maxEvents int
+	ttl       time.Duration
+	logger    *slog.Logger
+}
+
+func NewAuditRepository(client *redis.Client, maxEvents int, ttl time.Duration, logger *slog.Logger) *AuditRepository {
+	return &AuditRepository{
+		client:    client,
+		maxEvents: maxEvents,
+		ttl:       ttl,
+		logger:    logger,
+	}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, e *audit.Event) error {
+	start := time.Now()
+	key := auditKeyPrefix + e.Fingerprint().Value()
+
+	jsonData, err := json.Marshal(auditData{
+		Kind:   e.Kind(),
+		Actor:  e.Actor(),
+		Detail: e.Detail(),
+		At:     e.At(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal audit data: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, jsonData)
+		pipe.LTrim(ctx, key, int64(-r.maxEvents), -1)
+		pipe.Expire(ctx, key, r.ttl)
+		pipe.ZAdd(ctx, auditIndexKey, redis.Z{Score: float64(e.At().Unix()), Member: e.Fingerprint().Value()})
+		pipe.ZRemRangeByScore(ctx, auditIndexKey, "-inf", strconv.FormatInt(e.At().Add(-r.ttl).Unix(), 10))
+		return nil
+	})
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", key, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis rpush: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", key, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *AuditRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) ([]*audit.Event, error) {
+	start := time.Now()
+	key := auditKeyPrefix + fingerprint.Value()
+
+	results, err := r.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", key, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis lrange: %w", err)
+	}
+
+	events := r.decodeEvents(fingerprint, results)
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", key, duration),
+		slog.Int("count", len(events)),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	return events, nil
+}
+
+// FindBetween reads the history of every alert with an event since from.
+// Alerts whose last event was recorded before the index existed are not
+// found.
+func (r *AuditRepository) FindBetween(ctx context.Context, from, to time.Time) ([]*audit.Event, error) {
+	start := time.Now()
+
+	fingerprints, err := r.client.ZRangeByScore(ctx, auditIndexKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(from.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", auditIndexKey, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis zrangebyscore: %w", err)
+	}
+
+	cmds := make([]*redis.StringSliceCmd, len(fingerprints))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, fp := range fingerprints {
+			cmds[i] = pipe.LRange(ctx, auditKeyPrefix+fp, 0, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", auditKeyPrefix+"*", duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis lrange: %w", err)
+	}
+
+	var events []*audit.Event
+	for i, fp := range fingerprints {
+		for _, e := range r.decodeEvents(alert.RestoreFingerprint(fp), cmds[i].Val()) {
+			if !e.At().Before(from) && e.At().Before(to) {
+				events = append(events, e)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At().Before(events[j].At()) })
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", auditIndexKey, duration),
+		slog.Int("alerts", len(fingerprints)),
+		slog.Int("count", len(events)),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	return events, nil
+}
+
+func (r *AuditRepository) decodeEvents(fingerprint alert.Fingerprint, results []string) []*audit.Event {
+	events := make([]*audit.Event, 0, len(results))
+	for _, result := range results {
+		var d auditData
+		if err := json.Unmarshal([]byte(result), &d); err != nil {
+			r.logger.Warn("Failed to unmarshal audit event, skipping it",
+				slog.String("fingerprint", fingerprint.Value()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		events = append(events, audit.NewEvent(fingerprint, d.Kind, d.Actor, d.Detail, d.At))
+	}
+	return events
+}
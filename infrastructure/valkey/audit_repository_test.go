@@ -0,0 +1,98 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+)
+
+func setupTestAuditRepository(t *testing.T, maxEvents int) (*AuditRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewAuditRepository(client, maxEvents, 24*time.Hour, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestAuditAppendAndFind(t *testing.T) {
+	repo, mr := setupTestAuditRepository(t, 3)
+	ctx := context.Background()
+	fp := alert.RestoreFingerprint("fp-1")
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	events, err := repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	for i, kind := range []string{audit.KindReceived, audit.KindPosted, audit.KindAcknowledged, audit.KindResolved} {
+		actor := ""
+		if kind == audit.KindAcknowledged {
+			actor = "alice"
+		}
+		require.NoError(t, repo.Append(ctx, audit.NewEvent(fp, kind, actor, "detail", at.Add(time.Duration(i)*time.Minute))))
+	}
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-2"), audit.KindReceived, "", "", at)))
+
+	events, err = repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	require.Len(t, events, 3, "only the latest events are kept")
+	assert.Equal(t, audit.KindPosted, events[0].Kind())
+	assert.Equal(t, audit.KindAcknowledged, events[1].Kind())
+	assert.Equal(t, "alice", events[1].Actor())
+	assert.True(t, at.Add(2*time.Minute).Equal(events[1].At()))
+	assert.Equal(t, audit.KindResolved, events[2].Kind())
+	assert.Equal(t, "fp-1", events[2].Fingerprint().Value())
+
+	assert.Equal(t, 24*time.Hour, mr.TTL(auditKeyPrefix+"fp-1"))
+}
+
+func TestAuditFindBetween(t *testing.T) {
+	repo, _ := setupTestAuditRepository(t, 10)
+	ctx := context.Background()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-old"), audit.KindReceived, "", "", at.Add(-2*time.Hour))))
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-1"), audit.KindReceived, "", "", at.Add(-time.Minute))))
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-1"), audit.KindPosted, "", "", at.Add(2*time.Minute))))
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-2"), audit.KindReceived, "", "", at.Add(time.Minute))))
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(alert.RestoreFingerprint("fp-2"), audit.KindResolved, "", "", at.Add(time.Hour))))
+
+	events, err := repo.FindBetween(ctx, at, at.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "fp-2", events[0].Fingerprint().Value())
+	assert.Equal(t, audit.KindReceived, events[0].Kind())
+	assert.Equal(t, "fp-1", events[1].Fingerprint().Value())
+	assert.Equal(t, audit.KindPosted, events[1].Kind())
+
+	events, err = repo.FindBetween(ctx, at.Add(-3*time.Hour), at)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "fp-old", events[0].Fingerprint().Value())
+}
+
+func TestAuditFindSkipsBrokenEvents(t *testing.T) {
+	repo, mr := setupTestAuditRepository(t, 10)
+	ctx := context.Background()
+	fp := alert.RestoreFingerprint("fp-1")
+
+	_, err := mr.Push(auditKeyPrefix+"fp-1", "{broken")
+	require.NoError(t, err)
+	require.NoError(t, repo.Append(ctx, audit.NewEvent(fp, audit.KindReceived, "", "firing", time.Now())))
+
+	events, err := repo.FindByFingerprint(ctx, fp)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "firing", events[0].Detail())
+}
@@ -0,0 +1,46 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const channelIDKeyPrefix = "kmbridge:channel_id:"
+
+// ChannelIDCache keeps the ID of each channel routed to by name in plain
+// Valkey keys, so every replica and restart reuses the lookups.
+type ChannelIDCache struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewChannelIDCache(client *redis.Client, logger *slog.Logger) *ChannelIDCache {
+	return &ChannelIDCache{client: client, logger: logger}
+}
+
+func (c *ChannelIDCache) GetChannelID(ctx context.Context, teamID, name string) (string, bool, error) {
+	channelID, err := c.client.Get(ctx, channelIDKey(teamID, name)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("redis get: %w", err)
+	}
+	return channelID, true, nil
+}
+
+func (c *ChannelIDCache) SetChannelID(ctx context.Context, teamID, name, channelID string, ttl time.Duration) error {
+	if err := c.client.Set(ctx, channelIDKey(teamID, name), channelID, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+func channelIDKey(teamID, name string) string {
+	return channelIDKeyPrefix + teamID + ":" + name
+}
@@ -0,0 +1,41 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelIDCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cache := NewChannelIDCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	_, ok, err := cache.GetChannelID(ctx, "team-1", "alerts-prod")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, cache.SetChannelID(ctx, "team-1", "alerts-prod", "channel-1", time.Hour))
+	assert.Equal(t, time.Hour, mr.TTL(channelIDKeyPrefix+"team-1:alerts-prod"))
+
+	channelID, ok, err := cache.GetChannelID(ctx, "team-1", "alerts-prod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "channel-1", channelID)
+
+	_, ok, err = cache.GetChannelID(ctx, "team-2", "alerts-prod")
+	require.NoError(t, err)
+	assert.False(t, ok, "names are per team")
+
+	mr.FastForward(time.Hour)
+	_, ok, err = cache.GetChannelID(ctx, "team-1", "alerts-prod")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package valkey
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/audit"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+)
+
+// Compile-time contracts: the repositories are wired into use cases through
+// the domain interfaces, and the alert stream, locks, user email cache and
+// status board store through their ports.
+var (
+	_ post.Repository          = (*PostRepository)(nil)
+	_ post.PostIDFinder        = (*PostRepository)(nil)
+	_ group.Repository         = (*GroupRepository)(nil)
+	_ correlation.Repository   = (*CorrelationRepository)(nil)
+	_ retention.Repository     = (*RetentionRepository)(nil)
+	_ deadletter.Repository    = (*DeadLetterRepository)(nil)
+	_ audit.Repository         = (*AuditRepository)(nil)
+	_ incident.Repository      = (*IncidentRepository)(nil)
+	_ quiethours.Repository    = (*QuietHoursRepository)(nil)
+	_ pendingaction.Repository = (*PendingActionRepository)(nil)
+	_ port.AlertStream         = (*AlertStream)(nil)
+	_ port.Locker              = (*Locks)(nil)
+	_ port.IdempotencyStore    = (*Locks)(nil)
+	_ port.UserEmailCache      = (*UserEmailCache)(nil)
+	_ port.StatusBoardStore    = (*StatusBoardStore)(nil)
+)
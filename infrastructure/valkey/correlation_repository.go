@@ -0,0 +1,164 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	correlationKeyPrefix   = "kmbridge:correlation:"
+	correlationIndexPrefix = "kmbridge:correlation-id:"
+)
+
+type correlationData struct {
+	Fingerprint string    `json:"fingerprint"`
+	PostIDs     []string  `json:"post_ids,omitempty"`
+	IncidentID  string    `json:"incident_id,omitempty"`
+	TicketKey   string    `json:"ticket_key,omitempty"`
+	TicketURL   string    `json:"ticket_url,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CorrelationRepository stores each record under its fingerprint and one
+// index key per other identifier pointing back to the fingerprint. Index
+// keys left behind when an identifier changes are ignored on lookup.
+type CorrelationRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewCorrelationRepository(client *redis.Client, logger *slog.Logger) *CorrelationRepository {
+	return &CorrelationRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *CorrelationRepository) Save(ctx context.Context, rec *correlation.Record) error {
+	fingerprint := rec.Fingerprint().Value()
+	key := correlationKeyPrefix + fingerprint
+	start := time.Now()
+
+	data := correlationData{
+		Fingerprint: fingerprint,
+		PostIDs:     rec.PostIDs(),
+		IncidentID:  rec.IncidentID(),
+		TicketKey:   rec.TicketKey(),
+		TicketURL:   rec.TicketURL(),
+		UpdatedAt:   rec.UpdatedAt(),
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal correlation data: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, jsonData, ttl)
+		for _, id := range rec.IDs() {
+			if id != fingerprint {
+				pipe.Set(ctx, correlationIndexPrefix+id, fingerprint, ttl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", key, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", key, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *CorrelationRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*correlation.Record, error) {
+	result, err := r.get(ctx, correlationKeyPrefix+fingerprint.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	var data correlationData
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return nil, fmt.Errorf("unmarshal correlation data: %w", err)
+	}
+
+	return correlation.RestoreRecord(
+		alert.RestoreFingerprint(data.Fingerprint),
+		data.PostIDs,
+		data.IncidentID,
+		data.TicketKey,
+		data.TicketURL,
+		data.UpdatedAt,
+	), nil
+}
+
+func (r *CorrelationRepository) FindByID(ctx context.Context, id string) (*correlation.Record, error) {
+	rec, err := r.FindByFingerprint(ctx, alert.RestoreFingerprint(id))
+	if !errors.Is(err, correlation.ErrNotFound) {
+		return rec, err
+	}
+
+	fingerprint, err := r.get(ctx, correlationIndexPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err = r.FindByFingerprint(ctx, alert.RestoreFingerprint(fingerprint))
+	if err != nil {
+		return nil, err
+	}
+	if !rec.Has(id) {
+		return nil, correlation.ErrNotFound
+	}
+	return rec, nil
+}
+
+func (r *CorrelationRepository) get(ctx context.Context, key string) (string, error) {
+	start := time.Now()
+
+	result, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		if errors.Is(err, redis.Nil) {
+			r.logger.Debug("Redis GET miss",
+				logger.RedisFields("get", key, duration),
+			)
+			redisGetMiss.Inc()
+			return "", correlation.ErrNotFound
+		}
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", key, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return "", fmt.Errorf("redis get: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", key, duration),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+)
+
+func setupTestCorrelationRepository(t *testing.T) (*CorrelationRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewCorrelationRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestCorrelationSaveAndFind(t *testing.T) {
+	repo, mr := setupTestCorrelationRepository(t)
+	ctx := context.Background()
+
+	rec := correlation.NewRecord(alert.RestoreFingerprint("fp-1"), time.Now())
+	rec.AddPostID("post-1", time.Now())
+	rec.AddPostID("post-2", time.Now())
+	rec.SetIncident("inc-1", time.Now())
+	rec.SetTicket("OPS-42", "https://jira.example.com/browse/OPS-42", time.Now())
+
+	require.NoError(t, repo.Save(ctx, rec))
+	assert.Greater(t, mr.TTL("kmbridge:correlation:fp-1"), time.Duration(0))
+	assert.Greater(t, mr.TTL("kmbridge:correlation-id:OPS-42"), time.Duration(0))
+
+	for _, id := range []string{"fp-1", "post-1", "post-2", "inc-1", "OPS-42"} {
+		found, err := repo.FindByID(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "fp-1", found.Fingerprint().Value())
+		assert.Equal(t, []string{"post-1", "post-2"}, found.PostIDs())
+		assert.Equal(t, "inc-1", found.IncidentID())
+		assert.Equal(t, "OPS-42", found.TicketKey())
+		assert.Equal(t, "https://jira.example.com/browse/OPS-42", found.TicketURL())
+	}
+}
+
+func TestCorrelationFindNotFound(t *testing.T) {
+	repo, _ := setupTestCorrelationRepository(t)
+	ctx := context.Background()
+
+	_, err := repo.FindByFingerprint(ctx, alert.RestoreFingerprint("missing"))
+	assert.ErrorIs(t, err, correlation.ErrNotFound)
+	_, err = repo.FindByID(ctx, "missing")
+	assert.ErrorIs(t, err, correlation.ErrNotFound)
+}
+
+func TestCorrelationFindByIDIgnoresStaleIndex(t *testing.T) {
+	repo, _ := setupTestCorrelationRepository(t)
+	ctx := context.Background()
+
+	rec := correlation.NewRecord(alert.RestoreFingerprint("fp-1"), time.Now())
+	rec.SetIncident("inc-1", time.Now())
+	require.NoError(t, repo.Save(ctx, rec))
+	rec.SetIncident("inc-2", time.Now())
+	require.NoError(t, repo.Save(ctx, rec))
+
+	_, err := repo.FindByID(ctx, "inc-1")
+	assert.ErrorIs(t, err, correlation.ErrNotFound)
+	found, err := repo.FindByID(ctx, "inc-2")
+	require.NoError(t, err)
+	assert.Equal(t, "fp-1", found.Fingerprint().Value())
+}
@@ -0,0 +1,215 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const deadLetterEntriesKey = "kmbridge:deadletter:entries"
+
+type deadLetterData struct {
+	Kind          string    `json:"kind"`
+	Key           string    `json:"key"`
+	Payload       string    `json:"payload,omitempty"`
+	Attachment    string    `json:"attachment,omitempty"`
+	LastError     string    `json:"last_error"`
+	Attempts      int       `json:"attempts"`
+	FailedAt      time.Time `json:"failed_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// DeadLetterRepository keeps entries in a single hash keyed by entry ID.
+// Entries do not expire: they are removed once re-delivered or deleted
+// through the admin API.
+type DeadLetterRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewDeadLetterRepository(client *redis.Client, logger *slog.Logger) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *DeadLetterRepository) Save(ctx context.Context, e *deadletter.Entry) error {
+	start := time.Now()
+
+	data := deadLetterData{
+		Kind:          e.Kind(),
+		Key:           e.Key(),
+		Payload:       string(e.Payload()),
+		LastError:     e.LastError(),
+		Attempts:      e.Attempts(),
+		FailedAt:      e.FailedAt(),
+		LastAttemptAt: e.LastAttemptAt(),
+	}
+	if e.Kind() == deadletter.KindUpdate {
+		attachment := e.Attachment()
+		attachmentJSON, err := attachment.ToJSON()
+		if err != nil {
+			return fmt.Errorf("marshal attachment: %w", err)
+		}
+		data.Attachment = attachmentJSON
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal dead-letter data: %w", err)
+	}
+
+	if err := r.client.HSet(ctx, deadLetterEntriesKey, e.ID(), jsonData).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", deadLetterEntriesKey, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis hset: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", deadLetterEntriesKey, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *DeadLetterRepository) FindByID(ctx context.Context, id string) (*deadletter.Entry, error) {
+	start := time.Now()
+
+	result, err := r.client.HGet(ctx, deadLetterEntriesKey, id).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		if errors.Is(err, redis.Nil) {
+			r.logger.Debug("Redis GET miss",
+				logger.RedisFields("get", deadLetterEntriesKey, duration),
+			)
+			redisGetMiss.Inc()
+			return nil, deadletter.ErrNotFound
+		}
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", deadLetterEntriesKey, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis hget: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", deadLetterEntriesKey, duration),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	entry, err := toDeadLetterEntry(result)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal dead-letter data: %w", err)
+	}
+	return entry, nil
+}
+
+func (r *DeadLetterRepository) FindAll(ctx context.Context) ([]*deadletter.Entry, error) {
+	start := time.Now()
+
+	results, err := r.client.HGetAll(ctx, deadLetterEntriesKey).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis HGETALL failed",
+			logger.RedisFieldsWithError("scan", deadLetterEntriesKey, duration, err.Error()),
+		)
+		redisScanErr.Inc()
+		return nil, fmt.Errorf("redis hgetall: %w", err)
+	}
+
+	entries := make([]*deadletter.Entry, 0, len(results))
+	for id, result := range results {
+		entry, err := toDeadLetterEntry(result)
+		if err != nil {
+			r.logger.Warn("Failed to unmarshal dead-letter entry, dropping it",
+				slog.String("id", id),
+				slog.String("error", err.Error()),
+			)
+			if err := r.Delete(ctx, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].FailedAt().Before(entries[j].FailedAt()) })
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis HGETALL completed",
+		logger.RedisFields("scan", deadLetterEntriesKey, duration),
+		slog.Int("count", len(entries)),
+	)
+	redisScanOK.Inc()
+	redisScanDur.Update(float64(duration) / 1000)
+
+	return entries, nil
+}
+
+func toDeadLetterEntry(data string) (*deadletter.Entry, error) {
+	var d deadLetterData
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return nil, err
+	}
+	var attachment post.Attachment
+	if d.Attachment != "" {
+		a, err := post.AttachmentFromJSON(d.Attachment)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal attachment: %w", err)
+		}
+		attachment = *a
+	}
+	var payload []byte
+	if d.Payload != "" {
+		payload = []byte(d.Payload)
+	}
+	return deadletter.RestoreEntry(
+		d.Kind,
+		d.Key,
+		payload,
+		attachment,
+		d.LastError,
+		d.Attempts,
+		d.FailedAt,
+		d.LastAttemptAt,
+	), nil
+}
+
+func (r *DeadLetterRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+
+	if err := r.client.HDel(ctx, deadLetterEntriesKey, id).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", deadLetterEntriesKey, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis hdel: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", deadLetterEntriesKey, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+func setupTestDeadLetterRepository(t *testing.T) (*DeadLetterRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewDeadLetterRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestDeadLetterSaveAndFind(t *testing.T) {
+	repo, _ := setupTestDeadLetterRepository(t)
+	ctx := context.Background()
+	failedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	update := deadletter.NewUpdateEntry("post-1", post.Attachment{Title: "✅ Disk full"}, "status 502", failedAt.Add(time.Minute))
+	alertEntry := deadletter.NewAlertEntry("fp-1", []byte(`{"fingerprint":"fp-1"}`), "status 503", failedAt)
+	require.NoError(t, repo.Save(ctx, update))
+	require.NoError(t, repo.Save(ctx, alertEntry))
+
+	entries, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "alert:fp-1", entries[0].ID(), "oldest failure first")
+	assert.Equal(t, `{"fingerprint":"fp-1"}`, string(entries[0].Payload()))
+	assert.Equal(t, "update:post-1", entries[1].ID())
+	assert.Equal(t, "✅ Disk full", entries[1].Attachment().Title)
+	assert.Equal(t, "status 502", entries[1].LastError())
+
+	update.RecordFailure("status 500", failedAt.Add(2*time.Minute))
+	require.NoError(t, repo.Save(ctx, update))
+	found, err := repo.FindByID(ctx, "update:post-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Attempts())
+	assert.Equal(t, "status 500", found.LastError())
+	assert.True(t, failedAt.Add(time.Minute).Equal(found.FailedAt()))
+	assert.True(t, failedAt.Add(2*time.Minute).Equal(found.LastAttemptAt()))
+}
+
+func TestDeadLetterDeleteAndNotFound(t *testing.T) {
+	repo, mr := setupTestDeadLetterRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, deadletter.NewAlertEntry("fp-1", []byte("{}"), "boom", time.Now())))
+	require.NoError(t, repo.Delete(ctx, "alert:fp-1"))
+
+	_, err := repo.FindByID(ctx, "alert:fp-1")
+	assert.ErrorIs(t, err, deadletter.ErrNotFound)
+	assert.False(t, mr.Exists(deadLetterEntriesKey))
+}
+
+func TestDeadLetterFindAllDropsBrokenEntries(t *testing.T) {
+	repo, mr := setupTestDeadLetterRepository(t)
+	ctx := context.Background()
+
+	mr.HSet(deadLetterEntriesKey, "update:post-broken", "not json")
+
+	entries, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.False(t, mr.Exists(deadLetterEntriesKey))
+}
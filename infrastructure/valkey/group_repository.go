@@ -0,0 +1,157 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const groupKeyPrefix = "kmbridge:group:"
+
+type groupData struct {
+	Key         string            `json:"key"`
+	Value       string            `json:"value"`
+	ChannelID   string            `json:"channel_id"`
+	RootPostID  string            `json:"root_post_id"`
+	Members     map[string]string `json:"members"`
+	Total       int               `json:"total"`
+	CreatedAt   time.Time         `json:"created_at"`
+	LastUpdated time.Time         `json:"last_updated"`
+}
+
+type GroupRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewGroupRepository(client *redis.Client, logger *slog.Logger) *GroupRepository {
+	return &GroupRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *GroupRepository) Save(ctx context.Context, g *group.Group) error {
+	key := groupKeyPrefix + g.ID()
+	start := time.Now()
+
+	members := make(map[string]string, g.ActiveCount())
+	for fp, sev := range g.Members() {
+		members[fp] = sev.String()
+	}
+
+	data := groupData{
+		Key:         g.Key(),
+		Value:       g.Value(),
+		ChannelID:   g.ChannelID(),
+		RootPostID:  g.RootPostID(),
+		Members:     members,
+		Total:       g.Total(),
+		CreatedAt:   g.CreatedAt(),
+		LastUpdated: g.LastUpdated(),
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal group data: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, jsonData, ttl).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", key, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", key, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *GroupRepository) FindByID(ctx context.Context, id string) (*group.Group, error) {
+	key := groupKeyPrefix + id
+	start := time.Now()
+
+	result, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		if errors.Is(err, redis.Nil) {
+			r.logger.Debug("Redis GET miss",
+				logger.RedisFields("get", key, duration),
+			)
+			redisGetMiss.Inc()
+			return nil, group.ErrNotFound
+		}
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", key, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var data groupData
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return nil, fmt.Errorf("unmarshal group data: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", key, duration),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	members := make(map[string]alert.Severity, len(data.Members))
+	for fp, sev := range data.Members {
+		members[fp] = alert.RestoreSeverity(sev)
+	}
+
+	return group.RestoreGroup(
+		data.Key,
+		data.Value,
+		data.ChannelID,
+		data.RootPostID,
+		members,
+		data.Total,
+		data.CreatedAt,
+		data.LastUpdated,
+	), nil
+}
+
+func (r *GroupRepository) Delete(ctx context.Context, id string) error {
+	key := groupKeyPrefix + id
+	start := time.Now()
+
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", key, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", key, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+)
+
+func setupTestGroupRepository(t *testing.T) (*GroupRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewGroupRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestGroupSaveAndFindByID(t *testing.T) {
+	repo, mr := setupTestGroupRepository(t)
+	ctx := context.Background()
+
+	g := group.NewGroup("alertgroup", "database", "channel-1", "root-1", time.Now())
+	g.Add("fp-1", alert.RestoreSeverity("critical"), time.Now())
+	g.Add("fp-2", alert.RestoreSeverity("warning"), time.Now())
+	g.Remove("fp-2", time.Now())
+
+	require.NoError(t, repo.Save(ctx, g))
+	assert.True(t, mr.Exists("kmbridge:group:channel-1:alertgroup=database"))
+	assert.Greater(t, mr.TTL("kmbridge:group:channel-1:alertgroup=database"), time.Duration(0))
+
+	found, err := repo.FindByID(ctx, g.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "alertgroup", found.Key())
+	assert.Equal(t, "database", found.Value())
+	assert.Equal(t, "channel-1", found.ChannelID())
+	assert.Equal(t, "root-1", found.RootPostID())
+	assert.Equal(t, []string{"fp-1"}, found.Fingerprints())
+	assert.Equal(t, 2, found.Total())
+	assert.Equal(t, "critical", found.HighestSeverity().String())
+}
+
+func TestGroupFindByIDNotFound(t *testing.T) {
+	repo, _ := setupTestGroupRepository(t)
+
+	_, err := repo.FindByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, group.ErrNotFound)
+}
+
+func TestGroupDelete(t *testing.T) {
+	repo, _ := setupTestGroupRepository(t)
+	ctx := context.Background()
+
+	g := group.NewGroup("service", "api", "channel-1", "root-1", time.Now())
+	require.NoError(t, repo.Save(ctx, g))
+	require.NoError(t, repo.Delete(ctx, g.ID()))
+
+	_, err := repo.FindByID(ctx, g.ID())
+	assert.ErrorIs(t, err, group.ErrNotFound)
+}
+
+func TestGroupKeysDoNotLeakIntoFindAllActive(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	groups := NewGroupRepository(client, logger)
+	posts := NewPostRepository(client, logger)
+
+	require.NoError(t, groups.Save(context.Background(), group.NewGroup("service", "api", "channel-1", "root-1", time.Now())))
+
+	active, err := posts.FindAllActive(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
@@ -0,0 +1,156 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const incidentKeyPrefix = "kmbridge:incident:"
+
+type incidentData struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Severity       string    `json:"severity"`
+	Status         string    `json:"status"`
+	AlertsCount    int       `json:"alerts_count"`
+	StartedAt      time.Time `json:"started_at"`
+	PostID         string    `json:"post_id"`
+	ChannelID      string    `json:"channel_id"`
+	AcknowledgedBy string    `json:"acknowledged_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUpdated    time.Time `json:"last_updated"`
+}
+
+type IncidentRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewIncidentRepository(client *redis.Client, logger *slog.Logger) *IncidentRepository {
+	return &IncidentRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *IncidentRepository) Save(ctx context.Context, inc *incident.Incident) error {
+	key := incidentKeyPrefix + inc.ID()
+	start := time.Now()
+
+	data := incidentData{
+		ID:             inc.ID(),
+		Name:           inc.Name(),
+		Severity:       inc.Severity().String(),
+		Status:         inc.Status(),
+		AlertsCount:    inc.AlertsCount(),
+		StartedAt:      inc.StartedAt(),
+		PostID:         inc.PostID(),
+		ChannelID:      inc.ChannelID(),
+		AcknowledgedBy: inc.AcknowledgedBy(),
+		CreatedAt:      inc.CreatedAt(),
+		LastUpdated:    inc.LastUpdated(),
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal incident data: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, jsonData, ttl).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", key, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", key, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *IncidentRepository) FindByID(ctx context.Context, id string) (*incident.Incident, error) {
+	key := incidentKeyPrefix + id
+	start := time.Now()
+
+	result, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		if errors.Is(err, redis.Nil) {
+			r.logger.Debug("Redis GET miss",
+				logger.RedisFields("get", key, duration),
+			)
+			redisGetMiss.Inc()
+			return nil, incident.ErrNotFound
+		}
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", key, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	var data incidentData
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return nil, fmt.Errorf("unmarshal incident data: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", key, duration),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	return incident.RestoreIncident(
+		data.ID,
+		data.Name,
+		alert.RestoreSeverity(data.Severity),
+		data.Status,
+		data.AlertsCount,
+		data.StartedAt,
+		data.PostID,
+		data.ChannelID,
+		data.AcknowledgedBy,
+		data.CreatedAt,
+		data.LastUpdated,
+	), nil
+}
+
+func (r *IncidentRepository) Delete(ctx context.Context, id string) error {
+	key := incidentKeyPrefix + id
+	start := time.Now()
+
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", key, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", key, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
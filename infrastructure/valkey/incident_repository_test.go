@@ -0,0 +1,70 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+)
+
+func setupTestIncidentRepository(t *testing.T) (*IncidentRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewIncidentRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestIncidentSaveAndFindByID(t *testing.T) {
+	repo, mr := setupTestIncidentRepository(t)
+	ctx := context.Background()
+
+	startedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	inc := incident.NewIncident("inc-1", "Database outage", alert.RestoreSeverity("critical"), incident.StatusFiring, 4, startedAt, time.Now())
+	inc.Attach("post-1", "channel-1")
+	inc.Acknowledge("alice", time.Now())
+
+	require.NoError(t, repo.Save(ctx, inc))
+	assert.True(t, mr.Exists("kmbridge:incident:inc-1"))
+	assert.Greater(t, mr.TTL("kmbridge:incident:inc-1"), time.Duration(0))
+
+	found, err := repo.FindByID(ctx, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "inc-1", found.ID())
+	assert.Equal(t, "Database outage", found.Name())
+	assert.Equal(t, "critical", found.Severity().String())
+	assert.Equal(t, incident.StatusAcknowledged, found.Status())
+	assert.Equal(t, 4, found.AlertsCount())
+	assert.True(t, startedAt.Equal(found.StartedAt()))
+	assert.Equal(t, "post-1", found.PostID())
+	assert.Equal(t, "channel-1", found.ChannelID())
+	assert.Equal(t, "alice", found.AcknowledgedBy())
+}
+
+func TestIncidentFindByIDNotFound(t *testing.T) {
+	repo, _ := setupTestIncidentRepository(t)
+
+	_, err := repo.FindByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, incident.ErrNotFound)
+}
+
+func TestIncidentDelete(t *testing.T) {
+	repo, mr := setupTestIncidentRepository(t)
+	ctx := context.Background()
+
+	inc := incident.NewIncident("inc-1", "Database outage", alert.RestoreSeverity("high"), incident.StatusFiring, 1, time.Now(), time.Now())
+	require.NoError(t, repo.Save(ctx, inc))
+
+	require.NoError(t, repo.Delete(ctx, "inc-1"))
+	assert.False(t, mr.Exists("kmbridge:incident:inc-1"))
+}
@@ -0,0 +1,106 @@
+package valkey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lockKeyPrefix        = "kmbridge:lock:"
+	idempotencyKeyPrefix = "kmbridge:idempotency:"
+
+	// lockRetryInterval is how often Lock retries a key held by another
+	// replica.
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// releaseScript deletes a lock only while it still holds this holder's
+// token, so a holder whose lock expired cannot release the next holder's.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Locks implements locking and idempotency keys with plain Valkey keys, so
+// every replica connected to the same server shares them.
+type Locks struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewLocks(client *redis.Client, logger *slog.Logger) *Locks {
+	return &Locks{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (l *Locks) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	redisKey := lockKeyPrefix + key
+	for {
+		ok, err := l.client.SetNX(ctx, redisKey, token, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis setnx: %w", err)
+		}
+		if ok {
+			break
+		}
+
+		timer := time.NewTimer(lockRetryInterval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("wait for lock %s: %w", key, ctx.Err())
+		}
+	}
+
+	return func() {
+		// The caller's context may already be done; releasing must still work.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := releaseScript.Run(releaseCtx, l.client, []string{redisKey}, token).Err(); err != nil && !errors.Is(err, redis.Nil) {
+			l.logger.Error("Failed to release lock",
+				slog.String("key", key),
+				slog.String("error", err.Error()),
+			)
+		}
+	}, nil
+}
+
+func (l *Locks) Seen(ctx context.Context, key string) (bool, error) {
+	n, err := l.client.Exists(ctx, idempotencyKeyPrefix+key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis exists: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (l *Locks) Mark(ctx context.Context, key string, ttl time.Duration) error {
+	if err := l.client.Set(ctx, idempotencyKeyPrefix+key, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestLocks(t *testing.T) (*Locks, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewLocks(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestLocksLockAndRelease(t *testing.T) {
+	locks, mr := setupTestLocks(t)
+	ctx := context.Background()
+
+	release, err := locks.Lock(ctx, "alert:fp-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, mr.Exists(lockKeyPrefix+"alert:fp-1"))
+	assert.Equal(t, time.Minute, mr.TTL(lockKeyPrefix+"alert:fp-1"))
+
+	waitCtx, cancel := context.WithTimeout(ctx, 120*time.Millisecond)
+	defer cancel()
+	_, err = locks.Lock(waitCtx, "alert:fp-1", time.Minute)
+	require.Error(t, err, "the key is held")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	otherRelease, err := locks.Lock(ctx, "alert:fp-2", time.Minute)
+	require.NoError(t, err, "other keys are independent")
+	otherRelease()
+
+	release()
+	assert.False(t, mr.Exists(lockKeyPrefix+"alert:fp-1"))
+
+	release, err = locks.Lock(ctx, "alert:fp-1", time.Minute)
+	require.NoError(t, err)
+	release()
+}
+
+func TestLocksLockWaitsForRelease(t *testing.T) {
+	locks, _ := setupTestLocks(t)
+	ctx := context.Background()
+
+	release, err := locks.Lock(ctx, "alert:fp-1", time.Minute)
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		defer close(acquired)
+		second, err := locks.Lock(ctx, "alert:fp-1", time.Minute)
+		if assert.NoError(t, err) {
+			second()
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Lock did not acquire the released key")
+	}
+}
+
+func TestLocksExpiredHolderCannotReleaseNextHolder(t *testing.T) {
+	locks, mr := setupTestLocks(t)
+	ctx := context.Background()
+
+	stale, err := locks.Lock(ctx, "alert:fp-1", time.Second)
+	require.NoError(t, err)
+	mr.FastForward(2 * time.Second)
+
+	current, err := locks.Lock(ctx, "alert:fp-1", time.Minute)
+	require.NoError(t, err)
+
+	stale()
+	assert.True(t, mr.Exists(lockKeyPrefix+"alert:fp-1"), "the stale release left the new lock alone")
+	current()
+	assert.False(t, mr.Exists(lockKeyPrefix+"alert:fp-1"))
+}
+
+func TestLocksIdempotencyKeys(t *testing.T) {
+	locks, mr := setupTestLocks(t)
+	ctx := context.Background()
+
+	seen, err := locks.Seen(ctx, "fp-1:abc")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, locks.Mark(ctx, "fp-1:abc", 10*time.Minute))
+	seen, err = locks.Seen(ctx, "fp-1:abc")
+	require.NoError(t, err)
+	assert.True(t, seen)
+	assert.Equal(t, 10*time.Minute, mr.TTL(idempotencyKeyPrefix+"fp-1:abc"))
+
+	mr.FastForward(11 * time.Minute)
+	seen, err = locks.Seen(ctx, "fp-1:abc")
+	require.NoError(t, err)
+	assert.False(t, seen)
+}
@@ -0,0 +1,157 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const pendingActionsKey = "kmbridge:pending_actions"
+
+type pendingActionData struct {
+	Fingerprint string            `json:"fingerprint"`
+	Action      string            `json:"action"`
+	Actor       string            `json:"actor"`
+	Enrichments map[string]string `json:"enrichments,omitempty"`
+	PostID      string            `json:"post_id"`
+	ChannelID   string            `json:"channel_id"`
+	AlertName   string            `json:"alert_name"`
+	RequestedAt time.Time         `json:"requested_at"`
+	Attempts    int               `json:"attempts"`
+	LastError   string            `json:"last_error"`
+}
+
+// PendingActionRepository keeps actions in a single hash keyed by alert
+// fingerprint. Actions do not expire here: they are removed once replayed
+// or when the replay gives up on them.
+type PendingActionRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewPendingActionRepository(client *redis.Client, logger *slog.Logger) *PendingActionRepository {
+	return &PendingActionRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *PendingActionRepository) Save(ctx context.Context, a *pendingaction.Action) error {
+	start := time.Now()
+
+	jsonData, err := json.Marshal(pendingActionData{
+		Fingerprint: a.Fingerprint().Value(),
+		Action:      a.Action(),
+		Actor:       a.Actor(),
+		Enrichments: a.Enrichments(),
+		PostID:      a.PostID(),
+		ChannelID:   a.ChannelID(),
+		AlertName:   a.AlertName(),
+		RequestedAt: a.RequestedAt(),
+		Attempts:    a.Attempts(),
+		LastError:   a.LastError(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal pending action data: %w", err)
+	}
+
+	if err := r.client.HSet(ctx, pendingActionsKey, a.Fingerprint().Value(), jsonData).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", pendingActionsKey, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis hset: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", pendingActionsKey, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *PendingActionRepository) FindAll(ctx context.Context) ([]*pendingaction.Action, error) {
+	start := time.Now()
+
+	results, err := r.client.HGetAll(ctx, pendingActionsKey).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis HGETALL failed",
+			logger.RedisFieldsWithError("scan", pendingActionsKey, duration, err.Error()),
+		)
+		redisScanErr.Inc()
+		return nil, fmt.Errorf("redis hgetall: %w", err)
+	}
+
+	actions := make([]*pendingaction.Action, 0, len(results))
+	for fingerprint, result := range results {
+		var d pendingActionData
+		if err := json.Unmarshal([]byte(result), &d); err != nil {
+			r.logger.Warn("Failed to unmarshal pending action, dropping it",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+			if err := r.Delete(ctx, fingerprint); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		actions = append(actions, pendingaction.RestoreAction(
+			alert.RestoreFingerprint(d.Fingerprint),
+			d.Action,
+			d.Actor,
+			d.Enrichments,
+			d.PostID,
+			d.ChannelID,
+			d.AlertName,
+			d.RequestedAt,
+			d.Attempts,
+			d.LastError,
+		))
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].RequestedAt().Before(actions[j].RequestedAt()) })
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis HGETALL completed",
+		logger.RedisFields("scan", pendingActionsKey, duration),
+		slog.Int("count", len(actions)),
+	)
+	redisScanOK.Inc()
+	redisScanDur.Update(float64(duration) / 1000)
+
+	return actions, nil
+}
+
+func (r *PendingActionRepository) Delete(ctx context.Context, fingerprint string) error {
+	start := time.Now()
+
+	if err := r.client.HDel(ctx, pendingActionsKey, fingerprint).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", pendingActionsKey, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis hdel: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", pendingActionsKey, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
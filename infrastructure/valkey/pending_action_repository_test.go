@@ -0,0 +1,72 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/pendingaction"
+)
+
+func setupTestPendingActionRepository(t *testing.T) (*PendingActionRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewPendingActionRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestPendingActionSaveFindDelete(t *testing.T) {
+	repo, _ := setupTestPendingActionRepository(t)
+	ctx := context.Background()
+	requestedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	resolve := pendingaction.NewAction(alert.RestoreFingerprint("fp-2"), "resolve", "alice",
+		map[string]string{"status": "resolved", "resolution_note": "disk cleaned"}, "post-2", "channel-1", "Disk full", "status 503", requestedAt.Add(time.Minute))
+	ack := pendingaction.NewAction(alert.RestoreFingerprint("fp-1"), "acknowledge", "bob",
+		map[string]string{"status": "acknowledged"}, "post-1", "channel-1", "CPU high", "connection refused", requestedAt)
+	require.NoError(t, repo.Save(ctx, resolve))
+	require.NoError(t, repo.Save(ctx, ack))
+
+	actions, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "fp-1", actions[0].Fingerprint().Value(), "oldest request first")
+	assert.Equal(t, "resolve", actions[1].Action())
+	assert.Equal(t, "alice", actions[1].Actor())
+	assert.Equal(t, "disk cleaned", actions[1].Enrichments()["resolution_note"])
+	assert.Equal(t, "post-2", actions[1].PostID())
+	assert.Equal(t, "channel-1", actions[1].ChannelID())
+	assert.Equal(t, "Disk full", actions[1].AlertName())
+	assert.True(t, requestedAt.Add(time.Minute).Equal(actions[1].RequestedAt()))
+
+	ack.RecordFailure("status 502")
+	require.NoError(t, repo.Save(ctx, ack))
+	require.NoError(t, repo.Delete(ctx, "fp-2"))
+	require.NoError(t, repo.Delete(ctx, "fp-unknown"))
+
+	actions, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, 1, actions[0].Attempts())
+	assert.Equal(t, "status 502", actions[0].LastError())
+}
+
+func TestPendingActionDropsCorruptEntries(t *testing.T) {
+	repo, mr := setupTestPendingActionRepository(t)
+	mr.HSet(pendingActionsKey, "fp-1", "{not json")
+
+	actions, err := repo.FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.False(t, mr.Exists(pendingActionsKey))
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
@@ -19,6 +20,16 @@ import (
 const (
 	keyPrefix = "kmbridge:alert:"
 	ttl       = 7 * 24 * time.Hour
+
+	// Posts whose key expires are also kept here while post expiry is
+	// enabled: a sorted set of fingerprints scored by expiry time, and a hash
+	// of their post data, which outlives the key.
+	expiryDueKey     = "kmbridge:alert-expiry:due"
+	expiryEntriesKey = "kmbridge:alert-expiry:entries"
+
+	// postIDPrefix keys the fingerprint of each post by its post ID, with
+	// the TTL of the post's key. It must not match keyPrefix + "*".
+	postIDPrefix = "kmbridge:alert-post:"
 )
 
 var (
@@ -40,20 +51,101 @@ var (
 )
 
 type postData struct {
-	PostID            string    `json:"post_id"`
-	ChannelID         string    `json:"channel_id"`
-	Fingerprint       string    `json:"fingerprint"`
-	AlertName         string    `json:"alert_name"`
-	Severity          string    `json:"severity"`
-	FiringStartTime   time.Time `json:"firing_start_time"`
-	CreatedAt         time.Time `json:"created_at"`
-	LastUpdated       time.Time `json:"last_updated"`
-	LastKnownAssignee string    `json:"last_known_assignee,omitempty"`
+	PostID            string            `json:"post_id"`
+	ChannelID         string            `json:"channel_id"`
+	Fingerprint       string            `json:"fingerprint"`
+	AlertName         string            `json:"alert_name"`
+	Severity          string            `json:"severity"`
+	FiringStartTime   time.Time         `json:"firing_start_time"`
+	CreatedAt         time.Time         `json:"created_at"`
+	LastUpdated       time.Time         `json:"last_updated"`
+	LastKnownAssignee string            `json:"last_known_assignee,omitempty"`
+	ShownStatus       string            `json:"shown_status,omitempty"`
+	SnoozedUntil      time.Time         `json:"snoozed_until,omitzero"`
+	EscalationLevel   int               `json:"escalation_level,omitempty"`
+	EscalatedAt       time.Time         `json:"escalated_at,omitzero"`
+	AcknowledgedAt    time.Time         `json:"acknowledged_at,omitzero"`
+	ResolvedAt        time.Time         `json:"resolved_at,omitzero"`
+	AckUntil          time.Time         `json:"ack_until,omitzero"`
+	AckedBy           string            `json:"acked_by,omitempty"`
+	Refires           int               `json:"refires,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+}
+
+func newPostData(p *post.Post) postData {
+	return postData{
+		PostID:            p.PostID(),
+		ChannelID:         p.ChannelID(),
+		Fingerprint:       p.Fingerprint().Value(),
+		AlertName:         p.AlertName(),
+		Severity:          p.Severity().String(),
+		FiringStartTime:   p.FiringStartTime(),
+		CreatedAt:         p.CreatedAt(),
+		LastUpdated:       p.LastUpdated(),
+		LastKnownAssignee: p.LastKnownAssignee(),
+		ShownStatus:       p.ShownStatus(),
+		SnoozedUntil:      p.SnoozedUntil(),
+		EscalationLevel:   p.EscalationLevel(),
+		EscalatedAt:       p.EscalatedAt(),
+		AcknowledgedAt:    p.AcknowledgedAt(),
+		ResolvedAt:        p.ResolvedAt(),
+		AckUntil:          p.AckUntil(),
+		AckedBy:           p.AckedBy(),
+		Refires:           p.Refires(),
+		Labels:            p.Labels(),
+	}
+}
+
+func (d postData) toPost() *post.Post {
+	p := post.RestorePost(
+		d.PostID,
+		d.ChannelID,
+		alert.RestoreFingerprint(d.Fingerprint),
+		d.AlertName,
+		alert.RestoreSeverity(d.Severity),
+		d.FiringStartTime,
+		d.CreatedAt,
+		d.LastUpdated,
+		d.LastKnownAssignee,
+	)
+	p.ShowStatus(d.ShownStatus)
+	if !d.SnoozedUntil.IsZero() {
+		p.Snooze(d.SnoozedUntil)
+	}
+	if d.EscalationLevel > 0 {
+		p.RestoreEscalation(d.EscalationLevel, d.EscalatedAt)
+	}
+	p.RestoreLifecycle(d.AcknowledgedAt, d.ResolvedAt)
+	if !d.AckUntil.IsZero() {
+		p.AcknowledgeUntil(d.AckUntil, d.AckedBy)
+	}
+	p.RestoreRefires(d.Refires)
+	if d.Labels != nil {
+		p.SetLabels(d.Labels)
+	}
+	return p
 }
 
 type PostRepository struct {
 	client *redis.Client
 	logger *slog.Logger
+
+	// expiry is set by SetSeverityTTLs; nil keeps every post for ttl.
+	expiry *postExpiry
+}
+
+type postExpiry struct {
+	defaultTTL time.Duration
+	severities map[string]time.Duration
+}
+
+// ttlFor returns how long the post of an alert with severity is kept. Zero
+// means it never expires.
+func (e *postExpiry) ttlFor(severity string) time.Duration {
+	if d, ok := e.severities[severity]; ok {
+		return d
+	}
+	return e.defaultTTL
 }
 
 func NewPostRepository(client *redis.Client, logger *slog.Logger) *PostRepository {
@@ -63,28 +155,46 @@ func NewPostRepository(client *redis.Client, logger *slog.Logger) *PostRepositor
 	}
 }
 
+// SetSeverityTTLs keeps posts for the TTL of their alert's severity, or for
+// defaultTTL when the severity has none. A zero TTL keeps the post until it
+// is deleted. Posts that expire are remembered until FindExpired reports
+// them and ForgetExpired drops them.
+func (r *PostRepository) SetSeverityTTLs(defaultTTL time.Duration, severities map[string]time.Duration) {
+	r.expiry = &postExpiry{defaultTTL: defaultTTL, severities: severities}
+}
+
 func (r *PostRepository) Save(ctx context.Context, fingerprint alert.Fingerprint, p *post.Post) error {
 	key := keyPrefix + fingerprint.Value()
 	start := time.Now()
 
-	data := postData{
-		PostID:            p.PostID(),
-		ChannelID:         p.ChannelID(),
-		Fingerprint:       p.Fingerprint().Value(),
-		AlertName:         p.AlertName(),
-		Severity:          p.Severity().String(),
-		FiringStartTime:   p.FiringStartTime(),
-		CreatedAt:         p.CreatedAt(),
-		LastUpdated:       p.LastUpdated(),
-		LastKnownAssignee: p.LastKnownAssignee(),
+	keyTTL := ttl
+	if r.expiry != nil {
+		keyTTL = r.expiry.ttlFor(p.Severity().String())
+	}
+	// A snoozed post must outlive its snooze so the unsnooze job can still
+	// restore it.
+	if remaining := time.Until(p.SnoozedUntil()); remaining > 0 && keyTTL > 0 {
+		keyTTL += remaining
 	}
 
-	jsonData, err := json.Marshal(data)
+	jsonData, err := json.Marshal(newPostData(p))
 	if err != nil {
 		return fmt.Errorf("marshal post data: %w", err)
 	}
 
-	if err := r.client.Set(ctx, key, jsonData, ttl).Err(); err != nil {
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, key, jsonData, keyTTL)
+	pipe.Set(ctx, postIDPrefix+p.PostID(), fingerprint.Value(), keyTTL)
+	if r.expiry != nil {
+		if keyTTL > 0 {
+			pipe.ZAdd(ctx, expiryDueKey, redis.Z{Score: float64(start.Add(keyTTL).UnixMilli()), Member: fingerprint.Value()})
+			pipe.HSet(ctx, expiryEntriesKey, fingerprint.Value(), jsonData)
+		} else {
+			pipe.ZRem(ctx, expiryDueKey, fingerprint.Value())
+			pipe.HDel(ctx, expiryEntriesKey, fingerprint.Value())
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		duration := time.Since(start).Milliseconds()
 		r.logger.Error("Redis SET failed",
 			logger.RedisFieldsWithError("set", key, duration, err.Error()),
@@ -136,24 +246,55 @@ func (r *PostRepository) FindByFingerprint(ctx context.Context, fingerprint aler
 	redisGetOK.Inc()
 	redisGetDur.Update(float64(duration) / 1000)
 
-	return post.RestorePost(
-		data.PostID,
-		data.ChannelID,
-		alert.RestoreFingerprint(data.Fingerprint),
-		data.AlertName,
-		alert.RestoreSeverity(data.Severity),
-		data.FiringStartTime,
-		data.CreatedAt,
-		data.LastUpdated,
-		data.LastKnownAssignee,
-	), nil
+	return data.toPost(), nil
+}
+
+// FindByPostID returns the post with postID through the post ID index. An
+// index entry left behind by a post that was deleted or replaced is dropped.
+func (r *PostRepository) FindByPostID(ctx context.Context, postID string) (*post.Post, error) {
+	indexKey := postIDPrefix + postID
+	fingerprint, err := r.client.Get(ctx, indexKey).Result()
+	if errors.Is(err, redis.Nil) {
+		redisGetMiss.Inc()
+		return nil, post.ErrNotFound
+	}
+	if err != nil {
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	p, err := r.FindByFingerprint(ctx, alert.RestoreFingerprint(fingerprint))
+	if err != nil && !errors.Is(err, post.ErrNotFound) {
+		return nil, err
+	}
+	if p == nil || p.PostID() != postID {
+		_ = r.client.Del(ctx, indexKey).Err()
+		return nil, post.ErrNotFound
+	}
+	return p, nil
 }
 
 func (r *PostRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
 	key := keyPrefix + fingerprint.Value()
 	start := time.Now()
 
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	// The post ID index entry goes with the post; if the post cannot be read
+	// the entry is dropped on its next lookup instead.
+	var indexKey string
+	if existing, err := r.FindByFingerprint(ctx, fingerprint); err == nil {
+		indexKey = postIDPrefix + existing.PostID()
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if indexKey != "" {
+		pipe.Del(ctx, indexKey)
+	}
+	if r.expiry != nil {
+		pipe.ZRem(ctx, expiryDueKey, fingerprint.Value())
+		pipe.HDel(ctx, expiryEntriesKey, fingerprint.Value())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		duration := time.Since(start).Milliseconds()
 		r.logger.Error("Redis DEL failed",
 			logger.RedisFieldsWithError("del", key, duration, err.Error()),
@@ -241,18 +382,7 @@ func (r *PostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error
 			continue
 		}
 
-		p := post.RestorePost(
-			data.PostID,
-			data.ChannelID,
-			alert.RestoreFingerprint(data.Fingerprint),
-			data.AlertName,
-			alert.RestoreSeverity(data.Severity),
-			data.FiringStartTime,
-			data.CreatedAt,
-			data.LastUpdated,
-			data.LastKnownAssignee,
-		)
-		posts = append(posts, p)
+		posts = append(posts, data.toPost())
 	}
 
 	duration := time.Since(start).Milliseconds()
@@ -266,6 +396,65 @@ func (r *PostRepository) FindAllActive(ctx context.Context) ([]*post.Post, error
 	return posts, nil
 }
 
+// FindExpired returns the posts whose key expired by now. A post whose key
+// still exists, because Valkey has not expired it yet, is left for a later
+// call.
+func (r *PostRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]post.Expired, error) {
+	due, err := r.client.ZRangeByScoreWithScores(ctx, expiryDueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis zrangebyscore: %w", err)
+	}
+
+	var expired []post.Expired
+	for _, z := range due {
+		fingerprint, _ := z.Member.(string)
+		exists, err := r.client.Exists(ctx, keyPrefix+fingerprint).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis exists: %w", err)
+		}
+		if exists > 0 {
+			continue
+		}
+
+		raw, err := r.client.HGet(ctx, expiryEntriesKey, fingerprint).Result()
+		if errors.Is(err, redis.Nil) {
+			_ = r.client.ZRem(ctx, expiryDueKey, fingerprint).Err()
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redis hget: %w", err)
+		}
+		var data postData
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			r.logger.Warn("Dropping unreadable expired post",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+			_ = r.ForgetExpired(ctx, alert.RestoreFingerprint(fingerprint))
+			continue
+		}
+		expired = append(expired, post.Expired{
+			Post:      data.toPost(),
+			ExpiredAt: time.UnixMilli(int64(z.Score)),
+		})
+	}
+	return expired, nil
+}
+
+func (r *PostRepository) ForgetExpired(ctx context.Context, fingerprint alert.Fingerprint) error {
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, expiryDueKey, fingerprint.Value())
+	pipe.HDel(ctx, expiryEntriesKey, fingerprint.Value())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis forget expired post: %w", err)
+	}
+	return nil
+}
+
 func (r *PostRepository) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
@@ -36,7 +36,7 @@ func TestSaveAndFindByFingerprint(t *testing.T) {
 	ctx := context.Background()
 
 	fingerprint := alert.RestoreFingerprint("fp-test-123")
-	p := post.NewPost("post-abc", "channel-xyz", alert.RestoreFingerprint("fp-test-123"), "Test Alert", alert.RestoreSeverity("critical"), time.Now())
+	p := post.NewPost("post-abc", "channel-xyz", alert.RestoreFingerprint("fp-test-123"), "Test Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
 
 	err := repo.Save(ctx, fingerprint, p)
 	require.NoError(t, err)
@@ -72,13 +72,13 @@ func TestSaveOverwritesExisting(t *testing.T) {
 
 	fingerprint := alert.RestoreFingerprint("fp-overwrite")
 
-	p1 := post.NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-overwrite"), "Alert 1", alert.RestoreSeverity("high"), time.Now())
+	p1 := post.NewPost("post-1", "channel-1", alert.RestoreFingerprint("fp-overwrite"), "Alert 1", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	err := repo.Save(ctx, fingerprint, p1)
 	require.NoError(t, err)
 
 	time.Sleep(10 * time.Millisecond)
 
-	p2 := post.NewPost("post-2", "channel-2", alert.RestoreFingerprint("fp-overwrite"), "Alert 2", alert.RestoreSeverity("critical"), time.Now())
+	p2 := post.NewPost("post-2", "channel-2", alert.RestoreFingerprint("fp-overwrite"), "Alert 2", alert.RestoreSeverity("critical"), time.Now(), time.Now())
 	err = repo.Save(ctx, fingerprint, p2)
 	require.NoError(t, err)
 
@@ -95,7 +95,7 @@ func TestDelete(t *testing.T) {
 	ctx := context.Background()
 
 	fingerprint := alert.RestoreFingerprint("fp-delete")
-	p := post.NewPost("post-del", "channel-del", alert.RestoreFingerprint("fp-delete"), "Delete Test", alert.RestoreSeverity("warning"), time.Now())
+	p := post.NewPost("post-del", "channel-del", alert.RestoreFingerprint("fp-delete"), "Delete Test", alert.RestoreSeverity("warning"), time.Now(), time.Now())
 
 	err := repo.Save(ctx, fingerprint, p)
 	require.NoError(t, err)
@@ -113,6 +113,39 @@ func TestDelete(t *testing.T) {
 	assert.ErrorIs(t, err, post.ErrNotFound)
 }
 
+func TestFindByPostID(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	ctx := context.Background()
+
+	fingerprint := alert.RestoreFingerprint("fp-by-id")
+	require.NoError(t, repo.Save(ctx, fingerprint, post.NewPost("post-old", "channel-1", fingerprint, "Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())))
+
+	found, err := repo.FindByPostID(ctx, "post-old")
+	require.NoError(t, err)
+	assert.Equal(t, "fp-by-id", found.Fingerprint().Value())
+	assert.Equal(t, ttl, mr.TTL(postIDPrefix+"post-old"), "the index entry expires with the post")
+	posts, err := repo.FindAllActive(ctx)
+	require.NoError(t, err)
+	assert.Len(t, posts, 1, "index entries are not listed as posts")
+
+	// A recreated post replaces the old one under the same fingerprint.
+	require.NoError(t, repo.Save(ctx, fingerprint, post.NewPost("post-new", "channel-1", fingerprint, "Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())))
+	_, err = repo.FindByPostID(ctx, "post-old")
+	assert.ErrorIs(t, err, post.ErrNotFound)
+	assert.False(t, mr.Exists(postIDPrefix+"post-old"), "the stale entry is dropped")
+	found, err = repo.FindByPostID(ctx, "post-new")
+	require.NoError(t, err)
+	assert.Equal(t, "post-new", found.PostID())
+
+	require.NoError(t, repo.Delete(ctx, fingerprint))
+	assert.False(t, mr.Exists(postIDPrefix+"post-new"), "the entry is deleted with the post")
+	_, err = repo.FindByPostID(ctx, "post-new")
+	assert.ErrorIs(t, err, post.ErrNotFound)
+
+	_, err = repo.FindByPostID(ctx, "post-unknown")
+	assert.ErrorIs(t, err, post.ErrNotFound)
+}
+
 func TestPing(t *testing.T) {
 	repo, mr := setupTestRedis(t)
 	ctx := context.Background()
@@ -131,7 +164,7 @@ func TestTTLVerification(t *testing.T) {
 	ctx := context.Background()
 
 	fingerprint := alert.RestoreFingerprint("fp-ttl")
-	p := post.NewPost("post-ttl", "channel-ttl", alert.RestoreFingerprint("fp-ttl"), "TTL Test", alert.RestoreSeverity("info"), time.Now())
+	p := post.NewPost("post-ttl", "channel-ttl", alert.RestoreFingerprint("fp-ttl"), "TTL Test", alert.RestoreSeverity("info"), time.Now(), time.Now())
 
 	err := repo.Save(ctx, fingerprint, p)
 	require.NoError(t, err)
@@ -164,6 +197,7 @@ func TestSavePreservesAllFields(t *testing.T) {
 		updatedTime,
 		"testassignee",
 	)
+	p.ShowStatus("firing")
 
 	err := repo.Save(ctx, fingerprint, p)
 	require.NoError(t, err)
@@ -179,6 +213,161 @@ func TestSavePreservesAllFields(t *testing.T) {
 	assert.WithinDuration(t, createdTime, found.CreatedAt(), time.Millisecond)
 	assert.WithinDuration(t, updatedTime, found.LastUpdated(), time.Millisecond)
 	assert.Equal(t, "testassignee", found.LastKnownAssignee())
+	assert.Equal(t, "firing", found.ShownStatus())
+}
+
+func TestSeverityTTLs(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	repo.SetSeverityTTLs(2*time.Hour, map[string]time.Duration{"info": time.Hour, "critical": 0})
+	ctx := context.Background()
+
+	save := func(fp, severity string) {
+		t.Helper()
+		fingerprint := alert.RestoreFingerprint(fp)
+		require.NoError(t, repo.Save(ctx, fingerprint, post.NewPost("post-"+fp, "channel-1", fingerprint, "Alert "+fp, alert.RestoreSeverity(severity), time.Now(), time.Now())))
+	}
+	save("fp-info", "info")
+	save("fp-critical", "critical")
+	save("fp-high", "high")
+
+	assert.Equal(t, time.Hour, mr.TTL(keyPrefix+"fp-info"))
+	assert.Zero(t, mr.TTL(keyPrefix+"fp-critical"), "critical posts never expire")
+	assert.Equal(t, 2*time.Hour, mr.TTL(keyPrefix+"fp-high"), "severities without a TTL use the default")
+
+	later := time.Now().Add(time.Hour + time.Second)
+	expired, err := repo.FindExpired(ctx, later, 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "keys Valkey has not expired yet are left alone")
+
+	mr.FastForward(time.Hour)
+	expired, err = repo.FindExpired(ctx, later, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "post-fp-info", expired[0].Post.PostID())
+	assert.Equal(t, "Alert fp-info", expired[0].Post.AlertName())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expired[0].ExpiredAt, time.Second)
+
+	require.NoError(t, repo.ForgetExpired(ctx, alert.RestoreFingerprint("fp-info")))
+	expired, err = repo.FindExpired(ctx, later, 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	require.NoError(t, repo.Delete(ctx, alert.RestoreFingerprint("fp-high")))
+	mr.FastForward(time.Hour)
+	expired, err = repo.FindExpired(ctx, time.Now().Add(3*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "deleted posts do not expire")
+	assert.False(t, mr.Exists(expiryEntriesKey))
+}
+
+func TestSnoozePersistence(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	ctx := context.Background()
+
+	fingerprint := alert.RestoreFingerprint("fp-snooze")
+	p := post.NewPost("post-snooze", "channel-snooze", fingerprint, "Snoozed Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	until := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	p.Snooze(until)
+
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+
+	assert.Greater(t, mr.TTL(keyPrefix+fingerprint.Value()), ttl, "TTL should outlast the snooze")
+
+	found, err := repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.True(t, until.Equal(found.SnoozedUntil()))
+
+	all, err := repo.FindAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.True(t, until.Equal(all[0].SnoozedUntil()))
+
+	found.Unsnooze()
+	require.NoError(t, repo.Save(ctx, fingerprint, found))
+	raw, err := mr.Get(keyPrefix + fingerprint.Value())
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "snoozed_until")
+	assert.LessOrEqual(t, mr.TTL(keyPrefix+fingerprint.Value()), ttl)
+}
+
+func TestEscalationPersistence(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	ctx := context.Background()
+
+	fingerprint := alert.RestoreFingerprint("fp-escalate")
+	p := post.NewPost("post-escalate", "channel-1", fingerprint, "Escalated Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+	raw, err := mr.Get(keyPrefix + fingerprint.Value())
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "escalation_level")
+
+	at := time.Now().UTC().Truncate(time.Second)
+	p.Escalate(at)
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+
+	found, err := repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.EscalationLevel())
+	assert.True(t, at.Equal(found.EscalatedAt()))
+
+	all, err := repo.FindAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, 1, all[0].EscalationLevel())
+}
+
+func TestLifecyclePersistence(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	ctx := context.Background()
+
+	fingerprint := alert.RestoreFingerprint("fp-lifecycle")
+	p := post.NewPost("post-lifecycle", "channel-1", fingerprint, "Acked Alert", alert.RestoreSeverity("critical"), time.Now(), time.Now())
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+	raw, err := mr.Get(keyPrefix + fingerprint.Value())
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "acknowledged_at")
+
+	at := time.Now().UTC().Truncate(time.Second)
+	p.Acknowledge(at)
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+
+	found, err := repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(found.AcknowledgedAt()))
+	assert.True(t, found.ResolvedAt().IsZero())
+	assert.True(t, found.AckUntil().IsZero())
+	assert.Equal(t, 1, found.FireCount())
+
+	p.Refire()
+	p.AcknowledgeUntil(at.Add(2*time.Hour), "alice")
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+	found, err = repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.True(t, at.Add(2*time.Hour).Equal(found.AckUntil()))
+	assert.Equal(t, "alice", found.AckedBy())
+	assert.Equal(t, 2, found.FireCount())
+}
+
+func TestLabelsPersistence(t *testing.T) {
+	repo, mr := setupTestRedis(t)
+	ctx := context.Background()
+
+	fingerprint := alert.RestoreFingerprint("fp-labels")
+	p := post.NewPost("post-labels", "channel-1", fingerprint, "Labeled Alert", alert.RestoreSeverity("high"), time.Now(), time.Now())
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+	raw, err := mr.Get(keyPrefix + fingerprint.Value())
+	require.NoError(t, err)
+	assert.NotContains(t, raw, `"labels"`)
+
+	found, err := repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Nil(t, found.Labels(), "posts saved without labels have none recorded")
+
+	p.SetLabels(map[string]string{"team": "payments", "pod": "api-1"})
+	require.NoError(t, repo.Save(ctx, fingerprint, p))
+	found, err = repo.FindByFingerprint(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "pod": "api-1"}, found.Labels())
 }
 
 func TestNewPostRepository(t *testing.T) {
@@ -199,7 +388,7 @@ func TestSaveRedisSetError(t *testing.T) {
 	ctx := context.Background()
 
 	fingerprint := alert.RestoreFingerprint("fp-error")
-	p := post.NewPost("post-err", "channel-err", alert.RestoreFingerprint("fp-error"), "Error Test", alert.RestoreSeverity("critical"), time.Now())
+	p := post.NewPost("post-err", "channel-err", alert.RestoreFingerprint("fp-error"), "Error Test", alert.RestoreSeverity("critical"), time.Now(), time.Now())
 
 	mr.Close()
 
@@ -260,17 +449,17 @@ func TestFindAllActive(t *testing.T) {
 	assert.Empty(t, posts)
 
 	fingerprint1 := alert.RestoreFingerprint("fp-active-1")
-	p1 := post.NewPost("post-1", "channel-1", fingerprint1, "Alert 1", alert.RestoreSeverity("critical"), time.Now())
+	p1 := post.NewPost("post-1", "channel-1", fingerprint1, "Alert 1", alert.RestoreSeverity("critical"), time.Now(), time.Now())
 	err = repo.Save(ctx, fingerprint1, p1)
 	require.NoError(t, err)
 
 	fingerprint2 := alert.RestoreFingerprint("fp-active-2")
-	p2 := post.NewPost("post-2", "channel-2", fingerprint2, "Alert 2", alert.RestoreSeverity("high"), time.Now())
+	p2 := post.NewPost("post-2", "channel-2", fingerprint2, "Alert 2", alert.RestoreSeverity("high"), time.Now(), time.Now())
 	err = repo.Save(ctx, fingerprint2, p2)
 	require.NoError(t, err)
 
 	fingerprint3 := alert.RestoreFingerprint("fp-active-3")
-	p3 := post.NewPost("post-3", "channel-3", fingerprint3, "Alert 3", alert.RestoreSeverity("warning"), time.Now())
+	p3 := post.NewPost("post-3", "channel-3", fingerprint3, "Alert 3", alert.RestoreSeverity("warning"), time.Now(), time.Now())
 	p3.SetLastKnownAssignee("testuser")
 	err = repo.Save(ctx, fingerprint3, p3)
 	require.NoError(t, err)
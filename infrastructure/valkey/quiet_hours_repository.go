@@ -0,0 +1,208 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const quietHoursHeldKey = "kmbridge:quiet-hours:held"
+
+type quietHeldData struct {
+	ChannelID       string            `json:"channel_id"`
+	Fingerprint     string            `json:"fingerprint"`
+	AlertName       string            `json:"alert_name"`
+	Severity        string            `json:"severity"`
+	Status          string            `json:"status"`
+	Description     string            `json:"description,omitempty"`
+	Source          string            `json:"source,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	GeneratorURL    string            `json:"generator_url,omitempty"`
+	FiringStartTime time.Time         `json:"firing_start_time"`
+	HeldAt          time.Time         `json:"held_at"`
+	ResolvedAt      time.Time         `json:"resolved_at,omitempty"`
+	Digested        bool              `json:"digested,omitempty"`
+}
+
+// QuietHoursRepository keeps held alerts in a single hash keyed by
+// fingerprint. Held alerts do not expire: they are removed once quiet hours
+// end and they were posted or listed in the digest.
+type QuietHoursRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewQuietHoursRepository(client *redis.Client, logger *slog.Logger) *QuietHoursRepository {
+	return &QuietHoursRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *QuietHoursRepository) Save(ctx context.Context, h *quiethours.Held) error {
+	start := time.Now()
+
+	a := h.Alert()
+	jsonData, err := json.Marshal(quietHeldData{
+		ChannelID:       h.ChannelID(),
+		Fingerprint:     a.Fingerprint().Value(),
+		AlertName:       a.Name(),
+		Severity:        a.Severity().String(),
+		Status:          a.Status().String(),
+		Description:     a.Description(),
+		Source:          a.Source(),
+		Labels:          a.Labels(),
+		Annotations:     a.Annotations(),
+		GeneratorURL:    a.GeneratorURL(),
+		FiringStartTime: a.FiringStartTime(),
+		HeldAt:          h.HeldAt(),
+		ResolvedAt:      h.ResolvedAt(),
+		Digested:        h.IsDigested(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal held alert: %w", err)
+	}
+
+	if err := r.client.HSet(ctx, quietHoursHeldKey, a.Fingerprint().Value(), jsonData).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", quietHoursHeldKey, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis hset: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", quietHoursHeldKey, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *QuietHoursRepository) FindByFingerprint(ctx context.Context, fingerprint alert.Fingerprint) (*quiethours.Held, error) {
+	start := time.Now()
+
+	result, err := r.client.HGet(ctx, quietHoursHeldKey, fingerprint.Value()).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		if errors.Is(err, redis.Nil) {
+			r.logger.Debug("Redis GET miss",
+				logger.RedisFields("get", quietHoursHeldKey, duration),
+			)
+			redisGetMiss.Inc()
+			return nil, quiethours.ErrNotFound
+		}
+		r.logger.Error("Redis GET failed",
+			logger.RedisFieldsWithError("get", quietHoursHeldKey, duration, err.Error()),
+		)
+		redisGetErr.Inc()
+		return nil, fmt.Errorf("redis hget: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis GET completed",
+		logger.RedisFields("get", quietHoursHeldKey, duration),
+	)
+	redisGetOK.Inc()
+	redisGetDur.Update(float64(duration) / 1000)
+
+	held, err := toQuietHeld(result)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal held alert: %w", err)
+	}
+	return held, nil
+}
+
+func (r *QuietHoursRepository) FindAll(ctx context.Context) ([]*quiethours.Held, error) {
+	start := time.Now()
+
+	results, err := r.client.HGetAll(ctx, quietHoursHeldKey).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis HGETALL failed",
+			logger.RedisFieldsWithError("scan", quietHoursHeldKey, duration, err.Error()),
+		)
+		redisScanErr.Inc()
+		return nil, fmt.Errorf("redis hgetall: %w", err)
+	}
+
+	held := make([]*quiethours.Held, 0, len(results))
+	for fingerprint, result := range results {
+		h, err := toQuietHeld(result)
+		if err != nil {
+			r.logger.Warn("Failed to unmarshal held alert, dropping it",
+				slog.String("fingerprint", fingerprint),
+				slog.String("error", err.Error()),
+			)
+			if err := r.Delete(ctx, alert.RestoreFingerprint(fingerprint)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		held = append(held, h)
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i].HeldAt().Before(held[j].HeldAt()) })
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis HGETALL completed",
+		logger.RedisFields("scan", quietHoursHeldKey, duration),
+		slog.Int("count", len(held)),
+	)
+	redisScanOK.Inc()
+	redisScanDur.Update(float64(duration) / 1000)
+
+	return held, nil
+}
+
+func toQuietHeld(data string) (*quiethours.Held, error) {
+	var d quietHeldData
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return nil, err
+	}
+	a := alert.RestoreAlert(
+		alert.RestoreFingerprint(d.Fingerprint),
+		d.AlertName,
+		alert.RestoreSeverity(d.Severity),
+		alert.RestoreStatus(d.Status),
+		d.Description,
+		d.Source,
+		d.Labels,
+		d.FiringStartTime,
+	).WithAnnotations(d.Annotations).WithGeneratorURL(d.GeneratorURL)
+	return quiethours.RestoreHeld(d.ChannelID, a, d.HeldAt, d.ResolvedAt, d.Digested), nil
+}
+
+func (r *QuietHoursRepository) Delete(ctx context.Context, fingerprint alert.Fingerprint) error {
+	start := time.Now()
+
+	if err := r.client.HDel(ctx, quietHoursHeldKey, fingerprint.Value()).Err(); err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", quietHoursHeldKey, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis hdel: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", quietHoursHeldKey, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
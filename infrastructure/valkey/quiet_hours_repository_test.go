@@ -0,0 +1,67 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/quiethours"
+)
+
+func setupTestQuietHoursRepository(t *testing.T) (*QuietHoursRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewQuietHoursRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestQuietHoursRepository(t *testing.T) {
+	repo, mr := setupTestQuietHoursRepository(t)
+	ctx := context.Background()
+	heldAt := time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC)
+
+	newAlert := func(fp, name string) *alert.Alert {
+		return alert.RestoreAlert(alert.RestoreFingerprint(fp), name, alert.RestoreSeverity("warning"), alert.RestoreStatus("firing"),
+			"Disk is almost full", "prometheus", map[string]string{"host": "db-1"}, heldAt.Add(-time.Minute))
+	}
+	later := quiethours.NewHeld("ch-ops", newAlert("fp-2", "Slow queries"), heldAt.Add(time.Hour))
+	earlier := quiethours.NewHeld("ch-db", newAlert("fp-1", "Disk full"), heldAt)
+	require.NoError(t, repo.Save(ctx, later))
+	require.NoError(t, repo.Save(ctx, earlier))
+
+	earlier.MarkResolved(heldAt.Add(2 * time.Hour))
+	earlier.MarkDigested()
+	require.NoError(t, repo.Save(ctx, earlier))
+
+	found, err := repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "ch-db", found.ChannelID())
+	assert.Equal(t, "Disk full", found.Alert().Name())
+	assert.Equal(t, "warning", found.Alert().Severity().String())
+	assert.Equal(t, map[string]string{"host": "db-1"}, found.Alert().Labels())
+	assert.True(t, heldAt.Equal(found.HeldAt()))
+	assert.True(t, heldAt.Add(2*time.Hour).Equal(found.ResolvedAt()))
+	assert.True(t, found.IsDigested())
+
+	mr.HSet(quietHoursHeldKey, "fp-broken", "{not json")
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "fp-1", all[0].Fingerprint().Value(), "oldest first")
+	assert.False(t, all[1].IsResolved())
+	assert.Empty(t, mr.HGet(quietHoursHeldKey, "fp-broken"), "unreadable entries are dropped")
+
+	require.NoError(t, repo.Delete(ctx, alert.RestoreFingerprint("fp-1")))
+	_, err = repo.FindByFingerprint(ctx, alert.RestoreFingerprint("fp-1"))
+	assert.ErrorIs(t, err, quiethours.ErrNotFound)
+}
@@ -0,0 +1,208 @@
+package valkey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+const (
+	retentionScheduleKey = "kmbridge:retention:due"
+	retentionEntriesKey  = "kmbridge:retention:entries"
+)
+
+type retentionData struct {
+	PostID         string    `json:"post_id"`
+	ChannelID      string    `json:"channel_id"`
+	Fingerprint    string    `json:"fingerprint"`
+	AlertName      string    `json:"alert_name"`
+	Attachment     string    `json:"attachment"`
+	ResolvedAt     time.Time `json:"resolved_at"`
+	DueAt          time.Time `json:"due_at"`
+	ArchivedPostID string    `json:"archived_post_id,omitempty"`
+}
+
+// RetentionRepository keeps entries in a hash keyed by post ID and schedules
+// them in a sorted set scored by due time. Entries do not expire: they are
+// removed once the retention action has run.
+type RetentionRepository struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewRetentionRepository(client *redis.Client, logger *slog.Logger) *RetentionRepository {
+	return &RetentionRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (r *RetentionRepository) Save(ctx context.Context, e *retention.Entry) error {
+	start := time.Now()
+
+	attachment := e.Attachment()
+	attachmentJSON, err := attachment.ToJSON()
+	if err != nil {
+		return fmt.Errorf("marshal attachment: %w", err)
+	}
+
+	jsonData, err := json.Marshal(retentionData{
+		PostID:         e.PostID(),
+		ChannelID:      e.ChannelID(),
+		Fingerprint:    e.Fingerprint().Value(),
+		AlertName:      e.AlertName(),
+		Attachment:     attachmentJSON,
+		ResolvedAt:     e.ResolvedAt(),
+		DueAt:          e.DueAt(),
+		ArchivedPostID: e.ArchivedPostID(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal retention data: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, retentionEntriesKey, e.PostID(), jsonData)
+		pipe.ZAdd(ctx, retentionScheduleKey, redis.Z{Score: float64(e.DueAt().Unix()), Member: e.PostID()})
+		return nil
+	})
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis SET failed",
+			logger.RedisFieldsWithError("set", retentionEntriesKey, duration, err.Error()),
+		)
+		redisSetErr.Inc()
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis SET completed",
+		logger.RedisFields("set", retentionEntriesKey, duration),
+	)
+	redisSetOK.Inc()
+	redisSetDur.Update(float64(duration) / 1000)
+
+	return nil
+}
+
+func (r *RetentionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*retention.Entry, error) {
+	start := time.Now()
+
+	postIDs, err := r.client.ZRangeByScore(ctx, retentionScheduleKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis ZRANGEBYSCORE failed",
+			logger.RedisFieldsWithError("scan", retentionScheduleKey, duration, err.Error()),
+		)
+		redisScanErr.Inc()
+		return nil, fmt.Errorf("redis zrangebyscore: %w", err)
+	}
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	results, err := r.client.HMGet(ctx, retentionEntriesKey, postIDs...).Result()
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis HMGET failed",
+			logger.RedisFieldsWithError("scan", retentionEntriesKey, duration, err.Error()),
+		)
+		redisScanErr.Inc()
+		return nil, fmt.Errorf("redis hmget: %w", err)
+	}
+
+	entries := make([]*retention.Entry, 0, len(results))
+	for i, result := range results {
+		strResult, ok := result.(string)
+		if !ok {
+			// Scheduled without data; drop it so it is not returned again.
+			if err := r.Delete(ctx, postIDs[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		entry, err := toRetentionEntry(strResult)
+		if err != nil {
+			r.logger.Warn("Failed to unmarshal retention entry, dropping it",
+				slog.String("post_id", postIDs[i]),
+				slog.String("error", err.Error()),
+			)
+			if err := r.Delete(ctx, postIDs[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis ZRANGEBYSCORE+HMGET completed",
+		logger.RedisFields("scan", retentionScheduleKey, duration),
+		slog.Int("count", len(entries)),
+	)
+	redisScanOK.Inc()
+	redisScanDur.Update(float64(duration) / 1000)
+
+	return entries, nil
+}
+
+func toRetentionEntry(data string) (*retention.Entry, error) {
+	var d retentionData
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return nil, err
+	}
+	attachment, err := post.AttachmentFromJSON(d.Attachment)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal attachment: %w", err)
+	}
+	return retention.RestoreEntry(
+		d.PostID,
+		d.ChannelID,
+		alert.RestoreFingerprint(d.Fingerprint),
+		d.AlertName,
+		*attachment,
+		d.ResolvedAt,
+		d.DueAt,
+		d.ArchivedPostID,
+	), nil
+}
+
+func (r *RetentionRepository) Delete(ctx context.Context, postID string) error {
+	start := time.Now()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, retentionScheduleKey, postID)
+		pipe.HDel(ctx, retentionEntriesKey, postID)
+		return nil
+	})
+	if err != nil {
+		duration := time.Since(start).Milliseconds()
+		r.logger.Error("Redis DEL failed",
+			logger.RedisFieldsWithError("del", retentionEntriesKey, duration, err.Error()),
+		)
+		redisDelErr.Inc()
+		return fmt.Errorf("redis del: %w", err)
+	}
+
+	duration := time.Since(start).Milliseconds()
+	r.logger.Debug("Redis DEL completed",
+		logger.RedisFields("del", retentionEntriesKey, duration),
+	)
+	redisDelOK.Inc()
+
+	return nil
+}
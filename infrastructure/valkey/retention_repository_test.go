@@ -0,0 +1,96 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/retention"
+)
+
+func setupTestRetentionRepository(t *testing.T) (*RetentionRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	return NewRetentionRepository(client, slog.New(slog.NewJSONHandler(io.Discard, nil))), mr
+}
+
+func TestRetentionSaveAndFindDue(t *testing.T) {
+	repo, _ := setupTestRetentionRepository(t)
+	ctx := context.Background()
+	resolvedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, postID := range []string{"post-late", "post-early"} {
+		entry := retention.NewEntry(postID, "channel-1", alert.RestoreFingerprint("fp-"+postID), "Disk full",
+			post.Attachment{Color: "#00CC00", Title: "✅ Disk full"}, resolvedAt, resolvedAt.Add(time.Duration(2-i)*time.Hour))
+		require.NoError(t, repo.Save(ctx, entry))
+	}
+
+	due, err := repo.FindDue(ctx, resolvedAt.Add(30*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.FindDue(ctx, resolvedAt.Add(3*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "post-early", due[0].PostID(), "earliest first")
+	assert.Equal(t, "post-late", due[1].PostID())
+	assert.Equal(t, "channel-1", due[0].ChannelID())
+	assert.Equal(t, "fp-post-early", due[0].Fingerprint().Value())
+	assert.Equal(t, "Disk full", due[0].AlertName())
+	assert.Equal(t, "✅ Disk full", due[0].Attachment().Title)
+	assert.True(t, resolvedAt.Equal(due[0].ResolvedAt()))
+
+	due, err = repo.FindDue(ctx, resolvedAt.Add(3*time.Hour), 1)
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+}
+
+func TestRetentionSaveReplacesAndDelete(t *testing.T) {
+	repo, mr := setupTestRetentionRepository(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	entry := retention.NewEntry("post-1", "channel-1", alert.RestoreFingerprint("fp-1"), "Disk full", post.Attachment{}, now, now)
+	require.NoError(t, repo.Save(ctx, entry))
+	entry.MarkArchived("archive-1")
+	require.NoError(t, repo.Save(ctx, entry))
+
+	due, err := repo.FindDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "archive-1", due[0].ArchivedPostID())
+
+	require.NoError(t, repo.Delete(ctx, "post-1"))
+	due, err = repo.FindDue(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	assert.False(t, mr.Exists(retentionScheduleKey))
+}
+
+func TestRetentionFindDueDropsBrokenEntries(t *testing.T) {
+	repo, mr := setupTestRetentionRepository(t)
+	ctx := context.Background()
+
+	_, err := mr.ZAdd(retentionScheduleKey, 1, "post-missing")
+	require.NoError(t, err)
+	_, err = mr.ZAdd(retentionScheduleKey, 2, "post-broken")
+	require.NoError(t, err)
+	mr.HSet(retentionEntriesKey, "post-broken", "not json")
+
+	due, err := repo.FindDue(ctx, time.Unix(10, 0), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	assert.False(t, mr.Exists(retentionScheduleKey))
+}
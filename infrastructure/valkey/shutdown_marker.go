@@ -0,0 +1,45 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	shutdownKeyPrefix = "kmbridge:shutdown:"
+
+	// shutdownMarkerTTL keeps markers of replicas that never come back from
+	// piling up.
+	shutdownMarkerTTL = 7 * 24 * time.Hour
+)
+
+// ShutdownMarker records when each replica last shut down and whether it
+// drained everything it had accepted, so an operator can tell a clean stop
+// from one that lost alerts or updates.
+type ShutdownMarker struct {
+	client *redis.Client
+}
+
+func NewShutdownMarker(client *redis.Client) *ShutdownMarker {
+	return &ShutdownMarker{client: client}
+}
+
+// Write stores the marker of instance as a hash with the shutdown time and
+// whether the drain completed.
+func (m *ShutdownMarker) Write(ctx context.Context, instance string, at time.Time, drained bool) error {
+	key := shutdownKeyPrefix + instance
+	pipe := m.client.TxPipeline()
+	pipe.HSet(ctx, key,
+		"at", at.UTC().Format(time.RFC3339),
+		"drained", strconv.FormatBool(drained),
+	)
+	pipe.Expire(ctx, key, shutdownMarkerTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis write shutdown marker: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package valkey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownMarker(t *testing.T) {
+	mr := miniredis.RunT(t)
+	marker := NewShutdownMarker(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	require.NoError(t, marker.Write(context.Background(), "bridge-0", at, false))
+
+	key := shutdownKeyPrefix + "bridge-0"
+	assert.Equal(t, "2026-03-01T11:00:00Z", mr.HGet(key, "at"))
+	assert.Equal(t, "false", mr.HGet(key, "drained"))
+	assert.Equal(t, shutdownMarkerTTL, mr.TTL(key))
+}
@@ -0,0 +1,38 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const statusBoardsKey = "kmbridge:status_boards"
+
+// StatusBoardStore keeps the status board post of each channel in a single
+// hash keyed by channel ID. Boards do not expire: a channel keeps its board
+// while it has none to show.
+type StatusBoardStore struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewStatusBoardStore(client *redis.Client, logger *slog.Logger) *StatusBoardStore {
+	return &StatusBoardStore{client: client, logger: logger}
+}
+
+func (s *StatusBoardStore) GetStatusBoards(ctx context.Context) (map[string]string, error) {
+	boards, err := s.client.HGetAll(ctx, statusBoardsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis hgetall: %w", err)
+	}
+	return boards, nil
+}
+
+func (s *StatusBoardStore) SetStatusBoard(ctx context.Context, channelID, postID string) error {
+	if err := s.client.HSet(ctx, statusBoardsKey, channelID, postID).Err(); err != nil {
+		return fmt.Errorf("redis hset: %w", err)
+	}
+	return nil
+}
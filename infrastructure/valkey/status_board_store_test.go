@@ -0,0 +1,31 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusBoardStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewStatusBoardStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	boards, err := store.GetStatusBoards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, boards)
+
+	require.NoError(t, store.SetStatusBoard(ctx, "channel-1", "post-1"))
+	require.NoError(t, store.SetStatusBoard(ctx, "channel-2", "post-2"))
+	require.NoError(t, store.SetStatusBoard(ctx, "channel-1", "post-3"))
+
+	boards, err = store.GetStatusBoards(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"channel-1": "post-3", "channel-2": "post-2"}, boards)
+}
@@ -0,0 +1,48 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const userEmailKeyPrefix = "kmbridge:user_email:"
+
+// UserEmailCache keeps the Mattermost username found for each email in plain
+// Valkey keys, so every replica and restart reuses the lookups.
+type UserEmailCache struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+func NewUserEmailCache(client *redis.Client, logger *slog.Logger) *UserEmailCache {
+	return &UserEmailCache{client: client, logger: logger}
+}
+
+func (c *UserEmailCache) GetUsername(ctx context.Context, email string) (string, bool, error) {
+	username, err := c.client.Get(ctx, userEmailKey(email)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("redis get: %w", err)
+	}
+	return username, true, nil
+}
+
+func (c *UserEmailCache) SetUsername(ctx context.Context, email, username string, ttl time.Duration) error {
+	if err := c.client.Set(ctx, userEmailKey(email), username, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// userEmailKey ignores case, as emails are matched case-insensitively.
+func userEmailKey(email string) string {
+	return userEmailKeyPrefix + strings.ToLower(email)
+}
@@ -0,0 +1,43 @@
+package valkey
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEmailCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cache := NewUserEmailCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	_, ok, err := cache.GetUsername(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, cache.SetUsername(ctx, "Alice@Example.com", "alice", time.Hour))
+	assert.Equal(t, time.Hour, mr.TTL(userEmailKeyPrefix+"alice@example.com"))
+
+	username, ok, err := cache.GetUsername(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "alice", username)
+
+	require.NoError(t, cache.SetUsername(ctx, "nobody@example.com", "", time.Hour))
+	username, ok, err = cache.GetUsername(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.True(t, ok, "a miss is cached too")
+	assert.Empty(t, username)
+
+	mr.FastForward(time.Hour)
+	_, ok, err = cache.GetUsername(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
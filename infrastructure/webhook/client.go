@@ -0,0 +1,68 @@
+// Package webhook POSTs custom action payloads to URLs outside Keep and
+// Mattermost.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
+)
+
+type Client struct {
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+func NewClient(logger *slog.Logger) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			// A redirect would turn the POST into a GET; report it instead.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Send POSTs body as JSON to url. Any status other than 2xx is an error.
+// Requests are not retried, since the receiver may act on each of them.
+func (c *Client) Send(ctx context.Context, url string, body []byte) error {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Webhook request failed",
+			logger.ExternalFieldsWithError("webhook", url, "POST", 0, time.Since(start).Milliseconds(), err.Error()),
+		)
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	duration := time.Since(start).Milliseconds()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.logger.Error("Webhook request non-2xx",
+			logger.ExternalFieldsWithError("webhook", url, "POST", resp.StatusCode, duration, string(respBody)),
+		)
+		return fmt.Errorf("send webhook: status %d", resp.StatusCode)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	c.logger.Debug("Webhook request completed",
+		logger.ExternalFields("webhook", url, "POST", resp.StatusCode, duration),
+	)
+	return nil
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var capturedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		capturedBody = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewClient(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	require.NoError(t, client.Send(context.Background(), server.URL+"/hooks/restart", []byte(`{"pod":"api-1"}`)))
+	assert.Equal(t, `{"pod":"api-1"}`, capturedBody)
+}
+
+func TestSendFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	err := client.Send(context.Background(), server.URL, []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+
+	err = client.Send(context.Background(), server.URL+"/moved", []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 302", "redirects are not followed")
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+// HandleAlertmanager accepts Alertmanager webhook payloads so Prometheus
+// alerts can be sent to the bridge without Keep in between. Every alert in
+// the group is handled like a Keep webhook; if any fails the request fails
+// and Alertmanager retries the whole group, which is safe because alerts are
+// tracked by fingerprint. With a queue set, alerts are queued and the request
+// answered with 202 Accepted; if any alert cannot be queued the request fails
+// with 503 and Alertmanager retries the group.
+func (h *WebhookHandler) HandleAlertmanager(c *gin.Context) {
+	var input dto.AlertmanagerWebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Error("Failed to parse Alertmanager payload", slog.String("error", err.Error()))
+		invalidBody(c, err)
+		return
+	}
+	if input.Version != "" && input.Version != "4" {
+		h.logger.Error("Unsupported Alertmanager payload version", slog.String("version", input.Version))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported payload version"})
+		return
+	}
+
+	h.logger.Info("Incoming Alertmanager payload",
+		slog.String("receiver", input.Receiver),
+		slog.String("group_key", input.GroupKey),
+		slog.String("status", input.Status),
+		slog.Int("alerts", len(input.Alerts)),
+	)
+
+	if h.queue != nil {
+		for _, alertInput := range input.KeepAlertInputs() {
+			if err := h.queue.Enqueue(c.Request.Context(), alertInput); err != nil {
+				h.logger.Error("Failed to queue Alertmanager alert",
+					slog.String("fingerprint", alertInput.Fingerprint),
+					slog.String("error", err.Error()),
+				)
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "alert queue unavailable"})
+				return
+			}
+		}
+		c.JSON(http.StatusAccepted, gin.H{"status": "queued", "alerts": len(input.Alerts)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var errs []error
+	for _, alertInput := range input.KeepAlertInputs() {
+		if err := h.handleAlert.Execute(ctx, alertInput); err != nil {
+			errs = append(errs, fmt.Errorf("alert %s: %w", alertInput.Fingerprint, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		h.logger.Error("Failed to handle Alertmanager alerts",
+			slog.Int("failed", len(errs)),
+			slog.String("error", err.Error()),
+		)
+		handlingFailed(c, ctx)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "alerts": len(input.Alerts)})
+}
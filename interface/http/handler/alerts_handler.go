@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+type ActiveAlerts interface {
+	List(ctx context.Context) ([]dto.ActiveAlertOutput, error)
+	Delete(ctx context.Context, fingerprint string) error
+}
+
+// AlertsHandler lets operators list the alerts the bridge tracks and drop
+// stale ones.
+type AlertsHandler struct {
+	alerts ActiveAlerts
+	logger *slog.Logger
+}
+
+func NewAlertsHandler(alerts ActiveAlerts, logger *slog.Logger) *AlertsHandler {
+	return &AlertsHandler{alerts: alerts, logger: logger}
+}
+
+// HandleList serves GET /alerts.
+func (h *AlertsHandler) HandleList(c *gin.Context) {
+	alerts, err := h.alerts.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list active alerts", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+// HandleDelete serves DELETE /alerts/:fingerprint.
+func (h *AlertsHandler) HandleDelete(c *gin.Context) {
+	fingerprint := c.Param("fingerprint")
+	if _, err := alert.NewFingerprint(fingerprint); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fingerprint"})
+		return
+	}
+	if err := h.alerts.Delete(c.Request.Context(), fingerprint); err != nil {
+		if errors.Is(err, post.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		h.logger.Error("Failed to delete active alert",
+			slog.String("fingerprint", fingerprint),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
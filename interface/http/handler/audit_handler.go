@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type AuditTrail interface {
+	History(ctx context.Context, fingerprint string) ([]dto.AuditEventOutput, error)
+}
+
+// AuditHandler serves the recorded lifecycle of an alert.
+type AuditHandler struct {
+	trail  AuditTrail
+	logger *slog.Logger
+}
+
+func NewAuditHandler(trail AuditTrail, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{trail: trail, logger: logger}
+}
+
+// HandleHistory serves GET /alerts/:fingerprint/history. An alert without
+// recorded events has an empty history rather than a 404, since events
+// expire independently of the alert.
+func (h *AuditHandler) HandleHistory(c *gin.Context) {
+	fingerprint := c.Param("fingerprint")
+	if _, err := alert.NewFingerprint(fingerprint); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fingerprint"})
+		return
+	}
+
+	events, err := h.trail.History(c.Request.Context(), fingerprint)
+	if err != nil {
+		h.logger.Error("Failed to read alert history",
+			slog.String("fingerprint", fingerprint),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"fingerprint": fingerprint, "events": events})
+}
@@ -20,7 +20,7 @@ func NewCallbackHandler(handleCallback port.CallbackUseCase) *CallbackHandlerHTT
 func (h *CallbackHandlerHTTP) HandleCallback(c *gin.Context) {
 	var input dto.MattermostCallbackInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		invalidBody(c, err)
 		return
 	}
 
@@ -30,6 +30,20 @@ func (h *CallbackHandlerHTTP) HandleCallback(c *gin.Context) {
 		return
 	}
 
+	if result.Ephemeral != "" {
+		if result.RunAsync {
+			h.handleCallback.ExecuteAsync(input)
+		}
+		c.JSON(http.StatusOK, gin.H{"ephemeral_text": result.Ephemeral})
+		return
+	}
+
+	// The alert is handled once the opened dialog is submitted.
+	if result.DialogOpened {
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+
 	h.handleCallback.ExecuteAsync(input)
 
 	response := gin.H{
@@ -63,6 +77,19 @@ func attachmentToJSON(a dto.AttachmentDTO) gin.H {
 		if b.Style != "" {
 			action["style"] = b.Style
 		}
+		if b.Type != "" {
+			action["type"] = b.Type
+		}
+		if b.DataSource != "" {
+			action["data_source"] = b.DataSource
+		}
+		if len(b.Options) > 0 {
+			options := make([]gin.H, len(b.Options))
+			for j, o := range b.Options {
+				options[j] = gin.H{"text": o.Text, "value": o.Value}
+			}
+			action["options"] = options
+		}
 		actions[i] = action
 	}
 
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+// CallbackStatuses returns the outcome of button callbacks.
+type CallbackStatuses interface {
+	Status(id string) (dto.CallbackStatusOutput, bool)
+}
+
+// CallbackStatusHandler lets operators look up how the asynchronous phase of
+// a button callback went, by the ID logged with it and shown on posts the
+// watchdog rewrote.
+type CallbackStatusHandler struct {
+	statuses CallbackStatuses
+	logger   *slog.Logger
+}
+
+func NewCallbackStatusHandler(statuses CallbackStatuses, logger *slog.Logger) *CallbackStatusHandler {
+	return &CallbackStatusHandler{statuses: statuses, logger: logger}
+}
+
+// HandleStatus serves GET /callbacks/:id.
+func (h *CallbackStatusHandler) HandleStatus(c *gin.Context) {
+	status, ok := h.statuses.Status(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+)
+
+type CorrelationResolver interface {
+	Resolve(ctx context.Context, id string) (*dto.CorrelationOutput, error)
+}
+
+// CorrelationHandler looks up correlation records so tools can move between
+// an alert fingerprint, its Mattermost posts, Keep incident and ticket.
+type CorrelationHandler struct {
+	resolver CorrelationResolver
+	logger   *slog.Logger
+}
+
+func NewCorrelationHandler(resolver CorrelationResolver, logger *slog.Logger) *CorrelationHandler {
+	return &CorrelationHandler{resolver: resolver, logger: logger}
+}
+
+// HandleResolve serves GET /correlation/:id, where id is any identifier the
+// record holds.
+func (h *CorrelationHandler) HandleResolve(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" || len(id) > 512 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	output, err := h.resolver.Resolve(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, correlation.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		h.logger.Error("Failed to resolve correlation",
+			slog.String("id", id),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+)
+
+type DeadLetterQueue interface {
+	List(ctx context.Context) ([]dto.DeadLetterOutput, error)
+	Replay(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DeadLetterHandler lets operators inspect Mattermost deliveries that failed
+// and replay or discard them.
+type DeadLetterHandler struct {
+	queue  DeadLetterQueue
+	logger *slog.Logger
+}
+
+func NewDeadLetterHandler(queue DeadLetterQueue, logger *slog.Logger) *DeadLetterHandler {
+	return &DeadLetterHandler{queue: queue, logger: logger}
+}
+
+// HandleList serves GET /deadletter.
+func (h *DeadLetterHandler) HandleList(c *gin.Context) {
+	entries, err := h.queue.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list dead-letter entries", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// HandleReplay serves POST /deadletter/:id/replay.
+func (h *DeadLetterHandler) HandleReplay(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	if err := h.queue.Replay(c.Request.Context(), id); err != nil {
+		if errors.Is(err, deadletter.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		// The entry stays queued; report why it still fails.
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "redelivered"})
+}
+
+// HandleDelete serves DELETE /deadletter/:id.
+func (h *DeadLetterHandler) HandleDelete(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+	if err := h.queue.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, deadletter.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		h.logger.Error("Failed to delete dead-letter entry",
+			slog.String("id", id),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DeadLetterHandler) id(c *gin.Context) (string, bool) {
+	id := c.Param("id")
+	if id == "" || len(id) > 1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// DialogHandler serves the submissions of interactive dialogs opened by the
+// bridge.
+type DialogHandler struct {
+	dialogs port.DialogUseCase
+	logger  *slog.Logger
+}
+
+func NewDialogHandler(dialogs port.DialogUseCase, logger *slog.Logger) *DialogHandler {
+	return &DialogHandler{dialogs: dialogs, logger: logger}
+}
+
+// HandleSubmission serves POST /callback/dialog. Mattermost closes the dialog
+// on an empty response and shows "error" and "errors" in it otherwise.
+func (h *DialogHandler) HandleSubmission(c *gin.Context) {
+	var input dto.MattermostDialogSubmission
+	if err := c.ShouldBindJSON(&input); err != nil {
+		invalidBody(c, err)
+		return
+	}
+
+	result, err := h.dialogs.ExecuteDialogSubmission(input)
+	if err != nil {
+		h.logger.Error("Failed to handle dialog submission",
+			slog.String("callback_id", input.CallbackID),
+			slog.String("error", err.Error()),
+		)
+		c.JSON(http.StatusOK, gin.H{"error": "Failed to process the dialog, try again"})
+		return
+	}
+
+	response := gin.H{}
+	if result.Error != "" {
+		response["error"] = result.Error
+	}
+	if len(result.Errors) > 0 {
+		response["errors"] = result.Errors
+	}
+	c.JSON(http.StatusOK, response)
+}
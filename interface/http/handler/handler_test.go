@@ -3,12 +3,18 @@ package handler
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -18,6 +24,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port/portmock"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/correlation"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+	"github.com/alexmorbo/keep-mattermost-bridge/interface/http/middleware"
 )
 
 func testLogger() *slog.Logger {
@@ -149,6 +160,30 @@ func TestWebhookHandlerInvalidJSON(t *testing.T) {
 	assert.Equal(t, "invalid request body", response["error"])
 }
 
+func TestWebhookHandlerRequestLimits(t *testing.T) {
+	mockUseCase := &mockAlertExecutor{
+		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
+
+	router := setupTestRouter()
+	router.POST("/webhook", middleware.Limit(middleware.EndpointLimit{MaxBodyBytes: 200, Timeout: 20 * time.Millisecond}), handler.HandleAlert)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewBufferString(`{"name":"`+strings.Repeat("a", 300)+`"}`))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "bodies beyond the limit are refused while reading")
+
+	req = httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewBufferString(`{"name":"test-alert","status":"firing","severity":"critical","fingerprint":"abc123"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestTimeout, w.Code, "handling that outlasts the timeout is answered 408")
+}
+
 func TestWebhookHandlerUseCaseError(t *testing.T) {
 	mockUseCase := &mockAlertExecutor{
 		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
@@ -251,6 +286,152 @@ func TestCallbackHandlerValidJSON(t *testing.T) {
 	assert.Len(t, attachments, 1)
 }
 
+func TestCallbackHandlerEphemeral(t *testing.T) {
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			return &dto.CallbackOutput{Ephemeral: "**kubectl**"}, nil
+		},
+	}
+
+	handler := &CallbackHandlerHTTP{handleCallback: mockUseCase}
+
+	router := setupTestRouter()
+	router.POST("/callback", handler.HandleCallback)
+
+	body, err := json.Marshal(dto.MattermostCallbackInput{
+		UserID:  "user-123",
+		Context: map[string]string{"action": "commands"},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/callback", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ephemeral_text": "**kubectl**"}`, w.Body.String())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, mockUseCase.wasAsyncCalled(), "ephemeral replies have no async phase")
+}
+
+func TestCallbackHandlerEphemeralWithAsyncPhase(t *testing.T) {
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			return &dto.CallbackOutput{Ephemeral: "Running **Restart pod**…", RunAsync: true}, nil
+		},
+	}
+
+	handler := &CallbackHandlerHTTP{handleCallback: mockUseCase}
+
+	router := setupTestRouter()
+	router.POST("/callback", handler.HandleCallback)
+
+	body, err := json.Marshal(dto.MattermostCallbackInput{
+		UserID:  "user-123",
+		Context: map[string]string{"action": "custom", "custom_action": "Restart pod"},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/callback", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ephemeral_text": "Running **Restart pod**…"}`, w.Body.String())
+	assert.True(t, mockUseCase.wasAsyncCalled())
+}
+
+func TestCallbackHandlerDialogOpened(t *testing.T) {
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			assert.Equal(t, "trigger-1", input.TriggerID)
+			return &dto.CallbackOutput{DialogOpened: true}, nil
+		},
+	}
+	handler := &CallbackHandlerHTTP{handleCallback: mockUseCase}
+
+	router := setupTestRouter()
+	router.POST("/callback", handler.HandleCallback)
+
+	body := `{"user_id":"user-123","post_id":"post-456","trigger_id":"trigger-1","context":{"action":"resolve"}}`
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/callback", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{}`, w.Body.String())
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, mockUseCase.wasAsyncCalled(), "the dialog submission handles the alert")
+}
+
+type mockDialogUseCase struct {
+	output *dto.DialogSubmissionOutput
+	err    error
+	input  dto.MattermostDialogSubmission
+}
+
+func (m *mockDialogUseCase) ExecuteDialogSubmission(input dto.MattermostDialogSubmission) (*dto.DialogSubmissionOutput, error) {
+	m.input = input
+	return m.output, m.err
+}
+
+func TestDialogHandlerSubmission(t *testing.T) {
+	submit := func(t *testing.T, uc *mockDialogUseCase, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		router := setupTestRouter()
+		router.POST("/callback/dialog", NewDialogHandler(uc, testLogger()).HandleSubmission)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/callback/dialog", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	body := `{"callback_id":"resolve","state":"{}","user_id":"user-123","submission":{"resolution_note":"fixed"}}`
+
+	t.Run("accepted submission closes the dialog", func(t *testing.T) {
+		uc := &mockDialogUseCase{output: &dto.DialogSubmissionOutput{}}
+		w := submit(t, uc, body)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{}`, w.Body.String())
+		assert.Equal(t, "resolve", uc.input.CallbackID)
+		assert.Equal(t, "fixed", uc.input.Submission["resolution_note"])
+	})
+
+	t.Run("element errors keep the dialog open", func(t *testing.T) {
+		uc := &mockDialogUseCase{output: &dto.DialogSubmissionOutput{Errors: map[string]string{"resolution_note": "required"}}}
+		w := submit(t, uc, body)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"errors":{"resolution_note":"required"}}`, w.Body.String())
+	})
+
+	t.Run("use case error is shown in the dialog", func(t *testing.T) {
+		uc := &mockDialogUseCase{err: errors.New("bad state")}
+		w := submit(t, uc, body)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"error":"Failed to process the dialog, try again"}`, w.Body.String())
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := submit(t, &mockDialogUseCase{}, "not json")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestCallbackHandlerInvalidJSON(t *testing.T) {
 	mockUseCase := &mockCallbackExecutor{}
 	handler := &CallbackHandlerHTTP{handleCallback: mockUseCase}
@@ -356,6 +537,60 @@ func TestHealthHandlerReadyHealthy(t *testing.T) {
 	assert.Equal(t, "ready", response["status"])
 }
 
+type staticMaintenanceStatus []string
+
+func (s staticMaintenanceStatus) OpenWindows() []string { return s }
+
+func TestHealthHandlerReadyMaintenance(t *testing.T) {
+	mockRepo := &mockPostRepositoryPinger{
+		pingFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	handler := NewHealthHandler(mockRepo)
+	router := setupTestRouter()
+	router.GET("/health/ready", handler.Ready)
+
+	ready := func() string {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/health/ready", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	handler.SetMaintenance(staticMaintenanceStatus(nil))
+	assert.JSONEq(t, `{"status":"ready","maintenance_windows":[]}`, ready())
+
+	handler.SetMaintenance(staticMaintenanceStatus{"db-upgrade"})
+	assert.JSONEq(t, `{"status":"ready","maintenance_windows":["db-upgrade"]}`, ready())
+}
+
+type staticCircuitStatus string
+
+func (s staticCircuitStatus) CircuitState() string { return string(s) }
+
+func TestHealthHandlerReadyKeepCircuit(t *testing.T) {
+	mockRepo := &mockPostRepositoryPinger{
+		pingFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	handler := NewHealthHandler(mockRepo)
+	handler.SetKeepCircuit(staticCircuitStatus("open"))
+	router := setupTestRouter()
+	router.GET("/health/ready", handler.Ready)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/health/ready", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "an open circuit keeps the bridge ready")
+	assert.JSONEq(t, `{"status":"ready","keep_circuit":"open"}`, w.Body.String())
+}
+
 func TestHealthHandlerReadyUnhealthy(t *testing.T) {
 	mockRepo := &mockPostRepositoryPinger{
 		pingFunc: func(ctx context.Context) error {
@@ -450,6 +685,195 @@ func TestCallbackHandlerAcknowledgeAction(t *testing.T) {
 	assert.False(t, hasEphemeral, "ephemeral_text should not be present in two-phase response")
 }
 
+const alertmanagerPayload = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"DiskFull\"}",
+	"status": "firing",
+	"receiver": "bridge",
+	"alerts": [
+		{"status": "firing", "labels": {"alertname": "DiskFull", "severity": "critical"}, "fingerprint": "fp-1", "startsAt": "2024-01-01T00:00:00.123Z"},
+		{"status": "resolved", "labels": {"alertname": "DiskFull", "severity": "warning"}, "fingerprint": "fp-2"}
+	]
+}`
+
+func TestWebhookHandlerAlertmanager(t *testing.T) {
+	var inputs []dto.KeepAlertInput
+	mockUseCase := &mockAlertExecutor{
+		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			inputs = append(inputs, input)
+			return nil
+		},
+	}
+	handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
+
+	router := setupTestRouter()
+	router.POST("/webhook/alertmanager", handler.HandleAlertmanager)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook/alertmanager", strings.NewReader(alertmanagerPayload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok", "alerts": 2}`, w.Body.String())
+	require.Len(t, inputs, 2)
+	assert.Equal(t, "fp-1", inputs[0].Fingerprint)
+	assert.Equal(t, "DiskFull", inputs[0].Name)
+	assert.Equal(t, "firing", inputs[0].Status)
+	assert.Equal(t, "critical", inputs[0].Severity)
+	assert.Equal(t, "fp-2", inputs[1].Fingerprint)
+	assert.Equal(t, "resolved", inputs[1].Status)
+}
+
+type mockAlertQueue struct {
+	enqueued []dto.KeepAlertInput
+	err      error
+}
+
+func (m *mockAlertQueue) Enqueue(ctx context.Context, input dto.KeepAlertInput) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, input)
+	return nil
+}
+
+func TestWebhookHandlerQueuesAlert(t *testing.T) {
+	mockUseCase := &mockAlertExecutor{
+		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			t.Fatal("use case should not be called when a queue is set")
+			return nil
+		},
+	}
+	queue := &mockAlertQueue{}
+	handler := NewWebhookHandler(mockUseCase, testLogger())
+	handler.SetQueue(queue)
+
+	router := setupTestRouter()
+	router.POST("/webhook", handler.HandleAlert)
+
+	body := `{"id":"alert-123","name":"test-alert","status":"firing","severity":"critical","fingerprint":"abc123"}`
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"status": "queued"}`, w.Body.String())
+	require.Len(t, queue.enqueued, 1)
+	assert.Equal(t, "abc123", queue.enqueued[0].Fingerprint)
+}
+
+func TestWebhookHandlerQueueFull(t *testing.T) {
+	handler := NewWebhookHandler(&mockAlertExecutor{}, testLogger())
+	handler.SetQueue(&mockAlertQueue{err: errors.New("alert queue full")})
+
+	router := setupTestRouter()
+	router.POST("/webhook", handler.HandleAlert)
+	router.POST("/webhook/alertmanager", handler.HandleAlertmanager)
+
+	body := `{"id":"alert-123","name":"test-alert","status":"firing","severity":"critical","fingerprint":"abc123"}`
+	for path, payload := range map[string]string{"/webhook": body, "/webhook/alertmanager": alertmanagerPayload} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, path, strings.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+	}
+}
+
+func TestWebhookHandlerAlertmanagerQueuesAlerts(t *testing.T) {
+	queue := &mockAlertQueue{}
+	handler := NewWebhookHandler(&mockAlertExecutor{}, testLogger())
+	handler.SetQueue(queue)
+
+	router := setupTestRouter()
+	router.POST("/webhook/alertmanager", handler.HandleAlertmanager)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook/alertmanager", strings.NewReader(alertmanagerPayload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"status": "queued", "alerts": 2}`, w.Body.String())
+	require.Len(t, queue.enqueued, 2)
+	assert.Equal(t, "fp-1", queue.enqueued[0].Fingerprint)
+	assert.Equal(t, "fp-2", queue.enqueued[1].Fingerprint)
+}
+
+func TestWebhookHandlerAlertmanagerPartialFailure(t *testing.T) {
+	var fingerprints []string
+	mockUseCase := &mockAlertExecutor{
+		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			fingerprints = append(fingerprints, input.Fingerprint)
+			if input.Fingerprint == "fp-1" {
+				return errors.New("mattermost unavailable")
+			}
+			return nil
+		},
+	}
+	handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
+
+	router := setupTestRouter()
+	router.POST("/webhook/alertmanager", handler.HandleAlertmanager)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook/alertmanager", strings.NewReader(alertmanagerPayload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "Alertmanager retries the group")
+	assert.Equal(t, []string{"fp-1", "fp-2"}, fingerprints, "remaining alerts are still handled")
+}
+
+func TestWebhookHandlerAlertmanagerInvalidPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not json", body: "invalid json"},
+		{name: "no alerts", body: `{"version": "4", "alerts": []}`},
+		{name: "unknown status", body: `{"version": "4", "alerts": [{"status": "pending", "labels": {"alertname": "X"}}]}`},
+		{name: "unsupported version", body: `{"version": "5", "alerts": [{"status": "firing", "labels": {"alertname": "X"}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &mockAlertExecutor{
+				executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+					t.Fatal("use case must not be called")
+					return nil
+				},
+			}
+			handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
+
+			router := setupTestRouter()
+			router.POST("/webhook/alertmanager", handler.HandleAlertmanager)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook/alertmanager", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestWebhookHandlerMissingFields(t *testing.T) {
 	mockUseCase := &mockAlertExecutor{
 		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
@@ -484,6 +908,33 @@ func TestWebhookHandlerMissingFields(t *testing.T) {
 	assert.Equal(t, "invalid request body", response["error"])
 }
 
+func TestWebhookHandlerCoercesPayload(t *testing.T) {
+	var received dto.KeepAlertInput
+	mockUseCase := &mockAlertExecutor{
+		executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+			received = input
+			return nil
+		},
+	}
+	handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
+
+	router := setupTestRouter()
+	router.POST("/webhook", handler.HandleAlert)
+
+	body := `{"name": "test-alert", "status": "firing", "severity": 5, "fingerprint": "abc123", "source": "prometheus", "labels": {"port": 8080}}`
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/webhook", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "critical", received.Severity)
+	assert.Equal(t, dto.FlexStrings{"prometheus"}, received.Source)
+	assert.Equal(t, dto.FlexLabels{"port": "8080"}, received.Labels)
+}
+
 func TestWebhookHandlerEmptyBody(t *testing.T) {
 	mockUseCase := &mockAlertExecutor{}
 	handler := &WebhookHandler{handleAlert: mockUseCase, logger: testLogger()}
@@ -638,3 +1089,792 @@ func TestCallbackHandlerTwoPhaseFlow(t *testing.T) {
 		t.Fatal("Async execution did not complete in time")
 	}
 }
+
+type mockSlashCommandExecutor struct {
+	executeFunc func(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error)
+	called      bool
+}
+
+func (m *mockSlashCommandExecutor) Execute(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error) {
+	m.called = true
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, input)
+	}
+	return &dto.SlashCommandOutput{ResponseType: dto.SlashResponseEphemeral, Text: "ok"}, nil
+}
+
+func postSlashCommand(t *testing.T, h *SlashCommandHandler, form url.Values, authHeader string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.POST("/command", h.HandleCommand)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/command", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSlashCommandHandlerValidToken(t *testing.T) {
+	mockUseCase := &mockSlashCommandExecutor{
+		executeFunc: func(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error) {
+			assert.Equal(t, "user-1", input.UserID)
+			assert.Equal(t, "alice", input.UserName)
+			assert.Equal(t, "ch-1", input.ChannelID)
+			assert.Equal(t, "/keep", input.Command)
+			assert.Equal(t, "ack fp-1", input.Text)
+			return &dto.SlashCommandOutput{ResponseType: dto.SlashResponseEphemeral, Text: "Alert `fp-1` acknowledged."}, nil
+		},
+	}
+	h := NewSlashCommandHandler(mockUseCase, "secret", testLogger())
+
+	form := url.Values{
+		"token":      {"secret"},
+		"user_id":    {"user-1"},
+		"user_name":  {"alice"},
+		"channel_id": {"ch-1"},
+		"command":    {"/keep"},
+		"text":       {"ack fp-1"},
+	}
+	w := postSlashCommand(t, h, form, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, mockUseCase.called)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ephemeral", response["response_type"])
+	assert.Equal(t, "Alert `fp-1` acknowledged.", response["text"])
+}
+
+func TestSlashCommandHandlerAuthorizationHeader(t *testing.T) {
+	mockUseCase := &mockSlashCommandExecutor{}
+	h := NewSlashCommandHandler(mockUseCase, "secret", testLogger())
+
+	w := postSlashCommand(t, h, url.Values{"text": {"list"}}, "Token secret")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, mockUseCase.called)
+}
+
+func TestSlashCommandHandlerInvalidToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		token      string
+	}{
+		{name: "wrong token", configured: "secret", token: "guess"},
+		{name: "missing token", configured: "secret", token: ""},
+		{name: "no token configured", configured: "", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &mockSlashCommandExecutor{}
+			h := NewSlashCommandHandler(mockUseCase, tt.configured, testLogger())
+
+			w := postSlashCommand(t, h, url.Values{"token": {tt.token}, "text": {"list"}}, "")
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.False(t, mockUseCase.called)
+		})
+	}
+}
+
+func TestSlashCommandHandlerUseCaseError(t *testing.T) {
+	mockUseCase := &mockSlashCommandExecutor{
+		executeFunc: func(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error) {
+			return nil, errors.New("keep down")
+		},
+	}
+	h := NewSlashCommandHandler(mockUseCase, "secret", testLogger())
+
+	w := postSlashCommand(t, h, url.Values{"token": {"secret"}, "text": {"resolve fp-1"}}, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ephemeral", response["response_type"])
+	assert.Contains(t, response["text"], "Command failed")
+	assert.NotContains(t, response["text"], "keep down")
+}
+
+type mockCorrelationResolver struct {
+	records map[string]*dto.CorrelationOutput
+	err     error
+}
+
+func (m *mockCorrelationResolver) Resolve(ctx context.Context, id string) (*dto.CorrelationOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if out, ok := m.records[id]; ok {
+		return out, nil
+	}
+	return nil, correlation.ErrNotFound
+}
+
+func getCorrelation(t *testing.T, h *CorrelationHandler, id string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.GET("/correlation/:id", h.HandleResolve)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/correlation/"+id, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCorrelationHandlerResolve(t *testing.T) {
+	record := &dto.CorrelationOutput{
+		Fingerprint: "fp-1",
+		PostIDs:     []string{"post-1"},
+		IncidentID:  "inc-1",
+		TicketKey:   "OPS-42",
+		UpdatedAt:   time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	h := NewCorrelationHandler(&mockCorrelationResolver{records: map[string]*dto.CorrelationOutput{"OPS-42": record}}, testLogger())
+
+	w := getCorrelation(t, h, "OPS-42")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"fingerprint": "fp-1",
+		"post_ids": ["post-1"],
+		"incident_id": "inc-1",
+		"ticket_key": "OPS-42",
+		"updated_at": "2026-01-01T12:00:00Z"
+	}`, w.Body.String())
+
+	w = getCorrelation(t, h, "OPS-43")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCorrelationHandlerError(t *testing.T) {
+	h := NewCorrelationHandler(&mockCorrelationResolver{err: errors.New("redis down")}, testLogger())
+
+	w := getCorrelation(t, h, "fp-1")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "redis down")
+}
+
+type mockDeadLetterQueue struct {
+	entries   []dto.DeadLetterOutput
+	replayErr error
+	deleted   []string
+}
+
+func (m *mockDeadLetterQueue) List(ctx context.Context) ([]dto.DeadLetterOutput, error) {
+	return m.entries, nil
+}
+
+func (m *mockDeadLetterQueue) Replay(ctx context.Context, id string) error {
+	if id == "missing" {
+		return deadletter.ErrNotFound
+	}
+	return m.replayErr
+}
+
+func (m *mockDeadLetterQueue) Delete(ctx context.Context, id string) error {
+	if id == "missing" {
+		return deadletter.ErrNotFound
+	}
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func serveDeadLetter(t *testing.T, h *DeadLetterHandler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.GET("/deadletter", h.HandleList)
+	router.POST("/deadletter/:id/replay", h.HandleReplay)
+	router.DELETE("/deadletter/:id", h.HandleDelete)
+
+	req, err := http.NewRequestWithContext(context.Background(), method, path, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeadLetterHandler(t *testing.T) {
+	queue := &mockDeadLetterQueue{entries: []dto.DeadLetterOutput{{
+		ID:            "update:post-1",
+		Kind:          "update",
+		PostID:        "post-1",
+		Title:         "ACK",
+		LastError:     "status 502",
+		Attempts:      2,
+		FailedAt:      time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		LastAttemptAt: time.Date(2026, 1, 1, 12, 2, 0, 0, time.UTC),
+	}}}
+	h := NewDeadLetterHandler(queue, testLogger())
+
+	w := serveDeadLetter(t, h, http.MethodGet, "/deadletter")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"entries": [{
+		"id": "update:post-1",
+		"kind": "update",
+		"post_id": "post-1",
+		"title": "ACK",
+		"last_error": "status 502",
+		"attempts": 2,
+		"exhausted": false,
+		"failed_at": "2026-01-01T12:00:00Z",
+		"last_attempt_at": "2026-01-01T12:02:00Z"
+	}]}`, w.Body.String())
+
+	w = serveDeadLetter(t, h, http.MethodPost, "/deadletter/update:post-1/replay")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	queue.replayErr = errors.New("redeliver update:post-1: status 502")
+	w = serveDeadLetter(t, h, http.MethodPost, "/deadletter/update:post-1/replay")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "status 502")
+
+	w = serveDeadLetter(t, h, http.MethodPost, "/deadletter/missing/replay")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveDeadLetter(t, h, http.MethodDelete, "/deadletter/update:post-1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"update:post-1"}, queue.deleted)
+
+	w = serveDeadLetter(t, h, http.MethodDelete, "/deadletter/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+type mockAuditTrail struct {
+	events []dto.AuditEventOutput
+	err    error
+}
+
+func (m *mockAuditTrail) History(ctx context.Context, fingerprint string) ([]dto.AuditEventOutput, error) {
+	return m.events, m.err
+}
+
+func getHistory(t *testing.T, h *AuditHandler, fingerprint string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.GET("/alerts/:fingerprint/history", h.HandleHistory)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/alerts/"+fingerprint+"/history", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuditHandler(t *testing.T) {
+	trail := &mockAuditTrail{events: []dto.AuditEventOutput{
+		{Kind: "received", Detail: "status firing, severity critical", At: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{Kind: "acknowledged", Actor: "john", Detail: "in Mattermost", At: time.Date(2026, 1, 1, 12, 5, 0, 0, time.UTC)},
+	}}
+	h := NewAuditHandler(trail, testLogger())
+
+	w := getHistory(t, h, "fp-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"fingerprint": "fp-1", "events": [
+		{"kind": "received", "detail": "status firing, severity critical", "at": "2026-01-01T12:00:00Z"},
+		{"kind": "acknowledged", "actor": "john", "detail": "in Mattermost", "at": "2026-01-01T12:05:00Z"}
+	]}`, w.Body.String())
+
+	trail.err = errors.New("redis down")
+	w = getHistory(t, h, "fp-1")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "redis down")
+}
+
+type mockActiveAlerts struct {
+	alerts  []dto.ActiveAlertOutput
+	deleted []string
+}
+
+func (m *mockActiveAlerts) List(ctx context.Context) ([]dto.ActiveAlertOutput, error) {
+	return m.alerts, nil
+}
+
+func (m *mockActiveAlerts) Delete(ctx context.Context, fingerprint string) error {
+	if fingerprint == "missing" {
+		return post.ErrNotFound
+	}
+	m.deleted = append(m.deleted, fingerprint)
+	return nil
+}
+
+func serveAlerts(t *testing.T, h *AlertsHandler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.GET("/alerts", h.HandleList)
+	router.DELETE("/alerts/:fingerprint", h.HandleDelete)
+
+	req, err := http.NewRequestWithContext(context.Background(), method, path, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAlertsHandler(t *testing.T) {
+	alerts := &mockActiveAlerts{alerts: []dto.ActiveAlertOutput{{
+		Fingerprint: "fp-1",
+		AlertName:   "DB down",
+		Severity:    "critical",
+		ChannelID:   "ch-1",
+		PostID:      "post-1",
+		Assignee:    "john",
+		FiringSince: time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC),
+		AgeSeconds:  3600,
+	}}}
+	h := NewAlertsHandler(alerts, testLogger())
+
+	w := serveAlerts(t, h, http.MethodGet, "/alerts")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"alerts": [{
+		"fingerprint": "fp-1",
+		"alert_name": "DB down",
+		"severity": "critical",
+		"channel_id": "ch-1",
+		"post_id": "post-1",
+		"assignee": "john",
+		"firing_since": "2026-01-01T11:00:00Z",
+		"age_seconds": 3600
+	}]}`, w.Body.String())
+
+	w = serveAlerts(t, h, http.MethodDelete, "/alerts/fp-1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"fp-1"}, alerts.deleted)
+
+	w = serveAlerts(t, h, http.MethodDelete, "/alerts/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+type mockIncidentUseCase struct {
+	mockCallbackExecutor
+	received []dto.KeepIncidentInput
+	err      error
+}
+
+func (m *mockIncidentUseCase) Execute(ctx context.Context, input dto.KeepIncidentInput) error {
+	m.received = append(m.received, input)
+	return m.err
+}
+
+func serveIncident(t *testing.T, h *IncidentHandler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter()
+	router.POST("/webhook/incident", h.HandleWebhook)
+	router.POST("/callback/incident", h.HandleCallback)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIncidentHandlerWebhook(t *testing.T) {
+	incidents := &mockIncidentUseCase{}
+	h := NewIncidentHandler(incidents, testLogger())
+
+	w := serveIncident(t, h, "/webhook/incident", `{
+		"id": "inc-1",
+		"user_generated_name": "Database outage",
+		"severity": "critical",
+		"status": "firing",
+		"alerts_count": 3,
+		"start_time": "2024-06-01T10:00:00"
+	}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, incidents.received, 1)
+	assert.Equal(t, "inc-1", incidents.received[0].ID)
+	assert.Equal(t, 3, incidents.received[0].AlertsCount)
+
+	w = serveIncident(t, h, "/webhook/incident", `{"user_generated_name": "no id"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	incidents.err = errors.New("mattermost down")
+	w = serveIncident(t, h, "/webhook/incident", `{"id": "inc-1", "severity": "critical", "status": "firing"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestIncidentHandlerCallback(t *testing.T) {
+	incidents := &mockIncidentUseCase{}
+	incidents.executeImmediateFunc = func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+		assert.Equal(t, "inc-1", input.Context["incident_id"])
+		return &dto.CallbackOutput{Attachment: dto.AttachmentDTO{Title: "Incident: Database outage"}}, nil
+	}
+	h := NewIncidentHandler(incidents, testLogger())
+
+	w := serveIncident(t, h, "/callback/incident", `{"user_id": "u1", "post_id": "p1", "context": {"action": "acknowledge", "incident_id": "inc-1"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Incident: Database outage")
+	assert.True(t, incidents.wasAsyncCalled())
+}
+
+func slackRequest(t *testing.T, secret, payload string, now time.Time) *http.Request {
+	t.Helper()
+	body := url.Values{"payload": {payload}}.Encode()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/slack/actions/acme", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackActionsHandler(t *testing.T) {
+	var ephemeral map[string]any
+	responseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&ephemeral)
+	}))
+	defer responseServer.Close()
+
+	var immediate dto.MattermostCallbackInput
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			immediate = input
+			if input.Context["action"] == "commands" {
+				return &dto.CallbackOutput{Ephemeral: "kubectl"}, nil
+			}
+			return &dto.CallbackOutput{}, nil
+		},
+	}
+	handler := NewSlackActionsHandler(mockUseCase, map[string]string{"acme": "secret"}, testLogger())
+	router := setupTestRouter()
+	router.POST("/slack/actions/:server", handler.HandleActions)
+
+	payload := `{"type":"block_actions","user":{"id":"U1"},"channel":{"id":"C1"},"container":{"message_ts":"1700000000.000100"},` +
+		`"response_url":"` + responseServer.URL + `","actions":[{"value":"{\"action\":\"acknowledge\",\"fingerprint\":\"fp-1\"}"}]}`
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "wrong", payload, time.Now()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "secret", payload, time.Now().Add(-10*time.Minute)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "old requests are rejected")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "secret", payload, time.Now()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "U1", immediate.UserID)
+	assert.Equal(t, "C1", immediate.ChannelID)
+	assert.Equal(t, "C1/1700000000.000100", immediate.PostID)
+	assert.Equal(t, "fp-1", immediate.Context["fingerprint"])
+	assert.Equal(t, "{}", immediate.Context[post.ContextKeyAttachmentJSON])
+	assert.True(t, mockUseCase.wasAsyncCalled())
+
+	commands := strings.Replace(payload, `\"acknowledge\"`, `\"commands\"`, 1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, slackRequest(t, "secret", commands, time.Now()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]any{"response_type": "ephemeral", "replace_original": false, "text": "kubectl"}, ephemeral)
+}
+
+type staticTeamsAuthenticator string
+
+func (a staticTeamsAuthenticator) Authenticate(ctx context.Context, authorization string) error {
+	if authorization != "Bearer "+string(a) {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+type prefixServerIDs string
+
+func (p prefixServerIDs) QualifyID(channelID, id string) string {
+	return string(p) + ":" + id
+}
+
+func TestTeamsActionsHandler(t *testing.T) {
+	var immediate dto.MattermostCallbackInput
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			immediate = input
+			if input.Context["action"] == "commands" {
+				return &dto.CallbackOutput{Ephemeral: "kubectl"}, nil
+			}
+			return &dto.CallbackOutput{}, nil
+		},
+	}
+	var replies []string
+	mockClient := &portmock.MattermostClientMock{
+		ReplyToThreadFunc: func(ctx context.Context, channelID, rootID, message string) error {
+			replies = append(replies, channelID+" "+rootID+" "+message)
+			return nil
+		},
+	}
+	authenticators := map[string]TeamsAuthenticator{"acme": staticTeamsAuthenticator("token")}
+	handler := NewTeamsActionsHandler(mockUseCase, authenticators, mockClient, prefixServerIDs("acme"), testLogger())
+	router := setupTestRouter()
+	router.POST("/teams/messages/:server", handler.HandleMessages)
+
+	send := func(server, token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/teams/messages/"+server, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	activity := `{"type":"message","from":{"id":"29:user-1"},"conversation":{"id":"19:abc@thread.tacv2;messageid=1700000000001"},` +
+		`"replyToId":"1700000000001","value":{"kmbridge":{"action":"acknowledge","fingerprint":"fp-1"}}}`
+
+	assert.Equal(t, http.StatusNotFound, send("other", "token", activity))
+	assert.Equal(t, http.StatusUnauthorized, send("acme", "wrong", activity))
+
+	assert.Equal(t, http.StatusOK, send("acme", "token", `{"type":"conversationUpdate"}`))
+	assert.Empty(t, immediate.PostID, "other activities are ignored")
+
+	assert.Equal(t, http.StatusOK, send("acme", "token", activity))
+	assert.Equal(t, "19:abc@thread.tacv2/29:user-1", immediate.UserID)
+	assert.Equal(t, "19:abc@thread.tacv2", immediate.ChannelID)
+	assert.Equal(t, "19:abc@thread.tacv2/1700000000001", immediate.PostID)
+	assert.Equal(t, "fp-1", immediate.Context["fingerprint"])
+	assert.Equal(t, "{}", immediate.Context[post.ContextKeyAttachmentJSON])
+	assert.True(t, mockUseCase.wasAsyncCalled())
+
+	commands := strings.Replace(activity, `"acknowledge"`, `"commands"`, 1)
+	assert.Equal(t, http.StatusOK, send("acme", "token", commands))
+	assert.Equal(t, []string{"19:abc@thread.tacv2 acme:19:abc@thread.tacv2/1700000000001 kubectl"}, replies)
+}
+
+type fakeTelegramBot struct {
+	contexts map[string]map[string]string
+	answers  []string
+	replies  []string
+}
+
+func (b *fakeTelegramBot) ButtonContext(data string) (map[string]string, bool) {
+	buttonContext, ok := b.contexts[data]
+	return buttonContext, ok
+}
+
+func (b *fakeTelegramBot) AnswerCallback(ctx context.Context, callbackQueryID, text string) error {
+	b.answers = append(b.answers, callbackQueryID+" "+text)
+	return nil
+}
+
+func (b *fakeTelegramBot) ReplyToThread(ctx context.Context, channelID, rootID, message string) error {
+	b.replies = append(b.replies, channelID+" "+rootID+" "+message)
+	return nil
+}
+
+func TestTelegramUpdatesHandler(t *testing.T) {
+	var immediate dto.MattermostCallbackInput
+	mockUseCase := &mockCallbackExecutor{
+		executeImmediateFunc: func(input dto.MattermostCallbackInput) (*dto.CallbackOutput, error) {
+			immediate = input
+			switch input.Context["action"] {
+			case "commands":
+				return &dto.CallbackOutput{Ephemeral: strings.Repeat("kubectl ", 50)}, nil
+			case "resolve":
+				return &dto.CallbackOutput{Ephemeral: "Not permitted"}, nil
+			}
+			return &dto.CallbackOutput{}, nil
+		},
+	}
+	bot := &fakeTelegramBot{contexts: map[string]map[string]string{
+		"ack":      {"action": "acknowledge", "fingerprint": "fp-1"},
+		"resolve":  {"action": "resolve", "fingerprint": "fp-1"},
+		"commands": {"action": "commands", "fingerprint": "fp-1"},
+	}}
+	handler := NewTelegramUpdatesHandler(mockUseCase, map[string]TelegramBot{"acme": bot}, map[string]string{"acme": "secret"}, testLogger())
+	router := setupTestRouter()
+	router.POST("/telegram/updates/:server", handler.HandleUpdates)
+
+	send := func(server, secret, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/updates/"+server, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	click := func(data string) string {
+		return `{"update_id":1,"callback_query":{"id":"q1","from":{"id":777},"message":{"message_id":42,"chat":{"id":-100123}},"data":"` + data + `"}}`
+	}
+
+	assert.Equal(t, http.StatusNotFound, send("other", "secret", click("ack")))
+	assert.Equal(t, http.StatusUnauthorized, send("acme", "wrong", click("ack")))
+	assert.Equal(t, http.StatusOK, send("acme", "secret", `{"update_id":1,"message":{"text":"hi"}}`))
+
+	assert.Equal(t, http.StatusOK, send("acme", "secret", click("ack")))
+	assert.Equal(t, "-100123/777", immediate.UserID)
+	assert.Equal(t, "-100123", immediate.ChannelID)
+	assert.Equal(t, "-100123/42", immediate.PostID)
+	assert.Equal(t, "fp-1", immediate.Context["fingerprint"])
+	assert.Equal(t, "{}", immediate.Context[post.ContextKeyAttachmentJSON])
+	assert.NotContains(t, bot.contexts["ack"], post.ContextKeyAttachmentJSON, "stored contexts are left alone")
+	assert.True(t, mockUseCase.wasAsyncCalled())
+
+	assert.Equal(t, http.StatusOK, send("acme", "secret", click("resolve")))
+	assert.Equal(t, http.StatusOK, send("acme", "secret", click("commands")))
+	assert.Equal(t, http.StatusOK, send("acme", "secret", click("unknown")))
+	assert.Equal(t, []string{"q1 ", "q1 Not permitted", "q1 ", "q1 This button is no longer known to the bridge. It works again once the alert is updated."}, bot.answers)
+	assert.Equal(t, []string{"-100123 -100123/42 " + strings.Repeat("kubectl ", 50)}, bot.replies, "long answers are replied to the card")
+}
+
+type mockAlertPreviewer struct {
+	inputs []dto.KeepAlertInput
+}
+
+func (m *mockAlertPreviewer) Preview(ctx context.Context, input dto.KeepAlertInput) (dto.ReplayOutput, error) {
+	m.inputs = append(m.inputs, input)
+	return dto.ReplayOutput{Fingerprint: input.Fingerprint, Status: input.Status, Severity: input.Severity, Action: dto.ReplayCreate, ChannelID: "ch-1"}, nil
+}
+
+type mockArchivedAlerts map[string]dto.KeepAlertInput
+
+func (m mockArchivedAlerts) AlertPayload(ctx context.Context, id string) (dto.KeepAlertInput, error) {
+	input, ok := m[id]
+	if !ok {
+		return dto.KeepAlertInput{}, deadletter.ErrNotFound
+	}
+	return input, nil
+}
+
+func TestReplayHandler(t *testing.T) {
+	previewer := &mockAlertPreviewer{}
+	var executed []string
+	executor := &mockAlertExecutor{executeFunc: func(ctx context.Context, input dto.KeepAlertInput) error {
+		executed = append(executed, input.Fingerprint)
+		return nil
+	}}
+	archive := mockArchivedAlerts{"alert:fp-2": {Fingerprint: "fp-2", Name: "Disk full", Severity: "high", Status: "firing"}}
+	h := NewReplayHandler(previewer, executor, archive, testLogger())
+	router := setupTestRouter()
+	router.POST("/replay", h.HandleReplay)
+	replay := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/replay", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := replay(`{"alert":{"fingerprint":"fp-1","name":"DB down","severity":"critical","status":"firing"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"fingerprint": "fp-1",
+		"status": "firing",
+		"severity": "critical",
+		"action": "create",
+		"channel_id": "ch-1",
+		"executed": false
+	}`, w.Body.String())
+	assert.Empty(t, executed, "dry runs handle nothing")
+
+	w = replay(`{"deadletter_id":"alert:fp-2","mode":"live"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"executed":true`)
+	assert.Equal(t, []string{"fp-2"}, executed)
+	assert.Equal(t, "Disk full", previewer.inputs[1].Name)
+
+	assert.Equal(t, http.StatusNotFound, replay(`{"deadletter_id":"alert:missing"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, replay(`{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, replay(`{"deadletter_id":"alert:fp-2","mode":"later"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, replay(`{"alert":{"name":"no fingerprint"}}`).Code)
+}
+
+type mockCallbackStatuses map[string]dto.CallbackStatusOutput
+
+func (m mockCallbackStatuses) Status(id string) (dto.CallbackStatusOutput, bool) {
+	status, ok := m[id]
+	return status, ok
+}
+
+func TestCallbackStatusHandler(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	statuses := mockCallbackStatuses{"cb-1": {
+		ID: "cb-1", Action: "acknowledge", Fingerprint: "fp-1", PostID: "post-1", UserID: "user-1",
+		Status: "timed_out", Error: "Timed out after 45s", StartedAt: startedAt,
+	}}
+	router := setupTestRouter()
+	router.GET("/callbacks/:id", NewCallbackStatusHandler(statuses, testLogger()).HandleStatus)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callbacks/cb-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"id": "cb-1",
+		"action": "acknowledge",
+		"fingerprint": "fp-1",
+		"post_id": "post-1",
+		"user_id": "user-1",
+		"status": "timed_out",
+		"error": "Timed out after 45s",
+		"started_at": "2026-01-01T12:00:00Z"
+	}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callbacks/cb-2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+type mockAlertStats struct {
+	stats dto.AlertStatsOutput
+	err   error
+}
+
+func (m *mockAlertStats) Stats(ctx context.Context) (dto.AlertStatsOutput, error) {
+	return m.stats, m.err
+}
+
+func TestStatsHandler(t *testing.T) {
+	minute := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	stats := &mockAlertStats{stats: dto.AlertStatsOutput{
+		GeneratedAt: minute.Add(30 * time.Second),
+		Active: dto.ActiveStats{
+			Total:      1,
+			BySeverity: []dto.SeverityCount{{Severity: "critical", Count: 1}},
+			ByChannel:  []dto.ChannelCount{{ChannelID: "ch-1", Count: 1}},
+			ByAge:      []dto.AgeCount{{Age: "0-15m", Count: 1}},
+			Heatmap:    []dto.SeverityAgeCount{{Severity: "critical", Age: "0-15m", Count: 1}},
+		},
+		Throughput: dto.ThroughputStats{
+			WindowSeconds: 60,
+			WebhookCounts: dto.WebhookCounts{Received: 2, Firing: 1, Resolved: 1},
+			PerMinute:     []dto.MinuteThroughput{{Minute: minute, WebhookCounts: dto.WebhookCounts{Received: 2, Firing: 1, Resolved: 1}}},
+		},
+	}}
+	router := setupTestRouter()
+	router.GET("/stats", NewStatsHandler(stats, testLogger()).HandleStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"generated_at": "2026-01-01T12:00:30Z",
+		"active": {
+			"total": 1,
+			"by_severity": [{"severity": "critical", "count": 1}],
+			"by_channel": [{"channel_id": "ch-1", "count": 1}],
+			"by_age": [{"age": "0-15m", "count": 1}],
+			"heatmap": [{"severity": "critical", "age": "0-15m", "count": 1}]
+		},
+		"throughput": {
+			"window_seconds": 60,
+			"received": 2,
+			"firing": 1,
+			"resolved": 1,
+			"failed": 0,
+			"per_minute": [{"minute": "2026-01-01T12:00:00Z", "received": 2, "firing": 1, "resolved": 1, "failed": 0}]
+		}
+	}`, w.Body.String())
+
+	stats.err = errors.New("valkey down")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
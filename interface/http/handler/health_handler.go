@@ -12,14 +12,39 @@ type HealthChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// MaintenanceStatus reports the maintenance windows that are open now.
+type MaintenanceStatus interface {
+	OpenWindows() []string
+}
+
+// CircuitStatus reports the state of an API client's circuit breaker.
+type CircuitStatus interface {
+	CircuitState() string
+}
+
 type HealthHandler struct {
-	postRepo HealthChecker
+	postRepo    HealthChecker
+	maintenance MaintenanceStatus
+	keepCircuit CircuitStatus
 }
 
 func NewHealthHandler(postRepo HealthChecker) *HealthHandler {
 	return &HealthHandler{postRepo: postRepo}
 }
 
+// SetMaintenance adds the open maintenance windows to the readiness
+// response. A nil status, the default, leaves them out.
+func (h *HealthHandler) SetMaintenance(status MaintenanceStatus) {
+	h.maintenance = status
+}
+
+// SetKeepCircuit adds the state of the Keep client's circuit breaker to the
+// readiness response. An open circuit does not make the bridge unready: alert
+// posts still work, and actions are queued until Keep is back.
+func (h *HealthHandler) SetKeepCircuit(status CircuitStatus) {
+	h.keepCircuit = status
+}
+
 func (h *HealthHandler) Live(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
@@ -29,7 +54,18 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	body := gin.H{"status": "ready"}
+	if h.maintenance != nil {
+		open := h.maintenance.OpenWindows()
+		if open == nil {
+			open = []string{}
+		}
+		body["maintenance_windows"] = open
+	}
+	if h.keepCircuit != nil {
+		body["keep_circuit"] = h.keepCircuit.CircuitState()
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 func (h *HealthHandler) Metrics(c *gin.Context) {
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+)
+
+// IncidentUseCase handles Keep incident webhooks and the buttons of incident
+// posts.
+type IncidentUseCase interface {
+	port.CallbackUseCase
+	Execute(ctx context.Context, input dto.KeepIncidentInput) error
+}
+
+// IncidentHandler serves the incident webhook and the callback of incident
+// post buttons.
+type IncidentHandler struct {
+	incidents IncidentUseCase
+	callback  *CallbackHandlerHTTP
+	logger    *slog.Logger
+}
+
+func NewIncidentHandler(incidents IncidentUseCase, logger *slog.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		incidents: incidents,
+		callback:  NewCallbackHandler(incidents),
+		logger:    logger,
+	}
+}
+
+// HandleWebhook serves POST /webhook/incident.
+func (h *IncidentHandler) HandleWebhook(c *gin.Context) {
+	var input dto.KeepIncidentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Error("Failed to parse incident payload", slog.String("error", err.Error()))
+		invalidBody(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := h.incidents.Execute(ctx, input); err != nil {
+		h.logger.Error("Failed to handle incident",
+			slog.String("incident_id", input.ID),
+			slog.String("error", err.Error()),
+		)
+		handlingFailed(c, ctx)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// HandleCallback serves POST /callback/incident like the alert callback.
+func (h *IncidentHandler) HandleCallback(c *gin.Context) {
+	h.callback.HandleCallback(c)
+}
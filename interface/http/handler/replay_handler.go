@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/deadletter"
+)
+
+// Replay modes.
+const (
+	ReplayDryRun = "dry_run"
+	ReplayLive   = "live"
+)
+
+// AlertPreviewer reports what handling an alert would do without doing it.
+type AlertPreviewer interface {
+	Preview(ctx context.Context, input dto.KeepAlertInput) (dto.ReplayOutput, error)
+}
+
+// ArchivedAlerts returns the webhooks kept in the dead-letter queue.
+type ArchivedAlerts interface {
+	AlertPayload(ctx context.Context, id string) (dto.KeepAlertInput, error)
+}
+
+// ReplayHandler runs an alert through the alert use case again, to see how
+// the bridge routes and renders it. The alert is a raw webhook payload or a
+// webhook kept in the dead-letter queue. Dry runs change nothing; live runs
+// also handle the alert as if its webhook had just arrived.
+type ReplayHandler struct {
+	previewer   AlertPreviewer
+	handleAlert AlertHandler
+	archive     ArchivedAlerts
+	logger      *slog.Logger
+}
+
+// NewReplayHandler returns the replay handler. archive may be nil when the
+// dead-letter queue is disabled; requests naming an entry then fail.
+func NewReplayHandler(previewer AlertPreviewer, handleAlert AlertHandler, archive ArchivedAlerts, logger *slog.Logger) *ReplayHandler {
+	return &ReplayHandler{previewer: previewer, handleAlert: handleAlert, archive: archive, logger: logger}
+}
+
+type replayRequest struct {
+	Alert        json.RawMessage `json:"alert"`
+	DeadLetterID string          `json:"deadletter_id"`
+	Mode         string          `json:"mode"`
+}
+
+// HandleReplay serves POST /replay.
+func (h *ReplayHandler) HandleReplay(c *gin.Context) {
+	var req replayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	switch req.Mode {
+	case "":
+		req.Mode = ReplayDryRun
+	case ReplayDryRun, ReplayLive:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be dry_run or live"})
+		return
+	}
+
+	input, ok := h.input(c, req)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	out, err := h.previewer.Preview(ctx, input)
+	if err != nil {
+		if errors.Is(err, alert.ErrInvalidFingerprint) || errors.Is(err, alert.ErrInvalidSeverity) ||
+			errors.Is(err, alert.ErrInvalidStatus) || errors.Is(err, alert.ErrInvalidAlert) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to preview alert", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	if req.Mode == ReplayLive {
+		if err := h.handleAlert.Execute(ctx, input); err != nil {
+			h.logger.Error("Failed to replay alert",
+				slog.String("fingerprint", input.Fingerprint),
+				slog.String("error", err.Error()),
+			)
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "replay": out})
+			return
+		}
+		out.Executed = true
+		h.logger.Info("Alert replayed",
+			slog.String("fingerprint", input.Fingerprint),
+			slog.String("action", out.Action),
+		)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// input returns the alert of req, answering the request when there is none.
+func (h *ReplayHandler) input(c *gin.Context, req replayRequest) (dto.KeepAlertInput, bool) {
+	switch {
+	case len(req.Alert) > 0 && req.DeadLetterID != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set either alert or deadletter_id"})
+		return dto.KeepAlertInput{}, false
+	case len(req.Alert) > 0:
+		input, _, err := dto.DecodeKeepAlert(req.Alert)
+		if err == nil {
+			err = binding.Validator.ValidateStruct(&input)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert: " + err.Error()})
+			return dto.KeepAlertInput{}, false
+		}
+		return input, true
+	case req.DeadLetterID != "":
+		if h.archive == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dead-letter queue is disabled"})
+			return dto.KeepAlertInput{}, false
+		}
+		input, err := h.archive.AlertPayload(c.Request.Context(), req.DeadLetterID)
+		if err != nil {
+			if errors.Is(err, deadletter.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+				return dto.KeepAlertInput{}, false
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return dto.KeepAlertInput{}, false
+		}
+		return input, true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "alert or deadletter_id is required"})
+		return dto.KeepAlertInput{}, false
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// slackRequestMaxAge bounds replays of signed Slack requests.
+const slackRequestMaxAge = 5 * time.Minute
+
+// SlackActionsHandler serves the button clicks of alert cards posted to Slack,
+// as the Mattermost callback handler does for Mattermost. Each Slack app
+// points its interactivity request URL at /slack/actions/{server}; requests
+// must be signed with that server's signing secret.
+type SlackActionsHandler struct {
+	handleCallback port.CallbackUseCase
+	secrets        map[string]string // server name -> signing secret
+	httpClient     *http.Client
+	logger         *slog.Logger
+}
+
+func NewSlackActionsHandler(handleCallback port.CallbackUseCase, secrets map[string]string, logger *slog.Logger) *SlackActionsHandler {
+	return &SlackActionsHandler{
+		handleCallback: handleCallback,
+		secrets:        secrets,
+		httpClient:     &http.Client{Timeout: 5 * time.Second},
+		logger:         logger,
+	}
+}
+
+type slackActionsPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Container struct {
+		MessageTS string `json:"message_ts"`
+	} `json:"container"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		Value string `json:"value"`
+	} `json:"actions"`
+}
+
+// HandleActions serves POST /slack/actions/:server. Slack ignores the body of
+// the response, so ephemeral answers are sent to the payload's response URL.
+func (h *SlackActionsHandler) HandleActions(c *gin.Context) {
+	secret, ok := h.secrets[c.Param("server")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown slack server"})
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		invalidBody(c, err)
+		return
+	}
+	if !validSlackSignature(secret, c.GetHeader("X-Slack-Request-Timestamp"), c.GetHeader("X-Slack-Signature"), body, time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid slack signature"})
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	var payload slackActionsPayload
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	input := dto.MattermostCallbackInput{
+		UserID:    payload.User.ID,
+		PostID:    payload.Channel.ID + "/" + payload.Container.MessageTS, // as the Slack client returns post IDs
+		ChannelID: payload.Channel.ID,
+	}
+	if err := json.Unmarshal([]byte(payload.Actions[0].Value), &input.Context); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid action value"})
+		return
+	}
+	// Slack buttons cannot carry the rendered post, and the processing state
+	// built from it is not shown in Slack.
+	if _, ok := input.Context[post.ContextKeyAttachmentJSON]; !ok {
+		input.Context[post.ContextKeyAttachmentJSON] = "{}"
+	}
+
+	result, err := h.handleCallback.ExecuteImmediate(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if result.Ephemeral != "" {
+		if result.RunAsync {
+			h.handleCallback.ExecuteAsync(input)
+		}
+		h.respondEphemeral(c.Request.Context(), payload.ResponseURL, result.Ephemeral)
+		c.Status(http.StatusOK)
+		return
+	}
+	h.handleCallback.ExecuteAsync(input)
+	c.Status(http.StatusOK)
+}
+
+func (h *SlackActionsHandler) respondEphemeral(ctx context.Context, responseURL, text string) {
+	if responseURL == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{"response_type": "ephemeral", "replace_original": false, "text": text})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.logger.Warn("Failed to answer slack action", slog.String("error", err.Error()))
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// validSlackSignature checks the v0 signature Slack computes over the
+// timestamp and body with the app's signing secret.
+func validSlackSignature(secret, timestamp, signature string, body []byte, now time.Time) bool {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+type SlashCommandExecutor interface {
+	Execute(ctx context.Context, input dto.SlashCommandInput) (*dto.SlashCommandOutput, error)
+}
+
+// SlashCommandHandler serves Mattermost slash command requests. Requests are
+// form-encoded and must carry the command token configured in Mattermost.
+type SlashCommandHandler struct {
+	executor SlashCommandExecutor
+	token    string
+	logger   *slog.Logger
+}
+
+func NewSlashCommandHandler(executor SlashCommandExecutor, token string, logger *slog.Logger) *SlashCommandHandler {
+	return &SlashCommandHandler{executor: executor, token: token, logger: logger}
+}
+
+func (h *SlashCommandHandler) HandleCommand(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Token ")
+	}
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid command token"})
+		return
+	}
+
+	input := dto.SlashCommandInput{
+		UserID:    c.PostForm("user_id"),
+		UserName:  c.PostForm("user_name"),
+		ChannelID: c.PostForm("channel_id"),
+		Command:   c.PostForm("command"),
+		Text:      c.PostForm("text"),
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := h.executor.Execute(ctx, input)
+	if err != nil {
+		h.logger.Error("Slash command failed",
+			slog.String("text", input.Text),
+			slog.String("user_id", input.UserID),
+			slog.String("error", err.Error()),
+		)
+		// Mattermost only renders 200 responses, so failures are reported
+		// to the invoking user as an ephemeral message.
+		c.JSON(http.StatusOK, gin.H{
+			"response_type": dto.SlashResponseEphemeral,
+			"text":          "Command failed, see bridge logs for details.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_type": result.ResponseType,
+		"text":          result.Text,
+	})
+}
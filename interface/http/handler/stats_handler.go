@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+)
+
+type AlertStats interface {
+	Stats(ctx context.Context) (dto.AlertStatsOutput, error)
+}
+
+// StatsHandler serves alert counts for external dashboards, e.g. Grafana's
+// JSON data sources.
+type StatsHandler struct {
+	stats  AlertStats
+	logger *slog.Logger
+}
+
+func NewStatsHandler(stats AlertStats, logger *slog.Logger) *StatsHandler {
+	return &StatsHandler{stats: stats, logger: logger}
+}
+
+// HandleStats serves GET /stats.
+func (h *StatsHandler) HandleStats(c *gin.Context) {
+	stats, err := h.stats.Stats(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to compute alert stats", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
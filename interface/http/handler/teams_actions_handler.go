@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// TeamsAuthenticator checks the Authorization header the Bot Connector sends
+// with activities.
+type TeamsAuthenticator interface {
+	Authenticate(ctx context.Context, authorization string) error
+}
+
+// TeamsActionsHandler serves the button clicks of alert cards posted to
+// Microsoft Teams, as the Mattermost callback handler does for Mattermost.
+// Each Teams bot points its messaging endpoint at /teams/messages/{server}.
+// Teams has no ephemeral messages, so answers are replied in the card's
+// thread.
+type TeamsActionsHandler struct {
+	handleCallback port.CallbackUseCase
+	authenticators map[string]TeamsAuthenticator // server name -> bot
+	replies        port.MattermostClient
+	ids            port.MattermostServerIDs
+	logger         *slog.Logger
+}
+
+func NewTeamsActionsHandler(
+	handleCallback port.CallbackUseCase,
+	authenticators map[string]TeamsAuthenticator,
+	replies port.MattermostClient,
+	ids port.MattermostServerIDs,
+	logger *slog.Logger,
+) *TeamsActionsHandler {
+	return &TeamsActionsHandler{
+		handleCallback: handleCallback,
+		authenticators: authenticators,
+		replies:        replies,
+		ids:            ids,
+		logger:         logger,
+	}
+}
+
+type teamsActivity struct {
+	Type string `json:"type"`
+	From struct {
+		ID string `json:"id"`
+	} `json:"from"`
+	Conversation struct {
+		ID string `json:"id"`
+	} `json:"conversation"`
+	ReplyToID string `json:"replyToId"`
+	Value     struct {
+		Context map[string]string `json:"kmbridge"`
+	} `json:"value"`
+}
+
+// HandleMessages serves POST /teams/messages/:server. Activities other than
+// card button clicks are acknowledged and ignored.
+func (h *TeamsActionsHandler) HandleMessages(c *gin.Context) {
+	authenticator, ok := h.authenticators[c.Param("server")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown teams server"})
+		return
+	}
+	if err := authenticator.Authenticate(c.Request.Context(), c.GetHeader("Authorization")); err != nil {
+		h.logger.Warn("Rejected teams activity", slog.String("error", err.Error()))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid bot token"})
+		return
+	}
+
+	var activity teamsActivity
+	if err := c.ShouldBindJSON(&activity); err != nil {
+		invalidBody(c, err)
+		return
+	}
+	if activity.Type != "message" || activity.Value.Context == nil || activity.ReplyToID == "" {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	// Clicks in a channel thread come from "{channel};messageid={root}".
+	channelID, _, _ := strings.Cut(activity.Conversation.ID, ";")
+	input := dto.MattermostCallbackInput{
+		// As the Teams client returns post IDs and looks users up.
+		UserID:    channelID + "/" + activity.From.ID,
+		PostID:    channelID + "/" + activity.ReplyToID,
+		ChannelID: channelID,
+		Context:   activity.Value.Context,
+	}
+	// Teams buttons do not carry the rendered post, and the processing state
+	// built from it is not shown in Teams.
+	if _, ok := input.Context[post.ContextKeyAttachmentJSON]; !ok {
+		input.Context[post.ContextKeyAttachmentJSON] = "{}"
+	}
+
+	result, err := h.handleCallback.ExecuteImmediate(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if result.Ephemeral != "" {
+		if result.RunAsync {
+			h.handleCallback.ExecuteAsync(input)
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+		if err := h.replies.ReplyToThread(ctx, channelID, h.ids.QualifyID(channelID, input.PostID), result.Ephemeral); err != nil {
+			h.logger.Warn("Failed to answer teams action", slog.String("error", err.Error()))
+		}
+		c.Status(http.StatusOK)
+		return
+	}
+	h.handleCallback.ExecuteAsync(input)
+	c.Status(http.StatusOK)
+}
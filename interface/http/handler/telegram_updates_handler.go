@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
+	"github.com/alexmorbo/keep-mattermost-bridge/application/port"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/post"
+)
+
+// telegramAnswerMaxLength is the longest answer Telegram shows for a click;
+// longer answers are posted as replies to the card.
+const telegramAnswerMaxLength = 200
+
+// TelegramBot is the Telegram client of a server, as the updates handler
+// uses it.
+type TelegramBot interface {
+	ButtonContext(data string) (map[string]string, bool)
+	AnswerCallback(ctx context.Context, callbackQueryID, text string) error
+	ReplyToThread(ctx context.Context, channelID, rootID, message string) error
+}
+
+// TelegramUpdatesHandler serves the button clicks of alert cards posted to
+// Telegram, as the Mattermost callback handler does for Mattermost. Each bot's
+// webhook points at /telegram/updates/{server} with that server's secret
+// token.
+type TelegramUpdatesHandler struct {
+	handleCallback port.CallbackUseCase
+	bots           map[string]TelegramBot
+	secrets        map[string]string // server name -> webhook secret token
+	logger         *slog.Logger
+}
+
+func NewTelegramUpdatesHandler(
+	handleCallback port.CallbackUseCase,
+	bots map[string]TelegramBot,
+	secrets map[string]string,
+	logger *slog.Logger,
+) *TelegramUpdatesHandler {
+	return &TelegramUpdatesHandler{
+		handleCallback: handleCallback,
+		bots:           bots,
+		secrets:        secrets,
+		logger:         logger,
+	}
+}
+
+type telegramUpdate struct {
+	CallbackQuery *struct {
+		ID   string `json:"id"`
+		From struct {
+			ID int64 `json:"id"`
+		} `json:"from"`
+		Message *struct {
+			MessageID int64 `json:"message_id"`
+			Chat      struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+		} `json:"message"`
+		Data string `json:"data"`
+	} `json:"callback_query"`
+}
+
+// HandleUpdates serves POST /telegram/updates/:server. Updates other than
+// button clicks are acknowledged and ignored.
+func (h *TelegramUpdatesHandler) HandleUpdates(c *gin.Context) {
+	server := c.Param("server")
+	bot, ok := h.bots[server]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown telegram server"})
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Telegram-Bot-Api-Secret-Token")), []byte(h.secrets[server])) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid secret token"})
+		return
+	}
+
+	var update telegramUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		invalidBody(c, err)
+		return
+	}
+	query := update.CallbackQuery
+	if query == nil || query.Message == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	buttonContext, ok := bot.ButtonContext(query.Data)
+	if !ok {
+		h.answer(ctx, bot, query.ID, "This button is no longer known to the bridge. It works again once the alert is updated.")
+		c.Status(http.StatusOK)
+		return
+	}
+
+	chatID := strconv.FormatInt(query.Message.Chat.ID, 10)
+	input := dto.MattermostCallbackInput{
+		// As the Telegram client returns post IDs and looks users up.
+		UserID:    chatID + "/" + strconv.FormatInt(query.From.ID, 10),
+		PostID:    chatID + "/" + strconv.FormatInt(query.Message.MessageID, 10),
+		ChannelID: chatID,
+		Context:   maps.Clone(buttonContext),
+	}
+	// Telegram buttons do not carry the rendered post, and the processing
+	// state built from it is not shown in Telegram.
+	input.Context[post.ContextKeyAttachmentJSON] = "{}"
+
+	result, err := h.handleCallback.ExecuteImmediate(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if result.Ephemeral != "" {
+		if result.RunAsync {
+			h.handleCallback.ExecuteAsync(input)
+		}
+		if utf8.RuneCountInString(result.Ephemeral) <= telegramAnswerMaxLength {
+			h.answer(ctx, bot, query.ID, result.Ephemeral)
+		} else {
+			h.answer(ctx, bot, query.ID, "")
+			if err := bot.ReplyToThread(ctx, chatID, input.PostID, result.Ephemeral); err != nil {
+				h.logger.Warn("Failed to answer telegram action", slog.String("error", err.Error()))
+			}
+		}
+		c.Status(http.StatusOK)
+		return
+	}
+	h.handleCallback.ExecuteAsync(input)
+	h.answer(ctx, bot, query.ID, "")
+	c.Status(http.StatusOK)
+}
+
+func (h *TelegramUpdatesHandler) answer(ctx context.Context, bot TelegramBot, callbackQueryID, text string) {
+	if err := bot.AnswerCallback(ctx, callbackQueryID, text); err != nil {
+		h.logger.Warn("Failed to answer telegram callback", slog.String("error", err.Error()))
+	}
+}
@@ -3,22 +3,39 @@ package handler
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/VictoriaMetrics/metrics"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
 	"github.com/alexmorbo/keep-mattermost-bridge/application/dto"
 )
 
+// webhookPayloadsCoercedCounter counts Keep payloads, per detected schema,
+// that were accepted only after converting some field types.
+var webhookPayloadsCoercedCounter = func(schema string) *metrics.Counter {
+	return metrics.GetOrCreateCounter(`webhook_payloads_coerced_total{schema="` + schema + `"}`)
+}
+
 type AlertHandler interface {
 	Execute(ctx context.Context, input dto.KeepAlertInput) error
 }
 
+// AlertQueue accepts alerts for processing after the webhook has been
+// answered. Enqueue fails when the queue is full or shutting down.
+type AlertQueue interface {
+	Enqueue(ctx context.Context, input dto.KeepAlertInput) error
+}
+
 type WebhookHandler struct {
 	handleAlert AlertHandler
+	queue       AlertQueue
 	logger      *slog.Logger
 }
 
@@ -26,30 +43,81 @@ func NewWebhookHandler(handleAlert AlertHandler, logger *slog.Logger) *WebhookHa
 	return &WebhookHandler{handleAlert: handleAlert, logger: logger}
 }
 
+// SetQueue makes HandleAlert queue alerts and answer 202 Accepted instead of
+// processing them before responding. A nil queue, the default, processes
+// alerts synchronously.
+func (h *WebhookHandler) SetQueue(queue AlertQueue) {
+	h.queue = queue
+}
+
 func (h *WebhookHandler) HandleAlert(c *gin.Context) {
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+		invalidBody(c, err)
 		return
 	}
 	c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 
 	h.logger.Info("Incoming webhook payload", slog.String("body", string(body)))
 
-	var input dto.KeepAlertInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	input, decoding, err := dto.DecodeKeepAlert(body)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(&input)
+	}
+	if err != nil {
 		h.logger.Error("Failed to parse webhook payload", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
+	if len(decoding.Coerced) > 0 {
+		webhookPayloadsCoercedCounter(decoding.Schema).Inc()
+		h.logger.Debug("Webhook payload coerced",
+			slog.String("schema", decoding.Schema),
+			slog.Any("fields", decoding.Coerced),
+		)
+	}
+
+	if h.queue != nil {
+		if err := h.queue.Enqueue(c.Request.Context(), input); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "alert queue unavailable"})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"status": "queued"})
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	if err := h.handleAlert.Execute(ctx, input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		handlingFailed(c, ctx)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+// invalidBody answers a request whose body could not be read or decoded: 413
+// when it exceeds the endpoint's body limit, 408 when the sender was too slow
+// to send it and 400 otherwise.
+func invalidBody(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		c.JSON(http.StatusRequestTimeout, gin.H{"error": "request timed out"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+	}
+}
+
+// handlingFailed answers a request whose handling failed: 408 when the
+// request ran out of time and 500 otherwise.
+func handlingFailed(c *gin.Context, ctx context.Context) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		c.JSON(http.StatusRequestTimeout, gin.H{"error": "request timed out"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+}
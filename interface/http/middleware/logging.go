@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"log/slog"
+	"math/rand/v2"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,29 +14,136 @@ import (
 	"github.com/alexmorbo/keep-mattermost-bridge/pkg/logger"
 )
 
-func Logging(log *slog.Logger) gin.HandlerFunc {
+// maxLoggedBodyBytes caps the request body written to the debug log.
+const maxLoggedBodyBytes = 4096
+
+// AccessLogConfig controls which requests AccessLog writes.
+type AccessLogConfig struct {
+	// SampleRate is the share of successful requests that are logged, from
+	// 0 to 1. Requests answered with a 4xx or 5xx status are always logged.
+	SampleRate float64
+	// BodyPaths lists path prefixes whose request bodies are logged at debug
+	// level, with secrets redacted.
+	BodyPaths []string
+}
+
+// AccessLog writes one structured log line per request with its method,
+// path, status, duration, request ID and source IP. Server errors are logged
+// at error level and client errors at warn level.
+func AccessLog(log *slog.Logger, cfg AccessLogConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		method := c.Request.Method
 
+		if capturesBody(path, cfg.BodyPaths) && log.Enabled(c.Request.Context(), slog.LevelDebug) {
+			logRequestBody(c, log)
+		}
+
 		c.Next()
 
-		duration := time.Since(start)
 		status := c.Writer.Status()
-		requestID := logger.GetRequestID(c.Request.Context())
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		case cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate:
+			return
+		}
 
-		log.Info("HTTP request completed",
+		log.LogAttrs(c.Request.Context(), level, "HTTP request completed",
 			logger.HTTPFields(
-				requestID,
+				logger.GetRequestID(c.Request.Context()),
 				method,
 				path,
 				c.ClientIP(),
 				status,
-				duration.Milliseconds(),
+				time.Since(start).Milliseconds(),
 				int(c.Request.ContentLength),
 				c.Writer.Size(),
 			),
 		)
 	}
 }
+
+func capturesBody(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// logRequestBody logs the request body and hands an unread copy to the
+// handler. Bodies over the BodyLimit are left to the handler to reject.
+func logRequestBody(c *gin.Context, log *slog.Logger) {
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil {
+		return
+	}
+
+	log.Debug("HTTP request body",
+		slog.String("request_id", logger.GetRequestID(c.Request.Context())),
+		slog.String("path", c.Request.URL.Path),
+		slog.String("body", redactBody(body)),
+	)
+}
+
+// sensitiveKeys are JSON keys whose values never reach the log.
+var sensitiveKeys = []string{"token", "password", "secret", "api_key", "apikey", "authorization"}
+
+// redactBody replaces the values of sensitive JSON keys and truncates the
+// result. Bodies that are not JSON are not logged, since their secrets
+// cannot be told apart.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "[non-JSON body redacted]"
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "[body redacted]"
+	}
+	if len(redacted) > maxLoggedBodyBytes {
+		return string(redacted[:maxLoggedBodyBytes]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSensitiveKey(key) {
+				v[key] = "[REDACTED]"
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"bytes"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -48,7 +50,7 @@ func TestRequestIDUsesExistingHeader(t *testing.T) {
 	assert.Equal(t, "existing-request-id-123", ctxRequestID)
 }
 
-func TestLoggingMiddleware(t *testing.T) {
+func TestAccessLogMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
@@ -57,7 +59,7 @@ func TestLoggingMiddleware(t *testing.T) {
 	c, router := gin.CreateTestContext(w)
 
 	router.Use(RequestID())
-	router.Use(Logging(logger))
+	router.Use(AccessLog(logger, AccessLogConfig{SampleRate: 1}))
 	router.GET("/test", func(c *gin.Context) {
 		c.String(http.StatusOK, "test response")
 	})
@@ -70,7 +72,7 @@ func TestLoggingMiddleware(t *testing.T) {
 	assert.Equal(t, "test response", w.Body.String())
 }
 
-func TestLoggingMiddlewareWithDifferentStatuses(t *testing.T) {
+func TestAccessLogMiddlewareWithDifferentStatuses(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	tests := []struct {
@@ -109,7 +111,7 @@ func TestLoggingMiddlewareWithDifferentStatuses(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, router := gin.CreateTestContext(w)
 
-			router.Use(Logging(logger))
+			router.Use(AccessLog(logger, AccessLogConfig{SampleRate: 1}))
 			router.GET("/test", tt.handlerFunc)
 
 			c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -120,6 +122,63 @@ func TestLoggingMiddlewareWithDifferentStatuses(t *testing.T) {
 	}
 }
 
+func TestAccessLogSampling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	router := gin.New()
+	router.Use(AccessLog(logger, AccessLogConfig{SampleRate: 0}))
+	router.GET("/ok", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	router.GET("/fail", func(c *gin.Context) { c.String(http.StatusBadGateway, "fail") })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Empty(t, buf.String(), "successful requests are sampled out")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Contains(t, buf.String(), `"level":"ERROR"`, "failed requests are always logged")
+	assert.Contains(t, buf.String(), `"status_code":502`)
+}
+
+func TestAccessLogBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	router := gin.New()
+	router.Use(AccessLog(logger, AccessLogConfig{SampleRate: 1, BodyPaths: []string{"/api/v1/webhook"}}))
+	var received string
+	handler := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = string(body)
+		c.String(http.StatusOK, "ok")
+	}
+	router.POST("/api/v1/webhook/alert", handler)
+	router.POST("/api/v1/command", handler)
+
+	payload := `{"name":"DiskFull","context":{"api_key":"k-123","Token":"t-456"}}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/webhook/alert", strings.NewReader(payload)))
+
+	assert.Equal(t, payload, received, "the handler still gets the full body")
+	assert.Contains(t, buf.String(), "HTTP request body")
+	assert.Contains(t, buf.String(), "DiskFull")
+	assert.NotContains(t, buf.String(), "k-123")
+	assert.NotContains(t, buf.String(), "t-456")
+
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/command", strings.NewReader("token=secret")))
+	assert.NotContains(t, buf.String(), "HTTP request body", "only listed paths have their bodies logged")
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.Equal(t, "", redactBody(nil))
+	assert.Equal(t, "[non-JSON body redacted]", redactBody([]byte("token=secret")))
+	assert.Equal(t, `[{"password":"[REDACTED]","user":"ops"}]`, redactBody([]byte(`[{"user":"ops","password":"hunter2"}]`)))
+	assert.True(t, strings.HasSuffix(redactBody([]byte(`"`+strings.Repeat("a", 5000)+`"`)), "...(truncated)"))
+}
+
 func TestRecoveryMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -218,7 +277,7 @@ func TestAllMiddlewaresTogether(t *testing.T) {
 	router.Use(Recovery(logger))
 	router.Use(RequestID())
 	router.Use(Metrics())
-	router.Use(Logging(logger))
+	router.Use(AccessLog(logger, AccessLogConfig{SampleRate: 1}))
 
 	router.GET("/integrated", func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
@@ -232,3 +291,39 @@ func TestAllMiddlewaresTogether(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "request-id:")
 	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
 }
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "valid token", token: "secret", header: "Bearer secret", want: http.StatusOK},
+		{name: "wrong token", token: "secret", header: "Bearer guess", want: http.StatusUnauthorized},
+		{name: "missing header", token: "secret", want: http.StatusUnauthorized},
+		{name: "not a bearer token", token: "secret", header: "secret", want: http.StatusUnauthorized},
+		{name: "empty configured token", token: "", header: "Bearer ", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AdminAuth(tt.token))
+			router.GET("/admin", func(c *gin.Context) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodyBytes bounds request bodies when no limit is configured.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Rejection reasons of http_requests_rejected_total.
+const (
+	RejectBodyTooLarge = "body_too_large"
+	RejectTimeout      = "timeout"
+)
+
+// EndpointLimit bounds the requests of an endpoint.
+type EndpointLimit struct {
+	// MaxBodyBytes bounds the request body; larger requests are answered
+	// 413. Zero uses DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+	// Timeout bounds reading the request and handling it; requests that take
+	// longer are answered 408. Zero leaves requests to the server timeouts.
+	Timeout time.Duration
+}
+
+// RequestLimits holds the limits of the webhook and callback endpoints.
+type RequestLimits struct {
+	Webhook  EndpointLimit
+	Callback EndpointLimit
+}
+
+func requestsRejectedCounter(handler, reason string) *metrics.Counter {
+	handler = strings.ReplaceAll(handler, `"`, `_`)
+	return metrics.GetOrCreateCounter(`http_requests_rejected_total{handler="` + handler + `",reason="` + reason + `"}`)
+}
+
+// Limit enforces limit on the requests it handles. Bodies announced larger
+// than the limit are refused before they are read; bodies that turn out
+// larger fail to read, and handlers answer 413. The timeout sets the deadline
+// of the request context and of reading the body, so a slow sender cannot
+// hold the connection; handlers answer 408 once it passed, and so does Limit
+// when the handler answered nothing.
+func Limit(limit EndpointLimit) gin.HandlerFunc {
+	maxBytes := limit.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			countRejected(c)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+		if limit.Timeout > 0 {
+			deadline := time.Now().Add(limit.Timeout)
+			// Recorders in tests cannot set deadlines; the context still
+			// bounds the handler.
+			_ = http.NewResponseController(c.Writer).SetReadDeadline(deadline)
+			ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+			defer cancel()
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+
+		if !c.Writer.Written() && c.Request.Context().Err() == context.DeadlineExceeded {
+			c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{"error": "request timed out"})
+		}
+		countRejected(c)
+	}
+}
+
+func countRejected(c *gin.Context) {
+	handler := c.FullPath()
+	if handler == "" {
+		handler = "unknown"
+	}
+	switch c.Writer.Status() {
+	case http.StatusRequestEntityTooLarge:
+		requestsRejectedCounter(handler, RejectBodyTooLarge).Inc()
+	case http.StatusRequestTimeout:
+		requestsRejectedCounter(handler, RejectTimeout).Inc()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitRefusesAnnouncedLargeBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	router := gin.New()
+	router.POST("/limited", Limit(EndpointLimit{MaxBodyBytes: 100}), func(c *gin.Context) {
+		called = true
+		c.Status(http.StatusOK)
+	})
+
+	rejected := requestsRejectedCounter("/limited", RejectBodyTooLarge).Get()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/limited", bytes.NewBufferString(strings.Repeat("a", 101))))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.False(t, called, "the body is refused before the handler runs")
+	assert.Equal(t, rejected+1, requestsRejectedCounter("/limited", RejectBodyTooLarge).Get())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/limited", bytes.NewBufferString(strings.Repeat("a", 100))))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLimitDefaultsBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/default", Limit(EndpointLimit{}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/default", bytes.NewBufferString(strings.Repeat("a", DefaultMaxBodyBytes+1))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestLimitAnswersTimedOutRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/slow", Limit(EndpointLimit{Timeout: 10 * time.Millisecond}), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	timedOut := requestsRejectedCounter("/slow", RejectTimeout).Get()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/slow", nil))
+
+	assert.Equal(t, http.StatusRequestTimeout, w.Code)
+	assert.Equal(t, timedOut+1, requestsRejectedCounter("/slow", RejectTimeout).Get())
+}
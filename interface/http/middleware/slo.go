@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kinds of requests measured against response time objectives.
+const (
+	SLOWebhook  = "webhook"
+	SLOCallback = "callback"
+)
+
+// SLOObserver records how long a request of a measured kind took.
+type SLOObserver interface {
+	Observe(slo string, d time.Duration, failed bool)
+}
+
+// SLO times the request and reports it to observer as kind slo. Server errors
+// count as failed; client errors are the sender's fault and are not counted.
+func SLO(observer SLOObserver, slo string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+			return
+		}
+		observer.Observe(slo, time.Since(start), status >= http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sloObservation struct {
+	slo    string
+	failed bool
+}
+
+type recordingSLOObserver struct {
+	observed []sloObservation
+}
+
+func (r *recordingSLOObserver) Observe(slo string, d time.Duration, failed bool) {
+	r.observed = append(r.observed, sloObservation{slo: slo, failed: failed})
+}
+
+func TestSLO(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		status   int
+		expected []sloObservation
+	}{
+		{name: "success is observed", status: http.StatusOK, expected: []sloObservation{{slo: SLOWebhook}}},
+		{name: "client error is not counted", status: http.StatusBadRequest},
+		{name: "server error fails", status: http.StatusInternalServerError, expected: []sloObservation{{slo: SLOWebhook, failed: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := &recordingSLOObserver{}
+			router := gin.New()
+			router.Use(SLO(observer, SLOWebhook))
+			router.POST("/test", func(c *gin.Context) {
+				c.Status(tt.status)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", nil))
+
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.expected, observer.observed)
+		})
+	}
+}
@@ -9,31 +9,138 @@ import (
 	"github.com/alexmorbo/keep-mattermost-bridge/interface/http/middleware"
 )
 
-func NewRouter(
-	log *slog.Logger,
-	webhookHandler *handler.WebhookHandler,
-	callbackHandler *handler.CallbackHandlerHTTP,
-	healthHandler *handler.HealthHandler,
-) *gin.Engine {
+// RouterConfig holds the settings of NewRouter.
+type RouterConfig struct {
+	// BasePath, e.g. "/bridge", prefixes every route; "" serves from the
+	// root.
+	BasePath            string
+	AccessLogSampleRate float64
+	// LogBodies logs webhook and callback request bodies at debug level.
+	LogBodies bool
+	// Limits bounds the body size and handling time of webhooks and
+	// callbacks; other API requests are bounded to
+	// middleware.DefaultMaxBodyBytes.
+	Limits middleware.RequestLimits
+	// SLO measures response time objectives; nil measures nothing.
+	SLO middleware.SLOObserver
+	// AdminToken guards the admin routes; "" leaves them unregistered.
+	AdminToken string
+}
+
+// Handlers are the handlers NewRouter routes to. Webhook, Callback and Health
+// are required; the routes of the others are left unregistered when nil.
+type Handlers struct {
+	Webhook         *handler.WebhookHandler
+	Callback        *handler.CallbackHandlerHTTP
+	Health          *handler.HealthHandler
+	SlashCommand    *handler.SlashCommandHandler
+	Correlation     *handler.CorrelationHandler
+	DeadLetter      *handler.DeadLetterHandler
+	Audit           *handler.AuditHandler
+	Alerts          *handler.AlertsHandler
+	Incident        *handler.IncidentHandler
+	Dialog          *handler.DialogHandler
+	SlackActions    *handler.SlackActionsHandler
+	TeamsActions    *handler.TeamsActionsHandler
+	TelegramUpdates *handler.TelegramUpdatesHandler
+	Replay          *handler.ReplayHandler
+	CallbackStatus  *handler.CallbackStatusHandler
+	Stats           *handler.StatsHandler
+}
+
+// NewRouter builds the HTTP router for cfg and h.
+func NewRouter(log *slog.Logger, cfg RouterConfig, h Handlers) *gin.Engine {
 	router := gin.New()
 
 	// Recovery for all routes
 	router.Use(middleware.Recovery(log))
 
+	base := router.Group(cfg.BasePath)
+
 	// Health endpoints — only recovery middleware
-	router.GET("/health/live", healthHandler.Live)
-	router.GET("/health/ready", healthHandler.Ready)
-	router.GET("/metrics", healthHandler.Metrics)
+	base.GET("/health/live", h.Health.Live)
+	base.GET("/health/ready", h.Health.Ready)
+	base.GET("/metrics", h.Health.Metrics)
 
 	// API routes with full middleware stack
-	v1 := router.Group("/api/v1")
+	v1 := base.Group("/api/v1")
 	v1.Use(middleware.RequestID())
-	v1.Use(middleware.BodyLimit(1 << 20))
 	v1.Use(middleware.Metrics())
-	v1.Use(middleware.Logging(log))
+	accessLog := middleware.AccessLogConfig{SampleRate: cfg.AccessLogSampleRate}
+	if cfg.LogBodies {
+		accessLog.BodyPaths = []string{cfg.BasePath + "/api/v1/webhook", cfg.BasePath + "/api/v1/callback"}
+	}
+	v1.Use(middleware.AccessLog(log, accessLog))
 	{
-		v1.POST("/webhook/alert", webhookHandler.HandleAlert)
-		v1.POST("/callback", callbackHandler.HandleCallback)
+		// Webhooks and callbacks have their own limits. Response time
+		// objectives are optional; nil measures nothing.
+		withSLO := func(kind string, h gin.HandlerFunc) []gin.HandlerFunc {
+			limit := cfg.Limits.Callback
+			if kind == middleware.SLOWebhook {
+				limit = cfg.Limits.Webhook
+			}
+			if cfg.SLO == nil {
+				return []gin.HandlerFunc{middleware.Limit(limit), h}
+			}
+			return []gin.HandlerFunc{middleware.SLO(cfg.SLO, kind), middleware.Limit(limit), h}
+		}
+		bodyLimit := middleware.BodyLimit(middleware.DefaultMaxBodyBytes)
+		v1.POST("/webhook/alert", withSLO(middleware.SLOWebhook, h.Webhook.HandleAlert)...)
+		v1.POST("/webhook/alertmanager", withSLO(middleware.SLOWebhook, h.Webhook.HandleAlertmanager)...)
+		v1.POST("/callback", withSLO(middleware.SLOCallback, h.Callback.HandleCallback)...)
+		// Incidents are optional; nil leaves their routes unregistered.
+		if h.Incident != nil {
+			v1.POST("/webhook/incident", withSLO(middleware.SLOWebhook, h.Incident.HandleWebhook)...)
+			v1.POST("/callback/incident", withSLO(middleware.SLOCallback, h.Incident.HandleCallback)...)
+		}
+		// The resolve dialog is optional; nil leaves the route unregistered.
+		if h.Dialog != nil {
+			v1.POST("/callback/dialog", withSLO(middleware.SLOCallback, h.Dialog.HandleSubmission)...)
+		}
+		// Slack servers are optional; nil leaves the route unregistered.
+		if h.SlackActions != nil {
+			v1.POST("/slack/actions/:server", withSLO(middleware.SLOCallback, h.SlackActions.HandleActions)...)
+		}
+		// Teams servers are optional; nil leaves the route unregistered.
+		if h.TeamsActions != nil {
+			v1.POST("/teams/messages/:server", withSLO(middleware.SLOCallback, h.TeamsActions.HandleMessages)...)
+		}
+		// Telegram servers are optional; nil leaves the route unregistered.
+		if h.TelegramUpdates != nil {
+			v1.POST("/telegram/updates/:server", withSLO(middleware.SLOCallback, h.TelegramUpdates.HandleUpdates)...)
+		}
+		// Slash commands are optional; nil leaves the route unregistered.
+		if h.SlashCommand != nil {
+			v1.POST("/command", bodyLimit, h.SlashCommand.HandleCommand)
+		}
+		if h.Correlation != nil {
+			v1.GET("/correlation/:id", h.Correlation.HandleResolve)
+		}
+		// Admin routes expose or change internal state and require ADMIN_TOKEN.
+		if cfg.AdminToken != "" {
+			admin := v1.Group("", bodyLimit, middleware.AdminAuth(cfg.AdminToken))
+			if h.DeadLetter != nil {
+				admin.GET("/deadletter", h.DeadLetter.HandleList)
+				admin.POST("/deadletter/:id/replay", h.DeadLetter.HandleReplay)
+				admin.DELETE("/deadletter/:id", h.DeadLetter.HandleDelete)
+			}
+			if h.Alerts != nil {
+				admin.GET("/alerts", h.Alerts.HandleList)
+				admin.DELETE("/alerts/:fingerprint", h.Alerts.HandleDelete)
+			}
+			if h.Audit != nil {
+				admin.GET("/alerts/:fingerprint/history", h.Audit.HandleHistory)
+			}
+			if h.Replay != nil {
+				admin.POST("/replay", h.Replay.HandleReplay)
+			}
+			if h.CallbackStatus != nil {
+				admin.GET("/callbacks/:id", h.CallbackStatus.HandleStatus)
+			}
+			if h.Stats != nil {
+				admin.GET("/stats", h.Stats.HandleStats)
+			}
+		}
 	}
 
 	return router
@@ -23,7 +23,7 @@ func TestNewRouter(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	require.NotNil(t, router)
 
@@ -41,9 +41,120 @@ func TestNewRouter(t *testing.T) {
 	assert.Contains(t, routePaths, "/health/ready")
 	assert.Contains(t, routePaths, "/metrics")
 	assert.Contains(t, routePaths, "/api/v1/webhook/alert")
+	assert.Contains(t, routePaths, "/api/v1/webhook/alertmanager")
 	assert.Contains(t, routePaths, "/api/v1/callback")
 }
 
+func TestNewRouterSlashCommandRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	hasCommandRoute := func(router *gin.Engine) bool {
+		for _, route := range router.Routes() {
+			if route.Path == "/api/v1/command" && route.Method == http.MethodPost {
+				return true
+			}
+		}
+		return false
+	}
+
+	withoutSlash := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}})
+	assert.False(t, hasCommandRoute(withoutSlash))
+
+	withSlash := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}, SlashCommand: &handler.SlashCommandHandler{}})
+	assert.True(t, hasCommandRoute(withSlash))
+}
+
+func TestNewRouterCorrelationRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	hasCorrelationRoute := func(router *gin.Engine) bool {
+		for _, route := range router.Routes() {
+			if route.Path == "/api/v1/correlation/:id" && route.Method == http.MethodGet {
+				return true
+			}
+		}
+		return false
+	}
+
+	without := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}})
+	assert.False(t, hasCorrelationRoute(without))
+
+	with := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}, Correlation: &handler.CorrelationHandler{}})
+	assert.True(t, hasCorrelationRoute(with))
+}
+
+func TestNewRouterIncidentRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	incidentRoutes := func(router *gin.Engine) int {
+		var n int
+		for _, route := range router.Routes() {
+			if (route.Path == "/api/v1/webhook/incident" || route.Path == "/api/v1/callback/incident") && route.Method == http.MethodPost {
+				n++
+			}
+		}
+		return n
+	}
+
+	without := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}})
+	assert.Zero(t, incidentRoutes(without))
+
+	with := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}, Incident: &handler.IncidentHandler{}})
+	assert.Equal(t, 2, incidentRoutes(with))
+}
+
+func TestNewRouterDialogRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	hasDialogRoute := func(router *gin.Engine) bool {
+		for _, route := range router.Routes() {
+			if route.Path == "/api/v1/callback/dialog" && route.Method == http.MethodPost {
+				return true
+			}
+		}
+		return false
+	}
+
+	without := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}})
+	assert.False(t, hasDialogRoute(without))
+
+	with := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: &handler.HealthHandler{}, Dialog: &handler.DialogHandler{}})
+	assert.True(t, hasDialogRoute(with))
+}
+
+func TestNewRouterBasePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	healthHandler := handler.NewHealthHandler(nil)
+	router := NewRouter(logger, RouterConfig{BasePath: "/bridge", AccessLogSampleRate: 1}, Handlers{Webhook: &handler.WebhookHandler{}, Callback: &handler.CallbackHandlerHTTP{}, Health: healthHandler, SlashCommand: &handler.SlashCommandHandler{}})
+
+	routePaths := make(map[string]bool)
+	for _, route := range router.Routes() {
+		routePaths[route.Path] = true
+	}
+	for _, path := range []string{"/bridge/health/live", "/bridge/health/ready", "/bridge/metrics", "/bridge/api/v1/webhook/alert", "/bridge/api/v1/callback", "/bridge/api/v1/command"} {
+		assert.True(t, routePaths[path], "missing route %s", path)
+	}
+	assert.False(t, routePaths["/api/v1/callback"], "routes are only served under the base path")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bridge/health/live", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestRouterHealthEndpoints(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -53,7 +164,7 @@ func TestRouterHealthEndpoints(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	tests := []struct {
 		name   string
@@ -98,7 +209,7 @@ func TestRouterAPIv1Endpoints(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	tests := []struct {
 		name   string
@@ -138,7 +249,7 @@ func TestRouterMiddlewareOrder(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhook/alert", nil)
@@ -158,7 +269,7 @@ func TestRouterNotFoundRoute(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/nonexistent", nil)
@@ -177,7 +288,7 @@ func TestRouterMethodNotAllowed(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/health/live", nil)
@@ -197,7 +308,7 @@ func TestRouterCreation(t *testing.T) {
 	callbackHandler := &handler.CallbackHandlerHTTP{}
 	healthHandler := &handler.HealthHandler{}
 
-	router := NewRouter(logger, webhookHandler, callbackHandler, healthHandler)
+	router := NewRouter(logger, RouterConfig{AccessLogSampleRate: 1}, Handlers{Webhook: webhookHandler, Callback: callbackHandler, Health: healthHandler})
 
 	require.NotNil(t, router)
 }
@@ -0,0 +1,60 @@
+package attachment
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAnnotations shows every annotation but description, sorted, renaming
+// runbook_url.
+type testAnnotations struct{}
+
+func (testAnnotations) ShownAnnotations(annotations map[string]string) []string {
+	var keys []string
+	for key, value := range annotations {
+		if key != "description" && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (testAnnotations) AnnotationTitle(key string) string {
+	if key == "runbook_url" {
+		return "Runbook"
+	}
+	return key
+}
+
+func TestAnnotationFields(t *testing.T) {
+	builder, err := New(&testStyle{}, WithAnnotations(testAnnotations{}))
+	require.NoError(t, err)
+
+	a := newTestAlert("fp-1", time.Time{}).WithAnnotations(map[string]string{
+		"summary":     "Disk almost full",
+		"runbook_url": "https://runbooks/disk",
+		"description": "shown as the description",
+	})
+
+	var got []Field
+	for _, f := range builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui").Fields {
+		if f.Title == "summary" || f.Title == "Runbook" || f.Title == "description" {
+			got = append(got, f)
+		}
+	}
+	assert.Equal(t, []Field{
+		{Title: "Runbook", Value: "https://runbooks/disk"},
+		{Title: "summary", Value: "Disk almost full"},
+	}, got, "annotations are full-width fields under their titles; excluded ones are left out")
+
+	plain, err := New(&testStyle{})
+	require.NoError(t, err)
+	for _, f := range plain.BuildFiringAttachment(a, "http://callback", "http://keep.ui").Fields {
+		assert.NotEqual(t, "summary", f.Title, "annotations are not shown without WithAnnotations")
+	}
+}
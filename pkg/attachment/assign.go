@@ -0,0 +1,40 @@
+package attachment
+
+import (
+	"fmt"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// assignMenu returns the Assign message menu for a. It lists "Assign to me"
+// followed by the configured users, or lets Mattermost list every user when
+// none are configured.
+func (b *Builder) assignMenu(a *alert.Alert, callbackURL, attachmentJSON string) Button {
+	context := map[string]string{
+		ContextKeyAction:         ActionAssign,
+		ContextKeyFingerprint:    a.Fingerprint().Value(),
+		ContextKeyAlertName:      a.Name(),
+		ContextKeySeverity:       a.Severity().String(),
+		ContextKeyAttachmentJSON: attachmentJSON,
+	}
+
+	menu := Button{
+		ID:          ActionAssign,
+		Name:        "Assign",
+		Type:        ButtonTypeSelect,
+		Integration: ButtonIntegration{URL: callbackURL, Context: context},
+	}
+
+	if len(b.assignUsers) == 0 {
+		menu.DataSource = DataSourceUsers
+		context[ContextKeyDataSource] = DataSourceUsers
+		return menu
+	}
+
+	menu.Options = make([]SelectOption, 0, len(b.assignUsers)+1)
+	menu.Options = append(menu.Options, SelectOption{Text: "Assign to me", Value: AssignToMe})
+	for _, username := range b.assignUsers {
+		menu.Options = append(menu.Options, SelectOption{Text: fmt.Sprintf("@%s", username), Value: username})
+	}
+	return menu
+}
@@ -0,0 +1,52 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignMenuListsConfiguredUsers(t *testing.T) {
+	builder, err := New(&testStyle{}, WithAssignMenu([]string{"alice", "bob"}))
+	require.NoError(t, err)
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	require.Len(t, card.Actions, 3)
+	menu := card.Actions[2]
+	assert.Equal(t, ActionAssign, menu.ID)
+	assert.Equal(t, ButtonTypeSelect, menu.Type)
+	assert.Empty(t, menu.DataSource)
+	assert.Equal(t, []SelectOption{
+		{Text: "Assign to me", Value: AssignToMe},
+		{Text: "@alice", Value: "alice"},
+		{Text: "@bob", Value: "bob"},
+	}, menu.Options)
+	assert.Equal(t, ActionAssign, menu.Integration.Context[ContextKeyAction])
+	assert.Equal(t, "fp-1", menu.Integration.Context[ContextKeyFingerprint])
+	assert.NotEmpty(t, menu.Integration.Context[ContextKeyAttachmentJSON])
+	assert.Empty(t, menu.Integration.Context[ContextKeyDataSource])
+}
+
+func TestAssignMenuFallsBackToUserPicker(t *testing.T) {
+	builder, err := New(&testStyle{}, WithAssignMenu(nil))
+	require.NoError(t, err)
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	menu := card.Actions[len(card.Actions)-1]
+	assert.Equal(t, ActionAssign, menu.ID)
+	assert.Equal(t, DataSourceUsers, menu.DataSource)
+	assert.Empty(t, menu.Options)
+	assert.Equal(t, DataSourceUsers, menu.Integration.Context[ContextKeyDataSource])
+}
+
+func TestBuildAssignedAttachment(t *testing.T) {
+	builder, err := New(&testStyle{}, WithAssignMenu(nil))
+	require.NoError(t, err)
+
+	card := builder.BuildAssignedAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui", "alice")
+	assert.Equal(t, "Assigned to @alice", card.Footer)
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID, "assigning does not acknowledge")
+	assert.Equal(t, ActionAssign, card.Actions[len(card.Actions)-1].ID)
+}
@@ -0,0 +1,132 @@
+package attachment
+
+import "encoding/json"
+
+// Attachment is a Mattermost message attachment. The JSON form is what
+// Mattermost stores in a post's props and what ToJSON and FromJSON exchange.
+type Attachment struct {
+	Color      string
+	Title      string
+	TitleLink  string
+	Text       string
+	Fields     []Field
+	Actions    []Button
+	Footer     string
+	FooterIcon string
+	// Message is the post text shown above the attachment, e.g. mentions.
+	// It is not part of the attachment JSON.
+	Message string `json:"-"`
+	// Username and IconURL, when set, replace the bot's name and avatar on
+	// the post. They are kept in the attachment JSON so a re-rendered post,
+	// e.g. while a button is processing, keeps them.
+	Username string `json:",omitempty"`
+	IconURL  string `json:",omitempty"`
+}
+
+// Field is a titled value shown in the attachment body; Short fields are laid
+// out two per row.
+type Field struct {
+	Title string
+	Value string
+	Short bool
+}
+
+// Button is an interactive message button, or a message menu when Type is
+// ButtonTypeSelect. Clicking it, or picking a menu entry, makes Mattermost
+// POST the integration context to the integration URL; a menu adds the
+// picked value under ContextKeySelectedOption.
+type Button struct {
+	ID          string
+	Name        string
+	Style       string
+	Type        string // ButtonTypeButton when empty
+	DataSource  string // DataSourceUsers lists Mattermost users instead of Options
+	Options     []SelectOption
+	Integration ButtonIntegration
+}
+
+// SelectOption is an entry of a message menu.
+type SelectOption struct {
+	Text  string
+	Value string
+}
+
+type ButtonIntegration struct {
+	URL     string
+	Context map[string]string
+}
+
+// Button actions, stored in the button context under ContextKeyAction.
+const (
+	ActionAcknowledge   = "acknowledge"
+	ActionResolve       = "resolve"
+	ActionUnacknowledge = "unacknowledge"
+	ActionSnooze        = "snooze"
+	ActionCommands      = "commands"
+	ActionAssign        = "assign"
+	// ActionCustom runs the configured custom action named under
+	// ContextKeyCustomAction.
+	ActionCustom = "custom"
+	// ActionRunWorkflow runs the Keep workflow picked from the Run workflow
+	// menu for the alert.
+	ActionRunWorkflow = "run_workflow"
+	// ActionAcknowledgeFor acknowledges the alert for the duration picked
+	// from the Acknowledge for menu, after which it fires again.
+	ActionAcknowledgeFor = "acknowledge_for"
+	// ActionRetry runs the action under ContextKeyRetryAction again, after it
+	// failed to reach Keep.
+	ActionRetry = "retry"
+)
+
+const (
+	ButtonTypeButton = "button"
+	ButtonTypeSelect = "select"
+)
+
+// DataSourceUsers makes a message menu list Mattermost users; the picked
+// value is the user ID.
+const DataSourceUsers = "users"
+
+// AssignToMe is the Assign menu value that assigns the alert to the user who
+// picked it. Mattermost usernames cannot contain "@", so it never clashes
+// with a listed user.
+const AssignToMe = "@me"
+
+const (
+	ButtonStyleDefault = "default"
+	ButtonStyleSuccess = "success"
+	ButtonStyleDanger  = "danger"
+)
+
+// Keys of the button integration context.
+const (
+	ContextKeyAction         = "action"
+	ContextKeyFingerprint    = "fingerprint"
+	ContextKeyAlertName      = "alert_name"
+	ContextKeySeverity       = "severity"
+	ContextKeyAttachmentJSON = "attachment_json"
+	ContextKeyCommands       = "commands"
+	ContextKeyIncidentID     = "incident_id"
+	ContextKeyDataSource     = "data_source"
+	ContextKeyCustomAction   = "custom_action"
+	ContextKeyRetryAction    = "retry_action"
+	// ContextKeySelectedOption is added by Mattermost when a menu entry is
+	// picked.
+	ContextKeySelectedOption = "selected_option"
+)
+
+func (a *Attachment) ToJSON() (string, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func FromJSON(data string) (*Attachment, error) {
+	var attachment Attachment
+	if err := json.Unmarshal([]byte(data), &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
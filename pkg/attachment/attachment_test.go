@@ -1,4 +1,4 @@
-package post
+package attachment
 
 import (
 	"testing"
@@ -13,7 +13,7 @@ func TestAttachment_ToJSON_RoundTrip(t *testing.T) {
 		Title:     "🔴 Critical Alert",
 		TitleLink: "http://example.com/alert?id=123",
 		Text:      "This is the alert description",
-		Fields: []AttachmentField{
+		Fields: []Field{
 			{Title: "Severity", Value: "CRITICAL", Short: true},
 			{Title: "Host", Value: "server-1", Short: true},
 		},
@@ -28,7 +28,7 @@ func TestAttachment_ToJSON_RoundTrip(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jsonStr)
 
-	restored, err := AttachmentFromJSON(jsonStr)
+	restored, err := FromJSON(jsonStr)
 	require.NoError(t, err)
 
 	assert.Equal(t, original.Color, restored.Color)
@@ -48,7 +48,7 @@ func TestAttachment_ToJSON_EmptyAttachment(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jsonStr)
 
-	restored, err := AttachmentFromJSON(jsonStr)
+	restored, err := FromJSON(jsonStr)
 	require.NoError(t, err)
 
 	assert.Equal(t, "", restored.Color)
@@ -61,7 +61,7 @@ func TestAttachment_ToJSON_SpecialCharacters(t *testing.T) {
 	special := Attachment{
 		Title: "Alert with \"quotes\" and 'apostrophes'",
 		Text:  "Line1\nLine2\tTabbed",
-		Fields: []AttachmentField{
+		Fields: []Field{
 			{Title: "Unicode", Value: "日本語テスト émojis 🎉", Short: false},
 			{Title: "Symbols", Value: "<script>alert('xss')</script>", Short: false},
 		},
@@ -70,7 +70,7 @@ func TestAttachment_ToJSON_SpecialCharacters(t *testing.T) {
 	jsonStr, err := special.ToJSON()
 	require.NoError(t, err)
 
-	restored, err := AttachmentFromJSON(jsonStr)
+	restored, err := FromJSON(jsonStr)
 	require.NoError(t, err)
 
 	assert.Equal(t, special.Title, restored.Title)
@@ -79,17 +79,17 @@ func TestAttachment_ToJSON_SpecialCharacters(t *testing.T) {
 	assert.Equal(t, special.Fields[1].Value, restored.Fields[1].Value)
 }
 
-func TestAttachmentFromJSON_InvalidJSON(t *testing.T) {
-	_, err := AttachmentFromJSON("not valid json")
+func TestFromJSON_InvalidJSON(t *testing.T) {
+	_, err := FromJSON("not valid json")
 	require.Error(t, err)
 }
 
-func TestAttachmentFromJSON_EmptyString(t *testing.T) {
-	_, err := AttachmentFromJSON("")
+func TestFromJSON_EmptyString(t *testing.T) {
+	_, err := FromJSON("")
 	require.Error(t, err)
 }
 
-func TestAttachmentFromJSON_WrongType(t *testing.T) {
-	_, err := AttachmentFromJSON(`"just a string"`)
+func TestFromJSON_WrongType(t *testing.T) {
+	_, err := FromJSON(`"just a string"`)
 	require.Error(t, err)
 }
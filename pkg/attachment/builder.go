@@ -0,0 +1,1035 @@
+package attachment
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/group"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// Builder renders alerts as attachments. It is safe for concurrent use.
+type Builder struct {
+	style             Style
+	clock             clock.Clock
+	snoozeDuration    time.Duration
+	ackDurations      []time.Duration
+	copyCommands      []copyCommand
+	keepURL           string
+	assignEnabled     bool
+	assignUsers       []string
+	mentions          MentionPolicy
+	links             LinkPolicy
+	sourceLinks       *SourceLinks
+	customActions     CustomActionPolicy
+	workflowMenu      WorkflowMenu
+	enrichments       []EnrichmentField
+	annotations       AnnotationPolicy
+	escape            bool
+	rawMarkdown       []string
+	compact           bool
+	compactSeverities []string
+	identity          BotIdentityPolicy
+}
+
+// New returns a Builder that renders alerts in the given style.
+func New(style Style, opts ...Option) (*Builder, error) {
+	b := &Builder{style: style, clock: clock.Real()}
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (b *Builder) BuildFiringAttachment(a *alert.Alert, callbackURL, keepUIURL string) Attachment {
+	severity := a.Severity().String()
+	color := b.style.ColorForSeverity(severity)
+	emoji := b.style.EmojiForSeverity(severity)
+
+	title := formatTitle(emoji, a, b.clock.Now())
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(a.Fingerprint().Value()))
+
+	fields := b.alertFields(a, severity, titleLink)
+
+	attachmentWithoutButtons := b.withIdentity(Attachment{
+		Color:     color,
+		Title:     title,
+		TitleLink: titleLink,
+		Fields:    fields,
+	}, severity)
+
+	attachmentJSON, err := attachmentWithoutButtons.ToJSON()
+	if err != nil {
+		slog.Error("Failed to serialize attachment to JSON", slog.String("error", err.Error()))
+		attachmentJSON = ""
+	}
+
+	buttons := []Button{
+		{
+			ID:    ActionAcknowledge,
+			Name:  "Acknowledge",
+			Style: ButtonStyleDefault,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionAcknowledge,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		},
+		{
+			ID:    ActionResolve,
+			Name:  "Resolve",
+			Style: ButtonStyleSuccess,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionResolve,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		},
+	}
+
+	if b.snoozeDuration > 0 {
+		buttons = append(buttons, Button{
+			ID:    ActionSnooze,
+			Name:  "Snooze " + formatSnoozeDuration(b.snoozeDuration),
+			Style: ButtonStyleDefault,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionSnooze,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		})
+	}
+
+	if menu, ok := b.timedAckMenu(a, callbackURL, attachmentJSON); ok {
+		buttons = append(buttons, menu)
+	}
+
+	if b.assignEnabled {
+		buttons = append(buttons, b.assignMenu(a, callbackURL, attachmentJSON))
+	}
+
+	if button, ok := b.commandsButton(a, callbackURL, keepUIURL); ok {
+		buttons = append(buttons, button)
+	}
+
+	buttons = append(buttons, b.customActionButtons(a, callbackURL)...)
+
+	if menu, ok := b.runWorkflowMenu(a, callbackURL); ok {
+		buttons = append(buttons, menu)
+	}
+
+	if b.isCompact(a) {
+		buttons = keepButton(buttons, ActionAcknowledge)
+	}
+
+	return b.withIdentity(Attachment{
+		Color:     color,
+		Title:     title,
+		TitleLink: titleLink,
+		Fields:    fields,
+		Actions:   buttons,
+	}, severity)
+}
+
+// BuildAssignedAttachment renders a firing alert that has an assignee but was
+// not acknowledged: the firing card with the assignee in the footer.
+func (b *Builder) BuildAssignedAttachment(a *alert.Alert, callbackURL, keepUIURL, assignee string) Attachment {
+	attachment := b.BuildFiringAttachment(a, callbackURL, keepUIURL)
+	attachment.Footer = truncateWidth(fmt.Sprintf("Assigned to @%s", assignee), maxFooterWidth)
+	attachment.FooterIcon = b.style.FooterIconURL()
+	return attachment
+}
+
+// BuildFlappingAttachment renders an alert that keeps firing and resolving:
+// the firing card in the "flapping" color, with the number of state changes
+// within window in the footer. Its buttons act on the alert as usual.
+func (b *Builder) BuildFlappingAttachment(a *alert.Alert, callbackURL, keepUIURL string, changes int, window time.Duration) Attachment {
+	attachment := b.BuildFiringAttachment(a, callbackURL, keepUIURL)
+	attachment.Color = b.style.ColorForSeverity("flapping")
+	attachment.Title = formatTitle("🔁", a, b.clock.Now())
+	footer := fmt.Sprintf("Flapping: %d state changes in %s, updates paused", changes, formatSnoozeDuration(window))
+	attachment.Footer = truncateWidth(footer, maxFooterWidth)
+	attachment.FooterIcon = b.style.FooterIconURL()
+	return attachment
+}
+
+func (b *Builder) BuildAcknowledgedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string) Attachment {
+	severity := a.Severity().String()
+	color := b.style.ColorForSeverity("acknowledged")
+
+	title := formatTitle("👀", a, b.clock.Now())
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(a.Fingerprint().Value()))
+
+	fields := b.alertFields(a, severity, titleLink)
+
+	attachmentWithoutButtons := b.withIdentity(Attachment{
+		Color:     color,
+		Title:     title,
+		TitleLink: titleLink,
+		Fields:    fields,
+	}, severity)
+
+	attachmentJSON, err := attachmentWithoutButtons.ToJSON()
+	if err != nil {
+		slog.Error("Failed to serialize attachment to JSON", slog.String("error", err.Error()))
+		attachmentJSON = ""
+	}
+
+	buttons := []Button{
+		{
+			ID:    ActionUnacknowledge,
+			Name:  "Unacknowledge",
+			Style: ButtonStyleDefault,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionUnacknowledge,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		},
+		{
+			ID:    ActionResolve,
+			Name:  "Resolve",
+			Style: ButtonStyleSuccess,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionResolve,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		},
+	}
+
+	if button, ok := b.commandsButton(a, callbackURL, keepUIURL); ok {
+		buttons = append(buttons, button)
+	}
+
+	if b.isCompact(a) {
+		buttons = keepButton(buttons, ActionResolve)
+	}
+
+	var footer, footerIcon string
+	if username != "" {
+		footer = truncateWidth(fmt.Sprintf("Acknowledged by @%s", username), maxFooterWidth)
+		footerIcon = b.style.FooterIconURL()
+	}
+
+	return b.withIdentity(Attachment{
+		Color:      color,
+		Title:      title,
+		TitleLink:  titleLink,
+		Fields:     fields,
+		Actions:    buttons,
+		Footer:     footer,
+		FooterIcon: footerIcon,
+	}, severity)
+}
+
+// BuildSnoozedAttachment renders a snoozed alert. Only Resolve is offered;
+// the post returns to firing on its own when the snooze ends.
+func (b *Builder) BuildSnoozedAttachment(a *alert.Alert, callbackURL, keepUIURL, username string, until time.Time) Attachment {
+	severity := a.Severity().String()
+	color := b.style.ColorForSeverity("snoozed")
+
+	title := formatTitle("💤", a, b.clock.Now())
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(a.Fingerprint().Value()))
+
+	fields := b.alertFields(a, severity, titleLink)
+
+	attachmentWithoutButtons := b.withIdentity(Attachment{
+		Color:     color,
+		Title:     title,
+		TitleLink: titleLink,
+		Fields:    fields,
+	}, severity)
+
+	attachmentJSON, err := attachmentWithoutButtons.ToJSON()
+	if err != nil {
+		slog.Error("Failed to serialize attachment to JSON", slog.String("error", err.Error()))
+		attachmentJSON = ""
+	}
+
+	buttons := []Button{
+		{
+			ID:    ActionResolve,
+			Name:  "Resolve",
+			Style: ButtonStyleSuccess,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         ActionResolve,
+					ContextKeyFingerprint:    a.Fingerprint().Value(),
+					ContextKeyAlertName:      a.Name(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		},
+	}
+
+	if button, ok := b.commandsButton(a, callbackURL, keepUIURL); ok {
+		buttons = append(buttons, button)
+	}
+
+	if b.isCompact(a) {
+		buttons = keepButton(buttons, ActionResolve)
+	}
+
+	footer := fmt.Sprintf("Snoozed until %s", until.UTC().Format("2006-01-02 15:04 UTC"))
+	if username != "" {
+		footer = fmt.Sprintf("Snoozed by @%s until %s", username, until.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	return b.withIdentity(Attachment{
+		Color:      color,
+		Title:      title,
+		TitleLink:  titleLink,
+		Fields:     fields,
+		Actions:    buttons,
+		Footer:     truncateWidth(footer, maxFooterWidth),
+		FooterIcon: b.style.FooterIconURL(),
+	}, severity)
+}
+
+func (b *Builder) BuildResolvedAttachment(a *alert.Alert, keepUIURL, acknowledgedBy string) Attachment {
+	severity := a.Severity().String()
+	color := b.style.ColorForSeverity("resolved")
+
+	title := formatTitle("✅", a, b.clock.Now())
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(a.Fingerprint().Value()))
+
+	fields := b.alertFields(a, severity, titleLink)
+
+	var footer, footerIcon string
+	if acknowledgedBy != "" {
+		footer = truncateWidth(fmt.Sprintf("Was acknowledged by @%s", acknowledgedBy), maxFooterWidth)
+		footerIcon = b.style.FooterIconURL()
+	}
+
+	return b.withIdentity(Attachment{
+		Color:      color,
+		Title:      title,
+		TitleLink:  titleLink,
+		Fields:     fields,
+		Footer:     footer,
+		FooterIcon: footerIcon,
+	}, severity)
+}
+
+func (b *Builder) BuildSuppressedAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "suppressed", "🔇", "Alert suppressed")
+}
+
+func (b *Builder) BuildPendingAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "pending", "⏳", "Alert pending")
+}
+
+func (b *Builder) BuildMaintenanceAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "maintenance", "🔧", "Under maintenance")
+}
+
+// BuildDismissedAttachment renders an alert dismissed in Keep. Like the other
+// status cards it has no buttons: there is nothing left to act on.
+func (b *Builder) BuildDismissedAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "dismissed", "🚫", "Alert dismissed")
+}
+
+// BuildMergedAttachment renders an alert Keep merged into another alert; the
+// surviving alert carries on in its own post.
+func (b *Builder) BuildMergedAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "merged", "🔀", "Merged into another alert")
+}
+
+// BuildExpiredAttachment renders an alert whose stored post expired. The
+// bridge no longer follows it, so the buttons are removed.
+func (b *Builder) BuildExpiredAttachment(a *alert.Alert, keepUIURL string) Attachment {
+	return b.buildStatusAttachment(a, keepUIURL, "expired", "⌛", "Expired: no longer tracked by the bridge")
+}
+
+func (b *Builder) buildStatusAttachment(a *alert.Alert, keepUIURL, colorKey, emoji, footer string) Attachment {
+	severity := a.Severity().String()
+	color := b.style.ColorForSeverity(colorKey)
+
+	title := formatTitle(emoji, a, b.clock.Now())
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(a.Fingerprint().Value()))
+
+	fields := b.alertFields(a, severity, titleLink)
+
+	return b.withIdentity(Attachment{
+		Color:      color,
+		Title:      title,
+		TitleLink:  titleLink,
+		Fields:     fields,
+		Footer:     footer,
+		FooterIcon: b.style.FooterIconURL(),
+	}, severity)
+}
+
+func (b *Builder) BuildProcessingAttachment(attachmentJSON, action string) (Attachment, error) {
+	attachment, err := FromJSON(attachmentJSON)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("deserialize attachment: %w", err)
+	}
+
+	var style string
+	switch action {
+	case ActionResolve:
+		style = ButtonStyleSuccess
+	default:
+		style = ButtonStyleDefault
+	}
+
+	attachment.Actions = []Button{
+		{
+			ID:    "processing",
+			Name:  "Processing...",
+			Style: style,
+		},
+	}
+
+	return *attachment, nil
+}
+
+func (b *Builder) BuildErrorAttachment(alertName, fingerprint, keepUIURL, errorMsg string) Attachment {
+	titleLink := fmt.Sprintf("%s/alerts/feed?fingerprint=%s", keepUIURL, url.QueryEscape(fingerprint))
+
+	buttons := []Button{
+		{
+			ID:    "error",
+			Name:  "Error: " + errorMsg,
+			Style: ButtonStyleDanger,
+		},
+	}
+
+	return Attachment{
+		Color:     "#FF0000",
+		Title:     truncateWidth(alertName, maxAlertNameWidth),
+		TitleLink: titleLink,
+		Actions:   buttons,
+	}
+}
+
+// BuildCollapsedAttachment renders the one-line summary a resolved alert post
+// is collapsed to once its retention delay has passed.
+func (b *Builder) BuildCollapsedAttachment(alertName, fingerprint, keepUIURL string, resolvedAt time.Time) Attachment {
+	return Attachment{
+		Color: b.style.ColorForSeverity("resolved"),
+		Text: fmt.Sprintf("✅ [%s](%s/alerts/feed?fingerprint=%s) · resolved %s",
+			truncateWidth(alertName, maxAlertNameWidth),
+			keepUIURL,
+			url.QueryEscape(fingerprint),
+			resolvedAt.UTC().Format("2006-01-02 15:04 UTC"),
+		),
+	}
+}
+
+// BuildGroupRootAttachment renders the summary post that heads an alert group
+// thread. It is colored by the most severe active member and turns green once
+// every alert in the group has resolved.
+func (b *Builder) BuildGroupRootAttachment(g *group.Group, keepUIURL string) Attachment {
+	groupLabel := truncateWidth(fmt.Sprintf("%s=%s", g.Key(), g.Value()), maxAlertNameWidth)
+
+	var color, title string
+	if active := g.ActiveCount(); active > 0 {
+		severity := g.HighestSeverity().String()
+		color = b.style.ColorForSeverity(severity)
+		noun := "alerts"
+		if active == 1 {
+			noun = "alert"
+		}
+		title = fmt.Sprintf("%s %d active %s · %s", b.style.EmojiForSeverity(severity), active, noun, groupLabel)
+	} else {
+		color = b.style.ColorForSeverity("resolved")
+		title = fmt.Sprintf("✅ All alerts resolved · %s", groupLabel)
+	}
+
+	fields := []Field{
+		{Title: truncateWidth(g.Key(), maxFieldTitleWidth), Value: truncateWidth(g.Value(), maxFieldValueWidth), Short: true},
+		{Title: "Active", Value: fmt.Sprintf("%d of %d", g.ActiveCount(), g.Total()), Short: true},
+	}
+
+	return Attachment{
+		Color:      color,
+		Title:      title,
+		TitleLink:  keepUIURL + "/alerts/feed",
+		Text:       "Individual alerts are posted in this thread.",
+		Fields:     fields,
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+}
+
+// maxOverflowLines caps the held alerts listed in an overflow summary.
+const maxOverflowLines = 25
+
+// BuildOverflowAttachment lists the alerts a channel's notification budget
+// is holding back, oldest first. An empty list marks the overflow as over.
+func (b *Builder) BuildOverflowAttachment(held []HeldAlert, keepUIURL string) Attachment {
+	attachment := Attachment{
+		TitleLink:  keepUIURL + "/alerts/feed",
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+
+	if len(held) == 0 {
+		attachment.Color = b.style.ColorForSeverity("resolved")
+		attachment.Title = "✅ Notification budget recovered"
+		attachment.Text = "All held alerts were posted or resolved."
+		return attachment
+	}
+
+	highest := alert.RestoreSeverity(held[0].Severity)
+	lines := make([]string, 0, min(len(held), maxOverflowLines)+1)
+	for i, h := range held {
+		if severity := alert.RestoreSeverity(h.Severity); severity.Rank() > highest.Rank() {
+			highest = severity
+		}
+		if i >= maxOverflowLines {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s [%s](%s/alerts/feed?fingerprint=%s) · held since %s",
+			b.style.EmojiForSeverity(h.Severity),
+			truncateWidth(h.Name, maxAlertNameWidth),
+			keepUIURL,
+			url.QueryEscape(h.Fingerprint),
+			h.HeldAt.UTC().Format("15:04 UTC"),
+		))
+	}
+	if len(held) > maxOverflowLines {
+		lines = append(lines, fmt.Sprintf("…and %d more", len(held)-maxOverflowLines))
+	}
+
+	noun := "alerts"
+	if len(held) == 1 {
+		noun = "alert"
+	}
+	attachment.Color = b.style.ColorForSeverity(highest.String())
+	attachment.Title = fmt.Sprintf("⏸️ %d %s held · notification budget exceeded", len(held), noun)
+	attachment.Text = "These alerts will be posted one by one as the channel's hourly budget recovers.\n\n" + strings.Join(lines, "\n")
+	return attachment
+}
+
+// BuildQuietHoursDigestAttachment lists the alerts held during quiet hours,
+// oldest first, with those that resolved meanwhile marked as such. It takes
+// the color of the highest severity still firing.
+func (b *Builder) BuildQuietHoursDigestAttachment(held []DigestAlert, keepUIURL string) Attachment {
+	color := b.style.ColorForSeverity("resolved")
+	var highest alert.Severity
+	var firing int
+	lines := make([]string, 0, min(len(held), maxOverflowLines)+1)
+	for i, h := range held {
+		outcome := "still firing"
+		if !h.ResolvedAt.IsZero() {
+			outcome = "resolved at " + h.ResolvedAt.UTC().Format("15:04 UTC")
+		} else {
+			firing++
+			if severity := alert.RestoreSeverity(h.Severity); firing == 1 || severity.Rank() > highest.Rank() {
+				highest = severity
+				color = b.style.ColorForSeverity(severity.String())
+			}
+		}
+		if i >= maxOverflowLines {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s [%s](%s/alerts/feed?fingerprint=%s) · held at %s · %s",
+			b.style.EmojiForSeverity(h.Severity),
+			truncateWidth(h.Name, maxAlertNameWidth),
+			keepUIURL,
+			url.QueryEscape(h.Fingerprint),
+			h.HeldAt.UTC().Format("15:04 UTC"),
+			outcome,
+		))
+	}
+	if len(held) > maxOverflowLines {
+		lines = append(lines, fmt.Sprintf("…and %d more", len(held)-maxOverflowLines))
+	}
+
+	noun := "alerts"
+	if len(held) == 1 {
+		noun = "alert"
+	}
+	return Attachment{
+		Color:      color,
+		Title:      fmt.Sprintf("🌅 Quiet hours digest · %d %s held, %d still firing", len(held), noun, firing),
+		TitleLink:  keepUIURL + "/alerts/feed",
+		Text:       strings.Join(lines, "\n"),
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+}
+
+// BuildSLOReportAttachment summarizes how the bridge met its response time
+// objectives between from and to. It is green when every objective held.
+func (b *Builder) BuildSLOReportAttachment(results []SLOResult, from, to time.Time) Attachment {
+	color := b.style.ColorForSeverity("resolved")
+	fields := make([]Field, 0, len(results))
+	for _, r := range results {
+		mark := "✅"
+		if !r.Met() {
+			mark = "❌"
+			color = b.style.ColorForSeverity(alert.SeverityCritical)
+		}
+		fields = append(fields, Field{
+			Title: fmt.Sprintf("%s %s within %s", mark, r.Name, r.Threshold),
+			Value: fmt.Sprintf("%s of %d requests (target %s) · %.0f%% of error budget used",
+				formatPercent(r.Ratio()), r.Total, formatPercent(r.Target), r.BudgetUsed()*100),
+		})
+	}
+
+	return Attachment{
+		Color:      color,
+		Title:      fmt.Sprintf("📊 SLO report · %s – %s", from.UTC().Format("2006-01-02 15:04"), to.UTC().Format("2006-01-02 15:04 UTC")),
+		Fields:     fields,
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+}
+
+// digestSeverities lists severities in the order the digest counts them.
+var digestSeverities = []string{alert.SeverityCritical, alert.SeverityHigh, alert.SeverityWarning, alert.SeverityInfo, alert.SeverityLow, alert.SeverityDebug}
+
+// BuildAlertDigestAttachment summarizes the alerts of a digest period: how
+// many fired per severity, the noisiest ones, and how long they took to be
+// acknowledged and resolved. It takes the color of the highest severity
+// that fired.
+func (b *Builder) BuildAlertDigestAttachment(d AlertDigest, keepUIURL string) Attachment {
+	color := b.style.ColorForSeverity("resolved")
+	counts := make([]string, 0, len(digestSeverities))
+	for _, severity := range digestSeverities {
+		n := d.BySeverity[severity]
+		if n == 0 {
+			continue
+		}
+		if len(counts) == 0 {
+			color = b.style.ColorForSeverity(severity)
+		}
+		counts = append(counts, fmt.Sprintf("%s %d %s", b.style.EmojiForSeverity(severity), n, severity))
+	}
+	fired := strconv.Itoa(d.Fired)
+	if len(counts) > 0 {
+		fired += " · " + strings.Join(counts, " · ")
+	}
+
+	fields := []Field{
+		{Title: "Alerts fired", Value: fired},
+		{Title: "Mean time to acknowledge", Value: formatDigestMean(d.MTTA, d.Acknowledged, "acknowledged"), Short: true},
+		{Title: "Mean time to resolve", Value: formatDigestMean(d.MTTR, d.Resolved, "resolved"), Short: true},
+	}
+	var responses []string
+	for _, severity := range digestSeverities {
+		r, ok := d.ResponseBySeverity[severity]
+		if !ok || r.Acknowledged+r.Resolved == 0 {
+			continue
+		}
+		line := b.style.EmojiForSeverity(severity) + " " + severity
+		if r.Acknowledged > 0 {
+			line += fmt.Sprintf(" · MTTA %s (%d)", formatElapsed(r.MTTA), r.Acknowledged)
+		}
+		if r.Resolved > 0 {
+			line += fmt.Sprintf(" · MTTR %s (%d)", formatElapsed(r.MTTR), r.Resolved)
+		}
+		responses = append(responses, line)
+	}
+	if len(responses) > 0 {
+		fields = append(fields, Field{Title: "By severity", Value: strings.Join(responses, "\n")})
+	}
+
+	var text string
+	if len(d.Noisy) > 0 {
+		lines := make([]string, 0, len(d.Noisy)+1)
+		lines = append(lines, "**Noisiest alerts**")
+		for i, n := range d.Noisy {
+			lines = append(lines, fmt.Sprintf("%d. [%s](%s/alerts/feed?fingerprint=%s) · fired %d times",
+				i+1,
+				truncateWidth(n.Name, maxAlertNameWidth),
+				keepUIURL,
+				url.QueryEscape(n.Fingerprint),
+				n.Fires,
+			))
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	return Attachment{
+		Color:      color,
+		Title:      fmt.Sprintf("📋 Alert digest · %s – %s", d.From.UTC().Format("2006-01-02 15:04"), d.To.UTC().Format("2006-01-02 15:04 UTC")),
+		TitleLink:  keepUIURL + "/alerts/feed",
+		Text:       text,
+		Fields:     fields,
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+}
+
+// formatDigestMean renders a mean time over count alerts, e.g.
+// "12m over 4 acknowledged alerts".
+func formatDigestMean(mean time.Duration, count int, outcome string) string {
+	switch count {
+	case 0:
+		return "No alerts " + outcome
+	case 1:
+		return fmt.Sprintf("%s over 1 %s alert", formatElapsed(mean), outcome)
+	default:
+		return fmt.Sprintf("%s over %d %s alerts", formatElapsed(mean), count, outcome)
+	}
+}
+
+// formatPercent renders a fraction as a percentage with up to two decimals,
+// e.g. 0.9987 as "99.87%" and 0.99 as "99%".
+func formatPercent(f float64) string {
+	s := strconv.FormatFloat(f*100, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
+
+// alertFields returns the description, label and severity fields of a,
+// followed by the fingerprint when enabled and its links. Fields that do not
+// fit the post are shortened or left out, and a note links to the full alert
+// at titleLink. Compact attachments have none.
+func (b *Builder) alertFields(a *alert.Alert, severity, titleLink string) []Field {
+	if b.isCompact(a) {
+		return nil
+	}
+
+	fields := b.buildFields(a.Labels(), severity)
+
+	if b.style.ShowDescriptionField() && a.Description() != "" {
+		fields = append([]Field{
+			{Title: "Description", Value: b.escapeValue(DescriptionField, truncateWidth(a.Description(), maxDescriptionWidth)), Short: false},
+		}, fields...)
+	}
+
+	fields = append(fields, b.annotationFieldsOf(a)...)
+	fields = append(fields, b.enrichmentFieldsOf(a)...)
+
+	var trailing []Field
+	if b.style.ShowFingerprintField() {
+		fp := a.Fingerprint().Value()
+		trailing = append(trailing, Field{
+			Title: "Fingerprint",
+			Value: fmt.Sprintf("`%s`\nDetails: `/keep info %s`", fp, fp),
+			Short: true,
+		})
+	}
+
+	if field, ok := b.occurrencesField(a); ok {
+		trailing = append(trailing, field)
+	}
+
+	if field, ok := b.sourceField(a); ok {
+		trailing = append(trailing, field)
+	}
+
+	if field, ok := b.linksField(a); ok {
+		trailing = append(trailing, field)
+	}
+
+	note := Field{Value: fmt.Sprintf(truncatedFieldText, titleLink), Short: false}
+	budget := maxFieldsBytes - fieldsSize(trailing) - fieldsSize([]Field{note})
+	fields, truncated := fitFields(fields, budget, maxFields-len(trailing)-1)
+	if truncated {
+		attachmentRendersTruncatedCounter.Inc()
+		trailing = append(trailing, note)
+	}
+
+	return append(fields, trailing...)
+}
+
+// withIdentity sets the bot name and avatar of severity on a.
+func (b *Builder) withIdentity(a Attachment, severity string) Attachment {
+	if b.identity != nil {
+		a.Username, a.IconURL = b.identity.BotIdentity(severity)
+	}
+	return a
+}
+
+// isCompact reports whether a is rendered in the compact style: its title
+// line and a single button.
+func (b *Builder) isCompact(a *alert.Alert) bool {
+	return b.compact || slices.Contains(b.compactSeverities, a.Severity().String())
+}
+
+// keepButton returns the button of buttons with the given ID, the one action
+// a compact attachment offers.
+func keepButton(buttons []Button, id string) []Button {
+	for _, button := range buttons {
+		if button.ID == id {
+			return []Button{button}
+		}
+	}
+	return nil
+}
+
+// annotationFieldsOf returns the annotation fields of a the annotation
+// policy picks. Annotations are free text such as summaries, so they get the
+// description's width limit.
+func (b *Builder) annotationFieldsOf(a *alert.Alert) []Field {
+	if b.annotations == nil {
+		return nil
+	}
+	annotations := a.Annotations()
+	var fields []Field
+	for _, key := range b.annotations.ShownAnnotations(annotations) {
+		fields = append(fields, Field{
+			Title: truncateWidth(b.annotations.AnnotationTitle(key), maxFieldTitleWidth),
+			Value: b.escapeValue(key, truncateWidth(strings.TrimSpace(annotations[key]), maxDescriptionWidth)),
+			Short: false,
+		})
+	}
+	return fields
+}
+
+// enrichmentFieldsOf returns the configured enrichment fields a has a value
+// for. Values such as AI summaries can be long, so they get the description's
+// width limit.
+func (b *Builder) enrichmentFieldsOf(a *alert.Alert) []Field {
+	if len(b.enrichments) == 0 {
+		return nil
+	}
+	enrichments := a.Enrichments()
+	var fields []Field
+	for _, ef := range b.enrichments {
+		value := strings.TrimSpace(enrichments[ef.Key])
+		if value == "" {
+			continue
+		}
+		fields = append(fields, Field{
+			Title: truncateWidth(ef.Title, maxFieldTitleWidth),
+			Value: b.escapeValue(ef.Key, truncateWidth(value, maxDescriptionWidth)),
+			Short: ef.Short,
+		})
+	}
+	return fields
+}
+
+func (b *Builder) linksField(a *alert.Alert) (Field, bool) {
+	if b.links == nil {
+		return Field{}, false
+	}
+	links := b.links.Links(a)
+	if len(links) == 0 {
+		return Field{}, false
+	}
+	values := make([]string, len(links))
+	for i, link := range links {
+		values[i] = fmt.Sprintf("[%s](%s)", link.Title, link.URL)
+	}
+	return Field{Title: "Links", Value: strings.Join(values, " · "), Short: false}, true
+}
+
+func (b *Builder) buildFields(labels map[string]string, severity string) []Field {
+	var displayFields []Field
+	groupBuckets := make(map[string][]string)
+	var ungroupedLabels []string
+
+	groups := b.style.GetLabelGroups()
+	groupingEnabled := b.style.IsLabelGroupingEnabled()
+	threshold := b.style.GetLabelGroupingThreshold()
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if b.style.IsLabelExcluded(key) {
+			continue
+		}
+
+		value := labels[key]
+		if value == "" {
+			continue
+		}
+
+		if b.style.IsLabelDisplayed(key) {
+			displayName := b.style.RenameLabel(key)
+			displayFields = append(displayFields, Field{
+				Title: truncateWidth(displayName, maxFieldTitleWidth),
+				Value: b.escapeValue(key, truncateWidth(value, maxFieldValueWidth)),
+				Short: true,
+			})
+			continue
+		}
+
+		if groupingEnabled {
+			groupName := b.matchLabelToGroup(key, groups)
+			if groupName != "" {
+				formattedKey := b.formatLabelKey(key, groups)
+				groupBuckets[groupName] = append(groupBuckets[groupName], fmt.Sprintf(" %s: %s", formattedKey, b.labelCode(key, truncateWidth(value, maxFieldValueWidth))))
+			} else {
+				ungroupedLabels = append(ungroupedLabels, fmt.Sprintf(" %s: %s", key, b.labelCode(key, truncateWidth(value, maxFieldValueWidth))))
+			}
+		}
+	}
+
+	var severityField Field
+	showSeverity := b.style.ShowSeverityField()
+	severityPosition := b.style.SeverityFieldPosition()
+
+	if showSeverity {
+		severityField = Field{
+			Title: "Severity",
+			Value: strings.ToUpper(severity),
+			Short: true,
+		}
+	}
+
+	var result []Field
+
+	if showSeverity && severityPosition == SeverityPositionFirst {
+		result = append(result, severityField)
+	}
+
+	result = append(result, displayFields...)
+
+	if showSeverity && severityPosition == SeverityPositionAfterDisplay {
+		result = append(result, severityField)
+	}
+
+	if groupingEnabled {
+		sortedGroups := b.sortGroupsByPriority(groups)
+		for _, group := range sortedGroups {
+			bucket := groupBuckets[group.GroupName]
+			if len(bucket) >= threshold {
+				result = append(result, Field{
+					Title: group.GroupName,
+					Value: strings.Join(bucket, "\n"),
+					Short: true,
+				})
+			} else {
+				ungroupedLabels = append(ungroupedLabels, bucket...)
+			}
+		}
+
+		if len(ungroupedLabels) > 0 {
+			result = append(result, Field{
+				Title: "Labels",
+				Value: strings.Join(ungroupedLabels, "\n"),
+				Short: true,
+			})
+		}
+	}
+
+	if showSeverity && severityPosition == SeverityPositionLast {
+		result = append(result, severityField)
+	}
+
+	return result
+}
+
+func (b *Builder) matchLabelToGroup(key string, groups []LabelGroup) string {
+	for _, group := range groups {
+		for _, prefix := range group.Prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return group.GroupName
+			}
+		}
+	}
+	return ""
+}
+
+func (b *Builder) formatLabelKey(key string, groups []LabelGroup) string {
+	for _, group := range groups {
+		for _, prefix := range group.Prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return strings.TrimPrefix(key, prefix)
+			}
+		}
+	}
+	return key
+}
+
+func (b *Builder) sortGroupsByPriority(groups []LabelGroup) []LabelGroup {
+	sorted := make([]LabelGroup, len(groups))
+	copy(sorted, groups)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+	return sorted
+}
+
+// formatTitle renders "<emoji> <name> (<duration>)", truncating only the
+// alert name so the status emoji and firing duration always stay visible.
+func formatTitle(emoji string, a *alert.Alert, now time.Time) string {
+	title := fmt.Sprintf("%s %s", emoji, truncateWidth(a.Name(), maxAlertNameWidth))
+	if duration := formatDuration(a.FiringStartTime(), now); duration != "" {
+		title = fmt.Sprintf("%s (%s)", title, duration)
+	}
+	return title
+}
+
+func formatDuration(start, now time.Time) string {
+	if start.IsZero() {
+		return ""
+	}
+
+	d := now.Sub(start)
+	if d < 0 {
+		return ""
+	}
+	return formatElapsed(d)
+}
+
+// formatElapsed renders d in its two largest units, e.g. "2d 3h" or "5m".
+func formatElapsed(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return "<1m"
+	}
+}
+
+// formatSnoozeDuration renders d without trailing zero units, e.g. "1h" or
+// "1h30m" rather than "1h0m0s".
+func formatSnoozeDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,380 @@
+package attachment
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// testStyle is a minimal Style: labels listed in displayed become fields,
+// everything else is left out.
+type testStyle struct {
+	colors    map[string]string
+	emoji     map[string]string
+	displayed []string
+	footer    string
+}
+
+func (s *testStyle) ColorForSeverity(severity string) string { return s.colors[severity] }
+func (s *testStyle) EmojiForSeverity(severity string) string { return s.emoji[severity] }
+func (s *testStyle) IsLabelExcluded(label string) bool       { return false }
+func (s *testStyle) IsLabelDisplayed(label string) bool {
+	return slices.Contains(s.displayed, label)
+}
+func (s *testStyle) RenameLabel(label string) string { return label }
+func (s *testStyle) FooterText() string              { return s.footer }
+func (s *testStyle) FooterIconURL() string           { return "" }
+func (s *testStyle) IsLabelGroupingEnabled() bool    { return false }
+func (s *testStyle) GetLabelGroupingThreshold() int  { return 0 }
+func (s *testStyle) GetLabelGroups() []LabelGroup    { return nil }
+func (s *testStyle) ShowSeverityField() bool         { return true }
+func (s *testStyle) ShowDescriptionField() bool      { return true }
+func (s *testStyle) ShowFingerprintField() bool      { return false }
+func (s *testStyle) SeverityFieldPosition() string   { return SeverityPositionFirst }
+
+func newTestAlert(fingerprint string, firingStart time.Time) *alert.Alert {
+	return alert.RestoreAlert(
+		alert.RestoreFingerprint(fingerprint),
+		"Disk full",
+		alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"prometheus",
+		map[string]string{"namespace": "prod"},
+		firingStart,
+	)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	firingStart := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	style := &testStyle{colors: map[string]string{"critical": "#CC0000"}, emoji: map[string]string{"critical": "🔴"}, displayed: []string{"namespace"}}
+
+	builder, err := New(style)
+	require.NoError(t, err)
+	a := newTestAlert("fp-1", time.Time{})
+	card := builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui")
+	assert.Equal(t, "#CC0000", card.Color)
+	assert.Equal(t, "🔴 Disk full", card.Title)
+	assert.Equal(t, []Field{{Title: "Severity", Value: "CRITICAL", Short: true}, {Title: "namespace", Value: "prod", Short: true}}, card.Fields)
+	require.Len(t, card.Actions, 2, "no optional buttons by default")
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID)
+	assert.Equal(t, ActionResolve, card.Actions[1].ID)
+
+	builder, err = New(style,
+		WithClock(clock.NewFake(firingStart.Add(90*time.Minute))),
+		WithSnoozeDuration(time.Hour),
+		WithCopyCommands([]CopyCommand{{Name: "logs", Template: "kubectl -n {{.Labels.namespace}} logs"}}, ""),
+	)
+	require.NoError(t, err)
+	card = builder.BuildFiringAttachment(newTestAlert("fp-1", firingStart), "http://callback", "http://keep.ui")
+	assert.Equal(t, "🔴 Disk full (1h 30m)", card.Title)
+	require.Len(t, card.Actions, 4)
+	assert.Equal(t, "Snooze 1h", card.Actions[2].Name)
+	assert.Equal(t, ActionCommands, card.Actions[3].ID)
+}
+
+func TestFormatDuration(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		expected string
+	}{
+		{
+			name:     "zero time returns empty string",
+			start:    time.Time{},
+			expected: "",
+		},
+		{
+			name:     "future time returns empty string",
+			start:    now.Add(1 * time.Hour),
+			expected: "",
+		},
+		{
+			name:     "less than 1 minute ago",
+			start:    now.Add(-30 * time.Second),
+			expected: "<1m",
+		},
+		{
+			name:     "45 minutes ago",
+			start:    now.Add(-45 * time.Minute),
+			expected: "45m",
+		},
+		{
+			name:     "2 hours 15 minutes ago",
+			start:    now.Add(-2*time.Hour - 15*time.Minute),
+			expected: "2h 15m",
+		},
+		{
+			name:     "3 days 12 hours ago",
+			start:    now.Add(-3*24*time.Hour - 12*time.Hour),
+			expected: "3d 12h",
+		},
+		{
+			name:     "exactly 1 hour",
+			start:    now.Add(-1 * time.Hour),
+			expected: "1h 0m",
+		},
+		{
+			name:     "exactly 1 day",
+			start:    now.Add(-24 * time.Hour),
+			expected: "1d 0h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatDuration(tt.start, now)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFormatSnoozeDuration(t *testing.T) {
+	assert.Equal(t, "1h", formatSnoozeDuration(time.Hour))
+	assert.Equal(t, "30m", formatSnoozeDuration(30*time.Minute))
+	assert.Equal(t, "1h30m", formatSnoozeDuration(90*time.Minute))
+	assert.Equal(t, "168h", formatSnoozeDuration(168*time.Hour))
+	assert.Equal(t, "1m30s", formatSnoozeDuration(90*time.Second))
+}
+
+func TestBuildFlappingAttachment(t *testing.T) {
+	builder, err := New(&testStyle{colors: map[string]string{"critical": "#CC0000", "flapping": "#D35400"}})
+	require.NoError(t, err)
+
+	card := builder.BuildFlappingAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui", 6, 30*time.Minute)
+	assert.Equal(t, "#D35400", card.Color)
+	assert.True(t, strings.HasPrefix(card.Title, "🔁 "), card.Title)
+	assert.Equal(t, "Flapping: 6 state changes in 30m, updates paused", card.Footer)
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID)
+	assert.Equal(t, ActionResolve, card.Actions[1].ID)
+}
+
+func TestBuildCollapsedAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"resolved": "#00CC00"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+
+	attachment := builder.BuildCollapsedAttachment("Disk full", "fp 1", "http://keep.ui",
+		time.Date(2026, 1, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600)))
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Equal(t, "✅ [Disk full](http://keep.ui/alerts/feed?fingerprint=fp+1) · resolved 2026-01-01 11:30 UTC", attachment.Text)
+	assert.Empty(t, attachment.Title)
+	assert.Empty(t, attachment.Fields)
+	assert.Empty(t, attachment.Actions)
+	assert.Empty(t, attachment.Footer)
+}
+
+func TestBuildOverflowAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"critical": "#CC0000", "warning": "#EDA200", "resolved": "#00CC00"},
+		emoji:  map[string]string{"critical": "🔴", "warning": "⚠️"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+	heldAt := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	attachment := builder.BuildOverflowAttachment([]HeldAlert{
+		{Fingerprint: "fp 1", Name: "Disk full", Severity: "warning", HeldAt: heldAt},
+		{Fingerprint: "fp-2", Name: "Node down", Severity: "critical", HeldAt: heldAt.Add(time.Minute)},
+	}, "http://keep.ui")
+	assert.Equal(t, "#CC0000", attachment.Color, "colored by most severe held alert")
+	assert.Equal(t, "⏸️ 2 alerts held · notification budget exceeded", attachment.Title)
+	assert.Equal(t, "http://keep.ui/alerts/feed", attachment.TitleLink)
+	assert.Equal(t, "These alerts will be posted one by one as the channel's hourly budget recovers.\n\n"+
+		"⚠️ [Disk full](http://keep.ui/alerts/feed?fingerprint=fp+1) · held since 12:30 UTC\n"+
+		"🔴 [Node down](http://keep.ui/alerts/feed?fingerprint=fp-2) · held since 12:31 UTC", attachment.Text)
+	assert.Empty(t, attachment.Actions)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	held := make([]HeldAlert, maxOverflowLines+3)
+	for i := range held {
+		held[i] = HeldAlert{Fingerprint: "fp", Name: "Alert", Severity: "warning", HeldAt: heldAt}
+	}
+	attachment = builder.BuildOverflowAttachment(held, "http://keep.ui")
+	assert.True(t, strings.HasSuffix(attachment.Text, "\n…and 3 more"))
+
+	attachment = builder.BuildOverflowAttachment(nil, "http://keep.ui")
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Equal(t, "✅ Notification budget recovered", attachment.Title)
+}
+
+func TestBuildQuietHoursDigestAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"critical": "#CC0000", "warning": "#EDA200", "resolved": "#00CC00"},
+		emoji:  map[string]string{"critical": "🔴", "warning": "⚠️"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+	heldAt := time.Date(2026, 1, 1, 23, 10, 0, 0, time.UTC)
+
+	attachment := builder.BuildQuietHoursDigestAttachment([]DigestAlert{
+		{Fingerprint: "fp 1", Name: "Disk full", Severity: "warning", HeldAt: heldAt},
+		{Fingerprint: "fp-2", Name: "Node down", Severity: "critical", HeldAt: heldAt.Add(time.Hour), ResolvedAt: heldAt.Add(2 * time.Hour)},
+	}, "http://keep.ui")
+	assert.Equal(t, "#EDA200", attachment.Color, "colored by most severe alert still firing")
+	assert.Equal(t, "🌅 Quiet hours digest · 2 alerts held, 1 still firing", attachment.Title)
+	assert.Equal(t, "http://keep.ui/alerts/feed", attachment.TitleLink)
+	assert.Equal(t, "⚠️ [Disk full](http://keep.ui/alerts/feed?fingerprint=fp+1) · held at 23:10 UTC · still firing\n"+
+		"🔴 [Node down](http://keep.ui/alerts/feed?fingerprint=fp-2) · held at 00:10 UTC · resolved at 01:10 UTC", attachment.Text)
+	assert.Empty(t, attachment.Actions)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	held := make([]DigestAlert, maxOverflowLines+3)
+	for i := range held {
+		held[i] = DigestAlert{Fingerprint: "fp", Name: "Alert", Severity: "warning", HeldAt: heldAt, ResolvedAt: heldAt}
+	}
+	attachment = builder.BuildQuietHoursDigestAttachment(held, "http://keep.ui")
+	assert.True(t, strings.HasSuffix(attachment.Text, "\n…and 3 more"))
+	assert.Equal(t, "#00CC00", attachment.Color, "resolved color when nothing is firing")
+}
+
+func TestBuildAlertDigestAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"critical": "#CC0000", "warning": "#EDA200", "resolved": "#00CC00"},
+		emoji:  map[string]string{"critical": "🔴", "warning": "⚠️"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+	from := time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC)
+
+	attachment := builder.BuildAlertDigestAttachment(AlertDigest{
+		From:         from,
+		To:           from.Add(24 * time.Hour),
+		Fired:        5,
+		BySeverity:   map[string]int{"warning": 3, "critical": 2},
+		Noisy:        []NoisyAlert{{Fingerprint: "fp 1", Name: "Disk full", Fires: 7}, {Fingerprint: "fp-2", Name: "Node down", Fires: 2}},
+		Acknowledged: 1,
+		MTTA:         12 * time.Minute,
+		Resolved:     4,
+		MTTR:         90 * time.Minute,
+		ResponseBySeverity: map[string]ResponseTimes{
+			"critical": {Acknowledged: 1, MTTA: 12 * time.Minute, Resolved: 2, MTTR: time.Hour},
+			"warning":  {Resolved: 2, MTTR: 2 * time.Hour},
+		},
+	}, "http://keep.ui")
+	assert.Equal(t, "#CC0000", attachment.Color, "colored by the highest severity fired")
+	assert.Equal(t, "📋 Alert digest · 2026-01-04 09:00 – 2026-01-05 09:00 UTC", attachment.Title)
+	assert.Equal(t, "**Noisiest alerts**\n"+
+		"1. [Disk full](http://keep.ui/alerts/feed?fingerprint=fp+1) · fired 7 times\n"+
+		"2. [Node down](http://keep.ui/alerts/feed?fingerprint=fp-2) · fired 2 times", attachment.Text)
+	require.Len(t, attachment.Fields, 4)
+	assert.Equal(t, "5 · 🔴 2 critical · ⚠️ 3 warning", attachment.Fields[0].Value)
+	assert.Equal(t, "12m over 1 acknowledged alert", attachment.Fields[1].Value)
+	assert.Equal(t, "1h 30m over 4 resolved alerts", attachment.Fields[2].Value)
+	assert.Equal(t, "🔴 critical · MTTA 12m (1) · MTTR 1h 0m (2)\n⚠️ warning · MTTR 2h 0m (2)", attachment.Fields[3].Value)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	attachment = builder.BuildAlertDigestAttachment(AlertDigest{From: from, To: from.Add(time.Hour)}, "http://keep.ui")
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Empty(t, attachment.Text)
+	assert.Equal(t, "0", attachment.Fields[0].Value)
+	assert.Equal(t, "No alerts acknowledged", attachment.Fields[1].Value)
+	assert.Len(t, attachment.Fields, 3, "no severity breakdown without responses")
+}
+
+func TestBuildSLOReportAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"critical": "#CC0000", "resolved": "#00CC00"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	attachment := builder.BuildSLOReportAttachment([]SLOResult{
+		{Name: "webhook", Target: 0.99, Threshold: 2 * time.Second, Total: 1000, Good: 995},
+		{Name: "callback", Target: 0.99, Threshold: time.Second},
+	}, from, to)
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Equal(t, "📊 SLO report · 2026-01-01 00:00 – 2026-01-08 00:00 UTC", attachment.Title)
+	require.Len(t, attachment.Fields, 2)
+	assert.Equal(t, "✅ webhook within 2s", attachment.Fields[0].Title)
+	assert.Equal(t, "99.5% of 1000 requests (target 99%) · 50% of error budget used", attachment.Fields[0].Value)
+	assert.Equal(t, "✅ callback within 1s", attachment.Fields[1].Title)
+	assert.Equal(t, "100% of 0 requests (target 99%) · 0% of error budget used", attachment.Fields[1].Value)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	attachment = builder.BuildSLOReportAttachment([]SLOResult{
+		{Name: "webhook", Target: 0.999, Threshold: 2 * time.Second, Total: 1000, Good: 997},
+	}, from, to)
+	assert.Equal(t, "#CC0000", attachment.Color, "a missed objective turns the report red")
+	assert.Equal(t, "❌ webhook within 2s", attachment.Fields[0].Title)
+	assert.Equal(t, "99.7% of 1000 requests (target 99.9%) · 300% of error budget used", attachment.Fields[0].Value)
+}
+
+func TestBuilderBotIdentity(t *testing.T) {
+	firingStart := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	builder, err := New(&testStyle{}, WithBotIdentity(testBotIdentity{"critical": "red.png"}))
+	require.NoError(t, err)
+
+	a := newTestAlert("fp-critical", firingStart)
+	firing := builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui")
+	assert.Equal(t, "Alerts critical", firing.Username)
+	assert.Equal(t, "red.png", firing.IconURL)
+
+	processing, err := builder.BuildProcessingAttachment(firing.Actions[0].Integration.Context[ContextKeyAttachmentJSON], ActionAcknowledge)
+	require.NoError(t, err)
+	assert.Equal(t, "red.png", processing.IconURL, "the processing post keeps the identity")
+
+	resolved := builder.BuildResolvedAttachment(a, "http://keep.ui", "")
+	assert.Equal(t, "red.png", resolved.IconURL)
+
+	plain, err := New(&testStyle{})
+	require.NoError(t, err)
+	assert.Empty(t, plain.BuildFiringAttachment(a, "http://callback", "http://keep.ui").Username)
+}
+
+// testBotIdentity maps severities to icon URLs.
+type testBotIdentity map[string]string
+
+func (p testBotIdentity) BotIdentity(severity string) (string, string) {
+	return "Alerts " + severity, p[severity]
+}
+
+func TestBuilderCompactSeverities(t *testing.T) {
+	firingStart := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	style := &testStyle{emoji: map[string]string{"info": "🔵"}, displayed: []string{"namespace"}}
+	builder, err := New(style,
+		WithCompactSeverities([]string{"Info"}),
+		WithSnoozeDuration(time.Hour),
+		WithClock(clock.NewFake(firingStart.Add(5*time.Minute))),
+	)
+	require.NoError(t, err)
+
+	info := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-info"),
+		"Cert expires soon",
+		alert.RestoreSeverity(alert.SeverityInfo),
+		alert.RestoreStatus(alert.StatusFiring),
+		"The certificate expires in 14 days",
+		"prometheus",
+		map[string]string{"namespace": "prod"},
+		firingStart,
+	)
+	card := builder.BuildFiringAttachment(info, "http://callback", "http://keep.ui")
+	assert.Equal(t, "🔵 Cert expires soon (5m)", card.Title)
+	assert.Empty(t, card.Fields)
+	require.Len(t, card.Actions, 1)
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID)
+
+	acked := builder.BuildAcknowledgedAttachment(info, "http://callback", "http://keep.ui", "alice")
+	assert.Empty(t, acked.Fields)
+	require.Len(t, acked.Actions, 1)
+	assert.Equal(t, ActionResolve, acked.Actions[0].ID)
+	assert.Equal(t, "Acknowledged by @alice", acked.Footer)
+
+	full := builder.BuildFiringAttachment(newTestAlert("fp-critical", firingStart), "http://callback", "http://keep.ui")
+	assert.NotEmpty(t, full.Fields, "other severities keep the full style")
+	assert.Len(t, full.Actions, 3)
+}
@@ -0,0 +1,109 @@
+package attachment
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type copyCommand struct {
+	name string
+	tmpl *template.Template
+}
+
+// commandData is what copy command templates are rendered from.
+type commandData struct {
+	Fingerprint string
+	Name        string
+	Severity    string
+	Status      string
+	Labels      map[string]string
+	KeepURL     string
+	KeepUIURL   string
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+var commandFuncs = template.FuncMap{"quote": shellQuote}
+
+// shellQuote single-quotes s unless it consists only of characters that are
+// safe in a POSIX shell word.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// compileCopyCommands parses every command template upfront so broken
+// templates are reported when the builder is created.
+func compileCopyCommands(commands []CopyCommand) ([]copyCommand, error) {
+	compiled := make([]copyCommand, 0, len(commands))
+	for _, cmd := range commands {
+		tmpl, err := template.New(cmd.Name).Funcs(commandFuncs).Option("missingkey=error").Parse(cmd.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parse copy command %q: %w", cmd.Name, err)
+		}
+		compiled = append(compiled, copyCommand{name: cmd.Name, tmpl: tmpl})
+	}
+	return compiled, nil
+}
+
+// renderCommands renders the copy commands for a as a Markdown message with
+// one code block per command. Commands that fail to render, typically because
+// the alert lacks a label they use, are skipped; an empty result means none
+// applied.
+func (b *Builder) renderCommands(a *alert.Alert, keepUIURL string) string {
+	data := commandData{
+		Fingerprint: a.Fingerprint().Value(),
+		Name:        a.Name(),
+		Severity:    a.Severity().String(),
+		Status:      a.Status().String(),
+		Labels:      a.Labels(),
+		KeepURL:     b.keepURL,
+		KeepUIURL:   keepUIURL,
+	}
+
+	var blocks []string
+	for _, cmd := range b.copyCommands {
+		var out strings.Builder
+		if err := cmd.tmpl.Execute(&out, data); err != nil {
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("**%s**\n```\n%s\n```", cmd.name, strings.TrimSpace(out.String())))
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Commands for **%s** (`%s`):\n\n%s", a.Name(), a.Fingerprint().Value(), strings.Join(blocks, "\n"))
+}
+
+// commandsButton returns the Commands button for a, or false when no copy
+// command applies to it.
+func (b *Builder) commandsButton(a *alert.Alert, callbackURL, keepUIURL string) (Button, bool) {
+	if len(b.copyCommands) == 0 {
+		return Button{}, false
+	}
+	text := b.renderCommands(a, keepUIURL)
+	if text == "" {
+		return Button{}, false
+	}
+	return Button{
+		ID:    ActionCommands,
+		Name:  "Commands",
+		Style: ButtonStyleDefault,
+		Integration: ButtonIntegration{
+			URL: callbackURL,
+			Context: map[string]string{
+				ContextKeyAction:      ActionCommands,
+				ContextKeyFingerprint: a.Fingerprint().Value(),
+				ContextKeyAlertName:   a.Name(),
+				ContextKeyCommands:    text,
+			},
+		},
+	}, true
+}
@@ -0,0 +1,22 @@
+package attachment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCopyCommandsInvalidTemplate(t *testing.T) {
+	_, err := New(&testStyle{}, WithCopyCommands([]CopyCommand{{Name: "broken", Template: "{{.Labels"}}, ""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `parse copy command "broken"`)
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "prod-eu/api_1.2:8080", shellQuote("prod-eu/api_1.2:8080"))
+	assert.Equal(t, "'two words'", shellQuote("two words"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+	assert.Equal(t, "'$(rm -rf /)'", shellQuote("$(rm -rf /)"))
+	assert.Equal(t, "''", shellQuote(""))
+}
@@ -0,0 +1,41 @@
+package attachment
+
+import (
+	"fmt"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// customActionButtons returns the buttons of the custom actions that apply to
+// a. Button IDs only need to be unique within the post; the action is named
+// in the context, so reordering the configured actions does not change what
+// the buttons of existing posts run.
+func (b *Builder) customActionButtons(a *alert.Alert, callbackURL string) []Button {
+	if b.customActions == nil {
+		return nil
+	}
+	actions := b.customActions.CustomActions(a)
+	buttons := make([]Button, 0, len(actions))
+	for i, action := range actions {
+		style := action.Style
+		if style == "" {
+			style = ButtonStyleDefault
+		}
+		buttons = append(buttons, Button{
+			ID:    fmt.Sprintf("%s%d", ActionCustom, i),
+			Name:  action.Name,
+			Style: style,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:       ActionCustom,
+					ContextKeyCustomAction: action.Name,
+					ContextKeyFingerprint:  a.Fingerprint().Value(),
+					ContextKeyAlertName:    a.Name(),
+					ContextKeySeverity:     a.Severity().String(),
+				},
+			},
+		})
+	}
+	return buttons
+}
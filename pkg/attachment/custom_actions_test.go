@@ -0,0 +1,49 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type customActionPolicyFunc func(a *alert.Alert) []CustomAction
+
+func (f customActionPolicyFunc) CustomActions(a *alert.Alert) []CustomAction {
+	return f(a)
+}
+
+func TestCustomActionButtons(t *testing.T) {
+	policy := customActionPolicyFunc(func(a *alert.Alert) []CustomAction {
+		return []CustomAction{{Name: "Restart pod", Style: ButtonStyleDanger}, {Name: "Page SRE"}}
+	})
+	builder, err := New(&testStyle{}, WithCustomActions(policy))
+	require.NoError(t, err)
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	require.Len(t, card.Actions, 4)
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID)
+	assert.Equal(t, ActionResolve, card.Actions[1].ID)
+
+	restart := card.Actions[2]
+	assert.Equal(t, "custom0", restart.ID)
+	assert.Equal(t, "Restart pod", restart.Name)
+	assert.Equal(t, ButtonStyleDanger, restart.Style)
+	assert.Equal(t, "http://callback", restart.Integration.URL)
+	assert.Equal(t, map[string]string{
+		ContextKeyAction:       ActionCustom,
+		ContextKeyCustomAction: "Restart pod",
+		ContextKeyFingerprint:  "fp-1",
+		ContextKeyAlertName:    "Disk full",
+		ContextKeySeverity:     "critical",
+	}, restart.Integration.Context)
+	assert.Equal(t, ButtonStyleDefault, card.Actions[3].Style)
+
+	acked := builder.BuildAcknowledgedAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui", "alice")
+	for _, button := range acked.Actions {
+		assert.NotEqual(t, ActionCustom, button.Integration.Context[ContextKeyAction], "custom actions are only offered on firing alerts")
+	}
+}
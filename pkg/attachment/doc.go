@@ -0,0 +1,20 @@
+// Package attachment renders Keep alerts as Mattermost message attachments,
+// exactly as the bridge posts them, so other tools can produce the same alert
+// cards.
+//
+// A Builder is created with New from a Style, which supplies colors, emoji,
+// label handling and the footer, and optional features enabled through
+// Options:
+//
+//	builder, err := attachment.New(style,
+//		attachment.WithSnoozeDuration(time.Hour),
+//		attachment.WithCopyCommands(commands, "https://keep-api.example.com"),
+//	)
+//	card := builder.BuildFiringAttachment(a, callbackURL, keepUIURL)
+//
+// The exported API follows semantic versioning together with the module:
+// exported identifiers are not removed or changed incompatibly within a
+// major version. Attachment JSON produced by ToJSON stays readable by
+// FromJSON across versions, since rendered attachments are stored in button
+// contexts of existing posts.
+package attachment
@@ -0,0 +1,41 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichmentFields(t *testing.T) {
+	builder, err := New(&testStyle{}, WithEnrichmentFields([]EnrichmentField{
+		{Key: "notes", Title: "Notes", Short: true},
+		{Key: "ai_summary", Title: "AI summary"},
+		{Key: "runbook", Title: "Runbook"},
+	}))
+	require.NoError(t, err)
+
+	a := newTestAlert("fp-1", time.Time{}).WithEnrichments(map[string]string{
+		"ai_summary": "Disk usage grew 20% in an hour",
+		"notes":      "Known issue, see ticket",
+		"runbook":    "  ",
+		"assignee":   "alice",
+	})
+
+	var got []Field
+	for _, f := range builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui").Fields {
+		if f.Title == "Notes" || f.Title == "AI summary" || f.Title == "Runbook" || f.Title == "assignee" {
+			got = append(got, f)
+		}
+	}
+	assert.Equal(t, []Field{
+		{Title: "Notes", Value: "Known issue, see ticket", Short: true},
+		{Title: "AI summary", Value: "Disk usage grew 20% in an hour"},
+	}, got, "configured enrichments are shown in order; blank and unlisted ones are left out")
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-2", time.Time{}), "http://callback", "http://keep.ui")
+	for _, f := range card.Fields {
+		assert.NotEqual(t, "AI summary", f.Title, "alerts without enrichments get no fields")
+	}
+}
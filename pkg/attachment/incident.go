@@ -0,0 +1,106 @@
+package attachment
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+)
+
+// BuildIncidentAttachment renders a Keep incident in its current status. An
+// open incident offers Acknowledge and Resolve, an acknowledged one only
+// Resolve; closed incidents have no buttons. The buttons post to
+// callbackURL with the incident ID in their context.
+func (b *Builder) BuildIncidentAttachment(inc *incident.Incident, callbackURL, keepUIURL string) Attachment {
+	severity := inc.Severity().String()
+
+	var color, emoji, footer string
+	var actions []string
+	switch inc.Status() {
+	case incident.StatusAcknowledged:
+		color = b.style.ColorForSeverity("acknowledged")
+		emoji = "👀"
+		if inc.AcknowledgedBy() != "" {
+			footer = fmt.Sprintf("Acknowledged by @%s", inc.AcknowledgedBy())
+		}
+		actions = []string{ActionResolve}
+	case incident.StatusResolved:
+		color = b.style.ColorForSeverity("resolved")
+		emoji = "✅"
+		footer = "Incident resolved"
+	case incident.StatusMerged:
+		color = b.style.ColorForSeverity("merged")
+		emoji = "🔀"
+		footer = "Merged into another incident"
+	case incident.StatusDeleted:
+		color = b.style.ColorForSeverity("dismissed")
+		emoji = "🚫"
+		footer = "Incident deleted"
+	default:
+		color = b.style.ColorForSeverity(severity)
+		emoji = b.style.EmojiForSeverity(severity)
+		actions = []string{ActionAcknowledge, ActionResolve}
+	}
+
+	title := fmt.Sprintf("%s Incident: %s", emoji, truncateWidth(inc.Name(), maxAlertNameWidth))
+	if duration := formatDuration(inc.StartedAt(), b.clock.Now()); duration != "" && !inc.IsClosed() {
+		title = fmt.Sprintf("%s (%s)", title, duration)
+	}
+	titleLink := fmt.Sprintf("%s/incidents/%s", keepUIURL, url.PathEscape(inc.ID()))
+
+	noun := "alerts"
+	if inc.AlertsCount() == 1 {
+		noun = "alert"
+	}
+	fields := []Field{
+		{Title: "Severity", Value: strings.ToUpper(severity), Short: true},
+		{Title: "Linked alerts", Value: fmt.Sprintf("%d %s", inc.AlertsCount(), noun), Short: true},
+	}
+	if !inc.StartedAt().IsZero() {
+		fields = append(fields, Field{Title: "Started", Value: inc.StartedAt().UTC().Format("2006-01-02 15:04 UTC"), Short: true})
+	}
+
+	result := Attachment{
+		Color:     color,
+		Title:     title,
+		TitleLink: titleLink,
+		Fields:    fields,
+	}
+	if footer != "" {
+		result.Footer = truncateWidth(footer, maxFooterWidth)
+		result.FooterIcon = b.style.FooterIconURL()
+	}
+	if len(actions) == 0 {
+		return result
+	}
+
+	attachmentJSON, err := result.ToJSON()
+	if err != nil {
+		slog.Error("Failed to serialize attachment to JSON", slog.String("error", err.Error()))
+		attachmentJSON = ""
+	}
+
+	for _, action := range actions {
+		name, style := "Acknowledge", ButtonStyleDefault
+		if action == ActionResolve {
+			name, style = "Resolve", ButtonStyleSuccess
+		}
+		result.Actions = append(result.Actions, Button{
+			ID:    action,
+			Name:  name,
+			Style: style,
+			Integration: ButtonIntegration{
+				URL: callbackURL,
+				Context: map[string]string{
+					ContextKeyAction:         action,
+					ContextKeyIncidentID:     inc.ID(),
+					ContextKeySeverity:       severity,
+					ContextKeyAttachmentJSON: attachmentJSON,
+				},
+			},
+		})
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/incident"
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func newTestIncidentBuilder(t *testing.T, now time.Time) *Builder {
+	t.Helper()
+	style := &testStyle{
+		colors: map[string]string{"critical": "#CC0000", "acknowledged": "#FFAA00", "resolved": "#00CC00"},
+		emoji:  map[string]string{"critical": "🔴"},
+	}
+	builder, err := New(style, WithClock(clock.NewFake(now)))
+	require.NoError(t, err)
+	return builder
+}
+
+func TestBuildIncidentAttachmentFiring(t *testing.T) {
+	startedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	builder := newTestIncidentBuilder(t, startedAt.Add(2*time.Hour))
+	inc := incident.NewIncident("inc/1", "Database outage", alert.RestoreSeverity("critical"), incident.StatusFiring, 3, startedAt, time.Now())
+
+	card := builder.BuildIncidentAttachment(inc, "http://bridge/callback/incident", "http://keep.ui")
+
+	assert.Equal(t, "#CC0000", card.Color)
+	assert.Equal(t, "🔴 Incident: Database outage (2h 0m)", card.Title)
+	assert.Equal(t, "http://keep.ui/incidents/inc%2F1", card.TitleLink)
+	assert.Equal(t, []Field{
+		{Title: "Severity", Value: "CRITICAL", Short: true},
+		{Title: "Linked alerts", Value: "3 alerts", Short: true},
+		{Title: "Started", Value: "2024-06-01 10:00 UTC", Short: true},
+	}, card.Fields)
+	require.Len(t, card.Actions, 2)
+	assert.Equal(t, ActionAcknowledge, card.Actions[0].ID)
+	assert.Equal(t, ActionResolve, card.Actions[1].ID)
+	for _, button := range card.Actions {
+		assert.Equal(t, "http://bridge/callback/incident", button.Integration.URL)
+		assert.Equal(t, "inc/1", button.Integration.Context[ContextKeyIncidentID])
+		assert.Equal(t, button.ID, button.Integration.Context[ContextKeyAction])
+
+		restored, err := FromJSON(button.Integration.Context[ContextKeyAttachmentJSON])
+		require.NoError(t, err)
+		assert.Equal(t, card.Title, restored.Title)
+		assert.Empty(t, restored.Actions, "the stored attachment has no buttons")
+	}
+}
+
+func TestBuildIncidentAttachmentAcknowledged(t *testing.T) {
+	builder := newTestIncidentBuilder(t, time.Now())
+	inc := incident.NewIncident("inc-1", "Database outage", alert.RestoreSeverity("critical"), incident.StatusFiring, 1, time.Time{}, time.Now())
+	inc.Acknowledge("alice", time.Now())
+
+	card := builder.BuildIncidentAttachment(inc, "http://bridge/callback/incident", "http://keep.ui")
+
+	assert.Equal(t, "#FFAA00", card.Color)
+	assert.Equal(t, "👀 Incident: Database outage", card.Title)
+	assert.Equal(t, "1 alert", card.Fields[1].Value)
+	assert.Len(t, card.Fields, 2, "no Started field without a start time")
+	assert.Equal(t, "Acknowledged by @alice", card.Footer)
+	require.Len(t, card.Actions, 1)
+	assert.Equal(t, ActionResolve, card.Actions[0].ID)
+}
+
+func TestBuildIncidentAttachmentClosed(t *testing.T) {
+	startedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	builder := newTestIncidentBuilder(t, startedAt.Add(time.Hour))
+
+	for status, footer := range map[string]string{
+		incident.StatusResolved: "Incident resolved",
+		incident.StatusMerged:   "Merged into another incident",
+		incident.StatusDeleted:  "Incident deleted",
+	} {
+		inc := incident.NewIncident("inc-1", "Database outage", alert.RestoreSeverity("critical"), status, 2, startedAt, time.Now())
+		card := builder.BuildIncidentAttachment(inc, "http://bridge/callback/incident", "http://keep.ui")
+		assert.Equal(t, footer, card.Footer, status)
+		assert.Empty(t, card.Actions, status)
+		assert.NotContains(t, card.Title, "(1h 0m)", "closed incidents show no running duration")
+	}
+}
@@ -0,0 +1,43 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+type linkPolicyFunc func(a *alert.Alert) []Link
+
+func (f linkPolicyFunc) Links(a *alert.Alert) []Link {
+	return f(a)
+}
+
+func TestLinksField(t *testing.T) {
+	policy := linkPolicyFunc(func(a *alert.Alert) []Link {
+		if a.Fingerprint().Value() != "fp-1" {
+			return nil
+		}
+		return []Link{
+			{Title: "Runbook", URL: "https://wiki.example.com/disk-full"},
+			{Title: "Dashboard", URL: "https://grafana.example.com/d/disk?var-ns=prod"},
+		}
+	})
+	builder, err := New(&testStyle{}, WithLinks(policy))
+	require.NoError(t, err)
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	require.NotEmpty(t, card.Fields)
+	last := card.Fields[len(card.Fields)-1]
+	assert.Equal(t, "Links", last.Title)
+	assert.Equal(t, "[Runbook](https://wiki.example.com/disk-full) · [Dashboard](https://grafana.example.com/d/disk?var-ns=prod)", last.Value)
+	assert.False(t, last.Short)
+
+	card = builder.BuildFiringAttachment(newTestAlert("fp-2", time.Time{}), "http://callback", "http://keep.ui")
+	for _, f := range card.Fields {
+		assert.NotEqual(t, "Links", f.Title, "alerts without links get no field")
+	}
+}
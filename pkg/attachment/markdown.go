@@ -0,0 +1,62 @@
+package attachment
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DescriptionField names the description in the raw Markdown list of
+// WithEscaping; labels and enrichments are named by their key.
+const DescriptionField = "description"
+
+const zeroWidthSpace = "\u200b"
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `~`, `\~`,
+		`[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`,
+		`#`, `\#`, `>`, `\>`, `|`, `\|`,
+	)
+	mentionPattern   = regexp.MustCompile(`@([\pL\pN])`)
+	shortcodePattern = regexp.MustCompile(`:([a-zA-Z0-9_+-]+):`)
+)
+
+// escapeMarkdown makes s render as the text it is: Markdown syntax is
+// backslash-escaped, and a zero-width space after @ and the opening colon of
+// :shortcodes: keeps Mattermost from turning them into mentions and emoji.
+func escapeMarkdown(s string) string {
+	s = shortcodePattern.ReplaceAllString(s, ":"+zeroWidthSpace+"$1:")
+	s = markdownEscaper.Replace(s)
+	return mentionPattern.ReplaceAllString(s, "@"+zeroWidthSpace+"$1")
+}
+
+// codeSpan wraps s in inline code, with a fence longer than any run of
+// backticks in s so that s cannot end the span early.
+func codeSpan(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if len(fence) == 1 {
+		return fence + s + fence
+	}
+	return fence + " " + s + " " + fence
+}
+
+// escapeValue escapes the value of the field named key, unless escaping is
+// off or the field may use raw Markdown.
+func (b *Builder) escapeValue(key, value string) string {
+	if !b.escape || slices.Contains(b.rawMarkdown, key) {
+		return value
+	}
+	return escapeMarkdown(value)
+}
+
+// labelCode renders the value of the grouped label key as inline code.
+func (b *Builder) labelCode(key, value string) string {
+	if !b.escape || slices.Contains(b.rawMarkdown, key) {
+		return "`" + value + "`"
+	}
+	return codeSpan(strings.ReplaceAll(value, "\n", " "))
+}
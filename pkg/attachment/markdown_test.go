@@ -0,0 +1,111 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text", input: "api-server-1", want: "api-server-1"},
+		{name: "emphasis and links", input: "**prod** [x](http://e)", want: `\*\*prod\*\* \[x\]\(http://e\)`},
+		{name: "table pipes", input: "a|b", want: `a\|b`},
+		{name: "heading and quote", input: "# big > quote", want: `\# big \> quote`},
+		{name: "inline code", input: "`rm`", want: "\\`rm\\`"},
+		{name: "mentions", input: "@channel and @sre-oncall", want: "@\u200bchannel and @\u200bsre-oncall"},
+		{name: "lone at sign", input: "a @ b", want: "a @ b"},
+		{name: "emoji shortcode", input: ":fire: now", want: ":\u200bfire: now"},
+		{name: "shortcode with underscore", input: ":thumbs_up:", want: ":\u200bthumbs\\_up:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeMarkdown(tt.input))
+		})
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	assert.Equal(t, "`prod`", codeSpan("prod"))
+	assert.Equal(t, "`` a`b ``", codeSpan("a`b"))
+	assert.Equal(t, "``` a``b ```", codeSpan("a``b"))
+}
+
+func TestBuilderEscapesFieldValues(t *testing.T) {
+	labels := map[string]string{"team": "@sre *core*", "dashboard": "[grafana](http://g)"}
+	style := &testStyle{displayed: []string{"team", "dashboard"}}
+	a := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-1"),
+		"Disk full",
+		alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusFiring),
+		"**Disk** is full, ping @here",
+		"prometheus",
+		labels,
+		time.Time{},
+	).WithEnrichments(map[string]string{"notes": ":fire: see #123"})
+
+	builder, err := New(style,
+		WithEscaping([]string{"dashboard"}),
+		WithEnrichmentFields([]EnrichmentField{{Key: "notes", Title: "Notes"}}),
+	)
+	require.NoError(t, err)
+	values := fieldValues(builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui"))
+
+	assert.Equal(t, "\\*\\*Disk\\*\\* is full, ping @\u200bhere", values["Description"])
+	assert.Equal(t, "@\u200bsre \\*core\\*", values["team"])
+	assert.Equal(t, "[grafana](http://g)", values["dashboard"], "raw markdown fields are left as they are")
+	assert.Equal(t, ":\u200bfire: see \\#123", values["Notes"])
+
+	plain, err := New(style)
+	require.NoError(t, err)
+	values = fieldValues(plain.BuildFiringAttachment(a, "http://callback", "http://keep.ui"))
+	assert.Equal(t, "@sre *core*", values["team"], "values are shown as they are without escaping")
+}
+
+func TestBuilderEscapesGroupedLabels(t *testing.T) {
+	style := &groupingStyle{testStyle: testStyle{}}
+	a := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-1"),
+		"Disk full",
+		alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"prometheus",
+		map[string]string{"cmd": "echo `id`"},
+		time.Time{},
+	)
+
+	builder, err := New(style, WithEscaping(nil))
+	require.NoError(t, err)
+	values := fieldValues(builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui"))
+	assert.Equal(t, " cmd: `` echo `id` ``", values["Labels"])
+
+	plain, err := New(style)
+	require.NoError(t, err)
+	values = fieldValues(plain.BuildFiringAttachment(a, "http://callback", "http://keep.ui"))
+	assert.Equal(t, " cmd: `echo `id``", values["Labels"])
+}
+
+// groupingStyle lists every label that is not displayed under Labels.
+type groupingStyle struct {
+	testStyle
+}
+
+func (s *groupingStyle) IsLabelGroupingEnabled() bool { return true }
+
+func fieldValues(a Attachment) map[string]string {
+	values := make(map[string]string, len(a.Fields))
+	for _, f := range a.Fields {
+		values[f.Title] = f.Value
+	}
+	return values
+}
@@ -0,0 +1,17 @@
+package attachment
+
+import (
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// BuildMentionMessage returns the post text of a new firing alert posted in
+// channelID: the mentions picked by the policy set with WithMentions,
+// separated by spaces. It returns "" when nobody is to be mentioned.
+func (b *Builder) BuildMentionMessage(a *alert.Alert, channelID string) string {
+	if b.mentions == nil {
+		return ""
+	}
+	return strings.Join(b.mentions.Mentions(a.Severity().String(), channelID, b.clock.Now()), " ")
+}
@@ -0,0 +1,42 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type mentionPolicyFunc func(severity, channelID string, now time.Time) []string
+
+func (f mentionPolicyFunc) Mentions(severity, channelID string, now time.Time) []string {
+	return f(severity, channelID, now)
+}
+
+func TestBuildMentionMessage(t *testing.T) {
+	now := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	policy := mentionPolicyFunc(func(severity, channelID string, at time.Time) []string {
+		assert.Equal(t, "critical", severity)
+		assert.Equal(t, now, at)
+		if channelID == "ch-ops" {
+			return []string{"@here", "@sre-oncall"}
+		}
+		return nil
+	})
+	builder, err := New(&testStyle{}, WithMentions(policy), WithClock(clock.NewFake(now)))
+	require.NoError(t, err)
+
+	a := newTestAlert("fp-1", time.Time{})
+	assert.Equal(t, "@here @sre-oncall", builder.BuildMentionMessage(a, "ch-ops"))
+	assert.Empty(t, builder.BuildMentionMessage(a, "ch-dev"))
+
+	card := builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui")
+	assert.Empty(t, card.Message, "mentions are only added to new posts")
+
+	plain, err := New(&testStyle{})
+	require.NoError(t, err)
+	assert.Empty(t, plain.BuildMentionMessage(a, "ch-ops"))
+}
@@ -0,0 +1,21 @@
+package attachment
+
+import (
+	"fmt"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// occurrencesField returns the Occurrences field of an alert that fired more
+// than once since its post was created, e.g. "Fired 7 times, first seen 3h
+// ago".
+func (b *Builder) occurrencesField(a *alert.Alert) (Field, bool) {
+	if a.FireCount() < 2 {
+		return Field{}, false
+	}
+	value := fmt.Sprintf("Fired %d times", a.FireCount())
+	if seen := formatDuration(a.FiringStartTime(), b.clock.Now()); seen != "" {
+		value += fmt.Sprintf(", first seen %s ago", seen)
+	}
+	return Field{Title: "Occurrences", Value: value, Short: true}, true
+}
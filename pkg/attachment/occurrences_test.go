@@ -0,0 +1,40 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+func occurrencesFieldOf(card Attachment) (Field, bool) {
+	for _, f := range card.Fields {
+		if f.Title == "Occurrences" {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+func TestOccurrences(t *testing.T) {
+	firingStart := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	builder, err := New(&testStyle{}, WithClock(clock.NewFake(firingStart.Add(3*time.Hour))))
+	require.NoError(t, err)
+
+	_, ok := occurrencesFieldOf(builder.BuildFiringAttachment(newTestAlert("fp-1", firingStart), "http://callback", "http://keep.ui"))
+	assert.False(t, ok, "no field without a fire count")
+	_, ok = occurrencesFieldOf(builder.BuildFiringAttachment(newTestAlert("fp-1", firingStart).WithFireCount(1), "http://callback", "http://keep.ui"))
+	assert.False(t, ok, "no field for the first firing")
+
+	field, ok := occurrencesFieldOf(builder.BuildAcknowledgedAttachment(newTestAlert("fp-1", firingStart).WithFireCount(7), "http://callback", "http://keep.ui", "alice"))
+	require.True(t, ok)
+	assert.Equal(t, "Fired 7 times, first seen 3h 0m ago", field.Value)
+	assert.True(t, field.Short)
+
+	field, ok = occurrencesFieldOf(builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}).WithFireCount(2), "http://callback", "http://keep.ui"))
+	require.True(t, ok)
+	assert.Equal(t, "Fired 2 times", field.Value, "no start time, no age")
+}
@@ -0,0 +1,179 @@
+package attachment
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// Option configures a Builder created by New.
+type Option func(*Builder) error
+
+// WithClock sets the clock used to render firing durations, e.g. to render a
+// historical alert as it looked at a given moment. Defaults to the real clock.
+func WithClock(c clock.Clock) Option {
+	return func(b *Builder) error {
+		b.clock = c
+		return nil
+	}
+}
+
+// WithSnoozeDuration adds a Snooze button for d to firing attachments. Zero,
+// the default, leaves the button out.
+func WithSnoozeDuration(d time.Duration) Option {
+	return func(b *Builder) error {
+		b.snoozeDuration = d
+		return nil
+	}
+}
+
+// WithTimedAck adds an Acknowledge for menu offering the given durations to
+// firing attachments. No durations, the default, leave the menu out.
+func WithTimedAck(durations []time.Duration) Option {
+	return func(b *Builder) error {
+		b.ackDurations = slices.Clone(durations)
+		return nil
+	}
+}
+
+// WithCopyCommands adds a Commands button to firing, acknowledged and
+// snoozed attachments. Each command is a text/template rendered from the
+// alert with .Fingerprint, .Name, .Severity, .Status, .Labels, .KeepURL and
+// .KeepUIURL; the quote function shell-quotes a value when needed. keepURL
+// is the Keep API base URL exposed as .KeepURL. New fails if a template
+// does not parse.
+func WithCopyCommands(commands []CopyCommand, keepURL string) Option {
+	return func(b *Builder) error {
+		compiled, err := compileCopyCommands(commands)
+		if err != nil {
+			return err
+		}
+		b.copyCommands = compiled
+		b.keepURL = strings.TrimRight(keepURL, "/")
+		return nil
+	}
+}
+
+// WithAssignMenu adds an Assign menu to firing attachments. The menu offers
+// "Assign to me" and the given Mattermost usernames; with no usernames it
+// lists every Mattermost user instead.
+func WithAssignMenu(usernames []string) Option {
+	return func(b *Builder) error {
+		b.assignEnabled = true
+		b.assignUsers = slices.Clone(usernames)
+		return nil
+	}
+}
+
+// WithMentions makes BuildMentionMessage mention whom policy picks. Without
+// it BuildMentionMessage returns "".
+func WithMentions(policy MentionPolicy) Option {
+	return func(b *Builder) error {
+		b.mentions = policy
+		return nil
+	}
+}
+
+// WithLinks adds a Links field with the links policy picks to alert
+// attachments.
+func WithLinks(policy LinkPolicy) Option {
+	return func(b *Builder) error {
+		b.links = policy
+		return nil
+	}
+}
+
+// WithSourceLinks adds a Source field to alert attachments, linking the
+// alert's source to its generator URL. Sources match case-insensitively.
+func WithSourceLinks(links SourceLinks) Option {
+	return func(b *Builder) error {
+		sources := make([]string, len(links.Sources))
+		for i, source := range links.Sources {
+			sources[i] = strings.ToLower(source)
+		}
+		b.sourceLinks = &SourceLinks{Sources: sources, Annotations: slices.Clone(links.Annotations)}
+		return nil
+	}
+}
+
+// WithCustomActions adds the custom action buttons policy picks to firing
+// attachments, after the built-in buttons.
+func WithCustomActions(policy CustomActionPolicy) Option {
+	return func(b *Builder) error {
+		b.customActions = policy
+		return nil
+	}
+}
+
+// WithWorkflowMenu adds a Run workflow menu listing the workflows of menu to
+// firing attachments. The menu is left out while menu lists none.
+func WithWorkflowMenu(menu WorkflowMenu) Option {
+	return func(b *Builder) error {
+		b.workflowMenu = menu
+		return nil
+	}
+}
+
+// WithEnrichmentFields adds a field per entry of fields, in order, to alert
+// attachments whose alert carries a non-empty value for its enrichment key.
+func WithEnrichmentFields(fields []EnrichmentField) Option {
+	return func(b *Builder) error {
+		b.enrichments = fields
+		return nil
+	}
+}
+
+// WithAnnotations adds a full-width field per annotation policy picks to
+// alert attachments, after the label fields.
+func WithAnnotations(policy AnnotationPolicy) Option {
+	return func(b *Builder) error {
+		b.annotations = policy
+		return nil
+	}
+}
+
+// WithEscaping escapes Markdown, @mentions and emoji shortcodes in the
+// description, label, annotation and enrichment values of alert attachments,
+// so they render as plain text and notify nobody. Grouped label values stay
+// inline code. The fields listed in raw, by label, annotation or enrichment
+// key or DescriptionField, are shown as Markdown.
+func WithEscaping(raw []string) Option {
+	return func(b *Builder) error {
+		b.escape = true
+		b.rawMarkdown = slices.Clone(raw)
+		return nil
+	}
+}
+
+// WithCompact renders every alert attachment in the compact style: the
+// title line with its emoji, name and duration, the footer and a single
+// button, Acknowledge while firing and Resolve after, but no fields.
+func WithCompact() Option {
+	return func(b *Builder) error {
+		b.compact = true
+		return nil
+	}
+}
+
+// WithCompactSeverities renders the alert attachments of the given severities
+// in the compact style of WithCompact, e.g. to keep info alerts to one line.
+func WithCompactSeverities(severities []string) Option {
+	return func(b *Builder) error {
+		b.compactSeverities = make([]string, len(severities))
+		for i, severity := range severities {
+			b.compactSeverities[i] = strings.ToLower(severity)
+		}
+		return nil
+	}
+}
+
+// WithBotIdentity shows the posts of alert attachments under the bot name and
+// avatar policy picks for their severity.
+func WithBotIdentity(policy BotIdentityPolicy) Option {
+	return func(b *Builder) error {
+		b.identity = policy
+		return nil
+	}
+}
@@ -0,0 +1,22 @@
+package attachment
+
+import "maps"
+
+// RetryButton returns the Retry button of a post whose action failed to reach
+// Keep. actionContext is the context of the button that ran the action; the
+// Retry button carries it with the action moved under ContextKeyRetryAction,
+// so the click runs the same action again.
+func RetryButton(callbackURL string, actionContext map[string]string) Button {
+	context := maps.Clone(actionContext)
+	context[ContextKeyRetryAction] = actionContext[ContextKeyAction]
+	context[ContextKeyAction] = ActionRetry
+	return Button{
+		ID:    ActionRetry,
+		Name:  "Retry",
+		Style: ButtonStyleDanger,
+		Integration: ButtonIntegration{
+			URL:     callbackURL,
+			Context: context,
+		},
+	}
+}
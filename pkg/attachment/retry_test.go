@@ -0,0 +1,29 @@
+package attachment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryButton(t *testing.T) {
+	actionContext := map[string]string{
+		ContextKeyAction:      ActionResolve,
+		ContextKeyFingerprint: "fp-1",
+		ContextKeyAlertName:   "Test Alert",
+	}
+
+	button := RetryButton("http://callback", actionContext)
+
+	assert.Equal(t, ActionRetry, button.ID)
+	assert.Equal(t, "Retry", button.Name)
+	assert.Equal(t, ButtonStyleDanger, button.Style)
+	assert.Equal(t, "http://callback", button.Integration.URL)
+	assert.Equal(t, map[string]string{
+		ContextKeyAction:      ActionRetry,
+		ContextKeyRetryAction: ActionResolve,
+		ContextKeyFingerprint: "fp-1",
+		ContextKeyAlertName:   "Test Alert",
+	}, button.Integration.Context)
+	assert.Equal(t, ActionResolve, actionContext[ContextKeyAction], "the action context is left as it was")
+}
@@ -0,0 +1,64 @@
+package attachment
+
+import (
+	"encoding/json"
+
+	"github.com/VictoriaMetrics/metrics"
+)
+
+// Mattermost rejects posts whose props exceed about 16KB. The fields of an
+// alert are stored in the post itself and again in the attachment_json
+// context of up to four buttons, so each copy gets a fifth of that.
+const (
+	maxPropsBytes      = 16 * 1024
+	maxFieldsBytes     = maxPropsBytes / 5
+	maxFields          = 20
+	minTruncatedWidth  = 100
+	truncatedFieldText = "Some fields were shortened or left out: [View full alert in Keep](%s)"
+)
+
+// attachmentRendersTruncatedCounter counts renders that were cut. An alert's
+// card is rendered on every update, so it counts renders, not alerts.
+var attachmentRendersTruncatedCounter = metrics.NewCounter("attachment_renders_truncated_total")
+
+// fitFields keeps fields within budget bytes of JSON and maxFields entries.
+// Over budget, it shortens the longest value, down to minTruncatedWidth
+// columns, and once nothing is left to shorten drops fields from the end. It
+// reports whether anything was cut.
+func fitFields(fields []Field, budget, limit int) ([]Field, bool) {
+	truncated := false
+	if len(fields) > limit {
+		fields = fields[:max(limit, 0)]
+		truncated = true
+	}
+	for size := fieldsSize(fields); size > budget && len(fields) > 0; size = fieldsSize(fields) {
+		truncated = true
+		i := longestValue(fields)
+		width := stringWidth(fields[i].Value)
+		if width <= minTruncatedWidth {
+			fields = fields[:len(fields)-1]
+			continue
+		}
+		fields[i].Value = truncateWidth(fields[i].Value, max(width-(size-budget), minTruncatedWidth))
+	}
+	return fields, truncated
+}
+
+// fieldsSize returns the JSON size of fields in bytes.
+func fieldsSize(fields []Field) int {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+func longestValue(fields []Field) int {
+	longest := 0
+	for i, f := range fields {
+		if len(f.Value) > len(fields[longest].Value) {
+			longest = i
+		}
+	}
+	return longest
+}
@@ -0,0 +1,89 @@
+package attachment
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestFitFields(t *testing.T) {
+	short := []Field{{Title: "namespace", Value: "prod"}, {Title: "pod", Value: "api-1"}}
+	got, truncated := fitFields(short, 1000, 10)
+	assert.False(t, truncated)
+	assert.Equal(t, short, got, "fields within budget are left alone")
+
+	got, truncated = fitFields([]Field{{Title: "a", Value: "1"}, {Title: "b", Value: "2"}, {Title: "c", Value: "3"}}, 1000, 2)
+	assert.True(t, truncated)
+	assert.Equal(t, []Field{{Title: "a", Value: "1"}, {Title: "b", Value: "2"}}, got, "fields beyond the limit are dropped")
+
+	long := []Field{
+		{Title: "Description", Value: strings.Repeat("d", 2000)},
+		{Title: "namespace", Value: strings.Repeat("n", 500)},
+	}
+	got, truncated = fitFields(long, 1500, 10)
+	assert.True(t, truncated)
+	require.Len(t, got, 2, "shortening the longest value is tried before dropping fields")
+	assert.LessOrEqual(t, fieldsSize(got), 1500)
+	assert.True(t, strings.HasSuffix(got[0].Value, ellipsis))
+	assert.Equal(t, strings.Repeat("n", 500), got[1].Value)
+
+	many := make([]Field, 10)
+	for i := range many {
+		many[i] = Field{Title: fmt.Sprintf("label-%d", i), Value: strings.Repeat("v", minTruncatedWidth)}
+	}
+	got, truncated = fitFields(many, 500, 10)
+	assert.True(t, truncated)
+	assert.LessOrEqual(t, fieldsSize(got), 500)
+	assert.Equal(t, "label-0", got[0].Title, "fields are dropped from the end")
+}
+
+func TestBuilderFitsLargeAlert(t *testing.T) {
+	labels := make(map[string]string)
+	var displayed []string
+	for i := range 40 {
+		key := fmt.Sprintf("label-%02d", i)
+		labels[key] = strings.Repeat("x", 200)
+		displayed = append(displayed, key)
+	}
+	builder, err := New(&testStyle{displayed: displayed}, WithSnoozeDuration(time.Hour))
+	require.NoError(t, err)
+	a := alert.RestoreAlert(
+		alert.RestoreFingerprint("fp-large"),
+		"Disk full",
+		alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusFiring),
+		strings.Repeat("The disk is almost full. ", 200),
+		"prometheus",
+		labels,
+		time.Time{},
+	)
+	truncatedBefore := metrics.GetOrCreateCounter("attachment_renders_truncated_total").Get()
+
+	card := builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui")
+
+	assert.Equal(t, truncatedBefore+1, metrics.GetOrCreateCounter("attachment_renders_truncated_total").Get())
+	assert.LessOrEqual(t, len(card.Fields), maxFields)
+	assert.Equal(t, "Description", card.Fields[0].Title, "the description is shortened, not dropped")
+	assert.Less(t, len(card.Fields[0].Value), len(a.Description()))
+	last := card.Fields[len(card.Fields)-1]
+	assert.Contains(t, last.Value, "[View full alert in Keep](http://keep.ui/alerts/feed?fingerprint=fp-large)")
+	cardJSON, err := card.ToJSON()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(cardJSON), maxPropsBytes, "the post with its button contexts fits Mattermost's props limit")
+
+	small := builder.BuildFiringAttachment(newTestAlert("fp-small", time.Time{}), "http://callback", "http://keep.ui")
+	for _, f := range small.Fields {
+		assert.NotContains(t, f.Value, "View full alert in Keep")
+	}
+	assert.Equal(t, truncatedBefore+1, metrics.GetOrCreateCounter("attachment_renders_truncated_total").Get(), "alerts that fit are not counted")
+
+	builder.BuildAcknowledgedAttachment(a, "http://callback", "http://keep.ui", "alice")
+	assert.Equal(t, truncatedBefore+2, metrics.GetOrCreateCounter("attachment_renders_truncated_total").Get(), "every render is counted")
+}
@@ -0,0 +1,60 @@
+package attachment
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// SourceLinks configures the Source field of alert attachments.
+type SourceLinks struct {
+	// Sources are the alert sources, e.g. "prometheus" or "grafana", whose
+	// alerts get the field; empty means every source.
+	Sources []string
+	// Annotations are checked in order for the link when the alert carries
+	// no generator URL.
+	Annotations []string
+}
+
+// sourceField returns the Source field of a: its source linked to the page
+// of the alert in the system that generated it, or the plain source when no
+// link is known. It returns false when a's source is not configured.
+func (b *Builder) sourceField(a *alert.Alert) (Field, bool) {
+	if b.sourceLinks == nil || a.Source() == "" {
+		return Field{}, false
+	}
+	if len(b.sourceLinks.Sources) > 0 && !slices.ContainsFunc(strings.Split(a.Source(), ","), func(source string) bool {
+		return slices.Contains(b.sourceLinks.Sources, strings.ToLower(strings.TrimSpace(source)))
+	}) {
+		return Field{}, false
+	}
+
+	source := b.escapeValue("source", truncateWidth(a.Source(), maxFieldValueWidth))
+	link := b.sourceLink(a)
+	if link == "" {
+		return Field{Title: "Source", Value: source, Short: true}, true
+	}
+	return Field{Title: "Source", Value: "[" + source + "](" + link + ")", Short: true}, true
+}
+
+// sourceLink returns the generator URL of a, or the first configured
+// annotation holding one. Only absolute http and https URLs are linked.
+func (b *Builder) sourceLink(a *alert.Alert) string {
+	candidates := []string{a.GeneratorURL()}
+	annotations := a.Annotations()
+	for _, key := range b.sourceLinks.Annotations {
+		candidates = append(candidates, annotations[key])
+	}
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		u, err := url.Parse(candidate)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+		// Parentheses and spaces would end the Markdown link early.
+		return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(candidate)
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sourceFieldOf(card Attachment) (Field, bool) {
+	for _, f := range card.Fields {
+		if f.Title == "Source" {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+func TestSourceLinks(t *testing.T) {
+	plain, err := New(&testStyle{})
+	require.NoError(t, err)
+	_, ok := sourceFieldOf(plain.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui"))
+	assert.False(t, ok, "no Source field without the option")
+
+	builder, err := New(&testStyle{}, WithSourceLinks(SourceLinks{
+		Sources:     []string{"Prometheus", "grafana"},
+		Annotations: []string{"generator_url"},
+	}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		generator string
+		annotated string
+		want      string
+	}{
+		{name: "generator URL", generator: "http://prom:9090/graph?g0.expr=up", want: "[prometheus](http://prom:9090/graph?g0.expr=up)"},
+		{name: "annotation fallback", annotated: "https://grafana/alerting/grafana/abc/view", want: "[prometheus](https://grafana/alerting/grafana/abc/view)"},
+		{name: "parentheses escaped", generator: "http://prom/graph?g0.expr=rate(x[5m])", want: "[prometheus](http://prom/graph?g0.expr=rate%28x[5m]%29)"},
+		{name: "unsafe scheme left out", generator: "javascript:alert(1)", want: "prometheus"},
+		{name: "no link", want: "prometheus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAlert("fp-1", time.Time{}).WithGeneratorURL(tt.generator)
+			if tt.annotated != "" {
+				a = a.WithAnnotations(map[string]string{"generator_url": tt.annotated})
+			}
+			field, ok := sourceFieldOf(builder.BuildFiringAttachment(a, "http://callback", "http://keep.ui"))
+			require.True(t, ok)
+			assert.Equal(t, tt.want, field.Value)
+			assert.True(t, field.Short)
+		})
+	}
+
+	other, err := New(&testStyle{}, WithSourceLinks(SourceLinks{Sources: []string{"grafana"}}))
+	require.NoError(t, err)
+	_, ok = sourceFieldOf(other.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}).WithGeneratorURL("http://prom"), "http://callback", "http://keep.ui"))
+	assert.False(t, ok, "sources not listed get no field")
+}
@@ -0,0 +1,84 @@
+package attachment
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// maxStatusBoardLines caps the alerts listed on a status board.
+const maxStatusBoardLines = 50
+
+// BuildStatusBoardAttachment renders the status board of a channel: its
+// active alerts grouped by severity, most severe first, and oldest first
+// within a severity. It takes the color of the highest severity. The text
+// only changes with the alerts, so an unchanged board renders the same.
+func (b *Builder) BuildStatusBoardAttachment(alerts []BoardAlert, keepUIURL string) Attachment {
+	attachment := Attachment{
+		TitleLink:  keepUIURL + "/alerts/feed",
+		Footer:     b.style.FooterText(),
+		FooterIcon: b.style.FooterIconURL(),
+	}
+
+	if len(alerts) == 0 {
+		attachment.Color = b.style.ColorForSeverity("resolved")
+		attachment.Title = "✅ Status board · no active alerts"
+		attachment.Text = "All clear."
+		return attachment
+	}
+
+	sorted := slices.Clone(alerts)
+	slices.SortStableFunc(sorted, func(x, y BoardAlert) int {
+		if c := cmp.Compare(alert.RestoreSeverity(y.Severity).Rank(), alert.RestoreSeverity(x.Severity).Rank()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(x.Severity, y.Severity); c != 0 {
+			return c
+		}
+		return x.FiringSince.Compare(y.FiringSince)
+	})
+	counts := make(map[string]int)
+	for _, a := range sorted {
+		counts[a.Severity]++
+	}
+
+	lines := make([]string, 0, min(len(sorted), maxStatusBoardLines)+2*len(counts)+1)
+	for i, a := range sorted {
+		if i >= maxStatusBoardLines {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(sorted)-maxStatusBoardLines))
+			break
+		}
+		if i == 0 || a.Severity != sorted[i-1].Severity {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, fmt.Sprintf("**%s %s** (%d)", b.style.EmojiForSeverity(a.Severity), strings.ToUpper(a.Severity), counts[a.Severity]))
+		}
+		line := fmt.Sprintf("[%s](%s/alerts/feed?fingerprint=%s) · since %s",
+			truncateWidth(a.Name, maxAlertNameWidth),
+			keepUIURL,
+			url.QueryEscape(a.Fingerprint),
+			a.FiringSince.UTC().Format("2006-01-02 15:04 UTC"),
+		)
+		switch {
+		case a.Snoozed:
+			line += " · 💤 snoozed"
+		case a.Status == alert.StatusAcknowledged:
+			line += " · 👀 acknowledged"
+		}
+		lines = append(lines, "- "+line)
+	}
+
+	noun := "alerts"
+	if len(sorted) == 1 {
+		noun = "alert"
+	}
+	attachment.Color = b.style.ColorForSeverity(sorted[0].Severity)
+	attachment.Title = fmt.Sprintf("📋 Status board · %d active %s", len(sorted), noun)
+	attachment.Text = strings.Join(lines, "\n")
+	return attachment
+}
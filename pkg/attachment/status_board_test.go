@@ -0,0 +1,52 @@
+package attachment
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatusBoardAttachment(t *testing.T) {
+	builder, err := New(&testStyle{
+		colors: map[string]string{"critical": "#CC0000", "warning": "#EDA200", "resolved": "#00CC00"},
+		emoji:  map[string]string{"critical": "🔴", "warning": "⚠️"},
+		footer: "Keep AIOps",
+	})
+	require.NoError(t, err)
+	since := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	attachment := builder.BuildStatusBoardAttachment([]BoardAlert{
+		{Fingerprint: "fp-1", Name: "Disk full", Severity: "warning", Status: "firing", FiringSince: since},
+		{Fingerprint: "fp 2", Name: "Node down", Severity: "critical", Status: "acknowledged", FiringSince: since.Add(time.Minute)},
+		{Fingerprint: "fp-3", Name: "API errors", Severity: "critical", Status: "firing", FiringSince: since},
+		{Fingerprint: "fp-4", Name: "Queue lag", Severity: "warning", Status: "firing", Snoozed: true, FiringSince: since.Add(-time.Hour)},
+	}, "http://keep.ui")
+	assert.Equal(t, "#CC0000", attachment.Color, "colored by the most severe alert")
+	assert.Equal(t, "📋 Status board · 4 active alerts", attachment.Title)
+	assert.Equal(t, "http://keep.ui/alerts/feed", attachment.TitleLink)
+	assert.Equal(t, "**🔴 CRITICAL** (2)\n"+
+		"- [API errors](http://keep.ui/alerts/feed?fingerprint=fp-3) · since 2026-01-01 12:30 UTC\n"+
+		"- [Node down](http://keep.ui/alerts/feed?fingerprint=fp+2) · since 2026-01-01 12:31 UTC · 👀 acknowledged\n"+
+		"\n"+
+		"**⚠️ WARNING** (2)\n"+
+		"- [Queue lag](http://keep.ui/alerts/feed?fingerprint=fp-4) · since 2026-01-01 11:30 UTC · 💤 snoozed\n"+
+		"- [Disk full](http://keep.ui/alerts/feed?fingerprint=fp-1) · since 2026-01-01 12:30 UTC", attachment.Text)
+	assert.Empty(t, attachment.Actions)
+	assert.Equal(t, "Keep AIOps", attachment.Footer)
+
+	alerts := make([]BoardAlert, maxStatusBoardLines+3)
+	for i := range alerts {
+		alerts[i] = BoardAlert{Fingerprint: "fp", Name: "Alert", Severity: "warning", FiringSince: since}
+	}
+	attachment = builder.BuildStatusBoardAttachment(alerts, "http://keep.ui")
+	assert.True(t, strings.HasSuffix(attachment.Text, "\n…and 3 more"))
+	assert.Contains(t, attachment.Text, "(53)", "the count covers the alerts not listed")
+
+	attachment = builder.BuildStatusBoardAttachment(nil, "http://keep.ui")
+	assert.Equal(t, "#00CC00", attachment.Color)
+	assert.Equal(t, "✅ Status board · no active alerts", attachment.Title)
+	assert.Equal(t, "All clear.", attachment.Text)
+}
@@ -0,0 +1,203 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// Where the Severity field is placed among the alert fields.
+const (
+	SeverityPositionFirst        = "first"
+	SeverityPositionAfterDisplay = "after_display"
+	SeverityPositionLast         = "last"
+)
+
+// Style decides how alerts look: colors and emoji per severity or status,
+// which labels are shown and how, and the footer. The bridge's file config
+// implements it.
+type Style interface {
+	ColorForSeverity(severity string) string
+	EmojiForSeverity(severity string) string
+	IsLabelExcluded(label string) bool
+	IsLabelDisplayed(label string) bool
+	RenameLabel(label string) string
+	FooterText() string
+	FooterIconURL() string
+	IsLabelGroupingEnabled() bool
+	GetLabelGroupingThreshold() int
+	GetLabelGroups() []LabelGroup
+	ShowSeverityField() bool
+	ShowDescriptionField() bool
+	ShowFingerprintField() bool
+	SeverityFieldPosition() string
+}
+
+// MentionPolicy picks who is mentioned in the post text of a new firing
+// alert, e.g. "@here" or "@sre-oncall". The bridge's file config implements
+// it.
+type MentionPolicy interface {
+	Mentions(severity, channelID string, now time.Time) []string
+}
+
+// LinkPolicy picks the links shown with an alert, e.g. its runbook and
+// dashboard. The bridge's file config implements it.
+type LinkPolicy interface {
+	Links(a *alert.Alert) []Link
+}
+
+// AnnotationPolicy picks the annotations shown with an alert, apart from its
+// labels, and the titles they are shown under. The bridge's file config
+// implements it.
+type AnnotationPolicy interface {
+	ShownAnnotations(annotations map[string]string) []string
+	AnnotationTitle(key string) string
+}
+
+// BotIdentityPolicy picks the bot name and avatar alert posts of a severity
+// are shown with, e.g. a red avatar for critical alerts. Empty values keep
+// the bot's own. The bridge's file config implements it.
+type BotIdentityPolicy interface {
+	BotIdentity(severity string) (username, iconURL string)
+}
+
+// CustomActionPolicy picks the custom action buttons shown on a firing
+// alert. The bridge's file config implements it.
+type CustomActionPolicy interface {
+	CustomActions(a *alert.Alert) []CustomAction
+}
+
+// CustomAction is a configured button, e.g. "Restart pod". Name identifies
+// the action in the button context; Style is a Mattermost button style.
+type CustomAction struct {
+	Name  string
+	Style string
+}
+
+// WorkflowMenu lists the Keep workflows of the Run workflow menu on firing
+// alerts.
+type WorkflowMenu interface {
+	Workflows() []Workflow
+}
+
+// Workflow is a Keep workflow offered in the Run workflow menu.
+type Workflow struct {
+	ID   string
+	Name string
+}
+
+// EnrichmentField renders the Keep enrichment Key of an alert, e.g.
+// ai_summary, as an attachment field titled Title.
+type EnrichmentField struct {
+	Key   string
+	Title string
+	Short bool
+}
+
+// Link is a titled URL.
+type Link struct {
+	Title string
+	URL   string
+}
+
+// LabelGroup collects labels starting with any of Prefixes into one field
+// titled GroupName. Groups with a higher Priority are listed first.
+type LabelGroup struct {
+	Prefixes  []string
+	GroupName string
+	Priority  int
+}
+
+// CopyCommand is a named text/template rendered from alert data, offered by
+// the Commands button as a ready-to-copy command line.
+type CopyCommand struct {
+	Name     string
+	Template string
+}
+
+// HeldAlert is an alert a notification budget has not posted yet.
+type HeldAlert struct {
+	Fingerprint string
+	Name        string
+	Severity    string
+	HeldAt      time.Time
+}
+
+// DigestAlert is an alert held during quiet hours, as listed in the digest
+// posted when they end.
+type DigestAlert struct {
+	Fingerprint string
+	Name        string
+	Severity    string
+	HeldAt      time.Time
+	ResolvedAt  time.Time // zero when still firing
+}
+
+// BoardAlert is an active alert as listed on a channel's status board.
+type BoardAlert struct {
+	Fingerprint string
+	Name        string
+	Severity    string
+	Status      string // the status its post shows
+	Snoozed     bool
+	FiringSince time.Time
+}
+
+// AlertDigest is the alert activity of one digest period.
+type AlertDigest struct {
+	From, To     time.Time
+	Fired        int            // alerts that started firing
+	BySeverity   map[string]int // severity -> alerts that started firing
+	Noisy        []NoisyAlert   // most often fired alerts, noisiest first
+	Acknowledged int            // alerts acknowledged after firing
+	MTTA         time.Duration  // mean time from firing to acknowledge
+	Resolved     int            // alerts resolved after firing
+	MTTR         time.Duration  // mean time from firing to resolve
+	// ResponseBySeverity breaks Acknowledged, MTTA, Resolved and MTTR down
+	// by the severity the alerts fired with.
+	ResponseBySeverity map[string]ResponseTimes
+}
+
+// ResponseTimes is how fast alerts of one severity were acknowledged and
+// resolved within a digest period.
+type ResponseTimes struct {
+	Acknowledged int
+	MTTA         time.Duration
+	Resolved     int
+	MTTR         time.Duration
+}
+
+// NoisyAlert is an alert that fired often within a digest period.
+type NoisyAlert struct {
+	Fingerprint string
+	Name        string
+	Fires       int
+}
+
+// SLOResult is how one response time objective fared over a report period.
+type SLOResult struct {
+	Name      string  // e.g. "webhook"
+	Target    float64 // fraction of requests that must be good, e.g. 0.99
+	Threshold time.Duration
+	Total     uint64 // requests counted
+	Good      uint64 // requests answered within Threshold
+}
+
+// Ratio returns the fraction of good requests, or 1 when there were none.
+func (r SLOResult) Ratio() float64 {
+	if r.Total == 0 {
+		return 1
+	}
+	return float64(r.Good) / float64(r.Total)
+}
+
+// BudgetUsed returns the share of the error budget spent: 1 means exactly
+// 1-Target of the requests were bad, more means the objective was missed.
+func (r SLOResult) BudgetUsed() float64 {
+	return (1 - r.Ratio()) / (1 - r.Target)
+}
+
+// Met reports whether the objective held over the period.
+func (r SLOResult) Met() bool {
+	return r.Ratio() >= r.Target
+}
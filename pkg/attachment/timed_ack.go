@@ -0,0 +1,34 @@
+package attachment
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// timedAckMenu returns the Acknowledge for menu, whose options carry the
+// duration in Go syntax, e.g. "2h", or false when no durations are set.
+func (b *Builder) timedAckMenu(a *alert.Alert, callbackURL, attachmentJSON string) (Button, bool) {
+	if len(b.ackDurations) == 0 {
+		return Button{}, false
+	}
+
+	options := make([]SelectOption, 0, len(b.ackDurations))
+	for _, d := range b.ackDurations {
+		options = append(options, SelectOption{Text: formatSnoozeDuration(d), Value: formatSnoozeDuration(d)})
+	}
+	return Button{
+		ID:      ActionAcknowledgeFor,
+		Name:    "Acknowledge for…",
+		Type:    ButtonTypeSelect,
+		Options: options,
+		Integration: ButtonIntegration{
+			URL: callbackURL,
+			Context: map[string]string{
+				ContextKeyAction:         ActionAcknowledgeFor,
+				ContextKeyFingerprint:    a.Fingerprint().Value(),
+				ContextKeyAlertName:      a.Name(),
+				ContextKeySeverity:       a.Severity().String(),
+				ContextKeyAttachmentJSON: attachmentJSON,
+			},
+		},
+	}, true
+}
@@ -0,0 +1,36 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimedAckMenu(t *testing.T) {
+	builder, err := New(&testStyle{})
+	require.NoError(t, err)
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	for _, button := range card.Actions {
+		assert.NotEqual(t, ActionAcknowledgeFor, button.ID, "the menu is left out without durations")
+	}
+
+	builder, err = New(&testStyle{}, WithTimedAck([]time.Duration{30 * time.Minute, 2 * time.Hour, 90 * time.Minute}))
+	require.NoError(t, err)
+	card = builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	require.Len(t, card.Actions, 3)
+	menu := card.Actions[2]
+	assert.Equal(t, ActionAcknowledgeFor, menu.ID)
+	assert.Equal(t, "Acknowledge for…", menu.Name)
+	assert.Equal(t, ButtonTypeSelect, menu.Type)
+	assert.Equal(t, []SelectOption{{Text: "30m", Value: "30m"}, {Text: "2h", Value: "2h"}, {Text: "1h30m", Value: "1h30m"}}, menu.Options)
+	assert.Equal(t, ActionAcknowledgeFor, menu.Integration.Context[ContextKeyAction])
+	assert.Equal(t, "fp-1", menu.Integration.Context[ContextKeyFingerprint])
+	assert.NotEmpty(t, menu.Integration.Context[ContextKeyAttachmentJSON], "the processing state is rendered from the attachment")
+
+	acked := builder.BuildAcknowledgedAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui", "alice")
+	for _, button := range acked.Actions {
+		assert.NotEqual(t, ActionAcknowledgeFor, button.ID, "the menu is only offered on firing alerts")
+	}
+}
@@ -0,0 +1,123 @@
+package attachment
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const ellipsis = "…"
+
+// Display-width budgets for attachment parts. Wide runes (CJK, emoji) count
+// as two columns, combining marks and joiners as zero.
+const (
+	maxAlertNameWidth   = 120
+	maxFieldTitleWidth  = 64
+	maxFieldValueWidth  = 1000
+	maxDescriptionWidth = 3000
+	maxFooterWidth      = 200
+)
+
+// truncateWidth shortens s to at most maxWidth display columns, appending an
+// ellipsis when anything was cut. It never splits a rune or a grapheme cluster
+// (emoji ZWJ sequences, skin-tone modifiers, flags, combining marks).
+func truncateWidth(s string, maxWidth int) string {
+	if maxWidth <= 0 {
+		return ""
+	}
+	if stringWidth(s) <= maxWidth {
+		return s
+	}
+
+	budget := maxWidth - 1 // reserve a column for the ellipsis
+	width := 0
+	cut := 0
+	for _, c := range clusters(s) {
+		w := clusterWidth(c)
+		if width+w > budget {
+			break
+		}
+		width += w
+		cut += len(c)
+	}
+
+	return strings.TrimRightFunc(s[:cut], unicode.IsSpace) + ellipsis
+}
+
+// stringWidth returns the display width of s in terminal-style columns.
+func stringWidth(s string) int {
+	width := 0
+	for _, c := range clusters(s) {
+		width += clusterWidth(c)
+	}
+	return width
+}
+
+// clusters splits s into approximate grapheme clusters: a base rune followed
+// by any zero-width runes, with ZWJ-joined runes and regional-indicator pairs
+// kept together.
+func clusters(s string) []string {
+	var result []string
+	start := 0
+	for start < len(s) {
+		first, size := utf8.DecodeRuneInString(s[start:])
+		end := start + size
+		prev := first
+		runes := 1
+		for end < len(s) {
+			next, nextSize := utf8.DecodeRuneInString(s[end:])
+			joined := prev == zeroWidthJoiner ||
+				runeWidth(next) == 0 ||
+				(runes == 1 && isRegionalIndicator(first) && isRegionalIndicator(next))
+			if !joined {
+				break
+			}
+			end += nextSize
+			prev = next
+			runes++
+		}
+		result = append(result, s[start:end])
+		start = end
+	}
+	return result
+}
+
+func clusterWidth(cluster string) int {
+	width := 0
+	for _, r := range cluster {
+		if w := runeWidth(r); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+const zeroWidthJoiner = '\u200D'
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == zeroWidthJoiner,
+		r >= 0xFE00 && r <= 0xFE0F,   // variation selectors
+		r >= 0x1F3FB && r <= 0x1F3FF, // skin-tone modifiers
+		r >= 0xE0020 && r <= 0xE007F, // tag characters
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2600 && r <= 0x27BF, // misc symbols and dingbats
+		r >= 0x2E80 && r <= 0xA4CF, // CJK radicals through Yi
+		r >= 0xAC00 && r <= 0xD7A3, // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF, // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F000 && r <= 0x1FAFF, // emoji and pictographs
+		r >= 0x20000 && r <= 0x3FFFD: // CJK extensions
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,96 @@
+package attachment
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "ascii", input: "hello", want: 5},
+		{name: "empty", input: "", want: 0},
+		{name: "cjk", input: "数据库", want: 6},
+		{name: "hangul", input: "경고", want: 4},
+		{name: "emoji", input: "🔥", want: 2},
+		{name: "emoji with variation selector", input: "⚠️", want: 2},
+		{name: "zwj family is one cluster", input: "👨‍👩‍👧", want: 2},
+		{name: "skin tone modifier", input: "👍🏽", want: 2},
+		{name: "flag pair", input: "🇩🇪", want: 2},
+		{name: "combining accent", input: "é", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stringWidth(tt.input))
+		})
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth int
+		want     string
+	}{
+		{name: "fits unchanged", input: "short", maxWidth: 10, want: "short"},
+		{name: "exact fit unchanged", input: "12345", maxWidth: 5, want: "12345"},
+		{name: "ascii cut", input: "abcdefghij", maxWidth: 5, want: "abcd…"},
+		{name: "trailing space trimmed before ellipsis", input: "abc defgh", maxWidth: 5, want: "abc…"},
+		{name: "cjk never split mid-rune", input: "数据库连接失败", maxWidth: 6, want: "数据…"},
+		{name: "wide rune does not overflow budget", input: "数据库连接失败", maxWidth: 5, want: "数据…"},
+		{name: "zwj sequence kept whole", input: "👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧", maxWidth: 5, want: "👨‍👩‍👧👨‍👩‍👧…"},
+		{name: "flag kept whole", input: "🇩🇪🇫🇷🇬🇧", maxWidth: 4, want: "🇩🇪…"},
+		{name: "combining mark stays with base", input: "ééé", maxWidth: 2, want: "é…"},
+		{name: "zero width", input: "abc", maxWidth: 0, want: ""},
+		{name: "width one is ellipsis only", input: "abc", maxWidth: 1, want: "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateWidth(tt.input, tt.maxWidth)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, utf8.ValidString(got))
+			assert.LessOrEqual(t, stringWidth(got), max(tt.maxWidth, 0))
+		})
+	}
+}
+
+func TestBuilderTruncatesLongAlertNameAndLabels(t *testing.T) {
+	builder, err := New(&testStyle{displayed: []string{"namespace"}})
+	require.NoError(t, err)
+	longName := strings.Repeat("数据库连接失败🔥", 40)
+	a, err := alert.NewAlert(
+		alert.RestoreFingerprint("fp-long"),
+		longName,
+		alert.RestoreSeverity(alert.SeverityCritical),
+		alert.RestoreStatus(alert.StatusFiring),
+		"",
+		"test",
+		map[string]string{"namespace": strings.Repeat("ns-", 500)},
+		time.Time{},
+	)
+	require.NoError(t, err)
+
+	attachment := builder.BuildFiringAttachment(a, "http://callback", "http://keep")
+
+	assert.True(t, utf8.ValidString(attachment.Title))
+	assert.True(t, strings.HasSuffix(attachment.Title, ellipsis))
+	assert.LessOrEqual(t, stringWidth(attachment.Title), maxAlertNameWidth+3)
+	assert.Equal(t, longName, attachment.Actions[0].Integration.Context["alert_name"], "callback context keeps the full name")
+
+	for _, f := range attachment.Fields {
+		assert.LessOrEqual(t, stringWidth(f.Value), maxFieldValueWidth)
+	}
+}
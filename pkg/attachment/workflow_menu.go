@@ -0,0 +1,38 @@
+package attachment
+
+import (
+	"github.com/alexmorbo/keep-mattermost-bridge/domain/alert"
+)
+
+// runWorkflowMenu returns the Run workflow message menu for a, listing the
+// workflows by name with their Keep ID as value. ok is false when there are
+// no workflows to offer.
+func (b *Builder) runWorkflowMenu(a *alert.Alert, callbackURL string) (Button, bool) {
+	if b.workflowMenu == nil {
+		return Button{}, false
+	}
+	workflows := b.workflowMenu.Workflows()
+	if len(workflows) == 0 {
+		return Button{}, false
+	}
+
+	options := make([]SelectOption, 0, len(workflows))
+	for _, w := range workflows {
+		options = append(options, SelectOption{Text: w.Name, Value: w.ID})
+	}
+	return Button{
+		ID:      ActionRunWorkflow,
+		Name:    "Run workflow…",
+		Type:    ButtonTypeSelect,
+		Options: options,
+		Integration: ButtonIntegration{
+			URL: callbackURL,
+			Context: map[string]string{
+				ContextKeyAction:      ActionRunWorkflow,
+				ContextKeyFingerprint: a.Fingerprint().Value(),
+				ContextKeyAlertName:   a.Name(),
+				ContextKeySeverity:    a.Severity().String(),
+			},
+		},
+	}, true
+}
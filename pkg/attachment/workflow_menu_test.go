@@ -0,0 +1,45 @@
+package attachment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type workflowMenuFunc func() []Workflow
+
+func (f workflowMenuFunc) Workflows() []Workflow {
+	return f()
+}
+
+func TestRunWorkflowMenu(t *testing.T) {
+	var workflows []Workflow
+	builder, err := New(&testStyle{}, WithWorkflowMenu(workflowMenuFunc(func() []Workflow { return workflows })))
+	require.NoError(t, err)
+
+	card := builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	for _, button := range card.Actions {
+		assert.NotEqual(t, ActionRunWorkflow, button.ID, "the menu is left out without workflows")
+	}
+
+	workflows = []Workflow{{ID: "wf-1", Name: "Restart pod"}, {ID: "wf-2", Name: "Collect logs"}}
+	card = builder.BuildFiringAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui")
+	menu := card.Actions[len(card.Actions)-1]
+	assert.Equal(t, ActionRunWorkflow, menu.ID)
+	assert.Equal(t, "Run workflow…", menu.Name)
+	assert.Equal(t, ButtonTypeSelect, menu.Type)
+	assert.Equal(t, []SelectOption{{Text: "Restart pod", Value: "wf-1"}, {Text: "Collect logs", Value: "wf-2"}}, menu.Options)
+	assert.Equal(t, map[string]string{
+		ContextKeyAction:      ActionRunWorkflow,
+		ContextKeyFingerprint: "fp-1",
+		ContextKeyAlertName:   "Disk full",
+		ContextKeySeverity:    "critical",
+	}, menu.Integration.Context)
+
+	acked := builder.BuildAcknowledgedAttachment(newTestAlert("fp-1", time.Time{}), "http://callback", "http://keep.ui", "alice")
+	for _, button := range acked.Actions {
+		assert.NotEqual(t, ActionRunWorkflow, button.ID, "the menu is only offered on firing alerts")
+	}
+}
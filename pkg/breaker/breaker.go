@@ -0,0 +1,204 @@
+// Package breaker stops sending HTTP requests to an API that keeps failing,
+// so callers fail at once instead of waiting for timeouts, and lets a probe
+// request through now and then to notice when the API is back.
+package breaker
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// ErrOpen is returned for requests the breaker rejects without sending.
+var ErrOpen = errors.New("circuit breaker open")
+
+// States of a Transport.
+const (
+	// StateClosed sends every request.
+	StateClosed = "closed"
+	// StateOpen rejects every request with ErrOpen.
+	StateOpen = "open"
+	// StateHalfOpen sends a single probe request; its outcome closes or
+	// reopens the circuit.
+	StateHalfOpen = "half_open"
+)
+
+var (
+	stateGauge = func(service string) *metrics.Gauge {
+		return metrics.GetOrCreateGauge(`http_client_circuit_state{service="`+service+`"}`, nil)
+	}
+	transitionsCounter = func(service, state string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_circuit_transitions_total{service="` + service + `",state="` + state + `"}`)
+	}
+	rejectedCounter = func(service string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_circuit_rejected_total{service="` + service + `"}`)
+	}
+)
+
+// Settings say when a Transport opens and how long it stays open.
+type Settings struct {
+	// Failures is the number of consecutive failed requests that opens the
+	// circuit. Values below 1 are treated as 1.
+	Failures int
+	// OpenFor is how long the circuit stays open before a probe request is
+	// let through.
+	OpenFor time.Duration
+}
+
+// Transport is an http.RoundTripper shared by every request to one API. A
+// request fails when it gets a network error or a 5xx response; requests
+// whose own context ended are not counted. After Settings.Failures failures
+// in a row the circuit opens and requests fail with ErrOpen until OpenFor has
+// passed. Then one probe request is sent: success closes the circuit, failure
+// opens it again. A Transport passes requests through until SetSettings is
+// called.
+type Transport struct {
+	base    http.RoundTripper
+	service string
+	clock   clock.Clock
+
+	mu       sync.Mutex
+	enabled  bool
+	settings Settings
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+// NewTransport wraps base. service, e.g. "keep", labels the metrics.
+func NewTransport(base http.RoundTripper, service string) *Transport {
+	return &Transport{
+		base:    base,
+		service: service,
+		clock:   clock.Real(),
+		state:   StateClosed,
+	}
+}
+
+// SetSettings turns the breaker on. It must not be called while requests are
+// in flight.
+func (t *Transport) SetSettings(s Settings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enabled = true
+	t.settings = s
+	stateGauge(t.service).Set(0)
+}
+
+// SetClock replaces the clock used to time how long the circuit stays open.
+func (t *Transport) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+// State returns StateClosed, StateOpen or StateHalfOpen. An open circuit
+// whose OpenFor has passed is reported half-open.
+func (t *Transport) State() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state == StateOpen && t.clock.Now().Sub(t.openedAt) >= t.settings.OpenFor {
+		return StateHalfOpen
+	}
+	return t.state
+}
+
+// Available reports whether requests are sent: the circuit is not open, or
+// it is due for a probe.
+func (t *Transport) Available() bool {
+	return t.State() != StateOpen
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	enabled := t.enabled
+	t.mu.Unlock()
+	if !enabled {
+		return t.base.RoundTrip(req)
+	}
+
+	if !t.allow() {
+		rejectedCounter(t.service).Inc()
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, ErrOpen
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		t.release()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		t.failure()
+	default:
+		t.success()
+	}
+	return resp, err
+}
+
+// allow reports whether a request may be sent, turning an open circuit
+// half-open for the probe once OpenFor has passed.
+func (t *Transport) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.state {
+	case StateClosed:
+		return true
+	case StateOpen:
+		if t.clock.Now().Sub(t.openedAt) < t.settings.OpenFor {
+			return false
+		}
+		t.setState(StateHalfOpen)
+		return true
+	default: // a probe is in flight
+		return false
+	}
+}
+
+func (t *Transport) success() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures = 0
+	if t.state != StateClosed {
+		t.setState(StateClosed)
+	}
+}
+
+func (t *Transport) failure() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures++
+	if t.state == StateHalfOpen || t.failures >= max(t.settings.Failures, 1) {
+		t.openedAt = t.clock.Now()
+		if t.state != StateOpen {
+			t.setState(StateOpen)
+		}
+	}
+}
+
+// release lets the next request probe again when a probe was cancelled by
+// its caller.
+func (t *Transport) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state == StateHalfOpen {
+		t.setState(StateOpen)
+	}
+}
+
+func (t *Transport) setState(state string) {
+	t.state = state
+	transitionsCounter(t.service, state).Inc()
+	switch state {
+	case StateClosed:
+		stateGauge(t.service).Set(0)
+	case StateHalfOpen:
+		stateGauge(t.service).Set(1)
+	case StateOpen:
+		stateGauge(t.service).Set(2)
+	}
+}
@@ -0,0 +1,132 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// scriptedTransport answers with the given status, or fails when err is set.
+type scriptedTransport struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &http.Response{
+		StatusCode: s.status,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func send(t *testing.T, transport *Transport, ctx context.Context) error {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://keep.test/alerts", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func newBreaker(base http.RoundTripper) (*Transport, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	transport := NewTransport(base, "test")
+	transport.SetClock(fake)
+	transport.SetSettings(Settings{Failures: 3, OpenFor: 30 * time.Second})
+	return transport, fake
+}
+
+func TestTransportOpensAfterConsecutiveFailures(t *testing.T) {
+	base := &scriptedTransport{status: http.StatusBadGateway}
+	transport, _ := newBreaker(base)
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, send(t, transport, ctx), "5xx responses are passed on")
+	}
+	assert.Equal(t, StateOpen, transport.State())
+	assert.False(t, transport.Available())
+
+	assert.ErrorIs(t, send(t, transport, ctx), ErrOpen)
+	assert.Equal(t, 3, base.calls, "an open circuit sends nothing")
+}
+
+func TestTransportSuccessResetsFailures(t *testing.T) {
+	base := &scriptedTransport{err: errors.New("connection refused")}
+	transport, _ := newBreaker(base)
+	ctx := context.Background()
+
+	for range 2 {
+		assert.Error(t, send(t, transport, ctx))
+	}
+	base.err, base.status = nil, http.StatusNotFound
+	require.NoError(t, send(t, transport, ctx), "4xx responses count as success")
+	base.err = errors.New("connection refused")
+	for range 2 {
+		assert.Error(t, send(t, transport, ctx))
+	}
+	assert.Equal(t, StateClosed, transport.State())
+}
+
+func TestTransportHalfOpenProbe(t *testing.T) {
+	base := &scriptedTransport{status: http.StatusServiceUnavailable}
+	transport, fake := newBreaker(base)
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, send(t, transport, ctx))
+	}
+	fake.Advance(30 * time.Second)
+	assert.Equal(t, StateHalfOpen, transport.State())
+	assert.True(t, transport.Available())
+
+	require.NoError(t, send(t, transport, ctx), "the probe is sent")
+	assert.Equal(t, StateOpen, transport.State(), "a failed probe reopens the circuit")
+	assert.ErrorIs(t, send(t, transport, ctx), ErrOpen)
+
+	fake.Advance(30 * time.Second)
+	base.status = http.StatusOK
+	require.NoError(t, send(t, transport, ctx))
+	assert.Equal(t, StateClosed, transport.State(), "a successful probe closes the circuit")
+	require.NoError(t, send(t, transport, ctx))
+}
+
+func TestTransportIgnoresCancelledRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	base := &scriptedTransport{err: context.Canceled}
+	transport, _ := newBreaker(base)
+
+	for range 5 {
+		assert.Error(t, send(t, transport, ctx))
+	}
+	assert.Equal(t, StateClosed, transport.State())
+}
+
+func TestTransportDisabledByDefault(t *testing.T) {
+	base := &scriptedTransport{status: http.StatusInternalServerError}
+	transport := NewTransport(base, "test")
+
+	for range 10 {
+		require.NoError(t, send(t, transport, context.Background()))
+	}
+	assert.Equal(t, 10, base.calls)
+	assert.Equal(t, StateClosed, transport.State())
+}
@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRealClock(t *testing.T) {
+	c := Real()
+
+	before := time.Now()
+	assert.False(t, c.Now().Before(before))
+
+	ticker := c.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("real ticker did not fire")
+	}
+}
+
+func TestFakeNowAndAdvance(t *testing.T) {
+	f := NewFake(epoch)
+	assert.Equal(t, epoch, f.Now())
+
+	f.Advance(90 * time.Second)
+	assert.Equal(t, epoch.Add(90*time.Second), f.Now())
+}
+
+func TestFakeAfter(t *testing.T) {
+	f := NewFake(epoch)
+	ch := f.After(time.Minute)
+
+	f.Advance(59 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("fired early")
+	default:
+	}
+
+	f.Advance(time.Second)
+	select {
+	case fired := <-ch:
+		assert.Equal(t, epoch.Add(time.Minute), fired)
+	default:
+		t.Fatal("did not fire at deadline")
+	}
+}
+
+func TestFakeAfterNonPositive(t *testing.T) {
+	f := NewFake(epoch)
+	select {
+	case fired := <-f.After(0):
+		assert.Equal(t, epoch, fired)
+	default:
+		t.Fatal("zero duration should fire immediately")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(10 * time.Second)
+
+	f.Advance(10 * time.Second)
+	assert.Equal(t, epoch.Add(10*time.Second), <-ticker.C())
+
+	// Multiple periods elapsing at once drop ticks like time.Ticker does.
+	f.Advance(30 * time.Second)
+	assert.Equal(t, epoch.Add(20*time.Second), <-ticker.C())
+	select {
+	case <-ticker.C():
+		t.Fatal("expected dropped ticks")
+	default:
+	}
+
+	ticker.Stop()
+	f.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	f := NewFake(epoch)
+	done := make(chan struct{})
+
+	go func() {
+		<-f.After(time.Second)
+		close(done)
+	}()
+
+	f.BlockUntil(1)
+	f.Advance(time.Second)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+}
@@ -0,0 +1,122 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a manually advanced Clock. Timers and tickers fire only when
+// Advance moves the clock past their deadline, which makes scheduling
+// deterministic in tests.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	period   time.Duration // zero for one-shot timers
+	ch       chan time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.addWaiter(&fakeWaiter{deadline: f.now.Add(d), ch: ch})
+	return ch
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{deadline: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.addWaiter(w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Advance moves the clock forward and fires every timer and ticker whose
+// deadline has been reached. Like time.Ticker, a ticker whose channel is
+// full drops ticks instead of blocking.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+
+	remaining := f.waiters[:0]
+	for _, w := range f.waiters {
+		for !w.deadline.After(f.now) {
+			select {
+			case w.ch <- w.deadline:
+			default:
+			}
+			if w.period == 0 {
+				break
+			}
+			w.deadline = w.deadline.Add(w.period)
+		}
+		if w.period != 0 || w.deadline.After(f.now) {
+			remaining = append(remaining, w)
+		}
+	}
+	f.waiters = remaining
+}
+
+// BlockUntil waits until at least n timers or tickers are pending, so a test
+// can be sure a goroutine is waiting on the clock before calling Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) addWaiter(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+}
+
+func (f *Fake) removeWaiter(target *fakeWaiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, w := range f.waiters {
+		if w == target {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+func (t *fakeTicker) Stop()               { t.clock.removeWaiter(t.waiter) }
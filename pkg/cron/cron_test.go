@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	monday := time.Date(2026, 1, 5, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		location *time.Location
+		after    time.Time
+		want     time.Time
+	}{
+		{name: "every minute", spec: "* * * * *", after: monday, want: time.Date(2026, 1, 5, 10, 18, 0, 0, time.UTC)},
+		{name: "daily", spec: "@daily", after: monday, want: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "same minute is skipped", spec: "17 10 * * *", after: monday, want: time.Date(2026, 1, 6, 10, 17, 0, 0, time.UTC)},
+		{name: "weekly on friday", spec: "0 9 * * 5", after: monday, want: time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC)},
+		{name: "sunday as 7", spec: "30 8 * * 7", after: monday, want: time.Date(2026, 1, 11, 8, 30, 0, 0, time.UTC)},
+		{name: "steps and lists", spec: "*/20 9,18 * * 1-5", after: monday, want: time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)},
+		{name: "monthly", spec: "@monthly", after: monday, want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "either day field", spec: "0 0 13 * 5", after: monday, want: time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "location", spec: "0 9 * * *", location: berlin, after: monday, want: time.Date(2026, 1, 6, 8, 0, 0, 0, time.UTC)},
+		{name: "never", spec: "0 0 30 2 *", after: monday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec, tt.location)
+			require.NoError(t, err)
+			got := s.Next(tt.after)
+			if tt.want.IsZero() {
+				assert.True(t, got.IsZero())
+				return
+			}
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "0 9 * *", wantErr: "must have 5 fields"},
+		{spec: "60 9 * * *", wantErr: "invalid minute"},
+		{spec: "0 9-7 * * *", wantErr: "invalid range"},
+		{spec: "*/0 * * * *", wantErr: "invalid step"},
+		{spec: "0 9 * * mon", wantErr: "invalid day of week"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec, time.UTC)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -53,6 +53,23 @@ func RedisFieldsWithError(operation, key string, durationMs int64, errMsg string
 	)
 }
 
+func PostgresFields(operation, table string, durationMs int64) slog.Attr {
+	return slog.Group("postgres",
+		slog.String("operation", operation),
+		slog.String("table", table),
+		slog.Int64("duration_ms", durationMs),
+	)
+}
+
+func PostgresFieldsWithError(operation, table string, durationMs int64, errMsg string) slog.Attr {
+	return slog.Group("postgres",
+		slog.String("operation", operation),
+		slog.String("table", table),
+		slog.Int64("duration_ms", durationMs),
+		slog.String("error", errMsg),
+	)
+}
+
 func ApplicationFields(event string, attrs ...slog.Attr) slog.Attr {
 	args := make([]any, 0, len(attrs)+1)
 	args = append(args, slog.String("event", event))
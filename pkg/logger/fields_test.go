@@ -231,6 +231,40 @@ func TestRedisFieldsWithError(t *testing.T) {
 	}
 }
 
+func TestPostgresFieldsWithError(t *testing.T) {
+	attr := PostgresFieldsWithError("upsert", "kmbridge_posts", 7, "connection refused")
+
+	if attr.Key != "postgres" {
+		t.Errorf("expected key 'postgres', got %s", attr.Key)
+	}
+
+	expected := map[string]any{
+		"operation":   "upsert",
+		"table":       "kmbridge_posts",
+		"duration_ms": int64(7),
+		"error":       "connection refused",
+	}
+
+	groupAttrs := attr.Value.Group()
+	if len(groupAttrs) != len(expected) {
+		t.Errorf("expected %d attributes, got %d", len(expected), len(groupAttrs))
+	}
+
+	for _, a := range groupAttrs {
+		var actualVal any
+		switch a.Value.Kind() {
+		case slog.KindString:
+			actualVal = a.Value.String()
+		case slog.KindInt64:
+			actualVal = a.Value.Int64()
+		}
+
+		if actualVal != expected[a.Key] {
+			t.Errorf("attribute %s: expected %v, got %v", a.Key, expected[a.Key], actualVal)
+		}
+	}
+}
+
 func TestApplicationFields(t *testing.T) {
 	t.Run("with event only", func(t *testing.T) {
 		attr := ApplicationFields("startup")
@@ -0,0 +1,78 @@
+// Package outbound configures how the bridge's HTTP clients reach a server:
+// through a proxy, trusting a private certificate authority, or without
+// verifying the server's certificate at all.
+package outbound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Settings are the connection settings for one server.
+type Settings struct {
+	// ProxyURL is the http, https or socks5 proxy requests go through. Empty
+	// uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY from the environment.
+	ProxyURL string
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// InsecureSkipVerify accepts any server certificate. Only for testing.
+	InsecureSkipVerify bool
+}
+
+// ParseProxyURL parses a proxy URL, accepting only the schemes net/http
+// supports.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("scheme must be http, https or socks5, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+	return u, nil
+}
+
+// Apply sets the proxy and TLS settings of t.
+func (s Settings) Apply(t *http.Transport) error {
+	t.Proxy = http.ProxyFromEnvironment
+	if s.ProxyURL != "" {
+		proxyURL, err := ParseProxyURL(s.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if s.CAFile == "" && !s.InsecureSkipVerify {
+		return nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+	if s.CAFile != "" {
+		bundle, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("no certificates found in %s", s.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	t.TLSClientConfig = tlsConfig
+	return nil
+}
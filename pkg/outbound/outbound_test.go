@@ -0,0 +1,64 @@
+package outbound
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, transport *http.Transport, url string) error {
+	t.Helper()
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func TestApplyCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	require.Error(t, get(t, &http.Transport{}, server.URL), "the test server's certificate is self-signed")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, bundle, 0o600))
+	transport := &http.Transport{}
+	require.NoError(t, Settings{CAFile: caFile}.Apply(transport))
+	assert.NoError(t, get(t, transport, server.URL))
+
+	transport = &http.Transport{}
+	require.NoError(t, Settings{InsecureSkipVerify: true}.Apply(transport))
+	assert.NoError(t, get(t, transport, server.URL))
+
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	assert.ErrorContains(t, Settings{CAFile: caFile}.Apply(&http.Transport{}), "no certificates found")
+	assert.ErrorContains(t, Settings{CAFile: filepath.Join(t.TempDir(), "missing.pem")}.Apply(&http.Transport{}), "read CA file")
+}
+
+func TestApplyProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	transport := &http.Transport{}
+	require.NoError(t, Settings{ProxyURL: proxy.URL}.Apply(transport))
+	require.NoError(t, get(t, transport, "http://keep.internal/api/alerts"))
+	assert.Equal(t, "http://keep.internal/api/alerts", proxied)
+
+	transport = &http.Transport{}
+	require.NoError(t, Settings{}.Apply(transport))
+	assert.NotNil(t, transport.Proxy, "the proxy environment variables apply by default")
+	assert.Nil(t, transport.TLSClientConfig)
+
+	assert.ErrorContains(t, Settings{ProxyURL: "ftp://proxy"}.Apply(&http.Transport{}), "scheme must be http, https or socks5")
+}
@@ -0,0 +1,150 @@
+// Package ratelimit spaces out HTTP requests to an API with a token bucket and
+// pauses them while the server reports that its own limit is used up.
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+var (
+	throttledCounter = func(service string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_throttled_total{service="` + service + `"}`)
+	}
+	serverLimitCounter = func(service string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_server_limit_reached_total{service="` + service + `"}`)
+	}
+)
+
+// Limit is the request rate a Transport allows.
+type Limit struct {
+	// RequestsPerSecond is the sustained rate. Zero leaves the rate
+	// unlimited and only honors the server's X-Ratelimit headers.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent at once after a quiet
+	// period. Values below 1 are treated as 1.
+	Burst int
+}
+
+// Transport is an http.RoundTripper shared by every request to one API. It
+// holds requests back to stay within its Limit, and when a response reports
+// X-Ratelimit-Remaining: 0 it holds all requests until X-Ratelimit-Reset
+// seconds have passed. A Transport passes requests through until SetLimit is
+// called.
+type Transport struct {
+	base    http.RoundTripper
+	service string
+	clock   clock.Clock
+
+	mu          sync.Mutex
+	enabled     bool
+	limit       Limit
+	tokens      float64
+	refilled    time.Time
+	pausedUntil time.Time
+}
+
+// NewTransport wraps base. service, e.g. "mattermost", labels the metrics.
+func NewTransport(base http.RoundTripper, service string) *Transport {
+	return &Transport{
+		base:    base,
+		service: service,
+		clock:   clock.Real(),
+	}
+}
+
+// SetLimit turns rate limiting on. It must not be called while requests are
+// in flight.
+func (t *Transport) SetLimit(l Limit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enabled = true
+	t.limit = l
+	t.tokens = float64(t.burst())
+	t.refilled = t.clock.Now()
+}
+
+// SetClock replaces the clock used to refill tokens and wait.
+func (t *Transport) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	enabled := t.enabled
+	t.mu.Unlock()
+	if !enabled {
+		return t.base.RoundTrip(req)
+	}
+
+	if delay := t.reserve(); delay > 0 {
+		throttledCounter(t.service).Inc()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-t.clock.After(delay):
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.observe(resp)
+	}
+	return resp, err
+}
+
+// reserve takes a token for one request and returns how long the request has
+// to wait for it. Tokens may go negative so that waiting requests queue up in
+// order instead of racing for the next refill.
+func (t *Transport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	var delay time.Duration
+	if rps := t.limit.RequestsPerSecond; rps > 0 {
+		elapsed := now.Sub(t.refilled).Seconds()
+		t.tokens = math.Min(float64(t.burst()), t.tokens+elapsed*rps)
+		t.refilled = now
+		t.tokens--
+		if t.tokens < 0 {
+			delay = time.Duration(-t.tokens / rps * float64(time.Second))
+		}
+	}
+	if wait := t.pausedUntil.Sub(now); wait > delay {
+		delay = wait
+	}
+	return delay
+}
+
+// observe pauses requests when the server says no requests are left in its
+// current window.
+func (t *Transport) observe(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if err != nil || remaining > 0 {
+		return
+	}
+	reset, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Reset"))
+	if err != nil || reset < 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	until := t.clock.Now().Add(time.Duration(reset) * time.Second)
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+		serverLimitCounter(t.service).Inc()
+	}
+}
+
+func (t *Transport) burst() int {
+	return max(t.limit.Burst, 1)
+}
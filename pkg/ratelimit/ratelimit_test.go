@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+type countingTransport struct {
+	headers http.Header
+	calls   atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     c.headers.Clone(),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func newRequest(t *testing.T, ctx context.Context) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, "http://example.test/api", nil)
+	require.NoError(t, err)
+	return req
+}
+
+func newFake() *clock.Fake {
+	return clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+// roundTripAsync sends a request in the background; the returned channel
+// receives once the request has passed the limiter.
+func roundTripAsync(t *testing.T, transport *Transport) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		_, err := transport.RoundTrip(newRequest(t, context.Background()))
+		assert.NoError(t, err)
+		close(done)
+	}()
+	return done
+}
+
+func assertWaiting(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+		t.Fatal("request was not held back")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTransportPassesThroughUntilLimitSet(t *testing.T) {
+	base := &countingTransport{headers: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"60"}}}
+	transport := NewTransport(base, "test")
+	transport.SetClock(newFake())
+
+	for range 5 {
+		_, err := transport.RoundTrip(newRequest(t, context.Background()))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 5, int(base.calls.Load()))
+}
+
+func TestTransportTokenBucket(t *testing.T) {
+	base := &countingTransport{}
+	fake := newFake()
+	transport := NewTransport(base, "test")
+	transport.SetClock(fake)
+	transport.SetLimit(Limit{RequestsPerSecond: 2, Burst: 2})
+
+	for range 2 {
+		_, err := transport.RoundTrip(newRequest(t, context.Background()))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, int(base.calls.Load()), "burst is sent at once")
+
+	done := roundTripAsync(t, transport)
+	fake.BlockUntil(1)
+	assertWaiting(t, done)
+	fake.Advance(500 * time.Millisecond)
+	<-done
+	assert.Equal(t, 3, int(base.calls.Load()))
+}
+
+func TestTransportHonorsServerLimit(t *testing.T) {
+	base := &countingTransport{headers: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"2"}}}
+	fake := newFake()
+	transport := NewTransport(base, "test")
+	transport.SetClock(fake)
+	transport.SetLimit(Limit{})
+
+	_, err := transport.RoundTrip(newRequest(t, context.Background()))
+	require.NoError(t, err)
+
+	base.headers = http.Header{"X-Ratelimit-Remaining": {"9"}, "X-Ratelimit-Reset": {"1"}}
+	done := roundTripAsync(t, transport)
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	assertWaiting(t, done)
+	fake.Advance(time.Second)
+	<-done
+	assert.Equal(t, 2, int(base.calls.Load()))
+
+	_, err = transport.RoundTrip(newRequest(t, context.Background()))
+	require.NoError(t, err)
+	assert.Equal(t, 3, int(base.calls.Load()), "requests flow again while the server has budget left")
+}
+
+func TestTransportStopsOnCanceledContext(t *testing.T) {
+	base := &countingTransport{}
+	transport := NewTransport(base, "test")
+	transport.SetClock(newFake())
+	transport.SetLimit(Limit{RequestsPerSecond: 0.001, Burst: 1})
+
+	_, err := transport.RoundTrip(newRequest(t, context.Background()))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = transport.RoundTrip(newRequest(t, ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, int(base.calls.Load()))
+}
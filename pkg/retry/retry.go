@@ -0,0 +1,184 @@
+// Package retry retries HTTP requests that failed for reasons that are likely
+// to pass: network errors, 429 Too Many Requests and 5xx responses.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+var (
+	retriesCounter = func(service, operation string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_retries_total{service="` + service + `",operation="` + operation + `"}`)
+	}
+	exhaustedCounter = func(service, operation string) *metrics.Counter {
+		return metrics.GetOrCreateCounter(`http_client_retries_exhausted_total{service="` + service + `",operation="` + operation + `"}`)
+	}
+)
+
+// Policy says how often and how fast a failed request is retried.
+type Policy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialDelay is the wait before the first retry.
+	InitialDelay time.Duration
+	// Multiplier grows each following delay. Values below 1 are treated as 1.
+	Multiplier float64
+	// MaxDelay caps a single delay. Zero means no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each delay by up to this fraction in either direction,
+	// e.g. 0.2 turns 100ms into 80-120ms. Zero disables jitter.
+	Jitter float64
+}
+
+// Delay returns the wait before the given zero-based retry. r is a uniform
+// random number in [0, 1) used for jitter.
+func (p Policy) Delay(retry int, r float64) time.Duration {
+	mult := math.Max(p.Multiplier, 1)
+	d := float64(p.InitialDelay) * math.Pow(mult, float64(retry))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*r - 1)
+	}
+	return time.Duration(math.Max(d, 0))
+}
+
+type operationKey struct{}
+
+// WithOperation names the API operation of requests made with ctx, e.g.
+// "create_post". The name labels the retry metrics.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+func operationFrom(ctx context.Context) string {
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		return op
+	}
+	return "other"
+}
+
+// Transport is an http.RoundTripper that retries transient failures of the
+// wrapped transport. Request bodies are replayed with Request.GetBody, which
+// http.NewRequest sets for in-memory bodies; requests without it are not
+// retried.
+type Transport struct {
+	base    http.RoundTripper
+	service string
+	policy  Policy
+	clock   clock.Clock
+	rand    func() float64
+}
+
+// NewTransport wraps base. service, e.g. "mattermost", labels the retry
+// metrics.
+func NewTransport(base http.RoundTripper, service string, policy Policy) *Transport {
+	return &Transport{
+		base:    base,
+		service: service,
+		policy:  policy,
+		clock:   clock.Real(),
+		rand:    rand.Float64,
+	}
+}
+
+// SetPolicy replaces the retry policy. It must not be called while requests
+// are in flight.
+func (t *Transport) SetPolicy(p Policy) {
+	t.policy = p
+}
+
+// SetClock replaces the clock used to wait between attempts.
+func (t *Transport) SetClock(c clock.Clock) {
+	t.clock = c
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	operation := operationFrom(ctx)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if !retryable(ctx, resp, err) {
+			return resp, err
+		}
+		if attempt >= t.policy.MaxAttempts || (req.Body != nil && req.GetBody == nil) {
+			if attempt > 1 {
+				exhaustedCounter(t.service, operation).Inc()
+			}
+			return resp, err
+		}
+
+		delay := t.policy.Delay(attempt-1, t.rand())
+		if wait, ok := retryAfter(resp, t.clock.Now()); ok {
+			if t.policy.MaxDelay > 0 && wait > t.policy.MaxDelay {
+				// The server asks for a longer pause than we are willing to
+				// hold the caller for.
+				exhaustedCounter(t.service, operation).Inc()
+				return resp, err
+			}
+			delay = max(delay, wait)
+		}
+
+		next := req
+		if req.Body != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			next = req.Clone(ctx)
+			next.Body = body
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+
+		retriesCounter(t.service, operation).Inc()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.clock.After(delay):
+		}
+		req = next
+	}
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryAfter parses the Retry-After header of resp, given either in seconds
+// or as an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
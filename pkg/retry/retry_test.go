@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexmorbo/keep-mattermost-bridge/pkg/clock"
+)
+
+// scriptedTransport answers each round trip with the next status; 0 means a
+// network error.
+type scriptedTransport struct {
+	statuses []int
+	headers  http.Header
+	calls    atomic.Int32
+	bodies   []string
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := int(s.calls.Add(1)) - 1
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		s.bodies = append(s.bodies, string(body))
+	}
+	status := s.statuses[min(i, len(s.statuses)-1)]
+	if status == 0 {
+		return nil, errors.New("connection reset by peer")
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     s.headers.Clone(),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func testPolicy() Policy {
+	return Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 2, MaxDelay: 10 * time.Millisecond}
+}
+
+func newRequest(t *testing.T, ctx context.Context, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.test/api", strings.NewReader(body))
+	require.NoError(t, err)
+	return req
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{InitialDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, p.Delay(0, 0.5))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(1, 0.5))
+	assert.Equal(t, 300*time.Millisecond, p.Delay(2, 0.5), "capped by MaxDelay")
+
+	p.Jitter = 0.5
+	assert.Equal(t, 50*time.Millisecond, p.Delay(0, 0))
+	assert.Equal(t, 150*time.Millisecond, p.Delay(0, 1))
+}
+
+func TestTransportRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		calls    int
+		status   int
+	}{
+		{name: "success is not retried", statuses: []int{200}, calls: 1, status: 200},
+		{name: "client error is not retried", statuses: []int{400}, calls: 1, status: 400},
+		{name: "server error then success", statuses: []int{502, 200}, calls: 2, status: 200},
+		{name: "rate limited then success", statuses: []int{429, 201}, calls: 2, status: 201},
+		{name: "network error then success", statuses: []int{0, 200}, calls: 2, status: 200},
+		{name: "gives up after max attempts", statuses: []int{503}, calls: 3, status: 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &scriptedTransport{statuses: tt.statuses}
+			transport := NewTransport(base, "test", testPolicy())
+
+			resp, err := transport.RoundTrip(newRequest(t, context.Background(), `{"id":1}`))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.calls, int(base.calls.Load()))
+			for _, body := range base.bodies {
+				assert.Equal(t, `{"id":1}`, body, "body is replayed on every attempt")
+			}
+		})
+	}
+}
+
+func TestTransportDisabled(t *testing.T) {
+	base := &scriptedTransport{statuses: []int{503}}
+	transport := NewTransport(base, "test", Policy{MaxAttempts: 1})
+
+	resp, err := transport.RoundTrip(newRequest(t, context.Background(), ""))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 1, int(base.calls.Load()))
+}
+
+func TestTransportRetryAfter(t *testing.T) {
+	base := &scriptedTransport{statuses: []int{429, 200}, headers: http.Header{"Retry-After": {"1"}}}
+	transport := NewTransport(base, "test", Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Second})
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	transport.SetClock(fake)
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := transport.RoundTrip(newRequest(t, context.Background(), ""))
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	fake.BlockUntil(1)
+	fake.Advance(time.Second - time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("retried before Retry-After passed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	fake.Advance(time.Millisecond)
+	assert.Equal(t, http.StatusOK, (<-done).StatusCode)
+
+	base = &scriptedTransport{statuses: []int{429, 200}, headers: http.Header{"Retry-After": {"60"}}}
+	transport = NewTransport(base, "test", testPolicy())
+	resp, err := transport.RoundTrip(newRequest(t, context.Background(), ""))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "a pause beyond MaxDelay is not waited out")
+	assert.Equal(t, 1, int(base.calls.Load()))
+}
+
+func TestTransportStopsOnCanceledContext(t *testing.T) {
+	base := &scriptedTransport{statuses: []int{503}}
+	transport := NewTransport(base, "test", Policy{MaxAttempts: 5, InitialDelay: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := transport.RoundTrip(newRequest(t, ctx, ""))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, int(base.calls.Load()))
+}
+
+func TestTransportMetrics(t *testing.T) {
+	retries := metrics.GetOrCreateCounter(`http_client_retries_total{service="metrics_test",operation="create_post"}`)
+	exhausted := metrics.GetOrCreateCounter(`http_client_retries_exhausted_total{service="metrics_test",operation="create_post"}`)
+	retriesBefore, exhaustedBefore := retries.Get(), exhausted.Get()
+
+	base := &scriptedTransport{statuses: []int{500}}
+	transport := NewTransport(base, "metrics_test", testPolicy())
+	_, err := transport.RoundTrip(newRequest(t, WithOperation(context.Background(), "create_post"), ""))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), retries.Get()-retriesBefore)
+	assert.Equal(t, uint64(1), exhausted.Get()-exhaustedBefore)
+}
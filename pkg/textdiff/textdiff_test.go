@@ -0,0 +1,53 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedEqual(t *testing.T) {
+	assert.Empty(t, Unified("a", "b", "x\ny\n", "x\ny"))
+	assert.Empty(t, Unified("a", "b", "", ""))
+}
+
+func TestUnifiedChange(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\n"
+
+	assert.Equal(t, `--- keep
++++ kmbridge
+@@ -1,5 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
++six
+`, Unified("keep", "kmbridge", a, b))
+}
+
+func TestUnifiedSeparateHunks(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", i+1)
+	}
+	a := strings.Join(lines, "\n")
+	changed := append([]string(nil), lines...)
+	changed[1] = "first"
+	changed[18] = "second"
+	b := strings.Join(changed, "\n")
+
+	got := Unified("a", "b", a, b)
+	assert.Equal(t, 2, strings.Count(got, "@@ -"))
+	assert.Contains(t, got, "@@ -1,5 +1,5 @@\n")
+	assert.Contains(t, got, "@@ -16,5 +16,5 @@\n")
+	assert.Contains(t, got, "-xx\n+first\n")
+	assert.Contains(t, got, "+second\n")
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n", Unified("a", "b", "", "x\ny\n"))
+}